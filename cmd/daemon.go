@@ -0,0 +1,178 @@
+package main
+
+import (
+	"binance-trader/internal/config"
+	"binance-trader/internal/service"
+	"binance-trader/pkg/logger"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// runOptions are the command line settings of a trading session
+type runOptions struct {
+	tradingType config.TradingType
+	daemon      bool // --daemon or --headless: run without the interactive CLI
+	paper       bool // --paper: simulate orders against virtual balances
+}
+
+// parseRunArgs reads the trading type, --daemon and --paper from the command line. The flags may
+// come before or after the trading type, which defaults to spot.
+func parseRunArgs(args []string) (runOptions, error) {
+	opts := runOptions{tradingType: config.TradingTypeSpot}
+	typeSet := false
+	for _, arg := range args {
+		switch arg {
+		case "--daemon", "-daemon", "--headless", "-headless":
+			opts.daemon = true
+		case "--paper", "-paper":
+			opts.paper = true
+		case "spot", "futures", "both":
+			if typeSet {
+				return opts, fmt.Errorf("more than one trading type given")
+			}
+			opts.tradingType = config.TradingType(arg)
+			typeSet = true
+		default:
+			return opts, fmt.Errorf("unknown argument %q", arg)
+		}
+	}
+	return opts, nil
+}
+
+// printUsage describes the command line
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s [spot|futures|both] [--daemon] [--paper] | replay ...\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  spot    - Run spot trading system (default)\n")
+	fmt.Fprintf(os.Stderr, "  futures - Run futures trading system\n")
+	fmt.Fprintf(os.Stderr, "  both    - Run spot and futures side by side with a combined CLI\n")
+	fmt.Fprintf(os.Stderr, "  --daemon, --headless\n")
+	fmt.Fprintf(os.Stderr, "          - Run without the interactive CLI, e.g. under systemd or driven by the API server (same as run.mode: daemon)\n")
+	fmt.Fprintf(os.Stderr, "  --paper\n")
+	fmt.Fprintf(os.Stderr, "          - Simulate spot and futures orders against virtual balances (dry_run.enabled, 10000 USDT unless dry_run.starting_balance is set)\n")
+	fmt.Fprintf(os.Stderr, "  replay [-symbol SYMBOL] [-trace ID] <journal.jsonl>\n")
+	fmt.Fprintf(os.Stderr, "          - Replay a journal log and print a timeline with statistics\n")
+}
+
+// runInteractive runs a CLI, or in daemon mode waits for shutdown while the monitoring
+// services keep running
+func (app *Application) runInteractive(ctx context.Context, run func() error) error {
+	if !app.daemon {
+		return run()
+	}
+
+	app.logger.Info("Running as a daemon; the interactive CLI is disabled", map[string]interface{}{
+		"pid": os.Getpid(),
+	})
+	<-ctx.Done()
+	return nil
+}
+
+// handleSignal handles a signal received by the process and reports whether it asks for
+// shutdown. A daemon reloads its config on the reload signal and logs a status overview on
+// the status signal.
+func (app *Application) handleSignal(sig os.Signal) bool {
+	switch {
+	case reloadSignal != nil && sig == reloadSignal:
+		app.reloadConfig()
+		return false
+	case statusSignal != nil && sig == statusSignal:
+		app.logStatus()
+		return false
+	default:
+		return true
+	}
+}
+
+// reloadConfig reads the config file again. Safe mode and the log level change at once;
+// other settings are validated but take effect at the next start.
+func (app *Application) reloadConfig() error {
+	cfg, err := config.NewConfigManager().Load(app.configPath, app.tradingType)
+	if err != nil {
+		app.logger.Error("Config reload failed; keeping the running configuration", map[string]interface{}{
+			"config_file": app.configPath,
+			"error":       err.Error(),
+		})
+		return err
+	}
+
+	app.safeMode.Set(cfg.SafeMode)
+	for _, log := range append([]logger.Logger{app.logger}, app.marketLogs...) {
+		if setter, ok := log.(interface{ SetLevel(level string) error }); ok {
+			setter.SetLevel(cfg.Logging.Level)
+		}
+	}
+
+	app.logger.Info("Config reloaded; safe mode and log level applied, other changes apply after a restart", map[string]interface{}{
+		"config_file": app.configPath,
+		"safe_mode":   cfg.SafeMode,
+		"log_level":   cfg.Logging.Level,
+	})
+	return nil
+}
+
+// logStatus writes an overview of the running system to the log
+func (app *Application) logStatus() {
+	fields := map[string]interface{}{
+		"trading_type": string(app.tradingType),
+		"safe_mode":    app.safeMode.IsActive(),
+		"uptime":       time.Since(app.startedAt).Round(time.Second).String(),
+	}
+
+	if app.spotConditionalOrderSvc != nil {
+		if orders, err := app.spotConditionalOrderSvc.GetActiveConditionalOrders(); err == nil {
+			fields["spot_conditional_orders"] = len(orders)
+		}
+	}
+	if app.futuresConditionalOrderSvc != nil {
+		if orders, err := app.futuresConditionalOrderSvc.GetActiveConditionalOrders(); err == nil {
+			fields["futures_conditional_orders"] = len(orders)
+		}
+	}
+	if app.spotMaintenanceMonitor != nil {
+		fields["spot_maintenance"] = app.spotMaintenanceMonitor.IsInMaintenance()
+	}
+	if app.futuresMaintenanceMonitor != nil {
+		fields["futures_maintenance"] = app.futuresMaintenanceMonitor.IsInMaintenance()
+	}
+
+	var suspended []string
+	for _, monitor := range []service.SymbolStatusMonitor{app.spotSymbolStatus, app.futuresSymbolStatus} {
+		if monitor == nil {
+			continue
+		}
+		for _, suspension := range monitor.GetSuspendedSymbols() {
+			suspended = append(suspended, suspension.Symbol)
+		}
+	}
+	fields["suspended_symbols"] = strings.Join(suspended, ",")
+
+	app.logger.Info("Status overview", fields)
+}
+
+// writePIDFile writes the process ID to the configured PID file
+func (app *Application) writePIDFile() error {
+	if app.config.Run.PIDFile == "" {
+		return nil
+	}
+	if err := os.WriteFile(app.config.Run.PIDFile, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write PID file: %w", err)
+	}
+	return nil
+}
+
+// removePIDFile removes the PID file written at start
+func (app *Application) removePIDFile() {
+	if app.config.Run.PIDFile == "" {
+		return
+	}
+	if err := os.Remove(app.config.Run.PIDFile); err != nil && !os.IsNotExist(err) {
+		app.logger.Warn("Failed to remove PID file", map[string]interface{}{
+			"pid_file": app.config.Run.PIDFile,
+			"error":    err.Error(),
+		})
+	}
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"binance-trader/internal/config"
+	"binance-trader/internal/metrics"
+)
+
+func TestParseRunArgs(t *testing.T) {
+	tests := []struct {
+		args        []string
+		tradingType config.TradingType
+		daemon      bool
+		paper       bool
+		wantErr     bool
+	}{
+		{nil, config.TradingTypeSpot, false, false, false},
+		{[]string{"futures"}, config.TradingTypeFutures, false, false, false},
+		{[]string{"both", "--daemon"}, config.TradingTypeBoth, true, false, false},
+		{[]string{"--daemon", "futures"}, config.TradingTypeFutures, true, false, false},
+		{[]string{"--daemon"}, config.TradingTypeSpot, true, false, false},
+		{[]string{"spot", "--headless"}, config.TradingTypeSpot, true, false, false},
+		{[]string{"--paper"}, config.TradingTypeSpot, false, true, false},
+		{[]string{"spot", "--paper", "--daemon"}, config.TradingTypeSpot, true, true, false},
+		{[]string{"futures", "--paper"}, config.TradingTypeFutures, false, true, false},
+		{[]string{"--paper", "both"}, config.TradingTypeBoth, false, true, false},
+		{[]string{"spot", "futures"}, "", false, false, true},
+		{[]string{"futures", "--deamon"}, "", false, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			opts, err := parseRunArgs(tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseRunArgs(%v) expected an error", tt.args)
+				}
+				return
+			}
+			if err != nil || opts.tradingType != tt.tradingType || opts.daemon != tt.daemon || opts.paper != tt.paper {
+				t.Errorf("parseRunArgs(%v) = %+v, %v", tt.args, opts, err)
+			}
+		})
+	}
+}
+
+// daemonTestConfig is a spot config logging to dir; safe mode and the log level vary
+func daemonTestConfig(dir string, safeMode bool, level string) string {
+	return `
+safe_mode: ` + strconv.FormatBool(safeMode) + `
+spot:
+  api_key: test_spot_key
+  api_secret: test_spot_secret
+  base_url: https://api.binance.com
+  testnet: true
+
+risk:
+  max_order_amount: 1000.0
+  max_daily_orders: 100
+  min_balance_reserve: 100.0
+  max_api_calls_per_min: 1200
+
+logging:
+  level: ` + level + `
+  file: ` + filepath.Join(dir, "test.log") + `
+  spot_file: ` + filepath.Join(dir, "spot.log") + `
+  max_size_mb: 10
+  max_backups: 3
+
+retry:
+  max_attempts: 3
+  initial_delay_ms: 1000
+  backoff_multiplier: 2.0
+
+conditional_orders:
+  monitoring_interval_ms: 1000
+  max_active_orders: 100
+  trigger_execution_timeout_ms: 5000
+  enable_smart_polling: true
+
+stop_loss:
+  default_trail_percent: 1.0
+  min_trail_percent: 0.1
+  max_trail_percent: 5.0
+  update_interval_ms: 1000
+
+run:
+  pid_file: ` + filepath.Join(dir, "trader.pid") + `
+`
+}
+
+func TestDaemonMode(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	writeConfig := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+	}
+	writeConfig(daemonTestConfig(tmpDir, false, "info"))
+	os.Setenv("CONFIG_FILE", configPath)
+	defer os.Unsetenv("CONFIG_FILE")
+
+	app, err := initializeApplication(runOptions{tradingType: config.TradingTypeSpot, daemon: true})
+	if err != nil {
+		t.Fatalf("Failed to initialize application: %v", err)
+	}
+	if !app.daemon || app.config.Run.Mode != config.RunModeDaemon {
+		t.Fatalf("--daemon did not select daemon mode: %v, %q", app.daemon, app.config.Run.Mode)
+	}
+
+	// The PID file holds this process and is removed at shutdown
+	pidFile := filepath.Join(tmpDir, "trader.pid")
+	if err := app.writePIDFile(); err != nil {
+		t.Fatalf("writePIDFile() error = %v", err)
+	}
+	if data, _ := os.ReadFile(pidFile); strings.TrimSpace(string(data)) != strconv.Itoa(os.Getpid()) {
+		t.Errorf("PID file holds %q, want %d", data, os.Getpid())
+	}
+	defer func() {
+		app.removePIDFile()
+		if _, err := os.Stat(pidFile); !os.IsNotExist(err) {
+			t.Errorf("PID file was not removed: %v", err)
+		}
+	}()
+
+	// The CLI is not started; the daemon waits for shutdown instead
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := app.runInteractive(ctx, func() error {
+		t.Error("CLI started in daemon mode")
+		return nil
+	}); err != nil {
+		t.Errorf("runInteractive() error = %v", err)
+	}
+	app.daemon = false
+	started := false
+	app.runInteractive(context.Background(), func() error {
+		started = true
+		return nil
+	})
+	if !started {
+		t.Error("CLI not started in interactive mode")
+	}
+
+	if reloadSignal == nil {
+		app.closeLogs()
+		t.Skip("reload and status signals are not available on this platform")
+	}
+
+	// A broken config is rejected and the running settings are kept
+	writeConfig("safe_mode: [")
+	if app.handleSignal(reloadSignal) || app.safeMode.IsActive() {
+		t.Fatal("a broken config should neither shut down nor change safe mode")
+	}
+
+	// Safe mode and the log level follow the reloaded config
+	writeConfig(daemonTestConfig(tmpDir, true, "debug"))
+	if app.handleSignal(reloadSignal) {
+		t.Fatal("the reload signal should not shut down")
+	}
+	if !app.safeMode.IsActive() {
+		t.Error("safe mode was not applied by the reload")
+	}
+	if app.handleSignal(statusSignal) {
+		t.Fatal("the status signal should not shut down")
+	}
+	if !app.handleSignal(os.Interrupt) {
+		t.Error("an interrupt should shut down")
+	}
+
+	app.logger.Debug("Debug after reload", nil)
+	app.closeLogs()
+	data, err := os.ReadFile(filepath.Join(tmpDir, "spot.log"))
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	for _, want := range []string{"Config reload failed", "Config reloaded", "Status overview", `"safe_mode":true`, "Debug after reload"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("log does not contain %s", want)
+		}
+	}
+}
+
+func TestRiskLimitsHotReload(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	content := daemonTestConfig(tmpDir, false, "info")
+	writeConfig := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+	}
+	writeConfig(content)
+	os.Setenv("CONFIG_FILE", configPath)
+	defer os.Unsetenv("CONFIG_FILE")
+
+	app, err := initializeApplication(runOptions{tradingType: config.TradingTypeSpot, daemon: true})
+	if err != nil {
+		t.Fatalf("Failed to initialize application: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	app.startConfigWatch(ctx)
+
+	// waitForLimits rewrites the file until the risk manager holds the expected limits; the
+	// watcher starts asynchronously, so the first write may come before it
+	waitForLimits := func(content string, maxOrderAmount float64) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			writeConfig(content)
+			time.Sleep(300 * time.Millisecond)
+			if app.spotRiskMgr.GetCurrentLimits().MaxOrderAmount == maxOrderAmount {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("max order amount = %v, want %v", app.spotRiskMgr.GetCurrentLimits().MaxOrderAmount, maxOrderAmount)
+			}
+		}
+	}
+
+	// Tightened limits apply without a restart
+	tightened := strings.Replace(content, "max_order_amount: 1000.0", "max_order_amount: 250.0", 1)
+	tightened = strings.Replace(tightened, "max_daily_orders: 100", "max_daily_orders: 10", 1)
+	waitForLimits(tightened, 250)
+	if got := app.spotRiskMgr.GetCurrentLimits().MaxDailyOrders; got != 10 {
+		t.Errorf("max daily orders = %d, want 10", got)
+	}
+
+	// An invalid file is rejected and the reloaded limits stay in place
+	writeConfig(strings.Replace(content, "max_order_amount: 1000.0", "max_order_amount: -5", 1))
+	time.Sleep(500 * time.Millisecond)
+	if got := app.spotRiskMgr.GetCurrentLimits().MaxOrderAmount; got != 250 {
+		t.Errorf("max order amount after an invalid file = %v, want 250", got)
+	}
+
+	cancel()
+	app.closeLogs()
+	data, err := os.ReadFile(filepath.Join(tmpDir, "spot.log"))
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	for _, want := range []string{"Risk limits reloaded", "Config file change rejected"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("log does not contain %s", want)
+		}
+	}
+}
+
+func TestMetricsServer(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	content := daemonTestConfig(tmpDir, false, "info") + `
+metrics:
+  addr: 127.0.0.1:0
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	os.Setenv("CONFIG_FILE", configPath)
+	defer os.Unsetenv("CONFIG_FILE")
+
+	app, err := initializeApplication(runOptions{tradingType: config.TradingTypeSpot, daemon: true})
+	if err != nil {
+		t.Fatalf("Failed to initialize application: %v", err)
+	}
+	if app.metrics == nil {
+		t.Fatal("metrics server not started although metrics.addr is set")
+	}
+	url := "http://" + app.metrics.Addr() + metrics.Path
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", url, err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s status = %d", url, resp.StatusCode)
+	}
+	for _, want := range []string{"# TYPE api_requests_total counter", "# TYPE api_request_duration_seconds histogram",
+		"# TYPE conditional_orders_triggered_total counter", "# TYPE stop_loss_triggered_total counter"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics do not contain %q", want)
+		}
+	}
+
+	// Shutdown drains the metrics server
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := app.shutdown(ctx); err != nil {
+		t.Fatalf("shutdown() error = %v", err)
+	}
+	if _, err := http.Get(url); err == nil {
+		t.Error("metrics server still answers after shutdown")
+	}
+}
+
+func TestNewMetrics(t *testing.T) {
+	cfg := &config.Config{}
+	if registry, sink := newMetrics(cfg); registry != nil || sink == nil {
+		t.Errorf("newMetrics() = %v, %v; want no registry and a no-op sink when nothing serves metrics", registry, sink)
+	}
+
+	cfg.Server.Addr = "127.0.0.1:0"
+	registry, sink := newMetrics(cfg)
+	if registry == nil {
+		t.Fatal("newMetrics() returned no registry although the API server serves /metrics")
+	}
+	sink.Orders.Inc("BUY", "FILLED")
+	var out strings.Builder
+	registry.WriteText(&out)
+	if !strings.Contains(out.String(), `orders_total{side="BUY",status="FILLED"} 1`) {
+		t.Errorf("the sink does not record in the registry:\n%s", out.String())
+	}
+}
+
+func TestAPIServer(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	content := daemonTestConfig(tmpDir, false, "info") + `
+server:
+  addr: 127.0.0.1:0
+  token: daemon-token
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	os.Setenv("CONFIG_FILE", configPath)
+	defer os.Unsetenv("CONFIG_FILE")
+
+	app, err := initializeApplication(runOptions{tradingType: config.TradingTypeSpot, daemon: true})
+	if err != nil {
+		t.Fatalf("Failed to initialize application: %v", err)
+	}
+	if app.apiServer == nil {
+		t.Fatal("api server not started although server.addr is set")
+	}
+	url := "http://" + app.apiServer.Addr() + "/conditional-orders"
+
+	get := func(token string) int {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", url, err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if status := get("daemon-token"); status != http.StatusOK {
+		t.Errorf("GET %s status = %d, want 200", url, status)
+	}
+	if status := get("wrong"); status != http.StatusUnauthorized {
+		t.Errorf("GET %s with a wrong token status = %d, want 401", url, status)
+	}
+
+	// Shutdown stops the api server
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := app.shutdown(ctx); err != nil {
+		t.Fatalf("shutdown() error = %v", err)
+	}
+	if _, err := http.Get(url); err == nil {
+		t.Error("api server still answers after shutdown")
+	}
+}
@@ -2,34 +2,70 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"binance-trader/internal/api"
 	"binance-trader/internal/cli"
 	"binance-trader/internal/config"
+	"binance-trader/internal/metrics"
+	"binance-trader/internal/replay"
 	"binance-trader/internal/repository"
+	"binance-trader/internal/server"
 	"binance-trader/internal/service"
 	"binance-trader/pkg/logger"
+	pkgmetrics "binance-trader/pkg/metrics"
 )
 
 // Application holds all application dependencies
 type Application struct {
 	config      *config.Config
+	configPath  string               // Re-read when a daemon reloads its config
+	configMgr   config.ConfigManager // Watches the config file; nil disables hot reload
 	logger      logger.Logger
 	tradingType config.TradingType
+	daemon      bool      // No interactive CLI; the monitoring services run until a shutdown signal
+	startedAt   time.Time
+	marketLogs  []logger.Logger // Per-market loggers when both markets run
+	safeMode    *api.SafeMode
+	notifier    service.Notifier
+	metrics     *metrics.Server   // Serves /metrics when metrics.addr is set
+	registry    *metrics.Registry // Metrics served by the metrics and API servers; nil when neither runs
+	sink        *pkgmetrics.Sink  // Records the metrics in registry, or nowhere when it is nil
+	apiServer   *server.Server    // Serves the trading API when server.addr is set
+
+	// Order database shared by both markets when storage.type is sqlite
+	orderStorage *repository.SqliteOrderRepository
 	
 	// Spot-specific components
 	spotClient              api.BinanceClient
 	spotTradingService      service.TradingService
 	spotMarketService       service.MarketDataService
+	spotPriceStream         api.MarketStreamClient
+	spotUserStream          api.UserDataStream
 	spotOrderRepo           repository.OrderRepository
 	spotRiskMgr             service.RiskManager
 	spotConditionalOrderSvc service.ConditionalOrderService
 	spotStopLossSvc         service.StopLossService
+	spotAutomationSvc       service.AutomationService
+	spotMaintenanceMonitor  service.MaintenanceMonitor
+	spotSymbolGuard         service.SymbolFailureGuard
+	spotSymbolFilter        *service.SymbolFilter
+	spotCoverageChecker     service.ProtectionCoverageChecker
+	spotPositionWatcher     service.PositionChangeWatcher
+	spotMaintenanceSchedule service.MaintenanceScheduler
+	spotDustConverter       service.DustConverter
+	spotSymbolStatus        service.SymbolStatusMonitor
+	spotDryRun              service.DryRunSimulator
+	spotTWAPExecutor        *service.TWAPExecutor
+	spotBracketExecutor     *service.BracketExecutor
 	
 	// Futures-specific components
 	futuresClient              api.FuturesClient
@@ -40,9 +76,18 @@ type Application struct {
 	futuresConditionalOrderSvc service.FuturesConditionalOrderService
 	futuresStopLossSvc         service.FuturesStopLossService
 	futuresFundingService      service.FuturesFundingService
+	futuresSymbolGuard         service.SymbolFailureGuard
+	carrySvc                   service.CarryService
+	futuresCoverageChecker     service.ProtectionCoverageChecker
+	futuresPositionWatcher     service.PositionChangeWatcher
+	futuresMaintenanceMonitor  service.MaintenanceMonitor
+	futuresMaintenanceSchedule service.MaintenanceScheduler
+	futuresSymbolStatus        service.SymbolStatusMonitor
+	futuresDryRun              service.FuturesDryRunSimulator
 	
-	spotCLI    *cli.CLI
-	futuresCLI *cli.FuturesCLI
+	spotCLI     *cli.CLI
+	futuresCLI  *cli.FuturesCLI
+	combinedCLI *cli.CombinedCLI
 }
 
 func main() {
@@ -54,22 +99,24 @@ func main() {
 		}
 	}()
 
-	// Determine trading type from command line arguments
-	tradingType := config.TradingTypeSpot // Default to spot
-	if len(os.Args) > 1 {
-		switch os.Args[1] {
-		case "spot":
-			tradingType = config.TradingTypeSpot
-		case "futures":
-			tradingType = config.TradingTypeFutures
-		default:
-			fmt.Fprintf(os.Stderr, "Usage: %s [spot|futures]\n", os.Args[0])
-			fmt.Fprintf(os.Stderr, "  spot    - Run spot trading system (default)\n")
-			fmt.Fprintf(os.Stderr, "  futures - Run futures trading system\n")
+	// Developer tool: replay a journal log offline without connecting to the exchange
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		if err := runReplay(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Replay failed: %v\n", err)
 			os.Exit(1)
 		}
+		return
 	}
 
+	// Determine trading type and run mode from command line arguments
+	opts, err := parseRunArgs(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		printUsage()
+		os.Exit(1)
+	}
+	tradingType := opts.tradingType
+
 	// Run application with context
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -79,16 +126,39 @@ func main() {
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
 	// Initialize application
-	app, err := initializeApplication(tradingType)
+	app, err := initializeApplication(opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize application: %v\n", err)
 		os.Exit(1)
 	}
+	if err := app.writePIDFile(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
 
 	app.logger.Info("System initialized successfully", map[string]interface{}{
 		"trading_type": string(tradingType),
+		"daemon":       app.daemon,
 	})
 
+	// A daemon reloads its config and logs its status on signals; every other signal shuts down
+	if app.daemon {
+		for _, sig := range []os.Signal{reloadSignal, statusSignal} {
+			if sig != nil {
+				signal.Notify(sigChan, sig)
+			}
+		}
+	}
+	shutdownChan := make(chan os.Signal, 1)
+	go func() {
+		for sig := range sigChan {
+			if app.handleSignal(sig) {
+				shutdownChan <- sig
+				return
+			}
+		}
+	}()
+
 	// Run application in goroutine
 	errChan := make(chan error, 1)
 	go func() {
@@ -97,7 +167,7 @@ func main() {
 
 	// Wait for shutdown signal or error
 	select {
-	case sig := <-sigChan:
+	case sig := <-shutdownChan:
 		app.logger.Info("Received shutdown signal", map[string]interface{}{
 			"signal": sig.String(),
 		})
@@ -116,18 +186,24 @@ func main() {
 		
 	case err := <-errChan:
 		if err != nil {
+			// Fatal flushes the process logger before exiting; the market loggers are flushed here
+			app.removePIDFile()
+			app.closeMarketLogs()
 			app.logger.Fatal("Application error", map[string]interface{}{
 				"error": err.Error(),
 			})
 			os.Exit(1)
 		}
+		app.removePIDFile()
 	}
 
 	app.logger.Info("System shutdown complete", nil)
 }
 
 // initializeApplication initializes all application components with dependency injection
-func initializeApplication(tradingType config.TradingType) (*Application, error) {
+func initializeApplication(opts runOptions) (*Application, error) {
+	tradingType := opts.tradingType
+
 	// Get config file path from environment or use default
 	configPath := os.Getenv("CONFIG_FILE")
 	if configPath == "" {
@@ -136,10 +212,21 @@ func initializeApplication(tradingType config.TradingType) (*Application, error)
 
 	// Load configuration
 	configMgr := config.NewConfigManager()
-	cfg, err := configMgr.Load(configPath)
+	cfg, err := configMgr.Load(configPath, tradingType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
+	// --daemon overrides run.mode
+	if opts.daemon {
+		cfg.Run.Mode = config.RunModeDaemon
+	}
+	// --paper turns on the dry run simulator with virtual balances
+	if opts.paper {
+		cfg.DryRun.Enabled = true
+		if cfg.DryRun.StartingBalance == 0 {
+			cfg.DryRun.StartingBalance = service.DefaultPaperBalance
+		}
+	}
 
 	// Initialize logger
 	log, err := initializeLogger(cfg, tradingType)
@@ -155,8 +242,18 @@ func initializeApplication(tradingType config.TradingType) (*Application, error)
 	// Initialize application based on trading type
 	app := &Application{
 		config:      cfg,
+		configPath:  configPath,
+		configMgr:   configMgr,
 		logger:      log,
 		tradingType: tradingType,
+		safeMode:    api.NewSafeMode(cfg.SafeMode),
+		daemon:      cfg.Run.Mode == config.RunModeDaemon,
+		startedAt:   time.Now(),
+	}
+	app.registry, app.sink = newMetrics(cfg)
+
+	if app.safeMode.IsActive() {
+		log.Warn("Safe mode active: orders, cancellations and leverage/margin changes are disabled", nil)
 	}
 
 	switch tradingType {
@@ -168,15 +265,125 @@ func initializeApplication(tradingType config.TradingType) (*Application, error)
 		if err := initializeFuturesComponents(app, cfg, log); err != nil {
 			return nil, fmt.Errorf("failed to initialize futures components: %w", err)
 		}
+	case config.TradingTypeBoth:
+		if err := initializeBothComponents(app, cfg); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("unknown trading type: %s", tradingType)
 	}
 
+	if err := initializeNotifier(app, cfg); err != nil {
+		return nil, fmt.Errorf("failed to initialize notifications: %w", err)
+	}
+
+	if err := initializeMetrics(app, cfg); err != nil {
+		return nil, fmt.Errorf("failed to start metrics server: %w", err)
+	}
+	if cfg.Server.Addr != "" && app.apiServer == nil {
+		app.logger.Warn("server.addr is ignored: the API serves spot trading only", map[string]interface{}{
+			"trading_type": string(tradingType),
+		})
+	}
+
 	return app, nil
 }
 
+// initializeBothComponents initializes the spot and futures component sets side by side.
+// Each market gets its own clients, rate limiters, repositories and a logger tagged with
+// its trading type, so the markets only share the configuration and the CLI prompt.
+func initializeBothComponents(app *Application, cfg *config.Config) error {
+	spotLog, err := initializeLogger(cfg, config.TradingTypeSpot)
+	if err != nil {
+		return fmt.Errorf("failed to initialize spot logger: %w", err)
+	}
+	app.marketLogs = append(app.marketLogs, spotLog)
+	if err := initializeSpotComponents(app, cfg, spotLog); err != nil {
+		return fmt.Errorf("failed to initialize spot components: %w", err)
+	}
+
+	futuresLog, err := initializeLogger(cfg, config.TradingTypeFutures)
+	if err != nil {
+		return fmt.Errorf("failed to initialize futures logger: %w", err)
+	}
+	app.marketLogs = append(app.marketLogs, futuresLog)
+	if err := initializeFuturesComponents(app, cfg, futuresLog); err != nil {
+		return fmt.Errorf("failed to initialize futures components: %w", err)
+	}
+
+	app.combinedCLI = cli.NewCombinedCLI(app.spotCLI, app.futuresCLI)
+	return nil
+}
+
+// openOrderStorage opens the order database the first time a market asks for it when
+// storage.type is sqlite, and returns nil when orders are kept in memory
+func openOrderStorage(app *Application, cfg *config.Config) (*repository.SqliteOrderRepository, error) {
+	if cfg.Storage.Type != config.StorageTypeSQLite || app.orderStorage != nil {
+		return app.orderStorage, nil
+	}
+
+	if dir := filepath.Dir(cfg.Storage.Path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create order database directory: %w", err)
+		}
+	}
+	storage, err := repository.NewSqliteOrderRepository(cfg.Storage.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open order database: %w", err)
+	}
+	app.orderStorage = storage
+	app.logger.Info("Orders are stored in SQLite", map[string]interface{}{
+		"path": cfg.Storage.Path,
+	})
+	return storage, nil
+}
+
+// closeOrderStorage closes the order database if one is open
+func (app *Application) closeOrderStorage() {
+	if app.orderStorage == nil {
+		return
+	}
+	if err := app.orderStorage.Close(); err != nil {
+		app.logger.Error("Failed to close order database", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// newSymbolFilter creates the symbol filter rounding orders with trading.rounding_mode
+func newSymbolFilter(client api.SpotClient, trading *config.TradingConfig) (*service.SymbolFilter, error) {
+	roundingMode, err := service.ParseRoundingMode(trading.RoundingMode)
+	if err != nil {
+		return nil, err
+	}
+	exchangeInfoTTL := time.Duration(trading.ExchangeInfoTTLMs) * time.Millisecond
+	return service.NewSymbolFilter(service.NewExchangeInfoCache(client, exchangeInfoTTL), service.NewRounder(roundingMode)), nil
+}
+
 // initializeLogger creates and configures the logger based on trading type
 func initializeLogger(cfg *config.Config, tradingType config.TradingType) (logger.Logger, error) {
+	logFile, err := logFilePath(cfg, tradingType)
+	if err != nil {
+		return nil, err
+	}
+
+	// A daemon has no terminal to echo to; under journald it can log to stderr alone
+	daemon := cfg.Run.Mode == config.RunModeDaemon
+	loggerConfig := logger.Config{
+		Level:         cfg.Logging.Level,
+		FilePath:      logFile,
+		MaxSizeMB:     int64(cfg.Logging.MaxSizeMB),
+		MaxBackups:    cfg.Logging.MaxBackups,
+		EnableConsole: !daemon,
+		TradingType:   string(tradingType),
+		Journal:       daemon && cfg.Run.LogToJournal,
+	}
+	
+	return logger.NewLogger(loggerConfig)
+}
+
+// logFilePath returns the log file written by the logger of a trading type
+func logFilePath(cfg *config.Config, tradingType config.TradingType) (string, error) {
 	var logFile string
 	
 	switch tradingType {
@@ -192,20 +399,144 @@ func initializeLogger(cfg *config.Config, tradingType config.TradingType) (logge
 		} else {
 			logFile = cfg.Logging.File
 		}
+	case config.TradingTypeBoth:
+		// Process-level events; each market logs to its own file through its own logger
+		logFile = cfg.Logging.File
 	default:
-		return nil, fmt.Errorf("unknown trading type: %s", tradingType)
+		return "", fmt.Errorf("unknown trading type: %s", tradingType)
 	}
 
-	loggerConfig := logger.Config{
-		Level:         cfg.Logging.Level,
-		FilePath:      logFile,
-		MaxSizeMB:     int64(cfg.Logging.MaxSizeMB),
-		MaxBackups:    cfg.Logging.MaxBackups,
-		EnableConsole: true,
-		TradingType:   string(tradingType),
+	return logFile, nil
+}
+
+// newMetrics creates the registry the metrics are kept in when a server exposes them, and
+// otherwise a sink that discards them
+func newMetrics(cfg *config.Config) (*metrics.Registry, *pkgmetrics.Sink) {
+	if cfg.Metrics.Addr == "" && cfg.Server.Addr == "" {
+		return nil, pkgmetrics.Noop()
 	}
-	
-	return logger.NewLogger(loggerConfig)
+	registry := metrics.NewRegistry()
+	return registry, metrics.NewSink(registry)
+}
+
+// initializeMetrics starts the Prometheus metrics endpoint when metrics.addr is set
+func initializeMetrics(app *Application, cfg *config.Config) error {
+	if cfg.Metrics.Addr == "" {
+		return nil
+	}
+
+	server := metrics.NewServer(cfg.Metrics.Addr, app.registry)
+	if err := server.Start(); err != nil {
+		return err
+	}
+	app.metrics = server
+	app.logger.Info("Metrics server started", map[string]interface{}{
+		"addr": server.Addr(),
+		"path": metrics.Path,
+	})
+	return nil
+}
+
+// initializeNotifier creates the notifier and connects the alerts of the running stacks to it.
+// The daily digest reads the log files of the markets that are traded.
+func initializeNotifier(app *Application, cfg *config.Config) error {
+	notifier, err := service.NewNotifier(&cfg.Notifications, app.logger)
+	if err != nil {
+		return err
+	}
+
+	markets := []config.TradingType{app.tradingType}
+	if app.tradingType == config.TradingTypeBoth {
+		markets = []config.TradingType{config.TradingTypeSpot, config.TradingTypeFutures}
+	}
+
+	var journals []string
+	seen := make(map[string]bool)
+	for _, market := range markets {
+		logFile, err := logFilePath(cfg, market)
+		if err != nil {
+			return err
+		}
+		if logFile != "" && !seen[logFile] {
+			seen[logFile] = true
+			journals = append(journals, logFile)
+		}
+	}
+	notifier.SetDigestSource(service.NewJournalDigestSource(journals...))
+
+	if app.futuresPositionManager != nil {
+		notifier.SetPnLSource(func() (float64, error) {
+			positions, err := app.futuresPositionManager.GetAllPositions()
+			if err != nil {
+				return 0, err
+			}
+			var unrealized float64
+			for _, position := range positions {
+				unrealized += position.UnrealizedProfit
+			}
+			return unrealized, nil
+		})
+	}
+
+	// Stop coverage alerts are critical and bypass quiet hours
+	for _, checker := range []service.ProtectionCoverageChecker{app.spotCoverageChecker, app.futuresCoverageChecker} {
+		if checker == nil {
+			continue
+		}
+		checker.OnAlert(func(report *service.CoverageReport) {
+			notifier.Notify(&service.Notification{
+				Class:   service.NotificationUnprotectedPosition,
+				Title:   "Stop protection coverage below minimum",
+				Message: fmt.Sprintf("%.1f%% of notional protected; fully unprotected: %s", report.CoveragePct, strings.Join(report.Unprotected, ", ")),
+			})
+		})
+	}
+
+	// Tell the operator which stop orders followed a change in position size
+	for _, watcher := range []service.PositionChangeWatcher{app.spotPositionWatcher, app.futuresPositionWatcher} {
+		if watcher == nil {
+			continue
+		}
+		watcher.OnAdjustment(func(adjustment *service.ProtectionAdjustment) {
+			notifier.Notify(&service.Notification{
+				Class:   service.NotificationInfo,
+				Title:   "Stop orders adjusted to position size",
+				Message: adjustment.Summary(),
+			})
+		})
+	}
+
+	// Tell the operator which symbols stopped or resumed trading and what was paused
+	for _, monitor := range []service.SymbolStatusMonitor{app.spotSymbolStatus, app.futuresSymbolStatus} {
+		if monitor == nil {
+			continue
+		}
+		monitor.OnChange(func(change *service.SymbolStatusChange) {
+			title := "Symbol stopped trading, automation suspended"
+			if change.Resumed {
+				title = "Symbol trading again, automation resumed"
+			}
+			notifier.Notify(&service.Notification{
+				Class:   service.NotificationMaintenance,
+				Title:   title,
+				Message: change.Summary(),
+			})
+		})
+	}
+
+	// Tell the operator what was expired or re-evaluated after a suspend or clock jump
+	if app.spotConditionalOrderSvc != nil {
+		app.spotConditionalOrderSvc.OnMonitoringGap(func(report *service.MonitoringGapReport) {
+			notifier.Notify(&service.Notification{
+				Class:   service.NotificationInfo,
+				Title:   "Conditional order monitoring resumed after a gap",
+				Message: report.Summary(),
+			})
+		})
+	}
+
+	app.notifier = notifier
+	return nil
 }
 
 // initializeSpotComponents initializes all spot trading components
@@ -230,7 +561,7 @@ func initializeSpotComponents(app *Application, cfg *config.Config, log logger.L
 	}
 
 	// Initialize rate limiter
-	rateLimiter := api.NewRateLimiter(cfg.Risk.MaxAPICallsPerMin)
+	rateLimiter := api.NewRateLimiter(cfg.Risk.MaxAPICallsPerMin, api.DefaultEndpointWeights)
 
 	// Initialize HTTP client with retry configuration
 	retryConfig := api.RetryConfig{
@@ -238,38 +569,155 @@ func initializeSpotComponents(app *Application, cfg *config.Config, log logger.L
 		InitialDelayMs:    cfg.Retry.InitialDelayMs,
 		BackoffMultiplier: cfg.Retry.BackoffMultiplier,
 	}
-	httpClient := api.NewHTTPClient(rateLimiter, retryConfig)
+	httpClient := api.NewHTTPClientWithTimeouts(rateLimiter, retryConfig, buildTimeoutConfig(&cfg.Network.Timeouts))
+	httpClient.SetMetrics(app.sink)
 
 	// Initialize Binance spot client
 	spotClient, err := api.NewSpotClient(binanceConfig.BaseURL, httpClient, authMgr)
 	if err != nil {
 		return fmt.Errorf("failed to initialize spot client: %w", err)
 	}
+	// Dry run answers every order from the simulator; safe mode still applies on top of it
+	if cfg.SpotDryRun() {
+		app.spotDryRun = service.NewDryRunSimulator(spotClient, &cfg.DryRun, log)
+		spotClient = app.spotDryRun
+		log.Warn("Dry run active: spot orders are simulated against live market data", nil)
+		if cfg.DryRun.StartingBalance > 0 {
+			log.Warn("Paper trading: spot balances are virtual", map[string]interface{}{
+				"starting_balance": cfg.DryRun.StartingBalance,
+				"asset":            service.DefaultPaperBalanceAsset,
+			})
+		}
+	}
+	// Every spot service writes through this client, so safe mode is enforced here
+	spotClient = api.NewSafeModeSpotClient(spotClient, app.safeMode)
 	app.spotClient = spotClient
 
+	// Load exchange rate limits in the background; usage is recorded from response headers
+	go func() {
+		rules, err := spotClient.GetRateLimits()
+		if err != nil {
+			log.Warn("Failed to load exchange rate limits", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return
+		}
+		httpClient.SetRateLimits(rules)
+	}()
+
 	// Initialize order repository
+	storage, err := openOrderStorage(app, cfg)
+	if err != nil {
+		return err
+	}
 	app.spotOrderRepo = repository.NewMemoryOrderRepository()
+	if storage != nil {
+		app.spotOrderRepo = storage
+	}
 
-	// Initialize risk manager
-	riskLimits := &service.RiskLimits{
-		MaxOrderAmount:    cfg.Risk.MaxOrderAmount,
-		MaxDailyOrders:    cfg.Risk.MaxDailyOrders,
-		MinBalanceReserve: cfg.Risk.MinBalanceReserve,
-		MaxAPICallsPerMin: cfg.Risk.MaxAPICallsPerMin,
+	// Initialize risk manager, keeping the daily order count across restarts when configured
+	if cfg.Risk.DailyOrdersFile != "" {
+		app.spotRiskMgr, err = service.NewRiskManagerWithStore(buildRiskLimits(&cfg.Risk), spotClient,
+			repository.NewFileDailyOrderCountStore(cfg.Risk.DailyOrdersFile), log)
+		if err != nil {
+			return fmt.Errorf("failed to initialize risk manager: %w", err)
+		}
+	} else {
+		app.spotRiskMgr = service.NewRiskManager(buildRiskLimits(&cfg.Risk), spotClient)
+	}
+
+	// Apply risk limits edited in the config file without a restart
+	if app.configMgr != nil {
+		riskMgr := app.spotRiskMgr
+		app.configMgr.RegisterRiskLimitsCallback(func(risk *config.RiskConfig) {
+			if err := riskMgr.UpdateLimits(buildRiskLimits(risk)); err != nil {
+				log.Error("Failed to apply reloaded risk limits", map[string]interface{}{
+					"error": err.Error(),
+				})
+				return
+			}
+			log.Info("Risk limits reloaded", map[string]interface{}{
+				"max_order_amount":     risk.MaxOrderAmount,
+				"max_daily_orders":     risk.MaxDailyOrders,
+				"min_balance_reserve":  risk.MinBalanceReserve,
+				"max_notional_per_min": risk.MaxNotionalPerMin,
+				"notional_cap_mode":    risk.NotionalCapMode,
+			})
+		})
+	}
+
+	// Initialize market data service; prices come from the preferred stream with REST polling as fallback
+	app.spotMarketService = service.NewDataSourceManager(
+		service.NewMarketDataService(spotClient, 1*time.Second),
+		&cfg.Network.DataSource,
+		log,
+	)
+
+	// Serve best bid/ask from the bookTicker cache, polling REST while quotes are missing or stale
+	app.spotMarketService.SetBookTickerCache(service.NewBookTickerCache(spotClient, &cfg.Network.BookTicker, log))
+
+	// Push price subscriptions over the ticker stream; REST polling covers any time it is down
+	if cfg.MarketData.WebSocketEnabled {
+		stream, err := api.NewMarketStreamClient(api.MarketStreamOptions{
+			URL:              cfg.MarketData.StreamURL,
+			ReconnectInitial: time.Duration(cfg.MarketData.ReconnectInitialMs) * time.Millisecond,
+			ReconnectMax:     time.Duration(cfg.MarketData.ReconnectMaxMs) * time.Millisecond,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create market stream client: %w", err)
+		}
+		app.spotPriceStream = stream
+		app.spotMarketService.SetPriceStream(stream)
 	}
-	app.spotRiskMgr = service.NewRiskManager(riskLimits, spotClient)
 
 	// Initialize trading service
 	app.spotTradingService = service.NewSpotTradingService(spotClient, app.spotRiskMgr, app.spotOrderRepo, log)
+	app.spotTradingService.SetMetrics(app.sink)
 
-	// Initialize market data service
-	app.spotMarketService = service.NewMarketDataService(spotClient, 1*time.Second)
+	// Pause new orders for symbols that repeatedly fail
+	app.spotSymbolGuard = service.NewSymbolFailureGuard(&cfg.Trading.FailurePause, log)
+	app.spotTradingService.SetSymbolGuard(app.spotSymbolGuard)
 
-	// Initialize conditional order repository
-	conditionalOrderRepo := repository.NewMemoryConditionalOrderRepository()
+	// Cross-check order intents against recent exchange orders for a while after startup
+	if cfg.Trading.ReplayProtection.WindowMs > 0 {
+		app.spotTradingService.SetReplayProtection(service.NewReplayProtection(spotClient, &cfg.Trading.ReplayProtection, log))
+	}
 
-	// Initialize stop order repository
+	// Reject market orders the book would fill too far from the current price
+	if cfg.Risk.MaxSlippagePercent > 0 {
+		app.spotTradingService.SetSlippageProtection(app.spotMarketService, cfg.Risk.MaxSlippagePercent)
+	}
+
+	// Round orders to the symbol filters from exchange info and refuse what the exchange would
+	app.spotSymbolFilter, err = newSymbolFilter(spotClient, &cfg.Trading)
+	if err != nil {
+		return err
+	}
+	app.spotTradingService.SetSymbolFilter(app.spotSymbolFilter)
+
+	// Track order fills pushed over the user data stream instead of discovering them by polling
+	if cfg.MarketData.UserStreamEnabled && !cfg.SpotDryRun() {
+		stream, err := api.NewUserDataStream(spotClient, api.UserStreamOptions{
+			URL:              cfg.MarketData.UserStreamURL,
+			ReconnectInitial: time.Duration(cfg.MarketData.ReconnectInitialMs) * time.Millisecond,
+			ReconnectMax:     time.Duration(cfg.MarketData.ReconnectMaxMs) * time.Millisecond,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create user data stream: %w", err)
+		}
+		if err := app.spotTradingService.SetUserDataStream(stream); err != nil {
+			return err
+		}
+		app.spotUserStream = stream
+	}
+
+	// Initialize conditional order and stop order repositories
+	conditionalOrderRepo := repository.NewMemoryConditionalOrderRepository()
 	stopOrderRepo := repository.NewMemoryStopOrderRepository()
+	if storage != nil {
+		conditionalOrderRepo = storage.ConditionalOrders()
+		stopOrderRepo = storage.StopOrders()
+	}
 
 	// Initialize trigger engine
 	triggerEngine := service.NewTriggerEngine()
@@ -282,6 +730,8 @@ func initializeSpotComponents(app *Application, cfg *config.Config, log logger.L
 		app.spotMarketService,
 		log,
 	)
+	app.spotStopLossSvc.SetTrailConfig(&cfg.StopLoss)
+	app.spotStopLossSvc.SetMetrics(app.sink)
 
 	// Initialize conditional order service
 	app.spotConditionalOrderSvc = service.NewConditionalOrderService(
@@ -293,14 +743,117 @@ func initializeSpotComponents(app *Application, cfg *config.Config, log logger.L
 		app.spotStopLossSvc,
 		log,
 	)
+	app.spotConditionalOrderSvc.SetMetrics(app.sink)
+
+	// Initialize maintenance monitor and pause conditional executions during maintenance windows
+	app.spotMaintenanceMonitor = service.NewMaintenanceMonitor(spotClient, log, nil)
+	app.spotConditionalOrderSvc.SetMaintenanceMonitor(app.spotMaintenanceMonitor)
+
+	// Require confirmation of sharp price moves before stops act on them
+	app.spotConditionalOrderSvc.SetPriceSanityChecker(service.NewPriceSanityChecker(&cfg.StopLoss.PriceSanity, log))
+
+	// Initialize automation service
+	app.spotAutomationSvc = service.NewAutomationService(app.spotTradingService, app.spotMarketService, &cfg.Automation, log)
 
 	// Initialize Spot CLI
 	app.spotCLI = cli.NewCLI(app.spotTradingService, app.spotMarketService, app.spotConditionalOrderSvc, app.spotStopLossSvc, log)
+	app.spotCLI.SetAutomationService(app.spotAutomationSvc)
+	app.spotCLI.SetMaintenanceMonitor(app.spotMaintenanceMonitor)
+	app.spotCLI.SetSymbolGuard(app.spotSymbolGuard)
+	app.spotCLI.SetRateLimitStatusProvider(httpClient)
+	app.spotCLI.SetDisplayConfig(&cfg.CLI)
+	app.spotCLI.SetProfitGuardConfig(&cfg.StopLoss.ProfitGuard)
+	app.spotCLI.SetDryRun(cfg.SpotDryRun())
+	app.spotCLI.SetExchangeInfoCache(app.spotSymbolFilter.Cache())
+	app.spotCLI.SetPortfolioService(service.NewPortfolioService(app.spotTradingService, spotClient, log))
+	app.spotCLI.SetRiskManager(app.spotRiskMgr)
+	if app.spotDryRun != nil {
+		app.spotCLI.SetPaperAccount(app.spotDryRun.PaperAccount())
+	}
+	app.spotCLI.SetHoldingProvider(service.NewSpotHoldingProvider(spotClient))
+
+	// Check that tracked holdings are covered by stop orders
+	app.spotCoverageChecker = service.NewSpotCoverageChecker(spotClient, app.spotStopLossSvc, app.spotMarketService, &cfg.Risk.Coverage, log)
+	app.spotCLI.SetCoverageChecker(app.spotCoverageChecker)
+
+	// Keep local stops in proportion to tracked holdings when they are bought into or sold down
+	app.spotPositionWatcher, err = service.NewSpotPositionChangeWatcher(spotClient, app.spotStopLossSvc, stopOrderRepo, &cfg.Risk.Coverage, &cfg.Protection, log)
+	if err != nil {
+		return fmt.Errorf("failed to create position change watcher: %w", err)
+	}
+
+	// Convert small leftover balances to BNB
+	app.spotDustConverter = service.NewDustConverter(spotClient, &cfg.Dust, log)
+	app.spotCLI.SetDustConverter(app.spotDustConverter)
+
+	// Pause new orders ahead of announced maintenance windows
+	app.spotMaintenanceSchedule = service.NewMaintenanceScheduler(app.spotMaintenanceMonitor, &cfg.Maintenance, log)
+
+	// Suspend the orders of symbols that are halted or delisted until they trade again
+	app.spotSymbolStatus = service.NewSpotSymbolStatusMonitor(spotClient, &cfg.SymbolStatus, log, app.spotConditionalOrderSvc, service.NewStopOrderSuspender(stopOrderRepo))
+	app.spotCLI.SetSymbolStatusMonitor(app.spotSymbolStatus)
+
+	// Run twap orders so that shutdown stops them before their next slice
+	app.spotTWAPExecutor = service.NewTWAPExecutor(app.spotTradingService, log)
+	app.spotCLI.SetTWAPExecutor(app.spotTWAPExecutor)
+
+	// Run bracket orders so that shutdown cancels entry orders still waiting to fill
+	app.spotBracketExecutor = service.NewBracketExecutor(app.spotTradingService, app.spotStopLossSvc, log)
+	app.spotCLI.SetBracketExecutor(app.spotBracketExecutor)
+
+	// Serve the trading API alongside the CLI, or in its place for a daemon
+	if err := initializeAPIServer(app, cfg, log); err != nil {
+		return fmt.Errorf("failed to start api server: %w", err)
+	}
 
 	log.Info("Spot trading components initialized successfully", nil)
 	return nil
 }
 
+// initializeAPIServer starts the REST API over the spot services when server.addr is set
+func initializeAPIServer(app *Application, cfg *config.Config, log logger.Logger) error {
+	if cfg.Server.Addr == "" {
+		return nil
+	}
+
+	apiServer := server.NewServer(cfg.Server.Addr, cfg.Server.Token, app.spotTradingService, app.spotMarketService, app.spotConditionalOrderSvc, app.spotStopLossSvc, app.registry, log)
+	if err := apiServer.Start(); err != nil {
+		return err
+	}
+	app.apiServer = apiServer
+	log.Info("API server started", map[string]interface{}{
+		"addr": apiServer.Addr(),
+	})
+	return nil
+}
+
+// reconcilePositionMode warns when the exchange's position mode differs from the configured one;
+// switching is left to the position-mode command, since the exchange refuses it while positions are open
+func reconcilePositionMode(client api.FuturesClient, dualSide bool, log logger.Logger) {
+	mode, err := client.GetPositionMode()
+	if err != nil {
+		log.Warn("Failed to read the futures position mode", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if mode.DualSidePosition == dualSide {
+		return
+	}
+
+	modeName := func(dualSide bool) string {
+		if dualSide {
+			return "hedge"
+		}
+		return "oneway"
+	}
+	log.Warn("Futures position mode differs from dual_side_position", map[string]interface{}{
+		"exchange_mode":   modeName(mode.DualSidePosition),
+		"configured_mode": modeName(dualSide),
+		"hint":            "run position-mode " + modeName(dualSide) + " to switch",
+	})
+}
+
 // initializeFuturesComponents initializes all futures trading components
 func initializeFuturesComponents(app *Application, cfg *config.Config, log logger.Logger) error {
 	// Futures config must be present
@@ -320,7 +873,7 @@ func initializeFuturesComponents(app *Application, cfg *config.Config, log logge
 	}
 
 	// Initialize rate limiter
-	rateLimiter := api.NewRateLimiter(cfg.Futures.Risk.MaxAPICallsPerMin)
+	rateLimiter := api.NewRateLimiter(cfg.Futures.Risk.MaxAPICallsPerMin, api.DefaultEndpointWeights)
 
 	// Initialize HTTP client with retry configuration
 	retryConfig := api.RetryConfig{
@@ -328,13 +881,28 @@ func initializeFuturesComponents(app *Application, cfg *config.Config, log logge
 		InitialDelayMs:    cfg.Retry.InitialDelayMs,
 		BackoffMultiplier: cfg.Retry.BackoffMultiplier,
 	}
-	httpClient := api.NewHTTPClient(rateLimiter, retryConfig)
+	httpClient := api.NewHTTPClientWithTimeouts(rateLimiter, retryConfig, buildTimeoutConfig(&cfg.Network.Timeouts))
+	httpClient.SetMetrics(app.sink)
 
 	// Initialize Binance futures client
 	futuresClient, err := api.NewFuturesClient(cfg.Futures.BaseURL, httpClient, authMgr)
 	if err != nil {
 		return fmt.Errorf("failed to initialize futures client: %w", err)
 	}
+	// Dry run answers every futures order from the simulator; safe mode still applies on top of it
+	if cfg.FuturesDryRun() {
+		app.futuresDryRun = service.NewFuturesDryRunSimulator(futuresClient, &cfg.DryRun, log)
+		futuresClient = app.futuresDryRun
+		log.Warn("Dry run active: futures orders are simulated against live prices", nil)
+		if cfg.DryRun.StartingBalance > 0 {
+			log.Warn("Paper trading: futures margin is virtual", map[string]interface{}{
+				"starting_balance": cfg.DryRun.StartingBalance,
+				"asset":            service.DefaultPaperBalanceAsset,
+			})
+		}
+	}
+	// Every futures service writes through this client, so safe mode is enforced here
+	futuresClient = api.NewSafeModeFuturesClient(futuresClient, app.safeMode)
 	app.futuresClient = futuresClient
 
 	// Initialize futures order repository
@@ -345,6 +913,7 @@ func initializeFuturesComponents(app *Application, cfg *config.Config, log logge
 
 	// Initialize futures market data service
 	app.futuresMarketService = service.NewFuturesMarketDataService(futuresClient, log)
+	app.futuresMarketService.SetBookTickerCache(service.NewBookTickerCache(futuresClient, &cfg.Network.BookTicker, log))
 
 	// Initialize futures position manager
 	app.futuresPositionManager = service.NewFuturesPositionManager(
@@ -368,11 +937,33 @@ func initializeFuturesComponents(app *Application, cfg *config.Config, log logge
 		log,
 	)
 
+	// Pause new positions for symbols that repeatedly fail
+	app.futuresSymbolGuard = service.NewSymbolFailureGuard(&cfg.Trading.FailurePause, log)
+	app.futuresTradingService.SetSymbolGuard(app.futuresSymbolGuard)
+
+	// Keep the leveraged notional of limit orders within the position limit
+	app.futuresTradingService.SetRiskManager(app.futuresRiskManager)
+
+	// Compare the live account's position mode with the config in the background; simulated
+	// positions are kept per side, so the mode does not matter to them
+	if !cfg.FuturesDryRun() {
+		go reconcilePositionMode(futuresClient, cfg.Futures.DualSidePosition, log)
+	}
+	// Snapshot the available margin in the background
+	go app.futuresRiskManager.CheckAccountMargin()
+
 	// Initialize trigger engine
 	triggerEngine := service.NewTriggerEngine()
 
 	// Initialize stop order repository
+	storage, err := openOrderStorage(app, cfg)
+	if err != nil {
+		return err
+	}
 	stopOrderRepo := repository.NewMemoryStopOrderRepository()
+	if storage != nil {
+		stopOrderRepo = storage.FuturesStopOrders()
+	}
 
 	// Initialize futures stop loss service
 	app.futuresStopLossSvc = service.NewFuturesStopLossService(
@@ -383,6 +974,13 @@ func initializeFuturesComponents(app *Application, cfg *config.Config, log logge
 		log,
 	)
 
+	// Native trailing stops are not simulated, so dry run always tracks them locally
+	var trailingClient api.FuturesClient
+	if !cfg.FuturesDryRun() {
+		trailingClient = futuresClient
+	}
+	app.futuresStopLossSvc.SetTrailingStopConfig(&cfg.Futures.StopLoss, trailingClient)
+
 	// Initialize futures conditional order service
 	app.futuresConditionalOrderSvc = service.NewFuturesConditionalOrderService(
 		futuresClient,
@@ -392,6 +990,15 @@ func initializeFuturesComponents(app *Application, cfg *config.Config, log logge
 		log,
 	)
 
+	// BASIS triggers compare the perpetual with the spot price of the same pair
+	if spotPrices, err := initializeSpotPriceSource(app, cfg); err != nil {
+		log.Warn("Basis triggers disabled", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else {
+		app.futuresConditionalOrderSvc.SetSpotPriceSource(spotPrices)
+	}
+
 	// Initialize futures funding service
 	app.futuresFundingService = service.NewFuturesFundingService(
 		app.futuresMarketService,
@@ -407,27 +1014,212 @@ func initializeFuturesComponents(app *Application, cfg *config.Config, log logge
 		app.futuresStopLossSvc,
 		log,
 	)
+	app.futuresCLI.SetSymbolGuard(app.futuresSymbolGuard)
+	app.futuresCLI.SetRateLimitStatusProvider(httpClient)
+	app.futuresCLI.SetDisplayConfig(&cfg.CLI)
+	app.futuresCLI.SetProfitGuardConfig(&cfg.Futures.StopLoss.ProfitGuard)
+	app.futuresCLI.SetDryRun(cfg.FuturesDryRun())
+
+	// Check that open positions are covered by stop orders
+	app.futuresCoverageChecker = service.NewFuturesCoverageChecker(
+		app.futuresPositionManager,
+		app.futuresTradingService,
+		app.futuresStopLossSvc,
+		app.futuresMarketService,
+		&cfg.Risk.Coverage,
+		log,
+	)
+	app.futuresCLI.SetCoverageChecker(app.futuresCoverageChecker)
+
+	// Keep local stops in proportion to positions when they are added to or reduced
+	app.futuresPositionWatcher, err = service.NewFuturesPositionChangeWatcher(
+		app.futuresPositionManager,
+		app.futuresStopLossSvc,
+		app.futuresMarketService,
+		stopOrderRepo,
+		&cfg.Protection,
+		log,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create position change watcher: %w", err)
+	}
+
+	// Pause new positions and optionally flatten open ones ahead of announced maintenance windows;
+	// system status detection is spot-only, so this monitor only tracks scheduled windows
+	app.futuresMaintenanceMonitor = service.NewMaintenanceMonitor(nil, log, nil)
+	app.futuresMaintenanceSchedule = service.NewMaintenanceScheduler(app.futuresMaintenanceMonitor, &cfg.Maintenance, log)
+	app.futuresMaintenanceSchedule.SetFuturesServices(app.futuresPositionManager, app.futuresTradingService)
+	app.futuresCLI.SetMaintenanceMonitor(app.futuresMaintenanceMonitor)
+
+	// Suspend the orders of contracts that are settling or delisted until they trade again
+	app.futuresSymbolStatus = service.NewFuturesSymbolStatusMonitor(futuresClient, &cfg.SymbolStatus, log, app.futuresConditionalOrderSvc, service.NewStopOrderSuspender(stopOrderRepo))
+	app.futuresCLI.SetSymbolStatusMonitor(app.futuresSymbolStatus)
+
+	// The carry trade needs a spot leg; enable it only when spot credentials are configured
+	if err := initializeCarryService(app, cfg, log); err != nil {
+		log.Warn("Carry trading disabled", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else if app.carrySvc != nil {
+		app.futuresCLI.SetCarryService(app.carrySvc)
+	}
 
 	log.Info("Futures trading components initialized successfully", nil)
 	return nil
 }
 
-// run starts the application
-func (app *Application) run(ctx context.Context) error {
-	// Set up panic recovery
-	defer func() {
-		if r := recover(); r != nil {
-			app.logger.Error("Panic recovered in application run", map[string]interface{}{
-				"panic": fmt.Sprintf("%v", r),
-			})
-		}
-	}()
+// initializeSpotPriceSource returns the spot client of a combined session, or a read-only
+// client of the public price endpoint that needs no spot API keys
+func initializeSpotPriceSource(app *Application, cfg *config.Config) (api.SpotPriceClient, error) {
+	if app.spotClient != nil {
+		return app.spotClient, nil
+	}
+
+	baseURL := cfg.Futures.Monitoring.SpotPriceBaseURL
+	if baseURL == "" && cfg.Spot != nil {
+		baseURL = cfg.Spot.BaseURL
+	}
+	if baseURL == "" {
+		baseURL = cfg.Binance.BaseURL
+	}
+	if baseURL == "" {
+		return nil, fmt.Errorf("no spot base_url or futures.monitoring.spot_price_base_url configured")
+	}
+
+	retryConfig := api.RetryConfig{
+		MaxAttempts:       cfg.Retry.MaxAttempts,
+		InitialDelayMs:    cfg.Retry.InitialDelayMs,
+		BackoffMultiplier: cfg.Retry.BackoffMultiplier,
+	}
+	httpClient := api.NewHTTPClientWithTimeouts(api.NewRateLimiter(cfg.Risk.MaxAPICallsPerMin, api.DefaultEndpointWeights), retryConfig, buildTimeoutConfig(&cfg.Network.Timeouts))
+	httpClient.SetMetrics(app.sink)
+	return api.NewSpotPriceClient(baseURL, httpClient)
+}
+
+// initializeCarryService builds the spot components used by the carry trade's long leg
+func initializeCarryService(app *Application, cfg *config.Config, log logger.Logger) error {
+	binanceConfig := cfg.Spot
+	if binanceConfig == nil {
+		binanceConfig = &cfg.Binance
+	}
+	if binanceConfig.APIKey == "" || binanceConfig.APISecret == "" {
+		return nil
+	}
+
+	authMgr, err := api.NewAuthManager(binanceConfig.APIKey, binanceConfig.APISecret)
+	if err != nil {
+		return fmt.Errorf("failed to initialize spot auth manager: %w", err)
+	}
+
+	retryConfig := api.RetryConfig{
+		MaxAttempts:       cfg.Retry.MaxAttempts,
+		InitialDelayMs:    cfg.Retry.InitialDelayMs,
+		BackoffMultiplier: cfg.Retry.BackoffMultiplier,
+	}
+	httpClient := api.NewHTTPClientWithTimeouts(api.NewRateLimiter(cfg.Risk.MaxAPICallsPerMin, api.DefaultEndpointWeights), retryConfig, buildTimeoutConfig(&cfg.Network.Timeouts))
+	httpClient.SetMetrics(app.sink)
+
+	spotClient, err := api.NewSpotClient(binanceConfig.BaseURL, httpClient, authMgr)
+	if err != nil {
+		return fmt.Errorf("failed to initialize spot client: %w", err)
+	}
+
+	riskMgr := service.NewRiskManager(&service.RiskLimits{
+		MaxOrderAmount:    cfg.Risk.MaxOrderAmount,
+		MaxDailyOrders:    cfg.Risk.MaxDailyOrders,
+		MinBalanceReserve: cfg.Risk.MinBalanceReserve,
+		MaxAPICallsPerMin: cfg.Risk.MaxAPICallsPerMin,
+		MaxNotionalPerMin: cfg.Risk.MaxNotionalPerMin,
+		NotionalCapMode:   cfg.Risk.NotionalCapMode,
+	}, spotClient)
+	spotTradingService := service.NewSpotTradingService(spotClient, riskMgr, repository.NewMemoryOrderRepository(), log)
+	spotTradingService.SetMetrics(app.sink)
+	spotMarketService := service.NewMarketDataService(spotClient, 1*time.Second)
+
+	app.carrySvc = service.NewCarryService(
+		spotTradingService,
+		spotMarketService,
+		app.futuresTradingService,
+		app.futuresMarketService,
+		&cfg.Carry,
+		log,
+	)
+
+	return nil
+}
+
+// buildTimeoutConfig converts configured per-category timeouts to HTTP client timeouts
+func buildTimeoutConfig(cfg *config.TimeoutsConfig) api.TimeoutConfig {
+	return api.TimeoutConfig{
+		Order:      time.Duration(cfg.OrderMs) * time.Millisecond,
+		MarketData: time.Duration(cfg.MarketDataMs) * time.Millisecond,
+		Account:    time.Duration(cfg.AccountMs) * time.Millisecond,
+		Default:    time.Duration(cfg.DefaultMs) * time.Millisecond,
+	}
+}
+
+// buildRiskLimits converts the configured risk section to spot risk manager limits
+func buildRiskLimits(cfg *config.RiskConfig) *service.RiskLimits {
+	return &service.RiskLimits{
+		MaxOrderAmount:    cfg.MaxOrderAmount,
+		MaxDailyOrders:    cfg.MaxDailyOrders,
+		MinBalanceReserve: cfg.MinBalanceReserve,
+		MaxAPICallsPerMin: cfg.MaxAPICallsPerMin,
+		MaxNotionalPerMin: cfg.MaxNotionalPerMin,
+		NotionalCapMode:   cfg.NotionalCapMode,
+	}
+}
+
+// startConfigWatch reloads the config file on every change until ctx is done. Invalid
+// intermediate files are logged and skipped; the running configuration stays in place.
+func (app *Application) startConfigWatch(ctx context.Context) {
+	if app.configMgr == nil {
+		return
+	}
+
+	app.configMgr.RegisterReloadErrorCallback(func(err error) {
+		app.logger.Warn("Config file change rejected; keeping the running configuration", map[string]interface{}{
+			"config_file": app.configPath,
+			"error":       err.Error(),
+		})
+	})
+
+	go func() {
+		if err := app.configMgr.Watch(ctx); err != nil {
+			app.logger.Warn("Config file watching unavailable; risk limit changes need a restart", map[string]interface{}{
+				"config_file": app.configPath,
+				"error":       err.Error(),
+			})
+		}
+	}()
+}
+
+// run starts the application
+func (app *Application) run(ctx context.Context) error {
+	// Set up panic recovery
+	defer func() {
+		if r := recover(); r != nil {
+			app.logger.Error("Panic recovered in application run", map[string]interface{}{
+				"panic": fmt.Sprintf("%v", r),
+			})
+		}
+	}()
+
+	// Start scheduled delivery of quiet hours and daily digests
+	if err := app.startNotifications(); err != nil {
+		return fmt.Errorf("failed to start notification delivery: %w", err)
+	}
+
+	// Apply risk limit changes from the config file while running
+	app.startConfigWatch(ctx)
 
 	switch app.tradingType {
 	case config.TradingTypeSpot:
 		return app.runSpot(ctx)
 	case config.TradingTypeFutures:
 		return app.runFutures(ctx)
+	case config.TradingTypeBoth:
+		return app.runBoth(ctx)
 	default:
 		return fmt.Errorf("unknown trading type: %s", app.tradingType)
 	}
@@ -435,26 +1227,112 @@ func (app *Application) run(ctx context.Context) error {
 
 // runSpot runs the spot trading application
 func (app *Application) runSpot(ctx context.Context) error {
+	if err := app.startSpotMonitoring(); err != nil {
+		return err
+	}
+
+	// Run Spot CLI
+	if app.spotCLI != nil {
+		if err := app.runInteractive(ctx, app.spotCLI.Run); err != nil {
+			return fmt.Errorf("CLI error: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// startSpotMonitoring starts the background monitoring of the spot stack
+func (app *Application) startSpotMonitoring() error {
 	// Start monitoring engine for conditional orders
 	app.logger.Info("Starting spot conditional order monitoring", nil)
 	if err := app.spotConditionalOrderSvc.StartMonitoring(); err != nil {
 		return fmt.Errorf("failed to start conditional order monitoring: %w", err)
 	}
 
-	// Run Spot CLI
-	if app.spotCLI != nil {
-		if err := app.spotCLI.Run(); err != nil {
-			return fmt.Errorf("CLI error: %w", err)
+	// Start scheduled protection coverage check
+	if err := app.startCoverageMonitoring(app.spotCoverageChecker); err != nil {
+		return fmt.Errorf("failed to start protection coverage monitoring: %w", err)
+	}
+
+	// Start following holding size changes with the stop orders
+	if err := app.startPositionChangeMonitoring(app.spotPositionWatcher); err != nil {
+		return fmt.Errorf("failed to start position change monitoring: %w", err)
+	}
+
+	// Start scheduled maintenance check
+	if err := app.startMaintenanceSchedule(app.spotMaintenanceSchedule); err != nil {
+		return fmt.Errorf("failed to start scheduled maintenance monitoring: %w", err)
+	}
+
+	// Start following symbol trading status
+	if err := app.startSymbolStatusMonitoring(app.spotSymbolStatus); err != nil {
+		return fmt.Errorf("failed to start symbol status monitoring: %w", err)
+	}
+
+	// Start scheduled dust conversion
+	if err := app.startDustConversion(); err != nil {
+		return fmt.Errorf("failed to start scheduled dust conversion: %w", err)
+	}
+
+	// Start filling resting dry run orders from polled prices
+	if app.spotDryRun != nil {
+		pollInterval := time.Duration(app.config.DryRun.PollIntervalMs) * time.Millisecond
+		if err := app.spotDryRun.StartMonitoring(pollInterval); err != nil {
+			return fmt.Errorf("failed to start dry run simulation: %w", err)
 		}
 	}
 
+	// Start placing the orders of DCA and grid plans
+	checkInterval := time.Duration(app.config.Automation.CheckIntervalMs) * time.Millisecond
+	if err := app.spotAutomationSvc.StartMonitoring(checkInterval); err != nil {
+		return fmt.Errorf("failed to start automation plans: %w", err)
+	}
+
 	return nil
 }
 
 // runFutures runs the futures trading application
 func (app *Application) runFutures(ctx context.Context) error {
 	app.logger.Info("Starting futures trading system", nil)
-	
+
+	if err := app.startFuturesMonitoring(); err != nil {
+		return err
+	}
+
+	// Run Futures CLI
+	if app.futuresCLI != nil {
+		if err := app.runInteractive(ctx, app.futuresCLI.Run); err != nil {
+			return fmt.Errorf("CLI error: %w", err)
+		}
+	} else {
+		// If no CLI, just wait for context cancellation
+		app.logger.Info("Futures system running (CLI not available)", nil)
+		<-ctx.Done()
+	}
+
+	return nil
+}
+
+// runBoth runs the spot and futures stacks side by side behind the combined CLI
+func (app *Application) runBoth(ctx context.Context) error {
+	app.logger.Info("Starting spot and futures trading systems", nil)
+
+	if err := app.startSpotMonitoring(); err != nil {
+		return fmt.Errorf("spot: %w", err)
+	}
+	if err := app.startFuturesMonitoring(); err != nil {
+		return fmt.Errorf("futures: %w", err)
+	}
+
+	if err := app.runInteractive(ctx, app.combinedCLI.Run); err != nil {
+		return fmt.Errorf("CLI error: %w", err)
+	}
+
+	return nil
+}
+
+// startFuturesMonitoring starts the background monitoring of the futures stack
+func (app *Application) startFuturesMonitoring() error {
 	// Start monitoring for futures conditional orders
 	if app.futuresConditionalOrderSvc != nil {
 		app.logger.Info("Starting futures conditional order monitoring", nil)
@@ -463,6 +1341,14 @@ func (app *Application) runFutures(ctx context.Context) error {
 		}
 	}
 
+	// Start following the mark price with locally tracked trailing stops and take profits
+	if app.futuresStopLossSvc != nil {
+		checkInterval := time.Duration(app.config.Futures.StopLoss.CheckIntervalMs) * time.Millisecond
+		if err := app.futuresStopLossSvc.StartMonitoring(checkInterval); err != nil {
+			return fmt.Errorf("failed to start futures stop order monitoring: %w", err)
+		}
+	}
+
 	// Start funding rate monitoring
 	if app.futuresFundingService != nil {
 		app.logger.Info("Starting funding rate monitoring", nil)
@@ -472,20 +1358,220 @@ func (app *Application) runFutures(ctx context.Context) error {
 		}
 	}
 
-	// Run Futures CLI
-	if app.futuresCLI != nil {
-		if err := app.futuresCLI.Run(); err != nil {
-			return fmt.Errorf("CLI error: %w", err)
+	// Start carry monitoring
+	if app.carrySvc != nil {
+		checkInterval := time.Duration(app.config.Carry.CheckIntervalMs) * time.Millisecond
+		if err := app.carrySvc.StartMonitoring(checkInterval); err != nil {
+			return fmt.Errorf("failed to start carry monitoring: %w", err)
+		}
+	}
+
+	// Start scheduled protection coverage check
+	if err := app.startCoverageMonitoring(app.futuresCoverageChecker); err != nil {
+		return fmt.Errorf("failed to start protection coverage monitoring: %w", err)
+	}
+
+	// Start following position size changes with the stop orders
+	if err := app.startPositionChangeMonitoring(app.futuresPositionWatcher); err != nil {
+		return fmt.Errorf("failed to start position change monitoring: %w", err)
+	}
+
+	// Start scheduled maintenance check
+	if err := app.startMaintenanceSchedule(app.futuresMaintenanceSchedule); err != nil {
+		return fmt.Errorf("failed to start scheduled maintenance monitoring: %w", err)
+	}
+
+	// Start following contract trading status
+	if err := app.startSymbolStatusMonitoring(app.futuresSymbolStatus); err != nil {
+		return fmt.Errorf("failed to start symbol status monitoring: %w", err)
+	}
+
+	// Start filling resting dry run orders from polled prices
+	if app.futuresDryRun != nil {
+		pollInterval := time.Duration(app.config.DryRun.PollIntervalMs) * time.Millisecond
+		if err := app.futuresDryRun.StartMonitoring(pollInterval); err != nil {
+			return fmt.Errorf("failed to start futures dry run simulation: %w", err)
 		}
-	} else {
-		// If no CLI, just wait for context cancellation
-		app.logger.Info("Futures system running (CLI not available)", nil)
-		<-ctx.Done()
 	}
 
 	return nil
 }
 
+// startCoverageMonitoring schedules the protection coverage check when an interval is configured
+func (app *Application) startCoverageMonitoring(checker service.ProtectionCoverageChecker) error {
+	if checker == nil || app.config.Risk.Coverage.CheckIntervalMs <= 0 {
+		return nil
+	}
+
+	checkInterval := time.Duration(app.config.Risk.Coverage.CheckIntervalMs) * time.Millisecond
+	return checker.StartMonitoring(checkInterval)
+}
+
+// stopCoverageMonitoring stops the scheduled protection coverage check if it is running
+func (app *Application) stopCoverageMonitoring(checker service.ProtectionCoverageChecker) {
+	if checker == nil || app.config.Risk.Coverage.CheckIntervalMs <= 0 {
+		return
+	}
+
+	if err := checker.StopMonitoring(); err != nil {
+		app.logger.Debug("Protection coverage monitoring was not running during shutdown", nil)
+	}
+}
+
+// startSymbolStatusMonitoring starts refreshing symbol trading status from exchange info
+func (app *Application) startSymbolStatusMonitoring(monitor service.SymbolStatusMonitor) error {
+	if monitor == nil {
+		return nil
+	}
+
+	refreshInterval := time.Duration(app.config.SymbolStatus.RefreshIntervalMs) * time.Millisecond
+	return monitor.StartMonitoring(refreshInterval)
+}
+
+// stopSymbolStatusMonitoring stops refreshing symbol trading status if it is running
+func (app *Application) stopSymbolStatusMonitoring(monitor service.SymbolStatusMonitor) {
+	if monitor == nil {
+		return
+	}
+
+	if err := monitor.StopMonitoring(); err != nil {
+		app.logger.Debug("Symbol status monitoring was not running during shutdown", nil)
+	}
+}
+
+// startPositionChangeMonitoring starts following position size changes unless auto scaling is off
+func (app *Application) startPositionChangeMonitoring(watcher service.PositionChangeWatcher) error {
+	if watcher == nil || app.autoScaleMode() == service.AutoScaleOff {
+		return nil
+	}
+
+	checkInterval := time.Duration(app.config.Protection.CheckIntervalMs) * time.Millisecond
+	return watcher.StartMonitoring(checkInterval)
+}
+
+// stopPositionChangeMonitoring stops following position size changes if it is running
+func (app *Application) stopPositionChangeMonitoring(watcher service.PositionChangeWatcher) {
+	if watcher == nil || app.autoScaleMode() == service.AutoScaleOff {
+		return
+	}
+
+	if err := watcher.StopMonitoring(); err != nil {
+		app.logger.Debug("Position change monitoring was not running during shutdown", nil)
+	}
+}
+
+// autoScaleMode returns the configured protection.auto_scale mode; it is validated on load
+func (app *Application) autoScaleMode() service.AutoScaleMode {
+	mode, err := service.ParseAutoScaleMode(app.config.Protection.AutoScale)
+	if err != nil {
+		return service.AutoScaleOff
+	}
+	return mode
+}
+
+// startDustConversion schedules dust conversion when auto conversion and an interval are configured
+func (app *Application) startDustConversion() error {
+	if app.spotDustConverter == nil || !app.config.Dust.AutoConvert || app.config.Dust.CheckIntervalMs <= 0 {
+		return nil
+	}
+
+	checkInterval := time.Duration(app.config.Dust.CheckIntervalMs) * time.Millisecond
+	return app.spotDustConverter.StartMonitoring(checkInterval)
+}
+
+// stopDustConversion stops the scheduled dust conversion if it is running
+func (app *Application) stopDustConversion() {
+	if app.spotDustConverter == nil || !app.config.Dust.AutoConvert || app.config.Dust.CheckIntervalMs <= 0 {
+		return
+	}
+
+	if err := app.spotDustConverter.StopMonitoring(); err != nil {
+		app.logger.Debug("Dust conversion was not running during shutdown", nil)
+	}
+}
+
+// startNotifications schedules notification delivery when quiet hours or a daily digest need it
+func (app *Application) startNotifications() error {
+	if app.notifier == nil || !app.notificationsScheduled() {
+		return nil
+	}
+
+	checkInterval := time.Duration(app.config.Notifications.CheckIntervalMs) * time.Millisecond
+	return app.notifier.StartMonitoring(checkInterval)
+}
+
+// stopNotifications stops the scheduled notification delivery after sending the digests that are due
+func (app *Application) stopNotifications() {
+	if app.notifier == nil || !app.notificationsScheduled() {
+		return
+	}
+
+	if err := app.notifier.StopMonitoring(); err != nil {
+		app.logger.Debug("Notification delivery was not running during shutdown", nil)
+	}
+	app.notifier.Deliver()
+}
+
+// stopMetrics stops the metrics server after the scrapes in flight are answered
+func (app *Application) stopMetrics(ctx context.Context) {
+	if app.metrics == nil {
+		return
+	}
+
+	if err := app.metrics.Shutdown(ctx); err != nil {
+		app.logger.Warn("Failed to stop metrics server", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// stopAPIServer stops the API server after the requests in flight are answered
+func (app *Application) stopAPIServer(ctx context.Context) {
+	if app.apiServer == nil {
+		return
+	}
+
+	if err := app.apiServer.Shutdown(ctx); err != nil {
+		app.logger.Warn("Failed to stop API server", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// notificationsScheduled reports whether any channel has quiet hours or the daily digest is enabled
+func (app *Application) notificationsScheduled() bool {
+	if app.config.Notifications.DailyDigest.Enabled {
+		return true
+	}
+	for _, channel := range app.config.Notifications.Channels {
+		if channel.QuietHours.Enabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// startMaintenanceSchedule schedules the maintenance window check when windows and an interval are configured
+func (app *Application) startMaintenanceSchedule(scheduler service.MaintenanceScheduler) error {
+	if scheduler == nil || len(app.config.Maintenance.Windows) == 0 || app.config.Maintenance.CheckIntervalMs <= 0 {
+		return nil
+	}
+
+	checkInterval := time.Duration(app.config.Maintenance.CheckIntervalMs) * time.Millisecond
+	return scheduler.StartMonitoring(checkInterval)
+}
+
+// stopMaintenanceSchedule stops the maintenance window check if it is running
+func (app *Application) stopMaintenanceSchedule(scheduler service.MaintenanceScheduler) {
+	if scheduler == nil || len(app.config.Maintenance.Windows) == 0 || app.config.Maintenance.CheckIntervalMs <= 0 {
+		return
+	}
+
+	if err := scheduler.StopMonitoring(); err != nil {
+		app.logger.Debug("Scheduled maintenance monitoring was not running during shutdown", nil)
+	}
+}
+
 // shutdown performs graceful shutdown of all components
 func (app *Application) shutdown(ctx context.Context) error {
 	app.logger.Info("Starting graceful shutdown", nil)
@@ -496,12 +1582,20 @@ func (app *Application) shutdown(ctx context.Context) error {
 	go func() {
 		var shutdownErr error
 
+		// Stop taking API orders before the monitoring they rely on stops
+		app.stopAPIServer(ctx)
+
 		switch app.tradingType {
 		case config.TradingTypeSpot:
 			shutdownErr = app.shutdownSpot()
 		case config.TradingTypeFutures:
 			shutdownErr = app.shutdownFutures()
+		case config.TradingTypeBoth:
+			shutdownErr = app.shutdownBoth()
 		}
+		app.stopNotifications()
+		app.closeOrderStorage()
+		app.stopMetrics(ctx)
 
 		app.logger.Info("Shutdown: All resources cleaned up", nil)
 		done <- shutdownErr
@@ -514,14 +1608,67 @@ func (app *Application) shutdown(ctx context.Context) error {
 			return fmt.Errorf("error during shutdown: %w", err)
 		}
 		app.logger.Info("Graceful shutdown completed", nil)
+		app.removePIDFile()
+		app.closeLogs()
 		return nil
 	case <-ctx.Done():
+		app.removePIDFile()
+		app.closeLogs()
 		return fmt.Errorf("shutdown timeout exceeded")
 	}
 }
 
+// closeLogs writes the buffered log entries of every logger and closes the log files
+func (app *Application) closeLogs() {
+	app.closeMarketLogs()
+	if err := app.logger.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to close log file: %v\n", err)
+	}
+}
+
+// closeMarketLogs closes the per-market loggers
+func (app *Application) closeMarketLogs() {
+	for _, log := range app.marketLogs {
+		if err := log.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to close log file: %v\n", err)
+		}
+	}
+}
+
+// cancelPendingConditionalOrders records pending conditional orders as cancelled by shutdown
+func (app *Application) cancelPendingConditionalOrders(market string, cancelAll func(string, repository.CancelReason, string) (int, error)) {
+	cancelled, err := cancelAll("", repository.CancelReasonShutdown, "shutdown")
+	if err != nil {
+		app.logger.Warn("Failed to cancel pending conditional orders during shutdown", map[string]interface{}{
+			"market":    market,
+			"cancelled": cancelled,
+			"error":     err.Error(),
+		})
+		return
+	}
+
+	if cancelled > 0 {
+		app.logger.Info("Shutdown: Cancelled pending conditional orders", map[string]interface{}{
+			"market":    market,
+			"cancelled": cancelled,
+		})
+	}
+}
+
 // shutdownSpot performs graceful shutdown of spot components
 func (app *Application) shutdownSpot() error {
+	if app.spotTWAPExecutor != nil {
+		app.spotTWAPExecutor.Shutdown()
+	}
+	if app.spotBracketExecutor != nil {
+		app.spotBracketExecutor.Shutdown()
+	}
+	if app.spotAutomationSvc != nil {
+		if err := app.spotAutomationSvc.StopMonitoring(); err != nil {
+			app.logger.Debug("Automation plans were not running during shutdown", nil)
+		}
+	}
+
 	app.logger.Info("Shutdown: Stopping spot conditional order monitoring", nil)
 	
 	if app.spotConditionalOrderSvc != nil {
@@ -535,6 +1682,35 @@ func (app *Application) shutdownSpot() error {
 				return err
 			}
 		}
+
+		// Conditional orders kept in memory end with the process; stored ones are monitored
+		// again after a restart
+		if app.orderStorage == nil {
+			app.cancelPendingConditionalOrders("spot", app.spotConditionalOrderSvc.CancelAllConditionalOrders)
+		}
+	}
+
+	if app.spotMaintenanceMonitor != nil {
+		app.spotMaintenanceMonitor.Stop()
+	}
+
+	app.stopCoverageMonitoring(app.spotCoverageChecker)
+	app.stopPositionChangeMonitoring(app.spotPositionWatcher)
+	app.stopMaintenanceSchedule(app.spotMaintenanceSchedule)
+	app.stopSymbolStatusMonitoring(app.spotSymbolStatus)
+	app.stopDustConversion()
+
+	if app.spotPriceStream != nil {
+		app.spotPriceStream.Close()
+	}
+	if app.spotUserStream != nil {
+		app.spotUserStream.Close()
+	}
+
+	if app.spotDryRun != nil {
+		if err := app.spotDryRun.StopMonitoring(); err != nil {
+			app.logger.Debug("Dry run simulation was not running during shutdown", nil)
+		}
 	}
 
 	return nil
@@ -554,6 +1730,29 @@ func (app *Application) shutdownFutures() error {
 				return err
 			}
 		}
+
+		app.cancelPendingConditionalOrders("futures", app.futuresConditionalOrderSvc.CancelAllConditionalOrders)
+	}
+
+	// Stop following the mark price; local trailing stops are not kept across restarts
+	if app.futuresStopLossSvc != nil {
+		if err := app.futuresStopLossSvc.StopMonitoring(); err != nil {
+			app.logger.Debug("Futures stop order monitoring was not running during shutdown", nil)
+		}
+	}
+
+	app.stopCoverageMonitoring(app.futuresCoverageChecker)
+	app.stopPositionChangeMonitoring(app.futuresPositionWatcher)
+	app.stopMaintenanceSchedule(app.futuresMaintenanceSchedule)
+	app.stopSymbolStatusMonitoring(app.futuresSymbolStatus)
+
+	// Stop carry monitoring; open carries stay open on the exchange
+	if app.carrySvc != nil {
+		if err := app.carrySvc.StopMonitoring(); err != nil {
+			app.logger.Warn("Error stopping carry monitoring", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
 	}
 
 	// Stop funding rate monitoring
@@ -568,5 +1767,66 @@ func (app *Application) shutdownFutures() error {
 		}
 	}
 
+	if app.futuresDryRun != nil {
+		if err := app.futuresDryRun.StopMonitoring(); err != nil {
+			app.logger.Debug("Futures dry run simulation was not running during shutdown", nil)
+		}
+	}
+
+	return nil
+}
+
+// shutdownBoth stops the spot and futures stacks concurrently so both fit in the shared timeout
+func (app *Application) shutdownBoth() error {
+	spotDone := make(chan error, 1)
+	go func() {
+		spotDone <- app.shutdownSpot()
+	}()
+
+	futuresErr := app.shutdownFutures()
+	spotErr := <-spotDone
+
+	if spotErr != nil && futuresErr != nil {
+		return fmt.Errorf("spot: %v; futures: %w", spotErr, futuresErr)
+	}
+	if spotErr != nil {
+		return fmt.Errorf("spot: %w", spotErr)
+	}
+	if futuresErr != nil {
+		return fmt.Errorf("futures: %w", futuresErr)
+	}
+	return nil
+}
+
+// runReplay parses a journal log and prints its timeline and summary statistics
+func runReplay(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	flags.SetOutput(out)
+	symbol := flags.String("symbol", "", "only replay events for this symbol")
+	traceID := flags.String("trace", "", "only replay events with this trace ID")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: replay [-symbol SYMBOL] [-trace ID] <journal.jsonl>")
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	journal, err := replay.ParseJournal(file)
+	if err != nil {
+		return err
+	}
+
+	journal = journal.Filter(replay.Filter{Symbol: *symbol, TraceID: *traceID})
+
+	replay.WriteNarrative(out, journal)
+	fmt.Fprintln(out)
+	replay.WriteSummary(out, replay.Summarize(journal))
 	return nil
 }
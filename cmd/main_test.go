@@ -1,11 +1,20 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"binance-trader/internal/api"
 	"binance-trader/internal/config"
+	"binance-trader/internal/service"
 )
 
 // TestSpotEntryPointInitialization verifies that spot entry only initializes spot components
@@ -62,7 +71,7 @@ stop_loss:
 	defer os.Unsetenv("CONFIG_FILE")
 	
 	// Initialize spot application
-	app, err := initializeApplication(config.TradingTypeSpot)
+	app, err := initializeApplication(runOptions{tradingType: config.TradingTypeSpot})
 	if err != nil {
 		t.Fatalf("Failed to initialize spot application: %v", err)
 	}
@@ -94,7 +103,7 @@ stop_loss:
 	if app.spotStopLossSvc == nil {
 		t.Error("Spot stop loss service should be initialized")
 	}
-	if app.cli == nil {
+	if app.spotCLI == nil {
 		t.Error("CLI should be initialized for spot")
 	}
 	
@@ -177,7 +186,7 @@ stop_loss:
 	defer os.Unsetenv("CONFIG_FILE")
 	
 	// Initialize spot application with legacy config
-	app, err := initializeApplication(config.TradingTypeSpot)
+	app, err := initializeApplication(runOptions{tradingType: config.TradingTypeSpot})
 	if err != nil {
 		t.Fatalf("Failed to initialize spot application with legacy config: %v", err)
 	}
@@ -260,7 +269,7 @@ stop_loss:
 	defer os.Unsetenv("CONFIG_FILE")
 	
 	// Initialize futures application
-	app, err := initializeApplication(config.TradingTypeFutures)
+	app, err := initializeApplication(runOptions{tradingType: config.TradingTypeFutures})
 	if err != nil {
 		t.Fatalf("Failed to initialize futures application: %v", err)
 	}
@@ -319,3 +328,286 @@ stop_loss:
 		t.Error("Spot stop loss service should NOT be initialized for futures entry")
 	}
 }
+
+// TestBothEntryPointIsolation verifies that both mode initializes independent spot and futures stacks
+func TestBothEntryPointIsolation(t *testing.T) {
+	// Serve exchange responses in-process; the clients use the default transport
+	var spotRequests, futuresOrders int32
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		body := `{}`
+		switch {
+		case r.URL.Host == "fapi.binance.com" && r.URL.Path == "/fapi/v1/order":
+			atomic.AddInt32(&futuresOrders, 1)
+			body = `{"orderId":7,"symbol":"BTCUSDT","status":"NEW","side":"BUY","positionSide":"LONG","type":"MARKET","origQty":0.01}`
+		case r.URL.Host == "api.binance.com":
+			atomic.AddInt32(&spotRequests, 1)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    r,
+		}, nil
+	})
+	defer func() { http.DefaultTransport = defaultTransport }()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := fmt.Sprintf(`
+spot:
+  api_key: test_spot_key
+  api_secret: test_spot_secret
+  base_url: https://api.binance.com
+  testnet: true
+
+futures:
+  api_key: test_futures_key
+  api_secret: test_futures_secret
+  base_url: https://fapi.binance.com
+  testnet: true
+  default_leverage: 10
+  default_margin_type: CROSSED
+  risk:
+    max_order_value: 50000.0
+    max_position_value: 100000.0
+    max_leverage: 20
+    min_margin_ratio: 0.05
+    liquidation_buffer: 0.02
+    max_daily_orders: 200
+    max_api_calls_per_min: 2000
+  monitoring:
+    position_update_interval_ms: 5000
+    conditional_order_interval_ms: 1000
+    funding_rate_check_interval_ms: 60000
+
+risk:
+  max_order_amount: 1000.0
+  max_daily_orders: 1
+  min_balance_reserve: 100.0
+  max_api_calls_per_min: 1200
+
+logging:
+  level: info
+  file: %s
+  spot_file: %s
+  futures_file: %s
+  max_size_mb: 10
+  max_backups: 3
+
+retry:
+  max_attempts: 1
+  initial_delay_ms: 10
+  backoff_multiplier: 2.0
+
+conditional_orders:
+  monitoring_interval_ms: 1000
+  max_active_orders: 100
+  trigger_execution_timeout_ms: 5000
+  enable_smart_polling: true
+
+stop_loss:
+  default_trail_percent: 1.0
+  min_trail_percent: 0.1
+  max_trail_percent: 5.0
+  update_interval_ms: 1000
+`, filepath.Join(tmpDir, "both.log"), filepath.Join(tmpDir, "spot.log"), filepath.Join(tmpDir, "futures.log"))
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	os.Setenv("CONFIG_FILE", configPath)
+	defer os.Unsetenv("CONFIG_FILE")
+
+	app, err := initializeApplication(runOptions{tradingType: config.TradingTypeBoth})
+	if err != nil {
+		t.Fatalf("Failed to initialize combined application: %v", err)
+	}
+
+	if app.spotClient == nil || app.spotTradingService == nil || app.spotRiskMgr == nil || app.spotCLI == nil {
+		t.Error("Spot components should be initialized in both mode")
+	}
+	if app.futuresClient == nil || app.futuresTradingService == nil || app.futuresRiskManager == nil || app.futuresCLI == nil {
+		t.Error("Futures components should be initialized in both mode")
+	}
+	if app.combinedCLI == nil {
+		t.Error("Combined CLI should be initialized in both mode")
+	}
+
+	// Futures orders go through the futures client and leave the spot daily order budget untouched
+	if _, err := app.futuresTradingService.OpenLongPosition("BTCUSDT", 0.01, api.OrderTypeMarket, 0); err != nil {
+		t.Fatalf("Failed to open futures position: %v", err)
+	}
+	if atomic.LoadInt32(&futuresOrders) != 1 {
+		t.Errorf("Expected the order to reach the futures endpoint, got %d orders", futuresOrders)
+	}
+	spotBefore := atomic.LoadInt32(&spotRequests)
+	if err := app.spotRiskMgr.CheckDailyLimit(); err != nil {
+		t.Errorf("Spot daily limit should be unaffected by futures orders: %v", err)
+	}
+	if atomic.LoadInt32(&spotRequests) != spotBefore {
+		t.Error("Futures orders should not use the spot client")
+	}
+	if orders, _ := app.spotOrderRepo.FindBySymbol("BTCUSDT"); len(orders) != 0 {
+		t.Errorf("Futures orders should not be stored in the spot repository, got %d", len(orders))
+	}
+
+	// Both monitoring stacks start side by side and stop within the shared shutdown timeout
+	if err := app.startSpotMonitoring(); err != nil {
+		t.Fatalf("Failed to start spot monitoring: %v", err)
+	}
+	if err := app.startFuturesMonitoring(); err != nil {
+		t.Fatalf("Failed to start futures monitoring: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := app.shutdown(ctx); err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+}
+
+// TestSymbolFilterRoundingMode verifies that trading.rounding_mode reaches the symbol filter
+// orders are rounded with, for live and paper trading alike
+func TestSymbolFilterRoundingMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		trading string
+		paper   bool
+		want    service.RoundingMode
+	}{
+		{name: "default", trading: "", want: service.RoundingModeTruncate},
+		{name: "nearest", trading: "  rounding_mode: nearest\n", want: service.RoundingModeNearest},
+		{name: "conservative paper trading", trading: "  rounding_mode: conservative\n", paper: true, want: service.RoundingModeConservative},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, "config.yaml")
+			content := daemonTestConfig(tmpDir, false, "info") + "\ntrading:\n" + tt.trading
+			if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+			os.Setenv("CONFIG_FILE", configPath)
+			defer os.Unsetenv("CONFIG_FILE")
+
+			app, err := initializeApplication(runOptions{tradingType: config.TradingTypeSpot, paper: tt.paper})
+			if err != nil {
+				t.Fatalf("Failed to initialize application: %v", err)
+			}
+			defer app.shutdown(context.Background())
+
+			if app.spotSymbolFilter == nil {
+				t.Fatal("symbol filter not initialized")
+			}
+			if got := app.spotSymbolFilter.Mode(); got != tt.want {
+				t.Errorf("symbol filter rounding mode = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := newSymbolFilter(nil, &config.TradingConfig{RoundingMode: "ceiling"}); err == nil {
+		t.Error("newSymbolFilter() expected an error for an unknown rounding mode")
+	}
+}
+
+// TestDryRunKeysWireSimulators loads each documented dry run key and checks which markets the simulator answers
+func TestDryRunKeysWireSimulators(t *testing.T) {
+	// Answer exchange requests in-process and count the orders that reach the exchange
+	var spotOrders, futuresOrders int32
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.Method == http.MethodPost {
+			switch r.URL.Path {
+			case "/api/v3/order":
+				atomic.AddInt32(&spotOrders, 1)
+			case "/fapi/v1/order":
+				atomic.AddInt32(&futuresOrders, 1)
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+			Request:    r,
+		}, nil
+	})
+	defer func() { http.DefaultTransport = defaultTransport }()
+
+	tests := []struct {
+		name       string
+		spotKey    string
+		futuresKey string
+		extra      string
+		spot       bool
+		futures    bool
+	}{
+		{name: "live", spotKey: "false", futuresKey: "false"},
+		{name: "dry_run.enabled", spotKey: "false", futuresKey: "false", extra: "\ndry_run:\n  enabled: true\n", spot: true, futures: true},
+		{name: "trading.dry_run", spotKey: "false", futuresKey: "false", extra: "\ntrading:\n  dry_run: true\n", spot: true, futures: true},
+		{name: "spot.dry_run", spotKey: "true", futuresKey: "false", spot: true},
+		{name: "futures.dry_run", spotKey: "false", futuresKey: "true", futures: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, "config.yaml")
+			content := daemonTestConfig(tmpDir, false, "info") + `
+futures:
+  api_key: test_futures_key
+  api_secret: test_futures_secret
+  base_url: https://fapi.binance.com
+  testnet: true
+  default_leverage: 10
+  dry_run: ` + tt.futuresKey + `
+  risk:
+    max_order_value: 50000.0
+    max_position_value: 100000.0
+    max_leverage: 20
+    min_margin_ratio: 0.05
+` + tt.extra
+			// The spot section comes first in the shared daemon config
+			content = strings.Replace(content, "  testnet: true\n", "  testnet: true\n  dry_run: "+tt.spotKey+"\n", 1)
+			if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+			os.Setenv("CONFIG_FILE", configPath)
+			defer os.Unsetenv("CONFIG_FILE")
+
+			app, err := initializeApplication(runOptions{tradingType: config.TradingTypeBoth})
+			if err != nil {
+				t.Fatalf("Failed to initialize application: %v", err)
+			}
+			defer app.shutdown(context.Background())
+
+			if (app.spotDryRun != nil) != tt.spot {
+				t.Errorf("spot dry run simulator wired = %v, want %v", app.spotDryRun != nil, tt.spot)
+			}
+			if (app.futuresDryRun != nil) != tt.futures {
+				t.Errorf("futures dry run simulator wired = %v, want %v", app.futuresDryRun != nil, tt.futures)
+			}
+
+			// Whatever the outcome of the orders, only live markets may send them to the exchange
+			atomic.StoreInt32(&spotOrders, 0)
+			atomic.StoreInt32(&futuresOrders, 0)
+			app.spotClient.CreateOrder(&api.OrderRequest{Symbol: "BTCUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeLimit, Quantity: 0.01, Price: 100, TimeInForce: "GTC"})
+			app.futuresClient.CreateOrder(&api.FuturesOrderRequest{Symbol: "BTCUSDT", Side: api.OrderSideBuy, PositionSide: api.PositionSideLong, Type: api.OrderTypeLimit, Quantity: 0.01, Price: 100, TimeInForce: "GTC"})
+			if sent := atomic.LoadInt32(&spotOrders) > 0; sent == tt.spot {
+				t.Errorf("spot order sent to the exchange = %v with dry run %v", sent, tt.spot)
+			}
+			if sent := atomic.LoadInt32(&futuresOrders) > 0; sent == tt.futures {
+				t.Errorf("futures order sent to the exchange = %v with dry run %v", sent, tt.futures)
+			}
+		})
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// Signals a daemon handles in place instead of shutting down
+var (
+	reloadSignal os.Signal = syscall.SIGHUP  // Reload the config file
+	statusSignal os.Signal = syscall.SIGUSR1 // Log a status overview
+)
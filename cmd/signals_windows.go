@@ -0,0 +1,11 @@
+//go:build windows
+
+package main
+
+import "os"
+
+// Windows has no SIGHUP or SIGUSR1; a daemon there only handles shutdown
+var (
+	reloadSignal os.Signal
+	statusSignal os.Signal
+)
@@ -87,6 +87,14 @@ func (am *AuthManager) SerializeParams(params map[string]interface{}) string {
 		v := params[k]
 		var strValue string
 		
+		// Lists are sent as a repeated parameter, e.g. asset=ADA&asset=XRP
+		if list, ok := v.([]string); ok {
+			for _, item := range list {
+				values.Add(k, item)
+			}
+			continue
+		}
+		
 		switch val := v.(type) {
 		case string:
 			strValue = val
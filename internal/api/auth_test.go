@@ -208,6 +208,14 @@ func TestAuthManager_SerializeParams(t *testing.T) {
 			},
 			want: "quantity=1.5&symbol=BTCUSDT&test=true&timestamp=1234567890",
 		},
+		{
+			name: "repeated list param",
+			params: map[string]interface{}{
+				"asset":     []string{"XRP", "ADA"},
+				"timestamp": int64(1234567890),
+			},
+			want: "asset=XRP&asset=ADA&timestamp=1234567890",
+		},
 	}
 
 	for _, tt := range tests {
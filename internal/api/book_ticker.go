@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// BookTicker represents the best bid and ask of a symbol
+type BookTicker struct {
+	Symbol   string
+	BidPrice float64
+	BidQty   float64
+	AskPrice float64
+	AskQty   float64
+	UpdateID int64 // Order book update ID, 0 when not reported
+	Time     int64 // Exchange timestamp in milliseconds, 0 when not reported
+}
+
+// bookTickerEvent is a bookTicker stream frame; futures frames also carry event and transaction times.
+// Every key is tagged because encoding/json would otherwise match "e" to "E" case-insensitively.
+type bookTickerEvent struct {
+	EventType       string `json:"e"`
+	UpdateID        int64  `json:"u"`
+	Symbol          string `json:"s"`
+	BidPrice        string `json:"b"`
+	BidQty          string `json:"B"`
+	AskPrice        string `json:"a"`
+	AskQty          string `json:"A"`
+	EventTime       int64  `json:"E"`
+	TransactionTime int64  `json:"T"`
+}
+
+// bookTickerResponse is the REST bookTicker response
+type bookTickerResponse struct {
+	Symbol       string `json:"symbol"`
+	BidPrice     string `json:"bidPrice"`
+	BidQty       string `json:"bidQty"`
+	AskPrice     string `json:"askPrice"`
+	AskQty       string `json:"askQty"`
+	LastUpdateID int64  `json:"lastUpdateId"`
+	Time         int64  `json:"time"`
+}
+
+// ParseBookTickerEvent parses a spot or futures bookTicker stream frame, either raw or
+// wrapped in a combined stream envelope ({"stream": ..., "data": {...}})
+func ParseBookTickerEvent(data []byte) (*BookTicker, error) {
+	var envelope struct {
+		Stream string          `json:"stream"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse book ticker frame: %w", err)
+	}
+	if envelope.Stream != "" && len(envelope.Data) > 0 {
+		data = envelope.Data
+	}
+
+	var event bookTickerEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("failed to parse book ticker frame: %w", err)
+	}
+	if event.Symbol == "" {
+		return nil, fmt.Errorf("book ticker frame has no symbol")
+	}
+
+	ticker, err := newBookTicker(event.Symbol, event.BidPrice, event.BidQty, event.AskPrice, event.AskQty)
+	if err != nil {
+		return nil, err
+	}
+	ticker.UpdateID = event.UpdateID
+	ticker.Time = event.TransactionTime
+	if ticker.Time == 0 {
+		ticker.Time = event.EventTime
+	}
+	return ticker, nil
+}
+
+// parseBookTickerResponse parses a REST bookTicker response
+func parseBookTickerResponse(body []byte) (*BookTicker, error) {
+	var response bookTickerResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse book ticker: %w", err)
+	}
+
+	ticker, err := newBookTicker(response.Symbol, response.BidPrice, response.BidQty, response.AskPrice, response.AskQty)
+	if err != nil {
+		return nil, err
+	}
+	ticker.UpdateID = response.LastUpdateID
+	ticker.Time = response.Time
+	return ticker, nil
+}
+
+// newBookTicker builds a BookTicker from the string fields used by both REST and stream payloads
+func newBookTicker(symbol, bidPrice, bidQty, askPrice, askQty string) (*BookTicker, error) {
+	ticker := &BookTicker{Symbol: symbol}
+	fields := []struct {
+		name  string
+		value string
+		dest  *float64
+	}{
+		{"bid price", bidPrice, &ticker.BidPrice},
+		{"bid quantity", bidQty, &ticker.BidQty},
+		{"ask price", askPrice, &ticker.AskPrice},
+		{"ask quantity", askQty, &ticker.AskQty},
+	}
+
+	for _, field := range fields {
+		value, err := strconv.ParseFloat(field.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse book ticker %s %q: %w", field.name, field.value, err)
+		}
+		*field.dest = value
+	}
+	return ticker, nil
+}
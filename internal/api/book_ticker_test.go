@@ -0,0 +1,107 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBookTickerEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		frame    string
+		bid      float64
+		ask      float64
+		updateID int64
+		time     int64
+	}{
+		{
+			name:     "spot frame",
+			frame:    `{"u":400900217,"s":"BNBUSDT","b":"25.35190000","B":"31.21000000","a":"25.36520000","A":"40.66000000"}`,
+			bid:      25.3519,
+			ask:      25.3652,
+			updateID: 400900217,
+		},
+		{
+			name:     "futures frame",
+			frame:    `{"e":"bookTicker","u":400900217,"E":1568014460893,"T":1568014460891,"s":"BNBUSDT","b":"25.35190000","B":"31.21000000","a":"25.36520000","A":"40.66000000"}`,
+			bid:      25.3519,
+			ask:      25.3652,
+			updateID: 400900217,
+			time:     1568014460891,
+		},
+		{
+			name:     "combined stream envelope",
+			frame:    `{"stream":"btcusdt@bookTicker","data":{"u":1,"s":"BTCUSDT","b":"50000.10","B":"1.5","a":"50000.20","A":"2"}}`,
+			bid:      50000.10,
+			ask:      50000.20,
+			updateID: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticker, err := ParseBookTickerEvent([]byte(tt.frame))
+			if err != nil {
+				t.Fatalf("ParseBookTickerEvent failed: %v", err)
+			}
+			if ticker.BidPrice != tt.bid || ticker.AskPrice != tt.ask {
+				t.Errorf("expected bid %v ask %v, got %+v", tt.bid, tt.ask, ticker)
+			}
+			if ticker.UpdateID != tt.updateID || ticker.Time != tt.time {
+				t.Errorf("expected update %d time %d, got %+v", tt.updateID, tt.time, ticker)
+			}
+		})
+	}
+
+	invalid := []string{
+		`not json`,
+		`{"u":1,"b":"1","B":"1","a":"1","A":"1"}`,
+		`{"u":1,"s":"BTCUSDT","b":"abc","B":"1","a":"1","A":"1"}`,
+	}
+	for _, frame := range invalid {
+		if _, err := ParseBookTickerEvent([]byte(frame)); err == nil {
+			t.Errorf("expected error for frame %s", frame)
+		}
+	}
+}
+
+func TestGetBookTicker(t *testing.T) {
+	var requestedURL string
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			requestedURL = url
+			return []byte(`{"symbol":"BTCUSDT","bidPrice":"50000.10","bidQty":"1.5","askPrice":"50000.20","askQty":"2.0","lastUpdateId":42,"time":1700000000000}`), nil
+		},
+	}
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+
+	spot, err := NewSpotClient("https://api.binance.com", mockClient, authMgr)
+	if err != nil {
+		t.Fatalf("Failed to create spot client: %v", err)
+	}
+	ticker, err := spot.GetBookTicker("BTCUSDT")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(requestedURL, "/api/v3/ticker/bookTicker") {
+		t.Errorf("Expected spot bookTicker endpoint, got %s", requestedURL)
+	}
+	if ticker.Symbol != "BTCUSDT" || ticker.BidPrice != 50000.10 || ticker.AskQty != 2 || ticker.UpdateID != 42 {
+		t.Errorf("Unexpected book ticker: %+v", ticker)
+	}
+
+	futures, err := NewFuturesClient("https://fapi.binance.com", mockClient, authMgr)
+	if err != nil {
+		t.Fatalf("Failed to create futures client: %v", err)
+	}
+	ticker, err = futures.GetBookTicker("BTCUSDT")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(requestedURL, "/fapi/v1/ticker/bookTicker") {
+		t.Errorf("Expected futures bookTicker endpoint, got %s", requestedURL)
+	}
+	if ticker.Time != 1700000000000 {
+		t.Errorf("Expected exchange time, got %+v", ticker)
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// errCodeUnknownOrder is returned when the orders to cancel do not exist, e.g. a symbol without
+// open orders
+const errCodeUnknownOrder = -2011
+
+// BulkCancelFailure is an order a bulk cancel could not cancel
+type BulkCancelFailure struct {
+	OrderID int64 // 0 when the exchange did not say which order failed
+	Code    int   // Binance error code
+	Message string
+}
+
+// BulkCancelError reports the orders of a bulk cancel that failed; the orders that were cancelled
+// are returned alongside it
+type BulkCancelError struct {
+	Symbol    string
+	Cancelled int
+	Failures  []BulkCancelFailure
+}
+
+func (e *BulkCancelError) Error() string {
+	reasons := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		reason := fmt.Sprintf("%s (%d)", failure.Message, failure.Code)
+		if failure.OrderID > 0 {
+			reason = fmt.Sprintf("order %d: %s", failure.OrderID, reason)
+		}
+		reasons = append(reasons, reason)
+	}
+	return fmt.Sprintf("cancelled %d order(s) of %s, %d failed: %s",
+		e.Cancelled, e.Symbol, len(e.Failures), strings.Join(reasons, "; "))
+}
+
+// bulkCancelEntry is one entry of a bulk cancel response: a cancelled order, a cancelled order
+// list with its legs in OrderReports, or an error
+type bulkCancelEntry struct {
+	CancelResponse
+	Code         int              `json:"code"`
+	Msg          string           `json:"msg"`
+	OrderReports []CancelResponse `json:"orderReports"`
+}
+
+// parseBulkCancelResponse splits the entries of a bulk cancel response into the cancelled orders
+// and the failures; orderIDs names the order of each entry when the request listed them
+func parseBulkCancelResponse(body []byte, orderIDs []int64) ([]CancelResponse, []BulkCancelFailure, error) {
+	var entries []bulkCancelEntry
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse bulk cancel response: %w", err)
+	}
+
+	var cancelled []CancelResponse
+	var failures []BulkCancelFailure
+	for i, entry := range entries {
+		switch {
+		case entry.Code < 0:
+			failure := BulkCancelFailure{Code: entry.Code, Message: entry.Msg}
+			if i < len(orderIDs) {
+				failure.OrderID = orderIDs[i]
+			}
+			failures = append(failures, failure)
+		case len(entry.OrderReports) > 0:
+			cancelled = append(cancelled, entry.OrderReports...)
+		default:
+			cancelled = append(cancelled, entry.CancelResponse)
+		}
+	}
+	return cancelled, failures, nil
+}
@@ -1,15 +1,24 @@
 package api
 
-// AccountInfo represents account information from Binance
+import (
+	"time"
+
+	"binance-trader/pkg/metrics"
+)
+
+// AccountInfo represents account information from Binance. Commissions are in basis points.
 type AccountInfo struct {
-	MakerCommission  int64
-	TakerCommission  int64
-	BuyerCommission  int64
-	SellerCommission int64
+	MakerCommission  float64
+	TakerCommission  float64
+	BuyerCommission  float64
+	SellerCommission float64
 	CanTrade         bool
 	CanWithdraw      bool
 	CanDeposit       bool
 	UpdateTime       int64
+	AccountType      string    // e.g. SPOT
+	Permissions      []string  // e.g. SPOT, MARGIN
+	Balances         []Balance // Assets with a non-zero free or locked balance
 }
 
 // Balance represents an asset balance
@@ -25,6 +34,57 @@ type Price struct {
 	Price  float64
 }
 
+// SystemStatus represents the exchange system status
+type SystemStatus struct {
+	Status int    `json:"status"`
+	Msg    string `json:"msg"`
+}
+
+const (
+	SystemStatusNormal      = 0
+	SystemStatusMaintenance = 1
+)
+
+// IsMaintenance returns true if the exchange reports system maintenance
+func (s *SystemStatus) IsMaintenance() bool {
+	return s.Status == SystemStatusMaintenance
+}
+
+// SymbolStatusTrading is the exchange info status of a symbol open for trading; others
+// include BREAK (halted or delisted), HALT, PENDING_TRADING and, for futures, SETTLING and CLOSE
+const SymbolStatusTrading = "TRADING"
+
+// ExchangeSymbol is the trading status of one symbol in exchange info
+type ExchangeSymbol struct {
+	Symbol string `json:"symbol"`
+	Status string `json:"status"`
+}
+
+// IsTrading reports whether orders can currently be placed for the symbol
+func (s *ExchangeSymbol) IsTrading() bool {
+	return s.Status == SymbolStatusTrading
+}
+
+// SymbolInfo holds the order filters of one symbol from exchange info; a zero limit means
+// the exchange does not set it
+type SymbolInfo struct {
+	Symbol      string
+	Status      string
+	MinQty      float64 // LOT_SIZE
+	MaxQty      float64
+	StepSize    float64
+	MinPrice    float64 // PRICE_FILTER
+	MaxPrice    float64
+	TickSize    float64
+	MinNotional float64 // MIN_NOTIONAL, or the minimum of NOTIONAL
+}
+
+// ExchangeInfo holds the trading rules of every symbol
+type ExchangeInfo struct {
+	ServerTime int64
+	Symbols    []*SymbolInfo
+}
+
 // Kline represents candlestick data
 type Kline struct {
 	OpenTime  int64
@@ -36,6 +96,31 @@ type Kline struct {
 	CloseTime int64
 }
 
+// klineIntervals maps the exchange's kline intervals to their length
+var klineIntervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"3m":  3 * time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+	"1h":  time.Hour,
+	"2h":  2 * time.Hour,
+	"4h":  4 * time.Hour,
+	"6h":  6 * time.Hour,
+	"8h":  8 * time.Hour,
+	"12h": 12 * time.Hour,
+	"1d":  24 * time.Hour,
+	"3d":  72 * time.Hour,
+	"1w":  7 * 24 * time.Hour,
+}
+
+// KlineIntervalDuration returns the length of a kline interval such as "1h"; monthly
+// klines have no fixed length and are not supported
+func KlineIntervalDuration(interval string) (time.Duration, bool) {
+	duration, ok := klineIntervals[interval]
+	return duration, ok
+}
+
 // OrderSide represents order side (BUY/SELL)
 type OrderSide string
 
@@ -50,6 +135,14 @@ type OrderType string
 const (
 	OrderTypeMarket OrderType = "MARKET"
 	OrderTypeLimit  OrderType = "LIMIT"
+
+	// Order types of the legs of an OCO pair
+	OrderTypeLimitMaker    OrderType = "LIMIT_MAKER"
+	OrderTypeStopLoss      OrderType = "STOP_LOSS"
+	OrderTypeStopLossLimit OrderType = "STOP_LOSS_LIMIT"
+
+	// OrderTypeTrailingStopMarket is a futures stop that follows the best price by a callback rate
+	OrderTypeTrailingStopMarket OrderType = "TRAILING_STOP_MARKET"
 )
 
 // OrderStatus represents order status
@@ -72,12 +165,19 @@ type OrderRequest struct {
 	Quantity    float64
 	Price       float64
 	TimeInForce string
+
+	// QuoteOrderQty is the quote asset amount a market order spends or receives, set instead of Quantity
+	QuoteOrderQty float64
+
+	// NewClientOrderID is an optional caller-chosen ID used to recognise the order after a restart
+	NewClientOrderID string
 }
 
 // OrderResponse represents the response from creating an order
 type OrderResponse struct {
 	OrderID                 int64
 	Symbol                  string
+	ClientOrderID           string
 	Status                  OrderStatus
 	Price                   float64
 	OrigQty                 float64
@@ -90,15 +190,38 @@ type OrderResponse struct {
 type Order struct {
 	OrderID                 int64
 	Symbol                  string
+	ClientOrderID           string
 	Side                    OrderSide
 	Type                    OrderType
 	Status                  OrderStatus
 	Price                   float64
+	StopPrice               float64 // Trigger price of STOP_LOSS and TAKE_PROFIT orders
 	OrigQty                 float64
 	ExecutedQty             float64
 	CummulativeQuoteQty     float64
 	Time                    int64
 	UpdateTime              int64
+	OrderListID             int64 // Order list (OCO) of the leg; the exchange reports -1 for standalone orders
+}
+
+// InOrderList reports whether the order is a leg of an order list such as an OCO pair
+func (o *Order) InOrderList() bool {
+	return o.OrderListID > 0
+}
+
+// Trade represents a single fill of one of the account's orders
+type Trade struct {
+	ID              int64
+	Symbol          string
+	OrderID         int64
+	Price           float64
+	Qty             float64
+	QuoteQty        float64
+	Commission      float64
+	CommissionAsset string
+	Time            int64
+	IsBuyer         bool
+	IsMaker         bool
 }
 
 // CancelResponse represents the response from canceling an order
@@ -109,6 +232,93 @@ type CancelResponse struct {
 	Status            OrderStatus
 }
 
+// OrderListStatusType is the status of an order list as a whole
+type OrderListStatusType string
+
+const (
+	OrderListStatusResponse    OrderListStatusType = "RESPONSE"
+	OrderListStatusExecStarted OrderListStatusType = "EXEC_STARTED"
+	OrderListStatusAllDone     OrderListStatusType = "ALL_DONE"
+)
+
+// OrderListOrder identifies one leg of an order list
+type OrderListOrder struct {
+	Symbol        string
+	OrderID       int64
+	ClientOrderID string
+}
+
+// OrderList represents an order list such as an OCO pair. Cancelling any leg on the
+// exchange cancels the whole list.
+type OrderList struct {
+	OrderListID       int64
+	ContingencyType   string // OCO
+	ListStatusType    OrderListStatusType
+	ListOrderStatus   string // EXECUTING, ALL_DONE or REJECT
+	ListClientOrderID string
+	TransactionTime   int64
+	Symbol            string
+	Orders            []*OrderListOrder
+	OrderReports      []*Order // Final state of each leg; only returned by cancellation
+}
+
+// IsDone reports whether every leg of the list is in a final state
+func (l *OrderList) IsDone() bool {
+	return l.ListStatusType == OrderListStatusAllDone
+}
+
+// OCORequest represents a request to place an OCO pair: a limit leg at Price and a stop leg
+// that triggers once StopPrice trades. The stop leg places a limit order at StopLimitPrice,
+// or a market order when StopLimitPrice is 0.
+type OCORequest struct {
+	Symbol         string
+	Side           OrderSide
+	Quantity       float64
+	Price          float64
+	StopPrice      float64
+	StopLimitPrice float64
+}
+
+// OCOResponse represents the order list created for an OCO pair. The exchange places both
+// legs atomically and a fill of either cancels the other.
+type OCOResponse struct {
+	OrderList
+	LimitOrderID int64 // LIMIT_MAKER leg
+	StopOrderID  int64 // STOP_LOSS or STOP_LOSS_LIMIT leg
+}
+
+// DustAsset is a small spot balance the exchange can convert to BNB
+type DustAsset struct {
+	Asset      string
+	AmountFree float64
+	ToBTC      float64
+	ToBNB      float64 // Estimated BNB received before the service charge
+}
+
+// DustEligibility lists the balances currently convertible to BNB
+type DustEligibility struct {
+	Details            []*DustAsset
+	TotalTransferBNB   float64
+	DribbletPercentage float64 // Service charge rate of the conversion
+}
+
+// DustTransfer is the conversion result of one asset
+type DustTransfer struct {
+	FromAsset           string
+	Amount              float64
+	TransferredAmount   float64 // BNB received
+	ServiceChargeAmount float64
+	TranID              int64
+	OperateTime         int64
+}
+
+// DustConversionResult is the result of converting dust balances to BNB
+type DustConversionResult struct {
+	TotalServiceCharge float64
+	TotalTransferred   float64 // Total BNB received
+	Transfers          []*DustTransfer
+}
+
 // BinanceClient is an alias for SpotClient for backward compatibility
 // Deprecated: Use SpotClient instead
 type BinanceClient = SpotClient
@@ -117,6 +327,20 @@ type BinanceClient = SpotClient
 type HTTPClient interface {
 	Do(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error)
 	DoWithRetry(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error)
+
+	// Category-aware variants apply the timeout configured for the endpoint category
+	DoWithCategory(category EndpointCategory, method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error)
+	DoWithRetryCategory(category EndpointCategory, method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error)
+
+	// Signed requests rebuild their URL for every attempt so a retry is never sent with a stale timestamp
+	DoSignedWithRetryCategory(category EndpointCategory, method string, buildURL func() (string, error), headers map[string]string) ([]byte, error)
+
+	// Rate-limit usage recorded from the latest responses
+	GetRateLimitStatus() *RateLimitStatus
+	SetRateLimits(rules []RateLimitRule)
+
+	// Request metrics are recorded in the sink; a new client records them nowhere
+	SetMetrics(sink *metrics.Sink)
 }
 
 // NewBinanceClient creates a new Binance API client
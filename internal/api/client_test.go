@@ -3,8 +3,13 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
+	"binance-trader/pkg/errors"
+	"binance-trader/pkg/metrics"
+
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
 	"github.com/leanovate/gopter/prop"
@@ -30,6 +35,30 @@ func (m *mockHTTPClient) DoWithRetry(method, url string, params map[string]inter
 	return nil, fmt.Errorf("not implemented")
 }
 
+func (m *mockHTTPClient) DoWithCategory(category EndpointCategory, method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+	return m.Do(method, url, params, headers)
+}
+
+func (m *mockHTTPClient) DoWithRetryCategory(category EndpointCategory, method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+	return m.DoWithRetry(method, url, params, headers)
+}
+
+func (m *mockHTTPClient) DoSignedWithRetryCategory(category EndpointCategory, method string, buildURL func() (string, error), headers map[string]string) ([]byte, error) {
+	url, err := buildURL()
+	if err != nil {
+		return nil, err
+	}
+	return m.DoWithRetry(method, url, nil, headers)
+}
+
+func (m *mockHTTPClient) GetRateLimitStatus() *RateLimitStatus {
+	return &RateLimitStatus{}
+}
+
+func (m *mockHTTPClient) SetRateLimits(rules []RateLimitRule) {}
+
+func (m *mockHTTPClient) SetMetrics(sink *metrics.Sink) {}
+
 // Feature: binance-auto-trading, Property 4: 价格数据结构完整性
 // Validates: Requirements 2.1
 // For any trading pair price query response, the returned data must contain a valid price value (greater than 0)
@@ -410,6 +439,86 @@ func TestGetBalance(t *testing.T) {
 	}
 }
 
+func TestGetAccountInfo(t *testing.T) {
+	var requested string
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			requested = url
+			return []byte(`{
+				"makerCommission": 10,
+				"takerCommission": 10,
+				"canTrade": true,
+				"canWithdraw": false,
+				"updateTime": 1700000000000,
+				"accountType": "SPOT",
+				"permissions": ["SPOT", "MARGIN"],
+				"balances": [
+					{"asset": "BTC", "free": "0.50000000", "locked": "0.10000000"},
+					{"asset": "LTC", "free": "0.00000000", "locked": "0.00000000"},
+					{"asset": "USDT", "free": "0.00000000", "locked": "125.50000000"}
+				]
+			}`), nil
+		},
+	}
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	client, _ := NewBinanceClient("https://api.binance.com", mockClient, authMgr)
+
+	info, err := client.GetAccountInfo()
+	if err != nil {
+		t.Fatalf("GetAccountInfo() unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(requested, "https://api.binance.com/api/v3/account?") || !strings.Contains(requested, "timestamp=") || !strings.Contains(requested, "&signature=") {
+		t.Errorf("requested %s, want a signed /api/v3/account", requested)
+	}
+	if !info.CanTrade || info.CanWithdraw || info.MakerCommission != 10 || info.TakerCommission != 10 || info.UpdateTime != 1700000000000 {
+		t.Errorf("account info = %+v", info)
+	}
+	if info.AccountType != "SPOT" || !reflect.DeepEqual(info.Permissions, []string{"SPOT", "MARGIN"}) {
+		t.Errorf("account type %q with permissions %v, want SPOT with SPOT and MARGIN", info.AccountType, info.Permissions)
+	}
+
+	want := []Balance{{Asset: "BTC", Free: 0.5, Locked: 0.1}, {Asset: "USDT", Locked: 125.5}}
+	if len(info.Balances) != len(want) {
+		t.Fatalf("balances = %+v, want the non-zero %+v", info.Balances, want)
+	}
+	for i := range want {
+		if info.Balances[i] != want[i] {
+			t.Errorf("balance %d = %+v, want %+v", i, info.Balances[i], want[i])
+		}
+	}
+
+	mockClient.doWithRetryFunc = func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+		return []byte(`{"balances": [{"asset": "BTC", "free": "lots", "locked": "0"}]}`), nil
+	}
+	if _, err := client.GetAccountInfo(); err == nil {
+		t.Error("expected an error for a malformed balance")
+	}
+}
+
+func TestGetAllPrices(t *testing.T) {
+	var requested string
+	var requestParams map[string]interface{}
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			requested, requestParams = url, params
+			return []byte(`[{"symbol": "BTCUSDT", "price": "50000.10"}, {"symbol": "ETHBTC", "price": "0.05000000"}]`), nil
+		},
+	}
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	client, _ := NewBinanceClient("https://api.binance.com", mockClient, authMgr)
+
+	prices, err := client.GetAllPrices()
+	if err != nil {
+		t.Fatalf("GetAllPrices() unexpected error: %v", err)
+	}
+	if requested != "https://api.binance.com/api/v3/ticker/price" || len(requestParams) != 0 {
+		t.Errorf("requested %s with %v, want the ticker of every symbol", requested, requestParams)
+	}
+	if len(prices) != 2 || prices[0].Symbol != "BTCUSDT" || prices[0].Price != 50000.1 || prices[1].Price != 0.05 {
+		t.Errorf("GetAllPrices() = %+v", prices)
+	}
+}
+
 // Feature: binance-auto-trading, Property 8: 市价单类型正确性
 // Validates: Requirements 3.1
 // For any market buy order request, the order type field must be set to MARKET and should not include a price field
@@ -1049,6 +1158,75 @@ func TestCreateOrder(t *testing.T) {
 	}
 }
 
+// Unit test for CreateOrder with a client order ID
+func TestCreateOrderClientOrderID(t *testing.T) {
+	var requestedURL string
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			requestedURL = url
+			return []byte(`{"orderId":12345,"symbol":"BTCUSDT","clientOrderId":"dca-btc-1","status":"FILLED"}`), nil
+		},
+	}
+
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	client, _ := NewBinanceClient("https://api.binance.com", mockClient, authMgr)
+
+	resp, err := client.CreateOrder(&OrderRequest{
+		Symbol:           "BTCUSDT",
+		Side:             OrderSideBuy,
+		Type:             OrderTypeMarket,
+		Quantity:         0.1,
+		NewClientOrderID: "dca-btc-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(requestedURL, "newClientOrderId=dca-btc-1") {
+		t.Errorf("expected newClientOrderId in request, got %s", requestedURL)
+	}
+	if resp.ClientOrderID != "dca-btc-1" {
+		t.Errorf("expected client order ID in response, got %q", resp.ClientOrderID)
+	}
+}
+
+func TestCreateOrderQuoteOrderQty(t *testing.T) {
+	var requestedURL string
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			requestedURL = url
+			return []byte(`{"orderId":12345,"symbol":"BTCUSDT","status":"FILLED"}`), nil
+		},
+	}
+
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	client, _ := NewBinanceClient("https://api.binance.com", mockClient, authMgr)
+
+	if _, err := client.CreateOrder(&OrderRequest{
+		Symbol:        "BTCUSDT",
+		Side:          OrderSideBuy,
+		Type:          OrderTypeMarket,
+		QuoteOrderQty: 100,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(requestedURL, "quoteOrderQty=100") || strings.Contains(requestedURL, "quantity=") {
+		t.Errorf("expected quoteOrderQty without quantity in request, got %s", requestedURL)
+	}
+
+	requestedURL = ""
+	for _, order := range []*OrderRequest{
+		{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeMarket, Quantity: 0.002, QuoteOrderQty: 100},
+		{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeLimit, Price: 50000, QuoteOrderQty: 100},
+	} {
+		if _, err := client.CreateOrder(order); err == nil {
+			t.Errorf("expected error for %+v", order)
+		}
+	}
+	if requestedURL != "" {
+		t.Errorf("expected rejected orders not to be sent, got %s", requestedURL)
+	}
+}
+
 // Unit test for CancelOrder
 func TestCancelOrder(t *testing.T) {
 	tests := []struct {
@@ -1113,6 +1291,129 @@ func TestCancelOrder(t *testing.T) {
 	}
 }
 
+// Unit test for CancelOrderList
+func TestCancelOrderList(t *testing.T) {
+	var requested string
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			requested = method + " " + url
+			return []byte(`{
+				"orderListId":7,
+				"contingencyType":"OCO",
+				"listStatusType":"ALL_DONE",
+				"listOrderStatus":"ALL_DONE",
+				"transactionTime":1700000000000,
+				"symbol":"BTCUSDT",
+				"orders":[{"symbol":"BTCUSDT","orderId":101},{"symbol":"BTCUSDT","orderId":102}],
+				"orderReports":[
+					{"symbol":"BTCUSDT","orderId":101,"orderListId":7,"status":"CANCELED","type":"STOP_LOSS_LIMIT"},
+					{"symbol":"BTCUSDT","orderId":102,"orderListId":7,"status":"CANCELED","type":"LIMIT_MAKER"}
+				]
+			}`), nil
+		},
+	}
+
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	client, _ := NewSpotClient("https://api.binance.com", mockClient, authMgr)
+
+	list, err := client.CancelOrderList("BTCUSDT", 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(requested, "DELETE https://api.binance.com/api/v3/orderList?") || !strings.Contains(requested, "orderListId=7") {
+		t.Errorf("unexpected request %s", requested)
+	}
+	if !list.IsDone() || len(list.Orders) != 2 || len(list.OrderReports) != 2 {
+		t.Fatalf("unexpected order list %+v", list)
+	}
+	for _, leg := range list.OrderReports {
+		if !leg.InOrderList() || leg.OrderListID != 7 || leg.Status != OrderStatusCanceled {
+			t.Errorf("unexpected leg %+v", leg)
+		}
+	}
+
+	if _, err := client.CancelOrderList("BTCUSDT", -1); err == nil {
+		t.Error("expected error for a standalone order list ID")
+	}
+}
+
+// Unit test for CreateOCOOrder
+func TestCreateOCOOrder(t *testing.T) {
+	var requested string
+	response := `"orderReports":[
+		{"symbol":"BTCUSDT","orderId":101,"orderListId":7,"status":"NEW","type":"STOP_LOSS_LIMIT","side":"SELL"},
+		{"symbol":"BTCUSDT","orderId":102,"orderListId":7,"status":"NEW","type":"LIMIT_MAKER","side":"SELL"}
+	]`
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			requested = method + " " + url
+			return []byte(`{
+				"orderListId":7,
+				"contingencyType":"OCO",
+				"listStatusType":"EXEC_STARTED",
+				"listOrderStatus":"EXECUTING",
+				"transactionTime":1700000000000,
+				"symbol":"BTCUSDT",
+				"orders":[{"symbol":"BTCUSDT","orderId":101},{"symbol":"BTCUSDT","orderId":102}],
+				` + response + `
+			}`), nil
+		},
+	}
+
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	client, _ := NewSpotClient("https://api.binance.com", mockClient, authMgr)
+
+	tests := []struct {
+		name    string
+		request *OCORequest
+		params  []string
+	}{
+		{
+			name:    "sell with stop limit leg",
+			request: &OCORequest{Symbol: "BTCUSDT", Side: OrderSideSell, Quantity: 0.1, Price: 55000, StopPrice: 48000, StopLimitPrice: 47900},
+			params: []string{"side=SELL", "aboveType=LIMIT_MAKER", "abovePrice=55000", "belowType=STOP_LOSS_LIMIT",
+				"belowStopPrice=48000", "belowPrice=47900", "belowTimeInForce=GTC"},
+		},
+		{
+			name:    "buy with stop market leg",
+			request: &OCORequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Quantity: 0.1, Price: 45000, StopPrice: 52000},
+			params:  []string{"side=BUY", "belowType=LIMIT_MAKER", "belowPrice=45000", "aboveType=STOP_LOSS&", "aboveStopPrice=52000"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oco, err := client.CreateOCOOrder(tt.request)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.HasPrefix(requested, "POST https://api.binance.com/api/v3/orderList/oco?") {
+				t.Errorf("unexpected request %s", requested)
+			}
+			for _, param := range tt.params {
+				if !strings.Contains(requested, param) {
+					t.Errorf("request %s is missing %s", requested, param)
+				}
+			}
+			if oco.OrderListID != 7 || oco.LimitOrderID != 102 || oco.StopOrderID != 101 || len(oco.OrderReports) != 2 {
+				t.Errorf("unexpected OCO response %+v", oco)
+			}
+		})
+	}
+
+	// A response without both legs is an error rather than a half-known order list
+	response = `"orderReports":[]`
+	if _, err := client.CreateOCOOrder(tests[0].request); err == nil {
+		t.Error("expected error for a response missing its legs")
+	}
+
+	if _, err := client.CreateOCOOrder(&OCORequest{Symbol: "BTCUSDT", Side: OrderSideSell, Quantity: 0.1, Price: 55000}); err == nil {
+		t.Error("expected error for a missing stop price")
+	}
+	if _, err := client.CreateOCOOrder(&OCORequest{Symbol: "BTCUSDT", Quantity: 0.1, Price: 55000, StopPrice: 48000}); err == nil {
+		t.Error("expected error for a missing side")
+	}
+}
+
 // Unit test for GetOrder
 func TestGetOrder(t *testing.T) {
 	tests := []struct {
@@ -1322,3 +1623,274 @@ func TestGetHistoricalOrders(t *testing.T) {
 		})
 	}
 }
+
+// Unit test for GetSystemStatus
+func TestGetSystemStatus(t *testing.T) {
+	tests := []struct {
+		name              string
+		mockResp          string
+		expectError       bool
+		expectMaintenance bool
+	}{
+		{
+			name:              "normal status",
+			mockResp:          `{"status":0,"msg":"normal"}`,
+			expectError:       false,
+			expectMaintenance: false,
+		},
+		{
+			name:              "maintenance status",
+			mockResp:          `{"status":1,"msg":"system_maintenance"}`,
+			expectError:       false,
+			expectMaintenance: true,
+		},
+		{
+			name:        "invalid json response",
+			mockResp:    `invalid json`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requestedURL string
+			mockClient := &mockHTTPClient{
+				doFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+					requestedURL = url
+					return []byte(tt.mockResp), nil
+				},
+			}
+
+			authMgr, _ := NewAuthManager("test_key", "test_secret")
+			client, _ := NewSpotClient("https://api.binance.com", mockClient, authMgr)
+
+			status, err := client.GetSystemStatus()
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if requestedURL != "https://api.binance.com/sapi/v1/system/status" {
+				t.Errorf("unexpected request URL: %s", requestedURL)
+			}
+			if status.IsMaintenance() != tt.expectMaintenance {
+				t.Errorf("expected maintenance %v, got %v", tt.expectMaintenance, status.IsMaintenance())
+			}
+		})
+	}
+}
+
+// Unit test for GetMyTrades
+func TestGetMyTrades(t *testing.T) {
+	var requestedURL string
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			requestedURL = url
+			return []byte(`[
+				{"symbol":"BTCUSDT","id":501,"orderId":12345,"price":"50000.00","qty":"0.00060000","quoteQty":"30.00000000",
+				 "commission":"0.00000060","commissionAsset":"BTC","time":1700000001000,"isBuyer":true,"isMaker":false},
+				{"symbol":"BTCUSDT","id":502,"orderId":12345,"price":"50010.00","qty":"0.00040000","quoteQty":"20.00400000",
+				 "commission":"0.00000040","commissionAsset":"BTC","time":1700000002000,"isBuyer":true,"isMaker":true}
+			]`), nil
+		},
+	}
+
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	client, _ := NewSpotClient("https://api.binance.com", mockClient, authMgr)
+
+	trades, err := client.GetMyTrades("BTCUSDT", 12345)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(requestedURL, "/api/v3/myTrades?") || !strings.Contains(requestedURL, "orderId=12345") {
+		t.Errorf("unexpected request URL: %s", requestedURL)
+	}
+	if len(trades) != 2 {
+		t.Fatalf("expected 2 trades, got %d", len(trades))
+	}
+	if trades[0].ID != 501 || trades[0].OrderID != 12345 || trades[0].Price != 50000 || trades[0].Qty != 0.0006 {
+		t.Errorf("unexpected first trade: %+v", trades[0])
+	}
+	if trades[1].Commission != 0.0000004 || trades[1].CommissionAsset != "BTC" || !trades[1].IsMaker {
+		t.Errorf("unexpected second trade: %+v", trades[1])
+	}
+
+	if _, err := client.GetMyTrades("", 12345); err == nil {
+		t.Error("expected error for empty symbol")
+	}
+	if _, err := client.GetMyTrades("BTCUSDT", 0); err == nil {
+		t.Error("expected error for invalid orderID")
+	}
+}
+
+// Unit test for GetDustAssets
+func TestGetDustAssets(t *testing.T) {
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			if method != "POST" || !strings.Contains(url, "/sapi/v1/asset/dust-btc?") {
+				t.Errorf("unexpected request %s %s", method, url)
+			}
+			return []byte(`{"details":[{"asset":"ADA","assetFullName":"ADA","amountFree":"6.21","toBTC":"0.00016848","toBNB":"0.01777302","toBNBOffExchange":"0.01741756","exchange":"0.00035546"}],"totalTransferBtc":"0.00016848","totalTransferBNB":"0.01777302","dribbletPercentage":"0.02"}`), nil
+		},
+	}
+
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	client, _ := NewBinanceClient("https://api.binance.com", mockClient, authMgr)
+
+	eligibility, err := client.GetDustAssets()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(eligibility.Details) != 1 || eligibility.Details[0].Asset != "ADA" || eligibility.Details[0].ToBNB != 0.01777302 {
+		t.Errorf("unexpected dust assets: %+v", eligibility.Details)
+	}
+	if eligibility.DribbletPercentage != 0.02 {
+		t.Errorf("expected dribblet percentage 0.02, got %v", eligibility.DribbletPercentage)
+	}
+}
+
+// Unit test for ConvertDust
+func TestConvertDust(t *testing.T) {
+	var requestedURL, requestedMethod string
+	var retried bool
+	mockClient := &mockHTTPClient{
+		doFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			requestedMethod, requestedURL = method, url
+			if headers["X-MBX-APIKEY"] != "test_key" {
+				t.Errorf("expected API key header, got %v", headers)
+			}
+			return []byte(`{"totalServiceCharge":"0.02102542","totalTransfered":"1.05127099","transferResult":[{"amount":"0.03000000","fromAsset":"ETH","operateTime":1563368549307,"serviceChargeAmount":"0.00500000","tranId":2970932918,"transferedAmount":"0.25000000"},{"amount":"0.09000000","fromAsset":"LTC","operateTime":1563368549404,"serviceChargeAmount":"0.01548000","tranId":2970932918,"transferedAmount":"0.77400000"}]}`), nil
+		},
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			retried = true
+			return nil, fmt.Errorf("conversion must not be retried")
+		},
+	}
+
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	client, _ := NewBinanceClient("https://api.binance.com", mockClient, authMgr)
+
+	if _, err := client.ConvertDust(nil); err == nil {
+		t.Error("expected error when no asset is given")
+	}
+
+	result, err := client.ConvertDust([]string{"ETH", "LTC"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if retried {
+		t.Error("dust conversion should be sent without retries")
+	}
+	if requestedMethod != "POST" || !strings.HasPrefix(requestedURL, "https://api.binance.com/sapi/v1/asset/dust?asset=ETH&asset=LTC&timestamp=") {
+		t.Errorf("unexpected request %s %s", requestedMethod, requestedURL)
+	}
+	if !strings.Contains(requestedURL, "&signature=") {
+		t.Errorf("dust conversion request should be signed, got %s", requestedURL)
+	}
+
+	if result.TotalTransferred != 1.05127099 || result.TotalServiceCharge != 0.02102542 {
+		t.Errorf("unexpected totals: %+v", result)
+	}
+	if len(result.Transfers) != 2 || result.Transfers[1].FromAsset != "LTC" || result.Transfers[1].TransferredAmount != 0.774 {
+		t.Errorf("unexpected transfers: %+v", result.Transfers)
+	}
+}
+
+// Unit test for GetExchangeInfo
+func TestGetExchangeInfo(t *testing.T) {
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			if method != "GET" || !strings.HasSuffix(url, "/api/v3/exchangeInfo") {
+				t.Errorf("unexpected request %s %s", method, url)
+			}
+			return []byte(`{"serverTime":1700000000000,"symbols":[{"symbol":"BTCUSDT","status":"TRADING","filters":[` +
+				`{"filterType":"PRICE_FILTER","minPrice":"0.01000000","maxPrice":"1000000.00000000","tickSize":"0.01000000"},` +
+				`{"filterType":"LOT_SIZE","minQty":"0.00001000","maxQty":"9000.00000000","stepSize":"0.00001000"},` +
+				`{"filterType":"ICEBERG_PARTS","limit":10},` +
+				`{"filterType":"NOTIONAL","minNotional":"5.00000000","applyMinToMarket":true,"maxNotional":"9000000.00000000"}]},` +
+				`{"symbol":"ETHBTC","status":"BREAK","filters":[{"filterType":"MIN_NOTIONAL","minNotional":"0.00010000"}]}]}`), nil
+		},
+	}
+
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	client, _ := NewBinanceClient("https://api.binance.com", mockClient, authMgr)
+
+	info, err := client.GetExchangeInfo()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.ServerTime != 1700000000000 || len(info.Symbols) != 2 {
+		t.Fatalf("unexpected exchange info: %+v", info)
+	}
+	want := SymbolInfo{Symbol: "BTCUSDT", Status: "TRADING", MinQty: 0.00001, MaxQty: 9000, StepSize: 0.00001,
+		MinPrice: 0.01, MaxPrice: 1000000, TickSize: 0.01, MinNotional: 5}
+	if *info.Symbols[0] != want {
+		t.Errorf("BTCUSDT filters = %+v, want %+v", *info.Symbols[0], want)
+	}
+	if info.Symbols[1].MinNotional != 0.0001 || info.Symbols[1].StepSize != 0 {
+		t.Errorf("ETHBTC filters = %+v, want only a minimum notional of 0.0001", *info.Symbols[1])
+	}
+}
+
+func TestBulkCancelOrders(t *testing.T) {
+	var requestedMethod, requestedURL string
+	response := `[
+		{"symbol":"BTCUSDT","origClientOrderId":"a","orderId":11,"orderListId":-1,"status":"CANCELED"},
+		{"orderListId":7,"contingencyType":"OCO","orderReports":[
+			{"symbol":"BTCUSDT","orderId":12,"orderListId":7,"status":"CANCELED"},
+			{"symbol":"BTCUSDT","orderId":13,"orderListId":7,"status":"CANCELED"}]},
+		{"code":-2011,"msg":"Unknown order sent."}
+	]`
+	var responseErr error
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			requestedMethod, requestedURL = method, url
+			if responseErr != nil {
+				return nil, responseErr
+			}
+			return []byte(response), nil
+		},
+	}
+
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	client, _ := NewBinanceClient("https://api.binance.com", mockClient, authMgr)
+
+	// The plain order and both OCO legs are cancelled, the error entry fails
+	cancelled, err := client.BulkCancelOrders("BTCUSDT")
+	if requestedMethod != "DELETE" || !strings.Contains(requestedURL, "/api/v3/openOrders?") || !strings.Contains(requestedURL, "symbol=BTCUSDT") {
+		t.Errorf("unexpected request %s %s", requestedMethod, requestedURL)
+	}
+	if len(cancelled) != 3 || cancelled[0].OrderID != 11 || cancelled[1].OrderID != 12 || cancelled[2].OrderID != 13 {
+		t.Errorf("cancelled = %+v, want orders 11, 12 and 13", cancelled)
+	}
+	bulkErr, ok := err.(*BulkCancelError)
+	if !ok || bulkErr.Cancelled != 3 || len(bulkErr.Failures) != 1 || bulkErr.Failures[0].Code != -2011 {
+		t.Fatalf("error = %v, want a bulk cancel error with one -2011 failure", err)
+	}
+	if !strings.Contains(err.Error(), "cancelled 3 order(s) of BTCUSDT, 1 failed: Unknown order sent. (-2011)") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+
+	// Every order cancelled
+	response = `[{"symbol":"BTCUSDT","orderId":11,"status":"CANCELED"}]`
+	if cancelled, err := client.BulkCancelOrders("BTCUSDT"); err != nil || len(cancelled) != 1 {
+		t.Errorf("BulkCancelOrders() = %+v, %v; want one cancelled order", cancelled, err)
+	}
+
+	// A symbol without open orders is rejected with -2011, which cancels nothing
+	responseErr = errors.NewTradingError(errors.ErrInvalidParameter, "HTTP client error: 400", 400,
+		newResponseBodyError([]byte(`{"code":-2011,"msg":"Unknown order sent."}`)))
+	if cancelled, err := client.BulkCancelOrders("BTCUSDT"); err != nil || len(cancelled) != 0 {
+		t.Errorf("BulkCancelOrders() without open orders = %+v, %v; want nothing cancelled", cancelled, err)
+	}
+
+	if _, err := client.BulkCancelOrders(""); err == nil {
+		t.Error("expected an error for an empty symbol")
+	}
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 )
 
 // PositionSide represents position side for futures
@@ -32,8 +33,9 @@ const (
 
 // FuturesAccountInfo represents futures account information
 type FuturesAccountInfo struct {
-	Assets                      []FuturesAsset
+	Assets                      []FuturesAssetBalance
 	Positions                   []Position
+	AvailableBalance            float64 // Margin free for new positions across all assets
 	CanDeposit                  bool
 	CanTrade                    bool
 	CanWithdraw                 bool
@@ -49,8 +51,8 @@ type FuturesAccountInfo struct {
 	UpdateTime                  int64
 }
 
-// FuturesAsset represents a futures asset balance
-type FuturesAsset struct {
+// FuturesAssetBalance represents the balance of one margin asset of the futures account
+type FuturesAssetBalance struct {
 	Asset                  string
 	WalletBalance          float64
 	UnrealizedProfit       float64
@@ -126,6 +128,8 @@ type FuturesOrderRequest struct {
 	TimeInForce      string
 	ReduceOnly       bool
 	ClosePosition    bool
+	CallbackRate     float64 // Trailing stop callback in percent, for TRAILING_STOP_MARKET
+	ActivationPrice  float64 // Price that activates a trailing stop; 0 activates it at once
 }
 
 // FuturesOrderResponse represents a futures order response
@@ -186,15 +190,17 @@ type LeverageResponse struct {
 // FuturesClient defines the interface for interacting with Binance Futures API
 type FuturesClient interface {
 	// Account information
-	GetAccountInfo() (*FuturesAccountInfo, error)
+	GetFuturesAccountInfo() (*FuturesAccountInfo, error)
 	GetBalance() (*FuturesBalance, error)
 
 	// Market data
 	GetMarkPrice(symbol string) (*MarkPrice, error)
 	GetPrice(symbol string) (*Price, error)
+	GetBookTicker(symbol string) (*BookTicker, error)
 	GetKlines(symbol string, interval string, limit int) ([]*Kline, error)
 	GetFundingRate(symbol string) (*FundingRate, error)
 	GetFundingRateHistory(symbol string, startTime, endTime int64) ([]*FundingRate, error)
+	GetExchangeSymbols() ([]*ExchangeSymbol, error)
 
 	// Leverage and margin
 	SetLeverage(symbol string, leverage int) (*LeverageResponse, error)
@@ -205,6 +211,9 @@ type FuturesClient interface {
 	// Order operations
 	CreateOrder(order *FuturesOrderRequest) (*FuturesOrderResponse, error)
 	CancelOrder(symbol string, orderID int64) (*CancelResponse, error)
+	// BulkCancelFuturesOrders cancels every open order of a symbol; when some fail, the
+	// cancelled orders come with a *BulkCancelError
+	BulkCancelFuturesOrders(symbol string) ([]CancelResponse, error)
 	GetOrder(symbol string, orderID int64) (*FuturesOrder, error)
 	GetOpenOrders(symbol string) ([]*FuturesOrder, error)
 
@@ -246,37 +255,190 @@ func NewFuturesClient(baseURL string, httpClient HTTPClient, authMgr *AuthManage
 	}, nil
 }
 
-// GetAccountInfo retrieves futures account information
-func (c *futuresClient) GetAccountInfo() (*FuturesAccountInfo, error) {
-	params := make(map[string]interface{})
-	params["timestamp"] = c.authMgr.GenerateTimestamp()
-
-	queryString, err := c.authMgr.SignRequestWithParams(params)
-	if err != nil {
-		return nil, err
+// signedURL returns a builder for a signed endpoint URL that re-stamps and re-signs the
+// params on every call, so each retry goes out with a fresh timestamp
+func (c *futuresClient) signedURL(path string, params map[string]interface{}) func() (string, error) {
+	return func() (string, error) {
+		params["timestamp"] = c.authMgr.GenerateTimestamp()
+		queryString, err := c.authMgr.SignRequestWithParams(params)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s%s?%s", c.baseURL, path, queryString), nil
 	}
+}
 
-	url := fmt.Sprintf("%s/fapi/v2/account?%s", c.baseURL, queryString)
+// GetFuturesAccountInfo retrieves futures account information: wallet, margin and available
+// balances with the per-asset balances and positions
+func (c *futuresClient) GetFuturesAccountInfo() (*FuturesAccountInfo, error) {
+	params := make(map[string]interface{})
 	headers := map[string]string{
 		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
 	}
 
-	body, err := c.httpClient.DoWithRetry("GET", url, nil, headers)
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryAccount, "GET", c.signedURL("/fapi/v2/account", params), headers)
 	if err != nil {
 		return nil, err
 	}
 
-	var accountInfo FuturesAccountInfo
-	if err := json.Unmarshal(body, &accountInfo); err != nil {
+	return parseFuturesAccountInfo(body)
+}
+
+// futuresAccountResponse is the /fapi/v2/account response, which encodes amounts as strings
+type futuresAccountResponse struct {
+	FeeTier                     int    `json:"feeTier"`
+	CanTrade                    bool   `json:"canTrade"`
+	CanDeposit                  bool   `json:"canDeposit"`
+	CanWithdraw                 bool   `json:"canWithdraw"`
+	UpdateTime                  int64  `json:"updateTime"`
+	TotalInitialMargin          string `json:"totalInitialMargin"`
+	TotalMaintMargin            string `json:"totalMaintMargin"`
+	TotalWalletBalance          string `json:"totalWalletBalance"`
+	TotalUnrealizedProfit       string `json:"totalUnrealizedProfit"`
+	TotalMarginBalance          string `json:"totalMarginBalance"`
+	TotalPositionInitialMargin  string `json:"totalPositionInitialMargin"`
+	TotalOpenOrderInitialMargin string `json:"totalOpenOrderInitialMargin"`
+	AvailableBalance            string `json:"availableBalance"`
+	MaxWithdrawAmount           string `json:"maxWithdrawAmount"`
+	Assets                      []struct {
+		Asset                  string `json:"asset"`
+		WalletBalance          string `json:"walletBalance"`
+		UnrealizedProfit       string `json:"unrealizedProfit"`
+		MarginBalance          string `json:"marginBalance"`
+		MaintMargin            string `json:"maintMargin"`
+		InitialMargin          string `json:"initialMargin"`
+		PositionInitialMargin  string `json:"positionInitialMargin"`
+		OpenOrderInitialMargin string `json:"openOrderInitialMargin"`
+		CrossWalletBalance     string `json:"crossWalletBalance"`
+		CrossUnPnl             string `json:"crossUnPnl"`
+		AvailableBalance       string `json:"availableBalance"`
+		MaxWithdrawAmount      string `json:"maxWithdrawAmount"`
+		MarginAvailable        bool   `json:"marginAvailable"`
+		UpdateTime             int64  `json:"updateTime"`
+	} `json:"assets"`
+	Positions []struct {
+		Symbol                string `json:"symbol"`
+		PositionSide          string `json:"positionSide"`
+		PositionAmt           string `json:"positionAmt"`
+		EntryPrice            string `json:"entryPrice"`
+		UnrealizedProfit      string `json:"unrealizedProfit"`
+		PositionInitialMargin string `json:"positionInitialMargin"`
+		MaintMargin           string `json:"maintMargin"`
+		Leverage              string `json:"leverage"`
+		Isolated              bool   `json:"isolated"`
+		UpdateTime            int64  `json:"updateTime"`
+	} `json:"positions"`
+}
+
+// accountAmount is a string-encoded amount of the account response and where it is parsed to
+type accountAmount struct {
+	name  string
+	raw   string
+	value *float64
+}
+
+// parseAccountAmounts parses string-encoded amounts; an empty string is left at zero
+func parseAccountAmounts(amounts []accountAmount) error {
+	for _, amount := range amounts {
+		if amount.raw == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(amount.raw, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse futures account %s %q: %w", amount.name, amount.raw, err)
+		}
+		*amount.value = value
+	}
+	return nil
+}
+
+// parseFuturesAccountInfo parses a /fapi/v2/account response
+func parseFuturesAccountInfo(body []byte) (*FuturesAccountInfo, error) {
+	var response futuresAccountResponse
+	if err := json.Unmarshal(body, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse futures account info: %w", err)
 	}
 
-	return &accountInfo, nil
+	accountInfo := &FuturesAccountInfo{
+		CanDeposit:  response.CanDeposit,
+		CanTrade:    response.CanTrade,
+		CanWithdraw: response.CanWithdraw,
+		FeeTier:     response.FeeTier,
+		UpdateTime:  response.UpdateTime,
+	}
+	if err := parseAccountAmounts([]accountAmount{
+		{"total initial margin", response.TotalInitialMargin, &accountInfo.TotalInitialMargin},
+		{"total maintenance margin", response.TotalMaintMargin, &accountInfo.TotalMaintMargin},
+		{"total wallet balance", response.TotalWalletBalance, &accountInfo.TotalWalletBalance},
+		{"total unrealized profit", response.TotalUnrealizedProfit, &accountInfo.TotalUnrealizedProfit},
+		{"total margin balance", response.TotalMarginBalance, &accountInfo.TotalMarginBalance},
+		{"total position initial margin", response.TotalPositionInitialMargin, &accountInfo.TotalPositionInitialMargin},
+		{"total open order initial margin", response.TotalOpenOrderInitialMargin, &accountInfo.TotalOpenOrderInitialMargin},
+		{"available balance", response.AvailableBalance, &accountInfo.AvailableBalance},
+		{"max withdraw amount", response.MaxWithdrawAmount, &accountInfo.MaxWithdrawAmount},
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, data := range response.Assets {
+		asset := FuturesAssetBalance{
+			Asset:           data.Asset,
+			MarginAvailable: data.MarginAvailable,
+			UpdateTime:      data.UpdateTime,
+		}
+		if err := parseAccountAmounts([]accountAmount{
+			{data.Asset + " wallet balance", data.WalletBalance, &asset.WalletBalance},
+			{data.Asset + " unrealized profit", data.UnrealizedProfit, &asset.UnrealizedProfit},
+			{data.Asset + " margin balance", data.MarginBalance, &asset.MarginBalance},
+			{data.Asset + " maintenance margin", data.MaintMargin, &asset.MaintMargin},
+			{data.Asset + " initial margin", data.InitialMargin, &asset.InitialMargin},
+			{data.Asset + " position initial margin", data.PositionInitialMargin, &asset.PositionInitialMargin},
+			{data.Asset + " open order initial margin", data.OpenOrderInitialMargin, &asset.OpenOrderInitialMargin},
+			{data.Asset + " cross wallet balance", data.CrossWalletBalance, &asset.CrossWalletBalance},
+			{data.Asset + " cross unrealized profit", data.CrossUnPnl, &asset.CrossUnPnl},
+			{data.Asset + " available balance", data.AvailableBalance, &asset.AvailableBalance},
+			{data.Asset + " max withdraw amount", data.MaxWithdrawAmount, &asset.MaxWithdrawAmount},
+		}); err != nil {
+			return nil, err
+		}
+		accountInfo.Assets = append(accountInfo.Assets, asset)
+	}
+
+	for _, data := range response.Positions {
+		position := Position{
+			Symbol:       data.Symbol,
+			PositionSide: PositionSide(data.PositionSide),
+			MarginType:   MarginTypeCrossed,
+			UpdateTime:   data.UpdateTime,
+		}
+		if data.Isolated {
+			position.MarginType = MarginTypeIsolated
+		}
+		if err := parseAccountAmounts([]accountAmount{
+			{data.Symbol + " position amount", data.PositionAmt, &position.PositionAmt},
+			{data.Symbol + " entry price", data.EntryPrice, &position.EntryPrice},
+			{data.Symbol + " unrealized profit", data.UnrealizedProfit, &position.UnrealizedProfit},
+			{data.Symbol + " position initial margin", data.PositionInitialMargin, &position.PositionInitialMargin},
+			{data.Symbol + " maintenance margin", data.MaintMargin, &position.MaintenanceMargin},
+		}); err != nil {
+			return nil, err
+		}
+		if data.Leverage != "" {
+			leverage, err := strconv.Atoi(data.Leverage)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse futures account %s leverage %q: %w", data.Symbol, data.Leverage, err)
+			}
+			position.Leverage = leverage
+		}
+		accountInfo.Positions = append(accountInfo.Positions, position)
+	}
+
+	return accountInfo, nil
 }
 
 // GetBalance retrieves the USDT balance for futures account
 func (c *futuresClient) GetBalance() (*FuturesBalance, error) {
-	accountInfo, err := c.GetAccountInfo()
+	accountInfo, err := c.GetFuturesAccountInfo()
 	if err != nil {
 		return nil, err
 	}
@@ -308,7 +470,7 @@ func (c *futuresClient) GetMarkPrice(symbol string) (*MarkPrice, error) {
 
 	url := fmt.Sprintf("%s/fapi/v1/premiumIndex", c.baseURL)
 
-	body, err := c.httpClient.DoWithRetry("GET", url, params, nil)
+	body, err := c.httpClient.DoWithRetryCategory(EndpointCategoryMarketData, "GET", url, params, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -329,7 +491,7 @@ func (c *futuresClient) GetPrice(symbol string) (*Price, error) {
 
 	url := fmt.Sprintf("%s/fapi/v1/ticker/price", c.baseURL)
 
-	body, err := c.httpClient.DoWithRetry("GET", url, params, nil)
+	body, err := c.httpClient.DoWithRetryCategory(EndpointCategoryMarketData, "GET", url, params, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -354,6 +516,22 @@ func (c *futuresClient) GetPrice(symbol string) (*Price, error) {
 	}, nil
 }
 
+// GetBookTicker retrieves the best bid and ask for a symbol
+func (c *futuresClient) GetBookTicker(symbol string) (*BookTicker, error) {
+	params := map[string]interface{}{
+		"symbol": symbol,
+	}
+
+	url := fmt.Sprintf("%s/fapi/v1/ticker/bookTicker", c.baseURL)
+
+	body, err := c.httpClient.DoWithRetryCategory(EndpointCategoryMarketData, "GET", url, params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseBookTickerResponse(body)
+}
+
 // GetKlines retrieves candlestick data for a symbol
 func (c *futuresClient) GetKlines(symbol string, interval string, limit int) ([]*Kline, error) {
 	params := map[string]interface{}{
@@ -364,7 +542,7 @@ func (c *futuresClient) GetKlines(symbol string, interval string, limit int) ([]
 
 	url := fmt.Sprintf("%s/fapi/v1/klines", c.baseURL)
 
-	body, err := c.httpClient.DoWithRetry("GET", url, params, nil)
+	body, err := c.httpClient.DoWithRetryCategory(EndpointCategoryMarketData, "GET", url, params, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -419,7 +597,7 @@ func (c *futuresClient) GetFundingRate(symbol string) (*FundingRate, error) {
 
 	url := fmt.Sprintf("%s/fapi/v1/premiumIndex", c.baseURL)
 
-	body, err := c.httpClient.DoWithRetry("GET", url, params, nil)
+	body, err := c.httpClient.DoWithRetryCategory(EndpointCategoryMarketData, "GET", url, params, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -462,7 +640,7 @@ func (c *futuresClient) GetFundingRateHistory(symbol string, startTime, endTime
 
 	url := fmt.Sprintf("%s/fapi/v1/fundingRate", c.baseURL)
 
-	body, err := c.httpClient.DoWithRetry("GET", url, params, nil)
+	body, err := c.httpClient.DoWithRetryCategory(EndpointCategoryMarketData, "GET", url, params, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -491,6 +669,25 @@ func (c *futuresClient) GetFundingRateHistory(symbol string, startTime, endTime
 	return rates, nil
 }
 
+// GetExchangeSymbols retrieves the trading status of every contract from exchange info
+func (c *futuresClient) GetExchangeSymbols() ([]*ExchangeSymbol, error) {
+	url := fmt.Sprintf("%s/fapi/v1/exchangeInfo", c.baseURL)
+
+	body, err := c.httpClient.DoWithRetryCategory(EndpointCategoryMarketData, "GET", url, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var exchangeInfo struct {
+		Symbols []*ExchangeSymbol `json:"symbols"`
+	}
+	if err := json.Unmarshal(body, &exchangeInfo); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange info: %w", err)
+	}
+
+	return exchangeInfo.Symbols, nil
+}
+
 // SetLeverage sets leverage for a symbol
 func (c *futuresClient) SetLeverage(symbol string, leverage int) (*LeverageResponse, error) {
 	if leverage < 1 || leverage > 125 {
@@ -500,19 +697,11 @@ func (c *futuresClient) SetLeverage(symbol string, leverage int) (*LeverageRespo
 	params := make(map[string]interface{})
 	params["symbol"] = symbol
 	params["leverage"] = leverage
-	params["timestamp"] = c.authMgr.GenerateTimestamp()
-
-	queryString, err := c.authMgr.SignRequestWithParams(params)
-	if err != nil {
-		return nil, err
-	}
-
-	url := fmt.Sprintf("%s/fapi/v1/leverage?%s", c.baseURL, queryString)
 	headers := map[string]string{
 		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
 	}
 
-	body, err := c.httpClient.DoWithRetry("POST", url, nil, headers)
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryAccount, "POST", c.signedURL("/fapi/v1/leverage", params), headers)
 	if err != nil {
 		return nil, err
 	}
@@ -525,63 +714,51 @@ func (c *futuresClient) SetLeverage(symbol string, leverage int) (*LeverageRespo
 	return &response, nil
 }
 
-// SetMarginType sets margin type for a symbol
+// errCodeNoNeedToChangeMarginType is returned when the symbol already uses the requested margin type
+const errCodeNoNeedToChangeMarginType = -4046
+
+// SetMarginType sets margin type for a symbol; a symbol already using it is not an error
 func (c *futuresClient) SetMarginType(symbol string, marginType MarginType) error {
 	params := make(map[string]interface{})
 	params["symbol"] = symbol
 	params["marginType"] = string(marginType)
-	params["timestamp"] = c.authMgr.GenerateTimestamp()
-
-	queryString, err := c.authMgr.SignRequestWithParams(params)
-	if err != nil {
-		return err
-	}
-
-	url := fmt.Sprintf("%s/fapi/v1/marginType?%s", c.baseURL, queryString)
 	headers := map[string]string{
 		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
 	}
 
-	_, err = c.httpClient.DoWithRetry("POST", url, nil, headers)
+	_, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryAccount, "POST", c.signedURL("/fapi/v1/marginType", params), headers)
+	if binanceErrorCodeOf(err) == errCodeNoNeedToChangeMarginType {
+		return nil
+	}
 	return err
 }
 
-// SetPositionMode sets position mode (dual side or one way)
+// errCodeNoNeedToChangePositionSide is returned when the account already uses the requested position mode
+const errCodeNoNeedToChangePositionSide = -4059
+
+// SetPositionMode sets position mode (dual side or one way); an account already using it is not an error
 func (c *futuresClient) SetPositionMode(dualSidePosition bool) error {
 	params := make(map[string]interface{})
 	params["dualSidePosition"] = dualSidePosition
-	params["timestamp"] = c.authMgr.GenerateTimestamp()
-
-	queryString, err := c.authMgr.SignRequestWithParams(params)
-	if err != nil {
-		return err
-	}
-
-	url := fmt.Sprintf("%s/fapi/v1/positionSide/dual?%s", c.baseURL, queryString)
 	headers := map[string]string{
 		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
 	}
 
-	_, err = c.httpClient.DoWithRetry("POST", url, nil, headers)
+	_, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryAccount, "POST", c.signedURL("/fapi/v1/positionSide/dual", params), headers)
+	if binanceErrorCodeOf(err) == errCodeNoNeedToChangePositionSide {
+		return nil
+	}
 	return err
 }
 
 // GetPositionMode retrieves current position mode
 func (c *futuresClient) GetPositionMode() (*PositionMode, error) {
 	params := make(map[string]interface{})
-	params["timestamp"] = c.authMgr.GenerateTimestamp()
-
-	queryString, err := c.authMgr.SignRequestWithParams(params)
-	if err != nil {
-		return nil, err
-	}
-
-	url := fmt.Sprintf("%s/fapi/v1/positionSide/dual?%s", c.baseURL, queryString)
 	headers := map[string]string{
 		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
 	}
 
-	body, err := c.httpClient.DoWithRetry("GET", url, nil, headers)
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryAccount, "GET", c.signedURL("/fapi/v1/positionSide/dual", params), headers)
 	if err != nil {
 		return nil, err
 	}
@@ -605,7 +782,6 @@ func (c *futuresClient) CreateOrder(order *FuturesOrderRequest) (*FuturesOrderRe
 	params["side"] = string(order.Side)
 	params["type"] = string(order.Type)
 	params["quantity"] = order.Quantity
-	params["timestamp"] = c.authMgr.GenerateTimestamp()
 
 	if order.PositionSide != "" {
 		params["positionSide"] = string(order.PositionSide)
@@ -635,17 +811,21 @@ func (c *futuresClient) CreateOrder(order *FuturesOrderRequest) (*FuturesOrderRe
 		params["closePosition"] = "true"
 	}
 
-	queryString, err := c.authMgr.SignRequestWithParams(params)
-	if err != nil {
-		return nil, err
+	if order.Type == OrderTypeTrailingStopMarket {
+		if order.CallbackRate <= 0 {
+			return nil, fmt.Errorf("callback rate must be greater than 0 for trailing stop orders")
+		}
+		params["callbackRate"] = order.CallbackRate
+		if order.ActivationPrice > 0 {
+			params["activationPrice"] = order.ActivationPrice
+		}
 	}
 
-	url := fmt.Sprintf("%s/fapi/v1/order?%s", c.baseURL, queryString)
 	headers := map[string]string{
 		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
 	}
 
-	body, err := c.httpClient.DoWithRetry("POST", url, nil, headers)
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryOrder, "POST", c.signedURL("/fapi/v1/order", params), headers)
 	if err != nil {
 		return nil, err
 	}
@@ -670,19 +850,11 @@ func (c *futuresClient) CancelOrder(symbol string, orderID int64) (*CancelRespon
 	params := make(map[string]interface{})
 	params["symbol"] = symbol
 	params["orderId"] = orderID
-	params["timestamp"] = c.authMgr.GenerateTimestamp()
-
-	queryString, err := c.authMgr.SignRequestWithParams(params)
-	if err != nil {
-		return nil, err
-	}
-
-	url := fmt.Sprintf("%s/fapi/v1/order?%s", c.baseURL, queryString)
 	headers := map[string]string{
 		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
 	}
 
-	body, err := c.httpClient.DoWithRetry("DELETE", url, nil, headers)
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryOrder, "DELETE", c.signedURL("/fapi/v1/order", params), headers)
 	if err != nil {
 		return nil, err
 	}
@@ -695,6 +867,70 @@ func (c *futuresClient) CancelOrder(symbol string, orderID int64) (*CancelRespon
 	return &response, nil
 }
 
+// maxBatchCancelOrders is the most orders one /fapi/v1/batchOrders cancel request may name
+const maxBatchCancelOrders = 10
+
+// BulkCancelFuturesOrders cancels the open orders of a symbol through batch cancel requests of up
+// to maxBatchCancelOrders, which report each order's result. Orders placed after the open orders
+// are listed are not cancelled.
+func (c *futuresClient) BulkCancelFuturesOrders(symbol string) ([]CancelResponse, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol cannot be empty")
+	}
+
+	openOrders, err := c.GetOpenOrders(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open orders: %w", err)
+	}
+
+	var cancelled []CancelResponse
+	var failures []BulkCancelFailure
+	for start := 0; start < len(openOrders); start += maxBatchCancelOrders {
+		end := min(start+maxBatchCancelOrders, len(openOrders))
+		orderIDs := make([]int64, 0, end-start)
+		for _, order := range openOrders[start:end] {
+			orderIDs = append(orderIDs, order.OrderID)
+		}
+
+		batch, batchFailures, err := c.cancelBatch(symbol, orderIDs)
+		if err != nil {
+			// The whole batch failed; the next one may still go through
+			for _, orderID := range orderIDs {
+				failures = append(failures, BulkCancelFailure{OrderID: orderID, Code: binanceErrorCodeOf(err), Message: err.Error()})
+			}
+			continue
+		}
+		cancelled = append(cancelled, batch...)
+		failures = append(failures, batchFailures...)
+	}
+
+	if len(failures) > 0 {
+		return cancelled, &BulkCancelError{Symbol: symbol, Cancelled: len(cancelled), Failures: failures}
+	}
+	return cancelled, nil
+}
+
+// cancelBatch cancels up to maxBatchCancelOrders orders of a symbol in one request
+func (c *futuresClient) cancelBatch(symbol string, orderIDs []int64) ([]CancelResponse, []BulkCancelFailure, error) {
+	idList, err := json.Marshal(orderIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	params := make(map[string]interface{})
+	params["symbol"] = symbol
+	params["orderIdList"] = string(idList)
+	headers := map[string]string{
+		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
+	}
+
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryOrder, "DELETE", c.signedURL("/fapi/v1/batchOrders", params), headers)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseBulkCancelResponse(body, orderIDs)
+}
+
 // GetOrder retrieves futures order details
 func (c *futuresClient) GetOrder(symbol string, orderID int64) (*FuturesOrder, error) {
 	if symbol == "" {
@@ -707,19 +943,11 @@ func (c *futuresClient) GetOrder(symbol string, orderID int64) (*FuturesOrder, e
 	params := make(map[string]interface{})
 	params["symbol"] = symbol
 	params["orderId"] = orderID
-	params["timestamp"] = c.authMgr.GenerateTimestamp()
-
-	queryString, err := c.authMgr.SignRequestWithParams(params)
-	if err != nil {
-		return nil, err
-	}
-
-	url := fmt.Sprintf("%s/fapi/v1/order?%s", c.baseURL, queryString)
 	headers := map[string]string{
 		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
 	}
 
-	body, err := c.httpClient.DoWithRetry("GET", url, nil, headers)
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryOrder, "GET", c.signedURL("/fapi/v1/order", params), headers)
 	if err != nil {
 		return nil, err
 	}
@@ -738,19 +966,11 @@ func (c *futuresClient) GetOpenOrders(symbol string) ([]*FuturesOrder, error) {
 	if symbol != "" {
 		params["symbol"] = symbol
 	}
-	params["timestamp"] = c.authMgr.GenerateTimestamp()
-
-	queryString, err := c.authMgr.SignRequestWithParams(params)
-	if err != nil {
-		return nil, err
-	}
-
-	url := fmt.Sprintf("%s/fapi/v1/openOrders?%s", c.baseURL, queryString)
 	headers := map[string]string{
 		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
 	}
 
-	body, err := c.httpClient.DoWithRetry("GET", url, nil, headers)
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryOrder, "GET", c.signedURL("/fapi/v1/openOrders", params), headers)
 	if err != nil {
 		return nil, err
 	}
@@ -766,19 +986,15 @@ func (c *futuresClient) GetOpenOrders(symbol string) ([]*FuturesOrder, error) {
 // GetPositions retrieves positions for a specific symbol
 func (c *futuresClient) GetPositions(symbol string) ([]*Position, error) {
 	params := make(map[string]interface{})
-	params["timestamp"] = c.authMgr.GenerateTimestamp()
-
-	queryString, err := c.authMgr.SignRequestWithParams(params)
-	if err != nil {
-		return nil, err
+	if symbol != "" {
+		// Let the exchange filter by symbol instead of returning every position
+		params["symbol"] = symbol
 	}
-
-	url := fmt.Sprintf("%s/fapi/v2/positionRisk?%s", c.baseURL, queryString)
 	headers := map[string]string{
 		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
 	}
 
-	body, err := c.httpClient.DoWithRetry("GET", url, nil, headers)
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryAccount, "GET", c.signedURL("/fapi/v2/positionRisk", params), headers)
 	if err != nil {
 		return nil, err
 	}
@@ -2,8 +2,12 @@ package api
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
+	"binance-trader/pkg/errors"
+
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
 	"github.com/leanovate/gopter/prop"
@@ -245,3 +249,324 @@ func TestProperty3_RequestSignatureCorrectness(t *testing.T) {
 
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
+
+// TestGetPositionsSymbolParameter verifies that single-symbol position queries are filtered by the exchange
+func TestGetPositionsSymbolParameter(t *testing.T) {
+	var requestedURL string
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			requestedURL = url
+			return []byte(`[
+				{"symbol":"BTCUSDT","positionSide":"LONG","positionAmt":0.5},
+				{"symbol":"BTCUSDT","positionSide":"SHORT","positionAmt":-0.2}
+			]`), nil
+		},
+	}
+
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	client, err := NewFuturesClient("https://fapi.binance.com", mockClient, authMgr)
+	if err != nil {
+		t.Fatalf("Failed to create futures client: %v", err)
+	}
+
+	positions, err := client.GetPositions("BTCUSDT")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.Contains(requestedURL, "/fapi/v2/positionRisk?") {
+		t.Errorf("Expected positionRisk endpoint, got %s", requestedURL)
+	}
+	if !strings.Contains(requestedURL, "symbol=BTCUSDT") {
+		t.Errorf("Expected symbol parameter in request, got %s", requestedURL)
+	}
+	if len(positions) != 2 {
+		t.Fatalf("Expected LONG and SHORT entries, got %d positions", len(positions))
+	}
+
+	// Querying all positions must not send a symbol parameter
+	if _, err := client.GetAllPositions(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(requestedURL, "symbol=") {
+		t.Errorf("Expected no symbol parameter for all positions, got %s", requestedURL)
+	}
+}
+
+// TestCreateTrailingStopOrder verifies that trailing stops send their callback rate and activation price
+func TestCreateTrailingStopOrder(t *testing.T) {
+	var requestedURL string
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			requestedURL = url
+			return []byte(`{"orderId":42,"symbol":"BTCUSDT","status":"NEW","type":"TRAILING_STOP_MARKET"}`), nil
+		},
+	}
+
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	client, err := NewFuturesClient("https://fapi.binance.com", mockClient, authMgr)
+	if err != nil {
+		t.Fatalf("Failed to create futures client: %v", err)
+	}
+
+	order := &FuturesOrderRequest{
+		Symbol:          "BTCUSDT",
+		Side:            OrderSideSell,
+		PositionSide:    PositionSideLong,
+		Type:            OrderTypeTrailingStopMarket,
+		Quantity:        0.1,
+		ReduceOnly:      true,
+		CallbackRate:    1.5,
+		ActivationPrice: 52000,
+	}
+	response, err := client.CreateOrder(order)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.OrderID != 42 {
+		t.Errorf("Expected order ID 42, got %d", response.OrderID)
+	}
+	for _, want := range []string{"type=TRAILING_STOP_MARKET", "callbackRate=1.5", "activationPrice=52000", "reduceOnly=true"} {
+		if !strings.Contains(requestedURL, want) {
+			t.Errorf("Expected %s in request, got %s", want, requestedURL)
+		}
+	}
+
+	// A trailing stop needs a callback rate
+	order.CallbackRate = 0
+	if _, err := client.CreateOrder(order); err == nil {
+		t.Error("Expected an error for a trailing stop without a callback rate")
+	}
+}
+
+// TestSetMarginType verifies the margin type request and that an unchanged margin type succeeds
+func TestSetMarginType(t *testing.T) {
+	var requestedMethod, requestedURL string
+	var response error
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			requestedMethod, requestedURL = method, url
+			if response != nil {
+				return nil, response
+			}
+			return []byte(`{"code":200,"msg":"success"}`), nil
+		},
+	}
+
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	client, err := NewFuturesClient("https://fapi.binance.com", mockClient, authMgr)
+	if err != nil {
+		t.Fatalf("Failed to create futures client: %v", err)
+	}
+
+	if err := client.SetMarginType("BTCUSDT", MarginTypeIsolated); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requestedMethod != "POST" || !strings.Contains(requestedURL, "/fapi/v1/marginType?") {
+		t.Errorf("Expected POST to the marginType endpoint, got %s %s", requestedMethod, requestedURL)
+	}
+	for _, want := range []string{"symbol=BTCUSDT", "marginType=ISOLATED", "signature="} {
+		if !strings.Contains(requestedURL, want) {
+			t.Errorf("Expected %s in request, got %s", want, requestedURL)
+		}
+	}
+
+	// The symbol already uses the margin type
+	response = errors.NewTradingError(errors.ErrInvalidParameter, "HTTP client error: 400", http.StatusBadRequest,
+		newResponseBodyError([]byte(`{"code":-4046,"msg":"No need to change margin type."}`)))
+	if err := client.SetMarginType("BTCUSDT", MarginTypeIsolated); err != nil {
+		t.Errorf("Expected an unchanged margin type to succeed, got %v", err)
+	}
+
+	// Other rejections are still errors
+	response = errors.NewTradingError(errors.ErrInvalidParameter, "HTTP client error: 400", http.StatusBadRequest,
+		newResponseBodyError([]byte(`{"code":-4048,"msg":"Margin type cannot be changed if there exists position."}`)))
+	if err := client.SetMarginType("BTCUSDT", MarginTypeCrossed); err == nil || !strings.Contains(err.Error(), "-4048") {
+		t.Errorf("Expected the -4048 rejection, got %v", err)
+	}
+}
+
+func TestSetPositionMode(t *testing.T) {
+	var requestedMethod, requestedURL string
+	var response error
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			requestedMethod, requestedURL = method, url
+			if response != nil {
+				return nil, response
+			}
+			return []byte(`{"code":200,"msg":"success"}`), nil
+		},
+	}
+
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	client, err := NewFuturesClient("https://fapi.binance.com", mockClient, authMgr)
+	if err != nil {
+		t.Fatalf("Failed to create futures client: %v", err)
+	}
+
+	if err := client.SetPositionMode(true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requestedMethod != "POST" || !strings.Contains(requestedURL, "/fapi/v1/positionSide/dual?") {
+		t.Errorf("Expected POST to the positionSide/dual endpoint, got %s %s", requestedMethod, requestedURL)
+	}
+	for _, want := range []string{"dualSidePosition=true", "signature="} {
+		if !strings.Contains(requestedURL, want) {
+			t.Errorf("Expected %s in request, got %s", want, requestedURL)
+		}
+	}
+
+	// The account already uses the position mode
+	response = errors.NewTradingError(errors.ErrInvalidParameter, "HTTP client error: 400", http.StatusBadRequest,
+		newResponseBodyError([]byte(`{"code":-4059,"msg":"No need to change position side."}`)))
+	if err := client.SetPositionMode(false); err != nil {
+		t.Errorf("Expected an unchanged position mode to succeed, got %v", err)
+	}
+
+	// Other rejections are still errors
+	response = errors.NewTradingError(errors.ErrInvalidParameter, "HTTP client error: 400", http.StatusBadRequest,
+		newResponseBodyError([]byte(`{"code":-4068,"msg":"Position side cannot be changed if there exists position."}`)))
+	if err := client.SetPositionMode(false); err == nil || !strings.Contains(err.Error(), "-4068") {
+		t.Errorf("Expected the -4068 rejection, got %v", err)
+	}
+}
+
+// TestGetFuturesAccountInfo verifies that the string-encoded amounts of the account endpoint are parsed
+func TestGetFuturesAccountInfo(t *testing.T) {
+	var requestedMethod, requestedURL string
+	response := `{"feeTier":0,"canTrade":true,"canDeposit":true,"canWithdraw":true,"updateTime":0,` +
+		`"totalInitialMargin":"0.33683000","totalMaintMargin":"0.02695000","totalWalletBalance":"126.72469206",` +
+		`"totalUnrealizedProfit":"0.05100000","totalMarginBalance":"126.77569206","totalPositionInitialMargin":"0.33683000",` +
+		`"totalOpenOrderInitialMargin":"0.00000000","totalCrossWalletBalance":"126.72469206","totalCrossUnPnl":"0.05100000",` +
+		`"availableBalance":"126.43886206","maxWithdrawAmount":"126.43886206",` +
+		`"assets":[{"asset":"USDT","walletBalance":"126.72469206","unrealizedProfit":"0.05100000","marginBalance":"126.77569206",` +
+		`"maintMargin":"0.02695000","initialMargin":"0.33683000","positionInitialMargin":"0.33683000","openOrderInitialMargin":"0.00000000",` +
+		`"crossWalletBalance":"126.72469206","crossUnPnl":"0.05100000","availableBalance":"126.43886206","maxWithdrawAmount":"126.43886206",` +
+		`"marginAvailable":true,"updateTime":1625474304765}],` +
+		`"positions":[{"symbol":"BTCUSDT","initialMargin":"0.33683","maintMargin":"0.02695","unrealizedProfit":"0.05100000",` +
+		`"positionInitialMargin":"0.33683","openOrderInitialMargin":"0","leverage":"100","isolated":true,"entryPrice":"33632.4",` +
+		`"maxNotional":"250000","positionSide":"BOTH","positionAmt":"0.001","updateTime":1625474304765}]}`
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			requestedMethod, requestedURL = method, url
+			return []byte(response), nil
+		},
+	}
+
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	client, err := NewFuturesClient("https://fapi.binance.com", mockClient, authMgr)
+	if err != nil {
+		t.Fatalf("Failed to create futures client: %v", err)
+	}
+
+	info, err := client.GetFuturesAccountInfo()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requestedMethod != "GET" || !strings.Contains(requestedURL, "/fapi/v2/account?") || !strings.Contains(requestedURL, "signature=") {
+		t.Errorf("Expected a signed GET of the account endpoint, got %s %s", requestedMethod, requestedURL)
+	}
+	if info.TotalWalletBalance != 126.72469206 || info.TotalUnrealizedProfit != 0.051 ||
+		info.TotalMarginBalance != 126.77569206 || info.AvailableBalance != 126.43886206 {
+		t.Errorf("Unexpected account totals: %+v", info)
+	}
+	if len(info.Assets) != 1 || info.Assets[0].Asset != "USDT" || info.Assets[0].WalletBalance != 126.72469206 ||
+		info.Assets[0].AvailableBalance != 126.43886206 || !info.Assets[0].MarginAvailable {
+		t.Errorf("Unexpected assets: %+v", info.Assets)
+	}
+	if len(info.Positions) != 1 || info.Positions[0].Leverage != 100 || info.Positions[0].PositionAmt != 0.001 ||
+		info.Positions[0].MarginType != MarginTypeIsolated {
+		t.Errorf("Unexpected positions: %+v", info.Positions)
+	}
+
+	// GetBalance reads the USDT asset of the same response
+	balance, err := client.GetBalance()
+	if err != nil || balance.Balance != 126.72469206 || balance.AvailableBalance != 126.43886206 {
+		t.Errorf("GetBalance() = %+v, %v", balance, err)
+	}
+
+	response = `{"totalWalletBalance":"n/a","assets":[]}`
+	if _, err := client.GetFuturesAccountInfo(); err == nil || !strings.Contains(err.Error(), "total wallet balance") {
+		t.Errorf("Expected an error naming the unparsable amount, got %v", err)
+	}
+}
+
+// TestBulkCancelFuturesOrders verifies that open orders are cancelled in batches of ten and that
+// failed orders are reported next to the cancelled ones
+func TestBulkCancelFuturesOrders(t *testing.T) {
+	var openOrders []string
+	for id := 1; id <= 12; id++ {
+		openOrders = append(openOrders, fmt.Sprintf(`{"orderId":%d,"symbol":"BTCUSDT","status":"NEW"}`, id))
+	}
+	var batches []string
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			if method == "GET" && strings.Contains(url, "/fapi/v1/openOrders?") {
+				return []byte("[" + strings.Join(openOrders, ",") + "]"), nil
+			}
+			if method != "DELETE" || !strings.Contains(url, "/fapi/v1/batchOrders?") {
+				t.Fatalf("unexpected request %s %s", method, url)
+			}
+			batches = append(batches, url)
+			if len(batches) == 1 {
+				// Order 2 filled before it could be cancelled
+				entries := []string{`{"orderId":1,"symbol":"BTCUSDT","status":"CANCELED"}`, `{"code":-2011,"msg":"Unknown order sent."}`}
+				for id := 3; id <= 10; id++ {
+					entries = append(entries, fmt.Sprintf(`{"orderId":%d,"symbol":"BTCUSDT","status":"CANCELED"}`, id))
+				}
+				return []byte("[" + strings.Join(entries, ",") + "]"), nil
+			}
+			return []byte(`[{"orderId":11,"symbol":"BTCUSDT","status":"CANCELED"},{"orderId":12,"symbol":"BTCUSDT","status":"CANCELED"}]`), nil
+		},
+	}
+
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	client, err := NewFuturesClient("https://fapi.binance.com", mockClient, authMgr)
+	if err != nil {
+		t.Fatalf("Failed to create futures client: %v", err)
+	}
+
+	cancelled, err := client.BulkCancelFuturesOrders("BTCUSDT")
+	if len(batches) != 2 {
+		t.Fatalf("Expected 2 batch cancel requests, got %d", len(batches))
+	}
+	if !strings.Contains(batches[0], "orderIdList=%5B1%2C2%2C3%2C4%2C5%2C6%2C7%2C8%2C9%2C10%5D") || !strings.Contains(batches[1], "orderIdList=%5B11%2C12%5D") {
+		t.Errorf("Unexpected batches: %v", batches)
+	}
+	if len(cancelled) != 11 {
+		t.Errorf("Expected 11 cancelled orders, got %d", len(cancelled))
+	}
+	bulkErr, ok := err.(*BulkCancelError)
+	if !ok || len(bulkErr.Failures) != 1 || bulkErr.Failures[0].OrderID != 2 || bulkErr.Failures[0].Code != -2011 {
+		t.Fatalf("Expected order 2 to be reported as failed, got %v", err)
+	}
+
+	// A batch rejected as a whole fails its orders, the others are still cancelled
+	batches = nil
+	mockClient.doWithRetryFunc = func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+		if method == "GET" {
+			return []byte("[" + strings.Join(openOrders, ",") + "]"), nil
+		}
+		batches = append(batches, url)
+		if len(batches) == 1 {
+			return nil, fmt.Errorf("connection reset")
+		}
+		return []byte(`[{"orderId":11,"status":"CANCELED"},{"orderId":12,"status":"CANCELED"}]`), nil
+	}
+	cancelled, err = client.BulkCancelFuturesOrders("BTCUSDT")
+	if bulkErr, ok := err.(*BulkCancelError); !ok || len(bulkErr.Failures) != 10 || len(cancelled) != 2 {
+		t.Errorf("Expected 10 failed and 2 cancelled orders, got %d cancelled and %v", len(cancelled), err)
+	}
+
+	// Nothing to cancel
+	mockClient.doWithRetryFunc = func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+		if method != "GET" {
+			t.Fatalf("unexpected request %s %s", method, url)
+		}
+		return []byte(`[]`), nil
+	}
+	if cancelled, err := client.BulkCancelFuturesOrders("BTCUSDT"); err != nil || len(cancelled) != 0 {
+		t.Errorf("Expected nothing cancelled, got %v, %v", cancelled, err)
+	}
+}
@@ -2,13 +2,17 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
+	"binance-trader/pkg/metrics"
 	"binance-trader/pkg/errors"
 )
 
@@ -19,29 +23,115 @@ type RetryConfig struct {
 	BackoffMultiplier float64
 }
 
+// retryJitter is the fraction by which each backoff delay is randomly shortened or lengthened,
+// so clients failing together do not retry in lockstep
+const retryJitter = 0.2
+
+// DefaultRequestTimeout is used when no timeout is configured for an endpoint category
+const DefaultRequestTimeout = 30 * time.Second
+
+// EndpointCategory groups endpoints that share a request timeout
+type EndpointCategory string
+
+const (
+	EndpointCategoryOrder      EndpointCategory = "order"
+	EndpointCategoryMarketData EndpointCategory = "market_data"
+	EndpointCategoryAccount    EndpointCategory = "account"
+	EndpointCategoryDefault    EndpointCategory = "default"
+)
+
+// TimeoutConfig holds per-category request timeouts; zero values fall back to Default
+type TimeoutConfig struct {
+	Order      time.Duration
+	MarketData time.Duration
+	Account    time.Duration
+	Default    time.Duration
+}
+
+// forCategory returns the timeout for an endpoint category
+func (t TimeoutConfig) forCategory(category EndpointCategory) time.Duration {
+	var timeout time.Duration
+	switch category {
+	case EndpointCategoryOrder:
+		timeout = t.Order
+	case EndpointCategoryMarketData:
+		timeout = t.MarketData
+	case EndpointCategoryAccount:
+		timeout = t.Account
+	}
+
+	if timeout <= 0 {
+		timeout = t.Default
+	}
+	return timeout
+}
+
 // httpClient implements HTTPClient interface
 type httpClient struct {
 	client      *http.Client
 	rateLimiter *RateLimiter
 	retryConfig RetryConfig
+	rateLimits  *RateLimitTracker
+	timeouts    TimeoutConfig
+	sleep       func(time.Duration)
+	random      func() float64 // Returns values in [0, 1) for the backoff jitter
+	metrics     *metrics.Sink
 }
 
 // NewHTTPClient creates a new HTTP client with rate limiting and retry
 func NewHTTPClient(rateLimiter *RateLimiter, retryConfig RetryConfig) HTTPClient {
+	return NewHTTPClientWithTimeouts(rateLimiter, retryConfig, TimeoutConfig{Default: DefaultRequestTimeout})
+}
+
+// NewHTTPClientWithTimeouts creates a new HTTP client with per-category request timeouts
+func NewHTTPClientWithTimeouts(rateLimiter *RateLimiter, retryConfig RetryConfig, timeouts TimeoutConfig) HTTPClient {
+	if timeouts.Default <= 0 {
+		timeouts.Default = DefaultRequestTimeout
+	}
+
 	return &httpClient{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		// Timeouts are enforced per request via contexts
+		client:      &http.Client{},
 		rateLimiter: rateLimiter,
 		retryConfig: retryConfig,
+		rateLimits:  NewRateLimitTracker(),
+		timeouts:    timeouts,
+		sleep:       time.Sleep,
+		random:      rand.Float64,
+		metrics:     metrics.Noop(),
 	}
 }
 
+// GetRateLimitStatus returns the latest rate-limit usage reported by the exchange
+func (c *httpClient) GetRateLimitStatus() *RateLimitStatus {
+	return c.rateLimits.Status()
+}
+
+// SetRateLimits sets the exchange rate limits used for status reporting
+func (c *httpClient) SetRateLimits(rules []RateLimitRule) {
+	c.rateLimits.SetLimits(rules)
+}
+
+// SetMetrics sets the sink request metrics are recorded in
+func (c *httpClient) SetMetrics(sink *metrics.Sink) {
+	c.metrics = sink
+}
+
 // Do performs a single HTTP request without retry
 func (c *httpClient) Do(method, urlStr string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
-	// Wait for rate limiter
+	return c.DoWithCategory(EndpointCategoryDefault, method, urlStr, params, headers)
+}
+
+// DoWithCategory performs a single HTTP request bounded by the category timeout
+func (c *httpClient) DoWithCategory(category EndpointCategory, method, urlStr string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+	return c.doWithTimeout(c.timeouts.forCategory(category), method, urlStr, params, headers)
+}
+
+// doWithTimeout performs a single HTTP request; a non-positive timeout means no per-request deadline
+func (c *httpClient) doWithTimeout(timeout time.Duration, method, urlStr string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+	// Wait until the rate limiter has the request's weight
 	if c.rateLimiter != nil {
-		c.rateLimiter.Wait()
+		c.rateLimiter.WaitN(c.rateLimiter.RequestWeight(method, urlStr, params))
 	}
 
 	// Build request
@@ -50,12 +140,33 @@ func (c *httpClient) Do(method, urlStr string, params map[string]interface{}, he
 		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "failed to build request", 0, err)
 	}
 
+	if timeout > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
 	// Execute request
+	start := time.Now()
 	resp, err := c.client.Do(req)
+	c.metrics.APIRequestDuration.Observe(time.Since(start).Seconds(), req.URL.Path)
 	if err != nil {
+		c.metrics.APIRequests.Inc(req.URL.Path, "error")
 		return nil, errors.NewTradingError(errors.ErrNetwork, "HTTP request failed", 0, err)
 	}
 	defer resp.Body.Close()
+	c.metrics.APIRequests.Inc(req.URL.Path, strconv.Itoa(resp.StatusCode))
+
+	// Record rate-limit usage headers (present on error responses too)
+	if c.rateLimits != nil {
+		c.rateLimits.Record(resp.Header)
+	}
+	if c.rateLimiter != nil {
+		if used, err := strconv.Atoi(resp.Header.Get(usedWeightHeaderPrefix + "1M")); err == nil {
+			c.rateLimiter.SyncUsedWeight(used)
+			c.metrics.RateLimitUsedWeight.Set(float64(used))
+		}
+	}
 
 	// Read response body
 	body, err := io.ReadAll(resp.Body)
@@ -65,22 +176,33 @@ func (c *httpClient) Do(method, urlStr string, params map[string]interface{}, he
 
 	// Check for HTTP errors
 	if resp.StatusCode >= 400 {
-		// Check for rate limit error
-		if resp.StatusCode == 429 {
-			// Notify rate limiter about rate limit hit
+		// 429 asks us to slow down and 418 means the IP is banned for continuing after a 429;
+		// both say in Retry-After how long to stay away
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot {
+			cause := &retryAfterError{
+				status:     resp.StatusCode,
+				body:       string(body),
+				retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+			}
+			c.metrics.RateLimitRejections.Inc(strconv.Itoa(resp.StatusCode))
 			if c.rateLimiter != nil {
 				c.rateLimiter.OnRateLimitHit()
+				c.rateLimiter.BlockFor(cause.retryAfter)
+			}
+			message := "rate limit exceeded"
+			if resp.StatusCode == http.StatusTeapot {
+				message = "IP banned by the exchange"
 			}
-			return nil, errors.NewTradingError(errors.ErrRateLimit, "rate limit exceeded", resp.StatusCode, fmt.Errorf("status: %d, body: %s", resp.StatusCode, string(body)))
+			return nil, errors.NewTradingError(errors.ErrRateLimit, message, resp.StatusCode, cause)
 		}
 
 		// 4xx errors (except 429) are client errors and should not be retried
 		if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-			return nil, errors.NewTradingError(errors.ErrInvalidParameter, fmt.Sprintf("HTTP client error: %d", resp.StatusCode), resp.StatusCode, fmt.Errorf("body: %s", string(body)))
+			return nil, errors.NewTradingError(errors.ErrInvalidParameter, fmt.Sprintf("HTTP client error: %d", resp.StatusCode), resp.StatusCode, newResponseBodyError(body))
 		}
 
 		// 5xx errors are server errors and should be retried
-		return nil, errors.NewTradingError(errors.ErrNetwork, fmt.Sprintf("HTTP server error: %d", resp.StatusCode), resp.StatusCode, fmt.Errorf("body: %s", string(body)))
+		return nil, errors.NewTradingError(errors.ErrNetwork, fmt.Sprintf("HTTP server error: %d", resp.StatusCode), resp.StatusCode, newResponseBodyError(body))
 	}
 
 	return body, nil
@@ -88,12 +210,55 @@ func (c *httpClient) Do(method, urlStr string, params map[string]interface{}, he
 
 // DoWithRetry performs an HTTP request with exponential backoff retry
 func (c *httpClient) DoWithRetry(method, urlStr string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+	return c.DoWithRetryCategory(EndpointCategoryDefault, method, urlStr, params, headers)
+}
+
+// DoWithRetryCategory performs an HTTP request with exponential backoff retry.
+// The whole workflow, including backoff delays, is bounded by the category timeout
+// multiplied by the maximum number of attempts.
+func (c *httpClient) DoWithRetryCategory(category EndpointCategory, method, urlStr string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+	buildURL := func() (string, error) { return urlStr, nil }
+	return c.doWithRetry(category, method, buildURL, params, headers)
+}
+
+// DoSignedWithRetryCategory performs a signed HTTP request with exponential backoff retry.
+// The URL is rebuilt before every attempt so each retry carries a fresh timestamp and signature.
+func (c *httpClient) DoSignedWithRetryCategory(category EndpointCategory, method string, buildURL func() (string, error), headers map[string]string) ([]byte, error) {
+	return c.doWithRetry(category, method, buildURL, nil, headers)
+}
+
+// doWithRetry runs the retry workflow, building the request URL anew for each attempt
+func (c *httpClient) doWithRetry(category EndpointCategory, method string, buildURL func() (string, error), params map[string]interface{}, headers map[string]string) ([]byte, error) {
 	var lastErr error
 	delay := time.Duration(c.retryConfig.InitialDelayMs) * time.Millisecond
 
+	timeout := c.timeouts.forCategory(category)
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout * time.Duration(c.retryConfig.MaxAttempts))
+	}
+
 	for attempt := 1; attempt <= c.retryConfig.MaxAttempts; attempt++ {
+		// Never let a single attempt run past the workflow budget
+		attemptTimeout := timeout
+		if !deadline.IsZero() {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				// A non-positive timeout would send the request without any deadline
+				break
+			}
+			if remaining < attemptTimeout {
+				attemptTimeout = remaining
+			}
+		}
+
+		urlStr, err := buildURL()
+		if err != nil {
+			return nil, err
+		}
+
 		// Try the request
-		body, err := c.Do(method, urlStr, params, headers)
+		body, err := c.doWithTimeout(attemptTimeout, method, urlStr, params, headers)
 		if err == nil {
 			return body, nil
 		}
@@ -107,15 +272,110 @@ func (c *httpClient) DoWithRetry(method, urlStr string, params map[string]interf
 
 		// Don't sleep after the last attempt
 		if attempt < c.retryConfig.MaxAttempts {
-			time.Sleep(delay)
+			// Wait the jittered backoff, or longer when the exchange asked for it
+			wait := c.jitter(delay)
+			if retryAfter := retryAfterOf(err); retryAfter > wait {
+				wait = retryAfter
+			}
+			// Stop retrying if the wait would exhaust the remaining budget
+			if !deadline.IsZero() && time.Until(deadline) <= wait {
+				break
+			}
+			c.metrics.APIRetries.Inc(endpointPath(urlStr))
+			c.sleep(wait)
 			// Exponential backoff
 			delay = time.Duration(float64(delay) * c.retryConfig.BackoffMultiplier)
 		}
 	}
 
+	if lastErr == nil {
+		lastErr = errors.NewTradingError(errors.ErrNetwork, "request budget exhausted", 0, context.DeadlineExceeded)
+	}
 	return nil, lastErr
 }
 
+// endpointPath returns the path of a request URL, the endpoint label of the request metrics
+func endpointPath(urlStr string) string {
+	if parsed, err := url.Parse(urlStr); err == nil {
+		return parsed.Path
+	}
+	return urlStr
+}
+
+// jitter spreads a backoff delay uniformly over +/- retryJitter of its value
+func (c *httpClient) jitter(delay time.Duration) time.Duration {
+	return time.Duration(float64(delay) * (1 - retryJitter + 2*retryJitter*c.random()))
+}
+
+// retryAfterError is the cause of a 429 or 418 response, with the delay its Retry-After asked for
+type retryAfterError struct {
+	status     int
+	body       string
+	retryAfter time.Duration
+}
+
+func (e *retryAfterError) Error() string {
+	return fmt.Sprintf("status: %d, retry after: %s, body: %s", e.status, e.retryAfter, e.body)
+}
+
+// retryAfterOf returns the Retry-After delay carried by a request error, or 0
+func retryAfterOf(err error) time.Duration {
+	if tradingErr, ok := err.(*errors.TradingError); ok {
+		if cause, ok := tradingErr.Cause.(*retryAfterError); ok {
+			return cause.retryAfter
+		}
+	}
+	return 0
+}
+
+// responseBodyError is the cause of any other failed response, with the Binance error code of
+// its {"code":-1121,"msg":"..."} body
+type responseBodyError struct {
+	body string
+	code int // 0 when the body carries no code
+}
+
+func newResponseBodyError(body []byte) *responseBodyError {
+	var apiErr struct {
+		Code int `json:"code"`
+	}
+	// Bodies that are not Binance errors, e.g. a proxy's HTML page, carry no code
+	_ = json.Unmarshal(body, &apiErr)
+	return &responseBodyError{body: string(body), code: apiErr.Code}
+}
+
+func (e *responseBodyError) Error() string {
+	return fmt.Sprintf("body: %s", e.body)
+}
+
+// binanceErrorCodeOf returns the Binance error code carried by a request error, or 0
+func binanceErrorCodeOf(err error) int {
+	if tradingErr, ok := err.(*errors.TradingError); ok {
+		if cause, ok := tradingErr.Cause.(*responseBodyError); ok {
+			return cause.code
+		}
+	}
+	return 0
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date; a missing
+// or malformed header yields 0
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
 // buildRequest constructs an HTTP request
 func (c *httpClient) buildRequest(method, urlStr string, params map[string]interface{}, headers map[string]string) (*http.Request, error) {
 	var req *http.Request
@@ -166,7 +426,11 @@ func isRetryableError(err error) bool {
 
 	// Check if it's a TradingError
 	if tradingErr, ok := err.(*errors.TradingError); ok {
-		// Retry network errors and rate limit errors
+		// Retry network errors and rate limit errors; a ban (418) lasts until its Retry-After,
+		// which the rate limiter already holds later requests for
+		if tradingErr.Code == http.StatusTeapot {
+			return false
+		}
 		return tradingErr.Type == errors.ErrNetwork || tradingErr.Type == errors.ErrRateLimit
 	}
 
@@ -2,13 +2,18 @@ package api
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"binance-trader/internal/metrics"
 	"binance-trader/pkg/errors"
+	pkgmetrics "binance-trader/pkg/metrics"
 
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
@@ -83,9 +88,9 @@ func TestProperty_RetryMechanism(t *testing.T) {
 
 				expectedDelay := time.Duration(initialDelayMs) * time.Millisecond
 				for i, delay := range delays {
-					// Allow 20% tolerance for timing variations
+					// Delays are jittered by up to 20%, plus slack for timing variations
 					minDelay := expectedDelay * 8 / 10
-					maxDelay := expectedDelay * 12 / 10
+					maxDelay := expectedDelay*12/10 + 25*time.Millisecond
 
 					if delay < minDelay || delay > maxDelay {
 						t.Logf("Attempt %d: expected delay ~%v, got %v", i+1, expectedDelay, delay)
@@ -192,7 +197,7 @@ func TestProperty_RateLimitAdaptive(t *testing.T) {
 	properties.Property("rate limiter increases delay after rate limit hit", prop.ForAll(
 		func(maxCallsPerMinute int) bool {
 			// Create rate limiter
-			rateLimiter := NewRateLimiter(maxCallsPerMinute)
+			rateLimiter := NewRateLimiter(maxCallsPerMinute, nil)
 
 			// Initial adaptive delay should be 0
 			if rateLimiter.GetAdaptiveDelay() != 0 {
@@ -229,7 +234,7 @@ func TestProperty_RateLimitAdaptive(t *testing.T) {
 
 	properties.Property("rate limiter caps adaptive delay at maximum", prop.ForAll(
 		func(maxCallsPerMinute int) bool {
-			rateLimiter := NewRateLimiter(maxCallsPerMinute)
+			rateLimiter := NewRateLimiter(maxCallsPerMinute, nil)
 
 			// Hit rate limit many times
 			for i := 0; i < 10; i++ {
@@ -259,7 +264,7 @@ func TestProperty_RateLimitConcurrency(t *testing.T) {
 
 	properties.Property("rate limiter handles concurrent requests safely", prop.ForAll(
 		func(maxCallsPerMinute int, numGoroutines int) bool {
-			rateLimiter := NewRateLimiter(maxCallsPerMinute)
+			rateLimiter := NewRateLimiter(maxCallsPerMinute, nil)
 
 			// Track successful waits
 			successCount := int32(0)
@@ -468,6 +473,7 @@ func TestHTTPClient_ErrorHandling(t *testing.T) {
 			},
 			rateLimiter: nil,
 			retryConfig: RetryConfig{MaxAttempts: 1, InitialDelayMs: 10, BackoffMultiplier: 2.0},
+			metrics:     pkgmetrics.Noop(),
 		}
 
 		_, err := client.Do(http.MethodGet, server.URL, nil, nil)
@@ -485,7 +491,7 @@ func TestHTTPClient_ErrorHandling(t *testing.T) {
 	})
 
 	t.Run("rate limiter integration", func(t *testing.T) {
-		rateLimiter := NewRateLimiter(60) // 60 calls per minute
+		rateLimiter := NewRateLimiter(60, nil) // 60 calls per minute
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusTooManyRequests)
 			w.Write([]byte(`{"error": "rate limit"}`))
@@ -508,3 +514,354 @@ func TestHTTPClient_ErrorHandling(t *testing.T) {
 		}
 	})
 }
+
+func TestHTTPClient_RateLimitStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-MBX-USED-WEIGHT-1M", "1250")
+		w.Header().Set("X-MBX-ORDER-COUNT-10S", "7")
+		w.Header().Set("X-MBX-ORDER-COUNT-1D", "321")
+		w.Header().Set("X-MBX-USED-WEIGHT", "1250")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(nil, RetryConfig{MaxAttempts: 1, InitialDelayMs: 10, BackoffMultiplier: 2.0})
+
+	if status := client.GetRateLimitStatus(); status.UpdatedAt != 0 {
+		t.Errorf("expected no rate-limit data before any request, got %+v", status)
+	}
+
+	if _, err := client.Do(http.MethodGet, server.URL, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client.SetRateLimits([]RateLimitRule{
+		{RateLimitType: RateLimitTypeRequestWeight, Interval: "MINUTE", IntervalNum: 1, Limit: 6000},
+		{RateLimitType: RateLimitTypeOrders, Interval: "SECOND", IntervalNum: 10, Limit: 100},
+		{RateLimitType: RateLimitTypeOrders, Interval: "DAY", IntervalNum: 1, Limit: 200000},
+		{RateLimitType: "RAW_REQUESTS", Interval: "MINUTE", IntervalNum: 5, Limit: 61000},
+	})
+
+	status := client.GetRateLimitStatus()
+	if status.UpdatedAt == 0 {
+		t.Error("expected UpdatedAt to be set")
+	}
+	if status.UsedWeight["1M"] != 1250 {
+		t.Errorf("expected used weight 1250 for 1M, got %d", status.UsedWeight["1M"])
+	}
+	if len(status.UsedWeight) != 1 {
+		t.Errorf("expected only interval-suffixed weight headers, got %v", status.UsedWeight)
+	}
+	if status.OrderCounts["10S"] != 7 || status.OrderCounts["1D"] != 321 {
+		t.Errorf("unexpected order counts: %v", status.OrderCounts)
+	}
+	if status.WeightLimits["1M"] != 6000 {
+		t.Errorf("expected weight limit 6000 for 1M, got %d", status.WeightLimits["1M"])
+	}
+	if status.OrderLimits["10S"] != 100 || status.OrderLimits["1D"] != 200000 {
+		t.Errorf("unexpected order limits: %v", status.OrderLimits)
+	}
+
+	// Snapshots must not share state with the tracker
+	status.UsedWeight["1M"] = 0
+	if client.GetRateLimitStatus().UsedWeight["1M"] != 1250 {
+		t.Error("expected status snapshot to be a copy")
+	}
+}
+
+func TestHTTPClient_CategoryTimeouts(t *testing.T) {
+	// Slow endpoint that takes 300ms to respond
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		time.Sleep(300 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	timeouts := TimeoutConfig{
+		Order:      100 * time.Millisecond,
+		MarketData: time.Second,
+		Default:    time.Second,
+	}
+	retryConfig := RetryConfig{MaxAttempts: 3, InitialDelayMs: 10, BackoffMultiplier: 2.0}
+	client := NewHTTPClientWithTimeouts(nil, retryConfig, timeouts)
+
+	t.Run("fails at order timeout", func(t *testing.T) {
+		atomic.StoreInt32(&requests, 0)
+		start := time.Now()
+		_, err := client.DoWithRetryCategory(EndpointCategoryOrder, http.MethodGet, server.URL, nil, nil)
+		elapsed := time.Since(start)
+
+		if err == nil {
+			t.Fatal("expected order request to time out")
+		}
+		if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrNetwork {
+			t.Errorf("expected network error, got %v", err)
+		}
+		// Total workflow is bounded by timeout x attempts including backoff
+		if elapsed > 3*timeouts.Order+100*time.Millisecond {
+			t.Errorf("expected retry workflow to stay within budget, took %v", elapsed)
+		}
+		if atomic.LoadInt32(&requests) < 2 {
+			t.Errorf("expected timed out order request to be retried, got %d requests", requests)
+		}
+	})
+
+	t.Run("succeeds under market data timeout", func(t *testing.T) {
+		body, err := client.DoWithRetryCategory(EndpointCategoryMarketData, http.MethodGet, server.URL, nil, nil)
+		if err != nil {
+			t.Fatalf("expected market data request to succeed, got %v", err)
+		}
+		if string(body) != `{}` {
+			t.Errorf("unexpected body: %s", body)
+		}
+	})
+
+	t.Run("unset category falls back to default", func(t *testing.T) {
+		if _, err := client.DoWithCategory(EndpointCategoryAccount, http.MethodGet, server.URL, nil, nil); err != nil {
+			t.Errorf("expected account request to use default timeout, got %v", err)
+		}
+	})
+}
+
+// roundTripFunc is a mock transport answering requests with a function
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newMockTransportClient returns a client whose requests are answered by statuses in turn, each
+// with the given headers, and that records its backoff sleeps instead of sleeping
+func newMockTransportClient(limiter *RateLimiter, retryConfig RetryConfig, headers http.Header, statuses ...int) (*httpClient, *int, *[]time.Duration) {
+	client := NewHTTPClient(limiter, retryConfig).(*httpClient)
+	attempts := 0
+	client.client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		status := statuses[len(statuses)-1]
+		if attempts < len(statuses) {
+			status = statuses[attempts]
+		}
+		attempts++
+		return &http.Response{
+			StatusCode: status,
+			Header:     headers.Clone(),
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+			Request:    req,
+		}, nil
+	})
+	var sleeps []time.Duration
+	client.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+	return client, &attempts, &sleeps
+}
+
+func TestHTTPClient_HonorsRetryAfter(t *testing.T) {
+	retryConfig := RetryConfig{MaxAttempts: 3, InitialDelayMs: 100, BackoffMultiplier: 2.0}
+	client, attempts, sleeps := newMockTransportClient(nil, retryConfig, http.Header{"Retry-After": []string{"2"}},
+		http.StatusTooManyRequests, http.StatusOK)
+
+	body, err := client.DoWithRetry(http.MethodGet, "https://api.binance.com/api/v3/ticker/price", nil, nil)
+	if err != nil {
+		t.Fatalf("DoWithRetry() error = %v", err)
+	}
+	if string(body) != `{}` || *attempts != 2 {
+		t.Errorf("body = %s after %d attempts, want {} after 2", body, *attempts)
+	}
+	// Retry-After outweighs the 100ms backoff
+	if len(*sleeps) != 1 || (*sleeps)[0] != 2*time.Second {
+		t.Errorf("sleeps = %v, want [2s]", *sleeps)
+	}
+}
+
+func TestHTTPClient_StopsWhenBudgetIsSpent(t *testing.T) {
+	retryConfig := RetryConfig{MaxAttempts: 3, InitialDelayMs: 1, BackoffMultiplier: 2.0}
+	client, attempts, _ := newMockTransportClient(nil, retryConfig, nil, http.StatusServiceUnavailable)
+	client.timeouts = TimeoutConfig{Default: 20 * time.Millisecond}
+	// The backoff overruns the 60ms workflow budget
+	client.sleep = func(time.Duration) { time.Sleep(100 * time.Millisecond) }
+
+	_, err := client.DoWithRetry(http.MethodGet, "https://api.binance.com/api/v3/ticker/price", nil, nil)
+	tradingErr, ok := err.(*errors.TradingError)
+	if !ok || tradingErr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("DoWithRetry() error = %v, want the last 503 error", err)
+	}
+	if *attempts != 1 {
+		t.Errorf("attempts = %d, want 1; no request may be sent once the budget is spent", *attempts)
+	}
+}
+
+func TestHTTPClient_BanIsNotRetried(t *testing.T) {
+	limiter := NewRateLimiter(1200, nil)
+	retryConfig := RetryConfig{MaxAttempts: 3, InitialDelayMs: 100, BackoffMultiplier: 2.0}
+	client, attempts, sleeps := newMockTransportClient(limiter, retryConfig, http.Header{"Retry-After": []string{"120"}},
+		http.StatusTeapot)
+
+	start := time.Now()
+	_, err := client.DoWithRetry(http.MethodGet, "https://api.binance.com/api/v3/ticker/price", nil, nil)
+	tradingErr, ok := err.(*errors.TradingError)
+	if !ok || tradingErr.Type != errors.ErrRateLimit || tradingErr.Code != http.StatusTeapot {
+		t.Fatalf("DoWithRetry() error = %v, want a rate limit error with code 418", err)
+	}
+	if *attempts != 1 || len(*sleeps) != 0 {
+		t.Errorf("attempts = %d, sleeps = %v; a ban must not be retried", *attempts, *sleeps)
+	}
+	if retryAfterOf(err) != 2*time.Minute {
+		t.Errorf("retryAfterOf() = %v, want 2m", retryAfterOf(err))
+	}
+
+	// Later requests are held until the ban ends
+	if blocked := limiter.blockedUntil.Sub(start); blocked < 119*time.Second || blocked > 121*time.Second {
+		t.Errorf("limiter blocked for %v, want about 2m", blocked)
+	}
+}
+
+func TestHTTPClient_SignedRetriesAreResigned(t *testing.T) {
+	retryConfig := RetryConfig{MaxAttempts: 3, InitialDelayMs: 100, BackoffMultiplier: 2.0}
+	client, attempts, _ := newMockTransportClient(nil, retryConfig, nil, http.StatusServiceUnavailable, http.StatusOK)
+	// Let the clock move on between attempts, as the backoff would
+	client.sleep = func(time.Duration) { time.Sleep(5 * time.Millisecond) }
+	answer := client.client.Transport
+	var queries []string
+	client.client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		queries = append(queries, req.URL.RawQuery)
+		return answer.RoundTrip(req)
+	})
+
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	spot, err := NewSpotClient("https://api.binance.com", client, authMgr)
+	if err != nil {
+		t.Fatalf("NewSpotClient() error = %v", err)
+	}
+	order := &OrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeMarket, Quantity: 0.01}
+	if _, err := spot.CreateOrder(order); err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+
+	if *attempts != 2 || len(queries) != 2 {
+		t.Fatalf("attempts = %d, want 2", *attempts)
+	}
+	first, _ := url.ParseQuery(queries[0])
+	second, _ := url.ParseQuery(queries[1])
+	if first.Get("timestamp") == second.Get("timestamp") {
+		t.Errorf("both attempts carry timestamp %s, want a fresh one on the retry", first.Get("timestamp"))
+	}
+	// Each attempt's signature covers its own query
+	for i, query := range queries {
+		payload, signature, _ := strings.Cut(query, "&signature=")
+		if !authMgr.VerifySignature(payload, signature) {
+			t.Errorf("attempt %d: signature %s does not match query %s", i+1, signature, payload)
+		}
+	}
+}
+
+func TestHTTPClient_BinanceErrorCode(t *testing.T) {
+	client := NewHTTPClient(nil, RetryConfig{MaxAttempts: 1}).(*httpClient)
+	body := `{"code":-1121,"msg":"Invalid symbol."}`
+	client.client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})
+
+	_, err := client.DoWithRetry(http.MethodGet, "https://api.binance.com/api/v3/ticker/price", nil, nil)
+	if binanceErrorCodeOf(err) != -1121 || !strings.Contains(err.Error(), "Invalid symbol.") {
+		t.Errorf("DoWithRetry() error = %v, want code -1121 with the body", err)
+	}
+
+	// A body that is not a Binance error carries no code
+	body = `<html>Bad Gateway</html>`
+	if _, err := client.DoWithRetry(http.MethodGet, "https://api.binance.com/api/v3/ticker/price", nil, nil); err == nil || binanceErrorCodeOf(err) != 0 {
+		t.Errorf("DoWithRetry() error = %v, want no Binance code", err)
+	}
+	if binanceErrorCodeOf(fmt.Errorf("network down")) != 0 {
+		t.Error("expected no Binance code for other errors")
+	}
+}
+
+func TestHTTPClient_BackoffJitter(t *testing.T) {
+	retryConfig := RetryConfig{MaxAttempts: 3, InitialDelayMs: 100, BackoffMultiplier: 2.0}
+	tests := []struct {
+		name   string
+		random float64
+		want   []time.Duration
+	}{
+		{"shortest", 0, []time.Duration{80 * time.Millisecond, 160 * time.Millisecond}},
+		{"unchanged", 0.5, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}},
+		{"longest", 1, []time.Duration{120 * time.Millisecond, 240 * time.Millisecond}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, attempts, sleeps := newMockTransportClient(nil, retryConfig, nil, http.StatusServiceUnavailable)
+			client.random = func() float64 { return tt.random }
+
+			if _, err := client.DoWithRetry(http.MethodGet, "https://api.binance.com/api/v3/ticker/price", nil, nil); err == nil {
+				t.Fatal("DoWithRetry() succeeded against a failing server")
+			}
+			if *attempts != 3 || fmt.Sprint(*sleeps) != fmt.Sprint(tt.want) {
+				t.Errorf("attempts = %d, sleeps = %v; want 3 attempts sleeping %v", *attempts, *sleeps, tt.want)
+			}
+		})
+	}
+
+	// Client errors other than 429 fail at once
+	client, attempts, sleeps := newMockTransportClient(nil, retryConfig, nil, http.StatusBadRequest)
+	if _, err := client.DoWithRetry(http.MethodGet, "https://api.binance.com/api/v3/order", nil, nil); err == nil || *attempts != 1 || len(*sleeps) != 0 {
+		t.Errorf("400 response: err = %v, attempts = %d, sleeps = %v; want one failed attempt", err, *attempts, *sleeps)
+	}
+}
+
+func TestHTTPClient_Metrics(t *testing.T) {
+	const endpoint = "/api/v3/avgPrice"
+	headers := http.Header{}
+	headers.Set(usedWeightHeaderPrefix+"1M", "321")
+	retryConfig := RetryConfig{MaxAttempts: 3, InitialDelayMs: 100, BackoffMultiplier: 2.0}
+	client, _, _ := newMockTransportClient(NewRateLimiter(1200, nil), retryConfig, headers,
+		http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusOK)
+
+	sink := metrics.NewSink(metrics.NewRegistry())
+	client.SetMetrics(sink)
+
+	if _, err := client.DoWithRetry(http.MethodGet, "https://api.binance.com"+endpoint+"?symbol=BTCUSDT", nil, nil); err != nil {
+		t.Fatalf("DoWithRetry() error = %v", err)
+	}
+	if got := sink.APIRetries.(*metrics.Counter).Value(endpoint); got != 2 {
+		t.Errorf("retries counted = %v, want 2", got)
+	}
+	if got := sink.RateLimitRejections.(*metrics.Counter).Value("429"); got != 1 {
+		t.Errorf("rate limit rejections counted = %v, want 1", got)
+	}
+	if got := sink.APIRequests.(*metrics.Counter).Value(endpoint, "200"); got != 1 {
+		t.Errorf("successful requests counted = %v, want 1", got)
+	}
+	if got := sink.RateLimitUsedWeight.(*metrics.Gauge).Value(); got != 321 {
+		t.Errorf("used weight = %v, want 321", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"2", 2 * time.Second},
+		{"0", 0},
+		{"", 0},
+		{"-5", 0},
+		{"soon", 0},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,443 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMarketStreamURL is the Binance spot market stream endpoint
+const DefaultMarketStreamURL = "wss://stream.binance.com/ws"
+
+// Market stream reconnect backoff used when MarketStreamOptions sets none
+const (
+	DefaultStreamReconnectInitial = time.Second
+	DefaultStreamReconnectMax     = 30 * time.Second
+)
+
+const (
+	// streamDialTimeout bounds connecting and the opening handshake
+	streamDialTimeout = 10 * time.Second
+
+	// streamReadTimeout drops a silent connection; Binance pings every 20 seconds and
+	// ticker streams push every second
+	streamReadTimeout = time.Minute
+)
+
+// TickerEvent is a 24hr rolling window ticker frame (<symbol>@ticker), pushed every second
+type TickerEvent struct {
+	Symbol    string
+	LastPrice float64
+	Volume    float64 // Base asset volume of the last 24 hours
+	EventTime int64   // Milliseconds since epoch
+}
+
+// tickerEvent is a 24hrTicker stream frame. "c" and "C" are both tagged because encoding/json
+// would otherwise match the close time to the last price case-insensitively.
+type tickerEvent struct {
+	EventType string `json:"e"`
+	EventTime int64  `json:"E"`
+	Symbol    string `json:"s"`
+	LastPrice string `json:"c"`
+	CloseTime int64  `json:"C"`
+	Volume    string `json:"v"`
+}
+
+// ParseTickerEvent parses a 24hrTicker stream frame, either raw or wrapped in a combined
+// stream envelope ({"stream": ..., "data": {...}})
+func ParseTickerEvent(data []byte) (*TickerEvent, error) {
+	var envelope struct {
+		Stream string          `json:"stream"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse ticker frame: %w", err)
+	}
+	if envelope.Stream != "" && len(envelope.Data) > 0 {
+		data = envelope.Data
+	}
+
+	var event tickerEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("failed to parse ticker frame: %w", err)
+	}
+	if event.EventType != "24hrTicker" || event.Symbol == "" {
+		return nil, fmt.Errorf("not a ticker frame")
+	}
+
+	price, err := strconv.ParseFloat(event.LastPrice, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ticker last price %q: %w", event.LastPrice, err)
+	}
+	volume, err := strconv.ParseFloat(event.Volume, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ticker volume %q: %w", event.Volume, err)
+	}
+
+	return &TickerEvent{
+		Symbol:    event.Symbol,
+		LastPrice: price,
+		Volume:    volume,
+		EventTime: event.EventTime,
+	}, nil
+}
+
+// MarketStreamClient keeps one WebSocket connection to the market streams. Subscriptions
+// outlive the connection: when it drops, the client reconnects with exponential backoff and
+// subscribes again. Each subscribe method returns a function dropping that subscription.
+type MarketStreamClient interface {
+	// SubscribeTicker calls handler with every <symbol>@ticker frame of a symbol. The first
+	// subscription opens the connection.
+	SubscribeTicker(symbol string, handler func(*TickerEvent)) (func(), error)
+
+	// SubscribeBookTicker calls handler with every <symbol>@bookTicker frame of a symbol
+	SubscribeBookTicker(symbol string, handler func(*BookTicker)) (func(), error)
+
+	// Connected reports whether the stream is currently connected
+	Connected() bool
+
+	// ConnectedStreams returns the subscribed stream names, e.g. btcusdt@bookTicker, while
+	// connected and nothing otherwise
+	ConnectedStreams() []string
+
+	Close() error
+}
+
+// MarketStreamOptions configures a market stream client; zero values use the defaults
+type MarketStreamOptions struct {
+	URL              string
+	ReconnectInitial time.Duration // First reconnect delay, doubled after every failed attempt
+	ReconnectMax     time.Duration
+}
+
+// streamHandler receives the raw frames of one subscription
+type streamHandler struct {
+	handle func(data []byte)
+}
+
+// marketStreamClient implements MarketStreamClient
+type marketStreamClient struct {
+	url              string
+	reconnectInitial time.Duration
+	reconnectMax     time.Duration
+	closed           chan struct{}
+	closeOnce        sync.Once
+
+	mu       sync.Mutex
+	handlers map[string][]*streamHandler // By stream name, e.g. btcusdt@ticker
+	conn     *wsConn
+	started  bool
+	nextID   int64
+}
+
+// NewMarketStreamClient creates a market stream client; it connects on the first subscription
+func NewMarketStreamClient(opts MarketStreamOptions) (MarketStreamClient, error) {
+	if opts.URL == "" {
+		opts.URL = DefaultMarketStreamURL
+	}
+	if !strings.HasPrefix(opts.URL, "wss://") && !strings.HasPrefix(opts.URL, "ws://") {
+		return nil, fmt.Errorf("stream URL must use ws or wss, got: %s", opts.URL)
+	}
+	if opts.ReconnectInitial <= 0 {
+		opts.ReconnectInitial = DefaultStreamReconnectInitial
+	}
+	if opts.ReconnectMax < opts.ReconnectInitial {
+		opts.ReconnectMax = max(DefaultStreamReconnectMax, opts.ReconnectInitial)
+	}
+
+	return &marketStreamClient{
+		url:              opts.URL,
+		reconnectInitial: opts.ReconnectInitial,
+		reconnectMax:     opts.ReconnectMax,
+		closed:           make(chan struct{}),
+		handlers:         make(map[string][]*streamHandler),
+	}, nil
+}
+
+// SubscribeTicker adds a ticker handler for a symbol
+func (c *marketStreamClient) SubscribeTicker(symbol string, handler func(*TickerEvent)) (func(), error) {
+	if handler == nil {
+		return nil, fmt.Errorf("handler cannot be nil")
+	}
+	return c.subscribeStream(symbol, "ticker", func(data []byte) {
+		if event, err := ParseTickerEvent(data); err == nil {
+			handler(event)
+		}
+	})
+}
+
+// SubscribeBookTicker adds a book ticker handler for a symbol
+func (c *marketStreamClient) SubscribeBookTicker(symbol string, handler func(*BookTicker)) (func(), error) {
+	if handler == nil {
+		return nil, fmt.Errorf("handler cannot be nil")
+	}
+	return c.subscribeStream(symbol, "bookTicker", func(data []byte) {
+		if ticker, err := ParseBookTickerEvent(data); err == nil {
+			handler(ticker)
+		}
+	})
+}
+
+// subscribeStream adds a frame handler to the <symbol>@<kind> stream, subscribing the stream
+// on its first handler
+func (c *marketStreamClient) subscribeStream(symbol, kind string, handle func(data []byte)) (func(), error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol cannot be empty")
+	}
+	stream := strings.ToLower(symbol) + "@" + kind
+	handler := &streamHandler{handle: handle}
+
+	c.mu.Lock()
+	select {
+	case <-c.closed:
+		c.mu.Unlock()
+		return nil, fmt.Errorf("market stream is closed")
+	default:
+	}
+	_, subscribed := c.handlers[stream]
+	c.handlers[stream] = append(c.handlers[stream], handler)
+	conn := c.conn
+	if !c.started {
+		c.started = true
+		go c.run()
+	}
+	c.mu.Unlock()
+
+	// Without a connection the stream is subscribed when the connection is (re)established
+	if !subscribed && conn != nil {
+		c.send(conn, "SUBSCRIBE", []string{stream})
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { c.unsubscribeStream(stream, handler) })
+	}, nil
+}
+
+// unsubscribeStream drops a frame handler, unsubscribing the stream after its last handler
+func (c *marketStreamClient) unsubscribeStream(stream string, handler *streamHandler) {
+	c.mu.Lock()
+	handlers := c.handlers[stream]
+	kept := make([]*streamHandler, 0, len(handlers))
+	for _, h := range handlers {
+		if h != handler {
+			kept = append(kept, h)
+		}
+	}
+	if len(kept) > 0 {
+		c.handlers[stream] = kept
+		c.mu.Unlock()
+		return
+	}
+	delete(c.handlers, stream)
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn != nil {
+		c.send(conn, "UNSUBSCRIBE", []string{stream})
+	}
+}
+
+// Connected reports whether the stream is connected
+func (c *marketStreamClient) Connected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn != nil
+}
+
+// ConnectedStreams returns the subscribed streams, sorted, while connected
+func (c *marketStreamClient) ConnectedStreams() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	streams := make([]string, 0, len(c.handlers))
+	for stream := range c.handlers {
+		streams = append(streams, stream)
+	}
+	sort.Strings(streams)
+	return streams
+}
+
+// Close closes the connection and stops reconnecting
+func (c *marketStreamClient) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+	if conn != nil {
+		return conn.Close()
+	}
+	return nil
+}
+
+// run connects and reads until the client is closed, backing off between attempts. The
+// backoff is reset once a connection has delivered frames.
+func (c *marketStreamClient) run() {
+	failures := 0
+	for {
+		if conn, err := c.connect(); err == nil && c.readLoop(conn) {
+			failures = 0
+		}
+
+		select {
+		case <-c.closed:
+			return
+		case <-time.After(c.reconnectDelay(failures)):
+		}
+		failures++
+	}
+}
+
+// reconnectDelay returns the wait before the next attempt after a number of consecutive
+// failures: the initial delay doubled per failure, up to the maximum
+func (c *marketStreamClient) reconnectDelay(failures int) time.Duration {
+	delay := c.reconnectInitial
+	for i := 0; i < failures && delay < c.reconnectMax; i++ {
+		delay *= 2
+	}
+	return min(delay, c.reconnectMax)
+}
+
+// connect dials the stream and subscribes every stream with a handler
+func (c *marketStreamClient) connect() (*wsConn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), streamDialTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-c.closed:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	conn, err := dialWebSocket(ctx, c.url)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	select {
+	case <-c.closed:
+		c.mu.Unlock()
+		conn.Close()
+		return nil, fmt.Errorf("market stream is closed")
+	default:
+	}
+	c.conn = conn
+	streams := make([]string, 0, len(c.handlers))
+	for stream := range c.handlers {
+		streams = append(streams, stream)
+	}
+	c.mu.Unlock()
+
+	if len(streams) > 0 {
+		sort.Strings(streams)
+		if err := c.send(conn, "SUBSCRIBE", streams); err != nil {
+			c.disconnect(conn)
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// readLoop dispatches frames until the connection fails; it reports whether any frame arrived
+func (c *marketStreamClient) readLoop(conn *wsConn) bool {
+	defer c.disconnect(conn)
+
+	received := false
+	for {
+		conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+		data, err := conn.ReadMessage()
+		if err != nil {
+			return received
+		}
+		received = true
+		c.dispatch(data)
+	}
+}
+
+// disconnect forgets and closes a connection
+func (c *marketStreamClient) disconnect(conn *wsConn) {
+	c.mu.Lock()
+	if c.conn == conn {
+		c.conn = nil
+	}
+	c.mu.Unlock()
+	conn.Close()
+}
+
+// send sends a SUBSCRIBE or UNSUBSCRIBE request for streams
+func (c *marketStreamClient) send(conn *wsConn, method string, streams []string) error {
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	c.mu.Unlock()
+
+	request, err := json.Marshal(map[string]interface{}{
+		"method": method,
+		"params": streams,
+		"id":     id,
+	})
+	if err != nil {
+		return err
+	}
+	return conn.WriteText(request)
+}
+
+// dispatch passes a frame to the handlers of its stream; other frames, such as subscription
+// responses, are ignored
+func (c *marketStreamClient) dispatch(data []byte) {
+	stream, payload := frameStream(data)
+	if stream == "" {
+		return
+	}
+
+	c.mu.Lock()
+	handlers := c.handlers[stream]
+	c.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler.handle(payload)
+	}
+}
+
+// frameStream returns the stream a frame belongs to and its payload. Combined stream frames
+// name it; raw frames are told apart by their fields, since several streams share the
+// connection. Every key is tagged so "e" and "E" are not matched case-insensitively.
+func frameStream(data []byte) (string, []byte) {
+	var frame struct {
+		Stream    string          `json:"stream"`
+		Data      json.RawMessage `json:"data"`
+		EventType string          `json:"e"`
+		EventTime int64           `json:"E"`
+		Symbol    string          `json:"s"`
+		UpdateID  int64           `json:"u"`
+	}
+	if err := json.Unmarshal(data, &frame); err != nil {
+		return "", nil
+	}
+
+	switch {
+	case frame.Stream != "" && len(frame.Data) > 0:
+		return frame.Stream, frame.Data
+	case frame.Symbol == "":
+		return "", nil
+	case frame.EventType == "24hrTicker":
+		return strings.ToLower(frame.Symbol) + "@ticker", data
+	case (frame.EventType == "" || frame.EventType == "bookTicker") && frame.UpdateID > 0:
+		return strings.ToLower(frame.Symbol) + "@bookTicker", data
+	default:
+		return "", nil
+	}
+}
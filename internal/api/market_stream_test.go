@@ -0,0 +1,270 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tickerFrame is a 24hrTicker frame with the fields that clash case-insensitively
+func tickerFrame(symbol, price string) string {
+	return `{"e":"24hrTicker","E":1672515782136,"s":"` + symbol + `","p":"0.0015","P":"250.00","o":"0.0010","O":0,` +
+		`"c":"` + price + `","C":1672515782136,"v":"10000","q":"18","l":"0.0010","L":18150,"n":18151}`
+}
+
+func TestParseTickerEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		frame   string
+		symbol  string
+		price   float64
+		wantErr bool
+	}{
+		{name: "raw frame", frame: tickerFrame("BNBBTC", "0.0025"), symbol: "BNBBTC", price: 0.0025},
+		{name: "combined stream envelope", frame: `{"stream":"btcusdt@ticker","data":` + tickerFrame("BTCUSDT", "50000.10") + `}`, symbol: "BTCUSDT", price: 50000.10},
+		{name: "subscription response", frame: `{"result":null,"id":1}`, wantErr: true},
+		{name: "other event", frame: `{"e":"trade","s":"BTCUSDT","p":"50000"}`, wantErr: true},
+		{name: "invalid price", frame: tickerFrame("BTCUSDT", "n/a"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := ParseTickerEvent([]byte(tt.frame))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseTickerEvent() = %+v, want an error", event)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTickerEvent() error = %v", err)
+			}
+			if event.Symbol != tt.symbol || event.LastPrice != tt.price || event.Volume != 10000 || event.EventTime != 1672515782136 {
+				t.Errorf("ParseTickerEvent() = %+v", event)
+			}
+		})
+	}
+}
+
+// readRequest reads a SUBSCRIBE or UNSUBSCRIBE request and returns its streams
+func readRequest(t *testing.T, peer *fakeStreamConn, method string) []string {
+	t.Helper()
+	_, payload := peer.read()
+	var request struct {
+		Method string   `json:"method"`
+		Params []string `json:"params"`
+		ID     int64    `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(payload), &request); err != nil || request.Method != method || request.ID == 0 {
+		t.Fatalf("expected a %s request, got %q", method, payload)
+	}
+	return request.Params
+}
+
+func TestMarketStreamClient(t *testing.T) {
+	server := newFakeStreamServer(t)
+	client, err := NewMarketStreamClient(MarketStreamOptions{URL: server.URL(), ReconnectInitial: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewMarketStreamClient() error = %v", err)
+	}
+	defer client.Close()
+
+	prices := make(chan float64, 16)
+	handler := func(event *TickerEvent) {
+		if event.Symbol == "BTCUSDT" {
+			prices <- event.LastPrice
+		}
+	}
+	expectPrice := func(want float64) {
+		t.Helper()
+		select {
+		case got := <-prices:
+			if got != want {
+				t.Errorf("handler got %v, want %v", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no price %v within 5s", want)
+		}
+	}
+
+	// The first subscription connects and subscribes the ticker stream
+	if _, err := client.SubscribeTicker("BTCUSDT", handler); err != nil {
+		t.Fatalf("SubscribeTicker() error = %v", err)
+	}
+	peer := server.accept()
+	if streams := readRequest(t, peer, "SUBSCRIBE"); len(streams) != 1 || streams[0] != "btcusdt@ticker" {
+		t.Fatalf("subscribed %v, want [btcusdt@ticker]", streams)
+	}
+	peer.send(wsOpText, true, `{"result":null,"id":1}`)
+	peer.send(wsOpText, true, tickerFrame("BTCUSDT", "50000.5"))
+	expectPrice(50000.5)
+	if !client.Connected() {
+		t.Error("Connected() = false while frames arrive")
+	}
+
+	// Later subscriptions are sent on the open connection
+	if _, err := client.SubscribeBookTicker("ETHUSDT", func(*BookTicker) {}); err != nil {
+		t.Fatalf("SubscribeTicker() error = %v", err)
+	}
+	if streams := readRequest(t, peer, "SUBSCRIBE"); len(streams) != 1 || streams[0] != "ethusdt@bookTicker" {
+		t.Fatalf("subscribed %v, want [ethusdt@bookTicker]", streams)
+	}
+
+	// After a drop the client reconnects and subscribes every stream again
+	peer.conn.Close()
+	peer = server.accept()
+	if streams := strings.Join(readRequest(t, peer, "SUBSCRIBE"), ","); streams != "btcusdt@ticker,ethusdt@bookTicker" {
+		t.Fatalf("resubscribed %s, want both streams", streams)
+	}
+	peer.send(wsOpText, true, tickerFrame("BTCUSDT", "50100"))
+	expectPrice(50100)
+
+	// Once closed the client neither reconnects nor accepts subscriptions
+	client.Close()
+	if _, err := client.SubscribeTicker("SOLUSDT", handler); err == nil {
+		t.Error("SubscribeTicker() after Close expected an error")
+	}
+	select {
+	case <-server.conns:
+		t.Error("client reconnected after Close")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if client.Connected() {
+		t.Error("Connected() = true after Close")
+	}
+}
+
+func TestNewMarketStreamClientDefaults(t *testing.T) {
+	client, err := NewMarketStreamClient(MarketStreamOptions{URL: "https://stream.binance.com/ws"})
+	if client != nil || err == nil {
+		t.Errorf("NewMarketStreamClient() with an https URL = %v, %v, want an error", client, err)
+	}
+
+	client, err = NewMarketStreamClient(MarketStreamOptions{ReconnectInitial: 2 * time.Minute})
+	if err != nil {
+		t.Fatalf("NewMarketStreamClient() error = %v", err)
+	}
+	stream := client.(*marketStreamClient)
+	if stream.url != DefaultMarketStreamURL || stream.reconnectMax != 2*time.Minute {
+		t.Errorf("defaults: url %s, reconnect max %v", stream.url, stream.reconnectMax)
+	}
+}
+
+func TestMarketStreamDispatch(t *testing.T) {
+	server := newFakeStreamServer(t)
+	client, err := NewMarketStreamClient(MarketStreamOptions{URL: server.URL()})
+	if err != nil {
+		t.Fatalf("NewMarketStreamClient() error = %v", err)
+	}
+	defer client.Close()
+
+	got := make(chan string, 16)
+	client.SubscribeTicker("BTCUSDT", func(event *TickerEvent) {
+		got <- fmt.Sprintf("ticker %v", event.LastPrice)
+	})
+	client.SubscribeBookTicker("BTCUSDT", func(ticker *BookTicker) {
+		got <- fmt.Sprintf("book %v/%v", ticker.BidPrice, ticker.AskPrice)
+	})
+	client.SubscribeBookTicker("MARKUSDT", func(*BookTicker) {
+		got <- "mark"
+	})
+	peer := server.accept()
+	readRequest(t, peer, "SUBSCRIBE")
+
+	bookFrame := `{"u":400900217,"s":"BTCUSDT","b":"49999.5","B":"1","a":"50000.5","A":"2"}`
+	tests := []struct {
+		name  string
+		frame string
+		want  []string
+	}{
+		{"ticker frame", tickerFrame("BTCUSDT", "50000"), []string{"ticker 50000"}},
+		{"spot book ticker frame", bookFrame, []string{"book 49999.5/50000.5"}},
+		{"combined stream envelope", `{"stream":"btcusdt@bookTicker","data":` + bookFrame + `}`, []string{"book 49999.5/50000.5"}},
+		{"book ticker frame with event type", `{"e":"bookTicker","E":1568014460893,"u":7,"s":"BTCUSDT","b":"1","B":"1","a":"2","A":"1"}`, []string{"book 1/2"}},
+		{"unsubscribed symbol", tickerFrame("ETHUSDT", "3000"), nil},
+		{"subscription response", `{"result":null,"id":1}`, nil},
+		{"error response", `{"error":{"code":2,"msg":"Invalid request"},"id":2}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peer.send(wsOpText, true, tt.frame)
+			peer.send(wsOpText, true, `{"u":1,"s":"MARKUSDT","b":"1","B":"1","a":"1","A":"1"}`)
+
+			var delivered []string
+			for message := range got {
+				if message == "mark" {
+					break
+				}
+				delivered = append(delivered, message)
+			}
+			if strings.Join(delivered, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("delivered %v, want %v", delivered, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarketStreamUnsubscribe(t *testing.T) {
+	server := newFakeStreamServer(t)
+	client, err := NewMarketStreamClient(MarketStreamOptions{URL: server.URL()})
+	if err != nil {
+		t.Fatalf("NewMarketStreamClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if streams := client.ConnectedStreams(); len(streams) != 0 {
+		t.Errorf("ConnectedStreams() before connecting = %v", streams)
+	}
+
+	first, _ := client.SubscribeBookTicker("BTCUSDT", func(*BookTicker) {})
+	second, _ := client.SubscribeBookTicker("BTCUSDT", func(*BookTicker) {})
+	peer := server.accept()
+	readRequest(t, peer, "SUBSCRIBE")
+	if streams := client.ConnectedStreams(); strings.Join(streams, ",") != "btcusdt@bookTicker" {
+		t.Errorf("ConnectedStreams() = %v", streams)
+	}
+
+	// The stream stays subscribed while a handler is left; the next request is a new subscription
+	first()
+	first()
+	client.SubscribeTicker("ETHUSDT", func(*TickerEvent) {})
+	if streams := readRequest(t, peer, "SUBSCRIBE"); strings.Join(streams, ",") != "ethusdt@ticker" {
+		t.Fatalf("subscribed %v, want [ethusdt@ticker]", streams)
+	}
+
+	second()
+	if streams := readRequest(t, peer, "UNSUBSCRIBE"); strings.Join(streams, ",") != "btcusdt@bookTicker" {
+		t.Fatalf("unsubscribed %v, want [btcusdt@bookTicker]", streams)
+	}
+	if streams := client.ConnectedStreams(); strings.Join(streams, ",") != "ethusdt@ticker" {
+		t.Errorf("ConnectedStreams() after unsubscribing = %v", streams)
+	}
+}
+
+func TestMarketStreamReconnectDelay(t *testing.T) {
+	client, err := NewMarketStreamClient(MarketStreamOptions{URL: DefaultMarketStreamURL})
+	if err != nil {
+		t.Fatalf("NewMarketStreamClient() error = %v", err)
+	}
+	stream := client.(*marketStreamClient)
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{3, 8 * time.Second},
+		{4, 16 * time.Second},
+		{5, 30 * time.Second},
+		{1000, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := stream.reconnectDelay(tt.failures); got != tt.want {
+			t.Errorf("reconnectDelay(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
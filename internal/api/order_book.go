@@ -0,0 +1,128 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OrderBookLimits are the depth limits the exchange accepts, in levels per side
+var OrderBookLimits = []int{5, 10, 20, 50, 100, 500, 1000, 5000}
+
+// ValidateOrderBookLimit rejects a depth limit the exchange does not accept
+func ValidateOrderBookLimit(limit int) error {
+	allowed := make([]string, len(OrderBookLimits))
+	for i, valid := range OrderBookLimits {
+		if limit == valid {
+			return nil
+		}
+		allowed[i] = strconv.Itoa(valid)
+	}
+	return fmt.Errorf("invalid order book limit %d: must be one of %s", limit, strings.Join(allowed, ", "))
+}
+
+// OrderBookLevel is one price level of an order book
+type OrderBookLevel struct {
+	Price float64
+	Qty   float64
+}
+
+// OrderBook is a depth snapshot; bids are sorted best (highest) first and asks best (lowest) first
+type OrderBook struct {
+	Symbol       string
+	LastUpdateID int64
+	Bids         []OrderBookLevel
+	Asks         []OrderBookLevel
+}
+
+// BestBid returns the highest bid; ok is false when the bid side is empty
+func (b *OrderBook) BestBid() (level OrderBookLevel, ok bool) {
+	if len(b.Bids) == 0 {
+		return OrderBookLevel{}, false
+	}
+	return b.Bids[0], true
+}
+
+// BestAsk returns the lowest ask; ok is false when the ask side is empty
+func (b *OrderBook) BestAsk() (level OrderBookLevel, ok bool) {
+	if len(b.Asks) == 0 {
+		return OrderBookLevel{}, false
+	}
+	return b.Asks[0], true
+}
+
+// Imbalance returns (bid quantity - ask quantity) / (bid quantity + ask quantity) over the
+// best levels of each side, all of them when levels is 0. It ranges from -1, only asks, to 1,
+// only bids, and is 0 for an empty book.
+func (b *OrderBook) Imbalance(levels int) float64 {
+	sum := func(side []OrderBookLevel) float64 {
+		if levels > 0 && len(side) > levels {
+			side = side[:levels]
+		}
+		total := 0.0
+		for _, level := range side {
+			total += level.Qty
+		}
+		return total
+	}
+
+	bidQty, askQty := sum(b.Bids), sum(b.Asks)
+	if bidQty+askQty <= 0 {
+		return 0
+	}
+	return (bidQty - askQty) / (bidQty + askQty)
+}
+
+// orderBookResponse is the REST depth response; levels are [price, quantity] string pairs
+type orderBookResponse struct {
+	LastUpdateID int64       `json:"lastUpdateId"`
+	Bids         [][2]string `json:"bids"`
+	Asks         [][2]string `json:"asks"`
+}
+
+// parseOrderBookResponse parses a REST depth response
+func parseOrderBookResponse(symbol string, body []byte) (*OrderBook, error) {
+	var response orderBookResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse order book: %w", err)
+	}
+
+	bids, err := parseOrderBookLevels("bid", response.Bids)
+	if err != nil {
+		return nil, err
+	}
+	asks, err := parseOrderBookLevels("ask", response.Asks)
+	if err != nil {
+		return nil, err
+	}
+
+	// The exchange already sends levels best first; sorting keeps BestBid/BestAsk correct regardless
+	sort.SliceStable(bids, func(i, j int) bool { return bids[i].Price > bids[j].Price })
+	sort.SliceStable(asks, func(i, j int) bool { return asks[i].Price < asks[j].Price })
+
+	return &OrderBook{
+		Symbol:       symbol,
+		LastUpdateID: response.LastUpdateID,
+		Bids:         bids,
+		Asks:         asks,
+	}, nil
+}
+
+// parseOrderBookLevels converts [price, quantity] string pairs into levels
+func parseOrderBookLevels(side string, raw [][2]string) ([]OrderBookLevel, error) {
+	levels := make([]OrderBookLevel, 0, len(raw))
+	for _, pair := range raw {
+		price, err := strconv.ParseFloat(pair[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse order book %s price %q: %w", side, pair[0], err)
+		}
+		qty, err := strconv.ParseFloat(pair[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse order book %s quantity %q: %w", side, pair[1], err)
+		}
+		levels = append(levels, OrderBookLevel{Price: price, Qty: qty})
+	}
+	return levels, nil
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestGetOrderBook(t *testing.T) {
+	var requestedURL string
+	var requestedParams map[string]interface{}
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			requestedURL = url
+			requestedParams = params
+			return []byte(`{"lastUpdateId":1027024,"bids":[["50000.10","0.5"],["49999.00","1.25"]],"asks":[["50000.20","0.8"],["50001.00","2"]]}`), nil
+		},
+	}
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+
+	spot, err := NewSpotClient("https://api.binance.com", mockClient, authMgr)
+	if err != nil {
+		t.Fatalf("Failed to create spot client: %v", err)
+	}
+	book, err := spot.GetOrderBook("BTCUSDT", 20)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(requestedURL, "/api/v3/depth") || requestedParams["limit"] != 20 {
+		t.Errorf("Expected depth endpoint with limit 20, got %s %v", requestedURL, requestedParams)
+	}
+	if book.Symbol != "BTCUSDT" || book.LastUpdateID != 1027024 || len(book.Bids) != 2 || len(book.Asks) != 2 {
+		t.Fatalf("Unexpected order book: %+v", book)
+	}
+	if book.Bids[1] != (OrderBookLevel{Price: 49999, Qty: 1.25}) || book.Asks[0] != (OrderBookLevel{Price: 50000.2, Qty: 0.8}) {
+		t.Errorf("Unexpected levels: bids %+v asks %+v", book.Bids, book.Asks)
+	}
+
+	if _, err := parseOrderBookResponse("BTCUSDT", []byte(`{"bids":[["abc","1"]],"asks":[]}`)); err == nil {
+		t.Error("Expected an error for a malformed price")
+	}
+}
+
+func TestGetOrderBook_InvalidLimit(t *testing.T) {
+	requests := 0
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			requests++
+			return []byte(`{"lastUpdateId":1,"bids":[],"asks":[]}`), nil
+		},
+	}
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	spot, _ := NewSpotClient("https://api.binance.com", mockClient, authMgr)
+
+	for _, limit := range []int{0, -5, 7, 200, 10000} {
+		_, err := spot.GetOrderBook("BTCUSDT", limit)
+		if err == nil || !strings.Contains(err.Error(), "must be one of 5, 10, 20, 50, 100, 500, 1000, 5000") {
+			t.Errorf("GetOrderBook(limit %d) error = %v, want the allowed limits", limit, err)
+		}
+	}
+	if _, err := spot.GetOrderBook("", 5); err == nil {
+		t.Error("Expected an error for an empty symbol")
+	}
+	if requests != 0 {
+		t.Errorf("Invalid requests reached the exchange %d times", requests)
+	}
+
+	for _, limit := range OrderBookLimits {
+		if _, err := spot.GetOrderBook("BTCUSDT", limit); err != nil {
+			t.Errorf("GetOrderBook(limit %d) error = %v", limit, err)
+		}
+	}
+}
+
+// TestOrderBookLevelOrderingProperty verifies that parsed bids are sorted descending and asks ascending,
+// whatever order the levels arrive in, and that BestBid/BestAsk return the top of each side
+func TestOrderBookLevelOrderingProperty(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+	properties := gopter.NewProperties(parameters)
+
+	toPairs := func(prices []float64) [][2]string {
+		pairs := make([][2]string, len(prices))
+		for i, price := range prices {
+			pairs[i] = [2]string{strconv.FormatFloat(price, 'f', -1, 64), "1"}
+		}
+		return pairs
+	}
+
+	properties.Property("bids descend, asks ascend and the best levels are the extremes", prop.ForAll(
+		func(bidPrices, askPrices []float64) bool {
+			body, err := json.Marshal(orderBookResponse{LastUpdateID: 1, Bids: toPairs(bidPrices), Asks: toPairs(askPrices)})
+			if err != nil {
+				return false
+			}
+			book, err := parseOrderBookResponse("BTCUSDT", body)
+			if err != nil || len(book.Bids) != len(bidPrices) || len(book.Asks) != len(askPrices) {
+				return false
+			}
+
+			for i := 1; i < len(book.Bids); i++ {
+				if book.Bids[i].Price > book.Bids[i-1].Price {
+					return false
+				}
+			}
+			for i := 1; i < len(book.Asks); i++ {
+				if book.Asks[i].Price < book.Asks[i-1].Price {
+					return false
+				}
+			}
+
+			bestBid, ok := book.BestBid()
+			if ok != (len(bidPrices) > 0) {
+				return false
+			}
+			for _, price := range bidPrices {
+				if price > bestBid.Price {
+					return false
+				}
+			}
+			bestAsk, ok := book.BestAsk()
+			if ok != (len(askPrices) > 0) {
+				return false
+			}
+			for _, price := range askPrices {
+				if price < bestAsk.Price {
+					return false
+				}
+			}
+			return true
+		},
+		gen.SliceOf(gen.Float64Range(0.01, 100000)),
+		gen.SliceOf(gen.Float64Range(0.01, 100000)),
+	))
+
+	properties.TestingRun(t)
+}
+
+func TestOrderBookImbalance(t *testing.T) {
+	book := &OrderBook{
+		Bids: []OrderBookLevel{{Price: 100, Qty: 3}, {Price: 99, Qty: 1}, {Price: 98, Qty: 10}},
+		Asks: []OrderBookLevel{{Price: 101, Qty: 1}, {Price: 102, Qty: 1}},
+	}
+
+	tests := []struct {
+		levels int
+		want   float64
+	}{
+		{1, 0.5},       // (3 - 1) / (3 + 1)
+		{2, 1.0 / 3.0}, // (4 - 2) / (4 + 2)
+		{0, 0.75},      // (14 - 2) / (14 + 2)
+	}
+	for _, tt := range tests {
+		if got := book.Imbalance(tt.levels); got != tt.want {
+			t.Errorf("Imbalance(%d) = %v, want %v", tt.levels, got, tt.want)
+		}
+	}
+
+	if got := (&OrderBook{}).Imbalance(20); got != 0 {
+		t.Errorf("Imbalance of an empty book = %v, want 0", got)
+	}
+	if got := (&OrderBook{Asks: book.Asks}).Imbalance(20); got != -1 {
+		t.Errorf("Imbalance with only asks = %v, want -1", got)
+	}
+}
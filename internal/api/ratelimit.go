@@ -1,11 +1,50 @@
 package api
 
 import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
-// RateLimiter implements token bucket algorithm for rate limiting
+const (
+	// usedWeightHeaderPrefix is the prefix of the exchange request weight headers (e.g. X-MBX-USED-WEIGHT-1M)
+	usedWeightHeaderPrefix = "X-MBX-USED-WEIGHT-"
+	// orderCountHeaderPrefix is the prefix of the exchange order count headers (e.g. X-MBX-ORDER-COUNT-10S)
+	orderCountHeaderPrefix = "X-MBX-ORDER-COUNT-"
+
+	// RateLimitTypeRequestWeight is the exchange-info rate limit type for request weight
+	RateLimitTypeRequestWeight = "REQUEST_WEIGHT"
+	// RateLimitTypeOrders is the exchange-info rate limit type for order counts
+	RateLimitTypeOrders = "ORDERS"
+)
+
+// DefaultEndpointWeights are the request weights of the endpoints this client calls. Keys are
+// "METHOD /path" or, for every method, "/path"; endpoints not listed weigh 1.
+var DefaultEndpointWeights = map[string]int{
+	"/api/v3/account":            20,
+	"/api/v3/allOrders":          20,
+	"/api/v3/depth":              5, // limits up to 100; RequestWeight charges larger books by their limit
+	"/api/v3/exchangeInfo":       20,
+	"/api/v3/klines":             2,
+	"/api/v3/myTrades":           20,
+	"GET /api/v3/openOrders":     6,
+	"GET /api/v3/order":          4,
+	"GET /api/v3/orderList":      4,
+	"/api/v3/ticker/bookTicker":  2,
+	"/api/v3/ticker/price":       2, // one symbol; RequestWeight charges every symbol at once as 4
+	"/fapi/v1/klines":            5,
+	"/fapi/v1/ticker/bookTicker": 2,
+	"/fapi/v2/account":           5,
+	"/fapi/v2/positionRisk":      5,
+	"POST /sapi/v1/asset/dust":   10,
+}
+
+// RateLimiter implements token bucket algorithm for rate limiting; a request takes as many
+// tokens as its endpoint weighs
 type RateLimiter struct {
 	mu                sync.Mutex
 	tokens            float64
@@ -14,41 +53,145 @@ type RateLimiter struct {
 	lastRefill        time.Time
 	adaptiveDelay     time.Duration
 	rateLimitHitCount int
+	weights           map[string]int
+	blockedUntil      time.Time // Set from Retry-After; no request is sent before it
 }
 
 // NewRateLimiter creates a new rate limiter
-// maxCallsPerMinute: maximum number of API calls allowed per minute
-func NewRateLimiter(maxCallsPerMinute int) *RateLimiter {
-	maxTokens := float64(maxCallsPerMinute)
+// maxWeightPerMinute: request weight allowed per minute
+// weights: endpoint weights keyed like DefaultEndpointWeights; nil uses DefaultEndpointWeights
+func NewRateLimiter(maxWeightPerMinute int, weights map[string]int) *RateLimiter {
+	maxTokens := float64(maxWeightPerMinute)
 	refillRate := maxTokens / 60.0 // tokens per second
 
+	if weights == nil {
+		weights = DefaultEndpointWeights
+	}
+
 	return &RateLimiter{
 		tokens:        maxTokens,
 		maxTokens:     maxTokens,
 		refillRate:    refillRate,
 		lastRefill:    time.Now(),
 		adaptiveDelay: 0,
+		weights:       copyIntMap(weights),
 	}
 }
 
+// Weight returns the request weight of an endpoint
+func (rl *RateLimiter) Weight(method, urlStr string) int {
+	path := urlStr
+	if parsed, err := url.Parse(urlStr); err == nil {
+		path = parsed.Path
+	}
+
+	if weight, ok := rl.weights[strings.ToUpper(method)+" "+path]; ok {
+		return weight
+	}
+	if weight, ok := rl.weights[path]; ok {
+		return weight
+	}
+	return 1
+}
+
+// RequestWeight returns the request weight of a call, including weights that depend on its
+// parameters such as the depth limit
+func (rl *RateLimiter) RequestWeight(method, urlStr string, params map[string]interface{}) int {
+	weight := rl.Weight(method, urlStr)
+
+	path := urlStr
+	if parsed, err := url.Parse(urlStr); err == nil {
+		path = parsed.Path
+	}
+	switch path {
+	case "/api/v3/depth":
+		if limit, ok := intParam(params, "limit"); ok && OrderBookWeight(limit) > weight {
+			weight = OrderBookWeight(limit)
+		}
+	case "/api/v3/ticker/price":
+		if _, ok := params["symbol"]; !ok && allPricesWeight > weight {
+			weight = allPricesWeight
+		}
+	}
+	return weight
+}
+
+// allPricesWeight is the request weight of the price ticker of every symbol
+const allPricesWeight = 4
+
+// OrderBookWeight returns the request weight of a spot depth snapshot with the given limit
+func OrderBookWeight(limit int) int {
+	switch {
+	case limit <= 100:
+		return 5
+	case limit <= 500:
+		return 25
+	case limit <= 1000:
+		return 50
+	default:
+		return 250
+	}
+}
+
+// intParam reads an integer request parameter
+func intParam(params map[string]interface{}, key string) (int, bool) {
+	switch value := params[key].(type) {
+	case int:
+		return value, true
+	case int64:
+		return int(value), true
+	case string:
+		parsed, err := strconv.Atoi(value)
+		return parsed, err == nil
+	}
+	return 0, false
+}
+
 // Wait blocks until a token is available
 func (rl *RateLimiter) Wait() {
+	rl.WaitN(1)
+}
+
+// WaitFor blocks until the weight of an endpoint is available
+func (rl *RateLimiter) WaitFor(method, urlStr string) {
+	rl.WaitN(rl.Weight(method, urlStr))
+}
+
+// WaitN blocks until weight tokens are available. A weight above the bucket size waits for a
+// full bucket instead of blocking forever.
+func (rl *RateLimiter) WaitN(weight int) {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
+	need := float64(weight)
+	if need > rl.maxTokens {
+		need = rl.maxTokens
+	}
+
+	// Hold every request while the exchange has asked us to back off
+	for wait := time.Until(rl.blockedUntil); wait > 0; wait = time.Until(rl.blockedUntil) {
+		rl.mu.Unlock()
+		time.Sleep(wait)
+		rl.mu.Lock()
+	}
+
 	// Refill tokens based on time elapsed
 	rl.refill()
 
-	// Wait until we have at least one token
-	for rl.tokens < 1.0 {
+	// Wait until the bucket holds the weight, sleeping at most 100ms so waiters share it
+	for rl.tokens < need {
+		wait := time.Duration((need - rl.tokens) / rl.refillRate * float64(time.Second))
+		if wait > 100*time.Millisecond {
+			wait = 100 * time.Millisecond
+		}
 		rl.mu.Unlock()
-		time.Sleep(100 * time.Millisecond)
+		time.Sleep(wait)
 		rl.mu.Lock()
 		rl.refill()
 	}
 
-	// Consume one token
-	rl.tokens -= 1.0
+	// Consume the weight
+	rl.tokens -= need
 
 	// Apply adaptive delay if rate limit was hit recently
 	if rl.adaptiveDelay > 0 {
@@ -58,6 +201,23 @@ func (rl *RateLimiter) Wait() {
 	}
 }
 
+// SyncUsedWeight aligns the bucket with the weight the exchange reports as used in the current
+// minute (X-MBX-USED-WEIGHT-1M). Weight spent by other clients on the same IP is taken from the
+// bucket; a lower report never adds tokens the limiter has not refilled itself.
+func (rl *RateLimiter) SyncUsedWeight(usedWeight int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.refill()
+	remaining := rl.maxTokens - float64(usedWeight)
+	if remaining < 0 {
+		remaining = 0
+	}
+	if remaining < rl.tokens {
+		rl.tokens = remaining
+	}
+}
+
 // refill adds tokens based on elapsed time (must be called with lock held)
 func (rl *RateLimiter) refill() {
 	now := time.Now()
@@ -99,6 +259,20 @@ func (rl *RateLimiter) OnRateLimitHit() {
 	rl.tokens = 0
 }
 
+// BlockFor holds every request for d, as the exchange asks with Retry-After on 429 and 418
+// responses. A shorter block never cuts an earlier, longer one short.
+func (rl *RateLimiter) BlockFor(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if until := time.Now().Add(d); until.After(rl.blockedUntil) {
+		rl.blockedUntil = until
+	}
+}
+
 // GetAdaptiveDelay returns the current adaptive delay
 func (rl *RateLimiter) GetAdaptiveDelay() time.Duration {
 	rl.mu.Lock()
@@ -120,3 +294,137 @@ func (rl *RateLimiter) ResetAdaptiveDelay() {
 	rl.adaptiveDelay = 0
 	rl.rateLimitHitCount = 0
 }
+
+// RateLimitRule represents an entry of the exchange-info rateLimits array
+type RateLimitRule struct {
+	RateLimitType string `json:"rateLimitType"`
+	Interval      string `json:"interval"`
+	IntervalNum   int    `json:"intervalNum"`
+	Limit         int    `json:"limit"`
+}
+
+// Key returns the interval key used by the response headers (e.g. 1M, 10S, 1D)
+func (r RateLimitRule) Key() string {
+	if r.Interval == "" {
+		return ""
+	}
+	return fmt.Sprintf("%d%s", r.IntervalNum, strings.ToUpper(r.Interval[:1]))
+}
+
+// RateLimitStatus holds the latest exchange rate-limit usage keyed by interval (e.g. 1M, 10S, 1D)
+type RateLimitStatus struct {
+	UsedWeight   map[string]int
+	OrderCounts  map[string]int
+	WeightLimits map[string]int
+	OrderLimits  map[string]int
+	UpdatedAt    int64
+}
+
+// RateLimitStatusProvider exposes the latest rate-limit usage reported by the exchange
+type RateLimitStatusProvider interface {
+	GetRateLimitStatus() *RateLimitStatus
+}
+
+// RateLimitTracker records rate-limit usage from response headers
+type RateLimitTracker struct {
+	mu           sync.RWMutex
+	usedWeight   map[string]int
+	orderCounts  map[string]int
+	weightLimits map[string]int
+	orderLimits  map[string]int
+	updatedAt    int64
+}
+
+// NewRateLimitTracker creates a new rate-limit tracker
+func NewRateLimitTracker() *RateLimitTracker {
+	return &RateLimitTracker{
+		usedWeight:   make(map[string]int),
+		orderCounts:  make(map[string]int),
+		weightLimits: make(map[string]int),
+		orderLimits:  make(map[string]int),
+	}
+}
+
+// Record updates usage from X-MBX-USED-WEIGHT-* and X-MBX-ORDER-COUNT-* response headers
+func (t *RateLimitTracker) Record(header http.Header) {
+	usedWeight, orderCounts := ParseRateLimitHeaders(header)
+	if len(usedWeight) == 0 && len(orderCounts) == 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for interval, value := range usedWeight {
+		t.usedWeight[interval] = value
+	}
+	for interval, value := range orderCounts {
+		t.orderCounts[interval] = value
+	}
+	t.updatedAt = time.Now().Unix()
+}
+
+// SetLimits sets the limits reported by the exchange-info rateLimits array
+func (t *RateLimitTracker) SetLimits(rules []RateLimitRule) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, rule := range rules {
+		switch rule.RateLimitType {
+		case RateLimitTypeRequestWeight:
+			t.weightLimits[rule.Key()] = rule.Limit
+		case RateLimitTypeOrders:
+			t.orderLimits[rule.Key()] = rule.Limit
+		}
+	}
+}
+
+// Status returns a snapshot of the latest usage and limits
+func (t *RateLimitTracker) Status() *RateLimitStatus {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return &RateLimitStatus{
+		UsedWeight:   copyIntMap(t.usedWeight),
+		OrderCounts:  copyIntMap(t.orderCounts),
+		WeightLimits: copyIntMap(t.weightLimits),
+		OrderLimits:  copyIntMap(t.orderLimits),
+		UpdatedAt:    t.updatedAt,
+	}
+}
+
+// ParseRateLimitHeaders extracts used weight and order counts keyed by interval from response headers
+func ParseRateLimitHeaders(header http.Header) (map[string]int, map[string]int) {
+	usedWeight := make(map[string]int)
+	orderCounts := make(map[string]int)
+
+	for name, values := range header {
+		if len(values) == 0 {
+			continue
+		}
+
+		value, err := strconv.Atoi(strings.TrimSpace(values[0]))
+		if err != nil {
+			continue
+		}
+
+		upperName := strings.ToUpper(name)
+		switch {
+		case strings.HasPrefix(upperName, usedWeightHeaderPrefix):
+			usedWeight[strings.TrimPrefix(upperName, usedWeightHeaderPrefix)] = value
+		case strings.HasPrefix(upperName, orderCountHeaderPrefix):
+			orderCounts[strings.TrimPrefix(upperName, orderCountHeaderPrefix)] = value
+		}
+	}
+
+	return usedWeight, orderCounts
+}
+
+// copyIntMap returns a copy of an int map
+func copyIntMap(source map[string]int) map[string]int {
+	result := make(map[string]int, len(source))
+	for key, value := range source {
+		result[key] = value
+	}
+	return result
+}
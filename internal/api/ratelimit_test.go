@@ -0,0 +1,265 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterWeight(t *testing.T) {
+	limiter := NewRateLimiter(1200, nil)
+
+	tests := []struct {
+		method string
+		url    string
+		want   int
+	}{
+		{"GET", "https://api.binance.com/api/v3/allOrders", 20},
+		{"GET", "https://api.binance.com/api/v3/order", 4},
+		{"POST", "https://api.binance.com/api/v3/order", 1},
+		{"DELETE", "https://api.binance.com/api/v3/order", 1},
+		{"get", "https://api.binance.com/api/v3/openOrders?symbol=BTCUSDT", 6},
+		{"GET", "https://fapi.binance.com/fapi/v2/account", 5},
+		{"GET", "https://api.binance.com/api/v3/unknown", 1},
+		{"GET", "/api/v3/account", 20},
+	}
+	for _, tt := range tests {
+		if got := limiter.Weight(tt.method, tt.url); got != tt.want {
+			t.Errorf("Weight(%s %s) = %d, want %d", tt.method, tt.url, got, tt.want)
+		}
+	}
+
+	custom := NewRateLimiter(1200, map[string]int{"/api/v3/order": 3})
+	if got := custom.Weight("GET", "https://api.binance.com/api/v3/order"); got != 3 {
+		t.Errorf("custom Weight() = %d, want 3", got)
+	}
+	if got := custom.Weight("GET", "https://api.binance.com/api/v3/allOrders"); got != 1 {
+		t.Errorf("custom Weight() of an unlisted endpoint = %d, want 1", got)
+	}
+}
+
+func TestRateLimiterWaitN(t *testing.T) {
+	// 600 weight per minute refills 10 tokens per second
+	limiter := NewRateLimiter(600, nil)
+
+	start := time.Now()
+	limiter.WaitN(600)
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("WaitN() on a full bucket took %v", elapsed)
+	}
+
+	// The bucket is empty, so 5 tokens take about half a second
+	start = time.Now()
+	limiter.WaitN(5)
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("WaitN(5) on an empty bucket took %v, want about 500ms", elapsed)
+	}
+
+	// A weight above the bucket size waits for a full bucket instead of forever
+	small := NewRateLimiter(10, nil)
+	done := make(chan struct{})
+	go func() {
+		small.WaitN(50)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("WaitN() with a weight above the bucket size blocked")
+	}
+}
+
+func TestRateLimiterBlockFor(t *testing.T) {
+	limiter := NewRateLimiter(1200, nil)
+	limiter.BlockFor(300 * time.Millisecond)
+	// A shorter block does not cut the first one short
+	limiter.BlockFor(10 * time.Millisecond)
+
+	start := time.Now()
+	limiter.WaitN(1)
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("WaitN() while blocked took %v, want about 300ms", elapsed)
+	}
+
+	start = time.Now()
+	limiter.WaitN(1)
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("WaitN() after the block took %v", elapsed)
+	}
+}
+
+func TestHTTPClient_DeductsEndpointWeight(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	limiter := NewRateLimiter(1200, nil)
+	client := NewHTTPClient(limiter, RetryConfig{MaxAttempts: 1, InitialDelayMs: 10, BackoffMultiplier: 2.0})
+
+	if _, err := client.DoWithRetry(http.MethodGet, server.URL+"/api/v3/allOrders", nil, nil); err != nil {
+		t.Fatalf("DoWithRetry() error = %v", err)
+	}
+	if _, err := client.Do(http.MethodPost, server.URL+"/api/v3/order", nil, nil); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	limiter.mu.Lock()
+	tokens := limiter.tokens
+	limiter.mu.Unlock()
+	// 21 weight taken; at most a token refilled in the meantime
+	if tokens < 1178 || tokens > 1180 {
+		t.Errorf("tokens after allOrders and an order = %v, want about 1179", tokens)
+	}
+}
+
+func TestRateLimiterRequestWeight(t *testing.T) {
+	limiter := NewRateLimiter(1200, nil)
+	depth := "https://api.binance.com/api/v3/depth"
+
+	tests := []struct {
+		url    string
+		params map[string]interface{}
+		want   int
+	}{
+		{depth, map[string]interface{}{"symbol": "BTCUSDT", "limit": 5}, 5},
+		{depth, map[string]interface{}{"symbol": "BTCUSDT", "limit": 100}, 5},
+		{depth, map[string]interface{}{"symbol": "BTCUSDT", "limit": 500}, 25},
+		{depth, map[string]interface{}{"symbol": "BTCUSDT", "limit": "1000"}, 50},
+		{depth, map[string]interface{}{"symbol": "BTCUSDT", "limit": 5000}, 250},
+		{depth, nil, 5},
+		{"https://api.binance.com/api/v3/klines", map[string]interface{}{"limit": 5000}, 2},
+		{"https://api.binance.com/api/v3/ticker/price", map[string]interface{}{"symbol": "BTCUSDT"}, 2},
+		{"https://api.binance.com/api/v3/ticker/price", nil, 4},
+	}
+	for _, tt := range tests {
+		if got := limiter.RequestWeight("GET", tt.url, tt.params); got != tt.want {
+			t.Errorf("RequestWeight(%s, %v) = %d, want %d", tt.url, tt.params, got, tt.want)
+		}
+	}
+}
+
+// btcParams asks for one symbol, which the price ticker charges 2 for
+var btcParams = map[string]interface{}{"symbol": "BTCUSDT"}
+
+func TestHTTPClient_BlocksAtWeightThreshold(t *testing.T) {
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	// 600 weight per minute refills 10 tokens per second
+	limiter := NewRateLimiter(600, nil)
+	client := NewHTTPClient(limiter, RetryConfig{MaxAttempts: 1, InitialDelayMs: 10, BackoffMultiplier: 2.0})
+
+	// 25 allOrders (weight 20) and 50 prices (weight 2) spend the budget in 75 calls
+	start := time.Now()
+	for i := 0; i < 75; i++ {
+		path := "/api/v3/ticker/price"
+		if i%3 == 0 {
+			path = "/api/v3/allOrders"
+		}
+		if _, err := client.Do(http.MethodGet, server.URL+path, btcParams, nil); err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("75 calls within the weight budget took %v", elapsed)
+	}
+
+	// Far below 600 calls, the next heavy request waits for its weight to refill
+	done := make(chan struct{})
+	go func() {
+		client.Do(http.MethodGet, server.URL+"/api/v3/allOrders", nil, nil)
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatalf("request after %d calls was not blocked at the weight threshold", calls.Load()-1)
+	case <-time.After(500 * time.Millisecond):
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("blocked request never went through")
+	}
+	if got := calls.Load(); got != 76 {
+		t.Errorf("calls = %d, want 76", got)
+	}
+}
+
+func TestHTTPClient_SyncsUsedWeightHeader(t *testing.T) {
+	usedWeight := "1150"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-MBX-USED-WEIGHT-1M", usedWeight)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	limiter := NewRateLimiter(1200, nil)
+	client := NewHTTPClient(limiter, RetryConfig{MaxAttempts: 1, InitialDelayMs: 10, BackoffMultiplier: 2.0})
+	tokens := func() float64 {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		return limiter.tokens
+	}
+
+	// Weight used by other clients on the same IP is taken from the bucket
+	if _, err := client.Do(http.MethodGet, server.URL+"/api/v3/ticker/price", btcParams, nil); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if got := tokens(); got < 50 || got > 51 {
+		t.Errorf("tokens after the exchange reported 1150 used = %v, want about 50", got)
+	}
+
+	// A lower report does not add tokens back
+	usedWeight = "10"
+	if _, err := client.Do(http.MethodGet, server.URL+"/api/v3/ticker/price", btcParams, nil); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if got := tokens(); got < 48 || got > 49 {
+		t.Errorf("tokens after a lower report = %v, want about 48", got)
+	}
+}
+
+// BenchmarkRateLimiter_Concurrent measures the overhead of the limiter when it never blocks
+func BenchmarkRateLimiter_Concurrent(b *testing.B) {
+	limiter := NewRateLimiter(1<<30, nil)
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			limiter.WaitFor("GET", "https://api.binance.com/api/v3/ticker/price")
+		}
+	})
+}
+
+// BenchmarkRateLimiter_Saturated runs concurrent requests of mixed weight against a bucket of
+// 60000 weight per minute; the weight/s metric stays near the refill rate of 1000 once the
+// initial burst is spent
+func BenchmarkRateLimiter_Saturated(b *testing.B) {
+	limiter := NewRateLimiter(60000, nil)
+	limiter.WaitN(60000)
+	endpoints := []string{
+		"https://api.binance.com/api/v3/order",
+		"https://api.binance.com/api/v3/ticker/price",
+		"https://api.binance.com/api/v3/account",
+	}
+
+	var weight atomic.Int64
+	var next atomic.Int64
+	b.SetParallelism(8)
+	b.ResetTimer()
+	start := time.Now()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			url := endpoints[next.Add(1)%int64(len(endpoints))]
+			limiter.WaitFor("GET", url)
+			weight.Add(int64(limiter.Weight("GET", url)))
+		}
+	})
+	b.ReportMetric(float64(weight.Load())/time.Since(start).Seconds(), "weight/s")
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"binance-trader/pkg/errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// SafeMode is the shared switch that turns the system into an observation-only deployment.
+// While active, every write to the exchange fails before a request is sent; reads are untouched.
+type SafeMode struct {
+	active atomic.Bool
+}
+
+// NewSafeMode creates the safe mode switch
+func NewSafeMode(active bool) *SafeMode {
+	s := &SafeMode{}
+	s.active.Store(active)
+	return s
+}
+
+// IsActive reports whether writes are disabled
+func (s *SafeMode) IsActive() bool {
+	return s != nil && s.active.Load()
+}
+
+// Set turns safe mode on or off while the system runs, e.g. after the config is reloaded
+func (s *SafeMode) Set(active bool) {
+	s.active.Store(active)
+}
+
+// Check returns a safe mode error for the given write operation while safe mode is active
+func (s *SafeMode) Check(operation string) error {
+	if !s.IsActive() {
+		return nil
+	}
+	return errors.NewTradingError(errors.ErrSafeMode, fmt.Sprintf("safe mode active: %s is disabled", operation), 0, nil)
+}
+
+// safeModeSpotClient blocks the write operations of a spot client while safe mode is active
+type safeModeSpotClient struct {
+	SpotClient
+	safeMode *SafeMode
+}
+
+// NewSafeModeSpotClient wraps a spot client so its write operations consult safe mode
+func NewSafeModeSpotClient(client SpotClient, safeMode *SafeMode) SpotClient {
+	return &safeModeSpotClient{SpotClient: client, safeMode: safeMode}
+}
+
+// CreateOrder places an order unless safe mode is active
+func (c *safeModeSpotClient) CreateOrder(order *OrderRequest) (*OrderResponse, error) {
+	if err := c.safeMode.Check("order placement"); err != nil {
+		return nil, err
+	}
+	return c.SpotClient.CreateOrder(order)
+}
+
+// CancelOrder cancels an order unless safe mode is active
+func (c *safeModeSpotClient) CancelOrder(symbol string, orderID int64) (*CancelResponse, error) {
+	if err := c.safeMode.Check("order cancellation"); err != nil {
+		return nil, err
+	}
+	return c.SpotClient.CancelOrder(symbol, orderID)
+}
+
+// BulkCancelOrders cancels all open orders of a symbol unless safe mode is active
+func (c *safeModeSpotClient) BulkCancelOrders(symbol string) ([]CancelResponse, error) {
+	if err := c.safeMode.Check("order cancellation"); err != nil {
+		return nil, err
+	}
+	return c.SpotClient.BulkCancelOrders(symbol)
+}
+
+// CreateOCOOrder places an OCO pair unless safe mode is active
+func (c *safeModeSpotClient) CreateOCOOrder(order *OCORequest) (*OCOResponse, error) {
+	if err := c.safeMode.Check("order placement"); err != nil {
+		return nil, err
+	}
+	return c.SpotClient.CreateOCOOrder(order)
+}
+
+// CancelOrderList cancels an order list unless safe mode is active
+func (c *safeModeSpotClient) CancelOrderList(symbol string, orderListID int64) (*OrderList, error) {
+	if err := c.safeMode.Check("order cancellation"); err != nil {
+		return nil, err
+	}
+	return c.SpotClient.CancelOrderList(symbol, orderListID)
+}
+
+// ConvertDust converts dust to BNB unless safe mode is active
+func (c *safeModeSpotClient) ConvertDust(assets []string) (*DustConversionResult, error) {
+	if err := c.safeMode.Check("dust conversion"); err != nil {
+		return nil, err
+	}
+	return c.SpotClient.ConvertDust(assets)
+}
+
+// safeModeFuturesClient blocks the write operations of a futures client while safe mode is active
+type safeModeFuturesClient struct {
+	FuturesClient
+	safeMode *SafeMode
+}
+
+// NewSafeModeFuturesClient wraps a futures client so its write operations consult safe mode
+func NewSafeModeFuturesClient(client FuturesClient, safeMode *SafeMode) FuturesClient {
+	return &safeModeFuturesClient{FuturesClient: client, safeMode: safeMode}
+}
+
+// SetLeverage changes the leverage unless safe mode is active
+func (c *safeModeFuturesClient) SetLeverage(symbol string, leverage int) (*LeverageResponse, error) {
+	if err := c.safeMode.Check("leverage change"); err != nil {
+		return nil, err
+	}
+	return c.FuturesClient.SetLeverage(symbol, leverage)
+}
+
+// SetMarginType changes the margin type unless safe mode is active
+func (c *safeModeFuturesClient) SetMarginType(symbol string, marginType MarginType) error {
+	if err := c.safeMode.Check("margin type change"); err != nil {
+		return err
+	}
+	return c.FuturesClient.SetMarginType(symbol, marginType)
+}
+
+// SetPositionMode changes the position mode unless safe mode is active
+func (c *safeModeFuturesClient) SetPositionMode(dualSidePosition bool) error {
+	if err := c.safeMode.Check("position mode change"); err != nil {
+		return err
+	}
+	return c.FuturesClient.SetPositionMode(dualSidePosition)
+}
+
+// CreateOrder places an order unless safe mode is active
+func (c *safeModeFuturesClient) CreateOrder(order *FuturesOrderRequest) (*FuturesOrderResponse, error) {
+	if err := c.safeMode.Check("order placement"); err != nil {
+		return nil, err
+	}
+	return c.FuturesClient.CreateOrder(order)
+}
+
+// CancelOrder cancels an order unless safe mode is active
+func (c *safeModeFuturesClient) CancelOrder(symbol string, orderID int64) (*CancelResponse, error) {
+	if err := c.safeMode.Check("order cancellation"); err != nil {
+		return nil, err
+	}
+	return c.FuturesClient.CancelOrder(symbol, orderID)
+}
+
+// BulkCancelFuturesOrders cancels all open orders of a symbol unless safe mode is active
+func (c *safeModeFuturesClient) BulkCancelFuturesOrders(symbol string) ([]CancelResponse, error) {
+	if err := c.safeMode.Check("order cancellation"); err != nil {
+		return nil, err
+	}
+	return c.FuturesClient.BulkCancelFuturesOrders(symbol)
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"binance-trader/pkg/errors"
+)
+
+// recordingHTTPClient answers every request with a canned response and records the paths requested
+func recordingHTTPClient(requests *[]string) *mockHTTPClient {
+	respond := func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+		path := url
+		if i := strings.Index(path, "?"); i >= 0 {
+			path = path[:i]
+		}
+		*requests = append(*requests, method+" "+path)
+
+		switch {
+		case strings.HasSuffix(path, "/ticker/price"):
+			return []byte(`{"symbol":"BTCUSDT","price":"50000.00"}`), nil
+		case strings.HasSuffix(path, "/premiumIndex"):
+			return []byte(`{"symbol":"BTCUSDT","markPrice":50010.00,"indexPrice":50000.00,"lastFundingRate":0.0001,"nextFundingTime":1700000000000,"time":1700000000000}`), nil
+		case strings.HasSuffix(path, "/openOrders"):
+			return []byte(`[]`), nil
+		default:
+			return nil, fmt.Errorf("unexpected request %s %s", method, path)
+		}
+	}
+	return &mockHTTPClient{doFunc: respond, doWithRetryFunc: respond}
+}
+
+// assertSafeModeError checks that err is the safe mode error of an operation
+func assertSafeModeError(t *testing.T, operation string, err error) {
+	t.Helper()
+	tradingErr, ok := err.(*errors.TradingError)
+	if !ok || tradingErr.Type != errors.ErrSafeMode {
+		t.Errorf("%s: expected safe mode error, got %v", operation, err)
+		return
+	}
+	if !strings.HasPrefix(err.Error(), "safe mode active: ") {
+		t.Errorf("%s: unexpected message %q", operation, err.Error())
+	}
+}
+
+func TestSafeModeSpotClient(t *testing.T) {
+	var requests []string
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	inner, _ := NewBinanceClient("https://api.binance.com", recordingHTTPClient(&requests), authMgr)
+	client := NewSafeModeSpotClient(inner, NewSafeMode(true))
+
+	_, err := client.CreateOrder(&OrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeMarket, Quantity: 0.001})
+	assertSafeModeError(t, "CreateOrder", err)
+	_, err = client.CancelOrder("BTCUSDT", 12345)
+	assertSafeModeError(t, "CancelOrder", err)
+	_, err = client.CreateOCOOrder(&OCORequest{Symbol: "BTCUSDT", Side: OrderSideSell, Quantity: 0.1, Price: 55000, StopPrice: 48000, StopLimitPrice: 47900})
+	assertSafeModeError(t, "CreateOCOOrder", err)
+	_, err = client.CancelOrderList("BTCUSDT", 7)
+	assertSafeModeError(t, "CancelOrderList", err)
+	_, err = client.ConvertDust([]string{"SHIB"})
+	assertSafeModeError(t, "ConvertDust", err)
+
+	if len(requests) != 0 {
+		t.Fatalf("no request should reach the exchange in safe mode, got %v", requests)
+	}
+
+	price, err := client.GetPrice("BTCUSDT")
+	if err != nil || price.Price != 50000 {
+		t.Errorf("GetPrice() should still work in safe mode, got %+v, %v", price, err)
+	}
+	if _, err := client.GetOpenOrders("BTCUSDT"); err != nil {
+		t.Errorf("GetOpenOrders() should still work in safe mode, got %v", err)
+	}
+}
+
+func TestSafeModeFuturesClient(t *testing.T) {
+	var requests []string
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	inner, err := NewFuturesClient("https://fapi.binance.com", recordingHTTPClient(&requests), authMgr)
+	if err != nil {
+		t.Fatalf("NewFuturesClient() unexpected error: %v", err)
+	}
+	client := NewSafeModeFuturesClient(inner, NewSafeMode(true))
+
+	_, err = client.CreateOrder(&FuturesOrderRequest{Symbol: "BTCUSDT", Side: OrderSideBuy, Type: OrderTypeMarket, Quantity: 0.001})
+	assertSafeModeError(t, "CreateOrder", err)
+	_, err = client.CancelOrder("BTCUSDT", 12345)
+	assertSafeModeError(t, "CancelOrder", err)
+	_, err = client.SetLeverage("BTCUSDT", 10)
+	assertSafeModeError(t, "SetLeverage", err)
+	assertSafeModeError(t, "SetMarginType", client.SetMarginType("BTCUSDT", MarginTypeIsolated))
+	assertSafeModeError(t, "SetPositionMode", client.SetPositionMode(true))
+
+	if len(requests) != 0 {
+		t.Fatalf("no request should reach the exchange in safe mode, got %v", requests)
+	}
+
+	markPrice, err := client.GetMarkPrice("BTCUSDT")
+	if err != nil || markPrice.MarkPrice != 50010 {
+		t.Errorf("GetMarkPrice() should still work in safe mode, got %+v, %v", markPrice, err)
+	}
+	if _, err := client.GetOpenOrders("BTCUSDT"); err != nil {
+		t.Errorf("GetOpenOrders() should still work in safe mode, got %v", err)
+	}
+}
+
+func TestSafeModeInactive(t *testing.T) {
+	var requests []string
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	inner, _ := NewBinanceClient("https://api.binance.com", recordingHTTPClient(&requests), authMgr)
+	client := NewSafeModeSpotClient(inner, NewSafeMode(false))
+
+	// The canned client rejects the cancel, which proves the request was sent
+	if _, err := client.CancelOrder("BTCUSDT", 12345); err == nil || strings.Contains(err.Error(), "safe mode") {
+		t.Errorf("expected the exchange error without safe mode, got %v", err)
+	}
+	if len(requests) != 1 || requests[0] != "DELETE https://api.binance.com/api/v3/order" {
+		t.Errorf("expected the cancel request to be sent, got %v", requests)
+	}
+
+	var nilSafeMode *SafeMode
+	if nilSafeMode.IsActive() || nilSafeMode.Check("order placement") != nil {
+		t.Error("a missing safe mode switch should leave writes enabled")
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"binance-trader/pkg/timeutil"
 	"encoding/json"
 	"fmt"
 )
@@ -10,17 +11,48 @@ type SpotClient interface {
 	// Account information
 	GetAccountInfo() (*AccountInfo, error)
 	GetBalance(asset string) (*Balance, error)
+	GetAllBalances() ([]Balance, error)
 
 	// Market data
 	GetPrice(symbol string) (*Price, error)
+	GetAllPrices() ([]*Price, error)
+	GetBookTicker(symbol string) (*BookTicker, error)
+	GetOrderBook(symbol string, limit int) (*OrderBook, error)
 	GetKlines(symbol string, interval string, limit int) ([]*Kline, error)
 
 	// Order operations
 	CreateOrder(order *OrderRequest) (*OrderResponse, error)
 	CancelOrder(symbol string, orderID int64) (*CancelResponse, error)
+	// BulkCancelOrders cancels every open order of a symbol, including both legs of its order
+	// lists; when some fail, the cancelled orders come with a *BulkCancelError
+	BulkCancelOrders(symbol string) ([]CancelResponse, error)
 	GetOrder(symbol string, orderID int64) (*Order, error)
 	GetOpenOrders(symbol string) ([]*Order, error)
 	GetHistoricalOrders(symbol string, startTime, endTime int64) ([]*Order, error)
+	GetMyTrades(symbol string, orderID int64) ([]*Trade, error)
+
+	// Order lists (OCO)
+	CreateOCOOrder(order *OCORequest) (*OCOResponse, error)
+	CancelOrderList(symbol string, orderListID int64) (*OrderList, error)
+	GetOrderList(orderListID int64) (*OrderList, error)
+
+	// System status
+	GetSystemStatus() (*SystemStatus, error)
+	GetRateLimits() ([]RateLimitRule, error)
+	GetExchangeSymbols() ([]*ExchangeSymbol, error)
+	GetExchangeInfo() (*ExchangeInfo, error)
+
+	// Dust conversion to BNB
+	GetDustAssets() (*DustEligibility, error)
+	ConvertDust(assets []string) (*DustConversionResult, error)
+
+	// User data stream listen keys
+	ListenKeyClient
+}
+
+// SpotPriceClient reads spot prices from the public market data endpoint; it needs no credentials
+type SpotPriceClient interface {
+	GetPrice(symbol string) (*Price, error)
 }
 
 // spotClient implements SpotClient interface
@@ -51,52 +83,91 @@ func NewSpotClient(baseURL string, httpClient HTTPClient, authMgr *AuthManager)
 	}, nil
 }
 
+// NewSpotPriceClient creates a read-only spot client for prices, e.g. for a futures session
+// without spot API keys
+func NewSpotPriceClient(baseURL string, httpClient HTTPClient) (SpotPriceClient, error) {
+	if err := ValidateURL(baseURL); err != nil {
+		return nil, err
+	}
+	return &spotClient{
+		baseURL:    baseURL,
+		httpClient: httpClient,
+	}, nil
+}
+
+// signedURL returns a builder for a signed endpoint URL; every call stamps the params with
+// the current time and signs them again, so a retried request is not rejected as stale
+func (c *spotClient) signedURL(path string, params map[string]interface{}) func() (string, error) {
+	return func() (string, error) {
+		params["timestamp"] = c.authMgr.GenerateTimestamp()
+		queryString, err := c.authMgr.SignRequestWithParams(params)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s%s?%s", c.baseURL, path, queryString), nil
+	}
+}
+
 // GetAccountInfo retrieves account information from Binance
 func (c *spotClient) GetAccountInfo() (*AccountInfo, error) {
 	params := make(map[string]interface{})
-	params["timestamp"] = c.authMgr.GenerateTimestamp()
-	
-	queryString, err := c.authMgr.SignRequestWithParams(params)
-	if err != nil {
-		return nil, err
-	}
-	
-	url := fmt.Sprintf("%s/api/v3/account?%s", c.baseURL, queryString)
 	headers := map[string]string{
 		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
 	}
 	
-	body, err := c.httpClient.DoWithRetry("GET", url, nil, headers)
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryAccount, "GET", c.signedURL("/api/v3/account", params), headers)
 	if err != nil {
 		return nil, err
 	}
 	
-	var accountInfo AccountInfo
-	if err := json.Unmarshal(body, &accountInfo); err != nil {
+	var accountData struct {
+		AccountInfo
+		Balances []struct {
+			Asset  string `json:"asset"`
+			Free   string `json:"free"`
+			Locked string `json:"locked"`
+		} `json:"balances"`
+	}
+	if err := json.Unmarshal(body, &accountData); err != nil {
 		return nil, fmt.Errorf("failed to parse account info: %w", err)
 	}
+
+	accountInfo := accountData.AccountInfo
+	for _, data := range accountData.Balances {
+		balance := Balance{Asset: data.Asset}
+		if _, err := fmt.Sscanf(data.Free, "%f", &balance.Free); err != nil {
+			return nil, fmt.Errorf("failed to parse %s free balance %q: %w", data.Asset, data.Free, err)
+		}
+		if _, err := fmt.Sscanf(data.Locked, "%f", &balance.Locked); err != nil {
+			return nil, fmt.Errorf("failed to parse %s locked balance %q: %w", data.Asset, data.Locked, err)
+		}
+		if balance.Free != 0 || balance.Locked != 0 {
+			accountInfo.Balances = append(accountInfo.Balances, balance)
+		}
+	}
 	
 	return &accountInfo, nil
 }
 
+// GetAllBalances retrieves every non-zero balance of the account
+func (c *spotClient) GetAllBalances() ([]Balance, error) {
+	accountInfo, err := c.GetAccountInfo()
+	if err != nil {
+		return nil, err
+	}
+	return accountInfo.Balances, nil
+}
+
 // GetBalance retrieves the balance for a specific asset
 func (c *spotClient) GetBalance(asset string) (*Balance, error) {
 	// Parse balances from account info
 	var rawData map[string]interface{}
 	params := make(map[string]interface{})
-	params["timestamp"] = c.authMgr.GenerateTimestamp()
-	
-	queryString, err := c.authMgr.SignRequestWithParams(params)
-	if err != nil {
-		return nil, err
-	}
-	
-	url := fmt.Sprintf("%s/api/v3/account?%s", c.baseURL, queryString)
 	headers := map[string]string{
 		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
 	}
 	
-	body, err := c.httpClient.DoWithRetry("GET", url, nil, headers)
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryAccount, "GET", c.signedURL("/api/v3/account", params), headers)
 	if err != nil {
 		return nil, err
 	}
@@ -146,7 +217,7 @@ func (c *spotClient) GetPrice(symbol string) (*Price, error) {
 	
 	url := fmt.Sprintf("%s/api/v3/ticker/price", c.baseURL)
 	
-	body, err := c.httpClient.DoWithRetry("GET", url, params, nil)
+	body, err := c.httpClient.DoWithRetryCategory(EndpointCategoryMarketData, "GET", url, params, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -171,6 +242,74 @@ func (c *spotClient) GetPrice(symbol string) (*Price, error) {
 	}, nil
 }
 
+// GetAllPrices retrieves the current price of every symbol in one request
+func (c *spotClient) GetAllPrices() ([]*Price, error) {
+	url := fmt.Sprintf("%s/api/v3/ticker/price", c.baseURL)
+
+	body, err := c.httpClient.DoWithRetryCategory(EndpointCategoryMarketData, "GET", url, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pricesData []struct {
+		Symbol string `json:"symbol"`
+		Price  string `json:"price"`
+	}
+	if err := json.Unmarshal(body, &pricesData); err != nil {
+		return nil, fmt.Errorf("failed to parse prices: %w", err)
+	}
+
+	prices := make([]*Price, 0, len(pricesData))
+	for _, data := range pricesData {
+		price := &Price{Symbol: data.Symbol}
+		if _, err := fmt.Sscanf(data.Price, "%f", &price.Price); err != nil {
+			return nil, fmt.Errorf("failed to parse %s price %q: %w", data.Symbol, data.Price, err)
+		}
+		prices = append(prices, price)
+	}
+	return prices, nil
+}
+
+// GetBookTicker retrieves the best bid and ask for a symbol
+func (c *spotClient) GetBookTicker(symbol string) (*BookTicker, error) {
+	params := map[string]interface{}{
+		"symbol": symbol,
+	}
+	
+	url := fmt.Sprintf("%s/api/v3/ticker/bookTicker", c.baseURL)
+	
+	body, err := c.httpClient.DoWithRetryCategory(EndpointCategoryMarketData, "GET", url, params, nil)
+	if err != nil {
+		return nil, err
+	}
+	
+	return parseBookTickerResponse(body)
+}
+
+// GetOrderBook retrieves the order book of a symbol down to limit levels per side
+func (c *spotClient) GetOrderBook(symbol string, limit int) (*OrderBook, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol cannot be empty")
+	}
+	if err := ValidateOrderBookLimit(limit); err != nil {
+		return nil, err
+	}
+	
+	params := map[string]interface{}{
+		"symbol": symbol,
+		"limit":  limit,
+	}
+	
+	url := fmt.Sprintf("%s/api/v3/depth", c.baseURL)
+	
+	body, err := c.httpClient.DoWithRetryCategory(EndpointCategoryMarketData, "GET", url, params, nil)
+	if err != nil {
+		return nil, err
+	}
+	
+	return parseOrderBookResponse(symbol, body)
+}
+
 // GetKlines retrieves candlestick data for a symbol
 func (c *spotClient) GetKlines(symbol string, interval string, limit int) ([]*Kline, error) {
 	params := map[string]interface{}{
@@ -181,7 +320,7 @@ func (c *spotClient) GetKlines(symbol string, interval string, limit int) ([]*Kl
 	
 	url := fmt.Sprintf("%s/api/v3/klines", c.baseURL)
 	
-	body, err := c.httpClient.DoWithRetry("GET", url, params, nil)
+	body, err := c.httpClient.DoWithRetryCategory(EndpointCategoryMarketData, "GET", url, params, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -238,8 +377,19 @@ func (c *spotClient) CreateOrder(order *OrderRequest) (*OrderResponse, error) {
 	params["symbol"] = order.Symbol
 	params["side"] = string(order.Side)
 	params["type"] = string(order.Type)
-	params["quantity"] = order.Quantity
-	params["timestamp"] = c.authMgr.GenerateTimestamp()
+	
+	// Market orders may be sized by quote amount instead of base quantity, but not both
+	if order.QuoteOrderQty > 0 {
+		if order.Quantity > 0 {
+			return nil, fmt.Errorf("quantity and quoteOrderQty cannot both be set")
+		}
+		if order.Type != OrderTypeMarket {
+			return nil, fmt.Errorf("quoteOrderQty is only supported for market orders")
+		}
+		params["quoteOrderQty"] = order.QuoteOrderQty
+	} else {
+		params["quantity"] = order.Quantity
+	}
 	
 	// Only include price for limit orders
 	if order.Type == OrderTypeLimit {
@@ -256,17 +406,15 @@ func (c *spotClient) CreateOrder(order *OrderRequest) (*OrderResponse, error) {
 		}
 	}
 	
-	queryString, err := c.authMgr.SignRequestWithParams(params)
-	if err != nil {
-		return nil, err
+	if order.NewClientOrderID != "" {
+		params["newClientOrderId"] = order.NewClientOrderID
 	}
 	
-	url := fmt.Sprintf("%s/api/v3/order?%s", c.baseURL, queryString)
 	headers := map[string]string{
 		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
 	}
 	
-	body, err := c.httpClient.DoWithRetry("POST", url, nil, headers)
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryOrder, "POST", c.signedURL("/api/v3/order", params), headers)
 	if err != nil {
 		return nil, err
 	}
@@ -291,19 +439,11 @@ func (c *spotClient) CancelOrder(symbol string, orderID int64) (*CancelResponse,
 	params := make(map[string]interface{})
 	params["symbol"] = symbol
 	params["orderId"] = orderID
-	params["timestamp"] = c.authMgr.GenerateTimestamp()
-	
-	queryString, err := c.authMgr.SignRequestWithParams(params)
-	if err != nil {
-		return nil, err
-	}
-	
-	url := fmt.Sprintf("%s/api/v3/order?%s", c.baseURL, queryString)
 	headers := map[string]string{
 		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
 	}
 	
-	body, err := c.httpClient.DoWithRetry("DELETE", url, nil, headers)
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryOrder, "DELETE", c.signedURL("/api/v3/order", params), headers)
 	if err != nil {
 		return nil, err
 	}
@@ -316,6 +456,37 @@ func (c *spotClient) CancelOrder(symbol string, orderID int64) (*CancelResponse,
 	return &response, nil
 }
 
+// BulkCancelOrders cancels all open orders of a symbol in one request
+func (c *spotClient) BulkCancelOrders(symbol string) ([]CancelResponse, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol cannot be empty")
+	}
+	
+	params := make(map[string]interface{})
+	params["symbol"] = symbol
+	headers := map[string]string{
+		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
+	}
+	
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryOrder, "DELETE", c.signedURL("/api/v3/openOrders", params), headers)
+	if binanceErrorCodeOf(err) == errCodeUnknownOrder {
+		// The symbol has no open orders
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	
+	cancelled, failures, err := parseBulkCancelResponse(body, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(failures) > 0 {
+		return cancelled, &BulkCancelError{Symbol: symbol, Cancelled: len(cancelled), Failures: failures}
+	}
+	return cancelled, nil
+}
+
 // GetOrder retrieves order details
 func (c *spotClient) GetOrder(symbol string, orderID int64) (*Order, error) {
 	if symbol == "" {
@@ -328,19 +499,11 @@ func (c *spotClient) GetOrder(symbol string, orderID int64) (*Order, error) {
 	params := make(map[string]interface{})
 	params["symbol"] = symbol
 	params["orderId"] = orderID
-	params["timestamp"] = c.authMgr.GenerateTimestamp()
-	
-	queryString, err := c.authMgr.SignRequestWithParams(params)
-	if err != nil {
-		return nil, err
-	}
-	
-	url := fmt.Sprintf("%s/api/v3/order?%s", c.baseURL, queryString)
 	headers := map[string]string{
 		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
 	}
 	
-	body, err := c.httpClient.DoWithRetry("GET", url, nil, headers)
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryOrder, "GET", c.signedURL("/api/v3/order", params), headers)
 	if err != nil {
 		return nil, err
 	}
@@ -359,19 +522,11 @@ func (c *spotClient) GetOpenOrders(symbol string) ([]*Order, error) {
 	if symbol != "" {
 		params["symbol"] = symbol
 	}
-	params["timestamp"] = c.authMgr.GenerateTimestamp()
-	
-	queryString, err := c.authMgr.SignRequestWithParams(params)
-	if err != nil {
-		return nil, err
-	}
-	
-	url := fmt.Sprintf("%s/api/v3/openOrders?%s", c.baseURL, queryString)
 	headers := map[string]string{
 		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
 	}
 	
-	body, err := c.httpClient.DoWithRetry("GET", url, nil, headers)
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryOrder, "GET", c.signedURL("/api/v3/openOrders", params), headers)
 	if err != nil {
 		return nil, err
 	}
@@ -384,11 +539,18 @@ func (c *spotClient) GetOpenOrders(symbol string) ([]*Order, error) {
 	return orders, nil
 }
 
-// GetHistoricalOrders retrieves historical orders
+// GetHistoricalOrders retrieves historical orders placed within a range given in Unix
+// milliseconds, the exchange's resolution; zero leaves that end of the range open
 func (c *spotClient) GetHistoricalOrders(symbol string, startTime, endTime int64) ([]*Order, error) {
 	if symbol == "" {
 		return nil, fmt.Errorf("symbol cannot be empty")
 	}
+	if err := timeutil.ValidateMillis("start time", startTime); err != nil {
+		return nil, err
+	}
+	if err := timeutil.ValidateMillis("end time", endTime); err != nil {
+		return nil, err
+	}
 	
 	params := make(map[string]interface{})
 	params["symbol"] = symbol
@@ -398,27 +560,513 @@ func (c *spotClient) GetHistoricalOrders(symbol string, startTime, endTime int64
 	if endTime > 0 {
 		params["endTime"] = endTime
 	}
-	params["timestamp"] = c.authMgr.GenerateTimestamp()
+	headers := map[string]string{
+		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
+	}
 	
-	queryString, err := c.authMgr.SignRequestWithParams(params)
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryAccount, "GET", c.signedURL("/api/v3/allOrders", params), headers)
 	if err != nil {
 		return nil, err
 	}
 	
-	url := fmt.Sprintf("%s/api/v3/allOrders?%s", c.baseURL, queryString)
+	var orders []*Order
+	if err := json.Unmarshal(body, &orders); err != nil {
+		return nil, fmt.Errorf("failed to parse historical orders: %w", err)
+	}
+	
+	return orders, nil
+}
+
+// GetMyTrades retrieves the fills of an order
+func (c *spotClient) GetMyTrades(symbol string, orderID int64) ([]*Trade, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol cannot be empty")
+	}
+	if orderID <= 0 {
+		return nil, fmt.Errorf("orderID must be greater than 0")
+	}
+	
+	params := make(map[string]interface{})
+	params["symbol"] = symbol
+	params["orderId"] = orderID
 	headers := map[string]string{
 		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
 	}
 	
-	body, err := c.httpClient.DoWithRetry("GET", url, nil, headers)
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryAccount, "GET", c.signedURL("/api/v3/myTrades", params), headers)
 	if err != nil {
 		return nil, err
 	}
 	
-	var orders []*Order
-	if err := json.Unmarshal(body, &orders); err != nil {
-		return nil, fmt.Errorf("failed to parse historical orders: %w", err)
+	var tradesData []struct {
+		ID              int64  `json:"id"`
+		Symbol          string `json:"symbol"`
+		OrderID         int64  `json:"orderId"`
+		Price           string `json:"price"`
+		Qty             string `json:"qty"`
+		QuoteQty        string `json:"quoteQty"`
+		Commission      string `json:"commission"`
+		CommissionAsset string `json:"commissionAsset"`
+		Time            int64  `json:"time"`
+		IsBuyer         bool   `json:"isBuyer"`
+		IsMaker         bool   `json:"isMaker"`
+	}
+	if err := json.Unmarshal(body, &tradesData); err != nil {
+		return nil, fmt.Errorf("failed to parse trades: %w", err)
 	}
 	
-	return orders, nil
+	trades := make([]*Trade, 0, len(tradesData))
+	for _, data := range tradesData {
+		trade := &Trade{
+			ID:              data.ID,
+			Symbol:          data.Symbol,
+			OrderID:         data.OrderID,
+			CommissionAsset: data.CommissionAsset,
+			Time:            data.Time,
+			IsBuyer:         data.IsBuyer,
+			IsMaker:         data.IsMaker,
+		}
+		for _, field := range []struct {
+			raw   string
+			value *float64
+		}{
+			{data.Price, &trade.Price},
+			{data.Qty, &trade.Qty},
+			{data.QuoteQty, &trade.QuoteQty},
+			{data.Commission, &trade.Commission},
+		} {
+			if _, err := fmt.Sscanf(field.raw, "%f", field.value); err != nil {
+				return nil, fmt.Errorf("failed to parse trade %d value %q: %w", data.ID, field.raw, err)
+			}
+		}
+		trades = append(trades, trade)
+	}
+	
+	return trades, nil
+}
+
+// CreateOCOOrder places both legs of an OCO pair in a single request. The legs are given as
+// the one above and the one below the market: a sell rests its limit leg above and its stop
+// below, a buy the other way round.
+func (c *spotClient) CreateOCOOrder(order *OCORequest) (*OCOResponse, error) {
+	if order == nil {
+		return nil, fmt.Errorf("OCO request cannot be nil")
+	}
+	if order.Symbol == "" {
+		return nil, fmt.Errorf("symbol cannot be empty")
+	}
+	if order.Quantity <= 0 || order.Price <= 0 || order.StopPrice <= 0 || order.StopLimitPrice < 0 {
+		return nil, fmt.Errorf("quantity, price and stop price must be greater than 0")
+	}
+	
+	limitLeg, stopLeg := "above", "below"
+	switch order.Side {
+	case OrderSideSell:
+	case OrderSideBuy:
+		limitLeg, stopLeg = "below", "above"
+	default:
+		return nil, fmt.Errorf("invalid OCO side: %s", order.Side)
+	}
+	
+	params := make(map[string]interface{})
+	params["symbol"] = order.Symbol
+	params["side"] = string(order.Side)
+	params["quantity"] = order.Quantity
+	params[limitLeg+"Type"] = string(OrderTypeLimitMaker)
+	params[limitLeg+"Price"] = order.Price
+	params[stopLeg+"StopPrice"] = order.StopPrice
+	if order.StopLimitPrice > 0 {
+		params[stopLeg+"Type"] = string(OrderTypeStopLossLimit)
+		params[stopLeg+"Price"] = order.StopLimitPrice
+		params[stopLeg+"TimeInForce"] = "GTC"
+	} else {
+		params[stopLeg+"Type"] = string(OrderTypeStopLoss)
+	}
+	headers := map[string]string{
+		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
+	}
+	
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryOrder, "POST", c.signedURL("/api/v3/orderList/oco", params), headers)
+	if err != nil {
+		return nil, err
+	}
+	
+	var response OCOResponse
+	if err := json.Unmarshal(body, &response.OrderList); err != nil {
+		return nil, fmt.Errorf("failed to parse OCO order response: %w", err)
+	}
+	
+	// The legs are told apart by type; everything but the limit maker is the stop leg
+	for _, leg := range response.OrderReports {
+		if leg.Type == OrderTypeLimitMaker {
+			response.LimitOrderID = leg.OrderID
+		} else {
+			response.StopOrderID = leg.OrderID
+		}
+	}
+	if response.LimitOrderID == 0 || response.StopOrderID == 0 {
+		return nil, fmt.Errorf("OCO order response for list %d is missing a leg", response.OrderListID)
+	}
+	
+	return &response, nil
+}
+
+// CancelOrderList cancels every leg of an order list
+func (c *spotClient) CancelOrderList(symbol string, orderListID int64) (*OrderList, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol cannot be empty")
+	}
+	if orderListID <= 0 {
+		return nil, fmt.Errorf("orderListID must be greater than 0")
+	}
+	
+	params := make(map[string]interface{})
+	params["symbol"] = symbol
+	params["orderListId"] = orderListID
+	headers := map[string]string{
+		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
+	}
+	
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryOrder, "DELETE", c.signedURL("/api/v3/orderList", params), headers)
+	if err != nil {
+		return nil, err
+	}
+	
+	var list OrderList
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse order list cancel response: %w", err)
+	}
+	
+	return &list, nil
+}
+
+// GetOrderList retrieves the status of an order list and the IDs of its legs
+func (c *spotClient) GetOrderList(orderListID int64) (*OrderList, error) {
+	if orderListID <= 0 {
+		return nil, fmt.Errorf("orderListID must be greater than 0")
+	}
+	
+	params := make(map[string]interface{})
+	params["orderListId"] = orderListID
+	headers := map[string]string{
+		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
+	}
+	
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryOrder, "GET", c.signedURL("/api/v3/orderList", params), headers)
+	if err != nil {
+		return nil, err
+	}
+	
+	var list OrderList
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse order list: %w", err)
+	}
+	
+	return &list, nil
+}
+
+// GetSystemStatus retrieves the exchange system status (normal or maintenance)
+func (c *spotClient) GetSystemStatus() (*SystemStatus, error) {
+	url := fmt.Sprintf("%s/sapi/v1/system/status", c.baseURL)
+	
+	// Single attempt: callers poll this endpoint periodically
+	body, err := c.httpClient.DoWithCategory(EndpointCategoryDefault, "GET", url, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	
+	var status SystemStatus
+	if err := json.Unmarshal(body, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse system status: %w", err)
+	}
+	
+	return &status, nil
+}
+
+// GetRateLimits retrieves the request weight and order rate limits from exchange info
+func (c *spotClient) GetRateLimits() ([]RateLimitRule, error) {
+	url := fmt.Sprintf("%s/api/v3/exchangeInfo", c.baseURL)
+	
+	body, err := c.httpClient.DoWithRetryCategory(EndpointCategoryMarketData, "GET", url, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	
+	var exchangeInfo struct {
+		RateLimits []RateLimitRule `json:"rateLimits"`
+	}
+	if err := json.Unmarshal(body, &exchangeInfo); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange info: %w", err)
+	}
+	
+	return exchangeInfo.RateLimits, nil
+}
+
+// GetExchangeSymbols retrieves the trading status of every symbol from exchange info
+func (c *spotClient) GetExchangeSymbols() ([]*ExchangeSymbol, error) {
+	url := fmt.Sprintf("%s/api/v3/exchangeInfo", c.baseURL)
+	
+	body, err := c.httpClient.DoWithRetryCategory(EndpointCategoryMarketData, "GET", url, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	
+	var exchangeInfo struct {
+		Symbols []*ExchangeSymbol `json:"symbols"`
+	}
+	if err := json.Unmarshal(body, &exchangeInfo); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange info: %w", err)
+	}
+	
+	return exchangeInfo.Symbols, nil
+}
+
+// GetExchangeInfo retrieves the order filters of every symbol from exchange info
+func (c *spotClient) GetExchangeInfo() (*ExchangeInfo, error) {
+	url := fmt.Sprintf("%s/api/v3/exchangeInfo", c.baseURL)
+
+	body, err := c.httpClient.DoWithRetryCategory(EndpointCategoryMarketData, "GET", url, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		ServerTime int64 `json:"serverTime"`
+		Symbols    []struct {
+			Symbol  string `json:"symbol"`
+			Status  string `json:"status"`
+			Filters []struct {
+				FilterType  string `json:"filterType"`
+				MinQty      string `json:"minQty"`
+				MaxQty      string `json:"maxQty"`
+				StepSize    string `json:"stepSize"`
+				MinPrice    string `json:"minPrice"`
+				MaxPrice    string `json:"maxPrice"`
+				TickSize    string `json:"tickSize"`
+				MinNotional string `json:"minNotional"`
+			} `json:"filters"`
+		} `json:"symbols"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange info: %w", err)
+	}
+
+	type filterField struct {
+		raw   string
+		value *float64
+	}
+
+	info := &ExchangeInfo{ServerTime: data.ServerTime, Symbols: make([]*SymbolInfo, 0, len(data.Symbols))}
+	for _, symbol := range data.Symbols {
+		symbolInfo := &SymbolInfo{Symbol: symbol.Symbol, Status: symbol.Status}
+		for _, filter := range symbol.Filters {
+			var fields []filterField
+			switch filter.FilterType {
+			case "LOT_SIZE":
+				fields = []filterField{{filter.MinQty, &symbolInfo.MinQty}, {filter.MaxQty, &symbolInfo.MaxQty}, {filter.StepSize, &symbolInfo.StepSize}}
+			case "PRICE_FILTER":
+				fields = []filterField{{filter.MinPrice, &symbolInfo.MinPrice}, {filter.MaxPrice, &symbolInfo.MaxPrice}, {filter.TickSize, &symbolInfo.TickSize}}
+			case "MIN_NOTIONAL", "NOTIONAL":
+				fields = []filterField{{filter.MinNotional, &symbolInfo.MinNotional}}
+			}
+			for _, field := range fields {
+				if field.raw == "" {
+					continue
+				}
+				if _, err := fmt.Sscanf(field.raw, "%f", field.value); err != nil {
+					return nil, fmt.Errorf("failed to parse %s filter of %s: %w", filter.FilterType, symbol.Symbol, err)
+				}
+			}
+		}
+		info.Symbols = append(info.Symbols, symbolInfo)
+	}
+
+	return info, nil
+}
+
+// GetDustAssets retrieves the balances that can currently be converted to BNB
+func (c *spotClient) GetDustAssets() (*DustEligibility, error) {
+	params := make(map[string]interface{})
+	headers := map[string]string{
+		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
+	}
+
+	body, err := c.httpClient.DoSignedWithRetryCategory(EndpointCategoryAccount, "POST", c.signedURL("/sapi/v1/asset/dust-btc", params), headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Details []struct {
+			Asset      string `json:"asset"`
+			AmountFree string `json:"amountFree"`
+			ToBTC      string `json:"toBTC"`
+			ToBNB      string `json:"toBNB"`
+		} `json:"details"`
+		TotalTransferBNB   string `json:"totalTransferBNB"`
+		DribbletPercentage string `json:"dribbletPercentage"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse dust assets: %w", err)
+	}
+
+	eligibility := &DustEligibility{Details: make([]*DustAsset, 0, len(data.Details))}
+	for _, field := range []struct {
+		raw   string
+		value *float64
+	}{
+		{data.TotalTransferBNB, &eligibility.TotalTransferBNB},
+		{data.DribbletPercentage, &eligibility.DribbletPercentage},
+	} {
+		if _, err := fmt.Sscanf(field.raw, "%f", field.value); err != nil {
+			return nil, fmt.Errorf("failed to parse dust total %q: %w", field.raw, err)
+		}
+	}
+
+	for _, detail := range data.Details {
+		asset := &DustAsset{Asset: detail.Asset}
+		for _, field := range []struct {
+			raw   string
+			value *float64
+		}{
+			{detail.AmountFree, &asset.AmountFree},
+			{detail.ToBTC, &asset.ToBTC},
+			{detail.ToBNB, &asset.ToBNB},
+		} {
+			if _, err := fmt.Sscanf(field.raw, "%f", field.value); err != nil {
+				return nil, fmt.Errorf("failed to parse dust asset %s value %q: %w", detail.Asset, field.raw, err)
+			}
+		}
+		eligibility.Details = append(eligibility.Details, asset)
+	}
+
+	return eligibility, nil
+}
+
+// ConvertDust converts the given dust balances to BNB
+func (c *spotClient) ConvertDust(assets []string) (*DustConversionResult, error) {
+	if len(assets) == 0 {
+		return nil, fmt.Errorf("at least one asset is required")
+	}
+
+	params := make(map[string]interface{})
+	params["asset"] = assets
+	params["timestamp"] = c.authMgr.GenerateTimestamp()
+
+	queryString, err := c.authMgr.SignRequestWithParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/sapi/v1/asset/dust?%s", c.baseURL, queryString)
+	headers := map[string]string{
+		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
+	}
+
+	// Single attempt: a retried conversion could run twice if the first response was lost
+	body, err := c.httpClient.DoWithCategory(EndpointCategoryAccount, "POST", url, nil, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		TotalServiceCharge string `json:"totalServiceCharge"`
+		TotalTransfered    string `json:"totalTransfered"`
+		TransferResult     []struct {
+			Amount              string `json:"amount"`
+			FromAsset           string `json:"fromAsset"`
+			OperateTime         int64  `json:"operateTime"`
+			ServiceChargeAmount string `json:"serviceChargeAmount"`
+			TranID              int64  `json:"tranId"`
+			TransferedAmount    string `json:"transferedAmount"`
+		} `json:"transferResult"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse dust conversion: %w", err)
+	}
+
+	result := &DustConversionResult{Transfers: make([]*DustTransfer, 0, len(data.TransferResult))}
+	for _, field := range []struct {
+		raw   string
+		value *float64
+	}{
+		{data.TotalServiceCharge, &result.TotalServiceCharge},
+		{data.TotalTransfered, &result.TotalTransferred},
+	} {
+		if _, err := fmt.Sscanf(field.raw, "%f", field.value); err != nil {
+			return nil, fmt.Errorf("failed to parse dust conversion total %q: %w", field.raw, err)
+		}
+	}
+
+	for _, transfer := range data.TransferResult {
+		converted := &DustTransfer{
+			FromAsset:   transfer.FromAsset,
+			TranID:      transfer.TranID,
+			OperateTime: transfer.OperateTime,
+		}
+		for _, field := range []struct {
+			raw   string
+			value *float64
+		}{
+			{transfer.Amount, &converted.Amount},
+			{transfer.TransferedAmount, &converted.TransferredAmount},
+			{transfer.ServiceChargeAmount, &converted.ServiceChargeAmount},
+		} {
+			if _, err := fmt.Sscanf(field.raw, "%f", field.value); err != nil {
+				return nil, fmt.Errorf("failed to parse dust transfer %s value %q: %w", transfer.FromAsset, field.raw, err)
+			}
+		}
+		result.Transfers = append(result.Transfers, converted)
+	}
+
+	return result, nil
+}
+
+// CreateListenKey starts a user data stream, or returns the listen key of the one already open
+func (c *spotClient) CreateListenKey() (string, error) {
+	url := fmt.Sprintf("%s/api/v3/userDataStream", c.baseURL)
+	headers := map[string]string{
+		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
+	}
+	
+	body, err := c.httpClient.DoWithRetryCategory(EndpointCategoryAccount, "POST", url, nil, headers)
+	if err != nil {
+		return "", err
+	}
+	
+	var response struct {
+		ListenKey string `json:"listenKey"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("failed to parse listen key response: %w", err)
+	}
+	if response.ListenKey == "" {
+		return "", fmt.Errorf("listen key response has no listen key")
+	}
+	return response.ListenKey, nil
+}
+
+// KeepAliveListenKey extends the validity of a listen key by 60 minutes
+func (c *spotClient) KeepAliveListenKey(listenKey string) error {
+	return c.listenKeyRequest("PUT", listenKey)
+}
+
+// CloseListenKey closes a user data stream
+func (c *spotClient) CloseListenKey(listenKey string) error {
+	return c.listenKeyRequest("DELETE", listenKey)
+}
+
+// listenKeyRequest sends a keepalive or close request for a listen key
+func (c *spotClient) listenKeyRequest(method, listenKey string) error {
+	if listenKey == "" {
+		return fmt.Errorf("listen key cannot be empty")
+	}
+	
+	url := fmt.Sprintf("%s/api/v3/userDataStream?listenKey=%s", c.baseURL, listenKey)
+	headers := map[string]string{
+		"X-MBX-APIKEY": c.authMgr.GetAPIKey(),
+	}
+	
+	_, err := c.httpClient.DoWithRetryCategory(EndpointCategoryAccount, method, url, nil, headers)
+	return err
 }
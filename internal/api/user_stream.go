@@ -0,0 +1,398 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultUserStreamURL is the Binance spot user data stream endpoint; the listen key is appended
+const DefaultUserStreamURL = "wss://stream.binance.com:9443/ws"
+
+const (
+	// ListenKeyKeepAliveInterval is how often the listen key of a user data stream is kept alive;
+	// Binance expires keys 60 minutes after the last keepalive
+	ListenKeyKeepAliveInterval = 30 * time.Minute
+
+	// errCodeListenKeyNotExist is returned for a listen key that expired or was closed
+	errCodeListenKeyNotExist = -1125
+
+	// userStreamReadTimeout drops a silent connection; Binance pings every 20 seconds even when
+	// no account event is pushed
+	userStreamReadTimeout = time.Minute
+)
+
+// ListenKeyClient opens, keeps alive and closes user data streams
+type ListenKeyClient interface {
+	// CreateListenKey starts a user data stream, or returns the key of the one already open
+	CreateListenKey() (string, error)
+	KeepAliveListenKey(listenKey string) error
+	CloseListenKey(listenKey string) error
+}
+
+// executionReport is an order update frame of the user data stream. Every key whose other case
+// also appears in the frame is tagged, so encoding/json never matches them case-insensitively.
+type executionReport struct {
+	EventType       string `json:"e"`
+	EventTime       int64  `json:"E"`
+	Symbol          string `json:"s"`
+	ClientOrderID   string `json:"c"`
+	OrigClientID    string `json:"C"` // Client order ID of the cancelled order
+	Side            string `json:"S"`
+	Type            string `json:"o"`
+	Quantity        string `json:"q"`
+	QuoteQty        string `json:"Q"`
+	Price           string `json:"p"`
+	StopPrice       string `json:"P"`
+	OrderListID     int64  `json:"g"`
+	ExecutionType   string `json:"x"`
+	Status          string `json:"X"`
+	OrderID         int64  `json:"i"`
+	Ignore          int64  `json:"I"`
+	CumulativeQty   string `json:"z"`
+	CumulativeQuote string `json:"Z"`
+	TradeID         int64  `json:"t"`
+	TransactionTime int64  `json:"T"`
+	CreationTime    int64  `json:"O"`
+}
+
+// ParseExecutionReport parses an executionReport frame of the user data stream into the order
+// it updates
+func ParseExecutionReport(data []byte) (*Order, error) {
+	var report executionReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse execution report: %w", err)
+	}
+	if report.EventType != "executionReport" || report.OrderID <= 0 {
+		return nil, fmt.Errorf("not an execution report")
+	}
+
+	order := &Order{
+		OrderID:       report.OrderID,
+		Symbol:        report.Symbol,
+		ClientOrderID: report.ClientOrderID,
+		Side:          OrderSide(report.Side),
+		Type:          OrderType(report.Type),
+		Status:        OrderStatus(report.Status),
+		Time:          report.CreationTime,
+		UpdateTime:    report.TransactionTime,
+		OrderListID:   report.OrderListID,
+	}
+	// A cancellation reports the cancel request's client ID and the order's own one in "C"
+	if report.OrigClientID != "" {
+		order.ClientOrderID = report.OrigClientID
+	}
+	for _, field := range []struct {
+		name  string
+		raw   string
+		value *float64
+	}{
+		{"price", report.Price, &order.Price},
+		{"stop price", report.StopPrice, &order.StopPrice},
+		{"quantity", report.Quantity, &order.OrigQty},
+		{"executed quantity", report.CumulativeQty, &order.ExecutedQty},
+		{"quote quantity", report.CumulativeQuote, &order.CummulativeQuoteQty},
+	} {
+		value, err := strconv.ParseFloat(field.raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse execution report %s %q: %w", field.name, field.raw, err)
+		}
+		*field.value = value
+	}
+	return order, nil
+}
+
+// UserDataStream pushes the account's order updates. It keeps the listen key alive and, when
+// the connection drops or the key expires, reconnects with a fresh key and exponential backoff.
+type UserDataStream interface {
+	// SubscribeOrderUpdates calls handler with the order of every execution report: fills,
+	// partial fills, cancellations and expiries. The first subscription opens the stream. It
+	// returns a function dropping the subscription.
+	SubscribeOrderUpdates(handler func(*Order)) (func(), error)
+
+	// Connected reports whether the stream is currently connected
+	Connected() bool
+
+	Close() error
+}
+
+// UserStreamOptions configures a user data stream; zero values use the defaults
+type UserStreamOptions struct {
+	URL               string
+	ReconnectInitial  time.Duration // First reconnect delay, doubled after every failed attempt
+	ReconnectMax      time.Duration
+	KeepAliveInterval time.Duration
+}
+
+// orderUpdateHandler is one order update subscription
+type orderUpdateHandler struct {
+	handle func(*Order)
+}
+
+// userDataStream implements UserDataStream
+type userDataStream struct {
+	client           ListenKeyClient
+	url              string
+	reconnectInitial time.Duration
+	reconnectMax     time.Duration
+	keepAlive        time.Duration
+	closed           chan struct{}
+	closeOnce        sync.Once
+
+	mu        sync.Mutex
+	handlers  []*orderUpdateHandler
+	conn      *wsConn
+	listenKey string
+	started   bool
+}
+
+// NewUserDataStream creates a user data stream whose listen keys come from client; it connects
+// on the first subscription
+func NewUserDataStream(client ListenKeyClient, opts UserStreamOptions) (UserDataStream, error) {
+	if client == nil {
+		return nil, fmt.Errorf("listen key client cannot be nil")
+	}
+	if opts.URL == "" {
+		opts.URL = DefaultUserStreamURL
+	}
+	if !strings.HasPrefix(opts.URL, "wss://") && !strings.HasPrefix(opts.URL, "ws://") {
+		return nil, fmt.Errorf("stream URL must use ws or wss, got: %s", opts.URL)
+	}
+	if opts.ReconnectInitial <= 0 {
+		opts.ReconnectInitial = DefaultStreamReconnectInitial
+	}
+	if opts.ReconnectMax < opts.ReconnectInitial {
+		opts.ReconnectMax = max(DefaultStreamReconnectMax, opts.ReconnectInitial)
+	}
+	if opts.KeepAliveInterval <= 0 {
+		opts.KeepAliveInterval = ListenKeyKeepAliveInterval
+	}
+
+	return &userDataStream{
+		client:           client,
+		url:              strings.TrimSuffix(opts.URL, "/"),
+		reconnectInitial: opts.ReconnectInitial,
+		reconnectMax:     opts.ReconnectMax,
+		keepAlive:        opts.KeepAliveInterval,
+		closed:           make(chan struct{}),
+	}, nil
+}
+
+// SubscribeOrderUpdates adds an order update handler
+func (s *userDataStream) SubscribeOrderUpdates(handler func(*Order)) (func(), error) {
+	if handler == nil {
+		return nil, fmt.Errorf("handler cannot be nil")
+	}
+	subscription := &orderUpdateHandler{handle: handler}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.closed:
+		return nil, fmt.Errorf("user data stream is closed")
+	default:
+	}
+	s.handlers = append(s.handlers, subscription)
+	if !s.started {
+		s.started = true
+		go s.run()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { s.unsubscribe(subscription) })
+	}, nil
+}
+
+// unsubscribe drops an order update handler; the stream stays open for later subscriptions
+func (s *userDataStream) unsubscribe(handler *orderUpdateHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := make([]*orderUpdateHandler, 0, len(s.handlers))
+	for _, h := range s.handlers {
+		if h != handler {
+			kept = append(kept, h)
+		}
+	}
+	s.handlers = kept
+}
+
+// Connected reports whether the stream is connected
+func (s *userDataStream) Connected() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conn != nil
+}
+
+// Close closes the connection and its listen key and stops reconnecting
+func (s *userDataStream) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+	})
+
+	s.mu.Lock()
+	conn, listenKey := s.conn, s.listenKey
+	s.listenKey = ""
+	s.mu.Unlock()
+
+	if listenKey != "" {
+		s.client.CloseListenKey(listenKey)
+	}
+	if conn != nil {
+		return conn.Close()
+	}
+	return nil
+}
+
+// run connects and reads until the stream is closed, backing off between attempts. The backoff
+// is reset once a connection has delivered frames.
+func (s *userDataStream) run() {
+	failures := 0
+	for {
+		if conn, err := s.connect(); err == nil && s.readLoop(conn) {
+			failures = 0
+		}
+
+		select {
+		case <-s.closed:
+			return
+		case <-time.After(s.reconnectDelay(failures)):
+		}
+		failures++
+	}
+}
+
+// reconnectDelay returns the wait before the next attempt after a number of consecutive
+// failures: the initial delay doubled per failure, up to the maximum
+func (s *userDataStream) reconnectDelay(failures int) time.Duration {
+	delay := s.reconnectInitial
+	for i := 0; i < failures && delay < s.reconnectMax; i++ {
+		delay *= 2
+	}
+	return min(delay, s.reconnectMax)
+}
+
+// connect gets a listen key and dials its stream
+func (s *userDataStream) connect() (*wsConn, error) {
+	listenKey, err := s.client.CreateListenKey()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), streamDialTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.closed:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	conn, err := dialWebSocket(ctx, s.url+"/"+listenKey)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.closed:
+		conn.Close()
+		return nil, fmt.Errorf("user data stream is closed")
+	default:
+	}
+	s.conn, s.listenKey = conn, listenKey
+	return conn, nil
+}
+
+// readLoop dispatches frames until the connection fails or its listen key expires, keeping the
+// key alive meanwhile; it reports whether any frame arrived
+func (s *userDataStream) readLoop(conn *wsConn) bool {
+	done := make(chan struct{})
+	defer func() {
+		close(done)
+		s.disconnect(conn)
+	}()
+	go s.keepListenKeyAlive(conn, done)
+
+	received := false
+	for {
+		conn.SetReadDeadline(time.Now().Add(userStreamReadTimeout))
+		data, err := conn.ReadMessage()
+		if err != nil {
+			return received
+		}
+		received = true
+		if !s.dispatch(data) {
+			return received
+		}
+	}
+}
+
+// keepListenKeyAlive extends the listen key of a connection until done is closed. A key the
+// exchange no longer knows drops the connection, so the stream reconnects with a new one.
+func (s *userDataStream) keepListenKeyAlive(conn *wsConn, done chan struct{}) {
+	ticker := time.NewTicker(s.keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+		listenKey := s.listenKey
+		s.mu.Unlock()
+		if err := s.client.KeepAliveListenKey(listenKey); binanceErrorCodeOf(err) == errCodeListenKeyNotExist {
+			conn.Close()
+			return
+		}
+	}
+}
+
+// disconnect forgets and closes a connection
+func (s *userDataStream) disconnect(conn *wsConn) {
+	s.mu.Lock()
+	if s.conn == conn {
+		s.conn = nil
+	}
+	s.mu.Unlock()
+	conn.Close()
+}
+
+// dispatch passes the order of an execution report to the handlers; other account events are
+// ignored. It returns false when the frame announces that the listen key expired.
+func (s *userDataStream) dispatch(data []byte) bool {
+	var event struct {
+		EventType string `json:"e"`
+		EventTime int64  `json:"E"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return true
+	}
+
+	switch event.EventType {
+	case "listenKeyExpired":
+		return false
+	case "executionReport":
+		order, err := ParseExecutionReport(data)
+		if err != nil {
+			return true
+		}
+		s.mu.Lock()
+		handlers := s.handlers
+		s.mu.Unlock()
+		for _, handler := range handlers {
+			handler.handle(order)
+		}
+	}
+	return true
+}
@@ -0,0 +1,252 @@
+package api
+
+import (
+	"binance-trader/pkg/errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// executionReportFrame returns an executionReport frame for order 4293153 of BTCUSDT
+func executionReportFrame(execution, status, executedQty, quoteQty string) string {
+	return `{"e":"executionReport","E":1499405658658,"s":"BTCUSDT","c":"mUvoqJxFIILMdfAW5iGSOW","S":"BUY",` +
+		`"o":"LIMIT","f":"GTC","q":"1.00000000","p":"0.10264410","P":"0.00000000","F":"0.00000000","g":-1,` +
+		`"C":"","x":"` + execution + `","X":"` + status + `","r":"NONE","i":4293153,"l":"0.40000000",` +
+		`"z":"` + executedQty + `","L":"0.10264410","n":"0","N":null,"T":1499405658657,"t":-1,"I":8641984,` +
+		`"w":true,"m":false,"M":false,"O":1499405658657,"Z":"` + quoteQty + `","Y":"0.00000000","Q":"0.00000000"}`
+}
+
+func TestParseExecutionReport(t *testing.T) {
+	tests := []struct {
+		name        string
+		frame       string
+		status      OrderStatus
+		executedQty float64
+		quoteQty    float64
+	}{
+		{"new", executionReportFrame("NEW", "NEW", "0.00000000", "0.00000000"), OrderStatusNew, 0, 0},
+		{"partial fill", executionReportFrame("TRADE", "PARTIALLY_FILLED", "0.40000000", "0.04105764"), OrderStatusPartiallyFilled, 0.4, 0.04105764},
+		{"fill", executionReportFrame("TRADE", "FILLED", "1.00000000", "0.10264410"), OrderStatusFilled, 1, 0.1026441},
+		{"canceled", executionReportFrame("CANCELED", "CANCELED", "0.40000000", "0.04105764"), OrderStatusCanceled, 0.4, 0.04105764},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := ParseExecutionReport([]byte(tt.frame))
+			if err != nil {
+				t.Fatalf("ParseExecutionReport() error = %v", err)
+			}
+			if order.OrderID != 4293153 || order.Symbol != "BTCUSDT" || order.Side != OrderSideBuy || order.Type != OrderTypeLimit ||
+				order.Price != 0.1026441 || order.OrigQty != 1 || order.ClientOrderID != "mUvoqJxFIILMdfAW5iGSOW" {
+				t.Errorf("ParseExecutionReport() = %+v", order)
+			}
+			if order.Status != tt.status || order.ExecutedQty != tt.executedQty || order.CummulativeQuoteQty != tt.quoteQty {
+				t.Errorf("status = %s, executed %v for %v; want %s, %v for %v",
+					order.Status, order.ExecutedQty, order.CummulativeQuoteQty, tt.status, tt.executedQty, tt.quoteQty)
+			}
+			// "T" and "O" are not confused with "t" and "o"
+			if order.UpdateTime != 1499405658657 || order.Time != 1499405658657 || order.OrderListID != -1 {
+				t.Errorf("times = %d / %d, order list %d", order.Time, order.UpdateTime, order.OrderListID)
+			}
+		})
+	}
+
+	// A cancellation names the order's own client ID in "C"
+	cancelled := strings.Replace(executionReportFrame("CANCELED", "CANCELED", "0", "0"), `"C":""`, `"C":"original-id"`, 1)
+	if order, err := ParseExecutionReport([]byte(cancelled)); err != nil || order.ClientOrderID != "original-id" {
+		t.Errorf("ParseExecutionReport() of a cancellation = %+v, %v; want client ID original-id", order, err)
+	}
+
+	for name, frame := range map[string]string{
+		"account update": `{"e":"outboundAccountPosition","E":1564034571105,"u":1564034571073,"B":[]}`,
+		"bad quantity":   strings.Replace(executionReportFrame("NEW", "NEW", "0", "0"), `"q":"1.00000000"`, `"q":"x"`, 1),
+		"not JSON":       `executionReport`,
+	} {
+		if _, err := ParseExecutionReport([]byte(frame)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// fakeListenKeyClient hands out numbered listen keys and can reject keepalives
+type fakeListenKeyClient struct {
+	mu           sync.Mutex
+	created      int
+	keepAlives   []string
+	closed       []string
+	keepAliveErr error
+}
+
+func (c *fakeListenKeyClient) CreateListenKey() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.created++
+	return fmt.Sprintf("key%d", c.created), nil
+}
+
+func (c *fakeListenKeyClient) KeepAliveListenKey(listenKey string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keepAlives = append(c.keepAlives, listenKey)
+	return c.keepAliveErr
+}
+
+func (c *fakeListenKeyClient) CloseListenKey(listenKey string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = append(c.closed, listenKey)
+	return nil
+}
+
+func TestUserDataStream(t *testing.T) {
+	server := newFakeStreamServer(t)
+	keys := &fakeListenKeyClient{}
+	stream, err := NewUserDataStream(keys, UserStreamOptions{URL: server.URL(), ReconnectInitial: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewUserDataStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	orders := make(chan *Order, 16)
+	if _, err := stream.SubscribeOrderUpdates(func(order *Order) { orders <- order }); err != nil {
+		t.Fatalf("SubscribeOrderUpdates() error = %v", err)
+	}
+	expectStatus := func(want OrderStatus, executedQty float64) {
+		t.Helper()
+		select {
+		case order := <-orders:
+			if order.Status != want || order.ExecutedQty != executedQty {
+				t.Errorf("handler got %s with %v executed, want %s with %v", order.Status, order.ExecutedQty, want, executedQty)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no %s update within 5s", want)
+		}
+	}
+
+	// The first subscription connects to the stream of a new listen key
+	peer := server.accept()
+	if peer.path != "/ws/key1" {
+		t.Errorf("connected to %s, want /ws/key1", peer.path)
+	}
+	peer.send(wsOpText, true, `{"e":"outboundAccountPosition","E":1564034571105,"u":1564034571073,"B":[]}`)
+	peer.send(wsOpText, true, executionReportFrame("TRADE", "PARTIALLY_FILLED", "0.40000000", "0.04105764"))
+	expectStatus(OrderStatusPartiallyFilled, 0.4)
+	if !stream.Connected() {
+		t.Error("Connected() = false while frames arrive")
+	}
+
+	// An expired listen key is replaced and the stream reconnects on its own
+	peer.send(wsOpText, true, `{"e":"listenKeyExpired","E":1576653824250,"listenKey":"key1"}`)
+	peer = server.accept()
+	if peer.path != "/ws/key2" {
+		t.Errorf("reconnected to %s, want /ws/key2", peer.path)
+	}
+	peer.send(wsOpText, true, executionReportFrame("TRADE", "FILLED", "1.00000000", "0.10264410"))
+	expectStatus(OrderStatusFilled, 1)
+
+	// So does a dropped connection
+	peer.conn.Close()
+	peer = server.accept()
+	peer.send(wsOpText, true, executionReportFrame("CANCELED", "CANCELED", "0.00000000", "0.00000000"))
+	expectStatus(OrderStatusCanceled, 0)
+
+	// Once closed the stream closes its listen key and neither reconnects nor accepts subscriptions
+	stream.Close()
+	if _, err := stream.SubscribeOrderUpdates(func(*Order) {}); err == nil {
+		t.Error("SubscribeOrderUpdates() after Close expected an error")
+	}
+	select {
+	case <-server.conns:
+		t.Error("stream reconnected after Close")
+	case <-time.After(100 * time.Millisecond):
+	}
+	keys.mu.Lock()
+	defer keys.mu.Unlock()
+	if len(keys.closed) != 1 || keys.closed[0] != "key3" {
+		t.Errorf("closed listen keys = %v, want [key3]", keys.closed)
+	}
+}
+
+func TestUserDataStreamKeepAlive(t *testing.T) {
+	server := newFakeStreamServer(t)
+	keys := &fakeListenKeyClient{}
+	stream, err := NewUserDataStream(keys, UserStreamOptions{URL: server.URL(), ReconnectInitial: 10 * time.Millisecond,
+		KeepAliveInterval: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewUserDataStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	if _, err := stream.SubscribeOrderUpdates(func(*Order) {}); err != nil {
+		t.Fatalf("SubscribeOrderUpdates() error = %v", err)
+	}
+	server.accept()
+
+	// The key is kept alive while the connection lasts
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		keys.mu.Lock()
+		kept := len(keys.keepAlives)
+		keys.mu.Unlock()
+		if kept >= 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("listen key not kept alive within 5s")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// A keepalive of a key the exchange no longer knows (-1125) reconnects with a new key
+	keys.mu.Lock()
+	keys.keepAliveErr = errors.NewTradingError(errors.ErrInvalidParameter, "HTTP client error: 400", http.StatusBadRequest,
+		newResponseBodyError([]byte(`{"code":-1125,"msg":"This listenKey does not exist."}`)))
+	keys.mu.Unlock()
+	if peer := server.accept(); peer.path != "/ws/key2" {
+		t.Errorf("reconnected to %s, want /ws/key2", peer.path)
+	}
+}
+
+func TestListenKeyRequests(t *testing.T) {
+	var requests []string
+	var apiKey string
+	mockClient := &mockHTTPClient{
+		doWithRetryFunc: func(method, url string, params map[string]interface{}, headers map[string]string) ([]byte, error) {
+			requests = append(requests, method+" "+url)
+			apiKey = headers["X-MBX-APIKEY"]
+			return []byte(`{"listenKey":"pqia91ma19a5s61cv6a81va65sdf19v8a65a1a5s61cv6a81va65sdf19v8a65a1"}`), nil
+		},
+	}
+	authMgr, _ := NewAuthManager("test_key", "test_secret")
+	client, _ := NewBinanceClient("https://api.binance.com", mockClient, authMgr)
+
+	listenKey, err := client.CreateListenKey()
+	if err != nil || listenKey != "pqia91ma19a5s61cv6a81va65sdf19v8a65a1a5s61cv6a81va65sdf19v8a65a1" {
+		t.Fatalf("CreateListenKey() = %q, %v", listenKey, err)
+	}
+	if err := client.KeepAliveListenKey(listenKey); err != nil {
+		t.Errorf("KeepAliveListenKey() error = %v", err)
+	}
+	if err := client.CloseListenKey(listenKey); err != nil {
+		t.Errorf("CloseListenKey() error = %v", err)
+	}
+
+	want := []string{
+		"POST https://api.binance.com/api/v3/userDataStream",
+		"PUT https://api.binance.com/api/v3/userDataStream?listenKey=" + listenKey,
+		"DELETE https://api.binance.com/api/v3/userDataStream?listenKey=" + listenKey,
+	}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests =\n%s\nwant\n%s", strings.Join(requests, "\n"), strings.Join(want, "\n"))
+	}
+	// Listen key requests carry the API key but no signature
+	if apiKey != "test_key" || strings.Contains(strings.Join(requests, ""), "signature=") {
+		t.Errorf("API key = %q, requests %v", apiKey, requests)
+	}
+
+	if err := client.KeepAliveListenKey(""); err == nil {
+		t.Error("expected an error for an empty listen key")
+	}
+}
@@ -0,0 +1,288 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebSocket opcodes (RFC 6455 section 5.2)
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// wsAcceptGUID is appended to the handshake key to compute Sec-WebSocket-Accept
+const wsAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// wsMaxMessageSize bounds a message; market stream frames are a few hundred bytes
+const wsMaxMessageSize = 1 << 20
+
+// wsConn is a minimal RFC 6455 client connection covering what the Binance market streams
+// use: text messages, fragmentation, ping/pong and close. It has a single reader; writes
+// are serialized.
+type wsConn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	writeMu sync.Mutex
+}
+
+// dialWebSocket opens a WebSocket connection to a ws:// or wss:// URL
+func dialWebSocket(ctx context.Context, rawURL string) (*wsConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stream URL: %w", err)
+	}
+
+	port := u.Port()
+	switch u.Scheme {
+	case "wss":
+		if port == "" {
+			port = "443"
+		}
+	case "ws":
+		if port == "" {
+			port = "80"
+		}
+	default:
+		return nil, fmt.Errorf("stream URL must use ws or wss, got: %s", rawURL)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "wss" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	// The opening handshake is bounded by the context deadline
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	ws, err := handshakeWebSocket(conn, u)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return ws, nil
+}
+
+// handshakeWebSocket sends the opening handshake and checks the server accepted the upgrade
+func handshakeWebSocket(conn net.Conn, u *url.URL) (*wsConn, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	request := "GET " + u.RequestURI() + " HTTP/1.1\r\n" +
+		"Host: " + u.Host + "\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\n" +
+		"Sec-WebSocket-Version: 13\r\n\r\n"
+	if _, err := io.WriteString(conn, request); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read handshake response: %w", err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("stream handshake failed: %s", response.Status)
+	}
+	if !strings.EqualFold(response.Header.Get("Upgrade"), "websocket") ||
+		response.Header.Get("Sec-WebSocket-Accept") != wsAcceptKey(key) {
+		return nil, fmt.Errorf("stream handshake failed: invalid upgrade response")
+	}
+
+	return &wsConn{conn: conn, reader: reader}, nil
+}
+
+// wsAcceptKey computes the Sec-WebSocket-Accept value expected for a handshake key
+func wsAcceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + wsAcceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// ReadMessage returns the next text or binary message, answering pings on the way
+func (c *wsConn) ReadMessage() ([]byte, error) {
+	var message []byte
+	fragmented := false
+
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return nil, err
+			}
+		case wsOpPong:
+		case wsOpClose:
+			c.writeFrame(wsOpClose, payload[:min(len(payload), 2)])
+			return nil, fmt.Errorf("stream closed by server%s", wsCloseReason(payload))
+		case wsOpText, wsOpBinary:
+			if fragmented {
+				return nil, fmt.Errorf("stream protocol error: new message inside a fragmented one")
+			}
+			if fin {
+				return payload, nil
+			}
+			message = payload
+			fragmented = true
+		case wsOpContinuation:
+			if !fragmented {
+				return nil, fmt.Errorf("stream protocol error: unexpected continuation frame")
+			}
+			message = append(message, payload...)
+			if len(message) > wsMaxMessageSize {
+				return nil, fmt.Errorf("stream message exceeds %d bytes", wsMaxMessageSize)
+			}
+			if fin {
+				return message, nil
+			}
+		default:
+			return nil, fmt.Errorf("stream protocol error: unknown opcode %d", opcode)
+		}
+	}
+}
+
+// WriteText sends a text message
+func (c *wsConn) WriteText(data []byte) error {
+	return c.writeFrame(wsOpText, data)
+}
+
+// SetReadDeadline bounds the next ReadMessage
+func (c *wsConn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// Close sends a normal closure and closes the connection
+func (c *wsConn) Close() error {
+	c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	c.writeFrame(wsOpClose, []byte{0x03, 0xE8}) // 1000: normal closure
+	return c.conn.Close()
+}
+
+// readFrame reads one frame; servers do not mask frames, but masked ones are accepted
+func (c *wsConn) readFrame() (bool, byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin := header[0]&0x80 != 0
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	if length > wsMaxMessageSize {
+		return false, 0, nil, fmt.Errorf("stream frame exceeds %d bytes", wsMaxMessageSize)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+// writeFrame writes one final frame; client frames are always masked
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return err
+	}
+
+	frame := make([]byte, 0, 14+len(payload))
+	frame = append(frame, 0x80|opcode)
+	switch {
+	case len(payload) < 126:
+		frame = append(frame, 0x80|byte(len(payload)))
+	case len(payload) <= 0xFFFF:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(len(payload)))
+	}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// wsCloseReason describes the status code and reason of a close frame
+func wsCloseReason(payload []byte) string {
+	if len(payload) < 2 {
+		return ""
+	}
+	reason := fmt.Sprintf(" (%d", binary.BigEndian.Uint16(payload))
+	if len(payload) > 2 {
+		reason += ": " + string(payload[2:])
+	}
+	return reason + ")"
+}
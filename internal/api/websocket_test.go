@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeStreamServer is a WebSocket server for tests; every accepted connection is handed to the test
+type fakeStreamServer struct {
+	*httptest.Server
+	t     *testing.T
+	conns chan *fakeStreamConn
+}
+
+// fakeStreamConn is the server side of a test connection
+type fakeStreamConn struct {
+	t    *testing.T
+	conn net.Conn
+	ws   *wsConn
+	path string // Request path of the handshake
+}
+
+func newFakeStreamServer(t *testing.T) *fakeStreamServer {
+	t.Helper()
+	s := &fakeStreamServer{t: t, conns: make(chan *fakeStreamConn, 8)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Sec-WebSocket-Key")
+		if r.Header.Get("Upgrade") != "websocket" || key == "" {
+			http.Error(w, "not a websocket handshake", http.StatusBadRequest)
+			return
+		}
+
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijack() error = %v", err)
+			return
+		}
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + wsAcceptKey(key) + "\r\n\r\n")
+		rw.Flush()
+		s.conns <- &fakeStreamConn{t: t, conn: conn, ws: &wsConn{conn: conn, reader: rw.Reader}, path: r.URL.Path}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// URL returns the ws:// address of the server
+func (s *fakeStreamServer) URL() string {
+	return "ws://" + strings.TrimPrefix(s.Server.URL, "http://") + "/ws"
+}
+
+// accept waits for the next client connection
+func (s *fakeStreamServer) accept() *fakeStreamConn {
+	select {
+	case conn := <-s.conns:
+		return conn
+	case <-time.After(5 * time.Second):
+		s.t.Fatal("no stream connection within 5s")
+		return nil
+	}
+}
+
+// send writes an unmasked frame, as servers do
+func (c *fakeStreamConn) send(opcode byte, fin bool, payload string) {
+	header := []byte{opcode, byte(len(payload))}
+	if fin {
+		header[0] |= 0x80
+	}
+	if len(payload) >= 126 {
+		header = binary.BigEndian.AppendUint16([]byte{header[0], 126}, uint16(len(payload)))
+	}
+	if _, err := c.conn.Write(append(header, payload...)); err != nil {
+		c.t.Errorf("failed to send frame: %v", err)
+	}
+}
+
+// read returns the next frame from the client
+func (c *fakeStreamConn) read() (byte, string) {
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, opcode, payload, err := c.ws.readFrame()
+	if err != nil {
+		c.t.Fatalf("failed to read client frame: %v", err)
+	}
+	return opcode, string(payload)
+}
+
+func TestWebSocketMessages(t *testing.T) {
+	server := newFakeStreamServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client, err := dialWebSocket(ctx, server.URL())
+	if err != nil {
+		t.Fatalf("dialWebSocket() error = %v", err)
+	}
+	defer client.Close()
+	peer := server.accept()
+
+	// Client frames are masked; the server side unmasks them
+	if err := client.WriteText([]byte(`{"method":"SUBSCRIBE"}`)); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	if opcode, payload := peer.read(); opcode != wsOpText || payload != `{"method":"SUBSCRIBE"}` {
+		t.Errorf("server read %d %q", opcode, payload)
+	}
+
+	// A ping between fragments is answered and the message reassembled
+	long := strings.Repeat("x", 200)
+	peer.send(wsOpText, false, `{"part":"`)
+	peer.send(wsOpPing, true, "keepalive")
+	peer.send(wsOpContinuation, true, long+`"}`)
+
+	message, err := client.ReadMessage()
+	if err != nil || string(message) != `{"part":"`+long+`"}` {
+		t.Fatalf("ReadMessage() = %q, %v", message, err)
+	}
+	if opcode, payload := peer.read(); opcode != wsOpPong || payload != "keepalive" {
+		t.Errorf("ping answered with %d %q, want a pong echoing the payload", opcode, payload)
+	}
+
+	// A close frame ends the connection with its reason
+	peer.send(wsOpClose, true, "\x03\xe9going away")
+	if _, err := client.ReadMessage(); err == nil || !strings.Contains(err.Error(), "1001: going away") {
+		t.Errorf("ReadMessage() after close = %v", err)
+	}
+}
+
+func TestDialWebSocketRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	urls := map[string]string{
+		"refused upgrade": "ws://" + strings.TrimPrefix(server.URL, "http://") + "/ws",
+		"http scheme":     server.URL,
+	}
+	for name, url := range urls {
+		if _, err := dialWebSocket(ctx, url); err == nil {
+			t.Errorf("%s: dialWebSocket() expected an error", name)
+		}
+	}
+}
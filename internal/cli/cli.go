@@ -5,24 +5,49 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"binance-trader/internal/api"
+	"binance-trader/internal/config"
 	"binance-trader/internal/repository"
 	"binance-trader/internal/service"
 	"binance-trader/pkg/logger"
+	"binance-trader/pkg/timeutil"
 )
 
+// defaultOrderBookLimit is how many levels per side the orderbook command shows without a limit
+const defaultOrderBookLimit = 10
+
 // CLI represents the command-line interface
 type CLI struct {
 	tradingService          service.TradingService
 	marketService           service.MarketDataService
 	conditionalOrderService service.ConditionalOrderService
 	stopLossService         service.StopLossService
+	automationService       service.AutomationService
+	maintenanceMonitor      service.MaintenanceMonitor
+	symbolGuard             service.SymbolFailureGuard
+	rateLimitProvider       api.RateLimitStatusProvider
+	coverageChecker         service.ProtectionCoverageChecker
+	dustConverter           service.DustConverter
+	symbolStatus            service.SymbolStatusMonitor
+	profitGuard             *service.TakeProfitGuard
+	holdings                service.HoldingProvider
+	twapExecutor            *service.TWAPExecutor
+	bracketExecutor         *service.BracketExecutor
+	exchangeInfo            service.ExchangeInfoCache
+	paperAccount            service.PaperAccount
+	portfolio               service.PortfolioService
+	riskMgr                 service.RiskManager
+	dryRun                  bool
+	display                 *displayFormat
 	logger                  logger.Logger
 	reader                  io.Reader
 	writer                  io.Writer
+	commands                *commandTable
 }
 
 // NewCLI creates a new CLI instance
@@ -33,15 +58,124 @@ func NewCLI(
 	stopLossService service.StopLossService,
 	logger logger.Logger,
 ) *CLI {
-	return &CLI{
+	c := &CLI{
 		tradingService:          tradingService,
 		marketService:           marketService,
 		conditionalOrderService: conditionalOrderService,
 		stopLossService:         stopLossService,
+		profitGuard:             service.NewTakeProfitGuard(nil, service.DefaultSpotFeeRate),
+		twapExecutor:            service.NewTWAPExecutor(tradingService, logger),
+		bracketExecutor:         service.NewBracketExecutor(tradingService, stopLossService, logger),
+		display:                 newDisplayFormat(nil),
 		logger:                  logger,
 		reader:                  os.Stdin,
 		writer:                  os.Stdout,
 	}
+	c.commands = newCommandTable(c.commandSpecs())
+	return c
+}
+
+// SetDisplayConfig sets how prices, quantities and amounts are displayed
+func (c *CLI) SetDisplayConfig(cfg *config.CLIConfig) {
+	c.display = newDisplayFormat(cfg)
+}
+
+// SetProfitGuardConfig sets the fee rate and minimum profit take-profit targets are checked against
+func (c *CLI) SetProfitGuardConfig(cfg *config.ProfitGuardConfig) {
+	c.profitGuard = service.NewTakeProfitGuard(cfg, service.DefaultSpotFeeRate)
+}
+
+// SetHoldingProvider sets the optional holdings lookup used for percentage quantities in
+// conditional order templates
+func (c *CLI) SetHoldingProvider(holdings service.HoldingProvider) {
+	c.holdings = holdings
+}
+
+// SetTWAPExecutor sets the executor running twap orders, which records their child orders
+func (c *CLI) SetTWAPExecutor(executor *service.TWAPExecutor) {
+	c.twapExecutor = executor
+}
+
+// SetBracketExecutor sets the executor placing bracket orders with an entry order
+func (c *CLI) SetBracketExecutor(executor *service.BracketExecutor) {
+	c.bracketExecutor = executor
+}
+
+// SetExchangeInfoCache sets the optional symbol filter cache used by the filters command
+func (c *CLI) SetExchangeInfoCache(cache service.ExchangeInfoCache) {
+	c.exchangeInfo = cache
+}
+
+// SetPaperAccount sets the optional virtual account the balance command reports in paper trading
+func (c *CLI) SetPaperAccount(account service.PaperAccount) {
+	c.paperAccount = account
+}
+
+// SetPortfolioService sets the optional service valuing the account for the portfolio command
+func (c *CLI) SetPortfolioService(portfolio service.PortfolioService) {
+	c.portfolio = portfolio
+}
+
+// SetRiskManager sets the optional risk manager whose daily order count the dailyorders command shows
+func (c *CLI) SetRiskManager(riskMgr service.RiskManager) {
+	c.riskMgr = riskMgr
+}
+
+// SetDryRun marks the orders placed from this CLI as simulated, which the welcome message announces
+func (c *CLI) SetDryRun(active bool) {
+	c.dryRun = active
+}
+
+// SetAutomationService sets the optional automation service used by the automation command
+func (c *CLI) SetAutomationService(automationService service.AutomationService) {
+	c.automationService = automationService
+}
+
+// SetMaintenanceMonitor sets the optional maintenance monitor used to pause order placement
+func (c *CLI) SetMaintenanceMonitor(monitor service.MaintenanceMonitor) {
+	c.maintenanceMonitor = monitor
+}
+
+// SetSymbolGuard sets the optional symbol failure guard used by the paused command
+func (c *CLI) SetSymbolGuard(guard service.SymbolFailureGuard) {
+	c.symbolGuard = guard
+}
+
+// SetRateLimitStatusProvider sets the optional provider used by the ratelimit command
+func (c *CLI) SetRateLimitStatusProvider(provider api.RateLimitStatusProvider) {
+	c.rateLimitProvider = provider
+}
+
+// SetCoverageChecker sets the optional protection coverage checker used by the coverage command
+func (c *CLI) SetCoverageChecker(checker service.ProtectionCoverageChecker) {
+	c.coverageChecker = checker
+}
+
+// SetDustConverter sets the optional dust converter used by the dust command
+func (c *CLI) SetDustConverter(converter service.DustConverter) {
+	c.dustConverter = converter
+}
+
+// SetSymbolStatusMonitor sets the optional symbol status monitor whose suspended symbols the
+// status command lists
+func (c *CLI) SetSymbolStatusMonitor(monitor service.SymbolStatusMonitor) {
+	c.symbolStatus = monitor
+}
+
+// checkMaintenance returns an error if the exchange is in maintenance
+func (c *CLI) checkMaintenance() error {
+	if c.maintenanceMonitor != nil && c.maintenanceMonitor.IsInMaintenance() {
+		return fmt.Errorf("exchange is under maintenance, order placement is paused")
+	}
+	return nil
+}
+
+// recordMaintenanceResult reports the result of a manual order to the maintenance monitor, so
+// server errors met from the CLI count towards detecting maintenance as well
+func (c *CLI) recordMaintenanceResult(err error) {
+	if c.maintenanceMonitor != nil {
+		c.maintenanceMonitor.RecordResult(err)
+	}
 }
 
 // Command represents a parsed command
@@ -93,7 +227,7 @@ func (c *CLI) Run() error {
 		}
 
 		if err := c.executeCommand(cmd); err != nil {
-			fmt.Fprintf(c.writer, "Error: %s\n", err.Error())
+			c.commands.reportError(c.writer, cmd, err)
 		}
 	}
 
@@ -106,43 +240,7 @@ func (c *CLI) Run() error {
 
 // executeCommand executes a parsed command
 func (c *CLI) executeCommand(cmd *Command) error {
-	switch cmd.Name {
-	case "help":
-		c.printHelp()
-	case "price":
-		return c.handlePrice(cmd.Args)
-	case "balance":
-		return c.handleBalance(cmd.Args)
-	case "buy":
-		return c.handleBuy(cmd.Args)
-	case "sell":
-		return c.handleSell(cmd.Args)
-	case "cancel":
-		return c.handleCancel(cmd.Args)
-	case "status":
-		return c.handleStatus(cmd.Args)
-	case "orders":
-		return c.handleOrders(cmd.Args)
-	case "history":
-		return c.handleHistory(cmd.Args)
-	case "condorder":
-		return c.handleConditionalOrder(cmd.Args)
-	case "condorders":
-		return c.handleConditionalOrders(cmd.Args)
-	case "cancelcond":
-		return c.handleCancelConditionalOrder(cmd.Args)
-	case "stoploss":
-		return c.handleStopLoss(cmd.Args)
-	case "takeprofit":
-		return c.handleTakeProfit(cmd.Args)
-	case "stoporders":
-		return c.handleStopOrders(cmd.Args)
-	case "cancelstop":
-		return c.handleCancelStopOrder(cmd.Args)
-	default:
-		return fmt.Errorf("unknown command: %s (type 'help' for available commands)", cmd.Name)
-	}
-	return nil
+	return c.commands.execute(cmd)
 }
 
 // printWelcome prints the welcome message
@@ -150,46 +248,491 @@ func (c *CLI) printWelcome() {
 	fmt.Fprintln(c.writer, "===========================================")
 	fmt.Fprintln(c.writer, "  Binance Auto-Trading System")
 	fmt.Fprintln(c.writer, "===========================================")
+	if c.dryRun {
+		printDryRunBanner(c.writer)
+	}
 	fmt.Fprintln(c.writer, "Type 'help' for available commands")
 }
 
-// printHelp prints the help message
-func (c *CLI) printHelp() {
-	help := `
-Available Commands:
-  help                          - Show this help message
-  price <symbol>                - Get current price for a symbol (e.g., price BTCUSDT)
-  balance <asset>               - Get balance for an asset (e.g., balance USDT)
-  buy <symbol> <quantity>       - Place market buy order (e.g., buy BTCUSDT 0.001)
-  sell <symbol> <price> <qty>   - Place limit sell order (e.g., sell BTCUSDT 50000 0.001)
-  cancel <orderID>              - Cancel an order (e.g., cancel 12345)
-  status <orderID>              - Get order status (e.g., status 12345)
-  orders                        - List all active orders
-  history <symbol> <interval> <limit> - Get historical kline data (e.g., history BTCUSDT 1h 10)
-  
-  Conditional Orders:
-  condorder <symbol> <side> <qty> <trigger_type> <operator> <value>
-                                - Create conditional order (e.g., condorder BTCUSDT BUY 0.001 PRICE >= 50000)
-  condorders                    - List all active conditional orders
-  cancelcond <orderID>          - Cancel a conditional order
-  
-  Stop Loss / Take Profit:
-  stoploss <symbol> <position> <stop_price>
-                                - Set stop loss (e.g., stoploss BTCUSDT 0.001 49000)
-  takeprofit <symbol> <position> <target_price>
-                                - Set take profit (e.g., takeprofit BTCUSDT 0.001 51000)
-  stoporders <symbol>           - List all active stop orders for a symbol
-  cancelstop <orderID>          - Cancel a stop order
-  
-  exit, quit                    - Exit the application
-`
-	fmt.Fprintln(c.writer, help)
+// printDryRunBanner warns that orders are simulated and never reach the exchange
+func printDryRunBanner(w io.Writer) {
+	fmt.Fprintln(w, "*** DRY RUN: orders are simulated and never sent to the exchange ***")
+}
+
+// commandSpecs returns the spot commands in the order they are listed by help
+func (c *CLI) commandSpecs() []*commandSpec {
+	return []*commandSpec{
+		{
+			Name:        "price",
+			Category:    "Market Data",
+			Usage:       "price <symbol>",
+			Description: "Get current price for a symbol",
+			Arguments:   []string{"symbol      Trading pair, e.g. BTCUSDT"},
+			Examples:    []string{"price BTCUSDT", "price ethusdt"},
+			Handler:     c.handlePrice,
+		},
+		{
+			Name:        "balance",
+			Category:    "Market Data",
+			Usage:       "balance [asset]",
+			Description: "List every non-zero balance, or get the balance of one asset; in paper trading, the virtual balance next to the real one with the simulated P&L",
+			Arguments:   []string{"asset       Asset name, e.g. USDT; omit to list all holdings"},
+			Examples:    []string{"balance", "balance USDT"},
+			Handler:     c.handleBalance,
+		},
+		{
+			Name:        "balances",
+			Category:    "Market Data",
+			Usage:       "balances",
+			Description: "List every non-zero balance with free, locked and total amounts, largest total first",
+			Examples:    []string{"balances"},
+			Handler:     c.handleBalances,
+		},
+		{
+			Name:        "portfolio",
+			Category:    "Market Data",
+			Usage:       "portfolio",
+			Description: "Value every holding in USDT at current prices, through BTC for assets without a USDT pair, with the total",
+			Examples:    []string{"portfolio"},
+			Handler:     c.handlePortfolio,
+		},
+		{
+			Name:        "history",
+			Category:    "Market Data",
+			Usage:       "history <symbol> <interval> <limit>",
+			Description: "Get historical kline data",
+			Arguments: []string{
+				"symbol      Trading pair, e.g. BTCUSDT",
+				"interval    Kline interval: 1m, 5m, 15m, 1h, 4h, 1d, ...",
+				"limit       Number of klines to return",
+			},
+			Examples: []string{"history BTCUSDT 1h 10", "history ETHUSDT 1d 30"},
+			Handler:  c.handleHistory,
+		},
+		{
+			Name:        "orderbook",
+			Category:    "Market Data",
+			Usage:       "orderbook <symbol> [limit]",
+			Description: "Show the top order book levels and the spread",
+			Arguments: []string{
+				"symbol      Trading pair, e.g. BTCUSDT",
+				"limit       Levels per side: 5, 10, 20, 50, 100, 500, 1000 or 5000 (default 10)",
+			},
+			Examples: []string{"orderbook BTCUSDT", "orderbook ETHUSDT 20"},
+			Handler:  c.handleOrderBook,
+		},
+		{
+			Name:        "depth",
+			Category:    "Market Data",
+			Usage:       "depth <symbol> [limit]",
+			Description: "Show the top order book levels with cumulative quantities",
+			Arguments: []string{
+				"symbol      Trading pair, e.g. BTCUSDT",
+				"limit       Levels per side: 5, 10, 20, 50, 100, 500, 1000 or 5000 (default 10)",
+			},
+			Examples: []string{"depth BTCUSDT", "depth ETHUSDT 50"},
+			Handler:  c.handleDepth,
+		},
+		{
+			Name:        "buy",
+			Category:    "Trading",
+			Usage:       "buy <symbol> <quantity|--quote <amount>>",
+			Description: "Place market buy order",
+			Arguments: []string{
+				"symbol          Trading pair, e.g. BTCUSDT",
+				"quantity        Base asset quantity to buy",
+				"--quote amount  Quote asset amount to spend instead, e.g. 100 USDT",
+			},
+			Examples: []string{"buy BTCUSDT 0.001", "buy ETHUSDT 0.05", "buy BTCUSDT --quote 100"},
+			Handler:  c.handleBuy,
+		},
+		{
+			Name:        "sell-market",
+			Category:    "Trading",
+			Usage:       "sell-market <symbol> <quantity>",
+			Description: "Place market sell order",
+			Arguments: []string{
+				"symbol      Trading pair, e.g. BTCUSDT",
+				"quantity    Base asset quantity to sell, at most the free balance",
+			},
+			Examples: []string{"sell-market BTCUSDT 0.001", "sell-market ETHUSDT 0.05"},
+			Handler:  c.handleSellMarket,
+		},
+		{
+			Name:        "sell",
+			Category:    "Trading",
+			Usage:       "sell <symbol> <price> <quantity>",
+			Description: "Place limit sell order",
+			Arguments: []string{
+				"symbol      Trading pair, e.g. BTCUSDT",
+				"price       Limit price in the quote asset",
+				"quantity    Base asset quantity to sell",
+			},
+			Examples: []string{"sell BTCUSDT 50000 0.001", "sell ETHUSDT 3500 0.05"},
+			Handler:  c.handleSell,
+		},
+		{
+			Name:        "limitbuy",
+			Category:    "Trading",
+			Usage:       "limitbuy <symbol> <price> <quantity>",
+			Description: "Place limit buy order",
+			Arguments: []string{
+				"symbol      Trading pair, e.g. BTCUSDT",
+				"price       Limit price in the quote asset",
+				"quantity    Base asset quantity to buy",
+			},
+			Examples: []string{"limitbuy BTCUSDT 45000 0.001", "limitbuy ETHUSDT 2800 0.05"},
+			Handler:  c.handleLimitBuy,
+		},
+		{
+			Name:        "twap",
+			Category:    "Trading",
+			Usage:       "twap <symbol> <buy|sell> <qty> <slices> <intervalSec>",
+			Description: "Split a market order into equal slices placed evenly over time",
+			Arguments: []string{
+				"symbol       Trading pair, e.g. BTCUSDT",
+				"side         BUY or SELL",
+				"qty          Total base asset quantity",
+				"slices       Number of child market orders",
+				"intervalSec  Seconds between two child orders",
+			},
+			Examples: []string{"twap BTCUSDT buy 1 12 300", "twap ETHUSDT sell 10 5 360"},
+			Handler:  c.handleTWAP,
+		},
+		{
+			Name:        "cancel",
+			Category:    "Trading",
+			Usage:       "cancel <orderID>",
+			Description: "Cancel an order",
+			Arguments:   []string{"orderID     Exchange order ID"},
+			Examples:    []string{"cancel 12345"},
+			Handler:     c.handleCancel,
+		},
+		{
+			Name:        "cancelall",
+			Category:    "Trading",
+			Usage:       "cancelall <symbol>",
+			Description: "Cancel every open order of a symbol in one request, including both legs of its OCOs",
+			Arguments:   []string{"symbol      Trading pair, e.g. BTCUSDT"},
+			Examples:    []string{"cancelall BTCUSDT"},
+			Handler:     c.handleCancelAll,
+		},
+		{
+			Name:        "oco",
+			Category:    "Trading",
+			Usage:       "oco <symbol> <quantity> <stopPrice> <limitPrice> [stopLimitPrice]",
+			Description: "Protect a position with a take-profit and a stop-loss that cancel each other",
+			Arguments: []string{
+				"symbol          Trading pair, e.g. BTCUSDT",
+				"quantity        Base asset quantity to sell",
+				"stopPrice       Stop-loss trigger below the market",
+				"limitPrice      Take-profit limit price above the market",
+				"stopLimitPrice  Limit price of the stop leg (optional; omitted sells at market)",
+			},
+			Examples: []string{"oco BTCUSDT 0.01 48000 55000", "oco ETHUSDT 0.5 2800 3600 2790"},
+			Handler:  c.handleOCO,
+		},
+		{
+			Name:        "cancel-oco",
+			Category:    "Trading",
+			Usage:       "cancel-oco <symbol> <orderListID>",
+			Description: "Cancel both legs of an OCO order list",
+			Arguments: []string{
+				"symbol        Trading pair, e.g. BTCUSDT",
+				"orderListID   Exchange order list ID",
+			},
+			Examples: []string{"cancel-oco BTCUSDT 7"},
+			Handler:  c.handleCancelOCO,
+		},
+		{
+			Name:        "status",
+			Category:    "Trading",
+			Usage:       "status [orderID]",
+			Description: "Get order status, or system status if omitted",
+			Arguments:   []string{"orderID     Exchange order ID (optional)"},
+			Examples:    []string{"status", "status 12345"},
+			Handler:     c.handleStatus,
+		},
+		{
+			Name:        "orders",
+			Category:    "Trading",
+			Usage:       "orders",
+			Description: "List all active orders",
+			Examples:    []string{"orders"},
+			Handler:     c.handleOrders,
+		},
+		{
+			Name:        "trace",
+			Category:    "Trading",
+			Usage:       "trace <symbol> <orderID>",
+			Description: "Show an order's timeline: creation, fills with fees, final status",
+			Arguments: []string{
+				"symbol      Trading pair, e.g. BTCUSDT",
+				"orderID     Exchange order ID",
+			},
+			Examples: []string{"trace BTCUSDT 12345"},
+			Handler:  c.handleTrace,
+		},
+		{
+			Name:        "condorder",
+			Category:    "Conditional Orders",
+			Usage:       "condorder <symbol> <side> <quantity> <trigger_type> <operator> <value> [limit <price|offset>] [ttl <seconds|duration>] [window <ms|duration>] [--allow-duplicate] [--idempotency-key <key>]",
+			Description: "Create a market or limit order that is placed when its trigger fires",
+			Arguments: []string{
+				"symbol        Trading pair, e.g. BTCUSDT",
+				"side          BUY or SELL",
+				"quantity      Base asset quantity",
+				"trigger_type  PRICE (last price), PRICE_CHANGE (percent change), VOLUME (traded volume) or",
+				"              ASK_BID_IMBALANCE ((bid qty - ask qty) / (bid qty + ask qty) of the top 20 levels, -1 to 1) or",
+				"              MA_CROSS (fast simple moving average crossing the slow one) or",
+				"              VOLUME_SURGE (last minute's volume as a multiple of the average minute of a window) or",
+				"              TIME (current time, to place the order at a given moment)",
+				"operator      >=, <=, >, < (or GE, LE, GT, LT); for MA_CROSS > crosses above, < crosses below",
+				"value         Trigger threshold in the unit of the trigger type; for VOLUME <threshold>[@window],",
+				"              base asset volume over the window (default 24h); for MA_CROSS <fast>/<slow>[@interval],",
+				"              periods in klines of the interval (default 1h); for VOLUME_SURGE the multiplier;",
+				"              for TIME Unix seconds or milliseconds or an RFC3339 time (1700000000 or 2024-05-01T08:00:00Z)",
+				"limit         Place a limit order instead of a market order: at a fixed price, or at a signed",
+				"              offset from the trigger price, absolute (-50) or in percent (+0.2%)",
+				"ttl           Cancel the order as expired if it has not triggered within this many seconds",
+				"              or this duration (14400 or 4h); it stays in condhistory",
+				"window        VOLUME_SURGE baseline window in milliseconds or as a duration (3600000 or 1h,",
+				"              default 1h)",
+				"--allow-duplicate        Create even if an active order has the same symbol, side, quantity and trigger",
+				"--idempotency-key <key>  Retrying with the same key returns the order created first instead of a new one",
+				"export <file.yaml>                                   Save active conditional orders as a template",
+				"import <file.yaml> [--symbol <symbol>] [--dry-run] [--allow-duplicate]",
+				"                                                     Create the orders of a template, optionally for another symbol",
+			},
+			Examples: []string{
+				"condorder BTCUSDT BUY 0.001 PRICE >= 50000",
+				"condorder ETHUSDT SELL 0.05 PRICE_CHANGE <= -5",
+				"condorder BTCUSDT BUY 0.001 VOLUME GT 20000",
+				"condorder BTCUSDT BUY 0.001 VOLUME > 500@15m",
+				"condorder BTCUSDT BUY 0.001 ASK_BID_IMBALANCE >= 0.3",
+				"condorder BTCUSDT BUY 0.001 MA_CROSS > 9/21@15m",
+				"condorder BTCUSDT BUY 0.001 VOLUME_SURGE >= 2.5 WINDOW 3600000",
+				"condorder BTCUSDT BUY 0.1 TIME >= 1700000000",
+				"condorder BTCUSDT SELL 0.001 PRICE >= 50000 limit +0.2%",
+				"condorder BTCUSDT BUY 0.001 PRICE <= 48000 limit 47950",
+				"condorder BTCUSDT BUY 0.001 PRICE >= 50000 TTL 14400",
+				"condorder BTCUSDT BUY 0.001 PRICE <= 48000 --idempotency-key dip-2024-05-01",
+				"condorder export orders.yaml",
+				"condorder import orders.yaml --symbol ETHUSDT --dry-run",
+			},
+			Handler: c.handleConditionalOrder,
+		},
+		{
+			Name:        "condorders",
+			Category:    "Conditional Orders",
+			Usage:       "condorders",
+			Description: "List all active conditional orders",
+			Examples:    []string{"condorders"},
+			Handler:     c.handleConditionalOrders,
+		},
+		{
+			Name:        "cancelcond",
+			Category:    "Conditional Orders",
+			Usage:       "cancelcond <orderID|all> [symbol]",
+			Description: "Cancel a conditional order, or all pending ones",
+			Arguments: []string{
+				"orderID     Conditional order ID shown by condorders, or all",
+				"symbol      With all, only cancel orders of this trading pair; with an ID, refuse if the order is for another pair",
+			},
+			Examples: []string{"cancelcond 3f2c9a1e-8b4d-4c8e-9f1a-2b7d6e5c4a10 BTCUSDT", "cancelcond all", "cancelcond all BTCUSDT"},
+			Handler:  c.handleCancelConditionalOrder,
+		},
+		{
+			Name:        "condhistory",
+			Category:    "Conditional Orders",
+			Usage:       "condhistory [hours | <from> [to]]",
+			Description: "List executed and cancelled conditional orders with cancel reasons",
+			Arguments: []string{
+				"hours       Look back this many hours (default: 24)",
+				"from        Start of the range, RFC3339 (2024-05-01T00:00:00Z) or Unix milliseconds",
+				"to          End of the range in the same form (default: now)",
+			},
+			Examples: []string{"condhistory", "condhistory 72", "condhistory 2024-05-01T00:00:00Z 2024-05-02T00:00:00+08:00"},
+			Handler:  c.handleConditionalOrderHistory,
+		},
+		{
+			Name:        "stoploss",
+			Category:    "Stop Loss / Take Profit",
+			Usage:       "stoploss <symbol> <position> <stop_price>",
+			Description: "Set stop loss",
+			Arguments: []string{
+				"symbol      Trading pair, e.g. BTCUSDT",
+				"position    Quantity to sell when the stop triggers",
+				"stop_price  Price at or below which the position is sold",
+			},
+			Examples: []string{"stoploss BTCUSDT 0.001 49000"},
+			Handler:  c.handleStopLoss,
+		},
+		{
+			Name:        "takeprofit",
+			Category:    "Stop Loss / Take Profit",
+			Usage:       "takeprofit <symbol> <position> <target_price> [--entry <price>] [--force]",
+			Description: "Set take profit, refusing targets that lose money after fees",
+			Arguments: []string{
+				"symbol        Trading pair, e.g. BTCUSDT",
+				"position      Quantity to sell when the target is reached",
+				"target_price  Price at or above which the position is sold",
+				"--entry       Price the position was bought at (default: current price)",
+				"--force       Create the take profit even if it loses money after fees",
+			},
+			Examples: []string{"takeprofit BTCUSDT 0.001 51000", "takeprofit BTCUSDT 0.001 50100 --entry 50000 --force"},
+			Handler:  c.handleTakeProfit,
+		},
+		{
+			Name:        "bracket",
+			Category:    "Stop Loss / Take Profit",
+			Usage:       "bracket <symbol> [BUY <quantity> <entry_price> | <position>] <stop_price> <target_price> [--entry <price>] [--force]",
+			Description: "Set a stop loss and take profit pair, optionally after a limit buy entry; the first leg to trigger cancels the other",
+			Arguments: []string{
+				"symbol        Trading pair, e.g. BTCUSDT",
+				"BUY           Place a limit buy of quantity at entry_price first and set the pair once it fills;",
+				"              an entry not filled within 5 minutes is cancelled and no pair is set",
+				"position      Quantity already held to sell when either leg triggers",
+				"stop_price    Price at or below which the position is sold",
+				"target_price  Price at or above which the position is sold",
+				"--entry       Price the held position was bought at (default: current price)",
+				"--force       Create the pair even if the target loses money after fees",
+			},
+			Examples: []string{"bracket BTCUSDT 0.001 49000 51000", "bracket BTCUSDT BUY 0.001 50000 49000 52000"},
+			Handler:  c.handleBracket,
+		},
+		{
+			Name:        "trailingstop",
+			Category:    "Stop Loss / Take Profit",
+			Usage:       "trailingstop <symbol> <position> <trail_percent|--atr <period>x<multiplier>>",
+			Description: "Set a trailing stop with a fixed or volatility-based trail",
+			Arguments: []string{
+				"symbol         Trading pair, e.g. BTCUSDT",
+				"position       Quantity to sell when the stop triggers",
+				"trail_percent  Fixed distance of the stop below the highest price, e.g. 2 or 2%",
+				"--atr PxM      Trail of M times the ATR of the last P klines, recomputed on each candle close",
+				"               within stop_loss.min_trail_percent and max_trail_percent",
+			},
+			Examples: []string{"trailingstop BTCUSDT 0.001 2", "trailingstop SOLUSDT 5 --atr 14x2.5"},
+			Handler:  c.handleTrailingStop,
+		},
+		{
+			Name:        "stoporders",
+			Category:    "Stop Loss / Take Profit",
+			Usage:       "stoporders <symbol>",
+			Description: "List all active stop orders for a symbol",
+			Arguments:   []string{"symbol      Trading pair, e.g. BTCUSDT"},
+			Examples:    []string{"stoporders BTCUSDT"},
+			Handler:     c.handleStopOrders,
+		},
+		{
+			Name:        "cancelstop",
+			Category:    "Stop Loss / Take Profit",
+			Usage:       "cancelstop <orderID> [symbol]",
+			Description: "Cancel a stop order",
+			Arguments: []string{
+				"orderID     Stop order ID shown by stoporders",
+				"symbol      Refuse to cancel if the order is for another trading pair",
+			},
+			Examples: []string{"cancelstop SL_1700000000000000000_1", "cancelstop SL_1700000000000000000_1 BTCUSDT"},
+			Handler:  c.handleCancelStopOrder,
+		},
+		{
+			Name:        "coverage",
+			Category:    "Stop Loss / Take Profit",
+			Usage:       "coverage",
+			Description: "Check that tracked holdings are covered by stop orders",
+			Examples:    []string{"coverage"},
+			Handler: func(args []string) error {
+				return handleCoverage(c.writer, c.display, c.coverageChecker)
+			},
+		},
+		{
+			Name:        "automation",
+			Category:    "Automation",
+			Usage:       "automation <status|dca|grid|stop> [args...]",
+			Description: "Manage DCA and grid plans",
+			Arguments: []string{
+				"status                                       Show all running DCA and grid plans",
+				"dca <symbol> <quantity> <interval>           Buy quantity every interval (e.g. 24h)",
+				"grid <symbol> <lower> <upper> <grids> <qty>  Trade qty per grid level between lower and upper",
+				"stop <planID>                                Stop a DCA or grid plan",
+			},
+			Examples: []string{
+				"automation dca BTCUSDT 0.001 24h",
+				"automation grid BTCUSDT 45000 55000 10 0.001",
+				"automation stop 6a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d",
+			},
+			Handler: c.handleAutomation,
+		},
+		{
+			Name:        "dust",
+			Category:    "Trading",
+			Usage:       "dust [assets...]",
+			Description: "Convert small balances to BNB; without assets, converts all dust below the configured threshold",
+			Arguments:   []string{"assets      Assets to convert regardless of the threshold, e.g. SHIB DOGE"},
+			Examples:    []string{"dust", "dust SHIB DOGE"},
+			Handler:     c.handleDust,
+		},
+		{
+			Name:        "filters",
+			Category:    "System",
+			Usage:       "filters <symbol> | filters refresh",
+			Description: "Show the order filters of a symbol from exchange info, or reload them now",
+			Arguments: []string{
+				"symbol      Symbol whose step size, tick size and minimum notional are shown",
+				"refresh     Reload exchange info before its cache expires",
+			},
+			Examples: []string{"filters BTCUSDT", "filters refresh"},
+			Handler:  c.handleFilters,
+		},
+		{
+			Name:        "paused",
+			Category:    "System",
+			Usage:       "paused [clear <symbol>]",
+			Description: "List symbols paused after repeated order failures, or lift a pause",
+			Arguments:   []string{"clear <symbol>  Lift the pause for a symbol"},
+			Examples:    []string{"paused", "paused clear BTCUSDT"},
+			Handler: func(args []string) error {
+				return handleSymbolPauses(c.writer, c.display, c.symbolGuard, args)
+			},
+		},
+		{
+			Name:        "dailyorders",
+			Category:    "System",
+			Usage:       "dailyorders",
+			Description: "Show the orders placed today (UTC) against risk.max_daily_orders and when the count resets",
+			Examples:    []string{"dailyorders"},
+			Handler:     c.handleDailyOrders,
+		},
+		{
+			Name:        "ratelimit",
+			Category:    "System",
+			Usage:       "ratelimit",
+			Description: "Show exchange rate-limit usage (request weight and order counts)",
+			Examples:    []string{"ratelimit"},
+			Handler: func(args []string) error {
+				return handleRateLimitStatus(c.writer, c.display, c.rateLimitProvider)
+			},
+		},
+		{
+			Name:        "help",
+			Category:    "System",
+			Usage:       "help [command]",
+			Description: "Show all commands, or syntax and examples of one command",
+			Arguments:   []string{"command     Command to describe"},
+			Examples:    []string{"help", "help condorder"},
+			Handler: func(args []string) error {
+				return c.commands.help(c.writer, args)
+			},
+		},
+	}
 }
 
 // handlePrice handles the price command
 func (c *CLI) handlePrice(args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("usage: price <symbol>")
+		return fmt.Errorf("%w: price <symbol>", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
@@ -204,28 +747,183 @@ func (c *CLI) handlePrice(args []string) error {
 
 // handleBalance handles the balance command
 func (c *CLI) handleBalance(args []string) error {
-	if len(args) < 1 {
-		return fmt.Errorf("usage: balance <asset>")
+	if len(args) == 0 {
+		return c.showAllBalances()
+	}
+
+	asset := strings.ToUpper(args[0])
+	if c.paperAccount == nil {
+		balances, err := c.tradingService.GetAllBalances()
+		if err != nil {
+			return fmt.Errorf("failed to get balances: %w", err)
+		}
+		// Assets the account does not hold are not listed
+		balance := api.Balance{Asset: asset}
+		for _, held := range balances {
+			if held.Asset == asset {
+				balance = held
+				break
+			}
+		}
+
+		fmt.Fprintln(c.writer, "-------------------------------------------")
+		fmt.Fprintf(c.writer, "Balance of %s:\n", asset)
+		fmt.Fprintln(c.writer, "-------------------------------------------")
+		fmt.Fprintf(c.writer, "Free:   %s\n", c.display.fmtQty("", balance.Free))
+		fmt.Fprintf(c.writer, "Locked: %s\n", c.display.fmtQty("", balance.Locked))
+		fmt.Fprintf(c.writer, "Total:  %s\n", c.display.fmtQty("", balance.Free+balance.Locked))
+		return nil
+	}
+
+	balance, err := c.paperAccount.GetBalance(asset)
+	if err != nil {
+		return fmt.Errorf("failed to get paper balance: %w", err)
+	}
+	exchange, err := c.paperAccount.GetExchangeBalance(asset)
+	if err != nil {
+		return fmt.Errorf("failed to get exchange balance: %w", err)
+	}
+	summary, err := c.paperAccount.GetPaperSummary()
+	if err != nil {
+		return fmt.Errorf("failed to value paper account: %w", err)
+	}
+
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintf(c.writer, "Balance of %s (PAPER TRADING):\n", asset)
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintf(c.writer, "Paper:        %s free, %s locked\n", c.display.fmtQty("", balance.Free), c.display.fmtQty("", balance.Locked))
+	fmt.Fprintf(c.writer, "Exchange:     %s free, %s locked\n", c.display.fmtQty("", exchange.Free), c.display.fmtQty("", exchange.Locked))
+	fmt.Fprintf(c.writer, "Paper Equity: %s %s (started with %s)\n", c.display.fmtMoney(summary.Equity), service.DefaultPaperBalanceAsset, c.display.fmtMoney(summary.StartingBalance))
+	// A gain is shown with its sign like a loss
+	pnl, pnlPercent := c.display.fmtMoney(summary.PnL), c.display.fmtPercent(summary.PnLPercent, 2)
+	if summary.PnL > 0 {
+		pnl, pnlPercent = "+"+pnl, "+"+pnlPercent
+	}
+	fmt.Fprintf(c.writer, "Paper P&L:    %s %s (%s)\n", pnl, service.DefaultPaperBalanceAsset, pnlPercent)
+	return nil
+}
+
+// showAllBalances prints a table of every non-zero balance; in paper trading, the virtual ones
+func (c *CLI) showAllBalances() error {
+	balances, err := c.tradingService.GetAllBalances()
+	if err != nil {
+		return fmt.Errorf("failed to get balances: %w", err)
+	}
+	if len(balances) == 0 {
+		fmt.Fprintln(c.writer, "No balances")
+		return nil
+	}
+
+	title := "Balances:"
+	if c.paperAccount != nil {
+		title = "Balances (PAPER TRADING):"
+	}
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintln(c.writer, title)
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	// Largest holdings first; the amounts are in different assets, so this is by quantity
+	sort.SliceStable(balances, func(i, j int) bool {
+		return balances[i].Free+balances[i].Locked > balances[j].Free+balances[j].Locked
+	})
+	fmt.Fprintf(c.writer, "%-10s %16s %16s %16s\n", "Asset", "Free", "Locked", "Total")
+	for _, balance := range balances {
+		fmt.Fprintf(c.writer, "%-10s %16s %16s %16s\n", balance.Asset, c.display.fmtQty("", balance.Free),
+			c.display.fmtQty("", balance.Locked), c.display.fmtQty("", balance.Free+balance.Locked))
+	}
+	return nil
+}
+
+// handleBalances handles the balances command
+func (c *CLI) handleBalances(args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("%w: balances", ErrUsage)
+	}
+	return c.showAllBalances()
+}
+
+// handlePortfolio handles the portfolio command
+func (c *CLI) handlePortfolio(args []string) error {
+	if c.portfolio == nil {
+		return fmt.Errorf("portfolio is not available")
+	}
+
+	portfolio, err := c.portfolio.GetPortfolio()
+	if err != nil {
+		return err
+	}
+	if len(portfolio.Holdings) == 0 {
+		fmt.Fprintln(c.writer, "No balances")
+		return nil
 	}
 
-	// This would require access to the API client
-	// For now, return a message
-	return fmt.Errorf("balance command requires direct API access (not yet implemented in CLI)")
+	title := "Portfolio:"
+	if c.paperAccount != nil {
+		title = "Portfolio (PAPER TRADING):"
+	}
+	quote := service.PortfolioQuoteAsset
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintln(c.writer, title)
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintf(c.writer, "%-10s %16s %16s %16s %16s\n", "Asset", "Free", "Locked", "Price ("+quote+")", "Value ("+quote+")")
+	for _, holding := range portfolio.Holdings {
+		price, value := "unpriced", "-"
+		if holding.Priced {
+			price = c.display.fmtPrice(holding.Asset+quote, holding.Price)
+			value = c.display.fmtMoney(holding.Value)
+		}
+		fmt.Fprintf(c.writer, "%-10s %16s %16s %16s %16s\n", holding.Asset,
+			c.display.fmtQty("", holding.Free), c.display.fmtQty("", holding.Locked), price, value)
+	}
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintf(c.writer, "Total: %s %s", c.display.fmtMoney(portfolio.TotalValue), quote)
+	if portfolio.Unpriced > 0 {
+		fmt.Fprintf(c.writer, " (%d unpriced, not included)", portfolio.Unpriced)
+	}
+	fmt.Fprintln(c.writer)
+	return nil
 }
 
 // handleBuy handles the buy command
 func (c *CLI) handleBuy(args []string) error {
 	if len(args) < 2 {
-		return fmt.Errorf("usage: buy <symbol> <quantity>")
+		return fmt.Errorf("%w: buy <symbol> <quantity|--quote <amount>>", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
-	quantity, err := strconv.ParseFloat(args[1], 64)
+	if args[1] == "--quote" {
+		if len(args) < 3 {
+			return fmt.Errorf("%w: buy <symbol> --quote <amount>", ErrUsage)
+		}
+		quoteAmount, err := parseAmount("quote amount", args[2])
+		if err != nil {
+			return err
+		}
+
+		if err := c.checkMaintenance(); err != nil {
+			return err
+		}
+
+		order, err := c.tradingService.PlaceMarketBuyOrderByQuote(symbol, quoteAmount)
+		c.recordMaintenanceResult(err)
+		if err != nil {
+			return fmt.Errorf("failed to place buy order: %w", err)
+		}
+
+		c.formatOrder(order)
+		return nil
+	}
+
+	quantity, err := parseAmount("quantity", args[1])
 	if err != nil {
-		return fmt.Errorf("invalid quantity: %w", err)
+		return err
+	}
+
+	if err := c.checkMaintenance(); err != nil {
+		return err
 	}
 
 	order, err := c.tradingService.PlaceMarketBuyOrder(symbol, quantity)
+	c.recordMaintenanceResult(err)
 	if err != nil {
 		return fmt.Errorf("failed to place buy order: %w", err)
 	}
@@ -234,24 +932,55 @@ func (c *CLI) handleBuy(args []string) error {
 	return nil
 }
 
+// handleSellMarket handles the sell-market command
+func (c *CLI) handleSellMarket(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("%w: sell-market <symbol> <quantity>", ErrUsage)
+	}
+
+	symbol := strings.ToUpper(args[0])
+	quantity, err := parseAmount("quantity", args[1])
+	if err != nil {
+		return err
+	}
+
+	if err := c.checkMaintenance(); err != nil {
+		return err
+	}
+
+	order, err := c.tradingService.PlaceMarketSellOrder(symbol, quantity)
+	c.recordMaintenanceResult(err)
+	if err != nil {
+		return fmt.Errorf("failed to place sell order: %w", err)
+	}
+
+	c.formatOrder(order)
+	return nil
+}
+
 // handleSell handles the sell command
 func (c *CLI) handleSell(args []string) error {
 	if len(args) < 3 {
-		return fmt.Errorf("usage: sell <symbol> <price> <quantity>")
+		return fmt.Errorf("%w: sell <symbol> <price> <quantity>", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
-	price, err := strconv.ParseFloat(args[1], 64)
+	price, err := parseAmount("price", args[1])
 	if err != nil {
-		return fmt.Errorf("invalid price: %w", err)
+		return err
 	}
 
-	quantity, err := strconv.ParseFloat(args[2], 64)
+	quantity, err := parseAmount("quantity", args[2])
 	if err != nil {
-		return fmt.Errorf("invalid quantity: %w", err)
+		return err
+	}
+
+	if err := c.checkMaintenance(); err != nil {
+		return err
 	}
 
 	order, err := c.tradingService.PlaceLimitSellOrder(symbol, price, quantity)
+	c.recordMaintenanceResult(err)
 	if err != nil {
 		return fmt.Errorf("failed to place sell order: %w", err)
 	}
@@ -260,10 +989,89 @@ func (c *CLI) handleSell(args []string) error {
 	return nil
 }
 
+// handleLimitBuy handles the limitbuy command
+func (c *CLI) handleLimitBuy(args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("%w: limitbuy <symbol> <price> <quantity>", ErrUsage)
+	}
+
+	symbol := strings.ToUpper(args[0])
+	price, err := parseAmount("price", args[1])
+	if err != nil {
+		return err
+	}
+
+	quantity, err := parseAmount("quantity", args[2])
+	if err != nil {
+		return err
+	}
+
+	if err := c.checkMaintenance(); err != nil {
+		return err
+	}
+
+	order, err := c.tradingService.PlaceLimitBuyOrder(symbol, price, quantity)
+	c.recordMaintenanceResult(err)
+	if err != nil {
+		return fmt.Errorf("failed to place limit buy order: %w", err)
+	}
+
+	c.formatOrder(order)
+	return nil
+}
+
+// handleTWAP handles the twap command; it returns once the last slice is placed
+func (c *CLI) handleTWAP(args []string) error {
+	if len(args) < 5 {
+		return fmt.Errorf("%w: twap <symbol> <buy|sell> <qty> <slices> <intervalSec>", ErrUsage)
+	}
+
+	symbol := strings.ToUpper(args[0])
+	var side api.OrderSide
+	switch strings.ToUpper(args[1]) {
+	case "BUY":
+		side = api.OrderSideBuy
+	case "SELL":
+		side = api.OrderSideSell
+	default:
+		return fmt.Errorf("invalid side: must be BUY or SELL")
+	}
+
+	quantity, err := parseAmount("quantity", args[2])
+	if err != nil {
+		return err
+	}
+	slices, err := parseCount("slices", args[3])
+	if err != nil {
+		return err
+	}
+	seconds, err := parseNumber("interval", args[4], numberFormat{AllowZero: true})
+	if err != nil {
+		return err
+	}
+
+	if err := c.checkMaintenance(); err != nil {
+		return err
+	}
+
+	interval := time.Duration(seconds * float64(time.Second))
+	fmt.Fprintf(c.writer, "Placing %d slices of %s %s every %s...\n",
+		slices, c.display.fmtQty(symbol, quantity/float64(slices)), symbol, interval)
+
+	execution, err := c.twapExecutor.ExecuteTWAP(symbol, side, quantity, slices, interval)
+	if execution != nil {
+		c.formatTWAPExecution(execution)
+	}
+	if err != nil {
+		return fmt.Errorf("TWAP order incomplete: %w", err)
+	}
+	return nil
+}
+
 // handleCancel handles the cancel command
 func (c *CLI) handleCancel(args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("usage: cancel <orderID>")
+		return fmt.Errorf("%w: cancel <orderID>", ErrUsage)
 	}
 
 	orderID, err := strconv.ParseInt(args[0], 10, 64)
@@ -279,10 +1087,101 @@ func (c *CLI) handleCancel(args []string) error {
 	return nil
 }
 
+// handleCancelAll handles the cancelall command
+func (c *CLI) handleCancelAll(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: cancelall <symbol>", ErrUsage)
+	}
+
+	symbol := strings.ToUpper(args[0])
+	cancelled, err := c.tradingService.CancelAllOrders(symbol)
+	if err != nil {
+		// A partial failure reports how many orders were cancelled
+		return fmt.Errorf("failed to cancel all orders: %w", err)
+	}
+
+	fmt.Fprintf(c.writer, "Cancelled %d order(s) of %s\n", cancelled, symbol)
+	return nil
+}
+
+// handleOCO handles the oco command
+func (c *CLI) handleOCO(args []string) error {
+	if len(args) < 4 {
+		return fmt.Errorf("%w: oco <symbol> <quantity> <stopPrice> <limitPrice> [stopLimitPrice]", ErrUsage)
+	}
+
+	symbol := strings.ToUpper(args[0])
+	quantity, err := parseAmount("quantity", args[1])
+	if err != nil {
+		return err
+	}
+	stopPrice, err := parseAmount("stop price", args[2])
+	if err != nil {
+		return err
+	}
+	limitPrice, err := parseAmount("limit price", args[3])
+	if err != nil {
+		return err
+	}
+	var stopLimitPrice float64
+	if len(args) > 4 {
+		if stopLimitPrice, err = parseAmount("stop limit price", args[4]); err != nil {
+			return err
+		}
+	}
+
+	if err := c.checkMaintenance(); err != nil {
+		return err
+	}
+
+	oco, err := c.tradingService.PlaceOCOOrder(symbol, api.OrderSideSell, quantity, limitPrice, stopPrice, stopLimitPrice)
+	c.recordMaintenanceResult(err)
+	if err != nil {
+		return fmt.Errorf("failed to place OCO order: %w", err)
+	}
+
+	stopLeg := "market"
+	if stopLimitPrice > 0 {
+		stopLeg = "limit " + c.display.fmtPrice(symbol, stopLimitPrice)
+	}
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintln(c.writer, "OCO Order Created Successfully")
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintf(c.writer, "Order List ID:  %d\n", oco.OrderListID)
+	fmt.Fprintf(c.writer, "Symbol:         %s\n", symbol)
+	fmt.Fprintf(c.writer, "Quantity:       %s\n", c.display.fmtQty(symbol, quantity))
+	fmt.Fprintf(c.writer, "Take Profit:    #%d at %s\n", oco.LimitOrderID, c.display.fmtPrice(symbol, limitPrice))
+	fmt.Fprintf(c.writer, "Stop Loss:      #%d at %s, then %s\n", oco.StopOrderID, c.display.fmtPrice(symbol, stopPrice), stopLeg)
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintln(c.writer, "A fill of either leg cancels the other on the exchange")
+	return nil
+}
+
+// handleCancelOCO handles the cancel-oco command
+func (c *CLI) handleCancelOCO(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("%w: cancel-oco <symbol> <orderListID>", ErrUsage)
+	}
+
+	symbol := strings.ToUpper(args[0])
+	orderListID, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil || orderListID <= 0 {
+		return fmt.Errorf("invalid order list ID: %s", args[1])
+	}
+
+	if err := c.tradingService.CancelOCOOrder(symbol, orderListID); err != nil {
+		return fmt.Errorf("failed to cancel order list: %w", err)
+	}
+
+	fmt.Fprintf(c.writer, "Order list %d canceled successfully (both legs)\n", orderListID)
+	return nil
+}
+
 // handleStatus handles the status command
 func (c *CLI) handleStatus(args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("usage: status <orderID>")
+		formatSystemStatus(c.writer, c.display, c.maintenanceMonitor, c.symbolStatus)
+		return nil
 	}
 
 	orderID, err := strconv.ParseInt(args[0], 10, 64)
@@ -299,6 +1198,62 @@ func (c *CLI) handleStatus(args []string) error {
 	return nil
 }
 
+// handleTrace handles the trace command
+func (c *CLI) handleTrace(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("%w: trace <symbol> <orderID>", ErrUsage)
+	}
+
+	symbol := strings.ToUpper(args[0])
+	orderID, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	lifecycle, err := c.tradingService.GetOrderLifecycle(symbol, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to trace order: %w", err)
+	}
+
+	c.formatOrderLifecycle(lifecycle)
+	return nil
+}
+
+// formatSystemStatus formats and displays the exchange status and the symbols whose
+// automation is suspended, shared by the spot and futures CLIs
+func formatSystemStatus(w io.Writer, display *displayFormat, maintenance service.MaintenanceMonitor, symbolStatus service.SymbolStatusMonitor) {
+	fmt.Fprintln(w, "-------------------------------------------")
+	fmt.Fprintln(w, "System Status")
+	fmt.Fprintln(w, "-------------------------------------------")
+	if maintenance == nil {
+		fmt.Fprintln(w, "Exchange:       UNKNOWN")
+	} else if state := maintenance.GetState(); state.InMaintenance {
+		fmt.Fprintln(w, "Exchange:       MAINTENANCE (trading paused)")
+		fmt.Fprintf(w, "Since:          %s\n", display.fmtTime(timeutil.SecondsToMillis(state.Since)))
+		if state.Message != "" {
+			fmt.Fprintf(w, "Message:        %s\n", state.Message)
+		}
+	} else {
+		fmt.Fprintln(w, "Exchange:       NORMAL")
+	}
+	if symbolStatus != nil {
+		suspensions := symbolStatus.GetSuspendedSymbols()
+		if len(suspensions) == 0 {
+			fmt.Fprintln(w, "Suspended:      none")
+		} else {
+			fmt.Fprintf(w, "Suspended:      %d symbols not trading, automation paused\n", len(suspensions))
+			for _, suspension := range suspensions {
+				fmt.Fprintf(w, "  %-12s %-10s %d orders", suspension.Symbol, suspension.Status, suspension.Orders)
+				if suspension.Cancelled > 0 {
+					fmt.Fprintf(w, ", %d open orders cancelled", suspension.Cancelled)
+				}
+				fmt.Fprintf(w, " since %s\n", display.fmtTime(suspension.SuspendedAt))
+			}
+		}
+	}
+	fmt.Fprintln(w, "-------------------------------------------")
+}
+
 // handleOrders handles the orders command
 func (c *CLI) handleOrders(args []string) error {
 	orders, err := c.tradingService.GetActiveOrders()
@@ -313,14 +1268,14 @@ func (c *CLI) handleOrders(args []string) error {
 // handleHistory handles the history command
 func (c *CLI) handleHistory(args []string) error {
 	if len(args) < 3 {
-		return fmt.Errorf("usage: history <symbol> <interval> <limit>")
+		return fmt.Errorf("%w: history <symbol> <interval> <limit>", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
 	interval := args[1]
-	limit, err := strconv.Atoi(args[2])
+	limit, err := parseCount("limit", args[2])
 	if err != nil {
-		return fmt.Errorf("invalid limit: %w", err)
+		return err
 	}
 
 	klines, err := c.marketService.GetHistoricalData(symbol, interval, limit)
@@ -332,31 +1287,146 @@ func (c *CLI) handleHistory(args []string) error {
 	return nil
 }
 
-// formatPrice formats and displays price information
-func (c *CLI) formatPrice(symbol string, price float64) {
-	fmt.Fprintln(c.writer, "-------------------------------------------")
-	fmt.Fprintf(c.writer, "Symbol: %s\n", symbol)
-	fmt.Fprintf(c.writer, "Price:  %.8f\n", price)
-	fmt.Fprintln(c.writer, "-------------------------------------------")
+// handleOrderBook handles the orderbook command
+func (c *CLI) handleOrderBook(args []string) error {
+	book, limit, err := c.fetchOrderBook("orderbook", args)
+	if err != nil {
+		return err
+	}
+
+	c.formatOrderBook(book, limit)
+	return nil
 }
 
-// formatOrder formats and displays order information
-func (c *CLI) formatOrder(order *api.Order) {
-	fmt.Fprintln(c.writer, "-------------------------------------------")
-	fmt.Fprintln(c.writer, "Order Created Successfully")
-	fmt.Fprintln(c.writer, "-------------------------------------------")
-	fmt.Fprintf(c.writer, "Order ID:       %d\n", order.OrderID)
-	fmt.Fprintf(c.writer, "Symbol:         %s\n", order.Symbol)
-	fmt.Fprintf(c.writer, "Side:           %s\n", order.Side)
-	fmt.Fprintf(c.writer, "Type:           %s\n", order.Type)
+// handleDepth handles the depth command
+func (c *CLI) handleDepth(args []string) error {
+	book, limit, err := c.fetchOrderBook("depth", args)
+	if err != nil {
+		return err
+	}
+
+	c.formatDepth(book, limit)
+	return nil
+}
+
+// fetchOrderBook parses <symbol> [limit] and fetches the order book for the named command
+func (c *CLI) fetchOrderBook(command string, args []string) (*api.OrderBook, int, error) {
+	if len(args) < 1 {
+		return nil, 0, fmt.Errorf("%w: %s <symbol> [limit]", ErrUsage, command)
+	}
+
+	symbol := strings.ToUpper(args[0])
+	limit := defaultOrderBookLimit
+	if len(args) > 1 {
+		var err error
+		if limit, err = parseCount("limit", args[1]); err != nil {
+			return nil, 0, err
+		}
+	}
+	if err := api.ValidateOrderBookLimit(limit); err != nil {
+		return nil, 0, err
+	}
+
+	book, err := c.marketService.GetOrderBook(symbol, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get order book: %w", err)
+	}
+	return book, limit, nil
+}
+
+// formatPrice formats and displays price information
+func (c *CLI) formatPrice(symbol string, price float64) {
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintf(c.writer, "Symbol: %s\n", symbol)
+	fmt.Fprintf(c.writer, "Price:  %s\n", c.display.fmtPrice(symbol, price))
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+}
+
+// formatOrder formats and displays order information
+func (c *CLI) formatOrder(order *api.Order) {
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintln(c.writer, "Order Created Successfully")
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintf(c.writer, "Order ID:       %d\n", order.OrderID)
+	fmt.Fprintf(c.writer, "Symbol:         %s\n", order.Symbol)
+	fmt.Fprintf(c.writer, "Side:           %s\n", order.Side)
+	fmt.Fprintf(c.writer, "Type:           %s\n", order.Type)
 	fmt.Fprintf(c.writer, "Status:         %s\n", order.Status)
-	fmt.Fprintf(c.writer, "Price:          %.8f\n", order.Price)
-	fmt.Fprintf(c.writer, "Quantity:       %.8f\n", order.OrigQty)
-	fmt.Fprintf(c.writer, "Executed Qty:   %.8f\n", order.ExecutedQty)
-	fmt.Fprintf(c.writer, "Quote Qty:      %.8f\n", order.CummulativeQuoteQty)
+	fmt.Fprintf(c.writer, "Price:          %s\n", c.display.fmtPrice(order.Symbol, order.Price))
+	fmt.Fprintf(c.writer, "Quantity:       %s\n", c.display.fmtQty(order.Symbol, order.OrigQty))
+	fmt.Fprintf(c.writer, "Executed Qty:   %s\n", c.display.fmtQty(order.Symbol, order.ExecutedQty))
+	fmt.Fprintf(c.writer, "Quote Qty:      %s\n", c.display.fmtMoney(order.CummulativeQuoteQty))
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+}
+
+// formatTWAPExecution formats and displays the child orders and average fill of a TWAP order
+func (c *CLI) formatTWAPExecution(execution *service.TWAPExecution) {
+	symbol := execution.Symbol
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintf(c.writer, "TWAP %s %s: %d of %d slices\n", execution.Side, symbol, len(execution.Slices), execution.SliceCount)
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	for i, slice := range execution.Slices {
+		fmt.Fprintf(c.writer, "  %2d. Order %d  %s @ %s  %s\n",
+			i+1, slice.OrderID, c.display.fmtQty(symbol, slice.ExecutedQty), c.display.fmtPrice(symbol, slice.Price), slice.Status)
+	}
+	fmt.Fprintf(c.writer, "Executed Qty:   %s of %s\n", c.display.fmtQty(symbol, execution.ExecutedQty), c.display.fmtQty(symbol, execution.TotalQuantity))
+	if execution.ExecutedQty > 0 {
+		fmt.Fprintf(c.writer, "Avg Price:      %s\n", c.display.fmtPrice(symbol, execution.AveragePrice))
+		fmt.Fprintf(c.writer, "Quote Qty:      %s\n", c.display.fmtMoney(execution.QuoteQty))
+	}
 	fmt.Fprintln(c.writer, "-------------------------------------------")
 }
 
+// formatOrderLifecycle formats and displays an order timeline
+func (c *CLI) formatOrderLifecycle(lifecycle *service.OrderLifecycle) {
+	order := lifecycle.Exchange
+	fmt.Fprintln(c.writer, "===========================================")
+	fmt.Fprintf(c.writer, "Order Trace: %s #%d\n", lifecycle.Symbol, lifecycle.OrderID)
+	fmt.Fprintln(c.writer, "===========================================")
+	fmt.Fprintf(c.writer, "Side/Type:      %s %s\n", order.Side, order.Type)
+	fmt.Fprintf(c.writer, "Status:         %s\n", order.Status)
+	symbol := lifecycle.Symbol
+	fmt.Fprintf(c.writer, "Quantity:       %s (filled %s)\n", c.display.fmtQty(symbol, order.OrigQty), c.display.fmtQty(symbol, lifecycle.FilledQty))
+	if lifecycle.FilledQty > 0 {
+		fmt.Fprintf(c.writer, "Avg Price:      %s\n", c.display.fmtPrice(symbol, lifecycle.AvgPrice))
+		fmt.Fprintf(c.writer, "Quote Qty:      %s\n", c.display.fmtMoney(lifecycle.QuoteQty))
+	}
+
+	assets := make([]string, 0, len(lifecycle.Fees))
+	for asset := range lifecycle.Fees {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+	for _, asset := range assets {
+		fmt.Fprintf(c.writer, "Fees:           %s %s\n", c.display.fmtMoney(lifecycle.Fees[asset]), asset)
+	}
+
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintln(c.writer, "Timeline:")
+	for _, event := range lifecycle.Events {
+		timestamp := c.display.fmtTimeMillis(event.Time)
+		switch event.Type {
+		case service.LifecycleEventCreated:
+			fmt.Fprintf(c.writer, "  %s  CREATED  qty %s @ %s\n", timestamp, c.display.fmtQty(symbol, event.Quantity), c.display.fmtPrice(symbol, event.Price))
+		case service.LifecycleEventFill:
+			fmt.Fprintf(c.writer, "  %s  FILL     %s @ %s (total %s, fee %s %s, trade %d) -> %s\n",
+				timestamp, c.display.fmtQty(symbol, event.Quantity), c.display.fmtPrice(symbol, event.Price), c.display.fmtQty(symbol, event.CumulativeQty),
+				c.display.fmtMoney(event.Commission), event.CommissionAsset, event.TradeID, event.Status)
+		case service.LifecycleEventFinal:
+			fmt.Fprintf(c.writer, "  %s  %s\n", timestamp, event.Status)
+		}
+	}
+
+	if len(lifecycle.Discrepancies) > 0 {
+		fmt.Fprintln(c.writer, "-------------------------------------------")
+		fmt.Fprintln(c.writer, "Discrepancies:")
+		for _, discrepancy := range lifecycle.Discrepancies {
+			fmt.Fprintf(c.writer, "  ! %s\n", discrepancy)
+		}
+	}
+	fmt.Fprintln(c.writer, "===========================================")
+}
+
 // formatOrderStatus formats and displays order status information
 func (c *CLI) formatOrderStatus(status *service.OrderStatus) {
 	fmt.Fprintln(c.writer, "-------------------------------------------")
@@ -365,8 +1435,8 @@ func (c *CLI) formatOrderStatus(status *service.OrderStatus) {
 	fmt.Fprintf(c.writer, "Order ID:       %d\n", status.OrderID)
 	fmt.Fprintf(c.writer, "Symbol:         %s\n", status.Symbol)
 	fmt.Fprintf(c.writer, "Status:         %s\n", status.Status)
-	fmt.Fprintf(c.writer, "Executed Qty:   %.8f\n", status.ExecutedQty)
-	fmt.Fprintf(c.writer, "Price:          %.8f\n", status.Price)
+	fmt.Fprintf(c.writer, "Executed Qty:   %s\n", c.display.fmtQty(status.Symbol, status.ExecutedQty))
+	fmt.Fprintf(c.writer, "Price:          %s\n", c.display.fmtPrice(status.Symbol, status.Price))
 	fmt.Fprintln(c.writer, "-------------------------------------------")
 }
 
@@ -381,20 +1451,57 @@ func (c *CLI) formatOrderList(orders []*api.Order) {
 	fmt.Fprintf(c.writer, "Active Orders (%d)\n", len(orders))
 	fmt.Fprintln(c.writer, "===========================================")
 
-	for i, order := range orders {
-		fmt.Fprintf(c.writer, "\n[%d] Order ID: %d\n", i+1, order.OrderID)
-		fmt.Fprintf(c.writer, "    Symbol:       %s\n", order.Symbol)
-		fmt.Fprintf(c.writer, "    Side:         %s\n", order.Side)
-		fmt.Fprintf(c.writer, "    Type:         %s\n", order.Type)
-		fmt.Fprintf(c.writer, "    Status:       %s\n", order.Status)
-		fmt.Fprintf(c.writer, "    Price:        %.8f\n", order.Price)
-		fmt.Fprintf(c.writer, "    Quantity:     %.8f\n", order.OrigQty)
-		fmt.Fprintf(c.writer, "    Executed:     %.8f\n", order.ExecutedQty)
+	// Legs of an OCO list are shown together, since cancelling one leg cancels the list
+	i := 0
+	for _, group := range groupOrderLists(orders) {
+		if group[0].InOrderList() {
+			fmt.Fprintf(c.writer, "\n[OCO] Order List %d (%d legs, cancelling one leg cancels the list)\n", group[0].OrderListID, len(group))
+		}
+		for _, order := range group {
+			i++
+			c.formatActiveOrder(i, order)
+		}
 	}
 
 	fmt.Fprintln(c.writer, "===========================================")
 }
 
+// formatActiveOrder displays one entry of the active order list
+func (c *CLI) formatActiveOrder(i int, order *api.Order) {
+	marker := ""
+	if order.InOrderList() {
+		marker = fmt.Sprintf(" [OCO %d]", order.OrderListID)
+	}
+	fmt.Fprintf(c.writer, "\n[%d] Order ID: %d%s\n", i, order.OrderID, marker)
+	fmt.Fprintf(c.writer, "    Symbol:       %s\n", order.Symbol)
+	fmt.Fprintf(c.writer, "    Side:         %s\n", order.Side)
+	fmt.Fprintf(c.writer, "    Type:         %s\n", order.Type)
+	fmt.Fprintf(c.writer, "    Status:       %s\n", order.Status)
+	fmt.Fprintf(c.writer, "    Price:        %s\n", c.display.fmtPrice(order.Symbol, order.Price))
+	fmt.Fprintf(c.writer, "    Quantity:     %s\n", c.display.fmtQty(order.Symbol, order.OrigQty))
+	fmt.Fprintf(c.writer, "    Executed:     %s\n", c.display.fmtQty(order.Symbol, order.ExecutedQty))
+}
+
+// groupOrderLists groups the legs of each order list together at the position of its first
+// leg; standalone orders form groups of one
+func groupOrderLists(orders []*api.Order) [][]*api.Order {
+	var groups [][]*api.Order
+	listGroup := make(map[int64]int)
+	for _, order := range orders {
+		if !order.InOrderList() {
+			groups = append(groups, []*api.Order{order})
+			continue
+		}
+		if index, exists := listGroup[order.OrderListID]; exists {
+			groups[index] = append(groups[index], order)
+			continue
+		}
+		listGroup[order.OrderListID] = len(groups)
+		groups = append(groups, []*api.Order{order})
+	}
+	return groups
+}
+
 // formatKlines formats and displays kline data
 func (c *CLI) formatKlines(symbol, interval string, klines []*api.Kline) {
 	if len(klines) == 0 {
@@ -405,16 +1512,16 @@ func (c *CLI) formatKlines(symbol, interval string, klines []*api.Kline) {
 	fmt.Fprintln(c.writer, "===========================================")
 	fmt.Fprintf(c.writer, "Historical Data: %s (%s)\n", symbol, interval)
 	fmt.Fprintln(c.writer, "===========================================")
-	fmt.Fprintln(c.writer, "Time                Open        High        Low         Close       Volume")
+	fmt.Fprintln(c.writer, "Time                     Open        High        Low         Close       Volume")
 	fmt.Fprintln(c.writer, "-------------------------------------------")
 
 	for _, kline := range klines {
-		fmt.Fprintf(c.writer, "%-19d %-11.8f %-11.8f %-11.8f %-11.8f %.2f\n",
-			kline.OpenTime,
-			kline.Open,
-			kline.High,
-			kline.Low,
-			kline.Close,
+		fmt.Fprintf(c.writer, "%-24s %-11s %-11s %-11s %-11s %.2f\n",
+			c.display.fmtTime(kline.OpenTime),
+			c.display.fmtPrice(symbol, kline.Open),
+			c.display.fmtPrice(symbol, kline.High),
+			c.display.fmtPrice(symbol, kline.Low),
+			c.display.fmtPrice(symbol, kline.Close),
 			kline.Volume,
 		)
 	}
@@ -422,24 +1529,187 @@ func (c *CLI) formatKlines(symbol, interval string, klines []*api.Kline) {
 	fmt.Fprintln(c.writer, "===========================================")
 }
 
+// formatOrderBook displays the top levels of both sides next to each other, then the spread
+func (c *CLI) formatOrderBook(book *api.OrderBook, limit int) {
+	fmt.Fprintln(c.writer, "===========================================")
+	fmt.Fprintf(c.writer, "Order Book: %s (top %d)\n", book.Symbol, limit)
+	fmt.Fprintln(c.writer, "===========================================")
+	if len(book.Bids) == 0 && len(book.Asks) == 0 {
+		fmt.Fprintln(c.writer, "Order book is empty")
+		fmt.Fprintln(c.writer, "===========================================")
+		return
+	}
+
+	fmt.Fprintf(c.writer, "%-14s %-14s | %-14s %s\n", "Bid Qty", "Bid Price", "Ask Price", "Ask Qty")
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	rows := len(book.Bids)
+	if len(book.Asks) > rows {
+		rows = len(book.Asks)
+	}
+	if rows > limit {
+		rows = limit
+	}
+	for i := 0; i < rows; i++ {
+		bidQty, bidPrice, askPrice, askQty := "", "", "", ""
+		if i < len(book.Bids) {
+			bidQty = c.display.fmtQty(book.Symbol, book.Bids[i].Qty)
+			bidPrice = c.display.fmtPrice(book.Symbol, book.Bids[i].Price)
+		}
+		if i < len(book.Asks) {
+			askPrice = c.display.fmtPrice(book.Symbol, book.Asks[i].Price)
+			askQty = c.display.fmtQty(book.Symbol, book.Asks[i].Qty)
+		}
+		fmt.Fprintf(c.writer, "%-14s %-14s | %-14s %s\n", bidQty, bidPrice, askPrice, askQty)
+	}
+
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	c.formatSpread(book)
+	fmt.Fprintln(c.writer, "===========================================")
+}
+
+// formatSpread displays the best bid, best ask and spread of an order book
+func (c *CLI) formatSpread(book *api.OrderBook) {
+	bestBid, hasBid := book.BestBid()
+	bestAsk, hasAsk := book.BestAsk()
+	if !hasBid || !hasAsk {
+		fmt.Fprintln(c.writer, "Spread:         n/a (one side is empty)")
+		return
+	}
+	bid, ask := bestBid.Price, bestAsk.Price
+	spread := ask - bid
+	fmt.Fprintf(c.writer, "Best Bid:       %s\n", c.display.fmtPrice(book.Symbol, bid))
+	fmt.Fprintf(c.writer, "Best Ask:       %s\n", c.display.fmtPrice(book.Symbol, ask))
+	fmt.Fprintf(c.writer, "Spread:         %s (%.4f%% of mid)\n", c.display.fmtPrice(book.Symbol, spread), spread/((bid+ask)/2)*100)
+}
+
+// formatDepth displays each side of an order book with the quantity available up to every level,
+// asks listed from the furthest level down to the best so both sides meet at the spread
+func (c *CLI) formatDepth(book *api.OrderBook, limit int) {
+	fmt.Fprintln(c.writer, "===========================================")
+	fmt.Fprintf(c.writer, "Depth: %s (top %d)\n", book.Symbol, limit)
+	fmt.Fprintln(c.writer, "===========================================")
+	if len(book.Bids) == 0 && len(book.Asks) == 0 {
+		fmt.Fprintln(c.writer, "Order book is empty")
+		fmt.Fprintln(c.writer, "===========================================")
+		return
+	}
+
+	bids, asks := book.Bids, book.Asks
+	if len(bids) > limit {
+		bids = bids[:limit]
+	}
+	if len(asks) > limit {
+		asks = asks[:limit]
+	}
+
+	fmt.Fprintf(c.writer, "%-5s %-14s %-14s %s\n", "Side", "Price", "Qty", "Cumulative")
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	askTotals := make([]float64, len(asks))
+	total := 0.0
+	for i, level := range asks {
+		total += level.Qty
+		askTotals[i] = total
+	}
+	for i := len(asks) - 1; i >= 0; i-- {
+		fmt.Fprintf(c.writer, "%-5s %-14s %-14s %s\n", "ASK", c.display.fmtPrice(book.Symbol, asks[i].Price),
+			c.display.fmtQty(book.Symbol, asks[i].Qty), c.display.fmtQty(book.Symbol, askTotals[i]))
+	}
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	total = 0
+	for _, level := range bids {
+		total += level.Qty
+		fmt.Fprintf(c.writer, "%-5s %-14s %-14s %s\n", "BID", c.display.fmtPrice(book.Symbol, level.Price),
+			c.display.fmtQty(book.Symbol, level.Qty), c.display.fmtQty(book.Symbol, total))
+	}
+
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	c.formatSpread(book)
+	fmt.Fprintln(c.writer, "===========================================")
+}
+
 // handleConditionalOrder handles the condorder command
 func (c *CLI) handleConditionalOrder(args []string) error {
-	if len(args) < 6 {
-		return fmt.Errorf("usage: condorder <symbol> <side> <quantity> <trigger_type> <operator> <value>")
+	if len(args) > 0 {
+		switch strings.ToLower(args[0]) {
+		case "export":
+			return c.handleConditionalTemplateExport(args[1:])
+		case "import":
+			return c.handleConditionalTemplateImport(args[1:])
+		}
+	}
+
+	args, flags, err := parseCreationFlags(args)
+	if err != nil {
+		return err
+	}
+	options, ok := parseKeywordOptions(args, 6, "limit", "ttl", "window")
+	if !ok {
+		return fmt.Errorf("%w: condorder <symbol> <side> <quantity> <trigger_type> <operator> <value> [limit <price|offset>] [ttl <seconds|duration>] [window <ms|duration>] [--allow-duplicate] [--idempotency-key <key>]", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
 	side := strings.ToUpper(args[1])
-	quantity, err := strconv.ParseFloat(args[2], 64)
+	quantity, err := parseAmount("quantity", args[2])
 	if err != nil {
-		return fmt.Errorf("invalid quantity: %w", err)
+		return err
 	}
 
 	triggerType := strings.ToUpper(args[3])
 	operator := strings.ToUpper(args[4])
-	value, err := strconv.ParseFloat(args[5], 64)
+	// MA_CROSS takes its moving averages in place of a threshold
+	var value float64
+	var fastPeriod, slowPeriod int
+	var interval string
+	var volumeWindow time.Duration
+	if triggerType == "MA_CROSS" {
+		fastPeriod, slowPeriod, interval, err = parseMACrossover(args[5])
+	} else if triggerType == "VOLUME" {
+		// VOLUME takes an optional window after its threshold and compares the 24h volume without one
+		value, volumeWindow, err = parseVolumeSpec(args[5])
+		if volumeWindow == 0 {
+			volumeWindow = service.DefaultVolumeWindow
+		}
+	} else if triggerType == "TIME" {
+		var ts int64
+		ts, err = parseTriggerTime(args[5])
+		value = float64(ts)
+	} else if triggerType == "VOLUME_SURGE" {
+		// VOLUME_SURGE takes a multiplier and compares against its window option, 1h without one
+		value, err = parseAmount("volume surge multiplier", args[5])
+		volumeWindow = service.DefaultVolumeSurgeWindow
+		if spec, ok := options["window"]; ok && err == nil {
+			volumeWindow, err = parseDurationArg("window", spec, time.Millisecond)
+		}
+	} else {
+		// PRICE_CHANGE thresholds are percentages, so "-5%" and "-5" are the same
+		value, err = parseSigned("trigger value", args[5], triggerType == "PRICE_CHANGE")
+	}
 	if err != nil {
-		return fmt.Errorf("invalid trigger value: %w", err)
+		return err
+	}
+	if _, ok := options["window"]; ok && triggerType != "VOLUME_SURGE" {
+		return fmt.Errorf("window is only supported for VOLUME_SURGE triggers; VOLUME takes <threshold>@<window>")
+	}
+
+	// A trailing limit places a limit order at a fixed price or priced from the trigger price
+	orderType := api.OrderTypeMarket
+	var limitPrice, priceOffset float64
+	var offsetPercent bool
+	if spec, ok := options["limit"]; ok {
+		orderType = api.OrderTypeLimit
+		if limitPrice, priceOffset, offsetPercent, err = parseLimitSpec(spec); err != nil {
+			return err
+		}
+	}
+
+	// A TTL ends the order's time window, after which the monitoring engine expires it
+	var timeWindow *repository.TimeWindow
+	if spec, ok := options["ttl"]; ok {
+		ttl, err := parseDurationArg("ttl", spec, time.Second)
+		if err != nil {
+			return err
+		}
+		timeWindow = &repository.TimeWindow{EndTime: time.Now().Add(ttl)}
 	}
 
 	// Parse side
@@ -461,8 +1731,16 @@ func (c *CLI) handleConditionalOrder(args []string) error {
 		trigType = service.TriggerTypePriceChangePercent
 	case "VOLUME":
 		trigType = service.TriggerTypeVolume
+	case "ASK_BID_IMBALANCE":
+		trigType = service.TriggerTypeAskBidImbalance
+	case "MA_CROSS":
+		trigType = service.TriggerTypeMACrossover
+	case "VOLUME_SURGE":
+		trigType = service.TriggerTypeVolumeSurge
+	case "TIME":
+		trigType = service.TriggerTypeTime
 	default:
-		return fmt.Errorf("invalid trigger type: must be PRICE, PRICE_CHANGE, or VOLUME")
+		return fmt.Errorf("invalid trigger type: must be PRICE, PRICE_CHANGE, VOLUME, ASK_BID_IMBALANCE, MA_CROSS, VOLUME_SURGE, or TIME")
 	}
 
 	// Parse operator
@@ -482,29 +1760,97 @@ func (c *CLI) handleConditionalOrder(args []string) error {
 
 	// Create trigger condition (using repository types)
 	triggerCondition := &repository.TriggerCondition{
-		Type:     repository.TriggerType(trigType),
-		Operator: repository.ComparisonOperator(op),
-		Value:    value,
+		Type:       repository.TriggerType(trigType),
+		Operator:   repository.ComparisonOperator(op),
+		Value:      value,
+		Interval:   interval,
+		FastPeriod: fastPeriod,
+		SlowPeriod: slowPeriod,
+		TimeWindow: volumeWindow,
 	}
 
 	// Create conditional order request
 	request := &repository.ConditionalOrderRequest{
 		Symbol:           symbol,
 		Side:             orderSide,
-		Type:             api.OrderTypeMarket,
+		Type:             orderType,
 		Quantity:         quantity,
+		Price:            limitPrice,
+		PriceOffset:      priceOffset,
+		OffsetPercent:    offsetPercent,
 		TriggerCondition: triggerCondition,
+		TimeWindow:       timeWindow,
+		IdempotencyKey:   flags.idempotencyKey,
+		AllowDuplicate:   flags.allowDuplicate,
 	}
 
 	order, err := c.conditionalOrderService.CreateConditionalOrder(request)
 	if err != nil {
-		return fmt.Errorf("failed to create conditional order: %w", err)
+		return creationError(err)
 	}
 
 	c.formatConditionalOrder(order)
 	return nil
 }
 
+// creationFlags holds the duplicate and retry options of the condorder commands
+type creationFlags struct {
+	allowDuplicate bool   // Create even if an active order does the same
+	idempotencyKey string // Retrying with the same key returns the order created first
+}
+
+// parseCreationFlags removes --allow-duplicate and --idempotency-key <key> from args
+func parseCreationFlags(args []string) ([]string, *creationFlags, error) {
+	flags := &creationFlags{}
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--allow-duplicate":
+			flags.allowDuplicate = true
+		case "--idempotency-key":
+			if i+1 >= len(args) || args[i+1] == "" {
+				return nil, nil, fmt.Errorf("%w: --idempotency-key <key>", ErrUsage)
+			}
+			flags.idempotencyKey = args[i+1]
+			i++
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	return rest, flags, nil
+}
+
+// parseKeywordOptions reads the arguments after the first positional ones as keyword/value
+// pairs, e.g. "limit 47950 ttl 4h". Keywords are case-insensitive and may each appear once;
+// ok is false for an unknown, repeated or valueless keyword.
+func parseKeywordOptions(args []string, positional int, keywords ...string) (map[string]string, bool) {
+	if len(args) < positional || (len(args)-positional)%2 != 0 {
+		return nil, false
+	}
+	known := make(map[string]bool, len(keywords))
+	for _, keyword := range keywords {
+		known[keyword] = true
+	}
+	options := make(map[string]string)
+	for i := positional; i < len(args); i += 2 {
+		keyword := strings.ToLower(args[i])
+		if _, repeated := options[keyword]; repeated || !known[keyword] {
+			return nil, false
+		}
+		options[keyword] = args[i+1]
+	}
+	return options, true
+}
+
+// creationError wraps a failed conditional order creation, pointing out --allow-duplicate
+// when an active order already does the same
+func creationError(err error) error {
+	if service.IsDuplicateOrderError(err) {
+		return fmt.Errorf("failed to create conditional order: %w (add --allow-duplicate to create it anyway)", err)
+	}
+	return fmt.Errorf("failed to create conditional order: %w", err)
+}
+
 // handleConditionalOrders handles the condorders command
 func (c *CLI) handleConditionalOrders(args []string) error {
 	orders, err := c.conditionalOrderService.GetActiveConditionalOrders()
@@ -519,34 +1865,93 @@ func (c *CLI) handleConditionalOrders(args []string) error {
 // handleCancelConditionalOrder handles the cancelcond command
 func (c *CLI) handleCancelConditionalOrder(args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("usage: cancelcond <orderID>")
+		return fmt.Errorf("%w: cancelcond <orderID|all> [symbol]", ErrUsage)
 	}
 
 	orderID := args[0]
 
-	if err := c.conditionalOrderService.CancelConditionalOrder(orderID); err != nil {
+	if strings.EqualFold(orderID, "all") {
+		symbol := ""
+		if len(args) > 1 {
+			symbol = strings.ToUpper(args[1])
+		}
+
+		cancelled, err := c.conditionalOrderService.CancelAllConditionalOrders(symbol, repository.CancelReasonBulk, "cli")
+		if err != nil {
+			return fmt.Errorf("failed to cancel conditional orders after %d cancelled: %w", cancelled, err)
+		}
+
+		fmt.Fprintf(c.writer, "Cancelled %d conditional orders\n", cancelled)
+		return nil
+	}
+
+	symbol := ""
+	if len(args) > 1 {
+		symbol = strings.ToUpper(args[1])
+	}
+
+	order, err := c.conditionalOrderService.CancelConditionalOrder(orderID, symbol)
+	if err != nil {
 		return fmt.Errorf("failed to cancel conditional order: %w", err)
 	}
 
 	fmt.Fprintf(c.writer, "Conditional order %s canceled successfully\n", orderID)
+	c.formatCancelledConditionalOrder(order)
+	return nil
+}
+
+// handleConditionalOrderHistory handles the condhistory command
+func (c *CLI) handleConditionalOrderHistory(args []string) error {
+	startTime, endTime, err := parseHistoryRange(args)
+	if err != nil {
+		return err
+	}
+
+	orders, err := c.conditionalOrderService.GetConditionalOrderHistory(startTime, endTime)
+	if err != nil {
+		return fmt.Errorf("failed to get conditional order history: %w", err)
+	}
+
+	if len(orders) == 0 {
+		fmt.Fprintln(c.writer, "No conditional order history")
+		return nil
+	}
+
+	sort.Slice(orders, func(i, j int) bool { return orders[i].CreatedAt < orders[j].CreatedAt })
+
+	fmt.Fprintln(c.writer, "===========================================")
+	fmt.Fprintf(c.writer, "Conditional Order History (%d)\n", len(orders))
+	fmt.Fprintln(c.writer, "===========================================")
+	for i, order := range orders {
+		fmt.Fprintf(c.writer, "\n[%d] Order ID: %s\n", i+1, order.OrderID)
+		fmt.Fprintf(c.writer, "    Symbol:       %s\n", order.Symbol)
+		fmt.Fprintf(c.writer, "    Side:         %s\n", order.Side)
+		fmt.Fprintf(c.writer, "    Quantity:     %s\n", c.display.fmtQty(order.Symbol, order.Quantity))
+		fmt.Fprintf(c.writer, "    Status:       %s\n", order.Status)
+		if order.Status == repository.ConditionalOrderStatusCancelled {
+			formatCancellation(c.writer, c.display, order.CancelReason, order.CancelledBy, order.CancelledAt)
+		}
+	}
+	formatCancellationBreakdown(c.writer, repository.CountCancellationsByReason(orders))
+	fmt.Fprintln(c.writer, "===========================================")
 	return nil
 }
 
 // handleStopLoss handles the stoploss command
 func (c *CLI) handleStopLoss(args []string) error {
 	if len(args) < 3 {
-		return fmt.Errorf("usage: stoploss <symbol> <position> <stop_price>")
+		return fmt.Errorf("%w: stoploss <symbol> <position> <stop_price>", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
-	position, err := strconv.ParseFloat(args[1], 64)
+	position, err := parseAmount("position", args[1])
 	if err != nil {
-		return fmt.Errorf("invalid position: %w", err)
+		return err
 	}
 
-	stopPrice, err := strconv.ParseFloat(args[2], 64)
+	stopPrice, err := parseAmount("stop price", args[2])
 	if err != nil {
-		return fmt.Errorf("invalid stop price: %w", err)
+		return err
 	}
 
 	order, err := c.stopLossService.SetStopLoss(symbol, position, stopPrice)
@@ -558,21 +1963,69 @@ func (c *CLI) handleStopLoss(args []string) error {
 	return nil
 }
 
+// handleTrailingStop handles the trailingstop command
+func (c *CLI) handleTrailingStop(args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("%w: trailingstop <symbol> <position> <trail_percent|--atr <period>x<multiplier>>", ErrUsage)
+	}
+
+	symbol := strings.ToUpper(args[0])
+	position, err := parseAmount("position", args[1])
+	if err != nil {
+		return err
+	}
+
+	var order *repository.TrailingStopOrder
+	if args[2] == "--atr" {
+		if len(args) < 4 {
+			return fmt.Errorf("%w: trailingstop <symbol> <position> --atr <period>x<multiplier>", ErrUsage)
+		}
+		period, multiplier, err := parseATRSpec(args[3])
+		if err != nil {
+			return err
+		}
+		order, err = c.stopLossService.SetATRTrailingStop(symbol, position, service.ATRTrail{Period: period, Multiplier: multiplier})
+		if err != nil {
+			return fmt.Errorf("failed to set trailing stop: %w", err)
+		}
+	} else {
+		trailPercent, err := parseNumber("trail percent", args[2], numberFormat{AllowPercent: true})
+		if err != nil {
+			return err
+		}
+		order, err = c.stopLossService.SetTrailingStop(symbol, position, trailPercent)
+		if err != nil {
+			return fmt.Errorf("failed to set trailing stop: %w", err)
+		}
+	}
+
+	c.formatTrailingStopOrder(order)
+	return nil
+}
+
 // handleTakeProfit handles the takeprofit command
 func (c *CLI) handleTakeProfit(args []string) error {
+	args, flags, err := parseTakeProfitFlags(args, true)
+	if err != nil {
+		return err
+	}
 	if len(args) < 3 {
-		return fmt.Errorf("usage: takeprofit <symbol> <position> <target_price>")
+		return fmt.Errorf("%w: takeprofit <symbol> <position> <target_price> [--entry <price>] [--force]", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
-	position, err := strconv.ParseFloat(args[1], 64)
+	position, err := parseAmount("position", args[1])
 	if err != nil {
-		return fmt.Errorf("invalid position: %w", err)
+		return err
 	}
 
-	targetPrice, err := strconv.ParseFloat(args[2], 64)
+	targetPrice, err := parseAmount("target price", args[2])
 	if err != nil {
-		return fmt.Errorf("invalid target price: %w", err)
+		return err
+	}
+
+	if err := confirmTakeProfit(c.writer, c.display, symbol, c.checkTakeProfit(symbol, position, targetPrice, flags.entryPrice), flags.force); err != nil {
+		return err
 	}
 
 	order, err := c.stopLossService.SetTakeProfit(symbol, position, targetPrice)
@@ -584,10 +2037,130 @@ func (c *CLI) handleTakeProfit(args []string) error {
 	return nil
 }
 
+// handleBracket handles the bracket command
+func (c *CLI) handleBracket(args []string) error {
+	args, flags, err := parseTakeProfitFlags(args, true)
+	if err != nil {
+		return err
+	}
+	// A side in place of the position places an entry order first
+	if len(args) >= 2 && (strings.EqualFold(args[1], "BUY") || strings.EqualFold(args[1], "SELL")) {
+		if flags.entryPrice > 0 {
+			return fmt.Errorf("%w: --entry applies to a held position; a bracket with an entry order uses its entry price", ErrUsage)
+		}
+		return c.placeBracketOrder(args, flags.force)
+	}
+	if len(args) < 4 {
+		return fmt.Errorf("%w: bracket <symbol> [BUY <quantity> <entry_price> | <position>] <stop_price> <target_price> [--entry <price>] [--force]", ErrUsage)
+	}
+
+	symbol := strings.ToUpper(args[0])
+	position, err := parseAmount("position", args[1])
+	if err != nil {
+		return err
+	}
+
+	stopPrice, err := parseAmount("stop price", args[2])
+	if err != nil {
+		return err
+	}
+
+	targetPrice, err := parseAmount("target price", args[3])
+	if err != nil {
+		return err
+	}
+
+	if err := confirmTakeProfit(c.writer, c.display, symbol, c.checkTakeProfit(symbol, position, targetPrice, flags.entryPrice), flags.force); err != nil {
+		return err
+	}
+
+	pair, err := c.stopLossService.SetStopLossTakeProfit(symbol, position, stopPrice, targetPrice)
+	if err != nil {
+		return fmt.Errorf("failed to set stop loss and take profit: %w", err)
+	}
+
+	fmt.Fprintf(c.writer, "Pair ID:        %s\n", pair.PairID)
+	c.formatStopOrder(pair.StopLossOrder)
+	c.formatStopOrder(pair.TakeProfitOrder)
+	return nil
+}
+
+// placeBracketOrder places the limit entry order of a bracket and, once it fills, its stop loss
+// and take profit pair; it returns when the pair is set or the entry is cancelled
+func (c *CLI) placeBracketOrder(args []string, force bool) error {
+	if len(args) != 6 {
+		return fmt.Errorf("%w: bracket <symbol> BUY <quantity> <entry_price> <stop_price> <target_price> [--force]", ErrUsage)
+	}
+
+	symbol := strings.ToUpper(args[0])
+	side := api.OrderSide(strings.ToUpper(args[1]))
+	quantity, err := parseAmount("quantity", args[2])
+	if err != nil {
+		return err
+	}
+	entryPrice, err := parseAmount("entry price", args[3])
+	if err != nil {
+		return err
+	}
+	stopPrice, err := parseAmount("stop price", args[4])
+	if err != nil {
+		return err
+	}
+	targetPrice, err := parseAmount("target price", args[5])
+	if err != nil {
+		return err
+	}
+
+	if err := c.checkMaintenance(); err != nil {
+		return err
+	}
+	if err := confirmTakeProfit(c.writer, c.display, symbol, c.checkTakeProfit(symbol, quantity, targetPrice, entryPrice), force); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.writer, "Placing limit buy of %s %s at %s, waiting up to %s for it to fill...\n",
+		c.display.fmtQty(symbol, quantity), symbol, c.display.fmtPrice(symbol, entryPrice), service.DefaultBracketFillTimeout)
+
+	result, err := c.bracketExecutor.PlaceBracketOrder(symbol, side, quantity, entryPrice, stopPrice, targetPrice)
+	if result != nil {
+		fmt.Fprintf(c.writer, "Entry Order ID: %d\n", result.EntryOrderID)
+		fmt.Fprintf(c.writer, "Entry Status:   %s\n", result.EntryStatus)
+		fmt.Fprintf(c.writer, "Filled:         %s\n", c.display.fmtQty(symbol, result.FilledQty))
+	}
+	if err != nil {
+		return fmt.Errorf("bracket order incomplete: %w", err)
+	}
+
+	fmt.Fprintf(c.writer, "Pair ID:        %s\n", result.Pair.PairID)
+	c.formatStopOrder(result.Pair.StopLossOrder)
+	c.formatStopOrder(result.Pair.TakeProfitOrder)
+	return nil
+}
+
+// checkTakeProfit checks a take-profit target against the fee model. Without a known entry
+// price the holding is assumed bought at the current price; nil means neither was available.
+func (c *CLI) checkTakeProfit(symbol string, position, targetPrice, entryPrice float64) *service.TakeProfitCheck {
+	estimated := entryPrice <= 0
+	if estimated {
+		currentPrice, err := c.marketService.GetCurrentPrice(symbol)
+		if err != nil || currentPrice <= 0 {
+			return nil
+		}
+		entryPrice = currentPrice
+	}
+
+	check, err := c.profitGuard.Check(api.PositionSideLong, entryPrice, targetPrice, position)
+	if err != nil {
+		return nil
+	}
+	check.EntryEstimated = estimated
+	return check
+}
+
 // handleStopOrders handles the stoporders command
 func (c *CLI) handleStopOrders(args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("usage: stoporders <symbol>")
+		return fmt.Errorf("%w: stoporders <symbol>", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
@@ -604,19 +2177,171 @@ func (c *CLI) handleStopOrders(args []string) error {
 // handleCancelStopOrder handles the cancelstop command
 func (c *CLI) handleCancelStopOrder(args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("usage: cancelstop <orderID>")
+		return fmt.Errorf("%w: cancelstop <orderID> [symbol]", ErrUsage)
 	}
 
 	orderID := args[0]
+	symbol := ""
+	if len(args) > 1 {
+		symbol = strings.ToUpper(args[1])
+	}
 
-	if err := c.stopLossService.CancelStopOrder(orderID); err != nil {
+	cancelled, err := c.stopLossService.CancelStopOrder(orderID, symbol)
+	if err != nil {
 		return fmt.Errorf("failed to cancel stop order: %w", err)
 	}
 
 	fmt.Fprintf(c.writer, "Stop order %s canceled successfully\n", orderID)
+	c.formatCancelledStopOrder(cancelled)
 	return nil
 }
 
+// handleAutomation handles the automation command
+func (c *CLI) handleAutomation(args []string) error {
+	if c.automationService == nil {
+		return fmt.Errorf("automation is not available")
+	}
+	if len(args) < 1 {
+		return fmt.Errorf("%w: automation <status|dca|grid|stop> [args...]", ErrUsage)
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "status":
+		c.formatAutomationStatus(c.automationService.GetStatus())
+		return nil
+	case "dca":
+		return c.handleAutomationDCA(args[1:])
+	case "grid":
+		return c.handleAutomationGrid(args[1:])
+	case "stop":
+		return c.handleAutomationStop(args[1:])
+	default:
+		return fmt.Errorf("unknown automation subcommand: %s", args[0])
+	}
+}
+
+// handleAutomationDCA handles the automation dca subcommand
+func (c *CLI) handleAutomationDCA(args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("%w: automation dca <symbol> <quantity> <interval>", ErrUsage)
+	}
+
+	symbol := strings.ToUpper(args[0])
+	quantity, err := parseAmount("quantity", args[1])
+	if err != nil {
+		return err
+	}
+
+	interval, err := time.ParseDuration(args[2])
+	if err != nil {
+		return fmt.Errorf("invalid interval: %w", err)
+	}
+
+	plan, err := c.automationService.CreateDCAPlan(symbol, quantity, interval)
+	if err != nil {
+		return fmt.Errorf("failed to create DCA plan: %w", err)
+	}
+
+	fmt.Fprintf(c.writer, "DCA plan %s created: buying %s %s every %s, starting now\n",
+		plan.PlanID, c.display.fmtQty(plan.Symbol, plan.Quantity), plan.Symbol, plan.Interval)
+	return nil
+}
+
+// handleAutomationGrid handles the automation grid subcommand
+func (c *CLI) handleAutomationGrid(args []string) error {
+	if len(args) < 5 {
+		return fmt.Errorf("%w: automation grid <symbol> <lower> <upper> <grids> <quantity>", ErrUsage)
+	}
+
+	symbol := strings.ToUpper(args[0])
+	lowerPrice, err := parseAmount("lower price", args[1])
+	if err != nil {
+		return err
+	}
+
+	upperPrice, err := parseAmount("upper price", args[2])
+	if err != nil {
+		return err
+	}
+
+	gridCount, err := parseCount("grid count", args[3])
+	if err != nil {
+		return err
+	}
+
+	quantity, err := parseAmount("quantity", args[4])
+	if err != nil {
+		return err
+	}
+
+	plan, err := c.automationService.CreateGridPlan(symbol, lowerPrice, upperPrice, gridCount, quantity)
+	if err != nil {
+		return fmt.Errorf("failed to create grid plan: %w", err)
+	}
+
+	fmt.Fprintf(c.writer, "Grid plan %s created for %s: limit buys are placed on the levels below the current price\n",
+		plan.PlanID, plan.Symbol)
+	return nil
+}
+
+// handleAutomationStop handles the automation stop subcommand
+func (c *CLI) handleAutomationStop(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("%w: automation stop <planID>", ErrUsage)
+	}
+
+	planID := args[0]
+
+	// The plan ID may belong to either a DCA or a grid plan
+	if err := c.automationService.StopDCAPlan(planID); err == nil {
+		fmt.Fprintf(c.writer, "DCA plan %s stopped successfully\n", planID)
+		return nil
+	}
+
+	if err := c.automationService.StopGridPlan(planID); err != nil {
+		return fmt.Errorf("failed to stop plan: %w", err)
+	}
+
+	fmt.Fprintf(c.writer, "Grid plan %s stopped successfully\n", planID)
+	return nil
+}
+
+// formatAutomationStatus formats and displays a summary of running plans
+func (c *CLI) formatAutomationStatus(status *service.AutomationStatus) {
+	fmt.Fprintln(c.writer, "===========================================")
+	fmt.Fprintln(c.writer, "Automation Status")
+	fmt.Fprintln(c.writer, "===========================================")
+	fmt.Fprintf(c.writer, "DCA Plans:      %d/%d active\n", status.ActiveDCAPlans, status.MaxDCAPlans)
+	fmt.Fprintf(c.writer, "Grid Plans:     %d/%d active\n", status.ActiveGrids, status.MaxGrids)
+
+	for i, plan := range status.DCAPlans {
+		fmt.Fprintf(c.writer, "\n[DCA %d] Plan ID: %s\n", i+1, plan.PlanID)
+		fmt.Fprintf(c.writer, "    Symbol:       %s\n", plan.Symbol)
+		fmt.Fprintf(c.writer, "    Quantity:     %s\n", c.display.fmtQty(plan.Symbol, plan.Quantity))
+		fmt.Fprintf(c.writer, "    Interval:     %s\n", plan.Interval)
+		fmt.Fprintf(c.writer, "    Buys Placed:  %d\n", plan.Executions)
+		fmt.Fprintf(c.writer, "    Next Buy:     %s\n", c.display.fmtTime(plan.NextRunAt))
+		if plan.LastError != "" {
+			fmt.Fprintf(c.writer, "    Last Error:   %s\n", plan.LastError)
+		}
+	}
+
+	for i, plan := range status.GridPlans {
+		fmt.Fprintf(c.writer, "\n[Grid %d] Plan ID: %s\n", i+1, plan.PlanID)
+		fmt.Fprintf(c.writer, "    Symbol:       %s\n", plan.Symbol)
+		fmt.Fprintf(c.writer, "    Range:        %s - %s\n", c.display.fmtPrice(plan.Symbol, plan.LowerPrice), c.display.fmtPrice(plan.Symbol, plan.UpperPrice))
+		fmt.Fprintf(c.writer, "    Grids:        %d\n", plan.GridCount)
+		fmt.Fprintf(c.writer, "    Qty/Grid:     %s\n", c.display.fmtQty(plan.Symbol, plan.QuantityPerGrid))
+		fmt.Fprintf(c.writer, "    Open Orders:  %d\n", len(plan.Orders))
+		fmt.Fprintf(c.writer, "    Filled:       %d\n", plan.FilledOrders)
+		if plan.LastError != "" {
+			fmt.Fprintf(c.writer, "    Last Error:   %s\n", plan.LastError)
+		}
+	}
+
+	fmt.Fprintln(c.writer, "===========================================")
+}
+
 // formatConditionalOrder formats and displays conditional order information
 func (c *CLI) formatConditionalOrder(order *repository.ConditionalOrder) {
 	fmt.Fprintln(c.writer, "-------------------------------------------")
@@ -626,13 +2351,19 @@ func (c *CLI) formatConditionalOrder(order *repository.ConditionalOrder) {
 	fmt.Fprintf(c.writer, "Symbol:         %s\n", order.Symbol)
 	fmt.Fprintf(c.writer, "Side:           %s\n", order.Side)
 	fmt.Fprintf(c.writer, "Type:           %s\n", order.Type)
-	fmt.Fprintf(c.writer, "Quantity:       %.8f\n", order.Quantity)
+	fmt.Fprintf(c.writer, "Quantity:       %s\n", c.display.fmtQty(order.Symbol, order.Quantity))
+	if order.Type == api.OrderTypeLimit {
+		fmt.Fprintf(c.writer, "Limit Price:    %s\n", c.formatConditionalLimit(order))
+	}
 	fmt.Fprintf(c.writer, "Status:         %s\n", order.Status)
 	if order.TriggerCondition != nil {
-		fmt.Fprintf(c.writer, "Trigger:        %s %s %.8f\n",
+		fmt.Fprintf(c.writer, "Trigger:        %s %s %s\n",
 			c.formatTriggerType(order.TriggerCondition.Type),
 			c.formatOperator(order.TriggerCondition.Operator),
-			order.TriggerCondition.Value)
+			c.formatTriggerValue(order.Symbol, order.TriggerCondition))
+	}
+	if order.TimeWindow != nil && !order.TimeWindow.EndTime.IsZero() {
+		fmt.Fprintf(c.writer, "Expires:        %s\n", c.display.fmtTime(order.TimeWindow.EndTime.UnixMilli()))
 	}
 	fmt.Fprintln(c.writer, "-------------------------------------------")
 }
@@ -653,13 +2384,19 @@ func (c *CLI) formatConditionalOrderList(orders []*repository.ConditionalOrder)
 		fmt.Fprintf(c.writer, "    Symbol:       %s\n", order.Symbol)
 		fmt.Fprintf(c.writer, "    Side:         %s\n", order.Side)
 		fmt.Fprintf(c.writer, "    Type:         %s\n", order.Type)
-		fmt.Fprintf(c.writer, "    Quantity:     %.8f\n", order.Quantity)
+		fmt.Fprintf(c.writer, "    Quantity:     %s\n", c.display.fmtQty(order.Symbol, order.Quantity))
+		if order.Type == api.OrderTypeLimit {
+			fmt.Fprintf(c.writer, "    Limit Price:  %s\n", c.formatConditionalLimit(order))
+		}
 		fmt.Fprintf(c.writer, "    Status:       %s\n", order.Status)
 		if order.TriggerCondition != nil {
-			fmt.Fprintf(c.writer, "    Trigger:      %s %s %.8f\n",
+			fmt.Fprintf(c.writer, "    Trigger:      %s %s %s\n",
 				c.formatTriggerType(order.TriggerCondition.Type),
 				c.formatOperator(order.TriggerCondition.Operator),
-				order.TriggerCondition.Value)
+				c.formatTriggerValue(order.Symbol, order.TriggerCondition))
+		}
+		if order.TimeWindow != nil && !order.TimeWindow.EndTime.IsZero() {
+			fmt.Fprintf(c.writer, "    Expires:      %s\n", c.display.fmtTime(order.TimeWindow.EndTime.UnixMilli()))
 		}
 	}
 
@@ -674,12 +2411,67 @@ func (c *CLI) formatStopOrder(order *repository.StopOrder) {
 	fmt.Fprintf(c.writer, "Order ID:       %s\n", order.OrderID)
 	fmt.Fprintf(c.writer, "Symbol:         %s\n", order.Symbol)
 	fmt.Fprintf(c.writer, "Type:           %s\n", c.formatStopOrderType(order.Type))
-	fmt.Fprintf(c.writer, "Position:       %.8f\n", order.Position)
-	fmt.Fprintf(c.writer, "Stop Price:     %.8f\n", order.StopPrice)
+	fmt.Fprintf(c.writer, "Position:       %s\n", c.display.fmtQty(order.Symbol, order.Position))
+	fmt.Fprintf(c.writer, "Stop Price:     %s\n", c.display.fmtPrice(order.Symbol, order.StopPrice))
 	fmt.Fprintf(c.writer, "Status:         %s\n", order.Status)
 	fmt.Fprintln(c.writer, "-------------------------------------------")
 }
 
+// formatTrailingStopOrder formats and displays a created trailing stop order
+func (c *CLI) formatTrailingStopOrder(order *repository.TrailingStopOrder) {
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintln(c.writer, "Trailing Stop Created Successfully")
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintf(c.writer, "Order ID:       %s\n", order.OrderID)
+	fmt.Fprintf(c.writer, "Symbol:         %s\n", order.Symbol)
+	fmt.Fprintf(c.writer, "Position:       %s\n", c.display.fmtQty(order.Symbol, order.Position))
+	fmt.Fprintf(c.writer, "Trail:          %s\n", formatTrail(order))
+	fmt.Fprintf(c.writer, "Highest Price:  %s\n", c.display.fmtPrice(order.Symbol, order.HighestPrice))
+	fmt.Fprintf(c.writer, "Stop Price:     %s\n", c.display.fmtPrice(order.Symbol, order.CurrentStopPrice))
+	fmt.Fprintf(c.writer, "Status:         %s\n", order.Status)
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+}
+
+// formatTrail describes the effective trail, with its ATR settings in ATR mode
+func formatTrail(order *repository.TrailingStopOrder) string {
+	if order.TrailMode != repository.TrailModeATR {
+		return fmt.Sprintf("%.2f%%", order.TrailPercent)
+	}
+	return fmt.Sprintf("%.2f%% (%gx ATR(%d) %s = %g)", order.TrailPercent, order.ATRMultiplier, order.ATRPeriod, order.ATRInterval, order.ATR)
+}
+
+// formatCancelledConditionalOrder displays what a cancelled conditional order would have done
+func (c *CLI) formatCancelledConditionalOrder(order *repository.ConditionalOrder) {
+	fmt.Fprintf(c.writer, "    Symbol:       %s\n", order.Symbol)
+	fmt.Fprintf(c.writer, "    Side:         %s\n", order.Side)
+	fmt.Fprintf(c.writer, "    Type:         %s\n", order.Type)
+	fmt.Fprintf(c.writer, "    Quantity:     %s\n", c.display.fmtQty(order.Symbol, order.Quantity))
+	if order.TriggerCondition != nil {
+		fmt.Fprintf(c.writer, "    Trigger:      %s %s %s\n",
+			c.formatTriggerType(order.TriggerCondition.Type),
+			c.formatOperator(order.TriggerCondition.Operator),
+			c.formatTriggerValue(order.Symbol, order.TriggerCondition))
+	}
+}
+
+// formatCancelledStopOrder displays the stop or trailing stop order a cancellation removed
+func (c *CLI) formatCancelledStopOrder(cancelled *service.CancelledStopOrder) {
+	if order := cancelled.StopOrder; order != nil {
+		fmt.Fprintf(c.writer, "    Symbol:       %s\n", order.Symbol)
+		fmt.Fprintf(c.writer, "    Type:         %s\n", c.formatStopOrderType(order.Type))
+		fmt.Fprintf(c.writer, "    Position:     %s\n", c.display.fmtQty(order.Symbol, order.Position))
+		fmt.Fprintf(c.writer, "    Stop Price:   %s\n", c.display.fmtPrice(order.Symbol, order.StopPrice))
+		return
+	}
+	if order := cancelled.TrailingOrder; order != nil {
+		fmt.Fprintf(c.writer, "    Symbol:       %s\n", order.Symbol)
+		fmt.Fprintln(c.writer, "    Type:         TRAILING_STOP")
+		fmt.Fprintf(c.writer, "    Position:     %s\n", c.display.fmtQty(order.Symbol, order.Position))
+		fmt.Fprintf(c.writer, "    Trail:        %s\n", formatTrail(order))
+		fmt.Fprintf(c.writer, "    Stop Price:   %s\n", c.display.fmtPrice(order.Symbol, order.CurrentStopPrice))
+	}
+}
+
 // formatStopOrderList formats and displays a list of stop orders
 func (c *CLI) formatStopOrderList(orders []*repository.StopOrder) {
 	if len(orders) == 0 {
@@ -695,14 +2487,69 @@ func (c *CLI) formatStopOrderList(orders []*repository.StopOrder) {
 		fmt.Fprintf(c.writer, "\n[%d] Order ID: %s\n", i+1, order.OrderID)
 		fmt.Fprintf(c.writer, "    Symbol:       %s\n", order.Symbol)
 		fmt.Fprintf(c.writer, "    Type:         %s\n", c.formatStopOrderType(order.Type))
-		fmt.Fprintf(c.writer, "    Position:     %.8f\n", order.Position)
-		fmt.Fprintf(c.writer, "    Stop Price:   %.8f\n", order.StopPrice)
+		fmt.Fprintf(c.writer, "    Position:     %s\n", c.display.fmtQty(order.Symbol, order.Position))
+		fmt.Fprintf(c.writer, "    Stop Price:   %s\n", c.display.fmtPrice(order.Symbol, order.StopPrice))
 		fmt.Fprintf(c.writer, "    Status:       %s\n", order.Status)
 	}
 
 	fmt.Fprintln(c.writer, "===========================================")
 }
 
+// formatConditionalLimit formats the limit price of a conditional order, which is either fixed
+// or an offset from the trigger price
+func (c *CLI) formatConditionalLimit(order *repository.ConditionalOrder) string {
+	switch {
+	case order.Price > 0:
+		return c.display.fmtPrice(order.Symbol, order.Price)
+	case order.OffsetPercent:
+		return fmt.Sprintf("trigger price %+g%%", order.PriceOffset)
+	default:
+		return fmt.Sprintf("trigger price %+g", order.PriceOffset)
+	}
+}
+
+// formatTriggerValue formats a trigger threshold; price thresholds are displayed as prices
+func (c *CLI) formatTriggerValue(symbol string, condition *repository.TriggerCondition) string {
+	if condition.Type == repository.TriggerTypePrice {
+		return c.display.fmtPrice(symbol, condition.Value)
+	}
+	if condition.Type == repository.TriggerTypeVolume && condition.TimeWindow > 0 {
+		return fmt.Sprintf("%s over %s", c.display.fmtQty(symbol, condition.Value), service.FormatShortDuration(condition.TimeWindow))
+	}
+	if condition.Type == repository.TriggerTypeTime {
+		return c.display.fmtTime(int64(condition.Value))
+	}
+	if condition.Type == repository.TriggerTypeVolumeSurge {
+		return fmt.Sprintf("%gx the %s average", condition.Value, service.FormatShortDuration(condition.TimeWindow))
+	}
+	if condition.Type == repository.TriggerTypeMACrossover {
+		interval := condition.Interval
+		if interval == "" {
+			interval = service.DefaultMACrossoverInterval
+		}
+		return fmt.Sprintf("SMA %d/%d %s", condition.FastPeriod, condition.SlowPeriod, interval)
+	}
+	return fmt.Sprintf("%.8f", condition.Value)
+}
+
+// parseMACrossover parses the <fast>/<slow>[@interval] moving averages of an MA_CROSS trigger
+func parseMACrossover(spec string) (int, int, string, error) {
+	periods, interval, _ := strings.Cut(spec, "@")
+	fast, slow, ok := strings.Cut(periods, "/")
+	if !ok {
+		return 0, 0, "", fmt.Errorf("invalid MA_CROSS value %q: want <fast>/<slow>[@interval], e.g. 9/21@15m", spec)
+	}
+	fastPeriod, err := strconv.Atoi(fast)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("invalid MA_CROSS fast period %q", fast)
+	}
+	slowPeriod, err := strconv.Atoi(slow)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("invalid MA_CROSS slow period %q", slow)
+	}
+	return fastPeriod, slowPeriod, interval, nil
+}
+
 // formatTriggerType formats trigger type for display
 func (c *CLI) formatTriggerType(triggerType repository.TriggerType) string {
 	switch triggerType {
@@ -712,6 +2559,16 @@ func (c *CLI) formatTriggerType(triggerType repository.TriggerType) string {
 		return "PRICE_CHANGE"
 	case repository.TriggerTypeVolume:
 		return "VOLUME"
+	case repository.TriggerTypeRSI:
+		return "RSI"
+	case repository.TriggerTypeAskBidImbalance:
+		return "ASK_BID_IMBALANCE"
+	case repository.TriggerTypeMACrossover:
+		return "MA_CROSS"
+	case repository.TriggerTypeVolumeSurge:
+		return "VOLUME_SURGE"
+	case repository.TriggerTypeTime:
+		return "TIME"
 	default:
 		return "UNKNOWN"
 	}
@@ -744,3 +2601,310 @@ func (c *CLI) formatStopOrderType(orderType repository.StopOrderType) string {
 		return "UNKNOWN"
 	}
 }
+
+// handleSymbolPauses handles the paused command shared by the spot and futures CLIs
+func handleSymbolPauses(w io.Writer, display *displayFormat, guard service.SymbolFailureGuard, args []string) error {
+	if guard == nil {
+		return fmt.Errorf("symbol pausing is not available")
+	}
+
+	if len(args) == 0 {
+		formatSymbolPauses(w, display, guard.GetPausedSymbols())
+		return nil
+	}
+
+	if strings.ToLower(args[0]) != "clear" || len(args) < 2 {
+		return fmt.Errorf("%w: paused [clear <symbol>]", ErrUsage)
+	}
+
+	symbol := strings.ToUpper(args[1])
+	if !guard.ClearPause(symbol) {
+		return fmt.Errorf("symbol %s is not paused", symbol)
+	}
+
+	fmt.Fprintf(w, "Pause lifted for %s, new orders are allowed again\n", symbol)
+	return nil
+}
+
+// parseHistoryRange parses the range of the condhistory command into a creation time range
+// in Unix milliseconds: either look-back hours (default 24), or a start and optional end given
+// as RFC3339 times or Unix milliseconds
+func parseHistoryRange(args []string) (int64, int64, error) {
+	endTime := timeutil.NowMillis()
+	if len(args) == 0 {
+		return endTime - 24*time.Hour.Milliseconds(), endTime, nil
+	}
+
+	// Numbers too small to be millisecond timestamps are look-back hours
+	if hours, err := parseCount("hours", args[0]); err == nil && !timeutil.IsMillis(int64(hours)) {
+		return endTime - int64(hours)*time.Hour.Milliseconds(), endTime, nil
+	}
+
+	startTime, err := timeutil.Parse(args[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid start: expected hours, an RFC3339 time or Unix milliseconds: %w", err)
+	}
+	if len(args) > 1 {
+		if endTime, err = timeutil.Parse(args[1]); err != nil {
+			return 0, 0, fmt.Errorf("invalid end: %w", err)
+		}
+	}
+	if startTime > endTime {
+		return 0, 0, fmt.Errorf("start %s is after end %s", args[0], timeutil.Format(endTime, time.UTC))
+	}
+	return startTime, endTime, nil
+}
+
+// formatCancellation displays why, by whom and when a conditional order was cancelled
+func formatCancellation(w io.Writer, display *displayFormat, reason repository.CancelReason, cancelledBy string, cancelledAt int64) {
+	if reason == "" {
+		reason = "UNKNOWN"
+	}
+	fmt.Fprintf(w, "    Cancelled:    %s by %s", reason, cancelledBy)
+	if cancelledAt > 0 {
+		fmt.Fprintf(w, " at %s", display.fmtTime(cancelledAt))
+	}
+	fmt.Fprintln(w)
+}
+
+// formatCancellationBreakdown displays the number of cancellations per reason
+func formatCancellationBreakdown(w io.Writer, counts map[repository.CancelReason]int) {
+	if len(counts) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "\nCancellations by reason:")
+	for _, reason := range repository.CancelReasons {
+		if counts[reason] > 0 {
+			fmt.Fprintf(w, "    %-12s %d\n", reason, counts[reason])
+		}
+	}
+}
+
+// formatSymbolPauses formats and displays paused symbols
+func formatSymbolPauses(w io.Writer, display *displayFormat, pauses []*service.SymbolPause) {
+	if len(pauses) == 0 {
+		fmt.Fprintln(w, "No paused symbols")
+		return
+	}
+
+	fmt.Fprintln(w, "-------------------------------------------")
+	fmt.Fprintf(w, "Paused Symbols (%d):\n", len(pauses))
+	fmt.Fprintln(w, "-------------------------------------------")
+	for _, pause := range pauses {
+		fmt.Fprintf(w, "Symbol:      %s\n", pause.Symbol)
+		fmt.Fprintf(w, "Failures:    %d\n", pause.Failures)
+		fmt.Fprintf(w, "Paused At:   %s\n", display.fmtTime(timeutil.SecondsToMillis(pause.PausedAt)))
+		fmt.Fprintf(w, "Resumes At:  %s\n", display.fmtTime(timeutil.SecondsToMillis(pause.ResumeAt)))
+		fmt.Fprintf(w, "Last Error:  %s\n", pause.LastError)
+		fmt.Fprintln(w, "-------------------------------------------")
+	}
+}
+
+// handleRateLimitStatus handles the ratelimit command shared by the spot and futures CLIs
+// handleDailyOrders handles the dailyorders command
+func (c *CLI) handleDailyOrders(args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("%w: dailyorders", ErrUsage)
+	}
+	if c.riskMgr == nil {
+		return fmt.Errorf("daily order count is not available")
+	}
+
+	stats := c.riskMgr.GetDailyOrderStats()
+	remaining := stats.Limit - stats.Count
+	if remaining < 0 {
+		remaining = 0
+	}
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintf(c.writer, "Daily Orders (%s UTC):\n", stats.Date)
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintf(c.writer, "Placed:    %d / %d\n", stats.Count, stats.Limit)
+	fmt.Fprintf(c.writer, "Remaining: %d\n", remaining)
+	fmt.Fprintf(c.writer, "Resets:    %s\n", c.display.fmtTime(stats.ResetAt.UnixMilli()))
+	return nil
+}
+
+func handleRateLimitStatus(w io.Writer, display *displayFormat, provider api.RateLimitStatusProvider) error {
+	if provider == nil {
+		return fmt.Errorf("rate-limit status is not available")
+	}
+
+	formatRateLimitStatus(w, display, provider.GetRateLimitStatus())
+	return nil
+}
+
+// formatRateLimitStatus formats and displays rate-limit usage versus limits
+func formatRateLimitStatus(w io.Writer, display *displayFormat, status *api.RateLimitStatus) {
+	if status == nil || status.UpdatedAt == 0 {
+		fmt.Fprintln(w, "No rate-limit data received yet")
+		return
+	}
+
+	fmt.Fprintln(w, "-------------------------------------------")
+	fmt.Fprintf(w, "Rate Limit Status (updated %s):\n", display.fmtTime(timeutil.SecondsToMillis(status.UpdatedAt)))
+	fmt.Fprintln(w, "-------------------------------------------")
+	fmt.Fprintln(w, "Request Weight:")
+	formatRateLimitUsage(w, status.UsedWeight, status.WeightLimits)
+	fmt.Fprintln(w, "Order Count:")
+	formatRateLimitUsage(w, status.OrderCounts, status.OrderLimits)
+	fmt.Fprintln(w, "-------------------------------------------")
+}
+
+// formatRateLimitUsage prints usage per interval, including limits without recorded usage
+func formatRateLimitUsage(w io.Writer, used map[string]int, limits map[string]int) {
+	intervals := make([]string, 0, len(used)+len(limits))
+	for interval := range used {
+		intervals = append(intervals, interval)
+	}
+	for interval := range limits {
+		if _, exists := used[interval]; !exists {
+			intervals = append(intervals, interval)
+		}
+	}
+
+	if len(intervals) == 0 {
+		fmt.Fprintln(w, "  (no data)")
+		return
+	}
+	sort.Strings(intervals)
+
+	for _, interval := range intervals {
+		limit, hasLimit := limits[interval]
+		if !hasLimit || limit <= 0 {
+			fmt.Fprintf(w, "  %-5s %d / unknown\n", interval+":", used[interval])
+			continue
+		}
+		fmt.Fprintf(w, "  %-5s %d / %d (%.1f%%)\n", interval+":", used[interval], limit, float64(used[interval])/float64(limit)*100)
+	}
+}
+
+// handleFilters shows the cached order filters of a symbol or reloads exchange info
+func (c *CLI) handleFilters(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: filters <symbol> | filters refresh", ErrUsage)
+	}
+	if c.exchangeInfo == nil {
+		return fmt.Errorf("exchange filters are not available")
+	}
+
+	if strings.EqualFold(args[0], "refresh") {
+		if err := c.exchangeInfo.Refresh(); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.writer, "Exchange info reloaded at %s\n", c.exchangeInfo.UpdatedAt().Format(time.RFC3339))
+		return nil
+	}
+
+	info, err := c.exchangeInfo.GetSymbolInfo(strings.ToUpper(args[0]))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintf(c.writer, "Filters of %s (%s):\n", info.Symbol, info.Status)
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintf(c.writer, "Quantity:     %s - %s, step %s\n", formatFilter(info.MinQty), formatFilter(info.MaxQty), formatFilter(info.StepSize))
+	fmt.Fprintf(c.writer, "Price:        %s - %s, tick %s\n", formatFilter(info.MinPrice), formatFilter(info.MaxPrice), formatFilter(info.TickSize))
+	fmt.Fprintf(c.writer, "Min Notional: %s\n", formatFilter(info.MinNotional))
+	fmt.Fprintf(c.writer, "Loaded:       %s\n", c.exchangeInfo.UpdatedAt().Format(time.RFC3339))
+	return nil
+}
+
+// formatFilter formats a filter limit, which is unset when 0
+func formatFilter(value float64) string {
+	if value == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// handleDust converts dust balances to BNB and reports what was received
+func (c *CLI) handleDust(args []string) error {
+	if c.dustConverter == nil {
+		return fmt.Errorf("dust conversion is not available")
+	}
+
+	result, err := c.dustConverter.ConvertDust(args)
+	if err != nil {
+		return err
+	}
+
+	c.formatDustConversion(result)
+	return nil
+}
+
+// formatDustConversion formats and displays the BNB received per converted asset
+func (c *CLI) formatDustConversion(result *api.DustConversionResult) {
+	if len(result.Transfers) == 0 {
+		fmt.Fprintln(c.writer, "No dust to convert")
+		return
+	}
+
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintln(c.writer, "Dust Converted to BNB:")
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	for _, transfer := range result.Transfers {
+		fmt.Fprintf(c.writer, "%-10s %s -> %s BNB (fee %s BNB)\n",
+			transfer.FromAsset,
+			c.display.fmtQty("", transfer.Amount),
+			c.display.fmtQty("", transfer.TransferredAmount),
+			c.display.fmtQty("", transfer.ServiceChargeAmount))
+	}
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintf(c.writer, "BNB Received: %s (fees %s)\n",
+		c.display.fmtQty("", result.TotalTransferred), c.display.fmtQty("", result.TotalServiceCharge))
+}
+
+// handleCoverage runs the protection coverage check shared by the spot and futures CLIs
+func handleCoverage(w io.Writer, display *displayFormat, checker service.ProtectionCoverageChecker) error {
+	if checker == nil {
+		return fmt.Errorf("protection coverage check is not available")
+	}
+
+	report, err := checker.CheckCoverage()
+	if err != nil {
+		return err
+	}
+
+	formatCoverageReport(w, display, report)
+	return nil
+}
+
+// formatCoverageReport formats and displays stop protection per exposure and a summary line
+func formatCoverageReport(w io.Writer, display *displayFormat, report *service.CoverageReport) {
+	if len(report.Exposures) == 0 {
+		fmt.Fprintln(w, "No open positions or holdings")
+		return
+	}
+
+	fmt.Fprintln(w, "-------------------------------------------")
+	fmt.Fprintln(w, "Protection Coverage:")
+	fmt.Fprintln(w, "-------------------------------------------")
+	for _, exposure := range report.Exposures {
+		fmt.Fprintf(w, "%s\n", exposure.Label())
+		symbol := exposure.Symbol
+		fmt.Fprintf(w, "  Quantity:     %s @ %s\n", display.fmtQty(symbol, exposure.Quantity), display.fmtPrice(symbol, exposure.Price))
+		fmt.Fprintf(w, "  Protected:    %s (%s, %d local, %d native)\n",
+			display.fmtQty(symbol, exposure.ProtectedQty), display.fmtPercent(exposure.CoveragePct(), 1), exposure.LocalStops, exposure.NativeStops)
+		fmt.Fprintf(w, "  Unprotected:  %s\n", display.fmtQty(symbol, exposure.UnprotectedQty))
+		if exposure.StopPrice > 0 {
+			fmt.Fprintf(w, "  Stop:         %s (%s away)\n", display.fmtPrice(symbol, exposure.StopPrice), display.fmtPercent(exposure.StopDistancePct, 2))
+		} else {
+			fmt.Fprintln(w, "  Stop:         none")
+		}
+		takeProfit := "no"
+		if exposure.HasTakeProfit {
+			takeProfit = "yes"
+		}
+		fmt.Fprintf(w, "  Take Profit:  %s\n", takeProfit)
+	}
+	fmt.Fprintln(w, "-------------------------------------------")
+
+	if len(report.Unprotected) > 0 {
+		fmt.Fprintf(w, "WARNING: %s of notional protected; fully unprotected: %s\n",
+			display.fmtPercent(report.CoveragePct, 1), strings.Join(report.Unprotected, ", "))
+	} else {
+		fmt.Fprintf(w, "Summary: %s of notional protected, no fully unprotected exposure\n", display.fmtPercent(report.CoveragePct, 1))
+	}
+}
@@ -2,23 +2,38 @@ package cli
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"binance-trader/internal/api"
+	"binance-trader/internal/config"
 	"binance-trader/internal/repository"
 	"binance-trader/internal/service"
+	"binance-trader/pkg/metrics"
 )
 
 // mockTradingService is a mock implementation of TradingService
 type mockTradingService struct {
-	placeMarketBuyOrderFunc  func(symbol string, quantity float64) (*api.Order, error)
-	placeMarketSellOrderFunc func(symbol string, quantity float64) (*api.Order, error)
-	placeLimitSellOrderFunc  func(symbol string, price, quantity float64) (*api.Order, error)
-	cancelOrderFunc          func(orderID int64) error
-	getOrderStatusFunc       func(orderID int64) (*service.OrderStatus, error)
-	getActiveOrdersFunc      func() ([]*api.Order, error)
+	placeMarketBuyOrderFunc        func(symbol string, quantity float64) (*api.Order, error)
+	placeMarketBuyOrderByQuoteFunc func(symbol string, quoteAmount float64) (*api.Order, error)
+	placeMarketSellOrderFunc       func(symbol string, quantity float64) (*api.Order, error)
+	placeLimitSellOrderFunc        func(symbol string, price, quantity float64) (*api.Order, error)
+	placeLimitBuyOrderFunc         func(symbol string, price, quantity float64) (*api.Order, error)
+	placeOCOOrderFunc              func(symbol string, side api.OrderSide, quantity, price, stopPrice, stopLimitPrice float64) (*api.OCOResponse, error)
+	cancelOCOOrderFunc             func(symbol string, orderListID int64) error
+	cancelOrderFunc                func(orderID int64) error
+	cancelAllOrdersFunc            func(symbol string) (int, error)
+	getOrderStatusFunc             func(orderID int64) (*service.OrderStatus, error)
+	getActiveOrdersFunc            func() ([]*api.Order, error)
+	getAllBalancesFunc             func() ([]api.Balance, error)
+	getOrderLifecycleFunc          func(symbol string, orderID int64) (*service.OrderLifecycle, error)
+	executeTWAPFunc                func(ctx context.Context, symbol string, side api.OrderSide, totalQty float64, duration time.Duration, slices int) (*service.TWAPExecution, error)
 }
 
 func (m *mockTradingService) PlaceMarketBuyOrder(symbol string, quantity float64) (*api.Order, error) {
@@ -28,6 +43,13 @@ func (m *mockTradingService) PlaceMarketBuyOrder(symbol string, quantity float64
 	return nil, nil
 }
 
+func (m *mockTradingService) PlaceMarketBuyOrderByQuote(symbol string, quoteAmount float64) (*api.Order, error) {
+	if m.placeMarketBuyOrderByQuoteFunc != nil {
+		return m.placeMarketBuyOrderByQuoteFunc(symbol, quoteAmount)
+	}
+	return nil, nil
+}
+
 func (m *mockTradingService) PlaceMarketSellOrder(symbol string, quantity float64) (*api.Order, error) {
 	if m.placeMarketSellOrderFunc != nil {
 		return m.placeMarketSellOrderFunc(symbol, quantity)
@@ -42,6 +64,27 @@ func (m *mockTradingService) PlaceLimitSellOrder(symbol string, price, quantity
 	return nil, nil
 }
 
+func (m *mockTradingService) PlaceLimitBuyOrder(symbol string, price, quantity float64) (*api.Order, error) {
+	if m.placeLimitBuyOrderFunc != nil {
+		return m.placeLimitBuyOrderFunc(symbol, price, quantity)
+	}
+	return nil, nil
+}
+
+func (m *mockTradingService) PlaceOCOOrder(symbol string, side api.OrderSide, quantity, price, stopPrice, stopLimitPrice float64) (*api.OCOResponse, error) {
+	if m.placeOCOOrderFunc != nil {
+		return m.placeOCOOrderFunc(symbol, side, quantity, price, stopPrice, stopLimitPrice)
+	}
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockTradingService) CancelOCOOrder(symbol string, orderListID int64) error {
+	if m.cancelOCOOrderFunc != nil {
+		return m.cancelOCOOrderFunc(symbol, orderListID)
+	}
+	return nil
+}
+
 func (m *mockTradingService) CancelOrder(orderID int64) error {
 	if m.cancelOrderFunc != nil {
 		return m.cancelOrderFunc(orderID)
@@ -49,6 +92,13 @@ func (m *mockTradingService) CancelOrder(orderID int64) error {
 	return nil
 }
 
+func (m *mockTradingService) CancelAllOrders(symbol string) (int, error) {
+	if m.cancelAllOrdersFunc != nil {
+		return m.cancelAllOrdersFunc(symbol)
+	}
+	return 0, nil
+}
+
 func (m *mockTradingService) GetOrderStatus(orderID int64) (*service.OrderStatus, error) {
 	if m.getOrderStatusFunc != nil {
 		return m.getOrderStatusFunc(orderID)
@@ -63,12 +113,57 @@ func (m *mockTradingService) GetActiveOrders() ([]*api.Order, error) {
 	return nil, nil
 }
 
+func (m *mockTradingService) GetAllBalances() ([]api.Balance, error) {
+	if m.getAllBalancesFunc != nil {
+		return m.getAllBalancesFunc()
+	}
+	return nil, nil
+}
+
+func (m *mockTradingService) GetOrderLifecycle(symbol string, orderID int64) (*service.OrderLifecycle, error) {
+	if m.getOrderLifecycleFunc != nil {
+		return m.getOrderLifecycleFunc(symbol, orderID)
+	}
+	return nil, nil
+}
+
+func (m *mockTradingService) SetSymbolGuard(guard service.SymbolFailureGuard) {}
+
+func (m *mockTradingService) SetMetrics(sink *metrics.Sink) {}
+
+func (m *mockTradingService) SubmitOrderIntent(intent *service.OrderIntent) (*api.Order, error) {
+	return nil, nil
+}
+
+func (m *mockTradingService) SetReplayProtection(protection service.ReplayProtection) {}
+
+func (m *mockTradingService) SetUserDataStream(stream api.UserDataStream) error {
+	return nil
+}
+
+func (m *mockTradingService) SubscribeOrderUpdates(handler func(*api.Order)) (func(), error) {
+	return func() {}, nil
+}
+
+func (m *mockTradingService) SetSlippageProtection(marketData service.MarketDataService, maxSlippagePercent float64) {
+}
+
+func (m *mockTradingService) SetSymbolFilter(filter *service.SymbolFilter) {}
+
+func (m *mockTradingService) ExecuteTWAP(ctx context.Context, symbol string, side api.OrderSide, totalQty float64, duration time.Duration, slices int) (*service.TWAPExecution, error) {
+	if m.executeTWAPFunc != nil {
+		return m.executeTWAPFunc(ctx, symbol, side, totalQty, duration, slices)
+	}
+	return nil, nil
+}
+
 // mockMarketDataService is a mock implementation of MarketDataService
 type mockMarketDataService struct {
 	getCurrentPriceFunc     func(symbol string) (float64, error)
 	getHistoricalDataFunc   func(symbol string, interval string, limit int) ([]*api.Kline, error)
 	subscribeToPriceFunc    func(symbol string, callback func(float64)) error
 	getVolumeFunc           func(symbol string, timeWindow time.Duration) (float64, error)
+	getOrderBookFunc        func(symbol string, limit int) (*api.OrderBook, error)
 }
 
 func (m *mockMarketDataService) GetCurrentPrice(symbol string) (float64, error) {
@@ -92,6 +187,27 @@ func (m *mockMarketDataService) SubscribeToPrice(symbol string, callback func(fl
 	return nil
 }
 
+func (m *mockMarketDataService) GetBestBidAsk(symbol string) (*service.BestBidAsk, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockMarketDataService) SetBookTickerCache(cache service.BookTickerCache) {}
+
+func (m *mockMarketDataService) GetOrderBook(symbol string, limit int) (*api.OrderBook, error) {
+	if m.getOrderBookFunc != nil {
+		return m.getOrderBookFunc(symbol, limit)
+	}
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockMarketDataService) SetPriceStream(stream service.PriceStream) {}
+
+func (m *mockMarketDataService) SubscribePriceFeed(ctx context.Context, symbol string) (<-chan float64, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockMarketDataService) ConnectedStreams() []string { return nil }
+
 func (m *mockMarketDataService) GetVolume(symbol string, timeWindow time.Duration) (float64, error) {
 	if m.getVolumeFunc != nil {
 		return m.getVolumeFunc(symbol, timeWindow)
@@ -115,6 +231,7 @@ func (m *mockLogger) LogFuturesOrderEvent(eventType string, orderID int64, symbo
 func (m *mockLogger) LogLiquidationEvent(symbol string, positionSide string, liquidationPrice float64, lossAmount float64, reason string, fields map[string]interface{}) {}
 func (m *mockLogger) LogFundingRateSettlement(symbol string, fundingFee float64, fundingRate float64, positionSize float64, fields map[string]interface{}) {}
 func (m *mockLogger) SetTradingType(tradingType string)                                    {}
+func (m *mockLogger) Close() error                                                         { return nil }
 
 // TestParseCommand tests command parsing
 func TestParseCommand(t *testing.T) {
@@ -374,6 +491,34 @@ func TestFormatOrderList(t *testing.T) {
 			t.Errorf("formatOrderList() should contain second symbol")
 		}
 	})
+	
+	t.Run("groups OCO legs", func(t *testing.T) {
+		var buf bytes.Buffer
+		cli.writer = &buf
+		
+		orders := []*api.Order{
+			{OrderID: 101, Symbol: "BTCUSDT", Side: api.OrderSideSell, Type: "STOP_LOSS_LIMIT", Status: api.OrderStatusNew, OrderListID: 7},
+			{OrderID: 200, Symbol: "ETHUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeLimit, Status: api.OrderStatusNew, OrderListID: -1},
+			{OrderID: 102, Symbol: "BTCUSDT", Side: api.OrderSideSell, Type: "LIMIT_MAKER", Status: api.OrderStatusNew, OrderListID: 7},
+		}
+		
+		cli.formatOrderList(orders)
+		
+		output := buf.String()
+		header := strings.Index(output, "[OCO] Order List 7 (2 legs")
+		first := strings.Index(output, "[1] Order ID: 101 [OCO 7]")
+		second := strings.Index(output, "[2] Order ID: 102 [OCO 7]")
+		standalone := strings.Index(output, "[3] Order ID: 200\n")
+		if header < 0 || first < 0 || second < 0 || standalone < 0 {
+			t.Fatalf("formatOrderList() should group and mark the OCO legs, got:\n%s", output)
+		}
+		if !(header < first && first < second && second < standalone) {
+			t.Errorf("formatOrderList() should list the OCO legs together, got:\n%s", output)
+		}
+		if strings.Count(output, "[OCO]") != 1 {
+			t.Errorf("formatOrderList() should show one OCO header, got:\n%s", output)
+		}
+	})
 }
 
 // TestFormatKlines tests kline data formatting
@@ -469,6 +614,291 @@ func TestHandlePrice(t *testing.T) {
 	})
 }
 
+func TestHandleBalance(t *testing.T) {
+	mockTrading := &mockTradingService{
+		getAllBalancesFunc: func() ([]api.Balance, error) {
+			return []api.Balance{{Asset: "BTC", Free: 0.5, Locked: 0.25}, {Asset: "USDT", Free: 1200}}, nil
+		},
+	}
+	cli := NewCLI(mockTrading, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+
+	t.Run("all holdings", func(t *testing.T) {
+		var buf bytes.Buffer
+		cli.writer = &buf
+		if err := cli.handleBalance(nil); err != nil {
+			t.Fatalf("handleBalance() unexpected error: %v", err)
+		}
+		for _, want := range []string{"BTC", "0.50000000", "0.25000000", "0.75000000", "USDT", "1200.00000000"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("handleBalance() output missing %q:\n%s", want, buf.String())
+			}
+		}
+	})
+
+	t.Run("balances command", func(t *testing.T) {
+		var buf bytes.Buffer
+		cli.writer = &buf
+		if err := cli.handleBalances(nil); err != nil {
+			t.Fatalf("handleBalances() unexpected error: %v", err)
+		}
+		// The larger total comes first
+		output := buf.String()
+		if usdt, btc := strings.Index(output, "USDT"), strings.Index(output, "BTC"); usdt < 0 || btc < 0 || usdt > btc {
+			t.Errorf("handleBalances() should list USDT before BTC:\n%s", output)
+		}
+		if err := cli.handleBalances([]string{"BTC"}); !errors.Is(err, ErrUsage) {
+			t.Errorf("handleBalances(BTC) error = %v, want a usage error", err)
+		}
+	})
+
+	t.Run("one asset", func(t *testing.T) {
+		var buf bytes.Buffer
+		cli.writer = &buf
+		if err := cli.handleBalance([]string{"btc"}); err != nil {
+			t.Fatalf("handleBalance() unexpected error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "Balance of BTC") || !strings.Contains(buf.String(), "Total:  0.75000000") || strings.Contains(buf.String(), "USDT") {
+			t.Errorf("handleBalance(btc) output:\n%s", buf.String())
+		}
+	})
+
+	t.Run("asset not held", func(t *testing.T) {
+		var buf bytes.Buffer
+		cli.writer = &buf
+		if err := cli.handleBalance([]string{"ETH"}); err != nil {
+			t.Fatalf("handleBalance() unexpected error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "Free:   0.00000000") {
+			t.Errorf("handleBalance(ETH) output:\n%s", buf.String())
+		}
+	})
+
+	t.Run("account error", func(t *testing.T) {
+		mockTrading.getAllBalancesFunc = func() ([]api.Balance, error) {
+			return nil, fmt.Errorf("API connection failed")
+		}
+		if err := cli.handleBalance(nil); err == nil || !strings.Contains(err.Error(), "API connection failed") {
+			t.Errorf("handleBalance() error = %v, want the account error", err)
+		}
+	})
+}
+
+type mockPortfolioService struct {
+	portfolio *service.Portfolio
+}
+
+func (m *mockPortfolioService) GetPortfolio() (*service.Portfolio, error) {
+	return m.portfolio, nil
+}
+
+func TestHandlePortfolio(t *testing.T) {
+	cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+	var buf bytes.Buffer
+	cli.writer = &buf
+
+	if err := cli.handlePortfolio(nil); err == nil {
+		t.Error("handlePortfolio() without a portfolio service should fail")
+	}
+
+	cli.SetPortfolioService(&mockPortfolioService{portfolio: &service.Portfolio{
+		Holdings: []*service.PortfolioHolding{
+			{Asset: "BTC", Free: 0.1, Locked: 0.1, Priced: true, Price: 50000, Value: 10000, Route: "BTCUSDT"},
+			{Asset: "USDT", Free: 500, Priced: true, Price: 1, Value: 500},
+			{Asset: "NOPE", Free: 3},
+		},
+		TotalValue: 10500,
+		Unpriced:   1,
+	}})
+	if err := cli.handlePortfolio(nil); err != nil {
+		t.Fatalf("handlePortfolio() error = %v", err)
+	}
+	output := buf.String()
+	for _, want := range []string{"Value (USDT)", "BTC", "50000.00000000", "10000.00000000", "NOPE", "unpriced", "Total: 10500.00000000 USDT (1 unpriced, not included)"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("handlePortfolio() output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+// TestHandleOrderBook tests the orderbook command handler
+func TestHandleOrderBook(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantLimit int
+		wantOut   []string
+		wantErr   string
+	}{
+		{name: "default limit", args: []string{"btcusdt"}, wantLimit: 10, wantOut: []string{"Order Book: BTCUSDT (top 10)", "Best Bid:", "Best Ask:", "Spread:", "of mid"}},
+		{name: "explicit limit", args: []string{"BTCUSDT", "20"}, wantLimit: 20, wantOut: []string{"(top 20)"}},
+		{name: "limit the exchange rejects", args: []string{"BTCUSDT", "15"}, wantErr: "must be one of 5, 10, 20, 50, 100, 500, 1000, 5000"},
+		{name: "invalid limit", args: []string{"BTCUSDT", "abc"}, wantErr: "limit"},
+		{name: "missing symbol", args: []string{}, wantErr: "orderbook <symbol> [limit]"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotSymbol string
+			var gotLimit int
+			mockMarket := &mockMarketDataService{
+				getOrderBookFunc: func(symbol string, limit int) (*api.OrderBook, error) {
+					gotSymbol, gotLimit = symbol, limit
+					return &api.OrderBook{
+						Symbol: symbol,
+						Bids:   []api.OrderBookLevel{{Price: 50000, Qty: 0.5}, {Price: 49990, Qty: 1}},
+						Asks:   []api.OrderBookLevel{{Price: 50010, Qty: 0.8}},
+					}, nil
+				},
+			}
+			cli := NewCLI(&mockTradingService{}, mockMarket, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+			
+			var buf bytes.Buffer
+			cli.writer = &buf
+			
+			err := cli.handleOrderBook(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("handleOrderBook(%v) error = %v, want %q", tt.args, err, tt.wantErr)
+				}
+				if gotSymbol != "" {
+					t.Errorf("handleOrderBook(%v) fetched the order book", tt.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("handleOrderBook() unexpected error: %v", err)
+			}
+			if gotSymbol != "BTCUSDT" || gotLimit != tt.wantLimit {
+				t.Errorf("handleOrderBook() fetched %s limit %d, want BTCUSDT limit %d", gotSymbol, gotLimit, tt.wantLimit)
+			}
+			output := buf.String()
+			for _, want := range tt.wantOut {
+				if !strings.Contains(output, want) {
+					t.Errorf("handleOrderBook() output missing %q:\n%s", want, output)
+				}
+			}
+		})
+	}
+	
+	t.Run("one side empty", func(t *testing.T) {
+		mockMarket := &mockMarketDataService{
+			getOrderBookFunc: func(symbol string, limit int) (*api.OrderBook, error) {
+				return &api.OrderBook{Symbol: symbol, Bids: []api.OrderBookLevel{{Price: 50000, Qty: 1}}}, nil
+			},
+		}
+		cli := NewCLI(&mockTradingService{}, mockMarket, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		
+		var buf bytes.Buffer
+		cli.writer = &buf
+		
+		if err := cli.handleOrderBook([]string{"BTCUSDT", "5"}); err != nil {
+			t.Fatalf("handleOrderBook() unexpected error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "n/a (one side is empty)") {
+			t.Errorf("handleOrderBook() output = %q", buf.String())
+		}
+	})
+}
+
+// TestHandleDepth tests the depth command handler
+func TestHandleDepth(t *testing.T) {
+	var gotLimit int
+	mockMarket := &mockMarketDataService{
+		getOrderBookFunc: func(symbol string, limit int) (*api.OrderBook, error) {
+			gotLimit = limit
+			return &api.OrderBook{
+				Symbol: symbol,
+				Bids:   []api.OrderBookLevel{{Price: 50000, Qty: 0.5}, {Price: 49990, Qty: 1}, {Price: 49980, Qty: 2}},
+				Asks:   []api.OrderBookLevel{{Price: 50010, Qty: 0.25}, {Price: 50020, Qty: 0.75}},
+			}, nil
+		},
+	}
+	cli := NewCLI(&mockTradingService{}, mockMarket, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+	
+	var buf bytes.Buffer
+	cli.writer = &buf
+	
+	if err := cli.handleDepth([]string{"btcusdt", "5"}); err != nil {
+		t.Fatalf("handleDepth() unexpected error: %v", err)
+	}
+	if gotLimit != 5 {
+		t.Errorf("handleDepth() fetched limit %d, want 5", gotLimit)
+	}
+	
+	output := buf.String()
+	for _, want := range []string{"Depth: BTCUSDT (top 5)", "Cumulative", "Spread:"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("handleDepth() output missing %q:\n%s", want, output)
+		}
+	}
+	
+	// Cumulative quantities grow away from the spread on both sides
+	var cumulative []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 4 && (fields[0] == "ASK" || fields[0] == "BID") {
+			cumulative = append(cumulative, fields[0]+" "+fields[3])
+		}
+	}
+	want := []string{"ASK 1.00000000", "ASK 0.25000000", "BID 0.50000000", "BID 1.50000000", "BID 3.50000000"}
+	if strings.Join(cumulative, ",") != strings.Join(want, ",") {
+		t.Errorf("handleDepth() cumulative = %v, want %v", cumulative, want)
+	}
+	
+	if err := cli.handleDepth(nil); err == nil || !strings.Contains(err.Error(), "depth <symbol> [limit]") {
+		t.Errorf("handleDepth() without a symbol error = %v", err)
+	}
+}
+
+func TestHandleTWAP(t *testing.T) {
+	var quantities []float64
+	mockTrading := &mockTradingService{
+		placeMarketBuyOrderFunc: func(symbol string, quantity float64) (*api.Order, error) {
+			quantities = append(quantities, quantity)
+			price := 50000 + 200*float64(len(quantities)-1)
+			return &api.Order{
+				OrderID:             int64(len(quantities)),
+				Symbol:              symbol,
+				Side:                api.OrderSideBuy,
+				Status:              api.OrderStatusFilled,
+				OrigQty:             quantity,
+				ExecutedQty:         quantity,
+				CummulativeQuoteQty: quantity * price,
+			}, nil
+		},
+	}
+	cli := NewCLI(mockTrading, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+	
+	var buf bytes.Buffer
+	cli.writer = &buf
+	
+	if err := cli.handleTWAP([]string{"btcusdt", "buy", "1", "2", "0"}); err != nil {
+		t.Fatalf("handleTWAP() unexpected error: %v", err)
+	}
+	if len(quantities) != 2 || quantities[0] != 0.5 || quantities[1] != 0.5 {
+		t.Errorf("handleTWAP() child orders = %v, want two of 0.5", quantities)
+	}
+	for _, want := range []string{"TWAP BUY BTCUSDT: 2 of 2 slices", "Avg Price:", "50100"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("handleTWAP() output missing %q:\n%s", want, buf.String())
+		}
+	}
+	
+	for _, args := range [][]string{
+		{"BTCUSDT", "BUY", "1", "2"},
+		{"BTCUSDT", "HOLD", "1", "2", "30"},
+		{"BTCUSDT", "SELL", "1", "0", "30"},
+		{"BTCUSDT", "SELL", "1", "2", "-30"},
+	} {
+		if err := cli.handleTWAP(args); err == nil {
+			t.Errorf("handleTWAP(%v) expected an error", args)
+		}
+	}
+	if len(quantities) != 2 {
+		t.Errorf("handleTWAP() placed %d child orders after invalid input, want 2", len(quantities))
+	}
+}
+
 // TestHandleBuy tests the buy command handler
 func TestHandleBuy(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
@@ -501,6 +931,76 @@ func TestHandleBuy(t *testing.T) {
 		}
 	})
 	
+	t.Run("quote amount", func(t *testing.T) {
+		var gotQuote float64
+		mockTrading := &mockTradingService{
+			placeMarketBuyOrderByQuoteFunc: func(symbol string, quoteAmount float64) (*api.Order, error) {
+				gotQuote = quoteAmount
+				return &api.Order{
+					OrderID:             12348,
+					Symbol:              symbol,
+					Side:                api.OrderSideBuy,
+					Type:                api.OrderTypeMarket,
+					Status:              api.OrderStatusFilled,
+					ExecutedQty:         0.002,
+					CummulativeQuoteQty: quoteAmount,
+				}, nil
+			},
+		}
+		
+		cli := NewCLI(mockTrading, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		
+		var buf bytes.Buffer
+		cli.writer = &buf
+		
+		if err := cli.handleBuy([]string{"BTCUSDT", "--quote", "100"}); err != nil {
+			t.Errorf("handleBuy() unexpected error: %v", err)
+		}
+		if gotQuote != 100 {
+			t.Errorf("handleBuy() quote amount = %v, want 100", gotQuote)
+		}
+		if !strings.Contains(buf.String(), "12348") {
+			t.Errorf("handleBuy() output should contain order ID")
+		}
+		
+		if err := cli.handleBuy([]string{"BTCUSDT", "--quote"}); err == nil {
+			t.Errorf("handleBuy() expected error for missing quote amount")
+		}
+	})
+	
+	t.Run("maintenance", func(t *testing.T) {
+		calls := 0
+		serverErr := fmt.Errorf("failed to place order: %w", errors.New("HTTP server error: 503"))
+		mockTrading := &mockTradingService{
+			placeMarketBuyOrderFunc: func(symbol string, quantity float64) (*api.Order, error) {
+				calls++
+				return nil, serverErr
+			},
+		}
+		monitor := &recordingMaintenanceMonitor{}
+		
+		cli := NewCLI(mockTrading, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		cli.SetMaintenanceMonitor(monitor)
+		
+		// A failed manual order counts towards detecting maintenance
+		if err := cli.handleBuy([]string{"BTCUSDT", "0.001"}); err == nil {
+			t.Fatal("handleBuy() expected the server error")
+		}
+		if len(monitor.results) != 1 || monitor.results[0] != serverErr {
+			t.Errorf("maintenance monitor results = %v, want the server error", monitor.results)
+		}
+		
+		// Once maintenance is detected, manual orders are refused before reaching the exchange
+		monitor.inMaintenance = true
+		err := cli.handleBuy([]string{"BTCUSDT", "0.001"})
+		if err == nil || !strings.Contains(err.Error(), "exchange is under maintenance") {
+			t.Errorf("handleBuy() during maintenance error = %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("orders sent = %d, want 1", calls)
+		}
+	})
+	
 	t.Run("missing arguments", func(t *testing.T) {
 		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
 		
@@ -520,6 +1020,21 @@ func TestHandleBuy(t *testing.T) {
 	})
 }
 
+// recordingMaintenanceMonitor records the results reported to it and reports maintenance on demand
+type recordingMaintenanceMonitor struct {
+	service.MaintenanceMonitor
+	results       []error
+	inMaintenance bool
+}
+
+func (m *recordingMaintenanceMonitor) RecordResult(err error) {
+	m.results = append(m.results, err)
+}
+
+func (m *recordingMaintenanceMonitor) IsInMaintenance() bool {
+	return m.inMaintenance
+}
+
 // TestHandleSell tests the sell command handler
 func TestHandleSell(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
@@ -563,12 +1078,22 @@ func TestHandleSell(t *testing.T) {
 	})
 }
 
-// TestHandleCancel tests the cancel command handler
-func TestHandleCancel(t *testing.T) {
+// TestHandleSellMarket tests the sell-market command handler
+func TestHandleSellMarket(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
+		var gotSymbol string
+		var gotQuantity float64
 		mockTrading := &mockTradingService{
-			cancelOrderFunc: func(orderID int64) error {
-				return nil
+			placeMarketSellOrderFunc: func(symbol string, quantity float64) (*api.Order, error) {
+				gotSymbol, gotQuantity = symbol, quantity
+				return &api.Order{
+					OrderID: 12347,
+					Symbol:  symbol,
+					Side:    api.OrderSideSell,
+					Type:    api.OrderTypeMarket,
+					Status:  api.OrderStatusFilled,
+					OrigQty: quantity,
+				}, nil
 			},
 		}
 		
@@ -577,32 +1102,333 @@ func TestHandleCancel(t *testing.T) {
 		var buf bytes.Buffer
 		cli.writer = &buf
 		
-		err := cli.handleCancel([]string{"12345"})
-		if err != nil {
-			t.Errorf("handleCancel() unexpected error: %v", err)
+		if err := cli.handleSellMarket([]string{"btcusdt", "0.001"}); err != nil {
+			t.Errorf("handleSellMarket() unexpected error: %v", err)
 		}
-		
-		output := buf.String()
-		if !strings.Contains(output, "canceled successfully") {
-			t.Errorf("handleCancel() output should contain success message")
+		if gotSymbol != "BTCUSDT" || gotQuantity != 0.001 {
+			t.Errorf("handleSellMarket() sold %v %s, want 0.001 BTCUSDT", gotQuantity, gotSymbol)
+		}
+		if !strings.Contains(buf.String(), "12347") {
+			t.Errorf("handleSellMarket() output should contain order ID")
 		}
 	})
 	
-	t.Run("invalid order ID", func(t *testing.T) {
-		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
-		
-		err := cli.handleCancel([]string{"invalid"})
-		if err == nil {
+	t.Run("insufficient balance", func(t *testing.T) {
+		mockTrading := &mockTradingService{
+			placeMarketSellOrderFunc: func(symbol string, quantity float64) (*api.Order, error) {
+				return nil, fmt.Errorf("quantity 5 exceeds free balance 0.5 for asset BTC")
+			},
+		}
+		cli := NewCLI(mockTrading, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		
+		err := cli.handleSellMarket([]string{"BTCUSDT", "5"})
+		if err == nil || !strings.Contains(err.Error(), "exceeds free balance") {
+			t.Errorf("handleSellMarket() error = %v, want the balance error", err)
+		}
+	})
+	
+	t.Run("missing arguments", func(t *testing.T) {
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		
+		if err := cli.handleSellMarket([]string{"BTCUSDT"}); err == nil {
+			t.Errorf("handleSellMarket() expected error for missing argument")
+		}
+	})
+}
+
+// TestHandleLimitBuy tests the limitbuy command handler
+func TestHandleLimitBuy(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var gotSymbol string
+		var gotPrice, gotQuantity float64
+		mockTrading := &mockTradingService{
+			placeLimitBuyOrderFunc: func(symbol string, price, quantity float64) (*api.Order, error) {
+				gotSymbol, gotPrice, gotQuantity = symbol, price, quantity
+				return &api.Order{
+					OrderID: 12345,
+					Symbol:  symbol,
+					Side:    api.OrderSideBuy,
+					Type:    api.OrderTypeLimit,
+					Status:  api.OrderStatusNew,
+					Price:   price,
+					OrigQty: quantity,
+				}, nil
+			},
+		}
+		
+		cli := NewCLI(mockTrading, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		
+		var buf bytes.Buffer
+		cli.writer = &buf
+		
+		err := cli.handleLimitBuy([]string{"btcusdt", "45000", "0.001"})
+		if err != nil {
+			t.Errorf("handleLimitBuy() unexpected error: %v", err)
+		}
+		
+		if gotSymbol != "BTCUSDT" || gotPrice != 45000 || gotQuantity != 0.001 {
+			t.Errorf("handleLimitBuy() placed %s %v @ %v", gotSymbol, gotQuantity, gotPrice)
+		}
+		
+		output := buf.String()
+		if !strings.Contains(output, "12345") {
+			t.Errorf("handleLimitBuy() output should contain order ID")
+		}
+	})
+	
+	t.Run("missing arguments", func(t *testing.T) {
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		
+		err := cli.handleLimitBuy([]string{"BTCUSDT", "45000"})
+		if err == nil {
+			t.Errorf("handleLimitBuy() expected error for missing argument")
+		}
+	})
+	
+	t.Run("invalid price", func(t *testing.T) {
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		
+		err := cli.handleLimitBuy([]string{"BTCUSDT", "0", "0.001"})
+		if err == nil {
+			t.Errorf("handleLimitBuy() expected error for invalid price")
+		}
+	})
+	
+	t.Run("service error", func(t *testing.T) {
+		mockTrading := &mockTradingService{
+			placeLimitBuyOrderFunc: func(symbol string, price, quantity float64) (*api.Order, error) {
+				return nil, fmt.Errorf("insufficient balance")
+			},
+		}
+		cli := NewCLI(mockTrading, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		
+		err := cli.handleLimitBuy([]string{"BTCUSDT", "45000", "0.001"})
+		if err == nil || !strings.Contains(err.Error(), "insufficient balance") {
+			t.Errorf("handleLimitBuy() error = %v, want the service error", err)
+		}
+	})
+}
+
+// TestHandleCancel tests the cancel command handler
+func TestHandleCancel(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockTrading := &mockTradingService{
+			cancelOrderFunc: func(orderID int64) error {
+				return nil
+			},
+		}
+		
+		cli := NewCLI(mockTrading, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		
+		var buf bytes.Buffer
+		cli.writer = &buf
+		
+		err := cli.handleCancel([]string{"12345"})
+		if err != nil {
+			t.Errorf("handleCancel() unexpected error: %v", err)
+		}
+		
+		output := buf.String()
+		if !strings.Contains(output, "canceled successfully") {
+			t.Errorf("handleCancel() output should contain success message")
+		}
+	})
+	
+	t.Run("invalid order ID", func(t *testing.T) {
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		
+		err := cli.handleCancel([]string{"invalid"})
+		if err == nil {
 			t.Errorf("handleCancel() expected error for invalid order ID")
 		}
 	})
 }
 
+// TestHandleCancelAll tests the cancelall command handler
+func TestHandleCancelAll(t *testing.T) {
+	var gotSymbol string
+	var result error
+	mockTrading := &mockTradingService{
+		cancelAllOrdersFunc: func(symbol string) (int, error) {
+			gotSymbol = symbol
+			return 3, result
+		},
+	}
+	cli := NewCLI(mockTrading, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+	var buf bytes.Buffer
+	cli.writer = &buf
+
+	if err := cli.executeCommand(&Command{Name: "cancelall", Args: []string{"btcusdt"}}); err != nil {
+		t.Fatalf("cancelall unexpected error: %v", err)
+	}
+	if gotSymbol != "BTCUSDT" || !strings.Contains(buf.String(), "Cancelled 3 order(s) of BTCUSDT") {
+		t.Errorf("cancelall cancelled %q, output: %s", gotSymbol, buf.String())
+	}
+
+	// A partial failure names the orders that were not cancelled
+	result = &api.BulkCancelError{Symbol: "BTCUSDT", Cancelled: 3,
+		Failures: []api.BulkCancelFailure{{OrderID: 4, Code: -2011, Message: "Unknown order sent."}}}
+	err := cli.executeCommand(&Command{Name: "cancelall", Args: []string{"BTCUSDT"}})
+	if err == nil || !strings.Contains(err.Error(), "cancelled 3 order(s) of BTCUSDT, 1 failed: order 4") {
+		t.Errorf("cancelall partial failure error = %v", err)
+	}
+
+	if err := cli.executeCommand(&Command{Name: "cancelall"}); !errors.Is(err, ErrUsage) {
+		t.Errorf("cancelall without a symbol error = %v, want a usage error", err)
+	}
+}
+
+// TestHandleOCO tests the oco command handler
+func TestHandleOCO(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		wantStopLimit float64
+		wantStopLeg   string
+		wantErr       bool
+	}{
+		{name: "stop market leg", args: []string{"btcusdt", "0.01", "48000", "55000"}, wantStopLeg: "then market"},
+		{name: "stop limit leg", args: []string{"BTCUSDT", "0.01", "48000", "55000", "47900"}, wantStopLimit: 47900, wantStopLeg: "then limit"},
+		{name: "missing limit price", args: []string{"BTCUSDT", "0.01", "48000"}, wantErr: true},
+		{name: "invalid stop price", args: []string{"BTCUSDT", "0.01", "abc", "55000"}, wantErr: true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotSide api.OrderSide
+			var gotSymbol string
+			var gotQty, gotPrice, gotStop, gotStopLimit float64
+			mockTrading := &mockTradingService{
+				placeOCOOrderFunc: func(symbol string, side api.OrderSide, quantity, price, stopPrice, stopLimitPrice float64) (*api.OCOResponse, error) {
+					gotSymbol, gotSide = symbol, side
+					gotQty, gotPrice, gotStop, gotStopLimit = quantity, price, stopPrice, stopLimitPrice
+					return &api.OCOResponse{OrderList: api.OrderList{OrderListID: 7}, LimitOrderID: 102, StopOrderID: 101}, nil
+				},
+			}
+			cli := NewCLI(mockTrading, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+			
+			var buf bytes.Buffer
+			cli.writer = &buf
+			
+			err := cli.handleOCO(tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("handleOCO(%v) expected an error", tt.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("handleOCO() unexpected error: %v", err)
+			}
+			if gotSymbol != "BTCUSDT" || gotSide != api.OrderSideSell || gotQty != 0.01 || gotPrice != 55000 || gotStop != 48000 || gotStopLimit != tt.wantStopLimit {
+				t.Errorf("handleOCO() placed %s %s %v limit %v stop %v/%v", gotSide, gotSymbol, gotQty, gotPrice, gotStop, gotStopLimit)
+			}
+			output := buf.String()
+			if !strings.Contains(output, "Order List ID:  7") || !strings.Contains(output, "#102") || !strings.Contains(output, tt.wantStopLeg) {
+				t.Errorf("handleOCO() output = %q", output)
+			}
+		})
+	}
+}
+
+// TestHandleCancelOCO tests the cancel-oco command handler
+func TestHandleCancelOCO(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var gotSymbol string
+		var gotListID int64
+		mockTrading := &mockTradingService{
+			cancelOCOOrderFunc: func(symbol string, orderListID int64) error {
+				gotSymbol, gotListID = symbol, orderListID
+				return nil
+			},
+		}
+		
+		cli := NewCLI(mockTrading, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		
+		var buf bytes.Buffer
+		cli.writer = &buf
+		
+		if err := cli.handleCancelOCO([]string{"btcusdt", "7"}); err != nil {
+			t.Errorf("handleCancelOCO() unexpected error: %v", err)
+		}
+		if gotSymbol != "BTCUSDT" || gotListID != 7 {
+			t.Errorf("handleCancelOCO() cancelled %s list %d", gotSymbol, gotListID)
+		}
+		if !strings.Contains(buf.String(), "Order list 7 canceled") {
+			t.Errorf("handleCancelOCO() output = %q", buf.String())
+		}
+	})
+	
+	t.Run("invalid arguments", func(t *testing.T) {
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		
+		for _, args := range [][]string{{"BTCUSDT"}, {"BTCUSDT", "invalid"}, {"BTCUSDT", "-1"}} {
+			if err := cli.handleCancelOCO(args); err == nil {
+				t.Errorf("handleCancelOCO(%v) expected an error", args)
+			}
+		}
+	})
+	
+	t.Run("service error", func(t *testing.T) {
+		mockTrading := &mockTradingService{
+			cancelOCOOrderFunc: func(symbol string, orderListID int64) error {
+				return fmt.Errorf("unknown order list")
+			},
+		}
+		cli := NewCLI(mockTrading, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		
+		if err := cli.handleCancelOCO([]string{"BTCUSDT", "7"}); err == nil || !strings.Contains(err.Error(), "unknown order list") {
+			t.Errorf("handleCancelOCO() error = %v, want the service error", err)
+		}
+	})
+}
+
+// stubSymbolStatusMonitor reports a fixed list of suspended symbols
+type stubSymbolStatusMonitor struct {
+	service.SymbolStatusMonitor
+	suspended []*service.SymbolSuspension
+}
+
+func (m *stubSymbolStatusMonitor) GetSuspendedSymbols() []*service.SymbolSuspension {
+	return m.suspended
+}
+
+func TestHandleStatusListsSuspendedSymbols(t *testing.T) {
+	cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+	cli.SetSymbolStatusMonitor(&stubSymbolStatusMonitor{suspended: []*service.SymbolSuspension{
+		{Symbol: "LUNAUSDT", Status: "BREAK", SuspendedAt: 1714550400000, Orders: 3, Cancelled: 2},
+	}})
+
+	var buf bytes.Buffer
+	cli.writer = &buf
+	if err := cli.handleStatus(nil); err != nil {
+		t.Fatalf("handleStatus() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"Suspended:      1 symbols not trading", "LUNAUSDT", "BREAK", "3 orders, 2 open orders cancelled"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("status output should contain %q:\n%s", want, output)
+		}
+	}
+
+	cli.SetSymbolStatusMonitor(&stubSymbolStatusMonitor{})
+	buf.Reset()
+	if err := cli.handleStatus(nil); err != nil {
+		t.Fatalf("handleStatus() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Suspended:      none") {
+		t.Errorf("status output without suspensions:\n%s", buf.String())
+	}
+}
+
 // mockConditionalOrderService is a mock implementation of ConditionalOrderService
 type mockConditionalOrderService struct {
 	createConditionalOrderFunc       func(request *repository.ConditionalOrderRequest) (*repository.ConditionalOrder, error)
-	cancelConditionalOrderFunc       func(orderID string) error
+	cancelConditionalOrderFunc       func(orderID, symbol string) (*repository.ConditionalOrder, error)
+	cancelAllConditionalOrdersFunc   func(symbol string, reason repository.CancelReason, cancelledBy string) (int, error)
 	getActiveConditionalOrdersFunc   func() ([]*repository.ConditionalOrder, error)
+	getConditionalOrderHistoryFunc   func(startTime, endTime int64) ([]*repository.ConditionalOrder, error)
 }
 
 func (m *mockConditionalOrderService) CreateConditionalOrder(request *repository.ConditionalOrderRequest) (*repository.ConditionalOrder, error) {
@@ -612,13 +1438,24 @@ func (m *mockConditionalOrderService) CreateConditionalOrder(request *repository
 	return nil, nil
 }
 
-func (m *mockConditionalOrderService) CancelConditionalOrder(orderID string) error {
+func (m *mockConditionalOrderService) CancelConditionalOrder(orderID, symbol string) (*repository.ConditionalOrder, error) {
 	if m.cancelConditionalOrderFunc != nil {
-		return m.cancelConditionalOrderFunc(orderID)
+		return m.cancelConditionalOrderFunc(orderID, symbol)
 	}
+	return &repository.ConditionalOrder{OrderID: orderID}, nil
+}
+
+func (m *mockConditionalOrderService) CancelConditionalOrderWithReason(orderID string, reason repository.CancelReason, cancelledBy string) error {
 	return nil
 }
 
+func (m *mockConditionalOrderService) CancelAllConditionalOrders(symbol string, reason repository.CancelReason, cancelledBy string) (int, error) {
+	if m.cancelAllConditionalOrdersFunc != nil {
+		return m.cancelAllConditionalOrdersFunc(symbol, reason, cancelledBy)
+	}
+	return 0, nil
+}
+
 func (m *mockConditionalOrderService) UpdateConditionalOrder(orderID string, updates *service.ConditionalOrderUpdate) error {
 	return nil
 }
@@ -635,6 +1472,9 @@ func (m *mockConditionalOrderService) GetActiveConditionalOrders() ([]*repositor
 }
 
 func (m *mockConditionalOrderService) GetConditionalOrderHistory(startTime, endTime int64) ([]*repository.ConditionalOrder, error) {
+	if m.getConditionalOrderHistoryFunc != nil {
+		return m.getConditionalOrderHistoryFunc(startTime, endTime)
+	}
 	return nil, nil
 }
 
@@ -646,12 +1486,30 @@ func (m *mockConditionalOrderService) StopMonitoring() error {
 	return nil
 }
 
+func (m *mockConditionalOrderService) SetMaintenanceMonitor(monitor service.MaintenanceMonitor) {}
+func (m *mockConditionalOrderService) SetPriceSanityChecker(checker service.PriceSanityChecker) {}
+
+func (m *mockConditionalOrderService) SetMetrics(sink *metrics.Sink) {}
+func (m *mockConditionalOrderService) OnMonitoringGap(callback func(report *service.MonitoringGapReport)) {}
+func (m *mockConditionalOrderService) GetSymbolEvaluationStats() map[string]service.SymbolEvaluationStats {
+	return nil
+}
+func (m *mockConditionalOrderService) SuspendSymbol(symbol string) (int, error) {
+	return 0, nil
+}
+func (m *mockConditionalOrderService) ResumeSymbol(symbol string) (int, error) {
+	return 0, nil
+}
+
 // mockStopLossService is a mock implementation of StopLossService
 type mockStopLossService struct {
-	setStopLossFunc         func(symbol string, position float64, stopPrice float64) (*repository.StopOrder, error)
-	setTakeProfitFunc       func(symbol string, position float64, targetPrice float64) (*repository.StopOrder, error)
-	cancelStopOrderFunc     func(orderID string) error
-	getActiveStopOrdersFunc func(symbol string) ([]*repository.StopOrder, error)
+	setStopLossFunc           func(symbol string, position float64, stopPrice float64) (*repository.StopOrder, error)
+	setTakeProfitFunc         func(symbol string, position float64, targetPrice float64) (*repository.StopOrder, error)
+	cancelStopOrderFunc       func(orderID, symbol string) (*service.CancelledStopOrder, error)
+	getActiveStopOrdersFunc   func(symbol string) ([]*repository.StopOrder, error)
+	setTrailingStopFunc       func(symbol string, position float64, trailPercent float64) (*repository.TrailingStopOrder, error)
+	setATRTrailingStopFunc    func(symbol string, position float64, trail service.ATRTrail) (*repository.TrailingStopOrder, error)
+	setStopLossTakeProfitFunc func(symbol string, position float64, stopPrice, targetPrice float64) (*repository.StopOrderPair, error)
 }
 
 func (m *mockStopLossService) SetStopLoss(symbol string, position float64, stopPrice float64) (*repository.StopOrder, error) {
@@ -668,19 +1526,40 @@ func (m *mockStopLossService) SetTakeProfit(symbol string, position float64, tar
 	return nil, nil
 }
 
+func (m *mockStopLossService) SetTakeProfitLadder(symbol string, position float64, levels []service.TPLevel) ([]*repository.StopOrder, error) {
+	return nil, nil
+}
+
 func (m *mockStopLossService) SetStopLossTakeProfit(symbol string, position float64, stopPrice, targetPrice float64) (*repository.StopOrderPair, error) {
+	if m.setStopLossTakeProfitFunc != nil {
+		return m.setStopLossTakeProfitFunc(symbol, position, stopPrice, targetPrice)
+	}
 	return nil, nil
 }
 
 func (m *mockStopLossService) SetTrailingStop(symbol string, position float64, trailPercent float64) (*repository.TrailingStopOrder, error) {
+	if m.setTrailingStopFunc != nil {
+		return m.setTrailingStopFunc(symbol, position, trailPercent)
+	}
 	return nil, nil
 }
 
-func (m *mockStopLossService) CancelStopOrder(orderID string) error {
+func (m *mockStopLossService) SetATRTrailingStop(symbol string, position float64, trail service.ATRTrail) (*repository.TrailingStopOrder, error) {
+	if m.setATRTrailingStopFunc != nil {
+		return m.setATRTrailingStopFunc(symbol, position, trail)
+	}
+	return nil, nil
+}
+
+func (m *mockStopLossService) SetTrailConfig(cfg *config.StopLossConfig) {}
+
+func (m *mockStopLossService) SetMetrics(sink *metrics.Sink) {}
+
+func (m *mockStopLossService) CancelStopOrder(orderID, symbol string) (*service.CancelledStopOrder, error) {
 	if m.cancelStopOrderFunc != nil {
-		return m.cancelStopOrderFunc(orderID)
+		return m.cancelStopOrderFunc(orderID, symbol)
 	}
-	return nil
+	return &service.CancelledStopOrder{StopOrder: &repository.StopOrder{OrderID: orderID}}, nil
 }
 
 func (m *mockStopLossService) GetActiveStopOrders(symbol string) ([]*repository.StopOrder, error) {
@@ -753,397 +1632,1979 @@ func TestHandleConditionalOrder(t *testing.T) {
 			t.Errorf("handleConditionalOrder() expected error for invalid trigger value")
 		}
 	})
-}
 
-// TestHandleConditionalOrders tests the condorders command handler
-func TestHandleConditionalOrders(t *testing.T) {
-	t.Run("success with orders", func(t *testing.T) {
+	t.Run("ask bid imbalance", func(t *testing.T) {
+		var request *repository.ConditionalOrderRequest
 		mockCondService := &mockConditionalOrderService{
-			getActiveConditionalOrdersFunc: func() ([]*repository.ConditionalOrder, error) {
-				return []*repository.ConditionalOrder{
-					{
-						OrderID:  "cond-1",
-						Symbol:   "BTCUSDT",
-						Side:     api.OrderSideBuy,
-						Type:     api.OrderTypeMarket,
-						Quantity: 0.001,
-						Status:   repository.ConditionalOrderStatusPending,
-						TriggerCondition: &repository.TriggerCondition{
-							Type:     repository.TriggerTypePrice,
-							Operator: repository.OperatorGreaterEqual,
-							Value:    50000,
-						},
-					},
-				}, nil
+			createConditionalOrderFunc: func(req *repository.ConditionalOrderRequest) (*repository.ConditionalOrder, error) {
+				request = req
+				return &repository.ConditionalOrder{OrderID: "cond-imbalance", Symbol: req.Symbol, Side: req.Side, Type: req.Type,
+					Quantity: req.Quantity, Status: repository.ConditionalOrderStatusPending, TriggerCondition: req.TriggerCondition}, nil
 			},
 		}
-
 		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, mockCondService, &mockStopLossService{}, &mockLogger{})
-
 		var buf bytes.Buffer
 		cli.writer = &buf
 
-		err := cli.handleConditionalOrders([]string{})
-		if err != nil {
-			t.Errorf("handleConditionalOrders() unexpected error: %v", err)
+		if err := cli.handleConditionalOrder([]string{"BTCUSDT", "BUY", "0.001", "ask_bid_imbalance", ">=", "0.3"}); err != nil {
+			t.Fatalf("handleConditionalOrder() error = %v", err)
 		}
-
-		output := buf.String()
-		if !strings.Contains(output, "cond-1") {
-			t.Errorf("handleConditionalOrders() output should contain order ID")
+		condition := request.TriggerCondition
+		if condition.Type != repository.TriggerTypeAskBidImbalance || condition.Operator != repository.OperatorGreaterEqual || condition.Value != 0.3 {
+			t.Errorf("trigger condition = %+v, want ASK_BID_IMBALANCE >= 0.3", condition)
 		}
-		if !strings.Contains(output, "BTCUSDT") {
-			t.Errorf("handleConditionalOrders() output should contain symbol")
+		if !strings.Contains(buf.String(), "ASK_BID_IMBALANCE") {
+			t.Errorf("handleConditionalOrder() output should name the trigger type:\n%s", buf.String())
 		}
 	})
 
-	t.Run("empty list", func(t *testing.T) {
+	t.Run("ma crossover", func(t *testing.T) {
+		var request *repository.ConditionalOrderRequest
 		mockCondService := &mockConditionalOrderService{
-			getActiveConditionalOrdersFunc: func() ([]*repository.ConditionalOrder, error) {
-				return []*repository.ConditionalOrder{}, nil
+			createConditionalOrderFunc: func(req *repository.ConditionalOrderRequest) (*repository.ConditionalOrder, error) {
+				request = req
+				return &repository.ConditionalOrder{OrderID: "cond-ma", Symbol: req.Symbol, Side: req.Side, Type: req.Type,
+					Quantity: req.Quantity, Status: repository.ConditionalOrderStatusPending, TriggerCondition: req.TriggerCondition}, nil
 			},
 		}
-
 		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, mockCondService, &mockStopLossService{}, &mockLogger{})
-
 		var buf bytes.Buffer
 		cli.writer = &buf
 
-		err := cli.handleConditionalOrders([]string{})
-		if err != nil {
-			t.Errorf("handleConditionalOrders() unexpected error: %v", err)
+		if err := cli.handleConditionalOrder([]string{"BTCUSDT", "BUY", "0.001", "ma_cross", ">", "9/21@15m"}); err != nil {
+			t.Fatalf("handleConditionalOrder() error = %v", err)
+		}
+		condition := request.TriggerCondition
+		if condition.Type != repository.TriggerTypeMACrossover || condition.Operator != repository.OperatorGreaterThan ||
+			condition.FastPeriod != 9 || condition.SlowPeriod != 21 || condition.Interval != "15m" || condition.Value != 0 {
+			t.Errorf("trigger condition = %+v, want MA_CROSS > 9/21 on 15m", condition)
+		}
+		if !strings.Contains(buf.String(), "MA_CROSS > SMA 9/21 15m") {
+			t.Errorf("handleConditionalOrder() output should describe the crossover:\n%s", buf.String())
 		}
 
-		output := buf.String()
-		if !strings.Contains(output, "No active conditional orders") {
-			t.Errorf("handleConditionalOrders() should show 'No active conditional orders' for empty list")
+		// The interval is optional; the periods are not
+		if err := cli.handleConditionalOrder([]string{"BTCUSDT", "BUY", "0.001", "MA_CROSS", "<", "50/200"}); err != nil {
+			t.Fatalf("handleConditionalOrder() error = %v", err)
+		}
+		if request.TriggerCondition.Interval != "" || request.TriggerCondition.SlowPeriod != 200 {
+			t.Errorf("trigger condition = %+v, want 50/200 on the default interval", request.TriggerCondition)
+		}
+		for _, spec := range []string{"21", "9/x", "a/21@1h"} {
+			if err := cli.handleConditionalOrder([]string{"BTCUSDT", "BUY", "0.001", "MA_CROSS", ">", spec}); err == nil {
+				t.Errorf("handleConditionalOrder() with MA_CROSS value %q should fail", spec)
+			}
 		}
 	})
-}
 
-// TestHandleCancelConditionalOrder tests the cancelcond command handler
-func TestHandleCancelConditionalOrder(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
+	t.Run("volume surge", func(t *testing.T) {
+		var request *repository.ConditionalOrderRequest
 		mockCondService := &mockConditionalOrderService{
-			cancelConditionalOrderFunc: func(orderID string) error {
-				return nil
+			createConditionalOrderFunc: func(req *repository.ConditionalOrderRequest) (*repository.ConditionalOrder, error) {
+				request = req
+				return &repository.ConditionalOrder{OrderID: "cond-surge", Symbol: req.Symbol, Side: req.Side, Type: req.Type,
+					Quantity: req.Quantity, Status: repository.ConditionalOrderStatusPending, TriggerCondition: req.TriggerCondition}, nil
 			},
 		}
-
 		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, mockCondService, &mockStopLossService{}, &mockLogger{})
-
 		var buf bytes.Buffer
 		cli.writer = &buf
 
-		err := cli.handleCancelConditionalOrder([]string{"cond-12345"})
-		if err != nil {
-			t.Errorf("handleCancelConditionalOrder() unexpected error: %v", err)
+		if err := cli.handleConditionalOrder([]string{"BTCUSDT", "BUY", "0.001", "VOLUME_SURGE", ">=", "2.5", "WINDOW", "3600000"}); err != nil {
+			t.Fatalf("handleConditionalOrder() error = %v", err)
 		}
-
-		output := buf.String()
-		if !strings.Contains(output, "canceled successfully") {
-			t.Errorf("handleCancelConditionalOrder() output should contain success message")
+		condition := request.TriggerCondition
+		if condition.Type != repository.TriggerTypeVolumeSurge || condition.Operator != repository.OperatorGreaterEqual ||
+			condition.Value != 2.5 || condition.TimeWindow != time.Hour {
+			t.Errorf("trigger condition = %+v, want VOLUME_SURGE >= 2.5 over 1h", condition)
+		}
+		if !strings.Contains(buf.String(), "VOLUME_SURGE >= 2.5x the 1h average") {
+			t.Errorf("handleConditionalOrder() output should describe the surge:\n%s", buf.String())
 		}
-	})
 
-	t.Run("missing argument", func(t *testing.T) {
-		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		// The window defaults to an hour and also takes a duration
+		if err := cli.handleConditionalOrder([]string{"BTCUSDT", "BUY", "0.001", "volume_surge", ">", "3"}); err != nil {
+			t.Fatalf("handleConditionalOrder() error = %v", err)
+		}
+		if request.TriggerCondition.TimeWindow != service.DefaultVolumeSurgeWindow {
+			t.Errorf("default window = %v, want %v", request.TriggerCondition.TimeWindow, service.DefaultVolumeSurgeWindow)
+		}
+		if err := cli.handleConditionalOrder([]string{"BTCUSDT", "BUY", "0.001", "VOLUME_SURGE", ">", "3", "window", "4h"}); err != nil {
+			t.Fatalf("handleConditionalOrder() error = %v", err)
+		}
+		if request.TriggerCondition.TimeWindow != 4*time.Hour {
+			t.Errorf("window = %v, want 4h", request.TriggerCondition.TimeWindow)
+		}
 
-		err := cli.handleCancelConditionalOrder([]string{})
-		if err == nil {
-			t.Errorf("handleCancelConditionalOrder() expected error for missing argument")
+		for _, args := range [][]string{
+			{"VOLUME_SURGE", ">=", "-2"},
+			{"VOLUME_SURGE", ">=", "2.5", "WINDOW", "soon"},
+			{"PRICE", ">=", "50000", "WINDOW", "3600000"},
+		} {
+			if err := cli.handleConditionalOrder(append([]string{"BTCUSDT", "BUY", "0.001"}, args...)); err == nil {
+				t.Errorf("handleConditionalOrder() with %v should fail", args)
+			}
 		}
 	})
-}
 
-// TestHandleStopLoss tests the stoploss command handler
-func TestHandleStopLoss(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
-		mockStopService := &mockStopLossService{
-			setStopLossFunc: func(symbol string, position float64, stopPrice float64) (*repository.StopOrder, error) {
-				return &repository.StopOrder{
-					OrderID:   "sl-12345",
-					Symbol:    symbol,
-					Position:  position,
-					StopPrice: stopPrice,
-					Type:      repository.StopOrderTypeStopLoss,
-					Status:    repository.StopOrderStatusActive,
-				}, nil
+	t.Run("time", func(t *testing.T) {
+		var request *repository.ConditionalOrderRequest
+		mockCondService := &mockConditionalOrderService{
+			createConditionalOrderFunc: func(req *repository.ConditionalOrderRequest) (*repository.ConditionalOrder, error) {
+				request = req
+				return &repository.ConditionalOrder{OrderID: "cond-time", Symbol: req.Symbol, Side: req.Side, Type: req.Type,
+					Quantity: req.Quantity, Status: repository.ConditionalOrderStatusPending, TriggerCondition: req.TriggerCondition}, nil
 			},
 		}
-
-		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, mockStopService, &mockLogger{})
-
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, mockCondService, &mockStopLossService{}, &mockLogger{})
 		var buf bytes.Buffer
 		cli.writer = &buf
 
-		err := cli.handleStopLoss([]string{"BTCUSDT", "0.001", "49000"})
-		if err != nil {
-			t.Errorf("handleStopLoss() unexpected error: %v", err)
+		if err := cli.handleConditionalOrder([]string{"BTCUSDT", "BUY", "0.1", "TIME", ">=", "1700000000"}); err != nil {
+			t.Fatalf("handleConditionalOrder() error = %v", err)
+		}
+		condition := request.TriggerCondition
+		if condition.Type != repository.TriggerTypeTime || condition.Operator != repository.OperatorGreaterEqual || condition.Value != 1700000000000 {
+			t.Errorf("trigger condition = %+v, want TIME >= 1700000000000", condition)
+		}
+		if want := "TIME >= " + cli.display.fmtTime(1700000000000); !strings.Contains(buf.String(), want) {
+			t.Errorf("handleConditionalOrder() output should contain %q:\n%s", want, buf.String())
 		}
 
-		output := buf.String()
-		if !strings.Contains(output, "sl-12345") {
-			t.Errorf("handleStopLoss() output should contain order ID")
+		// RFC3339 times and Unix milliseconds name the same moment, stored in milliseconds
+		for _, spec := range []string{"2023-11-14T22:13:20Z", "2023-11-15T06:13:20+08:00", "1700000000000"} {
+			if err := cli.handleConditionalOrder([]string{"BTCUSDT", "BUY", "0.1", "time", ">=", spec}); err != nil {
+				t.Fatalf("handleConditionalOrder() with %q error = %v", spec, err)
+			}
+			if request.TriggerCondition.Value != 1700000000000 {
+				t.Errorf("trigger time of %q = %v, want 1700000000000", spec, request.TriggerCondition.Value)
+			}
 		}
-		if !strings.Contains(output, "STOP_LOSS") {
-			t.Errorf("handleStopLoss() output should contain order type")
+
+		for _, spec := range []string{"tomorrow", "0", "-1700000000", "2023-11-14 22:13:20"} {
+			if err := cli.handleConditionalOrder([]string{"BTCUSDT", "BUY", "0.1", "TIME", ">=", spec}); err == nil {
+				t.Errorf("handleConditionalOrder() with TIME value %q should fail", spec)
+			}
 		}
 	})
 
-	t.Run("missing arguments", func(t *testing.T) {
-		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+	t.Run("ttl", func(t *testing.T) {
+		var request *repository.ConditionalOrderRequest
+		mockCondService := &mockConditionalOrderService{
+			createConditionalOrderFunc: func(req *repository.ConditionalOrderRequest) (*repository.ConditionalOrder, error) {
+				request = req
+				return &repository.ConditionalOrder{OrderID: "cond-ttl", Symbol: req.Symbol, Side: req.Side, Type: req.Type,
+					Quantity: req.Quantity, Price: req.Price, Status: repository.ConditionalOrderStatusPending,
+					TriggerCondition: req.TriggerCondition, TimeWindow: req.TimeWindow}, nil
+			},
+		}
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, mockCondService, &mockStopLossService{}, &mockLogger{})
+		var buf bytes.Buffer
+		cli.writer = &buf
 
-		err := cli.handleStopLoss([]string{"BTCUSDT"})
-		if err == nil {
-			t.Errorf("handleStopLoss() expected error for missing arguments")
+		before := time.Now()
+		if err := cli.handleConditionalOrder([]string{"BTCUSDT", "BUY", "0.001", "PRICE", ">=", "50000", "TTL", "14400"}); err != nil {
+			t.Fatalf("handleConditionalOrder() error = %v", err)
+		}
+		window := request.TimeWindow
+		if window == nil || !window.StartTime.IsZero() || window.EndTime.Before(before.Add(4*time.Hour)) || window.EndTime.After(time.Now().Add(4*time.Hour)) {
+			t.Errorf("time window = %+v, want one ending 4h from now", window)
+		}
+		if !strings.Contains(buf.String(), "Expires:") {
+			t.Errorf("handleConditionalOrder() output should show the expiry:\n%s", buf.String())
 		}
-	})
 
-	t.Run("invalid position", func(t *testing.T) {
-		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		// The TTL combines with a limit in either order and takes a duration
+		if err := cli.handleConditionalOrder([]string{"BTCUSDT", "BUY", "0.001", "PRICE", "<=", "48000", "ttl", "90m", "limit", "47950"}); err != nil {
+			t.Fatalf("handleConditionalOrder() error = %v", err)
+		}
+		if request.Type != api.OrderTypeLimit || request.Price != 47950 || request.TimeWindow == nil {
+			t.Errorf("request = %+v, want a limit order with a time window", request)
+		}
 
-		err := cli.handleStopLoss([]string{"BTCUSDT", "invalid", "49000"})
-		if err == nil {
-			t.Errorf("handleStopLoss() expected error for invalid position")
+		for _, args := range [][]string{
+			{"ttl", "0"},
+			{"ttl", "-5m"},
+			{"ttl", "soon"},
+			{"ttl"},
+			{"ttl", "60", "ttl", "120"},
+			{"expires", "60"},
+		} {
+			if err := cli.handleConditionalOrder(append([]string{"BTCUSDT", "BUY", "0.001", "PRICE", ">=", "50000"}, args...)); err == nil {
+				t.Errorf("handleConditionalOrder() with %v should fail", args)
+			}
 		}
 	})
-}
 
-// TestHandleTakeProfit tests the takeprofit command handler
-func TestHandleTakeProfit(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
-		mockStopService := &mockStopLossService{
-			setTakeProfitFunc: func(symbol string, position float64, targetPrice float64) (*repository.StopOrder, error) {
-				return &repository.StopOrder{
-					OrderID:   "tp-12345",
-					Symbol:    symbol,
-					Position:  position,
-					StopPrice: targetPrice,
-					Type:      repository.StopOrderTypeTakeProfit,
-					Status:    repository.StopOrderStatusActive,
-				}, nil
+	t.Run("volume window", func(t *testing.T) {
+		var request *repository.ConditionalOrderRequest
+		mockCondService := &mockConditionalOrderService{
+			createConditionalOrderFunc: func(req *repository.ConditionalOrderRequest) (*repository.ConditionalOrder, error) {
+				request = req
+				return &repository.ConditionalOrder{OrderID: "cond-volume", Symbol: req.Symbol, Side: req.Side, Type: req.Type,
+					Quantity: req.Quantity, Status: repository.ConditionalOrderStatusPending, TriggerCondition: req.TriggerCondition}, nil
 			},
 		}
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, mockCondService, &mockStopLossService{}, &mockLogger{})
+		var buf bytes.Buffer
+		cli.writer = &buf
 
-		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, mockStopService, &mockLogger{})
+		if err := cli.handleConditionalOrder([]string{"BTCUSDT", "BUY", "0.001", "VOLUME", ">", "500@15m"}); err != nil {
+			t.Fatalf("handleConditionalOrder() error = %v", err)
+		}
+		if condition := request.TriggerCondition; condition.Value != 500 || condition.TimeWindow != 15*time.Minute {
+			t.Errorf("trigger condition = %+v, want VOLUME > 500 over 15m", condition)
+		}
+		if want := cli.display.fmtQty("BTCUSDT", 500) + " over 15m"; !strings.Contains(buf.String(), want) {
+			t.Errorf("handleConditionalOrder() output should contain %q:\n%s", want, buf.String())
+		}
+
+		// Without a window the 24h volume is compared, as before windows could be given
+		if err := cli.handleConditionalOrder([]string{"BTCUSDT", "BUY", "0.001", "VOLUME", "GT", "20000"}); err != nil {
+			t.Fatalf("handleConditionalOrder() error = %v", err)
+		}
+		if request.TriggerCondition.TimeWindow != 24*time.Hour {
+			t.Errorf("default window = %v, want 24h", request.TriggerCondition.TimeWindow)
+		}
+		if err := cli.handleConditionalOrder([]string{"BTCUSDT", "BUY", "0.001", "VOLUME", ">", "500@soon"}); err == nil {
+			t.Error("handleConditionalOrder() with an invalid volume window should fail")
+		}
+	})
 
+	t.Run("limit order", func(t *testing.T) {
+		var request *repository.ConditionalOrderRequest
+		mockCondService := &mockConditionalOrderService{
+			createConditionalOrderFunc: func(req *repository.ConditionalOrderRequest) (*repository.ConditionalOrder, error) {
+				request = req
+				return &repository.ConditionalOrder{OrderID: "cond-limit", Symbol: req.Symbol, Side: req.Side, Type: req.Type,
+					Quantity: req.Quantity, Price: req.Price, PriceOffset: req.PriceOffset, OffsetPercent: req.OffsetPercent,
+					Status: repository.ConditionalOrderStatusPending, TriggerCondition: req.TriggerCondition}, nil
+			},
+		}
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, mockCondService, &mockStopLossService{}, &mockLogger{})
 		var buf bytes.Buffer
 		cli.writer = &buf
 
-		err := cli.handleTakeProfit([]string{"BTCUSDT", "0.001", "51000"})
-		if err != nil {
-			t.Errorf("handleTakeProfit() unexpected error: %v", err)
+		tests := []struct {
+			spec    string
+			price   float64
+			offset  float64
+			percent bool
+			output  string
+		}{
+			{"+0.2%", 0, 0.2, true, "trigger price +0.2%"},
+			{"-50", 0, -50, false, "trigger price -50"},
+			{"47950", 47950, 0, false, "47950"},
+		}
+		for _, tt := range tests {
+			buf.Reset()
+			if err := cli.handleConditionalOrder([]string{"BTCUSDT", "SELL", "0.001", "PRICE", ">=", "50000", "LIMIT", tt.spec}); err != nil {
+				t.Fatalf("handleConditionalOrder(limit %s) error = %v", tt.spec, err)
+			}
+			if request.Type != api.OrderTypeLimit || request.Price != tt.price || request.PriceOffset != tt.offset || request.OffsetPercent != tt.percent {
+				t.Errorf("limit %s: request = %+v, want price %v, offset %v, percent %v", tt.spec, request, tt.price, tt.offset, tt.percent)
+			}
+			if !strings.Contains(buf.String(), "Limit Price:") || !strings.Contains(buf.String(), tt.output) {
+				t.Errorf("limit %s: output should show the limit price %q:\n%s", tt.spec, tt.output, buf.String())
+			}
 		}
 
-		output := buf.String()
-		if !strings.Contains(output, "tp-12345") {
-			t.Errorf("handleTakeProfit() output should contain order ID")
+		// Without the limit keyword the order stays a market order
+		if err := cli.handleConditionalOrder([]string{"BTCUSDT", "SELL", "0.001", "PRICE", ">=", "50000"}); err != nil {
+			t.Fatalf("handleConditionalOrder() error = %v", err)
 		}
-		if !strings.Contains(output, "TAKE_PROFIT") {
-			t.Errorf("handleTakeProfit() output should contain order type")
+		if request.Type != api.OrderTypeMarket || request.Price != 0 || request.PriceOffset != 0 {
+			t.Errorf("request = %+v, want a market order", request)
+		}
+
+		for _, args := range [][]string{
+			{"BTCUSDT", "SELL", "0.001", "PRICE", ">=", "50000", "limit"},
+			{"BTCUSDT", "SELL", "0.001", "PRICE", ">=", "50000", "stop", "+0.2%"},
+			{"BTCUSDT", "SELL", "0.001", "PRICE", ">=", "50000", "limit", "+x%"},
+		} {
+			if err := cli.handleConditionalOrder(args); err == nil {
+				t.Errorf("handleConditionalOrder(%v) should fail", args)
+			}
 		}
 	})
 
-	t.Run("missing arguments", func(t *testing.T) {
-		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+	t.Run("duplicates and idempotency keys", func(t *testing.T) {
+		condService := service.NewConditionalOrderService(repository.NewMemoryConditionalOrderRepository(), repository.NewMemoryStopOrderRepository(),
+			service.NewTriggerEngine(), nil, nil, nil, &mockLogger{})
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, condService, &mockStopLossService{}, &mockLogger{})
+		var buf bytes.Buffer
+		cli.writer = &buf
 
-		err := cli.handleTakeProfit([]string{"BTCUSDT", "0.001"})
-		if err == nil {
-			t.Errorf("handleTakeProfit() expected error for missing arguments")
+		command := []string{"BTCUSDT", "BUY", "0.001", "PRICE", "<=", "48000"}
+		if err := cli.handleConditionalOrder(command); err != nil {
+			t.Fatalf("handleConditionalOrder() error = %v", err)
+		}
+		// The same threshold written differently is still a duplicate
+		err := cli.handleConditionalOrder([]string{"btcusdt", "buy", "0.0010", "PRICE", "LE", "48000.00"})
+		if err == nil || !strings.Contains(err.Error(), "--allow-duplicate") {
+			t.Errorf("expected duplicate error pointing to --allow-duplicate, got %v", err)
+		}
+		if err := cli.handleConditionalOrder(append(command, "--allow-duplicate")); err != nil {
+			t.Errorf("handleConditionalOrder() with --allow-duplicate error = %v", err)
+		}
+
+		keyed := []string{"BTCUSDT", "SELL", "0.001", "PRICE", ">=", "60000", "--idempotency-key", "tp-1"}
+		if err := cli.handleConditionalOrder(keyed); err != nil {
+			t.Fatalf("handleConditionalOrder() error = %v", err)
+		}
+		if err := cli.handleConditionalOrder(keyed); err != nil {
+			t.Errorf("retry with the same idempotency key error = %v", err)
+		}
+		if active, _ := condService.GetActiveConditionalOrders(); len(active) != 3 {
+			t.Errorf("got %d active orders, want 3", len(active))
+		}
+
+		if err := cli.handleConditionalOrder([]string{"BTCUSDT", "SELL", "0.001", "PRICE", ">=", "60000", "--idempotency-key"}); !errors.Is(err, ErrUsage) {
+			t.Errorf("expected usage error for a missing key, got %v", err)
 		}
 	})
 }
 
-// TestHandleStopOrders tests the stoporders command handler
-func TestHandleStopOrders(t *testing.T) {
+// TestHandleConditionalOrderTemplate tests exporting and importing condorder templates
+func TestHandleConditionalOrderTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.yaml")
+	var created []*repository.ConditionalOrderRequest
+	mockCondService := &mockConditionalOrderService{
+		getActiveConditionalOrdersFunc: func() ([]*repository.ConditionalOrder, error) {
+			return []*repository.ConditionalOrder{{
+				OrderID:  "cond-1",
+				Symbol:   "BTCUSDT",
+				Side:     api.OrderSideBuy,
+				Type:     api.OrderTypeMarket,
+				Quantity: 0.01,
+				TriggerCondition: &repository.TriggerCondition{
+					Type:     repository.TriggerTypePrice,
+					Operator: repository.OperatorLessEqual,
+					Value:    48000,
+				},
+				Status: repository.ConditionalOrderStatusPending,
+			}}, nil
+		},
+		createConditionalOrderFunc: func(request *repository.ConditionalOrderRequest) (*repository.ConditionalOrder, error) {
+			created = append(created, request)
+			return &repository.ConditionalOrder{OrderID: fmt.Sprintf("cond-new-%d", len(created)), Symbol: request.Symbol}, nil
+		},
+	}
+	cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, mockCondService, &mockStopLossService{}, &mockLogger{})
+
+	var buf bytes.Buffer
+	cli.writer = &buf
+
+	if err := cli.handleConditionalOrder([]string{"export", path}); err != nil {
+		t.Fatalf("export unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Exported 1 conditional orders") {
+		t.Errorf("export output = %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := cli.handleConditionalOrder([]string{"import", path, "--dry-run"}); err != nil {
+		t.Fatalf("dry run unexpected error: %v", err)
+	}
+	if len(created) != 0 {
+		t.Errorf("dry run created %d orders", len(created))
+	}
+	output := buf.String()
+	if !strings.Contains(output, "PRICE <= 48000") || !strings.Contains(output, "Dry run: 1 conditional orders would be created") {
+		t.Errorf("dry run output = %q", output)
+	}
+
+	buf.Reset()
+	if err := cli.handleConditionalOrder([]string{"import", path, "--symbol", "ethusdt"}); err != nil {
+		t.Fatalf("import unexpected error: %v", err)
+	}
+	if len(created) != 1 || created[0].Symbol != "ETHUSDT" || created[0].Quantity != 0.01 {
+		t.Fatalf("created = %+v, want one ETHUSDT order", created)
+	}
+	if !strings.Contains(buf.String(), "Imported 1 conditional orders") {
+		t.Errorf("import output = %q", buf.String())
+	}
+
+	if err := os.WriteFile(path, []byte("version: 9\norders: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.handleConditionalOrder([]string{"import", path}); err == nil || !strings.Contains(err.Error(), "newer than the supported version") {
+		t.Errorf("expected version error, got %v", err)
+	}
+	if err := cli.handleConditionalOrder([]string{"import"}); !errors.Is(err, ErrUsage) {
+		t.Errorf("expected usage error, got %v", err)
+	}
+}
+
+// TestHandleConditionalOrders tests the condorders command handler
+func TestHandleConditionalOrders(t *testing.T) {
 	t.Run("success with orders", func(t *testing.T) {
-		mockStopService := &mockStopLossService{
-			getActiveStopOrdersFunc: func(symbol string) ([]*repository.StopOrder, error) {
-				return []*repository.StopOrder{
-					{
-						OrderID:   "sl-1",
-						Symbol:    symbol,
-						Position:  0.001,
-						StopPrice: 49000,
-						Type:      repository.StopOrderTypeStopLoss,
-						Status:    repository.StopOrderStatusActive,
-					},
+		mockCondService := &mockConditionalOrderService{
+			getActiveConditionalOrdersFunc: func() ([]*repository.ConditionalOrder, error) {
+				return []*repository.ConditionalOrder{
 					{
-						OrderID:   "tp-1",
-						Symbol:    symbol,
-						Position:  0.001,
-						StopPrice: 51000,
-						Type:      repository.StopOrderTypeTakeProfit,
-						Status:    repository.StopOrderStatusActive,
+						OrderID:  "cond-1",
+						Symbol:   "BTCUSDT",
+						Side:     api.OrderSideBuy,
+						Type:     api.OrderTypeMarket,
+						Quantity: 0.001,
+						Status:   repository.ConditionalOrderStatusPending,
+						TriggerCondition: &repository.TriggerCondition{
+							Type:     repository.TriggerTypePrice,
+							Operator: repository.OperatorGreaterEqual,
+							Value:    50000,
+						},
 					},
 				}, nil
 			},
 		}
 
-		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, mockStopService, &mockLogger{})
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, mockCondService, &mockStopLossService{}, &mockLogger{})
 
 		var buf bytes.Buffer
 		cli.writer = &buf
 
-		err := cli.handleStopOrders([]string{"BTCUSDT"})
+		err := cli.handleConditionalOrders([]string{})
 		if err != nil {
-			t.Errorf("handleStopOrders() unexpected error: %v", err)
+			t.Errorf("handleConditionalOrders() unexpected error: %v", err)
 		}
 
 		output := buf.String()
-		if !strings.Contains(output, "sl-1") {
-			t.Errorf("handleStopOrders() output should contain stop loss order ID")
+		if !strings.Contains(output, "cond-1") {
+			t.Errorf("handleConditionalOrders() output should contain order ID")
 		}
-		if !strings.Contains(output, "tp-1") {
-			t.Errorf("handleStopOrders() output should contain take profit order ID")
+		if !strings.Contains(output, "BTCUSDT") {
+			t.Errorf("handleConditionalOrders() output should contain symbol")
 		}
 	})
 
 	t.Run("empty list", func(t *testing.T) {
-		mockStopService := &mockStopLossService{
-			getActiveStopOrdersFunc: func(symbol string) ([]*repository.StopOrder, error) {
-				return []*repository.StopOrder{}, nil
+		mockCondService := &mockConditionalOrderService{
+			getActiveConditionalOrdersFunc: func() ([]*repository.ConditionalOrder, error) {
+				return []*repository.ConditionalOrder{}, nil
 			},
 		}
 
-		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, mockStopService, &mockLogger{})
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, mockCondService, &mockStopLossService{}, &mockLogger{})
 
 		var buf bytes.Buffer
 		cli.writer = &buf
 
-		err := cli.handleStopOrders([]string{"BTCUSDT"})
+		err := cli.handleConditionalOrders([]string{})
 		if err != nil {
-			t.Errorf("handleStopOrders() unexpected error: %v", err)
+			t.Errorf("handleConditionalOrders() unexpected error: %v", err)
 		}
 
 		output := buf.String()
-		if !strings.Contains(output, "No active stop orders") {
-			t.Errorf("handleStopOrders() should show 'No active stop orders' for empty list")
-		}
-	})
-
-	t.Run("missing argument", func(t *testing.T) {
-		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
-
-		err := cli.handleStopOrders([]string{})
-		if err == nil {
-			t.Errorf("handleStopOrders() expected error for missing argument")
+		if !strings.Contains(output, "No active conditional orders") {
+			t.Errorf("handleConditionalOrders() should show 'No active conditional orders' for empty list")
 		}
 	})
 }
 
-// TestHandleCancelStopOrder tests the cancelstop command handler
-func TestHandleCancelStopOrder(t *testing.T) {
+// TestHandleCancelConditionalOrder tests the cancelcond command handler
+func TestHandleCancelConditionalOrder(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
-		mockStopService := &mockStopLossService{
-			cancelStopOrderFunc: func(orderID string) error {
-				return nil
+		var gotSymbol string
+		mockCondService := &mockConditionalOrderService{
+			cancelConditionalOrderFunc: func(orderID, symbol string) (*repository.ConditionalOrder, error) {
+				gotSymbol = symbol
+				return &repository.ConditionalOrder{
+					OrderID:  orderID,
+					Symbol:   "BTCUSDT",
+					Side:     api.OrderSideBuy,
+					Type:     api.OrderTypeMarket,
+					Quantity: 0.5,
+					Status:   repository.ConditionalOrderStatusCancelled,
+					TriggerCondition: &repository.TriggerCondition{
+						Type:     repository.TriggerTypePrice,
+						Operator: repository.OperatorGreaterEqual,
+						Value:    50000,
+					},
+				}, nil
 			},
 		}
 
-		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, mockStopService, &mockLogger{})
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, mockCondService, &mockStopLossService{}, &mockLogger{})
 
 		var buf bytes.Buffer
 		cli.writer = &buf
 
-		err := cli.handleCancelStopOrder([]string{"sl-12345"})
+		err := cli.handleCancelConditionalOrder([]string{"cond-12345", "btcusdt"})
 		if err != nil {
-			t.Errorf("handleCancelStopOrder() unexpected error: %v", err)
+			t.Errorf("handleCancelConditionalOrder() unexpected error: %v", err)
+		}
+		if gotSymbol != "BTCUSDT" {
+			t.Errorf("expected the symbol check for BTCUSDT, got %q", gotSymbol)
 		}
 
+		// The cancelled order is shown for confirmation
 		output := buf.String()
-		if !strings.Contains(output, "canceled successfully") {
-			t.Errorf("handleCancelStopOrder() output should contain success message")
+		for _, want := range []string{"canceled successfully", "Symbol:       BTCUSDT", "Side:         BUY", "Quantity:     0.5", "Trigger:"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("handleCancelConditionalOrder() output should contain %q, got %q", want, output)
+			}
 		}
 	})
 
-	t.Run("missing argument", func(t *testing.T) {
-		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
-
+	t.Run("symbol mismatch", func(t *testing.T) {
+		mockCondService := &mockConditionalOrderService{
+			cancelConditionalOrderFunc: func(orderID, symbol string) (*repository.ConditionalOrder, error) {
+				return nil, fmt.Errorf("order cond-12345 belongs to ETHUSDT, not BTCUSDT")
+			},
+		}
+
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, mockCondService, &mockStopLossService{}, &mockLogger{})
+
+		var buf bytes.Buffer
+		cli.writer = &buf
+
+		err := cli.handleCancelConditionalOrder([]string{"cond-12345", "BTCUSDT"})
+		if err == nil || !strings.Contains(err.Error(), "belongs to ETHUSDT") {
+			t.Errorf("expected the mismatch to be reported, got %v", err)
+		}
+		if strings.Contains(buf.String(), "canceled successfully") {
+			t.Errorf("nothing should be reported as cancelled, got %q", buf.String())
+		}
+	})
+
+	t.Run("missing argument", func(t *testing.T) {
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+
+		err := cli.handleCancelConditionalOrder([]string{})
+		if err == nil {
+			t.Errorf("handleCancelConditionalOrder() expected error for missing argument")
+		}
+	})
+
+	t.Run("bulk cancel", func(t *testing.T) {
+		var gotSymbol string
+		var gotReason repository.CancelReason
+		mockCondService := &mockConditionalOrderService{
+			cancelAllConditionalOrdersFunc: func(symbol string, reason repository.CancelReason, cancelledBy string) (int, error) {
+				gotSymbol, gotReason = symbol, reason
+				return 3, nil
+			},
+		}
+
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, mockCondService, &mockStopLossService{}, &mockLogger{})
+
+		var buf bytes.Buffer
+		cli.writer = &buf
+
+		if err := cli.handleCancelConditionalOrder([]string{"all", "btcusdt"}); err != nil {
+			t.Fatalf("handleCancelConditionalOrder() unexpected error: %v", err)
+		}
+		if gotSymbol != "BTCUSDT" || gotReason != repository.CancelReasonBulk {
+			t.Errorf("expected bulk cancel of BTCUSDT, got %q with reason %s", gotSymbol, gotReason)
+		}
+		if !strings.Contains(buf.String(), "Cancelled 3 conditional orders") {
+			t.Errorf("expected bulk cancel count in output, got %q", buf.String())
+		}
+	})
+}
+
+// TestHandleConditionalOrderHistory tests the condhistory command handler
+func TestHandleConditionalOrderHistory(t *testing.T) {
+	var gotStart, gotEnd int64
+	mockCondService := &mockConditionalOrderService{
+		getConditionalOrderHistoryFunc: func(startTime, endTime int64) ([]*repository.ConditionalOrder, error) {
+			gotStart, gotEnd = startTime, endTime
+			return []*repository.ConditionalOrder{
+				{OrderID: "cond-2", Symbol: "BTCUSDT", Status: repository.ConditionalOrderStatusCancelled, CreatedAt: 2,
+					CancelReason: repository.CancelReasonExpired, CancelledBy: "monitor", CancelledAt: 1700000000000},
+				{OrderID: "cond-1", Symbol: "BTCUSDT", Status: repository.ConditionalOrderStatusExecuted, CreatedAt: 1},
+				{OrderID: "cond-3", Symbol: "ETHUSDT", Status: repository.ConditionalOrderStatusCancelled, CreatedAt: 3,
+					CancelReason: repository.CancelReasonUser, CancelledBy: "cli"},
+			}, nil
+		},
+	}
+
+	cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, mockCondService, &mockStopLossService{}, &mockLogger{})
+
+	var buf bytes.Buffer
+	cli.writer = &buf
+
+	if err := cli.handleConditionalOrderHistory([]string{"48"}); err != nil {
+		t.Fatalf("handleConditionalOrderHistory() unexpected error: %v", err)
+	}
+	if gotEnd-gotStart != 48*3600*1000 {
+		t.Errorf("expected a 48 hour range, got %d ms", gotEnd-gotStart)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"Conditional Order History (3)", "Cancelled:    EXPIRED by monitor at", "Cancelled:    USER by cli", "EXPIRED      1", "USER         1"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "cond-1") > strings.Index(output, "cond-2") {
+		t.Error("history should be listed oldest first")
+	}
+
+	if err := cli.handleConditionalOrderHistory([]string{"abc"}); err == nil {
+		t.Error("expected error for invalid hours")
+	}
+}
+
+func TestParseHistoryRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantStart int64
+		wantEnd   int64
+		errorMsg  string
+	}{
+		{name: "RFC3339 range", args: []string{"2024-01-01T00:00:00Z", "2024-01-02T08:00:00+08:00"}, wantStart: 1704067200000, wantEnd: 1704153600000},
+		{name: "milliseconds", args: []string{"1704067200000", "1704067200001"}, wantStart: 1704067200000, wantEnd: 1704067200001},
+		{name: "seconds rejected", args: []string{"2024-01-01T00:00:00Z", "1704153600"}, errorMsg: "looks like seconds"},
+		{name: "reversed", args: []string{"2024-01-02T00:00:00Z", "2024-01-01T00:00:00Z"}, errorMsg: "is after end"},
+		{name: "invalid start", args: []string{"yesterday"}, errorMsg: "invalid start"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := parseHistoryRange(tt.args)
+			if tt.errorMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+					t.Errorf("expected error containing %q, got %v", tt.errorMsg, err)
+				}
+				return
+			}
+			if err != nil || start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("parseHistoryRange(%v) = %d, %d, %v; want %d, %d", tt.args, start, end, err, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+
+	// A start without an end runs until now
+	start, end, err := parseHistoryRange([]string{"2024-01-01T00:00:00Z"})
+	if err != nil || start != 1704067200000 || end < time.Now().Add(-time.Minute).UnixMilli() {
+		t.Errorf("expected a range from the start until now, got %d, %d, %v", start, end, err)
+	}
+}
+
+func TestFormatCancellation_UsesDisplayTimezone(t *testing.T) {
+	display := newDisplayFormat(&config.CLIConfig{Timezone: "Asia/Shanghai"})
+
+	var buf bytes.Buffer
+	formatCancellation(&buf, display, repository.CancelReasonExpired, "monitor", 1704110400000)
+	if !strings.Contains(buf.String(), "at 2024-01-01 20:00:00 CST") {
+		t.Errorf("expected cancellation time in Asia/Shanghai, got %q", buf.String())
+	}
+
+	buf.Reset()
+	formatCancellation(&buf, newDisplayFormat(nil), repository.CancelReasonExpired, "monitor", 1704110400000)
+	if !strings.Contains(buf.String(), "at 2024-01-01 12:00:00 UTC") {
+		t.Errorf("expected cancellation time in UTC by default, got %q", buf.String())
+	}
+}
+
+// TestHandleStopLoss tests the stoploss command handler
+func TestHandleStopLoss(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockStopService := &mockStopLossService{
+			setStopLossFunc: func(symbol string, position float64, stopPrice float64) (*repository.StopOrder, error) {
+				return &repository.StopOrder{
+					OrderID:   "sl-12345",
+					Symbol:    symbol,
+					Position:  position,
+					StopPrice: stopPrice,
+					Type:      repository.StopOrderTypeStopLoss,
+					Status:    repository.StopOrderStatusActive,
+				}, nil
+			},
+		}
+
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, mockStopService, &mockLogger{})
+
+		var buf bytes.Buffer
+		cli.writer = &buf
+
+		err := cli.handleStopLoss([]string{"BTCUSDT", "0.001", "49000"})
+		if err != nil {
+			t.Errorf("handleStopLoss() unexpected error: %v", err)
+		}
+
+		output := buf.String()
+		if !strings.Contains(output, "sl-12345") {
+			t.Errorf("handleStopLoss() output should contain order ID")
+		}
+		if !strings.Contains(output, "STOP_LOSS") {
+			t.Errorf("handleStopLoss() output should contain order type")
+		}
+	})
+
+	t.Run("missing arguments", func(t *testing.T) {
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+
+		err := cli.handleStopLoss([]string{"BTCUSDT"})
+		if err == nil {
+			t.Errorf("handleStopLoss() expected error for missing arguments")
+		}
+	})
+
+	t.Run("invalid position", func(t *testing.T) {
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+
+		err := cli.handleStopLoss([]string{"BTCUSDT", "invalid", "49000"})
+		if err == nil {
+			t.Errorf("handleStopLoss() expected error for invalid position")
+		}
+	})
+}
+
+// TestHandleTakeProfit tests the takeprofit command handler
+func TestHandleTakeProfit(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockStopService := &mockStopLossService{
+			setTakeProfitFunc: func(symbol string, position float64, targetPrice float64) (*repository.StopOrder, error) {
+				return &repository.StopOrder{
+					OrderID:   "tp-12345",
+					Symbol:    symbol,
+					Position:  position,
+					StopPrice: targetPrice,
+					Type:      repository.StopOrderTypeTakeProfit,
+					Status:    repository.StopOrderStatusActive,
+				}, nil
+			},
+		}
+
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, mockStopService, &mockLogger{})
+
+		var buf bytes.Buffer
+		cli.writer = &buf
+
+		err := cli.handleTakeProfit([]string{"BTCUSDT", "0.001", "51000"})
+		if err != nil {
+			t.Errorf("handleTakeProfit() unexpected error: %v", err)
+		}
+
+		output := buf.String()
+		if !strings.Contains(output, "tp-12345") {
+			t.Errorf("handleTakeProfit() output should contain order ID")
+		}
+		if !strings.Contains(output, "TAKE_PROFIT") {
+			t.Errorf("handleTakeProfit() output should contain order type")
+		}
+	})
+
+	t.Run("missing arguments", func(t *testing.T) {
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+
+		err := cli.handleTakeProfit([]string{"BTCUSDT", "0.001"})
+		if err == nil {
+			t.Errorf("handleTakeProfit() expected error for missing arguments")
+		}
+	})
+}
+
+// TestHandleTakeProfitFeeGuard tests that take profits are checked against entry and exit fees
+func TestHandleBracketWithEntry(t *testing.T) {
+	var placed []float64
+	mockTrading := &mockTradingService{
+		placeLimitBuyOrderFunc: func(symbol string, price, quantity float64) (*api.Order, error) {
+			placed = append(placed, price, quantity)
+			return &api.Order{OrderID: 42, Symbol: symbol, Side: api.OrderSideBuy, Type: api.OrderTypeLimit,
+				Status: api.OrderStatusFilled, Price: price, OrigQty: quantity, ExecutedQty: quantity}, nil
+		},
+	}
+	var pairPosition float64
+	mockStopService := &mockStopLossService{
+		setStopLossTakeProfitFunc: func(symbol string, position float64, stopPrice, targetPrice float64) (*repository.StopOrderPair, error) {
+			pairPosition = position
+			return &repository.StopOrderPair{
+				PairID:          "pair-1",
+				Symbol:          symbol,
+				Position:        position,
+				StopLossOrder:   &repository.StopOrder{OrderID: "sl-1", Symbol: symbol, StopPrice: stopPrice, Type: repository.StopOrderTypeStopLoss},
+				TakeProfitOrder: &repository.StopOrder{OrderID: "tp-1", Symbol: symbol, StopPrice: targetPrice, Type: repository.StopOrderTypeTakeProfit},
+			}, nil
+		},
+	}
+	cli := NewCLI(mockTrading, &mockMarketDataService{}, &mockConditionalOrderService{}, mockStopService, &mockLogger{})
+	var buf bytes.Buffer
+	cli.writer = &buf
+
+	if err := cli.executeCommand(&Command{Name: "bracket", Args: []string{"btcusdt", "buy", "0.5", "100", "95", "110"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(placed) != 2 || placed[0] != 100 || placed[1] != 0.5 {
+		t.Errorf("entry order placed = %v, want 0.5 at 100", placed)
+	}
+	output := buf.String()
+	if pairPosition != 0.5 || !strings.Contains(output, "Entry Order ID: 42") || !strings.Contains(output, "pair-1") {
+		t.Errorf("expected the pair set for the filled entry, position %v:\n%s", pairPosition, output)
+	}
+
+	for _, args := range [][]string{
+		{"BTCUSDT", "BUY", "0.5", "100", "95"},
+		{"BTCUSDT", "BUY", "0.5", "100", "95", "110", "--entry", "100"},
+	} {
+		if err := cli.executeCommand(&Command{Name: "bracket", Args: args}); !errors.Is(err, ErrUsage) {
+			t.Errorf("bracket %v error = %v, want usage error", args, err)
+		}
+	}
+	if err := cli.executeCommand(&Command{Name: "bracket", Args: []string{"BTCUSDT", "SELL", "0.5", "100", "105", "90"}}); err == nil {
+		t.Error("expected a sell bracket to be refused")
+	}
+	if len(placed) != 2 {
+		t.Errorf("entry orders placed for refused brackets: %v", placed)
+	}
+}
+
+func TestHandleTakeProfitFeeGuard(t *testing.T) {
+	newGuardedCLI := func(created *int) *CLI {
+		mockStopService := &mockStopLossService{
+			setTakeProfitFunc: func(symbol string, position float64, targetPrice float64) (*repository.StopOrder, error) {
+				*created++
+				return &repository.StopOrder{OrderID: "tp-1", Symbol: symbol, Position: position, StopPrice: targetPrice,
+					Type: repository.StopOrderTypeTakeProfit, Status: repository.StopOrderStatusActive}, nil
+			},
+		}
+		mockMarket := &mockMarketDataService{
+			getCurrentPriceFunc: func(symbol string) (float64, error) { return 100, nil },
+		}
+		cli := NewCLI(&mockTradingService{}, mockMarket, &mockConditionalOrderService{}, mockStopService, &mockLogger{})
+		cli.SetProfitGuardConfig(&config.ProfitGuardConfig{FeeRate: 0.001, MinProfitPercent: 1})
+		return cli
+	}
+
+	t.Run("0.1% target nets negative and is refused", func(t *testing.T) {
+		created := 0
+		cli := newGuardedCLI(&created)
+		var buf bytes.Buffer
+		cli.writer = &buf
+
+		err := cli.handleTakeProfit([]string{"BTCUSDT", "1", "100.1", "--entry", "100"})
+		if err == nil || !strings.Contains(err.Error(), "--force") {
+			t.Fatalf("expected refusal suggesting --force, got %v", err)
+		}
+		if created != 0 {
+			t.Errorf("take profit should not be created, got %d", created)
+		}
+	})
+
+	t.Run("forced negative target is created with a warning", func(t *testing.T) {
+		created := 0
+		cli := newGuardedCLI(&created)
+		var buf bytes.Buffer
+		cli.writer = &buf
+
+		if err := cli.handleTakeProfit([]string{"BTCUSDT", "1", "100.1", "--entry", "100", "--force"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if created != 1 {
+			t.Errorf("take profit should be created once, got %d", created)
+		}
+		if !strings.Contains(buf.String(), "loses money after fees") {
+			t.Errorf("expected loss warning, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("1% target nets positive but below the minimum", func(t *testing.T) {
+		created := 0
+		cli := newGuardedCLI(&created)
+		var buf bytes.Buffer
+		cli.writer = &buf
+
+		if err := cli.handleTakeProfit([]string{"BTCUSDT", "1", "101", "--entry", "100"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		output := buf.String()
+		if created != 1 {
+			t.Errorf("take profit should be created once, got %d", created)
+		}
+		if !strings.Contains(output, "Net at target:") || !strings.Contains(output, "below the minimum") {
+			t.Errorf("expected net result and minimum warning, got:\n%s", output)
+		}
+	})
+
+	t.Run("entry estimated from current price", func(t *testing.T) {
+		created := 0
+		cli := newGuardedCLI(&created)
+		var buf bytes.Buffer
+		cli.writer = &buf
+
+		if err := cli.handleTakeProfit([]string{"BTCUSDT", "1", "105"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		output := buf.String()
+		if !strings.Contains(output, "estimated from current price") || strings.Contains(output, "Warning") {
+			t.Errorf("expected estimated entry without warnings, got:\n%s", output)
+		}
+	})
+
+	t.Run("bracket target leg is checked", func(t *testing.T) {
+		created := 0
+		cli := newGuardedCLI(&created)
+		pairs := 0
+		cli.stopLossService.(*mockStopLossService).setStopLossTakeProfitFunc = func(symbol string, position float64, stopPrice, targetPrice float64) (*repository.StopOrderPair, error) {
+			pairs++
+			return &repository.StopOrderPair{
+				PairID:          "pair-1",
+				Symbol:          symbol,
+				Position:        position,
+				StopLossOrder:   &repository.StopOrder{OrderID: "sl-1", Symbol: symbol, StopPrice: stopPrice, Type: repository.StopOrderTypeStopLoss},
+				TakeProfitOrder: &repository.StopOrder{OrderID: "tp-1", Symbol: symbol, StopPrice: targetPrice, Type: repository.StopOrderTypeTakeProfit},
+			}, nil
+		}
+		var buf bytes.Buffer
+		cli.writer = &buf
+
+		if err := cli.handleBracket([]string{"BTCUSDT", "1", "95", "100.1", "--entry", "100"}); err == nil {
+			t.Fatal("expected bracket with a losing target to be refused")
+		}
+		if err := cli.handleBracket([]string{"BTCUSDT", "1", "95", "101", "--entry", "100"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if pairs != 1 || !strings.Contains(buf.String(), "pair-1") {
+			t.Errorf("expected one pair created, got %d:\n%s", pairs, buf.String())
+		}
+	})
+}
+
+// TestHandleStopOrders tests the stoporders command handler
+func TestHandleStopOrders(t *testing.T) {
+	t.Run("success with orders", func(t *testing.T) {
+		mockStopService := &mockStopLossService{
+			getActiveStopOrdersFunc: func(symbol string) ([]*repository.StopOrder, error) {
+				return []*repository.StopOrder{
+					{
+						OrderID:   "sl-1",
+						Symbol:    symbol,
+						Position:  0.001,
+						StopPrice: 49000,
+						Type:      repository.StopOrderTypeStopLoss,
+						Status:    repository.StopOrderStatusActive,
+					},
+					{
+						OrderID:   "tp-1",
+						Symbol:    symbol,
+						Position:  0.001,
+						StopPrice: 51000,
+						Type:      repository.StopOrderTypeTakeProfit,
+						Status:    repository.StopOrderStatusActive,
+					},
+				}, nil
+			},
+		}
+
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, mockStopService, &mockLogger{})
+
+		var buf bytes.Buffer
+		cli.writer = &buf
+
+		err := cli.handleStopOrders([]string{"BTCUSDT"})
+		if err != nil {
+			t.Errorf("handleStopOrders() unexpected error: %v", err)
+		}
+
+		output := buf.String()
+		if !strings.Contains(output, "sl-1") {
+			t.Errorf("handleStopOrders() output should contain stop loss order ID")
+		}
+		if !strings.Contains(output, "tp-1") {
+			t.Errorf("handleStopOrders() output should contain take profit order ID")
+		}
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		mockStopService := &mockStopLossService{
+			getActiveStopOrdersFunc: func(symbol string) ([]*repository.StopOrder, error) {
+				return []*repository.StopOrder{}, nil
+			},
+		}
+
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, mockStopService, &mockLogger{})
+
+		var buf bytes.Buffer
+		cli.writer = &buf
+
+		err := cli.handleStopOrders([]string{"BTCUSDT"})
+		if err != nil {
+			t.Errorf("handleStopOrders() unexpected error: %v", err)
+		}
+
+		output := buf.String()
+		if !strings.Contains(output, "No active stop orders") {
+			t.Errorf("handleStopOrders() should show 'No active stop orders' for empty list")
+		}
+	})
+
+	t.Run("missing argument", func(t *testing.T) {
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+
+		err := cli.handleStopOrders([]string{})
+		if err == nil {
+			t.Errorf("handleStopOrders() expected error for missing argument")
+		}
+	})
+}
+
+// TestHandleCancelStopOrder tests the cancelstop command handler
+func TestHandleCancelStopOrder(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var gotSymbol string
+		mockStopService := &mockStopLossService{
+			cancelStopOrderFunc: func(orderID, symbol string) (*service.CancelledStopOrder, error) {
+				gotSymbol = symbol
+				return &service.CancelledStopOrder{StopOrder: &repository.StopOrder{
+					OrderID:   orderID,
+					Symbol:    "BTCUSDT",
+					Position:  0.5,
+					StopPrice: 45000,
+					Type:      repository.StopOrderTypeStopLoss,
+					Status:    repository.StopOrderStatusCancelled,
+				}}, nil
+			},
+		}
+
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, mockStopService, &mockLogger{})
+
+		var buf bytes.Buffer
+		cli.writer = &buf
+
+		err := cli.handleCancelStopOrder([]string{"sl-12345"})
+		if err != nil {
+			t.Errorf("handleCancelStopOrder() unexpected error: %v", err)
+		}
+		if gotSymbol != "" {
+			t.Errorf("expected no symbol check without a symbol, got %q", gotSymbol)
+		}
+
+		output := buf.String()
+		for _, want := range []string{"canceled successfully", "Symbol:       BTCUSDT", "Type:         STOP_LOSS", "Stop Price:   45000"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("handleCancelStopOrder() output should contain %q, got %q", want, output)
+			}
+		}
+	})
+
+	t.Run("trailing stop details", func(t *testing.T) {
+		mockStopService := &mockStopLossService{
+			cancelStopOrderFunc: func(orderID, symbol string) (*service.CancelledStopOrder, error) {
+				return &service.CancelledStopOrder{TrailingOrder: &repository.TrailingStopOrder{
+					OrderID:          orderID,
+					Symbol:           symbol,
+					Position:         1,
+					TrailPercent:     2.5,
+					CurrentStopPrice: 2900,
+				}}, nil
+			},
+		}
+
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, mockStopService, &mockLogger{})
+
+		var buf bytes.Buffer
+		cli.writer = &buf
+
+		if err := cli.handleCancelStopOrder([]string{"ts-1", "ethusdt"}); err != nil {
+			t.Fatalf("handleCancelStopOrder() unexpected error: %v", err)
+		}
+		output := buf.String()
+		for _, want := range []string{"Symbol:       ETHUSDT", "TRAILING_STOP", "Trail:        2.50%", "Stop Price:   2900"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("handleCancelStopOrder() output should contain %q, got %q", want, output)
+			}
+		}
+	})
+
+	t.Run("missing argument", func(t *testing.T) {
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+
 		err := cli.handleCancelStopOrder([]string{})
 		if err == nil {
 			t.Errorf("handleCancelStopOrder() expected error for missing argument")
 		}
-	})
+	})
+}
+
+// TestHandleTrailingStop tests the trailingstop command
+func TestHandleTrailingStop(t *testing.T) {
+	t.Run("fixed percent", func(t *testing.T) {
+		var gotPercent float64
+		mockStopService := &mockStopLossService{
+			setTrailingStopFunc: func(symbol string, position float64, trailPercent float64) (*repository.TrailingStopOrder, error) {
+				gotPercent = trailPercent
+				return &repository.TrailingStopOrder{
+					OrderID:          "ts-1",
+					Symbol:           symbol,
+					Position:         position,
+					TrailPercent:     trailPercent,
+					HighestPrice:     50000,
+					CurrentStopPrice: 49000,
+					Status:           repository.StopOrderStatusActive,
+				}, nil
+			},
+		}
+
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, mockStopService, &mockLogger{})
+		var buf bytes.Buffer
+		cli.writer = &buf
+
+		if err := cli.handleTrailingStop([]string{"btcusdt", "0.001", "2%"}); err != nil {
+			t.Fatalf("handleTrailingStop() unexpected error: %v", err)
+		}
+		if gotPercent != 2 {
+			t.Errorf("expected a 2%% trail, got %v", gotPercent)
+		}
+		output := buf.String()
+		for _, want := range []string{"Trailing Stop Created", "BTCUSDT", "Trail:          2.00%", "Stop Price:     49000"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("handleTrailingStop() output should contain %q, got %q", want, output)
+			}
+		}
+	})
+
+	t.Run("ATR trail", func(t *testing.T) {
+		var gotTrail service.ATRTrail
+		mockStopService := &mockStopLossService{
+			setATRTrailingStopFunc: func(symbol string, position float64, trail service.ATRTrail) (*repository.TrailingStopOrder, error) {
+				gotTrail = trail
+				return &repository.TrailingStopOrder{
+					OrderID:          "ts-2",
+					Symbol:           symbol,
+					Position:         position,
+					TrailPercent:     3.5,
+					HighestPrice:     100,
+					CurrentStopPrice: 96.5,
+					Status:           repository.StopOrderStatusActive,
+					TrailMode:        repository.TrailModeATR,
+					ATRPeriod:        trail.Period,
+					ATRMultiplier:    trail.Multiplier,
+					ATRInterval:      "1h",
+					ATR:              1.4,
+				}, nil
+			},
+		}
+
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, mockStopService, &mockLogger{})
+		var buf bytes.Buffer
+		cli.writer = &buf
+
+		if err := cli.handleTrailingStop([]string{"SOLUSDT", "5", "--atr", "14x2.5"}); err != nil {
+			t.Fatalf("handleTrailingStop() unexpected error: %v", err)
+		}
+		if gotTrail.Period != 14 || gotTrail.Multiplier != 2.5 {
+			t.Errorf("expected ATR(14) x 2.5, got %+v", gotTrail)
+		}
+		if output := buf.String(); !strings.Contains(output, "3.50% (2.5x ATR(14) 1h = 1.4)") {
+			t.Errorf("handleTrailingStop() output should describe the ATR trail, got %q", output)
+		}
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		cli.writer = &bytes.Buffer{}
+
+		invalid := [][]string{
+			{"BTCUSDT", "0.001"},
+			{"BTCUSDT", "0.001", "--atr"},
+			{"BTCUSDT", "0.001", "--atr", "14"},
+			{"BTCUSDT", "0.001", "--atr", "14x0"},
+			{"BTCUSDT", "abc", "2"},
+		}
+		for _, args := range invalid {
+			if err := cli.handleTrailingStop(args); err == nil {
+				t.Errorf("handleTrailingStop(%v) expected error", args)
+			}
+		}
+	})
+}
+
+// TestFormatConditionalOrder tests conditional order formatting
+func TestFormatConditionalOrder(t *testing.T) {
+	cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+
+	var buf bytes.Buffer
+	cli.writer = &buf
+
+	order := &repository.ConditionalOrder{
+		OrderID:  "cond-12345",
+		Symbol:   "BTCUSDT",
+		Side:     api.OrderSideBuy,
+		Type:     api.OrderTypeMarket,
+		Quantity: 0.001,
+		Status:   repository.ConditionalOrderStatusPending,
+		TriggerCondition: &repository.TriggerCondition{
+			Type:     repository.TriggerTypePrice,
+			Operator: repository.OperatorGreaterEqual,
+			Value:    50000,
+		},
+	}
+
+	cli.formatConditionalOrder(order)
+
+	output := buf.String()
+
+	expectedFields := []string{
+		"cond-12345",
+		"BTCUSDT",
+		"BUY",
+		"MARKET",
+		"0.001",
+		"PENDING",
+		"PRICE",
+		">=",
+		"50000",
+	}
+
+	for _, field := range expectedFields {
+		if !strings.Contains(output, field) {
+			t.Errorf("formatConditionalOrder() output should contain %s", field)
+		}
+	}
+}
+
+// TestFormatStopOrder tests stop order formatting
+func TestFormatStopOrder(t *testing.T) {
+	cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+
+	var buf bytes.Buffer
+	cli.writer = &buf
+
+	order := &repository.StopOrder{
+		OrderID:   "sl-12345",
+		Symbol:    "BTCUSDT",
+		Position:  0.001,
+		StopPrice: 49000,
+		Type:      repository.StopOrderTypeStopLoss,
+		Status:    repository.StopOrderStatusActive,
+	}
+
+	cli.formatStopOrder(order)
+
+	output := buf.String()
+
+	expectedFields := []string{
+		"sl-12345",
+		"BTCUSDT",
+		"STOP_LOSS",
+		"0.001",
+		"49000",
+		"ACTIVE",
+	}
+
+	for _, field := range expectedFields {
+		if !strings.Contains(output, field) {
+			t.Errorf("formatStopOrder() output should contain %s", field)
+		}
+	}
+}
+
+// TestHandleAutomationStatus tests the automation status summary
+func TestHandleAutomationStatus(t *testing.T) {
+	t.Run("summary", func(t *testing.T) {
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		automation := service.NewAutomationService(&mockTradingService{}, &mockMarketDataService{}, &config.AutomationConfig{MaxDCAPlans: 2, MaxGrids: 1}, &mockLogger{})
+		cli.SetAutomationService(automation)
+
+		var buf bytes.Buffer
+		cli.writer = &buf
+
+		if err := cli.handleAutomation([]string{"dca", "btcusdt", "0.001", "24h"}); err != nil {
+			t.Fatalf("handleAutomation(dca) unexpected error: %v", err)
+		}
+		if err := cli.handleAutomation([]string{"grid", "ETHUSDT", "2000", "3000", "5", "0.01"}); err != nil {
+			t.Fatalf("handleAutomation(grid) unexpected error: %v", err)
+		}
+
+		buf.Reset()
+		if err := cli.handleAutomation([]string{"status"}); err != nil {
+			t.Fatalf("handleAutomation(status) unexpected error: %v", err)
+		}
+
+		output := buf.String()
+		expectedFields := []string{
+			"1/2 active",
+			"1/1 active",
+			"BTCUSDT",
+			"ETHUSDT",
+			"24h0m0s",
+			"Buys Placed:  0",
+			"Open Orders:  0",
+		}
+
+		for _, field := range expectedFields {
+			if !strings.Contains(output, field) {
+				t.Errorf("automation status output should contain %s", field)
+			}
+		}
+	})
+
+	t.Run("cap exceeded", func(t *testing.T) {
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		cli.SetAutomationService(service.NewAutomationService(&mockTradingService{}, &mockMarketDataService{}, &config.AutomationConfig{MaxDCAPlans: 1, MaxGrids: 1}, &mockLogger{}))
+
+		var buf bytes.Buffer
+		cli.writer = &buf
+
+		if err := cli.handleAutomation([]string{"dca", "BTCUSDT", "0.001", "1h"}); err != nil {
+			t.Fatalf("handleAutomation(dca) unexpected error: %v", err)
+		}
+
+		err := cli.handleAutomation([]string{"dca", "ETHUSDT", "0.01", "1h"})
+		if err == nil || !strings.Contains(err.Error(), "maximum active DCA plans reached") {
+			t.Errorf("handleAutomation(dca) expected cap error, got %v", err)
+		}
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+
+		if err := cli.handleAutomation([]string{"status"}); err == nil {
+			t.Errorf("handleAutomation() expected error when automation service is not set")
+		}
+	})
+}
+
+func TestHandleSymbolPauses(t *testing.T) {
+	cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+	guard := service.NewSymbolFailureGuard(&config.FailurePauseConfig{MaxFailures: 1}, &mockLogger{})
+	cli.SetSymbolGuard(guard)
+
+	var buf bytes.Buffer
+	cli.writer = &buf
+
+	if err := cli.executeCommand(&Command{Name: "paused"}); err != nil {
+		t.Fatalf("paused unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No paused symbols") {
+		t.Errorf("expected empty pause list, got %s", buf.String())
+	}
+
+	guard.RecordFailure("BTCUSDT", fmt.Errorf("invalid symbol"))
+
+	buf.Reset()
+	if err := cli.executeCommand(&Command{Name: "paused"}); err != nil {
+		t.Fatalf("paused unexpected error: %v", err)
+	}
+	for _, field := range []string{"Paused Symbols (1)", "BTCUSDT", "invalid symbol"} {
+		if !strings.Contains(buf.String(), field) {
+			t.Errorf("paused output should contain %s", field)
+		}
+	}
+
+	buf.Reset()
+	if err := cli.executeCommand(&Command{Name: "paused", Args: []string{"clear", "btcusdt"}}); err != nil {
+		t.Fatalf("paused clear unexpected error: %v", err)
+	}
+	if err := guard.CheckSymbol("BTCUSDT"); err != nil {
+		t.Errorf("expected pause to be cleared, got %v", err)
+	}
+	if err := cli.executeCommand(&Command{Name: "paused", Args: []string{"clear", "BTCUSDT"}}); err == nil {
+		t.Error("expected error when clearing a symbol that is not paused")
+	}
+}
+
+func TestHandleDailyOrders(t *testing.T) {
+	cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+	if err := cli.executeCommand(&Command{Name: "dailyorders"}); err == nil {
+		t.Error("expected error without a risk manager")
+	}
+
+	riskMgr := service.NewRiskManager(&service.RiskLimits{MaxOrderAmount: 10000, MaxDailyOrders: 5}, nil)
+	riskMgr.(interface{ RecordOrder(float64) }).RecordOrder(100)
+	riskMgr.(interface{ RecordOrder(float64) }).RecordOrder(100)
+	cli.SetRiskManager(riskMgr)
+
+	var buf bytes.Buffer
+	cli.writer = &buf
+	if err := cli.executeCommand(&Command{Name: "dailyorders"}); err != nil {
+		t.Fatalf("dailyorders unexpected error: %v", err)
+	}
+	stats := riskMgr.GetDailyOrderStats()
+	for _, field := range []string{"Daily Orders (" + stats.Date + " UTC)", "Placed:    2 / 5", "Remaining: 3", "Resets:"} {
+		if !strings.Contains(buf.String(), field) {
+			t.Errorf("dailyorders output should contain %q:\n%s", field, buf.String())
+		}
+	}
+	if err := cli.executeCommand(&Command{Name: "dailyorders", Args: []string{"reset"}}); !errors.Is(err, ErrUsage) {
+		t.Errorf("dailyorders reset error = %v, want a usage error", err)
+	}
+}
+
+// mockRateLimitProvider returns a fixed rate-limit status
+type mockRateLimitProvider struct {
+	status *api.RateLimitStatus
+}
+
+func (m *mockRateLimitProvider) GetRateLimitStatus() *api.RateLimitStatus {
+	return m.status
+}
+
+func TestHandleRateLimitStatus(t *testing.T) {
+	cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+
+	var buf bytes.Buffer
+	cli.writer = &buf
+
+	if err := cli.executeCommand(&Command{Name: "ratelimit"}); err == nil {
+		t.Error("expected error when no rate-limit provider is set")
+	}
+
+	provider := &mockRateLimitProvider{status: &api.RateLimitStatus{}}
+	cli.SetRateLimitStatusProvider(provider)
+	if err := cli.executeCommand(&Command{Name: "ratelimit"}); err != nil {
+		t.Fatalf("ratelimit unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No rate-limit data received yet") {
+		t.Errorf("expected empty status message, got %s", buf.String())
+	}
+
+	provider.status = &api.RateLimitStatus{
+		UsedWeight:   map[string]int{"1M": 1500},
+		OrderCounts:  map[string]int{"10S": 5},
+		WeightLimits: map[string]int{"1M": 6000},
+		OrderLimits:  map[string]int{"10S": 100, "1D": 200000},
+		UpdatedAt:    time.Now().Unix(),
+	}
+
+	buf.Reset()
+	if err := cli.executeCommand(&Command{Name: "ratelimit"}); err != nil {
+		t.Fatalf("ratelimit unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	expectedFields := []string{
+		"1M:   1500 / 6000 (25.0%)",
+		"10S:  5 / 100 (5.0%)",
+		"1D:   0 / 200000 (0.0%)",
+	}
+	for _, field := range expectedFields {
+		if !strings.Contains(output, field) {
+			t.Errorf("ratelimit output should contain %q, got:\n%s", field, output)
+		}
+	}
 }
 
-// TestFormatConditionalOrder tests conditional order formatting
-func TestFormatConditionalOrder(t *testing.T) {
-	cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+// mockCarryService is a mock implementation of CarryService
+type mockCarryService struct {
+	positions []*service.CarryPosition
+	openErr   error
+}
+
+func (m *mockCarryService) Open(symbol string, notional float64) (*service.CarryPosition, error) {
+	if m.openErr != nil {
+		return nil, m.openErr
+	}
+	position := &service.CarryPosition{Symbol: symbol, Status: service.CarryStatusOpen, SpotQuantity: notional / 100, SpotLegOpen: true, FuturesLegOpen: true}
+	m.positions = append(m.positions, position)
+	return position, nil
+}
+
+func (m *mockCarryService) Close(symbol string, reason string) (*service.CarryPosition, error) {
+	for _, position := range m.positions {
+		if position.Symbol == symbol {
+			position.Status = service.CarryStatusClosed
+			position.CloseReason = reason
+			return position, nil
+		}
+	}
+	return nil, fmt.Errorf("no active carry for %s", symbol)
+}
+
+func (m *mockCarryService) GetPositions() []*service.CarryPosition {
+	return m.positions
+}
+
+func (m *mockCarryService) CheckPositions() {}
+
+func (m *mockCarryService) StartMonitoring(checkInterval time.Duration) error {
+	return nil
+}
+
+func (m *mockCarryService) StopMonitoring() error {
+	return nil
+}
+
+// mockFuturesMarginService records margin type changes; other trading methods are not used
+type mockFuturesMarginService struct {
+	service.FuturesTradingService
+	marginTypes map[string]api.MarginType
+}
+
+func (m *mockFuturesMarginService) SetMarginType(symbol string, marginType api.MarginType) error {
+	m.marginTypes[symbol] = marginType
+	return nil
+}
 
+func TestHandleMarginType(t *testing.T) {
+	trading := &mockFuturesMarginService{marginTypes: make(map[string]api.MarginType)}
+	cli := NewFuturesCLI(trading, nil, nil, nil, nil, &mockLogger{})
 	var buf bytes.Buffer
 	cli.writer = &buf
 
-	order := &repository.ConditionalOrder{
-		OrderID:  "cond-12345",
-		Symbol:   "BTCUSDT",
-		Side:     api.OrderSideBuy,
-		Type:     api.OrderTypeMarket,
-		Quantity: 0.001,
-		Status:   repository.ConditionalOrderStatusPending,
-		TriggerCondition: &repository.TriggerCondition{
-			Type:     repository.TriggerTypePrice,
-			Operator: repository.OperatorGreaterEqual,
-			Value:    50000,
-		},
+	if err := cli.executeCommand(&Command{Name: "margin-type", Args: []string{"btcusdt", "isolated"}}); err != nil {
+		t.Fatalf("margin-type unexpected error: %v", err)
+	}
+	if trading.marginTypes["BTCUSDT"] != api.MarginTypeIsolated {
+		t.Errorf("margin types = %v, want BTCUSDT ISOLATED", trading.marginTypes)
+	}
+	if !strings.Contains(buf.String(), "Margin type set to ISOLATED for BTCUSDT") {
+		t.Errorf("unexpected output: %s", buf.String())
 	}
 
-	cli.formatConditionalOrder(order)
+	if err := cli.executeCommand(&Command{Name: "margin-type", Args: []string{"ETHUSDT", "cross"}}); err != nil || trading.marginTypes["ETHUSDT"] != api.MarginTypeCrossed {
+		t.Errorf("margin-type CROSS = %v, %v; want CROSSED", trading.marginTypes["ETHUSDT"], err)
+	}
+	if err := cli.executeCommand(&Command{Name: "margin-type", Args: []string{"ETHUSDT", "portfolio"}}); err == nil {
+		t.Error("expected error for an unknown margin type")
+	}
+}
+
+// mockFuturesPositionModeService records position mode changes; other trading methods are not used
+type mockFuturesPositionModeService struct {
+	service.FuturesTradingService
+	modes []bool
+}
+
+func (m *mockFuturesPositionModeService) SetPositionMode(dualSide bool) error {
+	m.modes = append(m.modes, dualSide)
+	return nil
+}
+
+func TestHandlePositionMode(t *testing.T) {
+	trading := &mockFuturesPositionModeService{}
+	cli := NewFuturesCLI(trading, nil, nil, nil, nil, &mockLogger{})
+	var buf bytes.Buffer
+	cli.writer = &buf
+
+	if err := cli.executeCommand(&Command{Name: "position-mode", Args: []string{"hedge"}}); err != nil {
+		t.Fatalf("position-mode unexpected error: %v", err)
+	}
+	if err := cli.executeCommand(&Command{Name: "position-mode", Args: []string{"One-Way"}}); err != nil {
+		t.Fatalf("position-mode unexpected error: %v", err)
+	}
+	if len(trading.modes) != 2 || !trading.modes[0] || trading.modes[1] {
+		t.Errorf("position modes = %v, want [true false]", trading.modes)
+	}
+	if !strings.Contains(buf.String(), "Position mode set to HEDGE") || !strings.Contains(buf.String(), "Position mode set to ONEWAY") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+
+	for _, args := range [][]string{nil, {"true"}} {
+		if err := cli.executeCommand(&Command{Name: "position-mode", Args: args}); err == nil {
+			t.Errorf("position-mode %v should fail", args)
+		}
+	}
+	if len(trading.modes) != 2 {
+		t.Errorf("position modes = %v, want no more after invalid arguments", trading.modes)
+	}
+}
+
+// mockFuturesBalanceService returns a fixed account; other trading methods are not used
+type mockFuturesBalanceService struct {
+	service.FuturesTradingService
+	account *api.FuturesAccountInfo
+	err     error
+}
 
+func (m *mockFuturesBalanceService) GetAccountBalance() (*api.FuturesAccountInfo, error) {
+	return m.account, m.err
+}
+
+func TestHandleFuturesBalance(t *testing.T) {
+	trading := &mockFuturesBalanceService{account: &api.FuturesAccountInfo{
+		TotalWalletBalance:    1000,
+		TotalUnrealizedProfit: -25.5,
+		TotalMarginBalance:    974.5,
+		AvailableBalance:      800,
+		Assets: []api.FuturesAssetBalance{
+			{Asset: "USDT", WalletBalance: 1000, UnrealizedProfit: -25.5, MarginBalance: 974.5, AvailableBalance: 800},
+			{Asset: "BUSD"},
+		},
+	}}
+	cli := NewFuturesCLI(trading, nil, nil, nil, nil, &mockLogger{})
+	var buf bytes.Buffer
+	cli.writer = &buf
+
+	if err := cli.executeCommand(&Command{Name: "futures-balance"}); err != nil {
+		t.Fatalf("futures-balance unexpected error: %v", err)
+	}
 	output := buf.String()
+	for _, want := range []string{"Wallet Balance:   1000.00000000", "Unrealized PnL:   -25.50000000", "Margin Balance:   974.50000000",
+		"Available Margin: 800.00000000", "Asset:            USDT"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "BUSD") {
+		t.Errorf("output lists the unfunded BUSD asset:\n%s", output)
+	}
 
-	expectedFields := []string{
-		"cond-12345",
-		"BTCUSDT",
-		"BUY",
-		"MARKET",
-		"0.001",
-		"PENDING",
-		"PRICE",
-		">=",
-		"50000",
+	trading.err = fmt.Errorf("connection refused")
+	if err := cli.executeCommand(&Command{Name: "futures-balance"}); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("futures-balance error = %v, want the service error", err)
 	}
+}
 
-	for _, field := range expectedFields {
-		if !strings.Contains(output, field) {
-			t.Errorf("formatConditionalOrder() output should contain %s", field)
+// mockFuturesLimitService records the limit orders opening positions; other trading methods are not used
+type mockFuturesLimitService struct {
+	service.FuturesTradingService
+	orders []*api.FuturesOrder
+}
+
+func (m *mockFuturesLimitService) open(side api.OrderSide, positionSide api.PositionSide, symbol string, quantity float64, orderType api.OrderType, price float64) (*api.FuturesOrder, error) {
+	order := &api.FuturesOrder{OrderID: int64(len(m.orders) + 1), Symbol: symbol, Side: side, PositionSide: positionSide,
+		Type: orderType, Status: api.OrderStatusNew, Price: price, OrigQty: quantity}
+	m.orders = append(m.orders, order)
+	return order, nil
+}
+
+func (m *mockFuturesLimitService) OpenLongPosition(symbol string, quantity float64, orderType api.OrderType, price float64) (*api.FuturesOrder, error) {
+	return m.open(api.OrderSideBuy, api.PositionSideLong, symbol, quantity, orderType, price)
+}
+
+func (m *mockFuturesLimitService) OpenShortPosition(symbol string, quantity float64, orderType api.OrderType, price float64) (*api.FuturesOrder, error) {
+	return m.open(api.OrderSideSell, api.PositionSideShort, symbol, quantity, orderType, price)
+}
+
+func TestHandleLimitPositions(t *testing.T) {
+	trading := &mockFuturesLimitService{}
+	cli := NewFuturesCLI(trading, nil, nil, nil, nil, &mockLogger{})
+	var buf bytes.Buffer
+	cli.writer = &buf
+
+	if err := cli.executeCommand(&Command{Name: "limit-long", Args: []string{"btcusdt", "48000", "0.01"}}); err != nil {
+		t.Fatalf("limit-long unexpected error: %v", err)
+	}
+	if err := cli.executeCommand(&Command{Name: "limit-short", Args: []string{"BTCUSDT", "52000", "0.02"}}); err != nil {
+		t.Fatalf("limit-short unexpected error: %v", err)
+	}
+	if len(trading.orders) != 2 {
+		t.Fatalf("orders = %d, want 2", len(trading.orders))
+	}
+	long, short := trading.orders[0], trading.orders[1]
+	if long.Symbol != "BTCUSDT" || long.PositionSide != api.PositionSideLong || long.Type != api.OrderTypeLimit || long.Price != 48000 || long.OrigQty != 0.01 {
+		t.Errorf("limit-long order = %+v, want a LONG limit of 0.01 BTCUSDT at 48000", long)
+	}
+	if short.PositionSide != api.PositionSideShort || short.Type != api.OrderTypeLimit || short.Price != 52000 || short.OrigQty != 0.02 {
+		t.Errorf("limit-short order = %+v, want a SHORT limit of 0.02 at 52000", short)
+	}
+	if !strings.Contains(buf.String(), "Limit Long Order Placed") || !strings.Contains(buf.String(), "Limit Short Order Placed") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+
+	for _, args := range [][]string{
+		{"BTCUSDT", "0.01"},
+		{"BTCUSDT", "0", "0.01"},
+		{"BTCUSDT", "price", "0.01"},
+		{"BTCUSDT", "48000", "-1"},
+	} {
+		if err := cli.executeCommand(&Command{Name: "limit-long", Args: args}); err == nil {
+			t.Errorf("limit-long %v should fail", args)
 		}
 	}
+	if len(trading.orders) != 2 {
+		t.Errorf("orders = %d, want no more after invalid arguments", len(trading.orders))
+	}
 }
 
-// TestFormatStopOrder tests stop order formatting
-func TestFormatStopOrder(t *testing.T) {
-	cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+// TestHandleCarry tests the futures carry command handler
+func TestHandleCarry(t *testing.T) {
+	var buf bytes.Buffer
+	cli := NewFuturesCLI(nil, nil, nil, nil, nil, &mockLogger{})
+	cli.writer = &buf
+
+	if err := cli.executeCommand(&Command{Name: "carry", Args: []string{"status"}}); err == nil {
+		t.Error("expected error when carry service is not configured")
+	}
+
+	cli.SetCarryService(&mockCarryService{})
+
+	if err := cli.executeCommand(&Command{Name: "carry", Args: []string{"status"}}); err != nil {
+		t.Fatalf("carry status unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No carry positions") {
+		t.Errorf("expected empty carry list, got %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := cli.executeCommand(&Command{Name: "carry", Args: []string{"open", "btcusdt", "1000"}}); err != nil {
+		t.Fatalf("carry open unexpected error: %v", err)
+	}
+	for _, field := range []string{"Carry position opened", "BTCUSDT (OPEN)"} {
+		if !strings.Contains(buf.String(), field) {
+			t.Errorf("carry open output should contain %s, got %s", field, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := cli.executeCommand(&Command{Name: "carry", Args: []string{"close", "BTCUSDT"}}); err != nil {
+		t.Fatalf("carry close unexpected error: %v", err)
+	}
+	for _, field := range []string{"BTCUSDT (CLOSED)", "manual close"} {
+		if !strings.Contains(buf.String(), field) {
+			t.Errorf("carry close output should contain %s, got %s", field, buf.String())
+		}
+	}
+
+	if err := cli.executeCommand(&Command{Name: "carry", Args: []string{"open", "BTCUSDT"}}); err == nil {
+		t.Error("expected usage error for missing notional")
+	}
+}
 
+func TestHandleTrace(t *testing.T) {
 	var buf bytes.Buffer
+	mockTrading := &mockTradingService{
+		getOrderLifecycleFunc: func(symbol string, orderID int64) (*service.OrderLifecycle, error) {
+			return &service.OrderLifecycle{
+				Symbol:    symbol,
+				OrderID:   orderID,
+				Exchange:  &api.Order{OrderID: orderID, Symbol: symbol, Side: api.OrderSideBuy, Type: api.OrderTypeLimit, Status: api.OrderStatusFilled, OrigQty: 0.001},
+				FilledQty: 0.001,
+				QuoteQty:  50,
+				AvgPrice:  50000,
+				Fees:      map[string]float64{"BNB": 0.00002},
+				Events: []*service.LifecycleEvent{
+					{Type: service.LifecycleEventCreated, Time: 1700000000000, Status: api.OrderStatusNew, Price: 50000, Quantity: 0.001},
+					{Type: service.LifecycleEventFill, Time: 1700000001000, Status: api.OrderStatusFilled, Price: 50000, Quantity: 0.001, CumulativeQty: 0.001, Commission: 0.00002, CommissionAsset: "BNB", TradeID: 501},
+					{Type: service.LifecycleEventFinal, Time: 1700000001000, Status: api.OrderStatusFilled},
+				},
+				Discrepancies: []string{"local status NEW differs from exchange status FILLED"},
+			}, nil
+		},
+	}
+	cli := NewCLI(mockTrading, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
 	cli.writer = &buf
 
-	order := &repository.StopOrder{
-		OrderID:   "sl-12345",
-		Symbol:    "BTCUSDT",
-		Position:  0.001,
-		StopPrice: 49000,
-		Type:      repository.StopOrderTypeStopLoss,
-		Status:    repository.StopOrderStatusActive,
+	if err := cli.executeCommand(&Command{Name: "trace", Args: []string{"btcusdt", "12345"}}); err != nil {
+		t.Fatalf("trace unexpected error: %v", err)
+	}
+	for _, field := range []string{"Order Trace: BTCUSDT #12345", "CREATED", "FILL", "trade 501", "0.00002000 BNB", "Discrepancies", "local status NEW"} {
+		if !strings.Contains(buf.String(), field) {
+			t.Errorf("trace output should contain %q, got:\n%s", field, buf.String())
+		}
 	}
 
-	cli.formatStopOrder(order)
+	if err := cli.executeCommand(&Command{Name: "trace", Args: []string{"BTCUSDT"}}); !errors.Is(err, ErrUsage) {
+		t.Errorf("expected usage error for missing order ID, got %v", err)
+	}
+}
+
+// mockCoverageChecker returns a fixed coverage report
+type mockCoverageChecker struct {
+	report *service.CoverageReport
+}
+
+func (m *mockCoverageChecker) CheckCoverage() (*service.CoverageReport, error) {
+	return m.report, nil
+}
+
+func (m *mockCoverageChecker) OnAlert(callback func(report *service.CoverageReport)) {}
+
+func (m *mockCoverageChecker) StartMonitoring(checkInterval time.Duration) error {
+	return nil
+}
+
+func (m *mockCoverageChecker) StopMonitoring() error {
+	return nil
+}
+
+func TestHandleCoverage(t *testing.T) {
+	var buf bytes.Buffer
+	cli := NewFuturesCLI(nil, nil, nil, nil, nil, &mockLogger{})
+	cli.writer = &buf
+
+	if err := cli.executeCommand(&Command{Name: "coverage"}); err == nil {
+		t.Error("expected error when coverage checker is not configured")
+	}
+
+	cli.SetCoverageChecker(&mockCoverageChecker{report: &service.CoverageReport{
+		Exposures: []*service.ExposureCoverage{
+			{Symbol: "BTCUSDT", PositionSide: api.PositionSideLong, Long: true, Quantity: 0.2, Price: 50000, ProtectedQty: 0.2, StopPrice: 48000, StopDistancePct: 4, HasTakeProfit: true, LocalStops: 1},
+			{Symbol: "ETHUSDT", PositionSide: api.PositionSideBoth, Quantity: 1, Price: 3000, UnprotectedQty: 1},
+		},
+		CoveragePct: 76.9,
+		Unprotected: []string{"ETHUSDT"},
+	}})
+
+	if err := cli.executeCommand(&Command{Name: "coverage"}); err != nil {
+		t.Fatalf("coverage unexpected error: %v", err)
+	}
+	for _, field := range []string{"BTCUSDT LONG", "(100.0%, 1 local, 0 native)", "48000.00000000 (4.00% away)", "Take Profit:  yes", "Stop:         none", "WARNING: 76.9% of notional protected; fully unprotected: ETHUSDT"} {
+		if !strings.Contains(buf.String(), field) {
+			t.Errorf("coverage output should contain %q, got:\n%s", field, buf.String())
+		}
+	}
+
+	buf.Reset()
+	spot := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+	spot.writer = &buf
+	spot.SetCoverageChecker(&mockCoverageChecker{report: &service.CoverageReport{CoveragePct: 100}})
+
+	if err := spot.executeCommand(&Command{Name: "coverage"}); err != nil {
+		t.Fatalf("spot coverage unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No open positions or holdings") {
+		t.Errorf("expected empty coverage report, got %s", buf.String())
+	}
+}
+
+// mockDustConverter records the assets passed to ConvertDust
+type mockDustConverter struct {
+	converted []string
+	result    *api.DustConversionResult
+}
+
+func (m *mockDustConverter) FindDust() ([]*api.DustAsset, error) {
+	return nil, nil
+}
+
+func (m *mockDustConverter) ConvertDust(assets []string) (*api.DustConversionResult, error) {
+	m.converted = assets
+	return m.result, nil
+}
+
+func (m *mockDustConverter) StartMonitoring(checkInterval time.Duration) error {
+	return nil
+}
+
+func (m *mockDustConverter) StopMonitoring() error {
+	return nil
+}
+
+func TestHandleDust(t *testing.T) {
+	var buf bytes.Buffer
+	cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+	cli.writer = &buf
+
+	if err := cli.executeCommand(&Command{Name: "dust"}); err == nil {
+		t.Error("expected error when dust converter is not configured")
+	}
+
+	converter := &mockDustConverter{result: &api.DustConversionResult{
+		TotalTransferred:   0.003,
+		TotalServiceCharge: 0.00006,
+		Transfers: []*api.DustTransfer{
+			{FromAsset: "SHIB", Amount: 12000, TransferredAmount: 0.002, ServiceChargeAmount: 0.00004},
+			{FromAsset: "DOGE", Amount: 3, TransferredAmount: 0.001, ServiceChargeAmount: 0.00002},
+		},
+	}}
+	cli.SetDustConverter(converter)
+
+	if err := cli.executeCommand(&Command{Name: "dust", Args: []string{"SHIB", "DOGE"}}); err != nil {
+		t.Fatalf("dust unexpected error: %v", err)
+	}
+	if strings.Join(converter.converted, ",") != "SHIB,DOGE" {
+		t.Errorf("expected named assets to be converted, got %v", converter.converted)
+	}
+	for _, field := range []string{"SHIB", "-> 0.00200000 BNB", "BNB Received: 0.00300000 (fees 0.00006000)"} {
+		if !strings.Contains(buf.String(), field) {
+			t.Errorf("dust output should contain %q, got:\n%s", field, buf.String())
+		}
+	}
+
+	buf.Reset()
+	converter.result = &api.DustConversionResult{}
+	if err := cli.executeCommand(&Command{Name: "dust"}); err != nil {
+		t.Fatalf("dust unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No dust to convert") {
+		t.Errorf("expected empty conversion report, got %s", buf.String())
+	}
+}
+
+// mockExchangeInfoCache serves fixed symbol filters and counts refreshes
+type mockExchangeInfoCache struct {
+	symbols   map[string]*api.SymbolInfo
+	refreshes int
+}
+
+func (m *mockExchangeInfoCache) GetSymbolInfo(symbol string) (*api.SymbolInfo, error) {
+	if info, ok := m.symbols[symbol]; ok {
+		return info, nil
+	}
+	return nil, fmt.Errorf("symbol %s is not listed in exchange info", symbol)
+}
+
+func (m *mockExchangeInfoCache) Refresh() error {
+	m.refreshes++
+	return nil
+}
+
+func (m *mockExchangeInfoCache) UpdatedAt() time.Time {
+	return time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+}
+
+func TestHandleFilters(t *testing.T) {
+	cache := &mockExchangeInfoCache{symbols: map[string]*api.SymbolInfo{
+		"BTCUSDT": {Symbol: "BTCUSDT", Status: "TRADING", MinQty: 0.00001, MaxQty: 9000, StepSize: 0.00001, MinPrice: 0.01, TickSize: 0.01, MinNotional: 5},
+	}}
+	cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+	var buf bytes.Buffer
+	cli.writer = &buf
+
+	if err := cli.handleFilters([]string{"BTCUSDT"}); err == nil {
+		t.Error("handleFilters() without an exchange info cache should fail")
+	}
 
+	cli.SetExchangeInfoCache(cache)
+	if err := cli.handleFilters([]string{"btcusdt"}); err != nil {
+		t.Fatalf("handleFilters() error = %v", err)
+	}
 	output := buf.String()
+	for _, want := range []string{"BTCUSDT (TRADING)", "0.00001 - 9000, step 0.00001", "0.01 - -, tick 0.01", "Min Notional: 5"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("handleFilters() output missing %q:\n%s", want, output)
+		}
+	}
 
-	expectedFields := []string{
-		"sl-12345",
-		"BTCUSDT",
-		"STOP_LOSS",
-		"0.001",
-		"49000",
-		"ACTIVE",
+	if err := cli.handleFilters([]string{"refresh"}); err != nil || cache.refreshes != 1 {
+		t.Errorf("filters refresh error = %v, refreshes = %d, want 1", err, cache.refreshes)
 	}
+	if err := cli.handleFilters(nil); !errors.Is(err, ErrUsage) {
+		t.Errorf("handleFilters() without arguments error = %v, want usage", err)
+	}
+}
 
-	for _, field := range expectedFields {
-		if !strings.Contains(output, field) {
-			t.Errorf("formatStopOrder() output should contain %s", field)
+type mockPaperAccount struct{}
+
+func (m *mockPaperAccount) GetBalance(asset string) (*api.Balance, error) {
+	return &api.Balance{Asset: asset, Free: 8999, Locked: 1}, nil
+}
+
+func (m *mockPaperAccount) GetExchangeBalance(asset string) (*api.Balance, error) {
+	return &api.Balance{Asset: asset, Free: 250}, nil
+}
+
+func (m *mockPaperAccount) GetPaperSummary() (*service.PaperSummary, error) {
+	return &service.PaperSummary{StartingBalance: 10000, Equity: 9938, PnL: -62, PnLPercent: -0.62}, nil
+}
+
+func TestHandleBalance_PaperTrading(t *testing.T) {
+	cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+	var buf bytes.Buffer
+	cli.writer = &buf
+
+	// Without a paper account the balance is the exchange one
+	if err := cli.handleBalance([]string{"USDT"}); err != nil || strings.Contains(buf.String(), "PAPER TRADING") {
+		t.Errorf("handleBalance() without a paper account = %v:\n%s", err, buf.String())
+	}
+	buf.Reset()
+
+	cli.SetPaperAccount(&mockPaperAccount{})
+	if err := cli.handleBalance([]string{"usdt"}); err != nil {
+		t.Fatalf("handleBalance() error = %v", err)
+	}
+	output := buf.String()
+	for _, want := range []string{
+		"USDT (PAPER TRADING)",
+		"Paper:        8999.00000000 free, 1.00000000 locked",
+		"Exchange:     250.00000000 free",
+		"9938.00000000 USDT (started with 10000.00000000)",
+		"-62.00000000 USDT (-0.62%)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("handleBalance() output missing %q:\n%s", want, output)
 		}
 	}
+
+	// The holdings table lists what the trading service reports, which is virtual in paper trading
+	buf.Reset()
+	if err := cli.handleBalance(nil); err != nil || !strings.Contains(buf.String(), "No balances") {
+		t.Errorf("handleBalance() without holdings = %v:\n%s", err, buf.String())
+	}
 }
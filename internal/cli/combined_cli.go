@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"binance-trader/internal/config"
+)
+
+// CombinedCLI runs the spot and futures CLIs behind one prompt. Commands prefixed with
+// "spot" or "fut" go to that market; other commands go to the active context.
+type CombinedCLI struct {
+	spot    *CLI
+	futures *FuturesCLI
+	context config.TradingType
+	reader  io.Reader
+	writer  io.Writer
+}
+
+// NewCombinedCLI creates a combined CLI starting in the spot context
+func NewCombinedCLI(spot *CLI, futures *FuturesCLI) *CombinedCLI {
+	c := &CombinedCLI{
+		spot:    spot,
+		futures: futures,
+		context: config.TradingTypeSpot,
+		reader:  os.Stdin,
+	}
+	c.setWriter(os.Stdout)
+	return c
+}
+
+// setWriter directs the output of the combined CLI and both market CLIs to w
+func (c *CombinedCLI) setWriter(w io.Writer) {
+	c.writer = w
+	c.spot.writer = w
+	c.futures.writer = w
+}
+
+// Context returns the market that receives commands without a prefix
+func (c *CombinedCLI) Context() config.TradingType {
+	return c.context
+}
+
+// Run starts the interactive CLI
+func (c *CombinedCLI) Run() error {
+	c.printWelcome()
+
+	scanner := bufio.NewScanner(c.reader)
+	for {
+		fmt.Fprintf(c.writer, "\n[%s]> ", contextPrompt(c.context))
+
+		if !scanner.Scan() {
+			break
+		}
+
+		input := scanner.Text()
+		cmd, err := ParseCommand(input)
+		if err != nil {
+			fmt.Fprintf(c.writer, "Error: %s\n", err.Error())
+			continue
+		}
+
+		if cmd.Name == "exit" || cmd.Name == "quit" {
+			fmt.Fprintln(c.writer, "Goodbye!")
+			break
+		}
+
+		if err := c.executeCommand(cmd); err != nil {
+			fmt.Fprintf(c.writer, "Error: %s\n", err.Error())
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("scanner error: %w", err)
+	}
+
+	return nil
+}
+
+// executeCommand routes a parsed command to the spot or futures CLI. Errors of the market
+// command are reported by that CLI; only routing errors are returned.
+func (c *CombinedCLI) executeCommand(cmd *Command) error {
+	target := c.context
+
+	switch cmd.Name {
+	case "use":
+		if len(cmd.Args) != 1 {
+			return fmt.Errorf("%w: use <spot|fut>", ErrUsage)
+		}
+		context, err := parseContext(cmd.Args[0])
+		if err != nil {
+			return err
+		}
+		c.switchContext(context)
+		return nil
+	case "spot", "fut", "futures":
+		target, _ = parseContext(cmd.Name)
+		if len(cmd.Args) == 0 {
+			c.switchContext(target)
+			return nil
+		}
+		cmd = &Command{Name: strings.ToLower(cmd.Args[0]), Args: cmd.Args[1:]}
+	case "help":
+		if len(cmd.Args) == 0 {
+			c.printRouting()
+		}
+	}
+
+	if target == config.TradingTypeFutures {
+		if err := c.futures.executeCommand(cmd); err != nil {
+			c.futures.commands.reportError(c.writer, cmd, err)
+		}
+		return nil
+	}
+
+	if err := c.spot.executeCommand(cmd); err != nil {
+		c.spot.commands.reportError(c.writer, cmd, err)
+	}
+	return nil
+}
+
+// switchContext makes commands without a prefix go to the given market
+func (c *CombinedCLI) switchContext(context config.TradingType) {
+	c.context = context
+	fmt.Fprintf(c.writer, "Switched to %s context\n", context)
+}
+
+// printWelcome prints the welcome message
+func (c *CombinedCLI) printWelcome() {
+	fmt.Fprintln(c.writer, "===========================================")
+	fmt.Fprintln(c.writer, "  Binance Spot & Futures Trading System")
+	fmt.Fprintln(c.writer, "===========================================")
+	if c.spot.dryRun || c.futures.dryRun {
+		printDryRunBanner(c.writer)
+	}
+	fmt.Fprintln(c.writer, "Prefix commands with 'spot' or 'fut', or switch with 'use <spot|fut>'")
+	fmt.Fprintln(c.writer, "Type 'help' for available commands")
+}
+
+// printRouting prints how commands are routed, ahead of the active market's help
+func (c *CombinedCLI) printRouting() {
+	fmt.Fprintln(c.writer, "\nMarket Routing:")
+	fmt.Fprintf(c.writer, "  %-32s - %s\n", "spot <command>", "Run a spot command")
+	fmt.Fprintf(c.writer, "  %-32s - %s\n", "fut <command>", "Run a futures command")
+	fmt.Fprintf(c.writer, "  %-32s - %s\n", "use <spot|fut>", "Route commands without a prefix to a market")
+	fmt.Fprintf(c.writer, "\nActive context: %s\n", c.context)
+}
+
+// parseContext parses a market name accepted by the routing commands
+func parseContext(name string) (config.TradingType, error) {
+	switch strings.ToLower(name) {
+	case "spot":
+		return config.TradingTypeSpot, nil
+	case "fut", "futures":
+		return config.TradingTypeFutures, nil
+	default:
+		return "", fmt.Errorf("unknown market %q, expected spot or fut", name)
+	}
+}
+
+// contextPrompt returns the short market name shown in the prompt
+func contextPrompt(context config.TradingType) string {
+	if context == config.TradingTypeFutures {
+		return "fut"
+	}
+	return "spot"
+}
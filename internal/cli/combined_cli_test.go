@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"binance-trader/internal/config"
+)
+
+func newTestCombinedCLI(input string) (*CombinedCLI, *bytes.Buffer, *[]string) {
+	var priced []string
+	mockMarket := &mockMarketDataService{
+		getCurrentPriceFunc: func(symbol string) (float64, error) {
+			priced = append(priced, symbol)
+			return 50000.0, nil
+		},
+	}
+
+	spot := NewCLI(&mockTradingService{}, mockMarket, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+	futures := NewFuturesCLI(nil, nil, nil, nil, nil, &mockLogger{})
+
+	var buf bytes.Buffer
+	combined := NewCombinedCLI(spot, futures)
+	combined.reader = strings.NewReader(input)
+	combined.setWriter(&buf)
+	return combined, &buf, &priced
+}
+
+func TestCombinedCLI_PrefixRouting(t *testing.T) {
+	combined, buf, priced := newTestCombinedCLI("spot price BTCUSDT\nfut help mark-price\nfut price ETHUSDT\nexit\n")
+
+	if err := combined.Run(); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	if len(*priced) != 1 || (*priced)[0] != "BTCUSDT" {
+		t.Errorf("expected only the spot-prefixed price command to reach spot, got %v", *priced)
+	}
+	if !strings.Contains(output, "mark-price - Get mark price") {
+		t.Errorf("expected futures help for mark-price, got %q", output)
+	}
+	if !strings.Contains(output, "unknown command: price") {
+		t.Errorf("price is a spot command and should be unknown in futures, got %q", output)
+	}
+	if combined.Context() != config.TradingTypeSpot {
+		t.Errorf("prefixed commands should not change the context, got %s", combined.Context())
+	}
+}
+
+func TestCombinedCLI_ContextToggle(t *testing.T) {
+	combined, buf, priced := newTestCombinedCLI("use fut\nprice BTCUSDT\nspot\nprice ETHUSDT\nuse margin\nexit\n")
+
+	if err := combined.Run(); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "Switched to futures context") || !strings.Contains(output, "[fut]> ") {
+		t.Errorf("expected switch to the futures context, got %q", output)
+	}
+	if len(*priced) != 1 || (*priced)[0] != "ETHUSDT" {
+		t.Errorf("expected only the command issued in the spot context to reach spot, got %v", *priced)
+	}
+	if !strings.Contains(output, `unknown market "margin"`) {
+		t.Errorf("expected error for unknown market, got %q", output)
+	}
+	if combined.Context() != config.TradingTypeSpot {
+		t.Errorf("expected spot context, got %s", combined.Context())
+	}
+}
+
+func TestCombinedCLI_HelpShowsRouting(t *testing.T) {
+	combined, buf, _ := newTestCombinedCLI("")
+
+	if err := combined.executeCommand(&Command{Name: "help"}); err != nil {
+		t.Fatalf("help unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "use <spot|fut>") || !strings.Contains(output, "Active context: spot") {
+		t.Errorf("help should describe routing, got %q", output)
+	}
+	if !strings.Contains(output, "price <symbol>") {
+		t.Errorf("help should list the active market's commands, got %q", output)
+	}
+}
+
+func TestDryRunBanner(t *testing.T) {
+	const banner = "DRY RUN: orders are simulated"
+
+	combined, buf, _ := newTestCombinedCLI("exit\n")
+	if err := combined.Run(); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	if strings.Contains(buf.String(), banner) {
+		t.Errorf("live trading should not show the dry run banner:\n%s", buf.String())
+	}
+
+	// Either market trading on paper shows the banner in the combined CLI
+	combined, buf, _ = newTestCombinedCLI("exit\n")
+	combined.futures.SetDryRun(true)
+	if err := combined.Run(); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), banner) {
+		t.Errorf("expected the dry run banner, got:\n%s", buf.String())
+	}
+
+	var output bytes.Buffer
+	spot := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+	spot.SetDryRun(true)
+	spot.reader = strings.NewReader("exit\n")
+	spot.writer = &output
+	if err := spot.Run(); err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	if !strings.Contains(output.String(), banner) {
+		t.Errorf("expected the dry run banner in the spot CLI, got:\n%s", output.String())
+	}
+}
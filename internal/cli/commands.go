@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrUsage marks errors caused by missing or malformed command arguments. Handlers wrap
+// it (fmt.Errorf("%w: price <symbol>", ErrUsage)) so the CLI can show the command's help.
+var ErrUsage = errors.New("usage")
+
+// commandSpec describes a CLI command; the same table drives dispatch, the global help
+// listing and per-command help
+type commandSpec struct {
+	Name        string
+	Category    string
+	Usage       string   // Argument syntax, e.g. "price <symbol>"
+	Description string   // One-line summary shown in the global listing
+	Arguments   []string // Argument meanings and accepted values, one per line
+	Examples    []string
+	Handler     func(args []string) error
+}
+
+// commandTable holds the registered commands of a CLI in display order
+type commandTable struct {
+	commands []*commandSpec
+	byName   map[string]*commandSpec
+}
+
+// newCommandTable creates a command table; it panics on duplicate names
+func newCommandTable(commands []*commandSpec) *commandTable {
+	table := &commandTable{
+		commands: commands,
+		byName:   make(map[string]*commandSpec, len(commands)),
+	}
+	for _, command := range commands {
+		if _, exists := table.byName[command.Name]; exists {
+			panic(fmt.Sprintf("duplicate command %q", command.Name))
+		}
+		table.byName[command.Name] = command
+	}
+	return table
+}
+
+// execute dispatches a parsed command to its handler
+func (t *commandTable) execute(cmd *Command) error {
+	command, exists := t.byName[cmd.Name]
+	if !exists {
+		return t.unknownCommandError(cmd.Name)
+	}
+	return command.Handler(cmd.Args)
+}
+
+// reportError prints a command error, followed by the command's help for usage errors
+func (t *commandTable) reportError(w io.Writer, cmd *Command, err error) {
+	fmt.Fprintf(w, "Error: %s\n", err.Error())
+	if !errors.Is(err, ErrUsage) {
+		return
+	}
+	if command, exists := t.byName[cmd.Name]; exists {
+		t.writeCommandHelp(w, command)
+	}
+}
+
+// help prints the global listing, or the help of a single command when one is named
+func (t *commandTable) help(w io.Writer, args []string) error {
+	if len(args) == 0 {
+		t.writeHelp(w)
+		return nil
+	}
+
+	name := strings.ToLower(args[0])
+	command, exists := t.byName[name]
+	if !exists {
+		return t.unknownCommandError(name)
+	}
+	t.writeCommandHelp(w, command)
+	return nil
+}
+
+// writeHelp prints all commands grouped by category
+func (t *commandTable) writeHelp(w io.Writer) {
+	fmt.Fprintln(w, "\nAvailable Commands:")
+
+	category := ""
+	for _, command := range t.commands {
+		if command.Category != category {
+			category = command.Category
+			fmt.Fprintf(w, "\n%s:\n", category)
+		}
+		if len(command.Usage) > 32 {
+			fmt.Fprintf(w, "  %s\n  %-32s - %s\n", command.Usage, "", command.Description)
+			continue
+		}
+		fmt.Fprintf(w, "  %-32s - %s\n", command.Usage, command.Description)
+	}
+
+	fmt.Fprintf(w, "  %-32s - %s\n", "exit, quit", "Exit the application")
+	fmt.Fprintln(w, "\nType 'help <command>' for arguments and examples")
+}
+
+// writeCommandHelp prints the syntax, argument meanings and examples of one command
+func (t *commandTable) writeCommandHelp(w io.Writer, command *commandSpec) {
+	fmt.Fprintln(w, "-------------------------------------------")
+	fmt.Fprintf(w, "%s - %s\n", command.Name, command.Description)
+	fmt.Fprintln(w, "-------------------------------------------")
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintf(w, "  %s\n", command.Usage)
+
+	if len(command.Arguments) > 0 {
+		fmt.Fprintln(w, "Arguments:")
+		for _, line := range command.Arguments {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+
+	if len(command.Examples) > 0 {
+		fmt.Fprintln(w, "Examples:")
+		for _, example := range command.Examples {
+			fmt.Fprintf(w, "  %s\n", example)
+		}
+	}
+	fmt.Fprintln(w, "-------------------------------------------")
+}
+
+// unknownCommandError builds the unknown-command error, suggesting the closest command name
+func (t *commandTable) unknownCommandError(name string) error {
+	if suggestion := t.suggest(name); suggestion != "" {
+		return fmt.Errorf("unknown command: %s, did you mean '%s'? (type 'help' for available commands)", name, suggestion)
+	}
+	return fmt.Errorf("unknown command: %s (type 'help' for available commands)", name)
+}
+
+// suggest returns the registered command closest to name, or "" when none is close enough
+func (t *commandTable) suggest(name string) string {
+	best := ""
+	bestDistance := len(name)/3 + 1
+	for _, command := range t.commands {
+		if distance := editDistance(name, command.Name); distance < bestDistance {
+			best = command.Name
+			bestDistance = distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the optimal string alignment distance between a and b, which counts
+// an adjacent transposition ("condroder") as a single edit
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := 0; j <= len(rb); j++ {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
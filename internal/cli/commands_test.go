@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPerCommandHelp(t *testing.T) {
+	var buf bytes.Buffer
+	cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+	cli.writer = &buf
+
+	if err := cli.executeCommand(&Command{Name: "help", Args: []string{"condorder"}}); err != nil {
+		t.Fatalf("help condorder unexpected error: %v", err)
+	}
+
+	for _, field := range []string{
+		"condorder <symbol> <side> <quantity> <trigger_type> <operator> <value>",
+		"PRICE", "PRICE_CHANGE", "VOLUME",
+		">=, <=, >, <",
+		"condorder BTCUSDT BUY 0.001 PRICE >= 50000",
+		"condorder ETHUSDT SELL 0.05 PRICE_CHANGE <= -5",
+	} {
+		if !strings.Contains(buf.String(), field) {
+			t.Errorf("help condorder should contain %q, got:\n%s", field, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), "stoploss") {
+		t.Errorf("help condorder should only describe condorder, got:\n%s", buf.String())
+	}
+}
+
+func TestGlobalHelpListsAllCommands(t *testing.T) {
+	var buf bytes.Buffer
+	cli := NewFuturesCLI(nil, nil, nil, nil, nil, &mockLogger{})
+	cli.writer = &buf
+
+	if err := cli.executeCommand(&Command{Name: "help"}); err != nil {
+		t.Fatalf("help unexpected error: %v", err)
+	}
+
+	for _, command := range cli.commands.commands {
+		if !strings.Contains(buf.String(), command.Usage) {
+			t.Errorf("help should list %q", command.Usage)
+		}
+	}
+	if !strings.Contains(buf.String(), "help <command>") {
+		t.Error("help should point to per-command help")
+	}
+}
+
+func TestUnknownCommandSuggestion(t *testing.T) {
+	cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+
+	err := cli.executeCommand(&Command{Name: "condroder"})
+	if err == nil || !strings.Contains(err.Error(), "did you mean 'condorder'?") {
+		t.Errorf("expected suggestion for condroder, got %v", err)
+	}
+
+	err = cli.executeCommand(&Command{Name: "help", Args: []string{"stoplos"}})
+	if err == nil || !strings.Contains(err.Error(), "did you mean 'stoploss'?") {
+		t.Errorf("expected suggestion for help stoplos, got %v", err)
+	}
+
+	err = cli.executeCommand(&Command{Name: "xyz"})
+	if err == nil || strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("expected no suggestion for unrelated input, got %v", err)
+	}
+}
+
+func TestUsageErrorPrintsCommandHelp(t *testing.T) {
+	var input, output bytes.Buffer
+	cli := NewFuturesCLI(nil, nil, nil, nil, nil, &mockLogger{})
+	cli.reader = &input
+	cli.writer = &output
+	input.WriteString("leverage BTCUSDT\nexit\n")
+
+	if err := cli.Run(); err != nil {
+		t.Fatalf("Run unexpected error: %v", err)
+	}
+
+	for _, field := range []string{
+		"Error: usage: leverage <symbol> <value>",
+		"Leverage multiplier from 1 to 125",
+		"leverage BTCUSDT 10",
+	} {
+		if !strings.Contains(output.String(), field) {
+			t.Errorf("usage error output should contain %q, got:\n%s", field, output.String())
+		}
+	}
+
+	if err := cli.executeCommand(&Command{Name: "long"}); !errors.Is(err, ErrUsage) {
+		t.Errorf("expected ErrUsage for missing arguments, got %v", err)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"condorder", "condorder", 0},
+		{"condroder", "condorder", 1},
+		{"stoplos", "stoploss", 1},
+		{"ratelimit", "ratelimits", 1},
+		{"", "help", 4},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.expected {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
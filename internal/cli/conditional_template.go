@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"binance-trader/internal/repository"
+	"binance-trader/internal/service"
+)
+
+// handleConditionalTemplateExport writes the active conditional orders to a YAML template
+func (c *CLI) handleConditionalTemplateExport(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("%w: condorder export <file.yaml>", ErrUsage)
+	}
+	path := args[0]
+
+	orders, err := c.conditionalOrderService.GetActiveConditionalOrders()
+	if err != nil {
+		return fmt.Errorf("failed to get conditional orders: %w", err)
+	}
+	if len(orders) == 0 {
+		return fmt.Errorf("no active conditional orders to export")
+	}
+
+	template, err := service.NewConditionalOrderTemplate(orders, time.Now(), c.holdings)
+	if err != nil {
+		return fmt.Errorf("failed to export conditional orders: %w", err)
+	}
+	data, err := service.MarshalConditionalOrderTemplate(template)
+	if err != nil {
+		return fmt.Errorf("failed to export conditional orders: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	fmt.Fprintf(c.writer, "Exported %d conditional orders to %s (template version %d)\n", len(orders), path, template.Version)
+	return nil
+}
+
+// handleConditionalTemplateImport creates the conditional orders of a YAML template after
+// validating all of them; with --dry-run it only lists what would be created
+func (c *CLI) handleConditionalTemplateImport(args []string) error {
+	usage := fmt.Errorf("%w: condorder import <file.yaml> [--symbol <symbol>] [--dry-run] [--allow-duplicate]", ErrUsage)
+
+	var path, symbol string
+	dryRun, allowDuplicate := false, false
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--dry-run":
+			dryRun = true
+		case "--allow-duplicate":
+			allowDuplicate = true
+		case "--symbol":
+			if i+1 >= len(args) {
+				return usage
+			}
+			symbol = strings.ToUpper(args[i+1])
+			i++
+		default:
+			if path != "" {
+				return usage
+			}
+			path = args[i]
+		}
+	}
+	if path == "" {
+		return usage
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	template, err := service.ParseConditionalOrderTemplate(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	requests, err := template.Requests(service.TemplateOptions{
+		Symbol:       symbol,
+		Now:          time.Now(),
+		Holding:      c.holdings,
+		CurrentPrice: c.marketService.GetCurrentPrice,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	c.formatTemplatePreview(requests)
+	if dryRun {
+		fmt.Fprintf(c.writer, "Dry run: %d conditional orders would be created\n", len(requests))
+		return nil
+	}
+
+	if err := c.checkMaintenance(); err != nil {
+		return err
+	}
+
+	for i, request := range requests {
+		request.AllowDuplicate = allowDuplicate
+		order, err := c.conditionalOrderService.CreateConditionalOrder(request)
+		if err != nil {
+			return fmt.Errorf("created %d of %d conditional orders; order %d failed: %w", i, len(requests), i+1, creationError(err))
+		}
+		fmt.Fprintf(c.writer, "Created %s\n", order.OrderID)
+	}
+	fmt.Fprintf(c.writer, "Imported %d conditional orders from %s\n", len(requests), path)
+	return nil
+}
+
+// formatTemplatePreview lists the orders a template resolves to
+func (c *CLI) formatTemplatePreview(requests []*repository.ConditionalOrderRequest) {
+	fmt.Fprintln(c.writer, "===========================================")
+	fmt.Fprintf(c.writer, "Conditional Orders to Create (%d)\n", len(requests))
+	fmt.Fprintln(c.writer, "===========================================")
+
+	for i, request := range requests {
+		fmt.Fprintf(c.writer, "\n[%d] %s %s %s\n", i+1, request.Symbol, request.Side, request.Type)
+		fmt.Fprintf(c.writer, "    Quantity:     %s\n", c.display.fmtQty(request.Symbol, request.Quantity))
+		if request.Price > 0 {
+			fmt.Fprintf(c.writer, "    Price:        %s\n", c.display.fmtPrice(request.Symbol, request.Price))
+		}
+		fmt.Fprintf(c.writer, "    Trigger:      %s\n", service.DescribeTriggerCondition(request.TriggerCondition))
+		if window := request.TimeWindow; window != nil {
+			end := "-"
+			if !window.EndTime.IsZero() {
+				end = c.display.fmtTime(window.EndTime.UnixMilli())
+			}
+			fmt.Fprintf(c.writer, "    Window:       %s to %s\n", c.display.fmtTime(window.StartTime.UnixMilli()), end)
+		}
+	}
+
+	fmt.Fprintln(c.writer, "===========================================")
+}
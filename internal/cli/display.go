@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"binance-trader/internal/config"
+	"binance-trader/pkg/timeutil"
+)
+
+// defaultDisplayPrecision is used for prices, quantities and amounts without a configured precision
+const defaultDisplayPrecision = 8
+
+// localeSeparators maps a locale to its thousands and decimal separators
+var localeSeparators = map[string][2]string{
+	"en": {",", "."},
+	"de": {".", ","},
+	"fr": {" ", ","},
+}
+
+// displayFormat formats monetary values and timestamps printed by the spot and futures CLIs.
+// The zero configuration prints plain "%.8f" numbers and UTC times.
+type displayFormat struct {
+	thousandsSep      string
+	decimalSep        string
+	grouping          bool
+	pricePrecision    int
+	quantityPrecision int
+	moneyPrecision    int
+	symbols           map[string]config.SymbolDisplayConfig
+	location          *time.Location
+}
+
+// newDisplayFormat creates a display format from the cli configuration; nil uses the defaults
+func newDisplayFormat(cfg *config.CLIConfig) *displayFormat {
+	if cfg == nil {
+		cfg = &config.CLIConfig{}
+	}
+
+	separators, ok := localeSeparators[cfg.Locale]
+	if !ok {
+		separators = localeSeparators["en"]
+	}
+
+	d := &displayFormat{
+		thousandsSep:      separators[0],
+		decimalSep:        separators[1],
+		grouping:          cfg.Grouping,
+		pricePrecision:    cfg.PricePrecision,
+		quantityPrecision: cfg.QuantityPrecision,
+		moneyPrecision:    cfg.MoneyPrecision,
+		symbols:           make(map[string]config.SymbolDisplayConfig, len(cfg.Symbols)),
+	}
+
+	// The timezone is validated with the configuration; an unknown one falls back to UTC
+	location, err := cfg.Location()
+	if err != nil {
+		location = time.UTC
+	}
+	d.location = location
+
+	if d.pricePrecision <= 0 {
+		d.pricePrecision = defaultDisplayPrecision
+	}
+	if d.quantityPrecision <= 0 {
+		d.quantityPrecision = defaultDisplayPrecision
+	}
+	if d.moneyPrecision <= 0 {
+		d.moneyPrecision = defaultDisplayPrecision
+	}
+	for symbol, symbolCfg := range cfg.Symbols {
+		d.symbols[strings.ToUpper(symbol)] = symbolCfg
+	}
+
+	return d
+}
+
+// fmtPrice formats a price with the precision of its symbol
+func (d *displayFormat) fmtPrice(symbol string, price float64) string {
+	precision := d.pricePrecision
+	if symbolCfg, ok := d.symbols[symbol]; ok && symbolCfg.PricePrecision > 0 {
+		precision = symbolCfg.PricePrecision
+	}
+	return d.format(price, precision, true)
+}
+
+// fmtQty formats a base asset or contract quantity with the precision of its symbol
+func (d *displayFormat) fmtQty(symbol string, quantity float64) string {
+	precision := d.quantityPrecision
+	if symbolCfg, ok := d.symbols[symbol]; ok && symbolCfg.QuantityPrecision > 0 {
+		precision = symbolCfg.QuantityPrecision
+	}
+	return d.format(quantity, precision, true)
+}
+
+// fmtMoney formats a quote amount such as a notional, PnL, fee or balance
+func (d *displayFormat) fmtMoney(amount float64) string {
+	return d.format(amount, d.moneyPrecision, true)
+}
+
+// fmtPercent formats a value already expressed in percent with the given decimals and a % sign
+func (d *displayFormat) fmtPercent(percent float64, decimals int) string {
+	return d.format(percent, decimals, false) + "%"
+}
+
+// fmtTime formats a Unix millisecond timestamp in the configured timezone
+func (d *displayFormat) fmtTime(ms int64) string {
+	return timeutil.Format(ms, d.location)
+}
+
+// fmtTimeMillis formats a Unix millisecond timestamp to the millisecond, for event timelines
+func (d *displayFormat) fmtTimeMillis(ms int64) string {
+	return timeutil.FromMillis(ms).In(d.location).Format(timeutil.DisplayLayoutMillis)
+}
+
+// format renders value with the locale separators, grouping thousands when enabled
+func (d *displayFormat) format(value float64, precision int, group bool) string {
+	s := strconv.FormatFloat(value, 'f', precision, 64)
+	if !(group && d.grouping) && d.decimalSep == "." {
+		return s
+	}
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	integerPart, fraction, hasFraction := strings.Cut(s, ".")
+	if group && d.grouping {
+		integerPart = groupThousands(integerPart, d.thousandsSep)
+	}
+
+	if hasFraction {
+		return sign + integerPart + d.decimalSep + fraction
+	}
+	return sign + integerPart
+}
+
+// groupThousands inserts sep between groups of three digits
+func groupThousands(digits, sep string) string {
+	if len(digits) <= 3 || strings.Trim(digits, "0123456789") != "" {
+		return digits
+	}
+
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"binance-trader/internal/config"
+)
+
+func TestDisplayFormat_DefaultsMatchPlainFormatting(t *testing.T) {
+	display := newDisplayFormat(nil)
+
+	for _, v := range []float64{0, 0.001, 50000, 1234567.891, -2500.5} {
+		want := fmt.Sprintf("%.8f", v)
+		if got := display.fmtPrice("BTCUSDT", v); got != want {
+			t.Errorf("fmtPrice(%v) = %q, want %q", v, got, want)
+		}
+		if got := display.fmtQty("BTCUSDT", v); got != want {
+			t.Errorf("fmtQty(%v) = %q, want %q", v, got, want)
+		}
+		if got := display.fmtMoney(v); got != want {
+			t.Errorf("fmtMoney(%v) = %q, want %q", v, got, want)
+		}
+	}
+
+	if got := display.fmtPercent(12.3456, 2); got != "12.35%" {
+		t.Errorf("fmtPercent() = %q, want %q", got, "12.35%")
+	}
+}
+
+func TestDisplayFormat_LocaleGrouping(t *testing.T) {
+	tests := []struct {
+		locale string
+		value  float64
+		want   string
+	}{
+		{"en", 1234567.5, "1,234,567.50"},
+		{"en", -1234.5, "-1,234.50"},
+		{"en", 999.5, "999.50"},
+		{"de", 1234567.5, "1.234.567,50"},
+		{"fr", 1234567.5, "1 234 567,50"},
+		{"fr", -0.25, "-0,25"},
+	}
+
+	for _, tt := range tests {
+		display := newDisplayFormat(&config.CLIConfig{Locale: tt.locale, Grouping: true, MoneyPrecision: 2})
+		if got := display.fmtMoney(tt.value); got != tt.want {
+			t.Errorf("fmtMoney(%v) with locale %s = %q, want %q", tt.value, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestDisplayFormat_PercentNeverGrouped(t *testing.T) {
+	display := newDisplayFormat(&config.CLIConfig{Locale: "de", Grouping: true})
+
+	if got := display.fmtPercent(1234.5, 1); got != "1234,5%" {
+		t.Errorf("fmtPercent() = %q, want %q", got, "1234,5%")
+	}
+}
+
+func TestDisplayFormat_SymbolPrecision(t *testing.T) {
+	display := newDisplayFormat(&config.CLIConfig{
+		PricePrecision:    2,
+		QuantityPrecision: 6,
+		Symbols: map[string]config.SymbolDisplayConfig{
+			"dogeusdt": {PricePrecision: 5, QuantityPrecision: 0},
+		},
+	})
+
+	if got := display.fmtPrice("BTCUSDT", 50000.123); got != "50000.12" {
+		t.Errorf("fmtPrice(BTCUSDT) = %q, want %q", got, "50000.12")
+	}
+	if got := display.fmtPrice("DOGEUSDT", 0.0812345); got != "0.08123" {
+		t.Errorf("fmtPrice(DOGEUSDT) = %q, want %q", got, "0.08123")
+	}
+	// A zero symbol precision falls back to the global setting
+	if got := display.fmtQty("DOGEUSDT", 12.5); got != "12.500000" {
+		t.Errorf("fmtQty(DOGEUSDT) = %q, want %q", got, "12.500000")
+	}
+	if got := display.fmtMoney(1.5); got != "1.50000000" {
+		t.Errorf("fmtMoney() = %q, want %q", got, "1.50000000")
+	}
+}
+
+func TestHandlePrice_GroupedDisplay(t *testing.T) {
+	mockMarket := &mockMarketDataService{
+		getCurrentPriceFunc: func(symbol string) (float64, error) {
+			return 50000.0, nil
+		},
+	}
+
+	cli := NewCLI(&mockTradingService{}, mockMarket, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+	cli.SetDisplayConfig(&config.CLIConfig{
+		Locale:   "en",
+		Grouping: true,
+		Symbols:  map[string]config.SymbolDisplayConfig{"BTCUSDT": {PricePrecision: 2}},
+	})
+
+	var buf bytes.Buffer
+	cli.writer = &buf
+
+	if err := cli.handlePrice([]string{"BTCUSDT"}); err != nil {
+		t.Fatalf("handlePrice() unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "50,000.00") {
+		t.Errorf("handlePrice() output should contain grouped price, got %q", buf.String())
+	}
+}
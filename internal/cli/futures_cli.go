@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"strconv"
+	"sort"
 	"strings"
 
 	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/internal/repository"
 	"binance-trader/internal/service"
 	"binance-trader/pkg/logger"
 )
@@ -20,9 +22,20 @@ type FuturesCLI struct {
 	positionManager         service.FuturesPositionManager
 	conditionalOrderService service.FuturesConditionalOrderService
 	stopLossService         service.FuturesStopLossService
+	symbolGuard             service.SymbolFailureGuard
+	rateLimitProvider       api.RateLimitStatusProvider
+	carryService            service.CarryService
+	coverageChecker         service.ProtectionCoverageChecker
+	maintenanceMonitor      service.MaintenanceMonitor
+	symbolStatus            service.SymbolStatusMonitor
+	profitGuard             *service.TakeProfitGuard
+	dryRun                  bool
+	display                 *displayFormat
+	heatMap                 config.HeatMapConfig
 	logger                  logger.Logger
 	reader                  io.Reader
 	writer                  io.Writer
+	commands                *commandTable
 }
 
 // NewFuturesCLI creates a new futures CLI instance
@@ -34,16 +47,85 @@ func NewFuturesCLI(
 	stopLossService service.FuturesStopLossService,
 	logger logger.Logger,
 ) *FuturesCLI {
-	return &FuturesCLI{
+	c := &FuturesCLI{
 		tradingService:          tradingService,
 		marketService:           marketService,
 		positionManager:         positionManager,
 		conditionalOrderService: conditionalOrderService,
 		stopLossService:         stopLossService,
+		profitGuard:             service.NewTakeProfitGuard(nil, service.DefaultFuturesFeeRate),
+		display:                 newDisplayFormat(nil),
 		logger:                  logger,
 		reader:                  os.Stdin,
 		writer:                  os.Stdout,
 	}
+	c.commands = newCommandTable(c.commandSpecs())
+	return c
+}
+
+// SetDisplayConfig sets how prices, quantities and amounts are displayed, and the heat map risk buckets
+func (c *FuturesCLI) SetDisplayConfig(cfg *config.CLIConfig) {
+	c.display = newDisplayFormat(cfg)
+	if cfg != nil {
+		c.heatMap = cfg.HeatMap
+	}
+}
+
+// SetProfitGuardConfig sets the fee rate and minimum profit take-profit targets are checked against
+func (c *FuturesCLI) SetProfitGuardConfig(cfg *config.ProfitGuardConfig) {
+	c.profitGuard = service.NewTakeProfitGuard(cfg, service.DefaultFuturesFeeRate)
+}
+
+// SetDryRun marks the orders placed from this CLI as simulated, which the welcome message announces
+func (c *FuturesCLI) SetDryRun(active bool) {
+	c.dryRun = active
+}
+
+// SetSymbolGuard sets the optional symbol failure guard used by the paused command
+func (c *FuturesCLI) SetSymbolGuard(guard service.SymbolFailureGuard) {
+	c.symbolGuard = guard
+}
+
+// SetRateLimitStatusProvider sets the optional provider used by the ratelimit command
+func (c *FuturesCLI) SetRateLimitStatusProvider(provider api.RateLimitStatusProvider) {
+	c.rateLimitProvider = provider
+}
+
+// SetCarryService sets the optional spot/perpetual carry service used by the carry command
+func (c *FuturesCLI) SetCarryService(carryService service.CarryService) {
+	c.carryService = carryService
+}
+
+// SetCoverageChecker sets the optional protection coverage checker used by the coverage command
+func (c *FuturesCLI) SetCoverageChecker(checker service.ProtectionCoverageChecker) {
+	c.coverageChecker = checker
+}
+
+// SetMaintenanceMonitor sets the optional maintenance monitor used to pause opening positions
+func (c *FuturesCLI) SetMaintenanceMonitor(monitor service.MaintenanceMonitor) {
+	c.maintenanceMonitor = monitor
+}
+
+// SetSymbolStatusMonitor sets the optional symbol status monitor whose suspended symbols the
+// status command lists
+func (c *FuturesCLI) SetSymbolStatusMonitor(monitor service.SymbolStatusMonitor) {
+	c.symbolStatus = monitor
+}
+
+// checkMaintenance returns an error if the exchange is in maintenance
+func (c *FuturesCLI) checkMaintenance() error {
+	if c.maintenanceMonitor != nil && c.maintenanceMonitor.IsInMaintenance() {
+		return fmt.Errorf("exchange is under maintenance, order placement is paused")
+	}
+	return nil
+}
+
+// recordMaintenanceResult reports the result of a manual order to the maintenance monitor, so
+// server errors met from the CLI count towards detecting maintenance as well
+func (c *FuturesCLI) recordMaintenanceResult(err error) {
+	if c.maintenanceMonitor != nil {
+		c.maintenanceMonitor.RecordResult(err)
+	}
 }
 
 // Run starts the interactive futures CLI
@@ -71,7 +153,7 @@ func (c *FuturesCLI) Run() error {
 		}
 
 		if err := c.executeCommand(cmd); err != nil {
-			fmt.Fprintf(c.writer, "Error: %s\n", err.Error())
+			c.commands.reportError(c.writer, cmd, err)
 		}
 	}
 
@@ -84,45 +166,7 @@ func (c *FuturesCLI) Run() error {
 
 // executeCommand executes a parsed command
 func (c *FuturesCLI) executeCommand(cmd *Command) error {
-	switch cmd.Name {
-	case "help":
-		c.printHelp()
-	case "mark-price":
-		return c.handleMarkPrice(cmd.Args)
-	case "funding-rate":
-		return c.handleFundingRate(cmd.Args)
-	case "position":
-		return c.handlePosition(cmd.Args)
-	case "positions":
-		return c.handlePositions(cmd.Args)
-	case "long":
-		return c.handleLong(cmd.Args)
-	case "short":
-		return c.handleShort(cmd.Args)
-	case "close":
-		return c.handleClosePosition(cmd.Args)
-	case "leverage":
-		return c.handleLeverage(cmd.Args)
-	case "margin-type":
-		return c.handleMarginType(cmd.Args)
-	case "condorder":
-		return c.handleConditionalOrder(cmd.Args)
-	case "condorders":
-		return c.handleConditionalOrders(cmd.Args)
-	case "cancelcond":
-		return c.handleCancelConditionalOrder(cmd.Args)
-	case "stoploss":
-		return c.handleStopLoss(cmd.Args)
-	case "takeprofit":
-		return c.handleTakeProfit(cmd.Args)
-	case "stoporders":
-		return c.handleStopOrders(cmd.Args)
-	case "cancelstop":
-		return c.handleCancelStopOrder(cmd.Args)
-	default:
-		return fmt.Errorf("unknown command: %s (type 'help' for available commands)", cmd.Name)
-	}
-	return nil
+	return c.commands.execute(cmd)
 }
 
 // printWelcome prints the welcome message
@@ -130,58 +174,359 @@ func (c *FuturesCLI) printWelcome() {
 	fmt.Fprintln(c.writer, "===========================================")
 	fmt.Fprintln(c.writer, "  Binance Futures Trading System")
 	fmt.Fprintln(c.writer, "===========================================")
+	if c.dryRun {
+		printDryRunBanner(c.writer)
+	}
 	fmt.Fprintln(c.writer, "Type 'help' for available commands")
 }
 
-// printHelp prints the help message
-func (c *FuturesCLI) printHelp() {
-	help := `
-Available Commands:
-
-Market Data:
-  mark-price <symbol>              - Get mark price
-  funding-rate <symbol>            - Get funding rate
-  position <symbol>                - View position for symbol
-  positions                        - View all positions
-
-Trading:
-  long <symbol> <quantity>         - Open long position (market)
-  short <symbol> <quantity>        - Open short position (market)
-  close <symbol>                   - Close position
-
-Leverage & Margin:
-  leverage <symbol> <value>        - Set leverage (1-125)
-  margin-type <symbol> <type>      - Set margin type (CROSSED/ISOLATED)
-
-Conditional Orders:
-  condorder <symbol> <side> <position_side> <qty> <trigger_type> <operator> <value>
-                                   - Create conditional order
-                                   - Trigger types: MARK_PRICE, LAST_PRICE, PNL, FUNDING_RATE
-                                   - Sides: BUY, SELL
-                                   - Position sides: LONG, SHORT, BOTH
-                                   - Operators: >=, <=, >, <
-  condorders                       - List active conditional orders
-  cancelcond <orderID>             - Cancel conditional order
-
-Stop Loss / Take Profit:
-  stoploss <symbol> <side> <qty> <price>
-                                   - Set stop loss (side: LONG/SHORT)
-  takeprofit <symbol> <side> <qty> <price>
-                                   - Set take profit (side: LONG/SHORT)
-  stoporders <symbol>              - List stop orders
-  cancelstop <orderID>             - Cancel stop order
-
-System:
-  help                             - Show this help
-  exit, quit                       - Exit application
-`
-	fmt.Fprintln(c.writer, help)
+// commandSpecs returns the futures commands in the order they are listed by help
+func (c *FuturesCLI) commandSpecs() []*commandSpec {
+	return []*commandSpec{
+		{
+			Name:        "mark-price",
+			Category:    "Market Data",
+			Usage:       "mark-price <symbol>",
+			Description: "Get mark price",
+			Arguments:   []string{"symbol      Perpetual contract, e.g. BTCUSDT"},
+			Examples:    []string{"mark-price BTCUSDT"},
+			Handler:     c.handleMarkPrice,
+		},
+		{
+			Name:        "funding-rate",
+			Category:    "Market Data",
+			Usage:       "funding-rate <symbol>",
+			Description: "Get funding rate",
+			Arguments:   []string{"symbol      Perpetual contract, e.g. BTCUSDT"},
+			Examples:    []string{"funding-rate BTCUSDT"},
+			Handler:     c.handleFundingRate,
+		},
+		{
+			Name:        "position",
+			Category:    "Market Data",
+			Usage:       "position <symbol>",
+			Description: "View position for symbol",
+			Arguments:   []string{"symbol      Perpetual contract, e.g. BTCUSDT"},
+			Examples:    []string{"position BTCUSDT"},
+			Handler:     c.handlePosition,
+		},
+		{
+			Name:        "positions",
+			Category:    "Market Data",
+			Usage:       "positions",
+			Description: "View all positions",
+			Examples:    []string{"positions"},
+			Handler:     c.handlePositions,
+		},
+		{
+			Name:        "futures-balance",
+			Category:    "Market Data",
+			Usage:       "futures-balance",
+			Description: "View the account's wallet balance, unrealized PnL, margin balance and available margin",
+			Examples:    []string{"futures-balance"},
+			Handler:     c.handleFuturesBalance,
+		},
+		{
+			Name:        "heatmap",
+			Category:    "Market Data",
+			Usage:       "heatmap [--json]",
+			Description: "View open positions sorted by liquidation risk",
+			Arguments: []string{
+				"--json      Print the heat map as JSON without colors",
+			},
+			Examples: []string{"heatmap", "heatmap --json"},
+			Handler:  c.handleHeatMap,
+		},
+		{
+			Name:        "long",
+			Category:    "Trading",
+			Usage:       "long <symbol> <quantity>",
+			Description: "Open long position (market)",
+			Arguments: []string{
+				"symbol      Perpetual contract, e.g. BTCUSDT",
+				"quantity    Contract quantity",
+			},
+			Examples: []string{"long BTCUSDT 0.01", "long ETHUSDT 0.5"},
+			Handler:  c.handleLong,
+		},
+		{
+			Name:        "short",
+			Category:    "Trading",
+			Usage:       "short <symbol> <quantity>",
+			Description: "Open short position (market)",
+			Arguments: []string{
+				"symbol      Perpetual contract, e.g. BTCUSDT",
+				"quantity    Contract quantity",
+			},
+			Examples: []string{"short BTCUSDT 0.01", "short ETHUSDT 0.5"},
+			Handler:  c.handleShort,
+		},
+		{
+			Name:        "limit-long",
+			Category:    "Trading",
+			Usage:       "limit-long <symbol> <price> <quantity>",
+			Description: "Open long position with a limit order (GTC)",
+			Arguments: []string{
+				"symbol      Perpetual contract, e.g. BTCUSDT",
+				"price       Limit price",
+				"quantity    Contract quantity",
+			},
+			Examples: []string{"limit-long BTCUSDT 48000 0.01"},
+			Handler:  c.handleLimitLong,
+		},
+		{
+			Name:        "limit-short",
+			Category:    "Trading",
+			Usage:       "limit-short <symbol> <price> <quantity>",
+			Description: "Open short position with a limit order (GTC)",
+			Arguments: []string{
+				"symbol      Perpetual contract, e.g. BTCUSDT",
+				"price       Limit price",
+				"quantity    Contract quantity",
+			},
+			Examples: []string{"limit-short BTCUSDT 52000 0.01"},
+			Handler:  c.handleLimitShort,
+		},
+		{
+			Name:        "close",
+			Category:    "Trading",
+			Usage:       "close <symbol>",
+			Description: "Close position",
+			Arguments:   []string{"symbol      Perpetual contract, e.g. BTCUSDT"},
+			Examples:    []string{"close BTCUSDT"},
+			Handler:     c.handleClosePosition,
+		},
+		{
+			Name:        "leverage",
+			Category:    "Leverage & Margin",
+			Usage:       "leverage <symbol> <value>",
+			Description: "Set leverage (1-125)",
+			Arguments: []string{
+				"symbol      Perpetual contract, e.g. BTCUSDT",
+				"value       Leverage multiplier from 1 to 125",
+			},
+			Examples: []string{"leverage BTCUSDT 10", "leverage ETHUSDT 5"},
+			Handler:  c.handleLeverage,
+		},
+		{
+			Name:        "margin-type",
+			Category:    "Leverage & Margin",
+			Usage:       "margin-type <symbol> <type>",
+			Description: "Set margin type (CROSSED/ISOLATED); a symbol already using it is left unchanged",
+			Arguments: []string{
+				"symbol      Perpetual contract, e.g. BTCUSDT",
+				"type        CROSSED (or CROSS) or ISOLATED",
+			},
+			Examples: []string{"margin-type BTCUSDT ISOLATED", "margin-type ETHUSDT CROSSED"},
+			Handler:  c.handleMarginType,
+		},
+		{
+			Name:        "position-mode",
+			Category:    "Leverage & Margin",
+			Usage:       "position-mode <oneway|hedge>",
+			Description: "Set the account position mode; hedge mode holds LONG and SHORT positions of a symbol at once",
+			Arguments: []string{
+				"mode        ONEWAY (or ONE-WAY) or HEDGE; the exchange refuses to switch while positions are open",
+			},
+			Examples: []string{"position-mode hedge", "position-mode oneway"},
+			Handler:  c.handlePositionMode,
+		},
+		{
+			Name:        "condorder",
+			Category:    "Conditional Orders",
+			Usage:       "condorder <symbol> <side> <position_side> <qty> <trigger_type> <operator> <value> [--allow-duplicate] [--idempotency-key <key>]",
+			Description: "Create a market order that is placed when its trigger fires",
+			Arguments: []string{
+				"symbol         Perpetual contract, e.g. BTCUSDT",
+				"side           BUY or SELL",
+				"position_side  LONG, SHORT or BOTH",
+				"qty            Contract quantity",
+				"trigger_type   MARK_PRICE, LAST_PRICE, PNL (unrealized PnL), FUNDING_RATE or BASIS (percent premium of the mark price over spot)",
+				"operator       >=, <=, >, < (or GE, LE, GT, LT)",
+				"value          Trigger threshold in the unit of the trigger type",
+				"--allow-duplicate        Create even if an active order has the same symbol, sides, quantity and trigger",
+				"--idempotency-key <key>  Retrying with the same key returns the order created first instead of a new one",
+			},
+			Examples: []string{
+				"condorder BTCUSDT BUY LONG 0.01 MARK_PRICE <= 48000",
+				"condorder BTCUSDT SELL LONG 0.01 PNL >= 200",
+				"condorder ETHUSDT SELL SHORT 0.5 FUNDING_RATE > 0.0005",
+				"condorder BTCUSDT SELL SHORT 0.01 BASIS >= 0.8",
+			},
+			Handler: c.handleConditionalOrder,
+		},
+		{
+			Name:        "condorders",
+			Category:    "Conditional Orders",
+			Usage:       "condorders",
+			Description: "List active conditional orders",
+			Examples:    []string{"condorders"},
+			Handler:     c.handleConditionalOrders,
+		},
+		{
+			Name:        "cancelcond",
+			Category:    "Conditional Orders",
+			Usage:       "cancelcond <orderID|all> [symbol]",
+			Description: "Cancel conditional order, or all pending ones",
+			Arguments: []string{
+				"orderID     Conditional order ID shown by condorders, or all",
+				"symbol      With all, only cancel orders of this contract; with an ID, refuse if the order is for another contract",
+			},
+			Examples: []string{"cancelcond 3f2c9a1e-8b4d-4c8e-9f1a-2b7d6e5c4a10 BTCUSDT", "cancelcond all", "cancelcond all BTCUSDT"},
+			Handler:  c.handleCancelConditionalOrder,
+		},
+		{
+			Name:        "condhistory",
+			Category:    "Conditional Orders",
+			Usage:       "condhistory [hours | <from> [to]]",
+			Description: "List executed and cancelled conditional orders with cancel reasons",
+			Arguments: []string{
+				"hours       Look back this many hours (default: 24)",
+				"from        Start of the range, RFC3339 (2024-05-01T00:00:00Z) or Unix milliseconds",
+				"to          End of the range in the same form (default: now)",
+			},
+			Examples: []string{"condhistory", "condhistory 72", "condhistory 2024-05-01T00:00:00Z 2024-05-02T00:00:00+08:00"},
+			Handler:     c.handleConditionalOrderHistory,
+		},
+		{
+			Name:        "stoploss",
+			Category:    "Stop Loss / Take Profit",
+			Usage:       "stoploss <symbol> <side> <qty> <price>",
+			Description: "Set stop loss",
+			Arguments: []string{
+				"symbol      Perpetual contract, e.g. BTCUSDT",
+				"side        Position side to protect: LONG or SHORT",
+				"qty         Contract quantity to close",
+				"price       Stop price (below entry for LONG, above entry for SHORT)",
+			},
+			Examples: []string{"stoploss BTCUSDT LONG 0.01 48000", "stoploss BTCUSDT SHORT 0.01 52000"},
+			Handler:  c.handleStopLoss,
+		},
+		{
+			Name:        "takeprofit",
+			Category:    "Stop Loss / Take Profit",
+			Usage:       "takeprofit <symbol> <side> <qty> <price> [--force]",
+			Description: "Set take profit, refusing targets that lose money after fees",
+			Arguments: []string{
+				"symbol      Perpetual contract, e.g. BTCUSDT",
+				"side        Position side to close: LONG or SHORT",
+				"qty         Contract quantity to close",
+				"price       Target price (above entry for LONG, below entry for SHORT)",
+				"--force     Create the take profit even if it loses money after fees",
+			},
+			Examples: []string{"takeprofit BTCUSDT LONG 0.01 55000", "takeprofit BTCUSDT SHORT 0.01 45000"},
+			Handler:  c.handleTakeProfit,
+		},
+		{
+			Name:        "bracket",
+			Category:    "Stop Loss / Take Profit",
+			Usage:       "bracket <symbol> <side> <qty> <stop_price> <target_price> [--force]",
+			Description: "Set a stop loss and take profit pair; the first to trigger cancels the other",
+			Arguments: []string{
+				"symbol        Perpetual contract, e.g. BTCUSDT",
+				"side          Position side to protect: LONG or SHORT",
+				"qty           Contract quantity to close",
+				"stop_price    Stop price (below entry for LONG, above entry for SHORT)",
+				"target_price  Target price (above entry for LONG, below entry for SHORT)",
+				"--force       Create the pair even if the target loses money after fees",
+			},
+			Examples: []string{"bracket BTCUSDT LONG 0.01 48000 55000"},
+			Handler:  c.handleBracket,
+		},
+		{
+			Name:        "stoporders",
+			Category:    "Stop Loss / Take Profit",
+			Usage:       "stoporders <symbol>",
+			Description: "List stop orders",
+			Arguments:   []string{"symbol      Perpetual contract, e.g. BTCUSDT"},
+			Examples:    []string{"stoporders BTCUSDT"},
+			Handler:     c.handleStopOrders,
+		},
+		{
+			Name:        "cancelstop",
+			Category:    "Stop Loss / Take Profit",
+			Usage:       "cancelstop <orderID> [symbol]",
+			Description: "Cancel stop order",
+			Arguments: []string{
+				"orderID     Stop order ID shown by stoporders",
+				"symbol      Refuse to cancel if the order is for another contract",
+			},
+			Examples: []string{"cancelstop FSL_1700000000000000000_1", "cancelstop FSL_1700000000000000000_1 BTCUSDT"},
+			Handler:     c.handleCancelStopOrder,
+		},
+		{
+			Name:        "coverage",
+			Category:    "Stop Loss / Take Profit",
+			Usage:       "coverage",
+			Description: "Check that open positions are covered by stop orders",
+			Examples:    []string{"coverage"},
+			Handler: func(args []string) error {
+				return handleCoverage(c.writer, c.display, c.coverageChecker)
+			},
+		},
+		{
+			Name:        "carry",
+			Category:    "Funding Carry",
+			Usage:       "carry <open|status|close> [args...]",
+			Description: "Buy spot and short perpetual to collect funding",
+			Arguments: []string{
+				"open <symbol> <notional>  Open a carry with notional in USDT",
+				"status                    Show carry positions, basis and funding accrued",
+				"close <symbol>            Unwind both legs of a carry",
+			},
+			Examples: []string{"carry open BTCUSDT 1000", "carry status", "carry close BTCUSDT"},
+			Handler:  c.handleCarry,
+		},
+		{
+			Name:        "paused",
+			Category:    "System",
+			Usage:       "paused [clear <symbol>]",
+			Description: "List symbols paused after repeated order failures, or lift a pause",
+			Arguments:   []string{"clear <symbol>  Lift the pause for a symbol"},
+			Examples:    []string{"paused", "paused clear BTCUSDT"},
+			Handler: func(args []string) error {
+				return handleSymbolPauses(c.writer, c.display, c.symbolGuard, args)
+			},
+		},
+		{
+			Name:        "status",
+			Category:    "System",
+			Usage:       "status",
+			Description: "Show exchange status and contracts suspended because they stopped trading",
+			Examples:    []string{"status"},
+			Handler: func(args []string) error {
+				formatSystemStatus(c.writer, c.display, c.maintenanceMonitor, c.symbolStatus)
+				return nil
+			},
+		},
+		{
+			Name:        "ratelimit",
+			Category:    "System",
+			Usage:       "ratelimit",
+			Description: "Show exchange rate-limit usage",
+			Examples:    []string{"ratelimit"},
+			Handler: func(args []string) error {
+				return handleRateLimitStatus(c.writer, c.display, c.rateLimitProvider)
+			},
+		},
+		{
+			Name:        "help",
+			Category:    "System",
+			Usage:       "help [command]",
+			Description: "Show all commands, or syntax and examples of one command",
+			Arguments:   []string{"command     Command to describe"},
+			Examples:    []string{"help", "help condorder"},
+			Handler: func(args []string) error {
+				return c.commands.help(c.writer, args)
+			},
+		},
+	}
 }
 
 // handleMarkPrice handles the mark-price command
 func (c *FuturesCLI) handleMarkPrice(args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("usage: mark-price <symbol>")
+		return fmt.Errorf("%w: mark-price <symbol>", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
@@ -192,7 +537,7 @@ func (c *FuturesCLI) handleMarkPrice(args []string) error {
 
 	fmt.Fprintln(c.writer, "-------------------------------------------")
 	fmt.Fprintf(c.writer, "Symbol:      %s\n", symbol)
-	fmt.Fprintf(c.writer, "Mark Price:  %.8f\n", markPrice)
+	fmt.Fprintf(c.writer, "Mark Price:  %s\n", c.display.fmtPrice(symbol, markPrice))
 	fmt.Fprintln(c.writer, "-------------------------------------------")
 	return nil
 }
@@ -200,7 +545,7 @@ func (c *FuturesCLI) handleMarkPrice(args []string) error {
 // handleFundingRate handles the funding-rate command
 func (c *FuturesCLI) handleFundingRate(args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("usage: funding-rate <symbol>")
+		return fmt.Errorf("%w: funding-rate <symbol>", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
@@ -211,7 +556,7 @@ func (c *FuturesCLI) handleFundingRate(args []string) error {
 
 	fmt.Fprintln(c.writer, "-------------------------------------------")
 	fmt.Fprintf(c.writer, "Symbol:        %s\n", symbol)
-	fmt.Fprintf(c.writer, "Funding Rate:  %.6f%%\n", fundingRateData.FundingRate*100)
+	fmt.Fprintf(c.writer, "Funding Rate:  %s\n", c.display.fmtPercent(fundingRateData.FundingRate*100, 6))
 	fmt.Fprintln(c.writer, "-------------------------------------------")
 	return nil
 }
@@ -219,20 +564,20 @@ func (c *FuturesCLI) handleFundingRate(args []string) error {
 // handlePosition handles the position command
 func (c *FuturesCLI) handlePosition(args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("usage: position <symbol>")
+		return fmt.Errorf("%w: position <symbol>", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
 	
-	// Get all positions and filter by symbol
-	allPositions, err := c.positionManager.GetAllPositions()
+	// Query only this symbol (both sides in hedge mode)
+	symbolPositions, err := c.positionManager.GetPositionsBySymbol(symbol)
 	if err != nil {
 		return fmt.Errorf("failed to get position: %w", err)
 	}
 
 	var positions []*api.Position
-	for _, pos := range allPositions {
-		if pos.Symbol == symbol && pos.PositionAmt != 0 {
+	for _, pos := range symbolPositions {
+		if pos.PositionAmt != 0 {
 			positions = append(positions, pos)
 		}
 	}
@@ -280,19 +625,81 @@ func (c *FuturesCLI) handlePositions(args []string) error {
 	return nil
 }
 
+// handleFuturesBalance handles the futures-balance command
+func (c *FuturesCLI) handleFuturesBalance(args []string) error {
+	account, err := c.tradingService.GetAccountBalance()
+	if err != nil {
+		return fmt.Errorf("failed to get account balance: %w", err)
+	}
+
+	fmt.Fprintln(c.writer, "===========================================")
+	fmt.Fprintln(c.writer, "Futures Account")
+	fmt.Fprintln(c.writer, "===========================================")
+	fmt.Fprintf(c.writer, "Wallet Balance:   %s\n", c.display.fmtMoney(account.TotalWalletBalance))
+	fmt.Fprintf(c.writer, "Unrealized PnL:   %s\n", c.display.fmtMoney(account.TotalUnrealizedProfit))
+	fmt.Fprintf(c.writer, "Margin Balance:   %s\n", c.display.fmtMoney(account.TotalMarginBalance))
+	fmt.Fprintf(c.writer, "Available Margin: %s\n", c.display.fmtMoney(account.AvailableBalance))
+
+	// Margin assets the account never funded are left out
+	for _, asset := range account.Assets {
+		if asset.WalletBalance == 0 && asset.MarginBalance == 0 {
+			continue
+		}
+		fmt.Fprintln(c.writer, "-------------------------------------------")
+		fmt.Fprintf(c.writer, "Asset:            %s\n", asset.Asset)
+		fmt.Fprintf(c.writer, "Wallet Balance:   %s\n", c.display.fmtMoney(asset.WalletBalance))
+		fmt.Fprintf(c.writer, "Unrealized PnL:   %s\n", c.display.fmtMoney(asset.UnrealizedProfit))
+		fmt.Fprintf(c.writer, "Margin Balance:   %s\n", c.display.fmtMoney(asset.MarginBalance))
+		fmt.Fprintf(c.writer, "Available:        %s\n", c.display.fmtMoney(asset.AvailableBalance))
+	}
+	fmt.Fprintln(c.writer, "===========================================")
+	return nil
+}
+
+// handleHeatMap handles the heatmap command
+func (c *FuturesCLI) handleHeatMap(args []string) error {
+	jsonOutput := false
+	for _, arg := range args {
+		switch strings.ToLower(arg) {
+		case "--json", "json":
+			jsonOutput = true
+		default:
+			return fmt.Errorf("%w: heatmap [--json]", ErrUsage)
+		}
+	}
+
+	positions, err := c.positionManager.GetAllPositions()
+	if err != nil {
+		return fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	report := buildHeatMap(positions, c.heatMap, c.positionManager.CalculateLiquidationPrice)
+	if jsonOutput {
+		return writeHeatMapJSON(c.writer, report)
+	}
+
+	formatHeatMap(c.writer, c.display, report, isTerminal(c.writer))
+	return nil
+}
+
 // handleLong handles the long command
 func (c *FuturesCLI) handleLong(args []string) error {
 	if len(args) < 2 {
-		return fmt.Errorf("usage: long <symbol> <quantity>")
+		return fmt.Errorf("%w: long <symbol> <quantity>", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
-	quantity, err := strconv.ParseFloat(args[1], 64)
+	quantity, err := parseAmount("quantity", args[1])
 	if err != nil {
-		return fmt.Errorf("invalid quantity: %w", err)
+		return err
+	}
+
+	if err := c.checkMaintenance(); err != nil {
+		return err
 	}
 
 	order, err := c.tradingService.OpenLongPosition(symbol, quantity, api.OrderTypeMarket, 0)
+	c.recordMaintenanceResult(err)
 	if err != nil {
 		return fmt.Errorf("failed to open long position: %w", err)
 	}
@@ -303,7 +710,7 @@ func (c *FuturesCLI) handleLong(args []string) error {
 	fmt.Fprintf(c.writer, "Order ID:    %d\n", order.OrderID)
 	fmt.Fprintf(c.writer, "Symbol:      %s\n", order.Symbol)
 	fmt.Fprintf(c.writer, "Side:        %s\n", order.Side)
-	fmt.Fprintf(c.writer, "Quantity:    %.8f\n", order.OrigQty)
+	fmt.Fprintf(c.writer, "Quantity:    %s\n", c.display.fmtQty(order.Symbol, order.OrigQty))
 	fmt.Fprintf(c.writer, "Status:      %s\n", order.Status)
 	fmt.Fprintln(c.writer, "-------------------------------------------")
 	return nil
@@ -312,16 +719,21 @@ func (c *FuturesCLI) handleLong(args []string) error {
 // handleShort handles the short command
 func (c *FuturesCLI) handleShort(args []string) error {
 	if len(args) < 2 {
-		return fmt.Errorf("usage: short <symbol> <quantity>")
+		return fmt.Errorf("%w: short <symbol> <quantity>", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
-	quantity, err := strconv.ParseFloat(args[1], 64)
+	quantity, err := parseAmount("quantity", args[1])
 	if err != nil {
-		return fmt.Errorf("invalid quantity: %w", err)
+		return err
+	}
+
+	if err := c.checkMaintenance(); err != nil {
+		return err
 	}
 
 	order, err := c.tradingService.OpenShortPosition(symbol, quantity, api.OrderTypeMarket, 0)
+	c.recordMaintenanceResult(err)
 	if err != nil {
 		return fmt.Errorf("failed to open short position: %w", err)
 	}
@@ -332,7 +744,60 @@ func (c *FuturesCLI) handleShort(args []string) error {
 	fmt.Fprintf(c.writer, "Order ID:    %d\n", order.OrderID)
 	fmt.Fprintf(c.writer, "Symbol:      %s\n", order.Symbol)
 	fmt.Fprintf(c.writer, "Side:        %s\n", order.Side)
-	fmt.Fprintf(c.writer, "Quantity:    %.8f\n", order.OrigQty)
+	fmt.Fprintf(c.writer, "Quantity:    %s\n", c.display.fmtQty(order.Symbol, order.OrigQty))
+	fmt.Fprintf(c.writer, "Status:      %s\n", order.Status)
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	return nil
+}
+
+// handleLimitLong handles the limit-long command
+func (c *FuturesCLI) handleLimitLong(args []string) error {
+	return c.openLimitPosition("limit-long", api.PositionSideLong, args)
+}
+
+// handleLimitShort handles the limit-short command
+func (c *FuturesCLI) handleLimitShort(args []string) error {
+	return c.openLimitPosition("limit-short", api.PositionSideShort, args)
+}
+
+// openLimitPosition places a limit order opening a long or short position
+func (c *FuturesCLI) openLimitPosition(command string, positionSide api.PositionSide, args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("%w: %s <symbol> <price> <quantity>", ErrUsage, command)
+	}
+
+	symbol := strings.ToUpper(args[0])
+	price, err := parseAmount("price", args[1])
+	if err != nil {
+		return err
+	}
+	quantity, err := parseAmount("quantity", args[2])
+	if err != nil {
+		return err
+	}
+
+	if err := c.checkMaintenance(); err != nil {
+		return err
+	}
+
+	open, title := c.tradingService.OpenLongPosition, "Limit Long Order Placed"
+	if positionSide == api.PositionSideShort {
+		open, title = c.tradingService.OpenShortPosition, "Limit Short Order Placed"
+	}
+	order, err := open(symbol, quantity, api.OrderTypeLimit, price)
+	c.recordMaintenanceResult(err)
+	if err != nil {
+		return fmt.Errorf("failed to open %s position: %w", strings.ToLower(string(positionSide)), err)
+	}
+
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintln(c.writer, title)
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintf(c.writer, "Order ID:    %d\n", order.OrderID)
+	fmt.Fprintf(c.writer, "Symbol:      %s\n", order.Symbol)
+	fmt.Fprintf(c.writer, "Side:        %s\n", order.Side)
+	fmt.Fprintf(c.writer, "Price:       %s\n", c.display.fmtPrice(order.Symbol, order.Price))
+	fmt.Fprintf(c.writer, "Quantity:    %s\n", c.display.fmtQty(order.Symbol, order.OrigQty))
 	fmt.Fprintf(c.writer, "Status:      %s\n", order.Status)
 	fmt.Fprintln(c.writer, "-------------------------------------------")
 	return nil
@@ -341,7 +806,7 @@ func (c *FuturesCLI) handleShort(args []string) error {
 // handleClosePosition handles the close command
 func (c *FuturesCLI) handleClosePosition(args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("usage: close <symbol>")
+		return fmt.Errorf("%w: close <symbol>", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
@@ -359,13 +824,13 @@ func (c *FuturesCLI) handleClosePosition(args []string) error {
 // handleLeverage handles the leverage command
 func (c *FuturesCLI) handleLeverage(args []string) error {
 	if len(args) < 2 {
-		return fmt.Errorf("usage: leverage <symbol> <value>")
+		return fmt.Errorf("%w: leverage <symbol> <value>", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
-	leverage, err := strconv.Atoi(args[1])
+	leverage, err := parseCount("leverage", args[1])
 	if err != nil {
-		return fmt.Errorf("invalid leverage value: %w", err)
+		return err
 	}
 
 	_, err = c.tradingService.SetLeverage(symbol, leverage)
@@ -380,7 +845,7 @@ func (c *FuturesCLI) handleLeverage(args []string) error {
 // handleMarginType handles the margin-type command
 func (c *FuturesCLI) handleMarginType(args []string) error {
 	if len(args) < 2 {
-		return fmt.Errorf("usage: margin-type <symbol> <type>")
+		return fmt.Errorf("%w: margin-type <symbol> <type>", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
@@ -396,30 +861,71 @@ func (c *FuturesCLI) handleMarginType(args []string) error {
 		return fmt.Errorf("invalid margin type: must be CROSSED or ISOLATED")
 	}
 
-	// Note: SetMarginType needs to be added to FuturesTradingService interface
+	if err := c.tradingService.SetMarginType(symbol, marginType); err != nil {
+		return fmt.Errorf("failed to set margin type: %w", err)
+	}
+
 	fmt.Fprintf(c.writer, "Margin type set to %s for %s\n", marginType, symbol)
 	return nil
 }
 
+// handlePositionMode handles the position-mode command
+func (c *FuturesCLI) handlePositionMode(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("%w: position-mode <oneway|hedge>", ErrUsage)
+	}
+
+	var dualSide bool
+	switch strings.ToUpper(args[0]) {
+	case "ONEWAY", "ONE-WAY":
+		dualSide = false
+	case "HEDGE":
+		dualSide = true
+	default:
+		return fmt.Errorf("invalid position mode: must be ONEWAY or HEDGE")
+	}
+
+	if err := c.tradingService.SetPositionMode(dualSide); err != nil {
+		return fmt.Errorf("failed to set position mode: %w", err)
+	}
+
+	if dualSide {
+		fmt.Fprintln(c.writer, "Position mode set to HEDGE")
+	} else {
+		fmt.Fprintln(c.writer, "Position mode set to ONEWAY")
+	}
+	return nil
+}
+
 // handleConditionalOrder handles the condorder command
 func (c *FuturesCLI) handleConditionalOrder(args []string) error {
+	args, flags, err := parseCreationFlags(args)
+	if err != nil {
+		return err
+	}
 	if len(args) < 7 {
-		return fmt.Errorf("usage: condorder <symbol> <side> <position_side> <qty> <trigger_type> <operator> <value>")
+		return fmt.Errorf("%w: condorder <symbol> <side> <position_side> <qty> <trigger_type> <operator> <value> [--allow-duplicate] [--idempotency-key <key>]", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
 	sideStr := strings.ToUpper(args[1])
 	positionSideStr := strings.ToUpper(args[2])
-	quantity, err := strconv.ParseFloat(args[3], 64)
+	quantity, err := parseAmount("quantity", args[3])
 	if err != nil {
-		return fmt.Errorf("invalid quantity: %w", err)
+		return err
 	}
 
 	triggerTypeStr := strings.ToUpper(args[4])
 	operatorStr := strings.ToUpper(args[5])
-	value, err := strconv.ParseFloat(args[6], 64)
+	// Funding rates are fractions, so "0.05%" is read as 0.0005; basis is already in percent
+	value, err := parseNumber("trigger value", args[6], numberFormat{
+		AllowNegative:     true,
+		AllowZero:         true,
+		AllowPercent:      triggerTypeStr == "FUNDING_RATE" || triggerTypeStr == "BASIS",
+		PercentAsFraction: triggerTypeStr == "FUNDING_RATE",
+	})
 	if err != nil {
-		return fmt.Errorf("invalid trigger value: %w", err)
+		return err
 	}
 
 	// Parse side
@@ -456,6 +962,8 @@ func (c *FuturesCLI) handleConditionalOrder(args []string) error {
 		triggerType = service.FuturesTriggerTypeUnrealizedPnL
 	case "FUNDING_RATE":
 		triggerType = service.FuturesTriggerTypeFundingRate
+	case "BASIS":
+		triggerType = service.FuturesTriggerTypeBasis
 	default:
 		return fmt.Errorf("invalid trigger type")
 	}
@@ -487,11 +995,13 @@ func (c *FuturesCLI) handleConditionalOrder(args []string) error {
 			Operator: operator,
 			Value:    value,
 		},
+		IdempotencyKey: flags.idempotencyKey,
+		AllowDuplicate: flags.allowDuplicate,
 	}
 
 	order, err := c.conditionalOrderService.CreateConditionalOrder(request)
 	if err != nil {
-		return fmt.Errorf("failed to create conditional order: %w", err)
+		return creationError(err)
 	}
 
 	fmt.Fprintln(c.writer, "-------------------------------------------")
@@ -501,9 +1011,9 @@ func (c *FuturesCLI) handleConditionalOrder(args []string) error {
 	fmt.Fprintf(c.writer, "Symbol:      %s\n", order.Symbol)
 	fmt.Fprintf(c.writer, "Side:        %s\n", order.Side)
 	fmt.Fprintf(c.writer, "Position:    %s\n", order.PositionSide)
-	fmt.Fprintf(c.writer, "Quantity:    %.8f\n", order.Quantity)
-	fmt.Fprintf(c.writer, "Trigger:     %s %s %.8f\n", 
-		c.formatTriggerType(triggerType), c.formatOperator(operator), value)
+	fmt.Fprintf(c.writer, "Quantity:    %s\n", c.display.fmtQty(order.Symbol, order.Quantity))
+	fmt.Fprintf(c.writer, "Trigger:     %s %s %s\n", 
+		c.formatTriggerType(triggerType), c.formatOperator(operator), c.formatTriggerValue(order.Symbol, triggerType, value))
 	fmt.Fprintln(c.writer, "-------------------------------------------")
 	return nil
 }
@@ -528,12 +1038,12 @@ func (c *FuturesCLI) handleConditionalOrders(args []string) error {
 		fmt.Fprintf(c.writer, "    Symbol:      %s\n", order.Symbol)
 		fmt.Fprintf(c.writer, "    Side:        %s\n", order.Side)
 		fmt.Fprintf(c.writer, "    Position:    %s\n", order.PositionSide)
-		fmt.Fprintf(c.writer, "    Quantity:    %.8f\n", order.Quantity)
+		fmt.Fprintf(c.writer, "    Quantity:    %s\n", c.display.fmtQty(order.Symbol, order.Quantity))
 		if order.TriggerCondition != nil {
-			fmt.Fprintf(c.writer, "    Trigger:     %s %s %.8f\n",
+			fmt.Fprintf(c.writer, "    Trigger:     %s %s %s\n",
 				c.formatTriggerType(order.TriggerCondition.Type),
 				c.formatOperator(order.TriggerCondition.Operator),
-				order.TriggerCondition.Value)
+				c.formatTriggerValue(order.Symbol, order.TriggerCondition.Type, order.TriggerCondition.Value))
 		}
 		fmt.Fprintf(c.writer, "    Status:      %s\n", order.Status)
 	}
@@ -544,33 +1054,111 @@ func (c *FuturesCLI) handleConditionalOrders(args []string) error {
 // handleCancelConditionalOrder handles the cancelcond command
 func (c *FuturesCLI) handleCancelConditionalOrder(args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("usage: cancelcond <orderID>")
+		return fmt.Errorf("%w: cancelcond <orderID|all> [symbol]", ErrUsage)
 	}
 
 	orderID := args[0]
-	if err := c.conditionalOrderService.CancelConditionalOrder(orderID); err != nil {
+	if strings.EqualFold(orderID, "all") {
+		symbol := ""
+		if len(args) > 1 {
+			symbol = strings.ToUpper(args[1])
+		}
+
+		cancelled, err := c.conditionalOrderService.CancelAllConditionalOrders(symbol, repository.CancelReasonBulk, "cli")
+		if err != nil {
+			return fmt.Errorf("failed to cancel conditional orders after %d cancelled: %w", cancelled, err)
+		}
+
+		fmt.Fprintf(c.writer, "Cancelled %d conditional orders\n", cancelled)
+		return nil
+	}
+
+	symbol := ""
+	if len(args) > 1 {
+		symbol = strings.ToUpper(args[1])
+	}
+
+	order, err := c.conditionalOrderService.CancelConditionalOrder(orderID, symbol)
+	if err != nil {
 		return fmt.Errorf("failed to cancel conditional order: %w", err)
 	}
 
 	fmt.Fprintf(c.writer, "Conditional order %s cancelled successfully\n", orderID)
+	c.formatCancelledConditionalOrder(order)
+	return nil
+}
+
+// formatCancelledConditionalOrder displays what a cancelled conditional order would have done
+func (c *FuturesCLI) formatCancelledConditionalOrder(order *service.FuturesConditionalOrder) {
+	fmt.Fprintf(c.writer, "    Symbol:      %s\n", order.Symbol)
+	fmt.Fprintf(c.writer, "    Side:        %s\n", order.Side)
+	fmt.Fprintf(c.writer, "    Position:    %s\n", order.PositionSide)
+	fmt.Fprintf(c.writer, "    Quantity:    %s\n", c.display.fmtQty(order.Symbol, order.Quantity))
+	if order.TriggerCondition != nil {
+		fmt.Fprintf(c.writer, "    Trigger:     %s %s %s\n",
+			c.formatTriggerType(order.TriggerCondition.Type),
+			c.formatOperator(order.TriggerCondition.Operator),
+			c.formatTriggerValue(order.Symbol, order.TriggerCondition.Type, order.TriggerCondition.Value))
+	}
+}
+
+// handleConditionalOrderHistory handles the condhistory command
+func (c *FuturesCLI) handleConditionalOrderHistory(args []string) error {
+	startTime, endTime, err := parseHistoryRange(args)
+	if err != nil {
+		return err
+	}
+
+	orders, err := c.conditionalOrderService.GetConditionalOrderHistory(startTime, endTime)
+	if err != nil {
+		return fmt.Errorf("failed to get conditional order history: %w", err)
+	}
+
+	if len(orders) == 0 {
+		fmt.Fprintln(c.writer, "No conditional order history")
+		return nil
+	}
+
+	sort.Slice(orders, func(i, j int) bool { return orders[i].CreatedAt < orders[j].CreatedAt })
+
+	counts := make(map[repository.CancelReason]int)
+	fmt.Fprintln(c.writer, "===========================================")
+	fmt.Fprintf(c.writer, "Conditional Order History (%d)\n", len(orders))
+	fmt.Fprintln(c.writer, "===========================================")
+	for i, order := range orders {
+		fmt.Fprintf(c.writer, "\n[%d] Order ID: %s\n", i+1, order.OrderID)
+		fmt.Fprintf(c.writer, "    Symbol:       %s\n", order.Symbol)
+		fmt.Fprintf(c.writer, "    Side:         %s\n", order.Side)
+		fmt.Fprintf(c.writer, "    Position:     %s\n", order.PositionSide)
+		fmt.Fprintf(c.writer, "    Quantity:     %s\n", c.display.fmtQty(order.Symbol, order.Quantity))
+		fmt.Fprintf(c.writer, "    Status:       %s\n", order.Status)
+		if order.Status == repository.ConditionalOrderStatusCancelled {
+			formatCancellation(c.writer, c.display, order.CancelReason, order.CancelledBy, order.CancelledAt)
+			if order.CancelReason != "" {
+				counts[order.CancelReason]++
+			}
+		}
+	}
+	formatCancellationBreakdown(c.writer, counts)
+	fmt.Fprintln(c.writer, "===========================================")
 	return nil
 }
 
 // handleStopLoss handles the stoploss command
 func (c *FuturesCLI) handleStopLoss(args []string) error {
 	if len(args) < 4 {
-		return fmt.Errorf("usage: stoploss <symbol> <side> <quantity> <price>")
+		return fmt.Errorf("%w: stoploss <symbol> <side> <quantity> <price>", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
 	sideStr := strings.ToUpper(args[1])
-	quantity, err := strconv.ParseFloat(args[2], 64)
+	quantity, err := parseAmount("quantity", args[2])
 	if err != nil {
-		return fmt.Errorf("invalid quantity: %w", err)
+		return err
 	}
-	stopPrice, err := strconv.ParseFloat(args[3], 64)
+	stopPrice, err := parseAmount("stop price", args[3])
 	if err != nil {
-		return fmt.Errorf("invalid stop price: %w", err)
+		return err
 	}
 
 	var positionSide api.PositionSide
@@ -593,27 +1181,31 @@ func (c *FuturesCLI) handleStopLoss(args []string) error {
 	fmt.Fprintf(c.writer, "Order ID:    %s\n", order.OrderID)
 	fmt.Fprintf(c.writer, "Symbol:      %s\n", order.Symbol)
 	fmt.Fprintf(c.writer, "Side:        %s\n", positionSide)
-	fmt.Fprintf(c.writer, "Quantity:    %.8f\n", quantity)
-	fmt.Fprintf(c.writer, "Stop Price:  %.8f\n", order.StopPrice)
+	fmt.Fprintf(c.writer, "Quantity:    %s\n", c.display.fmtQty(order.Symbol, quantity))
+	fmt.Fprintf(c.writer, "Stop Price:  %s\n", c.display.fmtPrice(order.Symbol, order.StopPrice))
 	fmt.Fprintln(c.writer, "-------------------------------------------")
 	return nil
 }
 
 // handleTakeProfit handles the takeprofit command
 func (c *FuturesCLI) handleTakeProfit(args []string) error {
+	args, flags, err := parseTakeProfitFlags(args, false)
+	if err != nil {
+		return err
+	}
 	if len(args) < 4 {
-		return fmt.Errorf("usage: takeprofit <symbol> <side> <quantity> <price>")
+		return fmt.Errorf("%w: takeprofit <symbol> <side> <quantity> <price> [--force]", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
 	sideStr := strings.ToUpper(args[1])
-	quantity, err := strconv.ParseFloat(args[2], 64)
+	quantity, err := parseAmount("quantity", args[2])
 	if err != nil {
-		return fmt.Errorf("invalid quantity: %w", err)
+		return err
 	}
-	targetPrice, err := strconv.ParseFloat(args[3], 64)
+	targetPrice, err := parseAmount("target price", args[3])
 	if err != nil {
-		return fmt.Errorf("invalid target price: %w", err)
+		return err
 	}
 
 	var positionSide api.PositionSide
@@ -625,6 +1217,10 @@ func (c *FuturesCLI) handleTakeProfit(args []string) error {
 		return fmt.Errorf("invalid side: must be LONG or SHORT")
 	}
 
+	if err := confirmTakeProfit(c.writer, c.display, symbol, c.checkTakeProfit(symbol, positionSide, quantity, targetPrice), flags.force); err != nil {
+		return err
+	}
+
 	order, err := c.stopLossService.SetTakeProfit(symbol, positionSide, quantity, targetPrice)
 	if err != nil {
 		return fmt.Errorf("failed to set take profit: %w", err)
@@ -636,16 +1232,95 @@ func (c *FuturesCLI) handleTakeProfit(args []string) error {
 	fmt.Fprintf(c.writer, "Order ID:      %s\n", order.OrderID)
 	fmt.Fprintf(c.writer, "Symbol:        %s\n", order.Symbol)
 	fmt.Fprintf(c.writer, "Side:          %s\n", positionSide)
-	fmt.Fprintf(c.writer, "Quantity:      %.8f\n", quantity)
-	fmt.Fprintf(c.writer, "Target Price:  %.8f\n", order.StopPrice)
+	fmt.Fprintf(c.writer, "Quantity:      %s\n", c.display.fmtQty(order.Symbol, quantity))
+	fmt.Fprintf(c.writer, "Target Price:  %s\n", c.display.fmtPrice(order.Symbol, order.StopPrice))
 	fmt.Fprintln(c.writer, "-------------------------------------------")
 	return nil
 }
 
+// handleBracket handles the bracket command
+func (c *FuturesCLI) handleBracket(args []string) error {
+	args, flags, err := parseTakeProfitFlags(args, false)
+	if err != nil {
+		return err
+	}
+	if len(args) < 5 {
+		return fmt.Errorf("%w: bracket <symbol> <side> <quantity> <stop_price> <target_price> [--force]", ErrUsage)
+	}
+
+	symbol := strings.ToUpper(args[0])
+	sideStr := strings.ToUpper(args[1])
+	quantity, err := parseAmount("quantity", args[2])
+	if err != nil {
+		return err
+	}
+	stopPrice, err := parseAmount("stop price", args[3])
+	if err != nil {
+		return err
+	}
+	targetPrice, err := parseAmount("target price", args[4])
+	if err != nil {
+		return err
+	}
+
+	var positionSide api.PositionSide
+	if sideStr == "LONG" {
+		positionSide = api.PositionSideLong
+	} else if sideStr == "SHORT" {
+		positionSide = api.PositionSideShort
+	} else {
+		return fmt.Errorf("invalid side: must be LONG or SHORT")
+	}
+
+	if err := confirmTakeProfit(c.writer, c.display, symbol, c.checkTakeProfit(symbol, positionSide, quantity, targetPrice), flags.force); err != nil {
+		return err
+	}
+
+	pair, err := c.stopLossService.SetStopLossTakeProfit(symbol, positionSide, quantity, stopPrice, targetPrice)
+	if err != nil {
+		return fmt.Errorf("failed to set stop loss and take profit: %w", err)
+	}
+
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintln(c.writer, "Stop Loss / Take Profit Pair Set")
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	fmt.Fprintf(c.writer, "Pair ID:       %s\n", pair.PairID)
+	fmt.Fprintf(c.writer, "Symbol:        %s\n", pair.Symbol)
+	fmt.Fprintf(c.writer, "Side:          %s\n", positionSide)
+	fmt.Fprintf(c.writer, "Quantity:      %s\n", c.display.fmtQty(symbol, quantity))
+	fmt.Fprintf(c.writer, "Stop Price:    %s\n", c.display.fmtPrice(symbol, pair.StopLossOrder.StopPrice))
+	fmt.Fprintf(c.writer, "Target Price:  %s\n", c.display.fmtPrice(symbol, pair.TakeProfitOrder.StopPrice))
+	fmt.Fprintln(c.writer, "-------------------------------------------")
+	return nil
+}
+
+// checkTakeProfit checks a take-profit target against the fee model using the entry price of
+// the open position, or the mark price when there is none; nil means neither was available
+func (c *FuturesCLI) checkTakeProfit(symbol string, positionSide api.PositionSide, quantity, targetPrice float64) *service.TakeProfitCheck {
+	entryPrice, estimated := 0.0, false
+	if position, err := c.positionManager.GetPosition(symbol, positionSide); err == nil && position != nil {
+		entryPrice = position.EntryPrice
+	}
+	if entryPrice <= 0 {
+		markPrice, err := c.marketService.GetMarkPrice(symbol)
+		if err != nil || markPrice <= 0 {
+			return nil
+		}
+		entryPrice, estimated = markPrice, true
+	}
+
+	check, err := c.profitGuard.Check(positionSide, entryPrice, targetPrice, quantity)
+	if err != nil {
+		return nil
+	}
+	check.EntryEstimated = estimated
+	return check
+}
+
 // handleStopOrders handles the stoporders command
 func (c *FuturesCLI) handleStopOrders(args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("usage: stoporders <symbol>")
+		return fmt.Errorf("%w: stoporders <symbol>", ErrUsage)
 	}
 
 	symbol := strings.ToUpper(args[0])
@@ -665,9 +1340,9 @@ func (c *FuturesCLI) handleStopOrders(args []string) error {
 	for i, order := range orders {
 		fmt.Fprintf(c.writer, "\n[%d] Order ID: %s\n", i+1, order.OrderID)
 		fmt.Fprintf(c.writer, "    Symbol:      %s\n", order.Symbol)
-		fmt.Fprintf(c.writer, "    Type:        %s\n", order.Type)
-		fmt.Fprintf(c.writer, "    Position:    %.8f\n", order.Position)
-		fmt.Fprintf(c.writer, "    Stop Price:  %.8f\n", order.StopPrice)
+		fmt.Fprintf(c.writer, "    Type:        %s\n", c.formatStopOrderType(order.Type))
+		fmt.Fprintf(c.writer, "    Position:    %s\n", c.display.fmtQty(order.Symbol, order.Position))
+		fmt.Fprintf(c.writer, "    Stop Price:  %s\n", c.display.fmtPrice(order.Symbol, order.StopPrice))
 		fmt.Fprintf(c.writer, "    Status:      %s\n", order.Status)
 	}
 	fmt.Fprintln(c.writer, "===========================================")
@@ -677,35 +1352,152 @@ func (c *FuturesCLI) handleStopOrders(args []string) error {
 // handleCancelStopOrder handles the cancelstop command
 func (c *FuturesCLI) handleCancelStopOrder(args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("usage: cancelstop <orderID>")
+		return fmt.Errorf("%w: cancelstop <orderID> [symbol]", ErrUsage)
 	}
 
 	orderID := args[0]
-	if err := c.stopLossService.CancelStopOrder(orderID); err != nil {
+	symbol := ""
+	if len(args) > 1 {
+		symbol = strings.ToUpper(args[1])
+	}
+
+	cancelled, err := c.stopLossService.CancelStopOrder(orderID, symbol)
+	if err != nil {
 		return fmt.Errorf("failed to cancel stop order: %w", err)
 	}
 
 	fmt.Fprintf(c.writer, "Stop order %s cancelled successfully\n", orderID)
+	c.formatCancelledStopOrder(cancelled)
 	return nil
 }
 
+// formatCancelledStopOrder displays the stop or trailing stop order a cancellation removed
+func (c *FuturesCLI) formatCancelledStopOrder(cancelled *service.CancelledStopOrder) {
+	if order := cancelled.StopOrder; order != nil {
+		fmt.Fprintf(c.writer, "    Symbol:      %s\n", order.Symbol)
+		fmt.Fprintf(c.writer, "    Type:        %s\n", c.formatStopOrderType(order.Type))
+		fmt.Fprintf(c.writer, "    Position:    %s\n", c.display.fmtQty(order.Symbol, order.Position))
+		fmt.Fprintf(c.writer, "    Stop Price:  %s\n", c.display.fmtPrice(order.Symbol, order.StopPrice))
+		return
+	}
+	if order := cancelled.TrailingOrder; order != nil {
+		fmt.Fprintf(c.writer, "    Symbol:      %s\n", order.Symbol)
+		fmt.Fprintln(c.writer, "    Type:        TRAILING_STOP")
+		fmt.Fprintf(c.writer, "    Position:    %s\n", c.display.fmtQty(order.Symbol, order.Position))
+		fmt.Fprintf(c.writer, "    Callback:    %.2f%%\n", order.TrailPercent)
+		fmt.Fprintf(c.writer, "    Stop Price:  %s\n", c.display.fmtPrice(order.Symbol, order.CurrentStopPrice))
+	}
+}
+
 // Helper functions for formatting
 
+// handleCarry handles the carry command
+func (c *FuturesCLI) handleCarry(args []string) error {
+	if c.carryService == nil {
+		return fmt.Errorf("carry trading is not available (spot API credentials required)")
+	}
+
+	usage := fmt.Errorf("%w: carry open <symbol> <notional> | carry status | carry close <symbol>", ErrUsage)
+	if len(args) < 1 {
+		return usage
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "open":
+		if len(args) < 3 {
+			return usage
+		}
+		symbol := strings.ToUpper(args[1])
+		notional, err := parseAmount("notional", args[2])
+		if err != nil {
+			return err
+		}
+
+		position, err := c.carryService.Open(symbol, notional)
+		if err != nil {
+			return fmt.Errorf("failed to open carry: %w", err)
+		}
+
+		fmt.Fprintln(c.writer, "Carry position opened")
+		formatCarryPositions(c.writer, c.display, []*service.CarryPosition{position})
+		return nil
+
+	case "status":
+		formatCarryPositions(c.writer, c.display, c.carryService.GetPositions())
+		return nil
+
+	case "close":
+		if len(args) < 2 {
+			return usage
+		}
+		symbol := strings.ToUpper(args[1])
+
+		position, err := c.carryService.Close(symbol, "manual close")
+		if err != nil {
+			return fmt.Errorf("failed to close carry: %w", err)
+		}
+
+		fmt.Fprintln(c.writer, "Carry position closed")
+		formatCarryPositions(c.writer, c.display, []*service.CarryPosition{position})
+		return nil
+
+	default:
+		return usage
+	}
+}
+
+// formatCarryPositions formats and displays carry positions
+func formatCarryPositions(w io.Writer, display *displayFormat, positions []*service.CarryPosition) {
+	if len(positions) == 0 {
+		fmt.Fprintln(w, "No carry positions")
+		return
+	}
+
+	fmt.Fprintln(w, "-------------------------------------------")
+	for _, position := range positions {
+		fmt.Fprintf(w, "Symbol:          %s (%s)\n", position.Symbol, position.Status)
+		symbol := position.Symbol
+		fmt.Fprintf(w, "Spot Long:       %s @ %s\n", display.fmtQty(symbol, position.SpotQuantity), display.fmtPrice(symbol, position.SpotEntryPrice))
+		fmt.Fprintf(w, "Perp Short:      %s @ %s\n", display.fmtQty(symbol, position.HedgeQuantity), display.fmtPrice(symbol, position.FuturesEntryPrice))
+		fmt.Fprintf(w, "Basis:           %s (entry %s)\n", display.fmtPercent(position.CurrentBasis, 4), display.fmtPercent(position.EntryBasis, 4))
+		fmt.Fprintf(w, "Funding Rate:    %s (entry %s)\n", display.fmtPercent(position.CurrentFundingRate*100, 4), display.fmtPercent(position.EntryFundingRate*100, 4))
+		fmt.Fprintf(w, "Funding Accrued: %s\n", display.fmtMoney(position.FundingAccrued))
+		if position.Status == service.CarryStatusUnwinding {
+			fmt.Fprintf(w, "Open Legs:       spot=%t perp=%t\n", position.SpotLegOpen, position.FuturesLegOpen)
+		}
+		if position.CloseReason != "" {
+			fmt.Fprintf(w, "Close Reason:    %s\n", position.CloseReason)
+		}
+		fmt.Fprintln(w, "-------------------------------------------")
+	}
+}
+
 // formatPosition formats and displays position information
 func (c *FuturesCLI) formatPosition(pos *api.Position) {
 	fmt.Fprintln(c.writer, "-------------------------------------------")
 	fmt.Fprintf(c.writer, "Symbol:           %s\n", pos.Symbol)
 	fmt.Fprintf(c.writer, "Position Side:    %s\n", pos.PositionSide)
-	fmt.Fprintf(c.writer, "Position Amount:  %.8f\n", pos.PositionAmt)
-	fmt.Fprintf(c.writer, "Entry Price:      %.8f\n", pos.EntryPrice)
-	fmt.Fprintf(c.writer, "Mark Price:       %.8f\n", pos.MarkPrice)
-	fmt.Fprintf(c.writer, "Unrealized PnL:   %.8f\n", pos.UnrealizedProfit)
-	fmt.Fprintf(c.writer, "Liquidation:      %.8f\n", pos.LiquidationPrice)
+	fmt.Fprintf(c.writer, "Position Amount:  %s\n", c.display.fmtQty(pos.Symbol, pos.PositionAmt))
+	fmt.Fprintf(c.writer, "Entry Price:      %s\n", c.display.fmtPrice(pos.Symbol, pos.EntryPrice))
+	fmt.Fprintf(c.writer, "Mark Price:       %s\n", c.display.fmtPrice(pos.Symbol, pos.MarkPrice))
+	fmt.Fprintf(c.writer, "Unrealized PnL:   %s\n", c.display.fmtMoney(pos.UnrealizedProfit))
+	fmt.Fprintf(c.writer, "Liquidation:      %s\n", c.display.fmtPrice(pos.Symbol, pos.LiquidationPrice))
 	fmt.Fprintf(c.writer, "Leverage:         %dx\n", pos.Leverage)
 	fmt.Fprintf(c.writer, "Margin Type:      %s\n", pos.MarginType)
 	fmt.Fprintln(c.writer, "-------------------------------------------")
 }
 
+// formatTriggerValue formats a trigger threshold; price thresholds are displayed as prices
+func (c *FuturesCLI) formatTriggerValue(symbol string, triggerType service.FuturesTriggerType, value float64) string {
+	if triggerType == service.FuturesTriggerTypeMarkPrice || triggerType == service.FuturesTriggerTypeLastPrice {
+		return c.display.fmtPrice(symbol, value)
+	}
+	if triggerType == service.FuturesTriggerTypeBasis {
+		return fmt.Sprintf("%.3f%%", value)
+	}
+	return fmt.Sprintf("%.8f", value)
+}
+
 // formatTriggerType formats trigger type for display
 func (c *FuturesCLI) formatTriggerType(triggerType service.FuturesTriggerType) string {
 	switch triggerType {
@@ -719,6 +1511,8 @@ func (c *FuturesCLI) formatTriggerType(triggerType service.FuturesTriggerType) s
 		return "FUNDING_RATE"
 	case service.FuturesTriggerTypeMarginRatio:
 		return "MARGIN_RATIO"
+	case service.FuturesTriggerTypeBasis:
+		return "BASIS"
 	default:
 		return "UNKNOWN"
 	}
@@ -739,3 +1533,15 @@ func (c *FuturesCLI) formatOperator(operator service.ComparisonOperator) string
 		return "?"
 	}
 }
+
+// formatStopOrderType formats stop order type for display
+func (c *FuturesCLI) formatStopOrderType(orderType repository.StopOrderType) string {
+	switch orderType {
+	case repository.StopOrderTypeStopLoss:
+		return "STOP_LOSS"
+	case repository.StopOrderTypeTakeProfit:
+		return "TAKE_PROFIT"
+	default:
+		return "UNKNOWN"
+	}
+}
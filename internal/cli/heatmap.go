@@ -0,0 +1,285 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+)
+
+const (
+	defaultHighRiskPct   = 5.0
+	defaultMediumRiskPct = 15.0
+)
+
+// riskBucket classifies a position by how close its mark price is to liquidation
+type riskBucket string
+
+const (
+	riskBucketHigh   riskBucket = "HIGH"
+	riskBucketMedium riskBucket = "MEDIUM"
+	riskBucketLow    riskBucket = "LOW"
+)
+
+// severity orders buckets from most to least risky
+func (b riskBucket) severity() int {
+	switch b {
+	case riskBucketHigh:
+		return 2
+	case riskBucketMedium:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ANSI colors of the heat map
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiYellow = "\033[33m"
+	ansiGreen  = "\033[32m"
+)
+
+// heatMapRow is one open position of the heat map
+type heatMapRow struct {
+	Symbol           string     `json:"symbol"`
+	PositionSide     string     `json:"position_side"`
+	PositionAmt      float64    `json:"position_amt"`
+	Leverage         int        `json:"leverage"`
+	EntryPrice       float64    `json:"entry_price"`
+	MarkPrice        float64    `json:"mark_price"`
+	LiquidationPrice float64    `json:"liquidation_price"`
+	DistancePct      *float64   `json:"distance_to_liquidation_pct"` // nil without a liquidation price
+	UnrealizedPnL    float64    `json:"unrealized_pnl"`
+	Bucket           riskBucket `json:"risk"`
+}
+
+// heatMapReport is the heat map with its account footer
+type heatMapReport struct {
+	Rows               []*heatMapRow `json:"positions"`
+	TotalUnrealizedPnL float64       `json:"total_unrealized_pnl"`
+	MarginRatioPct     float64       `json:"margin_ratio_pct"`
+}
+
+// heatMapThresholds returns the configured risk thresholds, falling back to the defaults
+func heatMapThresholds(cfg config.HeatMapConfig) (float64, float64) {
+	high, medium := cfg.HighRiskPct, cfg.MediumRiskPct
+	if high <= 0 {
+		high = defaultHighRiskPct
+	}
+	if medium <= 0 {
+		medium = defaultMediumRiskPct
+	}
+	if medium < high {
+		medium = high
+	}
+	return high, medium
+}
+
+// assignRiskBucket classifies a distance to liquidation; positions without one are low risk
+func assignRiskBucket(distancePct *float64, highPct, mediumPct float64) riskBucket {
+	if distancePct == nil {
+		return riskBucketLow
+	}
+	switch {
+	case *distancePct <= highPct:
+		return riskBucketHigh
+	case *distancePct <= mediumPct:
+		return riskBucketMedium
+	default:
+		return riskBucketLow
+	}
+}
+
+// liquidationDistancePct returns how far the mark price is from liquidation in percent of the mark
+func liquidationDistancePct(markPrice, liquidationPrice float64) *float64 {
+	if markPrice <= 0 || liquidationPrice <= 0 {
+		return nil
+	}
+	distance := math.Abs(markPrice-liquidationPrice) / markPrice * 100
+	return &distance
+}
+
+// sortHeatMapRows orders rows by risk bucket, then closeness to liquidation, then worst PnL
+func sortHeatMapRows(rows []*heatMapRow) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.Bucket.severity() != b.Bucket.severity() {
+			return a.Bucket.severity() > b.Bucket.severity()
+		}
+		if (a.DistancePct == nil) != (b.DistancePct == nil) {
+			return a.DistancePct != nil
+		}
+		if a.DistancePct != nil && *a.DistancePct != *b.DistancePct {
+			return *a.DistancePct < *b.DistancePct
+		}
+		if a.UnrealizedPnL != b.UnrealizedPnL {
+			return a.UnrealizedPnL < b.UnrealizedPnL
+		}
+		return a.Symbol < b.Symbol
+	})
+}
+
+// buildHeatMap turns open positions into a sorted heat map. liquidationPrice supplies the
+// liquidation price of positions the exchange did not report one for.
+func buildHeatMap(positions []*api.Position, cfg config.HeatMapConfig, liquidationPrice func(*api.Position) (float64, error)) *heatMapReport {
+	highPct, mediumPct := heatMapThresholds(cfg)
+	report := &heatMapReport{}
+
+	var maintenanceMargin, marginBalance float64
+	for _, pos := range positions {
+		if pos.PositionAmt == 0 {
+			continue
+		}
+
+		liquidation := pos.LiquidationPrice
+		if liquidation <= 0 && liquidationPrice != nil {
+			if calculated, err := liquidationPrice(pos); err == nil {
+				liquidation = calculated
+			}
+		}
+
+		distance := liquidationDistancePct(pos.MarkPrice, liquidation)
+		report.Rows = append(report.Rows, &heatMapRow{
+			Symbol:           pos.Symbol,
+			PositionSide:     string(pos.PositionSide),
+			PositionAmt:      pos.PositionAmt,
+			Leverage:         pos.Leverage,
+			EntryPrice:       pos.EntryPrice,
+			MarkPrice:        pos.MarkPrice,
+			LiquidationPrice: liquidation,
+			DistancePct:      distance,
+			UnrealizedPnL:    pos.UnrealizedProfit,
+			Bucket:           assignRiskBucket(distance, highPct, mediumPct),
+		})
+
+		report.TotalUnrealizedPnL += pos.UnrealizedProfit
+		maintenanceMargin += pos.MaintenanceMargin
+		marginBalance += pos.PositionInitialMargin + pos.UnrealizedProfit
+	}
+
+	// Same formula as the per-position margin ratio, over all open positions
+	if maintenanceMargin > 0 {
+		if marginBalance <= 0 {
+			report.MarginRatioPct = math.Inf(1)
+		} else {
+			report.MarginRatioPct = maintenanceMargin / marginBalance * 100
+		}
+	}
+
+	sortHeatMapRows(report.Rows)
+	return report
+}
+
+// isTerminal reports whether w writes to a terminal, so escape codes are only sent to one
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorize wraps s in an ANSI color when color output is enabled
+func colorize(s, color string, enabled bool) string {
+	if !enabled || color == "" {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+// bucketColor returns the color of a risk bucket
+func bucketColor(bucket riskBucket) string {
+	switch bucket {
+	case riskBucketHigh:
+		return ansiRed
+	case riskBucketMedium:
+		return ansiYellow
+	default:
+		return ansiGreen
+	}
+}
+
+// pnlColor returns green for profits and red for losses
+func pnlColor(pnl float64) string {
+	switch {
+	case pnl > 0:
+		return ansiGreen
+	case pnl < 0:
+		return ansiRed
+	default:
+		return ""
+	}
+}
+
+// writeHeatMapJSON prints the heat map as JSON; the margin ratio is omitted when unbounded
+func writeHeatMapJSON(w io.Writer, report *heatMapReport) error {
+	output := *report
+	if output.Rows == nil {
+		output.Rows = []*heatMapRow{}
+	}
+	if math.IsInf(output.MarginRatioPct, 0) {
+		output.MarginRatioPct = -1
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+// formatHeatMap prints one line per position, colored by risk and PnL when color is enabled
+func formatHeatMap(w io.Writer, display *displayFormat, report *heatMapReport, color bool) {
+	fmt.Fprintln(w, "===========================================")
+	fmt.Fprintln(w, "Position Heat Map")
+	fmt.Fprintln(w, "===========================================")
+
+	if len(report.Rows) == 0 {
+		fmt.Fprintln(w, "No open positions")
+		return
+	}
+
+	fmt.Fprintf(w, "%-7s %-12s %-6s %18s %18s %18s %10s %18s\n",
+		"Risk", "Symbol", "Side", "Amount", "Mark", "Liquidation", "Distance", "Unrealized PnL")
+	for _, row := range report.Rows {
+		distance := "-"
+		if row.DistancePct != nil {
+			distance = display.fmtPercent(*row.DistancePct, 2)
+		}
+		side := row.PositionSide
+		if side == "" || side == string(api.PositionSideBoth) {
+			side = "LONG"
+			if row.PositionAmt < 0 {
+				side = "SHORT"
+			}
+		}
+
+		fmt.Fprintf(w, "%s %-12s %-6s %18s %18s %18s %10s %s\n",
+			colorize(fmt.Sprintf("%-7s", row.Bucket), bucketColor(row.Bucket), color),
+			row.Symbol,
+			side,
+			display.fmtQty(row.Symbol, row.PositionAmt),
+			display.fmtPrice(row.Symbol, row.MarkPrice),
+			display.fmtPrice(row.Symbol, row.LiquidationPrice),
+			distance,
+			colorize(fmt.Sprintf("%18s", display.fmtMoney(row.UnrealizedPnL)), pnlColor(row.UnrealizedPnL), color),
+		)
+	}
+
+	fmt.Fprintln(w, "-------------------------------------------")
+	fmt.Fprintf(w, "Total Unrealized PnL: %s\n", colorize(display.fmtMoney(report.TotalUnrealizedPnL), pnlColor(report.TotalUnrealizedPnL), color))
+	if math.IsInf(report.MarginRatioPct, 1) {
+		fmt.Fprintf(w, "Account Margin Ratio: %s\n", colorize("unbounded (margin balance exhausted)", ansiRed, color))
+		return
+	}
+	fmt.Fprintf(w, "Account Margin Ratio: %s\n", display.fmtPercent(report.MarginRatioPct, 2))
+}
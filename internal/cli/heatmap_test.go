@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+)
+
+// syntheticPositions covers every bucket, a missing liquidation price, a flat position and a PnL tie-break
+func syntheticPositions() []*api.Position {
+	return []*api.Position{
+		{Symbol: "BTCUSDT", PositionSide: api.PositionSideLong, PositionAmt: 0.5, MarkPrice: 50000, LiquidationPrice: 30000, UnrealizedProfit: 500, PositionInitialMargin: 2500, MaintenanceMargin: 100},
+		{Symbol: "ETHUSDT", PositionSide: api.PositionSideShort, PositionAmt: -2, MarkPrice: 3000, LiquidationPrice: 3090, UnrealizedProfit: -40, PositionInitialMargin: 300, MaintenanceMargin: 30},
+		{Symbol: "SOLUSDT", PositionSide: api.PositionSideLong, PositionAmt: 10, MarkPrice: 100, LiquidationPrice: 90, UnrealizedProfit: -20, PositionInitialMargin: 100, MaintenanceMargin: 5},
+		{Symbol: "BNBUSDT", PositionSide: api.PositionSideLong, PositionAmt: 1, MarkPrice: 500, LiquidationPrice: 450, UnrealizedProfit: -60, PositionInitialMargin: 50, MaintenanceMargin: 5},
+		{Symbol: "XRPUSDT", PositionSide: api.PositionSideLong, PositionAmt: 100, MarkPrice: 0.5, UnrealizedProfit: 1, PositionInitialMargin: 5},
+		{Symbol: "ADAUSDT", PositionSide: api.PositionSideLong, PositionAmt: 0, MarkPrice: 0.4, LiquidationPrice: 0.39},
+	}
+}
+
+func TestAssignRiskBucket(t *testing.T) {
+	distance := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name     string
+		distance *float64
+		want     riskBucket
+	}{
+		{"no liquidation price", nil, riskBucketLow},
+		{"inside high threshold", distance(2), riskBucketHigh},
+		{"on high threshold", distance(5), riskBucketHigh},
+		{"inside medium threshold", distance(10), riskBucketMedium},
+		{"on medium threshold", distance(15), riskBucketMedium},
+		{"far from liquidation", distance(40), riskBucketLow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := assignRiskBucket(tt.distance, 5, 15); got != tt.want {
+				t.Errorf("assignRiskBucket() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeatMapThresholds(t *testing.T) {
+	if high, medium := heatMapThresholds(config.HeatMapConfig{}); high != 5 || medium != 15 {
+		t.Errorf("expected default thresholds 5/15, got %v/%v", high, medium)
+	}
+	if high, medium := heatMapThresholds(config.HeatMapConfig{HighRiskPct: 20}); high != 20 || medium != 20 {
+		t.Errorf("medium threshold should not fall below the high one, got %v/%v", high, medium)
+	}
+}
+
+func TestBuildHeatMap_SortsByRisk(t *testing.T) {
+	calculated := 0
+	report := buildHeatMap(syntheticPositions(), config.HeatMapConfig{}, func(pos *api.Position) (float64, error) {
+		calculated++
+		return 0, nil
+	})
+
+	var order []string
+	for _, row := range report.Rows {
+		order = append(order, row.Symbol+":"+string(row.Bucket))
+	}
+	// ETH is 3% from liquidation; SOL and BNB tie at 10% and the larger loss comes first;
+	// XRP has no liquidation price and sorts after BTC, which is 40% away
+	want := "ETHUSDT:HIGH BNBUSDT:MEDIUM SOLUSDT:MEDIUM BTCUSDT:LOW XRPUSDT:LOW"
+	if got := strings.Join(order, " "); got != want {
+		t.Errorf("heat map order = %s, want %s", got, want)
+	}
+
+	if calculated != 1 {
+		t.Errorf("liquidation price should only be calculated for XRPUSDT, got %d calculations", calculated)
+	}
+	if math.Abs(report.TotalUnrealizedPnL-381) > 1e-9 {
+		t.Errorf("expected total unrealized PnL 381, got %v", report.TotalUnrealizedPnL)
+	}
+	// 140 maintenance over 3336 margin balance
+	if math.Abs(report.MarginRatioPct-140.0/3336*100) > 1e-9 {
+		t.Errorf("unexpected account margin ratio %v", report.MarginRatioPct)
+	}
+}
+
+func TestBuildHeatMap_ConfiguredThresholds(t *testing.T) {
+	report := buildHeatMap(syntheticPositions(), config.HeatMapConfig{HighRiskPct: 12, MediumRiskPct: 50}, nil)
+
+	buckets := make(map[string]riskBucket)
+	for _, row := range report.Rows {
+		buckets[row.Symbol] = row.Bucket
+	}
+	if buckets["SOLUSDT"] != riskBucketHigh || buckets["BTCUSDT"] != riskBucketMedium || buckets["XRPUSDT"] != riskBucketLow {
+		t.Errorf("unexpected buckets with configured thresholds: %v", buckets)
+	}
+}
+
+func TestFormatHeatMap_Color(t *testing.T) {
+	report := buildHeatMap(syntheticPositions(), config.HeatMapConfig{}, nil)
+
+	var plain bytes.Buffer
+	formatHeatMap(&plain, newDisplayFormat(nil), report, false)
+	if strings.Contains(plain.String(), "\033[") {
+		t.Errorf("plain output should not contain escape codes, got %q", plain.String())
+	}
+	if !strings.Contains(plain.String(), "Account Margin Ratio: 4.20%") {
+		t.Errorf("expected margin ratio footer, got %q", plain.String())
+	}
+
+	var colored bytes.Buffer
+	formatHeatMap(&colored, newDisplayFormat(nil), report, true)
+	if !strings.Contains(colored.String(), ansiRed+"HIGH") || !strings.Contains(colored.String(), ansiYellow+"MEDIUM") {
+		t.Errorf("colored output should color rows by risk, got %q", colored.String())
+	}
+}
+
+func TestWriteHeatMapJSON(t *testing.T) {
+	report := buildHeatMap(syntheticPositions(), config.HeatMapConfig{}, nil)
+
+	var buf bytes.Buffer
+	if err := writeHeatMapJSON(&buf, report); err != nil {
+		t.Fatalf("writeHeatMapJSON() unexpected error: %v", err)
+	}
+	if strings.Contains(buf.String(), "\033[") {
+		t.Error("JSON output should not contain escape codes")
+	}
+
+	var decoded struct {
+		Positions []struct {
+			Symbol      string   `json:"symbol"`
+			Risk        string   `json:"risk"`
+			DistancePct *float64 `json:"distance_to_liquidation_pct"`
+		} `json:"positions"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(decoded.Positions) != 5 || decoded.Positions[0].Symbol != "ETHUSDT" || decoded.Positions[0].Risk != "HIGH" {
+		t.Errorf("unexpected JSON positions: %+v", decoded.Positions)
+	}
+	if decoded.Positions[4].DistancePct != nil {
+		t.Error("positions without a liquidation price should have no distance")
+	}
+}
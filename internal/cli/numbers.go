@@ -0,0 +1,270 @@
+package cli
+
+import (
+	"binance-trader/pkg/timeutil"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// plainNumber matches a number once separators, sign and suffixes are stripped; it excludes
+// the hex, inf and nan forms strconv.ParseFloat would otherwise accept
+var plainNumber = regexp.MustCompile(`^(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// numberFormat describes which forms a numeric argument accepts
+type numberFormat struct {
+	AllowNegative     bool // Signed thresholds such as PnL or percent change triggers
+	AllowZero         bool
+	AllowPercent      bool // Accept a trailing % sign
+	PercentAsFraction bool // "0.05%" means 0.0005, for rates stored as fractions
+	Integer           bool // Counts such as leverage or grid levels
+}
+
+// numberSuffixes maps magnitude suffixes to their multipliers
+var numberSuffixes = map[byte]float64{
+	'k': 1e3,
+	'K': 1e3,
+	'm': 1e6,
+	'M': 1e6,
+}
+
+// parseAmount parses a quantity, price or notional, which must be greater than 0
+func parseAmount(name, s string) (float64, error) {
+	return parseNumber(name, s, numberFormat{})
+}
+
+// parseSigned parses a trigger threshold, which may be negative or zero
+func parseSigned(name, s string, allowPercent bool) (float64, error) {
+	return parseNumber(name, s, numberFormat{AllowNegative: true, AllowZero: true, AllowPercent: allowPercent})
+}
+
+// parseCount parses a positive whole number such as leverage or a grid count
+func parseCount(name, s string) (int, error) {
+	value, err := parseNumber(name, s, numberFormat{Integer: true})
+	if err != nil {
+		return 0, err
+	}
+	return int(value), nil
+}
+
+// parseATRSpec parses an ATR trail given as <period>x<multiplier>, e.g. "14x2.5"
+func parseATRSpec(s string) (period int, multiplier float64, err error) {
+	periodText, multiplierText, ok := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "x")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid ATR trail %q: expected <period>x<multiplier>, e.g. 14x2.5", s)
+	}
+	if period, err = parseCount("ATR period", periodText); err != nil {
+		return 0, 0, err
+	}
+	if multiplier, err = parseAmount("ATR multiplier", multiplierText); err != nil {
+		return 0, 0, err
+	}
+	return period, multiplier, nil
+}
+
+// parseVolumeSpec parses a VOLUME trigger given as <threshold>[@window], e.g. "20k@1h"; the
+// window is 0 when left out
+func parseVolumeSpec(s string) (threshold float64, window time.Duration, err error) {
+	thresholdText, windowText, hasWindow := strings.Cut(strings.TrimSpace(s), "@")
+	if threshold, err = parseAmount("volume threshold", thresholdText); err != nil {
+		return 0, 0, err
+	}
+	if !hasWindow {
+		return threshold, 0, nil
+	}
+	if window, err = time.ParseDuration(windowText); err != nil {
+		return 0, 0, fmt.Errorf("invalid volume window %q: expected a duration, e.g. 15m or 4h", windowText)
+	}
+	return threshold, window, nil
+}
+
+// parseDurationArg parses a positive duration given as a whole number of units, e.g. seconds
+// for a TTL of "14400", or as a duration such as "4h"
+func parseDurationArg(name, s string, unit time.Duration) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if count, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if count <= 0 {
+			return 0, fmt.Errorf("invalid %s %q: must be greater than 0", name, s)
+		}
+		return time.Duration(count) * unit, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s %q: expected a positive duration, e.g. %d or 4h", name, s, int64(4*time.Hour/unit))
+	}
+	return d, nil
+}
+
+// parseTriggerTime parses the timestamp of a TIME trigger, given as Unix seconds, Unix
+// milliseconds or an RFC3339 time, into Unix milliseconds
+func parseTriggerTime(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if ts, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if ts <= 0 {
+			return 0, fmt.Errorf("invalid trigger time %q: must be greater than 0", s)
+		}
+		return timeutil.NormalizeMillis(ts), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid trigger time %q: expected Unix seconds or milliseconds or an RFC3339 time, e.g. 2024-05-01T08:00:00Z", s)
+	}
+	return timeutil.Millis(t), nil
+}
+
+// parseLimitSpec parses the limit price of a conditional order: a plain price is fixed, a signed
+// value is an offset from the trigger price, absolute ("-50") or in percent ("+0.2%")
+func parseLimitSpec(s string) (price, offset float64, percent bool, err error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "+") && !strings.HasPrefix(s, "-") {
+		price, err = parseAmount("limit price", s)
+		return price, 0, false, err
+	}
+	offset, err = parseNumber("limit price offset", s, numberFormat{AllowNegative: true, AllowPercent: true})
+	return 0, offset, strings.HasSuffix(s, "%"), err
+}
+
+// parseNumber parses a CLI number. Besides plain and scientific notation ("1e-3") it accepts
+// underscores or commas as thousands separators ("50_000", "50,000") and k/m suffixes ("50k").
+// Commas must form groups of three after a non-zero leading group, so locale decimals such as
+// "1,5" or "0,001" are rejected instead of being read as a different value.
+func parseNumber(name, s string, format numberFormat) (float64, error) {
+	input := strings.TrimSpace(s)
+	value, err := parseNumberValue(input, format)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %s (accepted formats: %s)", name, s, err.Error(), acceptedNumberFormats(format))
+	}
+	return value, nil
+}
+
+// parseNumberValue does the parsing for parseNumber and returns unwrapped reasons
+func parseNumberValue(s string, format numberFormat) (float64, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty value")
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+	if negative && !format.AllowNegative {
+		return 0, fmt.Errorf("must not be negative")
+	}
+
+	percent := strings.HasSuffix(s, "%")
+	if percent {
+		if !format.AllowPercent {
+			return 0, fmt.Errorf("percent sign not allowed here")
+		}
+		s = strings.TrimSuffix(s, "%")
+	}
+
+	multiplier := 1.0
+	if len(s) > 0 {
+		if factor, ok := numberSuffixes[s[len(s)-1]]; ok {
+			if percent {
+				return 0, fmt.Errorf("suffix cannot be combined with a percent sign")
+			}
+			multiplier = factor
+			s = s[:len(s)-1]
+		}
+	}
+
+	s, err := stripThousandsSeparators(s)
+	if err != nil {
+		return 0, err
+	}
+	if !plainNumber.MatchString(s) {
+		return 0, fmt.Errorf("not a number")
+	}
+	if multiplier != 1 && strings.ContainsAny(s, "eE") {
+		return 0, fmt.Errorf("suffix cannot be combined with scientific notation")
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("out of range")
+	}
+	value *= multiplier
+	if negative {
+		value = -value
+	}
+	if percent && format.PercentAsFraction {
+		value /= 100
+	}
+
+	if value == 0 && !format.AllowZero {
+		return 0, fmt.Errorf("must be greater than 0")
+	}
+	if format.Integer && value != math.Trunc(value) {
+		return 0, fmt.Errorf("must be a whole number")
+	}
+	return value, nil
+}
+
+// stripThousandsSeparators removes underscores or commas used as thousands separators
+func stripThousandsSeparators(s string) (string, error) {
+	hasComma := strings.Contains(s, ",")
+	hasUnderscore := strings.Contains(s, "_")
+	if hasComma && hasUnderscore {
+		return "", fmt.Errorf("mixed thousands separators")
+	}
+	if !hasComma && !hasUnderscore {
+		return s, nil
+	}
+
+	separator := ","
+	if hasUnderscore {
+		separator = "_"
+	}
+
+	integerPart, fraction, hasFraction := strings.Cut(s, ".")
+	if strings.Contains(fraction, separator) {
+		return "", fmt.Errorf("thousands separator after the decimal point")
+	}
+
+	groups := strings.Split(integerPart, separator)
+	for i, group := range groups {
+		if group == "" || strings.Trim(group, "0123456789") != "" {
+			return "", fmt.Errorf("misplaced thousands separator")
+		}
+		// Commas are also used as decimal marks; only unambiguous groupings are accepted
+		if separator == "," {
+			if i == 0 && (len(group) > 3 || group[0] == '0') {
+				return "", fmt.Errorf("ambiguous comma, use '.' for decimals")
+			}
+			if i > 0 && len(group) != 3 {
+				return "", fmt.Errorf("ambiguous comma, use '.' for decimals")
+			}
+		}
+	}
+
+	result := strings.Join(groups, "")
+	if hasFraction {
+		result += "." + fraction
+	}
+	return result, nil
+}
+
+// acceptedNumberFormats lists the forms accepted for an argument in error messages
+func acceptedNumberFormats(format numberFormat) string {
+	if format.Integer {
+		return "10, 1,000, 1_000, 1k"
+	}
+
+	formats := "1000, 1,000, 1_000, 1.5k, 2m, 0.5, 1e-3"
+	if format.AllowNegative {
+		formats += ", -5"
+	}
+	if format.AllowPercent {
+		formats += ", 5%"
+	}
+	return formats
+}
@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+)
+
+func TestParseNumber_Accepted(t *testing.T) {
+	tests := []struct {
+		input  string
+		format numberFormat
+		want   float64
+	}{
+		{"50000", numberFormat{}, 50000},
+		{"0.001", numberFormat{}, 0.001},
+		{".5", numberFormat{}, 0.5},
+		{"5.", numberFormat{}, 5},
+		{"+2", numberFormat{}, 2},
+		{" 42 ", numberFormat{}, 42},
+		{"50,000", numberFormat{}, 50000},
+		{"1,234,567.89", numberFormat{}, 1234567.89},
+		{"50_000", numberFormat{}, 50000},
+		{"1_0", numberFormat{}, 10},
+		{"50k", numberFormat{}, 50000},
+		{"1.5K", numberFormat{}, 1500},
+		{"2m", numberFormat{}, 2000000},
+		{"1,500k", numberFormat{}, 1500000},
+		{"1e-3", numberFormat{}, 0.001},
+		{"2.5E+2", numberFormat{}, 250},
+		{"-5", numberFormat{AllowNegative: true}, -5},
+		{"-1.5k", numberFormat{AllowNegative: true}, -1500},
+		{"0", numberFormat{AllowZero: true}, 0},
+		{"-5%", numberFormat{AllowNegative: true, AllowPercent: true}, -5},
+		{"5", numberFormat{AllowPercent: true}, 5},
+		{"0.05%", numberFormat{AllowPercent: true, PercentAsFraction: true}, 0.0005},
+		{"0.0005", numberFormat{AllowPercent: true, PercentAsFraction: true}, 0.0005},
+		{"1e3", numberFormat{Integer: true}, 1000},
+		{"1k", numberFormat{Integer: true}, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseNumber("value", tt.input, tt.format)
+			if err != nil {
+				t.Fatalf("parseNumber(%q) unexpected error: %v", tt.input, err)
+			}
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("parseNumber(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNumber_Rejected(t *testing.T) {
+	tests := []struct {
+		input  string
+		format numberFormat
+		reason string
+	}{
+		{"", numberFormat{}, "empty value"},
+		{"abc", numberFormat{}, "not a number"},
+		{"1,5", numberFormat{}, "ambiguous comma"},
+		{"0,001", numberFormat{}, "ambiguous comma"},
+		{"1,50", numberFormat{}, "ambiguous comma"},
+		{"1234,567", numberFormat{}, "ambiguous comma"},
+		{"1,000,5", numberFormat{}, "ambiguous comma"},
+		{"1.000,5", numberFormat{}, "thousands separator after the decimal point"},
+		{"1,000_000", numberFormat{}, "mixed thousands separators"},
+		{",100", numberFormat{}, "misplaced thousands separator"},
+		{"100_", numberFormat{}, "misplaced thousands separator"},
+		{"1__0", numberFormat{}, "misplaced thousands separator"},
+		{"-5", numberFormat{}, "must not be negative"},
+		{"0", numberFormat{}, "must be greater than 0"},
+		{"0k", numberFormat{}, "must be greater than 0"},
+		{"5%", numberFormat{}, "percent sign not allowed"},
+		{"5k%", numberFormat{AllowPercent: true}, "suffix cannot be combined with a percent sign"},
+		{"1e3k", numberFormat{}, "suffix cannot be combined with scientific notation"},
+		{"k", numberFormat{}, "not a number"},
+		{"5x", numberFormat{}, "not a number"},
+		{"1.2.3", numberFormat{}, "not a number"},
+		{"0x10", numberFormat{}, "not a number"},
+		{"inf", numberFormat{}, "not a number"},
+		{"NaN", numberFormat{}, "not a number"},
+		{"1e999", numberFormat{}, "out of range"},
+		{"2.5", numberFormat{Integer: true}, "must be a whole number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := parseNumber("quantity", tt.input, tt.format)
+			if err == nil {
+				t.Fatalf("parseNumber(%q) expected error", tt.input)
+			}
+			if !strings.Contains(err.Error(), tt.reason) {
+				t.Errorf("parseNumber(%q) error %q should contain %q", tt.input, err.Error(), tt.reason)
+			}
+			if !strings.Contains(err.Error(), "accepted formats:") {
+				t.Errorf("parseNumber(%q) error should list accepted formats, got %q", tt.input, err.Error())
+			}
+		})
+	}
+}
+
+func TestParseNumber_ErrorMessage(t *testing.T) {
+	_, err := parseAmount("quantity", "1,5")
+	want := `invalid quantity "1,5": ambiguous comma, use '.' for decimals (accepted formats: 1000, 1,000, 1_000, 1.5k, 2m, 0.5, 1e-3)`
+	if err == nil || err.Error() != want {
+		t.Errorf("got %v, want %s", err, want)
+	}
+
+	_, err = parseSigned("trigger value", "abc", true)
+	if err == nil || !strings.Contains(err.Error(), "-5, 5%") {
+		t.Errorf("signed percent formats should be listed, got %v", err)
+	}
+
+	_, err = parseCount("leverage", "2.5")
+	if err == nil || !strings.Contains(err.Error(), "accepted formats: 10, 1,000, 1_000, 1k") {
+		t.Errorf("count formats should be listed, got %v", err)
+	}
+}
+
+func TestParseATRSpec(t *testing.T) {
+	period, multiplier, err := parseATRSpec("14x2.5")
+	if err != nil || period != 14 || multiplier != 2.5 {
+		t.Errorf("parseATRSpec(14x2.5) = %d, %v, %v", period, multiplier, err)
+	}
+
+	for _, input := range []string{"14", "x2", "14x", "2.5x14", "14x-1", "0x2"} {
+		if _, _, err := parseATRSpec(input); err == nil {
+			t.Errorf("parseATRSpec(%q) expected error", input)
+		}
+	}
+}
+
+func TestHandlersUseNumberParser(t *testing.T) {
+	t.Run("spot buy accepts suffixes", func(t *testing.T) {
+		var gotQuantity float64
+		mockTrading := &mockTradingService{
+			placeMarketBuyOrderFunc: func(symbol string, quantity float64) (*api.Order, error) {
+				gotQuantity = quantity
+				return &api.Order{OrderID: 1, Symbol: symbol}, nil
+			},
+		}
+		cli := NewCLI(mockTrading, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		cli.writer = &bytes.Buffer{}
+
+		if err := cli.handleBuy([]string{"DOGEUSDT", "1.5k"}); err != nil {
+			t.Fatalf("handleBuy unexpected error: %v", err)
+		}
+		if gotQuantity != 1500 {
+			t.Errorf("expected quantity 1500, got %v", gotQuantity)
+		}
+	})
+
+	t.Run("spot sell rejects ambiguous comma", func(t *testing.T) {
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, &mockLogger{})
+		err := cli.handleSell([]string{"BTCUSDT", "50,000", "1,5"})
+		if err == nil || !strings.Contains(err.Error(), `invalid quantity "1,5"`) {
+			t.Errorf("expected ambiguous quantity error, got %v", err)
+		}
+	})
+
+	t.Run("price change trigger accepts percent", func(t *testing.T) {
+		var gotValue float64
+		mockCondSvc := &mockConditionalOrderService{
+			createConditionalOrderFunc: func(request *repository.ConditionalOrderRequest) (*repository.ConditionalOrder, error) {
+				gotValue = request.TriggerCondition.Value
+				return &repository.ConditionalOrder{OrderID: "c1", Symbol: request.Symbol, TriggerCondition: request.TriggerCondition}, nil
+			},
+		}
+		cli := NewCLI(&mockTradingService{}, &mockMarketDataService{}, mockCondSvc, &mockStopLossService{}, &mockLogger{})
+		cli.writer = &bytes.Buffer{}
+
+		if err := cli.handleConditionalOrder([]string{"ETHUSDT", "SELL", "0.05", "PRICE_CHANGE", "<=", "-5%"}); err != nil {
+			t.Fatalf("handleConditionalOrder unexpected error: %v", err)
+		}
+		if gotValue != -5 {
+			t.Errorf("expected trigger value -5, got %v", gotValue)
+		}
+
+		if err := cli.handleConditionalOrder([]string{"ETHUSDT", "SELL", "0.05", "PRICE", "<=", "5%"}); err == nil {
+			t.Error("percent sign should be rejected for price triggers")
+		}
+	})
+
+	t.Run("futures leverage requires a whole number", func(t *testing.T) {
+		cli := NewFuturesCLI(nil, nil, nil, nil, nil, &mockLogger{})
+		err := cli.handleLeverage([]string{"BTCUSDT", "2.5"})
+		if err == nil || !strings.Contains(err.Error(), "must be a whole number") {
+			t.Errorf("expected whole number error, got %v", err)
+		}
+	})
+
+	t.Run("futures long rejects negative quantity", func(t *testing.T) {
+		cli := NewFuturesCLI(nil, nil, nil, nil, nil, &mockLogger{})
+		err := cli.handleLong([]string{"BTCUSDT", "-0.01"})
+		if err == nil || !strings.Contains(err.Error(), "must not be negative") {
+			t.Errorf("expected negative quantity error, got %v", err)
+		}
+	})
+}
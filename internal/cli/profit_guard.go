@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"binance-trader/internal/service"
+)
+
+// takeProfitFlags holds the options of commands that create a take profit
+type takeProfitFlags struct {
+	force      bool    // Create targets that lose money after fees
+	entryPrice float64 // Known entry price; 0 estimates it
+}
+
+// parseTakeProfitFlags removes --force and, when allowEntry is set, --entry <price> from args
+func parseTakeProfitFlags(args []string, allowEntry bool) ([]string, *takeProfitFlags, error) {
+	flags := &takeProfitFlags{}
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--force":
+			flags.force = true
+		case args[i] == "--entry" && allowEntry:
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("%w: --entry <price>", ErrUsage)
+			}
+			entry, err := parseAmount("entry price", args[i+1])
+			if err != nil {
+				return nil, nil, err
+			}
+			flags.entryPrice = entry
+			i++
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	return rest, flags, nil
+}
+
+// confirmTakeProfit prints the net result at a take-profit target after entry and exit fees.
+// Targets that lose money are refused unless forced; ones below the minimum profit only warn.
+// A nil check means no entry price was available and the target could not be checked.
+func confirmTakeProfit(w io.Writer, display *displayFormat, symbol string, check *service.TakeProfitCheck, force bool) error {
+	if check == nil {
+		fmt.Fprintln(w, "Warning: entry price unknown, take profit not checked against fees")
+		return nil
+	}
+
+	entry := display.fmtPrice(symbol, check.EntryPrice)
+	if check.EntryEstimated {
+		entry += " (estimated from current price)"
+	}
+
+	if check.Unprofitable() && !force {
+		return fmt.Errorf("take profit at %s loses %s after %s fees from entry %s (%s); use --force to create it anyway",
+			display.fmtPrice(symbol, check.TargetPrice), display.fmtMoney(-check.NetPnL), display.fmtMoney(check.Fees),
+			entry, display.fmtPercent(check.NetPercent, 2))
+	}
+
+	fmt.Fprintf(w, "Net at target:  %s (%s) after %s fees, entry %s\n",
+		display.fmtMoney(check.NetPnL), display.fmtPercent(check.NetPercent, 2), display.fmtMoney(check.Fees), entry)
+	switch {
+	case check.Unprofitable():
+		fmt.Fprintln(w, "Warning: take profit loses money after fees, created because of --force")
+	case check.BelowMinimum():
+		fmt.Fprintf(w, "Warning: net profit %s is below the minimum of %s\n",
+			display.fmtPercent(check.NetPercent, 2), display.fmtPercent(check.MinProfitPercent, 2))
+	}
+	return nil
+}
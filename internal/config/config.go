@@ -1,20 +1,39 @@
 package config
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"os"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
+
+	"binance-trader/internal/api"
 
 	"gopkg.in/yaml.v3"
 )
 
-// TradingType represents the type of trading (spot or futures)
+// TradingType represents the type of trading (spot, futures, or both in one process)
 type TradingType string
 
 const (
 	TradingTypeSpot    TradingType = "spot"
 	TradingTypeFutures TradingType = "futures"
+	TradingTypeBoth    TradingType = "both"
+)
+
+// Run modes
+const (
+	RunModeInteractive = "interactive"
+	RunModeDaemon      = "daemon"
+)
+
+// Storage types
+const (
+	StorageTypeMemory = "memory"
+	StorageTypeSQLite = "sqlite"
 )
 
 // BinanceConfig holds Binance API configuration
@@ -23,14 +42,33 @@ type BinanceConfig struct {
 	APISecret string `yaml:"api_secret"`
 	BaseURL   string `yaml:"base_url"`
 	Testnet   bool   `yaml:"testnet"`
+	DryRun    bool   `yaml:"dry_run"` // Simulate this market's orders with the dry_run settings
 }
 
 // RiskConfig holds risk management configuration
 type RiskConfig struct {
-	MaxOrderAmount    float64 `yaml:"max_order_amount"`
-	MaxDailyOrders    int     `yaml:"max_daily_orders"`
-	MinBalanceReserve float64 `yaml:"min_balance_reserve"`
-	MaxAPICallsPerMin int     `yaml:"max_api_calls_per_min"`
+	MaxOrderAmount     float64                  `yaml:"max_order_amount"`
+	MaxDailyOrders     int                      `yaml:"max_daily_orders"`
+	MinBalanceReserve  float64                  `yaml:"min_balance_reserve"`
+	MaxAPICallsPerMin  int                      `yaml:"max_api_calls_per_min"`
+	MaxNotionalPerMin  float64                  `yaml:"max_notional_per_min"` // 0 disables the throughput cap
+	NotionalCapMode    string                   `yaml:"notional_cap_mode"`    // reject or delay
+	MaxSlippagePercent float64                  `yaml:"max_slippage_percent"` // Reject market orders whose top-of-book fill deviates more, 0 disables the check
+	DailyOrdersFile    string                   `yaml:"daily_orders_file"`    // Keeps the daily order count across restarts, empty = memory only
+	Coverage           ProtectionCoverageConfig `yaml:"coverage"`
+}
+
+// ProtectionCoverageConfig holds the scheduled check that open exposure is covered by stop orders
+type ProtectionCoverageConfig struct {
+	CheckIntervalMs int      `yaml:"check_interval_ms"` // 0 disables the scheduled check
+	MinCoveragePct  float64  `yaml:"min_coverage_pct"`  // Notify when protected exposure falls below this percentage
+	SpotSymbols     []string `yaml:"spot_symbols"`      // Spot holdings to check, e.g. BTCUSDT
+}
+
+// ProtectionConfig holds how local stop orders follow changes in position size
+type ProtectionConfig struct {
+	AutoScale       string `yaml:"auto_scale"`        // off, scale or supplement
+	CheckIntervalMs int    `yaml:"check_interval_ms"` // 0 uses the default interval
 }
 
 // LoggingConfig holds logging configuration
@@ -50,6 +88,45 @@ type RetryConfig struct {
 	BackoffMultiplier float64 `yaml:"backoff_multiplier"`
 }
 
+// NetworkConfig holds HTTP network configuration
+type NetworkConfig struct {
+	Timeouts   TimeoutsConfig   `yaml:"timeouts"`
+	DataSource DataSourceConfig `yaml:"data_source"`
+	BookTicker BookTickerConfig `yaml:"book_ticker"`
+}
+
+// TimeoutsConfig holds per-endpoint-category request timeouts
+type TimeoutsConfig struct {
+	OrderMs      int `yaml:"order_ms"`
+	MarketDataMs int `yaml:"market_data_ms"`
+	AccountMs    int `yaml:"account_ms"`
+	DefaultMs    int `yaml:"default_ms"`
+}
+
+// DataSourceConfig holds the WebSocket-vs-REST price source fallback policy
+type DataSourceConfig struct {
+	Prefer            string `yaml:"prefer"`             // websocket or rest
+	MaxFailures       int    `yaml:"max_failures"`       // Stream failures within the window before falling back to REST
+	FailureWindowMs   int    `yaml:"failure_window_ms"`
+	ProbeIntervalMs   int    `yaml:"probe_interval_ms"`  // How often a fallen-back stream is probed
+	RecoverySuccesses int    `yaml:"recovery_successes"` // Consecutive healthy probes before switching back
+}
+
+// BookTickerConfig holds the best bid/ask stream cache settings
+type BookTickerConfig struct {
+	StaleAfterMs int `yaml:"stale_after_ms"` // Cached quotes older than this are refreshed over REST
+}
+
+// MarketDataConfig holds the spot market data stream settings
+type MarketDataConfig struct {
+	WebSocketEnabled   bool   `yaml:"websocket_enabled"`    // Push price subscriptions over the ticker stream instead of polling REST
+	StreamURL          string `yaml:"stream_url"`           // Empty uses wss://stream.binance.com/ws
+	ReconnectInitialMs int    `yaml:"reconnect_initial_ms"` // First reconnect delay, doubled after every failed attempt
+	ReconnectMaxMs     int    `yaml:"reconnect_max_ms"`
+	UserStreamEnabled  bool   `yaml:"user_stream_enabled"` // Track order fills pushed over the user data stream
+	UserStreamURL      string `yaml:"user_stream_url"`     // Empty uses wss://stream.binance.com:9443/ws
+}
+
 // ConditionalOrdersConfig holds conditional orders configuration
 type ConditionalOrdersConfig struct {
 	MonitoringIntervalMs      int  `yaml:"monitoring_interval_ms"`
@@ -60,10 +137,273 @@ type ConditionalOrdersConfig struct {
 
 // StopLossConfig holds stop loss configuration
 type StopLossConfig struct {
-	DefaultTrailPercent float64 `yaml:"default_trail_percent"`
-	MinTrailPercent     float64 `yaml:"min_trail_percent"`
-	MaxTrailPercent     float64 `yaml:"max_trail_percent"`
-	UpdateIntervalMs    int     `yaml:"update_interval_ms"`
+	DefaultTrailPercent float64           `yaml:"default_trail_percent"`
+	MinTrailPercent     float64           `yaml:"min_trail_percent"`
+	MaxTrailPercent     float64           `yaml:"max_trail_percent"`
+	ATRInterval         string            `yaml:"atr_interval"` // Kline interval of ATR trailing stops; empty = 1h
+	UpdateIntervalMs    int               `yaml:"update_interval_ms"`
+	PriceSanity         PriceSanityConfig `yaml:"price_sanity"`
+	ProfitGuard         ProfitGuardConfig `yaml:"profit_guard"`
+}
+
+// ProfitGuardConfig holds the fee-aware check of take-profit targets
+type ProfitGuardConfig struct {
+	FeeRate          float64 `yaml:"fee_rate"`           // Commission per side as a fraction, 0 = the market's taker rate
+	MinProfitPercent float64 `yaml:"min_profit_percent"` // Warn when the net profit at the target is below this percent of the entry value
+}
+
+// validate checks the fee rate and minimum profit; section prefixes the field names in errors
+func (p *ProfitGuardConfig) validate(section string) error {
+	if p.FeeRate < 0 || p.FeeRate >= 1 {
+		return fmt.Errorf("%s.fee_rate must be between 0 and 1", section)
+	}
+	if p.MinProfitPercent < 0 {
+		return fmt.Errorf("%s.min_profit_percent cannot be negative", section)
+	}
+	return nil
+}
+
+// PriceSanityConfig holds the bad-tick filter applied before protective triggers act
+type PriceSanityConfig struct {
+	MaxDeviationPercent    float64            `yaml:"max_deviation_percent"`
+	SymbolDeviationPercent map[string]float64 `yaml:"symbol_deviation_percent"`
+}
+
+// TradingConfig holds general order handling configuration
+type TradingConfig struct {
+	RoundingMode      string                 `yaml:"rounding_mode"`
+	ExchangeInfoTTLMs int                    `yaml:"exchange_info_ttl_ms"` // Symbol filter cache lifetime, 0 = 1 hour
+	FailurePause      FailurePauseConfig     `yaml:"failure_pause"`
+	ReplayProtection  ReplayProtectionConfig `yaml:"replay_protection"`
+	DryRun            bool                   `yaml:"dry_run"` // Alias of dry_run.enabled, kept for older config files
+}
+
+// FailurePauseConfig holds the auto-pause settings for symbols with repeated order failures
+type FailurePauseConfig struct {
+	MaxFailures int `yaml:"max_failures"`
+	WindowMs    int `yaml:"window_ms"`
+	CooldownMs  int `yaml:"cooldown_ms"`
+}
+
+// ReplayProtectionConfig holds the post-restart check that keeps executed orders from being resubmitted
+type ReplayProtectionConfig struct {
+	WindowMs int `yaml:"window_ms"` // How long after startup, and how far back, orders are cross-checked; 0 disables
+}
+
+// AutomationConfig holds limits for automated DCA and grid plans
+type AutomationConfig struct {
+	MaxDCAPlans     int `yaml:"max_dca_plans"`
+	MaxGrids        int `yaml:"max_grids"`
+	CheckIntervalMs int `yaml:"check_interval_ms"` // How often due plan orders are placed, 0 = 5000
+}
+
+// CarryConfig holds thresholds for the long spot / short perpetual funding carry
+type CarryConfig struct {
+	EntryFundingRate float64 `yaml:"entry_funding_rate"`
+	ExitFundingRate  float64 `yaml:"exit_funding_rate"`
+	HistoryPeriods   int     `yaml:"history_periods"`
+	MaxBasisPercent  float64 `yaml:"max_basis_percent"`
+	SpotFeeRate      float64 `yaml:"spot_fee_rate"`
+	FuturesFeeRate   float64 `yaml:"futures_fee_rate"`
+	CheckIntervalMs  int     `yaml:"check_interval_ms"`
+}
+
+// DustConfig holds the conversion of small spot balances ("dust") to BNB
+type DustConfig struct {
+	ThresholdBNB    float64  `yaml:"threshold_bnb"`     // Balances worth at most this much BNB are dust, 0 = every convertible balance
+	Exclude         []string `yaml:"exclude"`           // Assets never converted without being named explicitly
+	AutoConvert     bool     `yaml:"auto_convert"`      // Convert dust on the schedule
+	CheckIntervalMs int      `yaml:"check_interval_ms"` // Schedule interval, 0 disables it
+}
+
+// DryRunConfig holds the simulated spot and futures exchanges that replace real order placement
+type DryRunConfig struct {
+	Enabled             bool    `yaml:"enabled"`              // Simulate spot and futures orders instead of sending them
+	FillProbability     float64 `yaml:"fill_probability"`     // Chance a limit order fills when the price only touches its level, 0 = 1
+	VolumeParticipation float64 `yaml:"volume_participation"` // Share of the observed traded volume resting orders can take, 0 = 0.1
+	BookDepth           int     `yaml:"book_depth"`           // Order book levels market orders are filled against, 0 = 100
+	BookRefreshMs       int     `yaml:"book_refresh_ms"`      // How long a fetched order book is reused, 0 = 1000
+	PollIntervalMs      int     `yaml:"poll_interval_ms"`     // Market price polling interval, 0 = 1000
+	StartingBalance     float64 `yaml:"starting_balance"`     // Virtual USDT orders settle against, 0 = read balances from the exchange account
+}
+
+// RunConfig holds how the process runs: with the interactive CLI, or as a service under a supervisor
+type RunConfig struct {
+	Mode         string `yaml:"mode"`           // interactive (default) or daemon; --daemon overrides it
+	PIDFile      string `yaml:"pid_file"`       // Written at start and removed at shutdown, empty = none
+	LogToJournal bool   `yaml:"log_to_journal"` // Daemon only: log single-line text to stderr instead of the log files
+}
+
+// StorageConfig holds where orders, conditional orders and stop orders are kept
+type StorageConfig struct {
+	Type string `yaml:"type"` // memory (default, lost on restart) or sqlite
+	Path string `yaml:"path"` // SQLite database file, shared by spot and futures
+}
+
+// MetricsConfig holds the Prometheus metrics endpoint
+type MetricsConfig struct {
+	Addr string `yaml:"addr"` // host:port serving /metrics, empty = disabled
+}
+
+// ServerConfig holds the REST API that drives spot trading without a terminal
+type ServerConfig struct {
+	Addr  string `yaml:"addr"`  // host:port serving the API, empty = disabled
+	Token string `yaml:"token"` // Bearer token every request must present; required with addr
+}
+
+// CLIConfig holds how the spot and futures CLIs display numbers and times
+type CLIConfig struct {
+	Locale            string                         `yaml:"locale"`             // Separator style: en (1,234.5), de (1.234,5) or fr (1 234,5)
+	Timezone          string                         `yaml:"timezone"`           // IANA name timestamps are shown in, empty = UTC
+	Grouping          bool                           `yaml:"grouping"`           // Group thousands in prices, quantities and amounts
+	PricePrecision    int                            `yaml:"price_precision"`    // Decimals of prices, 0 = 8
+	QuantityPrecision int                            `yaml:"quantity_precision"` // Decimals of quantities, 0 = 8
+	MoneyPrecision    int                            `yaml:"money_precision"`    // Decimals of notionals, PnL and fees, 0 = 8
+	Symbols           map[string]SymbolDisplayConfig `yaml:"symbols"`            // Per-symbol overrides
+	HeatMap           HeatMapConfig                  `yaml:"heatmap"`
+}
+
+// HeatMapConfig holds the risk buckets of the futures position heat map, by distance from
+// the mark price to the liquidation price
+type HeatMapConfig struct {
+	HighRiskPct   float64 `yaml:"high_risk_pct"`   // Liquidation within this percent is high risk, 0 = 5
+	MediumRiskPct float64 `yaml:"medium_risk_pct"` // Liquidation within this percent is medium risk, 0 = 15
+}
+
+// Location loads the timezone timestamps are displayed in
+func (c *CLIConfig) Location() (*time.Location, error) {
+	return loadTimezone(c.Timezone)
+}
+
+// SymbolDisplayConfig overrides price and quantity decimals for one symbol
+type SymbolDisplayConfig struct {
+	PricePrecision    int `yaml:"price_precision"`
+	QuantityPrecision int `yaml:"quantity_precision"`
+}
+
+// SymbolStatusConfig holds the exchange info refresh that suspends automation for symbols
+// that are halted or delisted
+type SymbolStatusConfig struct {
+	RefreshIntervalMs int  `yaml:"refresh_interval_ms"` // 0 uses the default interval
+	CancelOpenOrders  bool `yaml:"cancel_open_orders"`  // Cancel exchange orders of a symbol when it stops trading
+}
+
+// MaintenanceConfig holds announced exchange maintenance windows during which trading is paused
+type MaintenanceConfig struct {
+	Windows         []MaintenanceWindowConfig `yaml:"windows"`
+	LeadTimeMs      int                       `yaml:"lead_time_ms"`      // Pause this long before each window starts
+	AutoFlatten     bool                      `yaml:"auto_flatten"`      // Close futures positions when the pause begins
+	CheckIntervalMs int                       `yaml:"check_interval_ms"` // 0 disables the schedule
+}
+
+// MaintenanceWindowConfig is one announced maintenance window in UTC
+type MaintenanceWindowConfig struct {
+	Start string `yaml:"start"` // RFC 3339, e.g. 2024-06-01T02:00:00Z
+	End   string `yaml:"end"`
+}
+
+// Bounds parses the window start and end times
+func (w MaintenanceWindowConfig) Bounds() (time.Time, time.Time, error) {
+	start, err := time.Parse(time.RFC3339, w.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start %q", w.Start)
+	}
+	end, err := time.Parse(time.RFC3339, w.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end %q", w.End)
+	}
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end must be after start")
+	}
+	return start.UTC(), end.UTC(), nil
+}
+
+// NotificationsConfig holds where alerts are delivered and when they are held back
+type NotificationsConfig struct {
+	Channels        []NotificationChannelConfig `yaml:"channels"`
+	DailyDigest     DailyDigestConfig           `yaml:"daily_digest"`
+	CheckIntervalMs int                         `yaml:"check_interval_ms"` // How often quiet hours and the daily digest are checked, 0 = 60000
+}
+
+// NotificationChannelConfig is one notification channel
+type NotificationChannelConfig struct {
+	Name       string           `yaml:"name"`
+	Type       string           `yaml:"type"` // log or webhook
+	URL        string           `yaml:"url"`  // HTTPS endpoint of a webhook channel
+	QuietHours QuietHoursConfig `yaml:"quiet_hours"`
+}
+
+// QuietHoursConfig is a daily window during which non-critical notifications are queued
+type QuietHoursConfig struct {
+	Start    string `yaml:"start"`    // HH:MM, empty disables quiet hours
+	End      string `yaml:"end"`      // HH:MM, before start for windows spanning midnight
+	Timezone string `yaml:"timezone"` // IANA name, empty = UTC
+}
+
+// Enabled reports whether quiet hours are configured
+func (q QuietHoursConfig) Enabled() bool {
+	return q.Start != "" || q.End != ""
+}
+
+// Window parses the start and end as offsets from midnight and loads the timezone
+func (q QuietHoursConfig) Window() (time.Duration, time.Duration, *time.Location, error) {
+	start, err := parseClock(q.Start)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("invalid start %q", q.Start)
+	}
+	end, err := parseClock(q.End)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("invalid end %q", q.End)
+	}
+	if start == end {
+		return 0, 0, nil, fmt.Errorf("start and end must differ")
+	}
+	location, err := loadTimezone(q.Timezone)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	return start, end, location, nil
+}
+
+// DailyDigestConfig holds the daily summary of fills, triggers and PnL
+type DailyDigestConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Time     string `yaml:"time"`     // HH:MM at which the digest is sent
+	Timezone string `yaml:"timezone"` // IANA name, empty = UTC
+}
+
+// Schedule parses the digest time as an offset from midnight and loads the timezone
+func (d DailyDigestConfig) Schedule() (time.Duration, *time.Location, error) {
+	at, err := parseClock(d.Time)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid time %q", d.Time)
+	}
+	location, err := loadTimezone(d.Timezone)
+	if err != nil {
+		return 0, nil, err
+	}
+	return at, location, nil
+}
+
+// parseClock parses an HH:MM time of day
+func parseClock(value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+// loadTimezone loads an IANA timezone, defaulting to UTC
+func loadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	return location, nil
 }
 
 // FuturesRiskConfig holds futures-specific risk configuration
@@ -82,13 +422,33 @@ type FuturesMonitoringConfig struct {
 	PositionUpdateIntervalMs      int `yaml:"position_update_interval_ms"`
 	ConditionalOrderIntervalMs    int `yaml:"conditional_order_interval_ms"`
 	FundingRateCheckIntervalMs    int `yaml:"funding_rate_check_interval_ms"`
+	SpotPriceBaseURL              string `yaml:"spot_price_base_url"` // Spot endpoint for BASIS triggers, empty uses the spot base_url
 }
 
 // FuturesStopLossConfig holds futures stop loss configuration
 type FuturesStopLossConfig struct {
-	DefaultCallbackRate float64 `yaml:"default_callback_rate"`
-	MinCallbackRate     float64 `yaml:"min_callback_rate"`
-	MaxCallbackRate     float64 `yaml:"max_callback_rate"`
+	DefaultCallbackRate float64           `yaml:"default_callback_rate"`
+	MinCallbackRate     float64           `yaml:"min_callback_rate"`
+	MaxCallbackRate     float64           `yaml:"max_callback_rate"`
+	TrailingMode        string            `yaml:"trailing_mode"`     // local (default) or native
+	CheckIntervalMs     int               `yaml:"check_interval_ms"` // How often local trailing stops and take profits follow the mark price
+	ProfitGuard         ProfitGuardConfig `yaml:"profit_guard"`
+}
+
+// validateCallbackRates checks that the callback rate bounds are ordered and hold the default;
+// a zero bound is not enforced
+func (c *FuturesStopLossConfig) validateCallbackRates() error {
+	if c.DefaultCallbackRate < 0 || c.MinCallbackRate < 0 || c.MaxCallbackRate < 0 {
+		return fmt.Errorf("stop_loss callback rates cannot be negative")
+	}
+	if c.MaxCallbackRate > 0 && c.MinCallbackRate > c.MaxCallbackRate {
+		return fmt.Errorf("stop_loss.min_callback_rate cannot exceed stop_loss.max_callback_rate")
+	}
+	if c.DefaultCallbackRate > 0 &&
+		(c.DefaultCallbackRate < c.MinCallbackRate || (c.MaxCallbackRate > 0 && c.DefaultCallbackRate > c.MaxCallbackRate)) {
+		return fmt.Errorf("stop_loss.default_callback_rate must be between the minimum and maximum callback rates")
+	}
+	return nil
 }
 
 // FuturesConfig holds futures-specific configuration
@@ -103,6 +463,7 @@ type FuturesConfig struct {
 	Risk              FuturesRiskConfig           `yaml:"risk"`
 	Monitoring        FuturesMonitoringConfig     `yaml:"monitoring"`
 	StopLoss          FuturesStopLossConfig       `yaml:"stop_loss"`
+	DryRun            bool                        `yaml:"dry_run"` // Simulate futures orders with the dry_run settings
 }
 
 // Config represents the application configuration
@@ -112,33 +473,101 @@ type Config struct {
 	Risk              RiskConfig              `yaml:"risk"`
 	Logging           LoggingConfig           `yaml:"logging"`
 	Retry             RetryConfig             `yaml:"retry"`
+	Network           NetworkConfig           `yaml:"network"`
+	MarketData        MarketDataConfig        `yaml:"market_data"`
 	ConditionalOrders ConditionalOrdersConfig `yaml:"conditional_orders"`
 	StopLoss          StopLossConfig          `yaml:"stop_loss"`
+	Automation        AutomationConfig        `yaml:"automation"`
+	Trading           TradingConfig           `yaml:"trading"`
+	Carry             CarryConfig             `yaml:"carry"`
+	Maintenance       MaintenanceConfig       `yaml:"maintenance"`
+	Dust              DustConfig              `yaml:"dust"`
+	DryRun            DryRunConfig            `yaml:"dry_run"`
+	Notifications     NotificationsConfig     `yaml:"notifications"`
+	Protection        ProtectionConfig        `yaml:"protection"`
+	SymbolStatus      SymbolStatusConfig      `yaml:"symbol_status"`
+	CLI               CLIConfig               `yaml:"cli"`
+	Run               RunConfig               `yaml:"run"`
+	Storage           StorageConfig           `yaml:"storage"`
+	Metrics           MetricsConfig           `yaml:"metrics"`
+	Server            ServerConfig            `yaml:"server"`
+	SafeMode          bool                    `yaml:"safe_mode"` // Disable every order, cancel and leverage/margin change
 	
 	// New fields for multi-trading type support
 	Spot    *BinanceConfig `yaml:"spot,omitempty"`
 	Futures *FuturesConfig `yaml:"futures,omitempty"`
 }
 
+// SpotDryRun reports whether spot orders go to the dry run simulator: dry_run.enabled,
+// or dry_run in the spot section (the binance section when there is no spot section)
+func (c *Config) SpotDryRun() bool {
+	if c.DryRun.Enabled {
+		return true
+	}
+	if c.Spot != nil {
+		return c.Spot.DryRun
+	}
+	return c.Binance.DryRun
+}
+
+// FuturesDryRun reports whether futures orders go to the dry run simulator: dry_run.enabled,
+// or dry_run in the futures section
+func (c *Config) FuturesDryRun() bool {
+	return c.DryRun.Enabled || (c.Futures != nil && c.Futures.DryRun)
+}
+
 // ConfigManager defines the interface for configuration management
 type ConfigManager interface {
-	Load(path string) (*Config, error)
-	Validate(config *Config) error
+	Load(path string, tradingTypes ...TradingType) (*Config, error)
+	Validate(config *Config, tradingTypes ...TradingType) error
 	GetConfig() *Config
+
+	// Watch reloads the loaded file whenever it changes until ctx is done. A changed file only
+	// replaces the configuration once it parses and validates for the same trading types.
+	Watch(ctx context.Context) error
+
+	// RegisterRiskLimitsCallback registers fn to receive the risk section whenever a reload changes it
+	RegisterRiskLimitsCallback(fn func(*RiskConfig))
+
+	// RegisterReloadErrorCallback registers fn to receive reload failures; the running
+	// configuration is kept when a reload fails
+	RegisterReloadErrorCallback(fn func(error))
 }
 
 // configManager implements the ConfigManager interface
 type configManager struct {
-	config *Config
+	mu             sync.RWMutex
+	config         *Config
+	path           string
+	tradingTypes   []TradingType
+	riskCallbacks  []func(*RiskConfig)
+	errorCallbacks []func(error)
+	watchDebounce  time.Duration
 }
 
 // NewConfigManager creates a new ConfigManager instance
 func NewConfigManager() ConfigManager {
-	return &configManager{}
+	return &configManager{watchDebounce: DefaultWatchDebounce}
 }
 
-// Load reads and parses the YAML configuration file with environment variable substitution
-func (cm *configManager) Load(path string) (*Config, error) {
+// Load reads and parses the YAML configuration file with environment variable substitution,
+// validating it for the given trading types
+func (cm *configManager) Load(path string, tradingTypes ...TradingType) (*Config, error) {
+	config, err := cm.parse(path, tradingTypes...)
+	if err != nil {
+		return nil, err
+	}
+
+	cm.mu.Lock()
+	cm.config = config
+	cm.path = path
+	cm.tradingTypes = tradingTypes
+	cm.mu.Unlock()
+	return config, nil
+}
+
+// parse reads, parses and validates a configuration file without storing it
+func (cm *configManager) parse(path string, tradingTypes ...TradingType) (*Config, error) {
 	// Read the configuration file
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -154,17 +583,23 @@ func (cm *configManager) Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	// trading.dry_run predates the dry_run section and must never fall through to live orders
+	if config.Trading.DryRun {
+		config.DryRun.Enabled = true
+	}
+
 	// Validate configuration
-	if err := cm.Validate(&config); err != nil {
+	if err := cm.Validate(&config, tradingTypes...); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
 
-	cm.config = &config
 	return &config, nil
 }
 
-// Validate checks if the configuration is valid
-func (cm *configManager) Validate(config *Config) error {
+// Validate checks if the configuration is valid for the given trading types. Sections
+// shared by all markets are always checked; spot and futures sections only when their
+// market is traded. Without trading types, every market with credentials is checked.
+func (cm *configManager) Validate(config *Config, tradingTypes ...TradingType) error {
 	if config == nil {
 		return fmt.Errorf("config cannot be nil")
 	}
@@ -179,27 +614,77 @@ func (cm *configManager) Validate(config *Config) error {
 		return fmt.Errorf("at least one trading configuration (binance, spot, or futures) is required")
 	}
 
-	// Validate legacy Binance configuration if present
-	if hasLegacyConfig {
-		if err := cm.validateBinanceConfig(&config.Binance); err != nil {
-			return fmt.Errorf("binance config: %w", err)
-		}
+	spotMode, futuresMode, err := resolveTradingModes(tradingTypes, hasLegacyConfig || hasSpotConfig, hasFuturesConfig)
+	if err != nil {
+		return err
 	}
-	
-	// Validate Spot configuration if present
-	if hasSpotConfig {
-		if err := cm.validateBinanceConfig(config.Spot); err != nil {
-			return fmt.Errorf("spot config: %w", err)
+
+	if spotMode {
+		if !hasLegacyConfig && !hasSpotConfig {
+			return fmt.Errorf("spot trading requires api_key in the spot or binance section")
+		}
+
+		// Validate legacy Binance configuration if present
+		if hasLegacyConfig {
+			if err := cm.validateBinanceConfig(&config.Binance); err != nil {
+				return fmt.Errorf("binance config: %w", err)
+			}
+		}
+
+		// Validate Spot configuration if present
+		if hasSpotConfig {
+			if err := cm.validateBinanceConfig(config.Spot); err != nil {
+				return fmt.Errorf("spot config: %w", err)
+			}
+		}
+
+		if err := cm.validateSpotSections(config); err != nil {
+			return fmt.Errorf("spot trading: %w", err)
 		}
 	}
-	
-	// Validate Futures configuration if present
-	if hasFuturesConfig {
+
+	if futuresMode {
+		if !hasFuturesConfig {
+			return fmt.Errorf("futures trading requires api_key in the futures section")
+		}
+
 		if err := cm.validateFuturesConfig(config.Futures); err != nil {
 			return fmt.Errorf("futures config: %w", err)
 		}
+
+		if err := cm.validateFuturesSections(config); err != nil {
+			return fmt.Errorf("futures trading: %w", err)
+		}
 	}
 
+	return cm.validateSharedSections(config)
+}
+
+// resolveTradingModes returns whether spot and futures sections must be valid. Without
+// trading types, the markets follow the configured credentials.
+func resolveTradingModes(tradingTypes []TradingType, hasSpot, hasFutures bool) (bool, bool, error) {
+	if len(tradingTypes) == 0 {
+		return hasSpot, hasFutures, nil
+	}
+
+	spotMode, futuresMode := false, false
+	for _, tradingType := range tradingTypes {
+		switch tradingType {
+		case TradingTypeSpot:
+			spotMode = true
+		case TradingTypeFutures:
+			futuresMode = true
+		case TradingTypeBoth:
+			spotMode, futuresMode = true, true
+		default:
+			return false, false, fmt.Errorf("unknown trading type: %s", tradingType)
+		}
+	}
+	return spotMode, futuresMode, nil
+}
+
+// validateSpotSections validates the sections only used when trading spot
+func (cm *configManager) validateSpotSections(config *Config) error {
 	// Validate Risk configuration
 	if config.Risk.MaxOrderAmount <= 0 {
 		return fmt.Errorf("risk.max_order_amount must be greater than 0")
@@ -213,6 +698,193 @@ func (cm *configManager) Validate(config *Config) error {
 	if config.Risk.MaxAPICallsPerMin <= 0 {
 		return fmt.Errorf("risk.max_api_calls_per_min must be greater than 0")
 	}
+	if config.Risk.MaxNotionalPerMin < 0 {
+		return fmt.Errorf("risk.max_notional_per_min cannot be negative")
+	}
+	if config.Risk.NotionalCapMode != "" && config.Risk.NotionalCapMode != "reject" && config.Risk.NotionalCapMode != "delay" {
+		return fmt.Errorf("risk.notional_cap_mode must be one of: reject, delay")
+	}
+	if config.Risk.MaxSlippagePercent < 0 {
+		return fmt.Errorf("risk.max_slippage_percent cannot be negative")
+	}
+	// Validate StopLoss configuration
+	if config.StopLoss.DefaultTrailPercent <= 0 {
+		return fmt.Errorf("stop_loss.default_trail_percent must be greater than 0")
+	}
+	if config.StopLoss.MinTrailPercent <= 0 {
+		return fmt.Errorf("stop_loss.min_trail_percent must be greater than 0")
+	}
+	if config.StopLoss.MaxTrailPercent <= 0 {
+		return fmt.Errorf("stop_loss.max_trail_percent must be greater than 0")
+	}
+	if config.StopLoss.MinTrailPercent > config.StopLoss.MaxTrailPercent {
+		return fmt.Errorf("stop_loss.min_trail_percent cannot be greater than max_trail_percent")
+	}
+	if config.StopLoss.DefaultTrailPercent < config.StopLoss.MinTrailPercent || config.StopLoss.DefaultTrailPercent > config.StopLoss.MaxTrailPercent {
+		return fmt.Errorf("stop_loss.default_trail_percent must be between min_trail_percent and max_trail_percent")
+	}
+	if config.StopLoss.ATRInterval != "" {
+		if _, ok := api.KlineIntervalDuration(config.StopLoss.ATRInterval); !ok {
+			return fmt.Errorf("stop_loss.atr_interval must be a kline interval such as 15m, 1h or 1d")
+		}
+	}
+	if config.StopLoss.UpdateIntervalMs <= 0 {
+		return fmt.Errorf("stop_loss.update_interval_ms must be greater than 0")
+	}
+	// max_deviation_percent of 0 disables the bad-tick filter
+	if config.StopLoss.PriceSanity.MaxDeviationPercent < 0 {
+		return fmt.Errorf("stop_loss.price_sanity.max_deviation_percent cannot be negative")
+	}
+	for symbol, percent := range config.StopLoss.PriceSanity.SymbolDeviationPercent {
+		if percent <= 0 {
+			return fmt.Errorf("stop_loss.price_sanity.symbol_deviation_percent.%s must be greater than 0", symbol)
+		}
+	}
+	if err := config.StopLoss.ProfitGuard.validate("stop_loss.profit_guard"); err != nil {
+		return err
+	}
+
+	// Validate MarketData configuration (zero values fall back to defaults)
+	if config.MarketData.StreamURL != "" && !strings.HasPrefix(config.MarketData.StreamURL, "wss://") {
+		return fmt.Errorf("market_data.stream_url must use wss")
+	}
+	if config.MarketData.UserStreamURL != "" && !strings.HasPrefix(config.MarketData.UserStreamURL, "wss://") {
+		return fmt.Errorf("market_data.user_stream_url must use wss")
+	}
+	if config.MarketData.ReconnectInitialMs < 0 {
+		return fmt.Errorf("market_data.reconnect_initial_ms cannot be negative")
+	}
+	if config.MarketData.ReconnectMaxMs < 0 {
+		return fmt.Errorf("market_data.reconnect_max_ms cannot be negative")
+	}
+	if config.MarketData.ReconnectMaxMs > 0 && config.MarketData.ReconnectMaxMs < config.MarketData.ReconnectInitialMs {
+		return fmt.Errorf("market_data.reconnect_max_ms cannot be less than reconnect_initial_ms")
+	}
+
+	// Validate Automation configuration (zero values fall back to defaults)
+	if config.Automation.MaxDCAPlans < 0 {
+		return fmt.Errorf("automation.max_dca_plans cannot be negative")
+	}
+	if config.Automation.MaxGrids < 0 {
+		return fmt.Errorf("automation.max_grids cannot be negative")
+	}
+	if config.Automation.CheckIntervalMs < 0 {
+		return fmt.Errorf("automation.check_interval_ms cannot be negative")
+	}
+
+	// Validate Dust configuration
+	if config.Dust.ThresholdBNB < 0 {
+		return fmt.Errorf("dust.threshold_bnb cannot be negative")
+	}
+	if config.Dust.CheckIntervalMs < 0 {
+		return fmt.Errorf("dust.check_interval_ms cannot be negative")
+	}
+
+	// Validate DryRun configuration (zero values fall back to defaults)
+	if config.DryRun.FillProbability < 0 || config.DryRun.FillProbability > 1 {
+		return fmt.Errorf("dry_run.fill_probability must be between 0 and 1")
+	}
+	if config.DryRun.VolumeParticipation < 0 || config.DryRun.VolumeParticipation > 1 {
+		return fmt.Errorf("dry_run.volume_participation must be between 0 and 1")
+	}
+	if config.DryRun.BookDepth != 0 {
+		if err := api.ValidateOrderBookLimit(config.DryRun.BookDepth); err != nil {
+			return fmt.Errorf("dry_run.book_depth: %w", err)
+		}
+	}
+	if config.DryRun.BookRefreshMs < 0 {
+		return fmt.Errorf("dry_run.book_refresh_ms cannot be negative")
+	}
+	if config.DryRun.PollIntervalMs < 0 {
+		return fmt.Errorf("dry_run.poll_interval_ms cannot be negative")
+	}
+	if config.DryRun.StartingBalance < 0 {
+		return fmt.Errorf("dry_run.starting_balance cannot be negative")
+	}
+
+	return nil
+}
+
+// validateFuturesSections validates the sections only used when trading futures
+func (cm *configManager) validateFuturesSections(config *Config) error {
+	// Validate Carry configuration (zero values fall back to defaults)
+	if config.Carry.EntryFundingRate < 0 {
+		return fmt.Errorf("carry.entry_funding_rate cannot be negative")
+	}
+	if config.Carry.ExitFundingRate < 0 {
+		return fmt.Errorf("carry.exit_funding_rate cannot be negative")
+	}
+	if config.Carry.EntryFundingRate > 0 && config.Carry.ExitFundingRate > config.Carry.EntryFundingRate {
+		return fmt.Errorf("carry.exit_funding_rate cannot exceed carry.entry_funding_rate")
+	}
+	if config.Carry.HistoryPeriods < 0 {
+		return fmt.Errorf("carry.history_periods cannot be negative")
+	}
+	if config.Carry.MaxBasisPercent < 0 {
+		return fmt.Errorf("carry.max_basis_percent cannot be negative")
+	}
+	if config.Carry.SpotFeeRate < 0 || config.Carry.SpotFeeRate >= 1 {
+		return fmt.Errorf("carry.spot_fee_rate must be between 0 and 1")
+	}
+	if config.Carry.FuturesFeeRate < 0 || config.Carry.FuturesFeeRate >= 1 {
+		return fmt.Errorf("carry.futures_fee_rate must be between 0 and 1")
+	}
+	if config.Carry.CheckIntervalMs < 0 {
+		return fmt.Errorf("carry.check_interval_ms cannot be negative")
+	}
+
+	return nil
+}
+
+// validateSharedSections validates the sections used by every market
+func (cm *configManager) validateSharedSections(config *Config) error {
+	// Protection coverage is checked for spot holdings and futures positions alike
+	if config.Risk.Coverage.CheckIntervalMs < 0 {
+		return fmt.Errorf("risk.coverage.check_interval_ms cannot be negative")
+	}
+	if config.Risk.Coverage.MinCoveragePct < 0 || config.Risk.Coverage.MinCoveragePct > 100 {
+		return fmt.Errorf("risk.coverage.min_coverage_pct must be between 0 and 100")
+	}
+	switch config.Protection.AutoScale {
+	case "", "off", "scale", "supplement":
+	default:
+		return fmt.Errorf("protection.auto_scale must be one of: off, scale, supplement")
+	}
+	if config.Protection.CheckIntervalMs < 0 {
+		return fmt.Errorf("protection.check_interval_ms cannot be negative")
+	}
+	switch config.Run.Mode {
+	case "", RunModeInteractive, RunModeDaemon:
+	default:
+		return fmt.Errorf("run.mode must be one of: interactive, daemon")
+	}
+	switch config.Storage.Type {
+	case "", StorageTypeMemory:
+	case StorageTypeSQLite:
+		if config.Storage.Path == "" {
+			return fmt.Errorf("storage.path is required when storage.type is sqlite")
+		}
+	default:
+		return fmt.Errorf("storage.type must be one of: memory, sqlite")
+	}
+	if config.Metrics.Addr != "" {
+		if _, _, err := net.SplitHostPort(config.Metrics.Addr); err != nil {
+			return fmt.Errorf("metrics.addr must be host:port: %w", err)
+		}
+	}
+	if config.Server.Addr != "" {
+		if _, _, err := net.SplitHostPort(config.Server.Addr); err != nil {
+			return fmt.Errorf("server.addr must be host:port: %w", err)
+		}
+		// The API places real orders, so it is never served without authentication
+		if config.Server.Token == "" || strings.HasPrefix(config.Server.Token, "${") {
+			return fmt.Errorf("server.token is required when server.addr is set")
+		}
+	}
+	// Exchange info is a heavy request, so it is refreshed at most every 10 seconds
+	if config.SymbolStatus.RefreshIntervalMs != 0 && config.SymbolStatus.RefreshIntervalMs < 10000 {
+		return fmt.Errorf("symbol_status.refresh_interval_ms must be 0 (default) or at least 10000")
+	}
 
 	// Validate Logging configuration
 	validLogLevels := map[string]bool{
@@ -245,6 +917,38 @@ func (cm *configManager) Validate(config *Config) error {
 		return fmt.Errorf("retry.backoff_multiplier must be greater than 1.0")
 	}
 
+	// Validate Network configuration (zero timeouts fall back to default_ms)
+	if config.Network.Timeouts.OrderMs < 0 {
+		return fmt.Errorf("network.timeouts.order_ms cannot be negative")
+	}
+	if config.Network.Timeouts.MarketDataMs < 0 {
+		return fmt.Errorf("network.timeouts.market_data_ms cannot be negative")
+	}
+	if config.Network.Timeouts.AccountMs < 0 {
+		return fmt.Errorf("network.timeouts.account_ms cannot be negative")
+	}
+	if config.Network.Timeouts.DefaultMs < 0 {
+		return fmt.Errorf("network.timeouts.default_ms cannot be negative")
+	}
+	if config.Network.DataSource.Prefer != "" && config.Network.DataSource.Prefer != "websocket" && config.Network.DataSource.Prefer != "rest" {
+		return fmt.Errorf("network.data_source.prefer must be one of: websocket, rest")
+	}
+	if config.Network.DataSource.MaxFailures < 0 {
+		return fmt.Errorf("network.data_source.max_failures cannot be negative")
+	}
+	if config.Network.DataSource.FailureWindowMs < 0 {
+		return fmt.Errorf("network.data_source.failure_window_ms cannot be negative")
+	}
+	if config.Network.DataSource.ProbeIntervalMs < 0 {
+		return fmt.Errorf("network.data_source.probe_interval_ms cannot be negative")
+	}
+	if config.Network.DataSource.RecoverySuccesses < 0 {
+		return fmt.Errorf("network.data_source.recovery_successes cannot be negative")
+	}
+	if config.Network.BookTicker.StaleAfterMs < 0 {
+		return fmt.Errorf("network.book_ticker.stale_after_ms cannot be negative")
+	}
+
 	// Validate ConditionalOrders configuration
 	if config.ConditionalOrders.MonitoringIntervalMs <= 0 {
 		return fmt.Errorf("conditional_orders.monitoring_interval_ms must be greater than 0")
@@ -256,31 +960,134 @@ func (cm *configManager) Validate(config *Config) error {
 		return fmt.Errorf("conditional_orders.trigger_execution_timeout_ms must be greater than 0")
 	}
 
-	// Validate StopLoss configuration
-	if config.StopLoss.DefaultTrailPercent <= 0 {
-		return fmt.Errorf("stop_loss.default_trail_percent must be greater than 0")
+	// Validate Trading configuration (empty rounding mode defaults to truncate)
+	validRoundingModes := map[string]bool{
+		"":             true,
+		"truncate":     true,
+		"nearest":      true,
+		"conservative": true,
 	}
-	if config.StopLoss.MinTrailPercent <= 0 {
-		return fmt.Errorf("stop_loss.min_trail_percent must be greater than 0")
+	if !validRoundingModes[config.Trading.RoundingMode] {
+		return fmt.Errorf("trading.rounding_mode must be one of: truncate, nearest, conservative")
 	}
-	if config.StopLoss.MaxTrailPercent <= 0 {
-		return fmt.Errorf("stop_loss.max_trail_percent must be greater than 0")
+	if config.Trading.ExchangeInfoTTLMs < 0 {
+		return fmt.Errorf("trading.exchange_info_ttl_ms cannot be negative")
 	}
-	if config.StopLoss.MinTrailPercent > config.StopLoss.MaxTrailPercent {
-		return fmt.Errorf("stop_loss.min_trail_percent cannot be greater than max_trail_percent")
+	// max_failures of 0 disables the auto-pause; zero window/cooldown fall back to defaults
+	if config.Trading.FailurePause.MaxFailures < 0 {
+		return fmt.Errorf("trading.failure_pause.max_failures cannot be negative")
 	}
-	if config.StopLoss.DefaultTrailPercent < config.StopLoss.MinTrailPercent || config.StopLoss.DefaultTrailPercent > config.StopLoss.MaxTrailPercent {
-		return fmt.Errorf("stop_loss.default_trail_percent must be between min_trail_percent and max_trail_percent")
+	if config.Trading.FailurePause.WindowMs < 0 {
+		return fmt.Errorf("trading.failure_pause.window_ms cannot be negative")
 	}
-	if config.StopLoss.UpdateIntervalMs <= 0 {
-		return fmt.Errorf("stop_loss.update_interval_ms must be greater than 0")
+	if config.Trading.FailurePause.CooldownMs < 0 {
+		return fmt.Errorf("trading.failure_pause.cooldown_ms cannot be negative")
+	}
+	if config.Trading.ReplayProtection.WindowMs < 0 {
+		return fmt.Errorf("trading.replay_protection.window_ms cannot be negative")
+	}
+
+	// Validate Maintenance configuration
+	for i, window := range config.Maintenance.Windows {
+		if _, _, err := window.Bounds(); err != nil {
+			return fmt.Errorf("maintenance.windows[%d]: %w", i, err)
+		}
+	}
+	if config.Maintenance.LeadTimeMs < 0 {
+		return fmt.Errorf("maintenance.lead_time_ms cannot be negative")
+	}
+	if config.Maintenance.CheckIntervalMs < 0 {
+		return fmt.Errorf("maintenance.check_interval_ms cannot be negative")
+	}
+
+	// Validate Notifications configuration
+	channelNames := make(map[string]bool)
+	for i, channel := range config.Notifications.Channels {
+		if channel.Name == "" {
+			return fmt.Errorf("notifications.channels[%d].name is required", i)
+		}
+		if channelNames[channel.Name] {
+			return fmt.Errorf("notifications.channels[%d]: duplicate channel name %q", i, channel.Name)
+		}
+		channelNames[channel.Name] = true
+
+		switch channel.Type {
+		case "log":
+		case "webhook":
+			if !strings.HasPrefix(channel.URL, "https://") {
+				return fmt.Errorf("notifications.channels[%d].url must use HTTPS protocol", i)
+			}
+		default:
+			return fmt.Errorf("notifications.channels[%d].type must be one of: log, webhook", i)
+		}
+
+		if channel.QuietHours.Enabled() {
+			if _, _, _, err := channel.QuietHours.Window(); err != nil {
+				return fmt.Errorf("notifications.channels[%d].quiet_hours: %w", i, err)
+			}
+		}
+	}
+	if config.Notifications.DailyDigest.Enabled {
+		if _, _, err := config.Notifications.DailyDigest.Schedule(); err != nil {
+			return fmt.Errorf("notifications.daily_digest: %w", err)
+		}
+	}
+	if config.Notifications.CheckIntervalMs < 0 {
+		return fmt.Errorf("notifications.check_interval_ms cannot be negative")
+	}
+
+	// Validate CLI display configuration
+	switch config.CLI.Locale {
+	case "", "en", "de", "fr":
+	default:
+		return fmt.Errorf("cli.locale must be one of: en, de, fr")
+	}
+	if _, err := config.CLI.Location(); err != nil {
+		return fmt.Errorf("cli.timezone: %w", err)
+	}
+	if err := validateDisplayPrecision("cli.price_precision", config.CLI.PricePrecision); err != nil {
+		return err
+	}
+	if err := validateDisplayPrecision("cli.quantity_precision", config.CLI.QuantityPrecision); err != nil {
+		return err
+	}
+	if err := validateDisplayPrecision("cli.money_precision", config.CLI.MoneyPrecision); err != nil {
+		return err
+	}
+	for symbol, display := range config.CLI.Symbols {
+		if err := validateDisplayPrecision(fmt.Sprintf("cli.symbols.%s.price_precision", symbol), display.PricePrecision); err != nil {
+			return err
+		}
+		if err := validateDisplayPrecision(fmt.Sprintf("cli.symbols.%s.quantity_precision", symbol), display.QuantityPrecision); err != nil {
+			return err
+		}
+	}
+	if config.CLI.HeatMap.HighRiskPct < 0 {
+		return fmt.Errorf("cli.heatmap.high_risk_pct cannot be negative")
+	}
+	if config.CLI.HeatMap.MediumRiskPct < 0 {
+		return fmt.Errorf("cli.heatmap.medium_risk_pct cannot be negative")
+	}
+	if config.CLI.HeatMap.HighRiskPct > 0 && config.CLI.HeatMap.MediumRiskPct > 0 &&
+		config.CLI.HeatMap.MediumRiskPct <= config.CLI.HeatMap.HighRiskPct {
+		return fmt.Errorf("cli.heatmap.medium_risk_pct must be greater than cli.heatmap.high_risk_pct")
 	}
 
 	return nil
 }
 
+// validateDisplayPrecision checks a number of displayed decimals
+func validateDisplayPrecision(field string, precision int) error {
+	if precision < 0 || precision > 16 {
+		return fmt.Errorf("%s must be between 0 and 16", field)
+	}
+	return nil
+}
+
 // GetConfig returns the current configuration
 func (cm *configManager) GetConfig() *Config {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
 	return cm.config
 }
 
@@ -342,6 +1149,23 @@ func (cm *configManager) validateFuturesConfig(config *FuturesConfig) error {
 	if config.Risk.LiquidationBuffer < 0 || config.Risk.LiquidationBuffer > 1 {
 		return fmt.Errorf("risk.liquidation_buffer must be between 0 and 1")
 	}
+	if err := config.StopLoss.ProfitGuard.validate("stop_loss.profit_guard"); err != nil {
+		return err
+	}
+	if err := config.StopLoss.validateCallbackRates(); err != nil {
+		return err
+	}
+	switch config.StopLoss.TrailingMode {
+	case "", "local", "native":
+	default:
+		return fmt.Errorf("stop_loss.trailing_mode must be one of: local, native")
+	}
+	if config.StopLoss.CheckIntervalMs < 0 {
+		return fmt.Errorf("stop_loss.check_interval_ms cannot be negative")
+	}
+	if config.Monitoring.SpotPriceBaseURL != "" && !strings.HasPrefix(config.Monitoring.SpotPriceBaseURL, "https://") {
+		return fmt.Errorf("monitoring.spot_price_base_url must use HTTPS protocol")
+	}
 	
 	return nil
 }
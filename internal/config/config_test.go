@@ -338,7 +338,7 @@ func TestValidateConfig(t *testing.T) {
 				},
 			},
 			expectError: true,
-			errorMsg:    "risk.max_order_amount must be greater than 0",
+			errorMsg:    "spot trading: risk.max_order_amount must be greater than 0",
 		},
 		{
 			name: "invalid log level",
@@ -1023,7 +1023,7 @@ func TestValidateStopLossConfig(t *testing.T) {
 				},
 			},
 			expectError: true,
-			errorMsg:    "stop_loss.default_trail_percent must be greater than 0",
+			errorMsg:    "spot trading: stop_loss.default_trail_percent must be greater than 0",
 		},
 		{
 			name: "min greater than max trail percent",
@@ -1064,7 +1064,7 @@ func TestValidateStopLossConfig(t *testing.T) {
 				},
 			},
 			expectError: true,
-			errorMsg:    "stop_loss.min_trail_percent cannot be greater than max_trail_percent",
+			errorMsg:    "spot trading: stop_loss.min_trail_percent cannot be greater than max_trail_percent",
 		},
 		{
 			name: "default trail percent out of range",
@@ -1105,7 +1105,7 @@ func TestValidateStopLossConfig(t *testing.T) {
 				},
 			},
 			expectError: true,
-			errorMsg:    "stop_loss.default_trail_percent must be between min_trail_percent and max_trail_percent",
+			errorMsg:    "spot trading: stop_loss.default_trail_percent must be between min_trail_percent and max_trail_percent",
 		},
 		{
 			name: "invalid update interval",
@@ -1146,7 +1146,49 @@ func TestValidateStopLossConfig(t *testing.T) {
 				},
 			},
 			expectError: true,
-			errorMsg:    "stop_loss.update_interval_ms must be greater than 0",
+			errorMsg:    "spot trading: stop_loss.update_interval_ms must be greater than 0",
+		},
+		{
+			name: "invalid atr interval",
+			config: &Config{
+				Binance: BinanceConfig{
+					APIKey:    "test_key",
+					APISecret: "test_secret",
+					BaseURL:   "https://api.binance.com",
+				},
+				Risk: RiskConfig{
+					MaxOrderAmount:    10000.0,
+					MaxDailyOrders:    100,
+					MinBalanceReserve: 100.0,
+					MaxAPICallsPerMin: 1000,
+				},
+				Logging: LoggingConfig{
+					Level:      "info",
+					File:       "logs/trading.log",
+					MaxSizeMB:  100,
+					MaxBackups: 5,
+				},
+				Retry: RetryConfig{
+					MaxAttempts:       3,
+					InitialDelayMs:    1000,
+					BackoffMultiplier: 2.0,
+				},
+				ConditionalOrders: ConditionalOrdersConfig{
+					MonitoringIntervalMs:      1000,
+					MaxActiveOrders:           500,
+					TriggerExecutionTimeoutMs: 3000,
+					EnableSmartPolling:        true,
+				},
+				StopLoss: StopLossConfig{
+					DefaultTrailPercent: 2.0,
+					MinTrailPercent:     0.1,
+					MaxTrailPercent:     10.0,
+					ATRInterval:         "90m",
+					UpdateIntervalMs:    500,
+				},
+			},
+			expectError: true,
+			errorMsg:    "spot trading: stop_loss.atr_interval must be a kline interval such as 15m, 1h or 1d",
 		},
 	}
 
@@ -1750,3 +1792,779 @@ stop_loss:
 		})
 	}
 }
+
+// newValidTestConfig returns a minimal valid configuration for validation tests
+func newValidTestConfig() *Config {
+	return &Config{
+		Binance: BinanceConfig{
+			APIKey:    "test_key",
+			APISecret: "test_secret",
+			BaseURL:   "https://api.binance.com",
+		},
+		Risk: RiskConfig{
+			MaxOrderAmount:    10000.0,
+			MaxDailyOrders:    100,
+			MinBalanceReserve: 100.0,
+			MaxAPICallsPerMin: 1000,
+		},
+		Logging: LoggingConfig{
+			Level:      "info",
+			File:       "logs/trading.log",
+			MaxSizeMB:  100,
+			MaxBackups: 5,
+		},
+		Retry: RetryConfig{
+			MaxAttempts:       3,
+			InitialDelayMs:    1000,
+			BackoffMultiplier: 2.0,
+		},
+		ConditionalOrders: ConditionalOrdersConfig{
+			MonitoringIntervalMs:      1000,
+			MaxActiveOrders:           500,
+			TriggerExecutionTimeoutMs: 3000,
+		},
+		StopLoss: StopLossConfig{
+			DefaultTrailPercent: 2.0,
+			MinTrailPercent:     0.1,
+			MaxTrailPercent:     10.0,
+			UpdateIntervalMs:    500,
+		},
+	}
+}
+
+func newValidTestFuturesConfig() *FuturesConfig {
+	return &FuturesConfig{
+		APIKey:          "test_futures_key",
+		APISecret:       "test_futures_secret",
+		BaseURL:         "https://fapi.binance.com",
+		DefaultLeverage: 10,
+		Risk: FuturesRiskConfig{
+			MaxOrderValue:    50000.0,
+			MaxPositionValue: 100000.0,
+			MaxLeverage:      20,
+			MinMarginRatio:   0.05,
+		},
+	}
+}
+
+func TestValidateFuturesStopLossConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		stopLoss FuturesStopLossConfig
+		errorMsg string
+	}{
+		{name: "unset is valid"},
+		{
+			name:     "native trailing with bounds",
+			stopLoss: FuturesStopLossConfig{DefaultCallbackRate: 1, MinCallbackRate: 0.1, MaxCallbackRate: 5, TrailingMode: "native", CheckIntervalMs: 500},
+		},
+		{
+			name:     "minimum above maximum",
+			stopLoss: FuturesStopLossConfig{MinCallbackRate: 3, MaxCallbackRate: 2},
+			errorMsg: "futures config: stop_loss.min_callback_rate cannot exceed stop_loss.max_callback_rate",
+		},
+		{
+			name:     "default outside the bounds",
+			stopLoss: FuturesStopLossConfig{DefaultCallbackRate: 6, MinCallbackRate: 0.1, MaxCallbackRate: 5},
+			errorMsg: "futures config: stop_loss.default_callback_rate must be between the minimum and maximum callback rates",
+		},
+		{
+			name:     "negative rate",
+			stopLoss: FuturesStopLossConfig{MinCallbackRate: -1},
+			errorMsg: "futures config: stop_loss callback rates cannot be negative",
+		},
+		{
+			name:     "unknown trailing mode",
+			stopLoss: FuturesStopLossConfig{TrailingMode: "exchange"},
+			errorMsg: "futures config: stop_loss.trailing_mode must be one of: local, native",
+		},
+		{
+			name:     "negative check interval",
+			stopLoss: FuturesStopLossConfig{CheckIntervalMs: -1},
+			errorMsg: "futures config: stop_loss.check_interval_ms cannot be negative",
+		},
+	}
+
+	cm := NewConfigManager()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newValidTestConfig()
+			cfg.Futures = newValidTestFuturesConfig()
+			cfg.Futures.StopLoss = tt.stopLoss
+
+			err := cm.Validate(cfg, TradingTypeFutures)
+			if tt.errorMsg == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.errorMsg {
+				t.Errorf("expected error %q, got %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestValidateTradingAndAutomationConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(c *Config)
+		errorMsg string
+	}{
+		{
+			name:   "defaults are valid",
+			modify: func(c *Config) {},
+		},
+		{
+			name:   "conservative rounding mode",
+			modify: func(c *Config) { c.Trading.RoundingMode = "conservative" },
+		},
+		{
+			name:     "invalid rounding mode",
+			modify:   func(c *Config) { c.Trading.RoundingMode = "ceil" },
+			errorMsg: "trading.rounding_mode must be one of: truncate, nearest, conservative",
+		},
+		{
+			name:     "negative failure pause threshold",
+			modify:   func(c *Config) { c.Trading.FailurePause.MaxFailures = -1 },
+			errorMsg: "trading.failure_pause.max_failures cannot be negative",
+		},
+		{
+			name:     "negative failure pause cooldown",
+			modify:   func(c *Config) { c.Trading.FailurePause.CooldownMs = -1 },
+			errorMsg: "trading.failure_pause.cooldown_ms cannot be negative",
+		},
+		{
+			name:     "negative replay protection window",
+			modify:   func(c *Config) { c.Trading.ReplayProtection.WindowMs = -1 },
+			errorMsg: "trading.replay_protection.window_ms cannot be negative",
+		},
+		{
+			name:   "notional throughput cap in delay mode",
+			modify: func(c *Config) { c.Risk.MaxNotionalPerMin = 50000; c.Risk.NotionalCapMode = "delay" },
+		},
+		{
+			name:     "negative notional throughput cap",
+			modify:   func(c *Config) { c.Risk.MaxNotionalPerMin = -1 },
+			errorMsg: "spot trading: risk.max_notional_per_min cannot be negative",
+		},
+		{
+			name:     "invalid notional cap mode",
+			modify:   func(c *Config) { c.Risk.NotionalCapMode = "queue" },
+			errorMsg: "spot trading: risk.notional_cap_mode must be one of: reject, delay",
+		},
+		{
+			name:   "market order slippage limit",
+			modify: func(c *Config) { c.Risk.MaxSlippagePercent = 0.5 },
+		},
+		{
+			name:     "negative market order slippage limit",
+			modify:   func(c *Config) { c.Risk.MaxSlippagePercent = -1 },
+			errorMsg: "spot trading: risk.max_slippage_percent cannot be negative",
+		},
+		{
+			name:     "negative coverage check interval",
+			modify:   func(c *Config) { c.Risk.Coverage.CheckIntervalMs = -1 },
+			errorMsg: "risk.coverage.check_interval_ms cannot be negative",
+		},
+		{
+			name:     "coverage percentage above 100",
+			modify:   func(c *Config) { c.Risk.Coverage.MinCoveragePct = 120 },
+			errorMsg: "risk.coverage.min_coverage_pct must be between 0 and 100",
+		},
+		{
+			name:   "symbol status refresh with open order cancellation",
+			modify: func(c *Config) { c.SymbolStatus = SymbolStatusConfig{RefreshIntervalMs: 30000, CancelOpenOrders: true} },
+		},
+		{
+			name:     "symbol status refresh too frequent",
+			modify:   func(c *Config) { c.SymbolStatus.RefreshIntervalMs = 1000 },
+			errorMsg: "symbol_status.refresh_interval_ms must be 0 (default) or at least 10000",
+		},
+		{
+			name:   "daemon run mode",
+			modify: func(c *Config) { c.Run = RunConfig{Mode: RunModeDaemon, PIDFile: "/run/binance-trader.pid", LogToJournal: true} },
+		},
+		{
+			name:     "invalid run mode",
+			modify:   func(c *Config) { c.Run.Mode = "background" },
+			errorMsg: "run.mode must be one of: interactive, daemon",
+		},
+		{
+			name:   "sqlite storage",
+			modify: func(c *Config) { c.Storage = StorageConfig{Type: StorageTypeSQLite, Path: "data/orders.db"} },
+		},
+		{
+			name:     "sqlite storage without path",
+			modify:   func(c *Config) { c.Storage = StorageConfig{Type: StorageTypeSQLite} },
+			errorMsg: "storage.path is required when storage.type is sqlite",
+		},
+		{
+			name:     "unknown storage type",
+			modify:   func(c *Config) { c.Storage.Type = "redis" },
+			errorMsg: "storage.type must be one of: memory, sqlite",
+		},
+		{
+			name:   "metrics endpoint",
+			modify: func(c *Config) { c.Metrics.Addr = "127.0.0.1:9090" },
+		},
+		{
+			name:     "metrics address without port",
+			modify:   func(c *Config) { c.Metrics.Addr = "localhost" },
+			errorMsg: "metrics.addr must be host:port: address localhost: missing port in address",
+		},
+		{
+			name:   "api server",
+			modify: func(c *Config) { c.Server = ServerConfig{Addr: "127.0.0.1:8080", Token: "secret"} },
+		},
+		{
+			name:     "api server address without port",
+			modify:   func(c *Config) { c.Server = ServerConfig{Addr: "localhost", Token: "secret"} },
+			errorMsg: "server.addr must be host:port: address localhost: missing port in address",
+		},
+		{
+			name:     "api server without token",
+			modify:   func(c *Config) { c.Server.Addr = "127.0.0.1:8080" },
+			errorMsg: "server.token is required when server.addr is set",
+		},
+		{
+			name:     "api server token from an unset variable",
+			modify:   func(c *Config) { c.Server = ServerConfig{Addr: "127.0.0.1:8080", Token: "${BINANCE_SERVER_TOKEN}"} },
+			errorMsg: "server.token is required when server.addr is set",
+		},
+		{
+			name: "websocket market data",
+			modify: func(c *Config) {
+				c.MarketData = MarketDataConfig{WebSocketEnabled: true, StreamURL: "wss://stream.binance.com:9443/ws", ReconnectInitialMs: 500, ReconnectMaxMs: 30000}
+			},
+		},
+		{
+			name:     "unencrypted market data stream",
+			modify:   func(c *Config) { c.MarketData.StreamURL = "ws://stream.binance.com/ws" },
+			errorMsg: "spot trading: market_data.stream_url must use wss",
+		},
+		{
+			name: "user data stream",
+			modify: func(c *Config) {
+				c.MarketData = MarketDataConfig{UserStreamEnabled: true, UserStreamURL: "wss://stream.binance.com:9443/ws"}
+			},
+		},
+		{
+			name:     "unencrypted user data stream",
+			modify:   func(c *Config) { c.MarketData.UserStreamURL = "ws://stream.binance.com:9443/ws" },
+			errorMsg: "spot trading: market_data.user_stream_url must use wss",
+		},
+		{
+			name:     "reconnect cap below the initial delay",
+			modify:   func(c *Config) { c.MarketData = MarketDataConfig{ReconnectInitialMs: 5000, ReconnectMaxMs: 1000} },
+			errorMsg: "spot trading: market_data.reconnect_max_ms cannot be less than reconnect_initial_ms",
+		},
+		{
+			name:   "rest data source preference",
+			modify: func(c *Config) { c.Network.DataSource.Prefer = "rest" },
+		},
+		{
+			name:     "invalid data source preference",
+			modify:   func(c *Config) { c.Network.DataSource.Prefer = "grpc" },
+			errorMsg: "network.data_source.prefer must be one of: websocket, rest",
+		},
+		{
+			name:     "negative data source failure threshold",
+			modify:   func(c *Config) { c.Network.DataSource.MaxFailures = -1 },
+			errorMsg: "network.data_source.max_failures cannot be negative",
+		},
+		{
+			name:     "negative book ticker staleness",
+			modify:   func(c *Config) { c.Network.BookTicker.StaleAfterMs = -1 },
+			errorMsg: "network.book_ticker.stale_after_ms cannot be negative",
+		},
+		{
+			name:   "network timeouts",
+			modify: func(c *Config) { c.Network.Timeouts = TimeoutsConfig{OrderMs: 2000, MarketDataMs: 10000} },
+		},
+		{
+			name:     "negative order timeout",
+			modify:   func(c *Config) { c.Network.Timeouts.OrderMs = -1 },
+			errorMsg: "network.timeouts.order_ms cannot be negative",
+		},
+		{
+			name:     "negative max dca plans",
+			modify:   func(c *Config) { c.Automation.MaxDCAPlans = -1 },
+			errorMsg: "spot trading: automation.max_dca_plans cannot be negative",
+		},
+		{
+			name:     "negative max grids",
+			modify:   func(c *Config) { c.Automation.MaxGrids = -1 },
+			errorMsg: "spot trading: automation.max_grids cannot be negative",
+		},
+		{
+			name:     "negative automation check interval",
+			modify:   func(c *Config) { c.Automation.CheckIntervalMs = -1 },
+			errorMsg: "spot trading: automation.check_interval_ms cannot be negative",
+		},
+		{
+			name:     "dry run fill probability above one",
+			modify:   func(c *Config) { c.DryRun.FillProbability = 1.5 },
+			errorMsg: "spot trading: dry_run.fill_probability must be between 0 and 1",
+		},
+		{
+			name:     "negative dry run volume participation",
+			modify:   func(c *Config) { c.DryRun.VolumeParticipation = -0.1 },
+			errorMsg: "spot trading: dry_run.volume_participation must be between 0 and 1",
+		},
+		{
+			name:     "dry run book depth above exchange limit",
+			modify:   func(c *Config) { c.DryRun.BookDepth = 10000 },
+			errorMsg: "spot trading: dry_run.book_depth: invalid order book limit 10000: must be one of 5, 10, 20, 50, 100, 500, 1000, 5000",
+		},
+		{
+			name:     "dry run book depth not an exchange limit",
+			modify:   func(c *Config) { c.DryRun.BookDepth = 150 },
+			errorMsg: "spot trading: dry_run.book_depth: invalid order book limit 150: must be one of 5, 10, 20, 50, 100, 500, 1000, 5000",
+		},
+		{
+			name:     "negative dry run poll interval",
+			modify:   func(c *Config) { c.DryRun.PollIntervalMs = -1 },
+			errorMsg: "spot trading: dry_run.poll_interval_ms cannot be negative",
+		},
+		{
+			name: "carry thresholds",
+			modify: func(c *Config) {
+				c.Futures = newValidTestFuturesConfig()
+				c.Carry = CarryConfig{EntryFundingRate: 0.0005, ExitFundingRate: 0.0001, HistoryPeriods: 6, MaxBasisPercent: 0.5}
+			},
+		},
+		{
+			name: "carry exit rate above entry rate",
+			modify: func(c *Config) {
+				c.Futures = newValidTestFuturesConfig()
+				c.Carry = CarryConfig{EntryFundingRate: 0.0001, ExitFundingRate: 0.0003}
+			},
+			errorMsg: "futures trading: carry.exit_funding_rate cannot exceed carry.entry_funding_rate",
+		},
+		{
+			name: "carry fee rate out of range",
+			modify: func(c *Config) {
+				c.Futures = newValidTestFuturesConfig()
+				c.Carry.SpotFeeRate = 1
+			},
+			errorMsg: "futures trading: carry.spot_fee_rate must be between 0 and 1",
+		},
+		{
+			name:   "carry is not checked without futures",
+			modify: func(c *Config) { c.Carry.SpotFeeRate = 1 },
+		},
+		{
+			name: "maintenance windows",
+			modify: func(c *Config) {
+				c.Maintenance = MaintenanceConfig{
+					Windows:         []MaintenanceWindowConfig{{Start: "2024-06-01T02:00:00Z", End: "2024-06-01T04:00:00Z"}},
+					LeadTimeMs:      300000,
+					AutoFlatten:     true,
+					CheckIntervalMs: 10000,
+				}
+			},
+		},
+		{
+			name: "maintenance window end before start",
+			modify: func(c *Config) {
+				c.Maintenance.Windows = []MaintenanceWindowConfig{{Start: "2024-06-01T04:00:00Z", End: "2024-06-01T02:00:00Z"}}
+			},
+			errorMsg: "maintenance.windows[0]: end must be after start",
+		},
+		{
+			name: "maintenance window invalid time",
+			modify: func(c *Config) {
+				c.Maintenance.Windows = []MaintenanceWindowConfig{{Start: "2024-06-01 02:00", End: "2024-06-01T04:00:00Z"}}
+			},
+			errorMsg: `maintenance.windows[0]: invalid start "2024-06-01 02:00"`,
+		},
+		{
+			name:     "negative maintenance lead time",
+			modify:   func(c *Config) { c.Maintenance.LeadTimeMs = -1 },
+			errorMsg: "maintenance.lead_time_ms cannot be negative",
+		},
+		{
+			name: "price sanity thresholds",
+			modify: func(c *Config) {
+				c.StopLoss.PriceSanity = PriceSanityConfig{MaxDeviationPercent: 10, SymbolDeviationPercent: map[string]float64{"BTCUSDT": 5}}
+			},
+		},
+		{
+			name:     "negative price sanity threshold",
+			modify:   func(c *Config) { c.StopLoss.PriceSanity.MaxDeviationPercent = -1 },
+			errorMsg: "spot trading: stop_loss.price_sanity.max_deviation_percent cannot be negative",
+		},
+		{
+			name:     "zero symbol price sanity threshold",
+			modify:   func(c *Config) { c.StopLoss.PriceSanity.SymbolDeviationPercent = map[string]float64{"DOGEUSDT": 0} },
+			errorMsg: "spot trading: stop_loss.price_sanity.symbol_deviation_percent.DOGEUSDT must be greater than 0",
+		},
+		{
+			name:   "protection auto scale",
+			modify: func(c *Config) { c.Protection = ProtectionConfig{AutoScale: "supplement", CheckIntervalMs: 10000} },
+		},
+		{
+			name:     "unknown protection auto scale mode",
+			modify:   func(c *Config) { c.Protection.AutoScale = "double" },
+			errorMsg: "protection.auto_scale must be one of: off, scale, supplement",
+		},
+		{
+			name:   "profit guard",
+			modify: func(c *Config) { c.StopLoss.ProfitGuard = ProfitGuardConfig{FeeRate: 0.00075, MinProfitPercent: 0.5} },
+		},
+		{
+			name:     "profit guard fee rate of 100%",
+			modify:   func(c *Config) { c.StopLoss.ProfitGuard.FeeRate = 1 },
+			errorMsg: "spot trading: stop_loss.profit_guard.fee_rate must be between 0 and 1",
+		},
+		{
+			name:     "negative minimum profit",
+			modify:   func(c *Config) { c.StopLoss.ProfitGuard.MinProfitPercent = -0.1 },
+			errorMsg: "spot trading: stop_loss.profit_guard.min_profit_percent cannot be negative",
+		},
+		{
+			name: "grouped german display",
+			modify: func(c *Config) {
+				c.CLI = CLIConfig{Locale: "de", Grouping: true, PricePrecision: 2, Symbols: map[string]SymbolDisplayConfig{"DOGEUSDT": {PricePrecision: 5}}}
+			},
+		},
+		{
+			name:     "invalid display locale",
+			modify:   func(c *Config) { c.CLI.Locale = "jp" },
+			errorMsg: "cli.locale must be one of: en, de, fr",
+		},
+		{
+			name:   "display timezone",
+			modify: func(c *Config) { c.CLI.Timezone = "Asia/Shanghai" },
+		},
+		{
+			name:     "unknown display timezone",
+			modify:   func(c *Config) { c.CLI.Timezone = "Mars/Olympus" },
+			errorMsg: `cli.timezone: unknown timezone "Mars/Olympus"`,
+		},
+		{
+			name:     "display precision too large",
+			modify:   func(c *Config) { c.CLI.QuantityPrecision = 20 },
+			errorMsg: "cli.quantity_precision must be between 0 and 16",
+		},
+		{
+			name:     "negative symbol display precision",
+			modify:   func(c *Config) { c.CLI.Symbols = map[string]SymbolDisplayConfig{"BTCUSDT": {PricePrecision: -1}} },
+			errorMsg: "cli.symbols.BTCUSDT.price_precision must be between 0 and 16",
+		},
+		{
+			name:     "negative heat map threshold",
+			modify:   func(c *Config) { c.CLI.HeatMap.HighRiskPct = -1 },
+			errorMsg: "cli.heatmap.high_risk_pct cannot be negative",
+		},
+		{
+			name:     "heat map medium threshold below high",
+			modify:   func(c *Config) { c.CLI.HeatMap = HeatMapConfig{HighRiskPct: 10, MediumRiskPct: 5} },
+			errorMsg: "cli.heatmap.medium_risk_pct must be greater than cli.heatmap.high_risk_pct",
+		},
+		{
+			name: "notification channels with quiet hours and daily digest",
+			modify: func(c *Config) {
+				c.Notifications = NotificationsConfig{
+					Channels: []NotificationChannelConfig{
+						{Name: "log", Type: "log"},
+						{Name: "phone", Type: "webhook", URL: "https://hooks.example.com/trading", QuietHours: QuietHoursConfig{Start: "22:00", End: "07:00", Timezone: "UTC"}},
+					},
+					DailyDigest:     DailyDigestConfig{Enabled: true, Time: "21:00"},
+					CheckIntervalMs: 60000,
+				}
+			},
+		},
+		{
+			name: "duplicate notification channel",
+			modify: func(c *Config) {
+				c.Notifications.Channels = []NotificationChannelConfig{{Name: "log", Type: "log"}, {Name: "log", Type: "log"}}
+			},
+			errorMsg: `notifications.channels[1]: duplicate channel name "log"`,
+		},
+		{
+			name:     "webhook without HTTPS",
+			modify:   func(c *Config) { c.Notifications.Channels = []NotificationChannelConfig{{Name: "hook", Type: "webhook", URL: "http://hooks.example.com"}} },
+			errorMsg: "notifications.channels[0].url must use HTTPS protocol",
+		},
+		{
+			name: "quiet hours without end",
+			modify: func(c *Config) {
+				c.Notifications.Channels = []NotificationChannelConfig{{Name: "log", Type: "log", QuietHours: QuietHoursConfig{Start: "22:00"}}}
+			},
+			errorMsg: `notifications.channels[0].quiet_hours: invalid end ""`,
+		},
+		{
+			name: "quiet hours unknown timezone",
+			modify: func(c *Config) {
+				c.Notifications.Channels = []NotificationChannelConfig{{Name: "log", Type: "log", QuietHours: QuietHoursConfig{Start: "22:00", End: "07:00", Timezone: "Mars/Olympus"}}}
+			},
+			errorMsg: `notifications.channels[0].quiet_hours: unknown timezone "Mars/Olympus"`,
+		},
+		{
+			name:     "invalid daily digest time",
+			modify:   func(c *Config) { c.Notifications.DailyDigest = DailyDigestConfig{Enabled: true, Time: "9pm"} },
+			errorMsg: `notifications.daily_digest: invalid time "9pm"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newValidTestConfig()
+			tt.modify(cfg)
+
+			err := NewConfigManager().Validate(cfg)
+			if tt.errorMsg == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.errorMsg {
+				t.Errorf("Validate() error = %v, expected %q", err, tt.errorMsg)
+			}
+		})
+	}
+}
+
+// sharedTestConfigYAML holds the sections every trading type needs
+const sharedTestConfigYAML = `
+logging:
+  level: info
+  file: logs/trading.log
+  max_size_mb: 100
+  max_backups: 5
+
+retry:
+  max_attempts: 3
+  initial_delay_ms: 1000
+  backoff_multiplier: 2.0
+
+conditional_orders:
+  monitoring_interval_ms: 1000
+  max_active_orders: 500
+  trigger_execution_timeout_ms: 3000
+`
+
+func TestLoadMinimalConfigPerTradingType(t *testing.T) {
+	futuresOnly := `futures:
+  api_key: test_futures_key
+  api_secret: test_futures_secret
+  base_url: https://fapi.binance.com
+  default_leverage: 10
+  risk:
+    max_order_value: 50000.0
+    max_position_value: 100000.0
+    max_leverage: 20
+    min_margin_ratio: 0.05
+` + sharedTestConfigYAML
+
+	spotOnly := `spot:
+  api_key: test_spot_key
+  api_secret: test_spot_secret
+  base_url: https://api.binance.com
+
+risk:
+  max_order_amount: 1000.0
+  max_daily_orders: 100
+  max_api_calls_per_min: 1200
+
+stop_loss:
+  default_trail_percent: 1.0
+  min_trail_percent: 0.1
+  max_trail_percent: 5.0
+  update_interval_ms: 1000
+` + sharedTestConfigYAML
+
+	tests := []struct {
+		name         string
+		content      string
+		tradingTypes []TradingType
+		errorMsg     string
+	}{
+		{
+			name:         "futures-only file for futures",
+			content:      futuresOnly,
+			tradingTypes: []TradingType{TradingTypeFutures},
+		},
+		{
+			name:    "futures-only file without trading type",
+			content: futuresOnly,
+		},
+		{
+			name:         "futures-only file for spot",
+			content:      futuresOnly,
+			tradingTypes: []TradingType{TradingTypeSpot},
+			errorMsg:     "config validation failed: spot trading requires api_key in the spot or binance section",
+		},
+		{
+			name:         "spot-only file for spot",
+			content:      spotOnly,
+			tradingTypes: []TradingType{TradingTypeSpot},
+		},
+		{
+			name:         "spot-only file for futures",
+			content:      spotOnly,
+			tradingTypes: []TradingType{TradingTypeFutures},
+			errorMsg:     "config validation failed: futures trading requires api_key in the futures section",
+		},
+		{
+			name:         "spot-only file for both",
+			content:      spotOnly,
+			tradingTypes: []TradingType{TradingTypeBoth},
+			errorMsg:     "config validation failed: futures trading requires api_key in the futures section",
+		},
+		{
+			name:         "minimal spot file missing risk",
+			content:      "spot:\n  api_key: k\n  api_secret: s\n  base_url: https://api.binance.com\n" + sharedTestConfigYAML,
+			tradingTypes: []TradingType{TradingTypeSpot},
+			errorMsg:     "config validation failed: spot trading: risk.max_order_amount must be greater than 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+
+			_, err := NewConfigManager().Load(configPath, tt.tradingTypes...)
+			if tt.errorMsg == "" {
+				if err != nil {
+					t.Errorf("Load() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.errorMsg {
+				t.Errorf("Load() error = %v, expected %q", err, tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestValidateByTradingType(t *testing.T) {
+	cm := NewConfigManager()
+
+	// Spot sections are not required when only futures is traded
+	cfg := newValidTestConfig()
+	cfg.Futures = newValidTestFuturesConfig()
+	cfg.Risk = RiskConfig{}
+	cfg.StopLoss = StopLossConfig{}
+	if err := cm.Validate(cfg, TradingTypeFutures); err != nil {
+		t.Errorf("futures validation should ignore spot sections, got %v", err)
+	}
+	if err := cm.Validate(cfg, TradingTypeBoth); err == nil || err.Error() != "spot trading: risk.max_order_amount must be greater than 0" {
+		t.Errorf("both validation should check spot sections, got %v", err)
+	}
+
+	// Futures sections are not required when only spot is traded
+	cfg = newValidTestConfig()
+	cfg.Futures = newValidTestFuturesConfig()
+	cfg.Futures.DefaultLeverage = 0
+	if err := cm.Validate(cfg, TradingTypeSpot); err != nil {
+		t.Errorf("spot validation should ignore the futures section, got %v", err)
+	}
+	if err := cm.Validate(cfg, TradingTypeFutures); err == nil || err.Error() != "futures config: default_leverage must be between 1 and 125" {
+		t.Errorf("futures validation should check the futures section, got %v", err)
+	}
+
+	// Shared sections are checked for every trading type
+	cfg = newValidTestConfig()
+	cfg.Futures = newValidTestFuturesConfig()
+	cfg.Logging.Level = "verbose"
+	for _, tradingType := range []TradingType{TradingTypeSpot, TradingTypeFutures} {
+		if err := cm.Validate(cfg, tradingType); err == nil || err.Error() != "logging.level must be one of: debug, info, warn, error" {
+			t.Errorf("%s validation should check logging, got %v", tradingType, err)
+		}
+	}
+
+	if err := cm.Validate(newValidTestConfig(), TradingType("margin")); err == nil || err.Error() != "unknown trading type: margin" {
+		t.Errorf("expected unknown trading type error, got %v", err)
+	}
+}
+
+// TestLoadDryRunKeys checks every documented dry run key reaches the market it applies to
+func TestLoadDryRunKeys(t *testing.T) {
+	markets := func(spotSection, spotKey, futuresKey string) string {
+		return spotSection + `:
+  api_key: test_spot_key
+  api_secret: test_spot_secret
+  base_url: https://api.binance.com
+  dry_run: ` + spotKey + `
+
+futures:
+  api_key: test_futures_key
+  api_secret: test_futures_secret
+  base_url: https://fapi.binance.com
+  default_leverage: 10
+  dry_run: ` + futuresKey + `
+  risk:
+    max_order_value: 50000.0
+    max_position_value: 100000.0
+    max_leverage: 20
+    min_margin_ratio: 0.05
+
+risk:
+  max_order_amount: 1000.0
+  max_daily_orders: 100
+  max_api_calls_per_min: 1200
+
+stop_loss:
+  default_trail_percent: 1.0
+  min_trail_percent: 0.1
+  max_trail_percent: 5.0
+  update_interval_ms: 1000
+` + sharedTestConfigYAML
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		spot    bool
+		futures bool
+	}{
+		{name: "no dry run keys", content: markets("spot", "false", "false")},
+		{name: "dry_run.enabled", content: markets("spot", "false", "false") + "\ndry_run:\n  enabled: true\n", spot: true, futures: true},
+		{name: "trading.dry_run alias", content: markets("spot", "false", "false") + "\ntrading:\n  dry_run: true\n", spot: true, futures: true},
+		{name: "spot.dry_run", content: markets("spot", "true", "false"), spot: true},
+		{name: "binance.dry_run without spot section", content: markets("binance", "true", "false"), spot: true},
+		{name: "futures.dry_run", content: markets("spot", "false", "true"), futures: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+
+			cfg, err := NewConfigManager().Load(configPath, TradingTypeBoth)
+			if err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+			if cfg.SpotDryRun() != tt.spot {
+				t.Errorf("SpotDryRun() = %v, expected %v", cfg.SpotDryRun(), tt.spot)
+			}
+			if cfg.FuturesDryRun() != tt.futures {
+				t.Errorf("FuturesDryRun() = %v, expected %v", cfg.FuturesDryRun(), tt.futures)
+			}
+		})
+	}
+}
+
+// TestLoadExampleConfig loads the shipped sample configuration, which is also the default config.yaml
+func TestLoadExampleConfig(t *testing.T) {
+	for _, name := range []string{"BINANCE_API_KEY", "BINANCE_API_SECRET", "BINANCE_FUTURES_API_KEY", "BINANCE_FUTURES_API_SECRET"} {
+		t.Setenv(name, "example")
+	}
+
+	cm := NewConfigManager()
+	for _, path := range []string{"../../config.example.yaml", "../../config.yaml"} {
+		if _, err := cm.Load(path, TradingTypeSpot, TradingTypeFutures); err != nil {
+			t.Errorf("Load(%s) error = %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchDebounce is how long Watch waits for writes to a changed file to settle before
+// reloading it, so an editor saving in several writes triggers one reload
+const DefaultWatchDebounce = 200 * time.Millisecond
+
+// RegisterRiskLimitsCallback registers a callback for changed risk limits
+func (cm *configManager) RegisterRiskLimitsCallback(fn func(*RiskConfig)) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.riskCallbacks = append(cm.riskCallbacks, fn)
+}
+
+// RegisterReloadErrorCallback registers a callback for failed reloads
+func (cm *configManager) RegisterReloadErrorCallback(fn func(error)) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.errorCallbacks = append(cm.errorCallbacks, fn)
+}
+
+// Watch watches the directory of the loaded file rather than the file itself, since editors
+// often save by writing a new file and renaming it over the old one
+func (cm *configManager) Watch(ctx context.Context) error {
+	cm.mu.RLock()
+	path := cm.path
+	cm.mu.RUnlock()
+	if path == "" {
+		return fmt.Errorf("no config file loaded to watch")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start config watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", filepath.Dir(path), err)
+	}
+
+	name := filepath.Clean(path)
+	var reload <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) != name || event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			reload = time.After(cm.watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			cm.reportReloadError(fmt.Errorf("config watcher: %w", err))
+		case <-reload:
+			reload = nil
+			cm.reload()
+		}
+	}
+}
+
+// reload re-reads the loaded file and, once it validates, replaces the configuration and
+// notifies the risk callbacks if the risk section changed
+func (cm *configManager) reload() {
+	cm.mu.RLock()
+	path, tradingTypes := cm.path, cm.tradingTypes
+	cm.mu.RUnlock()
+
+	config, err := cm.parse(path, tradingTypes...)
+	if err != nil {
+		cm.reportReloadError(err)
+		return
+	}
+
+	cm.mu.Lock()
+	previous := cm.config
+	cm.config = config
+	callbacks := append([]func(*RiskConfig){}, cm.riskCallbacks...)
+	cm.mu.Unlock()
+
+	if previous != nil && reflect.DeepEqual(previous.Risk, config.Risk) {
+		return
+	}
+	for _, fn := range callbacks {
+		risk := config.Risk
+		fn(&risk)
+	}
+}
+
+// reportReloadError passes a reload failure to the error callbacks
+func (cm *configManager) reportReloadError(err error) {
+	cm.mu.RLock()
+	callbacks := append([]func(error){}, cm.errorCallbacks...)
+	cm.mu.RUnlock()
+
+	for _, fn := range callbacks {
+		fn(err)
+	}
+}
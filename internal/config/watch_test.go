@@ -0,0 +1,158 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// watchTestConfig is a spot config with the given order limits
+func watchTestConfig(maxOrderAmount float64, maxDailyOrders int) string {
+	return fmt.Sprintf(`spot:
+  api_key: test_spot_key
+  api_secret: test_spot_secret
+  base_url: https://api.binance.com
+
+risk:
+  max_order_amount: %v
+  max_daily_orders: %d
+  max_api_calls_per_min: 1200
+
+stop_loss:
+  default_trail_percent: 1.0
+  min_trail_percent: 0.1
+  max_trail_percent: 5.0
+  update_interval_ms: 1000
+`, maxOrderAmount, maxDailyOrders) + sharedTestConfigYAML
+}
+
+// reloadRecorder collects the risk sections and errors passed to the reload callbacks
+type reloadRecorder struct {
+	mu     sync.Mutex
+	risks  []RiskConfig
+	errors []error
+}
+
+func (r *reloadRecorder) register(cm ConfigManager) {
+	cm.RegisterRiskLimitsCallback(func(risk *RiskConfig) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.risks = append(r.risks, *risk)
+	})
+	cm.RegisterReloadErrorCallback(func(err error) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.errors = append(r.errors, err)
+	})
+}
+
+func (r *reloadRecorder) counts() (int, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.risks), len(r.errors)
+}
+
+func TestConfigReload(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+	}
+	write(watchTestConfig(1000, 100))
+
+	cm := NewConfigManager().(*configManager)
+	if _, err := cm.Load(configPath, TradingTypeSpot); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	recorder := &reloadRecorder{}
+	recorder.register(cm)
+
+	// Tightened limits reach the callbacks
+	write(watchTestConfig(500, 20))
+	cm.reload()
+	if risks, errs := recorder.counts(); risks != 1 || errs != 0 {
+		t.Fatalf("callbacks after a risk change = %d risk / %d error, want 1 / 0", risks, errs)
+	}
+	if risk := recorder.risks[0]; risk.MaxOrderAmount != 500 || risk.MaxDailyOrders != 20 {
+		t.Errorf("reloaded risk = %+v, want 500 / 20", risk)
+	}
+
+	// An unchanged risk section is not passed on again
+	cm.reload()
+	if risks, _ := recorder.counts(); risks != 1 {
+		t.Errorf("risk callbacks after an unchanged reload = %d, want 1", risks)
+	}
+
+	// Half-written and invalid files are reported and the running configuration is kept
+	for _, content := range []string{"risk:\n  max_order_amount: [", watchTestConfig(0, 20)} {
+		write(content)
+		cm.reload()
+	}
+	if risks, errs := recorder.counts(); risks != 1 || errs != 2 {
+		t.Errorf("callbacks after invalid files = %d risk / %d error, want 1 / 2", risks, errs)
+	}
+	if !strings.Contains(recorder.errors[1].Error(), "risk.max_order_amount must be greater than 0") {
+		t.Errorf("validation error = %v", recorder.errors[1])
+	}
+	if got := cm.GetConfig().Risk.MaxOrderAmount; got != 500 {
+		t.Errorf("GetConfig() after invalid files has max_order_amount %v, want 500", got)
+	}
+}
+
+func TestConfigWatch(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(watchTestConfig(1000, 100)), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	if err := NewConfigManager().Watch(context.Background()); err == nil {
+		t.Error("Watch() without a loaded file should fail")
+	}
+
+	cm := NewConfigManager().(*configManager)
+	cm.watchDebounce = 10 * time.Millisecond
+	if _, err := cm.Load(configPath, TradingTypeSpot); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	recorder := &reloadRecorder{}
+	recorder.register(cm)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- cm.Watch(ctx) }()
+
+	// Rewrite the file until the watcher, which starts asynchronously, picks the change up
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if err := os.WriteFile(configPath, []byte(watchTestConfig(2500, 100)), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+		time.Sleep(100 * time.Millisecond)
+		if risks, _ := recorder.counts(); risks > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Watch() did not reload the changed file")
+		}
+	}
+	if got := cm.GetConfig().Risk.MaxOrderAmount; got != 2500 {
+		t.Errorf("GetConfig() after the change has max_order_amount %v, want 2500", got)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Watch() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Watch() did not return after the context was cancelled")
+	}
+}
@@ -0,0 +1,370 @@
+// Package metrics keeps counters and histograms and serves them in the Prometheus text
+// exposition format, so operators can scrape throughput, latency, errors and triggers.
+//
+// The format is written here rather than through prometheus/client_golang: the application
+// only needs counters, gauges and histograms served as text, which takes a few hundred lines,
+// while the client library would add its model, common and procfs modules and protobuf to a
+// dependency set that is otherwise a handful of modules. A Registry is created by the caller
+// and its metrics are handed to the services through NewSink; there is no global registry.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	pkgmetrics "binance-trader/pkg/metrics"
+)
+
+// ContentType is the media type of the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the upper bounds, in seconds, of request duration histograms
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// NewSink registers the application's metrics in the registry and returns the sink the
+// services record them through
+func NewSink(registry *Registry) *pkgmetrics.Sink {
+	return &pkgmetrics.Sink{
+		// status is the HTTP status code, or "error" when no response was received
+		APIRequests: registry.NewCounter("api_requests_total",
+			"Exchange REST requests by endpoint and HTTP status.", "endpoint", "status"),
+		APIRequestDuration: registry.NewHistogram("api_request_duration_seconds",
+			"Latency of exchange REST requests in seconds.", DefaultBuckets, "endpoint"),
+		APIRetries: registry.NewCounter("api_retries_total",
+			"Exchange REST requests retried after a retryable error.", "endpoint"),
+		// status is 429, or 418 once the IP is banned
+		RateLimitRejections: registry.NewCounter("rate_limit_rejections_total",
+			"Exchange REST requests rejected for exceeding the rate limit.", "status"),
+		RateLimitUsedWeight: registry.NewGauge("rate_limit_used_weight",
+			"Request weight used in the current minute, as reported by the exchange."),
+
+		// status is the order status the exchange returned, or "error" when it refused the
+		// order or did not answer
+		Orders: registry.NewCounter("orders_total",
+			"Spot orders sent to the exchange by side and resulting status.", "side", "status"),
+		ConditionalOrdersTriggered: registry.NewCounter("conditional_orders_triggered_total",
+			"Conditional orders whose trigger condition was met.", "symbol"),
+		// type is stop_loss, take_profit or trailing_stop
+		StopLossTriggered: registry.NewCounter("stop_loss_triggered_total",
+			"Stop loss, take profit and trailing stop orders triggered.", "symbol", "type"),
+		ActiveConditionalOrders: registry.NewGauge("conditional_orders_active",
+			"Conditional orders waiting for their trigger condition."),
+		ActiveStopOrders: registry.NewGauge("stop_orders_active",
+			"Active stop loss and take profit orders."),
+		MonitoringCycleDuration: registry.NewHistogram("monitoring_cycle_duration_seconds",
+			"Duration of monitoring engine cycles in seconds.", DefaultBuckets),
+	}
+}
+
+// metric is a metric family the registry writes
+type metric interface {
+	write(w *bufio.Writer)
+}
+
+// Registry holds metric families in registration order
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+	names   map[string]bool
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// register adds a metric family; a name registered twice is a programming error
+func (r *Registry) register(name string, m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.names[name] {
+		panic(fmt.Sprintf("metrics: %s registered twice", name))
+	}
+	r.names[name] = true
+	r.metrics = append(r.metrics, m)
+}
+
+// NewCounter registers a counter with the given label names
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{family: family{name: name, help: help, labels: labels}, values: make(map[string]*counterValue)}
+	r.register(name, c)
+	return c
+}
+
+// NewGauge registers a gauge with the given label names
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{family: family{name: name, help: help, labels: labels}, values: make(map[string]*counterValue)}
+	r.register(name, g)
+	return g
+}
+
+// NewHistogram registers a histogram with the given bucket upper bounds and label names
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	bounds := append([]float64(nil), buckets...)
+	sort.Float64s(bounds)
+	h := &Histogram{family: family{name: name, help: help, labels: labels}, buckets: bounds, values: make(map[string]*histogramValue)}
+	r.register(name, h)
+	return h
+}
+
+// WriteText writes every metric in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	metrics := append([]metric(nil), r.metrics...)
+	r.mu.Unlock()
+
+	buf := bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.write(buf)
+	}
+	return buf.Flush()
+}
+
+// Handler serves the registry in the Prometheus text exposition format
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		r.WriteText(w)
+	})
+}
+
+// family holds what counters and histograms share
+type family struct {
+	name   string
+	help   string
+	labels []string
+	mu     sync.Mutex
+}
+
+// key identifies a series by its label values
+func (f *family) key(labelValues []string) string {
+	if len(labelValues) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", f.name, len(f.labels), len(labelValues)))
+	}
+	return strings.Join(labelValues, "\xff")
+}
+
+// writeHeader writes the HELP and TYPE lines of the family
+func (f *family) writeHeader(w *bufio.Writer, metricType string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, metricType)
+}
+
+// labelPairs formats label values as {name="value",...}, with an optional extra pair last
+func (f *family) labelPairs(labelValues []string, extraName, extraValue string) string {
+	pairs := make([]string, 0, len(labelValues)+1)
+	for i, value := range labelValues {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", f.labels[i], escapeLabelValue(value)))
+	}
+	if extraName != "" {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", extraName, extraValue))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// counterValue is one series of a counter or gauge
+type counterValue struct {
+	labelValues []string
+	value       float64
+}
+
+// Counter is a monotonically increasing value per label set
+type Counter struct {
+	family
+	values map[string]*counterValue
+}
+
+// Inc adds one to the series of the label values
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds a non-negative amount to the series of the label values
+func (c *Counter) Add(amount float64, labelValues ...string) {
+	if amount < 0 {
+		panic(fmt.Sprintf("metrics: counter %s cannot decrease", c.name))
+	}
+	key := c.key(labelValues)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	series, exists := c.values[key]
+	if !exists {
+		series = &counterValue{labelValues: append([]string(nil), labelValues...)}
+		c.values[key] = series
+	}
+	series.value += amount
+}
+
+// Value returns the current value of the series of the label values
+func (c *Counter) Value(labelValues ...string) float64 {
+	key := c.key(labelValues)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if series, exists := c.values[key]; exists {
+		return series.value
+	}
+	return 0
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writeHeader(w, "counter")
+	for _, key := range sortedKeys(c.values) {
+		series := c.values[key]
+		fmt.Fprintf(w, "%s%s %s\n", c.name, c.labelPairs(series.labelValues, "", ""), formatFloat(series.value))
+	}
+}
+
+// Gauge is a value per label set that can go up and down
+type Gauge struct {
+	family
+	values map[string]*counterValue
+}
+
+// Set sets the series of the label values
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	key := g.key(labelValues)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	series, exists := g.values[key]
+	if !exists {
+		series = &counterValue{labelValues: append([]string(nil), labelValues...)}
+		g.values[key] = series
+	}
+	series.value = value
+}
+
+// Value returns the current value of the series of the label values
+func (g *Gauge) Value(labelValues ...string) float64 {
+	key := g.key(labelValues)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if series, exists := g.values[key]; exists {
+		return series.value
+	}
+	return 0
+}
+
+func (g *Gauge) write(w *bufio.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.writeHeader(w, "gauge")
+	for _, key := range sortedKeys(g.values) {
+		series := g.values[key]
+		fmt.Fprintf(w, "%s%s %s\n", g.name, g.labelPairs(series.labelValues, "", ""), formatFloat(series.value))
+	}
+}
+
+// histogramValue is one series of a histogram; counts are per bucket, not cumulative
+type histogramValue struct {
+	labelValues []string
+	counts      []uint64
+	count       uint64
+	sum         float64
+}
+
+// Histogram counts observations in buckets per label set
+type Histogram struct {
+	family
+	buckets []float64
+	values  map[string]*histogramValue
+}
+
+// Observe records a value in the series of the label values
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	key := h.key(labelValues)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	series, exists := h.values[key]
+	if !exists {
+		series = &histogramValue{labelValues: append([]string(nil), labelValues...), counts: make([]uint64, len(h.buckets))}
+		h.values[key] = series
+	}
+	if i := sort.SearchFloat64s(h.buckets, value); i < len(h.buckets) {
+		series.counts[i]++
+	}
+	series.count++
+	series.sum += value
+}
+
+// Count returns the number of observations in the series of the label values
+func (h *Histogram) Count(labelValues ...string) uint64 {
+	key := h.key(labelValues)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if series, exists := h.values[key]; exists {
+		return series.count
+	}
+	return 0
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.writeHeader(w, "histogram")
+	for _, key := range sortedKeys(h.values) {
+		series := h.values[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += series.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(series.labelValues, "le", formatFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(series.labelValues, "le", "+Inf"), series.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelPairs(series.labelValues, "", ""), formatFloat(series.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelPairs(series.labelValues, "", ""), series.count)
+	}
+}
+
+// sortedKeys returns the keys of a series map in order, so the output is stable
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatFloat formats a sample value the way Prometheus parses it
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// escapeHelp escapes backslashes and line feeds in HELP text
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+// escapeLabelValue escapes backslashes, double quotes and line feeds in label values
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,249 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sampleLine matches a sample line: a name, optional labels and a value
+var sampleLine = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*(\{([a-zA-Z_][a-zA-Z0-9_]*="([^"\\]|\\.)*",?)*\})? (\+Inf|-Inf|NaN|[-+]?[0-9.eE+-]+)$`)
+
+// validateText checks every line is a HELP or TYPE comment or a sample of a declared family
+func validateText(t *testing.T, text string) {
+	t.Helper()
+
+	declared := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "# HELP "):
+		case strings.HasPrefix(line, "# TYPE "):
+			fields := strings.Fields(line)
+			if len(fields) != 4 {
+				t.Errorf("malformed TYPE line %q", line)
+				continue
+			}
+			declared[fields[2]] = fields[3]
+		case sampleLine.MatchString(line):
+			name := line[:strings.IndexAny(line, "{ ")]
+			family := name
+			for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+				if declared[strings.TrimSuffix(name, suffix)] == "histogram" {
+					family = strings.TrimSuffix(name, suffix)
+				}
+			}
+			if declared[family] == "" {
+				t.Errorf("sample %q before its TYPE line", line)
+			}
+		default:
+			t.Errorf("invalid line %q", line)
+		}
+	}
+}
+
+func TestRegistryWriteText(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.NewCounter("requests_total", "Requests.", "endpoint", "status")
+	duration := registry.NewHistogram("duration_seconds", "Duration.", []float64{0.1, 1}, "endpoint")
+	active := registry.NewGauge("active_orders", "Active orders.")
+
+	requests.Inc("/api/v3/order", "200")
+	requests.Inc("/api/v3/order", "200")
+	requests.Inc(`/a"b\c`, "error")
+	duration.Observe(0.05, "/api/v3/order")
+	duration.Observe(0.5, "/api/v3/order")
+	duration.Observe(3, "/api/v3/order")
+	active.Set(4)
+	active.Set(2)
+
+	var out strings.Builder
+	if err := registry.WriteText(&out); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	text := out.String()
+	validateText(t, text)
+
+	for _, want := range []string{
+		"# TYPE requests_total counter\n",
+		`requests_total{endpoint="/api/v3/order",status="200"} 2` + "\n",
+		`requests_total{endpoint="/a\"b\\c",status="error"} 1` + "\n",
+		"# TYPE duration_seconds histogram\n",
+		`duration_seconds_bucket{endpoint="/api/v3/order",le="0.1"} 1` + "\n",
+		`duration_seconds_bucket{endpoint="/api/v3/order",le="1"} 2` + "\n",
+		`duration_seconds_bucket{endpoint="/api/v3/order",le="+Inf"} 3` + "\n",
+		`duration_seconds_sum{endpoint="/api/v3/order"} 3.55` + "\n",
+		`duration_seconds_count{endpoint="/api/v3/order"} 3` + "\n",
+		"# TYPE active_orders gauge\nactive_orders 2\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output does not contain %q:\n%s", want, text)
+		}
+	}
+
+	if got := requests.Value("/api/v3/order", "200"); got != 2 {
+		t.Errorf("Value() = %v, want 2", got)
+	}
+	if got := duration.Count("/api/v3/order"); got != 3 {
+		t.Errorf("Count() = %d, want 3", got)
+	}
+	if got := active.Value(); got != 2 {
+		t.Errorf("gauge Value() = %v, want 2", got)
+	}
+}
+
+func TestEscaping(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounter("escaped_total", "Help with a \\ backslash,\na line feed and \"quotes\".", "path").
+		Inc("a\\b\"c\nd")
+
+	var out strings.Builder
+	registry.WriteText(&out)
+	text := out.String()
+	validateText(t, text)
+
+	// HELP text escapes backslashes and line feeds but keeps double quotes
+	if want := `# HELP escaped_total Help with a \\ backslash,\na line feed and "quotes".` + "\n"; !strings.Contains(text, want) {
+		t.Errorf("output does not contain %q:\n%s", want, text)
+	}
+	// Label values escape all three
+	if want := `escaped_total{path="a\\b\"c\nd"} 1` + "\n"; !strings.Contains(text, want) {
+		t.Errorf("output does not contain %q:\n%s", want, text)
+	}
+}
+
+func TestHelpAndTypeOrder(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewGauge("zeta_active", "Registered first.").Set(1)
+	registry.NewHistogram("alpha_seconds", "Registered second.", []float64{1}, "endpoint").Observe(0.5, "/a")
+	registry.NewCounter("mid_total", "Registered last.", "symbol").Inc("BTCUSDT")
+
+	var out strings.Builder
+	registry.WriteText(&out)
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	types := map[string]string{"zeta_active": "gauge", "alpha_seconds": "histogram", "mid_total": "counter"}
+
+	// Families come in registration order, each as HELP, TYPE and then its samples
+	var families []string
+	for i := 0; i < len(lines); {
+		help := strings.Fields(lines[i])
+		if len(help) < 3 || help[1] != "HELP" {
+			t.Fatalf("line %d = %q, want a HELP line", i+1, lines[i])
+		}
+		name := help[2]
+		if i+1 >= len(lines) || lines[i+1] != "# TYPE "+name+" "+types[name] {
+			t.Fatalf("line %d = %q, want the TYPE line of %s right after its HELP line", i+2, lines[i+1], name)
+		}
+		families = append(families, name)
+		i += 2
+		for ; i < len(lines) && !strings.HasPrefix(lines[i], "#"); i++ {
+			if !strings.HasPrefix(lines[i], name) {
+				t.Errorf("sample %q listed under %s", lines[i], name)
+			}
+		}
+	}
+	if got := strings.Join(families, ","); got != "zeta_active,alpha_seconds,mid_total" {
+		t.Errorf("families = %s, want registration order", got)
+	}
+}
+
+func TestHistogramInfBucket(t *testing.T) {
+	registry := NewRegistry()
+	// Bounds are sorted whatever order they are given in
+	latency := registry.NewHistogram("latency_seconds", "Latency.", []float64{2, 1})
+	latency.Observe(0.5)
+	latency.Observe(2) // A bound's own value falls in its bucket
+	latency.Observe(5) // Above every bound: counted only by +Inf
+	latency.Observe(7)
+
+	var out strings.Builder
+	registry.WriteText(&out)
+	text := out.String()
+	validateText(t, text)
+
+	want := `latency_seconds_bucket{le="1"} 1
+latency_seconds_bucket{le="2"} 2
+latency_seconds_bucket{le="+Inf"} 4
+latency_seconds_sum 14.5
+latency_seconds_count 4
+`
+	if !strings.HasSuffix(text, want) {
+		t.Errorf("histogram output =\n%s\nwant it to end with\n%s", text, want)
+	}
+
+	// A series whose observations all exceed the bounds still has its +Inf bucket
+	registry = NewRegistry()
+	registry.NewHistogram("slow_seconds", "Slow.", []float64{0.1}, "endpoint").Observe(30, "/a")
+	out.Reset()
+	registry.WriteText(&out)
+	for _, line := range []string{`slow_seconds_bucket{endpoint="/a",le="0.1"} 0`, `slow_seconds_bucket{endpoint="/a",le="+Inf"} 1`} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("output does not contain %q:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestServer(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounter("triggered_total", "Triggered orders.", "symbol").Inc("BTCUSDT")
+
+	server := NewServer("127.0.0.1:0", registry)
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	resp, err := http.Get("http://" + server.Addr() + Path)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", Path, err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != ContentType {
+		t.Errorf("status = %d, content type = %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	validateText(t, string(body))
+	if !strings.Contains(string(body), `triggered_total{symbol="BTCUSDT"} 1`) {
+		t.Errorf("body does not contain the counter:\n%s", body)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if _, err := http.Get("http://" + server.Addr() + Path); err == nil {
+		t.Error("server still answers after Shutdown()")
+	}
+}
+
+func TestSinkRegistryIsValid(t *testing.T) {
+	registry := NewRegistry()
+	sink := NewSink(registry)
+	sink.APIRequests.Inc("/api/v3/ticker/price", "200")
+	sink.APIRequestDuration.Observe(0.02, "/api/v3/ticker/price")
+	sink.ConditionalOrdersTriggered.Inc("BTCUSDT")
+	sink.StopLossTriggered.Inc("BTCUSDT", "trailing_stop")
+	sink.APIRetries.Inc("/api/v3/order")
+	sink.RateLimitRejections.Inc("429")
+	sink.RateLimitUsedWeight.Set(120)
+	sink.Orders.Inc("BUY", "FILLED")
+	sink.ActiveConditionalOrders.Set(3)
+	sink.ActiveStopOrders.Set(1)
+	sink.MonitoringCycleDuration.Observe(0.03)
+
+	var out strings.Builder
+	if err := registry.WriteText(&out); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	validateText(t, out.String())
+	if !strings.Contains(out.String(), `orders_total{side="BUY",status="FILLED"} 1`) {
+		t.Errorf("output does not contain the order counter:\n%s", out.String())
+	}
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Path is where the server exposes the metrics
+const Path = "/metrics"
+
+// Server serves a registry over HTTP for Prometheus to scrape
+type Server struct {
+	server   *http.Server
+	listener net.Listener
+	done     chan struct{}
+}
+
+// NewServer creates a server exposing the registry on addr (host:port)
+func NewServer(addr string, registry *Registry) *Server {
+	mux := http.NewServeMux()
+	mux.Handle(Path, registry.Handler())
+
+	return &Server{
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		done: make(chan struct{}),
+	}
+}
+
+// Start listens on the address and serves in the background; a bind error is returned here
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+
+	go func() {
+		defer close(s.done)
+		s.server.Serve(listener)
+	}()
+	return nil
+}
+
+// Addr returns the address the server listens on, e.g. the port picked for ":0"
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return s.server.Addr
+	}
+	return s.listener.Addr().String()
+}
+
+// Shutdown stops accepting scrapes and waits for those in flight until ctx is done
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.listener == nil {
+		return errors.New("metrics server not started")
+	}
+	if err := s.server.Shutdown(ctx); err != nil {
+		return err
+	}
+	<-s.done
+	return nil
+}
@@ -0,0 +1,164 @@
+package replay
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SymbolActivity holds the fills and realized PnL of one symbol within a period
+type SymbolActivity struct {
+	Symbol      string
+	Fills       int
+	BoughtQty   float64
+	SoldQty     float64
+	RealizedPnL float64
+}
+
+// Activity summarizes the fills, triggers and realized PnL of a period
+type Activity struct {
+	From        time.Time
+	To          time.Time
+	Fills       int
+	Triggers    int // Conditional order and stop triggers
+	Errors      int
+	RealizedPnL float64
+	Symbols     []*SymbolActivity
+}
+
+// costBasis is the average-cost holding of a symbol built from earlier fills
+type costBasis struct {
+	quantity float64
+	avgPrice float64
+}
+
+// SummarizeActivity summarizes the entries in [from, to). An entry with an order ID and a
+// positive executed_qty counts as a fill, once per order. Realized PnL is computed for sells
+// against the average cost of buys seen so far in the journal, including buys before from;
+// fills without a price or quote_qty cannot be valued and contribute no PnL.
+func SummarizeActivity(journal *Journal, from, to time.Time) *Activity {
+	activity := &Activity{From: from, To: to}
+
+	// The last entry of each order carries its final executed quantity
+	lastFill := make(map[string]*Entry)
+	for _, entry := range journal.Entries {
+		if !entry.Time.Before(to) || entry.OrderID == "" || fieldFloat(entry.Fields, "executed_qty") <= 0 {
+			continue
+		}
+		lastFill[entry.OrderID] = entry
+	}
+
+	var fills []*Entry
+	for _, entry := range journal.Entries {
+		if entry.OrderID != "" && lastFill[entry.OrderID] == entry {
+			fills = append(fills, entry)
+		}
+	}
+
+	symbols := make(map[string]*SymbolActivity)
+	holdings := make(map[string]*costBasis)
+	for _, entry := range fills {
+		inPeriod := !entry.Time.Before(from)
+		symbol := entry.Symbol
+
+		var stats *SymbolActivity
+		if inPeriod {
+			activity.Fills++
+			stats = symbols[symbol]
+			if stats == nil {
+				stats = &SymbolActivity{Symbol: symbol}
+				symbols[symbol] = stats
+			}
+			stats.Fills++
+		}
+
+		quantity := fieldFloat(entry.Fields, "executed_qty")
+		price := fillPrice(entry.Fields, quantity)
+		holding := holdings[symbol]
+		if holding == nil {
+			holding = &costBasis{}
+			holdings[symbol] = holding
+		}
+
+		switch strings.ToUpper(fieldString(entry.Fields, "side")) {
+		case "BUY":
+			if inPeriod {
+				stats.BoughtQty += quantity
+			}
+			if price > 0 {
+				holding.avgPrice = (holding.avgPrice*holding.quantity + price*quantity) / (holding.quantity + quantity)
+				holding.quantity += quantity
+			}
+		case "SELL":
+			if inPeriod {
+				stats.SoldQty += quantity
+			}
+			matched := quantity
+			if matched > holding.quantity {
+				matched = holding.quantity
+			}
+			if price > 0 && matched > 0 {
+				if inPeriod {
+					pnl := (price - holding.avgPrice) * matched
+					stats.RealizedPnL += pnl
+					activity.RealizedPnL += pnl
+				}
+				holding.quantity -= matched
+			}
+		}
+	}
+
+	for _, entry := range journal.Entries {
+		if entry.Time.Before(from) || !entry.Time.Before(to) {
+			continue
+		}
+		switch entry.Kind {
+		case EventKindTrigger, EventKindStop:
+			activity.Triggers++
+		case EventKindError:
+			activity.Errors++
+		}
+	}
+
+	for _, stats := range symbols {
+		activity.Symbols = append(activity.Symbols, stats)
+	}
+	sort.Slice(activity.Symbols, func(i, j int) bool {
+		return activity.Symbols[i].Symbol < activity.Symbols[j].Symbol
+	})
+
+	return activity
+}
+
+// fillPrice returns the average fill price from quote_qty, falling back to the order price
+func fillPrice(fields map[string]interface{}, quantity float64) float64 {
+	if quoteQty := fieldFloat(fields, "quote_qty"); quoteQty > 0 && quantity > 0 {
+		return quoteQty / quantity
+	}
+	if avgPrice := fieldFloat(fields, "avg_price"); avgPrice > 0 {
+		return avgPrice
+	}
+	return fieldFloat(fields, "price")
+}
+
+// fieldFloat returns a numeric field, or 0 when it is missing or not a number
+func fieldFloat(fields map[string]interface{}, key string) float64 {
+	switch v := fields[key].(type) {
+	case json.Number:
+		value, err := v.Float64()
+		if err != nil {
+			return 0
+		}
+		return value
+	case string:
+		value, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0
+		}
+		return value
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,48 @@
+package replay
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+const activityJournal = `{"event_type":"order_created","level":"info","message":"Order event","order_id":2001,"order_type":"LIMIT","quantity":1,"executed_qty":1,"price":100,"side":"BUY","status":"FILLED","symbol":"SOLUSDT","timestamp":"2024-05-31T10:00:00Z"}
+{"event_type":"order_created","level":"info","message":"Order event","order_id":2002,"order_type":"MARKET","quantity":1,"executed_qty":1,"price":0,"quote_qty":120,"side":"BUY","status":"FILLED","symbol":"SOLUSDT","timestamp":"2024-06-01T09:00:00Z"}
+{"level":"info","message":"Trigger condition met, executing order","order_id":"cond-1","symbol":"SOLUSDT","timestamp":"2024-06-01T11:59:58Z"}
+{"event_type":"order_created","level":"info","message":"Order event","order_id":2003,"order_type":"MARKET","quantity":1.5,"executed_qty":1.5,"price":0,"quote_qty":195,"side":"SELL","status":"FILLED","symbol":"SOLUSDT","timestamp":"2024-06-01T12:00:00Z"}
+{"event_type":"order_created","level":"info","message":"Order event","order_id":2004,"order_type":"LIMIT","quantity":0.1,"executed_qty":0,"price":3000,"side":"BUY","status":"NEW","symbol":"ETHUSDT","timestamp":"2024-06-01T13:00:00Z"}
+{"level":"info","message":"Long position opened successfully","order_id":3001,"symbol":"BTCUSDT","status":"FILLED","executed_qty":0.01,"avg_price":65000,"timestamp":"2024-06-01T14:00:00Z"}
+{"level":"info","message":"Trailing stop order triggered","order_id":"stop-1","symbol":"BTCUSDT","timestamp":"2024-06-01T15:00:00Z"}
+{"error":"HTTP server error: 503","level":"error","message":"Error occurred","symbol":"ETHUSDT","timestamp":"2024-06-01T16:00:00Z"}
+{"event_type":"order_created","level":"info","message":"Order event","order_id":2005,"order_type":"MARKET","quantity":1,"executed_qty":1,"quote_qty":200,"side":"BUY","status":"FILLED","symbol":"SOLUSDT","timestamp":"2024-06-02T01:00:00Z"}`
+
+func TestSummarizeActivity(t *testing.T) {
+	journal, err := ParseJournal(strings.NewReader(activityJournal))
+	if err != nil {
+		t.Fatalf("ParseJournal() unexpected error: %v", err)
+	}
+
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	activity := SummarizeActivity(journal, from, from.Add(24*time.Hour))
+
+	// The unfilled ETH order and the fills outside the day are not counted
+	if activity.Fills != 3 {
+		t.Errorf("expected 3 fills, got %d", activity.Fills)
+	}
+	if activity.Triggers != 2 || activity.Errors != 1 {
+		t.Errorf("expected 2 triggers and 1 error, got %d and %d", activity.Triggers, activity.Errors)
+	}
+	if len(activity.Symbols) != 2 || activity.Symbols[0].Symbol != "BTCUSDT" || activity.Symbols[1].Symbol != "SOLUSDT" {
+		t.Fatalf("unexpected symbols: %+v", activity.Symbols)
+	}
+
+	// The sell of 1.5 @ 130 is matched against the average cost of 110 of the buys on both days
+	sol := activity.Symbols[1]
+	if sol.Fills != 2 || sol.BoughtQty != 1 || sol.SoldQty != 1.5 {
+		t.Errorf("unexpected SOLUSDT activity: %+v", sol)
+	}
+	if math.Abs(sol.RealizedPnL-30) > 1e-9 || math.Abs(activity.RealizedPnL-30) > 1e-9 {
+		t.Errorf("expected realized PnL 30, got %v (total %v)", sol.RealizedPnL, activity.RealizedPnL)
+	}
+}
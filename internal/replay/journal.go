@@ -0,0 +1,204 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EventKind classifies a journal entry
+type EventKind string
+
+const (
+	EventKindOrder   EventKind = "ORDER"
+	EventKindTrigger EventKind = "TRIGGER"
+	EventKindFill    EventKind = "FILL"
+	EventKindStop    EventKind = "STOP"
+	EventKindError   EventKind = "ERROR"
+	EventKindWarning EventKind = "WARN"
+	EventKindOther   EventKind = "OTHER"
+)
+
+// maxLineSize bounds a single journal line (large composite trigger logs included)
+const maxLineSize = 1024 * 1024
+
+// Entry represents a single parsed journal line
+type Entry struct {
+	Line      int
+	Time      time.Time
+	Level     string
+	Message   string
+	Kind      EventKind
+	EventType string
+	Symbol    string
+	OrderID   string
+	TraceID   string
+	Error     string
+	Fields    map[string]interface{}
+}
+
+// Journal holds parsed entries in chronological order
+type Journal struct {
+	Entries      []*Entry
+	SkippedLines int
+}
+
+// Filter restricts which entries are replayed
+type Filter struct {
+	Symbol  string
+	TraceID string
+}
+
+// ParseJournal parses JSON log lines written by the logger; malformed lines are skipped
+// and unknown event types are kept as OTHER so newer journals can still be replayed
+func ParseJournal(r io.Reader) (*Journal, error) {
+	journal := &Journal{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		entry, err := parseEntry(line)
+		if err != nil {
+			journal.SkippedLines++
+			continue
+		}
+		entry.Line = lineNum
+		journal.Entries = append(journal.Entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	// Order chronologically, keeping file order for identical timestamps
+	sort.SliceStable(journal.Entries, func(i, j int) bool {
+		return journal.Entries[i].Time.Before(journal.Entries[j].Time)
+	})
+
+	inheritSymbols(journal.Entries)
+
+	return journal, nil
+}
+
+// Filter returns a new journal containing only entries matching the filter
+func (j *Journal) Filter(filter Filter) *Journal {
+	filtered := &Journal{SkippedLines: j.SkippedLines}
+	for _, entry := range j.Entries {
+		if filter.Symbol != "" && !strings.EqualFold(entry.Symbol, filter.Symbol) {
+			continue
+		}
+		if filter.TraceID != "" && entry.TraceID != filter.TraceID {
+			continue
+		}
+		filtered.Entries = append(filtered.Entries, entry)
+	}
+	return filtered
+}
+
+// parseEntry decodes and classifies a single journal line
+func parseEntry(line []byte) (*Entry, error) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{
+		Level:     fieldString(fields, "level"),
+		Message:   fieldString(fields, "message"),
+		EventType: fieldString(fields, "event_type"),
+		Symbol:    fieldString(fields, "symbol"),
+		OrderID:   fieldString(fields, "order_id"),
+		TraceID:   fieldString(fields, "trace_id"),
+		Error:     fieldString(fields, "error"),
+		Fields:    fields,
+	}
+
+	timestamp := fieldString(fields, "timestamp")
+	parsed, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp %q: %w", timestamp, err)
+	}
+	entry.Time = parsed
+	entry.Kind = classify(entry)
+
+	return entry, nil
+}
+
+// classify determines the event kind from the log message and fields
+func classify(entry *Entry) EventKind {
+	message := strings.ToLower(entry.Message)
+
+	switch {
+	case entry.EventType != "":
+		return EventKindOrder
+	case strings.Contains(message, "stop") && strings.Contains(message, "triggered"):
+		return EventKindStop
+	case strings.Contains(message, "trigger condition met") || strings.HasSuffix(message, "conditional order triggered"):
+		return EventKindTrigger
+	case strings.Contains(message, "conditional order executed"):
+		return EventKindFill
+	case entry.Level == "error" || entry.Level == "fatal":
+		return EventKindError
+	case entry.Level == "warning":
+		return EventKindWarning
+	default:
+		return EventKindOther
+	}
+}
+
+// inheritSymbols fills missing symbols from earlier entries of the same order
+func inheritSymbols(entries []*Entry) {
+	symbols := make(map[string]string)
+	for _, entry := range entries {
+		if entry.OrderID == "" {
+			continue
+		}
+		if entry.Symbol != "" {
+			symbols[entry.OrderID] = entry.Symbol
+			continue
+		}
+		entry.Symbol = symbols[entry.OrderID]
+	}
+}
+
+// fieldString returns a field as a string regardless of its JSON type
+func fieldString(fields map[string]interface{}, key string) string {
+	value, exists := fields[key]
+	if !exists || value == nil {
+		return ""
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(encoded)
+	}
+}
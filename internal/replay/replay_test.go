@@ -0,0 +1,123 @@
+package replay
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+// renderReplay replays the fixture journal with a filter and returns the full report
+func renderReplay(t *testing.T, filter Filter) string {
+	t.Helper()
+
+	file, err := os.Open(filepath.Join("testdata", "journal.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer file.Close()
+
+	journal, err := ParseJournal(file)
+	if err != nil {
+		t.Fatalf("ParseJournal() unexpected error: %v", err)
+	}
+	journal = journal.Filter(filter)
+
+	var buf bytes.Buffer
+	WriteNarrative(&buf, journal)
+	buf.WriteString("\n")
+	WriteSummary(&buf, Summarize(journal))
+	return buf.String()
+}
+
+func TestReplayGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		golden string
+	}{
+		{name: "full journal", golden: "journal.golden"},
+		{name: "symbol filter", filter: Filter{Symbol: "btcusdt"}, golden: "journal_btcusdt.golden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := renderReplay(t, tt.filter)
+			goldenPath := filepath.Join("testdata", tt.golden)
+
+			if *updateGolden {
+				if err := os.WriteFile(goldenPath, []byte(output), 0644); err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+			}
+
+			expected, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+			if output != string(expected) {
+				t.Errorf("replay output does not match %s\n--- got ---\n%s\n--- want ---\n%s", tt.golden, output, expected)
+			}
+		})
+	}
+}
+
+func TestParseJournalToleratesUnknownEvents(t *testing.T) {
+	input := strings.Join([]string{
+		`{"level":"info","message":"Quantum hedge rebalanced","symbol":"BTCUSDT","timestamp":"2025-12-04T09:00:02Z","new_field":{"nested":true}}`,
+		`{"event_type":"order_teleported","level":"info","message":"Order event","order_id":7,"symbol":"BTCUSDT","timestamp":"2025-12-04T09:00:01Z"}`,
+		`{"level":"info","message":"missing timestamp"}`,
+		`not json`,
+	}, "\n")
+
+	journal, err := ParseJournal(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseJournal() unexpected error: %v", err)
+	}
+
+	if len(journal.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(journal.Entries))
+	}
+	if journal.SkippedLines != 2 {
+		t.Errorf("expected 2 skipped lines, got %d", journal.SkippedLines)
+	}
+
+	// Entries are replayed chronologically rather than in file order
+	if journal.Entries[0].Kind != EventKindOrder || journal.Entries[0].EventType != "order_teleported" {
+		t.Errorf("expected unknown order event type to be kept, got %+v", journal.Entries[0])
+	}
+	if journal.Entries[1].Kind != EventKindOther {
+		t.Errorf("expected unknown message to be classified as OTHER, got %s", journal.Entries[1].Kind)
+	}
+}
+
+func TestSummarizeLatencyAndTraceFilter(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "journal.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer file.Close()
+
+	journal, err := ParseJournal(file)
+	if err != nil {
+		t.Fatalf("ParseJournal() unexpected error: %v", err)
+	}
+
+	summary := Summarize(journal)
+	if len(summary.Latencies) != 1 || summary.Latencies[0].Duration != 3*time.Second {
+		t.Errorf("expected one 3s trigger-to-fill latency, got %+v", summary.Latencies)
+	}
+	if len(summary.UnfilledOrders) != 1 || summary.UnfilledOrders[0] != "cond-2" {
+		t.Errorf("expected cond-2 to be reported as unfilled, got %v", summary.UnfilledOrders)
+	}
+
+	traced := journal.Filter(Filter{TraceID: "t-42"})
+	if len(traced.Entries) != 1 || traced.Entries[0].OrderID != "1003" {
+		t.Errorf("expected trace filter to keep order 1003 only, got %d entries", len(traced.Entries))
+	}
+}
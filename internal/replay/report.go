@@ -0,0 +1,236 @@
+package replay
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// digitsPattern normalizes numbers so similar errors fall into the same cluster
+var digitsPattern = regexp.MustCompile(`[0-9]+(\.[0-9]+)?`)
+
+// HourlyCount holds the number of order events within an hour
+type HourlyCount struct {
+	Hour  time.Time
+	Count int
+}
+
+// Latency holds the delay between a trigger and its fill
+type Latency struct {
+	OrderID  string
+	Symbol   string
+	Duration time.Duration
+}
+
+// ErrorCluster groups errors with the same normalized message
+type ErrorCluster struct {
+	Key   string
+	Count int
+	First time.Time
+	Last  time.Time
+}
+
+// Summary holds replay statistics
+type Summary struct {
+	TotalEntries   int
+	SkippedLines   int
+	KindCounts     map[EventKind]int
+	OrdersPerHour  []HourlyCount
+	Latencies      []Latency
+	UnfilledOrders []string
+	ErrorClusters  []ErrorCluster
+}
+
+// Summarize computes statistics for a journal
+func Summarize(journal *Journal) *Summary {
+	summary := &Summary{
+		TotalEntries: len(journal.Entries),
+		SkippedLines: journal.SkippedLines,
+		KindCounts:   make(map[EventKind]int),
+	}
+
+	hourly := make(map[time.Time]int)
+	triggers := make(map[string]*Entry)
+	triggerOrder := make([]string, 0)
+	clusters := make(map[string]*ErrorCluster)
+
+	for _, entry := range journal.Entries {
+		summary.KindCounts[entry.Kind]++
+
+		switch entry.Kind {
+		case EventKindOrder:
+			hourly[entry.Time.Truncate(time.Hour)]++
+		case EventKindTrigger:
+			if _, exists := triggers[entry.OrderID]; !exists {
+				triggerOrder = append(triggerOrder, entry.OrderID)
+			}
+			triggers[entry.OrderID] = entry
+		case EventKindFill:
+			if trigger, exists := triggers[entry.OrderID]; exists {
+				summary.Latencies = append(summary.Latencies, Latency{
+					OrderID:  entry.OrderID,
+					Symbol:   entry.Symbol,
+					Duration: entry.Time.Sub(trigger.Time),
+				})
+				delete(triggers, entry.OrderID)
+			}
+		case EventKindError:
+			key := clusterKey(entry)
+			cluster, exists := clusters[key]
+			if !exists {
+				cluster = &ErrorCluster{Key: key, First: entry.Time}
+				clusters[key] = cluster
+			}
+			cluster.Count++
+			cluster.Last = entry.Time
+		}
+	}
+
+	for hour, count := range hourly {
+		summary.OrdersPerHour = append(summary.OrdersPerHour, HourlyCount{Hour: hour, Count: count})
+	}
+	sort.Slice(summary.OrdersPerHour, func(i, j int) bool {
+		return summary.OrdersPerHour[i].Hour.Before(summary.OrdersPerHour[j].Hour)
+	})
+
+	for _, orderID := range triggerOrder {
+		if _, pending := triggers[orderID]; pending {
+			summary.UnfilledOrders = append(summary.UnfilledOrders, orderID)
+		}
+	}
+
+	for _, cluster := range clusters {
+		summary.ErrorClusters = append(summary.ErrorClusters, *cluster)
+	}
+	sort.Slice(summary.ErrorClusters, func(i, j int) bool {
+		if summary.ErrorClusters[i].Count != summary.ErrorClusters[j].Count {
+			return summary.ErrorClusters[i].Count > summary.ErrorClusters[j].Count
+		}
+		return summary.ErrorClusters[i].Key < summary.ErrorClusters[j].Key
+	})
+
+	return summary
+}
+
+// WriteNarrative prints the chronological narrative of known events
+func WriteNarrative(w io.Writer, journal *Journal) {
+	fmt.Fprintln(w, "===========================================")
+	fmt.Fprintln(w, "  Timeline")
+	fmt.Fprintln(w, "===========================================")
+
+	for _, entry := range journal.Entries {
+		if entry.Kind == EventKindOther {
+			continue
+		}
+		fmt.Fprintf(w, "%s  %-7s  %s\n", entry.Time.UTC().Format(timeLayout), entry.Kind, describe(entry))
+	}
+}
+
+// WriteSummary prints replay statistics
+func WriteSummary(w io.Writer, summary *Summary) {
+	fmt.Fprintln(w, "===========================================")
+	fmt.Fprintln(w, "  Summary")
+	fmt.Fprintln(w, "===========================================")
+	fmt.Fprintf(w, "Entries:         %d (skipped lines: %d)\n", summary.TotalEntries, summary.SkippedLines)
+
+	kinds := []EventKind{EventKindOrder, EventKindTrigger, EventKindFill, EventKindStop, EventKindError, EventKindWarning, EventKindOther}
+	for _, kind := range kinds {
+		fmt.Fprintf(w, "  %-7s        %d\n", kind, summary.KindCounts[kind])
+	}
+
+	fmt.Fprintln(w, "-------------------------------------------")
+	fmt.Fprintln(w, "Orders per hour:")
+	if len(summary.OrdersPerHour) == 0 {
+		fmt.Fprintln(w, "  (none)")
+	}
+	for _, hourly := range summary.OrdersPerHour {
+		fmt.Fprintf(w, "  %s  %d\n", hourly.Hour.UTC().Format("2006-01-02 15:00"), hourly.Count)
+	}
+
+	fmt.Fprintln(w, "-------------------------------------------")
+	fmt.Fprintln(w, "Trigger-to-fill latency:")
+	if len(summary.Latencies) == 0 {
+		fmt.Fprintln(w, "  (none)")
+	} else {
+		var total, max time.Duration
+		for _, latency := range summary.Latencies {
+			fmt.Fprintf(w, "  %s %s  %s\n", latency.OrderID, latency.Symbol, latency.Duration)
+			total += latency.Duration
+			if latency.Duration > max {
+				max = latency.Duration
+			}
+		}
+		fmt.Fprintf(w, "  avg %s, max %s\n", total/time.Duration(len(summary.Latencies)), max)
+	}
+	if len(summary.UnfilledOrders) > 0 {
+		fmt.Fprintf(w, "  triggered without fill: %s\n", strings.Join(summary.UnfilledOrders, ", "))
+	}
+
+	fmt.Fprintln(w, "-------------------------------------------")
+	fmt.Fprintln(w, "Error clusters:")
+	if len(summary.ErrorClusters) == 0 {
+		fmt.Fprintln(w, "  (none)")
+	}
+	for _, cluster := range summary.ErrorClusters {
+		fmt.Fprintf(w, "  %dx  %s  (%s - %s)\n", cluster.Count, cluster.Key,
+			cluster.First.UTC().Format(timeLayout), cluster.Last.UTC().Format(timeLayout))
+	}
+}
+
+// describe renders a one-line description of an entry
+func describe(entry *Entry) string {
+	field := func(key string) string {
+		return fieldString(entry.Fields, key)
+	}
+
+	switch entry.Kind {
+	case EventKindOrder:
+		text := fmt.Sprintf("%s %s %s %s qty=%s", entry.Symbol, entry.EventType, field("side"), field("order_type"), field("quantity"))
+		if entry.OrderID != "" {
+			text += fmt.Sprintf(" (order %s)", entry.OrderID)
+		}
+		if status := field("status"); status != "" {
+			text += " status=" + status
+		}
+		return text
+	case EventKindTrigger:
+		text := fmt.Sprintf("%s conditional order %s triggered", entry.Symbol, entry.OrderID)
+		if price := field("trigger_price"); price != "" {
+			text += " at " + price
+		} else if value := field("trigger_value"); value != "" {
+			text += " at " + value
+		}
+		return text
+	case EventKindFill:
+		return fmt.Sprintf("%s conditional order %s executed as order %s", entry.Symbol, entry.OrderID, field("executed_order_id"))
+	case EventKindStop:
+		text := strings.TrimSpace(fmt.Sprintf("%s %s", entry.Symbol, entry.Message))
+		if entry.OrderID != "" {
+			text += fmt.Sprintf(" (order %s)", entry.OrderID)
+		}
+		return text
+	default:
+		text := entry.Message
+		if entry.Symbol != "" {
+			text = entry.Symbol + " " + text
+		}
+		if entry.Error != "" {
+			text += ": " + entry.Error
+		}
+		return text
+	}
+}
+
+// clusterKey normalizes an error entry into a cluster key
+func clusterKey(entry *Entry) string {
+	key := entry.Message
+	if entry.Error != "" {
+		key += ": " + entry.Error
+	}
+	return digitsPattern.ReplaceAllString(key, "#")
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"binance-trader/internal/api"
 	"binance-trader/pkg/errors"
+	"binance-trader/pkg/timeutil"
 	"sync"
 	"time"
 )
@@ -15,8 +16,34 @@ const (
 	ConditionalOrderStatusTriggered ConditionalOrderStatus = "TRIGGERED"
 	ConditionalOrderStatusExecuted  ConditionalOrderStatus = "EXECUTED"
 	ConditionalOrderStatusCancelled ConditionalOrderStatus = "CANCELLED"
+
+	// ConditionalOrderStatusSuspendedSymbol pauses evaluation while the symbol is not trading;
+	// the order returns to PENDING when trading resumes
+	ConditionalOrderStatusSuspendedSymbol ConditionalOrderStatus = "SUSPENDED_SYMBOL"
 )
 
+// CancelReason records why a conditional order was cancelled
+type CancelReason string
+
+const (
+	CancelReasonUser      CancelReason = "USER"       // Cancelled from the CLI
+	CancelReasonGroup     CancelReason = "GROUP"      // Cancelled because a linked order in its group filled
+	CancelReasonExpired   CancelReason = "EXPIRED"    // Its time window ended before it triggered
+	CancelReasonRiskBlock CancelReason = "RISK_BLOCK" // Risk limits the order can never pass rejected it when it triggered
+	CancelReasonBulk      CancelReason = "BULK"       // Cancelled together with other orders
+	CancelReasonShutdown  CancelReason = "SHUTDOWN"   // Still pending when the application stopped
+)
+
+// CancelReasons lists every cancel reason in report order
+var CancelReasons = []CancelReason{
+	CancelReasonUser,
+	CancelReasonGroup,
+	CancelReasonExpired,
+	CancelReasonRiskBlock,
+	CancelReasonBulk,
+	CancelReasonShutdown,
+}
+
 // TriggerType represents the type of trigger condition
 type TriggerType int
 
@@ -24,6 +51,11 @@ const (
 	TriggerTypePrice TriggerType = iota
 	TriggerTypePriceChangePercent
 	TriggerTypeVolume
+	TriggerTypeRSI
+	TriggerTypeAskBidImbalance
+	TriggerTypeMACrossover
+	TriggerTypeVolumeSurge
+	TriggerTypeTime
 )
 
 // ComparisonOperator represents comparison operators for trigger conditions
@@ -48,9 +80,13 @@ const (
 type TriggerCondition struct {
 	Type          TriggerType
 	Operator      ComparisonOperator
-	Value         float64
+	Value         float64       // For time conditions: Unix time in seconds
 	BasePrice     float64       // For price change percentage calculations
-	TimeWindow    time.Duration // For volume calculations
+	TimeWindow    time.Duration // For volume calculations; for volume surge conditions, the baseline window
+	Period        int           // For RSI conditions: number of klines the RSI is computed over
+	Interval      string        // For RSI and MA crossover conditions: kline interval, e.g. 1h; empty uses 1h
+	FastPeriod    int           // For MA crossover conditions: klines in the fast simple moving average
+	SlowPeriod    int           // For MA crossover conditions: klines in the slow simple moving average
 	CompositeType LogicOperator // For composite conditions
 	SubConditions []*TriggerCondition
 }
@@ -67,9 +103,13 @@ type ConditionalOrderRequest struct {
 	Side             api.OrderSide
 	Type             api.OrderType
 	Quantity         float64
-	Price            float64
+	Price            float64 // Limit price; limit orders without one are priced from the trigger price
+	PriceOffset      float64 // Added to the trigger price for limit orders without a price
+	OffsetPercent    bool    // PriceOffset is a percentage, e.g. 0.2 for the trigger price × 1.002
 	TriggerCondition *TriggerCondition
 	TimeWindow       *TimeWindow
+	IdempotencyKey   string // Optional; retrying with the same key returns the order created first
+	AllowDuplicate   bool   // Create even if an active order has the same symbol, side, quantity and trigger
 }
 
 // ConditionalOrder represents a conditional order
@@ -79,13 +119,19 @@ type ConditionalOrder struct {
 	Side             api.OrderSide
 	Type             api.OrderType
 	Quantity         float64
-	Price            float64
+	Price            float64 // Limit price; limit orders without one are priced from the trigger price
+	PriceOffset      float64 // Added to the trigger price for limit orders without a price
+	OffsetPercent    bool    // PriceOffset is a percentage, e.g. 0.2 for the trigger price × 1.002
 	TriggerCondition *TriggerCondition
 	Status           ConditionalOrderStatus
-	CreatedAt        int64
-	TriggeredAt      int64
+	CreatedAt        int64 // Unix ms, like every stored timestamp
+	TriggeredAt      int64 // Unix ms
 	ExecutedOrderID  int64
 	TimeWindow       *TimeWindow
+	CancelReason     CancelReason
+	CancelledBy      string // Component that cancelled the order, e.g. "cli" or "monitor"
+	CancelledAt      int64  // Unix ms
+	IdempotencyKey   string // Client-supplied key the order was created with, if any
 }
 
 // ConditionalOrderRepository defines the interface for conditional order data persistence
@@ -93,6 +139,7 @@ type ConditionalOrderRepository interface {
 	// CRUD operations
 	Save(order *ConditionalOrder) error
 	FindByID(orderID string) (*ConditionalOrder, error)
+	FindByIdempotencyKey(key string) (*ConditionalOrder, error)
 	FindBySymbol(symbol string) ([]*ConditionalOrder, error)
 	Update(order *ConditionalOrder) error
 	Delete(orderID string) error
@@ -100,10 +147,11 @@ type ConditionalOrderRepository interface {
 	// Query operations
 	FindActiveOrders() ([]*ConditionalOrder, error)
 	FindOrdersByStatus(status ConditionalOrderStatus) ([]*ConditionalOrder, error)
-	FindOrdersByTimeRange(startTime, endTime int64) ([]*ConditionalOrder, error)
+	FindOrdersByTimeRange(startTime, endTime int64) ([]*ConditionalOrder, error) // Creation time, Unix ms
 
 	// Status management
 	UpdateStatus(orderID string, newStatus ConditionalOrderStatus, triggeredAt int64, executedOrderID int64) error
+	UpdateStatusWithReason(orderID string, newStatus ConditionalOrderStatus, reason CancelReason, cancelledBy string, cancelledAt int64) error
 }
 
 // memoryConditionalOrderRepository implements ConditionalOrderRepository using in-memory storage
@@ -176,6 +224,34 @@ func (r *memoryConditionalOrderRepository) FindByID(orderID string) (*Conditiona
 	return &orderCopy, nil
 }
 
+// FindByIdempotencyKey retrieves the conditional order created with a client-supplied key
+func (r *memoryConditionalOrderRepository) FindByIdempotencyKey(key string) (*ConditionalOrder, error) {
+	if key == "" {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "idempotency key cannot be empty", 0, nil)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, order := range r.orders {
+		if order.IdempotencyKey != key {
+			continue
+		}
+		orderCopy := *order
+		if order.TriggerCondition != nil {
+			conditionCopy := *order.TriggerCondition
+			orderCopy.TriggerCondition = &conditionCopy
+		}
+		if order.TimeWindow != nil {
+			timeWindowCopy := *order.TimeWindow
+			orderCopy.TimeWindow = &timeWindowCopy
+		}
+		return &orderCopy, nil
+	}
+
+	return nil, errors.NewTradingError(errors.ErrConditionalOrderNotFound, "conditional order not found", 0, nil)
+}
+
 // FindBySymbol retrieves all conditional orders for a specific symbol
 func (r *memoryConditionalOrderRepository) FindBySymbol(symbol string) ([]*ConditionalOrder, error) {
 	if symbol == "" {
@@ -302,14 +378,26 @@ func (r *memoryConditionalOrderRepository) FindOrdersByStatus(status Conditional
 	return result, nil
 }
 
-// FindOrdersByTimeRange retrieves conditional orders within a time range
-func (r *memoryConditionalOrderRepository) FindOrdersByTimeRange(startTime, endTime int64) ([]*ConditionalOrder, error) {
-	if startTime < 0 || endTime < 0 {
-		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "time values cannot be negative", 0, nil)
+// ValidateTimeRange checks a history filter range given in Unix milliseconds, rejecting
+// negative bounds, bounds that are evidently in seconds and a start after the end
+func ValidateTimeRange(startTime, endTime int64) error {
+	if err := timeutil.ValidateMillis("start time", startTime); err != nil {
+		return errors.NewTradingError(errors.ErrInvalidParameter, err.Error(), 0, nil)
+	}
+	if err := timeutil.ValidateMillis("end time", endTime); err != nil {
+		return errors.NewTradingError(errors.ErrInvalidParameter, err.Error(), 0, nil)
 	}
-
 	if startTime > endTime {
-		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "start time cannot be after end time", 0, nil)
+		return errors.NewTradingError(errors.ErrInvalidParameter, "start time cannot be after end time", 0, nil)
+	}
+	return nil
+}
+
+// FindOrdersByTimeRange retrieves conditional orders created within a time range given in
+// Unix milliseconds, both ends inclusive
+func (r *memoryConditionalOrderRepository) FindOrdersByTimeRange(startTime, endTime int64) ([]*ConditionalOrder, error) {
+	if err := ValidateTimeRange(startTime, endTime); err != nil {
+		return nil, err
 	}
 
 	r.mu.RLock()
@@ -359,3 +447,40 @@ func (r *memoryConditionalOrderRepository) UpdateStatus(orderID string, newStatu
 
 	return nil
 }
+
+// UpdateStatusWithReason updates the status of a conditional order and records why and by whom it was cancelled
+func (r *memoryConditionalOrderRepository) UpdateStatusWithReason(orderID string, newStatus ConditionalOrderStatus, reason CancelReason, cancelledBy string, cancelledAt int64) error {
+	if orderID == "" {
+		return errors.NewTradingError(errors.ErrInvalidParameter, "order ID cannot be empty", 0, nil)
+	}
+
+	if reason == "" {
+		return errors.NewTradingError(errors.ErrInvalidParameter, "cancel reason cannot be empty", 0, nil)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, exists := r.orders[orderID]
+	if !exists {
+		return errors.NewTradingError(errors.ErrConditionalOrderNotFound, "conditional order not found", 0, nil)
+	}
+
+	order.Status = newStatus
+	order.CancelReason = reason
+	order.CancelledBy = cancelledBy
+	order.CancelledAt = cancelledAt
+
+	return nil
+}
+
+// CountCancellationsByReason counts cancelled orders per cancel reason
+func CountCancellationsByReason(orders []*ConditionalOrder) map[CancelReason]int {
+	counts := make(map[CancelReason]int)
+	for _, order := range orders {
+		if order.Status == ConditionalOrderStatusCancelled && order.CancelReason != "" {
+			counts[order.CancelReason]++
+		}
+	}
+	return counts
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"binance-trader/internal/api"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -330,13 +331,13 @@ func TestFindOrdersByStatus_FiltersCorrectly(t *testing.T) {
 func TestFindConditionalOrdersByTimeRange_FiltersCorrectly(t *testing.T) {
 	repo := NewMemoryConditionalOrderRepository()
 
-	now := time.Now().Unix()
+	now := time.Now().UnixMilli()
 
 	orders := []*ConditionalOrder{
-		{OrderID: "cond-1", Symbol: "BTCUSDT", Status: ConditionalOrderStatusPending, CreatedAt: now - 3600},
-		{OrderID: "cond-2", Symbol: "ETHUSDT", Status: ConditionalOrderStatusPending, CreatedAt: now - 1800},
+		{OrderID: "cond-1", Symbol: "BTCUSDT", Status: ConditionalOrderStatusPending, CreatedAt: now - 3600_000},
+		{OrderID: "cond-2", Symbol: "ETHUSDT", Status: ConditionalOrderStatusPending, CreatedAt: now - 1800_000},
 		{OrderID: "cond-3", Symbol: "BTCUSDT", Status: ConditionalOrderStatusPending, CreatedAt: now},
-		{OrderID: "cond-4", Symbol: "BNBUSDT", Status: ConditionalOrderStatusPending, CreatedAt: now + 3600},
+		{OrderID: "cond-4", Symbol: "BNBUSDT", Status: ConditionalOrderStatusPending, CreatedAt: now + 3600_000},
 	}
 
 	for _, order := range orders {
@@ -344,7 +345,7 @@ func TestFindConditionalOrdersByTimeRange_FiltersCorrectly(t *testing.T) {
 	}
 
 	// Find orders in range
-	rangeOrders, err := repo.FindOrdersByTimeRange(now-2000, now+1000)
+	rangeOrders, err := repo.FindOrdersByTimeRange(now-2000_000, now+1000_000)
 	if err != nil {
 		t.Errorf("FindOrdersByTimeRange() failed: %v", err)
 	}
@@ -352,6 +353,44 @@ func TestFindConditionalOrdersByTimeRange_FiltersCorrectly(t *testing.T) {
 	if len(rangeOrders) != 2 {
 		t.Errorf("Expected 2 orders in range, got %d", len(rangeOrders))
 	}
+
+	// Both bounds are inclusive, to the millisecond
+	boundary, err := repo.FindOrdersByTimeRange(now-1800_000, now)
+	if err != nil || len(boundary) != 2 {
+		t.Errorf("Expected the orders on both bounds, got %d (%v)", len(boundary), err)
+	}
+	if excluded, _ := repo.FindOrdersByTimeRange(now-1800_000+1, now-1); len(excluded) != 0 {
+		t.Errorf("Expected no orders one millisecond inside the bounds, got %d", len(excluded))
+	}
+}
+
+func TestFindConditionalOrdersByTimeRange_RejectsSeconds(t *testing.T) {
+	repo := NewMemoryConditionalOrderRepository()
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		start, end int64
+		errorMsg   string
+	}{
+		{"start in seconds", now.Unix() - 3600, now.UnixMilli(), "start time must be in Unix milliseconds"},
+		{"end in seconds", 0, now.Unix(), "end time must be in Unix milliseconds"},
+		{"negative", -1, now.UnixMilli(), "start time cannot be negative"},
+		{"reversed", now.UnixMilli(), now.UnixMilli() - 1, "start time cannot be after end time"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := repo.FindOrdersByTimeRange(tt.start, tt.end)
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errorMsg, err)
+			}
+		})
+	}
+
+	if _, err := repo.FindOrdersByTimeRange(0, now.UnixMilli()); err != nil {
+		t.Errorf("expected an open start to be accepted, got %v", err)
+	}
 }
 
 func TestUpdateStatus_UpdatesCorrectly(t *testing.T) {
@@ -431,3 +470,32 @@ func TestConcurrentAccess_ConditionalOrders(t *testing.T) {
 		}
 	}
 }
+
+func TestUpdateStatusWithReason(t *testing.T) {
+	repo := NewMemoryConditionalOrderRepository()
+	repo.Save(&ConditionalOrder{OrderID: "cond-1", Symbol: "BTCUSDT", Status: ConditionalOrderStatusPending})
+	repo.Save(&ConditionalOrder{OrderID: "cond-2", Symbol: "BTCUSDT", Status: ConditionalOrderStatusPending})
+
+	if err := repo.UpdateStatusWithReason("cond-1", ConditionalOrderStatusCancelled, CancelReasonExpired, "monitor", 1700000000); err != nil {
+		t.Fatalf("UpdateStatusWithReason() failed: %v", err)
+	}
+
+	order, _ := repo.FindByID("cond-1")
+	if order.Status != ConditionalOrderStatusCancelled || order.CancelReason != CancelReasonExpired ||
+		order.CancelledBy != "monitor" || order.CancelledAt != 1700000000 {
+		t.Errorf("expected cancellation to be recorded, got %+v", order)
+	}
+
+	if err := repo.UpdateStatusWithReason("cond-2", ConditionalOrderStatusCancelled, "", "cli", 1); err == nil {
+		t.Error("expected error for empty cancel reason")
+	}
+	if err := repo.UpdateStatusWithReason("missing", ConditionalOrderStatusCancelled, CancelReasonUser, "cli", 1); err == nil {
+		t.Error("expected error for unknown order")
+	}
+
+	repo.UpdateStatusWithReason("cond-2", ConditionalOrderStatusCancelled, CancelReasonExpired, "monitor", 1700000001)
+	orders, _ := repo.FindOrdersByStatus(ConditionalOrderStatusCancelled)
+	if counts := CountCancellationsByReason(orders); counts[CancelReasonExpired] != 2 || len(counts) != 1 {
+		t.Errorf("expected 2 expired cancellations, got %v", counts)
+	}
+}
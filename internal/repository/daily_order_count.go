@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// dailyOrderCountVersion is the current schema version of the daily order count file
+const dailyOrderCountVersion = 1
+
+// dailyOrderCountMigrator reads and writes the daily order count file
+var dailyOrderCountMigrator = NewMigrator("daily_orders", dailyOrderCountVersion)
+
+// DailyOrderCount is the number of orders placed on one UTC date
+type DailyOrderCount struct {
+	Date  string `json:"date"` // UTC date, e.g. 2024-03-01
+	Count int    `json:"count"`
+}
+
+// DailyOrderCountStore keeps the daily order count across restarts
+type DailyOrderCountStore interface {
+	// Load returns the saved count, or nil when none was saved
+	Load() (*DailyOrderCount, error)
+	Save(count *DailyOrderCount) error
+}
+
+// memoryDailyOrderCountStore keeps the daily order count for the life of the process
+type memoryDailyOrderCountStore struct {
+	mu    sync.Mutex
+	count *DailyOrderCount
+}
+
+// NewMemoryDailyOrderCountStore creates a daily order count store that does not survive a restart
+func NewMemoryDailyOrderCountStore() DailyOrderCountStore {
+	return &memoryDailyOrderCountStore{}
+}
+
+// Load returns the saved count
+func (s *memoryDailyOrderCountStore) Load() (*DailyOrderCount, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.count == nil {
+		return nil, nil
+	}
+	count := *s.count
+	return &count, nil
+}
+
+// Save replaces the saved count
+func (s *memoryDailyOrderCountStore) Save(count *DailyOrderCount) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved := *count
+	s.count = &saved
+	return nil
+}
+
+// fileDailyOrderCountStore keeps the daily order count in a small JSON file
+type fileDailyOrderCountStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileDailyOrderCountStore creates a daily order count store backed by the file at path;
+// the file and its directory are created on the first save
+func NewFileDailyOrderCountStore(path string) DailyOrderCountStore {
+	return &fileDailyOrderCountStore{path: path}
+}
+
+// Load reads the saved count; a missing file means nothing was saved yet
+func (s *fileDailyOrderCountStore) Load() (*DailyOrderCount, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open daily order count %s: %w", s.path, err)
+	}
+	defer file.Close()
+
+	var count DailyOrderCount
+	if err := dailyOrderCountMigrator.Read(file, &count); err != nil {
+		return nil, fmt.Errorf("failed to read daily order count %s: %w", s.path, err)
+	}
+	return &count, nil
+}
+
+// Save writes the count to a temporary file and renames it over the old one, so a crash
+// mid-write leaves the previous count in place
+func (s *fileDailyOrderCountStore) Save(count *DailyOrderCount) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for daily order count: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write daily order count: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := dailyOrderCountMigrator.Write(tmp, count); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write daily order count: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save daily order count: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileDailyOrderCountStore_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "daily_orders.json")
+	store := NewFileDailyOrderCountStore(path)
+
+	count, err := store.Load()
+	if err != nil || count != nil {
+		t.Fatalf("Load() before any save = %+v, %v; want nil", count, err)
+	}
+
+	if err := store.Save(&DailyOrderCount{Date: "2024-03-01", Count: 3}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := store.Save(&DailyOrderCount{Date: "2024-03-01", Count: 4}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// A new store reads what the previous process saved
+	count, err = NewFileDailyOrderCountStore(path).Load()
+	if err != nil || count == nil || count.Date != "2024-03-01" || count.Count != 4 {
+		t.Errorf("Load() = %+v, %v; want 4 on 2024-03-01", count, err)
+	}
+
+	// No temporary files are left next to the count
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("files in directory = %d, want only the count", len(entries))
+	}
+}
+
+func TestFileDailyOrderCountStore_RejectsOtherFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daily_orders.json")
+	if err := os.WriteFile(path, []byte(`{"format":"stop_orders","version":1,"data":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileDailyOrderCountStore(path).Load(); err == nil {
+		t.Error("expected an error for a file of another format")
+	}
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"binance-trader/pkg/errors"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// MigrationFunc upgrades the data of a persisted format by exactly one version
+type MigrationFunc func(data json.RawMessage) (json.RawMessage, error)
+
+// Migration describes a single upgrade step from FromVersion to FromVersion+1
+type Migration struct {
+	FromVersion int
+	Description string
+	Apply       MigrationFunc
+}
+
+// versionedEnvelope wraps every persisted payload with its format name and schema version
+type versionedEnvelope struct {
+	Format  string          `json:"format"`
+	Version int             `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Migrator owns the schema version of one persisted format and the steps that upgrade
+// older data to it. Data is always written at the current version; older data is
+// upgraded step by step at load time and newer data is rejected.
+type Migrator struct {
+	format         string
+	currentVersion int
+	migrations     map[int]Migration
+}
+
+// NewMigrator creates a migrator for a format at the given current schema version
+func NewMigrator(format string, currentVersion int) *Migrator {
+	return &Migrator{
+		format:         format,
+		currentVersion: currentVersion,
+		migrations:     make(map[int]Migration),
+	}
+}
+
+// Register adds an upgrade step; it panics on programming errors such as duplicate steps
+func (m *Migrator) Register(migration Migration) *Migrator {
+	if migration.FromVersion < 1 || migration.FromVersion >= m.currentVersion {
+		panic(fmt.Sprintf("%s: migration from version %d is outside 1..%d", m.format, migration.FromVersion, m.currentVersion-1))
+	}
+	if _, exists := m.migrations[migration.FromVersion]; exists {
+		panic(fmt.Sprintf("%s: duplicate migration from version %d", m.format, migration.FromVersion))
+	}
+	m.migrations[migration.FromVersion] = migration
+	return m
+}
+
+// Format returns the persisted format name
+func (m *Migrator) Format() string {
+	return m.format
+}
+
+// CurrentVersion returns the schema version written by this build
+func (m *Migrator) CurrentVersion() int {
+	return m.currentVersion
+}
+
+// Upgrade migrates data from the given version to the current version
+func (m *Migrator) Upgrade(version int, data json.RawMessage) (json.RawMessage, error) {
+	if version < 1 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter,
+			fmt.Sprintf("%s: invalid schema version %d", m.format, version), 0, nil)
+	}
+	if version > m.currentVersion {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter,
+			fmt.Sprintf("%s: file has schema version %d but this build supports up to version %d; downgrades are not supported, upgrade the application",
+				m.format, version, m.currentVersion), 0, nil)
+	}
+
+	for v := version; v < m.currentVersion; v++ {
+		migration, exists := m.migrations[v]
+		if !exists {
+			return nil, errors.NewTradingError(errors.ErrInvalidParameter,
+				fmt.Sprintf("%s: no migration from schema version %d to %d", m.format, v, v+1), 0, nil)
+		}
+
+		upgraded, err := migration.Apply(data)
+		if err != nil {
+			return nil, errors.NewTradingError(errors.ErrInvalidParameter,
+				fmt.Sprintf("%s: migration from version %d (%s) failed", m.format, v, migration.Description), 0, err)
+		}
+		data = upgraded
+	}
+
+	return data, nil
+}
+
+// Write encodes value at the current schema version
+func (m *Migrator) Write(w io.Writer, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("%s: failed to encode data: %w", m.format, err)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(versionedEnvelope{Format: m.format, Version: m.currentVersion, Data: data}); err != nil {
+		return fmt.Errorf("%s: failed to write data: %w", m.format, err)
+	}
+	return nil
+}
+
+// Read decodes a versioned payload into out, migrating older versions first
+func (m *Migrator) Read(r io.Reader, out interface{}) error {
+	var envelope versionedEnvelope
+	if err := json.NewDecoder(r).Decode(&envelope); err != nil {
+		return fmt.Errorf("%s: failed to read data: %w", m.format, err)
+	}
+
+	if envelope.Format != m.format {
+		return errors.NewTradingError(errors.ErrInvalidParameter,
+			fmt.Sprintf("expected %s data, got format %q", m.format, envelope.Format), 0, nil)
+	}
+
+	data, err := m.Upgrade(envelope.Version, envelope.Data)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("%s: failed to decode version %d data: %w", m.format, m.currentVersion, err)
+	}
+	return nil
+}
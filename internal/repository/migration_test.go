@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReadStopOrderSnapshot_MigratesV1(t *testing.T) {
+	file, err := os.Open("testdata/stop_orders_v1.json")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer file.Close()
+
+	orders, err := ReadStopOrderSnapshot(file)
+	if err != nil {
+		t.Fatalf("ReadStopOrderSnapshot failed: %v", err)
+	}
+
+	if len(orders) != 2 {
+		t.Fatalf("expected 2 orders, got %d", len(orders))
+	}
+	if orders[0].OrderID != "SL_1" || orders[0].Type != StopOrderTypeStopLoss || orders[0].StopPrice != 42000 {
+		t.Errorf("unexpected first order: %+v", orders[0])
+	}
+	if orders[1].Type != StopOrderTypeTakeProfit || orders[1].Status != StopOrderStatusTriggered || orders[1].ExecutedOrderID != 12345 {
+		t.Errorf("unexpected second order: %+v", orders[1])
+	}
+	if orders[0].CreatedAt != 1704110400000 || orders[1].TriggeredAt != 1704114000000 {
+		t.Errorf("expected timestamps in milliseconds, got %d and %d", orders[0].CreatedAt, orders[1].TriggeredAt)
+	}
+}
+
+func TestReadStopOrderSnapshot_MigratesV2TimestampsToMillis(t *testing.T) {
+	file, err := os.Open("testdata/stop_orders_v2.json")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer file.Close()
+
+	orders, err := ReadStopOrderSnapshot(file)
+	if err != nil {
+		t.Fatalf("ReadStopOrderSnapshot failed: %v", err)
+	}
+	if len(orders) != 2 {
+		t.Fatalf("expected 2 orders, got %d", len(orders))
+	}
+
+	// Seconds are converted; values already in milliseconds are kept
+	if orders[0].CreatedAt != 1704110400000 {
+		t.Errorf("expected created_at in seconds to be converted, got %d", orders[0].CreatedAt)
+	}
+	if orders[1].CreatedAt != 1704110400000 {
+		t.Errorf("expected created_at in milliseconds to be kept, got %d", orders[1].CreatedAt)
+	}
+	if orders[1].TriggeredAt != 1704114000000 {
+		t.Errorf("expected triggered_at in seconds to be converted, got %d", orders[1].TriggeredAt)
+	}
+	if orders[0].TriggeredAt != 0 {
+		t.Errorf("expected missing triggered_at to stay unset, got %d", orders[0].TriggeredAt)
+	}
+}
+
+func TestStopOrderSnapshot_RoundTrip(t *testing.T) {
+	orders := []*StopOrder{
+		{OrderID: "TP_1", Symbol: "ETHUSDT", Position: 2, StopPrice: 3000, Type: StopOrderTypeTakeProfit, Status: StopOrderStatusActive, CreatedAt: 1704110400000, PairID: "PAIR_1", PositionSide: "SHORT"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteStopOrderSnapshot(&buf, orders); err != nil {
+		t.Fatalf("WriteStopOrderSnapshot failed: %v", err)
+	}
+
+	var envelope versionedEnvelope
+	if err := json.Unmarshal(buf.Bytes(), &envelope); err != nil {
+		t.Fatalf("snapshot is not valid JSON: %v", err)
+	}
+	if envelope.Format != "stop_orders" || envelope.Version != stopOrderSnapshotVersion {
+		t.Errorf("expected stop_orders v%d, got %s v%d", stopOrderSnapshotVersion, envelope.Format, envelope.Version)
+	}
+	if !strings.Contains(string(envelope.Data), `"TAKE_PROFIT"`) {
+		t.Errorf("expected type to be stored by name, got %s", envelope.Data)
+	}
+
+	loaded, err := ReadStopOrderSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("ReadStopOrderSnapshot failed: %v", err)
+	}
+	if len(loaded) != 1 || *loaded[0] != *orders[0] {
+		t.Errorf("round trip mismatch: %+v", loaded)
+	}
+}
+
+func TestMigrator_UnsupportedVersions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		errorMsg string
+	}{
+		{
+			name:     "newer version",
+			input:    `{"format":"stop_orders","version":4,"data":{}}`,
+			errorMsg: "downgrades are not supported",
+		},
+		{
+			name:     "missing version",
+			input:    `{"format":"stop_orders","data":{}}`,
+			errorMsg: "invalid schema version 0",
+		},
+		{
+			name:     "wrong format",
+			input:    `{"format":"orders","version":1,"data":{}}`,
+			errorMsg: `expected stop_orders data, got format "orders"`,
+		},
+		{
+			name:     "corrupt v1 data",
+			input:    `{"format":"stop_orders","version":1,"data":{"stop_orders":[{"order_id":"X","type":"0"}]}}`,
+			errorMsg: "migration from version 1 (store stop order type by name) failed",
+		},
+		{
+			name:     "corrupt v2 timestamp",
+			input:    `{"format":"stop_orders","version":2,"data":{"stop_orders":[{"order_id":"X","type":"STOP_LOSS","created_at":"yesterday"}]}}`,
+			errorMsg: "migration from version 2 (store timestamps in milliseconds) failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadStopOrderSnapshot(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestMigrator_MissingStep(t *testing.T) {
+	migrator := NewMigrator("example", 3).Register(Migration{
+		FromVersion: 2,
+		Description: "noop",
+		Apply:       func(data json.RawMessage) (json.RawMessage, error) { return data, nil },
+	})
+
+	if _, err := migrator.Upgrade(1, json.RawMessage(`{}`)); err == nil || !strings.Contains(err.Error(), "no migration from schema version 1 to 2") {
+		t.Errorf("expected missing migration error, got %v", err)
+	}
+	if _, err := migrator.Upgrade(2, json.RawMessage(`{}`)); err != nil {
+		t.Errorf("expected upgrade from version 2 to succeed, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"binance-trader/internal/api"
 	"binance-trader/pkg/errors"
+	"sort"
 	"sync"
 )
 
@@ -18,6 +19,7 @@ type OrderRepository interface {
 	// Query operations
 	FindOpenOrders() ([]*api.Order, error)
 	FindOrdersByTimeRange(startTime, endTime int64) ([]*api.Order, error)
+	FindByOrderListID(orderListID int64) ([]*api.Order, error)
 	
 	// Sync operation
 	SyncOrderStatus(orderID int64, newStatus api.OrderStatus, executedQty float64, updateTime int64) error
@@ -136,6 +138,29 @@ func (r *memoryOrderRepository) Delete(orderID int64) error {
 	return nil
 }
 
+// FindByOrderListID retrieves the legs of an order list
+func (r *memoryOrderRepository) FindByOrderListID(orderListID int64) ([]*api.Order, error) {
+	if orderListID <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "order list ID must be greater than 0", 0, nil)
+	}
+	
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
+	var result []*api.Order
+	for _, order := range r.orders {
+		if order.OrderListID == orderListID {
+			orderCopy := *order
+			result = append(result, &orderCopy)
+		}
+	}
+	
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].OrderID < result[j].OrderID
+	})
+	return result, nil
+}
+
 // FindOpenOrders retrieves all orders with status NEW or PARTIALLY_FILLED
 func (r *memoryOrderRepository) FindOpenOrders() ([]*api.Order, error) {
 	r.mu.RLock()
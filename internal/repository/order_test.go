@@ -299,6 +299,33 @@ func TestFindOrdersByTimeRange_FiltersCorrectly(t *testing.T) {
 	}
 }
 
+func TestFindByOrderListID_ReturnsLegs(t *testing.T) {
+	repo := NewMemoryOrderRepository()
+	
+	orders := []*api.Order{
+		{OrderID: 12, Symbol: "BTCUSDT", Status: api.OrderStatusNew, OrderListID: 7},
+		{OrderID: 11, Symbol: "BTCUSDT", Status: api.OrderStatusNew, OrderListID: 7},
+		{OrderID: 13, Symbol: "BTCUSDT", Status: api.OrderStatusNew, OrderListID: -1},
+	}
+	
+	for _, order := range orders {
+		repo.Save(order)
+	}
+	
+	legs, err := repo.FindByOrderListID(7)
+	if err != nil {
+		t.Errorf("FindByOrderListID() failed: %v", err)
+	}
+	
+	if len(legs) != 2 || legs[0].OrderID != 11 || legs[1].OrderID != 12 {
+		t.Errorf("Expected legs 11 and 12, got %v", legs)
+	}
+	
+	if _, err := repo.FindByOrderListID(-1); err == nil {
+		t.Error("Expected error for a standalone order list ID")
+	}
+}
+
 func TestConcurrentAccess(t *testing.T) {
 	repo := NewMemoryOrderRepository()
 	
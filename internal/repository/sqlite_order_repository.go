@@ -0,0 +1,600 @@
+package repository
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/pkg/errors"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	_ "modernc.org/sqlite" // Registers the cgo-free "sqlite" driver
+)
+
+// Tables of the order database; every record is stored as a JSON document keyed by its ID
+const (
+	ordersTable                    = "orders"
+	conditionalOrdersTable         = "conditional_orders"
+	stopOrdersTable                = "stop_orders"
+	stopOrderPairsTable            = "stop_order_pairs"
+	trailingStopOrdersTable        = "trailing_stop_orders"
+	futuresStopOrdersTable         = "futures_stop_orders"
+	futuresStopOrderPairsTable     = "futures_stop_order_pairs"
+	futuresTrailingStopOrdersTable = "futures_trailing_stop_orders"
+)
+
+// sqliteMigrations upgrade the order database schema by one version each; PRAGMA user_version
+// holds the number of migrations a database has been through
+//
+// Version history:
+//
+//	1 - orders, conditional orders and spot and futures stop orders as JSON documents
+var sqliteMigrations = []string{
+	`CREATE TABLE orders (id TEXT PRIMARY KEY, data TEXT NOT NULL);
+	CREATE TABLE conditional_orders (id TEXT PRIMARY KEY, data TEXT NOT NULL);
+	CREATE TABLE stop_orders (id TEXT PRIMARY KEY, data TEXT NOT NULL);
+	CREATE TABLE stop_order_pairs (id TEXT PRIMARY KEY, data TEXT NOT NULL);
+	CREATE TABLE trailing_stop_orders (id TEXT PRIMARY KEY, data TEXT NOT NULL);
+	CREATE TABLE futures_stop_orders (id TEXT PRIMARY KEY, data TEXT NOT NULL);
+	CREATE TABLE futures_stop_order_pairs (id TEXT PRIMARY KEY, data TEXT NOT NULL);
+	CREATE TABLE futures_trailing_stop_orders (id TEXT PRIMARY KEY, data TEXT NOT NULL);`,
+}
+
+// SqliteOrderRepository persists orders, conditional orders and stop orders in a SQLite
+// database so they survive a restart. Reads are served from in-memory repositories loaded when
+// the database is opened. Every write reaches the database in a single transaction before it
+// returns; when the transaction fails the in-memory records are restored to what they were.
+type SqliteOrderRepository struct {
+	*sqliteOrderRepository
+	db                *sql.DB
+	conditionalOrders *sqliteConditionalOrderRepository
+	stopOrders        *sqliteStopOrderRepository
+	futuresStopOrders *sqliteStopOrderRepository
+}
+
+// NewSqliteOrderRepository opens the SQLite database at dsn, a file path or file: URI, migrates
+// its schema to the current version and loads the stored orders
+func NewSqliteOrderRepository(dsn string) (*SqliteOrderRepository, error) {
+	if dsn == "" {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "database DSN cannot be empty", 0, nil)
+	}
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open order database: %w", err)
+	}
+	// One connection serializes writers within the process and keeps :memory: databases whole
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec("PRAGMA busy_timeout = 5000"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open order database: %w", err)
+	}
+	if err := migrateSqlite(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	store := &sqliteStore{db: db}
+	repo := &SqliteOrderRepository{
+		sqliteOrderRepository: &sqliteOrderRepository{OrderRepository: NewMemoryOrderRepository(), store: store},
+		db:                    db,
+		conditionalOrders:     &sqliteConditionalOrderRepository{ConditionalOrderRepository: NewMemoryConditionalOrderRepository(), store: store},
+		stopOrders: &sqliteStopOrderRepository{
+			StopOrderRepository: NewMemoryStopOrderRepository(),
+			store:               store,
+			tables:              [...]string{stopOrdersTable, stopOrderPairsTable, trailingStopOrdersTable},
+		},
+		futuresStopOrders: &sqliteStopOrderRepository{
+			StopOrderRepository: NewMemoryStopOrderRepository(),
+			store:               store,
+			tables:              [...]string{futuresStopOrdersTable, futuresStopOrderPairsTable, futuresTrailingStopOrdersTable},
+		},
+	}
+	for _, load := range []func() error{repo.load, repo.conditionalOrders.load, repo.stopOrders.load, repo.futuresStopOrders.load} {
+		if err := load(); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return repo, nil
+}
+
+// ConditionalOrders returns the conditional orders stored in the database
+func (r *SqliteOrderRepository) ConditionalOrders() ConditionalOrderRepository {
+	return r.conditionalOrders
+}
+
+// StopOrders returns the spot stop orders stored in the database
+func (r *SqliteOrderRepository) StopOrders() StopOrderRepository {
+	return r.stopOrders
+}
+
+// FuturesStopOrders returns the futures stop orders, kept apart from the spot ones so both
+// markets can share one database
+func (r *SqliteOrderRepository) FuturesStopOrders() StopOrderRepository {
+	return r.futuresStopOrders
+}
+
+// Close closes the database
+func (r *SqliteOrderRepository) Close() error {
+	return r.db.Close()
+}
+
+// migrateSqlite applies the migrations a database has not been through yet, each in its own
+// transaction; databases written by a newer build are rejected
+func migrateSqlite(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read order database schema version: %w", err)
+	}
+	if version > len(sqliteMigrations) {
+		return errors.NewTradingError(errors.ErrInvalidParameter,
+			fmt.Sprintf("order database has schema version %d but this build supports up to version %d; downgrades are not supported, upgrade the application",
+				version, len(sqliteMigrations)), 0, nil)
+	}
+
+	for v := version; v < len(sqliteMigrations); v++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to migrate order database: %w", err)
+		}
+		if _, err := tx.Exec(sqliteMigrations[v]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to migrate order database from version %d: %w", v, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", v+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to migrate order database from version %d: %w", v, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to migrate order database from version %d: %w", v, err)
+		}
+	}
+	return nil
+}
+
+// sqliteStore writes and loads the JSON documents of the order database
+type sqliteStore struct {
+	db *sql.DB
+	mu sync.Mutex // Held across the in-memory change and its write, so both happen in the same order
+}
+
+// sqliteChange is a document to write, or to delete when record is nil
+type sqliteChange struct {
+	table  string
+	id     string
+	record interface{}
+}
+
+// write applies changes in one transaction
+func (s *sqliteStore) write(changes ...sqliteChange) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to write order database: %w", err)
+	}
+	for _, change := range changes {
+		if change.record == nil {
+			_, err = tx.Exec("DELETE FROM "+change.table+" WHERE id = ?", change.id)
+		} else {
+			var data []byte
+			if data, err = json.Marshal(change.record); err == nil {
+				_, err = tx.Exec("INSERT INTO "+change.table+" (id, data) VALUES (?, ?) "+
+					"ON CONFLICT (id) DO UPDATE SET data = excluded.data", change.id, string(data))
+			}
+		}
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to write %s %s: %w", change.table, change.id, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to write order database: %w", err)
+	}
+	return nil
+}
+
+// readAll passes every document of table to decode
+func (s *sqliteStore) readAll(table string, decode func(data []byte) error) error {
+	rows, err := s.db.Query("SELECT id, data FROM " + table)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, data string
+		if err := rows.Scan(&id, &data); err != nil {
+			return fmt.Errorf("failed to load %s: %w", table, err)
+		}
+		if err := decode([]byte(data)); err != nil {
+			return fmt.Errorf("failed to load %s %s: %w", table, id, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to load %s: %w", table, err)
+	}
+	return nil
+}
+
+// sqliteOrderRepository writes the orders of an in-memory repository through to the database
+type sqliteOrderRepository struct {
+	OrderRepository
+	store *sqliteStore
+}
+
+// load fills the in-memory repository from the database
+func (r *sqliteOrderRepository) load() error {
+	return r.store.readAll(ordersTable, func(data []byte) error {
+		var order api.Order
+		if err := json.Unmarshal(data, &order); err != nil {
+			return err
+		}
+		return r.OrderRepository.Save(&order)
+	})
+}
+
+// apply makes a change to the in-memory repository and writes the order it touched as it then
+// stands, deleting it when it is gone; when the write fails the order is restored
+func (r *sqliteOrderRepository) apply(orderID int64, change func() error) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	before, _ := r.OrderRepository.FindByID(orderID)
+	if err := change(); err != nil {
+		return err
+	}
+	write := sqliteChange{table: ordersTable, id: strconv.FormatInt(orderID, 10)}
+	if order, err := r.OrderRepository.FindByID(orderID); err == nil {
+		write.record = order
+	}
+	if err := r.store.write(write); err != nil {
+		if before != nil {
+			r.OrderRepository.Save(before)
+		} else {
+			r.OrderRepository.Delete(orderID)
+		}
+		return err
+	}
+	return nil
+}
+
+// Save stores a new order
+func (r *sqliteOrderRepository) Save(order *api.Order) error {
+	if order == nil {
+		return r.OrderRepository.Save(order)
+	}
+	return r.apply(order.OrderID, func() error { return r.OrderRepository.Save(order) })
+}
+
+// Update updates an existing order
+func (r *sqliteOrderRepository) Update(order *api.Order) error {
+	if order == nil {
+		return r.OrderRepository.Update(order)
+	}
+	return r.apply(order.OrderID, func() error { return r.OrderRepository.Update(order) })
+}
+
+// Delete removes an order by its ID
+func (r *sqliteOrderRepository) Delete(orderID int64) error {
+	return r.apply(orderID, func() error { return r.OrderRepository.Delete(orderID) })
+}
+
+// SyncOrderStatus updates the status and fill of an order
+func (r *sqliteOrderRepository) SyncOrderStatus(orderID int64, newStatus api.OrderStatus, executedQty float64, updateTime int64) error {
+	return r.apply(orderID, func() error {
+		return r.OrderRepository.SyncOrderStatus(orderID, newStatus, executedQty, updateTime)
+	})
+}
+
+// sqliteConditionalOrderRepository writes the conditional orders of an in-memory repository
+// through to the database
+type sqliteConditionalOrderRepository struct {
+	ConditionalOrderRepository
+	store *sqliteStore
+}
+
+// load fills the in-memory repository from the database
+func (r *sqliteConditionalOrderRepository) load() error {
+	return r.store.readAll(conditionalOrdersTable, func(data []byte) error {
+		var order ConditionalOrder
+		if err := json.Unmarshal(data, &order); err != nil {
+			return err
+		}
+		return r.ConditionalOrderRepository.Save(&order)
+	})
+}
+
+// apply makes a change to the in-memory repository and writes the conditional order it touched
+// as it then stands, deleting it when it is gone; when the write fails the order is restored
+func (r *sqliteConditionalOrderRepository) apply(orderID string, change func() error) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	before, _ := r.ConditionalOrderRepository.FindByID(orderID)
+	if err := change(); err != nil {
+		return err
+	}
+	write := sqliteChange{table: conditionalOrdersTable, id: orderID}
+	if order, err := r.ConditionalOrderRepository.FindByID(orderID); err == nil {
+		write.record = order
+	}
+	if err := r.store.write(write); err != nil {
+		if before != nil {
+			r.ConditionalOrderRepository.Save(before)
+		} else {
+			r.ConditionalOrderRepository.Delete(orderID)
+		}
+		return err
+	}
+	return nil
+}
+
+// Save stores a new conditional order
+func (r *sqliteConditionalOrderRepository) Save(order *ConditionalOrder) error {
+	if order == nil {
+		return r.ConditionalOrderRepository.Save(order)
+	}
+	return r.apply(order.OrderID, func() error { return r.ConditionalOrderRepository.Save(order) })
+}
+
+// Update updates an existing conditional order
+func (r *sqliteConditionalOrderRepository) Update(order *ConditionalOrder) error {
+	if order == nil {
+		return r.ConditionalOrderRepository.Update(order)
+	}
+	return r.apply(order.OrderID, func() error { return r.ConditionalOrderRepository.Update(order) })
+}
+
+// Delete removes a conditional order by its ID
+func (r *sqliteConditionalOrderRepository) Delete(orderID string) error {
+	return r.apply(orderID, func() error { return r.ConditionalOrderRepository.Delete(orderID) })
+}
+
+// UpdateStatus updates the status of a conditional order
+func (r *sqliteConditionalOrderRepository) UpdateStatus(orderID string, newStatus ConditionalOrderStatus, triggeredAt int64, executedOrderID int64) error {
+	return r.apply(orderID, func() error {
+		return r.ConditionalOrderRepository.UpdateStatus(orderID, newStatus, triggeredAt, executedOrderID)
+	})
+}
+
+// UpdateStatusWithReason updates the status of a conditional order and records why and by whom
+func (r *sqliteConditionalOrderRepository) UpdateStatusWithReason(orderID string, newStatus ConditionalOrderStatus, reason CancelReason, cancelledBy string, cancelledAt int64) error {
+	return r.apply(orderID, func() error {
+		return r.ConditionalOrderRepository.UpdateStatusWithReason(orderID, newStatus, reason, cancelledBy, cancelledAt)
+	})
+}
+
+// Kinds of stop order records, indexing sqliteStopOrderRepository.tables
+const (
+	stopOrderRecordKind = iota
+	stopOrderPairRecordKind
+	trailingStopOrderRecordKind
+)
+
+// stopOrderRecordRef names one stop order, pair or trailing stop order
+type stopOrderRecordRef struct {
+	kind int
+	id   string
+}
+
+// sqliteStopOrderRepository writes the stop orders, pairs and trailing stops of an in-memory
+// repository through to the database
+type sqliteStopOrderRepository struct {
+	StopOrderRepository
+	store  *sqliteStore
+	tables [3]string // Tables of stop orders, pairs and trailing stop orders
+}
+
+// load fills the in-memory repository from the database
+func (r *sqliteStopOrderRepository) load() error {
+	if err := r.store.readAll(r.tables[stopOrderRecordKind], func(data []byte) error {
+		var order StopOrder
+		if err := json.Unmarshal(data, &order); err != nil {
+			return err
+		}
+		return r.StopOrderRepository.SaveStopOrder(&order)
+	}); err != nil {
+		return err
+	}
+	if err := r.store.readAll(r.tables[stopOrderPairRecordKind], func(data []byte) error {
+		var pair StopOrderPair
+		if err := json.Unmarshal(data, &pair); err != nil {
+			return err
+		}
+		return r.StopOrderRepository.SaveStopOrderPair(&pair)
+	}); err != nil {
+		return err
+	}
+	return r.store.readAll(r.tables[trailingStopOrderRecordKind], func(data []byte) error {
+		var order TrailingStopOrder
+		if err := json.Unmarshal(data, &order); err != nil {
+			return err
+		}
+		return r.StopOrderRepository.SaveTrailingStopOrder(&order)
+	})
+}
+
+// current returns a record as the in-memory repository holds it, or nil when there is none
+func (r *sqliteStopOrderRepository) current(ref stopOrderRecordRef) interface{} {
+	switch ref.kind {
+	case stopOrderRecordKind:
+		if order, err := r.StopOrderRepository.FindStopOrderByID(ref.id); err == nil {
+			return order
+		}
+	case stopOrderPairRecordKind:
+		if pair, err := r.StopOrderRepository.FindStopOrderPairByID(ref.id); err == nil {
+			return pair
+		}
+	case trailingStopOrderRecordKind:
+		if order, err := r.StopOrderRepository.FindTrailingStopOrderByID(ref.id); err == nil {
+			return order
+		}
+	}
+	return nil
+}
+
+// restore puts a record returned by current back into the in-memory repository, or removes
+// the record when current returned nil
+func (r *sqliteStopOrderRepository) restore(ref stopOrderRecordRef, record interface{}) {
+	switch ref.kind {
+	case stopOrderRecordKind:
+		if order, ok := record.(*StopOrder); ok {
+			r.StopOrderRepository.SaveStopOrder(order)
+		} else {
+			r.StopOrderRepository.DeleteStopOrder(ref.id)
+		}
+	case stopOrderPairRecordKind:
+		if pair, ok := record.(*StopOrderPair); ok {
+			r.StopOrderRepository.SaveStopOrderPair(pair)
+		} else {
+			r.StopOrderRepository.DeleteStopOrderPair(ref.id)
+		}
+	case trailingStopOrderRecordKind:
+		if order, ok := record.(*TrailingStopOrder); ok {
+			r.StopOrderRepository.SaveTrailingStopOrder(order)
+		} else {
+			r.StopOrderRepository.DeleteTrailingStopOrder(ref.id)
+		}
+	}
+}
+
+// apply makes a change to the in-memory repository and writes the records it touched as they
+// then stand in one transaction; when the write fails all of them are restored
+func (r *sqliteStopOrderRepository) apply(change func() error, refs ...stopOrderRecordRef) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return r.applyLocked(change, refs...)
+}
+
+// applyLocked is apply for callers already holding the store lock
+func (r *sqliteStopOrderRepository) applyLocked(change func() error, refs ...stopOrderRecordRef) error {
+	before := make([]interface{}, len(refs))
+	for i, ref := range refs {
+		before[i] = r.current(ref)
+	}
+	if err := change(); err != nil {
+		return err
+	}
+	writes := make([]sqliteChange, len(refs))
+	for i, ref := range refs {
+		writes[i] = sqliteChange{table: r.tables[ref.kind], id: ref.id, record: r.current(ref)}
+	}
+	if err := r.store.write(writes...); err != nil {
+		for i, ref := range refs {
+			r.restore(ref, before[i])
+		}
+		return err
+	}
+	return nil
+}
+
+// SaveStopOrder stores a new stop order
+func (r *sqliteStopOrderRepository) SaveStopOrder(order *StopOrder) error {
+	if order == nil {
+		return r.StopOrderRepository.SaveStopOrder(order)
+	}
+	return r.apply(func() error { return r.StopOrderRepository.SaveStopOrder(order) },
+		stopOrderRecordRef{stopOrderRecordKind, order.OrderID})
+}
+
+// UpdateStopOrder updates an existing stop order
+func (r *sqliteStopOrderRepository) UpdateStopOrder(order *StopOrder) error {
+	if order == nil {
+		return r.StopOrderRepository.UpdateStopOrder(order)
+	}
+	return r.apply(func() error { return r.StopOrderRepository.UpdateStopOrder(order) },
+		stopOrderRecordRef{stopOrderRecordKind, order.OrderID})
+}
+
+// DeleteStopOrder removes a stop order by its ID
+func (r *sqliteStopOrderRepository) DeleteStopOrder(orderID string) error {
+	return r.apply(func() error { return r.StopOrderRepository.DeleteStopOrder(orderID) },
+		stopOrderRecordRef{stopOrderRecordKind, orderID})
+}
+
+// UpdateStopOrderStatus updates the status of a stop order
+func (r *sqliteStopOrderRepository) UpdateStopOrderStatus(orderID string, newStatus StopOrderStatus, triggeredAt int64, executedOrderID int64) error {
+	return r.apply(func() error {
+		return r.StopOrderRepository.UpdateStopOrderStatus(orderID, newStatus, triggeredAt, executedOrderID)
+	}, stopOrderRecordRef{stopOrderRecordKind, orderID})
+}
+
+// TriggerStopOrder marks a stop order triggered and writes it together with the pair legs it cancels
+func (r *sqliteStopOrderRepository) TriggerStopOrder(orderID string, triggeredAt int64) ([]*StopOrder, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Collect the records the trigger may touch while no other write can add to them
+	refs := []stopOrderRecordRef{{stopOrderRecordKind, orderID}}
+	if order, err := r.StopOrderRepository.FindStopOrderByID(orderID); err == nil && order.PairID != "" {
+		siblings, _ := r.StopOrderRepository.FindStopOrdersBySymbol(order.Symbol)
+		for _, sibling := range siblings {
+			if sibling.PairID == order.PairID && sibling.OrderID != orderID {
+				refs = append(refs, stopOrderRecordRef{stopOrderRecordKind, sibling.OrderID})
+			}
+		}
+		if _, err := r.StopOrderRepository.FindStopOrderPairByID(order.PairID); err == nil {
+			refs = append(refs, stopOrderRecordRef{stopOrderPairRecordKind, order.PairID})
+		}
+	}
+
+	var cancelled []*StopOrder
+	err := r.applyLocked(func() error {
+		var err error
+		cancelled, err = r.StopOrderRepository.TriggerStopOrder(orderID, triggeredAt)
+		return err
+	}, refs...)
+	if err != nil {
+		return nil, err
+	}
+	return cancelled, nil
+}
+
+// SaveStopOrderPair stores a new stop order pair
+func (r *sqliteStopOrderRepository) SaveStopOrderPair(pair *StopOrderPair) error {
+	if pair == nil {
+		return r.StopOrderRepository.SaveStopOrderPair(pair)
+	}
+	return r.apply(func() error { return r.StopOrderRepository.SaveStopOrderPair(pair) },
+		stopOrderRecordRef{stopOrderPairRecordKind, pair.PairID})
+}
+
+// UpdateStopOrderPair updates an existing stop order pair
+func (r *sqliteStopOrderRepository) UpdateStopOrderPair(pair *StopOrderPair) error {
+	if pair == nil {
+		return r.StopOrderRepository.UpdateStopOrderPair(pair)
+	}
+	return r.apply(func() error { return r.StopOrderRepository.UpdateStopOrderPair(pair) },
+		stopOrderRecordRef{stopOrderPairRecordKind, pair.PairID})
+}
+
+// DeleteStopOrderPair removes a stop order pair by its ID
+func (r *sqliteStopOrderRepository) DeleteStopOrderPair(pairID string) error {
+	return r.apply(func() error { return r.StopOrderRepository.DeleteStopOrderPair(pairID) },
+		stopOrderRecordRef{stopOrderPairRecordKind, pairID})
+}
+
+// SaveTrailingStopOrder stores a new trailing stop order
+func (r *sqliteStopOrderRepository) SaveTrailingStopOrder(order *TrailingStopOrder) error {
+	if order == nil {
+		return r.StopOrderRepository.SaveTrailingStopOrder(order)
+	}
+	return r.apply(func() error { return r.StopOrderRepository.SaveTrailingStopOrder(order) },
+		stopOrderRecordRef{trailingStopOrderRecordKind, order.OrderID})
+}
+
+// UpdateTrailingStopOrder updates an existing trailing stop order
+func (r *sqliteStopOrderRepository) UpdateTrailingStopOrder(order *TrailingStopOrder) error {
+	if order == nil {
+		return r.StopOrderRepository.UpdateTrailingStopOrder(order)
+	}
+	return r.apply(func() error { return r.StopOrderRepository.UpdateTrailingStopOrder(order) },
+		stopOrderRecordRef{trailingStopOrderRecordKind, order.OrderID})
+}
+
+// DeleteTrailingStopOrder removes a trailing stop order by its ID
+func (r *sqliteStopOrderRepository) DeleteTrailingStopOrder(orderID string) error {
+	return r.apply(func() error { return r.StopOrderRepository.DeleteTrailingStopOrder(orderID) },
+		stopOrderRecordRef{trailingStopOrderRecordKind, orderID})
+}
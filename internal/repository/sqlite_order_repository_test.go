@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/pkg/errors"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// openSqliteRepository opens the order database at path, failing the test on error
+func openSqliteRepository(t *testing.T, path string) *SqliteOrderRepository {
+	t.Helper()
+	repo, err := NewSqliteOrderRepository(path)
+	if err != nil {
+		t.Fatalf("NewSqliteOrderRepository() error = %v", err)
+	}
+	return repo
+}
+
+func TestSqliteOrderRepository_SurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.db")
+	repo := openSqliteRepository(t, path)
+
+	order := &api.Order{
+		OrderID: 1001, Symbol: "BTCUSDT", ClientOrderID: "bt_1001", Side: api.OrderSideBuy, Type: api.OrderTypeLimit,
+		Status: api.OrderStatusNew, Price: 50000.5, OrigQty: 0.25, Time: 1717200000000, UpdateTime: 1717200000000, OrderListID: -1,
+	}
+	conditional := &ConditionalOrder{
+		OrderID: "cond-1", Symbol: "ETHUSDT", Side: api.OrderSideSell, Type: api.OrderTypeMarket, Quantity: 2,
+		TriggerCondition: &TriggerCondition{
+			CompositeType: LogicAND,
+			SubConditions: []*TriggerCondition{
+				{Type: TriggerTypePrice, Operator: OperatorGreaterEqual, Value: 4000},
+				{Type: TriggerTypeVolume, Operator: OperatorGreaterThan, Value: 1500, TimeWindow: 5 * time.Minute},
+			},
+		},
+		Status:         ConditionalOrderStatusPending,
+		CreatedAt:      1717200000000,
+		TimeWindow:     &TimeWindow{StartTime: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), EndTime: time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)},
+		IdempotencyKey: "key-1",
+	}
+	stopLoss := &StopOrder{OrderID: "sl-1", Symbol: "BTCUSDT", Position: 0.25, StopPrice: 48000, Type: StopOrderTypeStopLoss, Status: StopOrderStatusActive, CreatedAt: 1717200000000}
+	takeProfit := &StopOrder{OrderID: "tp-1", Symbol: "BTCUSDT", Position: 0.25, StopPrice: 55000, Type: StopOrderTypeTakeProfit, Status: StopOrderStatusActive, CreatedAt: 1717200000000}
+	pair := &StopOrderPair{PairID: "pair-1", Symbol: "BTCUSDT", Position: 0.25, StopLossOrder: stopLoss, TakeProfitOrder: takeProfit, Status: "ACTIVE"}
+	trailing := &TrailingStopOrder{
+		OrderID: "trail-1", Symbol: "BTCUSDT", Position: 0.1, TrailPercent: 2, HighestPrice: 51000, CurrentStopPrice: 49980,
+		Status: StopOrderStatusActive, CreatedAt: 1717200000000, LastUpdatedAt: 1717200060000,
+		TrailMode: TrailModeATR, ATRPeriod: 14, ATRMultiplier: 2.5, ATRInterval: "1h", ATR: 408, LastCandleClose: 1717199999999,
+	}
+	futuresStop := &StopOrder{OrderID: "fsl-1", Symbol: "BTCUSDT", Position: 1, StopPrice: 47000, Type: StopOrderTypeStopLoss, Status: StopOrderStatusActive, CreatedAt: 1717200000000}
+
+	steps := []error{
+		repo.Save(order),
+		repo.Save(&api.Order{OrderID: 1002, Symbol: "BTCUSDT", Status: api.OrderStatusNew, Time: 1717200000000}),
+		repo.SyncOrderStatus(1001, api.OrderStatusPartiallyFilled, 0.1, 1717200030000),
+		repo.Delete(1002),
+		repo.ConditionalOrders().Save(conditional),
+		repo.ConditionalOrders().Save(&ConditionalOrder{OrderID: "cond-2", Symbol: "ETHUSDT", Status: ConditionalOrderStatusPending}),
+		repo.ConditionalOrders().UpdateStatusWithReason("cond-2", ConditionalOrderStatusCancelled, CancelReasonUser, "cli", 1717200040000),
+		repo.StopOrders().SaveStopOrder(stopLoss),
+		repo.StopOrders().SaveStopOrder(takeProfit),
+		repo.StopOrders().SaveStopOrderPair(pair),
+		repo.StopOrders().UpdateStopOrderStatus("tp-1", StopOrderStatusTriggered, 1717200050000, 9001),
+		repo.StopOrders().SaveTrailingStopOrder(trailing),
+		repo.FuturesStopOrders().SaveStopOrder(futuresStop),
+	}
+	for i, err := range steps {
+		if err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+	if err := repo.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Everything written before the restart is read back with identical fields
+	repo = openSqliteRepository(t, path)
+	defer repo.Close()
+
+	order.Status, order.ExecutedQty, order.UpdateTime = api.OrderStatusPartiallyFilled, 0.1, 1717200030000
+	if got, err := repo.FindByID(1001); err != nil || !reflect.DeepEqual(got, order) {
+		t.Errorf("FindByID(1001) = %+v, %v; want %+v", got, err, order)
+	}
+	if _, err := repo.FindByID(1002); err == nil {
+		t.Error("deleted order 1002 came back after the restart")
+	}
+	if open, _ := repo.FindOpenOrders(); len(open) != 1 {
+		t.Errorf("FindOpenOrders() returned %d orders, want 1", len(open))
+	}
+
+	if got, err := repo.ConditionalOrders().FindByIdempotencyKey("key-1"); err != nil || !reflect.DeepEqual(got, conditional) {
+		t.Errorf("FindByIdempotencyKey(key-1) = %+v, %v; want %+v", got, err, conditional)
+	}
+	if cancelled, err := repo.ConditionalOrders().FindByID("cond-2"); err != nil ||
+		cancelled.Status != ConditionalOrderStatusCancelled || cancelled.CancelReason != CancelReasonUser || cancelled.CancelledBy != "cli" {
+		t.Errorf("FindByID(cond-2) = %+v, %v; want the cancellation recorded", cancelled, err)
+	}
+	if active, _ := repo.ConditionalOrders().FindActiveOrders(); len(active) != 1 || active[0].OrderID != "cond-1" {
+		t.Errorf("FindActiveOrders() = %v, want [cond-1]", active)
+	}
+
+	if got, err := repo.StopOrders().FindStopOrderPairByID("pair-1"); err != nil || !reflect.DeepEqual(got, pair) {
+		t.Errorf("FindStopOrderPairByID(pair-1) = %+v, %v; want %+v", got, err, pair)
+	}
+	if got, err := repo.StopOrders().FindStopOrderByID("tp-1"); err != nil ||
+		got.Status != StopOrderStatusTriggered || got.TriggeredAt != 1717200050000 || got.ExecutedOrderID != 9001 {
+		t.Errorf("FindStopOrderByID(tp-1) = %+v, %v; want triggered by order 9001", got, err)
+	}
+	if got, err := repo.StopOrders().FindTrailingStopOrderByID("trail-1"); err != nil || !reflect.DeepEqual(got, trailing) {
+		t.Errorf("FindTrailingStopOrderByID(trail-1) = %+v, %v; want %+v", got, err, trailing)
+	}
+
+	// Spot and futures stop orders share the database but not their tables
+	if _, err := repo.StopOrders().FindStopOrderByID("fsl-1"); err == nil {
+		t.Error("futures stop order fsl-1 is listed with the spot stop orders")
+	}
+	if got, err := repo.FuturesStopOrders().FindStopOrderByID("fsl-1"); err != nil || !reflect.DeepEqual(got, futuresStop) {
+		t.Errorf("FuturesStopOrders().FindStopOrderByID(fsl-1) = %+v, %v; want %+v", got, err, futuresStop)
+	}
+}
+
+func TestSqliteOrderRepository_RejectedWritesAreNotStored(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.db")
+	repo := openSqliteRepository(t, path)
+
+	err := repo.Update(&api.Order{OrderID: 42, Symbol: "BTCUSDT"})
+	if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrOrderNotFound {
+		t.Errorf("Update() of an unknown order error = %v, want ErrOrderNotFound", err)
+	}
+	if err := repo.ConditionalOrders().Save(&ConditionalOrder{}); err == nil {
+		t.Error("Save() of a conditional order without ID expected an error")
+	}
+	repo.Close()
+
+	repo = openSqliteRepository(t, path)
+	defer repo.Close()
+	if _, err := repo.FindByID(42); err == nil {
+		t.Error("order 42 was stored although its update was rejected")
+	}
+}
+
+func TestSqliteOrderRepository_WriteFailureRevertsMemory(t *testing.T) {
+	repo := openSqliteRepository(t, filepath.Join(t.TempDir(), "orders.db"))
+	defer repo.Close()
+
+	if err := repo.StopOrders().SaveStopOrder(&StopOrder{OrderID: "sl-1", Symbol: "BTCUSDT", StopPrice: 48000, Status: StopOrderStatusActive}); err != nil {
+		t.Fatalf("SaveStopOrder() error = %v", err)
+	}
+	// Writes to a dropped table fail; the change must not linger in memory
+	if _, err := repo.db.Exec("ALTER TABLE stop_orders RENAME TO stop_orders_gone"); err != nil {
+		t.Fatalf("rename table: %v", err)
+	}
+	if err := repo.StopOrders().UpdateStopOrderStatus("sl-1", StopOrderStatusTriggered, 1717200000000, 7); err == nil {
+		t.Fatal("UpdateStopOrderStatus() expected an error when the write fails")
+	}
+	if _, err := repo.db.Exec("ALTER TABLE stop_orders_gone RENAME TO stop_orders"); err != nil {
+		t.Fatalf("rename table back: %v", err)
+	}
+
+	if got, err := repo.StopOrders().FindStopOrderByID("sl-1"); err != nil || got.Status != StopOrderStatusActive {
+		t.Errorf("FindStopOrderByID(sl-1) = %+v, %v; want the order still active", got, err)
+	}
+}
+
+func TestSqliteOrderRepository_TriggerStopOrderPersistsPair(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.db")
+	repo := openSqliteRepository(t, path)
+
+	stopLoss := &StopOrder{OrderID: "sl-1", Symbol: "BTCUSDT", Position: 1, StopPrice: 48000, Type: StopOrderTypeStopLoss, Status: StopOrderStatusActive, PairID: "pair-1"}
+	takeProfit := &StopOrder{OrderID: "tp-1", Symbol: "BTCUSDT", Position: 1, StopPrice: 55000, Type: StopOrderTypeTakeProfit, Status: StopOrderStatusActive, PairID: "pair-1"}
+	for _, err := range []error{
+		repo.StopOrders().SaveStopOrder(stopLoss),
+		repo.StopOrders().SaveStopOrder(takeProfit),
+		repo.StopOrders().SaveStopOrderPair(&StopOrderPair{PairID: "pair-1", Symbol: "BTCUSDT", Position: 1, StopLossOrder: stopLoss, TakeProfitOrder: takeProfit, Status: "ACTIVE"}),
+	} {
+		if err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+	cancelled, err := repo.StopOrders().TriggerStopOrder("sl-1", 1717200000000)
+	if err != nil || len(cancelled) != 1 || cancelled[0].OrderID != "tp-1" {
+		t.Fatalf("TriggerStopOrder(sl-1) = %v, %v; want tp-1 cancelled", cancelled, err)
+	}
+	repo.Close()
+
+	// The other leg stays cancelled after a restart, so it cannot trigger as well
+	repo = openSqliteRepository(t, path)
+	defer repo.Close()
+	if got, err := repo.StopOrders().FindStopOrderByID("sl-1"); err != nil || got.Status != StopOrderStatusTriggered || got.TriggeredAt != 1717200000000 {
+		t.Errorf("FindStopOrderByID(sl-1) = %+v, %v; want triggered", got, err)
+	}
+	if got, err := repo.StopOrders().FindStopOrderByID("tp-1"); err != nil || got.Status != StopOrderStatusCancelled {
+		t.Errorf("FindStopOrderByID(tp-1) = %+v, %v; want cancelled", got, err)
+	}
+	if got, err := repo.StopOrders().FindStopOrderPairByID("pair-1"); err != nil || got.Status != "COMPLETED" {
+		t.Errorf("FindStopOrderPairByID(pair-1) = %+v, %v; want completed", got, err)
+	}
+	if _, err := repo.StopOrders().TriggerStopOrder("tp-1", 1717200001000); err == nil {
+		t.Error("TriggerStopOrder(tp-1) after the restart expected an error")
+	}
+}
+
+func TestSqliteOrderRepository_SchemaVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.db")
+	openSqliteRepository(t, path).Close()
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil || version != len(sqliteMigrations) {
+		t.Errorf("schema version = %d, %v; want %d", version, err, len(sqliteMigrations))
+	}
+
+	// A database migrated by a newer build is refused rather than misread
+	if _, err := db.Exec("PRAGMA user_version = 99"); err != nil {
+		t.Fatalf("set user_version: %v", err)
+	}
+	db.Close()
+	if _, err := NewSqliteOrderRepository(path); err == nil {
+		t.Error("NewSqliteOrderRepository() of a newer schema expected an error")
+	}
+
+	if _, err := NewSqliteOrderRepository(""); err == nil {
+		t.Error("NewSqliteOrderRepository(\"\") expected an error")
+	}
+}
@@ -20,6 +20,10 @@ const (
 	StopOrderStatusActive    StopOrderStatus = "ACTIVE"
 	StopOrderStatusTriggered StopOrderStatus = "TRIGGERED"
 	StopOrderStatusCancelled StopOrderStatus = "CANCELLED"
+
+	// StopOrderStatusSuspendedSymbol pauses the order while the symbol is not trading; it
+	// returns to ACTIVE when trading resumes
+	StopOrderStatusSuspendedSymbol StopOrderStatus = "SUSPENDED_SYMBOL"
 )
 
 // StopOrder represents a stop loss or take profit order
@@ -30,9 +34,11 @@ type StopOrder struct {
 	StopPrice       float64
 	Type            StopOrderType
 	Status          StopOrderStatus
-	CreatedAt       int64
-	TriggeredAt     int64
+	CreatedAt       int64 // Unix ms
+	TriggeredAt     int64 // Unix ms
 	ExecutedOrderID int64
+	PairID          string // Set on both legs of a stop loss / take profit pair
+	PositionSide    string // LONG or SHORT for futures; empty for spot
 }
 
 // StopOrderPair represents a paired stop loss and take profit order
@@ -45,17 +51,38 @@ type StopOrderPair struct {
 	Status          string // ACTIVE, PARTIALLY_TRIGGERED, COMPLETED
 }
 
+// TrailMode selects how the trail distance of a trailing stop is determined
+type TrailMode string
+
+const (
+	TrailModePercent TrailMode = "PERCENT" // Fixed trail percent; the zero value means the same
+	TrailModeATR     TrailMode = "ATR"     // Trail follows the Average True Range of recent klines
+)
+
 // TrailingStopOrder represents a trailing stop loss order
 type TrailingStopOrder struct {
 	OrderID          string
 	Symbol           string
 	Position         float64
-	TrailPercent     float64
+	TrailPercent     float64 // Effective trail; recomputed on each candle close in ATR mode
 	HighestPrice     float64
 	CurrentStopPrice float64
 	Status           StopOrderStatus
-	CreatedAt        int64
-	LastUpdatedAt    int64
+	CreatedAt        int64 // Unix ms
+	LastUpdatedAt    int64 // Unix ms
+
+	// Futures: HighestPrice holds the lowest price of a SHORT trailing stop, and a native trailing
+	// stop is tracked by the exchange as ExchangeOrderID instead of locally
+	PositionSide    string // LONG or SHORT; empty for spot
+	ExchangeOrderID int64
+
+	// ATR mode: the trail is ATRMultiplier × ATR(ATRPeriod) of ATRInterval klines
+	TrailMode       TrailMode
+	ATRPeriod       int
+	ATRMultiplier   float64
+	ATRInterval     string
+	ATR             float64 // ATR behind the effective trail
+	LastCandleClose int64   // Close time (ms) of the newest candle the trail was computed from
 }
 
 // StopOrderRepository defines the interface for stop order data persistence
@@ -74,6 +101,11 @@ type StopOrderRepository interface {
 	// Stop order status management
 	UpdateStopOrderStatus(orderID string, newStatus StopOrderStatus, triggeredAt int64, executedOrderID int64) error
 
+	// TriggerStopOrder marks an active stop order triggered and, in the same critical section,
+	// cancels the active orders of its pair, so the other leg can never trigger as well.
+	// It returns the cancelled orders, or ErrOrderAlreadyTriggered when the order is not active.
+	TriggerStopOrder(orderID string, triggeredAt int64) ([]*StopOrder, error)
+
 	// Stop order pair CRUD operations
 	SaveStopOrderPair(pair *StopOrderPair) error
 	FindStopOrderPairByID(pairID string) (*StopOrderPair, error)
@@ -274,6 +306,56 @@ func (r *memoryStopOrderRepository) UpdateStopOrderStatus(orderID string, newSta
 	return nil
 }
 
+// TriggerStopOrder marks an active stop order triggered and cancels the rest of its pair
+func (r *memoryStopOrderRepository) TriggerStopOrder(orderID string, triggeredAt int64) ([]*StopOrder, error) {
+	if orderID == "" {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "order ID cannot be empty", 0, nil)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, exists := r.stopOrders[orderID]
+	if !exists {
+		return nil, errors.NewTradingError(errors.ErrStopOrderNotFound, "stop order not found", 0, nil)
+	}
+	if order.Status != StopOrderStatusActive {
+		return nil, errors.NewTradingError(errors.ErrOrderAlreadyTriggered, "stop order is not active", 0, nil)
+	}
+
+	order.Status = StopOrderStatusTriggered
+	order.TriggeredAt = triggeredAt
+	if order.PairID == "" {
+		return nil, nil
+	}
+
+	var cancelled []*StopOrder
+	for _, sibling := range r.stopOrders {
+		if sibling.PairID != order.PairID || sibling.OrderID == orderID || sibling.Status != StopOrderStatusActive {
+			continue
+		}
+		sibling.Status = StopOrderStatusCancelled
+		siblingCopy := *sibling
+		cancelled = append(cancelled, &siblingCopy)
+	}
+
+	// Keep the stored pair in step with its legs
+	if pair, exists := r.stopOrderPairs[order.PairID]; exists {
+		pair.Status = "COMPLETED"
+		for _, leg := range []*StopOrder{pair.StopLossOrder, pair.TakeProfitOrder} {
+			if leg == nil {
+				continue
+			}
+			if stored, exists := r.stopOrders[leg.OrderID]; exists {
+				leg.Status = stored.Status
+				leg.TriggeredAt = stored.TriggeredAt
+			}
+		}
+	}
+
+	return cancelled, nil
+}
+
 // SaveStopOrderPair stores a new stop order pair
 func (r *memoryStopOrderRepository) SaveStopOrderPair(pair *StopOrderPair) error {
 	if pair == nil {
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"binance-trader/pkg/timeutil"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// stopOrderSnapshotVersion is the current schema version of stop order snapshots
+//
+// Version history:
+//
+//	1 - stop order type stored as its numeric enum value
+//	2 - stop order type stored by name so reordering the enum cannot corrupt saved orders
+//	3 - timestamps stored in Unix milliseconds; earlier builds wrote seconds
+const stopOrderSnapshotVersion = 3
+
+// stopOrderSnapshotMigrator upgrades stop order snapshots to the current version
+var stopOrderSnapshotMigrator = NewMigrator("stop_orders", stopOrderSnapshotVersion).
+	Register(Migration{FromVersion: 1, Description: "store stop order type by name", Apply: migrateStopOrdersV1ToV2}).
+	Register(Migration{FromVersion: 2, Description: "store timestamps in milliseconds", Apply: migrateStopOrdersV2ToV3})
+
+// stopOrderTypeNames maps stop order types to their persisted names
+var stopOrderTypeNames = map[StopOrderType]string{
+	StopOrderTypeStopLoss:   "STOP_LOSS",
+	StopOrderTypeTakeProfit: "TAKE_PROFIT",
+}
+
+// stopOrderSnapshot is the persisted form of all stop orders
+type stopOrderSnapshot struct {
+	StopOrders []stopOrderRecord `json:"stop_orders"`
+}
+
+// stopOrderRecord is the persisted form of a stop order
+type stopOrderRecord struct {
+	OrderID         string          `json:"order_id"`
+	Symbol          string          `json:"symbol"`
+	Position        float64         `json:"position"`
+	StopPrice       float64         `json:"stop_price"`
+	Type            string          `json:"type"`
+	Status          StopOrderStatus `json:"status"`
+	CreatedAt       int64           `json:"created_at"`
+	TriggeredAt     int64           `json:"triggered_at,omitempty"`
+	ExecutedOrderID int64           `json:"executed_order_id,omitempty"`
+	PairID          string          `json:"pair_id,omitempty"`
+	PositionSide    string          `json:"position_side,omitempty"`
+}
+
+// WriteStopOrderSnapshot writes stop orders in the current snapshot format
+func WriteStopOrderSnapshot(w io.Writer, orders []*StopOrder) error {
+	snapshot := stopOrderSnapshot{StopOrders: make([]stopOrderRecord, 0, len(orders))}
+	for _, order := range orders {
+		typeName, exists := stopOrderTypeNames[order.Type]
+		if !exists {
+			return fmt.Errorf("stop order %s has unknown type %d", order.OrderID, order.Type)
+		}
+		snapshot.StopOrders = append(snapshot.StopOrders, stopOrderRecord{
+			OrderID:         order.OrderID,
+			Symbol:          order.Symbol,
+			Position:        order.Position,
+			StopPrice:       order.StopPrice,
+			Type:            typeName,
+			Status:          order.Status,
+			CreatedAt:       order.CreatedAt,
+			TriggeredAt:     order.TriggeredAt,
+			ExecutedOrderID: order.ExecutedOrderID,
+			PairID:          order.PairID,
+			PositionSide:    order.PositionSide,
+		})
+	}
+	return stopOrderSnapshotMigrator.Write(w, snapshot)
+}
+
+// ReadStopOrderSnapshot reads stop orders, upgrading snapshots written by older versions
+func ReadStopOrderSnapshot(r io.Reader) ([]*StopOrder, error) {
+	var snapshot stopOrderSnapshot
+	if err := stopOrderSnapshotMigrator.Read(r, &snapshot); err != nil {
+		return nil, err
+	}
+
+	orders := make([]*StopOrder, 0, len(snapshot.StopOrders))
+	for _, record := range snapshot.StopOrders {
+		orderType, err := parseStopOrderType(record.Type)
+		if err != nil {
+			return nil, fmt.Errorf("stop order %s: %w", record.OrderID, err)
+		}
+		orders = append(orders, &StopOrder{
+			OrderID:         record.OrderID,
+			Symbol:          record.Symbol,
+			Position:        record.Position,
+			StopPrice:       record.StopPrice,
+			Type:            orderType,
+			Status:          record.Status,
+			CreatedAt:       record.CreatedAt,
+			TriggeredAt:     record.TriggeredAt,
+			ExecutedOrderID: record.ExecutedOrderID,
+			PairID:          record.PairID,
+			PositionSide:    record.PositionSide,
+		})
+	}
+	return orders, nil
+}
+
+// parseStopOrderType converts a persisted type name back to a stop order type
+func parseStopOrderType(name string) (StopOrderType, error) {
+	for orderType, typeName := range stopOrderTypeNames {
+		if typeName == name {
+			return orderType, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown stop order type %q", name)
+}
+
+// migrateStopOrdersV1ToV2 replaces numeric stop order types with their names
+func migrateStopOrdersV1ToV2(data json.RawMessage) (json.RawMessage, error) {
+	var snapshot struct {
+		StopOrders []map[string]interface{} `json:"stop_orders"`
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	// Version 1 enum order: 0 = stop loss, 1 = take profit
+	v1Types := map[float64]string{0: "STOP_LOSS", 1: "TAKE_PROFIT"}
+	for _, record := range snapshot.StopOrders {
+		numeric, ok := record["type"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("stop order %v: expected numeric type, got %v", record["order_id"], record["type"])
+		}
+		name, exists := v1Types[numeric]
+		if !exists {
+			return nil, fmt.Errorf("stop order %v: unknown type %v", record["order_id"], numeric)
+		}
+		record["type"] = name
+	}
+
+	return json.Marshal(snapshot)
+}
+
+// migrateStopOrdersV2ToV3 converts timestamps to milliseconds. Version 2 files were written
+// by builds that stored seconds, but orders restored from exchange data already carried
+// milliseconds, so each value is converted only when its magnitude shows it is in seconds.
+func migrateStopOrdersV2ToV3(data json.RawMessage) (json.RawMessage, error) {
+	var snapshot struct {
+		StopOrders []map[string]json.RawMessage `json:"stop_orders"`
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	for _, record := range snapshot.StopOrders {
+		for _, field := range []string{"created_at", "triggered_at"} {
+			raw, exists := record[field]
+			if !exists {
+				continue
+			}
+			var ts int64
+			if err := json.Unmarshal(raw, &ts); err != nil {
+				return nil, fmt.Errorf("stop order %s: invalid %s %s", record["order_id"], field, raw)
+			}
+			record[field] = json.RawMessage(fmt.Sprint(timeutil.NormalizeMillis(ts)))
+		}
+	}
+
+	return json.Marshal(snapshot)
+}
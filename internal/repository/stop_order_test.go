@@ -305,6 +305,67 @@ func TestStopOrderRepository_UpdateStatus(t *testing.T) {
 	}
 }
 
+// TestStopOrderRepository_TriggerStopOrder tests that triggering a leg cancels its pair
+func TestStopOrderRepository_TriggerStopOrder(t *testing.T) {
+	repo := NewMemoryStopOrderRepository()
+
+	stopLoss := &StopOrder{OrderID: "sl-001", Symbol: "BTCUSDT", Position: 1.0, StopPrice: 45000.0, Type: StopOrderTypeStopLoss, Status: StopOrderStatusActive, PairID: "pair-001"}
+	takeProfit := &StopOrder{OrderID: "tp-001", Symbol: "BTCUSDT", Position: 1.0, StopPrice: 55000.0, Type: StopOrderTypeTakeProfit, Status: StopOrderStatusActive, PairID: "pair-001"}
+	unpaired := &StopOrder{OrderID: "tp-002", Symbol: "BTCUSDT", Position: 1.0, StopPrice: 56000.0, Type: StopOrderTypeTakeProfit, Status: StopOrderStatusActive}
+	for _, order := range []*StopOrder{stopLoss, takeProfit, unpaired} {
+		if err := repo.SaveStopOrder(order); err != nil {
+			t.Fatalf("SaveStopOrder failed: %v", err)
+		}
+	}
+	pair := &StopOrderPair{PairID: "pair-001", Symbol: "BTCUSDT", Position: 1.0, StopLossOrder: stopLoss, TakeProfitOrder: takeProfit, Status: "ACTIVE"}
+	if err := repo.SaveStopOrderPair(pair); err != nil {
+		t.Fatalf("SaveStopOrderPair failed: %v", err)
+	}
+
+	cancelled, err := repo.TriggerStopOrder("sl-001", 1704110400000)
+	if err != nil {
+		t.Fatalf("TriggerStopOrder failed: %v", err)
+	}
+	if len(cancelled) != 1 || cancelled[0].OrderID != "tp-001" || cancelled[0].Status != StopOrderStatusCancelled {
+		t.Fatalf("Expected the take profit leg to be cancelled, got %+v", cancelled)
+	}
+
+	found, _ := repo.FindStopOrderByID("sl-001")
+	if found.Status != StopOrderStatusTriggered || found.TriggeredAt != 1704110400000 {
+		t.Errorf("Expected stop loss TRIGGERED at 1704110400000, got %s at %d", found.Status, found.TriggeredAt)
+	}
+	found, _ = repo.FindStopOrderByID("tp-001")
+	if found.Status != StopOrderStatusCancelled {
+		t.Errorf("Expected take profit CANCELLED, got %s", found.Status)
+	}
+	found, _ = repo.FindStopOrderByID("tp-002")
+	if found.Status != StopOrderStatusActive {
+		t.Errorf("Expected the unpaired order to stay ACTIVE, got %s", found.Status)
+	}
+
+	storedPair, _ := repo.FindStopOrderPairByID("pair-001")
+	if storedPair.Status != "COMPLETED" || storedPair.StopLossOrder.Status != StopOrderStatusTriggered || storedPair.TakeProfitOrder.Status != StopOrderStatusCancelled {
+		t.Errorf("Expected a completed pair, got %s (%s / %s)", storedPair.Status, storedPair.StopLossOrder.Status, storedPair.TakeProfitOrder.Status)
+	}
+
+	// Neither leg can trigger again
+	for _, orderID := range []string{"sl-001", "tp-001"} {
+		_, err := repo.TriggerStopOrder(orderID, 1704110401000)
+		if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrOrderAlreadyTriggered {
+			t.Errorf("TriggerStopOrder(%s) error = %v, want ErrOrderAlreadyTriggered", orderID, err)
+		}
+	}
+
+	cancelled, err = repo.TriggerStopOrder("tp-002", 1704110402000)
+	if err != nil || len(cancelled) != 0 {
+		t.Errorf("TriggerStopOrder of an unpaired order = %v, %v", cancelled, err)
+	}
+
+	if _, err := repo.TriggerStopOrder("missing", 1704110400000); err == nil {
+		t.Error("Expected an error for an unknown order")
+	}
+}
+
 // TestStopOrderPairRepository_SaveAndFind tests saving and finding stop order pairs
 func TestStopOrderPairRepository_SaveAndFind(t *testing.T) {
 	repo := NewMemoryStopOrderRepository()
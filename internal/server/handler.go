@@ -0,0 +1,426 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"binance-trader/internal/api"
+	"binance-trader/internal/metrics"
+	"binance-trader/internal/repository"
+	"binance-trader/internal/service"
+	"binance-trader/pkg/errors"
+	"binance-trader/pkg/logger"
+)
+
+// maxBodyBytes bounds request bodies; every request fits in a few hundred bytes
+const maxBodyBytes = 1 << 20
+
+// cancelledBy records API cancellations on conditional orders, next to "cli" and "monitor"
+const cancelledBy = "api"
+
+// handler serves the trading API on top of the services the CLI uses
+type handler struct {
+	trading     service.TradingService
+	market      service.MarketDataService
+	conditional service.ConditionalOrderService
+	stopLoss    service.StopLossService
+	logger      logger.Logger
+}
+
+// NewHandler returns the trading API:
+//
+//	POST   /orders/market-buy           {"symbol", "quantity"}
+//	POST   /orders/market-sell          {"symbol", "quantity"}
+//	POST   /orders/limit-buy            {"symbol", "price", "quantity"}
+//	POST   /orders/limit-sell           {"symbol", "price", "quantity"}
+//	GET    /orders/open
+//	DELETE /orders/{id}
+//	POST   /conditional-orders          {"symbol", "side", "quantity", "trigger": {"type", "operator", "value"}}
+//	GET    /conditional-orders
+//	DELETE /conditional-orders/{id}
+//	POST   /stop-loss                   {"symbol", "quantity", "stop_price"}
+//	GET    /stop-orders/{symbol}
+//	GET    /price/{symbol}
+//	GET    /metrics                     Prometheus text format, when registry is not nil
+//
+// Every request must carry "Authorization: Bearer <token>".
+func NewHandler(
+	token string,
+	trading service.TradingService,
+	market service.MarketDataService,
+	conditional service.ConditionalOrderService,
+	stopLoss service.StopLossService,
+	registry *metrics.Registry,
+	log logger.Logger,
+) http.Handler {
+	h := &handler{
+		trading:     trading,
+		market:      market,
+		conditional: conditional,
+		stopLoss:    stopLoss,
+		logger:      log,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orders/market-buy", methods(map[string]http.HandlerFunc{http.MethodPost: h.handleMarketBuy}))
+	mux.HandleFunc("/orders/market-sell", methods(map[string]http.HandlerFunc{http.MethodPost: h.handleMarketSell}))
+	mux.HandleFunc("/orders/limit-buy", methods(map[string]http.HandlerFunc{http.MethodPost: h.handleLimitBuy}))
+	mux.HandleFunc("/orders/limit-sell", methods(map[string]http.HandlerFunc{http.MethodPost: h.handleLimitSell}))
+	mux.HandleFunc("/orders/open", methods(map[string]http.HandlerFunc{http.MethodGet: h.handleOpenOrders}))
+	mux.HandleFunc("/orders/", methods(map[string]http.HandlerFunc{http.MethodDelete: h.handleCancelOrder}))
+	mux.HandleFunc("/conditional-orders", methods(map[string]http.HandlerFunc{
+		http.MethodPost: h.handleCreateConditionalOrder,
+		http.MethodGet:  h.handleConditionalOrders,
+	}))
+	mux.HandleFunc("/conditional-orders/", methods(map[string]http.HandlerFunc{http.MethodDelete: h.handleCancelConditionalOrder}))
+	mux.HandleFunc("/stop-loss", methods(map[string]http.HandlerFunc{http.MethodPost: h.handleStopLoss}))
+	mux.HandleFunc("/stop-orders/", methods(map[string]http.HandlerFunc{http.MethodGet: h.handleStopOrders}))
+	mux.HandleFunc("/price/", methods(map[string]http.HandlerFunc{http.MethodGet: h.handlePrice}))
+	if registry != nil {
+		mux.HandleFunc(metrics.Path, methods(map[string]http.HandlerFunc{http.MethodGet: registry.Handler().ServeHTTP}))
+	}
+
+	return requireToken(token, log, mux)
+}
+
+// requireToken rejects requests that do not present the bearer token
+func requireToken(token string, log logger.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			log.Warn("Rejected API request without a valid token", map[string]interface{}{
+				"method":      r.Method,
+				"path":        r.URL.Path,
+				"remote_addr": r.RemoteAddr,
+			})
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "missing or invalid bearer token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// methods dispatches a route by request method and answers 405 for the others
+func methods(handlers map[string]http.HandlerFunc) http.HandlerFunc {
+	allowed := make([]string, 0, len(handlers))
+	for method := range handlers {
+		allowed = append(allowed, method)
+	}
+	allow := strings.Join(allowed, ", ")
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		handle, ok := handlers[r.Method]
+		if !ok {
+			w.Header().Set("Allow", allow)
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: fmt.Sprintf("method %s not allowed", r.Method)})
+			return
+		}
+		handle(w, r)
+	}
+}
+
+// pathParam returns the single path segment after prefix, e.g. the ID of /orders/{id}
+func pathParam(w http.ResponseWriter, r *http.Request, prefix string) (string, bool) {
+	param := strings.TrimPrefix(r.URL.Path, prefix)
+	if param == "" || strings.Contains(param, "/") {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: fmt.Sprintf("no route for %s", r.URL.Path)})
+		return "", false
+	}
+	return param, true
+}
+
+// orderRequest is the body of the order and stop loss routes
+type orderRequest struct {
+	Symbol    string  `json:"symbol"`
+	Quantity  float64 `json:"quantity"`
+	Price     float64 `json:"price,omitempty"`
+	StopPrice float64 `json:"stop_price,omitempty"`
+}
+
+// handleMarketBuy places a market buy order
+func (h *handler) handleMarketBuy(w http.ResponseWriter, r *http.Request) {
+	var req orderRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	order, err := h.trading.PlaceMarketBuyOrder(strings.ToUpper(req.Symbol), req.Quantity)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, newOrderResponse(order))
+}
+
+// handleMarketSell places a market sell order
+func (h *handler) handleMarketSell(w http.ResponseWriter, r *http.Request) {
+	var req orderRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	order, err := h.trading.PlaceMarketSellOrder(strings.ToUpper(req.Symbol), req.Quantity)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, newOrderResponse(order))
+}
+
+// handleLimitBuy places a limit buy order
+func (h *handler) handleLimitBuy(w http.ResponseWriter, r *http.Request) {
+	var req orderRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	order, err := h.trading.PlaceLimitBuyOrder(strings.ToUpper(req.Symbol), req.Price, req.Quantity)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, newOrderResponse(order))
+}
+
+// handleLimitSell places a limit sell order
+func (h *handler) handleLimitSell(w http.ResponseWriter, r *http.Request) {
+	var req orderRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	order, err := h.trading.PlaceLimitSellOrder(strings.ToUpper(req.Symbol), req.Price, req.Quantity)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, newOrderResponse(order))
+}
+
+// handleOpenOrders lists the open exchange orders
+func (h *handler) handleOpenOrders(w http.ResponseWriter, r *http.Request) {
+	orders, err := h.trading.GetActiveOrders()
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	response := make([]orderResponse, len(orders))
+	for i, order := range orders {
+		response[i] = newOrderResponse(order)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleCancelOrder cancels an exchange order
+func (h *handler) handleCancelOrder(w http.ResponseWriter, r *http.Request) {
+	param, ok := pathParam(w, r, "/orders/")
+	if !ok {
+		return
+	}
+	orderID, err := strconv.ParseInt(param, 10, 64)
+	if err != nil || orderID <= 0 {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid order ID %q", param)})
+		return
+	}
+
+	if err := h.trading.CancelOrder(orderID); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, cancelResponse{OrderID: param, Cancelled: true})
+}
+
+// conditionalOrderRequest is the body of POST /conditional-orders; orders execute at market
+// like those created with condorder
+type conditionalOrderRequest struct {
+	Symbol         string         `json:"symbol"`
+	Side           string         `json:"side"`
+	Quantity       float64        `json:"quantity"`
+	Trigger        triggerPayload `json:"trigger"`
+	IdempotencyKey string         `json:"idempotency_key,omitempty"`
+	AllowDuplicate bool           `json:"allow_duplicate,omitempty"`
+}
+
+// handleCreateConditionalOrder creates a conditional order
+func (h *handler) handleCreateConditionalOrder(w http.ResponseWriter, r *http.Request) {
+	var req conditionalOrderRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	side := api.OrderSide(strings.ToUpper(req.Side))
+	if side != api.OrderSideBuy && side != api.OrderSideSell {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid side: must be BUY or SELL"})
+		return
+	}
+	condition, err := req.Trigger.condition()
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	order, err := h.conditional.CreateConditionalOrder(&repository.ConditionalOrderRequest{
+		Symbol:           strings.ToUpper(req.Symbol),
+		Side:             side,
+		Type:             api.OrderTypeMarket,
+		Quantity:         req.Quantity,
+		TriggerCondition: condition,
+		IdempotencyKey:   req.IdempotencyKey,
+		AllowDuplicate:   req.AllowDuplicate,
+	})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, newConditionalOrderResponse(order))
+}
+
+// handleConditionalOrders lists the active conditional orders
+func (h *handler) handleConditionalOrders(w http.ResponseWriter, r *http.Request) {
+	orders, err := h.conditional.GetActiveConditionalOrders()
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	response := make([]conditionalOrderResponse, len(orders))
+	for i, order := range orders {
+		response[i] = newConditionalOrderResponse(order)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleCancelConditionalOrder cancels a pending conditional order
+func (h *handler) handleCancelConditionalOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := pathParam(w, r, "/conditional-orders/")
+	if !ok {
+		return
+	}
+
+	if err := h.conditional.CancelConditionalOrderWithReason(orderID, repository.CancelReasonUser, cancelledBy); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	order, err := h.conditional.GetConditionalOrder(orderID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newConditionalOrderResponse(order))
+}
+
+// handleStopLoss sets a stop loss on a position
+func (h *handler) handleStopLoss(w http.ResponseWriter, r *http.Request) {
+	var req orderRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	order, err := h.stopLoss.SetStopLoss(strings.ToUpper(req.Symbol), req.Quantity, req.StopPrice)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, newStopOrderResponse(order))
+}
+
+// handleStopOrders lists the active stop orders of a symbol
+func (h *handler) handleStopOrders(w http.ResponseWriter, r *http.Request) {
+	symbol, ok := pathParam(w, r, "/stop-orders/")
+	if !ok {
+		return
+	}
+
+	orders, err := h.stopLoss.GetActiveStopOrders(strings.ToUpper(symbol))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	response := make([]stopOrderResponse, len(orders))
+	for i, order := range orders {
+		response[i] = newStopOrderResponse(order)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// decodeRequest reads a JSON body into v, answering 400 when it is malformed or has unknown fields
+func decodeRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid request body: %v", err)})
+		return false
+	}
+	return true
+}
+
+// handlePrice returns the current price of a symbol
+func (h *handler) handlePrice(w http.ResponseWriter, r *http.Request) {
+	symbol, ok := pathParam(w, r, "/price/")
+	if !ok {
+		return
+	}
+	symbol = strings.ToUpper(symbol)
+
+	price, err := h.market.GetCurrentPrice(symbol)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, priceResponse{Symbol: symbol, Price: price})
+}
+
+// writeError answers with the status matching a service error
+func (h *handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status := statusOf(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error("API request failed", map[string]interface{}{
+			"method": r.Method,
+			"path":   r.URL.Path,
+			"error":  err.Error(),
+		})
+	}
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// statusOf maps a trading error to an HTTP status; other errors are internal
+func statusOf(err error) int {
+	var tradingErr *errors.TradingError
+	if !stderrors.As(err, &tradingErr) {
+		return http.StatusInternalServerError
+	}
+
+	switch tradingErr.Type {
+	case errors.ErrInvalidParameter, errors.ErrInvalidTriggerCondition:
+		return http.StatusBadRequest
+	case errors.ErrOrderNotFound, errors.ErrConditionalOrderNotFound, errors.ErrStopOrderNotFound:
+		return http.StatusNotFound
+	case errors.ErrDuplicateConditionalOrder, errors.ErrOrderAlreadyTriggered:
+		return http.StatusConflict
+	case errors.ErrInsufficientBalance, errors.ErrRiskLimitExceeded:
+		return http.StatusUnprocessableEntity
+	case errors.ErrRateLimit, errors.ErrSymbolPaused, errors.ErrNotionalCapReached, errors.ErrDailyLimitReached:
+		return http.StatusTooManyRequests
+	case errors.ErrSafeMode:
+		return http.StatusServiceUnavailable
+	case errors.ErrNetwork, errors.ErrAuthentication:
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeJSON writes v as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"binance-trader/internal/metrics"
+	"binance-trader/internal/service"
+	"binance-trader/pkg/logger"
+)
+
+// Server serves the trading API over HTTP for deployments without a terminal
+type Server struct {
+	server   *http.Server
+	listener net.Listener
+	done     chan struct{}
+}
+
+// NewServer creates a server exposing the spot services on addr (host:port); every request
+// must present token as a bearer token. The metrics in registry are served too when it is not nil.
+func NewServer(
+	addr string,
+	token string,
+	trading service.TradingService,
+	market service.MarketDataService,
+	conditional service.ConditionalOrderService,
+	stopLoss service.StopLossService,
+	registry *metrics.Registry,
+	log logger.Logger,
+) *Server {
+	return &Server{
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           NewHandler(token, trading, market, conditional, stopLoss, registry, log),
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		done: make(chan struct{}),
+	}
+}
+
+// Start listens on the address and serves in the background; a bind error is returned here
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+
+	go func() {
+		defer close(s.done)
+		s.server.Serve(listener)
+	}()
+	return nil
+}
+
+// Addr returns the address the server listens on, e.g. the port picked for ":0"
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return s.server.Addr
+	}
+	return s.listener.Addr().String()
+}
+
+// Shutdown stops accepting requests and waits for those in flight until ctx is done
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.listener == nil {
+		return errors.New("api server not started")
+	}
+	if err := s.server.Shutdown(ctx); err != nil {
+		return err
+	}
+	<-s.done
+	return nil
+}
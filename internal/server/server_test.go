@@ -0,0 +1,543 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"binance-trader/internal/api"
+	"binance-trader/internal/metrics"
+	"binance-trader/internal/repository"
+	"binance-trader/internal/service"
+	"binance-trader/pkg/errors"
+	"binance-trader/pkg/logger"
+)
+
+const testToken = "test-token"
+
+// mockTradingService implements the order routes; other methods are not used by the API
+type mockTradingService struct {
+	service.TradingService
+	orders    map[int64]*api.Order
+	nextID    int64
+	placeErr  error
+	cancelled []int64
+}
+
+func newMockTradingService() *mockTradingService {
+	return &mockTradingService{orders: make(map[int64]*api.Order), nextID: 1}
+}
+
+func (m *mockTradingService) place(symbol string, side api.OrderSide, orderType api.OrderType, price, quantity float64) (*api.Order, error) {
+	if m.placeErr != nil {
+		return nil, m.placeErr
+	}
+	order := &api.Order{OrderID: m.nextID, Symbol: symbol, Side: side, Type: orderType, Status: api.OrderStatusNew, Price: price, OrigQty: quantity}
+	m.orders[order.OrderID] = order
+	m.nextID++
+	return order, nil
+}
+
+func (m *mockTradingService) PlaceMarketBuyOrder(symbol string, quantity float64) (*api.Order, error) {
+	return m.place(symbol, api.OrderSideBuy, api.OrderTypeMarket, 0, quantity)
+}
+
+func (m *mockTradingService) PlaceMarketSellOrder(symbol string, quantity float64) (*api.Order, error) {
+	return m.place(symbol, api.OrderSideSell, api.OrderTypeMarket, 0, quantity)
+}
+
+func (m *mockTradingService) PlaceLimitBuyOrder(symbol string, price, quantity float64) (*api.Order, error) {
+	return m.place(symbol, api.OrderSideBuy, api.OrderTypeLimit, price, quantity)
+}
+
+func (m *mockTradingService) PlaceLimitSellOrder(symbol string, price, quantity float64) (*api.Order, error) {
+	return m.place(symbol, api.OrderSideSell, api.OrderTypeLimit, price, quantity)
+}
+
+func (m *mockTradingService) GetActiveOrders() ([]*api.Order, error) {
+	orders := make([]*api.Order, 0, len(m.orders))
+	for id := int64(1); id < m.nextID; id++ {
+		if order, ok := m.orders[id]; ok {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func (m *mockTradingService) CancelOrder(orderID int64) error {
+	if _, ok := m.orders[orderID]; !ok {
+		return errors.NewTradingError(errors.ErrOrderNotFound, fmt.Sprintf("order %d not found", orderID), 0, nil)
+	}
+	delete(m.orders, orderID)
+	m.cancelled = append(m.cancelled, orderID)
+	return nil
+}
+
+// mockConditionalOrderService keeps conditional orders in memory
+type mockConditionalOrderService struct {
+	service.ConditionalOrderService
+	orders []*repository.ConditionalOrder
+}
+
+func (m *mockConditionalOrderService) CreateConditionalOrder(request *repository.ConditionalOrderRequest) (*repository.ConditionalOrder, error) {
+	order := &repository.ConditionalOrder{
+		OrderID:          fmt.Sprintf("cond-%d", len(m.orders)+1),
+		Symbol:           request.Symbol,
+		Side:             request.Side,
+		Type:             request.Type,
+		Quantity:         request.Quantity,
+		TriggerCondition: request.TriggerCondition,
+		Status:           repository.ConditionalOrderStatusPending,
+		IdempotencyKey:   request.IdempotencyKey,
+	}
+	m.orders = append(m.orders, order)
+	return order, nil
+}
+
+func (m *mockConditionalOrderService) GetActiveConditionalOrders() ([]*repository.ConditionalOrder, error) {
+	var active []*repository.ConditionalOrder
+	for _, order := range m.orders {
+		if order.Status == repository.ConditionalOrderStatusPending {
+			active = append(active, order)
+		}
+	}
+	return active, nil
+}
+
+func (m *mockConditionalOrderService) GetConditionalOrder(orderID string) (*repository.ConditionalOrder, error) {
+	for _, order := range m.orders {
+		if order.OrderID == orderID {
+			return order, nil
+		}
+	}
+	return nil, errors.NewTradingError(errors.ErrConditionalOrderNotFound, "conditional order not found", 0, nil)
+}
+
+func (m *mockConditionalOrderService) CancelConditionalOrderWithReason(orderID string, reason repository.CancelReason, cancelledBy string) error {
+	order, err := m.GetConditionalOrder(orderID)
+	if err != nil {
+		return err
+	}
+	order.Status = repository.ConditionalOrderStatusCancelled
+	order.CancelReason = reason
+	order.CancelledBy = cancelledBy
+	return nil
+}
+
+// mockStopLossService keeps stop orders in memory
+type mockStopLossService struct {
+	service.StopLossService
+	orders []*repository.StopOrder
+}
+
+func (m *mockStopLossService) SetStopLoss(symbol string, position float64, stopPrice float64) (*repository.StopOrder, error) {
+	if stopPrice <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "stop price must be positive", 0, nil)
+	}
+	order := &repository.StopOrder{
+		OrderID:   fmt.Sprintf("stop-%d", len(m.orders)+1),
+		Symbol:    symbol,
+		Position:  position,
+		StopPrice: stopPrice,
+		Type:      repository.StopOrderTypeStopLoss,
+		Status:    repository.StopOrderStatusActive,
+	}
+	m.orders = append(m.orders, order)
+	return order, nil
+}
+
+func (m *mockStopLossService) GetActiveStopOrders(symbol string) ([]*repository.StopOrder, error) {
+	var active []*repository.StopOrder
+	for _, order := range m.orders {
+		if order.Symbol == symbol {
+			active = append(active, order)
+		}
+	}
+	return active, nil
+}
+
+// mockLogger records warnings and errors, the only levels the API logs at
+type mockLogger struct {
+	logger.Logger
+	warnings []string
+	errors   []string
+}
+
+func (m *mockLogger) Warn(msg string, fields map[string]interface{}) {
+	m.warnings = append(m.warnings, msg)
+}
+
+func (m *mockLogger) Error(msg string, fields map[string]interface{}) {
+	m.errors = append(m.errors, msg)
+}
+
+// mockMarketDataService implements the price route; other methods are not used by the API
+type mockMarketDataService struct {
+	service.MarketDataService
+	prices map[string]float64
+}
+
+func (m *mockMarketDataService) GetCurrentPrice(symbol string) (float64, error) {
+	price, ok := m.prices[symbol]
+	if !ok {
+		return 0, errors.NewTradingError(errors.ErrInvalidParameter, "invalid symbol "+symbol, 0, nil)
+	}
+	return price, nil
+}
+
+// testAPI is a running API over mock services
+type testAPI struct {
+	server      *httptest.Server
+	trading     *mockTradingService
+	market      *mockMarketDataService
+	conditional *mockConditionalOrderService
+	stopLoss    *mockStopLossService
+	registry    *metrics.Registry
+	logger      *mockLogger
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	a := &testAPI{
+		trading:     newMockTradingService(),
+		market:      &mockMarketDataService{prices: map[string]float64{"BTCUSDT": 50000}},
+		conditional: &mockConditionalOrderService{},
+		stopLoss:    &mockStopLossService{},
+		registry:    metrics.NewRegistry(),
+		logger:      &mockLogger{},
+	}
+	a.server = httptest.NewServer(NewHandler(testToken, a.trading, a.market, a.conditional, a.stopLoss, a.registry, a.logger))
+	t.Cleanup(a.server.Close)
+	return a
+}
+
+// do sends an authenticated request and decodes the JSON response into out when it is not nil
+func (a *testAPI) do(t *testing.T, method, path, body string, out interface{}) int {
+	t.Helper()
+	return a.doWithToken(t, testToken, method, path, body, out)
+}
+
+func (a *testAPI) doWithToken(t *testing.T, token, method, path, body string, out interface{}) int {
+	t.Helper()
+	req, err := http.NewRequest(method, a.server.URL+path, bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := a.server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s error = %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("%s %s content type = %q, want application/json", method, path, resp.Header.Get("Content-Type"))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: decoding response: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestHandler_RequiresToken(t *testing.T) {
+	a := newTestAPI(t)
+
+	for _, token := range []string{"", "wrong-token"} {
+		var body errorResponse
+		status := a.doWithToken(t, token, http.MethodPost, "/orders/market-buy", `{"symbol":"BTCUSDT","quantity":0.1}`, &body)
+		if status != http.StatusUnauthorized || body.Error == "" {
+			t.Errorf("token %q: status = %d, body = %+v; want 401 with an error", token, status, body)
+		}
+	}
+	if len(a.trading.orders) != 0 {
+		t.Errorf("unauthenticated requests placed %d orders", len(a.trading.orders))
+	}
+	if len(a.logger.warnings) != 2 {
+		t.Errorf("rejections logged = %d, want 2", len(a.logger.warnings))
+	}
+
+	// An empty configured token never matches an empty bearer token
+	open := httptest.NewServer(NewHandler("", a.trading, a.market, a.conditional, a.stopLoss, a.registry, a.logger))
+	defer open.Close()
+	req, _ := http.NewRequest(http.MethodGet, open.URL+"/orders/open", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	resp, err := open.Client().Do(req)
+	if err != nil {
+		t.Fatalf("GET /orders/open error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("empty token status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestHandler_Orders(t *testing.T) {
+	a := newTestAPI(t)
+
+	var buy orderResponse
+	if status := a.do(t, http.MethodPost, "/orders/market-buy", `{"symbol":"btcusdt","quantity":0.1}`, &buy); status != http.StatusCreated {
+		t.Fatalf("market buy status = %d, want 201", status)
+	}
+	if buy.OrderID != 1 || buy.Symbol != "BTCUSDT" || buy.Side != "BUY" || buy.Type != "MARKET" || buy.Quantity != 0.1 {
+		t.Errorf("market buy = %+v", buy)
+	}
+
+	var sell orderResponse
+	if status := a.do(t, http.MethodPost, "/orders/limit-sell", `{"symbol":"BTCUSDT","price":55000,"quantity":0.1}`, &sell); status != http.StatusCreated {
+		t.Fatalf("limit sell status = %d, want 201", status)
+	}
+	if sell.Side != "SELL" || sell.Type != "LIMIT" || sell.Price != 55000 {
+		t.Errorf("limit sell = %+v", sell)
+	}
+
+	var marketSell, limitBuy orderResponse
+	if status := a.do(t, http.MethodPost, "/orders/market-sell", `{"symbol":"BTCUSDT","quantity":0.05}`, &marketSell); status != http.StatusCreated {
+		t.Fatalf("market sell status = %d, want 201", status)
+	}
+	if marketSell.Side != "SELL" || marketSell.Type != "MARKET" || marketSell.Quantity != 0.05 {
+		t.Errorf("market sell = %+v", marketSell)
+	}
+	if status := a.do(t, http.MethodPost, "/orders/limit-buy", `{"symbol":"BTCUSDT","price":48000,"quantity":0.1}`, &limitBuy); status != http.StatusCreated {
+		t.Fatalf("limit buy status = %d, want 201", status)
+	}
+	if limitBuy.Side != "BUY" || limitBuy.Type != "LIMIT" || limitBuy.Price != 48000 {
+		t.Errorf("limit buy = %+v", limitBuy)
+	}
+
+	var open []orderResponse
+	if status := a.do(t, http.MethodGet, "/orders/open", "", &open); status != http.StatusOK || len(open) != 4 {
+		t.Fatalf("open orders = %d, %+v; want 200 with four orders", status, open)
+	}
+
+	var cancelled cancelResponse
+	if status := a.do(t, http.MethodDelete, "/orders/2", "", &cancelled); status != http.StatusOK || !cancelled.Cancelled || cancelled.OrderID != "2" {
+		t.Errorf("cancel = %d, %+v; want 200 for order 2", status, cancelled)
+	}
+	if len(a.trading.cancelled) != 1 || a.trading.cancelled[0] != 2 {
+		t.Errorf("cancelled orders = %v, want [2]", a.trading.cancelled)
+	}
+
+	tests := []struct {
+		name, method, path, body string
+		want                     int
+	}{
+		{"cancel unknown order", http.MethodDelete, "/orders/99", "", http.StatusNotFound},
+		{"cancel with a malformed ID", http.MethodDelete, "/orders/abc", "", http.StatusBadRequest},
+		{"nested path", http.MethodDelete, "/orders/1/fills", "", http.StatusNotFound},
+		{"wrong method", http.MethodGet, "/orders/market-buy", "", http.StatusMethodNotAllowed},
+		{"malformed body", http.MethodPost, "/orders/market-buy", `{"symbol":`, http.StatusBadRequest},
+		{"unknown field", http.MethodPost, "/orders/market-buy", `{"symbol":"BTCUSDT","qty":1}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		var body errorResponse
+		if status := a.do(t, tt.method, tt.path, tt.body, &body); status != tt.want || body.Error == "" {
+			t.Errorf("%s: status = %d, body = %+v; want %d with an error", tt.name, status, body, tt.want)
+		}
+	}
+
+	// Service errors keep their message and map to a matching status
+	a.trading.placeErr = errors.NewTradingError(errors.ErrRiskLimitExceeded, "daily order limit reached", 0, nil)
+	var rejected errorResponse
+	if status := a.do(t, http.MethodPost, "/orders/market-buy", `{"symbol":"BTCUSDT","quantity":1}`, &rejected); status != http.StatusUnprocessableEntity || !strings.Contains(rejected.Error, "daily order limit reached") {
+		t.Errorf("risk rejection = %d, %+v; want 422", status, rejected)
+	}
+
+	a.trading.placeErr = fmt.Errorf("connection reset")
+	if status := a.do(t, http.MethodPost, "/orders/market-buy", `{"symbol":"BTCUSDT","quantity":1}`, nil); status != http.StatusInternalServerError {
+		t.Errorf("unexpected error status = %d, want 500", status)
+	}
+	if len(a.logger.errors) != 1 {
+		t.Errorf("internal errors logged = %d, want 1", len(a.logger.errors))
+	}
+}
+
+func TestHandler_ConditionalOrders(t *testing.T) {
+	a := newTestAPI(t)
+
+	var created conditionalOrderResponse
+	body := `{"symbol":"ethusdt","side":"buy","quantity":2,"trigger":{"type":"price","operator":"<=","value":2500},"idempotency_key":"dip-1"}`
+	if status := a.do(t, http.MethodPost, "/conditional-orders", body, &created); status != http.StatusCreated {
+		t.Fatalf("create status = %d, want 201", status)
+	}
+	if created.OrderID != "cond-1" || created.Symbol != "ETHUSDT" || created.Side != "BUY" || created.Type != "MARKET" || created.Status != "PENDING" {
+		t.Errorf("created = %+v", created)
+	}
+	if created.Trigger == nil || created.Trigger.Type != "PRICE" || created.Trigger.Operator != "<=" || created.Trigger.Value != 2500 {
+		t.Errorf("created trigger = %+v, want PRICE <= 2500", created.Trigger)
+	}
+	stored := a.conditional.orders[0]
+	if stored.TriggerCondition.Type != repository.TriggerTypePrice || stored.TriggerCondition.Operator != repository.OperatorLessEqual || stored.IdempotencyKey != "dip-1" {
+		t.Errorf("stored order = %+v, trigger %+v", stored, stored.TriggerCondition)
+	}
+
+	var active []conditionalOrderResponse
+	if status := a.do(t, http.MethodGet, "/conditional-orders", "", &active); status != http.StatusOK || len(active) != 1 {
+		t.Fatalf("active = %d, %+v; want one order", status, active)
+	}
+
+	var cancelled conditionalOrderResponse
+	if status := a.do(t, http.MethodDelete, "/conditional-orders/cond-1", "", &cancelled); status != http.StatusOK {
+		t.Fatalf("cancel status = %d, want 200", status)
+	}
+	if cancelled.Status != "CANCELLED" || cancelled.CancelReason != "USER" || cancelled.CancelledBy != "api" {
+		t.Errorf("cancelled = %+v, want CANCELLED by the api", cancelled)
+	}
+	if status := a.do(t, http.MethodGet, "/conditional-orders", "", &active); status != http.StatusOK || len(active) != 0 {
+		t.Errorf("active after cancel = %+v, want none", active)
+	}
+
+	tests := []struct {
+		name, method, path, body string
+		want                     int
+	}{
+		{"invalid side", http.MethodPost, "/conditional-orders", `{"symbol":"ETHUSDT","side":"HOLD","quantity":1,"trigger":{"type":"PRICE","operator":">","value":1}}`, http.StatusBadRequest},
+		{"invalid trigger type", http.MethodPost, "/conditional-orders", `{"symbol":"ETHUSDT","side":"BUY","quantity":1,"trigger":{"type":"RSI","operator":">","value":1}}`, http.StatusBadRequest},
+		{"invalid operator", http.MethodPost, "/conditional-orders", `{"symbol":"ETHUSDT","side":"BUY","quantity":1,"trigger":{"type":"PRICE","operator":"=","value":1}}`, http.StatusBadRequest},
+		{"invalid volume window", http.MethodPost, "/conditional-orders", `{"symbol":"ETHUSDT","side":"BUY","quantity":1,"trigger":{"type":"VOLUME","operator":">","value":1,"window":"soon"}}`, http.StatusBadRequest},
+		{"window on a price trigger", http.MethodPost, "/conditional-orders", `{"symbol":"ETHUSDT","side":"BUY","quantity":1,"trigger":{"type":"PRICE","operator":">","value":1,"window":"1h"}}`, http.StatusBadRequest},
+		{"cancel unknown order", http.MethodDelete, "/conditional-orders/cond-9", "", http.StatusNotFound},
+		{"wrong method", http.MethodPut, "/conditional-orders", "", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		var body errorResponse
+		if status := a.do(t, tt.method, tt.path, tt.body, &body); status != tt.want || body.Error == "" {
+			t.Errorf("%s: status = %d, body = %+v; want %d with an error", tt.name, status, body, tt.want)
+		}
+	}
+	if len(a.conditional.orders) != 1 {
+		t.Errorf("rejected requests created orders: %d stored", len(a.conditional.orders))
+	}
+
+	// Volume triggers take a window, 24h when left out
+	for window, want := range map[string]time.Duration{"4h": 4 * time.Hour, "": 24 * time.Hour} {
+		body := fmt.Sprintf(`{"symbol":"ETHUSDT","side":"BUY","quantity":1,"trigger":{"type":"VOLUME","operator":">","value":5000,"window":%q}}`, window)
+		if status := a.do(t, http.MethodPost, "/conditional-orders", body, &created); status != http.StatusCreated {
+			t.Fatalf("volume order with window %q status = %d, want 201", window, status)
+		}
+		stored := a.conditional.orders[len(a.conditional.orders)-1]
+		if stored.TriggerCondition.TimeWindow != want || created.Trigger.Window != service.FormatShortDuration(want) {
+			t.Errorf("volume window %q: stored %v, response %q; want %v", window, stored.TriggerCondition.TimeWindow, created.Trigger.Window, want)
+		}
+	}
+}
+
+func TestHandler_StopLoss(t *testing.T) {
+	a := newTestAPI(t)
+
+	var stop stopOrderResponse
+	if status := a.do(t, http.MethodPost, "/stop-loss", `{"symbol":"btcusdt","quantity":0.5,"stop_price":48000}`, &stop); status != http.StatusCreated {
+		t.Fatalf("stop loss status = %d, want 201", status)
+	}
+	if stop.OrderID != "stop-1" || stop.Symbol != "BTCUSDT" || stop.Type != "STOP_LOSS" || stop.Quantity != 0.5 || stop.StopPrice != 48000 || stop.Status != "ACTIVE" {
+		t.Errorf("stop loss = %+v", stop)
+	}
+
+	var invalid errorResponse
+	if status := a.do(t, http.MethodPost, "/stop-loss", `{"symbol":"BTCUSDT","quantity":0.5}`, &invalid); status != http.StatusBadRequest {
+		t.Errorf("stop loss without a price status = %d, want 400", status)
+	}
+
+	var orders []stopOrderResponse
+	if status := a.do(t, http.MethodGet, "/stop-orders/btcusdt", "", &orders); status != http.StatusOK || len(orders) != 1 || orders[0].OrderID != "stop-1" {
+		t.Errorf("stop orders = %d, %+v; want stop-1", status, orders)
+	}
+	if status := a.do(t, http.MethodGet, "/stop-orders/ETHUSDT", "", &orders); status != http.StatusOK || len(orders) != 0 {
+		t.Errorf("stop orders of another symbol = %+v, want none", orders)
+	}
+	if status := a.do(t, http.MethodGet, "/stop-orders/", "", nil); status != http.StatusNotFound {
+		t.Errorf("stop orders without a symbol status = %d, want 404", status)
+	}
+}
+
+func TestHandler_Price(t *testing.T) {
+	a := newTestAPI(t)
+
+	var price priceResponse
+	if status := a.do(t, http.MethodGet, "/price/btcusdt", "", &price); status != http.StatusOK {
+		t.Fatalf("price status = %d, want 200", status)
+	}
+	if price.Symbol != "BTCUSDT" || price.Price != 50000 {
+		t.Errorf("price = %+v, want BTCUSDT at 50000", price)
+	}
+
+	var invalid errorResponse
+	if status := a.do(t, http.MethodGet, "/price/DOGEBTC", "", &invalid); status != http.StatusBadRequest || invalid.Error == "" {
+		t.Errorf("price of an unknown symbol = %d, %+v; want 400 with an error", status, invalid)
+	}
+	if status := a.do(t, http.MethodGet, "/price/", "", nil); status != http.StatusNotFound {
+		t.Errorf("price without a symbol status = %d, want 404", status)
+	}
+	if status := a.do(t, http.MethodPost, "/price/BTCUSDT", "", nil); status != http.StatusMethodNotAllowed {
+		t.Errorf("POST price status = %d, want 405", status)
+	}
+}
+
+func TestHandler_Metrics(t *testing.T) {
+	a := newTestAPI(t)
+	metrics.NewSink(a.registry).ActiveStopOrders.Set(2)
+
+	get := func(token string) (*http.Response, string) {
+		req, _ := http.NewRequest(http.MethodGet, a.server.URL+metrics.Path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := a.server.Client().Do(req)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", metrics.Path, err)
+		}
+		defer resp.Body.Close()
+		var body strings.Builder
+		io.Copy(&body, resp.Body)
+		return resp, body.String()
+	}
+
+	resp, body := get(testToken)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != metrics.ContentType {
+		t.Fatalf("metrics status = %d, content type = %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(body, "stop_orders_active 2\n") {
+		t.Errorf("metrics body does not contain the stop order gauge:\n%s", body)
+	}
+	if resp, _ := get(""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("metrics without a token status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestServer(t *testing.T) {
+	trading := newMockTradingService()
+	server := NewServer("127.0.0.1:0", testToken, trading, &mockMarketDataService{}, &mockConditionalOrderService{}, &mockStopLossService{}, nil, &mockLogger{})
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://"+server.Addr()+"/orders/open", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /orders/open error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if _, err := http.DefaultClient.Do(req); err == nil {
+		t.Error("server still answers after Shutdown()")
+	}
+}
@@ -0,0 +1,218 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"binance-trader/internal/service"
+)
+
+// errorResponse is the body of every failed request
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// cancelResponse confirms an exchange order cancellation
+type cancelResponse struct {
+	OrderID   string `json:"order_id"`
+	Cancelled bool   `json:"cancelled"`
+}
+
+// priceResponse is the current price of a symbol
+type priceResponse struct {
+	Symbol string  `json:"symbol"`
+	Price  float64 `json:"price"`
+}
+
+// orderResponse is an exchange order
+type orderResponse struct {
+	OrderID       int64   `json:"order_id"`
+	ClientOrderID string  `json:"client_order_id,omitempty"`
+	Symbol        string  `json:"symbol"`
+	Side          string  `json:"side"`
+	Type          string  `json:"type"`
+	Status        string  `json:"status"`
+	Price         float64 `json:"price"`
+	Quantity      float64 `json:"quantity"`
+	ExecutedQty   float64 `json:"executed_qty"`
+	QuoteQty      float64 `json:"quote_qty"`
+	Time          int64   `json:"time"`
+}
+
+func newOrderResponse(order *api.Order) orderResponse {
+	return orderResponse{
+		OrderID:       order.OrderID,
+		ClientOrderID: order.ClientOrderID,
+		Symbol:        order.Symbol,
+		Side:          string(order.Side),
+		Type:          string(order.Type),
+		Status:        string(order.Status),
+		Price:         order.Price,
+		Quantity:      order.OrigQty,
+		ExecutedQty:   order.ExecutedQty,
+		QuoteQty:      order.CummulativeQuoteQty,
+		Time:          order.Time,
+	}
+}
+
+// conditionalOrderResponse is a conditional order; timestamps are Unix ms
+type conditionalOrderResponse struct {
+	OrderID         string          `json:"order_id"`
+	Symbol          string          `json:"symbol"`
+	Side            string          `json:"side"`
+	Type            string          `json:"type"`
+	Quantity        float64         `json:"quantity"`
+	Price           float64         `json:"price,omitempty"`
+	Trigger         *triggerPayload `json:"trigger,omitempty"`
+	Status          string          `json:"status"`
+	CreatedAt       int64           `json:"created_at"`
+	TriggeredAt     int64           `json:"triggered_at,omitempty"`
+	ExecutedOrderID int64           `json:"executed_order_id,omitempty"`
+	CancelReason    string          `json:"cancel_reason,omitempty"`
+	CancelledBy     string          `json:"cancelled_by,omitempty"`
+}
+
+func newConditionalOrderResponse(order *repository.ConditionalOrder) conditionalOrderResponse {
+	return conditionalOrderResponse{
+		OrderID:         order.OrderID,
+		Symbol:          order.Symbol,
+		Side:            string(order.Side),
+		Type:            string(order.Type),
+		Quantity:        order.Quantity,
+		Price:           order.Price,
+		Trigger:         newTriggerPayload(order.TriggerCondition),
+		Status:          string(order.Status),
+		CreatedAt:       order.CreatedAt,
+		TriggeredAt:     order.TriggeredAt,
+		ExecutedOrderID: order.ExecutedOrderID,
+		CancelReason:    string(order.CancelReason),
+		CancelledBy:     order.CancelledBy,
+	}
+}
+
+// stopOrderResponse is a stop loss or take profit order; timestamps are Unix ms
+type stopOrderResponse struct {
+	OrderID         string  `json:"order_id"`
+	Symbol          string  `json:"symbol"`
+	Type            string  `json:"type"`
+	Quantity        float64 `json:"quantity"`
+	StopPrice       float64 `json:"stop_price"`
+	Status          string  `json:"status"`
+	CreatedAt       int64   `json:"created_at"`
+	TriggeredAt     int64   `json:"triggered_at,omitempty"`
+	ExecutedOrderID int64   `json:"executed_order_id,omitempty"`
+	PairID          string  `json:"pair_id,omitempty"`
+}
+
+var stopOrderTypeNames = map[repository.StopOrderType]string{
+	repository.StopOrderTypeStopLoss:   "STOP_LOSS",
+	repository.StopOrderTypeTakeProfit: "TAKE_PROFIT",
+}
+
+func newStopOrderResponse(order *repository.StopOrder) stopOrderResponse {
+	return stopOrderResponse{
+		OrderID:         order.OrderID,
+		Symbol:          order.Symbol,
+		Type:            stopOrderTypeNames[order.Type],
+		Quantity:        order.Position,
+		StopPrice:       order.StopPrice,
+		Status:          string(order.Status),
+		CreatedAt:       order.CreatedAt,
+		TriggeredAt:     order.TriggeredAt,
+		ExecutedOrderID: order.ExecutedOrderID,
+		PairID:          order.PairID,
+	}
+}
+
+// triggerPayload is a trigger condition using the names of the condorder command:
+// type PRICE, PRICE_CHANGE or VOLUME and operator >, <, >= or <=. Composite conditions
+// only appear in responses, with logic AND or OR over their sub-conditions.
+type triggerPayload struct {
+	Type       string            `json:"type,omitempty"`
+	Operator   string            `json:"operator,omitempty"`
+	Value      float64           `json:"value"`
+	Window     string            `json:"window,omitempty"` // VOLUME window, e.g. 1h; 24h when unset
+	Logic      string            `json:"logic,omitempty"`
+	Conditions []*triggerPayload `json:"conditions,omitempty"`
+}
+
+var triggerTypeNames = map[repository.TriggerType]string{
+	repository.TriggerTypePrice:              "PRICE",
+	repository.TriggerTypePriceChangePercent: "PRICE_CHANGE",
+	repository.TriggerTypeVolume:             "VOLUME",
+}
+
+var operatorNames = map[repository.ComparisonOperator]string{
+	repository.OperatorGreaterThan:  ">",
+	repository.OperatorLessThan:     "<",
+	repository.OperatorGreaterEqual: ">=",
+	repository.OperatorLessEqual:    "<=",
+}
+
+func newTriggerPayload(condition *repository.TriggerCondition) *triggerPayload {
+	if condition == nil {
+		return nil
+	}
+	if len(condition.SubConditions) > 0 {
+		payload := &triggerPayload{Logic: "AND"}
+		if condition.CompositeType == repository.LogicOR {
+			payload.Logic = "OR"
+		}
+		for _, sub := range condition.SubConditions {
+			payload.Conditions = append(payload.Conditions, newTriggerPayload(sub))
+		}
+		return payload
+	}
+	payload := &triggerPayload{
+		Type:     triggerTypeNames[condition.Type],
+		Operator: operatorNames[condition.Operator],
+		Value:    condition.Value,
+	}
+	if condition.Type == repository.TriggerTypeVolume && condition.TimeWindow > 0 {
+		payload.Window = service.FormatShortDuration(condition.TimeWindow)
+	}
+	return payload
+}
+
+// condition converts a requested trigger into a trigger condition
+func (p *triggerPayload) condition() (*repository.TriggerCondition, error) {
+	condition := &repository.TriggerCondition{Value: p.Value}
+
+	triggerType := strings.ToUpper(p.Type)
+	found := false
+	for value, name := range triggerTypeNames {
+		if name == triggerType {
+			condition.Type, found = value, true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("invalid trigger type: must be PRICE, PRICE_CHANGE, or VOLUME")
+	}
+
+	found = false
+	for value, name := range operatorNames {
+		if name == p.Operator {
+			condition.Operator, found = value, true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("invalid operator: must be >=, <=, >, or <")
+	}
+
+	if condition.Type == repository.TriggerTypeVolume {
+		condition.TimeWindow = service.DefaultVolumeWindow
+		if p.Window != "" {
+			window, err := time.ParseDuration(p.Window)
+			if err != nil {
+				return nil, fmt.Errorf("invalid window %q: expected a duration, e.g. 15m or 4h", p.Window)
+			}
+			condition.TimeWindow = window
+		}
+	} else if p.Window != "" {
+		return nil, fmt.Errorf("window is only supported for VOLUME triggers")
+	}
+	return condition, nil
+}
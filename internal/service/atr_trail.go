@@ -0,0 +1,269 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	"binance-trader/pkg/timeutil"
+	"fmt"
+	"math"
+)
+
+const (
+	// DefaultATRInterval is used when stop_loss.atr_interval is not configured
+	DefaultATRInterval = "1h"
+	// DefaultMinTrailPercent and DefaultMaxTrailPercent bound ATR trails until SetTrailConfig is called
+	DefaultMinTrailPercent = 0.1
+	DefaultMaxTrailPercent = 10.0
+
+	// maxATRPeriod keeps the kline request within the exchange's limit of 1000
+	maxATRPeriod = 300
+)
+
+// ATRTrail configures a trailing stop whose trail follows volatility: the trail distance is
+// Multiplier × the Average True Range of the last Period klines of Interval
+type ATRTrail struct {
+	Period     int
+	Multiplier float64
+	Interval   string // Kline interval; empty uses stop_loss.atr_interval
+}
+
+// atrReading is the trail computed from the newest closed candles
+type atrReading struct {
+	ATR          float64
+	TrailPercent float64
+	CandleClose  int64 // Close time (ms) of the newest closed candle
+}
+
+// AverageTrueRange returns Wilder's Average True Range of klines ordered oldest first. The
+// first value averages the true ranges of the first period candles after the first one;
+// every later candle smooths it as (ATR × (period-1) + TR) / period.
+func AverageTrueRange(klines []*api.Kline, period int) (float64, error) {
+	if period <= 0 {
+		return 0, fmt.Errorf("ATR period must be greater than 0")
+	}
+	if len(klines) < period+1 {
+		return 0, fmt.Errorf("ATR(%d) needs %d klines, got %d", period, period+1, len(klines))
+	}
+
+	trueRange := func(i int) float64 {
+		high, low, prevClose := klines[i].High, klines[i].Low, klines[i-1].Close
+		return math.Max(high-low, math.Max(math.Abs(high-prevClose), math.Abs(low-prevClose)))
+	}
+
+	atr := 0.0
+	for i := 1; i <= period; i++ {
+		atr += trueRange(i)
+	}
+	atr /= float64(period)
+
+	for i := period + 1; i < len(klines); i++ {
+		atr = (atr*float64(period-1) + trueRange(i)) / float64(period)
+	}
+	return atr, nil
+}
+
+// SetTrailConfig sets the trail bounds and kline interval of ATR trailing stops
+func (s *stopLossService) SetTrailConfig(cfg *config.StopLossConfig) {
+	if cfg == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cfg.MinTrailPercent > 0 {
+		s.minTrailPercent = cfg.MinTrailPercent
+	}
+	if cfg.MaxTrailPercent > 0 {
+		s.maxTrailPercent = cfg.MaxTrailPercent
+	}
+	if cfg.ATRInterval != "" {
+		s.atrInterval = cfg.ATRInterval
+	}
+}
+
+// SetATRTrailingStop sets a trailing stop whose trail is recomputed from the ATR on each
+// candle close, bounded by the configured minimum and maximum trail percent
+func (s *stopLossService) SetATRTrailingStop(symbol string, position float64, trail ATRTrail) (*repository.TrailingStopOrder, error) {
+	if symbol == "" {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "symbol cannot be empty", 0, nil)
+	}
+
+	if position <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "position must be greater than 0", 0, nil)
+	}
+
+	if trail.Period <= 0 || trail.Period > maxATRPeriod {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter,
+			fmt.Sprintf("ATR period must be between 1 and %d", maxATRPeriod), 0, nil)
+	}
+
+	if trail.Multiplier <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "ATR multiplier must be greater than 0", 0, nil)
+	}
+
+	if trail.Interval == "" {
+		s.mu.RLock()
+		trail.Interval = s.atrInterval
+		s.mu.RUnlock()
+	}
+	if _, ok := api.KlineIntervalDuration(trail.Interval); !ok {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter,
+			fmt.Sprintf("unsupported ATR kline interval %q", trail.Interval), 0, nil)
+	}
+
+	reading, err := s.readATRTrail(symbol, trail)
+	if err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation": "set_atr_trailing_stop",
+			"symbol":    symbol,
+		})
+		return nil, err
+	}
+
+	currentPrice, err := s.marketService.GetCurrentPrice(symbol)
+	if err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation": "set_atr_trailing_stop",
+			"symbol":    symbol,
+		})
+		return nil, err
+	}
+
+	now := timeutil.Millis(s.now())
+	order := &repository.TrailingStopOrder{
+		OrderID:          generateOrderID("TS"),
+		Symbol:           symbol,
+		Position:         position,
+		TrailPercent:     reading.TrailPercent,
+		HighestPrice:     currentPrice,
+		CurrentStopPrice: currentPrice * (1 - reading.TrailPercent/100),
+		Status:           repository.StopOrderStatusActive,
+		CreatedAt:        now,
+		LastUpdatedAt:    now,
+		TrailMode:        repository.TrailModeATR,
+		ATRPeriod:        trail.Period,
+		ATRMultiplier:    trail.Multiplier,
+		ATRInterval:      trail.Interval,
+		ATR:              reading.ATR,
+		LastCandleClose:  reading.CandleClose,
+	}
+
+	if err := s.stopOrderRepo.SaveTrailingStopOrder(order); err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation": "set_atr_trailing_stop",
+			"symbol":    symbol,
+			"position":  position,
+		})
+		return nil, err
+	}
+
+	s.logger.Info("ATR trailing stop order created", map[string]interface{}{
+		"order_id":       order.OrderID,
+		"symbol":         symbol,
+		"position":       position,
+		"atr_period":     trail.Period,
+		"atr_multiplier": trail.Multiplier,
+		"atr_interval":   trail.Interval,
+		"atr":            reading.ATR,
+		"trail_percent":  reading.TrailPercent,
+		"highest_price":  currentPrice,
+		"initial_stop":   order.CurrentStopPrice,
+	})
+
+	return order, nil
+}
+
+// readATRTrail computes the trail percent from the ATR of the newest closed candles, as a
+// share of the last close, clamped to the configured bounds. The candle still forming is
+// excluded so the trail only changes when a candle closes.
+func (s *stopLossService) readATRTrail(symbol string, trail ATRTrail) (*atrReading, error) {
+	// Extra candles let Wilder smoothing settle; one more covers the candle still forming
+	klines, err := s.marketService.GetHistoricalData(symbol, trail.Interval, 3*trail.Period+2)
+	if err != nil {
+		return nil, err
+	}
+
+	nowMs := s.now().UnixMilli()
+	closed := make([]*api.Kline, 0, len(klines))
+	for _, kline := range klines {
+		if kline.CloseTime < nowMs {
+			closed = append(closed, kline)
+		}
+	}
+
+	atr, err := AverageTrueRange(closed, trail.Period)
+	if err != nil {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter,
+			fmt.Sprintf("not enough %s klines for %s: %s", trail.Interval, symbol, err.Error()), 0, nil)
+	}
+
+	last := closed[len(closed)-1]
+	if last.Close <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter,
+			fmt.Sprintf("invalid close price for %s", symbol), 0, nil)
+	}
+
+	s.mu.RLock()
+	minPercent, maxPercent := s.minTrailPercent, s.maxTrailPercent
+	s.mu.RUnlock()
+
+	percent := atr * trail.Multiplier / last.Close * 100
+	percent = math.Min(math.Max(percent, minPercent), maxPercent)
+
+	return &atrReading{ATR: atr, TrailPercent: percent, CandleClose: last.CloseTime}, nil
+}
+
+// refreshATRTrail recomputes the trail of an ATR trailing stop once a candle has closed
+// since the last computation, moving the stop with the new trail from the highest price.
+// The trail may widen as well as narrow. It reports whether the order changed.
+func (s *stopLossService) refreshATRTrail(order *repository.TrailingStopOrder) bool {
+	interval, ok := api.KlineIntervalDuration(order.ATRInterval)
+	if !ok || s.now().UnixMilli() <= order.LastCandleClose+interval.Milliseconds() {
+		return false
+	}
+
+	reading, err := s.readATRTrail(order.Symbol, ATRTrail{
+		Period:     order.ATRPeriod,
+		Multiplier: order.ATRMultiplier,
+		Interval:   order.ATRInterval,
+	})
+	if err != nil {
+		// The previous trail stays in effect until the next attempt
+		s.logger.Warn("Failed to recompute ATR trail", map[string]interface{}{
+			"order_id": order.OrderID,
+			"symbol":   order.Symbol,
+			"error":    err.Error(),
+		})
+		return false
+	}
+	if reading.CandleClose <= order.LastCandleClose {
+		return false
+	}
+
+	previous := order.TrailPercent
+	order.ATR = reading.ATR
+	order.TrailPercent = reading.TrailPercent
+	order.LastCandleClose = reading.CandleClose
+	order.CurrentStopPrice = order.HighestPrice * (1 - reading.TrailPercent/100)
+	order.LastUpdatedAt = timeutil.Millis(s.now())
+
+	if err := s.stopOrderRepo.UpdateTrailingStopOrder(order); err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation": "refresh_atr_trail",
+			"order_id":  order.OrderID,
+		})
+		return false
+	}
+
+	s.logger.Debug("ATR trail recomputed", map[string]interface{}{
+		"order_id":       order.OrderID,
+		"symbol":         order.Symbol,
+		"atr":            reading.ATR,
+		"previous_trail": previous,
+		"trail_percent":  reading.TrailPercent,
+		"new_stop_price": order.CurrentStopPrice,
+	})
+	return true
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/internal/repository"
+	"math"
+	"testing"
+	"time"
+)
+
+// atrFixtureStart is the open time of the first fixture candle
+var atrFixtureStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// atrFixture is an hourly series with hand-calculated ATR(3) values (Wilder smoothing):
+//
+//	candle  high  low  close  true range
+//	0       102   98   100    -
+//	1       105   99   104    6   (high-low)
+//	2       106   101  102    5   (high-low)
+//	3       103   97   98     6   (high-low)        ATR = (6+5+6)/3     = 17/3
+//	4       104   102  103    6   (high-prevClose)  ATR = (17/3×2+6)/3  = 52/9
+//	5       110   100  108    10  (high-low)        ATR = (52/9×2+10)/3 = 194/27
+//	6       109   107  108    2   (high-low)        ATR = (194/27×2+2)/3 = 442/81
+func atrFixture() []*api.Kline {
+	bars := [][3]float64{
+		{102, 98, 100},
+		{105, 99, 104},
+		{106, 101, 102},
+		{103, 97, 98},
+		{104, 102, 103},
+		{110, 100, 108},
+		{109, 107, 108},
+	}
+
+	klines := make([]*api.Kline, len(bars))
+	for i, bar := range bars {
+		open := atrFixtureStart.Add(time.Duration(i) * time.Hour)
+		klines[i] = &api.Kline{
+			OpenTime:  open.UnixMilli(),
+			High:      bar[0],
+			Low:       bar[1],
+			Close:     bar[2],
+			CloseTime: open.Add(time.Hour).UnixMilli() - 1,
+		}
+	}
+	return klines
+}
+
+// atrTrailAt returns the time just after candle i of the fixture closed
+func atrTrailAt(i int) time.Time {
+	return atrFixtureStart.Add(time.Duration(i+1) * time.Hour).Add(time.Second)
+}
+
+// klineMarketDataService serves the fixture candles opened before the current time, so the
+// newest one is still forming
+type klineMarketDataService struct {
+	mockStopLossMarketDataService
+	klines   []*api.Kline
+	now      func() time.Time
+	requests int
+	interval string
+	limit    int
+}
+
+func (m *klineMarketDataService) GetHistoricalData(symbol string, interval string, limit int) ([]*api.Kline, error) {
+	m.requests++
+	m.interval = interval
+	m.limit = limit
+
+	var result []*api.Kline
+	for _, kline := range m.klines {
+		if kline.OpenTime <= m.now().UnixMilli() {
+			result = append(result, kline)
+		}
+	}
+	return result, nil
+}
+
+func assertNear(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("%s: expected %.10f, got %.10f", name, want, got)
+	}
+}
+
+func TestAverageTrueRange(t *testing.T) {
+	klines := atrFixture()
+
+	tests := []struct {
+		candles int
+		want    float64
+	}{
+		{4, 17.0 / 3},
+		{5, 52.0 / 9},
+		{6, 194.0 / 27},
+		{7, 442.0 / 81},
+	}
+	for _, tt := range tests {
+		atr, err := AverageTrueRange(klines[:tt.candles], 3)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		assertNear(t, "ATR", atr, tt.want)
+	}
+
+	if _, err := AverageTrueRange(klines[:3], 3); err == nil {
+		t.Error("expected an error with fewer than period+1 klines")
+	}
+	if _, err := AverageTrueRange(klines, 0); err == nil {
+		t.Error("expected an error for a zero period")
+	}
+}
+
+func newATRTestService(current *time.Time) (StopLossService, *klineMarketDataService, repository.StopOrderRepository) {
+	market := &klineMarketDataService{
+		mockStopLossMarketDataService: mockStopLossMarketDataService{currentPrice: 108},
+		klines:                        atrFixture(),
+		now:                           func() time.Time { return *current },
+	}
+	repo := repository.NewMemoryStopOrderRepository()
+	svc := NewStopLossService(repo, NewTriggerEngine(), &mockStopLossTradingService{}, market, &mockLogger{})
+	svc.(*stopLossService).now = market.now
+	return svc, market, repo
+}
+
+func TestSetATRTrailingStop(t *testing.T) {
+	current := atrTrailAt(5)
+	svc, market, _ := newATRTestService(&current)
+
+	order, err := svc.SetATRTrailingStop("BTCUSDT", 1.0, ATRTrail{Period: 3, Multiplier: 0.5})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if market.interval != DefaultATRInterval || market.limit != 11 {
+		t.Errorf("expected %s klines with limit 11, got %s with %d", DefaultATRInterval, market.interval, market.limit)
+	}
+
+	// Candle 6 is still forming, so the trail comes from candles 0-5
+	wantTrail := 194.0 / 27 * 0.5 / 108 * 100
+	assertNear(t, "ATR", order.ATR, 194.0/27)
+	assertNear(t, "trail percent", order.TrailPercent, wantTrail)
+	assertNear(t, "stop price", order.CurrentStopPrice, 108*(1-wantTrail/100))
+
+	if order.TrailMode != repository.TrailModeATR || order.ATRPeriod != 3 || order.ATRMultiplier != 0.5 || order.ATRInterval != "1h" {
+		t.Errorf("unexpected ATR settings %+v", order)
+	}
+	if order.LastCandleClose != atrFixture()[5].CloseTime {
+		t.Errorf("expected the close time of candle 5, got %d", order.LastCandleClose)
+	}
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		invalid := []ATRTrail{
+			{Period: 0, Multiplier: 2},
+			{Period: 14, Multiplier: 0},
+			{Period: 14, Multiplier: 2, Interval: "90m"},
+		}
+		for _, trail := range invalid {
+			if _, err := svc.SetATRTrailingStop("BTCUSDT", 1.0, trail); err == nil {
+				t.Errorf("expected an error for %+v", trail)
+			}
+		}
+	})
+
+	t.Run("not enough klines", func(t *testing.T) {
+		if _, err := svc.SetATRTrailingStop("BTCUSDT", 1.0, ATRTrail{Period: 14, Multiplier: 2}); err == nil {
+			t.Error("expected an error when the history is shorter than the period")
+		}
+	})
+}
+
+func TestATRTrailBounds(t *testing.T) {
+	current := atrTrailAt(5)
+	svc, _, _ := newATRTestService(&current)
+	svc.SetTrailConfig(&config.StopLossConfig{MinTrailPercent: 1, MaxTrailPercent: 5, ATRInterval: "1h"})
+
+	wide, err := svc.SetATRTrailingStop("BTCUSDT", 1.0, ATRTrail{Period: 3, Multiplier: 10})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	assertNear(t, "max bound", wide.TrailPercent, 5)
+
+	tight, err := svc.SetATRTrailingStop("BTCUSDT", 1.0, ATRTrail{Period: 3, Multiplier: 0.01})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	assertNear(t, "min bound", tight.TrailPercent, 1)
+}
+
+func TestATRTrailRecomputedOnCandleClose(t *testing.T) {
+	current := atrTrailAt(4)
+	svc, market, repo := newATRTestService(&current)
+	sls := svc.(*stopLossService)
+
+	order, err := svc.SetATRTrailingStop("BTCUSDT", 1.0, ATRTrail{Period: 3, Multiplier: 0.5})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	assertNear(t, "initial trail", order.TrailPercent, 52.0/9*0.5/103*100)
+
+	// No candle closed yet: the trail is kept without fetching klines
+	requests := market.requests
+	current = current.Add(30 * time.Minute)
+	if _, err := sls.UpdateTrailingStopPrice(order.OrderID, 107); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if market.requests != requests {
+		t.Errorf("expected no kline request before the candle closes, got %d", market.requests-requests)
+	}
+
+	// Candle 5 closes with a wide range: the trail widens from the highest price
+	current = atrTrailAt(5)
+	if _, err := sls.UpdateTrailingStopPrice(order.OrderID, 107); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	widened, _ := repo.FindTrailingStopOrderByID(order.OrderID)
+	wantWide := 194.0 / 27 * 0.5 / 108 * 100
+	assertNear(t, "widened trail", widened.TrailPercent, wantWide)
+	assertNear(t, "widened stop", widened.CurrentStopPrice, 108*(1-wantWide/100))
+	if widened.TrailPercent <= order.TrailPercent {
+		t.Errorf("expected the trail to widen, got %.4f after %.4f", widened.TrailPercent, order.TrailPercent)
+	}
+
+	// Candle 6 is calm: the trail narrows
+	current = atrTrailAt(6)
+	if _, err := sls.UpdateTrailingStopPrice(order.OrderID, 107); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	narrowed, _ := repo.FindTrailingStopOrderByID(order.OrderID)
+	wantNarrow := 442.0 / 81 * 0.5 / 108 * 100
+	assertNear(t, "narrowed trail", narrowed.TrailPercent, wantNarrow)
+	assertNear(t, "narrowed stop", narrowed.CurrentStopPrice, 108*(1-wantNarrow/100))
+
+	// An explicit percent ends ATR mode
+	if err := svc.UpdateTrailingStop(order.OrderID, 2.0); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	fixed, _ := repo.FindTrailingStopOrderByID(order.OrderID)
+	if fixed.TrailMode != repository.TrailModePercent || fixed.TrailPercent != 2.0 {
+		t.Errorf("expected a fixed 2%% trail, got %s %.2f", fixed.TrailMode, fixed.TrailPercent)
+	}
+}
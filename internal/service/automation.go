@@ -0,0 +1,673 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/pkg/errors"
+	"binance-trader/pkg/logger"
+	"binance-trader/pkg/timeutil"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultMaxDCAPlans is used when automation.max_dca_plans is not configured
+	DefaultMaxDCAPlans = 10
+	// DefaultMaxGrids is used when automation.max_grids is not configured
+	DefaultMaxGrids = 5
+	// DefaultAutomationCheckInterval is used when automation.check_interval_ms is not configured
+	DefaultAutomationCheckInterval = 5 * time.Second
+)
+
+// PlanStatus represents the status of an automation plan
+type PlanStatus string
+
+const (
+	PlanStatusActive  PlanStatus = "ACTIVE"
+	PlanStatusStopped PlanStatus = "STOPPED"
+)
+
+// DCAPlan represents a dollar-cost averaging plan: a market buy of Quantity every Interval
+type DCAPlan struct {
+	PlanID    string
+	Symbol    string
+	Quantity  float64
+	Interval  time.Duration
+	Status    PlanStatus
+	CreatedAt int64 // Unix ms
+	StoppedAt int64 // Unix ms
+
+	// Execution
+	NextRunAt   int64  // Unix ms of the next buy; the first one is placed right after creation
+	Executions  int    // Buys placed so far
+	LastOrderID int64  // Exchange order ID of the last buy
+	LastError   string // Why the last buy failed, empty after a success
+}
+
+// GridPlan represents a grid trading plan. GridCount price levels are spread evenly from
+// LowerPrice to UpperPrice; a limit buy rests on every level below the price, and each fill
+// is answered with the opposite order one level away.
+type GridPlan struct {
+	PlanID          string
+	Symbol          string
+	LowerPrice      float64
+	UpperPrice      float64
+	GridCount       int
+	QuantityPerGrid float64
+	Status          PlanStatus
+	CreatedAt       int64 // Unix ms
+	StoppedAt       int64 // Unix ms
+
+	// Execution
+	Orders       []*GridOrder // Orders resting on the grid, or waiting to be placed
+	Started      bool         // The initial buys were placed
+	FilledOrders int          // Grid orders filled so far
+	LastError    string       // Why the last placement failed, empty after a success
+}
+
+// GridOrder is an order of a grid plan on one of its levels
+type GridOrder struct {
+	Level   int
+	Price   float64
+	Side    api.OrderSide
+	OrderID int64 // 0 until the order is placed
+}
+
+// copy returns a copy of the plan that shares none of its orders
+func (p *GridPlan) copy() *GridPlan {
+	planCopy := *p
+	planCopy.Orders = make([]*GridOrder, len(p.Orders))
+	for i, order := range p.Orders {
+		orderCopy := *order
+		planCopy.Orders[i] = &orderCopy
+	}
+	return &planCopy
+}
+
+// levelPrice returns the price of a grid level, level 0 being LowerPrice
+func (p *GridPlan) levelPrice(level int) float64 {
+	return p.LowerPrice + float64(level)*(p.UpperPrice-p.LowerPrice)/float64(p.GridCount-1)
+}
+
+// AutomationStatus summarizes all running automation plans
+type AutomationStatus struct {
+	ActiveDCAPlans int
+	MaxDCAPlans    int
+	ActiveGrids    int
+	MaxGrids       int
+	DCAPlans       []*DCAPlan
+	GridPlans      []*GridPlan
+}
+
+// AutomationService defines the interface for managing DCA and grid plans
+type AutomationService interface {
+	// DCA plans
+	CreateDCAPlan(symbol string, quantity float64, interval time.Duration) (*DCAPlan, error)
+	StopDCAPlan(planID string) error
+	ListDCAPlans() ([]*DCAPlan, int)
+
+	// Grid plans
+	CreateGridPlan(symbol string, lowerPrice, upperPrice float64, gridCount int, quantityPerGrid float64) (*GridPlan, error)
+	StopGridPlan(planID string) error
+	ListGridPlans() ([]*GridPlan, int)
+
+	// Summary
+	GetStatus() *AutomationStatus
+
+	// RunPlans places the orders that are due: the buys of DCA plans whose interval has
+	// elapsed, and for grid plans the initial buys and the orders answering fills
+	RunPlans()
+
+	// Periodic execution
+	StartMonitoring(checkInterval time.Duration) error
+	StopMonitoring() error
+}
+
+// automationService implements AutomationService interface
+type automationService struct {
+	trading     SpotTradingService
+	marketData  MarketDataService
+	maxDCAPlans int
+	maxGrids    int
+	dcaPlans    map[string]*DCAPlan
+	gridPlans   map[string]*GridPlan
+	logger      logger.Logger
+	mu          sync.RWMutex
+	now         func() time.Time
+
+	// runMu serializes order placement with stopping plans, so no order of a stopped plan
+	// is placed after its stop returns
+	runMu sync.Mutex
+
+	// Monitoring
+	stopChan     chan struct{}
+	monitoringMu sync.Mutex
+	isMonitoring bool
+}
+
+// NewAutomationService creates a new automation service placing plan orders through trading
+func NewAutomationService(trading SpotTradingService, marketData MarketDataService, cfg *config.AutomationConfig, log logger.Logger) AutomationService {
+	maxDCAPlans := DefaultMaxDCAPlans
+	maxGrids := DefaultMaxGrids
+	if cfg != nil {
+		if cfg.MaxDCAPlans > 0 {
+			maxDCAPlans = cfg.MaxDCAPlans
+		}
+		if cfg.MaxGrids > 0 {
+			maxGrids = cfg.MaxGrids
+		}
+	}
+
+	return &automationService{
+		trading:     trading,
+		marketData:  marketData,
+		maxDCAPlans: maxDCAPlans,
+		maxGrids:    maxGrids,
+		dcaPlans:    make(map[string]*DCAPlan),
+		gridPlans:   make(map[string]*GridPlan),
+		logger:      log,
+		now:         time.Now,
+	}
+}
+
+// CreateDCAPlan creates a new DCA plan if the active plan cap allows it
+func (s *automationService) CreateDCAPlan(symbol string, quantity float64, interval time.Duration) (*DCAPlan, error) {
+	// Validate input parameters
+	if symbol == "" {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "symbol cannot be empty", 0, nil)
+	}
+	if quantity <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "quantity must be greater than 0", 0, nil)
+	}
+	if interval <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "interval must be greater than 0", 0, nil)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Enforce the active plan cap
+	if active := s.countActiveDCAPlans(); active >= s.maxDCAPlans {
+		return nil, errors.NewTradingError(
+			errors.ErrRiskLimitExceeded,
+			fmt.Sprintf("maximum active DCA plans reached (%d/%d)", active, s.maxDCAPlans),
+			0,
+			nil,
+		)
+	}
+
+	now := timeutil.Millis(s.now())
+	plan := &DCAPlan{
+		PlanID:    uuid.New().String(),
+		Symbol:    symbol,
+		Quantity:  quantity,
+		Interval:  interval,
+		Status:    PlanStatusActive,
+		CreatedAt: now,
+		NextRunAt: now,
+	}
+	s.dcaPlans[plan.PlanID] = plan
+
+	s.logger.Info("DCA plan created", map[string]interface{}{
+		"plan_id":  plan.PlanID,
+		"symbol":   symbol,
+		"quantity": quantity,
+		"interval": interval.String(),
+	})
+
+	planCopy := *plan
+	return &planCopy, nil
+}
+
+// StopDCAPlan stops an active DCA plan
+func (s *automationService) StopDCAPlan(planID string) error {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plan, exists := s.dcaPlans[planID]
+	if !exists {
+		return errors.NewTradingError(errors.ErrInvalidParameter, fmt.Sprintf("DCA plan not found: %s", planID), 0, nil)
+	}
+	if plan.Status != PlanStatusActive {
+		return errors.NewTradingError(errors.ErrInvalidParameter, fmt.Sprintf("DCA plan is not active: %s", planID), 0, nil)
+	}
+
+	plan.Status = PlanStatusStopped
+	plan.StoppedAt = timeutil.Millis(s.now())
+
+	s.logger.Info("DCA plan stopped", map[string]interface{}{
+		"plan_id": planID,
+		"symbol":  plan.Symbol,
+	})
+
+	return nil
+}
+
+// ListDCAPlans returns all DCA plans and the number of active ones
+func (s *automationService) ListDCAPlans() ([]*DCAPlan, int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	plans := make([]*DCAPlan, 0, len(s.dcaPlans))
+	for _, plan := range s.dcaPlans {
+		planCopy := *plan
+		plans = append(plans, &planCopy)
+	}
+	sort.Slice(plans, func(i, j int) bool {
+		if plans[i].CreatedAt != plans[j].CreatedAt {
+			return plans[i].CreatedAt < plans[j].CreatedAt
+		}
+		return plans[i].PlanID < plans[j].PlanID
+	})
+
+	return plans, s.countActiveDCAPlans()
+}
+
+// CreateGridPlan creates a new grid plan if the active grid cap allows it
+func (s *automationService) CreateGridPlan(symbol string, lowerPrice, upperPrice float64, gridCount int, quantityPerGrid float64) (*GridPlan, error) {
+	// Validate input parameters
+	if symbol == "" {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "symbol cannot be empty", 0, nil)
+	}
+	if lowerPrice <= 0 || upperPrice <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "grid prices must be greater than 0", 0, nil)
+	}
+	if lowerPrice >= upperPrice {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "lower price must be less than upper price", 0, nil)
+	}
+	if gridCount < 2 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "grid count must be at least 2", 0, nil)
+	}
+	if quantityPerGrid <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "quantity per grid must be greater than 0", 0, nil)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Enforce the active grid cap
+	if active := s.countActiveGrids(); active >= s.maxGrids {
+		return nil, errors.NewTradingError(
+			errors.ErrRiskLimitExceeded,
+			fmt.Sprintf("maximum active grid plans reached (%d/%d)", active, s.maxGrids),
+			0,
+			nil,
+		)
+	}
+
+	plan := &GridPlan{
+		PlanID:          uuid.New().String(),
+		Symbol:          symbol,
+		LowerPrice:      lowerPrice,
+		UpperPrice:      upperPrice,
+		GridCount:       gridCount,
+		QuantityPerGrid: quantityPerGrid,
+		Status:          PlanStatusActive,
+		CreatedAt:       timeutil.Millis(s.now()),
+	}
+	s.gridPlans[plan.PlanID] = plan
+
+	s.logger.Info("Grid plan created", map[string]interface{}{
+		"plan_id":     plan.PlanID,
+		"symbol":      symbol,
+		"lower_price": lowerPrice,
+		"upper_price": upperPrice,
+		"grid_count":  gridCount,
+	})
+
+	return plan.copy(), nil
+}
+
+// StopGridPlan stops an active grid plan and cancels its resting orders
+func (s *automationService) StopGridPlan(planID string) error {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	s.mu.Lock()
+	plan, exists := s.gridPlans[planID]
+	if !exists {
+		s.mu.Unlock()
+		return errors.NewTradingError(errors.ErrInvalidParameter, fmt.Sprintf("grid plan not found: %s", planID), 0, nil)
+	}
+	if plan.Status != PlanStatusActive {
+		s.mu.Unlock()
+		return errors.NewTradingError(errors.ErrInvalidParameter, fmt.Sprintf("grid plan is not active: %s", planID), 0, nil)
+	}
+
+	plan.Status = PlanStatusStopped
+	plan.StoppedAt = timeutil.Millis(s.now())
+	orders := plan.Orders
+	plan.Orders = nil
+	symbol := plan.Symbol
+	s.mu.Unlock()
+
+	// Cancel outside the lock; placement cannot race with it while runMu is held
+	var failed []*GridOrder
+	var lastErr error
+	for _, order := range orders {
+		if order.OrderID == 0 {
+			continue
+		}
+		if err := s.trading.CancelOrder(order.OrderID); err != nil {
+			failed = append(failed, order)
+			lastErr = err
+			s.logger.Warn("Failed to cancel grid order", map[string]interface{}{
+				"plan_id":  planID,
+				"symbol":   symbol,
+				"order_id": order.OrderID,
+				"error":    err.Error(),
+			})
+		}
+	}
+
+	s.logger.Info("Grid plan stopped", map[string]interface{}{
+		"plan_id":          planID,
+		"symbol":           symbol,
+		"cancelled_orders": len(orders) - len(failed),
+	})
+
+	if len(failed) > 0 {
+		// Keep the orders still resting listed on the plan
+		s.mu.Lock()
+		plan.Orders = failed
+		s.mu.Unlock()
+		return fmt.Errorf("grid plan %s stopped, but %d of its orders could not be cancelled: %w", planID, len(failed), lastErr)
+	}
+
+	return nil
+}
+
+// ListGridPlans returns all grid plans and the number of active ones
+func (s *automationService) ListGridPlans() ([]*GridPlan, int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	plans := make([]*GridPlan, 0, len(s.gridPlans))
+	for _, plan := range s.gridPlans {
+		plans = append(plans, plan.copy())
+	}
+	sort.Slice(plans, func(i, j int) bool {
+		if plans[i].CreatedAt != plans[j].CreatedAt {
+			return plans[i].CreatedAt < plans[j].CreatedAt
+		}
+		return plans[i].PlanID < plans[j].PlanID
+	})
+
+	return plans, s.countActiveGrids()
+}
+
+// GetStatus returns a summary of all running plans
+func (s *automationService) GetStatus() *AutomationStatus {
+	dcaPlans, activeDCA := s.ListDCAPlans()
+	gridPlans, activeGrids := s.ListGridPlans()
+
+	status := &AutomationStatus{
+		ActiveDCAPlans: activeDCA,
+		MaxDCAPlans:    s.maxDCAPlans,
+		ActiveGrids:    activeGrids,
+		MaxGrids:       s.maxGrids,
+	}
+
+	for _, plan := range dcaPlans {
+		if plan.Status == PlanStatusActive {
+			status.DCAPlans = append(status.DCAPlans, plan)
+		}
+	}
+	for _, plan := range gridPlans {
+		if plan.Status == PlanStatusActive {
+			status.GridPlans = append(status.GridPlans, plan)
+		}
+	}
+
+	return status
+}
+
+// countActiveDCAPlans counts active DCA plans (caller must hold the lock)
+func (s *automationService) countActiveDCAPlans() int {
+	count := 0
+	for _, plan := range s.dcaPlans {
+		if plan.Status == PlanStatusActive {
+			count++
+		}
+	}
+	return count
+}
+
+// countActiveGrids counts active grid plans (caller must hold the lock)
+func (s *automationService) countActiveGrids() int {
+	count := 0
+	for _, plan := range s.gridPlans {
+		if plan.Status == PlanStatusActive {
+			count++
+		}
+	}
+	return count
+}
+
+// RunPlans places the orders that are due for every active plan
+func (s *automationService) RunPlans() {
+	if s.trading == nil {
+		return
+	}
+
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	dcaPlans, _ := s.ListDCAPlans()
+	now := timeutil.Millis(s.now())
+	for _, plan := range dcaPlans {
+		if plan.Status == PlanStatusActive && plan.NextRunAt <= now {
+			s.runDCAPlan(plan)
+		}
+	}
+
+	gridPlans, _ := s.ListGridPlans()
+	for _, plan := range gridPlans {
+		if plan.Status == PlanStatusActive {
+			s.runGridPlan(plan)
+		}
+	}
+}
+
+// runDCAPlan places the buy of a due DCA plan and schedules the next one. A failed buy is
+// not retried before the next interval, so an outage cannot turn into a burst of buys.
+func (s *automationService) runDCAPlan(plan *DCAPlan) {
+	order, err := s.trading.PlaceMarketBuyOrder(plan.Symbol, plan.Quantity)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := s.dcaPlans[plan.PlanID]
+	stored.NextRunAt = timeutil.Millis(s.now().Add(stored.Interval))
+	if err != nil {
+		stored.LastError = err.Error()
+		s.logger.Warn("DCA buy failed", map[string]interface{}{
+			"plan_id": plan.PlanID,
+			"symbol":  plan.Symbol,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	stored.Executions++
+	stored.LastOrderID = order.OrderID
+	stored.LastError = ""
+	s.logger.Info("DCA buy placed", map[string]interface{}{
+		"plan_id":    plan.PlanID,
+		"symbol":     plan.Symbol,
+		"quantity":   plan.Quantity,
+		"order_id":   order.OrderID,
+		"executions": stored.Executions,
+	})
+}
+
+// runGridPlan lays out the initial buys of a grid on its first run, then answers every fill
+// with the opposite order one level away and places the orders still waiting
+func (s *automationService) runGridPlan(plan *GridPlan) {
+	var lastErr error
+
+	if !plan.Started {
+		price, err := s.marketData.GetCurrentPrice(plan.Symbol)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to get current price: %w", err)
+		} else {
+			// No buy on the top level: there is no level above it to sell on
+			plan.Orders = nil
+			for level := 0; level < plan.GridCount-1; level++ {
+				if levelPrice := plan.levelPrice(level); levelPrice < price {
+					plan.Orders = append(plan.Orders, &GridOrder{Level: level, Price: levelPrice, Side: api.OrderSideBuy})
+				}
+			}
+		}
+	} else {
+		orders := make([]*GridOrder, 0, len(plan.Orders))
+		for _, order := range plan.Orders {
+			if order.OrderID == 0 {
+				orders = append(orders, order)
+				continue
+			}
+			status, err := s.trading.GetOrderStatus(order.OrderID)
+			if err != nil {
+				lastErr = fmt.Errorf("failed to get status of order %d: %w", order.OrderID, err)
+				orders = append(orders, order)
+				continue
+			}
+
+			switch status.Status {
+			case api.OrderStatusFilled:
+				plan.FilledOrders++
+				counter := &GridOrder{Level: order.Level + 1, Side: api.OrderSideSell}
+				if order.Side == api.OrderSideSell {
+					counter = &GridOrder{Level: order.Level - 1, Side: api.OrderSideBuy}
+				}
+				counter.Price = plan.levelPrice(counter.Level)
+				orders = append(orders, counter)
+				s.logger.Info("Grid order filled", map[string]interface{}{
+					"plan_id":  plan.PlanID,
+					"symbol":   plan.Symbol,
+					"order_id": order.OrderID,
+					"side":     order.Side,
+					"price":    order.Price,
+				})
+			case api.OrderStatusCanceled, api.OrderStatusRejected, api.OrderStatusExpired:
+				// Cancelled outside the plan; its level stays empty
+				s.logger.Warn("Grid order ended without a fill", map[string]interface{}{
+					"plan_id":  plan.PlanID,
+					"symbol":   plan.Symbol,
+					"order_id": order.OrderID,
+					"status":   status.Status,
+				})
+			default:
+				orders = append(orders, order)
+			}
+		}
+		plan.Orders = orders
+	}
+
+	placed := 0
+	for _, order := range plan.Orders {
+		if order.OrderID != 0 {
+			continue
+		}
+		var result *api.Order
+		var err error
+		if order.Side == api.OrderSideBuy {
+			result, err = s.trading.PlaceLimitBuyOrder(plan.Symbol, order.Price, plan.QuantityPerGrid)
+		} else {
+			result, err = s.trading.PlaceLimitSellOrder(plan.Symbol, order.Price, plan.QuantityPerGrid)
+		}
+		if err != nil {
+			lastErr = fmt.Errorf("failed to place %s at %v: %w", order.Side, order.Price, err)
+			continue
+		}
+		order.OrderID = result.OrderID
+		placed++
+	}
+	if !plan.Started && placed > 0 {
+		// Until a first order rests, the layout is redone from the price at the next run
+		plan.Started = true
+	}
+
+	if lastErr != nil {
+		plan.LastError = lastErr.Error()
+		s.logger.Warn("Grid plan run incomplete", map[string]interface{}{
+			"plan_id": plan.PlanID,
+			"symbol":  plan.Symbol,
+			"error":   lastErr.Error(),
+		})
+	} else {
+		plan.LastError = ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := s.gridPlans[plan.PlanID]
+	stored.Orders = plan.Orders
+	stored.Started = plan.Started
+	stored.FilledOrders = plan.FilledOrders
+	stored.LastError = plan.LastError
+}
+
+// StartMonitoring starts running due plans on every interval
+func (s *automationService) StartMonitoring(checkInterval time.Duration) error {
+	s.monitoringMu.Lock()
+	defer s.monitoringMu.Unlock()
+
+	if s.isMonitoring {
+		return fmt.Errorf("monitoring is already running")
+	}
+
+	if checkInterval <= 0 {
+		checkInterval = DefaultAutomationCheckInterval
+	}
+
+	s.stopChan = make(chan struct{})
+	s.isMonitoring = true
+
+	go s.monitoringLoop(checkInterval)
+
+	s.logger.Info("Started automation plan execution", map[string]interface{}{
+		"check_interval": checkInterval.String(),
+	})
+
+	return nil
+}
+
+// StopMonitoring stops running plans; resting grid orders stay on the exchange
+func (s *automationService) StopMonitoring() error {
+	s.monitoringMu.Lock()
+	defer s.monitoringMu.Unlock()
+
+	if !s.isMonitoring {
+		return fmt.Errorf("monitoring is not running")
+	}
+
+	close(s.stopChan)
+	s.isMonitoring = false
+
+	s.logger.Info("Stopped automation plan execution", nil)
+
+	return nil
+}
+
+// monitoringLoop runs due plans on every tick
+func (s *automationService) monitoringLoop(checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			s.RunPlans()
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/pkg/errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// automationTradingService records plan orders and lets tests fill or fail them
+type automationTradingService struct {
+	mockStopLossTradingService
+	nextID    int64
+	buyErr    error
+	buys      []float64
+	limits    map[int64]*api.Order
+	statuses  map[int64]api.OrderStatus
+	cancelled []int64
+}
+
+func newAutomationTradingService() *automationTradingService {
+	return &automationTradingService{limits: make(map[int64]*api.Order), statuses: make(map[int64]api.OrderStatus)}
+}
+
+func (m *automationTradingService) PlaceMarketBuyOrder(symbol string, quantity float64) (*api.Order, error) {
+	if m.buyErr != nil {
+		return nil, m.buyErr
+	}
+	m.nextID++
+	m.buys = append(m.buys, quantity)
+	return &api.Order{OrderID: m.nextID, Symbol: symbol, Status: api.OrderStatusFilled}, nil
+}
+
+func (m *automationTradingService) placeLimit(symbol string, side api.OrderSide, price, quantity float64) (*api.Order, error) {
+	m.nextID++
+	order := &api.Order{OrderID: m.nextID, Symbol: symbol, Side: side, Price: price, OrigQty: quantity, Status: api.OrderStatusNew}
+	m.limits[order.OrderID] = order
+	m.statuses[order.OrderID] = api.OrderStatusNew
+	return order, nil
+}
+
+func (m *automationTradingService) PlaceLimitBuyOrder(symbol string, price, quantity float64) (*api.Order, error) {
+	return m.placeLimit(symbol, api.OrderSideBuy, price, quantity)
+}
+
+func (m *automationTradingService) PlaceLimitSellOrder(symbol string, price, quantity float64) (*api.Order, error) {
+	return m.placeLimit(symbol, api.OrderSideSell, price, quantity)
+}
+
+func (m *automationTradingService) GetOrderStatus(orderID int64) (*OrderStatus, error) {
+	return &OrderStatus{OrderID: orderID, Status: m.statuses[orderID]}, nil
+}
+
+func (m *automationTradingService) CancelOrder(orderID int64) error {
+	m.cancelled = append(m.cancelled, orderID)
+	m.statuses[orderID] = api.OrderStatusCanceled
+	return nil
+}
+
+// restingOrder returns the order resting at price on the given side
+func (m *automationTradingService) restingOrder(side api.OrderSide, price float64) *api.Order {
+	for id, order := range m.limits {
+		if order.Side == side && order.Price == price && m.statuses[id] == api.OrderStatusNew {
+			return order
+		}
+	}
+	return nil
+}
+
+func TestAutomationService_DCAPlanCap(t *testing.T) {
+	svc := NewAutomationService(nil, nil, &config.AutomationConfig{MaxDCAPlans: 2, MaxGrids: 1}, &mockLogger{})
+
+	first, err := svc.CreateDCAPlan("BTCUSDT", 0.001, time.Hour)
+	if err != nil {
+		t.Fatalf("Expected first plan to be created, got %v", err)
+	}
+	if _, err := svc.CreateDCAPlan("ETHUSDT", 0.01, time.Hour); err != nil {
+		t.Fatalf("Expected second plan to be created, got %v", err)
+	}
+
+	// Third plan exceeds the cap
+	_, err = svc.CreateDCAPlan("BNBUSDT", 0.1, time.Hour)
+	if err == nil {
+		t.Fatal("Expected error when exceeding max DCA plans")
+	}
+	tradingErr, ok := err.(*errors.TradingError)
+	if !ok || tradingErr.Type != errors.ErrRiskLimitExceeded {
+		t.Errorf("Expected ErrRiskLimitExceeded, got %v", err)
+	}
+
+	// Stopping a plan frees a slot
+	if err := svc.StopDCAPlan(first.PlanID); err != nil {
+		t.Fatalf("Expected plan to be stopped, got %v", err)
+	}
+	if _, err := svc.CreateDCAPlan("BNBUSDT", 0.1, time.Hour); err != nil {
+		t.Errorf("Expected plan to be created after stopping one, got %v", err)
+	}
+
+	plans, active := svc.ListDCAPlans()
+	if len(plans) != 3 {
+		t.Errorf("Expected 3 plans, got %d", len(plans))
+	}
+	if active != 2 {
+		t.Errorf("Expected 2 active plans, got %d", active)
+	}
+}
+
+func TestAutomationService_GridPlanCap(t *testing.T) {
+	svc := NewAutomationService(nil, nil, &config.AutomationConfig{MaxDCAPlans: 1, MaxGrids: 1}, &mockLogger{})
+
+	if _, err := svc.CreateGridPlan("BTCUSDT", 45000, 55000, 10, 0.001); err != nil {
+		t.Fatalf("Expected grid plan to be created, got %v", err)
+	}
+
+	_, err := svc.CreateGridPlan("ETHUSDT", 2000, 3000, 5, 0.01)
+	if err == nil {
+		t.Fatal("Expected error when exceeding max grid plans")
+	}
+	tradingErr, ok := err.(*errors.TradingError)
+	if !ok || tradingErr.Type != errors.ErrRiskLimitExceeded {
+		t.Errorf("Expected ErrRiskLimitExceeded, got %v", err)
+	}
+
+	_, active := svc.ListGridPlans()
+	if active != 1 {
+		t.Errorf("Expected 1 active grid plan, got %d", active)
+	}
+}
+
+func TestAutomationService_InvalidGridPlan(t *testing.T) {
+	svc := NewAutomationService(nil, nil, nil, &mockLogger{})
+
+	if _, err := svc.CreateGridPlan("BTCUSDT", 55000, 45000, 10, 0.001); err == nil {
+		t.Error("Expected error when lower price is above upper price")
+	}
+	if _, err := svc.CreateGridPlan("BTCUSDT", 45000, 55000, 1, 0.001); err == nil {
+		t.Error("Expected error when grid count is less than 2")
+	}
+}
+
+func TestAutomationService_DefaultLimits(t *testing.T) {
+	svc := NewAutomationService(nil, nil, &config.AutomationConfig{}, &mockLogger{})
+
+	status := svc.GetStatus()
+	if status.MaxDCAPlans != DefaultMaxDCAPlans {
+		t.Errorf("Expected default max DCA plans %d, got %d", DefaultMaxDCAPlans, status.MaxDCAPlans)
+	}
+	if status.MaxGrids != DefaultMaxGrids {
+		t.Errorf("Expected default max grids %d, got %d", DefaultMaxGrids, status.MaxGrids)
+	}
+}
+
+func TestAutomationService_GetStatus(t *testing.T) {
+	svc := NewAutomationService(nil, nil, &config.AutomationConfig{MaxDCAPlans: 3, MaxGrids: 2}, &mockLogger{})
+
+	dca, _ := svc.CreateDCAPlan("BTCUSDT", 0.001, time.Hour)
+	svc.CreateDCAPlan("ETHUSDT", 0.01, 24*time.Hour)
+	svc.CreateGridPlan("BTCUSDT", 45000, 55000, 10, 0.001)
+	svc.StopDCAPlan(dca.PlanID)
+
+	status := svc.GetStatus()
+	if status.ActiveDCAPlans != 1 || len(status.DCAPlans) != 1 {
+		t.Errorf("Expected 1 active DCA plan, got %d (%d listed)", status.ActiveDCAPlans, len(status.DCAPlans))
+	}
+	if status.ActiveGrids != 1 || len(status.GridPlans) != 1 {
+		t.Errorf("Expected 1 active grid plan, got %d (%d listed)", status.ActiveGrids, len(status.GridPlans))
+	}
+	if status.DCAPlans[0].Symbol != "ETHUSDT" {
+		t.Errorf("Expected remaining DCA plan for ETHUSDT, got %s", status.DCAPlans[0].Symbol)
+	}
+}
+
+func TestAutomationService_RunDCAPlan(t *testing.T) {
+	trading := newAutomationTradingService()
+	svc := NewAutomationService(trading, &mockStopLossMarketDataService{}, nil, &mockLogger{}).(*automationService)
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	plan, err := svc.CreateDCAPlan("BTCUSDT", 0.001, time.Hour)
+	if err != nil {
+		t.Fatalf("CreateDCAPlan() error = %v", err)
+	}
+
+	// The first buy is placed right away, the next one only after the interval
+	svc.RunPlans()
+	now = now.Add(30 * time.Minute)
+	svc.RunPlans()
+	if len(trading.buys) != 1 || trading.buys[0] != 0.001 {
+		t.Fatalf("buys after 30m = %v, want one of 0.001", trading.buys)
+	}
+	now = now.Add(30 * time.Minute)
+	svc.RunPlans()
+	if len(trading.buys) != 2 {
+		t.Fatalf("buys after 1h = %v, want two", trading.buys)
+	}
+
+	// A failed buy waits for the next interval instead of retrying on every check
+	trading.buyErr = fmt.Errorf("insufficient balance")
+	now = now.Add(time.Hour)
+	svc.RunPlans()
+	svc.RunPlans()
+	plans, _ := svc.ListDCAPlans()
+	if plans[0].Executions != 2 || plans[0].LastError != "insufficient balance" || plans[0].NextRunAt != now.Add(time.Hour).UnixMilli() {
+		t.Errorf("plan after a failed buy = %+v", plans[0])
+	}
+
+	// A stopped plan places nothing more
+	trading.buyErr = nil
+	if err := svc.StopDCAPlan(plan.PlanID); err != nil {
+		t.Fatalf("StopDCAPlan() error = %v", err)
+	}
+	now = now.Add(2 * time.Hour)
+	svc.RunPlans()
+	if len(trading.buys) != 2 {
+		t.Errorf("buys after stop = %v, want two", trading.buys)
+	}
+}
+
+func TestAutomationService_RunGridPlan(t *testing.T) {
+	trading := newAutomationTradingService()
+	market := &mockStopLossMarketDataService{currentPrice: 2450}
+	svc := NewAutomationService(trading, market, nil, &mockLogger{})
+
+	// Levels 2000, 2250, 2500, 2750 and 3000; buys rest on the two below the price
+	plan, err := svc.CreateGridPlan("ETHUSDT", 2000, 3000, 5, 0.1)
+	if err != nil {
+		t.Fatalf("CreateGridPlan() error = %v", err)
+	}
+	svc.RunPlans()
+	if trading.restingOrder(api.OrderSideBuy, 2000) == nil || trading.restingOrder(api.OrderSideBuy, 2250) == nil || len(trading.limits) != 2 {
+		t.Fatalf("initial orders = %v, want buys at 2000 and 2250", trading.limits)
+	}
+
+	// A filled buy is answered with a sell one level up, and that sell with a buy again
+	buy := trading.restingOrder(api.OrderSideBuy, 2250)
+	trading.statuses[buy.OrderID] = api.OrderStatusFilled
+	svc.RunPlans()
+	sell := trading.restingOrder(api.OrderSideSell, 2500)
+	if sell == nil || sell.OrigQty != 0.1 {
+		t.Fatalf("no sell of 0.1 at 2500 after the buy at 2250 filled: %v", trading.limits)
+	}
+	trading.statuses[sell.OrderID] = api.OrderStatusFilled
+	svc.RunPlans()
+	if trading.restingOrder(api.OrderSideBuy, 2250) == nil {
+		t.Fatalf("no buy at 2250 after the sell at 2500 filled: %v", trading.limits)
+	}
+
+	plans, _ := svc.ListGridPlans()
+	if plans[0].FilledOrders != 2 || len(plans[0].Orders) != 2 {
+		t.Errorf("grid plan = %d filled, %d open; want 2 filled, 2 open", plans[0].FilledOrders, len(plans[0].Orders))
+	}
+
+	// Stopping the plan cancels what still rests
+	if err := svc.StopGridPlan(plan.PlanID); err != nil {
+		t.Fatalf("StopGridPlan() error = %v", err)
+	}
+	if len(trading.cancelled) != 2 {
+		t.Errorf("cancelled orders = %v, want the two resting buys", trading.cancelled)
+	}
+	svc.RunPlans()
+	if len(trading.limits) != 4 {
+		t.Errorf("orders placed after stop: %d in total, want 4", len(trading.limits))
+	}
+}
+
+func TestAutomationService_GridWaitsForPriceInRange(t *testing.T) {
+	trading := newAutomationTradingService()
+	market := &mockStopLossMarketDataService{currentPrice: 1900}
+	svc := NewAutomationService(trading, market, nil, &mockLogger{})
+
+	if _, err := svc.CreateGridPlan("ETHUSDT", 2000, 3000, 5, 0.1); err != nil {
+		t.Fatalf("CreateGridPlan() error = %v", err)
+	}
+	svc.RunPlans()
+	if len(trading.limits) != 0 {
+		t.Fatalf("orders placed below the range: %v", trading.limits)
+	}
+
+	// Above the range every level but the top one gets a buy
+	market.currentPrice = 3100
+	svc.RunPlans()
+	if len(trading.limits) != 4 || trading.restingOrder(api.OrderSideBuy, 3000) != nil {
+		t.Errorf("orders above the range = %v, want buys on the four lower levels", trading.limits)
+	}
+}
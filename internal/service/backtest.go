@@ -0,0 +1,288 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// backtestVolumeWindow is the window of volume conditions without one, like the 24h volume
+// the monitoring engine compares them against
+const backtestVolumeWindow = 24 * time.Hour
+
+// BacktestTrade is a conditional order that triggered during a backtest
+type BacktestTrade struct {
+	OrderID     string
+	Side        api.OrderSide
+	Quantity    float64
+	CandleIndex int     // Index of the trigger candle in the replayed klines
+	TriggerTime int64   // Close time of the trigger candle, Unix ms
+	EntryPrice  float64 // Close of the trigger candle
+	ExitPrice   float64 // Close of the last candle
+	PnL         float64
+}
+
+// BacktestReport is the outcome of replaying klines through conditional orders
+type BacktestReport struct {
+	Candles     int
+	Trades      []*BacktestTrade // In trigger order
+	Untriggered []string         // IDs of the orders that never triggered
+	Wins        int
+	Losses      int
+	TotalPnL    float64
+}
+
+// Backtester replays historical klines through conditional orders with the trigger engine the
+// monitoring engine uses, so a strategy triggers on the same candles it would have live. Each
+// candle is one tick at its close price; an order triggers once, on the first candle meeting its
+// condition within its time window, and is entered at that close. Price, price change, volume
+// and time conditions and composites of them can be backtested; conditions needing the order
+// book or state between ticks cannot.
+type Backtester struct {
+	triggerEngine TriggerEngine
+}
+
+// NewBacktester creates a backtester evaluating conditions with triggerEngine
+func NewBacktester(triggerEngine TriggerEngine) *Backtester {
+	return &Backtester{triggerEngine: triggerEngine}
+}
+
+// Run replays klines, oldest first, through orders. The P&L of a triggered order assumes entry
+// at the trigger price and exit at the close of the last candle: a BUY gains when the price
+// rose, a SELL when it fell.
+func (b *Backtester) Run(klines []*api.Kline, orders []*repository.ConditionalOrder) (*BacktestReport, error) {
+	if len(klines) == 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "no klines to replay", 0, nil)
+	}
+	for _, order := range orders {
+		if err := validateBacktestOrder(order); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &BacktestReport{Candles: len(klines)}
+	pending := orders
+	for i, kline := range klines {
+		remaining := make([]*repository.ConditionalOrder, 0, len(pending))
+		for _, order := range pending {
+			if !backtestInTimeWindow(order, kline) {
+				remaining = append(remaining, order)
+				continue
+			}
+			met, err := b.evaluate(order.TriggerCondition, klines[:i+1])
+			if err != nil {
+				return nil, fmt.Errorf("failed to evaluate order %s at candle %d: %w", order.OrderID, i, err)
+			}
+			if !met {
+				remaining = append(remaining, order)
+				continue
+			}
+			report.Trades = append(report.Trades, &BacktestTrade{
+				OrderID:     order.OrderID,
+				Side:        order.Side,
+				Quantity:    order.Quantity,
+				CandleIndex: i,
+				TriggerTime: kline.CloseTime,
+				EntryPrice:  kline.Close,
+			})
+		}
+		pending = remaining
+	}
+
+	exitPrice := klines[len(klines)-1].Close
+	for _, trade := range report.Trades {
+		trade.ExitPrice = exitPrice
+		trade.PnL = (exitPrice - trade.EntryPrice) * trade.Quantity
+		if trade.Side == api.OrderSideSell {
+			trade.PnL = -trade.PnL
+		}
+		report.TotalPnL += trade.PnL
+		switch {
+		case trade.PnL > 0:
+			report.Wins++
+		case trade.PnL < 0:
+			report.Losses++
+		}
+	}
+	for _, order := range pending {
+		report.Untriggered = append(report.Untriggered, order.OrderID)
+	}
+	return report, nil
+}
+
+// evaluate evaluates a condition at the last of the replayed klines; composites are evaluated
+// sub-condition by sub-condition, each against its own value, like the monitoring engine does
+func (b *Backtester) evaluate(condition *repository.TriggerCondition, history []*api.Kline) (bool, error) {
+	if len(condition.SubConditions) == 0 {
+		return b.triggerEngine.EvaluateCondition(b.convertToServiceTriggerCondition(condition), backtestValue(condition, history))
+	}
+
+	met := condition.CompositeType == repository.LogicAND
+	for _, subCondition := range condition.SubConditions {
+		subMet, err := b.evaluate(subCondition, history)
+		if err != nil {
+			return false, err
+		}
+		if condition.CompositeType == repository.LogicAND {
+			met = met && subMet
+		} else {
+			met = met || subMet
+		}
+	}
+	return met, nil
+}
+
+// backtestValue returns the value a condition compares against at the last of the replayed
+// klines: its close, the change of the close from the base price, the volume over the
+// condition's window or the time the candle closed
+func backtestValue(condition *repository.TriggerCondition, history []*api.Kline) float64 {
+	kline := history[len(history)-1]
+	switch condition.Type {
+	case repository.TriggerTypePriceChangePercent:
+		if condition.BasePrice > 0 {
+			return ((kline.Close - condition.BasePrice) / condition.BasePrice) * 100.0
+		}
+		return 0
+	case repository.TriggerTypeVolume:
+		window := condition.TimeWindow
+		if window <= 0 {
+			window = backtestVolumeWindow
+		}
+		return windowVolume(history, window, time.UnixMilli(kline.CloseTime))
+	case repository.TriggerTypeTime:
+		return float64(kline.CloseTime + 1)
+	default:
+		return kline.Close
+	}
+}
+
+// backtestInTimeWindow returns whether an order may trigger on a candle
+func backtestInTimeWindow(order *repository.ConditionalOrder, kline *api.Kline) bool {
+	if order.TimeWindow == nil {
+		return true
+	}
+	return IsWithinTimeWindow(candleEnd(kline), &TimeWindow{
+		StartTime: order.TimeWindow.StartTime,
+		EndTime:   order.TimeWindow.EndTime,
+	})
+}
+
+// candleEnd returns when a candle closes in Unix seconds; its CloseTime is its last millisecond
+func candleEnd(kline *api.Kline) int64 {
+	return (kline.CloseTime + 1) / 1000
+}
+
+// validateBacktestOrder checks that an order can be backtested
+func validateBacktestOrder(order *repository.ConditionalOrder) error {
+	if order == nil || order.TriggerCondition == nil {
+		return errors.NewTradingError(errors.ErrInvalidParameter, "order and its trigger condition cannot be nil", 0, nil)
+	}
+	if order.Quantity <= 0 {
+		return errors.NewTradingError(errors.ErrInvalidParameter,
+			fmt.Sprintf("order %s: quantity must be greater than 0", order.OrderID), 0, nil)
+	}
+	if order.Side != api.OrderSideBuy && order.Side != api.OrderSideSell {
+		return errors.NewTradingError(errors.ErrInvalidParameter,
+			fmt.Sprintf("order %s: side must be BUY or SELL", order.OrderID), 0, nil)
+	}
+	return validateBacktestCondition(order.OrderID, order.TriggerCondition)
+}
+
+// validateBacktestCondition checks that every sub-condition can be evaluated from klines alone
+func validateBacktestCondition(orderID string, condition *repository.TriggerCondition) error {
+	if condition == nil {
+		return errors.NewTradingError(errors.ErrInvalidParameter,
+			fmt.Sprintf("order %s: condition cannot be nil", orderID), 0, nil)
+	}
+	if len(condition.SubConditions) > 0 {
+		if condition.CompositeType != repository.LogicAND && condition.CompositeType != repository.LogicOR {
+			return errors.NewTradingError(errors.ErrInvalidParameter,
+				fmt.Sprintf("order %s: unknown logic operator: %d", orderID, condition.CompositeType), 0, nil)
+		}
+		for _, subCondition := range condition.SubConditions {
+			if err := validateBacktestCondition(orderID, subCondition); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch condition.Type {
+	case repository.TriggerTypePrice, repository.TriggerTypePriceChangePercent,
+		repository.TriggerTypeVolume, repository.TriggerTypeTime:
+		return nil
+	}
+	return errors.NewTradingError(errors.ErrInvalidParameter,
+		fmt.Sprintf("order %s: trigger type %d cannot be backtested from klines", orderID, condition.Type), 0, nil)
+}
+
+// convertToServiceTriggerCondition converts repository trigger condition to service trigger condition
+func (b *Backtester) convertToServiceTriggerCondition(repoCond *repository.TriggerCondition) *TriggerCondition {
+	return &TriggerCondition{
+		Type:          TriggerType(repoCond.Type),
+		Operator:      ComparisonOperator(repoCond.Operator),
+		Value:         repoCond.Value,
+		BasePrice:     repoCond.BasePrice,
+		TimeWindow:    repoCond.TimeWindow,
+		Period:        repoCond.Period,
+		Interval:      repoCond.Interval,
+		FastPeriod:    repoCond.FastPeriod,
+		SlowPeriod:    repoCond.SlowPeriod,
+		CompositeType: LogicOperator(repoCond.CompositeType),
+	}
+}
+
+// LoadKlinesCSV reads klines in the CSV layout of Binance's historical data downloads: open
+// time, open, high, low, close, volume and close time, followed by columns that are ignored. A
+// header row is skipped, and microsecond timestamps are converted to milliseconds.
+func LoadKlinesCSV(r io.Reader) ([]*api.Kline, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	var klines []*api.Kline
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read klines CSV: %w", err)
+		}
+		if len(record) < 7 {
+			return nil, fmt.Errorf("line %d: expected at least 7 columns, got %d", line, len(record))
+		}
+		if line == 1 {
+			if _, err := strconv.ParseInt(record[0], 10, 64); err != nil {
+				continue
+			}
+		}
+
+		kline := &api.Kline{}
+		for i, field := range []*float64{&kline.Open, &kline.High, &kline.Low, &kline.Close, &kline.Volume} {
+			value, err := strconv.ParseFloat(record[i+1], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid number %q: %w", line, record[i+1], err)
+			}
+			*field = value
+		}
+		for i, field := range []*int64{&kline.OpenTime, &kline.CloseTime} {
+			column := record[i*6]
+			value, err := strconv.ParseInt(column, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid timestamp %q: %w", line, column, err)
+			}
+			// Timestamps after the year 5000 in milliseconds are microseconds
+			if value >= 1e14 {
+				value /= 1000
+			}
+			*field = value
+		}
+		klines = append(klines, kline)
+	}
+	return klines, nil
+}
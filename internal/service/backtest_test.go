@@ -0,0 +1,173 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// backtestStart is the open time of the first replayed one-minute candle
+var backtestStart = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// backtestKlines returns one-minute candles closing at the given prices with a volume of 10 each
+func backtestKlines(closes ...float64) []*api.Kline {
+	klines := make([]*api.Kline, len(closes))
+	for i, price := range closes {
+		openTime := backtestStart.Add(time.Duration(i) * time.Minute).UnixMilli()
+		klines[i] = &api.Kline{
+			OpenTime: openTime, Open: price, High: price, Low: price, Close: price, Volume: 10,
+			CloseTime: openTime + time.Minute.Milliseconds() - 1,
+		}
+	}
+	return klines
+}
+
+// backtestOrder returns a market order triggering when the price compares to value
+func backtestOrder(id string, side api.OrderSide, quantity float64, operator repository.ComparisonOperator, value float64) *repository.ConditionalOrder {
+	return &repository.ConditionalOrder{
+		OrderID:  id,
+		Symbol:   "BTCUSDT",
+		Side:     side,
+		Type:     api.OrderTypeMarket,
+		Quantity: quantity,
+		TriggerCondition: &repository.TriggerCondition{
+			Type:     repository.TriggerTypePrice,
+			Operator: operator,
+			Value:    value,
+		},
+	}
+}
+
+func TestBacktester_Run(t *testing.T) {
+	klines := backtestKlines(100, 101, 99, 103, 105, 102, 106)
+	orders := []*repository.ConditionalOrder{
+		backtestOrder("breakout", api.OrderSideBuy, 2, repository.OperatorGreaterEqual, 103),
+		backtestOrder("dip", api.OrderSideSell, 1, repository.OperatorLessEqual, 99),
+		backtestOrder("moon", api.OrderSideBuy, 1, repository.OperatorGreaterEqual, 200),
+	}
+
+	report, err := NewBacktester(NewTriggerEngine()).Run(klines, orders)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.Candles != 7 || len(report.Trades) != 2 {
+		t.Fatalf("report = %d candles, %d trades; want 7 candles, 2 trades", report.Candles, len(report.Trades))
+	}
+
+	// Trades are listed in trigger order: the dip on candle 2, the breakout on candle 3
+	dip, breakout := report.Trades[0], report.Trades[1]
+	if dip.OrderID != "dip" || dip.CandleIndex != 2 || dip.EntryPrice != 99 || dip.TriggerTime != klines[2].CloseTime {
+		t.Errorf("first trade = %+v, want dip on candle 2 at 99", dip)
+	}
+	if breakout.OrderID != "breakout" || breakout.CandleIndex != 3 || breakout.EntryPrice != 103 {
+		t.Errorf("second trade = %+v, want breakout on candle 3 at 103", breakout)
+	}
+
+	// Both exit at the last close of 106: the long gains, the short loses
+	if breakout.ExitPrice != 106 || breakout.PnL != 6 || dip.PnL != -7 {
+		t.Errorf("PnL = breakout %v, dip %v; want 6, -7", breakout.PnL, dip.PnL)
+	}
+	if report.Wins != 1 || report.Losses != 1 || report.TotalPnL != -1 {
+		t.Errorf("wins %d, losses %d, total %v; want 1, 1, -1", report.Wins, report.Losses, report.TotalPnL)
+	}
+	if len(report.Untriggered) != 1 || report.Untriggered[0] != "moon" {
+		t.Errorf("untriggered = %v, want [moon]", report.Untriggered)
+	}
+}
+
+func TestBacktester_TimeWindowAndComposite(t *testing.T) {
+	klines := backtestKlines(100, 101, 102, 103, 104, 105)
+	klines[4].Volume = 50
+
+	// Met from the first candle, but the window opens when candle 2 closes
+	windowed := backtestOrder("windowed", api.OrderSideBuy, 1, repository.OperatorGreaterEqual, 100)
+	windowed.TimeWindow = &repository.TimeWindow{StartTime: time.UnixMilli(klines[2].CloseTime)}
+
+	// The price is above 101 from candle 2 on, but the 2-minute volume reaches 60 only on candle 4
+	composite := backtestOrder("composite", api.OrderSideBuy, 1, repository.OperatorGreaterEqual, 0)
+	composite.TriggerCondition = &repository.TriggerCondition{
+		CompositeType: repository.LogicAND,
+		SubConditions: []*repository.TriggerCondition{
+			{Type: repository.TriggerTypePrice, Operator: repository.OperatorGreaterThan, Value: 101},
+			{Type: repository.TriggerTypeVolume, Operator: repository.OperatorGreaterEqual, Value: 60, TimeWindow: 2 * time.Minute},
+		},
+	}
+
+	report, err := NewBacktester(NewTriggerEngine()).Run(klines, []*repository.ConditionalOrder{windowed, composite})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(report.Trades) != 2 || report.Trades[0].OrderID != "windowed" || report.Trades[0].CandleIndex != 2 ||
+		report.Trades[1].OrderID != "composite" || report.Trades[1].CandleIndex != 4 {
+		for _, trade := range report.Trades {
+			t.Logf("trade %+v", trade)
+		}
+		t.Fatal("want windowed on candle 2 and composite on candle 4")
+	}
+}
+
+func TestBacktester_Validation(t *testing.T) {
+	backtester := NewBacktester(NewTriggerEngine())
+	rsi := backtestOrder("rsi", api.OrderSideBuy, 1, repository.OperatorLessThan, 30)
+	rsi.TriggerCondition.Type = repository.TriggerTypeRSI
+	nested := backtestOrder("nested", api.OrderSideBuy, 1, repository.OperatorGreaterEqual, 0)
+	nested.TriggerCondition = &repository.TriggerCondition{
+		CompositeType: repository.LogicOR,
+		SubConditions: []*repository.TriggerCondition{{Type: repository.TriggerTypeAskBidImbalance, Value: 0.5}},
+	}
+
+	tests := []struct {
+		name   string
+		klines []*api.Kline
+		order  *repository.ConditionalOrder
+	}{
+		{"no klines", nil, backtestOrder("a", api.OrderSideBuy, 1, repository.OperatorGreaterEqual, 100)},
+		{"zero quantity", backtestKlines(100), backtestOrder("a", api.OrderSideBuy, 0, repository.OperatorGreaterEqual, 100)},
+		{"RSI condition", backtestKlines(100), rsi},
+		{"imbalance sub-condition", backtestKlines(100), nested},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := backtester.Run(tt.klines, []*repository.ConditionalOrder{tt.order})
+			if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrInvalidParameter {
+				t.Errorf("Run() error = %v, want ErrInvalidParameter", err)
+			}
+		})
+	}
+}
+
+func TestLoadKlinesCSV(t *testing.T) {
+	data := "open_time,open,high,low,close,volume,close_time,quote_volume,count,taker_buy_volume,taker_buy_quote_volume,ignore\n" +
+		"1717200000000,67000.1,67100,66900,67050.5,12.5,1717200059999,838131.25,100,6,402000,0\n" +
+		"1717200060000000,67050.5,67200,67000,67150,8,1717200119999999,537200,80,4,268600,0\n"
+
+	klines, err := LoadKlinesCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("LoadKlinesCSV() error = %v", err)
+	}
+	if len(klines) != 2 {
+		t.Fatalf("got %d klines, want 2", len(klines))
+	}
+	first := klines[0]
+	if first.OpenTime != 1717200000000 || first.Open != 67000.1 || first.High != 67100 || first.Low != 66900 ||
+		first.Close != 67050.5 || first.Volume != 12.5 || first.CloseTime != 1717200059999 {
+		t.Errorf("first kline = %+v", first)
+	}
+	// Microsecond timestamps are read as milliseconds
+	if klines[1].OpenTime != 1717200060000 || klines[1].CloseTime != 1717200119999 {
+		t.Errorf("second kline times = %d / %d", klines[1].OpenTime, klines[1].CloseTime)
+	}
+
+	for name, bad := range map[string]string{
+		"short row":   "1717200000000,1,2,3\n",
+		"bad price":   "1717200000000,x,2,3,4,5,1717200059999\n",
+		"bad closing": "1717200000000,1,2,3,4,5,soon\n",
+	} {
+		if _, err := LoadKlinesCSV(strings.NewReader(bad)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
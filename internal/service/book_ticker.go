@@ -0,0 +1,253 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/pkg/logger"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultBookTickerStaleAfter is used when no staleness threshold is configured
+const DefaultBookTickerStaleAfter = 3 * time.Second
+
+// BookTickerSource fetches the best bid and ask over REST; spot and futures clients implement it
+type BookTickerSource interface {
+	GetBookTicker(symbol string) (*api.BookTicker, error)
+}
+
+// BookTickerStream is the transport delivering bookTicker frames, e.g. a WebSocket connection
+type BookTickerStream interface {
+	Subscribe(symbol string) error
+	Unsubscribe(symbol string) error
+}
+
+// BestBidAsk is the best bid and ask of a symbol
+type BestBidAsk struct {
+	Symbol    string
+	BidPrice  float64
+	BidQty    float64
+	AskPrice  float64
+	AskQty    float64
+	UpdatedAt int64 // Milliseconds since epoch when the quote was received
+	Source    DataSourceKind
+}
+
+// Spread returns the difference between the best ask and the best bid
+func (q *BestBidAsk) Spread() float64 {
+	return q.AskPrice - q.BidPrice
+}
+
+// Mid returns the midpoint between the best bid and the best ask
+func (q *BestBidAsk) Mid() float64 {
+	return (q.BidPrice + q.AskPrice) / 2
+}
+
+// BookTickerCache maintains per-symbol best bid/ask from bookTicker stream frames. Features that
+// need the book subscribe on demand; the stream subscription is shared and reference counted.
+// Quotes that are missing or stale are fetched over REST.
+type BookTickerCache interface {
+	// SetStream sets the stream transport; nil serves every quote from REST
+	SetStream(stream BookTickerStream)
+
+	// Subscribe adds a reference to a symbol; the first reference subscribes the stream
+	Subscribe(symbol string) error
+
+	// Unsubscribe drops a reference; the stream is unsubscribed when the last reference is dropped
+	Unsubscribe(symbol string) error
+
+	// HandleFrame applies a raw bookTicker stream frame to the cache
+	HandleFrame(data []byte) error
+
+	GetBestBidAsk(symbol string) (*BestBidAsk, error)
+
+	// GetSubscriptions returns the reference count of every subscribed symbol
+	GetSubscriptions() map[string]int
+}
+
+// cachedBookTicker is the latest stream quote of a symbol
+type cachedBookTicker struct {
+	ticker     *api.BookTicker
+	receivedAt time.Time
+}
+
+// bookTickerCache implements BookTickerCache
+type bookTickerCache struct {
+	rest       BookTickerSource
+	staleAfter time.Duration
+	logger     logger.Logger
+	now        func() time.Time
+
+	mu      sync.Mutex
+	stream  BookTickerStream
+	refs    map[string]int
+	tickers map[string]*cachedBookTicker
+}
+
+// NewBookTickerCache creates a book ticker cache that falls back to rest for stale quotes
+func NewBookTickerCache(rest BookTickerSource, cfg *config.BookTickerConfig, log logger.Logger) BookTickerCache {
+	if cfg == nil {
+		cfg = &config.BookTickerConfig{}
+	}
+
+	c := &bookTickerCache{
+		rest:       rest,
+		staleAfter: time.Duration(cfg.StaleAfterMs) * time.Millisecond,
+		logger:     log,
+		now:        time.Now,
+		refs:       make(map[string]int),
+		tickers:    make(map[string]*cachedBookTicker),
+	}
+
+	if c.staleAfter <= 0 {
+		c.staleAfter = DefaultBookTickerStaleAfter
+	}
+
+	return c
+}
+
+// SetStream sets the stream transport
+func (c *bookTickerCache) SetStream(stream BookTickerStream) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stream = stream
+}
+
+// Subscribe adds a reference to a symbol and subscribes the stream on the first one
+func (c *bookTickerCache) Subscribe(symbol string) error {
+	if symbol == "" {
+		return fmt.Errorf("symbol cannot be empty")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.refs[symbol] == 0 && c.stream != nil {
+		if err := c.stream.Subscribe(symbol); err != nil {
+			return fmt.Errorf("failed to subscribe book ticker for %s: %w", symbol, err)
+		}
+	}
+	c.refs[symbol]++
+	return nil
+}
+
+// Unsubscribe drops a reference and unsubscribes the stream once nothing needs the symbol
+func (c *bookTickerCache) Unsubscribe(symbol string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	refs := c.refs[symbol]
+	if refs == 0 {
+		return fmt.Errorf("book ticker for %s is not subscribed", symbol)
+	}
+
+	if refs > 1 {
+		c.refs[symbol] = refs - 1
+		return nil
+	}
+
+	delete(c.refs, symbol)
+	delete(c.tickers, symbol)
+	if c.stream != nil {
+		if err := c.stream.Unsubscribe(symbol); err != nil {
+			return fmt.Errorf("failed to unsubscribe book ticker for %s: %w", symbol, err)
+		}
+	}
+	return nil
+}
+
+// HandleFrame caches the quote of a subscribed symbol, ignoring frames older than the cached one
+func (c *bookTickerCache) HandleFrame(data []byte) error {
+	ticker, err := api.ParseBookTickerEvent(data)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.refs[ticker.Symbol] == 0 {
+		return nil
+	}
+
+	if cached, exists := c.tickers[ticker.Symbol]; exists && ticker.UpdateID > 0 && ticker.UpdateID <= cached.ticker.UpdateID {
+		return nil
+	}
+
+	c.tickers[ticker.Symbol] = &cachedBookTicker{
+		ticker:     ticker,
+		receivedAt: c.now(),
+	}
+	return nil
+}
+
+// GetBestBidAsk returns the cached stream quote while it is fresh and a REST quote otherwise
+func (c *bookTickerCache) GetBestBidAsk(symbol string) (*BestBidAsk, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol cannot be empty")
+	}
+
+	c.mu.Lock()
+	cached, exists := c.tickers[symbol]
+	now := c.now()
+	c.mu.Unlock()
+
+	if exists {
+		age := now.Sub(cached.receivedAt)
+		if age <= c.staleAfter {
+			return newBestBidAsk(cached.ticker, cached.receivedAt, DataSourceWebSocket), nil
+		}
+
+		c.logger.Debug("Book ticker quote is stale, falling back to REST", map[string]interface{}{
+			"symbol":      symbol,
+			"age":         age.String(),
+			"stale_after": c.staleAfter.String(),
+		})
+	}
+
+	return fetchBestBidAsk(c.rest, symbol, now)
+}
+
+// GetSubscriptions returns the reference count of every subscribed symbol
+func (c *bookTickerCache) GetSubscriptions() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := make(map[string]int, len(c.refs))
+	for symbol, refs := range c.refs {
+		result[symbol] = refs
+	}
+	return result
+}
+
+// fetchBestBidAsk reads the best bid and ask over REST
+func fetchBestBidAsk(rest BookTickerSource, symbol string, now time.Time) (*BestBidAsk, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol cannot be empty")
+	}
+
+	ticker, err := rest.GetBookTicker(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get book ticker for %s: %w", symbol, err)
+	}
+
+	if ticker.BidPrice <= 0 || ticker.AskPrice <= 0 {
+		return nil, fmt.Errorf("invalid book ticker received for %s: bid %f, ask %f", symbol, ticker.BidPrice, ticker.AskPrice)
+	}
+
+	return newBestBidAsk(ticker, now, DataSourceREST), nil
+}
+
+// newBestBidAsk converts a book ticker into a quote
+func newBestBidAsk(ticker *api.BookTicker, receivedAt time.Time, source DataSourceKind) *BestBidAsk {
+	return &BestBidAsk{
+		Symbol:    ticker.Symbol,
+		BidPrice:  ticker.BidPrice,
+		BidQty:    ticker.BidQty,
+		AskPrice:  ticker.AskPrice,
+		AskQty:    ticker.AskQty,
+		UpdatedAt: receivedAt.UnixMilli(),
+		Source:    source,
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// recordedBookTickerFrames are bookTicker frames as received from the spot and futures streams
+var recordedBookTickerFrames = []string{
+	`{"u":400900217,"s":"BTCUSDT","b":"50000.10000000","B":"1.50000000","a":"50000.20000000","A":"2.00000000"}`,
+	`{"e":"bookTicker","u":400900218,"E":1700000000005,"T":1700000000004,"s":"BTCUSDT","b":"50000.30","B":"0.8","a":"50000.40","A":"1.1"}`,
+	`{"stream":"ethusdt@bookTicker","data":{"u":7,"s":"ETHUSDT","b":"3000.01","B":"10","a":"3000.02","A":"12"}}`,
+}
+
+// stubBookTickerStream records stream subscriptions
+type stubBookTickerStream struct {
+	subscribed   []string
+	unsubscribed []string
+	err          error
+}
+
+func (s *stubBookTickerStream) Subscribe(symbol string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.subscribed = append(s.subscribed, symbol)
+	return nil
+}
+
+func (s *stubBookTickerStream) Unsubscribe(symbol string) error {
+	s.unsubscribed = append(s.unsubscribed, symbol)
+	return nil
+}
+
+// stubBookTickerSource serves REST book tickers and counts requests
+type stubBookTickerSource struct {
+	calls int
+}
+
+func (s *stubBookTickerSource) GetBookTicker(symbol string) (*api.BookTicker, error) {
+	s.calls++
+	return &api.BookTicker{Symbol: symbol, BidPrice: 49990, BidQty: 3, AskPrice: 50010, AskQty: 4}, nil
+}
+
+func newTestBookTickerCache() (*bookTickerCache, *stubBookTickerStream, *stubBookTickerSource, *time.Time) {
+	rest := &stubBookTickerSource{}
+	cache := NewBookTickerCache(rest, &config.BookTickerConfig{StaleAfterMs: 2000}, &mockLogger{}).(*bookTickerCache)
+	stream := &stubBookTickerStream{}
+	cache.SetStream(stream)
+
+	current := time.Unix(1700000000, 0)
+	cache.now = func() time.Time { return current }
+	return cache, stream, rest, &current
+}
+
+func TestBookTickerCache_AppliesRecordedFrames(t *testing.T) {
+	cache, _, rest, current := newTestBookTickerCache()
+	cache.Subscribe("BTCUSDT")
+	cache.Subscribe("ETHUSDT")
+
+	for _, frame := range recordedBookTickerFrames {
+		if err := cache.HandleFrame([]byte(frame)); err != nil {
+			t.Fatalf("HandleFrame(%s) failed: %v", frame, err)
+		}
+	}
+
+	quote, err := cache.GetBestBidAsk("BTCUSDT")
+	if err != nil {
+		t.Fatalf("GetBestBidAsk failed: %v", err)
+	}
+	if quote.BidPrice != 50000.30 || quote.AskPrice != 50000.40 || quote.Source != DataSourceWebSocket {
+		t.Errorf("expected latest stream quote, got %+v", quote)
+	}
+	if quote.UpdatedAt != current.UnixMilli() {
+		t.Errorf("expected receive timestamp %d, got %d", current.UnixMilli(), quote.UpdatedAt)
+	}
+	if spread := quote.Spread(); spread < 0.099 || spread > 0.101 {
+		t.Errorf("expected spread 0.1, got %v", spread)
+	}
+
+	quote, _ = cache.GetBestBidAsk("ETHUSDT")
+	if quote.BidPrice != 3000.01 || quote.Source != DataSourceWebSocket {
+		t.Errorf("expected combined stream frame applied, got %+v", quote)
+	}
+	if rest.calls != 0 {
+		t.Errorf("fresh quotes should not hit REST, got %d calls", rest.calls)
+	}
+
+	// An out-of-order frame does not replace a newer quote
+	cache.HandleFrame([]byte(recordedBookTickerFrames[0]))
+	if quote, _ := cache.GetBestBidAsk("BTCUSDT"); quote.BidPrice != 50000.30 {
+		t.Errorf("older frame should be ignored, got bid %v", quote.BidPrice)
+	}
+
+	if err := cache.HandleFrame([]byte(`{"s":"BTCUSDT","b":"x"}`)); err == nil {
+		t.Error("expected error for malformed frame")
+	}
+}
+
+func TestBookTickerCache_StaleQuoteFallsBackToREST(t *testing.T) {
+	cache, _, rest, current := newTestBookTickerCache()
+	cache.Subscribe("BTCUSDT")
+	cache.HandleFrame([]byte(recordedBookTickerFrames[0]))
+
+	*current = current.Add(2 * time.Second)
+	if quote, _ := cache.GetBestBidAsk("BTCUSDT"); quote.Source != DataSourceWebSocket {
+		t.Fatalf("quote at the staleness threshold should still be served from the stream, got %s", quote.Source)
+	}
+
+	*current = current.Add(time.Millisecond)
+	quote, err := cache.GetBestBidAsk("BTCUSDT")
+	if err != nil {
+		t.Fatalf("GetBestBidAsk failed: %v", err)
+	}
+	if quote.Source != DataSourceREST || quote.BidPrice != 49990 || rest.calls != 1 {
+		t.Errorf("expected REST fallback for stale quote, got %+v after %d calls", quote, rest.calls)
+	}
+
+	// A new frame makes the stream authoritative again
+	cache.HandleFrame([]byte(recordedBookTickerFrames[1]))
+	if quote, _ := cache.GetBestBidAsk("BTCUSDT"); quote.Source != DataSourceWebSocket || quote.BidPrice != 50000.30 {
+		t.Errorf("expected stream quote after a new frame, got %+v", quote)
+	}
+
+	// Symbols without stream data are served from REST
+	if quote, _ := cache.GetBestBidAsk("SOLUSDT"); quote.Source != DataSourceREST {
+		t.Errorf("expected REST quote for unsubscribed symbol, got %s", quote.Source)
+	}
+}
+
+func TestBookTickerCache_RefcountedSubscriptions(t *testing.T) {
+	cache, stream, _, _ := newTestBookTickerCache()
+
+	cache.Subscribe("BTCUSDT")
+	cache.Subscribe("BTCUSDT")
+	if len(stream.subscribed) != 1 {
+		t.Fatalf("stream should be subscribed once, got %v", stream.subscribed)
+	}
+	if refs := cache.GetSubscriptions()["BTCUSDT"]; refs != 2 {
+		t.Errorf("expected 2 references, got %d", refs)
+	}
+
+	// Frames for symbols nobody subscribed to are dropped
+	cache.HandleFrame([]byte(recordedBookTickerFrames[2]))
+	if _, exists := cache.tickers["ETHUSDT"]; exists {
+		t.Error("frames for unsubscribed symbols should not be cached")
+	}
+
+	cache.HandleFrame([]byte(recordedBookTickerFrames[0]))
+	cache.Unsubscribe("BTCUSDT")
+	if len(stream.unsubscribed) != 0 {
+		t.Fatalf("stream should stay subscribed while referenced, got %v", stream.unsubscribed)
+	}
+
+	cache.Unsubscribe("BTCUSDT")
+	if len(stream.unsubscribed) != 1 || len(cache.GetSubscriptions()) != 0 {
+		t.Errorf("last release should unsubscribe the stream, got %v", stream.unsubscribed)
+	}
+	if _, exists := cache.tickers["BTCUSDT"]; exists {
+		t.Error("cached quote should be dropped with the last reference")
+	}
+
+	if err := cache.Unsubscribe("BTCUSDT"); err == nil {
+		t.Error("expected error releasing an unsubscribed symbol")
+	}
+
+	stream.err = fmt.Errorf("connection closed")
+	if err := cache.Subscribe("ETHUSDT"); err == nil || len(cache.GetSubscriptions()) != 0 {
+		t.Errorf("failed stream subscription should not be counted, got %v", err)
+	}
+}
+
+func TestMarketDataService_GetBestBidAsk(t *testing.T) {
+	client := &mockBinanceClient{
+		getBookTickerFunc: func(symbol string) (*api.BookTicker, error) {
+			return &api.BookTicker{Symbol: symbol, BidPrice: 100, AskPrice: 100.5}, nil
+		},
+	}
+	market := NewMarketDataService(client, time.Second)
+
+	quote, err := market.GetBestBidAsk("BNBUSDT")
+	if err != nil || quote.Source != DataSourceREST || quote.AskPrice != 100.5 {
+		t.Fatalf("expected REST quote without a cache, got %+v (%v)", quote, err)
+	}
+
+	cache, _, _, _ := newTestBookTickerCache()
+	cache.Subscribe("BTCUSDT")
+	cache.HandleFrame([]byte(recordedBookTickerFrames[0]))
+	market.SetBookTickerCache(cache)
+
+	quote, err = market.GetBestBidAsk("BTCUSDT")
+	if err != nil || quote.Source != DataSourceWebSocket || quote.BidPrice != 50000.10 {
+		t.Errorf("expected cached stream quote, got %+v (%v)", quote, err)
+	}
+}
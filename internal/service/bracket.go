@@ -0,0 +1,198 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	"binance-trader/pkg/logger"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultBracketFillTimeout is how long a bracket waits for its entry order to fill before
+	// cancelling it
+	DefaultBracketFillTimeout = 5 * time.Minute
+
+	// bracketPollInterval is how often the status of an unfilled entry order is checked
+	bracketPollInterval = 2 * time.Second
+)
+
+// BracketResult is the outcome of a bracket order: its limit entry order and the stop loss and
+// take profit pair protecting what the entry bought
+type BracketResult struct {
+	Symbol       string
+	EntryOrderID int64
+	EntryStatus  api.OrderStatus
+	FilledQty    float64                   // Quantity the entry bought, which the pair protects
+	Pair         *repository.StopOrderPair // nil until the entry has filled
+}
+
+// BracketExecutor places bracket orders: a limit entry order and, once it fills, a stop loss and
+// take profit pair where the first leg to trigger cancels the other. Shutdown cancels the entry
+// orders still waiting to fill.
+type BracketExecutor struct {
+	trading     SpotTradingService
+	stopLoss    StopLossService
+	logger      logger.Logger
+	after       func(time.Duration) <-chan time.Time
+	fillTimeout time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewBracketExecutor creates a bracket executor placing entry orders through trading and their
+// stop loss and take profit pairs through stopLoss
+func NewBracketExecutor(trading SpotTradingService, stopLoss StopLossService, log logger.Logger) *BracketExecutor {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &BracketExecutor{
+		trading:     trading,
+		stopLoss:    stopLoss,
+		logger:      log,
+		after:       time.After,
+		fillTimeout: DefaultBracketFillTimeout,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// PlaceBracketOrder places a limit buy of quantity at entryPrice and waits for it to fill, then
+// sets a stop loss at stopPrice and a take profit at targetPrice for the filled quantity. An
+// entry that has not filled within the fill timeout is cancelled and no pair is set; the part of
+// an entry that filled before its cancellation is still protected. Spot stop orders sell a held
+// position, so only BUY brackets are supported.
+func (e *BracketExecutor) PlaceBracketOrder(symbol string, side api.OrderSide, quantity, entryPrice, stopPrice, targetPrice float64) (*BracketResult, error) {
+	if err := validateBracket(symbol, side, quantity, entryPrice, stopPrice, targetPrice); err != nil {
+		return nil, err
+	}
+
+	entry, err := e.trading.PlaceLimitBuyOrder(symbol, entryPrice, quantity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place entry order: %w", err)
+	}
+	result := &BracketResult{
+		Symbol:       symbol,
+		EntryOrderID: entry.OrderID,
+		EntryStatus:  entry.Status,
+		FilledQty:    entry.ExecutedQty,
+	}
+	e.logger.Info("Bracket entry order placed", map[string]interface{}{
+		"symbol":       symbol,
+		"order_id":     entry.OrderID,
+		"entry_price":  entryPrice,
+		"stop_price":   stopPrice,
+		"target_price": targetPrice,
+	})
+
+	if err := e.awaitEntry(result); err != nil {
+		return result, err
+	}
+	if result.FilledQty <= 0 {
+		e.logger.Warn("Bracket entry order did not fill, bracket cancelled", map[string]interface{}{
+			"symbol":   symbol,
+			"order_id": result.EntryOrderID,
+			"status":   string(result.EntryStatus),
+		})
+		return result, fmt.Errorf("entry order %d ended %s without a fill; bracket cancelled", result.EntryOrderID, result.EntryStatus)
+	}
+
+	pair, err := e.stopLoss.SetStopLossTakeProfit(symbol, result.FilledQty, stopPrice, targetPrice)
+	if err != nil {
+		e.logger.Error("Bracket entry filled but its stop loss and take profit were not set", map[string]interface{}{
+			"symbol":     symbol,
+			"order_id":   result.EntryOrderID,
+			"filled_qty": result.FilledQty,
+			"error":      err.Error(),
+		})
+		return result, fmt.Errorf("entry order %d filled but its stop loss and take profit were not set: %w", result.EntryOrderID, err)
+	}
+	result.Pair = pair
+
+	e.logger.Info("Bracket order completed", map[string]interface{}{
+		"symbol":     symbol,
+		"order_id":   result.EntryOrderID,
+		"filled_qty": result.FilledQty,
+		"pair_id":    pair.PairID,
+	})
+	return result, nil
+}
+
+// Shutdown cancels the entry orders still waiting to fill and any placed later
+func (e *BracketExecutor) Shutdown() {
+	e.cancel()
+}
+
+// awaitEntry polls the entry order until it has filled or ended. When the fill timeout passes
+// or the executor shuts down first, the entry is cancelled instead.
+func (e *BracketExecutor) awaitEntry(result *BracketResult) error {
+	deadline := e.after(e.fillTimeout)
+	for !entryOrderDone(result.EntryStatus) {
+		select {
+		case <-e.ctx.Done():
+			return e.cancelEntry(result)
+		case <-deadline:
+			return e.cancelEntry(result)
+		case <-e.after(bracketPollInterval):
+		}
+
+		status, err := e.trading.GetOrderStatus(result.EntryOrderID)
+		if err != nil {
+			e.logger.Warn("Failed to check bracket entry order", map[string]interface{}{
+				"order_id": result.EntryOrderID,
+				"error":    err.Error(),
+			})
+			continue
+		}
+		result.EntryStatus, result.FilledQty = status.Status, status.ExecutedQty
+	}
+	return nil
+}
+
+// cancelEntry cancels an entry order that did not fill in time and reads back how much of it
+// filled before the cancellation, which may have lost a race with the last fill
+func (e *BracketExecutor) cancelEntry(result *BracketResult) error {
+	cancelErr := e.trading.CancelOrder(result.EntryOrderID)
+	status, err := e.trading.GetOrderStatus(result.EntryOrderID)
+	if err == nil {
+		result.EntryStatus, result.FilledQty = status.Status, status.ExecutedQty
+	} else if cancelErr == nil {
+		result.EntryStatus = api.OrderStatusCanceled
+	}
+	if cancelErr != nil && !entryOrderDone(result.EntryStatus) {
+		return fmt.Errorf("failed to cancel entry order %d: %w", result.EntryOrderID, cancelErr)
+	}
+	return nil
+}
+
+// entryOrderDone returns whether an order can no longer fill
+func entryOrderDone(status api.OrderStatus) bool {
+	switch status {
+	case api.OrderStatusFilled, api.OrderStatusCanceled, api.OrderStatusRejected, api.OrderStatusExpired:
+		return true
+	}
+	return false
+}
+
+// validateBracket checks the parameters of a bracket order
+func validateBracket(symbol string, side api.OrderSide, quantity, entryPrice, stopPrice, targetPrice float64) error {
+	var message string
+	switch {
+	case symbol == "":
+		message = "symbol cannot be empty"
+	case side != api.OrderSideBuy:
+		message = "side must be BUY: spot stop orders sell a held position"
+	case quantity <= 0:
+		message = "quantity must be greater than 0"
+	case entryPrice <= 0:
+		message = "entry price must be greater than 0"
+	case stopPrice <= 0 || stopPrice >= entryPrice:
+		message = "stop price must be between 0 and the entry price"
+	case targetPrice <= entryPrice:
+		message = "target price must be above the entry price"
+	default:
+		return nil
+	}
+	return errors.NewTradingError(errors.ErrInvalidParameter, message, 0, nil)
+}
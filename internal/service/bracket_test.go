@@ -0,0 +1,177 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	"testing"
+	"time"
+)
+
+// bracketTradingService reports the scripted statuses of the entry order in turn, repeating the
+// last one, and records cancellations
+type bracketTradingService struct {
+	mockTradingService
+	statuses  []api.OrderStatus
+	filled    float64 // Executed quantity reported with FILLED and after a cancellation
+	polls     int
+	cancelled []int64
+}
+
+func (m *bracketTradingService) GetOrderStatus(orderID int64) (*OrderStatus, error) {
+	status := m.statuses[len(m.statuses)-1]
+	if m.polls < len(m.statuses) {
+		status = m.statuses[m.polls]
+	}
+	m.polls++
+
+	executed := 0.0
+	if status == api.OrderStatusFilled || len(m.cancelled) > 0 {
+		executed = m.filled
+	}
+	return &OrderStatus{OrderID: orderID, Status: status, ExecutedQty: executed}, nil
+}
+
+func (m *bracketTradingService) CancelOrder(orderID int64) error {
+	m.cancelled = append(m.cancelled, orderID)
+	m.statuses = []api.OrderStatus{api.OrderStatusCanceled}
+	m.polls = 0
+	return nil
+}
+
+// newBracketTestExecutor returns an executor whose polls fire at once and whose fill timeout
+// fires only when expired is true
+func newBracketTestExecutor(trading *bracketTradingService, expired bool) (*BracketExecutor, repository.StopOrderRepository) {
+	stopRepo := repository.NewMemoryStopOrderRepository()
+	stopLoss := NewStopLossService(stopRepo, NewTriggerEngine(), trading, &mockStopLossMarketDataService{currentPrice: 50000}, &mockLogger{})
+	executor := NewBracketExecutor(trading, stopLoss, &mockLogger{})
+	executor.after = func(d time.Duration) <-chan time.Time {
+		fired := make(chan time.Time, 1)
+		if d == executor.fillTimeout {
+			if expired {
+				fired <- time.Time{}
+			}
+			return fired
+		}
+		if expired {
+			return nil
+		}
+		fired <- time.Time{}
+		return fired
+	}
+	return executor, stopRepo
+}
+
+func TestBracketExecutor_FilledEntry(t *testing.T) {
+	trading := &bracketTradingService{
+		statuses: []api.OrderStatus{api.OrderStatusNew, api.OrderStatusPartiallyFilled, api.OrderStatusFilled},
+		filled:   0.01,
+	}
+	executor, stopRepo := newBracketTestExecutor(trading, false)
+
+	result, err := executor.PlaceBracketOrder("BTCUSDT", api.OrderSideBuy, 0.01, 50000, 48000, 55000)
+	if err != nil {
+		t.Fatalf("PlaceBracketOrder() error = %v", err)
+	}
+	if result.EntryStatus != api.OrderStatusFilled || result.FilledQty != 0.01 || len(trading.cancelled) != 0 {
+		t.Errorf("entry = %s with %v filled, cancelled %v; want filled 0.01, none cancelled",
+			result.EntryStatus, result.FilledQty, trading.cancelled)
+	}
+
+	// Exactly one stop loss and take profit pair protects the filled entry
+	active, _ := stopRepo.FindActiveStopOrders("BTCUSDT")
+	if len(active) != 2 || result.Pair == nil {
+		t.Fatalf("active stop orders = %d, pair = %v; want one pair", len(active), result.Pair)
+	}
+	for _, order := range active {
+		if order.PairID != result.Pair.PairID || order.Position != 0.01 {
+			t.Errorf("stop order %s: pair %s position %v, want pair %s position 0.01",
+				order.OrderID, order.PairID, order.Position, result.Pair.PairID)
+		}
+	}
+	if result.Pair.StopLossOrder.StopPrice != 48000 || result.Pair.TakeProfitOrder.StopPrice != 55000 {
+		t.Errorf("pair prices = %v / %v, want 48000 / 55000",
+			result.Pair.StopLossOrder.StopPrice, result.Pair.TakeProfitOrder.StopPrice)
+	}
+}
+
+func TestBracketExecutor_UnfilledEntryTimesOut(t *testing.T) {
+	trading := &bracketTradingService{statuses: []api.OrderStatus{api.OrderStatusNew}}
+	executor, stopRepo := newBracketTestExecutor(trading, true)
+
+	result, err := executor.PlaceBracketOrder("BTCUSDT", api.OrderSideBuy, 0.01, 50000, 48000, 55000)
+	if err == nil {
+		t.Fatal("expected an error for an entry that never filled")
+	}
+	if len(trading.cancelled) != 1 || trading.cancelled[0] != result.EntryOrderID {
+		t.Errorf("cancelled orders = %v, want the entry order %d", trading.cancelled, result.EntryOrderID)
+	}
+	if result.EntryStatus != api.OrderStatusCanceled || result.Pair != nil {
+		t.Errorf("entry = %s with pair %v, want canceled without a pair", result.EntryStatus, result.Pair)
+	}
+	if active, _ := stopRepo.FindActiveStopOrders("BTCUSDT"); len(active) != 0 {
+		t.Errorf("active stop orders = %d, want none", len(active))
+	}
+}
+
+func TestBracketExecutor_PartialFillKeptAtTimeout(t *testing.T) {
+	trading := &bracketTradingService{statuses: []api.OrderStatus{api.OrderStatusPartiallyFilled}, filled: 0.004}
+	executor, stopRepo := newBracketTestExecutor(trading, true)
+
+	result, err := executor.PlaceBracketOrder("BTCUSDT", api.OrderSideBuy, 0.01, 50000, 48000, 55000)
+	if err != nil {
+		t.Fatalf("PlaceBracketOrder() error = %v", err)
+	}
+	if len(trading.cancelled) != 1 {
+		t.Errorf("cancelled orders = %v, want the rest of the entry cancelled", trading.cancelled)
+	}
+
+	// The pair protects what was bought, not the quantity asked for
+	active, _ := stopRepo.FindActiveStopOrders("BTCUSDT")
+	if len(active) != 2 || result.Pair == nil || result.Pair.Position != 0.004 {
+		t.Errorf("active stop orders = %d, pair = %+v; want one pair for 0.004", len(active), result.Pair)
+	}
+}
+
+func TestBracketExecutor_Shutdown(t *testing.T) {
+	trading := &bracketTradingService{statuses: []api.OrderStatus{api.OrderStatusNew}}
+	executor, stopRepo := newBracketTestExecutor(trading, false)
+	executor.after = func(time.Duration) <-chan time.Time { return nil }
+	executor.Shutdown()
+
+	if _, err := executor.PlaceBracketOrder("BTCUSDT", api.OrderSideBuy, 0.01, 50000, 48000, 55000); err == nil {
+		t.Fatal("expected an error for an entry cancelled by shutdown")
+	}
+	if len(trading.cancelled) != 1 {
+		t.Errorf("cancelled orders = %v, want the entry order", trading.cancelled)
+	}
+	if active, _ := stopRepo.FindActiveStopOrders("BTCUSDT"); len(active) != 0 {
+		t.Errorf("active stop orders = %d, want none", len(active))
+	}
+}
+
+func TestBracketExecutor_Validation(t *testing.T) {
+	trading := &bracketTradingService{statuses: []api.OrderStatus{api.OrderStatusFilled}}
+	executor, _ := newBracketTestExecutor(trading, false)
+
+	tests := []struct {
+		name                string
+		side                api.OrderSide
+		quantity            float64
+		entry, stop, target float64
+	}{
+		{"sell side", api.OrderSideSell, 0.01, 50000, 52000, 45000},
+		{"zero quantity", api.OrderSideBuy, 0, 50000, 48000, 55000},
+		{"zero entry", api.OrderSideBuy, 0.01, 0, 48000, 55000},
+		{"stop above entry", api.OrderSideBuy, 0.01, 50000, 51000, 55000},
+		{"target below entry", api.OrderSideBuy, 0.01, 50000, 48000, 49000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executor.PlaceBracketOrder("BTCUSDT", tt.side, tt.quantity, tt.entry, tt.stop, tt.target)
+			if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrInvalidParameter {
+				t.Errorf("PlaceBracketOrder() error = %v, want ErrInvalidParameter", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,547 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/pkg/errors"
+	"binance-trader/pkg/logger"
+	"binance-trader/pkg/timeutil"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCarryEntryFundingRate is the minimum funding rate per period to open a carry (0.03%)
+	DefaultCarryEntryFundingRate = 0.0003
+	// DefaultCarryExitFundingRate is the funding rate per period below which a carry is unwound (0.01%)
+	DefaultCarryExitFundingRate = 0.0001
+	// DefaultCarryHistoryPeriods is the number of past funding periods that must meet the entry rate
+	DefaultCarryHistoryPeriods = 3
+	// DefaultCarryMaxBasisPercent is the basis move from entry that stops out a carry
+	DefaultCarryMaxBasisPercent = 1.0
+	// DefaultCarrySpotFeeRate is the spot taker commission rate
+	DefaultCarrySpotFeeRate = 0.001
+	// DefaultCarryFuturesFeeRate is the futures taker commission rate
+	DefaultCarryFuturesFeeRate = 0.0004
+	// DefaultCarryCheckInterval is how often open carries are re-evaluated
+	DefaultCarryCheckInterval = 1 * time.Minute
+
+	// fundingPeriod is the perpetual funding interval
+	fundingPeriod = 8 * time.Hour
+)
+
+// CarryStatus represents the lifecycle state of a carry position
+type CarryStatus string
+
+const (
+	CarryStatusOpen      CarryStatus = "OPEN"
+	CarryStatusUnwinding CarryStatus = "UNWINDING"
+	CarryStatusClosed    CarryStatus = "CLOSED"
+)
+
+// CarrySizing holds the leg quantities for a delta-neutral carry
+type CarrySizing struct {
+	SpotQuantity    float64 // Spot quantity bought
+	HedgeQuantity   float64 // Futures short quantity, equal to the spot quantity received after commission
+	SpotNotional    float64
+	FuturesNotional float64
+	EstimatedFees   float64 // Entry commissions of both legs in quote asset
+}
+
+// CarryPosition represents a long spot / short perpetual carry
+type CarryPosition struct {
+	Symbol             string
+	Status             CarryStatus
+	SpotQuantity       float64
+	HedgeQuantity      float64
+	SpotEntryPrice     float64
+	FuturesEntryPrice  float64
+	EntryBasis         float64 // Percent premium of perpetual over spot at entry
+	CurrentBasis       float64
+	EntryFundingRate   float64
+	CurrentFundingRate float64
+	FundingAccrued     float64 // Estimated funding received by the short leg in quote asset
+	SpotLegOpen        bool
+	FuturesLegOpen     bool
+	SpotOrderID        int64
+	FuturesOrderID     int64
+	OpenedAt           int64 // Unix ms
+	ClosedAt           int64 // Unix ms
+	CloseReason        string
+}
+
+// CarryService defines the interface for the spot/perpetual funding carry workflow
+type CarryService interface {
+	// Open buys spot and shorts the perpetual for the target notional
+	Open(symbol string, notional float64) (*CarryPosition, error)
+	// Close unwinds whichever legs of the carry are still open
+	Close(symbol string, reason string) (*CarryPosition, error)
+	// GetPositions returns all carries, including closed ones
+	GetPositions() []*CarryPosition
+	// CheckPositions refreshes basis and funding and unwinds carries that hit an exit condition
+	CheckPositions()
+
+	StartMonitoring(checkInterval time.Duration) error
+	StopMonitoring() error
+}
+
+// carryService implements CarryService
+type carryService struct {
+	spotTrading    SpotTradingService
+	spotMarket     MarketDataService
+	futuresTrading FuturesTradingService
+	futuresMarket  FuturesMarketDataService
+	logger         logger.Logger
+
+	entryFundingRate float64
+	exitFundingRate  float64
+	historyPeriods   int
+	maxBasisPercent  float64
+	spotFeeRate      float64
+	futuresFeeRate   float64
+
+	positions map[string]*CarryPosition
+	mu        sync.Mutex
+	now       func() time.Time
+
+	// Monitoring
+	stopChan     chan struct{}
+	monitoringMu sync.Mutex
+	isMonitoring bool
+}
+
+// NewCarryService creates a new carry service
+func NewCarryService(
+	spotTrading SpotTradingService,
+	spotMarket MarketDataService,
+	futuresTrading FuturesTradingService,
+	futuresMarket FuturesMarketDataService,
+	cfg *config.CarryConfig,
+	log logger.Logger,
+) CarryService {
+	s := &carryService{
+		spotTrading:      spotTrading,
+		spotMarket:       spotMarket,
+		futuresTrading:   futuresTrading,
+		futuresMarket:    futuresMarket,
+		logger:           log,
+		entryFundingRate: DefaultCarryEntryFundingRate,
+		exitFundingRate:  DefaultCarryExitFundingRate,
+		historyPeriods:   DefaultCarryHistoryPeriods,
+		maxBasisPercent:  DefaultCarryMaxBasisPercent,
+		spotFeeRate:      DefaultCarrySpotFeeRate,
+		futuresFeeRate:   DefaultCarryFuturesFeeRate,
+		positions:        make(map[string]*CarryPosition),
+		now:              time.Now,
+	}
+
+	if cfg != nil {
+		if cfg.EntryFundingRate > 0 {
+			s.entryFundingRate = cfg.EntryFundingRate
+		}
+		if cfg.ExitFundingRate > 0 {
+			s.exitFundingRate = cfg.ExitFundingRate
+		}
+		if cfg.HistoryPeriods > 0 {
+			s.historyPeriods = cfg.HistoryPeriods
+		}
+		if cfg.MaxBasisPercent > 0 {
+			s.maxBasisPercent = cfg.MaxBasisPercent
+		}
+		if cfg.SpotFeeRate > 0 {
+			s.spotFeeRate = cfg.SpotFeeRate
+		}
+		if cfg.FuturesFeeRate > 0 {
+			s.futuresFeeRate = cfg.FuturesFeeRate
+		}
+	}
+
+	return s
+}
+
+// CalculateCarrySizing sizes both legs for delta neutrality. The spot commission is taken
+// from the bought asset, so the short covers only the quantity actually received.
+func CalculateCarrySizing(notional, spotPrice, perpPrice, spotFeeRate, futuresFeeRate float64) (*CarrySizing, error) {
+	if notional <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "notional must be greater than 0", 0, nil)
+	}
+	if spotPrice <= 0 || perpPrice <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "prices must be greater than 0", 0, nil)
+	}
+	if spotFeeRate < 0 || spotFeeRate >= 1 || futuresFeeRate < 0 || futuresFeeRate >= 1 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "fee rates must be in [0, 1)", 0, nil)
+	}
+
+	spotQuantity := notional / spotPrice
+	hedgeQuantity := spotQuantity * (1 - spotFeeRate)
+	futuresNotional := hedgeQuantity * perpPrice
+
+	return &CarrySizing{
+		SpotQuantity:    spotQuantity,
+		HedgeQuantity:   hedgeQuantity,
+		SpotNotional:    notional,
+		FuturesNotional: futuresNotional,
+		EstimatedFees:   notional*spotFeeRate + futuresNotional*futuresFeeRate,
+	}, nil
+}
+
+// basisPercent returns the premium of the perpetual over spot in percent
+func basisPercent(spotPrice, perpPrice float64) float64 {
+	return (perpPrice - spotPrice) / spotPrice * 100
+}
+
+// Open checks the funding rate and opens both legs, closing the spot leg if the short fails
+func (s *carryService) Open(symbol string, notional float64) (*CarryPosition, error) {
+	if symbol == "" {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "symbol cannot be empty", 0, nil)
+	}
+	if notional <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "notional must be greater than 0", 0, nil)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, exists := s.positions[symbol]; exists && existing.Status != CarryStatusClosed {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter,
+			fmt.Sprintf("carry already active for %s", symbol), 0, nil)
+	}
+
+	fundingRate, err := s.checkEntryFunding(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	spotPrice, err := s.spotMarket.GetCurrentPrice(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot price: %w", err)
+	}
+	perpPrice, err := s.futuresMarket.GetMarkPrice(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mark price: %w", err)
+	}
+
+	sizing, err := CalculateCarrySizing(notional, spotPrice, perpPrice, s.spotFeeRate, s.futuresFeeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	// First leg: buy spot
+	spotOrder, err := s.spotTrading.PlaceMarketBuyOrder(symbol, sizing.SpotQuantity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spot leg: %w", err)
+	}
+
+	position := &CarryPosition{
+		Symbol:            symbol,
+		Status:            CarryStatusOpen,
+		SpotQuantity:      sizing.SpotQuantity,
+		HedgeQuantity:     sizing.HedgeQuantity,
+		SpotEntryPrice:    spotPrice,
+		FuturesEntryPrice: perpPrice,
+		EntryBasis:        basisPercent(spotPrice, perpPrice),
+		EntryFundingRate:  fundingRate,
+		SpotLegOpen:       true,
+		SpotOrderID:       spotOrder.OrderID,
+		OpenedAt:          timeutil.Millis(s.now()),
+	}
+	position.CurrentBasis = position.EntryBasis
+	position.CurrentFundingRate = fundingRate
+
+	// Second leg: short the perpetual; roll back the spot leg on failure
+	futuresOrder, err := s.futuresTrading.OpenShortPosition(symbol, sizing.HedgeQuantity, api.OrderTypeMarket, 0)
+	if err != nil {
+		s.logger.Error("Carry futures leg failed, rolling back spot leg", map[string]interface{}{
+			"symbol":   symbol,
+			"quantity": sizing.HedgeQuantity,
+			"error":    err.Error(),
+		})
+
+		if _, rollbackErr := s.spotTrading.PlaceMarketSellOrder(symbol, sizing.HedgeQuantity); rollbackErr != nil {
+			// Keep the unhedged spot leg visible so it can be closed with carry close
+			position.Status = CarryStatusUnwinding
+			position.CloseReason = "futures leg failed"
+			s.positions[symbol] = position
+
+			s.logger.Error("Carry rollback failed, spot leg left open", map[string]interface{}{
+				"symbol":   symbol,
+				"quantity": sizing.HedgeQuantity,
+				"error":    rollbackErr.Error(),
+			})
+			return nil, fmt.Errorf("failed to open futures leg: %v; rollback of spot leg also failed: %w", err, rollbackErr)
+		}
+
+		return nil, fmt.Errorf("failed to open futures leg (spot leg rolled back): %w", err)
+	}
+
+	position.FuturesLegOpen = true
+	position.FuturesOrderID = futuresOrder.OrderID
+	s.positions[symbol] = position
+
+	s.logger.Info("Carry position opened", map[string]interface{}{
+		"symbol":         symbol,
+		"notional":       notional,
+		"spot_quantity":  sizing.SpotQuantity,
+		"hedge_quantity": sizing.HedgeQuantity,
+		"entry_basis":    position.EntryBasis,
+		"funding_rate":   fundingRate,
+		"estimated_fees": sizing.EstimatedFees,
+	})
+
+	copied := *position
+	return &copied, nil
+}
+
+// checkEntryFunding requires the current and recent funding rates to meet the entry threshold
+func (s *carryService) checkEntryFunding(symbol string) (float64, error) {
+	current, err := s.futuresMarket.GetFundingRate(symbol)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get funding rate: %w", err)
+	}
+	if current.FundingRate < s.entryFundingRate {
+		return 0, errors.NewTradingError(errors.ErrInvalidParameter,
+			fmt.Sprintf("funding rate %.4f%% is below entry threshold %.4f%%",
+				current.FundingRate*100, s.entryFundingRate*100), 0, nil)
+	}
+
+	end := s.now()
+	start := end.Add(-time.Duration(s.historyPeriods+1) * fundingPeriod)
+	history, err := s.futuresMarket.GetFundingRateHistory(symbol, start.UnixMilli(), end.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get funding rate history: %w", err)
+	}
+	if len(history) < s.historyPeriods {
+		return 0, errors.NewTradingError(errors.ErrInvalidParameter,
+			fmt.Sprintf("insufficient funding history: %d of %d periods", len(history), s.historyPeriods), 0, nil)
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].FundingTime < history[j].FundingTime
+	})
+	for _, rate := range history[len(history)-s.historyPeriods:] {
+		if rate.FundingRate < s.entryFundingRate {
+			return 0, errors.NewTradingError(errors.ErrInvalidParameter,
+				fmt.Sprintf("funding rate was %.4f%% at %s, below entry threshold %.4f%%",
+					rate.FundingRate*100, time.UnixMilli(rate.FundingTime).UTC().Format("2006-01-02 15:04"),
+					s.entryFundingRate*100), 0, nil)
+		}
+	}
+
+	return current.FundingRate, nil
+}
+
+// Close unwinds the futures leg first so the spot leg is never left unhedged on failure
+func (s *carryService) Close(symbol string, reason string) (*CarryPosition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	position, err := s.closeLocked(symbol, reason)
+	if position != nil {
+		copied := *position
+		position = &copied
+	}
+	return position, err
+}
+
+// closeLocked closes the remaining legs; the caller must hold s.mu
+func (s *carryService) closeLocked(symbol string, reason string) (*CarryPosition, error) {
+	position, exists := s.positions[symbol]
+	if !exists || position.Status == CarryStatusClosed {
+		return nil, errors.NewTradingError(errors.ErrPositionNotFound,
+			fmt.Sprintf("no active carry for %s", symbol), 0, nil)
+	}
+
+	if position.CloseReason == "" {
+		position.CloseReason = reason
+	}
+	position.Status = CarryStatusUnwinding
+
+	if position.FuturesLegOpen {
+		if _, err := s.futuresTrading.ClosePosition(symbol, api.PositionSideShort, position.HedgeQuantity); err != nil {
+			return position, fmt.Errorf("failed to close futures leg: %w", err)
+		}
+		position.FuturesLegOpen = false
+	}
+
+	if position.SpotLegOpen {
+		if _, err := s.spotTrading.PlaceMarketSellOrder(symbol, position.HedgeQuantity); err != nil {
+			return position, fmt.Errorf("failed to close spot leg: %w", err)
+		}
+		position.SpotLegOpen = false
+	}
+
+	position.Status = CarryStatusClosed
+	position.ClosedAt = timeutil.Millis(s.now())
+
+	s.logger.Info("Carry position closed", map[string]interface{}{
+		"symbol":          symbol,
+		"reason":          position.CloseReason,
+		"funding_accrued": position.FundingAccrued,
+		"exit_basis":      position.CurrentBasis,
+	})
+
+	return position, nil
+}
+
+// GetPositions returns copies of all carries sorted by symbol
+func (s *carryService) GetPositions() []*CarryPosition {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	positions := make([]*CarryPosition, 0, len(s.positions))
+	for _, position := range s.positions {
+		copied := *position
+		positions = append(positions, &copied)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].Symbol < positions[j].Symbol
+	})
+	return positions
+}
+
+// CheckPositions refreshes open carries and unwinds those hitting an exit condition
+func (s *carryService) CheckPositions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for symbol, position := range s.positions {
+		switch position.Status {
+		case CarryStatusClosed:
+			continue
+		case CarryStatusUnwinding:
+			// Retry legs left open by an earlier failure
+			if _, err := s.closeLocked(symbol, position.CloseReason); err != nil {
+				s.logger.Warn("Carry unwind retry failed", map[string]interface{}{
+					"symbol": symbol,
+					"error":  err.Error(),
+				})
+			}
+			continue
+		}
+
+		reason, err := s.refreshPosition(position)
+		if err != nil {
+			s.logger.Warn("Failed to refresh carry position", map[string]interface{}{
+				"symbol": symbol,
+				"error":  err.Error(),
+			})
+			continue
+		}
+		if reason == "" {
+			continue
+		}
+
+		s.logger.Info("Carry exit condition met", map[string]interface{}{
+			"symbol":        symbol,
+			"reason":        reason,
+			"funding_rate":  position.CurrentFundingRate,
+			"current_basis": position.CurrentBasis,
+		})
+		if _, err := s.closeLocked(symbol, reason); err != nil {
+			s.logger.Error("Failed to unwind carry position", map[string]interface{}{
+				"symbol": symbol,
+				"error":  err.Error(),
+			})
+		}
+	}
+}
+
+// refreshPosition updates basis and funding accrual and returns an exit reason if one applies
+func (s *carryService) refreshPosition(position *CarryPosition) (string, error) {
+	spotPrice, err := s.spotMarket.GetCurrentPrice(position.Symbol)
+	if err != nil {
+		return "", fmt.Errorf("failed to get spot price: %w", err)
+	}
+	perpPrice, err := s.futuresMarket.GetMarkPrice(position.Symbol)
+	if err != nil {
+		return "", fmt.Errorf("failed to get mark price: %w", err)
+	}
+	funding, err := s.futuresMarket.GetFundingRate(position.Symbol)
+	if err != nil {
+		return "", fmt.Errorf("failed to get funding rate: %w", err)
+	}
+
+	position.CurrentBasis = basisPercent(spotPrice, perpPrice)
+	position.CurrentFundingRate = funding.FundingRate
+
+	// Funding settled since entry; shorts receive positive funding
+	history, err := s.futuresMarket.GetFundingRateHistory(position.Symbol, position.OpenedAt, s.now().UnixMilli())
+	if err == nil {
+		accrued := 0.0
+		for _, rate := range history {
+			accrued += rate.FundingRate * position.HedgeQuantity * perpPrice
+		}
+		position.FundingAccrued = accrued
+	}
+
+	if funding.FundingRate < s.exitFundingRate {
+		return fmt.Sprintf("funding rate %.4f%% below exit threshold %.4f%%",
+			funding.FundingRate*100, s.exitFundingRate*100), nil
+	}
+	if math.Abs(position.CurrentBasis-position.EntryBasis) > s.maxBasisPercent {
+		return fmt.Sprintf("basis moved from %.3f%% to %.3f%% (stop %.2f%%)",
+			position.EntryBasis, position.CurrentBasis, s.maxBasisPercent), nil
+	}
+
+	return "", nil
+}
+
+// StartMonitoring starts periodic evaluation of open carries
+func (s *carryService) StartMonitoring(checkInterval time.Duration) error {
+	s.monitoringMu.Lock()
+	defer s.monitoringMu.Unlock()
+
+	if s.isMonitoring {
+		return fmt.Errorf("monitoring is already running")
+	}
+
+	if checkInterval <= 0 {
+		checkInterval = DefaultCarryCheckInterval
+	}
+
+	s.stopChan = make(chan struct{})
+	s.isMonitoring = true
+
+	go s.monitoringLoop(checkInterval)
+
+	s.logger.Info("Started carry monitoring", map[string]interface{}{
+		"check_interval": checkInterval.String(),
+	})
+
+	return nil
+}
+
+// StopMonitoring stops periodic evaluation
+func (s *carryService) StopMonitoring() error {
+	s.monitoringMu.Lock()
+	defer s.monitoringMu.Unlock()
+
+	if !s.isMonitoring {
+		return fmt.Errorf("monitoring is not running")
+	}
+
+	close(s.stopChan)
+	s.isMonitoring = false
+
+	s.logger.Info("Stopped carry monitoring", nil)
+
+	return nil
+}
+
+// monitoringLoop re-evaluates open carries on every tick
+func (s *carryService) monitoringLoop(checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			s.CheckPositions()
+		}
+	}
+}
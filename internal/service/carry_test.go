@@ -0,0 +1,304 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/pkg/errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// carrySpotTradingService records spot orders and can fail sells
+type carrySpotTradingService struct {
+	mockStopLossTradingService
+	buys     []float64
+	sells    []float64
+	sellErrs []error
+}
+
+func (m *carrySpotTradingService) PlaceMarketBuyOrder(symbol string, quantity float64) (*api.Order, error) {
+	m.buys = append(m.buys, quantity)
+	return &api.Order{OrderID: 1, Symbol: symbol, Status: api.OrderStatusFilled}, nil
+}
+
+func (m *carrySpotTradingService) PlaceMarketSellOrder(symbol string, quantity float64) (*api.Order, error) {
+	if len(m.sellErrs) > 0 {
+		err := m.sellErrs[0]
+		m.sellErrs = m.sellErrs[1:]
+		if err != nil {
+			return nil, err
+		}
+	}
+	m.sells = append(m.sells, quantity)
+	return &api.Order{OrderID: 2, Symbol: symbol, Status: api.OrderStatusFilled}, nil
+}
+
+// carryFuturesTradingService records futures orders and can fail the short
+type carryFuturesTradingService struct {
+	mockFuturesTradingService
+	shorts   []float64
+	closes   []float64
+	shortErr error
+}
+
+func (m *carryFuturesTradingService) OpenShortPosition(symbol string, quantity float64, orderType api.OrderType, price float64) (*api.FuturesOrder, error) {
+	if m.shortErr != nil {
+		return nil, m.shortErr
+	}
+	m.shorts = append(m.shorts, quantity)
+	return &api.FuturesOrder{OrderID: 3, Symbol: symbol, Status: api.OrderStatusFilled}, nil
+}
+
+func (m *carryFuturesTradingService) ClosePosition(symbol string, positionSide api.PositionSide, quantity float64) (*api.FuturesOrder, error) {
+	m.closes = append(m.closes, quantity)
+	return &api.FuturesOrder{OrderID: 4, Symbol: symbol, Status: api.OrderStatusFilled}, nil
+}
+
+// carryFuturesMarketDataService serves configurable funding rates
+type carryFuturesMarketDataService struct {
+	mockFuturesMarketDataService
+	fundingRate float64
+	history     []float64
+}
+
+func (m *carryFuturesMarketDataService) GetFundingRate(symbol string) (*api.FundingRate, error) {
+	return &api.FundingRate{Symbol: symbol, FundingRate: m.fundingRate}, nil
+}
+
+func (m *carryFuturesMarketDataService) GetFundingRateHistory(symbol string, startTime, endTime int64) ([]*api.FundingRate, error) {
+	rates := make([]*api.FundingRate, 0, len(m.history))
+	for i, rate := range m.history {
+		rates = append(rates, &api.FundingRate{Symbol: symbol, FundingRate: rate, FundingTime: int64(i+1) * 1000})
+	}
+	return rates, nil
+}
+
+// newTestCarryService creates a carry service with spot at 100 and perp at 100.1
+func newTestCarryService() (*carryService, *carrySpotTradingService, *mockStopLossMarketDataService, *carryFuturesTradingService, *carryFuturesMarketDataService) {
+	spotTrading := &carrySpotTradingService{}
+	spotMarket := &mockStopLossMarketDataService{currentPrice: 100}
+	futuresTrading := &carryFuturesTradingService{}
+	futuresMarket := &carryFuturesMarketDataService{
+		mockFuturesMarketDataService: mockFuturesMarketDataService{markPrice: 100.1},
+		fundingRate:                  0.0005,
+		history:                      []float64{0.0004, 0.0005, 0.0006},
+	}
+
+	svc := NewCarryService(spotTrading, spotMarket, futuresTrading, futuresMarket, &config.CarryConfig{
+		EntryFundingRate: 0.0003,
+		ExitFundingRate:  0.0001,
+		HistoryPeriods:   3,
+		MaxBasisPercent:  0.5,
+		SpotFeeRate:      0.001,
+		FuturesFeeRate:   0.0004,
+	}, &mockLogger{}).(*carryService)
+	svc.now = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	return svc, spotTrading, spotMarket, futuresTrading, futuresMarket
+}
+
+func TestCalculateCarrySizing(t *testing.T) {
+	sizing, err := CalculateCarrySizing(10000, 50000, 50100, 0.001, 0.0004)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// 0.2 BTC bought, 0.1% commission leaves 0.1998 BTC to hedge
+	checks := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{"spot quantity", sizing.SpotQuantity, 0.2},
+		{"hedge quantity", sizing.HedgeQuantity, 0.1998},
+		{"futures notional", sizing.FuturesNotional, 0.1998 * 50100},
+		{"estimated fees", sizing.EstimatedFees, 10 + 0.1998*50100*0.0004},
+	}
+	for _, check := range checks {
+		if math.Abs(check.got-check.expected) > 1e-9 {
+			t.Errorf("%s: expected %.10f, got %.10f", check.name, check.expected, check.got)
+		}
+	}
+
+	// Without spot commission the legs are equal
+	if sizing, _ := CalculateCarrySizing(1000, 100, 101, 0, 0.0004); sizing.HedgeQuantity != sizing.SpotQuantity {
+		t.Errorf("expected equal legs without spot fee, got %f / %f", sizing.SpotQuantity, sizing.HedgeQuantity)
+	}
+
+	invalid := [][5]float64{
+		{0, 100, 100, 0.001, 0.0004},
+		{1000, 0, 100, 0.001, 0.0004},
+		{1000, 100, 100, 1, 0.0004},
+		{1000, 100, 100, 0.001, -0.1},
+	}
+	for _, args := range invalid {
+		if _, err := CalculateCarrySizing(args[0], args[1], args[2], args[3], args[4]); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
+
+func TestCarryService_OpenAndClose(t *testing.T) {
+	svc, spotTrading, _, futuresTrading, _ := newTestCarryService()
+
+	position, err := svc.Open("BTCUSDT", 1000)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(spotTrading.buys) != 1 || math.Abs(spotTrading.buys[0]-10) > 1e-9 {
+		t.Errorf("expected spot buy of 10, got %v", spotTrading.buys)
+	}
+	if len(futuresTrading.shorts) != 1 || math.Abs(futuresTrading.shorts[0]-9.99) > 1e-9 {
+		t.Errorf("expected perp short of 9.99, got %v", futuresTrading.shorts)
+	}
+	if position.Status != CarryStatusOpen || !position.SpotLegOpen || !position.FuturesLegOpen {
+		t.Errorf("expected open carry with both legs, got %+v", position)
+	}
+	if math.Abs(position.EntryBasis-0.1) > 1e-9 {
+		t.Errorf("expected entry basis 0.1%%, got %f", position.EntryBasis)
+	}
+
+	if _, err := svc.Open("BTCUSDT", 1000); err == nil {
+		t.Error("expected error when a carry is already active")
+	}
+
+	closed, err := svc.Close("BTCUSDT", "manual close")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if closed.Status != CarryStatusClosed || closed.CloseReason != "manual close" {
+		t.Errorf("unexpected closed carry: %+v", closed)
+	}
+	if len(futuresTrading.closes) != 1 || len(spotTrading.sells) != 1 || spotTrading.sells[0] != futuresTrading.closes[0] {
+		t.Errorf("expected both legs closed with equal quantity, got closes=%v sells=%v", futuresTrading.closes, spotTrading.sells)
+	}
+}
+
+func TestCarryService_OpenRequiresPersistentFunding(t *testing.T) {
+	svc, spotTrading, _, _, futuresMarket := newTestCarryService()
+
+	futuresMarket.fundingRate = 0.0002
+	if _, err := svc.Open("BTCUSDT", 1000); err == nil {
+		t.Error("expected error when current funding is below the entry threshold")
+	}
+
+	futuresMarket.fundingRate = 0.0005
+	futuresMarket.history = []float64{0.0005, 0.0001, 0.0005}
+	if _, err := svc.Open("BTCUSDT", 1000); err == nil {
+		t.Error("expected error when a recent funding period is below the entry threshold")
+	}
+
+	futuresMarket.history = []float64{0.0005}
+	if _, err := svc.Open("BTCUSDT", 1000); err == nil {
+		t.Error("expected error with insufficient funding history")
+	}
+
+	if len(spotTrading.buys) != 0 {
+		t.Errorf("expected no orders when funding checks fail, got %v", spotTrading.buys)
+	}
+}
+
+func TestCarryService_RollbackWhenFuturesLegFails(t *testing.T) {
+	svc, spotTrading, _, futuresTrading, _ := newTestCarryService()
+	futuresTrading.shortErr = errors.NewTradingError(errors.ErrInsufficientMargin, "insufficient margin", 400, nil)
+
+	if _, err := svc.Open("BTCUSDT", 1000); err == nil || !strings.Contains(err.Error(), "rolled back") {
+		t.Fatalf("expected rolled back error, got %v", err)
+	}
+
+	// The spot quantity received is sold again
+	if len(spotTrading.sells) != 1 || math.Abs(spotTrading.sells[0]-9.99) > 1e-9 {
+		t.Errorf("expected spot rollback sell of 9.99, got %v", spotTrading.sells)
+	}
+	if positions := svc.GetPositions(); len(positions) != 0 {
+		t.Errorf("expected no carry after rollback, got %d", len(positions))
+	}
+
+	// A new attempt is allowed once the futures side works again
+	futuresTrading.shortErr = nil
+	if _, err := svc.Open("BTCUSDT", 1000); err != nil {
+		t.Errorf("expected open to succeed after rollback, got %v", err)
+	}
+}
+
+func TestCarryService_FailedRollbackLeavesUnwindingCarry(t *testing.T) {
+	svc, spotTrading, _, futuresTrading, _ := newTestCarryService()
+	futuresTrading.shortErr = errors.NewTradingError(errors.ErrInsufficientMargin, "insufficient margin", 400, nil)
+	spotTrading.sellErrs = []error{errors.NewTradingError(errors.ErrNetwork, "timeout", 0, nil)}
+
+	if _, err := svc.Open("BTCUSDT", 1000); err == nil {
+		t.Fatal("expected error when both the futures leg and rollback fail")
+	}
+
+	positions := svc.GetPositions()
+	if len(positions) != 1 || positions[0].Status != CarryStatusUnwinding || !positions[0].SpotLegOpen || positions[0].FuturesLegOpen {
+		t.Fatalf("expected unwinding carry with only the spot leg open, got %+v", positions)
+	}
+
+	// The monitor retries the unwind and only sells spot
+	svc.CheckPositions()
+	positions = svc.GetPositions()
+	if positions[0].Status != CarryStatusClosed {
+		t.Errorf("expected carry to be closed after retry, got %s", positions[0].Status)
+	}
+	if len(futuresTrading.closes) != 0 || len(spotTrading.sells) != 1 {
+		t.Errorf("expected only a spot sell, got closes=%v sells=%v", futuresTrading.closes, spotTrading.sells)
+	}
+}
+
+func TestCarryService_CheckPositionsExitConditions(t *testing.T) {
+	t.Run("funding below exit threshold", func(t *testing.T) {
+		svc, _, _, futuresTrading, futuresMarket := newTestCarryService()
+		if _, err := svc.Open("BTCUSDT", 1000); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		futuresMarket.fundingRate = 0.0002
+		svc.CheckPositions()
+		if svc.GetPositions()[0].Status != CarryStatusOpen {
+			t.Fatal("expected carry to stay open above the exit threshold")
+		}
+
+		futuresMarket.fundingRate = 0.00005
+		svc.CheckPositions()
+		position := svc.GetPositions()[0]
+		if position.Status != CarryStatusClosed || !strings.Contains(position.CloseReason, "funding rate") {
+			t.Errorf("expected funding exit, got %s / %q", position.Status, position.CloseReason)
+		}
+		if len(futuresTrading.closes) != 1 {
+			t.Errorf("expected futures leg to be closed, got %v", futuresTrading.closes)
+		}
+	})
+
+	t.Run("basis stop", func(t *testing.T) {
+		svc, _, spotMarket, _, _ := newTestCarryService()
+		if _, err := svc.Open("BTCUSDT", 1000); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		// Perp now 0.7% below spot: basis moved 0.8% from entry
+		spotMarket.currentPrice = 100.8
+		svc.CheckPositions()
+		position := svc.GetPositions()[0]
+		if position.Status != CarryStatusClosed || !strings.Contains(position.CloseReason, "basis") {
+			t.Errorf("expected basis stop, got %s / %q", position.Status, position.CloseReason)
+		}
+	})
+
+	t.Run("funding accrual", func(t *testing.T) {
+		svc, _, _, _, _ := newTestCarryService()
+		if _, err := svc.Open("BTCUSDT", 1000); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		svc.CheckPositions()
+		position := svc.GetPositions()[0]
+		expected := (0.0004 + 0.0005 + 0.0006) * 9.99 * 100.1
+		if math.Abs(position.FundingAccrued-expected) > 1e-9 {
+			t.Errorf("expected funding accrued %.8f, got %.8f", expected, position.FundingAccrued)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/timeutil"
+	"fmt"
+)
+
+// evaluateComposite evaluates a condition sub-condition by sub-condition, each against its own
+// value: the price, its change from the base price, the volume over its window or the current
+// time. Every value is fetched before the logic operator applies, so an AND of price and volume
+// never triggers on one of them alone.
+func (me *MonitoringEngine) evaluateComposite(condition *repository.TriggerCondition, marketData *MarketData) (bool, error) {
+	if condition == nil {
+		return false, fmt.Errorf("condition cannot be nil")
+	}
+	if len(condition.SubConditions) == 0 {
+		value, err := me.subConditionValue(condition, marketData)
+		if err != nil {
+			return false, err
+		}
+		return me.triggerEngine.EvaluateCondition(me.convertToServiceTriggerCondition(condition), value)
+	}
+
+	if condition.CompositeType != repository.LogicAND && condition.CompositeType != repository.LogicOR {
+		return false, fmt.Errorf("unknown logic operator: %d", condition.CompositeType)
+	}
+	met := condition.CompositeType == repository.LogicAND
+	for _, subCondition := range condition.SubConditions {
+		subMet, err := me.evaluateComposite(subCondition, marketData)
+		if err != nil {
+			return false, err
+		}
+		if condition.CompositeType == repository.LogicAND {
+			met = met && subMet
+		} else {
+			met = met || subMet
+		}
+	}
+	return met, nil
+}
+
+// subConditionValue returns the value a sub-condition of a composite compares against
+func (me *MonitoringEngine) subConditionValue(condition *repository.TriggerCondition, marketData *MarketData) (float64, error) {
+	switch condition.Type {
+	case repository.TriggerTypeVolume:
+		return me.volumeValue(marketData.Symbol, condition)
+	case repository.TriggerTypeTime:
+		return float64(timeutil.Millis(me.now())), nil
+	default:
+		return me.extractValueFromMarketData(marketData, condition), nil
+	}
+}
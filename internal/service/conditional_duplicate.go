@@ -0,0 +1,193 @@
+package service
+
+import (
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	"math"
+	"time"
+)
+
+// duplicateTolerance is the relative difference below which two quantities, prices or trigger
+// values are the same number written differently, e.g. 0.1 and 0.10000000000000001
+const duplicateTolerance = 1e-9
+
+// triggerShape is the part of a spot or futures trigger condition that decides when it fires
+type triggerShape struct {
+	kind      int
+	priceType string
+	operator  int
+	value     float64
+	window    time.Duration
+	period    int    // RSI period, or the slow period of an MA crossover
+	fast      int    // MA crossover fast period
+	interval  string // RSI or MA crossover kline interval
+	composite bool
+	logic     int
+	subs      []*triggerShape
+}
+
+// spotTriggerShape returns the shape of a spot trigger condition. The base price of a
+// PRICE_CHANGE trigger is taken at creation and left out, so re-entering the same command
+// a moment later still matches.
+func spotTriggerShape(condition *repository.TriggerCondition) *triggerShape {
+	if condition == nil {
+		return nil
+	}
+	if len(condition.SubConditions) > 0 {
+		shape := &triggerShape{composite: true, logic: int(condition.CompositeType)}
+		for _, sub := range condition.SubConditions {
+			shape.subs = append(shape.subs, spotTriggerShape(sub))
+		}
+		return normalizeTriggerShape(shape)
+	}
+	shape := &triggerShape{
+		kind:     int(condition.Type),
+		operator: int(condition.Operator),
+		value:    condition.Value,
+		window:   condition.TimeWindow,
+	}
+	if condition.Type == repository.TriggerTypeRSI {
+		shape.period, shape.interval = condition.Period, rsiInterval(condition)
+	}
+	if condition.Type == repository.TriggerTypeMACrossover {
+		shape.fast, shape.period, shape.interval = condition.FastPeriod, condition.SlowPeriod, maCrossoverInterval(condition)
+	}
+	return shape
+}
+
+// futuresTriggerShape returns the shape of a futures trigger condition, leaving out the base price
+func futuresTriggerShape(condition *FuturesTriggerCondition) *triggerShape {
+	if condition == nil {
+		return nil
+	}
+	if len(condition.SubConditions) > 0 {
+		shape := &triggerShape{composite: true, logic: int(condition.CompositeType)}
+		for _, sub := range condition.SubConditions {
+			shape.subs = append(shape.subs, futuresTriggerShape(sub))
+		}
+		return normalizeTriggerShape(shape)
+	}
+	return &triggerShape{
+		kind:      int(condition.Type),
+		priceType: string(condition.PriceType),
+		operator:  int(condition.Operator),
+		value:     condition.Value,
+		window:    condition.TimeWindow,
+	}
+}
+
+// normalizeTriggerShape flattens nested composites of the same logic, drops repeated
+// sub-conditions and unwraps a composite left with a single sub-condition
+func normalizeTriggerShape(shape *triggerShape) *triggerShape {
+	var subs []*triggerShape
+	for _, sub := range shape.subs {
+		if sub == nil {
+			continue
+		}
+		flattened := []*triggerShape{sub}
+		if sub.composite && sub.logic == shape.logic {
+			flattened = sub.subs
+		}
+		for _, candidate := range flattened {
+			if !containsTriggerShape(subs, candidate) {
+				subs = append(subs, candidate)
+			}
+		}
+	}
+	if len(subs) == 1 {
+		return subs[0]
+	}
+	shape.subs = subs
+	return shape
+}
+
+// containsTriggerShape reports whether shapes holds a condition equal to shape
+func containsTriggerShape(shapes []*triggerShape, shape *triggerShape) bool {
+	for _, candidate := range shapes {
+		if sameTriggerShape(candidate, shape) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameTriggerShape compares normalized shapes; sub-conditions match in any order
+func sameTriggerShape(a, b *triggerShape) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.composite != b.composite {
+		return false
+	}
+	if !a.composite {
+		return a.kind == b.kind && a.priceType == b.priceType && a.operator == b.operator &&
+			sameAmount(a.value, b.value) && a.window == b.window && a.period == b.period && a.fast == b.fast && a.interval == b.interval
+	}
+	if a.logic != b.logic || len(a.subs) != len(b.subs) {
+		return false
+	}
+	used := make([]bool, len(b.subs))
+	for _, sub := range a.subs {
+		matched := false
+		for i, candidate := range b.subs {
+			if !used[i] && sameTriggerShape(sub, candidate) {
+				used[i] = true
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// sameAmount compares two numbers with a relative tolerance
+func sameAmount(a, b float64) bool {
+	if a == b {
+		return true
+	}
+	return math.Abs(a-b) <= duplicateTolerance*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// SameTriggerCondition reports whether two spot trigger conditions fire under the same market
+// conditions: composites match whatever the order of their sub-conditions, nested composites of
+// the same logic are flattened and a composite of a single condition equals that condition
+func SameTriggerCondition(a, b *repository.TriggerCondition) bool {
+	return sameTriggerShape(spotTriggerShape(a), spotTriggerShape(b))
+}
+
+// sameConditionalOrder reports whether an existing order does what a creation request asks for
+func sameConditionalOrder(order *repository.ConditionalOrder, request *repository.ConditionalOrderRequest) bool {
+	return order.Symbol == request.Symbol && order.Side == request.Side && order.Type == request.Type &&
+		sameAmount(order.Quantity, request.Quantity) && sameAmount(order.Price, request.Price) &&
+		sameAmount(order.PriceOffset, request.PriceOffset) && order.OffsetPercent == request.OffsetPercent &&
+		SameTriggerCondition(order.TriggerCondition, request.TriggerCondition)
+}
+
+// sameFuturesConditionalOrder reports whether an existing futures order does what a creation request asks for
+func sameFuturesConditionalOrder(order *FuturesConditionalOrder, request *FuturesConditionalOrderRequest) bool {
+	return order.Symbol == request.Symbol && order.Side == request.Side && order.PositionSide == request.PositionSide &&
+		order.Type == request.Type && order.ReduceOnly == request.ReduceOnly &&
+		sameAmount(order.Quantity, request.Quantity) && sameAmount(order.Price, request.Price) &&
+		sameTriggerShape(futuresTriggerShape(order.TriggerCondition), futuresTriggerShape(request.TriggerCondition))
+}
+
+// duplicateOrderError rejects a creation request that repeats an active order
+func duplicateOrderError(orderID string) error {
+	return errors.NewTradingError(errors.ErrDuplicateConditionalOrder,
+		"active conditional order "+orderID+" already has the same symbol, side, quantity and trigger", 0, nil)
+}
+
+// idempotencyKeyError rejects a creation request whose key was used for a different order
+func idempotencyKeyError(key, orderID string) error {
+	return errors.NewTradingError(errors.ErrInvalidParameter,
+		"idempotency key "+key+" was already used for conditional order "+orderID+" with different parameters", 0, nil)
+}
+
+// IsDuplicateOrderError reports whether err rejected a conditional order as a duplicate of an active one
+func IsDuplicateOrderError(err error) bool {
+	tradingErr, ok := err.(*errors.TradingError)
+	return ok && tradingErr.Type == errors.ErrDuplicateConditionalOrder
+}
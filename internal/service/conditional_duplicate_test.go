@@ -0,0 +1,219 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"strings"
+	"testing"
+	"time"
+)
+
+func priceTrigger(operator repository.ComparisonOperator, value float64) *repository.TriggerCondition {
+	return &repository.TriggerCondition{Type: repository.TriggerTypePrice, Operator: operator, Value: value}
+}
+
+func TestSameTriggerCondition(t *testing.T) {
+	volume := &repository.TriggerCondition{Type: repository.TriggerTypeVolume, Operator: repository.OperatorGreaterThan, Value: 20000, TimeWindow: time.Hour}
+	dip := priceTrigger(repository.OperatorLessEqual, 48000)
+	and := func(subs ...*repository.TriggerCondition) *repository.TriggerCondition {
+		return &repository.TriggerCondition{CompositeType: repository.LogicAND, SubConditions: subs}
+	}
+
+	tests := []struct {
+		name string
+		a, b *repository.TriggerCondition
+		same bool
+	}{
+		{"identical", dip, priceTrigger(repository.OperatorLessEqual, 48000), true},
+		{"value written differently", priceTrigger(repository.OperatorGreaterEqual, 0.1+0.2), priceTrigger(repository.OperatorGreaterEqual, 0.3), true},
+		{"sub-conditions reordered", and(dip, volume), and(volume, dip), true},
+		{"nested composite of the same logic", and(dip, and(volume, priceTrigger(repository.OperatorGreaterThan, 40000))), and(priceTrigger(repository.OperatorGreaterThan, 40000), dip, volume), true},
+		{"composite of one condition", and(dip), dip, true},
+		{"repeated sub-condition", and(dip, dip, volume), and(volume, dip), true},
+		{
+			"base price is taken at creation",
+			&repository.TriggerCondition{Type: repository.TriggerTypePriceChangePercent, Operator: repository.OperatorLessEqual, Value: -5, BasePrice: 50000},
+			&repository.TriggerCondition{Type: repository.TriggerTypePriceChangePercent, Operator: repository.OperatorLessEqual, Value: -5, BasePrice: 50012.5},
+			true,
+		},
+		{"strict and inclusive operators", priceTrigger(repository.OperatorLessThan, 48000), dip, false},
+		{"neighbouring value", priceTrigger(repository.OperatorLessEqual, 48000.5), dip, false},
+		{"different trigger type", &repository.TriggerCondition{Type: repository.TriggerTypeVolume, Operator: repository.OperatorLessEqual, Value: 48000}, dip, false},
+		{"different volume window", volume, &repository.TriggerCondition{Type: repository.TriggerTypeVolume, Operator: repository.OperatorGreaterThan, Value: 20000, TimeWindow: 4 * time.Hour}, false},
+		{"AND and OR", and(dip, volume), &repository.TriggerCondition{CompositeType: repository.LogicOR, SubConditions: []*repository.TriggerCondition{dip, volume}}, false},
+		{"extra sub-condition", and(dip, volume), and(dip), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SameTriggerCondition(tt.a, tt.b); got != tt.same {
+				t.Errorf("SameTriggerCondition(%s, %s) = %v, want %v", DescribeTriggerCondition(tt.a), DescribeTriggerCondition(tt.b), got, tt.same)
+			}
+			if got := SameTriggerCondition(tt.b, tt.a); got != tt.same {
+				t.Errorf("SameTriggerCondition is not symmetric for %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestCreateConditionalOrderRejectsDuplicates(t *testing.T) {
+	repo := repository.NewMemoryConditionalOrderRepository()
+	svc := NewConditionalOrderService(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(), nil, nil, nil, &mockLogger{})
+
+	request := func() *repository.ConditionalOrderRequest {
+		return &repository.ConditionalOrderRequest{
+			Symbol:           "BTCUSDT",
+			Side:             api.OrderSideBuy,
+			Type:             api.OrderTypeMarket,
+			Quantity:         0.01,
+			TriggerCondition: priceTrigger(repository.OperatorLessEqual, 48000),
+		}
+	}
+
+	original, err := svc.CreateConditionalOrder(request())
+	if err != nil {
+		t.Fatalf("CreateConditionalOrder() error = %v", err)
+	}
+
+	// The same command entered twice is refused with the ID of the first order
+	_, err = svc.CreateConditionalOrder(request())
+	if !IsDuplicateOrderError(err) || !strings.Contains(err.Error(), original.OrderID) {
+		t.Fatalf("duplicate error = %v, want one naming %s", err, original.OrderID)
+	}
+
+	// Near-duplicates are different orders
+	nearDuplicates := map[string]func(r *repository.ConditionalOrderRequest){
+		"quantity":  func(r *repository.ConditionalOrderRequest) { r.Quantity = 0.02 },
+		"side":      func(r *repository.ConditionalOrderRequest) { r.Side = api.OrderSideSell },
+		"symbol":    func(r *repository.ConditionalOrderRequest) { r.Symbol = "ETHUSDT" },
+		"operator":  func(r *repository.ConditionalOrderRequest) { r.TriggerCondition.Operator = repository.OperatorLessThan },
+		"threshold": func(r *repository.ConditionalOrderRequest) { r.TriggerCondition.Value = 47900 },
+		"limit price": func(r *repository.ConditionalOrderRequest) {
+			r.Type = api.OrderTypeLimit
+			r.Price = 48000
+		},
+	}
+	for name, modify := range nearDuplicates {
+		r := request()
+		modify(r)
+		if _, err := svc.CreateConditionalOrder(r); err != nil {
+			t.Errorf("order differing in %s was refused: %v", name, err)
+		}
+	}
+
+	// Asked for explicitly, a duplicate is created
+	r := request()
+	r.AllowDuplicate = true
+	if _, err := svc.CreateConditionalOrder(r); err != nil {
+		t.Errorf("CreateConditionalOrder() with AllowDuplicate error = %v", err)
+	}
+
+	// A cancelled order no longer blocks a new one
+	fresh := request()
+	fresh.Symbol = "SOLUSDT"
+	cancelled, err := svc.CreateConditionalOrder(fresh)
+	if err != nil {
+		t.Fatalf("CreateConditionalOrder() error = %v", err)
+	}
+	if _, err := svc.CancelConditionalOrder(cancelled.OrderID, ""); err != nil {
+		t.Fatalf("CancelConditionalOrder() error = %v", err)
+	}
+	fresh = request()
+	fresh.Symbol = "SOLUSDT"
+	if _, err := svc.CreateConditionalOrder(fresh); err != nil {
+		t.Errorf("order repeating a cancelled one was refused: %v", err)
+	}
+}
+
+func TestCreateConditionalOrderIdempotencyKey(t *testing.T) {
+	repo := repository.NewMemoryConditionalOrderRepository()
+	svc := NewConditionalOrderService(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(), nil, nil, nil, &mockLogger{})
+
+	request := func() *repository.ConditionalOrderRequest {
+		return &repository.ConditionalOrderRequest{
+			Symbol:           "BTCUSDT",
+			Side:             api.OrderSideSell,
+			Type:             api.OrderTypeMarket,
+			Quantity:         0.5,
+			TriggerCondition: priceTrigger(repository.OperatorGreaterEqual, 60000),
+			IdempotencyKey:   "take-profit-1",
+		}
+	}
+
+	original, err := svc.CreateConditionalOrder(request())
+	if err != nil {
+		t.Fatalf("CreateConditionalOrder() error = %v", err)
+	}
+	if original.IdempotencyKey != "take-profit-1" {
+		t.Errorf("IdempotencyKey = %q, want take-profit-1", original.IdempotencyKey)
+	}
+
+	// A retry returns the original order instead of a duplicate error or a second order
+	retried, err := svc.CreateConditionalOrder(request())
+	if err != nil || retried.OrderID != original.OrderID {
+		t.Fatalf("retry = %v, %v, want the original order %s", retried, err, original.OrderID)
+	}
+	if active, _ := svc.GetActiveConditionalOrders(); len(active) != 1 {
+		t.Errorf("got %d active orders after a retry, want 1", len(active))
+	}
+
+	// The key stays bound to its order after it stops being active
+	if err := repo.UpdateStatus(original.OrderID, repository.ConditionalOrderStatusExecuted, 1, 42); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+	if retried, err := svc.CreateConditionalOrder(request()); err != nil || retried.OrderID != original.OrderID || retried.ExecutedOrderID != 42 {
+		t.Errorf("retry after execution = %+v, %v, want the executed original", retried, err)
+	}
+
+	// Reusing the key for a different order is an error
+	different := request()
+	different.Quantity = 1
+	if _, err := svc.CreateConditionalOrder(different); err == nil || !strings.Contains(err.Error(), "already used for conditional order "+original.OrderID) {
+		t.Errorf("reused key error = %v", err)
+	}
+}
+
+func TestCreateFuturesConditionalOrderDuplicates(t *testing.T) {
+	svc := NewFuturesConditionalOrderService(nil, nil, nil, nil, &mockLogger{})
+
+	request := func() *FuturesConditionalOrderRequest {
+		return &FuturesConditionalOrderRequest{
+			Symbol:       "BTCUSDT",
+			Side:         api.OrderSideSell,
+			PositionSide: api.PositionSideLong,
+			Type:         api.OrderTypeMarket,
+			Quantity:     0.01,
+			TriggerCondition: &FuturesTriggerCondition{
+				Type:     FuturesTriggerTypeMarkPrice,
+				Operator: OperatorGreaterEqual,
+				Value:    60000,
+			},
+		}
+	}
+
+	original, err := svc.CreateConditionalOrder(request())
+	if err != nil {
+		t.Fatalf("CreateConditionalOrder() error = %v", err)
+	}
+	if _, err := svc.CreateConditionalOrder(request()); !IsDuplicateOrderError(err) {
+		t.Errorf("duplicate error = %v", err)
+	}
+
+	other := request()
+	other.PositionSide = api.PositionSideShort
+	if _, err := svc.CreateConditionalOrder(other); err != nil {
+		t.Errorf("order for the other position side was refused: %v", err)
+	}
+
+	keyed := request()
+	keyed.Quantity = 0.02
+	keyed.IdempotencyKey = "scale-out"
+	first, err := svc.CreateConditionalOrder(keyed)
+	if err != nil {
+		t.Fatalf("CreateConditionalOrder() error = %v", err)
+	}
+	retried, err := svc.CreateConditionalOrder(keyed)
+	if err != nil || retried.OrderID != first.OrderID || retried.OrderID == original.OrderID {
+		t.Errorf("retry = %v, %v, want %s", retried, err, first.OrderID)
+	}
+}
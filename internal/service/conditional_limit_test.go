@@ -0,0 +1,196 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// recordingLimitTradingService records the price of each limit order and the side of each market order
+type recordingLimitTradingService struct {
+	mockTradingService
+	limitPrices []float64
+	marketSides []api.OrderSide
+}
+
+func (m *recordingLimitTradingService) PlaceLimitSellOrder(symbol string, price, quantity float64) (*api.Order, error) {
+	m.limitPrices = append(m.limitPrices, price)
+	return m.mockTradingService.PlaceLimitSellOrder(symbol, price, quantity)
+}
+
+func (m *recordingLimitTradingService) PlaceLimitBuyOrder(symbol string, price, quantity float64) (*api.Order, error) {
+	m.limitPrices = append(m.limitPrices, price)
+	return m.mockTradingService.PlaceLimitBuyOrder(symbol, price, quantity)
+}
+
+func (m *recordingLimitTradingService) PlaceMarketBuyOrder(symbol string, quantity float64) (*api.Order, error) {
+	m.marketSides = append(m.marketSides, api.OrderSideBuy)
+	return m.mockTradingService.PlaceMarketBuyOrder(symbol, quantity)
+}
+
+func (m *recordingLimitTradingService) PlaceMarketSellOrder(symbol string, quantity float64) (*api.Order, error) {
+	m.marketSides = append(m.marketSides, api.OrderSideSell)
+	return m.mockTradingService.PlaceMarketSellOrder(symbol, quantity)
+}
+
+func newPriceTriggeredOrder(id string, side api.OrderSide, orderType api.OrderType) *repository.ConditionalOrder {
+	return &repository.ConditionalOrder{
+		OrderID:  id,
+		Symbol:   "BTCUSDT",
+		Side:     side,
+		Type:     orderType,
+		Quantity: 0.001,
+		TriggerCondition: &repository.TriggerCondition{
+			Type:     repository.TriggerTypePrice,
+			Operator: repository.OperatorGreaterEqual,
+			Value:    50000,
+		},
+		Status:    repository.ConditionalOrderStatusPending,
+		CreatedAt: time.Now().Unix(),
+	}
+}
+
+func TestConditionalLimitPrice(t *testing.T) {
+	tests := []struct {
+		name    string
+		price   float64
+		offset  float64
+		percent bool
+		want    float64
+		wantErr bool
+	}{
+		{"percent above", 0, 0.2, true, 50100, false},
+		{"percent below", 0, -0.5, true, 49750, false},
+		{"absolute above", 0, 25, false, 50025, false},
+		{"absolute below", 0, -50, false, 49950, false},
+		{"fixed price ignores the trigger", 47950, 0, false, 47950, false},
+		{"not positive", 0, -50000, false, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &repository.ConditionalOrder{Price: tt.price, PriceOffset: tt.offset, OffsetPercent: tt.percent}
+			price, err := conditionalLimitPrice(order, 50000)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("conditionalLimitPrice() = %v, want an error", price)
+				}
+				return
+			}
+			if err != nil || math.Abs(price-tt.want) > 1e-9 {
+				t.Errorf("conditionalLimitPrice() = %v, %v; want %v", price, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonitoringEngine_ConditionalLimitPricedFromTrigger(t *testing.T) {
+	repo := repository.NewMemoryConditionalOrderRepository()
+	trading := &recordingLimitTradingService{}
+	market := &mockMarketDataService{prices: map[string]float64{"BTCUSDT": 50000}}
+	engine := NewMonitoringEngine(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+		trading, market, &mockStopLossService{}, &mockLogger{}, nil)
+
+	sell := newPriceTriggeredOrder("sell", api.OrderSideSell, api.OrderTypeLimit)
+	sell.PriceOffset, sell.OffsetPercent = 0.2, true
+	buy := newPriceTriggeredOrder("buy", api.OrderSideBuy, api.OrderTypeLimit)
+	buy.PriceOffset = -50
+	for _, order := range []*repository.ConditionalOrder{sell, buy} {
+		repo.Save(order)
+		engine.processOrder(order)
+	}
+
+	want := []float64{50100, 49950}
+	if len(trading.limitPrices) != len(want) {
+		t.Fatalf("limit orders placed = %v, want %v", trading.limitPrices, want)
+	}
+	for i := range want {
+		if math.Abs(trading.limitPrices[i]-want[i]) > 1e-9 {
+			t.Errorf("limit order %d price = %v, want %v", i, trading.limitPrices[i], want[i])
+		}
+	}
+	for _, order := range []*repository.ConditionalOrder{sell, buy} {
+		if updated, _ := repo.FindByID(order.OrderID); updated.Status != repository.ConditionalOrderStatusExecuted {
+			t.Errorf("%s order status = %s, want executed", order.OrderID, updated.Status)
+		}
+	}
+}
+
+func TestMonitoringEngine_ConditionalMarketOrdersExecute(t *testing.T) {
+	repo := repository.NewMemoryConditionalOrderRepository()
+	trading := &recordingLimitTradingService{}
+	market := &mockMarketDataService{prices: map[string]float64{"BTCUSDT": 50000}}
+	engine := NewMonitoringEngine(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+		trading, market, &mockStopLossService{}, &mockLogger{}, nil)
+
+	for _, order := range []*repository.ConditionalOrder{
+		newPriceTriggeredOrder("buy", api.OrderSideBuy, api.OrderTypeMarket),
+		newPriceTriggeredOrder("sell", api.OrderSideSell, api.OrderTypeMarket),
+	} {
+		repo.Save(order)
+		engine.processOrder(order)
+		if updated, _ := repo.FindByID(order.OrderID); updated.Status != repository.ConditionalOrderStatusExecuted {
+			t.Errorf("%s order status = %s, want executed", order.OrderID, updated.Status)
+		}
+	}
+	if len(trading.limitPrices) != 0 {
+		t.Errorf("limit orders placed = %v, want none", trading.limitPrices)
+	}
+	if len(trading.marketSides) != 2 || trading.marketSides[0] != api.OrderSideBuy || trading.marketSides[1] != api.OrderSideSell {
+		t.Errorf("market orders placed = %v, want a buy then a sell", trading.marketSides)
+	}
+}
+
+func TestConditionalOrderService_ValidateLimitPrice(t *testing.T) {
+	svc := NewConditionalOrderService(repository.NewMemoryConditionalOrderRepository(), repository.NewMemoryStopOrderRepository(),
+		NewTriggerEngine(), &mockTradingService{}, &mockMarketDataService{}, &mockStopLossService{}, &mockLogger{})
+
+	tests := []struct {
+		name      string
+		orderType api.OrderType
+		price     float64
+		offset    float64
+		percent   bool
+		wantErr   bool
+	}{
+		{"market", api.OrderTypeMarket, 0, 0, false, false},
+		{"limit at a price", api.OrderTypeLimit, 49000, 0, false, false},
+		{"limit at a percent offset", api.OrderTypeLimit, 0, 0.2, true, false},
+		{"limit at an absolute offset", api.OrderTypeLimit, 0, -50, false, false},
+		{"limit without a price", api.OrderTypeLimit, 0, 0, false, true},
+		{"price and offset", api.OrderTypeLimit, 49000, 10, false, true},
+		{"offset of -100%", api.OrderTypeLimit, 0, -100, true, true},
+		{"market with a price", api.OrderTypeMarket, 49000, 0, false, true},
+		{"market with an offset", api.OrderTypeMarket, 0, 0.2, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateConditionalOrder(&repository.ConditionalOrderRequest{
+				Symbol:        "BTCUSDT",
+				Side:          api.OrderSideSell,
+				Type:          tt.orderType,
+				Quantity:      0.01,
+				Price:         tt.price,
+				PriceOffset:   tt.offset,
+				OffsetPercent: tt.percent,
+				TriggerCondition: &repository.TriggerCondition{
+					Type:     repository.TriggerTypePrice,
+					Operator: repository.OperatorGreaterEqual,
+					Value:    50000,
+				},
+				AllowDuplicate: true,
+			})
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("CreateConditionalOrder() error = %v", err)
+				}
+				return
+			}
+			if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrInvalidParameter {
+				t.Errorf("CreateConditionalOrder() error = %v, want ErrInvalidParameter", err)
+			}
+		})
+	}
+}
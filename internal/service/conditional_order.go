@@ -5,8 +5,10 @@ import (
 	"binance-trader/internal/repository"
 	"binance-trader/pkg/errors"
 	"binance-trader/pkg/logger"
+	"binance-trader/pkg/metrics"
+	"binance-trader/pkg/timeutil"
 	"fmt"
-	"time"
+	"sync"
 
 	"github.com/google/uuid"
 )
@@ -17,7 +19,9 @@ type ConditionalOrderService interface {
 	CreateConditionalOrder(request *repository.ConditionalOrderRequest) (*repository.ConditionalOrder, error)
 
 	// Manage conditional orders
-	CancelConditionalOrder(orderID string) error
+	CancelConditionalOrder(orderID, symbol string) (*repository.ConditionalOrder, error)
+	CancelConditionalOrderWithReason(orderID string, reason repository.CancelReason, cancelledBy string) error
+	CancelAllConditionalOrders(symbol string, reason repository.CancelReason, cancelledBy string) (int, error)
 	UpdateConditionalOrder(orderID string, updates *ConditionalOrderUpdate) error
 	GetConditionalOrder(orderID string) (*repository.ConditionalOrder, error)
 	GetActiveConditionalOrders() ([]*repository.ConditionalOrder, error)
@@ -26,6 +30,14 @@ type ConditionalOrderService interface {
 	// Monitoring and triggering
 	StartMonitoring() error
 	StopMonitoring() error
+	SetMaintenanceMonitor(monitor MaintenanceMonitor)
+	SetPriceSanityChecker(checker PriceSanityChecker)
+	SetMetrics(sink *metrics.Sink)
+	OnMonitoringGap(callback func(report *MonitoringGapReport))
+	GetSymbolEvaluationStats() map[string]SymbolEvaluationStats
+
+	// Pending orders of a symbol that stops trading are suspended until it trades again
+	SymbolSuspender
 }
 
 // ConditionalOrderUpdate represents updates to a conditional order
@@ -44,6 +56,7 @@ type conditionalOrderService struct {
 	marketDataService MarketDataService
 	logger            logger.Logger
 	monitoringEngine  *MonitoringEngine
+	createMu          sync.Mutex // Serializes the duplicate check and save of new orders
 }
 
 // NewConditionalOrderService creates a new conditional order service
@@ -93,8 +106,8 @@ func (s *conditionalOrderService) CreateConditionalOrder(request *repository.Con
 		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "quantity must be greater than 0", 0, nil)
 	}
 
-	if request.Type == api.OrderTypeLimit && request.Price <= 0 {
-		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "price must be greater than 0 for limit orders", 0, nil)
+	if err := validateConditionalLimitPrice(request); err != nil {
+		return nil, err
 	}
 
 	if request.TriggerCondition == nil {
@@ -106,6 +119,14 @@ func (s *conditionalOrderService) CreateConditionalOrder(request *repository.Con
 		return nil, err
 	}
 
+	s.createMu.Lock()
+	defer s.createMu.Unlock()
+
+	// A retried request returns the order its key created; a repeated one is refused
+	if existing, err := s.findExistingOrder(request); err != nil || existing != nil {
+		return existing, err
+	}
+
 	// Generate unique order ID
 	orderID := uuid.New().String()
 
@@ -117,10 +138,13 @@ func (s *conditionalOrderService) CreateConditionalOrder(request *repository.Con
 		Type:             request.Type,
 		Quantity:         request.Quantity,
 		Price:            request.Price,
+		PriceOffset:      request.PriceOffset,
+		OffsetPercent:    request.OffsetPercent,
 		TriggerCondition: request.TriggerCondition,
 		Status:           repository.ConditionalOrderStatusPending,
-		CreatedAt:        time.Now().Unix(),
+		CreatedAt:        timeutil.NowMillis(),
 		TimeWindow:       request.TimeWindow,
+		IdempotencyKey:   request.IdempotencyKey,
 	}
 
 	// Save to repository
@@ -152,8 +176,67 @@ func (s *conditionalOrderService) CreateConditionalOrder(request *repository.Con
 	return order, nil
 }
 
-// CancelConditionalOrder cancels a conditional order
-func (s *conditionalOrderService) CancelConditionalOrder(orderID string) error {
+// findExistingOrder returns the order created earlier with the request's idempotency key, or an
+// error if the key belongs to a different order or an active order already does the same
+func (s *conditionalOrderService) findExistingOrder(request *repository.ConditionalOrderRequest) (*repository.ConditionalOrder, error) {
+	if request.IdempotencyKey != "" {
+		order, err := s.repo.FindByIdempotencyKey(request.IdempotencyKey)
+		if err == nil {
+			if !sameConditionalOrder(order, request) {
+				return nil, idempotencyKeyError(request.IdempotencyKey, order.OrderID)
+			}
+			s.logger.Info("Conditional order already created for idempotency key", map[string]interface{}{
+				"order_id":        order.OrderID,
+				"idempotency_key": request.IdempotencyKey,
+			})
+			return order, nil
+		}
+		if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrConditionalOrderNotFound {
+			return nil, err
+		}
+	}
+
+	if request.AllowDuplicate {
+		return nil, nil
+	}
+
+	active, err := s.repo.FindActiveOrders()
+	if err != nil {
+		return nil, err
+	}
+	suspended, err := s.repo.FindOrdersByStatus(repository.ConditionalOrderStatusSuspendedSymbol)
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range append(active, suspended...) {
+		if sameConditionalOrder(order, request) {
+			return nil, duplicateOrderError(order.OrderID)
+		}
+	}
+	return nil, nil
+}
+
+// CancelConditionalOrder cancels a conditional order on behalf of the user; a non-empty symbol must
+// match the order's symbol. The cancelled order is returned so callers can show what was cancelled.
+func (s *conditionalOrderService) CancelConditionalOrder(orderID, symbol string) (*repository.ConditionalOrder, error) {
+	if orderID != "" && symbol != "" {
+		order, err := s.repo.FindByID(orderID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkOrderSymbol(orderID, order.Symbol, symbol); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.CancelConditionalOrderWithReason(orderID, repository.CancelReasonUser, "cli"); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(orderID)
+}
+
+// CancelConditionalOrderWithReason cancels a conditional order and records why and by whom
+func (s *conditionalOrderService) CancelConditionalOrderWithReason(orderID string, reason repository.CancelReason, cancelledBy string) error {
 	if orderID == "" {
 		return errors.NewTradingError(errors.ErrInvalidParameter, "order ID cannot be empty", 0, nil)
 	}
@@ -165,7 +248,7 @@ func (s *conditionalOrderService) CancelConditionalOrder(orderID string) error {
 	}
 
 	// Check if order can be cancelled
-	if order.Status != repository.ConditionalOrderStatusPending {
+	if order.Status != repository.ConditionalOrderStatusPending && order.Status != repository.ConditionalOrderStatusSuspendedSymbol {
 		return errors.NewTradingError(
 			errors.ErrInvalidParameter,
 			fmt.Sprintf("cannot cancel order with status %s", order.Status),
@@ -175,7 +258,7 @@ func (s *conditionalOrderService) CancelConditionalOrder(orderID string) error {
 	}
 
 	// Update status to cancelled
-	if err := s.repo.UpdateStatus(orderID, repository.ConditionalOrderStatusCancelled, 0, 0); err != nil {
+	if err := s.repo.UpdateStatusWithReason(orderID, repository.ConditionalOrderStatusCancelled, reason, cancelledBy, timeutil.NowMillis()); err != nil {
 		s.logger.LogError(err, map[string]interface{}{
 			"operation": "cancel_conditional_order",
 			"order_id":  orderID,
@@ -192,13 +275,71 @@ func (s *conditionalOrderService) CancelConditionalOrder(orderID string) error {
 	}
 
 	s.logger.Info("Conditional order cancelled", map[string]interface{}{
-		"order_id": orderID,
-		"symbol":   order.Symbol,
+		"order_id":     orderID,
+		"symbol":       order.Symbol,
+		"reason":       string(reason),
+		"cancelled_by": cancelledBy,
 	})
 
 	return nil
 }
 
+// CancelAllConditionalOrders cancels every pending conditional order, or only those of symbol
+// when it is set, and returns how many were cancelled
+func (s *conditionalOrderService) CancelAllConditionalOrders(symbol string, reason repository.CancelReason, cancelledBy string) (int, error) {
+	orders, err := s.repo.FindActiveOrders()
+	if err != nil {
+		return 0, err
+	}
+
+	cancelled := 0
+	for _, order := range orders {
+		if symbol != "" && order.Symbol != symbol {
+			continue
+		}
+		if err := s.CancelConditionalOrderWithReason(order.OrderID, reason, cancelledBy); err != nil {
+			return cancelled, err
+		}
+		cancelled++
+	}
+
+	return cancelled, nil
+}
+
+// SuspendSymbol moves the pending orders of a symbol to SUSPENDED_SYMBOL so they are not
+// evaluated, and returns how many were suspended
+func (s *conditionalOrderService) SuspendSymbol(symbol string) (int, error) {
+	orders, err := s.repo.FindActiveOrders()
+	if err != nil {
+		return 0, err
+	}
+	return s.setSymbolStatus(orders, symbol, repository.ConditionalOrderStatusSuspendedSymbol)
+}
+
+// ResumeSymbol returns the suspended orders of a symbol to PENDING
+func (s *conditionalOrderService) ResumeSymbol(symbol string) (int, error) {
+	orders, err := s.repo.FindOrdersByStatus(repository.ConditionalOrderStatusSuspendedSymbol)
+	if err != nil {
+		return 0, err
+	}
+	return s.setSymbolStatus(orders, symbol, repository.ConditionalOrderStatusPending)
+}
+
+// setSymbolStatus moves the orders of symbol to status
+func (s *conditionalOrderService) setSymbolStatus(orders []*repository.ConditionalOrder, symbol string, status repository.ConditionalOrderStatus) (int, error) {
+	changed := 0
+	for _, order := range orders {
+		if order.Symbol != symbol {
+			continue
+		}
+		if err := s.repo.UpdateStatus(order.OrderID, status, 0, 0); err != nil {
+			return changed, err
+		}
+		changed++
+	}
+	return changed, nil
+}
+
 // UpdateConditionalOrder updates a conditional order
 func (s *conditionalOrderService) UpdateConditionalOrder(orderID string, updates *ConditionalOrderUpdate) error {
 	if orderID == "" {
@@ -253,7 +394,9 @@ func (s *conditionalOrderService) UpdateConditionalOrder(orderID string, updates
 		if order.Type == api.OrderTypeLimit && *updates.Price <= 0 {
 			return errors.NewTradingError(errors.ErrInvalidParameter, "price must be greater than 0 for limit orders", 0, nil)
 		}
+		// A fixed price replaces pricing from the trigger price
 		order.Price = *updates.Price
+		order.PriceOffset, order.OffsetPercent = 0, false
 	}
 
 	if updates.TimeWindow != nil {
@@ -291,14 +434,11 @@ func (s *conditionalOrderService) GetActiveConditionalOrders() ([]*repository.Co
 	return s.repo.FindActiveOrders()
 }
 
-// GetConditionalOrderHistory retrieves conditional order history within a time range
+// GetConditionalOrderHistory retrieves executed and cancelled conditional orders created
+// within a time range given in Unix milliseconds
 func (s *conditionalOrderService) GetConditionalOrderHistory(startTime, endTime int64) ([]*repository.ConditionalOrder, error) {
-	if startTime < 0 || endTime < 0 {
-		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "time values cannot be negative", 0, nil)
-	}
-
-	if startTime > endTime {
-		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "start time cannot be after end time", 0, nil)
+	if err := repository.ValidateTimeRange(startTime, endTime); err != nil {
+		return nil, err
 	}
 
 	// Get all orders in time range
@@ -329,6 +469,75 @@ func (s *conditionalOrderService) StopMonitoring() error {
 	return s.monitoringEngine.Stop()
 }
 
+// SetMaintenanceMonitor pauses trigger executions during exchange maintenance
+func (s *conditionalOrderService) SetMaintenanceMonitor(monitor MaintenanceMonitor) {
+	s.monitoringEngine.SetMaintenanceMonitor(monitor)
+}
+
+// SetPriceSanityChecker holds back protective triggers on unconfirmed price spikes
+func (s *conditionalOrderService) SetPriceSanityChecker(checker PriceSanityChecker) {
+	s.monitoringEngine.SetPriceSanityChecker(checker)
+}
+
+// SetMetrics records the monitoring metrics in the sink instead of discarding them
+func (s *conditionalOrderService) SetMetrics(sink *metrics.Sink) {
+	s.monitoringEngine.SetMetrics(sink)
+}
+
+// OnMonitoringGap registers a callback invoked after monitoring resumes from a gap
+func (s *conditionalOrderService) OnMonitoringGap(callback func(report *MonitoringGapReport)) {
+	s.monitoringEngine.OnMonitoringGap(callback)
+}
+
+// GetSymbolEvaluationStats returns the monitoring latency statistics per symbol
+func (s *conditionalOrderService) GetSymbolEvaluationStats() map[string]SymbolEvaluationStats {
+	return s.monitoringEngine.GetSymbolEvaluationStats()
+}
+
+// validateConditionalLimitPrice checks that a limit order has a fixed price or an offset from
+// the trigger price, but not both, and that a market order has neither
+func validateConditionalLimitPrice(request *repository.ConditionalOrderRequest) error {
+	if request.Type != api.OrderTypeLimit {
+		if request.Price != 0 || request.PriceOffset != 0 {
+			return errors.NewTradingError(errors.ErrInvalidParameter, "market orders take no price or price offset", 0, nil)
+		}
+		return nil
+	}
+
+	if request.Price < 0 || (request.Price == 0 && request.PriceOffset == 0) {
+		return errors.NewTradingError(errors.ErrInvalidParameter, "price must be greater than 0 for limit orders, or a price offset given", 0, nil)
+	}
+	if request.Price > 0 && request.PriceOffset != 0 {
+		return errors.NewTradingError(errors.ErrInvalidParameter, "a limit order takes a price or a price offset, not both", 0, nil)
+	}
+	if request.OffsetPercent && request.PriceOffset <= -100 {
+		return errors.NewTradingError(errors.ErrInvalidParameter, "a percentage price offset must be above -100%", 0, nil)
+	}
+	return nil
+}
+
+// conditionalLimitPrice returns the price a triggered limit order is placed at: its fixed price,
+// or the trigger price moved by its offset
+func conditionalLimitPrice(order *repository.ConditionalOrder, triggerPrice float64) (float64, error) {
+	if order.Price > 0 {
+		return order.Price, nil
+	}
+
+	price := triggerPrice + order.PriceOffset
+	if order.OffsetPercent {
+		price = triggerPrice * (1 + order.PriceOffset/100)
+	}
+	if price <= 0 {
+		return 0, errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			fmt.Sprintf("limit price %.8f from trigger price %.8f is not positive", price, triggerPrice),
+			0,
+			nil,
+		)
+	}
+	return price, nil
+}
+
 // validateTriggerCondition validates a trigger condition
 func (s *conditionalOrderService) validateTriggerCondition(condition *repository.TriggerCondition) error {
 	if condition == nil {
@@ -338,6 +547,20 @@ func (s *conditionalOrderService) validateTriggerCondition(condition *repository
 	// Validate composite conditions
 	if len(condition.SubConditions) > 0 {
 		for _, subCond := range condition.SubConditions {
+			// Each sub-condition is evaluated against its own price, volume or time; indicators
+			// that keep state across ticks cannot be combined
+			if subCond != nil && subCond.Type == repository.TriggerTypeRSI {
+				return errors.NewTradingError(errors.ErrInvalidTriggerCondition, "RSI conditions cannot be part of a composite condition", 0, nil)
+			}
+			if subCond != nil && subCond.Type == repository.TriggerTypeAskBidImbalance {
+				return errors.NewTradingError(errors.ErrInvalidTriggerCondition, "ask/bid imbalance conditions cannot be part of a composite condition", 0, nil)
+			}
+			if subCond != nil && subCond.Type == repository.TriggerTypeMACrossover {
+				return errors.NewTradingError(errors.ErrInvalidTriggerCondition, "MA crossover conditions cannot be part of a composite condition", 0, nil)
+			}
+			if subCond != nil && subCond.Type == repository.TriggerTypeVolumeSurge {
+				return errors.NewTradingError(errors.ErrInvalidTriggerCondition, "volume surge conditions cannot be part of a composite condition", 0, nil)
+			}
 			if err := s.validateTriggerCondition(subCond); err != nil {
 				return err
 			}
@@ -355,6 +578,42 @@ func (s *conditionalOrderService) validateTriggerCondition(condition *repository
 		)
 	}
 
+	if condition.Type == repository.TriggerTypeVolume {
+		if err := validateVolumeCondition(condition); err != nil {
+			return errors.NewTradingError(errors.ErrInvalidTriggerCondition, err.Error(), 0, nil)
+		}
+	}
+
+	if condition.Type == repository.TriggerTypeRSI {
+		if err := validateRSICondition(condition); err != nil {
+			return errors.NewTradingError(errors.ErrInvalidTriggerCondition, err.Error(), 0, nil)
+		}
+	}
+
+	if condition.Type == repository.TriggerTypeAskBidImbalance {
+		if err := validateImbalanceCondition(condition); err != nil {
+			return errors.NewTradingError(errors.ErrInvalidTriggerCondition, err.Error(), 0, nil)
+		}
+	}
+
+	if condition.Type == repository.TriggerTypeMACrossover {
+		if err := validateMACrossoverCondition(condition); err != nil {
+			return errors.NewTradingError(errors.ErrInvalidTriggerCondition, err.Error(), 0, nil)
+		}
+	}
+
+	if condition.Type == repository.TriggerTypeVolumeSurge {
+		if err := validateVolumeSurgeCondition(condition); err != nil {
+			return errors.NewTradingError(errors.ErrInvalidTriggerCondition, err.Error(), 0, nil)
+		}
+	}
+
+	if condition.Type == repository.TriggerTypeTime {
+		if err := validateTimeCondition(condition); err != nil {
+			return errors.NewTradingError(errors.ErrInvalidTriggerCondition, err.Error(), 0, nil)
+		}
+	}
+
 	return nil
 }
 
@@ -370,6 +629,10 @@ func (s *conditionalOrderService) convertToServiceTriggerCondition(repoCond *rep
 		Value:         repoCond.Value,
 		BasePrice:     repoCond.BasePrice,
 		TimeWindow:    repoCond.TimeWindow,
+		Period:        repoCond.Period,
+		Interval:      repoCond.Interval,
+		FastPeriod:    repoCond.FastPeriod,
+		SlowPeriod:    repoCond.SlowPeriod,
 		CompositeType: LogicOperator(repoCond.CompositeType),
 	}
 
@@ -5,6 +5,7 @@ import (
 	"binance-trader/internal/repository"
 	"binance-trader/pkg/logger"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -122,7 +123,7 @@ func TestProperty_ConditionalOrderCancellationEffect(t *testing.T) {
 			}
 
 			// Cancel order
-			if err := service.CancelConditionalOrder(order.OrderID); err != nil {
+			if _, err := service.CancelConditionalOrder(order.OrderID, ""); err != nil {
 				t.Logf("Failed to cancel order: %v", err)
 				return false
 			}
@@ -250,7 +251,7 @@ func TestProperty_HistoricalOrderQueryFiltering(t *testing.T) {
 
 			// Query history (use wide time range to get all orders)
 			startTime := int64(0)
-			endTime := time.Now().Unix() + 1000000
+			endTime := time.Now().UnixMilli() + 1000000
 
 			historyOrders, err := service.GetConditionalOrderHistory(startTime, endTime)
 			if err != nil {
@@ -304,7 +305,7 @@ func genConditionalOrder() gopter.Gen {
 		gen.Float64Range(1.0, 100000.0),                    // Price
 		genTriggerCondition(),                               // TriggerCondition
 		genConditionalOrderStatus(),                         // Status
-		gen.Int64Range(1000000000, 2000000000),             // CreatedAt
+		gen.Int64Range(1000000000000, 1700000000000),        // CreatedAt (Unix ms)
 	).Map(func(values []interface{}) *repository.ConditionalOrder {
 		// Generate unique order ID using counter
 		orderIDCounter++
@@ -447,11 +448,23 @@ func TestConditionalOrderService_CancelConditionalOrder(t *testing.T) {
 		t.Fatalf("Failed to create order: %v", err)
 	}
 
-	// Cancel the order
-	err = service.CancelConditionalOrder(order.OrderID)
+	// An expected symbol that does not match is refused
+	if _, err := service.CancelConditionalOrder(order.OrderID, "ETHUSDT"); err == nil || !strings.Contains(err.Error(), "belongs to BTCUSDT, not ETHUSDT") {
+		t.Fatalf("Expected symbol mismatch error, got %v", err)
+	}
+	if pending, _ := service.GetConditionalOrder(order.OrderID); pending.Status != repository.ConditionalOrderStatusPending {
+		t.Fatalf("Expected the order to stay pending, got %s", pending.Status)
+	}
+
+	// Cancel the order; the cancelled order is returned for confirmation
+	returned, err := service.CancelConditionalOrder(order.OrderID, "BTCUSDT")
 	if err != nil {
 		t.Fatalf("Failed to cancel order: %v", err)
 	}
+	if returned.OrderID != order.OrderID || returned.Quantity != 1.0 || returned.TriggerCondition == nil ||
+		returned.Status != repository.ConditionalOrderStatusCancelled || returned.CancelReason != repository.CancelReasonUser {
+		t.Errorf("Unexpected cancelled order: %+v", returned)
+	}
 
 	// Verify order is cancelled
 	cancelledOrder, err := service.GetConditionalOrder(order.OrderID)
@@ -464,7 +477,7 @@ func TestConditionalOrderService_CancelConditionalOrder(t *testing.T) {
 	}
 
 	// Test cancelling non-existent order
-	err = service.CancelConditionalOrder("non-existent-id")
+	_, err = service.CancelConditionalOrder("non-existent-id", "")
 	if err == nil {
 		t.Error("Expected error for non-existent order")
 	}
@@ -545,6 +558,7 @@ func TestConditionalOrderService_GetActiveConditionalOrders(t *testing.T) {
 				Operator: repository.OperatorGreaterThan,
 				Value:    50000.0,
 			},
+			AllowDuplicate: true,
 		}
 		_, err := service.CreateConditionalOrder(request)
 		if err != nil {
@@ -596,14 +610,14 @@ func TestConditionalOrderService_GetConditionalOrderHistory(t *testing.T) {
 		t.Fatalf("Failed to create order: %v", err)
 	}
 
-	err = service.CancelConditionalOrder(order.OrderID)
+	_, err = service.CancelConditionalOrder(order.OrderID, "")
 	if err != nil {
 		t.Fatalf("Failed to cancel order: %v", err)
 	}
 
 	// Get history
-	startTime := time.Now().Unix() - 1000
-	endTime := time.Now().Unix() + 1000
+	startTime := time.Now().UnixMilli() - 1000_000
+	endTime := time.Now().UnixMilli() + 1000_000
 
 	history, err := service.GetConditionalOrderHistory(startTime, endTime)
 	if err != nil {
@@ -621,3 +635,67 @@ func TestConditionalOrderService_GetConditionalOrderHistory(t *testing.T) {
 
 
 
+
+func TestConditionalOrderService_CancelReasons(t *testing.T) {
+	repo := repository.NewMemoryConditionalOrderRepository()
+	log, _ := logger.NewLogger(logger.Config{Level: "info", EnableConsole: false})
+	service := NewConditionalOrderService(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(), nil, nil, nil, log)
+
+	create := func(symbol string) string {
+		order, err := service.CreateConditionalOrder(&repository.ConditionalOrderRequest{
+			Symbol:   symbol,
+			Side:     api.OrderSideBuy,
+			Type:     api.OrderTypeMarket,
+			Quantity: 1.0,
+			TriggerCondition: &repository.TriggerCondition{
+				Type:     repository.TriggerTypePrice,
+				Operator: repository.OperatorGreaterThan,
+				Value:    50000.0,
+			},
+			AllowDuplicate: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create order: %v", err)
+		}
+		return order.OrderID
+	}
+
+	userID := create("BTCUSDT")
+	bulkID := create("ETHUSDT")
+	keptID := create("BTCUSDT")
+
+	// CLI cancellation
+	if _, err := service.CancelConditionalOrder(userID, ""); err != nil {
+		t.Fatalf("Failed to cancel order: %v", err)
+	}
+	order, _ := service.GetConditionalOrder(userID)
+	if order.CancelReason != repository.CancelReasonUser || order.CancelledBy != "cli" || order.CancelledAt == 0 {
+		t.Errorf("Expected user cancellation by cli, got %s by %q at %d", order.CancelReason, order.CancelledBy, order.CancelledAt)
+	}
+
+	// Bulk cancellation limited to one symbol
+	cancelled, err := service.CancelAllConditionalOrders("ETHUSDT", repository.CancelReasonBulk, "cli")
+	if err != nil || cancelled != 1 {
+		t.Fatalf("Expected 1 bulk cancellation, got %d (%v)", cancelled, err)
+	}
+	if order, _ := service.GetConditionalOrder(bulkID); order.CancelReason != repository.CancelReasonBulk {
+		t.Errorf("Expected BULK reason, got %s", order.CancelReason)
+	}
+	if order, _ := service.GetConditionalOrder(keptID); order.Status != repository.ConditionalOrderStatusPending {
+		t.Errorf("Orders of other symbols should stay pending, got %s", order.Status)
+	}
+
+	// Shutdown cancels everything still pending
+	if cancelled, _ := service.CancelAllConditionalOrders("", repository.CancelReasonShutdown, "shutdown"); cancelled != 1 {
+		t.Errorf("Expected 1 shutdown cancellation, got %d", cancelled)
+	}
+	if order, _ := service.GetConditionalOrder(keptID); order.CancelReason != repository.CancelReasonShutdown || order.CancelledBy != "shutdown" {
+		t.Errorf("Expected SHUTDOWN reason, got %s by %q", order.CancelReason, order.CancelledBy)
+	}
+
+	history, _ := service.GetConditionalOrderHistory(0, time.Now().UnixMilli()+1)
+	counts := repository.CountCancellationsByReason(history)
+	if counts[repository.CancelReasonUser] != 1 || counts[repository.CancelReasonBulk] != 1 || counts[repository.CancelReasonShutdown] != 1 {
+		t.Errorf("Expected one cancellation per reason, got %v", counts)
+	}
+}
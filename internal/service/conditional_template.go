@@ -0,0 +1,505 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConditionalTemplateVersion is the template schema written by export. Readers accept this
+// version and older ones and refuse newer ones rather than guessing at fields they don't know.
+const ConditionalTemplateVersion = 1
+
+// HoldingProvider returns the base asset balance held for a symbol
+type HoldingProvider func(symbol string) (float64, error)
+
+// NewSpotHoldingProvider returns the free and locked base asset balance of a spot symbol
+func NewSpotHoldingProvider(client api.SpotClient) HoldingProvider {
+	return func(symbol string) (float64, error) {
+		quote := extractQuoteAsset(symbol)
+		if quote == "" {
+			return 0, fmt.Errorf("cannot determine base asset of %s", symbol)
+		}
+		balance, err := client.GetBalance(strings.TrimSuffix(symbol, quote))
+		if err != nil {
+			return 0, err
+		}
+		return balance.Free + balance.Locked, nil
+	}
+}
+
+// ConditionalOrderTemplate is a reusable set of conditional order definitions
+type ConditionalOrderTemplate struct {
+	Version    int                           `yaml:"version"`
+	ExportedAt string                        `yaml:"exported_at,omitempty"` // RFC3339, informational
+	Orders     []*ConditionalOrderDefinition `yaml:"orders"`
+}
+
+// ConditionalOrderDefinition describes one conditional order independently of when it is created
+type ConditionalOrderDefinition struct {
+	Symbol          string             `yaml:"symbol,omitempty"` // May be left out when importing with a symbol override
+	Side            string             `yaml:"side"`             // BUY or SELL
+	Type            string             `yaml:"type,omitempty"`   // MARKET (default) or LIMIT
+	Quantity        float64            `yaml:"quantity,omitempty"`
+	QuantityPercent float64            `yaml:"quantity_percent,omitempty"` // SELL only: percent of the holding at import
+	Price           float64            `yaml:"price,omitempty"`            // LIMIT only
+	PriceOffset     float64            `yaml:"price_offset,omitempty"`     // LIMIT without a price: added to the trigger price
+	OffsetPercent   bool               `yaml:"offset_percent,omitempty"`   // price_offset is a percentage of the trigger price
+	Trigger         *TriggerDefinition `yaml:"trigger"`
+	StartsIn        string             `yaml:"starts_in,omitempty"`  // Delay before the order may trigger, e.g. 30m
+	ExpiresIn       string             `yaml:"expires_in,omitempty"` // Time after which the order is cancelled, e.g. 24h
+}
+
+// TriggerDefinition is a simple trigger (type, operator and value) or a composite of conditions
+type TriggerDefinition struct {
+	Type       string               `yaml:"type,omitempty"`       // PRICE, PRICE_CHANGE or VOLUME
+	Operator   string               `yaml:"operator,omitempty"`   // >=, <=, > or <
+	Value      float64              `yaml:"value,omitempty"`      // Threshold in the unit of the type
+	BasePrice  float64              `yaml:"base_price,omitempty"` // PRICE_CHANGE reference; the current price when unset
+	Window     string               `yaml:"window,omitempty"`     // VOLUME time window, e.g. 1h; 24h when unset
+	Logic      string               `yaml:"logic,omitempty"`      // Composite: AND or OR
+	Conditions []*TriggerDefinition `yaml:"conditions,omitempty"` // Composite: the combined conditions
+}
+
+// TemplateOptions controls how template definitions become conditional order requests
+type TemplateOptions struct {
+	Symbol       string                               // Overrides the symbol of every definition when set
+	Now          time.Time                            // Start of the relative time windows
+	Holding      HoldingProvider                      // Resolves quantity_percent; required only when used
+	CurrentPrice func(symbol string) (float64, error) // Fills missing PRICE_CHANGE base prices
+}
+
+// Names used for trigger types, operators and logic in templates
+var (
+	triggerTypeNames = map[repository.TriggerType]string{
+		repository.TriggerTypePrice:              "PRICE",
+		repository.TriggerTypePriceChangePercent: "PRICE_CHANGE",
+		repository.TriggerTypeVolume:             "VOLUME",
+	}
+	operatorNames = map[repository.ComparisonOperator]string{
+		repository.OperatorGreaterEqual: ">=",
+		repository.OperatorLessEqual:    "<=",
+		repository.OperatorGreaterThan:  ">",
+		repository.OperatorLessThan:     "<",
+	}
+	logicNames = map[repository.LogicOperator]string{
+		repository.LogicAND: "AND",
+		repository.LogicOR:  "OR",
+	}
+)
+
+// NewConditionalOrderTemplate describes orders as a template. Time windows become durations
+// relative to now, and SELL quantities become a percent of the current holding when holding is
+// set and the symbol has a balance.
+func NewConditionalOrderTemplate(orders []*repository.ConditionalOrder, now time.Time, holding HoldingProvider) (*ConditionalOrderTemplate, error) {
+	template := &ConditionalOrderTemplate{
+		Version:    ConditionalTemplateVersion,
+		ExportedAt: now.UTC().Format(time.RFC3339),
+		Orders:     make([]*ConditionalOrderDefinition, 0, len(orders)),
+	}
+
+	for _, order := range orders {
+		trigger, err := describeTrigger(order.TriggerCondition)
+		if err != nil {
+			return nil, fmt.Errorf("order %s: %w", order.OrderID, err)
+		}
+
+		definition := &ConditionalOrderDefinition{
+			Symbol:   order.Symbol,
+			Side:     string(order.Side),
+			Type:     string(order.Type),
+			Quantity: order.Quantity,
+			Trigger:  trigger,
+		}
+		if order.Type == api.OrderTypeLimit {
+			definition.Price = order.Price
+			definition.PriceOffset = order.PriceOffset
+			definition.OffsetPercent = order.OffsetPercent
+		}
+
+		if order.Side == api.OrderSideSell && holding != nil {
+			held, err := holding(order.Symbol)
+			if err != nil {
+				return nil, fmt.Errorf("order %s: failed to get holding of %s: %w", order.OrderID, order.Symbol, err)
+			}
+			if held > 0 && order.Quantity <= held {
+				definition.Quantity = 0
+				definition.QuantityPercent = order.Quantity / held * 100
+			}
+		}
+
+		if window := order.TimeWindow; window != nil {
+			if window.StartTime.After(now) {
+				definition.StartsIn = FormatShortDuration(window.StartTime.Sub(now))
+			}
+			if window.EndTime.After(now) {
+				definition.ExpiresIn = FormatShortDuration(window.EndTime.Sub(now))
+			}
+		}
+
+		template.Orders = append(template.Orders, definition)
+	}
+
+	return template, nil
+}
+
+// describeTrigger converts a trigger condition to its template form
+func describeTrigger(condition *repository.TriggerCondition) (*TriggerDefinition, error) {
+	if condition == nil {
+		return nil, fmt.Errorf("missing trigger condition")
+	}
+
+	if len(condition.SubConditions) > 0 {
+		trigger := &TriggerDefinition{Logic: logicNames[condition.CompositeType]}
+		for _, sub := range condition.SubConditions {
+			subTrigger, err := describeTrigger(sub)
+			if err != nil {
+				return nil, err
+			}
+			trigger.Conditions = append(trigger.Conditions, subTrigger)
+		}
+		return trigger, nil
+	}
+
+	typeName, ok := triggerTypeNames[condition.Type]
+	if !ok {
+		return nil, fmt.Errorf("unsupported trigger type %d", condition.Type)
+	}
+	trigger := &TriggerDefinition{
+		Type:     typeName,
+		Operator: operatorNames[condition.Operator],
+		Value:    condition.Value,
+	}
+	switch condition.Type {
+	case repository.TriggerTypePriceChangePercent:
+		trigger.BasePrice = condition.BasePrice
+	case repository.TriggerTypeVolume:
+		if condition.TimeWindow > 0 {
+			trigger.Window = FormatShortDuration(condition.TimeWindow)
+		}
+	}
+	return trigger, nil
+}
+
+// FormatShortDuration renders a duration in whole seconds without zero trailing units, e.g. 24h or 1h30m
+func FormatShortDuration(d time.Duration) string {
+	s := d.Round(time.Second).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// MarshalConditionalOrderTemplate encodes a template as YAML
+func MarshalConditionalOrderTemplate(template *ConditionalOrderTemplate) ([]byte, error) {
+	return yaml.Marshal(template)
+}
+
+// ParseConditionalOrderTemplate decodes a YAML template and validates its version and every definition
+func ParseConditionalOrderTemplate(data []byte) (*ConditionalOrderTemplate, error) {
+	var template ConditionalOrderTemplate
+	if err := yaml.Unmarshal(data, &template); err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+
+	if template.Version <= 0 {
+		return nil, fmt.Errorf("template has no version")
+	}
+	if template.Version > ConditionalTemplateVersion {
+		return nil, fmt.Errorf("template version %d is newer than the supported version %d", template.Version, ConditionalTemplateVersion)
+	}
+	if len(template.Orders) == 0 {
+		return nil, fmt.Errorf("template has no orders")
+	}
+
+	for i, definition := range template.Orders {
+		if err := definition.validate(); err != nil {
+			return nil, fmt.Errorf("orders[%d]: %w", i, err)
+		}
+	}
+
+	return &template, nil
+}
+
+// validate checks a definition without resolving anything that depends on the market
+func (d *ConditionalOrderDefinition) validate() error {
+	switch strings.ToUpper(d.Side) {
+	case string(api.OrderSideBuy), string(api.OrderSideSell):
+	default:
+		return fmt.Errorf("side must be BUY or SELL, got %q", d.Side)
+	}
+
+	switch strings.ToUpper(d.Type) {
+	case "", string(api.OrderTypeMarket):
+	case string(api.OrderTypeLimit):
+		if d.Price < 0 || (d.Price == 0 && d.PriceOffset == 0) {
+			return fmt.Errorf("price or price_offset is required for LIMIT orders")
+		}
+		if d.Price > 0 && d.PriceOffset != 0 {
+			return fmt.Errorf("set price or price_offset, not both")
+		}
+	default:
+		return fmt.Errorf("type must be MARKET or LIMIT, got %q", d.Type)
+	}
+
+	switch {
+	case d.Quantity < 0 || d.QuantityPercent < 0:
+		return fmt.Errorf("quantity cannot be negative")
+	case d.Quantity > 0 && d.QuantityPercent > 0:
+		return fmt.Errorf("set quantity or quantity_percent, not both")
+	case d.Quantity == 0 && d.QuantityPercent == 0:
+		return fmt.Errorf("quantity or quantity_percent is required")
+	case d.QuantityPercent > 100:
+		return fmt.Errorf("quantity_percent cannot exceed 100")
+	case d.QuantityPercent > 0 && strings.ToUpper(d.Side) != string(api.OrderSideSell):
+		return fmt.Errorf("quantity_percent is only supported for SELL orders")
+	}
+
+	if d.Trigger == nil {
+		return fmt.Errorf("trigger is required")
+	}
+	if _, err := d.Trigger.condition(); err != nil {
+		return fmt.Errorf("trigger: %w", err)
+	}
+
+	startsIn, err := parseTemplateDuration("starts_in", d.StartsIn)
+	if err != nil {
+		return err
+	}
+	expiresIn, err := parseTemplateDuration("expires_in", d.ExpiresIn)
+	if err != nil {
+		return err
+	}
+	if d.ExpiresIn != "" && expiresIn <= startsIn {
+		return fmt.Errorf("expires_in must be later than starts_in")
+	}
+	return nil
+}
+
+// parseTemplateDuration parses an optional non-negative duration; empty means zero
+func parseTemplateDuration(field, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid duration %q", field, value)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%s cannot be negative", field)
+	}
+	return d, nil
+}
+
+// condition converts the template form of a trigger to a trigger condition
+func (t *TriggerDefinition) condition() (*repository.TriggerCondition, error) {
+	if len(t.Conditions) > 0 {
+		if t.Type != "" {
+			return nil, fmt.Errorf("a composite trigger cannot also have a type")
+		}
+
+		condition := &repository.TriggerCondition{}
+		switch strings.ToUpper(t.Logic) {
+		case "AND":
+			condition.CompositeType = repository.LogicAND
+		case "OR":
+			condition.CompositeType = repository.LogicOR
+		default:
+			return nil, fmt.Errorf("logic must be AND or OR, got %q", t.Logic)
+		}
+
+		for i, sub := range t.Conditions {
+			if sub == nil {
+				return nil, fmt.Errorf("conditions[%d] is empty", i)
+			}
+			subCondition, err := sub.condition()
+			if err != nil {
+				return nil, fmt.Errorf("conditions[%d]: %w", i, err)
+			}
+			condition.SubConditions = append(condition.SubConditions, subCondition)
+		}
+		return condition, nil
+	}
+
+	condition := &repository.TriggerCondition{Value: t.Value, BasePrice: t.BasePrice}
+	switch strings.ToUpper(t.Type) {
+	case "PRICE":
+		condition.Type = repository.TriggerTypePrice
+	case "PRICE_CHANGE":
+		condition.Type = repository.TriggerTypePriceChangePercent
+	case "VOLUME":
+		condition.Type = repository.TriggerTypeVolume
+		window, err := parseTemplateDuration("window", t.Window)
+		if err != nil {
+			return nil, err
+		}
+		if window == 0 {
+			window = DefaultVolumeWindow
+		}
+		condition.TimeWindow = window
+	default:
+		return nil, fmt.Errorf("type must be PRICE, PRICE_CHANGE or VOLUME, got %q", t.Type)
+	}
+
+	switch strings.ToUpper(t.Operator) {
+	case ">=", "GE":
+		condition.Operator = repository.OperatorGreaterEqual
+	case "<=", "LE":
+		condition.Operator = repository.OperatorLessEqual
+	case ">", "GT":
+		condition.Operator = repository.OperatorGreaterThan
+	case "<", "LT":
+		condition.Operator = repository.OperatorLessThan
+	default:
+		return nil, fmt.Errorf("operator must be >=, <=, > or <, got %q", t.Operator)
+	}
+
+	if condition.BasePrice < 0 {
+		return nil, fmt.Errorf("base_price cannot be negative")
+	}
+	return condition, nil
+}
+
+// Requests turns every definition into a conditional order request, resolving symbols,
+// percentages, base prices and time windows. Nothing is returned unless all definitions resolve.
+func (t *ConditionalOrderTemplate) Requests(opts TemplateOptions) ([]*repository.ConditionalOrderRequest, error) {
+	requests := make([]*repository.ConditionalOrderRequest, 0, len(t.Orders))
+	for i, definition := range t.Orders {
+		request, err := definition.request(opts)
+		if err != nil {
+			return nil, fmt.Errorf("orders[%d]: %w", i, err)
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+// request resolves one definition
+func (d *ConditionalOrderDefinition) request(opts TemplateOptions) (*repository.ConditionalOrderRequest, error) {
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+
+	symbol := strings.ToUpper(d.Symbol)
+	override := opts.Symbol != ""
+	if override {
+		symbol = strings.ToUpper(opts.Symbol)
+	}
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required without a symbol override")
+	}
+
+	condition, err := d.Trigger.condition()
+	if err != nil {
+		return nil, fmt.Errorf("trigger: %w", err)
+	}
+	// A base price belongs to the symbol it was exported from
+	if err := fillBasePrices(condition, symbol, override, opts.CurrentPrice); err != nil {
+		return nil, err
+	}
+
+	quantity := d.Quantity
+	if d.QuantityPercent > 0 {
+		if opts.Holding == nil {
+			return nil, fmt.Errorf("quantity_percent needs the holding of %s, which is not available", symbol)
+		}
+		held, err := opts.Holding(symbol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get holding of %s: %w", symbol, err)
+		}
+		if held <= 0 {
+			return nil, fmt.Errorf("quantity_percent of %g%%: no %s holding", d.QuantityPercent, symbol)
+		}
+		quantity = held * d.QuantityPercent / 100
+	}
+
+	orderType := api.OrderTypeMarket
+	if strings.ToUpper(d.Type) == string(api.OrderTypeLimit) {
+		orderType = api.OrderTypeLimit
+	}
+
+	request := &repository.ConditionalOrderRequest{
+		Symbol:           symbol,
+		Side:             api.OrderSide(strings.ToUpper(d.Side)),
+		Type:             orderType,
+		Quantity:         quantity,
+		Price:            d.Price,
+		TriggerCondition: condition,
+	}
+	if orderType == api.OrderTypeLimit {
+		request.PriceOffset = d.PriceOffset
+		request.OffsetPercent = d.OffsetPercent
+	}
+
+	if d.StartsIn != "" || d.ExpiresIn != "" {
+		// Both durations were checked by validate
+		startsIn, _ := parseTemplateDuration("starts_in", d.StartsIn)
+		expiresIn, _ := parseTemplateDuration("expires_in", d.ExpiresIn)
+		request.TimeWindow = &repository.TimeWindow{StartTime: opts.Now.Add(startsIn)}
+		if d.ExpiresIn != "" {
+			request.TimeWindow.EndTime = opts.Now.Add(expiresIn)
+		}
+	}
+
+	return request, nil
+}
+
+// fillBasePrices sets the base price of PRICE_CHANGE conditions that have none, or of all of
+// them when the template is applied to another symbol
+func fillBasePrices(condition *repository.TriggerCondition, symbol string, override bool, currentPrice func(symbol string) (float64, error)) error {
+	for _, sub := range condition.SubConditions {
+		if err := fillBasePrices(sub, symbol, override, currentPrice); err != nil {
+			return err
+		}
+	}
+	if condition.Type != repository.TriggerTypePriceChangePercent || len(condition.SubConditions) > 0 {
+		return nil
+	}
+	if condition.BasePrice > 0 && !override {
+		return nil
+	}
+
+	if currentPrice == nil {
+		return fmt.Errorf("PRICE_CHANGE trigger needs a base price for %s", symbol)
+	}
+	price, err := currentPrice(symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get current price of %s: %w", symbol, err)
+	}
+	if price <= 0 {
+		return fmt.Errorf("no current price for %s", symbol)
+	}
+	condition.BasePrice = price
+	return nil
+}
+
+// DescribeTriggerCondition renders a trigger condition the way templates write it, e.g.
+// "PRICE >= 50000" or "(PRICE >= 50000 AND VOLUME > 20000 over 1h)"
+func DescribeTriggerCondition(condition *repository.TriggerCondition) string {
+	if condition == nil {
+		return "-"
+	}
+	if len(condition.SubConditions) > 0 {
+		parts := make([]string, len(condition.SubConditions))
+		for i, sub := range condition.SubConditions {
+			parts[i] = DescribeTriggerCondition(sub)
+		}
+		return "(" + strings.Join(parts, " "+logicNames[condition.CompositeType]+" ") + ")"
+	}
+
+	description := fmt.Sprintf("%s %s %g", triggerTypeNames[condition.Type], operatorNames[condition.Operator], condition.Value)
+	switch {
+	case condition.Type == repository.TriggerTypePriceChangePercent && condition.BasePrice > 0:
+		description += fmt.Sprintf("%% from %g", condition.BasePrice)
+	case condition.Type == repository.TriggerTypeVolume && condition.TimeWindow > 0:
+		description += " over " + FormatShortDuration(condition.TimeWindow)
+	}
+	return description
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// templateFixtureOrders covers market and limit orders, percent quantities, time windows and
+// composite triggers
+func templateFixtureOrders(now time.Time) []*repository.ConditionalOrder {
+	return []*repository.ConditionalOrder{
+		{
+			OrderID:  "buy-dip",
+			Symbol:   "BTCUSDT",
+			Side:     api.OrderSideBuy,
+			Type:     api.OrderTypeMarket,
+			Quantity: 0.01,
+			TriggerCondition: &repository.TriggerCondition{
+				Type:     repository.TriggerTypePrice,
+				Operator: repository.OperatorLessEqual,
+				Value:    48000,
+			},
+			TimeWindow: &repository.TimeWindow{StartTime: now.Add(30 * time.Minute), EndTime: now.Add(24 * time.Hour)},
+			Status:     repository.ConditionalOrderStatusPending,
+		},
+		{
+			OrderID:  "take-quarter",
+			Symbol:   "BTCUSDT",
+			Side:     api.OrderSideSell,
+			Type:     api.OrderTypeLimit,
+			Quantity: 0.5,
+			Price:    55000,
+			TriggerCondition: &repository.TriggerCondition{
+				CompositeType: repository.LogicAND,
+				SubConditions: []*repository.TriggerCondition{
+					{Type: repository.TriggerTypePriceChangePercent, Operator: repository.OperatorGreaterEqual, Value: 5, BasePrice: 50000},
+					{Type: repository.TriggerTypeVolume, Operator: repository.OperatorGreaterThan, Value: 20000, TimeWindow: time.Hour},
+				},
+			},
+			Status: repository.ConditionalOrderStatusPending,
+		},
+		{
+			OrderID:       "sell-breakout",
+			Symbol:        "BTCUSDT",
+			Side:          api.OrderSideSell,
+			Type:          api.OrderTypeLimit,
+			Quantity:      0.1,
+			PriceOffset:   0.2,
+			OffsetPercent: true,
+			TriggerCondition: &repository.TriggerCondition{
+				Type:     repository.TriggerTypePrice,
+				Operator: repository.OperatorGreaterEqual,
+				Value:    60000,
+			},
+			Status: repository.ConditionalOrderStatusPending,
+		},
+	}
+}
+
+// templateHoldings returns fixed base asset holdings per symbol
+func templateHoldings(holdings map[string]float64) HoldingProvider {
+	return func(symbol string) (float64, error) {
+		held, ok := holdings[symbol]
+		if !ok {
+			return 0, fmt.Errorf("no balance for %s", symbol)
+		}
+		return held, nil
+	}
+}
+
+func TestConditionalOrderTemplateRoundTrip(t *testing.T) {
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	orders := templateFixtureOrders(now)
+	holdings := templateHoldings(map[string]float64{"BTCUSDT": 2})
+
+	template, err := NewConditionalOrderTemplate(orders, now, holdings)
+	if err != nil {
+		t.Fatalf("NewConditionalOrderTemplate() error = %v", err)
+	}
+	data, err := MarshalConditionalOrderTemplate(template)
+	if err != nil {
+		t.Fatalf("MarshalConditionalOrderTemplate() error = %v", err)
+	}
+
+	text := string(data)
+	for _, want := range []string{"version: 1", "quantity_percent: 25", "starts_in: 30m", "expires_in: 24h", "window: 1h", "logic: AND", "price_offset: 0.2"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("exported template should contain %q:\n%s", want, text)
+		}
+	}
+
+	parsed, err := ParseConditionalOrderTemplate(data)
+	if err != nil {
+		t.Fatalf("ParseConditionalOrderTemplate() error = %v\n%s", err, text)
+	}
+	requests, err := parsed.Requests(TemplateOptions{Now: now, Holding: holdings})
+	if err != nil {
+		t.Fatalf("Requests() error = %v", err)
+	}
+
+	if len(requests) != len(orders) {
+		t.Fatalf("got %d requests, want %d", len(requests), len(orders))
+	}
+	for i, request := range requests {
+		order := orders[i]
+		if request.Symbol != order.Symbol || request.Side != order.Side || request.Type != order.Type || request.Price != order.Price ||
+			request.PriceOffset != order.PriceOffset || request.OffsetPercent != order.OffsetPercent {
+			t.Errorf("request %d = %+v, want the fields of %+v", i, request, order)
+		}
+		if math.Abs(request.Quantity-order.Quantity) > 1e-12 {
+			t.Errorf("request %d quantity = %v, want %v", i, request.Quantity, order.Quantity)
+		}
+		if !reflect.DeepEqual(request.TriggerCondition, order.TriggerCondition) {
+			t.Errorf("request %d trigger = %s, want %s", i, DescribeTriggerCondition(request.TriggerCondition), DescribeTriggerCondition(order.TriggerCondition))
+		}
+		if !reflect.DeepEqual(request.TimeWindow, order.TimeWindow) {
+			t.Errorf("request %d window = %+v, want %+v", i, request.TimeWindow, order.TimeWindow)
+		}
+	}
+}
+
+func TestConditionalOrderTemplateSymbolOverride(t *testing.T) {
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	template, err := NewConditionalOrderTemplate(templateFixtureOrders(now), now, templateHoldings(map[string]float64{"BTCUSDT": 2}))
+	if err != nil {
+		t.Fatalf("NewConditionalOrderTemplate() error = %v", err)
+	}
+
+	requests, err := template.Requests(TemplateOptions{
+		Symbol:  "ethusdt",
+		Now:     now.Add(time.Hour),
+		Holding: templateHoldings(map[string]float64{"ETHUSDT": 8}),
+		CurrentPrice: func(symbol string) (float64, error) {
+			if symbol != "ETHUSDT" {
+				return 0, fmt.Errorf("unexpected symbol %s", symbol)
+			}
+			return 3000, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Requests() error = %v", err)
+	}
+
+	for _, request := range requests {
+		if request.Symbol != "ETHUSDT" {
+			t.Errorf("request symbol = %s, want ETHUSDT", request.Symbol)
+		}
+	}
+	// 25% of the ETH holding, with the price change measured from the current ETH price
+	if requests[1].Quantity != 2 {
+		t.Errorf("percent quantity = %v, want 2", requests[1].Quantity)
+	}
+	if base := requests[1].TriggerCondition.SubConditions[0].BasePrice; base != 3000 {
+		t.Errorf("base price = %v, want the current ETHUSDT price 3000", base)
+	}
+	// Windows are relative to the import time
+	if want := now.Add(time.Hour + 30*time.Minute); !requests[0].TimeWindow.StartTime.Equal(want) {
+		t.Errorf("window start = %v, want %v", requests[0].TimeWindow.StartTime, want)
+	}
+
+	// Without an override, the template needs a symbol for every order
+	bare := &ConditionalOrderTemplate{Version: 1, Orders: []*ConditionalOrderDefinition{{
+		Side:     "BUY",
+		Quantity: 1,
+		Trigger:  &TriggerDefinition{Type: "PRICE", Operator: "<=", Value: 10},
+	}}}
+	if _, err := bare.Requests(TemplateOptions{Now: now}); err == nil || !strings.Contains(err.Error(), "symbol is required") {
+		t.Errorf("expected missing symbol error, got %v", err)
+	}
+	if requests, err := bare.Requests(TemplateOptions{Symbol: "SOLUSDT", Now: now}); err != nil || requests[0].Symbol != "SOLUSDT" {
+		t.Errorf("override Requests() = %v, %v", requests, err)
+	}
+}
+
+func TestParseConditionalOrderTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		errorMsg string
+	}{
+		{
+			name: "older fields only",
+			yaml: "version: 1\norders:\n  - symbol: BTCUSDT\n    side: buy\n    quantity: 0.1\n    trigger: {type: price, operator: GE, value: 50000}\n",
+		},
+		{
+			name: "unknown fields are ignored",
+			yaml: "version: 1\nnotes: weekly set\norders:\n  - symbol: BTCUSDT\n    side: BUY\n    quantity: 0.1\n    label: dip\n    trigger: {type: PRICE, operator: '<=', value: 40000}\n",
+		},
+		{
+			name:     "newer version",
+			yaml:     "version: 2\norders: []\n",
+			errorMsg: "template version 2 is newer than the supported version 1",
+		},
+		{
+			name:     "missing version",
+			yaml:     "orders:\n  - side: BUY\n",
+			errorMsg: "template has no version",
+		},
+		{
+			name:     "percent of a buy",
+			yaml:     "version: 1\norders:\n  - symbol: BTCUSDT\n    side: BUY\n    quantity_percent: 50\n    trigger: {type: PRICE, operator: '<=', value: 40000}\n",
+			errorMsg: "orders[0]: quantity_percent is only supported for SELL orders",
+		},
+		{
+			name:     "bad nested operator",
+			yaml:     "version: 1\norders:\n  - symbol: BTCUSDT\n    side: SELL\n    quantity: 1\n    trigger:\n      logic: OR\n      conditions:\n        - {type: PRICE, operator: '>=', value: 60000}\n        - {type: VOLUME, operator: '=', value: 1}\n",
+			errorMsg: `orders[0]: trigger: conditions[1]: operator must be >=, <=, > or <, got "="`,
+		},
+		{
+			name:     "window ends before it starts",
+			yaml:     "version: 1\norders:\n  - symbol: BTCUSDT\n    side: BUY\n    quantity: 1\n    starts_in: 2h\n    expires_in: 1h\n    trigger: {type: PRICE, operator: '<=', value: 40000}\n",
+			errorMsg: "orders[0]: expires_in must be later than starts_in",
+		},
+		{
+			name:     "limit price and offset",
+			yaml:     "version: 1\norders:\n  - symbol: BTCUSDT\n    side: SELL\n    type: LIMIT\n    quantity: 1\n    price: 60000\n    price_offset: 50\n    trigger: {type: PRICE, operator: '>=', value: 60000}\n",
+			errorMsg: "orders[0]: set price or price_offset, not both",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConditionalOrderTemplate([]byte(tt.yaml))
+			if tt.errorMsg == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/pkg/logger"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DataSourceKind identifies where prices are read from
+type DataSourceKind string
+
+const (
+	DataSourceWebSocket DataSourceKind = "WEBSOCKET"
+	DataSourceREST      DataSourceKind = "REST"
+)
+
+// Default fallback policy thresholds
+const (
+	DefaultDataSourceMaxFailures       = 3
+	DefaultDataSourceFailureWindow     = time.Minute
+	DefaultDataSourceProbeInterval     = 5 * time.Second
+	DefaultDataSourceRecoverySuccesses = 3
+)
+
+// PriceSource supplies the latest price of a symbol, e.g. from a WebSocket stream
+type PriceSource interface {
+	GetPrice(symbol string) (float64, error)
+}
+
+// DataSourceHealth is a snapshot of one symbol's stream health
+type DataSourceHealth struct {
+	Symbol         string
+	Active         DataSourceKind
+	RecentFailures int   // Stream failures within the failure window
+	SwitchedAt     int64 // Milliseconds since epoch of the last switch, 0 if never switched
+	RecoveryProbes int   // Consecutive healthy probes since falling back
+}
+
+// DataSourceManager serves prices from the preferred stream and transparently falls back to
+// REST polling per symbol while the stream is unhealthy. Consumers use it as a MarketDataService.
+type DataSourceManager interface {
+	MarketDataService
+
+	// SetStreamSource sets the preferred streaming price source; nil serves everything from REST
+	SetStreamSource(source PriceSource)
+
+	// ReportDisconnect records a stream disconnect for a symbol
+	ReportDisconnect(symbol string)
+
+	GetActiveSource(symbol string) DataSourceKind
+	GetSourceHealth() []*DataSourceHealth
+}
+
+// streamHealth tracks the stream state of one symbol
+type streamHealth struct {
+	active         DataSourceKind
+	failures       []time.Time
+	switchedAt     time.Time
+	lastProbe      time.Time
+	recoveryProbes int
+}
+
+// dataSourceRoute is the way a price request is served
+type dataSourceRoute int
+
+const (
+	routeREST dataSourceRoute = iota
+	routeStream
+	routeProbe
+)
+
+// dataSourceManager implements DataSourceManager
+type dataSourceManager struct {
+	rest              MarketDataService
+	stream            PriceSource
+	preferStream      bool
+	maxFailures       int
+	failureWindow     time.Duration
+	probeInterval     time.Duration
+	recoverySuccesses int
+	logger            logger.Logger
+	now               func() time.Time
+
+	mu      sync.Mutex
+	streams map[string]*streamHealth
+}
+
+// NewDataSourceManager creates a data source manager that polls rest while no healthy stream is available
+func NewDataSourceManager(rest MarketDataService, cfg *config.DataSourceConfig, log logger.Logger) DataSourceManager {
+	if cfg == nil {
+		cfg = &config.DataSourceConfig{}
+	}
+
+	m := &dataSourceManager{
+		rest:              rest,
+		preferStream:      cfg.Prefer != "rest",
+		maxFailures:       cfg.MaxFailures,
+		failureWindow:     time.Duration(cfg.FailureWindowMs) * time.Millisecond,
+		probeInterval:     time.Duration(cfg.ProbeIntervalMs) * time.Millisecond,
+		recoverySuccesses: cfg.RecoverySuccesses,
+		logger:            log,
+		now:               time.Now,
+		streams:           make(map[string]*streamHealth),
+	}
+
+	if m.maxFailures <= 0 {
+		m.maxFailures = DefaultDataSourceMaxFailures
+	}
+	if m.failureWindow <= 0 {
+		m.failureWindow = DefaultDataSourceFailureWindow
+	}
+	if m.probeInterval <= 0 {
+		m.probeInterval = DefaultDataSourceProbeInterval
+	}
+	if m.recoverySuccesses <= 0 {
+		m.recoverySuccesses = DefaultDataSourceRecoverySuccesses
+	}
+
+	return m
+}
+
+// SetStreamSource sets the preferred streaming price source
+func (m *dataSourceManager) SetStreamSource(source PriceSource) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stream = source
+}
+
+// GetCurrentPrice returns the price from the stream while it is healthy and from REST otherwise
+func (m *dataSourceManager) GetCurrentPrice(symbol string) (float64, error) {
+	if symbol == "" {
+		return 0, fmt.Errorf("symbol cannot be empty")
+	}
+
+	route, stream := m.route(symbol)
+	switch route {
+	case routeStream:
+		price, err := stream.GetPrice(symbol)
+		if err == nil && price > 0 {
+			return price, nil
+		}
+		m.recordFailure(symbol, err)
+	case routeProbe:
+		price, err := stream.GetPrice(symbol)
+		if m.recordProbe(symbol, err == nil && price > 0) {
+			return price, nil
+		}
+	}
+
+	return m.rest.GetCurrentPrice(symbol)
+}
+
+// GetHistoricalData is always served by REST
+func (m *dataSourceManager) GetHistoricalData(symbol string, interval string, limit int) ([]*api.Kline, error) {
+	return m.rest.GetHistoricalData(symbol, interval, limit)
+}
+
+// SubscribeToPrice is delegated to the REST market data service
+func (m *dataSourceManager) SubscribeToPrice(symbol string, callback func(float64)) error {
+	return m.rest.SubscribeToPrice(symbol, callback)
+}
+
+// SetPriceStream sets the ticker stream of the REST market data service's subscriptions
+func (m *dataSourceManager) SetPriceStream(stream PriceStream) {
+	m.rest.SetPriceStream(stream)
+}
+
+// SubscribePriceFeed is delegated to the REST market data service, which owns the price stream
+func (m *dataSourceManager) SubscribePriceFeed(ctx context.Context, symbol string) (<-chan float64, error) {
+	return m.rest.SubscribePriceFeed(ctx, symbol)
+}
+
+// ConnectedStreams is delegated to the REST market data service
+func (m *dataSourceManager) ConnectedStreams() []string {
+	return m.rest.ConnectedStreams()
+}
+
+// GetVolume is always served by REST
+func (m *dataSourceManager) GetVolume(symbol string, timeWindow time.Duration) (float64, error) {
+	return m.rest.GetVolume(symbol, timeWindow)
+}
+
+// GetBestBidAsk is delegated to the REST market data service, which owns the book ticker cache
+func (m *dataSourceManager) GetBestBidAsk(symbol string) (*BestBidAsk, error) {
+	return m.rest.GetBestBidAsk(symbol)
+}
+
+// GetOrderBook is always served by REST
+func (m *dataSourceManager) GetOrderBook(symbol string, limit int) (*api.OrderBook, error) {
+	return m.rest.GetOrderBook(symbol, limit)
+}
+
+// SetBookTickerCache sets the book ticker cache on the REST market data service
+func (m *dataSourceManager) SetBookTickerCache(cache BookTickerCache) {
+	m.rest.SetBookTickerCache(cache)
+}
+
+// ReportDisconnect records a stream disconnect for a symbol
+func (m *dataSourceManager) ReportDisconnect(symbol string) {
+	m.recordFailure(symbol, fmt.Errorf("stream disconnected"))
+}
+
+// GetActiveSource returns the source currently serving a symbol
+func (m *dataSourceManager) GetActiveSource(symbol string) DataSourceKind {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stream == nil || !m.preferStream {
+		return DataSourceREST
+	}
+	return m.health(symbol).active
+}
+
+// GetSourceHealth returns the stream health of every symbol seen so far, sorted by symbol
+func (m *dataSourceManager) GetSourceHealth() []*DataSourceHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	result := make([]*DataSourceHealth, 0, len(m.streams))
+	for symbol, health := range m.streams {
+		m.pruneFailures(health, now)
+		snapshot := &DataSourceHealth{
+			Symbol:         symbol,
+			Active:         health.active,
+			RecentFailures: len(health.failures),
+			RecoveryProbes: health.recoveryProbes,
+		}
+		if m.stream == nil || !m.preferStream {
+			snapshot.Active = DataSourceREST
+		}
+		if !health.switchedAt.IsZero() {
+			snapshot.SwitchedAt = health.switchedAt.UnixMilli()
+		}
+		result = append(result, snapshot)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Symbol < result[j].Symbol
+	})
+	return result
+}
+
+// route decides how the next price request for a symbol is served
+func (m *dataSourceManager) route(symbol string) (dataSourceRoute, PriceSource) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stream == nil || !m.preferStream {
+		return routeREST, nil
+	}
+
+	health := m.health(symbol)
+	if health.active == DataSourceWebSocket {
+		return routeStream, m.stream
+	}
+
+	now := m.now()
+	if now.Sub(health.lastProbe) < m.probeInterval {
+		return routeREST, nil
+	}
+	health.lastProbe = now
+	return routeProbe, m.stream
+}
+
+// recordFailure counts a stream failure and falls back to REST once the threshold is reached
+func (m *dataSourceManager) recordFailure(symbol string, cause error) {
+	m.mu.Lock()
+	health := m.health(symbol)
+	now := m.now()
+	m.pruneFailures(health, now)
+	health.failures = append(health.failures, now)
+
+	if health.active != DataSourceWebSocket || len(health.failures) < m.maxFailures {
+		m.mu.Unlock()
+		return
+	}
+
+	health.active = DataSourceREST
+	health.switchedAt = now
+	health.lastProbe = now
+	health.recoveryProbes = 0
+	failures := len(health.failures)
+	m.mu.Unlock()
+
+	fields := map[string]interface{}{
+		"symbol":         symbol,
+		"failures":       failures,
+		"failure_window": m.failureWindow.String(),
+	}
+	if cause != nil {
+		fields["error"] = cause.Error()
+	}
+	m.logger.Warn("Price stream unhealthy, falling back to REST polling", fields)
+}
+
+// recordProbe records a stream probe made while on REST; it returns true when the stream is restored
+func (m *dataSourceManager) recordProbe(symbol string, healthy bool) bool {
+	m.mu.Lock()
+	health := m.health(symbol)
+	if !healthy {
+		health.recoveryProbes = 0
+		m.mu.Unlock()
+		return false
+	}
+
+	health.recoveryProbes++
+	if health.recoveryProbes < m.recoverySuccesses {
+		m.mu.Unlock()
+		return false
+	}
+
+	now := m.now()
+	downtime := now.Sub(health.switchedAt)
+	health.active = DataSourceWebSocket
+	health.switchedAt = now
+	health.failures = nil
+	health.recoveryProbes = 0
+	m.mu.Unlock()
+
+	m.logger.Info("Price stream recovered, switching back from REST polling", map[string]interface{}{
+		"symbol":   symbol,
+		"downtime": downtime.String(),
+	})
+	return true
+}
+
+// health returns the stream state of a symbol; callers must hold m.mu
+func (m *dataSourceManager) health(symbol string) *streamHealth {
+	health, exists := m.streams[symbol]
+	if !exists {
+		health = &streamHealth{active: DataSourceWebSocket}
+		m.streams[symbol] = health
+	}
+	return health
+}
+
+// pruneFailures drops failures older than the failure window; callers must hold m.mu
+func (m *dataSourceManager) pruneFailures(health *streamHealth, now time.Time) {
+	cutoff := now.Add(-m.failureWindow)
+	kept := health.failures[:0]
+	for _, failure := range health.failures {
+		if failure.After(cutoff) {
+			kept = append(kept, failure)
+		}
+	}
+	health.failures = kept
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"binance-trader/internal/config"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// stubPriceSource is a stream source whose health can be toggled
+type stubPriceSource struct {
+	price   float64
+	healthy bool
+	calls   int
+}
+
+func (s *stubPriceSource) GetPrice(symbol string) (float64, error) {
+	s.calls++
+	if !s.healthy {
+		return 0, fmt.Errorf("stream for %s disconnected", symbol)
+	}
+	return s.price, nil
+}
+
+func newTestDataSourceManager(stream PriceSource, restPrice float64) (*dataSourceManager, *time.Time) {
+	rest := &mockMarketDataService{prices: map[string]float64{"BTCUSDT": restPrice}}
+	cfg := &config.DataSourceConfig{
+		Prefer:            "websocket",
+		MaxFailures:       2,
+		FailureWindowMs:   60000,
+		ProbeIntervalMs:   5000,
+		RecoverySuccesses: 2,
+	}
+	manager := NewDataSourceManager(rest, cfg, &mockLogger{}).(*dataSourceManager)
+	manager.SetStreamSource(stream)
+
+	current := time.Unix(1700000000, 0)
+	manager.now = func() time.Time { return current }
+	return manager, &current
+}
+
+func TestDataSourceManager_PrefersHealthyStream(t *testing.T) {
+	stream := &stubPriceSource{price: 50100, healthy: true}
+	manager, _ := newTestDataSourceManager(stream, 50000)
+
+	price, err := manager.GetCurrentPrice("BTCUSDT")
+	if err != nil {
+		t.Fatalf("GetCurrentPrice failed: %v", err)
+	}
+	if price != 50100 {
+		t.Errorf("expected stream price 50100, got %v", price)
+	}
+	if source := manager.GetActiveSource("BTCUSDT"); source != DataSourceWebSocket {
+		t.Errorf("expected WEBSOCKET source, got %s", source)
+	}
+}
+
+func TestDataSourceManager_SwitchesToRESTWhenStreamUnhealthy(t *testing.T) {
+	stream := &stubPriceSource{price: 50100}
+	manager, current := newTestDataSourceManager(stream, 50000)
+
+	// A failed stream read is served transparently from REST
+	price, err := manager.GetCurrentPrice("BTCUSDT")
+	if err != nil || price != 50000 {
+		t.Fatalf("expected REST price 50000 after stream failure, got %v (%v)", price, err)
+	}
+	if source := manager.GetActiveSource("BTCUSDT"); source != DataSourceWebSocket {
+		t.Fatalf("a single failure should not switch sources, got %s", source)
+	}
+
+	*current = current.Add(time.Second)
+	manager.GetCurrentPrice("BTCUSDT")
+	if source := manager.GetActiveSource("BTCUSDT"); source != DataSourceREST {
+		t.Fatalf("expected REST after repeated failures, got %s", source)
+	}
+
+	// While on REST the stream is only probed once per probe interval
+	calls := stream.calls
+	*current = current.Add(time.Second)
+	if price, _ := manager.GetCurrentPrice("BTCUSDT"); price != 50000 {
+		t.Errorf("expected REST price while on fallback, got %v", price)
+	}
+	if stream.calls != calls {
+		t.Errorf("stream should not be read before the probe interval, got %d extra calls", stream.calls-calls)
+	}
+
+	health := manager.GetSourceHealth()
+	if len(health) != 1 || health[0].Active != DataSourceREST || health[0].RecentFailures != 2 || health[0].SwitchedAt == 0 {
+		t.Errorf("unexpected health snapshot: %+v", health[0])
+	}
+}
+
+func TestDataSourceManager_FailuresOutsideWindowDoNotSwitch(t *testing.T) {
+	stream := &stubPriceSource{price: 50100}
+	manager, current := newTestDataSourceManager(stream, 50000)
+
+	manager.GetCurrentPrice("BTCUSDT")
+	*current = current.Add(2 * time.Minute)
+	manager.GetCurrentPrice("BTCUSDT")
+
+	if source := manager.GetActiveSource("BTCUSDT"); source != DataSourceWebSocket {
+		t.Errorf("failures further apart than the window should not switch, got %s", source)
+	}
+}
+
+func TestDataSourceManager_ReportDisconnect(t *testing.T) {
+	stream := &stubPriceSource{price: 50100, healthy: true}
+	manager, _ := newTestDataSourceManager(stream, 50000)
+
+	manager.ReportDisconnect("BTCUSDT")
+	manager.ReportDisconnect("BTCUSDT")
+
+	if source := manager.GetActiveSource("BTCUSDT"); source != DataSourceREST {
+		t.Errorf("expected REST after repeated disconnects, got %s", source)
+	}
+	if source := manager.GetActiveSource("ETHUSDT"); source != DataSourceWebSocket {
+		t.Errorf("other symbols should keep their stream, got %s", source)
+	}
+}
+
+func TestDataSourceManager_SwitchesBackOnRecovery(t *testing.T) {
+	stream := &stubPriceSource{price: 50100}
+	manager, current := newTestDataSourceManager(stream, 50000)
+
+	manager.GetCurrentPrice("BTCUSDT")
+	manager.GetCurrentPrice("BTCUSDT")
+	if source := manager.GetActiveSource("BTCUSDT"); source != DataSourceREST {
+		t.Fatalf("expected REST after repeated failures, got %s", source)
+	}
+
+	stream.healthy = true
+
+	// First healthy probe is not enough to switch back
+	*current = current.Add(5 * time.Second)
+	if price, _ := manager.GetCurrentPrice("BTCUSDT"); price != 50000 {
+		t.Errorf("expected REST price until recovery completes, got %v", price)
+	}
+
+	// A failed probe resets the recovery count
+	stream.healthy = false
+	*current = current.Add(5 * time.Second)
+	manager.GetCurrentPrice("BTCUSDT")
+	stream.healthy = true
+	*current = current.Add(5 * time.Second)
+	manager.GetCurrentPrice("BTCUSDT")
+	if source := manager.GetActiveSource("BTCUSDT"); source != DataSourceREST {
+		t.Fatalf("a failed probe should restart recovery, got %s", source)
+	}
+
+	*current = current.Add(5 * time.Second)
+	price, err := manager.GetCurrentPrice("BTCUSDT")
+	if err != nil || price != 50100 {
+		t.Errorf("expected stream price once recovered, got %v (%v)", price, err)
+	}
+	if source := manager.GetActiveSource("BTCUSDT"); source != DataSourceWebSocket {
+		t.Errorf("expected WEBSOCKET after recovery, got %s", source)
+	}
+
+	health := manager.GetSourceHealth()
+	if health[0].RecentFailures != 0 || health[0].RecoveryProbes != 0 {
+		t.Errorf("expected health reset after recovery, got %+v", health[0])
+	}
+}
+
+func TestDataSourceManager_RESTPreferenceOrMissingStream(t *testing.T) {
+	stream := &stubPriceSource{price: 50100, healthy: true}
+	rest := &mockMarketDataService{prices: map[string]float64{"BTCUSDT": 50000}}
+
+	manager := NewDataSourceManager(rest, &config.DataSourceConfig{Prefer: "rest"}, &mockLogger{})
+	manager.SetStreamSource(stream)
+	if price, _ := manager.GetCurrentPrice("BTCUSDT"); price != 50000 || stream.calls != 0 {
+		t.Errorf("rest preference should never read the stream, got %v with %d stream calls", price, stream.calls)
+	}
+
+	manager = NewDataSourceManager(rest, nil, &mockLogger{})
+	if price, _ := manager.GetCurrentPrice("BTCUSDT"); price != 50000 {
+		t.Errorf("expected REST price without a stream, got %v", price)
+	}
+	if source := manager.GetActiveSource("BTCUSDT"); source != DataSourceREST {
+		t.Errorf("expected REST without a stream, got %s", source)
+	}
+}
@@ -0,0 +1,654 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/pkg/errors"
+	"binance-trader/pkg/logger"
+	"binance-trader/pkg/timeutil"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultDryRunVolumeParticipation is used when dry_run.volume_participation is not configured
+	DefaultDryRunVolumeParticipation = 0.1
+	// DefaultDryRunBookDepth is used when dry_run.book_depth is not configured
+	DefaultDryRunBookDepth = 100
+	// DefaultDryRunBookRefresh is used when dry_run.book_refresh_ms is not configured
+	DefaultDryRunBookRefresh = time.Second
+	// DefaultDryRunPollInterval is used when dry_run.poll_interval_ms is not configured
+	DefaultDryRunPollInterval = time.Second
+)
+
+// dryRunQtyTolerance absorbs float rounding when comparing filled and ordered quantities
+const dryRunQtyTolerance = 1e-9
+
+// FillModel decides whether a resting limit order fills once the market price reaches its level
+type FillModel interface {
+	// ShouldFill is only called for prices at or through the order's limit price
+	ShouldFill(order *api.Order, price float64) bool
+}
+
+// touchFillModel always fills orders the price trades through; an order whose level is only
+// touched fills with a fixed probability, since its place in the queue at that price is unknown
+type touchFillModel struct {
+	probability float64
+	random      func() float64
+}
+
+// NewTouchFillModel creates the default fill model; random returns values in [0, 1)
+func NewTouchFillModel(probability float64, random func() float64) FillModel {
+	if random == nil {
+		random = rand.Float64
+	}
+	return &touchFillModel{probability: probability, random: random}
+}
+
+// ShouldFill implements FillModel
+func (m *touchFillModel) ShouldFill(order *api.Order, price float64) bool {
+	if price != order.Price {
+		return true
+	}
+	return m.random() < m.probability
+}
+
+// DryRunSimulator is a spot client that simulates order placement against live market data.
+// Market data and account reads go to the wrapped client; orders never reach the exchange.
+// With a starting balance, balances are virtual and move with the simulated fills.
+type DryRunSimulator interface {
+	api.SpotClient
+
+	// PaperAccount returns the virtual account orders settle against, nil when balances are
+	// read from the exchange account
+	PaperAccount() PaperAccount
+
+	// SetFillModel replaces the model deciding whether reached limit orders fill
+	SetFillModel(model FillModel)
+
+	// ObserveTrade feeds a market price and the volume traded since the previous observation;
+	// resting limit orders the price reaches are filled from that volume in placement order
+	ObserveTrade(symbol string, price, volume float64, at time.Time)
+
+	// Poll observes the latest 1m kline of every symbol with open simulated orders
+	Poll() error
+
+	// Price polling
+	StartMonitoring(pollInterval time.Duration) error
+	StopMonitoring() error
+}
+
+// cachedOrderBook is an order book snapshot that simulated market orders consume until it is refreshed
+type cachedOrderBook struct {
+	book      *api.OrderBook
+	fetchedAt time.Time
+}
+
+// dryRunSimulator implements DryRunSimulator
+type dryRunSimulator struct {
+	api.SpotClient
+	fillModel     FillModel
+	participation float64
+	bookDepth     int
+	bookRefresh   time.Duration
+	logger        logger.Logger
+	now           func() time.Time
+
+	mu          sync.Mutex
+	nextOrderID int64
+	nextTradeID int64
+	orders      map[int64]*api.Order
+	trades      map[int64][]*api.Trade
+	books       map[string]*cachedOrderBook
+	lastKlines  map[string]*api.Kline
+
+	// Virtual balances by asset, nil when balances are read from the exchange account
+	balances        map[string]*api.Balance
+	startingBalance float64
+
+	// Monitoring
+	stopChan     chan struct{}
+	monitoringMu sync.Mutex
+	isMonitoring bool
+}
+
+// NewDryRunSimulator wraps a spot client so that orders are simulated instead of sent
+func NewDryRunSimulator(client api.SpotClient, cfg *config.DryRunConfig, log logger.Logger) DryRunSimulator {
+	if cfg == nil {
+		cfg = &config.DryRunConfig{}
+	}
+
+	simulator := &dryRunSimulator{
+		SpotClient:    client,
+		fillModel:     NewTouchFillModel(1, nil),
+		participation: DefaultDryRunVolumeParticipation,
+		bookDepth:     DefaultDryRunBookDepth,
+		bookRefresh:   DefaultDryRunBookRefresh,
+		logger:        log,
+		now:           time.Now,
+		orders:        make(map[int64]*api.Order),
+		trades:        make(map[int64][]*api.Trade),
+		books:         make(map[string]*cachedOrderBook),
+		lastKlines:    make(map[string]*api.Kline),
+	}
+
+	if cfg.FillProbability > 0 {
+		simulator.fillModel = NewTouchFillModel(cfg.FillProbability, nil)
+	}
+	if cfg.VolumeParticipation > 0 {
+		simulator.participation = cfg.VolumeParticipation
+	}
+	if cfg.BookDepth > 0 {
+		simulator.bookDepth = cfg.BookDepth
+	}
+	if cfg.BookRefreshMs > 0 {
+		simulator.bookRefresh = time.Duration(cfg.BookRefreshMs) * time.Millisecond
+	}
+	if cfg.StartingBalance > 0 {
+		simulator.startingBalance = cfg.StartingBalance
+		simulator.balances = map[string]*api.Balance{
+			DefaultPaperBalanceAsset: {Asset: DefaultPaperBalanceAsset, Free: cfg.StartingBalance},
+		}
+	}
+
+	return simulator
+}
+
+// SetFillModel replaces the fill model
+func (s *dryRunSimulator) SetFillModel(model FillModel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fillModel = model
+}
+
+// CreateOrder simulates an order: the part of it the order book can fill executes immediately,
+// the rest of a limit order rests until the market reaches it and the rest of a market order expires
+func (s *dryRunSimulator) CreateOrder(req *api.OrderRequest) (*api.OrderResponse, error) {
+	if req == nil || req.Symbol == "" || req.Quantity <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "symbol and a positive quantity are required", 0, nil)
+	}
+	if req.Type != api.OrderTypeMarket && req.Type != api.OrderTypeLimit {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, fmt.Sprintf("order type %s is not simulated in dry run", req.Type), 0, nil)
+	}
+	if req.Type == api.OrderTypeLimit && req.Price <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "limit orders require a positive price", 0, nil)
+	}
+
+	book, err := s.orderBook(req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.reserveFunds(req, book); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UnixMilli()
+	s.nextOrderID++
+	order := &api.Order{
+		OrderID:       s.nextOrderID,
+		Symbol:        req.Symbol,
+		ClientOrderID: req.NewClientOrderID,
+		Side:          req.Side,
+		Type:          req.Type,
+		Status:        api.OrderStatusNew,
+		Price:         req.Price,
+		OrigQty:       req.Quantity,
+		Time:          now,
+		UpdateTime:    now,
+	}
+	if order.ClientOrderID == "" {
+		order.ClientOrderID = fmt.Sprintf("dryrun-%d", order.OrderID)
+	}
+	s.orders[order.OrderID] = order
+
+	s.fillAgainstBook(order, book, now)
+	if order.Type == api.OrderTypeMarket && order.Status != api.OrderStatusFilled {
+		order.Status = api.OrderStatusExpired
+	}
+
+	s.logger.Info("Dry run order simulated", map[string]interface{}{
+		"order_id":     order.OrderID,
+		"symbol":       order.Symbol,
+		"side":         string(order.Side),
+		"type":         string(order.Type),
+		"status":       string(order.Status),
+		"quantity":     order.OrigQty,
+		"executed_qty": order.ExecutedQty,
+		"quote_qty":    order.CummulativeQuoteQty,
+	})
+
+	return &api.OrderResponse{
+		OrderID:             order.OrderID,
+		Symbol:              order.Symbol,
+		ClientOrderID:       order.ClientOrderID,
+		Status:              order.Status,
+		Price:               order.Price,
+		OrigQty:             order.OrigQty,
+		ExecutedQty:         order.ExecutedQty,
+		CummulativeQuoteQty: order.CummulativeQuoteQty,
+		TransactTime:        now,
+	}, nil
+}
+
+// CancelOrder cancels an open simulated order
+func (s *dryRunSimulator) CancelOrder(symbol string, orderID int64) (*api.CancelResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, err := s.findOrder(symbol, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !isOpenStatus(order.Status) {
+		return nil, errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			fmt.Sprintf("order %d is already %s", orderID, order.Status),
+			0,
+			nil,
+		)
+	}
+
+	s.releaseFunds(order)
+	order.Status = api.OrderStatusCanceled
+	order.UpdateTime = s.now().UnixMilli()
+
+	return &api.CancelResponse{
+		Symbol:            order.Symbol,
+		OrderID:           order.OrderID,
+		OrigClientOrderID: order.ClientOrderID,
+		Status:            order.Status,
+	}, nil
+}
+
+// BulkCancelOrders cancels every open simulated order of a symbol
+func (s *dryRunSimulator) BulkCancelOrders(symbol string) ([]api.CancelResponse, error) {
+	if symbol == "" {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "symbol cannot be empty", 0, nil)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var cancelled []api.CancelResponse
+	now := s.now().UnixMilli()
+	for _, order := range s.sortedOrders(symbol) {
+		if !isOpenStatus(order.Status) {
+			continue
+		}
+		s.releaseFunds(order)
+		order.Status = api.OrderStatusCanceled
+		order.UpdateTime = now
+		cancelled = append(cancelled, api.CancelResponse{
+			Symbol:            order.Symbol,
+			OrderID:           order.OrderID,
+			OrigClientOrderID: order.ClientOrderID,
+			Status:            order.Status,
+		})
+	}
+	return cancelled, nil
+}
+
+// GetOrder returns a simulated order
+func (s *dryRunSimulator) GetOrder(symbol string, orderID int64) (*api.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, err := s.findOrder(symbol, orderID)
+	if err != nil {
+		return nil, err
+	}
+	orderCopy := *order
+	return &orderCopy, nil
+}
+
+// GetOpenOrders returns the open simulated orders of a symbol, or of all symbols for ""
+func (s *dryRunSimulator) GetOpenOrders(symbol string) ([]*api.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var orders []*api.Order
+	for _, order := range s.sortedOrders(symbol) {
+		if isOpenStatus(order.Status) {
+			orderCopy := *order
+			orders = append(orders, &orderCopy)
+		}
+	}
+	return orders, nil
+}
+
+// GetHistoricalOrders returns the simulated orders of a symbol placed within the time range,
+// given in Unix milliseconds like the exchange
+func (s *dryRunSimulator) GetHistoricalOrders(symbol string, startTime, endTime int64) ([]*api.Order, error) {
+	if err := timeutil.ValidateMillis("start time", startTime); err != nil {
+		return nil, err
+	}
+	if err := timeutil.ValidateMillis("end time", endTime); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var orders []*api.Order
+	for _, order := range s.sortedOrders(symbol) {
+		if order.Time >= startTime && order.Time <= endTime {
+			orderCopy := *order
+			orders = append(orders, &orderCopy)
+		}
+	}
+	return orders, nil
+}
+
+// GetMyTrades returns the simulated fills of an order, or of every order of the symbol for orderID 0
+func (s *dryRunSimulator) GetMyTrades(symbol string, orderID int64) ([]*api.Trade, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var trades []*api.Trade
+	for _, order := range s.sortedOrders(symbol) {
+		if orderID != 0 && order.OrderID != orderID {
+			continue
+		}
+		for _, trade := range s.trades[order.OrderID] {
+			tradeCopy := *trade
+			trades = append(trades, &tradeCopy)
+		}
+	}
+	return trades, nil
+}
+
+// CreateOCOOrder is rejected in dry run: order lists are not simulated
+func (s *dryRunSimulator) CreateOCOOrder(order *api.OCORequest) (*api.OCOResponse, error) {
+	return nil, errors.NewTradingError(errors.ErrInvalidParameter, "order lists are not simulated in dry run", 0, nil)
+}
+
+// CancelOrderList is rejected in dry run: simulated orders never belong to an order list
+func (s *dryRunSimulator) CancelOrderList(symbol string, orderListID int64) (*api.OrderList, error) {
+	return nil, errors.NewTradingError(errors.ErrInvalidParameter, "order lists are not simulated in dry run", 0, nil)
+}
+
+// ConvertDust is a write without a meaningful simulation, so it is rejected in dry run
+func (s *dryRunSimulator) ConvertDust(assets []string) (*api.DustConversionResult, error) {
+	return nil, errors.NewTradingError(errors.ErrInvalidParameter, "dust conversion is not simulated in dry run", 0, nil)
+}
+
+// ObserveTrade fills resting limit orders the observed price reaches, sharing the volume in placement order
+func (s *dryRunSimulator) ObserveTrade(symbol string, price, volume float64, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	available := volume * s.participation
+	for _, order := range s.sortedOrders(symbol) {
+		if available <= dryRunQtyTolerance {
+			return
+		}
+		if order.Type != api.OrderTypeLimit || !isOpenStatus(order.Status) || !limitReached(order, price) {
+			continue
+		}
+		if !s.fillModel.ShouldFill(order, price) {
+			continue
+		}
+
+		qty := math.Min(order.OrigQty-order.ExecutedQty, available)
+		available -= qty
+		s.fill(order, order.Price, qty, true, at.UnixMilli())
+
+		s.logger.Info("Dry run limit order filled", map[string]interface{}{
+			"order_id":     order.OrderID,
+			"symbol":       order.Symbol,
+			"side":         string(order.Side),
+			"status":       string(order.Status),
+			"fill_qty":     qty,
+			"executed_qty": order.ExecutedQty,
+			"price":        order.Price,
+			"market_price": price,
+		})
+	}
+}
+
+// Poll observes every symbol with open simulated orders. The traded volume is the growth of the
+// current 1m kline since the previous poll; a symbol's first poll only records the baseline.
+func (s *dryRunSimulator) Poll() error {
+	s.mu.Lock()
+	symbols := make(map[string]bool)
+	for _, order := range s.orders {
+		if order.Type == api.OrderTypeLimit && isOpenStatus(order.Status) {
+			symbols[order.Symbol] = true
+		}
+	}
+	for symbol := range s.lastKlines {
+		if !symbols[symbol] {
+			delete(s.lastKlines, symbol)
+		}
+	}
+	s.mu.Unlock()
+
+	var firstErr error
+	for symbol := range symbols {
+		klines, err := s.SpotClient.GetKlines(symbol, "1m", 1)
+		if err != nil {
+			s.logger.Warn("Dry run price poll failed", map[string]interface{}{
+				"symbol": symbol,
+				"error":  err.Error(),
+			})
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if len(klines) == 0 {
+			continue
+		}
+		kline := klines[len(klines)-1]
+
+		s.mu.Lock()
+		last, seen := s.lastKlines[symbol]
+		s.lastKlines[symbol] = kline
+		s.mu.Unlock()
+
+		volume := 0.0
+		if seen {
+			volume = kline.Volume
+			if last.OpenTime == kline.OpenTime {
+				volume -= last.Volume
+			}
+		}
+		s.ObserveTrade(symbol, kline.Close, math.Max(volume, 0), s.now())
+	}
+
+	return firstErr
+}
+
+// StartMonitoring starts polling market prices for resting simulated orders
+func (s *dryRunSimulator) StartMonitoring(pollInterval time.Duration) error {
+	s.monitoringMu.Lock()
+	defer s.monitoringMu.Unlock()
+
+	if s.isMonitoring {
+		return fmt.Errorf("monitoring is already running")
+	}
+
+	if pollInterval <= 0 {
+		pollInterval = DefaultDryRunPollInterval
+	}
+
+	s.stopChan = make(chan struct{})
+	s.isMonitoring = true
+
+	go s.monitoringLoop(pollInterval)
+
+	s.logger.Info("Started dry run order simulation", map[string]interface{}{
+		"poll_interval":        pollInterval.String(),
+		"volume_participation": s.participation,
+		"book_depth":           s.bookDepth,
+	})
+
+	return nil
+}
+
+// StopMonitoring stops polling market prices
+func (s *dryRunSimulator) StopMonitoring() error {
+	s.monitoringMu.Lock()
+	defer s.monitoringMu.Unlock()
+
+	if !s.isMonitoring {
+		return fmt.Errorf("monitoring is not running")
+	}
+
+	close(s.stopChan)
+	s.isMonitoring = false
+
+	s.logger.Info("Stopped dry run order simulation", nil)
+
+	return nil
+}
+
+// monitoringLoop polls on every tick; failures are logged by Poll and retried on the next tick
+func (s *dryRunSimulator) monitoringLoop(pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			s.Poll()
+		}
+	}
+}
+
+// orderBook returns the cached order book of a symbol, fetching a fresh copy once it is stale
+func (s *dryRunSimulator) orderBook(symbol string) (*api.OrderBook, error) {
+	s.mu.Lock()
+	cached, exists := s.books[symbol]
+	s.mu.Unlock()
+	if exists && s.now().Sub(cached.fetchedAt) < s.bookRefresh {
+		return cached.book, nil
+	}
+
+	book, err := s.SpotClient.GetOrderBook(symbol, s.bookDepth)
+	if err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation": "dry_run_order_book",
+			"symbol":    symbol,
+		})
+		return nil, err
+	}
+
+	// Simulated fills consume the levels, so keep a private copy
+	bookCopy := &api.OrderBook{
+		Symbol:       book.Symbol,
+		LastUpdateID: book.LastUpdateID,
+		Bids:         append([]api.OrderBookLevel(nil), book.Bids...),
+		Asks:         append([]api.OrderBookLevel(nil), book.Asks...),
+	}
+
+	s.mu.Lock()
+	s.books[symbol] = &cachedOrderBook{book: bookCopy, fetchedAt: s.now()}
+	s.mu.Unlock()
+
+	return bookCopy, nil
+}
+
+// fillAgainstBook takes liquidity level by level from the opposite side of the book, up to the
+// limit price for limit orders; consumed quantity stays consumed until the book is refreshed
+func (s *dryRunSimulator) fillAgainstBook(order *api.Order, book *api.OrderBook, now int64) {
+	levels := book.Asks
+	if order.Side == api.OrderSideSell {
+		levels = book.Bids
+	}
+
+	for i := range levels {
+		remaining := order.OrigQty - order.ExecutedQty
+		if remaining <= dryRunQtyTolerance {
+			return
+		}
+		level := &levels[i]
+		if order.Type == api.OrderTypeLimit && !limitReached(order, level.Price) {
+			return
+		}
+		if level.Qty <= 0 {
+			continue
+		}
+
+		qty := math.Min(remaining, level.Qty)
+		level.Qty -= qty
+		s.fill(order, level.Price, qty, false, now)
+	}
+}
+
+// fill records a fill and moves the order to PARTIALLY_FILLED or FILLED
+func (s *dryRunSimulator) fill(order *api.Order, price, qty float64, maker bool, now int64) {
+	s.settleFill(order, price, qty)
+	order.ExecutedQty += qty
+	order.CummulativeQuoteQty += price * qty
+	order.UpdateTime = now
+	order.Status = api.OrderStatusPartiallyFilled
+	if order.ExecutedQty >= order.OrigQty-dryRunQtyTolerance {
+		order.ExecutedQty = order.OrigQty
+		order.Status = api.OrderStatusFilled
+	}
+
+	s.nextTradeID++
+	s.trades[order.OrderID] = append(s.trades[order.OrderID], &api.Trade{
+		ID:       s.nextTradeID,
+		Symbol:   order.Symbol,
+		OrderID:  order.OrderID,
+		Price:    price,
+		Qty:      qty,
+		QuoteQty: price * qty,
+		Time:     now,
+		IsBuyer:  order.Side == api.OrderSideBuy,
+		IsMaker:  maker,
+	})
+}
+
+// findOrder looks up a simulated order of a symbol
+func (s *dryRunSimulator) findOrder(symbol string, orderID int64) (*api.Order, error) {
+	order, exists := s.orders[orderID]
+	if !exists || order.Symbol != symbol {
+		return nil, errors.NewTradingError(
+			errors.ErrOrderNotFound,
+			fmt.Sprintf("order %d not found for %s", orderID, symbol),
+			0,
+			nil,
+		)
+	}
+	return order, nil
+}
+
+// sortedOrders returns the simulated orders of a symbol (all symbols for "") in placement order
+func (s *dryRunSimulator) sortedOrders(symbol string) []*api.Order {
+	orders := make([]*api.Order, 0, len(s.orders))
+	for _, order := range s.orders {
+		if symbol == "" || order.Symbol == symbol {
+			orders = append(orders, order)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].OrderID < orders[j].OrderID
+	})
+	return orders
+}
+
+// limitReached reports whether a price is at or better than the order's limit
+func limitReached(order *api.Order, price float64) bool {
+	if order.Side == api.OrderSideSell {
+		return price >= order.Price
+	}
+	return price <= order.Price
+}
+
+// isOpenStatus reports whether an order can still fill
+func isOpenStatus(status api.OrderStatus) bool {
+	return status == api.OrderStatusNew || status == api.OrderStatusPartiallyFilled
+}
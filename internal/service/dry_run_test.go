@@ -0,0 +1,291 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/pkg/errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// dryRunBook is a fresh SOLUSDT book with 3.5 SOL of asks between 100 and 102
+func dryRunBook() *api.OrderBook {
+	return &api.OrderBook{
+		Symbol: "SOLUSDT",
+		Bids: []api.OrderBookLevel{
+			{Price: 99.9, Qty: 1},
+			{Price: 99.5, Qty: 2},
+		},
+		Asks: []api.OrderBookLevel{
+			{Price: 100, Qty: 0.5},
+			{Price: 101, Qty: 1},
+			{Price: 102, Qty: 2},
+		},
+	}
+}
+
+// newTestDryRunSimulator creates a simulator over dryRunBook with an injectable clock
+func newTestDryRunSimulator(cfg *config.DryRunConfig, client *mockBinanceClient) (*dryRunSimulator, *time.Time) {
+	if client.getOrderBookFunc == nil {
+		client.getOrderBookFunc = func(symbol string, limit int) (*api.OrderBook, error) {
+			return dryRunBook(), nil
+		}
+	}
+	simulator := NewDryRunSimulator(client, cfg, &mockLogger{}).(*dryRunSimulator)
+
+	current := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	simulator.now = func() time.Time { return current }
+	return simulator, &current
+}
+
+func assertDryRunOrder(t *testing.T, simulator DryRunSimulator, orderID int64, status api.OrderStatus, executedQty float64) *api.Order {
+	t.Helper()
+	order, err := simulator.GetOrder("SOLUSDT", orderID)
+	if err != nil {
+		t.Fatalf("GetOrder(%d) unexpected error: %v", orderID, err)
+	}
+	if order.Status != status || math.Abs(order.ExecutedQty-executedQty) > 1e-9 {
+		t.Fatalf("order %d: expected %s with %v executed, got %s with %v", orderID, status, executedQty, order.Status, order.ExecutedQty)
+	}
+	return order
+}
+
+func TestDryRunSimulator_LimitOrderReplay(t *testing.T) {
+	simulator, current := newTestDryRunSimulator(&config.DryRunConfig{VolumeParticipation: 0.1}, &mockBinanceClient{})
+
+	resp, err := simulator.CreateOrder(&api.OrderRequest{Symbol: "SOLUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeLimit, Quantity: 1, Price: 99})
+	if err != nil {
+		t.Fatalf("CreateOrder() unexpected error: %v", err)
+	}
+	if resp.Status != api.OrderStatusNew || resp.ExecutedQty != 0 {
+		t.Fatalf("a limit order below the ask should rest, got %+v", resp)
+	}
+
+	// Replay a price path; each step is one second and reports the volume traded since the previous one
+	path := []struct {
+		price  float64
+		volume float64
+		status api.OrderStatus
+		filled float64
+	}{
+		{price: 100.5, volume: 50, status: api.OrderStatusNew, filled: 0},              // above the limit
+		{price: 98.8, volume: 4, status: api.OrderStatusPartiallyFilled, filled: 0.4},  // 10% of 4
+		{price: 99.6, volume: 30, status: api.OrderStatusPartiallyFilled, filled: 0.4}, // bounced above the limit
+		{price: 98.5, volume: 20, status: api.OrderStatusFilled, filled: 1},            // 2 available, 0.6 remaining
+		{price: 97, volume: 50, status: api.OrderStatusFilled, filled: 1},
+	}
+	var fillTimes []int64
+	for i, step := range path {
+		*current = current.Add(time.Second)
+		simulator.ObserveTrade("SOLUSDT", step.price, step.volume, *current)
+
+		order := assertDryRunOrder(t, simulator, resp.OrderID, step.status, step.filled)
+		if step.filled > 0 && (len(fillTimes) == 0 || order.ExecutedQty > path[i-1].filled) {
+			fillTimes = append(fillTimes, order.UpdateTime)
+		}
+	}
+
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	if len(fillTimes) != 2 || fillTimes[0] != start.Add(2*time.Second).UnixMilli() || fillTimes[1] != start.Add(4*time.Second).UnixMilli() {
+		t.Errorf("expected fills at the 2nd and 4th step, got %v", fillTimes)
+	}
+
+	// Resting orders fill at their limit price as maker
+	trades, _ := simulator.GetMyTrades("SOLUSDT", resp.OrderID)
+	if len(trades) != 2 || trades[0].Qty != 0.4 || math.Abs(trades[1].Qty-0.6) > 1e-9 {
+		t.Fatalf("unexpected fills: %+v", trades)
+	}
+	for _, trade := range trades {
+		if trade.Price != 99 || !trade.IsMaker || !trade.IsBuyer {
+			t.Errorf("unexpected fill: %+v", trade)
+		}
+	}
+	order, _ := simulator.GetOrder("SOLUSDT", resp.OrderID)
+	if math.Abs(order.CummulativeQuoteQty-99) > 1e-9 {
+		t.Errorf("expected quote quantity 99, got %v", order.CummulativeQuoteQty)
+	}
+	if open, _ := simulator.GetOpenOrders(""); len(open) != 0 {
+		t.Errorf("expected no open orders, got %d", len(open))
+	}
+}
+
+func TestDryRunSimulator_VolumeSharedInPlacementOrder(t *testing.T) {
+	simulator, current := newTestDryRunSimulator(&config.DryRunConfig{VolumeParticipation: 0.5}, &mockBinanceClient{})
+
+	first, _ := simulator.CreateOrder(&api.OrderRequest{Symbol: "SOLUSDT", Side: api.OrderSideSell, Type: api.OrderTypeLimit, Quantity: 1, Price: 105})
+	second, _ := simulator.CreateOrder(&api.OrderRequest{Symbol: "SOLUSDT", Side: api.OrderSideSell, Type: api.OrderTypeLimit, Quantity: 1, Price: 104})
+
+	// 1.5 of the 3 traded is available: the older order fills first even though its price is worse
+	*current = current.Add(time.Second)
+	simulator.ObserveTrade("SOLUSDT", 106, 3, *current)
+	assertDryRunOrder(t, simulator, first.OrderID, api.OrderStatusFilled, 1)
+	assertDryRunOrder(t, simulator, second.OrderID, api.OrderStatusPartiallyFilled, 0.5)
+
+	// Cancelling keeps the partial fill
+	cancel, err := simulator.CancelOrder("SOLUSDT", second.OrderID)
+	if err != nil || cancel.Status != api.OrderStatusCanceled {
+		t.Fatalf("CancelOrder() = %+v, %v", cancel, err)
+	}
+	simulator.ObserveTrade("SOLUSDT", 106, 10, *current)
+	assertDryRunOrder(t, simulator, second.OrderID, api.OrderStatusCanceled, 0.5)
+
+	if _, err := simulator.CancelOrder("SOLUSDT", second.OrderID); err == nil {
+		t.Error("cancelling a canceled order should fail")
+	}
+
+	// Bulk cancel only touches the open orders of its symbol
+	third, _ := simulator.CreateOrder(&api.OrderRequest{Symbol: "SOLUSDT", Side: api.OrderSideSell, Type: api.OrderTypeLimit, Quantity: 1, Price: 110})
+	other, _ := simulator.CreateOrder(&api.OrderRequest{Symbol: "ETHUSDT", Side: api.OrderSideSell, Type: api.OrderTypeLimit, Quantity: 1, Price: 4000})
+	cancelled, err := simulator.BulkCancelOrders("SOLUSDT")
+	if err != nil || len(cancelled) != 1 || cancelled[0].OrderID != third.OrderID {
+		t.Fatalf("BulkCancelOrders() = %+v, %v; want only order %d", cancelled, err, third.OrderID)
+	}
+	if order, _ := simulator.GetOrder("ETHUSDT", other.OrderID); order.Status != api.OrderStatusNew {
+		t.Errorf("ETHUSDT order = %s, want NEW", order.Status)
+	}
+	_, err = simulator.GetOrder("SOLUSDT", 999)
+	if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrOrderNotFound {
+		t.Errorf("expected order not found, got %v", err)
+	}
+}
+
+func TestDryRunSimulator_FillModel(t *testing.T) {
+	simulator, current := newTestDryRunSimulator(&config.DryRunConfig{}, &mockBinanceClient{})
+	roll := 0.7
+	simulator.SetFillModel(NewTouchFillModel(0.5, func() float64 { return roll }))
+
+	resp, _ := simulator.CreateOrder(&api.OrderRequest{Symbol: "SOLUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeLimit, Quantity: 1, Price: 99})
+
+	// Touching the level with an unlucky queue position does not fill
+	*current = current.Add(time.Second)
+	simulator.ObserveTrade("SOLUSDT", 99, 100, *current)
+	assertDryRunOrder(t, simulator, resp.OrderID, api.OrderStatusNew, 0)
+
+	roll = 0.3
+	simulator.ObserveTrade("SOLUSDT", 99, 5, *current)
+	assertDryRunOrder(t, simulator, resp.OrderID, api.OrderStatusPartiallyFilled, 0.5)
+
+	// Trading through the level always fills
+	roll = 0.9
+	simulator.ObserveTrade("SOLUSDT", 98.9, 5, *current)
+	assertDryRunOrder(t, simulator, resp.OrderID, api.OrderStatusFilled, 1)
+}
+
+func TestDryRunSimulator_MarketOrderSlippage(t *testing.T) {
+	bookFetches := 0
+	client := &mockBinanceClient{
+		getOrderBookFunc: func(symbol string, limit int) (*api.OrderBook, error) {
+			bookFetches++
+			return dryRunBook(), nil
+		},
+	}
+	simulator, current := newTestDryRunSimulator(&config.DryRunConfig{BookRefreshMs: 5000}, client)
+
+	// 0.5 @ 100 + 0.5 @ 101
+	resp, err := simulator.CreateOrder(&api.OrderRequest{Symbol: "SOLUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeMarket, Quantity: 1})
+	if err != nil {
+		t.Fatalf("CreateOrder() unexpected error: %v", err)
+	}
+	if resp.Status != api.OrderStatusFilled || resp.CummulativeQuoteQty != 100.5 {
+		t.Fatalf("expected a fill averaging 100.5, got %+v", resp)
+	}
+	trades, _ := simulator.GetMyTrades("SOLUSDT", resp.OrderID)
+	if len(trades) != 2 || trades[0].Price != 100 || trades[1].Price != 101 || trades[0].IsMaker {
+		t.Errorf("expected taker fills at 100 and 101, got %+v", trades)
+	}
+
+	// The cached book remembers the consumed liquidity: 0.5 @ 101 + 0.5 @ 102
+	resp, _ = simulator.CreateOrder(&api.OrderRequest{Symbol: "SOLUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeMarket, Quantity: 1})
+	if resp.CummulativeQuoteQty != 101.5 || bookFetches != 1 {
+		t.Errorf("expected a fill averaging 101.5 from the cached book, got %+v after %d fetches", resp, bookFetches)
+	}
+
+	// More than the remaining depth: the rest of a market order expires
+	resp, _ = simulator.CreateOrder(&api.OrderRequest{Symbol: "SOLUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeMarket, Quantity: 2})
+	if resp.Status != api.OrderStatusExpired || resp.ExecutedQty != 1.5 {
+		t.Errorf("expected 1.5 filled and the rest expired, got %+v", resp)
+	}
+
+	// A stale book is fetched again
+	*current = current.Add(6 * time.Second)
+	resp, _ = simulator.CreateOrder(&api.OrderRequest{Symbol: "SOLUSDT", Side: api.OrderSideSell, Type: api.OrderTypeMarket, Quantity: 2})
+	if bookFetches != 2 || resp.Status != api.OrderStatusFilled || math.Abs(resp.CummulativeQuoteQty-(99.9+99.5)) > 1e-9 {
+		t.Errorf("expected a sell into the refreshed bids, got %+v after %d fetches", resp, bookFetches)
+	}
+}
+
+func TestDryRunSimulator_MarketableLimitOrder(t *testing.T) {
+	simulator, current := newTestDryRunSimulator(&config.DryRunConfig{VolumeParticipation: 1}, &mockBinanceClient{})
+
+	// Takes the 0.5 ask at 100, the ask at 101 is above the limit so the rest rests at 100.5
+	resp, _ := simulator.CreateOrder(&api.OrderRequest{Symbol: "SOLUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeLimit, Quantity: 2, Price: 100.5})
+	if resp.Status != api.OrderStatusPartiallyFilled || resp.ExecutedQty != 0.5 || resp.CummulativeQuoteQty != 50 {
+		t.Fatalf("expected 0.5 filled at 100, got %+v", resp)
+	}
+	open, _ := simulator.GetOpenOrders("SOLUSDT")
+	if len(open) != 1 || open[0].OrderID != resp.OrderID {
+		t.Fatalf("expected the order to stay open, got %+v", open)
+	}
+
+	*current = current.Add(time.Second)
+	simulator.ObserveTrade("SOLUSDT", 100.2, 3, *current)
+	order := assertDryRunOrder(t, simulator, resp.OrderID, api.OrderStatusFilled, 2)
+	if order.CummulativeQuoteQty != 50+1.5*100.5 {
+		t.Errorf("unexpected quote quantity %v", order.CummulativeQuoteQty)
+	}
+}
+
+func TestDryRunSimulator_PollUsesKlineVolume(t *testing.T) {
+	minute := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC).UnixMilli()
+	klines := []*api.Kline{
+		{OpenTime: minute, Close: 100.4, Volume: 40},       // baseline
+		{OpenTime: minute, Close: 98.9, Volume: 45},        // 5 traded since the previous poll
+		{OpenTime: minute + 60000, Close: 98.7, Volume: 8}, // new minute: 8 traded
+		{OpenTime: minute + 60000, Close: 98.7, Volume: 8}, // nothing traded
+	}
+	polls := 0
+	client := &mockBinanceClient{
+		getKlinesFunc: func(symbol string, interval string, limit int) ([]*api.Kline, error) {
+			kline := klines[polls]
+			polls++
+			return []*api.Kline{kline}, nil
+		},
+	}
+	simulator, _ := newTestDryRunSimulator(&config.DryRunConfig{VolumeParticipation: 0.1}, client)
+
+	if err := simulator.Poll(); err != nil || polls != 0 {
+		t.Fatalf("nothing should be polled without open orders, got %v after %d polls", err, polls)
+	}
+
+	resp, _ := simulator.CreateOrder(&api.OrderRequest{Symbol: "SOLUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeLimit, Quantity: 1, Price: 99})
+	expected := []float64{0, 0.5, 1.3, 1.3}
+	for i := range klines {
+		if err := simulator.Poll(); err != nil {
+			t.Fatalf("Poll() unexpected error: %v", err)
+		}
+		order, _ := simulator.GetOrder("SOLUSDT", resp.OrderID)
+		if math.Abs(order.ExecutedQty-math.Min(expected[i], 1)) > 1e-9 {
+			t.Errorf("poll %d: expected %v executed, got %v", i, math.Min(expected[i], 1), order.ExecutedQty)
+		}
+	}
+	assertDryRunOrder(t, simulator, resp.OrderID, api.OrderStatusFilled, 1)
+}
+
+func TestDryRunSimulator_ReadsPassThroughAndDustRejected(t *testing.T) {
+	simulator, _ := newTestDryRunSimulator(nil, &mockBinanceClient{})
+
+	price, err := simulator.GetPrice("BTCUSDT")
+	if err != nil || price.Price != 50000 {
+		t.Errorf("GetPrice() should use the wrapped client, got %+v, %v", price, err)
+	}
+	if _, err := simulator.ConvertDust(nil); err == nil {
+		t.Error("dust conversion should be rejected in dry run")
+	}
+	if _, err := simulator.CancelOrderList("BTCUSDT", 7); err == nil {
+		t.Error("order list cancellation should be rejected in dry run")
+	}
+	if _, err := simulator.CreateOrder(&api.OrderRequest{Symbol: "SOLUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeLimit, Quantity: 1}); err == nil {
+		t.Error("a limit order without price should be rejected")
+	}
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/pkg/errors"
+	"binance-trader/pkg/logger"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDustCheckInterval is used when the dust schedule is started without an interval
+const DefaultDustCheckInterval = 6 * time.Hour
+
+// DustConverter converts small spot balances to BNB
+type DustConverter interface {
+	// FindDust returns the convertible balances at or below the configured threshold
+	FindDust() ([]*api.DustAsset, error)
+
+	// ConvertDust converts the given assets to BNB; without assets it converts everything FindDust returns
+	ConvertDust(assets []string) (*api.DustConversionResult, error)
+
+	// Scheduled conversion
+	StartMonitoring(checkInterval time.Duration) error
+	StopMonitoring() error
+}
+
+// dustConverter implements DustConverter
+type dustConverter struct {
+	client       api.SpotClient
+	thresholdBNB float64
+	exclude      map[string]bool
+	logger       logger.Logger
+
+	// Monitoring
+	stopChan     chan struct{}
+	monitoringMu sync.Mutex
+	isMonitoring bool
+}
+
+// NewDustConverter creates a dust converter
+func NewDustConverter(client api.SpotClient, cfg *config.DustConfig, log logger.Logger) DustConverter {
+	if cfg == nil {
+		cfg = &config.DustConfig{}
+	}
+
+	exclude := make(map[string]bool, len(cfg.Exclude))
+	for _, asset := range cfg.Exclude {
+		exclude[strings.ToUpper(asset)] = true
+	}
+
+	return &dustConverter{
+		client:       client,
+		thresholdBNB: cfg.ThresholdBNB,
+		exclude:      exclude,
+		logger:       log,
+	}
+}
+
+// selectDust returns the eligible assets worth at most thresholdBNB (0 = any value) that are
+// not excluded, ordered by asset
+func selectDust(eligible []*api.DustAsset, thresholdBNB float64, exclude map[string]bool) []*api.DustAsset {
+	var selected []*api.DustAsset
+	for _, asset := range eligible {
+		if asset.AmountFree <= 0 || exclude[asset.Asset] {
+			continue
+		}
+		if thresholdBNB > 0 && asset.ToBNB > thresholdBNB {
+			continue
+		}
+		selected = append(selected, asset)
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Asset < selected[j].Asset
+	})
+	return selected
+}
+
+// FindDust returns the convertible balances at or below the configured threshold
+func (d *dustConverter) FindDust() ([]*api.DustAsset, error) {
+	eligibility, err := d.client.GetDustAssets()
+	if err != nil {
+		return nil, errors.NewTradingError(errors.ErrNetwork, "failed to get convertible dust balances", 0, err)
+	}
+
+	return selectDust(eligibility.Details, d.thresholdBNB, d.exclude), nil
+}
+
+// ConvertDust converts the given assets to BNB. Named assets must be convertible, but are
+// converted regardless of the threshold and exclusions.
+func (d *dustConverter) ConvertDust(assets []string) (*api.DustConversionResult, error) {
+	eligibility, err := d.client.GetDustAssets()
+	if err != nil {
+		return nil, errors.NewTradingError(errors.ErrNetwork, "failed to get convertible dust balances", 0, err)
+	}
+
+	var names []string
+	if len(assets) == 0 {
+		for _, asset := range selectDust(eligibility.Details, d.thresholdBNB, d.exclude) {
+			names = append(names, asset.Asset)
+		}
+		if len(names) == 0 {
+			return &api.DustConversionResult{}, nil
+		}
+	} else {
+		convertible := make(map[string]bool, len(eligibility.Details))
+		for _, asset := range eligibility.Details {
+			convertible[asset.Asset] = asset.AmountFree > 0
+		}
+
+		var ineligible []string
+		for _, asset := range assets {
+			asset = strings.ToUpper(asset)
+			if !convertible[asset] {
+				ineligible = append(ineligible, asset)
+				continue
+			}
+			names = append(names, asset)
+		}
+		if len(ineligible) > 0 {
+			return nil, errors.NewTradingError(
+				errors.ErrInvalidParameter,
+				fmt.Sprintf("not convertible to BNB: %s", strings.Join(ineligible, ", ")),
+				0,
+				nil,
+			)
+		}
+	}
+
+	result, err := d.client.ConvertDust(names)
+	if err != nil {
+		d.logger.Error("Failed to convert dust to BNB", map[string]interface{}{
+			"assets": strings.Join(names, ","),
+			"error":  err.Error(),
+		})
+		return nil, errors.NewTradingError(errors.ErrNetwork, "failed to convert dust to BNB", 0, err)
+	}
+
+	d.logger.Info("Converted dust to BNB", map[string]interface{}{
+		"assets":         strings.Join(names, ","),
+		"bnb_received":   result.TotalTransferred,
+		"service_charge": result.TotalServiceCharge,
+	})
+
+	return result, nil
+}
+
+// StartMonitoring starts converting dust on a schedule
+func (d *dustConverter) StartMonitoring(checkInterval time.Duration) error {
+	d.monitoringMu.Lock()
+	defer d.monitoringMu.Unlock()
+
+	if d.isMonitoring {
+		return fmt.Errorf("monitoring is already running")
+	}
+
+	if checkInterval <= 0 {
+		checkInterval = DefaultDustCheckInterval
+	}
+
+	d.stopChan = make(chan struct{})
+	d.isMonitoring = true
+
+	go d.monitoringLoop(checkInterval)
+
+	d.logger.Info("Started scheduled dust conversion", map[string]interface{}{
+		"check_interval": checkInterval.String(),
+		"threshold_bnb":  d.thresholdBNB,
+	})
+
+	return nil
+}
+
+// StopMonitoring stops the scheduled dust conversion
+func (d *dustConverter) StopMonitoring() error {
+	d.monitoringMu.Lock()
+	defer d.monitoringMu.Unlock()
+
+	if !d.isMonitoring {
+		return fmt.Errorf("monitoring is not running")
+	}
+
+	close(d.stopChan)
+	d.isMonitoring = false
+
+	d.logger.Info("Stopped scheduled dust conversion", nil)
+
+	return nil
+}
+
+// monitoringLoop converts dust on every tick; failures are logged and retried on the next tick
+func (d *dustConverter) monitoringLoop(checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.stopChan:
+			return
+		case <-ticker.C:
+			d.ConvertDust(nil)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/pkg/errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// dustEligibility is a dust-btc response with assets on both sides of a 0.001 BNB threshold
+func dustEligibility() *api.DustEligibility {
+	return &api.DustEligibility{
+		Details: []*api.DustAsset{
+			{Asset: "SHIB", AmountFree: 12000, ToBNB: 0.0004},
+			{Asset: "ADA", AmountFree: 0.5, ToBNB: 0.0009},
+			{Asset: "DOGE", AmountFree: 3, ToBNB: 0.001},
+			{Asset: "ETH", AmountFree: 0.01, ToBNB: 0.06},
+			{Asset: "XRP", AmountFree: 0, ToBNB: 0},
+		},
+		TotalTransferBNB:   0.0623,
+		DribbletPercentage: 0.02,
+	}
+}
+
+func dustAssetNames(assets []*api.DustAsset) []string {
+	var names []string
+	for _, asset := range assets {
+		names = append(names, asset.Asset)
+	}
+	return names
+}
+
+func TestSelectDust(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold float64
+		exclude   map[string]bool
+		want      []string
+	}{
+		{"at or below threshold", 0.001, nil, []string{"ADA", "DOGE", "SHIB"}},
+		{"excluded assets are skipped", 0.001, map[string]bool{"ADA": true}, []string{"DOGE", "SHIB"}},
+		{"tighter threshold", 0.0005, nil, []string{"SHIB"}},
+		{"no threshold selects every convertible balance", 0, nil, []string{"ADA", "DOGE", "ETH", "SHIB"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dustAssetNames(selectDust(dustEligibility().Details, tt.threshold, tt.exclude))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectDust() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDustConverter_ConvertsSelectedDust(t *testing.T) {
+	var converted []string
+	client := &mockBinanceClient{
+		getDustAssetsFunc: func() (*api.DustEligibility, error) {
+			return dustEligibility(), nil
+		},
+		convertDustFunc: func(assets []string) (*api.DustConversionResult, error) {
+			converted = assets
+			return &api.DustConversionResult{TotalTransferred: 0.0022}, nil
+		},
+	}
+
+	converter := NewDustConverter(client, &config.DustConfig{ThresholdBNB: 0.001, Exclude: []string{"doge"}}, &mockLogger{})
+
+	result, err := converter.ConvertDust(nil)
+	if err != nil {
+		t.Fatalf("ConvertDust() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(converted, []string{"ADA", "SHIB"}) {
+		t.Errorf("expected dust below the threshold without excluded assets, got %v", converted)
+	}
+	if result.TotalTransferred != 0.0022 {
+		t.Errorf("expected BNB received to be reported, got %v", result.TotalTransferred)
+	}
+}
+
+func TestDustConverter_NothingToConvert(t *testing.T) {
+	client := &mockBinanceClient{
+		getDustAssetsFunc: func() (*api.DustEligibility, error) {
+			return dustEligibility(), nil
+		},
+		convertDustFunc: func(assets []string) (*api.DustConversionResult, error) {
+			t.Fatalf("no conversion expected, got %v", assets)
+			return nil, nil
+		},
+	}
+
+	converter := NewDustConverter(client, &config.DustConfig{ThresholdBNB: 0.0001}, &mockLogger{})
+
+	result, err := converter.ConvertDust(nil)
+	if err != nil {
+		t.Fatalf("ConvertDust() unexpected error: %v", err)
+	}
+	if len(result.Transfers) != 0 {
+		t.Errorf("expected empty result, got %+v", result)
+	}
+}
+
+func TestDustConverter_ValidatesNamedAssets(t *testing.T) {
+	var converted []string
+	client := &mockBinanceClient{
+		getDustAssetsFunc: func() (*api.DustEligibility, error) {
+			return dustEligibility(), nil
+		},
+		convertDustFunc: func(assets []string) (*api.DustConversionResult, error) {
+			converted = assets
+			return &api.DustConversionResult{}, nil
+		},
+	}
+
+	converter := NewDustConverter(client, &config.DustConfig{ThresholdBNB: 0.001, Exclude: []string{"ETH"}}, &mockLogger{})
+
+	_, err := converter.ConvertDust([]string{"shib", "BNB", "XRP"})
+	if err == nil {
+		t.Fatal("expected error for assets that are not convertible")
+	}
+	tradingErr, ok := err.(*errors.TradingError)
+	if !ok || tradingErr.Type != errors.ErrInvalidParameter {
+		t.Errorf("expected invalid parameter error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "BNB, XRP") {
+		t.Errorf("error should name the ineligible assets, got %v", err)
+	}
+	if converted != nil {
+		t.Errorf("nothing should be converted when an asset is ineligible, got %v", converted)
+	}
+
+	// Named assets bypass the threshold and exclusions
+	if _, err := converter.ConvertDust([]string{"eth", "shib"}); err != nil {
+		t.Fatalf("ConvertDust() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(converted, []string{"ETH", "SHIB"}) {
+		t.Errorf("expected named assets to be converted, got %v", converted)
+	}
+}
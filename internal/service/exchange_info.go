@@ -0,0 +1,196 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/pkg/errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultExchangeInfoTTL is used when trading.exchange_info_ttl_ms is not configured
+const DefaultExchangeInfoTTL = time.Hour
+
+// ExchangeInfoCache keeps the order filters of every symbol in memory and reloads them from
+// exchange info once they are older than the TTL
+type ExchangeInfoCache interface {
+	// GetSymbolInfo returns the filters of a symbol, reloading exchange info when stale
+	GetSymbolInfo(symbol string) (*api.SymbolInfo, error)
+
+	// Refresh reloads exchange info regardless of its age
+	Refresh() error
+
+	// UpdatedAt returns when exchange info was last loaded, zero before the first load
+	UpdatedAt() time.Time
+}
+
+// exchangeInfoCache implements ExchangeInfoCache
+type exchangeInfoCache struct {
+	fetch func() (*api.ExchangeInfo, error)
+	ttl   time.Duration
+	now   func() time.Time
+
+	// mu is held while loading so concurrent orders wait for one request
+	mu        sync.Mutex
+	symbols   map[string]*api.SymbolInfo
+	updatedAt time.Time
+}
+
+// NewExchangeInfoCache creates a cache of the spot symbol filters; a ttl of 0 uses DefaultExchangeInfoTTL
+func NewExchangeInfoCache(client api.SpotClient, ttl time.Duration) ExchangeInfoCache {
+	if ttl <= 0 {
+		ttl = DefaultExchangeInfoTTL
+	}
+	return &exchangeInfoCache{
+		fetch: client.GetExchangeInfo,
+		ttl:   ttl,
+		now:   time.Now,
+	}
+}
+
+// GetSymbolInfo returns a copy of the filters of a symbol
+func (c *exchangeInfoCache) GetSymbolInfo(symbol string) (*api.SymbolInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.symbols == nil || c.now().Sub(c.updatedAt) >= c.ttl {
+		if err := c.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	info, ok := c.symbols[symbol]
+	if !ok {
+		return nil, errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			fmt.Sprintf("symbol %s is not listed in exchange info", symbol),
+			0,
+			nil,
+		)
+	}
+	infoCopy := *info
+	return &infoCopy, nil
+}
+
+// Refresh reloads exchange info
+func (c *exchangeInfoCache) Refresh() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.load()
+}
+
+// UpdatedAt returns when exchange info was last loaded
+func (c *exchangeInfoCache) UpdatedAt() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.updatedAt
+}
+
+// load fetches exchange info and replaces the cached filters; the caller holds mu. A failed
+// load keeps the previous filters, which the next lookup tries to replace again.
+func (c *exchangeInfoCache) load() error {
+	info, err := c.fetch()
+	if err != nil {
+		return fmt.Errorf("failed to load exchange info: %w", err)
+	}
+
+	symbols := make(map[string]*api.SymbolInfo, len(info.Symbols))
+	for _, symbol := range info.Symbols {
+		symbols[symbol.Symbol] = symbol
+	}
+	c.symbols = symbols
+	c.updatedAt = c.now()
+	return nil
+}
+
+// SymbolFilter rounds orders to the step and tick size of their symbol and rejects the ones
+// the exchange would refuse under its LOT_SIZE, PRICE_FILTER and MIN_NOTIONAL filters
+type SymbolFilter struct {
+	cache   ExchangeInfoCache
+	rounder *Rounder
+}
+
+// NewSymbolFilter creates a symbol filter rounding with the given rounder; nil truncates
+func NewSymbolFilter(cache ExchangeInfoCache, rounder *Rounder) *SymbolFilter {
+	if rounder == nil {
+		rounder = NewRounder(RoundingModeTruncate)
+	}
+	return &SymbolFilter{cache: cache, rounder: rounder}
+}
+
+// Cache returns the exchange info cache the filters are read from
+func (f *SymbolFilter) Cache() ExchangeInfoCache {
+	return f.cache
+}
+
+// Mode returns the rounding mode quantities and prices are aligned with
+func (f *SymbolFilter) Mode() RoundingMode {
+	return f.rounder.Mode()
+}
+
+// Apply rounds the quantity and price of req in place and checks them against the filters of
+// its symbol. Orders without a price are valued at marketPrice, which is only called when the
+// symbol has a minimum notional; quote quantity orders are valued at their quote quantity.
+func (f *SymbolFilter) Apply(req *api.OrderRequest, marketPrice func() (float64, error)) error {
+	info, err := f.cache.GetSymbolInfo(req.Symbol)
+	if err != nil {
+		return err
+	}
+
+	if req.Quantity > 0 {
+		quantity := f.rounder.RoundQuantity(req.Quantity, info.StepSize, req.Side)
+		if quantity <= 0 || quantity < info.MinQty-roundingEpsilon {
+			return filterError("quantity %s is below the minimum of %s for %s (LOT_SIZE, step %s)",
+				formatFilterValue(req.Quantity), formatFilterValue(info.MinQty), req.Symbol, formatFilterValue(info.StepSize))
+		}
+		if info.MaxQty > 0 && quantity > info.MaxQty+roundingEpsilon {
+			return filterError("quantity %s is above the maximum of %s for %s (LOT_SIZE)",
+				formatFilterValue(quantity), formatFilterValue(info.MaxQty), req.Symbol)
+		}
+		req.Quantity = quantity
+	}
+
+	if req.Price > 0 {
+		price := f.rounder.RoundPrice(req.Price, info.TickSize, req.Side)
+		if price <= 0 || price < info.MinPrice-roundingEpsilon {
+			return filterError("price %s is below the minimum of %s for %s (PRICE_FILTER, tick %s)",
+				formatFilterValue(req.Price), formatFilterValue(info.MinPrice), req.Symbol, formatFilterValue(info.TickSize))
+		}
+		if info.MaxPrice > 0 && price > info.MaxPrice+roundingEpsilon {
+			return filterError("price %s is above the maximum of %s for %s (PRICE_FILTER)",
+				formatFilterValue(price), formatFilterValue(info.MaxPrice), req.Symbol)
+		}
+		req.Price = price
+	}
+
+	if info.MinNotional <= 0 {
+		return nil
+	}
+	notional := req.QuoteOrderQty
+	if notional <= 0 {
+		price := req.Price
+		if price <= 0 && marketPrice != nil {
+			if price, err = marketPrice(); err != nil {
+				return err
+			}
+		}
+		notional = req.Quantity * price
+	}
+	if notional > 0 && notional < info.MinNotional-roundingEpsilon {
+		return filterError("order value %s is below the minimum notional of %s for %s (MIN_NOTIONAL)",
+			formatFilterValue(notional), formatFilterValue(info.MinNotional), req.Symbol)
+	}
+	return nil
+}
+
+// filterError returns the error of an order refused by a symbol filter
+func filterError(format string, args ...interface{}) error {
+	return errors.NewTradingError(errors.ErrInvalidParameter, fmt.Sprintf(format, args...), 0, nil)
+}
+
+// formatFilterValue formats a filter value to at most 8 decimals without trailing zeros
+func formatFilterValue(value float64) string {
+	return strconv.FormatFloat(math.Round(value*1e8)/1e8, 'f', -1, 64)
+}
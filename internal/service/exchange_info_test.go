@@ -0,0 +1,202 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// btcFilters are the filters the filter tests trade BTCUSDT under
+var btcFilters = &api.SymbolInfo{
+	Symbol:      "BTCUSDT",
+	Status:      api.SymbolStatusTrading,
+	MinQty:      0.001,
+	MaxQty:      100,
+	StepSize:    0.001,
+	MinPrice:    0.01,
+	MaxPrice:    1000000,
+	TickSize:    0.01,
+	MinNotional: 10,
+}
+
+// newFilterTestService creates a trading service checking orders against btcFilters; the
+// returned slice collects the requests that reach the exchange
+func newFilterTestService(mode RoundingMode) (SpotTradingService, *[]*api.OrderRequest) {
+	var created []*api.OrderRequest
+	mockClient := &mockBinanceClient{
+		createOrderFunc: func(req *api.OrderRequest) (*api.OrderResponse, error) {
+			reqCopy := *req
+			created = append(created, &reqCopy)
+			return &api.OrderResponse{
+				OrderID:             int64(len(created)),
+				Symbol:              req.Symbol,
+				Status:              api.OrderStatusNew,
+				Price:               req.Price,
+				OrigQty:             req.Quantity,
+				CummulativeQuoteQty: req.Quantity * 50000,
+			}, nil
+		},
+		getBalanceFunc: func(asset string) (*api.Balance, error) {
+			return &api.Balance{Asset: asset, Free: 100000.0}, nil
+		},
+		getExchangeInfoFunc: func() (*api.ExchangeInfo, error) {
+			return &api.ExchangeInfo{Symbols: []*api.SymbolInfo{btcFilters}}, nil
+		},
+	}
+	riskMgr := NewRiskManager(&RiskLimits{
+		MaxOrderAmount:    100000.0,
+		MaxDailyOrders:    100,
+		MinBalanceReserve: 100.0,
+	}, mockClient)
+
+	service := NewTradingService(mockClient, riskMgr, repository.NewMemoryOrderRepository(), &mockLogger{})
+	service.SetSymbolFilter(NewSymbolFilter(NewExchangeInfoCache(mockClient, time.Hour), NewRounder(mode)))
+	return service, &created
+}
+
+func TestSymbolFilter_RoundsQuantityDownToStepSize(t *testing.T) {
+	service, created := newFilterTestService(RoundingModeTruncate)
+
+	if _, err := service.PlaceMarketSellOrder("BTCUSDT", 0.123456789); err != nil {
+		t.Fatalf("PlaceMarketSellOrder() error = %v", err)
+	}
+	if _, err := service.PlaceMarketBuyOrder("BTCUSDT", 0.0999); err != nil {
+		t.Fatalf("PlaceMarketBuyOrder() error = %v", err)
+	}
+
+	if len(*created) != 2 {
+		t.Fatalf("orders sent = %d, want 2", len(*created))
+	}
+	for i, want := range []float64{0.123, 0.099} {
+		if got := (*created)[i].Quantity; got != want {
+			t.Errorf("order %d quantity = %v, want %v rounded down to the 0.001 step", i, got, want)
+		}
+	}
+
+	// A quantity below one step rounds to nothing and is refused locally
+	_, err := service.PlaceMarketSellOrder("BTCUSDT", 0.0009)
+	if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrInvalidParameter {
+		t.Errorf("PlaceMarketSellOrder(0.0009) error = %v, want a LOT_SIZE ErrInvalidParameter", err)
+	}
+	if len(*created) != 2 {
+		t.Errorf("orders sent = %d, want the sub-step order refused before the exchange", len(*created))
+	}
+}
+
+func TestSymbolFilter_AlignsLimitPriceToTickSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      RoundingMode
+		side      api.OrderSide
+		price     float64
+		wantPrice float64
+	}{
+		{"truncated buy", RoundingModeTruncate, api.OrderSideBuy, 50000.128, 50000.12},
+		{"truncated sell", RoundingModeTruncate, api.OrderSideSell, 50000.128, 50000.12},
+		{"nearest", RoundingModeNearest, api.OrderSideBuy, 50000.126, 50000.13},
+		{"conservative sell rounds up", RoundingModeConservative, api.OrderSideSell, 50000.121, 50000.13},
+		{"already aligned", RoundingModeTruncate, api.OrderSideBuy, 50000.12, 50000.12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, created := newFilterTestService(tt.mode)
+
+			var order *api.Order
+			var err error
+			if tt.side == api.OrderSideBuy {
+				order, err = service.PlaceLimitBuyOrder("BTCUSDT", tt.price, 0.01)
+			} else {
+				order, err = service.PlaceLimitSellOrder("BTCUSDT", tt.price, 0.01)
+			}
+			if err != nil {
+				t.Fatalf("limit order error = %v", err)
+			}
+			if len(*created) != 1 || (*created)[0].Price != tt.wantPrice {
+				t.Fatalf("requests sent = %+v, want one at %v", *created, tt.wantPrice)
+			}
+			if order.Price != tt.wantPrice {
+				t.Errorf("order price = %v, want %v", order.Price, tt.wantPrice)
+			}
+		})
+	}
+}
+
+func TestSymbolFilter_MinNotionalRejected(t *testing.T) {
+	service, created := newFilterTestService(RoundingModeTruncate)
+
+	// Market orders are valued at the current price of 50000, so 0.0001 BTC is worth 5 USDT
+	attempts := map[string]func() (*api.Order, error){
+		"market buy": func() (*api.Order, error) { return service.PlaceMarketBuyOrder("BTCUSDT", 0.0001) },
+		"quote buy":  func() (*api.Order, error) { return service.PlaceMarketBuyOrderByQuote("BTCUSDT", 5) },
+		"limit buy":  func() (*api.Order, error) { return service.PlaceLimitBuyOrder("BTCUSDT", 100, 0.05) },
+	}
+	for name, place := range attempts {
+		_, err := place()
+		tradingErr, ok := err.(*errors.TradingError)
+		if !ok || tradingErr.Type != errors.ErrInvalidParameter {
+			t.Errorf("%s error = %v, want a MIN_NOTIONAL ErrInvalidParameter", name, err)
+		}
+	}
+	if len(*created) != 0 {
+		t.Errorf("orders sent = %d, want every order below the minimum notional refused locally", len(*created))
+	}
+
+	// The minimum itself is accepted
+	if _, err := service.PlaceLimitBuyOrder("BTCUSDT", 1000, 0.01); err != nil {
+		t.Errorf("PlaceLimitBuyOrder() at the minimum notional error = %v", err)
+	}
+}
+
+func TestExchangeInfoCache_TTLAndRefresh(t *testing.T) {
+	fetches := 0
+	var fetchErr error
+	mockClient := &mockBinanceClient{
+		getExchangeInfoFunc: func() (*api.ExchangeInfo, error) {
+			fetches++
+			if fetchErr != nil {
+				return nil, fetchErr
+			}
+			return &api.ExchangeInfo{Symbols: []*api.SymbolInfo{btcFilters}}, nil
+		},
+	}
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	cache := NewExchangeInfoCache(mockClient, time.Minute).(*exchangeInfoCache)
+	cache.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if info, err := cache.GetSymbolInfo("BTCUSDT"); err != nil || info.StepSize != 0.001 {
+			t.Fatalf("GetSymbolInfo() = %+v, %v", info, err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("fetches within the TTL = %d, want 1", fetches)
+	}
+
+	if _, err := cache.GetSymbolInfo("DOGEBTC"); err == nil {
+		t.Error("expected an error for a symbol missing from exchange info")
+	}
+
+	// A forced refresh reloads at once, an expired cache on the next lookup
+	if err := cache.Refresh(); err != nil || fetches != 2 {
+		t.Errorf("Refresh() error = %v, fetches = %d, want 2", err, fetches)
+	}
+	now = now.Add(time.Minute)
+	cache.GetSymbolInfo("BTCUSDT")
+	if fetches != 3 || !cache.UpdatedAt().Equal(now) {
+		t.Errorf("fetches after the TTL = %d, updated at %v, want 3 at %v", fetches, cache.UpdatedAt(), now)
+	}
+
+	// A failed reload is reported and retried on the next lookup
+	fetchErr = fmt.Errorf("connection reset")
+	if err := cache.Refresh(); err == nil {
+		t.Error("expected the refresh error")
+	}
+	fetchErr = nil
+	now = now.Add(time.Minute)
+	if _, err := cache.GetSymbolInfo("BTCUSDT"); err != nil || fetches != 5 {
+		t.Errorf("GetSymbolInfo() after a failed refresh error = %v, fetches = %d, want 5", err, fetches)
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/pkg/errors"
+	"fmt"
+)
+
+// basisQuote holds the perpetual and spot price of a symbol, fetched back to back
+type basisQuote struct {
+	perpPrice float64
+	spotPrice float64
+}
+
+// basis returns the premium of the perpetual over spot in percent
+func (q *basisQuote) basis() float64 {
+	return basisPercent(q.spotPrice, q.perpPrice)
+}
+
+// SetSpotPriceSource sets where BASIS triggers read the spot leg; without one they are refused
+func (s *futuresConditionalOrderService) SetSpotPriceSource(source api.SpotPriceClient) {
+	s.spotPrices = source
+}
+
+// quoteBasis returns both legs of a symbol's basis. During a monitoring cycle each symbol is
+// quoted once, so every basis trigger of the cycle compares the same pair of prices.
+func (s *futuresConditionalOrderService) quoteBasis(symbol string, priceType api.PriceType) (*basisQuote, error) {
+	key := symbol + "/" + string(priceType)
+	if quote, ok := s.cycleQuotes[key]; ok {
+		return quote, nil
+	}
+	if s.spotPrices == nil {
+		return nil, errors.NewTradingError(errors.ErrInvalidTriggerCondition, "basis triggers need a spot price source", 0, nil)
+	}
+
+	var perpPrice float64
+	var err error
+	if priceType == api.PriceTypeLast {
+		perpPrice, err = s.marketDataService.GetLastPrice(symbol)
+	} else {
+		perpPrice, err = s.marketDataService.GetMarkPrice(symbol)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	spot, err := s.spotPrices.GetPrice(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot price for %s: %w", symbol, err)
+	}
+	if spot.Price <= 0 || perpPrice <= 0 {
+		return nil, fmt.Errorf("invalid basis prices for %s: perpetual %v, spot %v", symbol, perpPrice, spot.Price)
+	}
+
+	quote := &basisQuote{perpPrice: perpPrice, spotPrice: spot.Price}
+	if s.cycleQuotes != nil {
+		s.cycleQuotes[key] = quote
+	}
+	return quote, nil
+}
+
+// evaluateBasisTrigger compares the percent premium of the perpetual over spot with the threshold
+func (s *futuresConditionalOrderService) evaluateBasisTrigger(symbol string, condition *FuturesTriggerCondition) (bool, float64, error) {
+	quote, err := s.quoteBasis(symbol, condition.PriceType)
+	if err != nil {
+		return false, 0, err
+	}
+
+	basis := quote.basis()
+	return s.compareValue(basis, condition.Operator, condition.Value), basis, nil
+}
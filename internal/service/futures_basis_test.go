@@ -0,0 +1,116 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"fmt"
+	"testing"
+)
+
+// stubSpotPrices serves one spot price and counts requests
+type stubSpotPrices struct {
+	price    float64
+	err      error
+	requests int
+}
+
+func (s *stubSpotPrices) GetPrice(symbol string) (*api.Price, error) {
+	s.requests++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &api.Price{Symbol: symbol, Price: s.price}, nil
+}
+
+func TestFuturesBasisTrigger(t *testing.T) {
+	market := &mockFuturesMarketDataServiceShared{}
+	spot := &stubSpotPrices{price: 50000}
+	trading := &mockFuturesTradingServiceShared{}
+	svc := NewFuturesConditionalOrderService(nil, market, nil, trading, &mockLogger{}).(*futuresConditionalOrderService)
+
+	request := func(quantity float64) *FuturesConditionalOrderRequest {
+		return &FuturesConditionalOrderRequest{
+			Symbol:           "BTCUSDT",
+			Side:             api.OrderSideSell,
+			PositionSide:     api.PositionSideShort,
+			Type:             api.OrderTypeMarket,
+			Quantity:         quantity,
+			TriggerCondition: &FuturesTriggerCondition{Type: FuturesTriggerTypeBasis, Operator: OperatorGreaterEqual, Value: 0.8},
+		}
+	}
+
+	// Without a spot leg the trigger cannot be evaluated, so it is refused up front
+	if _, err := svc.CreateConditionalOrder(request(0.01)); err == nil {
+		t.Fatal("expected a BASIS trigger to be refused without a spot price source")
+	}
+
+	svc.SetSpotPriceSource(spot)
+	first, err := svc.CreateConditionalOrder(request(0.01))
+	if err != nil {
+		t.Fatalf("CreateConditionalOrder() error = %v", err)
+	}
+	second, err := svc.CreateConditionalOrder(request(0.02))
+	if err != nil {
+		t.Fatalf("CreateConditionalOrder() error = %v", err)
+	}
+
+	// 0.7% above spot: below the threshold; both orders share one quote of the cycle
+	market.markPrice = 50350
+	svc.checkOrders()
+	if first.Status != repository.ConditionalOrderStatusPending || second.Status != repository.ConditionalOrderStatusPending {
+		t.Fatalf("orders triggered at 0.7%% basis: %s, %s", first.Status, second.Status)
+	}
+	if spot.requests != 1 {
+		t.Errorf("spot price requested %d times in one cycle, want 1", spot.requests)
+	}
+
+	// The spot price is unavailable: the orders wait for the next cycle
+	spot.err = fmt.Errorf("connection reset")
+	market.markPrice = 50500
+	svc.checkOrders()
+	if first.Status != repository.ConditionalOrderStatusPending || len(trading.orders) != 0 {
+		t.Fatalf("order triggered without a spot price: %s", first.Status)
+	}
+
+	// 1% above spot: both trigger
+	spot.err = nil
+	svc.checkOrders()
+	if first.Status != repository.ConditionalOrderStatusExecuted || second.Status != repository.ConditionalOrderStatusExecuted {
+		t.Fatalf("orders at 1%% basis: %s, %s, want executed", first.Status, second.Status)
+	}
+	if len(trading.orders) != 2 || trading.orders[0].Side != api.OrderSideSell {
+		t.Errorf("placed orders = %+v, want two sells", trading.orders)
+	}
+}
+
+func TestFuturesBasisTriggerPriceTypes(t *testing.T) {
+	market := &mockFuturesMarketDataServiceShared{markPrice: 2990, lastPrice: 3010}
+	svc := NewFuturesConditionalOrderService(nil, market, nil, nil, &mockLogger{}).(*futuresConditionalOrderService)
+	svc.SetSpotPriceSource(&stubSpotPrices{price: 3000})
+
+	tests := []struct {
+		name      string
+		condition *FuturesTriggerCondition
+		triggered bool
+		basis     float64
+	}{
+		{"discount on the mark price", &FuturesTriggerCondition{Type: FuturesTriggerTypeBasis, Operator: OperatorLessEqual, Value: -0.3}, true, -1.0 / 3},
+		{"premium on the last price", &FuturesTriggerCondition{Type: FuturesTriggerTypeBasis, Operator: OperatorLessEqual, Value: -0.3, PriceType: api.PriceTypeLast}, false, 1.0 / 3},
+		{"zero threshold", &FuturesTriggerCondition{Type: FuturesTriggerTypeBasis, Operator: OperatorLessThan, Value: 0}, true, -1.0 / 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := svc.validateTriggerCondition(tt.condition); err != nil {
+				t.Fatalf("validateTriggerCondition() error = %v", err)
+			}
+			triggered, basis, err := svc.evaluateTriggerCondition(&FuturesConditionalOrder{Symbol: "ETHUSDT", TriggerCondition: tt.condition})
+			if err != nil {
+				t.Fatalf("evaluateTriggerCondition() error = %v", err)
+			}
+			if triggered != tt.triggered || !sameAmount(basis, tt.basis) {
+				t.Errorf("got %v at %.6f%%, want %v at %.6f%%", triggered, basis, tt.triggered, tt.basis)
+			}
+		})
+	}
+}
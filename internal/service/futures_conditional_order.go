@@ -5,7 +5,9 @@ import (
 	"binance-trader/internal/repository"
 	"binance-trader/pkg/errors"
 	"binance-trader/pkg/logger"
+	"binance-trader/pkg/timeutil"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,6 +22,7 @@ const (
 	FuturesTriggerTypeUnrealizedPnL
 	FuturesTriggerTypeFundingRate
 	FuturesTriggerTypeMarginRatio
+	FuturesTriggerTypeBasis // Percent premium of the perpetual over the spot price
 )
 
 // FuturesTriggerCondition represents a futures-specific trigger condition
@@ -47,6 +50,8 @@ type FuturesConditionalOrderRequest struct {
 	TriggerCondition *FuturesTriggerCondition
 	ReduceOnly       bool
 	TimeWindow       *repository.TimeWindow
+	IdempotencyKey   string // Optional; retrying with the same key returns the order created first
+	AllowDuplicate   bool   // Create even if an active order has the same symbol, side, quantity and trigger
 }
 
 // FuturesConditionalOrder represents a futures conditional order
@@ -60,11 +65,15 @@ type FuturesConditionalOrder struct {
 	Price            float64
 	TriggerCondition *FuturesTriggerCondition
 	Status           repository.ConditionalOrderStatus
-	CreatedAt        int64
-	TriggeredAt      int64
+	CreatedAt        int64 // Unix ms
+	TriggeredAt      int64 // Unix ms
 	ExecutedOrderID  int64
 	ReduceOnly       bool
 	TimeWindow       *repository.TimeWindow
+	CancelReason     repository.CancelReason
+	CancelledBy      string
+	CancelledAt      int64  // Unix ms
+	IdempotencyKey   string // Client-supplied key the order was created with, if any
 }
 
 // FuturesConditionalOrderUpdate represents updates to a futures conditional order
@@ -81,7 +90,9 @@ type FuturesConditionalOrderService interface {
 	CreateConditionalOrder(request *FuturesConditionalOrderRequest) (*FuturesConditionalOrder, error)
 
 	// Manage conditional orders
-	CancelConditionalOrder(orderID string) error
+	CancelConditionalOrder(orderID, symbol string) (*FuturesConditionalOrder, error)
+	CancelConditionalOrderWithReason(orderID string, reason repository.CancelReason, cancelledBy string) error
+	CancelAllConditionalOrders(symbol string, reason repository.CancelReason, cancelledBy string) (int, error)
 	UpdateConditionalOrder(orderID string, updates *FuturesConditionalOrderUpdate) error
 	GetConditionalOrder(orderID string) (*FuturesConditionalOrder, error)
 	GetActiveConditionalOrders() ([]*FuturesConditionalOrder, error)
@@ -90,6 +101,12 @@ type FuturesConditionalOrderService interface {
 	// Monitoring and triggering
 	StartMonitoring() error
 	StopMonitoring() error
+
+	// SetSpotPriceSource enables BASIS triggers, which compare the perpetual with spot
+	SetSpotPriceSource(source api.SpotPriceClient)
+
+	// Pending orders of a symbol that stops trading are suspended until it trades again
+	SymbolSuspender
 }
 
 // futuresConditionalOrderService implements FuturesConditionalOrderService interface
@@ -102,6 +119,8 @@ type futuresConditionalOrderService struct {
 	orders            map[string]*FuturesConditionalOrder
 	monitoring        bool
 	stopChan          chan struct{}
+	spotPrices        api.SpotPriceClient
+	cycleQuotes       map[string]*basisQuote // Basis quotes of the running monitoring cycle
 }
 
 // NewFuturesConditionalOrderService creates a new futures conditional order service
@@ -151,6 +170,11 @@ func (s *futuresConditionalOrderService) CreateConditionalOrder(request *Futures
 		return nil, err
 	}
 
+	// A retried request returns the order its key created; a repeated one is refused
+	if existing, err := s.findExistingOrder(request); err != nil || existing != nil {
+		return existing, err
+	}
+
 	// Generate unique order ID
 	orderID := uuid.New().String()
 
@@ -165,9 +189,10 @@ func (s *futuresConditionalOrderService) CreateConditionalOrder(request *Futures
 		Price:            request.Price,
 		TriggerCondition: request.TriggerCondition,
 		Status:           repository.ConditionalOrderStatusPending,
-		CreatedAt:        time.Now().Unix(),
+		CreatedAt:        timeutil.NowMillis(),
 		ReduceOnly:       request.ReduceOnly,
 		TimeWindow:       request.TimeWindow,
+		IdempotencyKey:   request.IdempotencyKey,
 	}
 
 	// Save order
@@ -186,8 +211,59 @@ func (s *futuresConditionalOrderService) CreateConditionalOrder(request *Futures
 	return order, nil
 }
 
-// CancelConditionalOrder cancels a futures conditional order
-func (s *futuresConditionalOrderService) CancelConditionalOrder(orderID string) error {
+// findExistingOrder returns the order created earlier with the request's idempotency key, or an
+// error if the key belongs to a different order or an active order already does the same
+func (s *futuresConditionalOrderService) findExistingOrder(request *FuturesConditionalOrderRequest) (*FuturesConditionalOrder, error) {
+	if request.IdempotencyKey != "" {
+		for _, order := range s.orders {
+			if order.IdempotencyKey != request.IdempotencyKey {
+				continue
+			}
+			if !sameFuturesConditionalOrder(order, request) {
+				return nil, idempotencyKeyError(request.IdempotencyKey, order.OrderID)
+			}
+			s.logger.Info("Futures conditional order already created for idempotency key", map[string]interface{}{
+				"order_id":        order.OrderID,
+				"idempotency_key": request.IdempotencyKey,
+			})
+			return order, nil
+		}
+	}
+
+	if request.AllowDuplicate {
+		return nil, nil
+	}
+
+	for _, order := range s.orders {
+		active := order.Status == repository.ConditionalOrderStatusPending || order.Status == repository.ConditionalOrderStatusSuspendedSymbol
+		if active && sameFuturesConditionalOrder(order, request) {
+			return nil, duplicateOrderError(order.OrderID)
+		}
+	}
+	return nil, nil
+}
+
+// CancelConditionalOrder cancels a futures conditional order on behalf of the user; a non-empty symbol
+// must match the order's symbol. The cancelled order is returned so callers can show what was cancelled.
+func (s *futuresConditionalOrderService) CancelConditionalOrder(orderID, symbol string) (*FuturesConditionalOrder, error) {
+	if orderID != "" && symbol != "" {
+		order, err := s.GetConditionalOrder(orderID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkOrderSymbol(orderID, order.Symbol, symbol); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.CancelConditionalOrderWithReason(orderID, repository.CancelReasonUser, "cli"); err != nil {
+		return nil, err
+	}
+	return s.GetConditionalOrder(orderID)
+}
+
+// CancelConditionalOrderWithReason cancels a futures conditional order and records why and by whom
+func (s *futuresConditionalOrderService) CancelConditionalOrderWithReason(orderID string, reason repository.CancelReason, cancelledBy string) error {
 	if orderID == "" {
 		return errors.NewTradingError(errors.ErrInvalidParameter, "order ID cannot be empty", 0, nil)
 	}
@@ -204,7 +280,7 @@ func (s *futuresConditionalOrderService) CancelConditionalOrder(orderID string)
 	}
 
 	// Check if order can be cancelled
-	if order.Status != repository.ConditionalOrderStatusPending {
+	if order.Status != repository.ConditionalOrderStatusPending && order.Status != repository.ConditionalOrderStatusSuspendedSymbol {
 		return errors.NewTradingError(
 			errors.ErrInvalidParameter,
 			fmt.Sprintf("cannot cancel order with status %s", order.Status),
@@ -213,17 +289,48 @@ func (s *futuresConditionalOrderService) CancelConditionalOrder(orderID string)
 		)
 	}
 
+	if reason == "" {
+		return errors.NewTradingError(errors.ErrInvalidParameter, "cancel reason cannot be empty", 0, nil)
+	}
+
 	// Update status to cancelled
 	order.Status = repository.ConditionalOrderStatusCancelled
+	order.CancelReason = reason
+	order.CancelledBy = cancelledBy
+	order.CancelledAt = timeutil.NowMillis()
 
 	s.logger.Info("Futures conditional order cancelled", map[string]interface{}{
-		"order_id": orderID,
-		"symbol":   order.Symbol,
+		"order_id":     orderID,
+		"symbol":       order.Symbol,
+		"reason":       string(reason),
+		"cancelled_by": cancelledBy,
 	})
 
 	return nil
 }
 
+// CancelAllConditionalOrders cancels every pending futures conditional order, or only those of
+// symbol when it is set, and returns how many were cancelled
+func (s *futuresConditionalOrderService) CancelAllConditionalOrders(symbol string, reason repository.CancelReason, cancelledBy string) (int, error) {
+	var orderIDs []string
+	for orderID, order := range s.orders {
+		if order.Status == repository.ConditionalOrderStatusPending && (symbol == "" || order.Symbol == symbol) {
+			orderIDs = append(orderIDs, orderID)
+		}
+	}
+	sort.Strings(orderIDs)
+
+	cancelled := 0
+	for _, orderID := range orderIDs {
+		if err := s.CancelConditionalOrderWithReason(orderID, reason, cancelledBy); err != nil {
+			return cancelled, err
+		}
+		cancelled++
+	}
+
+	return cancelled, nil
+}
+
 // UpdateConditionalOrder updates a futures conditional order
 func (s *futuresConditionalOrderService) UpdateConditionalOrder(orderID string, updates *FuturesConditionalOrderUpdate) error {
 	if orderID == "" {
@@ -319,14 +426,11 @@ func (s *futuresConditionalOrderService) GetActiveConditionalOrders() ([]*Future
 	return activeOrders, nil
 }
 
-// GetConditionalOrderHistory retrieves futures conditional order history within a time range
+// GetConditionalOrderHistory retrieves executed and cancelled futures conditional orders
+// created within a time range given in Unix milliseconds
 func (s *futuresConditionalOrderService) GetConditionalOrderHistory(startTime, endTime int64) ([]*FuturesConditionalOrder, error) {
-	if startTime < 0 || endTime < 0 {
-		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "time values cannot be negative", 0, nil)
-	}
-
-	if startTime > endTime {
-		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "start time cannot be after end time", 0, nil)
+	if err := repository.ValidateTimeRange(startTime, endTime); err != nil {
+		return nil, err
 	}
 
 	var historyOrders []*FuturesConditionalOrder
@@ -342,6 +446,29 @@ func (s *futuresConditionalOrderService) GetConditionalOrderHistory(startTime, e
 	return historyOrders, nil
 }
 
+// SuspendSymbol moves the pending orders of a symbol to SUSPENDED_SYMBOL so they are not
+// evaluated, and returns how many were suspended
+func (s *futuresConditionalOrderService) SuspendSymbol(symbol string) (int, error) {
+	return s.setSymbolStatus(symbol, repository.ConditionalOrderStatusPending, repository.ConditionalOrderStatusSuspendedSymbol), nil
+}
+
+// ResumeSymbol returns the suspended orders of a symbol to PENDING
+func (s *futuresConditionalOrderService) ResumeSymbol(symbol string) (int, error) {
+	return s.setSymbolStatus(symbol, repository.ConditionalOrderStatusSuspendedSymbol, repository.ConditionalOrderStatusPending), nil
+}
+
+// setSymbolStatus moves the orders of symbol from one status to another
+func (s *futuresConditionalOrderService) setSymbolStatus(symbol string, from, to repository.ConditionalOrderStatus) int {
+	changed := 0
+	for _, order := range s.orders {
+		if order.Symbol == symbol && order.Status == from {
+			order.Status = to
+			changed++
+		}
+	}
+	return changed
+}
+
 // StartMonitoring starts the monitoring engine
 func (s *futuresConditionalOrderService) StartMonitoring() error {
 	if s.monitoring {
@@ -388,6 +515,9 @@ func (s *futuresConditionalOrderService) monitorOrders() {
 
 // checkOrders checks all pending orders for trigger conditions
 func (s *futuresConditionalOrderService) checkOrders() {
+	s.cycleQuotes = make(map[string]*basisQuote)
+	defer func() { s.cycleQuotes = nil }()
+
 	for _, order := range s.orders {
 		if order.Status != repository.ConditionalOrderStatusPending {
 			continue
@@ -400,6 +530,8 @@ func (s *futuresConditionalOrderService) checkOrders() {
 				continue
 			}
 			if !order.TimeWindow.EndTime.IsZero() && now.After(order.TimeWindow.EndTime) {
+				// Orders whose window has ended can never trigger
+				s.CancelConditionalOrderWithReason(order.OrderID, repository.CancelReasonExpired, "monitor")
 				continue
 			}
 		}
@@ -439,6 +571,8 @@ func (s *futuresConditionalOrderService) evaluateTriggerCondition(order *Futures
 		return s.evaluateUnrealizedPnLTrigger(order.Symbol, order.PositionSide, condition)
 	case FuturesTriggerTypeFundingRate:
 		return s.evaluateFundingRateTrigger(order.Symbol, condition)
+	case FuturesTriggerTypeBasis:
+		return s.evaluateBasisTrigger(order.Symbol, condition)
 	default:
 		return false, 0, fmt.Errorf("unknown trigger type: %d", condition.Type)
 	}
@@ -573,7 +707,7 @@ func (s *futuresConditionalOrderService) compareValue(currentValue float64, oper
 func (s *futuresConditionalOrderService) executeTrigger(order *FuturesConditionalOrder, triggerValue float64) {
 	// Update order status
 	order.Status = repository.ConditionalOrderStatusTriggered
-	order.TriggeredAt = time.Now().Unix()
+	order.TriggeredAt = timeutil.NowMillis()
 
 	s.logger.Info("Futures conditional order triggered", map[string]interface{}{
 		"order_id":      order.OrderID,
@@ -605,6 +739,15 @@ func (s *futuresConditionalOrderService) executeTrigger(order *FuturesConditiona
 			"order_id": order.OrderID,
 			"error":    err.Error(),
 		})
+		// A temporary guard leaves the order pending until it clears
+		if errors.IsTemporary(err) {
+			order.Status = repository.ConditionalOrderStatusPending
+			order.TriggeredAt = 0
+			return
+		}
+		if isRiskBlock(err) {
+			s.cancelTriggered(order, repository.CancelReasonRiskBlock)
+		}
 		return
 	}
 
@@ -619,6 +762,21 @@ func (s *futuresConditionalOrderService) executeTrigger(order *FuturesConditiona
 	})
 }
 
+// cancelTriggered cancels a triggered order whose execution was blocked
+func (s *futuresConditionalOrderService) cancelTriggered(order *FuturesConditionalOrder, reason repository.CancelReason) {
+	order.Status = repository.ConditionalOrderStatusCancelled
+	order.CancelReason = reason
+	order.CancelledBy = "monitor"
+	order.CancelledAt = timeutil.NowMillis()
+
+	s.logger.Info("Futures conditional order cancelled", map[string]interface{}{
+		"order_id":     order.OrderID,
+		"symbol":       order.Symbol,
+		"reason":       string(reason),
+		"cancelled_by": "monitor",
+	})
+}
+
 // validateTriggerCondition validates a futures trigger condition
 func (s *futuresConditionalOrderService) validateTriggerCondition(condition *FuturesTriggerCondition) error {
 	if condition == nil {
@@ -635,8 +793,12 @@ func (s *futuresConditionalOrderService) validateTriggerCondition(condition *Fut
 		return nil
 	}
 
-	// Validate simple conditions
-	if condition.Value == 0 && condition.Type != FuturesTriggerTypeFundingRate {
+	if condition.Type == FuturesTriggerTypeBasis && s.spotPrices == nil {
+		return errors.NewTradingError(errors.ErrInvalidTriggerCondition, "basis triggers need a spot price source", 0, nil)
+	}
+
+	// Validate simple conditions; funding rates and basis are often zero
+	if condition.Value == 0 && condition.Type != FuturesTriggerTypeFundingRate && condition.Type != FuturesTriggerTypeBasis {
 		return errors.NewTradingError(
 			errors.ErrInvalidTriggerCondition,
 			"trigger value cannot be zero",
@@ -3,6 +3,7 @@ package service
 import (
 	"binance-trader/internal/api"
 	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
 	"binance-trader/pkg/logger"
 	"testing"
 	"time"
@@ -438,13 +439,13 @@ func TestProperty36_TriggerEventLogCompleteness(t *testing.T) {
 			}
 
 			// Record time before trigger
-			beforeTrigger := time.Now().Unix()
+			beforeTrigger := time.Now().UnixMilli()
 
 			// Execute trigger
 			service.(*futuresConditionalOrderService).executeTrigger(order, markPrice)
 
 			// Record time after trigger
-			afterTrigger := time.Now().Unix()
+			afterTrigger := time.Now().UnixMilli()
 
 			// Verify order status changed to triggered/executed
 			if order.Status != repository.ConditionalOrderStatusExecuted {
@@ -474,3 +475,93 @@ func TestProperty36_TriggerEventLogCompleteness(t *testing.T) {
 
 	properties.TestingRun(t)
 }
+
+// riskBlockedFuturesTradingService rejects every order with err, a position limit error by default
+type riskBlockedFuturesTradingService struct {
+	mockFuturesTradingService
+	err error
+}
+
+func (m *riskBlockedFuturesTradingService) OpenLongPosition(symbol string, quantity float64, orderType api.OrderType, price float64) (*api.FuturesOrder, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return nil, errors.NewTradingError(errors.ErrMaxPositionExceeded, "position limit reached", 0, nil)
+}
+
+func TestFuturesConditionalOrder_TemporaryGuardKeepsOrderPending(t *testing.T) {
+	mockLogger, _ := logger.NewLogger(logger.Config{Level: "info", EnableConsole: false})
+	trading := &riskBlockedFuturesTradingService{err: errors.NewTradingError(errors.ErrSymbolPaused, "new orders for BTCUSDT are paused", 0, nil)}
+	service := NewFuturesConditionalOrderService(nil, nil, nil, trading, mockLogger).(*futuresConditionalOrderService)
+
+	order := &FuturesConditionalOrder{
+		OrderID:  "cond-paused",
+		Symbol:   "BTCUSDT",
+		Side:     api.OrderSideBuy,
+		Type:     api.OrderTypeMarket,
+		Quantity: 0.01,
+		Status:   repository.ConditionalOrderStatusPending,
+	}
+	service.orders[order.OrderID] = order
+
+	service.executeTrigger(order, 50000)
+	if order.Status != repository.ConditionalOrderStatusPending || order.TriggeredAt != 0 {
+		t.Errorf("Expected the paused order to stay PENDING, got %s (%s)", order.Status, order.CancelReason)
+	}
+}
+
+func TestFuturesConditionalOrder_CancelReasons(t *testing.T) {
+	mockLogger, _ := logger.NewLogger(logger.Config{Level: "info", EnableConsole: false})
+	service := NewFuturesConditionalOrderService(nil, nil, nil, &riskBlockedFuturesTradingService{}, mockLogger).(*futuresConditionalOrderService)
+
+	newOrder := func(orderID, symbol string) *FuturesConditionalOrder {
+		order := &FuturesConditionalOrder{
+			OrderID:  orderID,
+			Symbol:   symbol,
+			Side:     api.OrderSideBuy,
+			Type:     api.OrderTypeMarket,
+			Quantity: 0.01,
+			Status:   repository.ConditionalOrderStatusPending,
+		}
+		service.orders[orderID] = order
+		return order
+	}
+
+	// Expired orders are cancelled before their trigger is evaluated
+	expired := newOrder("cond-expired", "BTCUSDT")
+	expired.TimeWindow = &repository.TimeWindow{EndTime: time.Now().Add(-time.Minute)}
+	service.checkOrders()
+
+	user := newOrder("cond-user", "BTCUSDT")
+	bulk := newOrder("cond-bulk", "ETHUSDT")
+	risk := newOrder("cond-risk", "BTCUSDT")
+
+	if _, err := service.CancelConditionalOrder(user.OrderID, "ETHUSDT"); err == nil || user.Status != repository.ConditionalOrderStatusPending {
+		t.Fatalf("Expected a symbol mismatch to be refused, got %v (%s)", err, user.Status)
+	}
+	if cancelled, err := service.CancelConditionalOrder(user.OrderID, "BTCUSDT"); err != nil || cancelled.OrderID != user.OrderID || cancelled.Symbol != "BTCUSDT" {
+		t.Fatalf("Failed to cancel order: %+v, %v", cancelled, err)
+	}
+
+	if cancelled, err := service.CancelAllConditionalOrders("ETHUSDT", repository.CancelReasonBulk, "cli"); err != nil || cancelled != 1 {
+		t.Fatalf("Expected 1 bulk cancellation, got %d (%v)", cancelled, err)
+	}
+
+	service.executeTrigger(risk, 50000)
+
+	expected := map[*FuturesConditionalOrder]repository.CancelReason{
+		user:    repository.CancelReasonUser,
+		bulk:    repository.CancelReasonBulk,
+		expired: repository.CancelReasonExpired,
+		risk:    repository.CancelReasonRiskBlock,
+	}
+	for order, reason := range expected {
+		if order.Status != repository.ConditionalOrderStatusCancelled || order.CancelReason != reason || order.CancelledAt == 0 {
+			t.Errorf("%s: expected cancellation with reason %s, got %s (%s)", order.OrderID, reason, order.Status, order.CancelReason)
+		}
+	}
+
+	if _, err := service.CancelConditionalOrder(user.OrderID, ""); err == nil {
+		t.Error("Expected error cancelling an already cancelled order")
+	}
+}
@@ -0,0 +1,755 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/pkg/errors"
+	"binance-trader/pkg/logger"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDryRunLeverage is the leverage of simulated futures positions until SetLeverage changes it
+const DefaultDryRunLeverage = 20
+
+// FuturesDryRunSimulator is a futures client that simulates order placement against live prices.
+// Market data reads go to the wrapped client; orders and leverage, margin type and position mode
+// changes never reach the exchange. Positions are always simulated. With a starting balance they
+// hold margin from a virtual USDT wallet and settle their PnL into it; otherwise account reads go
+// to the exchange account.
+type FuturesDryRunSimulator interface {
+	api.FuturesClient
+
+	// ObservePrice fills the resting limit orders of a symbol that the price reaches
+	ObservePrice(symbol string, price float64, at time.Time)
+
+	// Poll observes the last price of every symbol with open simulated orders
+	Poll() error
+
+	// Price polling
+	StartMonitoring(pollInterval time.Duration) error
+	StopMonitoring() error
+}
+
+// dryRunFuturesOrder is a simulated futures order with the margin it holds
+type dryRunFuturesOrder struct {
+	order    *api.FuturesOrder
+	reserved float64 // Margin held until an opening order fills or is cancelled
+}
+
+// dryRunPosition is one side of a symbol's simulated position
+type dryRunPosition struct {
+	qty        float64
+	entryPrice float64
+	margin     float64
+	leverage   int
+}
+
+// futuresDryRunSimulator implements FuturesDryRunSimulator
+type futuresDryRunSimulator struct {
+	api.FuturesClient
+	logger logger.Logger
+	now    func() time.Time
+
+	mu          sync.Mutex
+	nextOrderID int64
+	orders      map[int64]*dryRunFuturesOrder
+	positions   map[string]*dryRunPosition // Keyed by symbol and position side
+	leverage    map[string]int
+	marginTypes map[string]api.MarginType
+	dualSide    *bool // Position mode set in dry run, nil until SetPositionMode is called
+
+	// Free margin of the virtual USDT wallet, only checked and reported with a starting balance
+	virtual bool
+	balance float64
+
+	// Monitoring
+	stopChan     chan struct{}
+	monitoringMu sync.Mutex
+	isMonitoring bool
+}
+
+// NewFuturesDryRunSimulator wraps a futures client so that orders are simulated instead of sent
+func NewFuturesDryRunSimulator(client api.FuturesClient, cfg *config.DryRunConfig, log logger.Logger) FuturesDryRunSimulator {
+	if cfg == nil {
+		cfg = &config.DryRunConfig{}
+	}
+
+	return &futuresDryRunSimulator{
+		FuturesClient: client,
+		logger:        log,
+		now:           time.Now,
+		orders:        make(map[int64]*dryRunFuturesOrder),
+		positions:     make(map[string]*dryRunPosition),
+		leverage:      make(map[string]int),
+		marginTypes:   make(map[string]api.MarginType),
+		virtual:       cfg.StartingBalance > 0,
+		balance:       cfg.StartingBalance,
+	}
+}
+
+// CreateOrder simulates a hedge mode order: market orders fill at once at the last price, limit
+// orders once the price reaches them. Opening orders hold margin at the symbol's leverage, and
+// closing orders release it and realize the profit or loss.
+func (s *futuresDryRunSimulator) CreateOrder(req *api.FuturesOrderRequest) (*api.FuturesOrderResponse, error) {
+	if req == nil || req.Symbol == "" || req.Quantity <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "symbol and a positive quantity are required", 0, nil)
+	}
+	if req.Type != api.OrderTypeMarket && req.Type != api.OrderTypeLimit {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, fmt.Sprintf("order type %s is not simulated in dry run", req.Type), 0, nil)
+	}
+	if req.Type == api.OrderTypeLimit && req.Price <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "limit orders require a positive price", 0, nil)
+	}
+	if req.PositionSide != api.PositionSideLong && req.PositionSide != api.PositionSideShort {
+		return nil, errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			fmt.Sprintf("position side %q is not simulated in dry run, use LONG or SHORT", req.PositionSide),
+			0,
+			nil,
+		)
+	}
+	closing := closesPosition(req.Side, req.PositionSide)
+
+	lastPrice, err := s.lastPrice(req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reserved := 0.0
+	if closing {
+		if err := s.checkReduce(req.Symbol, req.PositionSide, req.Quantity); err != nil {
+			return nil, err
+		}
+	} else {
+		holdPrice := lastPrice
+		if req.Type == api.OrderTypeLimit {
+			holdPrice = req.Price
+		}
+		reserved = req.Quantity * holdPrice / float64(s.leverageOf(req.Symbol))
+		if s.virtual && s.balance < reserved-dryRunQtyTolerance {
+			return nil, errors.NewTradingError(
+				errors.ErrInsufficientMargin,
+				fmt.Sprintf("insufficient virtual margin: %.8f available, %.8f required", s.balance, reserved),
+				0,
+				nil,
+			)
+		}
+		s.balance -= reserved
+	}
+
+	now := s.now().UnixMilli()
+	s.nextOrderID++
+	simulated := &dryRunFuturesOrder{
+		order: &api.FuturesOrder{
+			OrderID:      s.nextOrderID,
+			Symbol:       req.Symbol,
+			Side:         req.Side,
+			PositionSide: req.PositionSide,
+			Type:         req.Type,
+			Status:       api.OrderStatusNew,
+			Price:        req.Price,
+			OrigQty:      req.Quantity,
+			ReduceOnly:   closing,
+			Time:         now,
+			UpdateTime:   now,
+		},
+		reserved: reserved,
+	}
+	s.orders[simulated.order.OrderID] = simulated
+
+	// A limit order the market has already crossed fills at the better last price
+	if req.Type == api.OrderTypeMarket || futuresLimitReached(simulated.order, lastPrice) {
+		s.fill(simulated, lastPrice)
+	}
+
+	order := simulated.order
+	s.logger.Info("Dry run futures order simulated", map[string]interface{}{
+		"order_id":      order.OrderID,
+		"symbol":        order.Symbol,
+		"side":          string(order.Side),
+		"position_side": string(order.PositionSide),
+		"type":          string(order.Type),
+		"status":        string(order.Status),
+		"quantity":      order.OrigQty,
+		"avg_price":     order.AvgPrice,
+		"market_price":  lastPrice,
+		"dry_run":       true,
+	})
+
+	return &api.FuturesOrderResponse{
+		OrderID:      order.OrderID,
+		Symbol:       order.Symbol,
+		Status:       order.Status,
+		Price:        order.Price,
+		AvgPrice:     order.AvgPrice,
+		OrigQty:      order.OrigQty,
+		ExecutedQty:  order.ExecutedQty,
+		CumQty:       order.ExecutedQty,
+		CumQuote:     order.ExecutedQty * order.AvgPrice,
+		Type:         order.Type,
+		ReduceOnly:   order.ReduceOnly,
+		Side:         order.Side,
+		PositionSide: order.PositionSide,
+		OrigType:     order.Type,
+		UpdateTime:   order.UpdateTime,
+	}, nil
+}
+
+// CancelOrder cancels an open simulated order and releases the margin it holds
+func (s *futuresDryRunSimulator) CancelOrder(symbol string, orderID int64) (*api.CancelResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	simulated, exists := s.orders[orderID]
+	if !exists || simulated.order.Symbol != symbol {
+		return nil, errors.NewTradingError(errors.ErrOrderNotFound, fmt.Sprintf("order %d not found for %s", orderID, symbol), 0, nil)
+	}
+	if !isOpenStatus(simulated.order.Status) {
+		return nil, errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			fmt.Sprintf("order %d is already %s", orderID, simulated.order.Status),
+			0,
+			nil,
+		)
+	}
+
+	s.cancel(simulated)
+	return &api.CancelResponse{Symbol: symbol, OrderID: orderID, Status: simulated.order.Status}, nil
+}
+
+// BulkCancelFuturesOrders cancels every open simulated order of a symbol
+func (s *futuresDryRunSimulator) BulkCancelFuturesOrders(symbol string) ([]api.CancelResponse, error) {
+	if symbol == "" {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "symbol cannot be empty", 0, nil)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var cancelled []api.CancelResponse
+	for _, simulated := range s.sortedOrders(symbol) {
+		if !isOpenStatus(simulated.order.Status) {
+			continue
+		}
+		s.cancel(simulated)
+		cancelled = append(cancelled, api.CancelResponse{
+			Symbol:  symbol,
+			OrderID: simulated.order.OrderID,
+			Status:  simulated.order.Status,
+		})
+	}
+	return cancelled, nil
+}
+
+// GetOrder returns a simulated order
+func (s *futuresDryRunSimulator) GetOrder(symbol string, orderID int64) (*api.FuturesOrder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	simulated, exists := s.orders[orderID]
+	if !exists || simulated.order.Symbol != symbol {
+		return nil, errors.NewTradingError(errors.ErrOrderNotFound, fmt.Sprintf("order %d not found for %s", orderID, symbol), 0, nil)
+	}
+	orderCopy := *simulated.order
+	return &orderCopy, nil
+}
+
+// GetOpenOrders returns the open simulated orders of a symbol, or of all symbols for ""
+func (s *futuresDryRunSimulator) GetOpenOrders(symbol string) ([]*api.FuturesOrder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var orders []*api.FuturesOrder
+	for _, simulated := range s.sortedOrders(symbol) {
+		if isOpenStatus(simulated.order.Status) {
+			orderCopy := *simulated.order
+			orders = append(orders, &orderCopy)
+		}
+	}
+	return orders, nil
+}
+
+// GetPositions returns the simulated positions of a symbol valued at the mark price
+func (s *futuresDryRunSimulator) GetPositions(symbol string) ([]*api.Position, error) {
+	if symbol == "" {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "symbol cannot be empty", 0, nil)
+	}
+	return s.valuedPositions(symbol)
+}
+
+// GetAllPositions returns every simulated position valued at the mark price
+func (s *futuresDryRunSimulator) GetAllPositions() ([]*api.Position, error) {
+	return s.valuedPositions("")
+}
+
+// SetLeverage sets the leverage of new simulated positions of a symbol
+func (s *futuresDryRunSimulator) SetLeverage(symbol string, leverage int) (*api.LeverageResponse, error) {
+	if symbol == "" {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "symbol cannot be empty", 0, nil)
+	}
+	if leverage < 1 || leverage > 125 {
+		return nil, errors.NewTradingError(
+			errors.ErrInvalidLeverage,
+			fmt.Sprintf("leverage must be between 1 and 125, got: %d", leverage),
+			0,
+			nil,
+		)
+	}
+
+	s.mu.Lock()
+	s.leverage[symbol] = leverage
+	s.mu.Unlock()
+
+	s.logger.Info("Dry run leverage set", map[string]interface{}{
+		"symbol":   symbol,
+		"leverage": leverage,
+		"dry_run":  true,
+	})
+	return &api.LeverageResponse{Leverage: leverage, Symbol: symbol}, nil
+}
+
+// SetMarginType records the margin type of a symbol; simulated positions always hold their own
+// margin, so it does not change how they settle
+func (s *futuresDryRunSimulator) SetMarginType(symbol string, marginType api.MarginType) error {
+	if symbol == "" {
+		return errors.NewTradingError(errors.ErrInvalidParameter, "symbol cannot be empty", 0, nil)
+	}
+	if marginType != api.MarginTypeIsolated && marginType != api.MarginTypeCrossed {
+		return errors.NewTradingError(errors.ErrInvalidParameter, fmt.Sprintf("invalid margin type: %s", marginType), 0, nil)
+	}
+
+	s.mu.Lock()
+	s.marginTypes[symbol] = marginType
+	s.mu.Unlock()
+
+	s.logger.Info("Dry run margin type set", map[string]interface{}{
+		"symbol":      symbol,
+		"margin_type": marginType,
+		"dry_run":     true,
+	})
+	return nil
+}
+
+// SetPositionMode records the position mode; simulated positions are always kept per side, so
+// the mode does not change how they open or close
+func (s *futuresDryRunSimulator) SetPositionMode(dualSidePosition bool) error {
+	s.mu.Lock()
+	s.dualSide = &dualSidePosition
+	s.mu.Unlock()
+
+	s.logger.Info("Dry run position mode set", map[string]interface{}{
+		"mode":    positionModeName(dualSidePosition),
+		"dry_run": true,
+	})
+	return nil
+}
+
+// GetPositionMode returns the mode set in dry run, or the exchange account's mode until one is set
+func (s *futuresDryRunSimulator) GetPositionMode() (*api.PositionMode, error) {
+	s.mu.Lock()
+	dualSide := s.dualSide
+	s.mu.Unlock()
+
+	if dualSide == nil {
+		return s.FuturesClient.GetPositionMode()
+	}
+	return &api.PositionMode{DualSidePosition: *dualSide}, nil
+}
+
+// GetFuturesAccountInfo returns the virtual account with a starting balance: its wallet balance
+// is the free margin plus the margin held by positions and open orders. Without one it returns
+// the exchange account. Either way the positions are the simulated ones.
+func (s *futuresDryRunSimulator) GetFuturesAccountInfo() (*api.FuturesAccountInfo, error) {
+	positions, err := s.valuedPositions("")
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.virtual {
+		account, err := s.FuturesClient.GetFuturesAccountInfo()
+		if err != nil {
+			return nil, err
+		}
+		account.Positions = nil
+		for _, position := range positions {
+			account.Positions = append(account.Positions, *position)
+		}
+		return account, nil
+	}
+
+	unrealized := 0.0
+	for _, position := range positions {
+		unrealized += position.UnrealizedProfit
+	}
+
+	s.mu.Lock()
+	free, held := s.balance, s.heldMargin()
+	s.mu.Unlock()
+	wallet := free + held
+
+	account := &api.FuturesAccountInfo{
+		Assets: []api.FuturesAssetBalance{{
+			Asset:            DefaultPaperBalanceAsset,
+			WalletBalance:    wallet,
+			UnrealizedProfit: unrealized,
+			MarginBalance:    wallet + unrealized,
+			InitialMargin:    held,
+			AvailableBalance: free,
+			MarginAvailable:  true,
+		}},
+		AvailableBalance:      free,
+		CanTrade:              true,
+		TotalInitialMargin:    held,
+		TotalMarginBalance:    wallet + unrealized,
+		TotalUnrealizedProfit: unrealized,
+		TotalWalletBalance:    wallet,
+		UpdateTime:            s.now().UnixMilli(),
+	}
+	for _, position := range positions {
+		account.Positions = append(account.Positions, *position)
+	}
+	return account, nil
+}
+
+// GetBalance returns the USDT balance of the virtual wallet, or of the exchange account without
+// a starting balance
+func (s *futuresDryRunSimulator) GetBalance() (*api.FuturesBalance, error) {
+	if !s.virtual {
+		return s.FuturesClient.GetBalance()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wallet := s.balance + s.heldMargin()
+	return &api.FuturesBalance{
+		Asset:              DefaultPaperBalanceAsset,
+		Balance:            wallet,
+		AvailableBalance:   s.balance,
+		CrossWalletBalance: wallet,
+		MaxWithdrawAmount:  s.balance,
+		MarginAvailable:    true,
+		UpdateTime:         s.now().UnixMilli(),
+	}, nil
+}
+
+// ObservePrice fills the resting limit orders of a symbol the price reaches at their limit price
+func (s *futuresDryRunSimulator) ObservePrice(symbol string, price float64, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, simulated := range s.sortedOrders(symbol) {
+		order := simulated.order
+		if order.Type != api.OrderTypeLimit || !isOpenStatus(order.Status) || !futuresLimitReached(order, price) {
+			continue
+		}
+		s.fill(simulated, order.Price)
+
+		s.logger.Info("Dry run futures limit order filled", map[string]interface{}{
+			"order_id":      order.OrderID,
+			"symbol":        order.Symbol,
+			"side":          string(order.Side),
+			"position_side": string(order.PositionSide),
+			"status":        string(order.Status),
+			"price":         order.Price,
+			"market_price":  price,
+			"dry_run":       true,
+		})
+	}
+}
+
+// Poll observes the last price of every symbol with open simulated limit orders
+func (s *futuresDryRunSimulator) Poll() error {
+	s.mu.Lock()
+	symbols := make(map[string]bool)
+	for _, simulated := range s.orders {
+		if simulated.order.Type == api.OrderTypeLimit && isOpenStatus(simulated.order.Status) {
+			symbols[simulated.order.Symbol] = true
+		}
+	}
+	s.mu.Unlock()
+
+	var firstErr error
+	for symbol := range symbols {
+		price, err := s.FuturesClient.GetPrice(symbol)
+		if err != nil {
+			s.logger.Warn("Dry run futures price poll failed", map[string]interface{}{
+				"symbol": symbol,
+				"error":  err.Error(),
+			})
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		s.ObservePrice(symbol, price.Price, s.now())
+	}
+
+	return firstErr
+}
+
+// StartMonitoring starts polling prices for resting simulated orders
+func (s *futuresDryRunSimulator) StartMonitoring(pollInterval time.Duration) error {
+	s.monitoringMu.Lock()
+	defer s.monitoringMu.Unlock()
+
+	if s.isMonitoring {
+		return fmt.Errorf("monitoring is already running")
+	}
+
+	if pollInterval <= 0 {
+		pollInterval = DefaultDryRunPollInterval
+	}
+
+	s.stopChan = make(chan struct{})
+	s.isMonitoring = true
+
+	go s.monitoringLoop(pollInterval)
+
+	s.logger.Info("Started futures dry run order simulation", map[string]interface{}{
+		"poll_interval": pollInterval.String(),
+	})
+
+	return nil
+}
+
+// StopMonitoring stops polling prices
+func (s *futuresDryRunSimulator) StopMonitoring() error {
+	s.monitoringMu.Lock()
+	defer s.monitoringMu.Unlock()
+
+	if !s.isMonitoring {
+		return fmt.Errorf("monitoring is not running")
+	}
+
+	close(s.stopChan)
+	s.isMonitoring = false
+
+	s.logger.Info("Stopped futures dry run order simulation", nil)
+
+	return nil
+}
+
+// monitoringLoop polls on every tick; failures are logged by Poll and retried on the next tick
+func (s *futuresDryRunSimulator) monitoringLoop(pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			s.Poll()
+		}
+	}
+}
+
+// fill executes the whole order at the price: opening orders add to the position and return
+// unused held margin, closing orders release margin and realize the profit or loss. A closing
+// limit order whose position has shrunk below its quantity expires instead. The caller holds s.mu.
+func (s *futuresDryRunSimulator) fill(simulated *dryRunFuturesOrder, price float64) {
+	order := simulated.order
+	key := positionKey(order.Symbol, order.PositionSide)
+	order.UpdateTime = s.now().UnixMilli()
+
+	if order.ReduceOnly {
+		if err := s.checkReduce(order.Symbol, order.PositionSide, order.OrigQty); err != nil {
+			order.Status = api.OrderStatusExpired
+			s.logger.Warn("Dry run closing order expired", map[string]interface{}{
+				"order_id": order.OrderID,
+				"symbol":   order.Symbol,
+				"error":    err.Error(),
+				"dry_run":  true,
+			})
+			return
+		}
+
+		position := s.positions[key]
+		released := position.margin * order.OrigQty / position.qty
+		pnl := (price - position.entryPrice) * order.OrigQty
+		if order.PositionSide == api.PositionSideShort {
+			pnl = -pnl
+		}
+		s.balance += released + pnl
+		position.margin -= released
+		position.qty -= order.OrigQty
+		if position.qty <= dryRunQtyTolerance {
+			delete(s.positions, key)
+		}
+	} else {
+		leverage := s.leverageOf(order.Symbol)
+		margin := order.OrigQty * price / float64(leverage)
+		s.balance += simulated.reserved - margin
+		simulated.reserved = 0
+
+		position, exists := s.positions[key]
+		if !exists {
+			position = &dryRunPosition{}
+			s.positions[key] = position
+		}
+		position.entryPrice = (position.entryPrice*position.qty + price*order.OrigQty) / (position.qty + order.OrigQty)
+		position.qty += order.OrigQty
+		position.margin += margin
+		position.leverage = leverage
+	}
+
+	order.Status = api.OrderStatusFilled
+	order.ExecutedQty = order.OrigQty
+	order.AvgPrice = price
+}
+
+// cancel cancels an open simulated order and releases the margin it holds; the caller holds s.mu
+func (s *futuresDryRunSimulator) cancel(simulated *dryRunFuturesOrder) {
+	s.balance += simulated.reserved
+	simulated.reserved = 0
+	simulated.order.Status = api.OrderStatusCanceled
+	simulated.order.UpdateTime = s.now().UnixMilli()
+}
+
+// checkReduce rejects closing more than the simulated position holds; the caller holds s.mu
+func (s *futuresDryRunSimulator) checkReduce(symbol string, positionSide api.PositionSide, quantity float64) error {
+	position, exists := s.positions[positionKey(symbol, positionSide)]
+	if !exists {
+		return errors.NewTradingError(errors.ErrPositionNotFound, fmt.Sprintf("no simulated %s position for %s", positionSide, symbol), 0, nil)
+	}
+	if quantity > position.qty+dryRunQtyTolerance {
+		return errors.NewTradingError(
+			errors.ErrReduceOnlyViolation,
+			fmt.Sprintf("close quantity %.8f exceeds the %s position of %.8f", quantity, positionSide, position.qty),
+			0,
+			nil,
+		)
+	}
+	return nil
+}
+
+// valuedPositions returns the simulated positions of a symbol (all symbols for "") valued at the
+// mark price; short amounts are negative like on the exchange
+func (s *futuresDryRunSimulator) valuedPositions(symbol string) ([]*api.Position, error) {
+	s.mu.Lock()
+	keys := make([]string, 0, len(s.positions))
+	for key := range s.positions {
+		if symbol == "" || strings.HasPrefix(key, symbol+"/") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	positions := make([]*api.Position, 0, len(keys))
+	for _, key := range keys {
+		position := s.positions[key]
+		positionSymbol, side, _ := strings.Cut(key, "/")
+		amount := position.qty
+		if api.PositionSide(side) == api.PositionSideShort {
+			amount = -amount
+		}
+		marginType := s.marginTypes[positionSymbol]
+		if marginType == "" {
+			marginType = api.MarginTypeCrossed
+		}
+		positions = append(positions, &api.Position{
+			Symbol:                positionSymbol,
+			PositionSide:          api.PositionSide(side),
+			PositionAmt:           amount,
+			EntryPrice:            position.entryPrice,
+			Leverage:              position.leverage,
+			MarginType:            marginType,
+			PositionInitialMargin: position.margin,
+			UpdateTime:            s.now().UnixMilli(),
+		})
+	}
+	s.mu.Unlock()
+
+	marks := make(map[string]float64)
+	for _, position := range positions {
+		mark, cached := marks[position.Symbol]
+		if !cached {
+			markPrice, err := s.FuturesClient.GetMarkPrice(position.Symbol)
+			if err != nil {
+				return nil, fmt.Errorf("failed to value simulated position %s: %w", position.Symbol, err)
+			}
+			mark = markPrice.MarkPrice
+			marks[position.Symbol] = mark
+		}
+		position.MarkPrice = mark
+		// Short amounts are negative, so the same formula values both sides
+		position.UnrealizedProfit = (mark - position.EntryPrice) * position.PositionAmt
+	}
+	return positions, nil
+}
+
+// heldMargin returns the margin held by positions and open orders; the caller holds s.mu
+func (s *futuresDryRunSimulator) heldMargin() float64 {
+	held := 0.0
+	for _, position := range s.positions {
+		held += position.margin
+	}
+	for _, simulated := range s.orders {
+		held += simulated.reserved
+	}
+	return held
+}
+
+// lastPrice returns the price simulated orders of a symbol fill at
+func (s *futuresDryRunSimulator) lastPrice(symbol string) (float64, error) {
+	price, err := s.FuturesClient.GetPrice(symbol)
+	if err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation": "dry_run_futures_price",
+			"symbol":    symbol,
+		})
+		return 0, err
+	}
+	return price.Price, nil
+}
+
+// leverageOf returns the leverage of a symbol; the caller holds s.mu
+func (s *futuresDryRunSimulator) leverageOf(symbol string) int {
+	if leverage, exists := s.leverage[symbol]; exists {
+		return leverage
+	}
+	return DefaultDryRunLeverage
+}
+
+// sortedOrders returns the simulated orders of a symbol (all symbols for "") in placement order
+func (s *futuresDryRunSimulator) sortedOrders(symbol string) []*dryRunFuturesOrder {
+	orders := make([]*dryRunFuturesOrder, 0, len(s.orders))
+	for _, simulated := range s.orders {
+		if symbol == "" || simulated.order.Symbol == symbol {
+			orders = append(orders, simulated)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].order.OrderID < orders[j].order.OrderID
+	})
+	return orders
+}
+
+// closesPosition reports whether an order side reduces the given side of a hedge mode position
+func closesPosition(side api.OrderSide, positionSide api.PositionSide) bool {
+	if positionSide == api.PositionSideLong {
+		return side == api.OrderSideSell
+	}
+	return side == api.OrderSideBuy
+}
+
+// positionKey identifies one side of a symbol's simulated position
+func positionKey(symbol string, side api.PositionSide) string {
+	return symbol + "/" + string(side)
+}
+
+// futuresLimitReached reports whether a price is at or better than a futures order's limit
+func futuresLimitReached(order *api.FuturesOrder, price float64) bool {
+	if order.Side == api.OrderSideSell {
+		return price >= order.Price
+	}
+	return price <= order.Price
+}
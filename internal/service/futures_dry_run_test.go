@@ -0,0 +1,270 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	stderrors "errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// newPricedFuturesClient returns a futures client whose last and mark price both read *price
+func newPricedFuturesClient(price *float64) *mockFuturesClient {
+	return &mockFuturesClient{
+		priceFunc: func(symbol string) (*api.Price, error) {
+			return &api.Price{Symbol: symbol, Price: *price}, nil
+		},
+		markPriceFunc: func(symbol string) (*api.MarkPrice, error) {
+			return &api.MarkPrice{Symbol: symbol, MarkPrice: *price}, nil
+		},
+	}
+}
+
+func TestFuturesDryRunSimulator(t *testing.T) {
+	price := 50000.0
+	log := &mockLoggerCapture{}
+	simulator := NewFuturesDryRunSimulator(newPricedFuturesClient(&price), &config.DryRunConfig{StartingBalance: DefaultPaperBalance}, log)
+	trading := NewFuturesTradingService(simulator, repository.NewMemoryFuturesOrderRepository(), log)
+
+	assertAvailable := func(want float64) {
+		t.Helper()
+		balance, err := simulator.GetBalance()
+		if err != nil || math.Abs(balance.AvailableBalance-want) > 1e-6 {
+			t.Errorf("available balance = %+v, %v; want %v", balance, err, want)
+		}
+	}
+
+	// A market long at the default leverage holds 1/20 of the notional as margin
+	long, err := trading.OpenLongPosition("BTCUSDT", 1, api.OrderTypeMarket, 0)
+	if err != nil {
+		t.Fatalf("OpenLongPosition() error = %v", err)
+	}
+	if long.Status != api.OrderStatusFilled || long.AvgPrice != 50000 {
+		t.Fatalf("market long = %+v, want FILLED at 50000", long)
+	}
+	assertAvailable(DefaultPaperBalance - 2500)
+
+	// Closing half realizes half of the profit and releases half of the margin
+	price = 51000
+	if _, err := trading.ClosePosition("BTCUSDT", api.PositionSideLong, 0.5); err != nil {
+		t.Fatalf("ClosePosition() error = %v", err)
+	}
+	assertAvailable(9250)
+	positions, err := simulator.GetPositions("BTCUSDT")
+	if err != nil || len(positions) != 1 || positions[0].PositionAmt != 0.5 || positions[0].EntryPrice != 50000 || positions[0].UnrealizedProfit != 500 {
+		t.Fatalf("positions = %+v, %v; want 0.5 long at 50000 with 500 unrealized", positions, err)
+	}
+
+	_, err = trading.ClosePosition("BTCUSDT", api.PositionSideLong, 1)
+	var tradingErr *errors.TradingError
+	if !stderrors.As(err, &tradingErr) || tradingErr.Type != errors.ErrReduceOnlyViolation {
+		t.Errorf("closing more than the position error = %v, want ErrReduceOnlyViolation", err)
+	}
+
+	// A limit short rests with its margin held and fills when the price reaches it
+	if _, err := trading.SetLeverage("BTCUSDT", 10); err != nil {
+		t.Fatalf("SetLeverage() error = %v", err)
+	}
+	short, err := trading.OpenShortPosition("BTCUSDT", 1, api.OrderTypeLimit, 52000)
+	if err != nil || short.Status != api.OrderStatusNew {
+		t.Fatalf("limit short = %+v, %v; want NEW", short, err)
+	}
+	assertAvailable(4050)
+	if active, _ := trading.GetActiveOrders("BTCUSDT"); len(active) != 1 || active[0].OrderID != short.OrderID {
+		t.Fatalf("active orders = %v, want the limit short", active)
+	}
+
+	price = 51500
+	if err := simulator.Poll(); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if order, _ := simulator.GetOrder("BTCUSDT", short.OrderID); order.Status != api.OrderStatusNew {
+		t.Fatalf("limit short below its price = %s, want NEW", order.Status)
+	}
+
+	price = 52500
+	if err := simulator.Poll(); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	order, err := simulator.GetOrder("BTCUSDT", short.OrderID)
+	if err != nil || order.Status != api.OrderStatusFilled || order.AvgPrice != 52000 {
+		t.Fatalf("limit short after the price crossed = %+v, %v; want FILLED at 52000", order, err)
+	}
+	assertAvailable(4050)
+	if leverage, _ := trading.GetLeverage("BTCUSDT"); leverage != 20 {
+		t.Errorf("GetLeverage() = %d, want 20 from the long opened first", leverage)
+	}
+
+	// Closing everything realizes 0 on the long and 2000 on the short
+	price = 50000
+	closed, err := trading.CloseAllPositions("BTCUSDT")
+	if err != nil || len(closed) != 2 {
+		t.Fatalf("CloseAllPositions() = %v, %v; want two closing orders", closed, err)
+	}
+	assertAvailable(12500)
+	if positions, _ := simulator.GetAllPositions(); len(positions) != 0 {
+		t.Errorf("positions after closing everything = %+v", positions)
+	}
+
+	// A cancelled limit order releases its margin
+	resting, err := trading.OpenLongPosition("ETHUSDT", 2, api.OrderTypeLimit, 2500)
+	if err != nil {
+		t.Fatalf("OpenLongPosition() error = %v", err)
+	}
+	assertAvailable(12250)
+	if err := trading.CancelOrder("ETHUSDT", resting.OrderID); err != nil {
+		t.Fatalf("CancelOrder() error = %v", err)
+	}
+	assertAvailable(12500)
+
+	for _, limit := range []float64{2500, 2400} {
+		if _, err := trading.OpenLongPosition("ETHUSDT", 1, api.OrderTypeLimit, limit); err != nil {
+			t.Fatalf("OpenLongPosition() error = %v", err)
+		}
+	}
+	if cancelled, err := trading.CancelAllOrders("ETHUSDT"); err != nil || cancelled != 2 {
+		t.Fatalf("CancelAllOrders() = %d, %v; want 2", cancelled, err)
+	}
+	assertAvailable(12500)
+
+	_, err = trading.OpenLongPosition("BTCUSDT", 100, api.OrderTypeMarket, 0)
+	if !stderrors.As(err, &tradingErr) || tradingErr.Type != errors.ErrInsufficientMargin {
+		t.Errorf("position beyond the margin error = %v, want ErrInsufficientMargin", err)
+	}
+
+	// Order types the simulator cannot fill are rejected rather than sent
+	if _, err := simulator.CreateOrder(&api.FuturesOrderRequest{
+		Symbol: "BTCUSDT", Side: api.OrderSideSell, PositionSide: api.PositionSideLong,
+		Type: api.OrderTypeTrailingStopMarket, Quantity: 1, CallbackRate: 1,
+	}); err == nil {
+		t.Error("trailing stop orders should not be simulated")
+	}
+
+	simulated := 0
+	for _, entry := range log.entries {
+		if entry["message"] == "Dry run futures order simulated" {
+			simulated++
+			if entry["dry_run"] != true {
+				t.Errorf("simulated order log %v is not marked dry_run", entry)
+			}
+		}
+	}
+	if simulated == 0 {
+		t.Error("no simulated order was logged")
+	}
+}
+
+func TestFuturesDryRunSimulator_AccountInfo(t *testing.T) {
+	price := 50000.0
+	simulator := NewFuturesDryRunSimulator(newPricedFuturesClient(&price), &config.DryRunConfig{StartingBalance: DefaultPaperBalance}, &mockLogger{})
+
+	// A filled long holds 2500 of margin and a resting limit short 3000
+	if _, err := simulator.CreateOrder(&api.FuturesOrderRequest{
+		Symbol: "BTCUSDT", Side: api.OrderSideBuy, PositionSide: api.PositionSideLong, Type: api.OrderTypeMarket, Quantity: 1,
+	}); err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	if _, err := simulator.CreateOrder(&api.FuturesOrderRequest{
+		Symbol: "BTCUSDT", Side: api.OrderSideSell, PositionSide: api.PositionSideShort, Type: api.OrderTypeLimit, Quantity: 1, Price: 60000,
+	}); err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	price = 51000
+
+	account, err := simulator.GetFuturesAccountInfo()
+	if err != nil {
+		t.Fatalf("GetFuturesAccountInfo() error = %v", err)
+	}
+	// Held margin stays in the wallet balance; only the free balance is available
+	if account.TotalWalletBalance != DefaultPaperBalance || account.AvailableBalance != 4500 ||
+		account.TotalUnrealizedProfit != 1000 || account.TotalMarginBalance != DefaultPaperBalance+1000 {
+		t.Errorf("account = wallet %v, available %v, unrealized %v, margin %v; want %v, 4500, 1000, %v",
+			account.TotalWalletBalance, account.AvailableBalance, account.TotalUnrealizedProfit, account.TotalMarginBalance,
+			DefaultPaperBalance, DefaultPaperBalance+1000)
+	}
+	if len(account.Assets) != 1 || account.Assets[0].Asset != DefaultPaperBalanceAsset || account.Assets[0].AvailableBalance != 4500 {
+		t.Errorf("assets = %+v, want the USDT margin balance", account.Assets)
+	}
+	if len(account.Positions) != 1 || account.Positions[0].UnrealizedProfit != 1000 {
+		t.Errorf("positions = %+v, want the long with 1000 unrealized", account.Positions)
+	}
+}
+
+func TestFuturesDryRunSimulator_ExchangeAccount(t *testing.T) {
+	price := 3000.0
+	client := newPricedFuturesClient(&price)
+	client.accountInfoFunc = func() (*api.FuturesAccountInfo, error) {
+		return &api.FuturesAccountInfo{
+			TotalWalletBalance: 800,
+			AvailableBalance:   800,
+			Positions:          []api.Position{{Symbol: "BTCUSDT", PositionSide: api.PositionSideLong, PositionAmt: 2}},
+		}, nil
+	}
+	simulator := NewFuturesDryRunSimulator(client, &config.DryRunConfig{}, &mockLogger{})
+
+	// Without a starting balance margin is not checked, but positions are still simulated
+	if _, err := simulator.CreateOrder(&api.FuturesOrderRequest{
+		Symbol: "ETHUSDT", Side: api.OrderSideSell, PositionSide: api.PositionSideShort, Type: api.OrderTypeMarket, Quantity: 10,
+	}); err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	price = 2900
+
+	account, err := simulator.GetFuturesAccountInfo()
+	if err != nil {
+		t.Fatalf("GetFuturesAccountInfo() error = %v", err)
+	}
+	if account.TotalWalletBalance != 800 {
+		t.Errorf("wallet balance = %v, want 800 from the exchange account", account.TotalWalletBalance)
+	}
+	if len(account.Positions) != 1 || account.Positions[0].Symbol != "ETHUSDT" || account.Positions[0].PositionAmt != -10 ||
+		account.Positions[0].UnrealizedProfit != 1000 {
+		t.Errorf("positions = %+v, want only the simulated short with 1000 unrealized", account.Positions)
+	}
+
+	// Position mode changes stay local
+	if err := simulator.SetPositionMode(true); err != nil {
+		t.Fatalf("SetPositionMode() error = %v", err)
+	}
+	if mode, err := simulator.GetPositionMode(); err != nil || !mode.DualSidePosition {
+		t.Errorf("GetPositionMode() = %+v, %v; want hedge mode", mode, err)
+	}
+	if _, err := simulator.SetLeverage("ETHUSDT", 200); err == nil {
+		t.Error("leverage above 125 should be rejected")
+	}
+}
+
+func TestFuturesDryRunSimulator_ClosingLimitExpires(t *testing.T) {
+	price := 100.0
+	simulator := NewFuturesDryRunSimulator(newPricedFuturesClient(&price), nil, &mockLogger{})
+
+	if _, err := simulator.CreateOrder(&api.FuturesOrderRequest{
+		Symbol: "SOLUSDT", Side: api.OrderSideBuy, PositionSide: api.PositionSideLong, Type: api.OrderTypeMarket, Quantity: 5,
+	}); err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	takeProfit, err := simulator.CreateOrder(&api.FuturesOrderRequest{
+		Symbol: "SOLUSDT", Side: api.OrderSideSell, PositionSide: api.PositionSideLong, Type: api.OrderTypeLimit, Quantity: 5, Price: 120,
+	})
+	if err != nil || takeProfit.Status != api.OrderStatusNew || !takeProfit.ReduceOnly {
+		t.Fatalf("closing limit = %+v, %v; want a NEW reduce-only order", takeProfit, err)
+	}
+	if _, err := simulator.CreateOrder(&api.FuturesOrderRequest{
+		Symbol: "SOLUSDT", Side: api.OrderSideSell, PositionSide: api.PositionSideLong, Type: api.OrderTypeMarket, Quantity: 5,
+	}); err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+
+	// The position is gone by the time the limit is reached, so it expires instead of opening a short
+	simulator.ObservePrice("SOLUSDT", 121, time.Now())
+	order, err := simulator.GetOrder("SOLUSDT", takeProfit.OrderID)
+	if err != nil || order.Status != api.OrderStatusExpired {
+		t.Errorf("closing limit without a position = %+v, %v; want EXPIRED", order, err)
+	}
+	if positions, _ := simulator.GetAllPositions(); len(positions) != 0 {
+		t.Errorf("positions = %+v, want none", positions)
+	}
+}
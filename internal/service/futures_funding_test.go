@@ -2,6 +2,7 @@ package service
 
 import (
 	"binance-trader/internal/api"
+	"fmt"
 	"testing"
 	"time"
 
@@ -29,6 +30,10 @@ func (m *mockFundingMarketService) GetHistoricalData(symbol string, interval str
 	return nil, m.err
 }
 
+func (m *mockFundingMarketService) GetVolume(symbol string, timeWindow time.Duration) (float64, error) {
+	return 0, m.err
+}
+
 func (m *mockFundingMarketService) GetFundingRate(symbol string) (*api.FundingRate, error) {
 	if m.err != nil {
 		return nil, m.err
@@ -47,6 +52,12 @@ func (m *mockFundingMarketService) SubscribeToMarkPrice(symbol string, callback
 	return m.err
 }
 
+func (m *mockFundingMarketService) GetBestBidAsk(symbol string) (*BestBidAsk, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockFundingMarketService) SetBookTickerCache(cache BookTickerCache) {}
+
 // Feature: usdt-futures-trading, Property 45: 资金费率结算触发
 // Validates: Requirements 12.1
 func TestProperty_FundingRateSettlementTrigger(t *testing.T) {
@@ -22,7 +22,7 @@ type mockFuturesLeverageClient struct {
 	getAllPositionsFunc  func() ([]*api.Position, error)
 }
 
-func (m *mockFuturesLeverageClient) GetAccountInfo() (*api.FuturesAccountInfo, error) {
+func (m *mockFuturesLeverageClient) GetFuturesAccountInfo() (*api.FuturesAccountInfo, error) {
 	return nil, nil
 }
 
@@ -38,6 +38,10 @@ func (m *mockFuturesLeverageClient) GetPrice(symbol string) (*api.Price, error)
 	return nil, nil
 }
 
+func (m *mockFuturesLeverageClient) GetBookTicker(symbol string) (*api.BookTicker, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
 func (m *mockFuturesLeverageClient) GetKlines(symbol string, interval string, limit int) ([]*api.Kline, error) {
 	return nil, nil
 }
@@ -50,6 +54,10 @@ func (m *mockFuturesLeverageClient) GetFundingRateHistory(symbol string, startTi
 	return nil, nil
 }
 
+func (m *mockFuturesLeverageClient) GetExchangeSymbols() ([]*api.ExchangeSymbol, error) {
+	return nil, nil
+}
+
 func (m *mockFuturesLeverageClient) SetLeverage(symbol string, leverage int) (*api.LeverageResponse, error) {
 	if m.setLeverageFunc != nil {
 		return m.setLeverageFunc(symbol, leverage)
@@ -86,6 +94,10 @@ func (m *mockFuturesLeverageClient) CancelOrder(symbol string, orderID int64) (*
 	return nil, nil
 }
 
+func (m *mockFuturesLeverageClient) BulkCancelFuturesOrders(symbol string) ([]api.CancelResponse, error) {
+	return nil, nil
+}
+
 func (m *mockFuturesLeverageClient) GetOrder(symbol string, orderID int64) (*api.FuturesOrder, error) {
 	return nil, nil
 }
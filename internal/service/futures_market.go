@@ -13,9 +13,14 @@ type FuturesMarketDataService interface {
 	GetMarkPrice(symbol string) (float64, error)
 	GetLastPrice(symbol string) (float64, error)
 	GetHistoricalData(symbol string, interval string, limit int) ([]*api.Kline, error)
+	GetVolume(symbol string, timeWindow time.Duration) (float64, error)
 	GetFundingRate(symbol string) (*api.FundingRate, error)
 	GetFundingRateHistory(symbol string, startTime, endTime int64) ([]*api.FundingRate, error)
 	SubscribeToMarkPrice(symbol string, callback func(float64)) error
+
+	// GetBestBidAsk returns the best bid and ask, from the book ticker cache when one is set
+	GetBestBidAsk(symbol string) (*BestBidAsk, error)
+	SetBookTickerCache(cache BookTickerCache)
 }
 
 // futuresMarketCache represents cached market data
@@ -48,6 +53,7 @@ type futuresMarketDataService struct {
 	cache        map[string]*futuresMarketCache
 	cacheTTL     map[string]time.Duration
 	cacheMutex   sync.RWMutex
+	bookTickers  BookTickerCache
 }
 
 // NewFuturesMarketDataService creates a new futures market data service
@@ -270,6 +276,23 @@ func (s *futuresMarketDataService) GetHistoricalData(symbol string, interval str
 	return klines, nil
 }
 
+// GetVolume retrieves the cumulative volume for a symbol within a time window
+func (s *futuresMarketDataService) GetVolume(symbol string, timeWindow time.Duration) (float64, error) {
+	if timeWindow <= 0 {
+		return 0, fmt.Errorf("time window must be greater than 0")
+	}
+	
+	interval, limit := volumeKlineRequest(timeWindow)
+	klines, err := s.GetHistoricalData(symbol, interval, limit)
+	if err != nil {
+		return 0, err
+	}
+	if len(klines) == 0 {
+		return 0, fmt.Errorf("no kline data available for %s", symbol)
+	}
+	return windowVolume(klines, timeWindow, time.Now()), nil
+}
+
 // GetFundingRate retrieves the current funding rate for a symbol with caching
 func (s *futuresMarketDataService) GetFundingRate(symbol string) (*api.FundingRate, error) {
 	if symbol == "" {
@@ -390,3 +413,22 @@ func (s *futuresMarketDataService) SubscribeToMarkPrice(symbol string, callback
 	// In a real implementation, this would use WebSocket connections
 	return fmt.Errorf("mark price subscription not implemented yet")
 }
+
+// SetBookTickerCache sets the stream-fed cache used for best bid/ask
+func (s *futuresMarketDataService) SetBookTickerCache(cache BookTickerCache) {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+	s.bookTickers = cache
+}
+
+// GetBestBidAsk returns the best bid and ask, polling REST when no book ticker cache is set
+func (s *futuresMarketDataService) GetBestBidAsk(symbol string) (*BestBidAsk, error) {
+	s.cacheMutex.RLock()
+	cache := s.bookTickers
+	s.cacheMutex.RUnlock()
+	
+	if cache != nil {
+		return cache.GetBestBidAsk(symbol)
+	}
+	return fetchBestBidAsk(s.client, symbol, time.Now())
+}
@@ -22,6 +22,7 @@ type mockFuturesClient struct {
 	balanceFunc             func() (*api.FuturesBalance, error)
 	createOrderFunc         func(*api.FuturesOrderRequest) (*api.FuturesOrderResponse, error)
 	cancelOrderFunc         func(string, int64) (*api.CancelResponse, error)
+	bulkCancelFunc          func(string) ([]api.CancelResponse, error)
 	getOrderFunc            func(string, int64) (*api.FuturesOrder, error)
 	getOpenOrdersFunc       func(string) ([]*api.FuturesOrder, error)
 	getPositionsFunc        func(string) ([]*api.Position, error)
@@ -42,6 +43,10 @@ func (m *mockFuturesClient) GetPrice(symbol string) (*api.Price, error) {
 	return nil, fmt.Errorf("not implemented")
 }
 
+func (m *mockFuturesClient) GetBookTicker(symbol string) (*api.BookTicker, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
 func (m *mockFuturesClient) GetKlines(symbol string, interval string, limit int) ([]*api.Kline, error) {
 	if m.klinesFunc != nil {
 		return m.klinesFunc(symbol, interval, limit)
@@ -63,7 +68,11 @@ func (m *mockFuturesClient) GetFundingRateHistory(symbol string, startTime, endT
 	return nil, fmt.Errorf("not implemented")
 }
 
-func (m *mockFuturesClient) GetAccountInfo() (*api.FuturesAccountInfo, error) {
+func (m *mockFuturesClient) GetExchangeSymbols() ([]*api.ExchangeSymbol, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockFuturesClient) GetFuturesAccountInfo() (*api.FuturesAccountInfo, error) {
 	if m.accountInfoFunc != nil {
 		return m.accountInfoFunc()
 	}
@@ -119,6 +128,13 @@ func (m *mockFuturesClient) CancelOrder(symbol string, orderID int64) (*api.Canc
 	return &api.CancelResponse{}, nil
 }
 
+func (m *mockFuturesClient) BulkCancelFuturesOrders(symbol string) ([]api.CancelResponse, error) {
+	if m.bulkCancelFunc != nil {
+		return m.bulkCancelFunc(symbol)
+	}
+	return nil, fmt.Errorf("not implemented")
+}
+
 func (m *mockFuturesClient) GetOrder(symbol string, orderID int64) (*api.FuturesOrder, error) {
 	if m.getOrderFunc != nil {
 		return m.getOrderFunc(symbol, orderID)
@@ -7,12 +7,18 @@ import (
 	"binance-trader/pkg/logger"
 	"fmt"
 	"math"
+	"sync"
+	"time"
 )
 
+// defaultPositionCacheTTL is how long a position snapshot is reused before querying the API again
+const defaultPositionCacheTTL = 2 * time.Second
+
 // FuturesPositionManager defines the interface for futures position management
 type FuturesPositionManager interface {
 	// Position queries
 	GetPosition(symbol string, positionSide api.PositionSide) (*api.Position, error)
+	GetPositionsBySymbol(symbol string) ([]*api.Position, error)
 	GetAllPositions() ([]*api.Position, error)
 	
 	// Position calculations
@@ -33,6 +39,18 @@ type futuresPositionManager struct {
 	client     api.FuturesClient
 	repository repository.FuturesPositionRepository
 	logger     logger.Logger
+
+	// Position snapshot cache keyed by symbol
+	mu        sync.RWMutex
+	snapshots map[string]*positionSnapshot
+	cacheTTL  time.Duration
+	now       func() time.Time
+}
+
+// positionSnapshot holds the positions of a symbol at a point in time
+type positionSnapshot struct {
+	positions []*api.Position
+	fetchedAt time.Time
 }
 
 // NewFuturesPositionManager creates a new futures position manager
@@ -45,11 +63,42 @@ func NewFuturesPositionManager(
 		client:     client,
 		repository: repository,
 		logger:     logger,
+		snapshots:  make(map[string]*positionSnapshot),
+		cacheTTL:   defaultPositionCacheTTL,
+		now:        time.Now,
 	}
 }
 
 // GetPosition retrieves a specific position
 func (m *futuresPositionManager) GetPosition(symbol string, positionSide api.PositionSide) (*api.Position, error) {
+	m.logger.Debug("Getting position", map[string]interface{}{
+		"symbol":        symbol,
+		"position_side": positionSide,
+	})
+	
+	positions, err := m.GetPositionsBySymbol(symbol)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Find the specific position side
+	for _, pos := range positions {
+		if pos.PositionSide == positionSide {
+			return pos, nil
+		}
+	}
+	
+	return nil, errors.NewTradingError(
+		errors.ErrPositionNotFound,
+		fmt.Sprintf("position not found for %s %s", symbol, positionSide),
+		0,
+		nil,
+	)
+}
+
+// GetPositionsBySymbol retrieves all position entries for a symbol (LONG and SHORT in hedge mode),
+// using the cached snapshot when it is still fresh
+func (m *futuresPositionManager) GetPositionsBySymbol(symbol string) ([]*api.Position, error) {
 	if symbol == "" {
 		return nil, errors.NewTradingError(
 			errors.ErrInvalidParameter,
@@ -59,12 +108,12 @@ func (m *futuresPositionManager) GetPosition(symbol string, positionSide api.Pos
 		)
 	}
 	
-	m.logger.Debug("Getting position", map[string]interface{}{
-		"symbol":        symbol,
-		"position_side": positionSide,
-	})
+	// Serve from cache if the snapshot is fresh
+	if positions, ok := m.getCachedPositions(symbol); ok {
+		return positions, nil
+	}
 	
-	// Get positions from API
+	// Query only this symbol from the API
 	positions, err := m.client.GetPositions(symbol)
 	if err != nil {
 		m.logger.Error("Failed to get position", map[string]interface{}{
@@ -74,26 +123,66 @@ func (m *futuresPositionManager) GetPosition(symbol string, positionSide api.Pos
 		return nil, fmt.Errorf("failed to get position: %w", err)
 	}
 	
-	// Find the specific position side
-	for _, pos := range positions {
-		if pos.PositionSide == positionSide {
-			// Save to repository
+	m.storePositions(map[string][]*api.Position{symbol: positions})
+	
+	return positions, nil
+}
+
+// getCachedPositions returns copies of the cached positions for a symbol if fresh
+func (m *futuresPositionManager) getCachedPositions(symbol string) ([]*api.Position, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	
+	snapshot, exists := m.snapshots[symbol]
+	if !exists || m.now().Sub(snapshot.fetchedAt) >= m.cacheTTL {
+		return nil, false
+	}
+	
+	positions := make([]*api.Position, 0, len(snapshot.positions))
+	for _, pos := range snapshot.positions {
+		posCopy := *pos
+		positions = append(positions, &posCopy)
+	}
+	return positions, true
+}
+
+// storePositions caches position snapshots per symbol and saves them to the repository
+func (m *futuresPositionManager) storePositions(bySymbol map[string][]*api.Position) {
+	fetchedAt := m.now()
+	
+	m.mu.Lock()
+	for symbol, positions := range bySymbol {
+		cached := make([]*api.Position, 0, len(positions))
+		for _, pos := range positions {
+			posCopy := *pos
+			cached = append(cached, &posCopy)
+		}
+		m.snapshots[symbol] = &positionSnapshot{
+			positions: cached,
+			fetchedAt: fetchedAt,
+		}
+	}
+	m.mu.Unlock()
+	
+	for symbol, positions := range bySymbol {
+		for _, pos := range positions {
 			if err := m.repository.SavePosition(pos); err != nil {
 				m.logger.Warn("Failed to save position to repository", map[string]interface{}{
 					"symbol": symbol,
 					"error":  err.Error(),
 				})
 			}
-			return pos, nil
 		}
 	}
-	
-	return nil, errors.NewTradingError(
-		errors.ErrPositionNotFound,
-		fmt.Sprintf("position not found for %s %s", symbol, positionSide),
-		0,
-		nil,
-	)
+}
+
+// groupPositionsBySymbol groups positions by their symbol
+func groupPositionsBySymbol(positions []*api.Position) map[string][]*api.Position {
+	bySymbol := make(map[string][]*api.Position)
+	for _, pos := range positions {
+		bySymbol[pos.Symbol] = append(bySymbol[pos.Symbol], pos)
+	}
+	return bySymbol
 }
 
 // GetAllPositions retrieves all positions
@@ -109,15 +198,8 @@ func (m *futuresPositionManager) GetAllPositions() ([]*api.Position, error) {
 		return nil, fmt.Errorf("failed to get all positions: %w", err)
 	}
 	
-	// Save all positions to repository
-	for _, pos := range positions {
-		if err := m.repository.SavePosition(pos); err != nil {
-			m.logger.Warn("Failed to save position to repository", map[string]interface{}{
-				"symbol": pos.Symbol,
-				"error":  err.Error(),
-			})
-		}
-	}
+	// Refresh snapshots and save all positions to repository
+	m.storePositions(groupPositionsBySymbol(positions))
 	
 	m.logger.Debug("Retrieved all positions", map[string]interface{}{
 		"count": len(positions),
@@ -246,15 +328,8 @@ func (m *futuresPositionManager) UpdatePosition(symbol string) error {
 		return fmt.Errorf("failed to update position: %w", err)
 	}
 	
-	// Save all positions for this symbol
-	for _, pos := range positions {
-		if err := m.repository.SavePosition(pos); err != nil {
-			m.logger.Warn("Failed to save position to repository", map[string]interface{}{
-				"symbol": symbol,
-				"error":  err.Error(),
-			})
-		}
-	}
+	// Refresh snapshot and save all positions for this symbol
+	m.storePositions(map[string][]*api.Position{symbol: positions})
 	
 	m.logger.Debug("Position updated", map[string]interface{}{
 		"symbol": symbol,
@@ -277,15 +352,8 @@ func (m *futuresPositionManager) UpdateAllPositions() error {
 		return fmt.Errorf("failed to update all positions: %w", err)
 	}
 	
-	// Save all positions
-	for _, pos := range positions {
-		if err := m.repository.SavePosition(pos); err != nil {
-			m.logger.Warn("Failed to save position to repository", map[string]interface{}{
-				"symbol": pos.Symbol,
-				"error":  err.Error(),
-			})
-		}
-	}
+	// Refresh snapshots and save all positions
+	m.storePositions(groupPositionsBySymbol(positions))
 	
 	m.logger.Debug("All positions updated", map[string]interface{}{
 		"count": len(positions),
@@ -4,7 +4,9 @@ import (
 	"binance-trader/internal/api"
 	"binance-trader/internal/repository"
 	"binance-trader/pkg/logger"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
@@ -17,7 +19,7 @@ type mockFuturesClientForPosition struct {
 	err       error
 }
 
-func (m *mockFuturesClientForPosition) GetAccountInfo() (*api.FuturesAccountInfo, error) {
+func (m *mockFuturesClientForPosition) GetFuturesAccountInfo() (*api.FuturesAccountInfo, error) {
 	return nil, nil
 }
 
@@ -33,6 +35,10 @@ func (m *mockFuturesClientForPosition) GetPrice(symbol string) (*api.Price, erro
 	return nil, nil
 }
 
+func (m *mockFuturesClientForPosition) GetBookTicker(symbol string) (*api.BookTicker, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
 func (m *mockFuturesClientForPosition) GetKlines(symbol string, interval string, limit int) ([]*api.Kline, error) {
 	return nil, nil
 }
@@ -45,6 +51,10 @@ func (m *mockFuturesClientForPosition) GetFundingRateHistory(symbol string, star
 	return nil, nil
 }
 
+func (m *mockFuturesClientForPosition) GetExchangeSymbols() ([]*api.ExchangeSymbol, error) {
+	return nil, nil
+}
+
 func (m *mockFuturesClientForPosition) SetLeverage(symbol string, leverage int) (*api.LeverageResponse, error) {
 	return nil, nil
 }
@@ -69,6 +79,10 @@ func (m *mockFuturesClientForPosition) CancelOrder(symbol string, orderID int64)
 	return nil, nil
 }
 
+func (m *mockFuturesClientForPosition) BulkCancelFuturesOrders(symbol string) ([]api.CancelResponse, error) {
+	return nil, nil
+}
+
 func (m *mockFuturesClientForPosition) GetOrder(symbol string, orderID int64) (*api.FuturesOrder, error) {
 	return nil, nil
 }
@@ -423,3 +437,100 @@ func TestProperty22_DualPositionSeparateDisplay(t *testing.T) {
 
 	properties.TestingRun(t)
 }
+
+// countingFuturesClientForPosition counts position API calls and records queried symbols
+type countingFuturesClientForPosition struct {
+	mockFuturesClientForPosition
+	calls   int
+	symbols []string
+}
+
+func (m *countingFuturesClientForPosition) GetPositions(symbol string) ([]*api.Position, error) {
+	m.calls++
+	m.symbols = append(m.symbols, symbol)
+	return m.mockFuturesClientForPosition.GetPositions(symbol)
+}
+
+func newHedgeModeTestPositions() []*api.Position {
+	return []*api.Position{
+		{Symbol: "BTCUSDT", PositionSide: api.PositionSideLong, PositionAmt: 0.5, EntryPrice: 50000},
+		{Symbol: "BTCUSDT", PositionSide: api.PositionSideShort, PositionAmt: -0.2, EntryPrice: 51000},
+		{Symbol: "ETHUSDT", PositionSide: api.PositionSideLong, PositionAmt: 1.0, EntryPrice: 3000},
+	}
+}
+
+func TestGetPositionsBySymbol_HedgeModeAndCacheHit(t *testing.T) {
+	mockClient := &countingFuturesClientForPosition{
+		mockFuturesClientForPosition: mockFuturesClientForPosition{positions: newHedgeModeTestPositions()},
+	}
+	repo := repository.NewMemoryFuturesPositionRepository()
+	testLogger, _ := logger.NewLogger(logger.Config{Level: "info", FilePath: "", EnableConsole: false})
+	manager := NewFuturesPositionManager(mockClient, repo, testLogger)
+
+	positions, err := manager.GetPositionsBySymbol("BTCUSDT")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Hedge mode must return both LONG and SHORT entries
+	if len(positions) != 2 {
+		t.Fatalf("Expected 2 positions, got %d", len(positions))
+	}
+	sides := map[api.PositionSide]bool{}
+	for _, pos := range positions {
+		if pos.Symbol != "BTCUSDT" {
+			t.Errorf("Expected BTCUSDT, got %s", pos.Symbol)
+		}
+		sides[pos.PositionSide] = true
+	}
+	if !sides[api.PositionSideLong] || !sides[api.PositionSideShort] {
+		t.Errorf("Expected LONG and SHORT entries, got %v", sides)
+	}
+
+	if mockClient.calls != 1 || mockClient.symbols[0] != "BTCUSDT" {
+		t.Fatalf("Expected one symbol-filtered API call, got %d calls for %v", mockClient.calls, mockClient.symbols)
+	}
+
+	// Fresh snapshot must be served without another API call
+	if _, err := manager.GetPosition("BTCUSDT", api.PositionSideShort); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if mockClient.calls != 1 {
+		t.Errorf("Expected cache hit, got %d API calls", mockClient.calls)
+	}
+}
+
+func TestGetPositionsBySymbol_StaleSnapshotRefetches(t *testing.T) {
+	mockClient := &countingFuturesClientForPosition{
+		mockFuturesClientForPosition: mockFuturesClientForPosition{positions: newHedgeModeTestPositions()},
+	}
+	repo := repository.NewMemoryFuturesPositionRepository()
+	testLogger, _ := logger.NewLogger(logger.Config{Level: "info", FilePath: "", EnableConsole: false})
+	manager := NewFuturesPositionManager(mockClient, repo, testLogger).(*futuresPositionManager)
+
+	current := time.Now()
+	manager.now = func() time.Time { return current }
+
+	if _, err := manager.GetPositionsBySymbol("ETHUSDT"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Cached copies must not be affected by callers mutating results
+	cached, _ := manager.GetPositionsBySymbol("ETHUSDT")
+	cached[0].PositionAmt = 99
+	again, _ := manager.GetPositionsBySymbol("ETHUSDT")
+	if again[0].PositionAmt != 1.0 {
+		t.Errorf("Expected cached position to be unchanged, got %f", again[0].PositionAmt)
+	}
+	if mockClient.calls != 1 {
+		t.Fatalf("Expected 1 API call before expiry, got %d", mockClient.calls)
+	}
+
+	current = current.Add(defaultPositionCacheTTL)
+	if _, err := manager.GetPositionsBySymbol("ETHUSDT"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if mockClient.calls != 2 {
+		t.Errorf("Expected stale snapshot to trigger API call, got %d calls", mockClient.calls)
+	}
+}
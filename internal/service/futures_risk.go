@@ -28,10 +28,18 @@ type FuturesRiskManager interface {
 	CheckLiquidationRisk(position *api.Position, markPrice float64) (bool, error)
 	CheckMarginSufficiency(symbol string, quantity float64, leverage int) error
 	CheckMaxPositionSize(symbol string, quantity float64) error
+	// CheckOrderNotional checks that the leveraged notional of an order, price * quantity * leverage,
+	// does not exceed the maximum position value
+	CheckOrderNotional(symbol string, price, quantity float64, leverage int) error
 	
 	// Risk monitoring
 	MonitorPositions() error
 	GetRiskMetrics() (*FuturesRiskMetrics, error)
+	// CheckAccountMargin snapshots the available margin of the account and warns when it is
+	// below MinMarginRatio of the wallet balance; it runs at startup
+	CheckAccountMargin() error
+	// AvailableMargin returns the available margin of the last account snapshot
+	AvailableMargin() float64
 	
 	// Limit management
 	UpdateLimits(limits *config.FuturesRiskConfig) error
@@ -40,11 +48,12 @@ type FuturesRiskManager interface {
 
 // futuresRiskManager implements FuturesRiskManager interface
 type futuresRiskManager struct {
-	limits         *config.FuturesRiskConfig
-	client         api.FuturesClient
-	positionMgr    FuturesPositionManager
-	logger         logger.Logger
-	mu             sync.RWMutex
+	limits          *config.FuturesRiskConfig
+	client          api.FuturesClient
+	positionMgr     FuturesPositionManager
+	logger          logger.Logger
+	availableMargin float64
+	mu              sync.RWMutex
 }
 
 // NewFuturesRiskManager creates a new futures risk manager
@@ -126,7 +135,7 @@ func (rm *futuresRiskManager) ValidateOrder(order *api.FuturesOrderRequest) erro
 	// Check if this would exceed max position value (only for opening positions)
 	if !order.ReduceOnly {
 		// Get current positions
-		positions, err := rm.client.GetPositions(order.Symbol)
+		positions, err := rm.getSymbolPositions(order.Symbol)
 		if err != nil {
 			rm.logger.Warn("Failed to get positions for risk check", map[string]interface{}{
 				"symbol": order.Symbol,
@@ -337,7 +346,7 @@ func (rm *futuresRiskManager) CheckMaxPositionSize(symbol string, quantity float
 	orderValue := quantity * price.Price
 	
 	// Get current positions
-	positions, err := rm.client.GetPositions(symbol)
+	positions, err := rm.getSymbolPositions(symbol)
 	if err != nil {
 		rm.logger.Warn("Failed to get positions for size check", map[string]interface{}{
 			"symbol": symbol,
@@ -385,6 +394,43 @@ func (rm *futuresRiskManager) CheckMaxPositionSize(symbol string, quantity float
 	return nil
 }
 
+// CheckOrderNotional checks the leveraged notional of an order against the maximum position value;
+// an unknown leverage below 1 counts as 1
+func (rm *futuresRiskManager) CheckOrderNotional(symbol string, price, quantity float64, leverage int) error {
+	if price <= 0 || quantity <= 0 {
+		return errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			"price and quantity must be greater than 0",
+			0,
+			nil,
+		)
+	}
+	if leverage < 1 {
+		leverage = 1
+	}
+	
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	
+	notional := price * quantity * float64(leverage)
+	if notional > rm.limits.MaxPositionValue {
+		rm.logger.Warn("Order notional exceeds position limit", map[string]interface{}{
+			"symbol":    symbol,
+			"notional":  notional,
+			"leverage":  leverage,
+			"max_value": rm.limits.MaxPositionValue,
+		})
+		return errors.NewTradingError(
+			errors.ErrMaxPositionExceeded,
+			fmt.Sprintf("order notional %.2f at %dx leverage exceeds maximum position limit %.2f", notional, leverage, rm.limits.MaxPositionValue),
+			0,
+			nil,
+		)
+	}
+	
+	return nil
+}
+
 // MonitorPositions monitors all positions for risk
 func (rm *futuresRiskManager) MonitorPositions() error {
 	rm.logger.Debug("Monitoring positions for risk", nil)
@@ -462,6 +508,42 @@ func (rm *futuresRiskManager) MonitorPositions() error {
 	return nil
 }
 
+// CheckAccountMargin snapshots the available margin of the account
+func (rm *futuresRiskManager) CheckAccountMargin() error {
+	account, err := rm.client.GetFuturesAccountInfo()
+	if err != nil {
+		rm.logger.Warn("Failed to get futures account for the margin snapshot", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to get account info: %w", err)
+	}
+	
+	rm.mu.Lock()
+	rm.availableMargin = account.AvailableBalance
+	minMarginRatio := rm.limits.MinMarginRatio
+	rm.mu.Unlock()
+	
+	fields := map[string]interface{}{
+		"available_margin": account.AvailableBalance,
+		"wallet_balance":   account.TotalWalletBalance,
+		"min_margin_ratio": minMarginRatio,
+	}
+	if account.AvailableBalance < minMarginRatio*account.TotalWalletBalance {
+		rm.logger.Warn("Available futures margin below min_margin_ratio of the wallet balance", fields)
+		return nil
+	}
+	
+	rm.logger.Info("Futures margin snapshot taken", fields)
+	return nil
+}
+
+// AvailableMargin returns the available margin of the last account snapshot
+func (rm *futuresRiskManager) AvailableMargin() float64 {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	return rm.availableMargin
+}
+
 // GetRiskMetrics calculates and returns current risk metrics
 func (rm *futuresRiskManager) GetRiskMetrics() (*FuturesRiskMetrics, error) {
 	rm.logger.Debug("Calculating risk metrics", nil)
@@ -631,3 +713,15 @@ func (rm *futuresRiskManager) GetCurrentLimits() *config.FuturesRiskConfig {
 		MaxAPICallsPerMin: rm.limits.MaxAPICallsPerMin,
 	}
 }
+
+// getSymbolPositions retrieves fresh positions for a single symbol. The position manager's
+// snapshot is refreshed first so that orders filled moments ago count against the limits.
+func (rm *futuresRiskManager) getSymbolPositions(symbol string) ([]*api.Position, error) {
+	if rm.positionMgr != nil {
+		if err := rm.positionMgr.UpdatePosition(symbol); err != nil {
+			return nil, err
+		}
+		return rm.positionMgr.GetPositionsBySymbol(symbol)
+	}
+	return rm.client.GetPositions(symbol)
+}
@@ -4,7 +4,9 @@ import (
 	"binance-trader/internal/api"
 	"binance-trader/internal/config"
 	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
 	"binance-trader/pkg/logger"
+	"fmt"
 	"testing"
 
 	"github.com/leanovate/gopter"
@@ -18,12 +20,20 @@ type mockFuturesPositionManager struct {
 	calculateLiquidationPriceFunc func(position *api.Position) (float64, error)
 	calculateMarginRatioFunc      func(position *api.Position) (float64, error)
 	getAllPositionsFunc           func() ([]*api.Position, error)
+	getPositionsBySymbolFunc      func(symbol string) ([]*api.Position, error)
 }
 
 func (m *mockFuturesPositionManager) GetPosition(symbol string, positionSide api.PositionSide) (*api.Position, error) {
 	return nil, nil
 }
 
+func (m *mockFuturesPositionManager) GetPositionsBySymbol(symbol string) ([]*api.Position, error) {
+	if m.getPositionsBySymbolFunc != nil {
+		return m.getPositionsBySymbolFunc(symbol)
+	}
+	return []*api.Position{}, nil
+}
+
 func (m *mockFuturesPositionManager) GetAllPositions() ([]*api.Position, error) {
 	if m.getAllPositionsFunc != nil {
 		return m.getAllPositionsFunc()
@@ -346,6 +356,9 @@ func TestProperty_TotalPositionLimit(t *testing.T) {
 				},
 			}
 
+			// Risk checks read symbol positions through the position manager
+			mockPosMgr.getPositionsBySymbolFunc = mockClient.getPositionsFunc
+
 			// Create risk manager with specified max position value
 			limits := &config.FuturesRiskConfig{
 				MaxOrderValue:     maxPositionValue,
@@ -429,3 +442,113 @@ func TestProperty_AbnormalFundingRateLogging(t *testing.T) {
 
 	properties.TestingRun(t)
 }
+
+func TestFuturesRiskManager_CheckOrderNotional(t *testing.T) {
+	rm := NewFuturesRiskManager(&config.FuturesRiskConfig{MaxPositionValue: 100000}, &mockFuturesClient{}, &mockFuturesPositionManager{}, &mockLogger{})
+
+	tests := []struct {
+		name            string
+		price, quantity float64
+		leverage        int
+		wantErr         bool
+		errType         errors.ErrorType
+	}{
+		{"within the limit", 50000, 0.1, 10, false, 0},
+		{"exactly at the limit", 50000, 0.2, 10, false, 0},
+		{"leverage pushes it over", 50000, 0.2, 11, true, errors.ErrMaxPositionExceeded},
+		{"unknown leverage counts as 1", 50000, 2, 0, false, 0},
+		{"unleveraged order over the limit", 50000, 2.5, 1, true, errors.ErrMaxPositionExceeded},
+		{"missing price", 0, 0.1, 10, true, errors.ErrInvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rm.CheckOrderNotional("BTCUSDT", tt.price, tt.quantity, tt.leverage)
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("CheckOrderNotional() error = %v", err)
+				}
+				return
+			}
+			if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != tt.errType {
+				t.Errorf("CheckOrderNotional() error = %v, want type %v", err, tt.errType)
+			}
+		})
+	}
+}
+
+func TestFuturesRiskManager_CheckAccountMargin(t *testing.T) {
+	tests := []struct {
+		name      string
+		available float64
+		wantWarn  bool
+	}{
+		{"enough available margin", 500, false},
+		{"exactly at the ratio", 50, false},
+		{"below the ratio", 49, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockFuturesClient{
+				accountInfoFunc: func() (*api.FuturesAccountInfo, error) {
+					return &api.FuturesAccountInfo{TotalWalletBalance: 1000, AvailableBalance: tt.available}, nil
+				},
+			}
+			log := &mockLoggerCapture{}
+			rm := NewFuturesRiskManager(&config.FuturesRiskConfig{MinMarginRatio: 0.05}, client, &mockFuturesPositionManager{}, log)
+
+			if err := rm.CheckAccountMargin(); err != nil {
+				t.Fatalf("CheckAccountMargin() error = %v", err)
+			}
+			if rm.AvailableMargin() != tt.available {
+				t.Errorf("AvailableMargin() = %v, want %v", rm.AvailableMargin(), tt.available)
+			}
+			warned := false
+			for _, entry := range log.entries {
+				warned = warned || entry["level"] == "warn"
+			}
+			if warned != tt.wantWarn {
+				t.Errorf("warned = %v, want %v (log %v)", warned, tt.wantWarn, log.entries)
+			}
+		})
+	}
+
+	client := &mockFuturesClient{
+		accountInfoFunc: func() (*api.FuturesAccountInfo, error) { return nil, fmt.Errorf("connection refused") },
+	}
+	rm := NewFuturesRiskManager(nil, client, &mockFuturesPositionManager{}, &mockLogger{})
+	if err := rm.CheckAccountMargin(); err == nil || rm.AvailableMargin() != 0 {
+		t.Errorf("CheckAccountMargin() = %v with snapshot %v, want an error and no snapshot", err, rm.AvailableMargin())
+	}
+}
+
+func TestFuturesRiskManager_ValidateOrderReadsFreshPositions(t *testing.T) {
+	client := &countingFuturesClientForPosition{}
+	testLogger, _ := logger.NewLogger(logger.Config{Level: "info", FilePath: "", EnableConsole: false})
+	positionMgr := NewFuturesPositionManager(client, repository.NewMemoryFuturesPositionRepository(), testLogger)
+	limits := &config.FuturesRiskConfig{MaxOrderValue: 10000, MaxPositionValue: 15000, MaxLeverage: 20}
+	rm := NewFuturesRiskManager(limits, &mockFuturesClient{}, positionMgr, testLogger)
+
+	order := &api.FuturesOrderRequest{
+		Symbol:       "BTCUSDT",
+		Side:         api.OrderSideBuy,
+		PositionSide: api.PositionSideLong,
+		Type:         api.OrderTypeLimit,
+		Quantity:     0.2,
+		Price:        50000,
+	}
+	if err := rm.ValidateOrder(order); err != nil {
+		t.Fatalf("First open should pass, got %v", err)
+	}
+
+	// The first order fills within the position cache TTL
+	client.positions = []*api.Position{
+		{Symbol: "BTCUSDT", PositionSide: api.PositionSideLong, PositionAmt: 0.2, EntryPrice: 50000},
+	}
+	err := rm.ValidateOrder(order)
+	if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrMaxPositionExceeded {
+		t.Errorf("Second open should exceed the position limit, got %v", err)
+	}
+	if client.calls != 2 {
+		t.Errorf("Expected each validation to fetch positions, got %d calls", client.calls)
+	}
+}
@@ -2,26 +2,61 @@ package service
 
 import (
 	"binance-trader/internal/api"
+	"binance-trader/internal/config"
 	"binance-trader/internal/repository"
 	"binance-trader/pkg/errors"
 	"binance-trader/pkg/logger"
+	"binance-trader/pkg/timeutil"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 )
 
+// TrailingStopMode selects where futures trailing stops are tracked
+type TrailingStopMode string
+
+const (
+	// TrailingStopModeLocal follows the mark price locally and closes the position with a
+	// reduce-only market order once the price retraces by the callback rate
+	TrailingStopModeLocal TrailingStopMode = "local"
+	// TrailingStopModeNative places a TRAILING_STOP_MARKET order that the exchange tracks
+	TrailingStopModeNative TrailingStopMode = "native"
+)
+
+// DefaultFuturesTrailingCheckInterval is how often local trailing stops follow the mark price
+// when futures.stop_loss.check_interval_ms is not set
+const DefaultFuturesTrailingCheckInterval = time.Second
+
 // FuturesStopLossService defines the interface for futures stop loss and take profit operations
 type FuturesStopLossService interface {
 	// Set stop loss and take profit
 	SetStopLoss(symbol string, positionSide api.PositionSide, quantity float64, stopPrice float64) (*repository.StopOrder, error)
 	SetTakeProfit(symbol string, positionSide api.PositionSide, quantity float64, targetPrice float64) (*repository.StopOrder, error)
+	SetTakeProfitLadder(symbol string, positionSide api.PositionSide, quantity float64, levels []TPLevel) ([]*repository.StopOrder, error)
 	SetStopLossTakeProfit(symbol string, positionSide api.PositionSide, quantity float64, stopPrice, targetPrice float64) (*repository.StopOrderPair, error)
 	SetTrailingStop(symbol string, positionSide api.PositionSide, quantity float64, callbackRate float64) (*repository.TrailingStopOrder, error)
 
 	// Manage stop orders
-	CancelStopOrder(orderID string) error
+	CancelStopOrder(orderID, symbol string) (*CancelledStopOrder, error)
 	GetActiveStopOrders(symbol string) ([]*repository.StopOrder, error)
 	UpdateTrailingStop(orderID string, newCallbackRate float64) error
+
+	// SetTrailingStopConfig sets the callback rate bounds and where new trailing stops are
+	// tracked; native mode places them through client, and a nil client keeps them local
+	SetTrailingStopConfig(cfg *config.FuturesStopLossConfig, client api.FuturesClient)
+
+	// CheckTrailingStops moves the locally tracked trailing stops with the mark price and
+	// closes the positions whose stop was hit
+	CheckTrailingStops() error
+
+	// CheckTakeProfits closes the take profit orders, ladder levels included, whose target
+	// the mark price has reached
+	CheckTakeProfits() error
+
+	// Scheduled trailing stop and take profit check
+	StartMonitoring(checkInterval time.Duration) error
+	StopMonitoring() error
 }
 
 // futuresStopLossService implements the FuturesStopLossService interface
@@ -31,6 +66,20 @@ type futuresStopLossService struct {
 	futuresTradingService FuturesTradingService
 	futuresMarketService  FuturesMarketDataService
 	logger            logger.Logger
+
+	// Trailing stops
+	client          api.FuturesClient // Places native trailing stops
+	trailingMode    TrailingStopMode
+	minCallbackRate float64 // 0 means no minimum
+	maxCallbackRate float64 // 0 means no maximum
+
+	mu              sync.Mutex
+	trailingSymbols map[string]bool // Symbols that have had locally tracked trailing stops
+
+	// Monitoring
+	stopChan     chan struct{}
+	monitoringMu sync.Mutex
+	isMonitoring bool
 }
 
 // NewFuturesStopLossService creates a new futures stop loss service instance
@@ -47,7 +96,43 @@ func NewFuturesStopLossService(
 		futuresTradingService: futuresTradingService,
 		futuresMarketService:  futuresMarketService,
 		logger:                log,
+		trailingMode:          TrailingStopModeLocal,
+		trailingSymbols:       make(map[string]bool),
+	}
+}
+
+// SetTrailingStopConfig sets the callback rate bounds and the trailing stop mode
+func (s *futuresStopLossService) SetTrailingStopConfig(cfg *config.FuturesStopLossConfig, client api.FuturesClient) {
+	s.minCallbackRate = cfg.MinCallbackRate
+	s.maxCallbackRate = cfg.MaxCallbackRate
+	s.trailingMode = TrailingStopModeLocal
+	s.client = nil
+
+	if TrailingStopMode(cfg.TrailingMode) != TrailingStopModeNative {
+		return
+	}
+	if client == nil {
+		s.logger.Warn("Native trailing stops need the futures client, tracking them locally", nil)
+		return
 	}
+	s.trailingMode = TrailingStopModeNative
+	s.client = client
+}
+
+// validateCallbackRate checks a trailing stop callback rate against the configured bounds
+func (s *futuresStopLossService) validateCallbackRate(callbackRate float64) error {
+	var message string
+	switch {
+	case callbackRate <= 0:
+		message = "callback rate must be greater than 0"
+	case s.minCallbackRate > 0 && callbackRate < s.minCallbackRate:
+		message = fmt.Sprintf("callback rate %.2f%% is below the minimum of %.2f%%", callbackRate, s.minCallbackRate)
+	case s.maxCallbackRate > 0 && callbackRate > s.maxCallbackRate:
+		message = fmt.Sprintf("callback rate %.2f%% exceeds the maximum of %.2f%%", callbackRate, s.maxCallbackRate)
+	default:
+		return nil
+	}
+	return errors.NewTradingError(errors.ErrInvalidParameter, message, 0, nil)
 }
 
 // SetStopLoss sets a stop loss order for a futures position
@@ -71,13 +156,14 @@ func (s *futuresStopLossService) SetStopLoss(symbol string, positionSide api.Pos
 
 	// Create stop order
 	stopOrder := &repository.StopOrder{
-		OrderID:   generateFuturesStopOrderID("FSL"),
-		Symbol:    symbol,
-		Position:  quantity,
-		StopPrice: stopPrice,
-		Type:      repository.StopOrderTypeStopLoss,
-		Status:    repository.StopOrderStatusActive,
-		CreatedAt: time.Now().Unix(),
+		OrderID:      generateFuturesStopOrderID("FSL"),
+		Symbol:       symbol,
+		PositionSide: string(positionSide),
+		Position:     quantity,
+		StopPrice:    stopPrice,
+		Type:         repository.StopOrderTypeStopLoss,
+		Status:       repository.StopOrderStatusActive,
+		CreatedAt:    timeutil.NowMillis(),
 	}
 
 	// Save to repository
@@ -147,13 +233,14 @@ func (s *futuresStopLossService) SetTakeProfit(symbol string, positionSide api.P
 
 	// Create take profit order
 	takeProfitOrder := &repository.StopOrder{
-		OrderID:   generateFuturesStopOrderID("FTP"),
-		Symbol:    symbol,
-		Position:  quantity,
-		StopPrice: targetPrice,
-		Type:      repository.StopOrderTypeTakeProfit,
-		Status:    repository.StopOrderStatusActive,
-		CreatedAt: time.Now().Unix(),
+		OrderID:      generateFuturesStopOrderID("FTP"),
+		Symbol:       symbol,
+		PositionSide: string(positionSide),
+		Position:     quantity,
+		StopPrice:    targetPrice,
+		Type:         repository.StopOrderTypeTakeProfit,
+		Status:       repository.StopOrderStatusActive,
+		CreatedAt:    timeutil.NowMillis(),
 	}
 
 	// Save to repository
@@ -202,6 +289,88 @@ func (s *futuresStopLossService) SetTakeProfit(symbol string, positionSide api.P
 	return takeProfitOrder, nil
 }
 
+// SetTakeProfitLadder registers one take profit order per level for a futures position.
+// Long ladders must have ascending prices and short ladders descending prices.
+func (s *futuresStopLossService) SetTakeProfitLadder(symbol string, positionSide api.PositionSide, quantity float64, levels []TPLevel) ([]*repository.StopOrder, error) {
+	// Validate input parameters
+	if symbol == "" {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "symbol cannot be empty", 0, nil)
+	}
+
+	if quantity <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "quantity must be greater than 0", 0, nil)
+	}
+
+	if positionSide != api.PositionSideLong && positionSide != api.PositionSideShort {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "position side must be LONG or SHORT", 0, nil)
+	}
+
+	if err := validateTakeProfitLadder(levels, positionSide == api.PositionSideLong); err != nil {
+		return nil, err
+	}
+
+	// Create and save one take profit order per level
+	orders := make([]*repository.StopOrder, 0, len(levels))
+	for _, level := range levels {
+		takeProfitOrder := &repository.StopOrder{
+			OrderID:      generateFuturesStopOrderID("FTP"),
+			Symbol:       symbol,
+			PositionSide: string(positionSide),
+			Position:     quantity * level.Portion / 100,
+			StopPrice:    level.Price,
+			Type:         repository.StopOrderTypeTakeProfit,
+			Status:       repository.StopOrderStatusActive,
+			CreatedAt:    timeutil.NowMillis(),
+		}
+
+		if err := s.stopOrderRepo.SaveStopOrder(takeProfitOrder); err != nil {
+			s.logger.LogError(err, map[string]interface{}{
+				"operation":     "set_futures_take_profit_ladder",
+				"symbol":        symbol,
+				"position_side": positionSide,
+				"target_price":  level.Price,
+			})
+			// Rollback levels saved so far
+			for _, saved := range orders {
+				s.stopOrderRepo.DeleteStopOrder(saved.OrderID)
+			}
+			return nil, err
+		}
+
+		orders = append(orders, takeProfitOrder)
+	}
+
+	// Register trigger conditions based on position side
+	operator := OperatorGreaterEqual
+	if positionSide == api.PositionSideShort {
+		operator = OperatorLessEqual
+	}
+
+	for _, order := range orders {
+		condition := &TriggerCondition{
+			Type:     TriggerTypePrice,
+			Operator: operator,
+			Value:    order.StopPrice,
+		}
+
+		if err := s.triggerEngine.RegisterCondition(order.OrderID, condition); err != nil {
+			s.logger.Warn("Failed to register trigger condition", map[string]interface{}{
+				"order_id": order.OrderID,
+				"error":    err.Error(),
+			})
+		}
+	}
+
+	s.logger.Info("Futures take profit ladder created", map[string]interface{}{
+		"symbol":        symbol,
+		"position_side": positionSide,
+		"quantity":      quantity,
+		"levels":        len(orders),
+	})
+
+	return orders, nil
+}
+
 // SetStopLossTakeProfit sets both stop loss and take profit orders as a pair
 func (s *futuresStopLossService) SetStopLossTakeProfit(symbol string, positionSide api.PositionSide, quantity float64, stopPrice, targetPrice float64) (*repository.StopOrderPair, error) {
 	// Validate input parameters
@@ -227,24 +396,26 @@ func (s *futuresStopLossService) SetStopLossTakeProfit(symbol string, positionSi
 
 	// Create stop loss order
 	stopLossOrder := &repository.StopOrder{
-		OrderID:   generateFuturesStopOrderID("FSL"),
-		Symbol:    symbol,
-		Position:  quantity,
-		StopPrice: stopPrice,
-		Type:      repository.StopOrderTypeStopLoss,
-		Status:    repository.StopOrderStatusActive,
-		CreatedAt: time.Now().Unix(),
+		OrderID:      generateFuturesStopOrderID("FSL"),
+		Symbol:       symbol,
+		PositionSide: string(positionSide),
+		Position:     quantity,
+		StopPrice:    stopPrice,
+		Type:         repository.StopOrderTypeStopLoss,
+		Status:       repository.StopOrderStatusActive,
+		CreatedAt:    timeutil.NowMillis(),
 	}
 
 	// Create take profit order
 	takeProfitOrder := &repository.StopOrder{
-		OrderID:   generateFuturesStopOrderID("FTP"),
-		Symbol:    symbol,
-		Position:  quantity,
-		StopPrice: targetPrice,
-		Type:      repository.StopOrderTypeTakeProfit,
-		Status:    repository.StopOrderStatusActive,
-		CreatedAt: time.Now().Unix(),
+		OrderID:      generateFuturesStopOrderID("FTP"),
+		Symbol:       symbol,
+		PositionSide: string(positionSide),
+		Position:     quantity,
+		StopPrice:    targetPrice,
+		Type:         repository.StopOrderTypeTakeProfit,
+		Status:       repository.StopOrderStatusActive,
+		CreatedAt:    timeutil.NowMillis(),
 	}
 
 	// Create order pair
@@ -338,8 +509,8 @@ func (s *futuresStopLossService) SetTrailingStop(symbol string, positionSide api
 		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "quantity must be greater than 0", 0, nil)
 	}
 
-	if callbackRate <= 0 {
-		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "callback rate must be greater than 0", 0, nil)
+	if err := s.validateCallbackRate(callbackRate); err != nil {
+		return nil, err
 	}
 
 	if positionSide != api.PositionSideLong && positionSide != api.PositionSideShort {
@@ -377,8 +548,18 @@ func (s *futuresStopLossService) SetTrailingStop(symbol string, positionSide api
 		HighestPrice:     extremePrice,
 		CurrentStopPrice: initialStopPrice,
 		Status:           repository.StopOrderStatusActive,
-		CreatedAt:        time.Now().Unix(),
-		LastUpdatedAt:    time.Now().Unix(),
+		CreatedAt:        timeutil.NowMillis(),
+		LastUpdatedAt:    timeutil.NowMillis(),
+		PositionSide:     string(positionSide),
+	}
+
+	// In native mode the exchange follows the price; the local order only records it
+	if s.trailingMode == TrailingStopModeNative {
+		exchangeOrderID, err := s.placeNativeTrailingStop(symbol, positionSide, quantity, callbackRate)
+		if err != nil {
+			return nil, err
+		}
+		trailingStopOrder.ExchangeOrderID = exchangeOrderID
 	}
 
 	// Save to repository
@@ -401,29 +582,73 @@ func (s *futuresStopLossService) SetTrailingStop(symbol string, positionSide api
 		"callback_rate":  callbackRate,
 		"extreme_price":  extremePrice,
 		"initial_stop":   initialStopPrice,
+		"mode":           string(s.trailingMode),
+		"exchange_order": trailingStopOrder.ExchangeOrderID,
 	})
 
+	if trailingStopOrder.ExchangeOrderID == 0 {
+		s.mu.Lock()
+		s.trailingSymbols[symbol] = true
+		s.mu.Unlock()
+	}
+
 	return trailingStopOrder, nil
 }
 
-// CancelStopOrder cancels a stop order
-func (s *futuresStopLossService) CancelStopOrder(orderID string) error {
+// placeNativeTrailingStop places a reduce-only TRAILING_STOP_MARKET order on the opposite side
+// of the position, activated at once, and returns its exchange order ID
+func (s *futuresStopLossService) placeNativeTrailingStop(symbol string, positionSide api.PositionSide, quantity, callbackRate float64) (int64, error) {
+	side := api.OrderSideSell
+	if positionSide == api.PositionSideShort {
+		side = api.OrderSideBuy
+	}
+
+	response, err := s.client.CreateOrder(&api.FuturesOrderRequest{
+		Symbol:       symbol,
+		Side:         side,
+		PositionSide: positionSide,
+		Type:         api.OrderTypeTrailingStopMarket,
+		Quantity:     quantity,
+		ReduceOnly:   true,
+		CallbackRate: callbackRate,
+	})
+	if err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation":     "place_native_trailing_stop",
+			"symbol":        symbol,
+			"position_side": positionSide,
+			"quantity":      quantity,
+			"callback_rate": callbackRate,
+		})
+		return 0, err
+	}
+	return response.OrderID, nil
+}
+
+// CancelStopOrder cancels a stop order; a non-empty symbol must match the order's symbol.
+// The cancelled order is returned so callers can show what was cancelled.
+func (s *futuresStopLossService) CancelStopOrder(orderID, symbol string) (*CancelledStopOrder, error) {
 	// Validate input
 	if orderID == "" {
-		return errors.NewTradingError(errors.ErrInvalidParameter, "order ID cannot be empty", 0, nil)
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "order ID cannot be empty", 0, nil)
 	}
 
 	// Try to find as regular stop order
 	stopOrder, err := s.stopOrderRepo.FindStopOrderByID(orderID)
 	if err == nil {
+		if err := checkOrderSymbol(orderID, stopOrder.Symbol, symbol); err != nil {
+			return nil, err
+		}
+
 		// Update status to cancelled
 		if err := s.stopOrderRepo.UpdateStopOrderStatus(orderID, repository.StopOrderStatusCancelled, 0, 0); err != nil {
 			s.logger.LogError(err, map[string]interface{}{
 				"operation": "cancel_futures_stop_order",
 				"order_id":  orderID,
 			})
-			return err
+			return nil, err
 		}
+		stopOrder.Status = repository.StopOrderStatusCancelled
 
 		// Unregister trigger condition
 		s.triggerEngine.UnregisterCondition(orderID)
@@ -433,12 +658,31 @@ func (s *futuresStopLossService) CancelStopOrder(orderID string) error {
 			"symbol":   stopOrder.Symbol,
 		})
 
-		return nil
+		return &CancelledStopOrder{StopOrder: stopOrder}, nil
 	}
 
 	// Try to find as trailing stop order
 	trailingOrder, err := s.stopOrderRepo.FindTrailingStopOrderByID(orderID)
 	if err == nil {
+		if err := checkOrderSymbol(orderID, trailingOrder.Symbol, symbol); err != nil {
+			return nil, err
+		}
+
+		// A native trailing stop is cancelled on the exchange first so it cannot fire later
+		if trailingOrder.ExchangeOrderID != 0 && trailingOrder.Status == repository.StopOrderStatusActive {
+			if s.client == nil {
+				return nil, errors.NewTradingError(errors.ErrInvalidParameter, "native trailing stop cannot be cancelled without the futures client", 0, nil)
+			}
+			if _, err := s.client.CancelOrder(trailingOrder.Symbol, trailingOrder.ExchangeOrderID); err != nil {
+				s.logger.LogError(err, map[string]interface{}{
+					"operation":         "cancel_native_trailing_stop",
+					"order_id":          orderID,
+					"exchange_order_id": trailingOrder.ExchangeOrderID,
+				})
+				return nil, err
+			}
+		}
+
 		// Update status to cancelled
 		trailingOrder.Status = repository.StopOrderStatusCancelled
 		if err := s.stopOrderRepo.UpdateTrailingStopOrder(trailingOrder); err != nil {
@@ -446,7 +690,7 @@ func (s *futuresStopLossService) CancelStopOrder(orderID string) error {
 				"operation": "cancel_futures_stop_order",
 				"order_id":  orderID,
 			})
-			return err
+			return nil, err
 		}
 
 		s.logger.Info("Futures trailing stop order cancelled", map[string]interface{}{
@@ -454,11 +698,11 @@ func (s *futuresStopLossService) CancelStopOrder(orderID string) error {
 			"symbol":   trailingOrder.Symbol,
 		})
 
-		return nil
+		return &CancelledStopOrder{TrailingOrder: trailingOrder}, nil
 	}
 
 	// Order not found
-	return errors.NewTradingError(errors.ErrStopOrderNotFound, "stop order not found", 0, nil)
+	return nil, errors.NewTradingError(errors.ErrStopOrderNotFound, "stop order not found", 0, nil)
 }
 
 // GetActiveStopOrders retrieves all active stop orders for a symbol
@@ -493,8 +737,8 @@ func (s *futuresStopLossService) UpdateTrailingStop(orderID string, newCallbackR
 		return errors.NewTradingError(errors.ErrInvalidParameter, "order ID cannot be empty", 0, nil)
 	}
 
-	if newCallbackRate <= 0 {
-		return errors.NewTradingError(errors.ErrInvalidParameter, "callback rate must be greater than 0", 0, nil)
+	if err := s.validateCallbackRate(newCallbackRate); err != nil {
+		return err
 	}
 
 	// Find trailing stop order
@@ -507,10 +751,18 @@ func (s *futuresStopLossService) UpdateTrailingStop(orderID string, newCallbackR
 		return err
 	}
 
-	// Update callback rate and recalculate stop price
+	if trailingOrder.ExchangeOrderID != 0 {
+		return errors.NewTradingError(errors.ErrInvalidParameter, "a native trailing stop cannot be changed, cancel it and set a new one", 0, nil)
+	}
+
+	// Update callback rate and recalculate stop price from the extreme price
 	trailingOrder.TrailPercent = newCallbackRate
-	trailingOrder.CurrentStopPrice = trailingOrder.HighestPrice * (1 - newCallbackRate/100)
-	trailingOrder.LastUpdatedAt = time.Now().Unix()
+	if api.PositionSide(trailingOrder.PositionSide) == api.PositionSideShort {
+		trailingOrder.CurrentStopPrice = trailingOrder.HighestPrice * (1 + newCallbackRate/100)
+	} else {
+		trailingOrder.CurrentStopPrice = trailingOrder.HighestPrice * (1 - newCallbackRate/100)
+	}
+	trailingOrder.LastUpdatedAt = timeutil.NowMillis()
 
 	// Save updated order
 	if err := s.stopOrderRepo.UpdateTrailingStopOrder(trailingOrder); err != nil {
@@ -551,7 +803,7 @@ func (s *futuresStopLossService) UpdateTrailingStopPrice(orderID string, positio
 		if currentPrice > trailingOrder.HighestPrice {
 			trailingOrder.HighestPrice = currentPrice
 			trailingOrder.CurrentStopPrice = currentPrice * (1 - trailingOrder.TrailPercent/100)
-			trailingOrder.LastUpdatedAt = time.Now().Unix()
+			trailingOrder.LastUpdatedAt = timeutil.NowMillis()
 			updated = true
 
 			// Save updated order
@@ -588,7 +840,7 @@ func (s *futuresStopLossService) UpdateTrailingStopPrice(orderID string, positio
 		if currentPrice < trailingOrder.HighestPrice || trailingOrder.HighestPrice == 0 {
 			trailingOrder.HighestPrice = currentPrice // Using HighestPrice field to store lowest for shorts
 			trailingOrder.CurrentStopPrice = currentPrice * (1 + trailingOrder.TrailPercent/100)
-			trailingOrder.LastUpdatedAt = time.Now().Unix()
+			trailingOrder.LastUpdatedAt = timeutil.NowMillis()
 			updated = true
 
 			// Save updated order
@@ -625,6 +877,261 @@ func (s *futuresStopLossService) UpdateTrailingStopPrice(orderID string, positio
 	return updated, nil
 }
 
+// CheckTrailingStops updates every locally tracked trailing stop with the current mark price
+func (s *futuresStopLossService) CheckTrailingStops() error {
+	s.mu.Lock()
+	symbols := make([]string, 0, len(s.trailingSymbols))
+	for symbol := range s.trailingSymbols {
+		symbols = append(symbols, symbol)
+	}
+	s.mu.Unlock()
+	sort.Strings(symbols)
+
+	for _, symbol := range symbols {
+		orders, err := s.stopOrderRepo.FindActiveTrailingStopOrders(symbol)
+		if err != nil {
+			return err
+		}
+
+		var local []*repository.TrailingStopOrder
+		for _, order := range orders {
+			if order.ExchangeOrderID == 0 && order.PositionSide != "" {
+				local = append(local, order)
+			}
+		}
+		if len(local) == 0 {
+			continue
+		}
+
+		markPrice, err := s.futuresMarketService.GetMarkPrice(symbol)
+		if err != nil {
+			s.logger.Warn("Failed to get mark price for trailing stops", map[string]interface{}{
+				"symbol": symbol,
+				"error":  err.Error(),
+			})
+			continue
+		}
+
+		// Failures are logged by UpdateTrailingStopPrice; the other orders are still checked
+		for _, order := range local {
+			s.UpdateTrailingStopPrice(order.OrderID, api.PositionSide(order.PositionSide), markPrice)
+		}
+	}
+
+	return nil
+}
+
+// CheckTakeProfits executes every active take profit order whose target the mark price has reached
+func (s *futuresStopLossService) CheckTakeProfits() error {
+	activeOrders, err := s.stopOrderRepo.FindStopOrdersByStatus(repository.StopOrderStatusActive)
+	if err != nil {
+		return err
+	}
+
+	// Orders without a position side cannot tell which way their target is reached
+	var takeProfits []*repository.StopOrder
+	for _, order := range activeOrders {
+		if order.Type == repository.StopOrderTypeTakeProfit && order.PositionSide != "" {
+			takeProfits = append(takeProfits, order)
+		}
+	}
+
+	// Nearest targets first, so a gap through several ladder levels closes them in order
+	sort.SliceStable(takeProfits, func(i, j int) bool {
+		a, b := takeProfits[i], takeProfits[j]
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		if a.PositionSide != b.PositionSide {
+			return a.PositionSide < b.PositionSide
+		}
+		if api.PositionSide(a.PositionSide) == api.PositionSideShort {
+			return a.StopPrice > b.StopPrice
+		}
+		return a.StopPrice < b.StopPrice
+	})
+
+	markPrices := make(map[string]float64)
+	for _, order := range takeProfits {
+		markPrice, exists := markPrices[order.Symbol]
+		if !exists {
+			markPrice, err = s.futuresMarketService.GetMarkPrice(order.Symbol)
+			if err != nil {
+				s.logger.Warn("Failed to get mark price for take profits", map[string]interface{}{
+					"symbol": order.Symbol,
+					"error":  err.Error(),
+				})
+				continue
+			}
+			markPrices[order.Symbol] = markPrice
+		}
+
+		// Failures are logged by ExecuteTakeProfitIfReached; the other orders are still checked
+		s.ExecuteTakeProfitIfReached(order.OrderID, markPrice)
+	}
+
+	return nil
+}
+
+// StartMonitoring starts the scheduled trailing stop and take profit check
+func (s *futuresStopLossService) StartMonitoring(checkInterval time.Duration) error {
+	s.monitoringMu.Lock()
+	defer s.monitoringMu.Unlock()
+
+	if s.isMonitoring {
+		return fmt.Errorf("monitoring is already running")
+	}
+
+	if checkInterval <= 0 {
+		checkInterval = DefaultFuturesTrailingCheckInterval
+	}
+
+	s.stopChan = make(chan struct{})
+	s.isMonitoring = true
+
+	go s.monitoringLoop(checkInterval)
+
+	s.logger.Info("Started futures stop order monitoring", map[string]interface{}{
+		"mode":           string(s.trailingMode),
+		"check_interval": checkInterval.String(),
+	})
+
+	return nil
+}
+
+// StopMonitoring stops the scheduled trailing stop and take profit check
+func (s *futuresStopLossService) StopMonitoring() error {
+	s.monitoringMu.Lock()
+	defer s.monitoringMu.Unlock()
+
+	if !s.isMonitoring {
+		return fmt.Errorf("monitoring is not running")
+	}
+
+	close(s.stopChan)
+	s.isMonitoring = false
+
+	s.logger.Info("Stopped futures stop order monitoring", nil)
+
+	return nil
+}
+
+// monitoringLoop runs the trailing stop and take profit checks on every tick
+func (s *futuresStopLossService) monitoringLoop(checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			if err := s.CheckTrailingStops(); err != nil {
+				s.logger.Warn("Futures trailing stop check failed", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+			if err := s.CheckTakeProfits(); err != nil {
+				s.logger.Warn("Futures take profit check failed", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+		}
+	}
+}
+
+// ExecuteTakeProfitIfReached closes the order's quantity once the price reaches its target
+// This is called periodically by CheckTakeProfits
+func (s *futuresStopLossService) ExecuteTakeProfitIfReached(orderID string, currentPrice float64) (bool, error) {
+	order, err := s.stopOrderRepo.FindStopOrderByID(orderID)
+	if err != nil {
+		return false, err
+	}
+
+	if order.Type != repository.StopOrderTypeTakeProfit || order.Status != repository.StopOrderStatusActive {
+		return false, nil
+	}
+
+	positionSide := api.PositionSide(order.PositionSide)
+	if positionSide != api.PositionSideLong && positionSide != api.PositionSideShort {
+		return false, errors.NewTradingError(errors.ErrInvalidParameter, "take profit order has no position side", 0, nil)
+	}
+
+	// Long targets are reached from below, short targets from above
+	if positionSide == api.PositionSideLong && currentPrice < order.StopPrice {
+		return false, nil
+	}
+	if positionSide == api.PositionSideShort && currentPrice > order.StopPrice {
+		return false, nil
+	}
+
+	// Mark as triggered before executing so the level is never closed twice
+	triggeredAt := timeutil.NowMillis()
+	if err := s.stopOrderRepo.UpdateStopOrderStatus(orderID, repository.StopOrderStatusTriggered, triggeredAt, 0); err != nil {
+		return false, err
+	}
+	s.triggerEngine.UnregisterCondition(orderID)
+
+	executedOrder, err := s.futuresTradingService.ClosePosition(order.Symbol, positionSide, order.Position)
+	if err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation":     "execute_futures_take_profit",
+			"order_id":      orderID,
+			"symbol":        order.Symbol,
+			"position_side": positionSide,
+			"quantity":      order.Position,
+			"trigger_price": currentPrice,
+		})
+		s.reactivateTakeProfit(order)
+		return false, err
+	}
+
+	s.stopOrderRepo.UpdateStopOrderStatus(orderID, repository.StopOrderStatusTriggered, 0, executedOrder.OrderID)
+
+	s.logger.Info("Futures take profit order triggered and executed", map[string]interface{}{
+		"order_id":          orderID,
+		"symbol":            order.Symbol,
+		"position_side":     positionSide,
+		"quantity":          order.Position,
+		"target_price":      order.StopPrice,
+		"trigger_price":     currentPrice,
+		"executed_order_id": executedOrder.OrderID,
+	})
+
+	return true, nil
+}
+
+// reactivateTakeProfit returns a take profit order whose close failed to ACTIVE, so the next
+// check retries it instead of leaving its quantity without a target
+func (s *futuresStopLossService) reactivateTakeProfit(order *repository.StopOrder) {
+	restored := *order
+	restored.Status = repository.StopOrderStatusActive
+	restored.TriggeredAt = 0
+	if err := s.stopOrderRepo.UpdateStopOrder(&restored); err != nil {
+		s.logger.Warn("Failed to reactivate take profit order", map[string]interface{}{
+			"order_id": order.OrderID,
+			"error":    err.Error(),
+		})
+		return
+	}
+
+	operator := OperatorGreaterEqual
+	if api.PositionSide(order.PositionSide) == api.PositionSideShort {
+		operator = OperatorLessEqual
+	}
+	condition := &TriggerCondition{
+		Type:     TriggerTypePrice,
+		Operator: operator,
+		Value:    order.StopPrice,
+	}
+	if err := s.triggerEngine.RegisterCondition(order.OrderID, condition); err != nil {
+		s.logger.Warn("Failed to register trigger condition", map[string]interface{}{
+			"order_id": order.OrderID,
+			"error":    err.Error(),
+		})
+	}
+}
+
 // triggerTrailingStop executes a trailing stop order when triggered
 func (s *futuresStopLossService) triggerTrailingStop(order *repository.TrailingStopOrder, positionSide api.PositionSide, triggerPrice float64) error {
 	// Update status to triggered
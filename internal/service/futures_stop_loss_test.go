@@ -2,10 +2,14 @@ package service
 
 import (
 	"binance-trader/internal/api"
+	"binance-trader/internal/config"
 	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
 	"binance-trader/pkg/logger"
+	"fmt"
 	"math"
 	"testing"
+	"time"
 
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
@@ -36,6 +40,10 @@ func (m *mockFuturesTradingService) CancelOrder(symbol string, orderID int64) er
 	return nil
 }
 
+func (m *mockFuturesTradingService) CancelAllOrders(symbol string) (int, error) {
+	return 0, nil
+}
+
 func (m *mockFuturesTradingService) GetOrderStatus(orderID int64) (*api.FuturesOrder, error) {
 	return &api.FuturesOrder{OrderID: orderID, Status: api.OrderStatusFilled}, nil
 }
@@ -52,6 +60,20 @@ func (m *mockFuturesTradingService) GetLeverage(symbol string) (int, error) {
 	return 10, nil
 }
 
+func (m *mockFuturesTradingService) SetMarginType(symbol string, marginType api.MarginType) error {
+	return nil
+}
+
+func (m *mockFuturesTradingService) SetSymbolGuard(guard SymbolFailureGuard) {}
+
+func (m *mockFuturesTradingService) SetRiskManager(riskMgr FuturesRiskManager) {}
+
+func (m *mockFuturesTradingService) SetPositionMode(dualSide bool) error { return nil }
+
+func (m *mockFuturesTradingService) GetAccountBalance() (*api.FuturesAccountInfo, error) {
+	return &api.FuturesAccountInfo{}, nil
+}
+
 type mockFuturesMarketDataService struct {
 	markPrice float64
 }
@@ -68,6 +90,10 @@ func (m *mockFuturesMarketDataService) GetHistoricalData(symbol string, interval
 	return []*api.Kline{}, nil
 }
 
+func (m *mockFuturesMarketDataService) GetVolume(symbol string, timeWindow time.Duration) (float64, error) {
+	return 0, nil
+}
+
 func (m *mockFuturesMarketDataService) GetFundingRate(symbol string) (*api.FundingRate, error) {
 	return &api.FundingRate{Symbol: symbol, FundingRate: 0.0001}, nil
 }
@@ -76,6 +102,12 @@ func (m *mockFuturesMarketDataService) SubscribeToMarkPrice(symbol string, callb
 	return nil
 }
 
+func (m *mockFuturesMarketDataService) GetBestBidAsk(symbol string) (*BestBidAsk, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockFuturesMarketDataService) SetBookTickerCache(cache BookTickerCache) {}
+
 func (m *mockFuturesMarketDataService) GetOpenInterest(symbol string) (float64, error) {
 	return 1000000.0, nil
 }
@@ -462,3 +494,159 @@ func TestProperty_FuturesTrailingStopPriceAdjustment(t *testing.T) {
 
 	properties.TestingRun(t)
 }
+
+// closeRecordingFuturesTradingService records the positions closed by triggered stops
+type closeRecordingFuturesTradingService struct {
+	mockFuturesTradingService
+	closed []api.PositionSide
+}
+
+func (m *closeRecordingFuturesTradingService) ClosePosition(symbol string, positionSide api.PositionSide, quantity float64) (*api.FuturesOrder, error) {
+	m.closed = append(m.closed, positionSide)
+	return m.mockFuturesTradingService.ClosePosition(symbol, positionSide, quantity)
+}
+
+func TestFuturesTrailingStop_LocalTracking(t *testing.T) {
+	tests := []struct {
+		name         string
+		positionSide api.PositionSide
+		prices       []float64 // Mark prices seen by consecutive checks
+		wantExtreme  float64   // Highest price for LONG, lowest for SHORT, before the trigger
+	}{
+		// The stop follows the high at 2% below and fires on the retrace to 51940 < 53000*0.98
+		{"long tracks the highest price", api.PositionSideLong, []float64{51000, 53000, 52500, 51940}, 53000},
+		// The stop follows the low at 2% above and fires on the bounce to 48500 > 47500*1.02
+		{"short tracks the lowest price", api.PositionSideShort, []float64{49000, 47500, 48000, 48500}, 47500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stopOrderRepo := repository.NewMemoryStopOrderRepository()
+			trading := &closeRecordingFuturesTradingService{}
+			market := &mockFuturesMarketDataService{markPrice: 50000}
+			svc := NewFuturesStopLossService(stopOrderRepo, NewTriggerEngine(), trading, market, &mockLogger{})
+			svc.SetTrailingStopConfig(&config.FuturesStopLossConfig{MinCallbackRate: 0.1, MaxCallbackRate: 5}, nil)
+
+			order, err := svc.SetTrailingStop("BTCUSDT", tt.positionSide, 0.1, 2)
+			if err != nil {
+				t.Fatalf("SetTrailingStop() error = %v", err)
+			}
+			if order.ExchangeOrderID != 0 || order.PositionSide != string(tt.positionSide) {
+				t.Fatalf("order = %+v, want a local %s trailing stop", order, tt.positionSide)
+			}
+
+			for i, price := range tt.prices {
+				market.markPrice = price
+				if err := svc.CheckTrailingStops(); err != nil {
+					t.Fatalf("CheckTrailingStops() error = %v", err)
+				}
+				stored, _ := stopOrderRepo.FindTrailingStopOrderByID(order.OrderID)
+				last := i == len(tt.prices)-1
+				if !last && stored.Status != repository.StopOrderStatusActive {
+					t.Fatalf("trailing stop fired at %v, want it active", price)
+				}
+				if last {
+					if stored.Status != repository.StopOrderStatusTriggered {
+						t.Fatalf("trailing stop at %v = %s, want TRIGGERED", price, stored.Status)
+					}
+					if stored.HighestPrice != tt.wantExtreme {
+						t.Errorf("extreme price = %v, want %v", stored.HighestPrice, tt.wantExtreme)
+					}
+				}
+			}
+
+			if len(trading.closed) != 1 || trading.closed[0] != tt.positionSide {
+				t.Errorf("closed positions = %v, want one %s close", trading.closed, tt.positionSide)
+			}
+		})
+	}
+}
+
+func TestFuturesTrailingStop_CallbackRateBounds(t *testing.T) {
+	svc := NewFuturesStopLossService(repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+		&mockFuturesTradingService{}, &mockFuturesMarketDataService{markPrice: 50000}, &mockLogger{})
+	svc.SetTrailingStopConfig(&config.FuturesStopLossConfig{MinCallbackRate: 0.5, MaxCallbackRate: 5}, nil)
+
+	for _, rate := range []float64{0, 0.4, 5.1} {
+		_, err := svc.SetTrailingStop("BTCUSDT", api.PositionSideLong, 0.1, rate)
+		if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrInvalidParameter {
+			t.Errorf("SetTrailingStop() with rate %v error = %v, want ErrInvalidParameter", rate, err)
+		}
+	}
+
+	order, err := svc.SetTrailingStop("BTCUSDT", api.PositionSideShort, 0.1, 5)
+	if err != nil {
+		t.Fatalf("SetTrailingStop() at the maximum error = %v", err)
+	}
+	if err := svc.UpdateTrailingStop(order.OrderID, 6); err == nil {
+		t.Error("UpdateTrailingStop() above the maximum should fail")
+	}
+
+	// A SHORT stop stays above the lowest price when its callback changes
+	if err := svc.UpdateTrailingStop(order.OrderID, 1); err != nil {
+		t.Fatalf("UpdateTrailingStop() error = %v", err)
+	}
+	orders, _ := svc.(*futuresStopLossService).stopOrderRepo.FindActiveTrailingStopOrders("BTCUSDT")
+	if len(orders) != 1 || math.Abs(orders[0].CurrentStopPrice-50500) > 1e-6 {
+		t.Errorf("updated SHORT stop = %+v, want 50500", orders)
+	}
+}
+
+func TestFuturesTrailingStop_Native(t *testing.T) {
+	var placed *api.FuturesOrderRequest
+	var cancelled int64
+	client := &mockFuturesClient{
+		createOrderFunc: func(order *api.FuturesOrderRequest) (*api.FuturesOrderResponse, error) {
+			placed = order
+			return &api.FuturesOrderResponse{OrderID: 777, Symbol: order.Symbol, Status: api.OrderStatusNew, Type: order.Type}, nil
+		},
+		cancelOrderFunc: func(symbol string, orderID int64) (*api.CancelResponse, error) {
+			cancelled = orderID
+			return &api.CancelResponse{Symbol: symbol, OrderID: orderID, Status: api.OrderStatusCanceled}, nil
+		},
+	}
+	stopOrderRepo := repository.NewMemoryStopOrderRepository()
+	trading := &closeRecordingFuturesTradingService{}
+	market := &mockFuturesMarketDataService{markPrice: 3000}
+	svc := NewFuturesStopLossService(stopOrderRepo, NewTriggerEngine(), trading, market, &mockLogger{})
+	svc.SetTrailingStopConfig(&config.FuturesStopLossConfig{TrailingMode: "native", MinCallbackRate: 0.1, MaxCallbackRate: 5}, client)
+
+	order, err := svc.SetTrailingStop("ETHUSDT", api.PositionSideShort, 2, 1.5)
+	if err != nil {
+		t.Fatalf("SetTrailingStop() error = %v", err)
+	}
+	if placed == nil || placed.Type != api.OrderTypeTrailingStopMarket || placed.Side != api.OrderSideBuy ||
+		placed.PositionSide != api.PositionSideShort || !placed.ReduceOnly || placed.CallbackRate != 1.5 || placed.Quantity != 2 {
+		t.Fatalf("placed order = %+v, want a reduce-only BUY TRAILING_STOP_MARKET at 1.5%%", placed)
+	}
+	if order.ExchangeOrderID != 777 {
+		t.Errorf("exchange order ID = %d, want 777", order.ExchangeOrderID)
+	}
+
+	// The exchange tracks the stop, so the local check leaves it alone
+	market.markPrice = 3200
+	if err := svc.CheckTrailingStops(); err != nil {
+		t.Fatalf("CheckTrailingStops() error = %v", err)
+	}
+	if len(trading.closed) != 0 {
+		t.Errorf("closed positions = %v, want none", trading.closed)
+	}
+	if err := svc.UpdateTrailingStop(order.OrderID, 2); err == nil {
+		t.Error("UpdateTrailingStop() of a native trailing stop should fail")
+	}
+
+	if _, err := svc.CancelStopOrder(order.OrderID, "ETHUSDT"); err != nil {
+		t.Fatalf("CancelStopOrder() error = %v", err)
+	}
+	if cancelled != 777 {
+		t.Errorf("cancelled exchange order = %d, want 777", cancelled)
+	}
+
+	// Without a client native mode falls back to local tracking
+	svc.SetTrailingStopConfig(&config.FuturesStopLossConfig{TrailingMode: "native"}, nil)
+	placed = nil
+	local, err := svc.SetTrailingStop("ETHUSDT", api.PositionSideLong, 1, 1)
+	if err != nil || placed != nil || local.ExchangeOrderID != 0 {
+		t.Errorf("fallback trailing stop = %+v, %v; want a local order", local, err)
+	}
+}
@@ -21,19 +21,44 @@ type FuturesTradingService interface {
 	
 	// Order management
 	CancelOrder(symbol string, orderID int64) error
+	// CancelAllOrders cancels every open order of a symbol and returns how many were cancelled;
+	// when some fail, the count of the others comes with a *api.BulkCancelError
+	CancelAllOrders(symbol string) (int, error)
 	GetOrderStatus(orderID int64) (*api.FuturesOrder, error)
 	GetActiveOrders(symbol string) ([]*api.FuturesOrder, error)
 	
 	// Leverage management
 	SetLeverage(symbol string, leverage int) (*api.LeverageResponse, error)
 	GetLeverage(symbol string) (int, error)
+	
+	// SetMarginType switches a symbol between cross and isolated margin; a symbol already
+	// using the margin type is left as it is
+	SetMarginType(symbol string, marginType api.MarginType) error
+	
+	// SetPositionMode switches the account between hedge mode (dualSide), which holds long and
+	// short positions of a symbol at once, and one-way mode; an account already in the mode is
+	// left as it is
+	SetPositionMode(dualSide bool) error
+	
+	// GetAccountBalance returns the wallet, margin and available balances of the account with
+	// its per-asset balances
+	GetAccountBalance() (*api.FuturesAccountInfo, error)
+	
+	// SetSymbolGuard sets the optional guard that pauses new orders for repeatedly failing symbols
+	SetSymbolGuard(guard SymbolFailureGuard)
+	
+	// SetRiskManager sets the optional risk manager that checks the leveraged notional of limit
+	// orders opening positions
+	SetRiskManager(riskMgr FuturesRiskManager)
 }
 
 // futuresTradingService implements FuturesTradingService interface
 type futuresTradingService struct {
-	client     api.FuturesClient
-	repository repository.FuturesOrderRepository
-	logger     logger.Logger
+	client      api.FuturesClient
+	repository  repository.FuturesOrderRepository
+	logger      logger.Logger
+	symbolGuard SymbolFailureGuard
+	riskMgr     FuturesRiskManager
 }
 
 // NewFuturesTradingService creates a new futures trading service
@@ -49,6 +74,48 @@ func NewFuturesTradingService(
 	}
 }
 
+// SetSymbolGuard sets the optional symbol failure guard
+func (s *futuresTradingService) SetSymbolGuard(guard SymbolFailureGuard) {
+	s.symbolGuard = guard
+}
+
+// SetRiskManager sets the optional risk manager
+func (s *futuresTradingService) SetRiskManager(riskMgr FuturesRiskManager) {
+	s.riskMgr = riskMgr
+}
+
+// checkLimitNotional rejects limit orders whose leveraged notional exceeds the risk limits
+func (s *futuresTradingService) checkLimitNotional(symbol string, quantity float64, orderType api.OrderType, price float64) error {
+	if s.riskMgr == nil || orderType != api.OrderTypeLimit {
+		return nil
+	}
+	leverage, err := s.GetLeverage(symbol)
+	if err != nil {
+		return err
+	}
+	return s.riskMgr.CheckOrderNotional(symbol, price, quantity, leverage)
+}
+
+// checkSymbolPaused returns an error if opening new positions for the symbol is paused
+func (s *futuresTradingService) checkSymbolPaused(symbol string) error {
+	if s.symbolGuard == nil {
+		return nil
+	}
+	return s.symbolGuard.CheckSymbol(symbol)
+}
+
+// recordOrderResult reports an order placement result to the symbol failure guard
+func (s *futuresTradingService) recordOrderResult(symbol string, err error) {
+	if s.symbolGuard == nil {
+		return
+	}
+	if err != nil {
+		s.symbolGuard.RecordFailure(symbol, err)
+		return
+	}
+	s.symbolGuard.RecordSuccess(symbol)
+}
+
 // OpenLongPosition opens a long position (buy)
 func (s *futuresTradingService) OpenLongPosition(symbol string, quantity float64, orderType api.OrderType, price float64) (*api.FuturesOrder, error) {
 	if symbol == "" {
@@ -79,6 +146,15 @@ func (s *futuresTradingService) OpenLongPosition(symbol string, quantity float64
 		)
 	}
 	
+	// Reject new positions for symbols paused after repeated failures
+	if err := s.checkSymbolPaused(symbol); err != nil {
+		return nil, err
+	}
+	
+	if err := s.checkLimitNotional(symbol, quantity, orderType, price); err != nil {
+		return nil, err
+	}
+	
 	// Create order request for opening long position
 	orderReq := &api.FuturesOrderRequest{
 		Symbol:       symbol,
@@ -98,6 +174,7 @@ func (s *futuresTradingService) OpenLongPosition(symbol string, quantity float64
 	
 	// Create order via API
 	response, err := s.client.CreateOrder(orderReq)
+	s.recordOrderResult(symbol, err)
 	if err != nil {
 		s.logger.Error("Failed to open long position", map[string]interface{}{
 			"symbol":   symbol,
@@ -174,6 +251,15 @@ func (s *futuresTradingService) OpenShortPosition(symbol string, quantity float6
 		)
 	}
 	
+	// Reject new positions for symbols paused after repeated failures
+	if err := s.checkSymbolPaused(symbol); err != nil {
+		return nil, err
+	}
+	
+	if err := s.checkLimitNotional(symbol, quantity, orderType, price); err != nil {
+		return nil, err
+	}
+	
 	// Create order request for opening short position
 	orderReq := &api.FuturesOrderRequest{
 		Symbol:       symbol,
@@ -193,6 +279,7 @@ func (s *futuresTradingService) OpenShortPosition(symbol string, quantity float6
 	
 	// Create order via API
 	response, err := s.client.CreateOrder(orderReq)
+	s.recordOrderResult(symbol, err)
 	if err != nil {
 		s.logger.Error("Failed to open short position", map[string]interface{}{
 			"symbol":   symbol,
@@ -293,6 +380,7 @@ func (s *futuresTradingService) ClosePosition(symbol string, positionSide api.Po
 	
 	// Create order via API
 	response, err := s.client.CreateOrder(orderReq)
+	s.recordOrderResult(symbol, err)
 	if err != nil {
 		s.logger.Error("Failed to close position", map[string]interface{}{
 			"symbol":        symbol,
@@ -451,6 +539,55 @@ func (s *futuresTradingService) CancelOrder(symbol string, orderID int64) error
 	return nil
 }
 
+// CancelAllOrders cancels all open orders of a symbol and marks the cancelled orders the
+// repository knows as canceled
+func (s *futuresTradingService) CancelAllOrders(symbol string) (int, error) {
+	if symbol == "" {
+		return 0, errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			"symbol cannot be empty",
+			0,
+			nil,
+		)
+	}
+	
+	s.logger.Info("Cancelling all orders", map[string]interface{}{
+		"symbol": symbol,
+	})
+	
+	cancelled, err := s.client.BulkCancelFuturesOrders(symbol)
+	now := time.Now().UnixMilli()
+	for _, resp := range cancelled {
+		// Orders placed outside this process are not in the repository
+		order, findErr := s.repository.FindByID(resp.OrderID)
+		if findErr != nil {
+			continue
+		}
+		if syncErr := s.repository.SyncOrderStatus(resp.OrderID, api.OrderStatusCanceled, order.ExecutedQty, order.AvgPrice, now); syncErr != nil {
+			s.logger.Warn("Failed to update order status in repository", map[string]interface{}{
+				"order_id": resp.OrderID,
+				"error":    syncErr.Error(),
+			})
+		}
+	}
+	
+	if err != nil {
+		s.logger.Error("Failed to cancel all orders", map[string]interface{}{
+			"symbol":    symbol,
+			"cancelled": len(cancelled),
+			"error":     err.Error(),
+		})
+		return len(cancelled), fmt.Errorf("failed to cancel all orders: %w", err)
+	}
+	
+	s.logger.Info("All orders cancelled", map[string]interface{}{
+		"symbol":    symbol,
+		"cancelled": len(cancelled),
+	})
+	
+	return len(cancelled), nil
+}
+
 // GetOrderStatus retrieves the current status of an order
 func (s *futuresTradingService) GetOrderStatus(orderID int64) (*api.FuturesOrder, error) {
 	if orderID <= 0 {
@@ -554,6 +691,91 @@ func (s *futuresTradingService) SetLeverage(symbol string, leverage int) (*api.L
 	return response, nil
 }
 
+// SetMarginType sets margin type for a symbol
+func (s *futuresTradingService) SetMarginType(symbol string, marginType api.MarginType) error {
+	if symbol == "" {
+		return errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			"symbol cannot be empty",
+			0,
+			nil,
+		)
+	}
+	
+	if marginType != api.MarginTypeIsolated && marginType != api.MarginTypeCrossed {
+		return errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			fmt.Sprintf("invalid margin type: %s", marginType),
+			0,
+			nil,
+		)
+	}
+	
+	s.logger.Info("Setting margin type", map[string]interface{}{
+		"symbol":      symbol,
+		"margin_type": marginType,
+	})
+	
+	if err := s.client.SetMarginType(symbol, marginType); err != nil {
+		s.logger.Error("Failed to set margin type", map[string]interface{}{
+			"symbol":      symbol,
+			"margin_type": marginType,
+			"error":       err.Error(),
+		})
+		return fmt.Errorf("failed to set margin type: %w", err)
+	}
+	
+	s.logger.Info("Margin type set successfully", map[string]interface{}{
+		"symbol":      symbol,
+		"margin_type": marginType,
+	})
+	
+	return nil
+}
+
+// SetPositionMode sets the position mode of the account
+func (s *futuresTradingService) SetPositionMode(dualSide bool) error {
+	mode := positionModeName(dualSide)
+	s.logger.Info("Setting position mode", map[string]interface{}{
+		"mode": mode,
+	})
+	
+	if err := s.client.SetPositionMode(dualSide); err != nil {
+		s.logger.Error("Failed to set position mode", map[string]interface{}{
+			"mode":  mode,
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to set position mode: %w", err)
+	}
+	
+	s.logger.Info("Position mode set successfully", map[string]interface{}{
+		"mode": mode,
+	})
+	
+	return nil
+}
+
+// GetAccountBalance retrieves the balances of the futures account
+func (s *futuresTradingService) GetAccountBalance() (*api.FuturesAccountInfo, error) {
+	account, err := s.client.GetFuturesAccountInfo()
+	if err != nil {
+		s.logger.Error("Failed to get futures account balance", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to get account balance: %w", err)
+	}
+	
+	return account, nil
+}
+
+// positionModeName names a position mode for logs and messages
+func positionModeName(dualSide bool) string {
+	if dualSide {
+		return "hedge"
+	}
+	return "one-way"
+}
+
 // GetLeverage retrieves current leverage for a symbol
 func (s *futuresTradingService) GetLeverage(symbol string) (int, error) {
 	if symbol == "" {
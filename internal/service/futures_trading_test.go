@@ -2,9 +2,11 @@ package service
 
 import (
 	"binance-trader/internal/api"
+	"binance-trader/internal/config"
 	"binance-trader/internal/repository"
 	"binance-trader/pkg/errors"
 	"binance-trader/pkg/logger"
+	stderrors "errors"
 	"testing"
 
 	"github.com/leanovate/gopter"
@@ -314,3 +316,131 @@ func TestProperty17_MarginSufficiencyCheck(t *testing.T) {
 
 	properties.TestingRun(t)
 }
+
+func TestFuturesTradingService_SetMarginType(t *testing.T) {
+	var calls []api.MarginType
+	client := &mockFuturesLeverageClient{
+		setMarginTypeFunc: func(symbol string, marginType api.MarginType) error {
+			calls = append(calls, marginType)
+			if marginType == api.MarginTypeCrossed {
+				return errors.NewTradingError(errors.ErrMarginModeConflict, "open positions exist", 0, nil)
+			}
+			return nil
+		},
+	}
+	service := NewFuturesTradingService(client, repository.NewMemoryFuturesOrderRepository(), &mockLogger{})
+
+	if err := service.SetMarginType("BTCUSDT", api.MarginTypeIsolated); err != nil {
+		t.Fatalf("SetMarginType() error = %v", err)
+	}
+	if err := service.SetMarginType("BTCUSDT", api.MarginTypeCrossed); err == nil {
+		t.Error("expected the exchange rejection to be returned")
+	}
+	if err := service.SetMarginType("BTCUSDT", "PORTFOLIO"); err == nil {
+		t.Error("expected an error for an unknown margin type")
+	}
+	if err := service.SetMarginType("", api.MarginTypeIsolated); err == nil {
+		t.Error("expected an error for an empty symbol")
+	}
+	if len(calls) != 2 {
+		t.Errorf("exchange calls = %v, want only the two valid requests", calls)
+	}
+}
+
+func TestFuturesTradingService_SetPositionMode(t *testing.T) {
+	var calls []bool
+	client := &mockFuturesLeverageClient{
+		setPositionModeFunc: func(dualSidePosition bool) error {
+			calls = append(calls, dualSidePosition)
+			if !dualSidePosition {
+				return errors.NewTradingError(errors.ErrPositionModeConflict, "open positions exist", 0, nil)
+			}
+			return nil
+		},
+	}
+	service := NewFuturesTradingService(client, repository.NewMemoryFuturesOrderRepository(), &mockLogger{})
+
+	if err := service.SetPositionMode(true); err != nil {
+		t.Fatalf("SetPositionMode(true) error = %v", err)
+	}
+	err := service.SetPositionMode(false)
+	if tradingErr, ok := stderrors.Unwrap(err).(*errors.TradingError); !ok || tradingErr.Type != errors.ErrPositionModeConflict {
+		t.Errorf("SetPositionMode(false) error = %v, want the exchange rejection", err)
+	}
+	if len(calls) != 2 || !calls[0] || calls[1] {
+		t.Errorf("exchange calls = %v, want [true false]", calls)
+	}
+}
+
+func TestFuturesTradingService_CancelAllOrdersPartialFailure(t *testing.T) {
+	client := &mockFuturesClient{
+		bulkCancelFunc: func(symbol string) ([]api.CancelResponse, error) {
+			cancelled := []api.CancelResponse{{Symbol: symbol, OrderID: 1, Status: api.OrderStatusCanceled}}
+			return cancelled, &api.BulkCancelError{Symbol: symbol, Cancelled: 1,
+				Failures: []api.BulkCancelFailure{{OrderID: 2, Code: -2011, Message: "Unknown order sent."}}}
+		},
+	}
+	repo := repository.NewMemoryFuturesOrderRepository()
+	for id := int64(1); id <= 2; id++ {
+		repo.Save(&api.FuturesOrder{OrderID: id, Symbol: "BTCUSDT", Status: api.OrderStatusNew, OrigQty: 0.1})
+	}
+	service := NewFuturesTradingService(client, repo, &mockLogger{})
+
+	cancelled, err := service.CancelAllOrders("BTCUSDT")
+	if cancelled != 1 || err == nil {
+		t.Fatalf("CancelAllOrders() = %d, %v; want 1 cancelled and the failure", cancelled, err)
+	}
+	var bulkErr *api.BulkCancelError
+	if !stderrors.As(err, &bulkErr) || bulkErr.Failures[0].OrderID != 2 {
+		t.Errorf("error = %v, want the bulk cancel error for order 2", err)
+	}
+	if order, _ := repo.FindByID(1); order.Status != api.OrderStatusCanceled {
+		t.Errorf("order 1 = %s, want CANCELED", order.Status)
+	}
+	if order, _ := repo.FindByID(2); order.Status != api.OrderStatusNew {
+		t.Errorf("order 2 = %s, want NEW", order.Status)
+	}
+}
+
+func TestFuturesTradingService_LimitOrders(t *testing.T) {
+	var requests []*api.FuturesOrderRequest
+	client := &mockFuturesClient{
+		createOrderFunc: func(req *api.FuturesOrderRequest) (*api.FuturesOrderResponse, error) {
+			requests = append(requests, req)
+			return &api.FuturesOrderResponse{OrderID: int64(len(requests)), Symbol: req.Symbol, Status: api.OrderStatusNew,
+				Side: req.Side, PositionSide: req.PositionSide, Type: req.Type, Price: req.Price, OrigQty: req.Quantity}, nil
+		},
+		getPositionsFunc: func(symbol string) ([]*api.Position, error) {
+			return []*api.Position{{Symbol: symbol, Leverage: 10}}, nil
+		},
+	}
+	riskMgr := NewFuturesRiskManager(&config.FuturesRiskConfig{MaxPositionValue: 100000}, client, &mockFuturesPositionManager{}, &mockLogger{})
+	service := NewFuturesTradingService(client, repository.NewMemoryFuturesOrderRepository(), &mockLogger{})
+	service.SetRiskManager(riskMgr)
+
+	order, err := service.OpenLongPosition("BTCUSDT", 0.1, api.OrderTypeLimit, 48000)
+	if err != nil {
+		t.Fatalf("OpenLongPosition() error = %v", err)
+	}
+	if order.Price != 48000 || requests[0].Type != api.OrderTypeLimit || requests[0].Price != 48000 {
+		t.Errorf("order = %+v from request %+v, want a limit order at 48000", order, requests[0])
+	}
+
+	// 52000 * 0.2 at 10x is 104000, over the 100000 limit
+	_, err = service.OpenShortPosition("BTCUSDT", 0.2, api.OrderTypeLimit, 52000)
+	if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrMaxPositionExceeded {
+		t.Errorf("OpenShortPosition() over the limit error = %v, want ErrMaxPositionExceeded", err)
+	}
+
+	for _, open := range []func(string, float64, api.OrderType, float64) (*api.FuturesOrder, error){
+		service.OpenLongPosition, service.OpenShortPosition,
+	} {
+		_, err := open("BTCUSDT", 0.1, api.OrderTypeLimit, 0)
+		if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrInvalidParameter {
+			t.Errorf("limit order without a price error = %v, want ErrInvalidParameter", err)
+		}
+	}
+	if len(requests) != 1 {
+		t.Errorf("orders sent = %d, want only the one within the limits", len(requests))
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"binance-trader/internal/repository"
+	"fmt"
+	"time"
+)
+
+// ImbalanceDepth is the number of order book levels per side that ask/bid imbalance
+// conditions sum
+const ImbalanceDepth = 20
+
+// bookImbalance is the ask/bid imbalance of one symbol and when it was computed
+type bookImbalance struct {
+	value float64
+	at    time.Time
+}
+
+// validateImbalanceCondition checks the threshold of an ask/bid imbalance condition
+func validateImbalanceCondition(condition *repository.TriggerCondition) error {
+	if condition.Value < -1 || condition.Value > 1 {
+		return fmt.Errorf("ask/bid imbalance threshold must be between -1 and 1")
+	}
+	return nil
+}
+
+// imbalanceValue returns the ask/bid imbalance of the best ImbalanceDepth levels of a symbol's
+// order book. Like market data, a value is reused for a second so orders on the same symbol
+// share one depth request per tick.
+func (me *MonitoringEngine) imbalanceValue(symbol string) (float64, error) {
+	now := me.now()
+
+	me.mu.RLock()
+	cached, ok := me.imbalanceCache[symbol]
+	me.mu.RUnlock()
+	if ok && now.Sub(cached.at) < time.Second {
+		return cached.value, nil
+	}
+
+	book, err := me.marketDataService.GetOrderBook(symbol, ImbalanceDepth)
+	me.recordAPIResult(err)
+	if err != nil {
+		return 0, err
+	}
+	value := book.Imbalance(ImbalanceDepth)
+
+	me.mu.Lock()
+	me.imbalanceCache[symbol] = &bookImbalance{value: value, at: now}
+	me.mu.Unlock()
+
+	return value, nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+// bookMarketDataService serves a fixed order book and counts the depth requests
+type bookMarketDataService struct {
+	mockMarketDataService
+	book     *api.OrderBook
+	requests int
+}
+
+func (m *bookMarketDataService) GetOrderBook(symbol string, limit int) (*api.OrderBook, error) {
+	m.requests++
+	return m.book, nil
+}
+
+// bookLevels builds one side of an order book from quantities, one price step apart
+func bookLevels(quantities []float64) []api.OrderBookLevel {
+	levels := make([]api.OrderBookLevel, len(quantities))
+	for i, qty := range quantities {
+		levels[i] = api.OrderBookLevel{Price: 100 + float64(i), Qty: qty}
+	}
+	return levels
+}
+
+// Feature: binance-auto-trading, ask/bid imbalance trigger
+// For any order book, the ask/bid imbalance is within [-1, 1] at every depth, and it is 1 or -1
+// exactly when only one side has quantity
+func TestProperty_AskBidImbalanceRange(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("imbalance ratio is always in [-1, 1]", prop.ForAll(
+		func(bids, asks []float64, depth int) bool {
+			book := &api.OrderBook{Bids: bookLevels(bids), Asks: bookLevels(asks)}
+			imbalance := book.Imbalance(depth)
+			if imbalance < -1 || imbalance > 1 {
+				return false
+			}
+
+			engine := NewTriggerEngine()
+			condition := &TriggerCondition{Type: TriggerTypeAskBidImbalance, Operator: OperatorGreaterEqual, Value: 0}
+			if _, err := engine.EvaluateCondition(condition, imbalance); err != nil {
+				return false
+			}
+
+			bidOnly := len(asks) == 0 && len(bids) > 0
+			askOnly := len(bids) == 0 && len(asks) > 0
+			return bidOnly == (imbalance == 1) && askOnly == (imbalance == -1)
+		},
+		gen.SliceOf(gen.Float64Range(0.0001, 1000000)),
+		gen.SliceOf(gen.Float64Range(0.0001, 1000000)),
+		gen.IntRange(0, 50),
+	))
+
+	properties.TestingRun(t)
+}
+
+func TestTriggerEngine_AskBidImbalanceOutOfRange(t *testing.T) {
+	engine := NewTriggerEngine()
+	condition := &TriggerCondition{Type: TriggerTypeAskBidImbalance, Operator: OperatorGreaterEqual, Value: 0.3}
+
+	if met, err := engine.EvaluateCondition(condition, 0.35); err != nil || !met {
+		t.Errorf("EvaluateCondition(0.35) = %v, %v; want met", met, err)
+	}
+	if met, err := engine.EvaluateCondition(condition, -0.2); err != nil || met {
+		t.Errorf("EvaluateCondition(-0.2) = %v, %v; want not met", met, err)
+	}
+	if _, err := engine.EvaluateCondition(condition, 1.5); err == nil {
+		t.Error("expected an error for an imbalance outside [-1, 1]")
+	}
+}
+
+func TestMonitoringEngine_AskBidImbalanceTrigger(t *testing.T) {
+	// Bids 9, asks 3 over the top levels: (9 - 3) / (9 + 3) = 0.5
+	market := &bookMarketDataService{
+		mockMarketDataService: mockMarketDataService{prices: map[string]float64{"BTCUSDT": 50000}},
+		book: &api.OrderBook{
+			Bids: []api.OrderBookLevel{{Price: 49999, Qty: 4}, {Price: 49998, Qty: 5}},
+			Asks: []api.OrderBookLevel{{Price: 50001, Qty: 1}, {Price: 50002, Qty: 2}},
+		},
+	}
+	repo := repository.NewMemoryConditionalOrderRepository()
+	engine := NewMonitoringEngine(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+		&mockTradingService{}, market, &mockStopLossService{}, &mockLogger{}, nil)
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	engine.now = func() time.Time { return now }
+
+	newOrder := func(id string, value float64) *repository.ConditionalOrder {
+		order := &repository.ConditionalOrder{
+			OrderID:  id,
+			Symbol:   "BTCUSDT",
+			Side:     api.OrderSideBuy,
+			Type:     api.OrderTypeMarket,
+			Quantity: 1.0,
+			TriggerCondition: &repository.TriggerCondition{
+				Type:     repository.TriggerTypeAskBidImbalance,
+				Operator: repository.OperatorGreaterEqual,
+				Value:    value,
+			},
+			Status:    repository.ConditionalOrderStatusPending,
+			CreatedAt: now.Unix(),
+		}
+		repo.Save(order)
+		return order
+	}
+	below := newOrder("imbalance-0.3", 0.3)
+	above := newOrder("imbalance-0.6", 0.6)
+
+	engine.processOrder(below)
+	engine.processOrder(above)
+
+	if updated, _ := repo.FindByID(below.OrderID); updated.Status != repository.ConditionalOrderStatusExecuted {
+		t.Errorf("imbalance >= 0.3 order status = %s, want EXECUTED at an imbalance of 0.5", updated.Status)
+	}
+	if updated, _ := repo.FindByID(above.OrderID); updated.Status != repository.ConditionalOrderStatusPending {
+		t.Errorf("imbalance >= 0.6 order status = %s, want PENDING at an imbalance of 0.5", updated.Status)
+	}
+	// Orders on the same symbol share the depth request of the tick
+	if market.requests != 1 {
+		t.Errorf("depth requests = %d, want 1", market.requests)
+	}
+}
+
+func TestConditionalOrderService_ValidateImbalanceCondition(t *testing.T) {
+	svc := NewConditionalOrderService(repository.NewMemoryConditionalOrderRepository(), repository.NewMemoryStopOrderRepository(),
+		NewTriggerEngine(), &mockTradingService{}, &mockMarketDataService{}, &mockStopLossService{}, &mockLogger{})
+
+	imbalance := func(value float64) *repository.TriggerCondition {
+		return &repository.TriggerCondition{Type: repository.TriggerTypeAskBidImbalance, Operator: repository.OperatorLessEqual, Value: value}
+	}
+	tests := []struct {
+		name      string
+		condition *repository.TriggerCondition
+		wantErr   bool
+	}{
+		{"negative threshold", imbalance(-0.3), false},
+		{"bound", imbalance(1), false},
+		{"above 1", imbalance(1.2), true},
+		{"below -1", imbalance(-1.5), true},
+		{"inside a composite", &repository.TriggerCondition{
+			CompositeType: repository.LogicAND,
+			SubConditions: []*repository.TriggerCondition{
+				imbalance(0.3),
+				{Type: repository.TriggerTypePrice, Operator: repository.OperatorLessThan, Value: 50000},
+			},
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateConditionalOrder(&repository.ConditionalOrderRequest{
+				Symbol:           "BTCUSDT",
+				Side:             api.OrderSideBuy,
+				Type:             api.OrderTypeMarket,
+				Quantity:         0.01,
+				TriggerCondition: tt.condition,
+				AllowDuplicate:   true,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateConditionalOrder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
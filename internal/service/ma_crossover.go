@@ -0,0 +1,100 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"fmt"
+)
+
+const (
+	// DefaultMACrossoverInterval is the kline interval of MA crossover conditions that do not set one
+	DefaultMACrossoverInterval = "1h"
+
+	// maxMACrossoverPeriod keeps the kline request within the exchange's limit of 1000
+	maxMACrossoverPeriod = 300
+)
+
+// SimpleMovingAverage returns the mean of the last period values, which are ordered oldest first
+func SimpleMovingAverage(values []float64, period int) (float64, error) {
+	if period <= 0 {
+		return 0, fmt.Errorf("moving average period must be greater than 0")
+	}
+	if len(values) < period {
+		return 0, fmt.Errorf("SMA(%d) needs %d values, got %d", period, period, len(values))
+	}
+
+	sum := 0.0
+	for _, value := range values[len(values)-period:] {
+		sum += value
+	}
+	return sum / float64(period), nil
+}
+
+// validateMACrossoverCondition checks the periods, kline interval and direction of an MA
+// crossover condition. A greater operator fires on the fast MA crossing above the slow one
+// (golden cross), a less operator on it crossing below (death cross); there is no threshold.
+func validateMACrossoverCondition(condition *repository.TriggerCondition) error {
+	if condition.FastPeriod <= 0 || condition.SlowPeriod > maxMACrossoverPeriod {
+		return fmt.Errorf("MA crossover periods must be between 1 and %d", maxMACrossoverPeriod)
+	}
+	if condition.FastPeriod >= condition.SlowPeriod {
+		return fmt.Errorf("MA crossover fast period must be shorter than the slow period")
+	}
+	if condition.Interval != "" {
+		if _, ok := api.KlineIntervalDuration(condition.Interval); !ok {
+			return fmt.Errorf("unsupported MA crossover kline interval %q", condition.Interval)
+		}
+	}
+	if condition.Value != 0 {
+		return fmt.Errorf("MA crossover conditions compare the fast MA with the slow MA and take no threshold")
+	}
+	return nil
+}
+
+// maSpread returns the fast MA minus the slow MA of a condition over the closed klines of its
+// interval followed by the current price, so the averages move with the candle still forming
+func (me *MonitoringEngine) maSpread(symbol string, condition *repository.TriggerCondition, price float64) (float64, error) {
+	closes, err := me.closedKlineCloses(symbol, maCrossoverInterval(condition), condition.SlowPeriod)
+	if err != nil {
+		return 0, err
+	}
+	closes = append(closes, price)
+
+	fast, err := SimpleMovingAverage(closes, condition.FastPeriod)
+	if err != nil {
+		return 0, err
+	}
+	slow, err := SimpleMovingAverage(closes, condition.SlowPeriod)
+	if err != nil {
+		return 0, err
+	}
+	return fast - slow, nil
+}
+
+// maCrossed records which side of the slow MA the fast MA is on for an order and reports
+// whether it changed sides since the previous tick. The first tick only records the side, and
+// a tick with equal averages keeps the previous one, so touching the slow MA is not a cross.
+func (me *MonitoringEngine) maCrossed(orderID string, spread float64) bool {
+	side := 1
+	switch {
+	case spread < 0:
+		side = -1
+	case spread == 0:
+		return false
+	}
+
+	me.mu.Lock()
+	defer me.mu.Unlock()
+
+	previous, seen := me.maCrossSides[orderID]
+	me.maCrossSides[orderID] = side
+	return seen && previous != side
+}
+
+// maCrossoverInterval returns the kline interval of an MA crossover condition
+func maCrossoverInterval(condition *repository.TriggerCondition) string {
+	if condition.Interval == "" {
+		return DefaultMACrossoverInterval
+	}
+	return condition.Interval
+}
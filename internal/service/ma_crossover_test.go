@@ -0,0 +1,191 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	"testing"
+	"time"
+)
+
+func newMACrossoverOrder(id string, operator repository.ComparisonOperator, fast, slow int) *repository.ConditionalOrder {
+	return &repository.ConditionalOrder{
+		OrderID:  id,
+		Symbol:   "BTCUSDT",
+		Side:     api.OrderSideBuy,
+		Type:     api.OrderTypeMarket,
+		Quantity: 1.0,
+		TriggerCondition: &repository.TriggerCondition{
+			Type:       repository.TriggerTypeMACrossover,
+			Operator:   operator,
+			FastPeriod: fast,
+			SlowPeriod: slow,
+			Interval:   "1h",
+		},
+		Status:    repository.ConditionalOrderStatusPending,
+		CreatedAt: time.Now().Unix(),
+	}
+}
+
+func TestSimpleMovingAverage(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6}
+	for _, tt := range []struct {
+		period int
+		want   float64
+	}{{1, 6}, {3, 5}, {6, 3.5}} {
+		if sma, err := SimpleMovingAverage(values, tt.period); err != nil || sma != tt.want {
+			t.Errorf("SMA(%d) = %v, %v; want %v", tt.period, sma, err, tt.want)
+		}
+	}
+	if _, err := SimpleMovingAverage(values, 7); err == nil {
+		t.Error("expected an error with fewer values than the period")
+	}
+	if _, err := SimpleMovingAverage(values, 0); err == nil {
+		t.Error("expected an error for a zero period")
+	}
+}
+
+func TestMonitoringEngine_MAGoldenCross(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 5, 0, 0, time.UTC)
+	// With closed candles ending 12, 11, 10, SMA(2) - SMA(4) of the current price p is (p - 13) / 4:
+	// the fast MA is below the slow one under 13 and above it over 13
+	market := &rsiMarketDataService{
+		mockMarketDataService: mockMarketDataService{prices: map[string]float64{"BTCUSDT": 12}},
+		closes:                []float64{15, 14, 13, 12, 11, 10, 10},
+		now:                   &now,
+	}
+	repo := repository.NewMemoryConditionalOrderRepository()
+	engine := NewMonitoringEngine(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+		&mockTradingService{}, market, &mockStopLossService{}, &mockLogger{}, nil)
+	engine.now = func() time.Time { return now }
+
+	golden := newMACrossoverOrder("golden", repository.OperatorGreaterThan, 2, 4)
+	death := newMACrossoverOrder("death", repository.OperatorLessThan, 2, 4)
+	repo.Save(golden)
+	repo.Save(death)
+
+	status := func(order *repository.ConditionalOrder) repository.ConditionalOrderStatus {
+		updated, _ := repo.FindByID(order.OrderID)
+		return updated.Status
+	}
+	tick := func(price float64) {
+		market.prices["BTCUSDT"] = price
+		now = now.Add(2 * time.Second)
+		engine.processOrder(golden)
+		engine.processOrder(death)
+	}
+
+	// Below, then touching the slow MA, then still below: being below is not a cross
+	for _, price := range []float64{12, 13, 12.5} {
+		tick(price)
+		if status(golden) != repository.ConditionalOrderStatusPending || status(death) != repository.ConditionalOrderStatusPending {
+			t.Fatalf("at %v: golden %s, death %s, want both pending before a cross", price, status(golden), status(death))
+		}
+	}
+
+	// The fast MA crosses above: the golden cross fires, the death cross waits for the way down
+	tick(13.5)
+	if status(golden) != repository.ConditionalOrderStatusExecuted {
+		t.Errorf("golden cross order = %s, want executed", status(golden))
+	}
+	if status(death) != repository.ConditionalOrderStatusPending {
+		t.Errorf("death cross order = %s, want pending", status(death))
+	}
+
+	// Staying above does not fire again; crossing back below fires the death cross
+	tick(14)
+	if status(death) != repository.ConditionalOrderStatusPending {
+		t.Errorf("death cross order above the slow MA = %s, want pending", status(death))
+	}
+	tick(12)
+	if status(death) != repository.ConditionalOrderStatusExecuted {
+		t.Errorf("death cross order = %s, want executed", status(death))
+	}
+	if market.fetches != 1 {
+		t.Errorf("kline fetches within one candle = %d, want 1", market.fetches)
+	}
+}
+
+func TestMonitoringEngine_MACrossoverNeedsPreviousSide(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 5, 0, 0, time.UTC)
+	market := &rsiMarketDataService{
+		mockMarketDataService: mockMarketDataService{prices: map[string]float64{"BTCUSDT": 20}},
+		closes:                []float64{15, 14, 13, 12, 11, 10, 10},
+		now:                   &now,
+	}
+	repo := repository.NewMemoryConditionalOrderRepository()
+	engine := NewMonitoringEngine(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+		&mockTradingService{}, market, &mockStopLossService{}, &mockLogger{}, nil)
+	engine.now = func() time.Time { return now }
+
+	// Created while the fast MA is already above: nothing crossed since it was first seen
+	order := newMACrossoverOrder("late", repository.OperatorGreaterEqual, 2, 4)
+	repo.Save(order)
+	for i := 0; i < 3; i++ {
+		engine.processOrder(order)
+	}
+	if updated, _ := repo.FindByID(order.OrderID); updated.Status != repository.ConditionalOrderStatusPending {
+		t.Errorf("order status = %s, want pending without a cross", updated.Status)
+	}
+
+	// The side of an order that is no longer active is forgotten on reload
+	repo.UpdateStatus(order.OrderID, repository.ConditionalOrderStatusCancelled, 0, 0)
+	engine.mu.Lock()
+	engine.loadActiveOrders()
+	_, kept := engine.maCrossSides[order.OrderID]
+	engine.mu.Unlock()
+	if kept {
+		t.Error("expected the crossover state of a cancelled order to be dropped")
+	}
+}
+
+func TestConditionalOrderService_ValidateMACrossoverCondition(t *testing.T) {
+	svc := NewConditionalOrderService(repository.NewMemoryConditionalOrderRepository(), repository.NewMemoryStopOrderRepository(),
+		NewTriggerEngine(), &mockTradingService{}, &mockMarketDataService{}, &mockStopLossService{}, &mockLogger{})
+
+	cross := func(fast, slow int, interval string, value float64) *repository.TriggerCondition {
+		return &repository.TriggerCondition{Type: repository.TriggerTypeMACrossover, Operator: repository.OperatorGreaterThan,
+			FastPeriod: fast, SlowPeriod: slow, Interval: interval, Value: value}
+	}
+	tests := []struct {
+		name      string
+		condition *repository.TriggerCondition
+		wantErr   bool
+	}{
+		{"valid", cross(9, 21, "15m", 0), false},
+		{"default interval", cross(50, 200, "", 0), false},
+		{"no fast period", cross(0, 21, "1h", 0), true},
+		{"fast not shorter", cross(21, 21, "1h", 0), true},
+		{"slow too long", cross(9, maxMACrossoverPeriod+1, "1h", 0), true},
+		{"unknown interval", cross(9, 21, "7m", 0), true},
+		{"threshold", cross(9, 21, "1h", 5), true},
+		{"inside a composite", &repository.TriggerCondition{
+			CompositeType: repository.LogicAND,
+			SubConditions: []*repository.TriggerCondition{
+				cross(9, 21, "1h", 0),
+				{Type: repository.TriggerTypePrice, Operator: repository.OperatorGreaterThan, Value: 50000},
+			},
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateConditionalOrder(&repository.ConditionalOrderRequest{
+				Symbol:           "BTCUSDT",
+				Side:             api.OrderSideBuy,
+				Type:             api.OrderTypeMarket,
+				Quantity:         0.01,
+				TriggerCondition: tt.condition,
+				AllowDuplicate:   true,
+			})
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("CreateConditionalOrder() error = %v", err)
+				}
+				return
+			}
+			if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrInvalidTriggerCondition {
+				t.Errorf("CreateConditionalOrder() error = %v, want ErrInvalidTriggerCondition", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/pkg/errors"
+	"binance-trader/pkg/logger"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MaintenanceState represents the current exchange maintenance state
+type MaintenanceState struct {
+	InMaintenance       bool
+	Since               int64
+	Message             string
+	ConsecutiveFailures int
+	Scheduled           bool // Paused for an announced maintenance window
+}
+
+// MaintenanceMonitor detects exchange maintenance windows and pauses trading while they last
+type MaintenanceMonitor interface {
+	// RecordResult tracks API call results; consecutive server errors trigger a status check
+	RecordResult(err error)
+	IsInMaintenance() bool
+	GetState() *MaintenanceState
+
+	// CheckStatus polls the exchange system status and updates the maintenance state
+	CheckStatus() (*api.SystemStatus, error)
+
+	// SetScheduledPause pauses or resumes trading for an announced maintenance window
+	SetScheduledPause(paused bool, message string)
+
+	// OnResume registers a callback invoked when maintenance ends
+	OnResume(callback func())
+	Stop()
+}
+
+// MaintenanceMonitorConfig holds configuration for the maintenance monitor
+type MaintenanceMonitorConfig struct {
+	FailureThreshold int
+	PollInterval     time.Duration
+}
+
+// maintenanceMonitor implements MaintenanceMonitor interface
+type maintenanceMonitor struct {
+	client           api.SpotClient
+	logger           logger.Logger
+	failureThreshold int
+	pollInterval     time.Duration
+
+	mu                  sync.RWMutex
+	inMaintenance       bool
+	since               int64
+	message             string
+	consecutiveFailures int
+	scheduled           bool
+	scheduledMessage    string
+	resumeCallbacks     []func()
+	stopChan            chan struct{}
+}
+
+// NewMaintenanceMonitor creates a new maintenance monitor. A nil client disables status
+// detection so the monitor only pauses for scheduled windows.
+func NewMaintenanceMonitor(client api.SpotClient, log logger.Logger, config *MaintenanceMonitorConfig) MaintenanceMonitor {
+	if config == nil {
+		config = &MaintenanceMonitorConfig{
+			FailureThreshold: 3,
+			PollInterval:     30 * time.Second,
+		}
+	}
+
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 3
+	}
+
+	if config.PollInterval <= 0 {
+		config.PollInterval = 30 * time.Second
+	}
+
+	return &maintenanceMonitor{
+		client:           client,
+		logger:           log,
+		failureThreshold: config.FailureThreshold,
+		pollInterval:     config.PollInterval,
+	}
+}
+
+// RecordResult tracks consecutive server errors and checks system status when the threshold is reached
+func (m *maintenanceMonitor) RecordResult(err error) {
+	m.mu.Lock()
+	if err == nil {
+		m.consecutiveFailures = 0
+		m.mu.Unlock()
+		return
+	}
+
+	if !isServerError(err) {
+		m.mu.Unlock()
+		return
+	}
+
+	m.consecutiveFailures++
+	shouldCheck := m.client != nil && !m.inMaintenance && m.consecutiveFailures >= m.failureThreshold
+	m.mu.Unlock()
+
+	if shouldCheck {
+		if _, checkErr := m.CheckStatus(); checkErr != nil {
+			m.logger.Warn("Failed to check exchange system status", map[string]interface{}{
+				"error": checkErr.Error(),
+			})
+		}
+	}
+}
+
+// IsInMaintenance returns whether the exchange is currently in maintenance
+func (m *maintenanceMonitor) IsInMaintenance() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.inMaintenance || m.scheduled
+}
+
+// GetState returns a snapshot of the maintenance state
+func (m *maintenanceMonitor) GetState() *MaintenanceState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	state := &MaintenanceState{
+		InMaintenance:       m.inMaintenance || m.scheduled,
+		Since:               m.since,
+		Message:             m.message,
+		ConsecutiveFailures: m.consecutiveFailures,
+		Scheduled:           m.scheduled,
+	}
+	if !m.inMaintenance && m.scheduled {
+		state.Message = m.scheduledMessage
+	}
+	return state
+}
+
+// CheckStatus polls the exchange system status and enters or leaves maintenance accordingly
+func (m *maintenanceMonitor) CheckStatus() (*api.SystemStatus, error) {
+	if m.client == nil {
+		return nil, fmt.Errorf("system status client not configured")
+	}
+
+	status, err := m.client.GetSystemStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	if status.IsMaintenance() {
+		m.enterMaintenance(status.Msg)
+	} else {
+		m.exitMaintenance()
+	}
+
+	return status, nil
+}
+
+// SetScheduledPause pauses trading for an announced window; resume callbacks run when the
+// pause is lifted unless detected maintenance is still ongoing
+func (m *maintenanceMonitor) SetScheduledPause(paused bool, message string) {
+	m.mu.Lock()
+	if m.scheduled == paused {
+		m.mu.Unlock()
+		return
+	}
+
+	m.scheduled = paused
+	m.scheduledMessage = message
+	if paused {
+		m.mu.Unlock()
+		return
+	}
+
+	var callbacks []func()
+	if !m.inMaintenance {
+		callbacks = make([]func(), len(m.resumeCallbacks))
+		copy(callbacks, m.resumeCallbacks)
+	}
+	m.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback()
+	}
+}
+
+// OnResume registers a callback invoked when maintenance ends
+func (m *maintenanceMonitor) OnResume(callback func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resumeCallbacks = append(m.resumeCallbacks, callback)
+}
+
+// Stop stops status polling
+func (m *maintenanceMonitor) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stopChan != nil {
+		close(m.stopChan)
+		m.stopChan = nil
+	}
+}
+
+// enterMaintenance switches to maintenance mode and starts polling for recovery
+func (m *maintenanceMonitor) enterMaintenance(message string) {
+	m.mu.Lock()
+	if m.inMaintenance {
+		m.mu.Unlock()
+		return
+	}
+
+	m.inMaintenance = true
+	m.since = time.Now().Unix()
+	m.message = message
+	m.stopChan = make(chan struct{})
+	stopChan := m.stopChan
+	m.mu.Unlock()
+
+	// Single notification for the whole maintenance window
+	m.logger.Warn("Exchange maintenance detected, pausing trigger executions and order placements", map[string]interface{}{
+		"message":       message,
+		"poll_interval": m.pollInterval.String(),
+	})
+
+	go m.pollLoop(stopChan)
+}
+
+// exitMaintenance leaves maintenance mode and notifies resume callbacks
+func (m *maintenanceMonitor) exitMaintenance() {
+	m.mu.Lock()
+	if !m.inMaintenance {
+		m.mu.Unlock()
+		return
+	}
+
+	duration := time.Now().Unix() - m.since
+	m.inMaintenance = false
+	m.since = 0
+	m.message = ""
+	m.consecutiveFailures = 0
+	if m.stopChan != nil {
+		close(m.stopChan)
+		m.stopChan = nil
+	}
+	var callbacks []func()
+	if !m.scheduled {
+		callbacks = make([]func(), len(m.resumeCallbacks))
+		copy(callbacks, m.resumeCallbacks)
+	}
+	m.mu.Unlock()
+
+	m.logger.Info("Exchange maintenance ended, resuming trading", map[string]interface{}{
+		"duration_seconds": duration,
+	})
+
+	for _, callback := range callbacks {
+		callback()
+	}
+}
+
+// pollLoop polls the system status until maintenance ends
+func (m *maintenanceMonitor) pollLoop(stopChan chan struct{}) {
+	ticker := time.NewTicker(m.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			if _, err := m.CheckStatus(); err != nil {
+				m.logger.Debug("System status poll failed during maintenance", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+		}
+	}
+}
+
+// isServerError checks if an error is an exchange-side (5xx) failure, also when it comes
+// wrapped by the service that made the call
+func isServerError(err error) bool {
+	var tradingErr *errors.TradingError
+	if !stderrors.As(err, &tradingErr) {
+		return false
+	}
+	return tradingErr.Type == errors.ErrNetwork && tradingErr.Code >= 500
+}
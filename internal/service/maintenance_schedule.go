@@ -0,0 +1,292 @@
+package service
+
+import (
+	"binance-trader/internal/config"
+	"binance-trader/pkg/logger"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Defaults for the scheduled maintenance check
+const (
+	DefaultMaintenanceCheckInterval = 10 * time.Second
+	DefaultMaintenanceLeadTime      = 5 * time.Minute
+)
+
+// ScheduledMaintenanceWindow is an announced exchange maintenance window
+type ScheduledMaintenanceWindow struct {
+	Start   int64 // Milliseconds since epoch
+	End     int64
+	PauseAt int64 // Start minus the configured lead time
+	Active  bool  // Trading is currently paused for this window
+}
+
+// MaintenanceScheduler pauses trading shortly before announced maintenance windows and
+// resumes it once they end, optionally flattening futures positions first
+type MaintenanceScheduler interface {
+	// CheckWindows evaluates the schedule against the current time
+	CheckWindows()
+	IsPaused() bool
+
+	// GetWindows returns the windows that have not ended yet, earliest first
+	GetWindows() []*ScheduledMaintenanceWindow
+
+	// SetFuturesServices sets the services used to flatten futures positions before a window
+	SetFuturesServices(positionManager FuturesPositionManager, tradingService FuturesTradingService)
+
+	// Scheduled check
+	StartMonitoring(checkInterval time.Duration) error
+	StopMonitoring() error
+}
+
+// maintenanceWindow is a parsed window
+type maintenanceWindow struct {
+	start time.Time
+	end   time.Time
+}
+
+// maintenanceScheduler implements MaintenanceScheduler
+type maintenanceScheduler struct {
+	monitor         MaintenanceMonitor
+	windows         []maintenanceWindow
+	leadTime        time.Duration
+	autoFlatten     bool
+	positionManager FuturesPositionManager
+	tradingService  FuturesTradingService
+	logger          logger.Logger
+	now             func() time.Time
+
+	mu     sync.Mutex
+	paused bool
+	active maintenanceWindow
+
+	monitoringMu sync.Mutex
+	stopChan     chan struct{}
+	isMonitoring bool
+}
+
+// NewMaintenanceScheduler creates a scheduler that pauses trading through the maintenance monitor
+func NewMaintenanceScheduler(monitor MaintenanceMonitor, cfg *config.MaintenanceConfig, log logger.Logger) MaintenanceScheduler {
+	if cfg == nil {
+		cfg = &config.MaintenanceConfig{}
+	}
+
+	s := &maintenanceScheduler{
+		monitor:     monitor,
+		leadTime:    time.Duration(cfg.LeadTimeMs) * time.Millisecond,
+		autoFlatten: cfg.AutoFlatten,
+		logger:      log,
+		now:         time.Now,
+	}
+
+	if s.leadTime <= 0 {
+		s.leadTime = DefaultMaintenanceLeadTime
+	}
+
+	for _, window := range cfg.Windows {
+		start, end, err := window.Bounds()
+		if err != nil {
+			log.Warn("Ignoring invalid maintenance window", map[string]interface{}{
+				"start": window.Start,
+				"end":   window.End,
+				"error": err.Error(),
+			})
+			continue
+		}
+		s.windows = append(s.windows, maintenanceWindow{start: start, end: end})
+	}
+
+	sort.Slice(s.windows, func(i, j int) bool {
+		return s.windows[i].start.Before(s.windows[j].start)
+	})
+
+	return s
+}
+
+// SetFuturesServices sets the services used to flatten futures positions
+func (s *maintenanceScheduler) SetFuturesServices(positionManager FuturesPositionManager, tradingService FuturesTradingService) {
+	s.positionManager = positionManager
+	s.tradingService = tradingService
+}
+
+// CheckWindows pauses trading within the lead time of a window and resumes once no window applies
+func (s *maintenanceScheduler) CheckWindows() {
+	now := s.now()
+	window, inWindow := s.currentWindow(now)
+
+	s.mu.Lock()
+	wasPaused := s.paused
+	previous := s.active
+	s.paused = inWindow
+	s.active = window
+	s.mu.Unlock()
+
+	switch {
+	case inWindow && !wasPaused:
+		s.monitor.SetScheduledPause(true, fmt.Sprintf("scheduled maintenance %s - %s UTC",
+			window.start.Format(time.RFC3339), window.end.Format(time.RFC3339)))
+
+		s.logger.Warn("Scheduled exchange maintenance approaching, pausing new orders", map[string]interface{}{
+			"window_start": window.start.Format(time.RFC3339),
+			"window_end":   window.end.Format(time.RFC3339),
+			"lead_time":    s.leadTime.String(),
+			"auto_flatten": s.autoFlatten,
+		})
+
+		if s.autoFlatten {
+			s.flattenPositions()
+		}
+
+	case !inWindow && wasPaused:
+		s.monitor.SetScheduledPause(false, "")
+
+		s.logger.Info("Scheduled exchange maintenance ended, resuming trading", map[string]interface{}{
+			"window_start": previous.start.Format(time.RFC3339),
+			"window_end":   previous.end.Format(time.RFC3339),
+		})
+	}
+}
+
+// IsPaused returns whether trading is paused for a scheduled window
+func (s *maintenanceScheduler) IsPaused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.paused
+}
+
+// GetWindows returns the windows that have not ended yet
+func (s *maintenanceScheduler) GetWindows() []*ScheduledMaintenanceWindow {
+	now := s.now()
+
+	result := make([]*ScheduledMaintenanceWindow, 0, len(s.windows))
+	for _, window := range s.windows {
+		if !now.Before(window.end) {
+			continue
+		}
+		pauseAt := window.start.Add(-s.leadTime)
+		result = append(result, &ScheduledMaintenanceWindow{
+			Start:   window.start.UnixMilli(),
+			End:     window.end.UnixMilli(),
+			PauseAt: pauseAt.UnixMilli(),
+			Active:  !now.Before(pauseAt),
+		})
+	}
+	return result
+}
+
+// StartMonitoring starts the scheduled maintenance check
+func (s *maintenanceScheduler) StartMonitoring(checkInterval time.Duration) error {
+	s.monitoringMu.Lock()
+	defer s.monitoringMu.Unlock()
+
+	if s.isMonitoring {
+		return fmt.Errorf("monitoring is already running")
+	}
+
+	if checkInterval <= 0 {
+		checkInterval = DefaultMaintenanceCheckInterval
+	}
+
+	s.stopChan = make(chan struct{})
+	s.isMonitoring = true
+
+	go s.monitoringLoop(checkInterval)
+
+	s.logger.Info("Started scheduled maintenance monitoring", map[string]interface{}{
+		"check_interval": checkInterval.String(),
+		"windows":        len(s.windows),
+		"lead_time":      s.leadTime.String(),
+		"auto_flatten":   s.autoFlatten,
+	})
+
+	return nil
+}
+
+// StopMonitoring stops the scheduled maintenance check
+func (s *maintenanceScheduler) StopMonitoring() error {
+	s.monitoringMu.Lock()
+	defer s.monitoringMu.Unlock()
+
+	if !s.isMonitoring {
+		return fmt.Errorf("monitoring is not running")
+	}
+
+	close(s.stopChan)
+	s.isMonitoring = false
+
+	s.logger.Info("Stopped scheduled maintenance monitoring", nil)
+
+	return nil
+}
+
+// monitoringLoop checks the schedule immediately and then on every tick
+func (s *maintenanceScheduler) monitoringLoop(checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	s.CheckWindows()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			s.CheckWindows()
+		}
+	}
+}
+
+// currentWindow returns the window whose pause period contains now
+func (s *maintenanceScheduler) currentWindow(now time.Time) (maintenanceWindow, bool) {
+	for _, window := range s.windows {
+		if !now.Before(window.start.Add(-s.leadTime)) && now.Before(window.end) {
+			return window, true
+		}
+	}
+	return maintenanceWindow{}, false
+}
+
+// flattenPositions closes every open futures position
+func (s *maintenanceScheduler) flattenPositions() {
+	if s.positionManager == nil || s.tradingService == nil {
+		return
+	}
+
+	positions, err := s.positionManager.GetAllPositions()
+	if err != nil {
+		s.logger.Warn("Failed to load positions before scheduled maintenance", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, position := range positions {
+		if position.PositionAmt == 0 || seen[position.Symbol] {
+			continue
+		}
+		seen[position.Symbol] = true
+		symbols = append(symbols, position.Symbol)
+	}
+	sort.Strings(symbols)
+
+	closed := 0
+	for _, symbol := range symbols {
+		if _, err := s.tradingService.CloseAllPositions(symbol); err != nil {
+			s.logger.Warn("Failed to flatten position before scheduled maintenance", map[string]interface{}{
+				"symbol": symbol,
+				"error":  err.Error(),
+			})
+			continue
+		}
+		closed++
+	}
+
+	s.logger.Info("Flattened positions before scheduled maintenance", map[string]interface{}{
+		"symbols": len(symbols),
+		"closed":  closed,
+	})
+}
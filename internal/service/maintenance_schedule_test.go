@@ -0,0 +1,168 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"testing"
+	"time"
+)
+
+// flattenRecordingTradingService records the symbols closed by CloseAllPositions
+type flattenRecordingTradingService struct {
+	mockFuturesTradingServiceShared
+	closed []string
+}
+
+func (s *flattenRecordingTradingService) CloseAllPositions(symbol string) ([]*api.FuturesOrder, error) {
+	s.closed = append(s.closed, symbol)
+	return nil, nil
+}
+
+func newTestMaintenanceScheduler(autoFlatten bool) (*maintenanceScheduler, MaintenanceMonitor, *maintenanceWarnLogger, *time.Time) {
+	log := &maintenanceWarnLogger{}
+	monitor := NewMaintenanceMonitor(nil, log, nil)
+	cfg := &config.MaintenanceConfig{
+		Windows: []config.MaintenanceWindowConfig{
+			{Start: "2024-06-01T02:00:00Z", End: "2024-06-01T04:00:00Z"},
+		},
+		LeadTimeMs:  int((5 * time.Minute).Milliseconds()),
+		AutoFlatten: autoFlatten,
+	}
+	scheduler := NewMaintenanceScheduler(monitor, cfg, log).(*maintenanceScheduler)
+
+	current := time.Date(2024, 6, 1, 1, 50, 0, 0, time.UTC)
+	scheduler.now = func() time.Time { return current }
+	return scheduler, monitor, log, &current
+}
+
+func TestMaintenanceScheduler_PausesBeforeWindowAndResumesAfter(t *testing.T) {
+	scheduler, monitor, log, current := newTestMaintenanceScheduler(false)
+
+	resumed := 0
+	monitor.OnResume(func() { resumed++ })
+
+	scheduler.CheckWindows()
+	if scheduler.IsPaused() || monitor.IsInMaintenance() {
+		t.Fatal("trading should not be paused before the lead time")
+	}
+
+	// Within the lead time new orders are paused
+	*current = time.Date(2024, 6, 1, 1, 56, 0, 0, time.UTC)
+	scheduler.CheckWindows()
+	if !scheduler.IsPaused() || !monitor.IsInMaintenance() {
+		t.Fatal("trading should be paused within the lead time")
+	}
+	state := monitor.GetState()
+	if !state.Scheduled || state.Message == "" {
+		t.Errorf("expected scheduled maintenance state, got %+v", state)
+	}
+	if log.warnCount() != 1 {
+		t.Errorf("expected one pause notification, got %d", log.warnCount())
+	}
+
+	// Still paused during the window without further notifications
+	*current = time.Date(2024, 6, 1, 3, 0, 0, 0, time.UTC)
+	scheduler.CheckWindows()
+	if !monitor.IsInMaintenance() || log.warnCount() != 1 {
+		t.Errorf("expected a single notification while paused, got %d", log.warnCount())
+	}
+	if resumed != 0 {
+		t.Errorf("resume callbacks should not run during the window, got %d", resumed)
+	}
+
+	// Trading resumes once the window ends
+	*current = time.Date(2024, 6, 1, 4, 0, 0, 0, time.UTC)
+	scheduler.CheckWindows()
+	if scheduler.IsPaused() || monitor.IsInMaintenance() {
+		t.Fatal("trading should resume after the window")
+	}
+	if resumed != 1 {
+		t.Errorf("expected resume callbacks once, got %d", resumed)
+	}
+}
+
+func TestMaintenanceScheduler_AutoFlatten(t *testing.T) {
+	positionMgr := &mockFuturesPositionManagerShared{positions: map[string]*api.Position{
+		"BTCUSDTLONG":  {Symbol: "BTCUSDT", PositionSide: api.PositionSideLong, PositionAmt: 0.1},
+		"ETHUSDTSHORT": {Symbol: "ETHUSDT", PositionSide: api.PositionSideShort, PositionAmt: -1},
+		"XRPUSDTBOTH":  {Symbol: "XRPUSDT", PositionSide: api.PositionSideBoth},
+	}}
+
+	for _, autoFlatten := range []bool{true, false} {
+		scheduler, _, _, current := newTestMaintenanceScheduler(autoFlatten)
+		tradingService := &flattenRecordingTradingService{}
+		scheduler.SetFuturesServices(positionMgr, tradingService)
+
+		*current = time.Date(2024, 6, 1, 1, 55, 0, 0, time.UTC)
+		scheduler.CheckWindows()
+		scheduler.CheckWindows()
+
+		if !autoFlatten {
+			if len(tradingService.closed) != 0 {
+				t.Errorf("positions should be kept when auto flatten is disabled, closed %v", tradingService.closed)
+			}
+			continue
+		}
+		if len(tradingService.closed) != 2 || tradingService.closed[0] != "BTCUSDT" || tradingService.closed[1] != "ETHUSDT" {
+			t.Errorf("expected BTCUSDT and ETHUSDT flattened once, got %v", tradingService.closed)
+		}
+	}
+}
+
+func TestMaintenanceScheduler_GetWindows(t *testing.T) {
+	log := &maintenanceWarnLogger{}
+	cfg := &config.MaintenanceConfig{
+		Windows: []config.MaintenanceWindowConfig{
+			{Start: "2024-06-08T02:00:00Z", End: "2024-06-08T03:00:00Z"},
+			{Start: "2024-05-01T02:00:00Z", End: "2024-05-01T03:00:00Z"},
+			{Start: "2024-06-01T02:00:00Z", End: "2024-06-01T04:00:00Z"},
+			{Start: "invalid", End: "2024-06-01T04:00:00Z"},
+		},
+	}
+	scheduler := NewMaintenanceScheduler(NewMaintenanceMonitor(nil, log, nil), cfg, log).(*maintenanceScheduler)
+	current := time.Date(2024, 6, 1, 1, 57, 0, 0, time.UTC)
+	scheduler.now = func() time.Time { return current }
+
+	windows := scheduler.GetWindows()
+	if len(windows) != 2 {
+		t.Fatalf("expected 2 upcoming windows, got %d", len(windows))
+	}
+	first := windows[0]
+	if first.Start != time.Date(2024, 6, 1, 2, 0, 0, 0, time.UTC).UnixMilli() || !first.Active {
+		t.Errorf("expected the active 2024-06-01 window first, got %+v", first)
+	}
+	if first.PauseAt != time.Date(2024, 6, 1, 1, 55, 0, 0, time.UTC).UnixMilli() {
+		t.Errorf("expected the default lead time before the window, got %+v", first)
+	}
+	if windows[1].Active {
+		t.Errorf("later window should not be active, got %+v", windows[1])
+	}
+}
+
+func TestMaintenanceMonitor_ScheduledPauseWithDetectedMaintenance(t *testing.T) {
+	exchange := &maintenanceExchange{}
+	client := &mockBinanceClient{getSystemStatusFunc: exchange.systemStatus}
+	monitor := NewMaintenanceMonitor(client, &maintenanceWarnLogger{}, &MaintenanceMonitorConfig{FailureThreshold: 1, PollInterval: time.Hour})
+	defer monitor.Stop()
+
+	resumed := 0
+	monitor.OnResume(func() { resumed++ })
+
+	monitor.SetScheduledPause(true, "scheduled maintenance")
+	exchange.setMaintenance(true)
+	if _, err := monitor.CheckStatus(); err != nil {
+		t.Fatalf("CheckStatus failed: %v", err)
+	}
+
+	// Lifting the scheduled pause keeps trading paused while maintenance is still detected
+	monitor.SetScheduledPause(false, "")
+	if !monitor.IsInMaintenance() || resumed != 0 {
+		t.Fatalf("expected detected maintenance to keep trading paused, resumed %d", resumed)
+	}
+
+	exchange.setMaintenance(false)
+	monitor.CheckStatus()
+	if monitor.IsInMaintenance() || resumed != 1 {
+		t.Errorf("expected trading to resume once, resumed %d", resumed)
+	}
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// maintenanceWarnLogger counts warnings emitted during maintenance transitions
+type maintenanceWarnLogger struct {
+	mockLogger
+	mu    sync.Mutex
+	warns int
+}
+
+func (l *maintenanceWarnLogger) Warn(msg string, fields map[string]interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warns++
+}
+
+func (l *maintenanceWarnLogger) warnCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.warns
+}
+
+// maintenanceExchange simulates an exchange switching between normal and maintenance
+type maintenanceExchange struct {
+	mu            sync.Mutex
+	inMaintenance bool
+	placedOrders  int
+	failedOrders  int
+}
+
+func (e *maintenanceExchange) setMaintenance(inMaintenance bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.inMaintenance = inMaintenance
+}
+
+func (e *maintenanceExchange) systemStatus() (*api.SystemStatus, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.inMaintenance {
+		return &api.SystemStatus{Status: api.SystemStatusMaintenance, Msg: "system maintenance"}, nil
+	}
+	return &api.SystemStatus{Status: api.SystemStatusNormal, Msg: "normal"}, nil
+}
+
+func (e *maintenanceExchange) counts() (int, int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.placedOrders, e.failedOrders
+}
+
+// maintenanceTradingService fails order placement with 503 while the exchange is in maintenance
+type maintenanceTradingService struct {
+	mockTradingService
+	exchange *maintenanceExchange
+}
+
+func (m *maintenanceTradingService) PlaceMarketBuyOrder(symbol string, quantity float64) (*api.Order, error) {
+	m.exchange.mu.Lock()
+	defer m.exchange.mu.Unlock()
+	if m.exchange.inMaintenance {
+		m.exchange.failedOrders++
+		return nil, errors.NewTradingError(errors.ErrNetwork, "HTTP server error: 503", 503, nil)
+	}
+	m.exchange.placedOrders++
+	return &api.Order{OrderID: 777, Symbol: symbol, Side: api.OrderSideBuy, Type: api.OrderTypeMarket, OrigQty: quantity}, nil
+}
+
+func TestMaintenanceMonitor_DetectsMaintenanceAfterServerErrors(t *testing.T) {
+	exchange := &maintenanceExchange{inMaintenance: true}
+	client := &mockBinanceClient{getSystemStatusFunc: exchange.systemStatus}
+	log := &maintenanceWarnLogger{}
+	monitor := NewMaintenanceMonitor(client, log, &MaintenanceMonitorConfig{
+		FailureThreshold: 2,
+		PollInterval:     time.Hour,
+	})
+	defer monitor.Stop()
+
+	serverErr := errors.NewTradingError(errors.ErrNetwork, "HTTP server error: 503", 503, nil)
+
+	// Client errors do not count towards the threshold
+	monitor.RecordResult(errors.NewTradingError(errors.ErrInvalidParameter, "HTTP client error: 400", 400, nil))
+	monitor.RecordResult(serverErr)
+	if monitor.IsInMaintenance() {
+		t.Fatal("Expected no maintenance before threshold is reached")
+	}
+
+	monitor.RecordResult(serverErr)
+	if !monitor.IsInMaintenance() {
+		t.Fatal("Expected maintenance after consecutive server errors")
+	}
+
+	state := monitor.GetState()
+	if state.Message != "system maintenance" || state.Since == 0 {
+		t.Errorf("Expected maintenance state to be populated, got %+v", state)
+	}
+
+	// Further errors during the window must not emit more notifications
+	monitor.RecordResult(serverErr)
+	monitor.RecordResult(serverErr)
+	if log.warnCount() != 1 {
+		t.Errorf("Expected a single maintenance notification, got %d", log.warnCount())
+	}
+}
+
+func TestMaintenanceMonitor_SuccessResetsFailures(t *testing.T) {
+	exchange := &maintenanceExchange{inMaintenance: true}
+	client := &mockBinanceClient{getSystemStatusFunc: exchange.systemStatus}
+	monitor := NewMaintenanceMonitor(client, &mockLogger{}, &MaintenanceMonitorConfig{
+		FailureThreshold: 2,
+		PollInterval:     time.Hour,
+	})
+	defer monitor.Stop()
+
+	serverErr := errors.NewTradingError(errors.ErrNetwork, "HTTP server error: 502", 502, nil)
+	monitor.RecordResult(serverErr)
+	monitor.RecordResult(nil)
+	monitor.RecordResult(serverErr)
+
+	if monitor.IsInMaintenance() {
+		t.Error("Expected successful call to reset consecutive failures")
+	}
+}
+
+func TestMonitoringEngine_PausesDuringMaintenance(t *testing.T) {
+	exchange := &maintenanceExchange{inMaintenance: true}
+	client := &mockBinanceClient{getSystemStatusFunc: exchange.systemStatus}
+	monitor := NewMaintenanceMonitor(client, &mockLogger{}, &MaintenanceMonitorConfig{
+		FailureThreshold: 1,
+		PollInterval:     10 * time.Millisecond,
+	})
+	defer monitor.Stop()
+
+	repo := repository.NewMemoryConditionalOrderRepository()
+	trading := &maintenanceTradingService{exchange: exchange}
+	market := &mockMarketDataService{prices: map[string]float64{"BTCUSDT": 50000}}
+	engine := NewMonitoringEngine(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+		trading, market, &mockStopLossService{}, &mockLogger{}, &MonitoringEngineConfig{UpdateInterval: time.Hour})
+	engine.SetMaintenanceMonitor(monitor)
+
+	repo.Save(&repository.ConditionalOrder{
+		OrderID:  "maint-1",
+		Symbol:   "BTCUSDT",
+		Side:     api.OrderSideBuy,
+		Type:     api.OrderTypeMarket,
+		Quantity: 0.001,
+		TriggerCondition: &repository.TriggerCondition{
+			Type:     repository.TriggerTypePrice,
+			Operator: repository.OperatorGreaterThan,
+			Value:    49000,
+		},
+		Status:    repository.ConditionalOrderStatusPending,
+		CreatedAt: time.Now().Unix(),
+	})
+
+	if err := engine.Start(); err != nil {
+		t.Fatalf("Failed to start engine: %v", err)
+	}
+	defer engine.Stop()
+
+	// First evaluation hits a 503 and detects maintenance
+	engine.checkAndTriggerOrders()
+	if !monitor.IsInMaintenance() {
+		t.Fatal("Expected maintenance to be detected")
+	}
+
+	order, _ := repo.FindByID("maint-1")
+	if order.Status != repository.ConditionalOrderStatusPending {
+		t.Fatalf("Expected order to stay PENDING during maintenance, got %s", order.Status)
+	}
+
+	// Further evaluations are paused while maintenance lasts
+	engine.checkAndTriggerOrders()
+	engine.checkAndTriggerOrders()
+	if _, failed := exchange.counts(); failed != 1 {
+		t.Errorf("Expected no order placements during maintenance, got %d attempts", failed)
+	}
+
+	// Maintenance ends: conditions are re-evaluated without waiting for the next tick
+	exchange.setMaintenance(false)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		order, _ = repo.FindByID("maint-1")
+		if order.Status == repository.ConditionalOrderStatusExecuted {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if order.Status != repository.ConditionalOrderStatusExecuted {
+		t.Fatalf("Expected order to execute after maintenance, got %s", order.Status)
+	}
+	if monitor.IsInMaintenance() {
+		t.Error("Expected maintenance mode to be cleared")
+	}
+	if placed, _ := exchange.counts(); placed != 1 {
+		t.Errorf("Expected exactly one order placement after maintenance, got %d", placed)
+	}
+}
+
+func TestMonitoringEngine_DetectsMaintenanceFromWrappedPriceErrors(t *testing.T) {
+	exchange := &maintenanceExchange{inMaintenance: true}
+	client := &mockBinanceClient{
+		getSystemStatusFunc: exchange.systemStatus,
+		getPriceFunc: func(symbol string) (*api.Price, error) {
+			return nil, errors.NewTradingError(errors.ErrNetwork, "HTTP server error: 503", 503, nil)
+		},
+	}
+	monitor := NewMaintenanceMonitor(client, &mockLogger{}, &MaintenanceMonitorConfig{
+		FailureThreshold: 2,
+		PollInterval:     time.Hour,
+	})
+	defer monitor.Stop()
+
+	// The market data service wraps the client error, as it does in production
+	engine := NewMonitoringEngine(repository.NewMemoryConditionalOrderRepository(), repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+		&mockTradingService{}, NewMarketDataService(client, time.Second), &mockStopLossService{}, &mockLogger{}, &MonitoringEngineConfig{UpdateInterval: time.Hour})
+	engine.SetMaintenanceMonitor(monitor)
+
+	for i := 0; i < 2; i++ {
+		if _, err := engine.getMarketData("BTCUSDT"); err == nil {
+			t.Fatal("getMarketData() expected the 503 to be returned")
+		}
+	}
+	if !monitor.IsInMaintenance() {
+		t.Error("Expected maintenance after wrapped server errors from price polling")
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"binance-trader/internal/api"
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -13,6 +14,32 @@ type MarketDataService interface {
 	GetHistoricalData(symbol string, interval string, limit int) ([]*api.Kline, error)
 	SubscribeToPrice(symbol string, callback func(float64)) error
 	GetVolume(symbol string, timeWindow time.Duration) (float64, error)
+
+	// GetBestBidAsk returns the best bid and ask, from the book ticker cache when one is set
+	GetBestBidAsk(symbol string) (*BestBidAsk, error)
+	SetBookTickerCache(cache BookTickerCache)
+
+	// GetOrderBook returns a depth snapshot; limit must be one of api.OrderBookLimits
+	GetOrderBook(symbol string, limit int) (*api.OrderBook, error)
+
+	// SetPriceStream sets the stream feeding price subscriptions; nil polls REST
+	SetPriceStream(stream PriceStream)
+
+	// SubscribePriceFeed streams the mid price of a symbol's best bid and ask until ctx is
+	// done. The channel holds only the latest price, so a slow reader skips stale ones.
+	SubscribePriceFeed(ctx context.Context, symbol string) (<-chan float64, error)
+
+	// ConnectedStreams returns the streams currently delivering frames, for health checks
+	ConnectedStreams() []string
+}
+
+// PriceStream is the transport pushing market frames, e.g. the WebSocket market stream.
+// Each subscribe method returns a function dropping that subscription.
+type PriceStream interface {
+	SubscribeTicker(symbol string, handler func(*api.TickerEvent)) (func(), error)
+	SubscribeBookTicker(symbol string, handler func(*api.BookTicker)) (func(), error)
+	Connected() bool
+	ConnectedStreams() []string
 }
 
 // priceCache represents a cached price entry
@@ -28,25 +55,53 @@ type volumeCache struct {
 	timestamp  time.Time
 }
 
+// DefaultKlineCacheTTL is how long historical klines are reused when no TTL is configured
+const DefaultKlineCacheTTL = 5 * time.Second
+
+// MarketDataServiceConfig holds the cache settings of the market data service
+type MarketDataServiceConfig struct {
+	CacheTTL      time.Duration // Price and volume cache lifetime, 0 = 1s
+	KlineCacheTTL time.Duration // Historical kline cache lifetime, 0 = DefaultKlineCacheTTL, negative disables it
+}
+
 // marketDataService implements MarketDataService interface
 type marketDataService struct {
 	client       api.BinanceClient
 	priceCache   map[string]*priceCache
 	volumeCache  map[string]*volumeCache
+	klines       *klineCache
 	cacheTTL     time.Duration
 	cacheMutex   sync.RWMutex
+	bookTickers  BookTickerCache
+	priceStream  PriceStream
 }
 
 // NewMarketDataService creates a new market data service
 func NewMarketDataService(client api.BinanceClient, cacheTTL time.Duration) MarketDataService {
+	return NewMarketDataServiceWithConfig(client, &MarketDataServiceConfig{CacheTTL: cacheTTL})
+}
+
+// NewMarketDataServiceWithConfig creates a new market data service with explicit cache settings
+func NewMarketDataServiceWithConfig(client api.BinanceClient, config *MarketDataServiceConfig) MarketDataService {
+	if config == nil {
+		config = &MarketDataServiceConfig{}
+	}
+	
+	cacheTTL := config.CacheTTL
 	if cacheTTL <= 0 {
 		cacheTTL = 1 * time.Second // Default cache TTL
 	}
 	
+	klineTTL := config.KlineCacheTTL
+	if klineTTL == 0 {
+		klineTTL = DefaultKlineCacheTTL
+	}
+	
 	return &marketDataService{
 		client:      client,
 		priceCache:  make(map[string]*priceCache),
 		volumeCache: make(map[string]*volumeCache),
+		klines:      &klineCache{ttl: klineTTL},
 		cacheTTL:    cacheTTL,
 	}
 }
@@ -76,15 +131,18 @@ func (s *marketDataService) GetCurrentPrice(symbol string) (float64, error) {
 		return 0, fmt.Errorf("invalid price received: %f", priceData.Price)
 	}
 	
-	// Update cache
+	s.storePrice(symbol, priceData.Price)
+	return priceData.Price, nil
+}
+
+// storePrice caches the latest price of a symbol
+func (s *marketDataService) storePrice(symbol string, price float64) {
 	s.cacheMutex.Lock()
 	s.priceCache[symbol] = &priceCache{
-		price:     priceData.Price,
+		price:     price,
 		timestamp: time.Now(),
 	}
 	s.cacheMutex.Unlock()
-	
-	return priceData.Price, nil
 }
 
 // GetHistoricalData retrieves historical kline data for a symbol
@@ -101,19 +159,280 @@ func (s *marketDataService) GetHistoricalData(symbol string, interval string, li
 		return nil, fmt.Errorf("limit must be greater than 0")
 	}
 	
+	key := fmt.Sprintf("%s:%s:%d", symbol, interval, limit)
+	now := time.Now()
+	if klines, ok := s.klines.get(key, now); ok {
+		return klines, nil
+	}
+	
 	klines, err := s.client.GetKlines(symbol, interval, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get historical data for %s: %w", symbol, err)
 	}
 	
+	s.klines.put(key, klines, now)
 	return klines, nil
 }
 
-// SubscribeToPrice subscribes to price updates for a symbol
+// klineCache keeps recent kline responses by symbol, interval and limit, so evaluation cycles
+// asking for the same klines many times a second share one request
+type klineCache struct {
+	ttl     time.Duration
+	entries sync.Map // key -> *klineCacheEntry
+}
+
+// klineCacheEntry is one cached kline response
+type klineCacheEntry struct {
+	klines   []*api.Kline
+	expireAt time.Time
+}
+
+// get returns a copy of the cached klines of a key while they are fresh
+func (c *klineCache) get(key string, now time.Time) ([]*api.Kline, bool) {
+	if c.ttl < 0 {
+		return nil, false
+	}
+	value, ok := c.entries.Load(key)
+	if !ok {
+		return nil, false
+	}
+	entry := value.(*klineCacheEntry)
+	if !now.Before(entry.expireAt) {
+		c.entries.CompareAndDelete(key, value)
+		return nil, false
+	}
+	return append([]*api.Kline(nil), entry.klines...), true
+}
+
+// put caches klines fetched at now. The entry expires after the TTL, or earlier when the
+// newest candle closes, so a candle that just closed is never served with its forming values.
+func (c *klineCache) put(key string, klines []*api.Kline, now time.Time) {
+	if c.ttl < 0 {
+		return
+	}
+	expireAt := now.Add(c.ttl)
+	if len(klines) > 0 {
+		if closeAt := time.UnixMilli(klines[len(klines)-1].CloseTime + 1); closeAt.After(now) && closeAt.Before(expireAt) {
+			expireAt = closeAt
+		}
+	}
+	c.entries.Store(key, &klineCacheEntry{
+		klines:   append([]*api.Kline(nil), klines...),
+		expireAt: expireAt,
+	})
+}
+
+// SubscribeToPrice calls callback with the price of a symbol for the life of the service: on
+// every ticker frame while the price stream is connected, and by polling REST once per cache
+// TTL while it is not, e.g. before the first connection or during a reconnect
 func (s *marketDataService) SubscribeToPrice(symbol string, callback func(float64)) error {
-	// This is a placeholder implementation
-	// In a real implementation, this would use WebSocket connections
-	return fmt.Errorf("price subscription not implemented yet")
+	if symbol == "" {
+		return fmt.Errorf("symbol cannot be empty")
+	}
+	if callback == nil {
+		return fmt.Errorf("callback cannot be nil")
+	}
+
+	s.cacheMutex.RLock()
+	stream := s.priceStream
+	s.cacheMutex.RUnlock()
+
+	if stream != nil {
+		_, err := stream.SubscribeTicker(symbol, func(event *api.TickerEvent) {
+			if event.LastPrice <= 0 {
+				return
+			}
+			// Streamed prices also serve GetCurrentPrice, saving the REST request
+			s.storePrice(symbol, event.LastPrice)
+			callback(event.LastPrice)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to price stream for %s: %w", symbol, err)
+		}
+	}
+
+	go s.pollPrice(symbol, callback, stream)
+	return nil
+}
+
+// pollPrice polls REST for a subscribed symbol whenever the price stream is not connected
+func (s *marketDataService) pollPrice(symbol string, callback func(float64), stream PriceStream) {
+	ticker := time.NewTicker(s.cacheTTL)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if stream != nil && stream.Connected() {
+			continue
+		}
+		if price, err := s.GetCurrentPrice(symbol); err == nil {
+			callback(price)
+		}
+	}
+}
+
+// SetPriceStream sets the stream used by subscriptions made after the call
+func (s *marketDataService) SetPriceStream(stream PriceStream) {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+	s.priceStream = stream
+}
+
+// SubscribePriceFeed streams the mid price of every bookTicker frame of a symbol. Without a
+// price stream there is no feed, and callers poll GetCurrentPrice instead.
+func (s *marketDataService) SubscribePriceFeed(ctx context.Context, symbol string) (<-chan float64, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol cannot be empty")
+	}
+
+	s.cacheMutex.RLock()
+	stream := s.priceStream
+	s.cacheMutex.RUnlock()
+	if stream == nil {
+		return nil, fmt.Errorf("no price stream configured")
+	}
+
+	feed := &priceFeed{prices: make(chan float64, 1)}
+	unsubscribe, err := stream.SubscribeBookTicker(symbol, func(ticker *api.BookTicker) {
+		if ticker.BidPrice > 0 && ticker.AskPrice > 0 {
+			feed.publish((ticker.BidPrice + ticker.AskPrice) / 2)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to price feed for %s: %w", symbol, err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		unsubscribe()
+		feed.close()
+	}()
+	return feed.prices, nil
+}
+
+// ConnectedStreams returns the subscribed streams while the price stream is connected
+func (s *marketDataService) ConnectedStreams() []string {
+	s.cacheMutex.RLock()
+	stream := s.priceStream
+	s.cacheMutex.RUnlock()
+
+	if stream == nil {
+		return nil
+	}
+	return stream.ConnectedStreams()
+}
+
+// priceFeed is a channel of the latest price; publishing replaces an unread price
+type priceFeed struct {
+	mu     sync.Mutex
+	prices chan float64
+	closed bool
+}
+
+// publish offers a price, dropping the unread one if the reader is behind
+func (f *priceFeed) publish(price float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return
+	}
+	select {
+	case <-f.prices:
+	default:
+	}
+	f.prices <- price
+}
+
+// close ends the feed; frames still in flight are dropped
+func (f *priceFeed) close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	close(f.prices)
+}
+
+// SetBookTickerCache sets the stream-fed cache used for best bid/ask
+func (s *marketDataService) SetBookTickerCache(cache BookTickerCache) {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+	s.bookTickers = cache
+}
+
+// GetBestBidAsk returns the best bid and ask, polling REST when no book ticker cache is set
+func (s *marketDataService) GetBestBidAsk(symbol string) (*BestBidAsk, error) {
+	s.cacheMutex.RLock()
+	cache := s.bookTickers
+	s.cacheMutex.RUnlock()
+	
+	if cache != nil {
+		return cache.GetBestBidAsk(symbol)
+	}
+	return fetchBestBidAsk(s.client, symbol, time.Now())
+}
+
+// GetOrderBook retrieves a depth snapshot of a symbol from REST
+func (s *marketDataService) GetOrderBook(symbol string, limit int) (*api.OrderBook, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol cannot be empty")
+	}
+	
+	return s.client.GetOrderBook(symbol, limit)
+}
+
+// volumeKlineIntervals are the kline intervals volume windows are summed over, finest first
+var volumeKlineIntervals = []struct {
+	interval string
+	step     time.Duration
+}{
+	{"1m", time.Minute},
+	{"5m", 5 * time.Minute},
+	{"15m", 15 * time.Minute},
+	{"1h", time.Hour},
+}
+
+// volumeKlineInterval returns the finest kline interval whose 1000 klines cover a window:
+// 1-minute klines up to about 16 hours, 5-minute ones for a 24h window
+func volumeKlineInterval(timeWindow time.Duration) (string, time.Duration) {
+	for _, candidate := range volumeKlineIntervals {
+		if timeWindow <= 1000*candidate.step {
+			return candidate.interval, candidate.step
+		}
+	}
+	last := volumeKlineIntervals[len(volumeKlineIntervals)-1]
+	return last.interval, last.step
+}
+
+// volumeKlineRequest returns the interval and number of klines to fetch for a volume window
+func volumeKlineRequest(timeWindow time.Duration) (string, int) {
+	// Use the finest interval that covers the window within the limit of 1000 klines
+	interval, step := volumeKlineInterval(timeWindow)
+	limit := int(timeWindow / step)
+	
+	// Binance API has a maximum limit of 1000 klines
+	if limit > 1000 {
+		limit = 1000
+	}
+	
+	// If time window is less than 1 minute, use at least 1 kline
+	if limit < 1 {
+		limit = 1
+	}
+	return interval, limit
+}
+
+// windowVolume sums the volume of the klines opened within the window ending at now
+func windowVolume(klines []*api.Kline, timeWindow time.Duration, now time.Time) float64 {
+	windowStart := now.UnixMilli() - timeWindow.Milliseconds()
+	
+	var totalVolume float64
+	for _, kline := range klines {
+		// Only include klines within the time window
+		if kline.OpenTime >= windowStart {
+			totalVolume += kline.Volume
+		}
+	}
+	return totalVolume
 }
 
 // GetVolume retrieves the cumulative volume for a symbol within a time window
@@ -138,22 +457,8 @@ func (s *marketDataService) GetVolume(symbol string, timeWindow time.Duration) (
 		return cached.volume, nil
 	}
 	
-	// Calculate how many klines we need based on time window
-	// Use 1-minute intervals for granular volume data
-	interval := "1m"
-	limit := int(timeWindow.Minutes())
-	
-	// Binance API has a maximum limit of 1000 klines
-	if limit > 1000 {
-		limit = 1000
-	}
-	
-	// If time window is less than 1 minute, use at least 1 kline
-	if limit < 1 {
-		limit = 1
-	}
-	
 	// Fetch klines from API
+	interval, limit := volumeKlineRequest(timeWindow)
 	klines, err := s.client.GetKlines(symbol, interval, limit)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get klines for volume calculation: %w", err)
@@ -163,17 +468,7 @@ func (s *marketDataService) GetVolume(symbol string, timeWindow time.Duration) (
 		return 0, fmt.Errorf("no kline data available for %s", symbol)
 	}
 	
-	// Calculate cumulative volume within the time window
-	now := time.Now().UnixMilli()
-	windowStart := now - timeWindow.Milliseconds()
-	
-	var totalVolume float64
-	for _, kline := range klines {
-		// Only include klines within the time window
-		if kline.OpenTime >= windowStart {
-			totalVolume += kline.Volume
-		}
-	}
+	totalVolume := windowVolume(klines, timeWindow, time.Now())
 	
 	// Update cache
 	s.cacheMutex.Lock()
@@ -2,7 +2,12 @@ package service
 
 import (
 	"binance-trader/internal/api"
+	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -281,6 +286,172 @@ func TestGetHistoricalData_APIError(t *testing.T) {
 	}
 }
 
+// TestGetVolume_LongWindow tests that windows beyond 1000 minutes are summed over coarser klines
+func TestGetVolume_LongWindow(t *testing.T) {
+	tests := []struct {
+		window       time.Duration
+		wantInterval string
+		wantLimit    int
+	}{
+		{time.Hour, "1m", 60},
+		{24 * time.Hour, "5m", 288},
+		{7 * 24 * time.Hour, "15m", 672},
+	}
+	for _, tt := range tests {
+		var interval string
+		var limit int
+		mockClient := &mockBinanceClient{
+			getKlinesFunc: func(symbol string, i string, l int) ([]*api.Kline, error) {
+				interval, limit = i, l
+				return []*api.Kline{{OpenTime: time.Now().UnixMilli(), Volume: 1}}, nil
+			},
+		}
+		if _, err := NewMarketDataService(mockClient, time.Second).GetVolume("BTCUSDT", tt.window); err != nil {
+			t.Fatalf("GetVolume(%s) error = %v", tt.window, err)
+		}
+		if interval != tt.wantInterval || limit != tt.wantLimit {
+			t.Errorf("GetVolume(%s) requested %d %s klines, want %d %s", tt.window, limit, interval, tt.wantLimit, tt.wantInterval)
+		}
+	}
+}
+
+// countingKlineClient returns one forming 1h candle and counts the kline requests
+func countingKlineClient(calls *atomic.Int64) *mockBinanceClient {
+	return &mockBinanceClient{
+		getKlinesFunc: func(symbol string, interval string, limit int) ([]*api.Kline, error) {
+			n := calls.Add(1)
+			closeTime := time.Now().Add(time.Hour).UnixMilli()
+			return []*api.Kline{{Close: float64(n), CloseTime: closeTime}}, nil
+		},
+	}
+}
+
+// TestGetHistoricalData_KlineCache tests that repeated requests within the TTL share one API call
+func TestGetHistoricalData_KlineCache(t *testing.T) {
+	var calls atomic.Int64
+	service := NewMarketDataServiceWithConfig(countingKlineClient(&calls), &MarketDataServiceConfig{KlineCacheTTL: 50 * time.Millisecond})
+
+	for i := 0; i < 5; i++ {
+		klines, err := service.GetHistoricalData("BTCUSDT", "1h", 10)
+		if err != nil || len(klines) != 1 || klines[0].Close != 1 {
+			t.Fatalf("GetHistoricalData() = %v, %v; want the first response", klines, err)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("API calls within the TTL = %d, want 1", calls.Load())
+	}
+
+	// Another symbol, interval or limit is a separate entry
+	service.GetHistoricalData("ETHUSDT", "1h", 10)
+	service.GetHistoricalData("BTCUSDT", "15m", 10)
+	service.GetHistoricalData("BTCUSDT", "1h", 20)
+	if calls.Load() != 4 {
+		t.Errorf("API calls for distinct keys = %d, want 4", calls.Load())
+	}
+
+	// Expired entries are fetched again
+	time.Sleep(100 * time.Millisecond)
+	if klines, _ := service.GetHistoricalData("BTCUSDT", "1h", 10); klines[0].Close != 5 {
+		t.Errorf("close after expiry = %v, want the fresh response", klines[0].Close)
+	}
+}
+
+// TestGetHistoricalData_KlineCacheCandleClose tests that cached klines expire when their newest candle closes
+func TestGetHistoricalData_KlineCacheCandleClose(t *testing.T) {
+	var calls atomic.Int64
+	closeTime := time.Now().Add(30 * time.Millisecond).UnixMilli()
+	mockClient := &mockBinanceClient{
+		getKlinesFunc: func(symbol string, interval string, limit int) ([]*api.Kline, error) {
+			calls.Add(1)
+			return []*api.Kline{{Close: 100, CloseTime: closeTime}}, nil
+		},
+	}
+	service := NewMarketDataServiceWithConfig(mockClient, &MarketDataServiceConfig{KlineCacheTTL: time.Hour})
+
+	service.GetHistoricalData("BTCUSDT", "1m", 10)
+	service.GetHistoricalData("BTCUSDT", "1m", 10)
+	time.Sleep(60 * time.Millisecond)
+	service.GetHistoricalData("BTCUSDT", "1m", 10)
+	if calls.Load() != 2 {
+		t.Errorf("API calls = %d, want 2 with the cache expiring at the candle close", calls.Load())
+	}
+}
+
+// TestGetHistoricalData_KlineCacheSkipsErrors tests that failed requests are not cached and a negative TTL disables the cache
+func TestGetHistoricalData_KlineCacheSkipsErrors(t *testing.T) {
+	var calls atomic.Int64
+	mockClient := &mockBinanceClient{
+		getKlinesFunc: func(symbol string, interval string, limit int) ([]*api.Kline, error) {
+			calls.Add(1)
+			return nil, fmt.Errorf("API error")
+		},
+	}
+	service := NewMarketDataService(mockClient, time.Second)
+	service.GetHistoricalData("BTCUSDT", "1h", 10)
+	service.GetHistoricalData("BTCUSDT", "1h", 10)
+	if calls.Load() != 2 {
+		t.Errorf("API calls after errors = %d, want 2", calls.Load())
+	}
+
+	calls.Store(0)
+	service = NewMarketDataServiceWithConfig(countingKlineClient(&calls), &MarketDataServiceConfig{KlineCacheTTL: -1})
+	service.GetHistoricalData("BTCUSDT", "1h", 10)
+	service.GetHistoricalData("BTCUSDT", "1h", 10)
+	if calls.Load() != 2 {
+		t.Errorf("API calls with the cache disabled = %d, want 2", calls.Load())
+	}
+}
+
+// TestGetHistoricalData_KlineCacheConcurrent tests that concurrent evaluations of the same
+// klines make at least 10 times fewer API calls than requests
+func TestGetHistoricalData_KlineCacheConcurrent(t *testing.T) {
+	var calls atomic.Int64
+	service := NewMarketDataService(countingKlineClient(&calls), time.Second)
+
+	const workers, requests = 20, 50
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < requests; i++ {
+				if _, err := service.GetHistoricalData("BTCUSDT", "1h", 42); err != nil {
+					t.Errorf("GetHistoricalData() error = %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := calls.Load(); got*10 > workers*requests {
+		t.Errorf("API calls for %d requests = %d, want at most %d", workers*requests, got, workers*requests/10)
+	}
+}
+
+// BenchmarkGetHistoricalData_Concurrent compares the API calls of concurrent kline requests with
+// and without the cache; the api-calls/op metric is the share of requests reaching the exchange
+func BenchmarkGetHistoricalData_Concurrent(b *testing.B) {
+	for _, bench := range []struct {
+		name string
+		ttl  time.Duration
+	}{{"uncached", -1}, {"cached", DefaultKlineCacheTTL}} {
+		b.Run(bench.name, func(b *testing.B) {
+			var calls atomic.Int64
+			service := NewMarketDataServiceWithConfig(countingKlineClient(&calls), &MarketDataServiceConfig{KlineCacheTTL: bench.ttl})
+			symbols := []string{"BTCUSDT", "ETHUSDT", "SOLUSDT"}
+
+			var next atomic.Int64
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					service.GetHistoricalData(symbols[next.Add(1)%int64(len(symbols))], "1h", 42)
+				}
+			})
+			b.ReportMetric(float64(calls.Load())/float64(b.N), "api-calls/op")
+		})
+	}
+}
+
 // TestNewMarketDataService_DefaultCacheTTL tests default cache TTL
 func TestNewMarketDataService_DefaultCacheTTL(t *testing.T) {
 	mockClient := &mockBinanceClient{}
@@ -558,3 +729,244 @@ func TestGetVolume_DifferentTimeWindows(t *testing.T) {
 		t.Errorf("expected API to be called twice for different time windows, got %d calls", callCount)
 	}
 }
+
+// stubPriceStream records stream handlers; tests push frames and toggle the connection
+type stubPriceStream struct {
+	mu        sync.Mutex
+	handlers  map[string]func(*api.TickerEvent)
+	books     map[string]func(*api.BookTicker)
+	connected bool
+	err       error
+}
+
+func (s *stubPriceStream) SubscribeTicker(symbol string, handler func(*api.TickerEvent)) (func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.handlers == nil {
+		s.handlers = make(map[string]func(*api.TickerEvent))
+	}
+	s.handlers[symbol] = handler
+	return func() {}, nil
+}
+
+func (s *stubPriceStream) SubscribeBookTicker(symbol string, handler func(*api.BookTicker)) (func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.books == nil {
+		s.books = make(map[string]func(*api.BookTicker))
+	}
+	s.books[symbol] = handler
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.books, symbol)
+	}, nil
+}
+
+func (s *stubPriceStream) Connected() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connected
+}
+
+func (s *stubPriceStream) ConnectedStreams() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.connected {
+		return nil
+	}
+	var streams []string
+	for symbol := range s.books {
+		streams = append(streams, strings.ToLower(symbol)+"@bookTicker")
+	}
+	sort.Strings(streams)
+	return streams
+}
+
+func (s *stubPriceStream) setConnected(connected bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.connected = connected
+}
+
+func (s *stubPriceStream) push(symbol string, price float64) {
+	s.mu.Lock()
+	handler := s.handlers[symbol]
+	s.mu.Unlock()
+	handler(&api.TickerEvent{Symbol: symbol, LastPrice: price})
+}
+
+// pushBook delivers a book ticker frame; it reports whether the symbol is subscribed
+func (s *stubPriceStream) pushBook(symbol string, bid, ask float64) bool {
+	s.mu.Lock()
+	handler, ok := s.books[symbol]
+	s.mu.Unlock()
+	if ok {
+		handler(&api.BookTicker{Symbol: symbol, BidPrice: bid, AskPrice: ask})
+	}
+	return ok
+}
+
+// TestSubscribeToPrice tests streamed prices with REST polling whenever the stream is down
+func TestSubscribeToPrice(t *testing.T) {
+	var restCalls atomic.Int32
+	mockClient := &mockBinanceClient{
+		getPriceFunc: func(symbol string) (*api.Price, error) {
+			restCalls.Add(1)
+			return &api.Price{Symbol: symbol, Price: 49000}, nil
+		},
+	}
+	service := NewMarketDataService(mockClient, 20*time.Millisecond)
+	stream := &stubPriceStream{connected: true}
+	service.SetPriceStream(stream)
+
+	prices := make(chan float64, 64)
+	if err := service.SubscribeToPrice("BTCUSDT", func(price float64) { prices <- price }); err != nil {
+		t.Fatalf("SubscribeToPrice() error = %v", err)
+	}
+	expectPrice := func(want float64) {
+		t.Helper()
+		for {
+			select {
+			case got := <-prices:
+				if got == want {
+					return
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("no price %v within 2s", want)
+			}
+		}
+	}
+
+	// While connected every tick is delivered and REST is left alone
+	stream.push("BTCUSDT", 50000)
+	expectPrice(50000)
+	if price, err := service.GetCurrentPrice("BTCUSDT"); err != nil || price != 50000 {
+		t.Errorf("GetCurrentPrice() = %v, %v, want the streamed price", price, err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if calls := restCalls.Load(); calls != 0 {
+		t.Errorf("REST polled %d times while the stream was connected", calls)
+	}
+
+	// While disconnected the price is polled over REST
+	stream.setConnected(false)
+	expectPrice(49000)
+
+	// Back on the stream
+	stream.setConnected(true)
+	stream.push("BTCUSDT", 50100)
+	expectPrice(50100)
+}
+
+// TestSubscribeToPrice_Errors tests subscription argument and stream errors
+func TestSubscribeToPrice_Errors(t *testing.T) {
+	service := NewMarketDataService(&mockBinanceClient{}, time.Second)
+	if err := service.SubscribeToPrice("", func(float64) {}); err == nil {
+		t.Error("expected error for empty symbol, got nil")
+	}
+	if err := service.SubscribeToPrice("BTCUSDT", nil); err == nil {
+		t.Error("expected error for nil callback, got nil")
+	}
+
+	service.SetPriceStream(&stubPriceStream{err: fmt.Errorf("market stream is closed")})
+	if err := service.SubscribeToPrice("BTCUSDT", func(float64) {}); err == nil {
+		t.Error("expected error when the stream refuses the subscription, got nil")
+	}
+}
+
+// TestSubscribePriceFeed tests the book ticker price feed
+func TestSubscribePriceFeed(t *testing.T) {
+	tests := []struct {
+		name    string
+		stream  *stubPriceStream
+		symbol  string
+		frames  [][2]float64 // bid, ask
+		want    float64
+		wantErr bool
+	}{
+		{name: "mid of the best bid and ask", stream: &stubPriceStream{connected: true}, symbol: "BTCUSDT", frames: [][2]float64{{49999, 50001}}, want: 50000},
+		{name: "only the latest price is kept", stream: &stubPriceStream{connected: true}, symbol: "BTCUSDT", frames: [][2]float64{{1, 3}, {3, 5}, {5, 7}}, want: 6},
+		{name: "frames without a quote are skipped", stream: &stubPriceStream{connected: true}, symbol: "BTCUSDT", frames: [][2]float64{{49999, 50001}, {0, 50001}}, want: 50000},
+		{name: "no price stream", symbol: "BTCUSDT", wantErr: true},
+		{name: "stream refuses the subscription", stream: &stubPriceStream{err: fmt.Errorf("market stream is closed")}, symbol: "BTCUSDT", wantErr: true},
+		{name: "empty symbol", stream: &stubPriceStream{connected: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewMarketDataService(&mockBinanceClient{}, time.Second)
+			if tt.stream != nil {
+				service.SetPriceStream(tt.stream)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			prices, err := service.SubscribePriceFeed(ctx, tt.symbol)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("SubscribePriceFeed() expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SubscribePriceFeed() error = %v", err)
+			}
+
+			for _, frame := range tt.frames {
+				tt.stream.pushBook(tt.symbol, frame[0], frame[1])
+			}
+			if price := <-prices; price != tt.want {
+				t.Errorf("feed delivered %v, want %v", price, tt.want)
+			}
+			select {
+			case price := <-prices:
+				t.Errorf("feed delivered a stale price %v", price)
+			default:
+			}
+		})
+	}
+}
+
+// TestSubscribePriceFeed_Cancel tests that a feed ends with its context
+func TestSubscribePriceFeed_Cancel(t *testing.T) {
+	stream := &stubPriceStream{connected: true}
+	service := NewDataSourceManager(NewMarketDataService(&mockBinanceClient{}, time.Second), nil, &mockLogger{})
+	service.SetPriceStream(stream)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	prices, err := service.SubscribePriceFeed(ctx, "ETHUSDT")
+	if err != nil {
+		t.Fatalf("SubscribePriceFeed() error = %v", err)
+	}
+	if streams := service.ConnectedStreams(); strings.Join(streams, ",") != "ethusdt@bookTicker" {
+		t.Errorf("ConnectedStreams() = %v, want [ethusdt@bookTicker]", streams)
+	}
+
+	cancel()
+	select {
+	case _, open := <-prices:
+		if open {
+			t.Error("feed delivered a price after cancel")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("feed not closed within 2s of cancel")
+	}
+	if stream.pushBook("ETHUSDT", 3000, 3001) {
+		t.Error("stream still subscribed after cancel")
+	}
+	if streams := service.ConnectedStreams(); len(streams) != 0 {
+		t.Errorf("ConnectedStreams() after cancel = %v", streams)
+	}
+
+	stream.setConnected(false)
+	if streams := service.ConnectedStreams(); len(streams) != 0 {
+		t.Errorf("ConnectedStreams() while disconnected = %v", streams)
+	}
+}
@@ -2,9 +2,13 @@ package service
 
 import (
 	"binance-trader/internal/api"
+	"binance-trader/pkg/metrics"
 	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
 	"binance-trader/pkg/logger"
+	"binance-trader/pkg/timeutil"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 )
@@ -18,18 +22,37 @@ type MonitoringEngine struct {
 	marketDataService MarketDataService
 	stopLossService   StopLossService
 	logger            logger.Logger
+	maintenance       MaintenanceMonitor
+	priceChecker      PriceSanityChecker
+	gapCallbacks      []func(report *MonitoringGapReport)
+	metrics           *metrics.Sink
+	now               func() time.Time
 	
 	// Monitoring state
 	mu              sync.RWMutex
 	activeOrders    map[string]*repository.ConditionalOrder
 	marketDataCache map[string]*MarketData
+	klineCache      map[string]*rsiKlines     // Closed klines of RSI conditions by symbol and interval
+	imbalanceCache  map[string]*bookImbalance // Ask/bid imbalance of the latest tick by symbol
+	maCrossSides    map[string]int            // Side of the slow MA the fast MA was on at the previous tick by order
+	feeds           map[string]*symbolFeed
+	lastCycle       time.Time
+	
+	// Fair scheduling state
+	rotationStart string
+	inFlight      map[string]bool
+	symbolStats   map[string]*SymbolEvaluationStats
 	
 	// Configuration
 	updateInterval time.Duration
+	gapThreshold   time.Duration
+	cycleBudget    time.Duration
+	symbolTimeout  time.Duration
 	
 	// Control channels
-	stopChan chan struct{}
-	doneChan chan struct{}
+	stopChan   chan struct{}
+	doneChan   chan struct{}
+	resumeChan chan struct{}
 	
 	// Status
 	isRunning bool
@@ -38,6 +61,15 @@ type MonitoringEngine struct {
 // MonitoringEngineConfig holds configuration for the monitoring engine
 type MonitoringEngineConfig struct {
 	UpdateInterval time.Duration
+	// GapThreshold is how late a cycle may start after the previous one before it is
+	// treated as a monitoring gap; defaults to the larger of ten intervals and one minute
+	GapThreshold time.Duration
+	// CycleBudget bounds the time spent evaluating symbols in one cycle; symbols left over
+	// are evaluated first in the next cycle. Defaults to the update interval.
+	CycleBudget time.Duration
+	// SymbolTimeout is how long a cycle waits for one symbol's market data before skipping
+	// its orders until the next cycle; defaults to half the update interval
+	SymbolTimeout time.Duration
 }
 
 // NewMonitoringEngine creates a new monitoring engine instance
@@ -61,6 +93,21 @@ func NewMonitoringEngine(
 		config.UpdateInterval = 1 * time.Second
 	}
 	
+	gapThreshold := config.GapThreshold
+	if gapThreshold <= 0 {
+		gapThreshold = defaultGapThreshold(config.UpdateInterval)
+	}
+	
+	cycleBudget := config.CycleBudget
+	if cycleBudget <= 0 {
+		cycleBudget = config.UpdateInterval
+	}
+	
+	symbolTimeout := config.SymbolTimeout
+	if symbolTimeout <= 0 {
+		symbolTimeout = config.UpdateInterval / 2
+	}
+	
 	return &MonitoringEngine{
 		repo:              repo,
 		stopOrderRepo:     stopOrderRepo,
@@ -71,9 +118,21 @@ func NewMonitoringEngine(
 		logger:            logger,
 		activeOrders:      make(map[string]*repository.ConditionalOrder),
 		marketDataCache:   make(map[string]*MarketData),
+		klineCache:        make(map[string]*rsiKlines),
+		imbalanceCache:    make(map[string]*bookImbalance),
+		maCrossSides:      make(map[string]int),
+		feeds:             make(map[string]*symbolFeed),
+		inFlight:          make(map[string]bool),
+		symbolStats:       make(map[string]*SymbolEvaluationStats),
 		updateInterval:    config.UpdateInterval,
+		gapThreshold:      gapThreshold,
+		cycleBudget:       cycleBudget,
+		symbolTimeout:     symbolTimeout,
+		metrics:           metrics.Noop(),
+		now:               time.Now,
 		stopChan:          make(chan struct{}),
 		doneChan:          make(chan struct{}),
+		resumeChan:        make(chan struct{}, 1),
 		isRunning:         false,
 	}
 }
@@ -93,6 +152,7 @@ func (me *MonitoringEngine) Start() error {
 	}
 	
 	me.isRunning = true
+	me.lastCycle = time.Time{}
 	me.stopChan = make(chan struct{})
 	me.doneChan = make(chan struct{})
 	
@@ -124,12 +184,65 @@ func (me *MonitoringEngine) Stop() error {
 	
 	// Wait for monitoring loop to finish
 	<-me.doneChan
+	me.stopPriceFeeds()
 	
 	me.logger.Info("Monitoring engine stopped", nil)
 	
 	return nil
 }
 
+// SetMaintenanceMonitor attaches a maintenance monitor that pauses trigger executions
+// during exchange maintenance and re-evaluates all conditions once it ends
+func (me *MonitoringEngine) SetMaintenanceMonitor(monitor MaintenanceMonitor) {
+	me.mu.Lock()
+	me.maintenance = monitor
+	me.mu.Unlock()
+	
+	if monitor != nil {
+		monitor.OnResume(func() {
+			// Wake the monitoring loop so all conditions are re-evaluated immediately
+			select {
+			case me.resumeChan <- struct{}{}:
+			default:
+			}
+		})
+	}
+}
+
+// SetPriceSanityChecker attaches a checker that holds back protective triggers on
+// prices that jump beyond the configured deviation until a second reading confirms them
+func (me *MonitoringEngine) SetPriceSanityChecker(checker PriceSanityChecker) {
+	me.mu.Lock()
+	me.priceChecker = checker
+	me.mu.Unlock()
+}
+
+// SetMetrics sets the sink cycle, trigger and active order metrics are recorded in; call it
+// before monitoring starts
+func (me *MonitoringEngine) SetMetrics(sink *metrics.Sink) {
+	me.metrics = sink
+}
+
+// isPausedForMaintenance returns whether the exchange is currently in maintenance
+func (me *MonitoringEngine) isPausedForMaintenance() bool {
+	me.mu.RLock()
+	monitor := me.maintenance
+	me.mu.RUnlock()
+	
+	return monitor != nil && monitor.IsInMaintenance()
+}
+
+// recordAPIResult reports an API call result to the maintenance monitor
+func (me *MonitoringEngine) recordAPIResult(err error) {
+	me.mu.RLock()
+	monitor := me.maintenance
+	me.mu.RUnlock()
+	
+	if monitor != nil {
+		monitor.RecordResult(err)
+	}
+}
+
 // IsRunning returns whether the monitoring engine is currently running
 func (me *MonitoringEngine) IsRunning() bool {
 	me.mu.RLock()
@@ -149,6 +262,13 @@ func (me *MonitoringEngine) loadActiveOrders() error {
 		me.activeOrders[order.OrderID] = order
 	}
 	
+	// Crossover state of orders that are no longer active is not needed again
+	for orderID := range me.maCrossSides {
+		if _, active := me.activeOrders[orderID]; !active {
+			delete(me.maCrossSides, orderID)
+		}
+	}
+	
 	return nil
 }
 
@@ -167,12 +287,31 @@ func (me *MonitoringEngine) monitoringLoop() {
 			
 		case <-ticker.C:
 			me.checkAndTriggerOrders()
+			
+		case <-me.resumeChan:
+			me.logger.Info("Re-evaluating all conditions after maintenance", nil)
+			me.checkAndTriggerOrders()
 		}
 	}
 }
 
 // checkAndTriggerOrders checks all active orders and triggers them if conditions are met
 func (me *MonitoringEngine) checkAndTriggerOrders() {
+	cycleStart := time.Now()
+	defer func() {
+		me.metrics.MonitoringCycleDuration.Observe(time.Since(cycleStart).Seconds())
+	}()
+
+	// Catch up on a suspend or clock jump before evaluating anything
+	if start, end, gap := me.recordCycle(); gap {
+		me.handleMonitoringGap(start, end)
+	}
+	
+	// Orders stay pending while the exchange is in maintenance
+	if me.isPausedForMaintenance() {
+		return
+	}
+	
 	// Reload active orders to catch any new orders
 	me.mu.Lock()
 	if err := me.loadActiveOrders(); err != nil {
@@ -183,6 +322,8 @@ func (me *MonitoringEngine) checkAndTriggerOrders() {
 		return
 	}
 	
+	me.metrics.ActiveConditionalOrders.Set(float64(len(me.activeOrders)))
+
 	// Create a copy of active orders to avoid holding lock during processing
 	ordersCopy := make([]*repository.ConditionalOrder, 0, len(me.activeOrders))
 	for _, order := range me.activeOrders {
@@ -190,13 +331,17 @@ func (me *MonitoringEngine) checkAndTriggerOrders() {
 	}
 	me.mu.Unlock()
 	
-	// Process each conditional order
-	for _, order := range ordersCopy {
-		me.processOrder(order)
-	}
+	// Keep a pushed price feed for every symbol being watched
+	me.syncPriceFeeds(ordersCopy)
+	
+	// Evaluate conditional orders symbol by symbol within the cycle budget
+	me.evaluateSymbols(ordersCopy)
 	
 	// Process trailing stop orders
 	me.processTrailingStopOrders()
+	
+	// Process stop loss and take profit orders (including ladder levels)
+	me.processStopOrders()
 }
 
 // extractValueFromMarketData extracts the appropriate value from market data based on trigger type
@@ -218,16 +363,8 @@ func (me *MonitoringEngine) extractValueFromMarketData(marketData *MarketData, c
 
 // processOrder processes a single conditional order
 func (me *MonitoringEngine) processOrder(order *repository.ConditionalOrder) {
-	// Check time window if specified
-	if order.TimeWindow != nil {
-		currentTime := time.Now().Unix()
-		tw := &TimeWindow{
-			StartTime: order.TimeWindow.StartTime,
-			EndTime:   order.TimeWindow.EndTime,
-		}
-		if !IsWithinTimeWindow(currentTime, tw) {
-			return
-		}
+	if !me.inTimeWindow(order) {
+		return
 	}
 	
 	// Get market data
@@ -241,10 +378,112 @@ func (me *MonitoringEngine) processOrder(order *repository.ConditionalOrder) {
 		return
 	}
 	
+	me.evaluateOrder(order, marketData)
+}
+
+// inTimeWindow returns whether an order may trigger now, expiring orders whose window has ended
+func (me *MonitoringEngine) inTimeWindow(order *repository.ConditionalOrder) bool {
+	if order.TimeWindow == nil {
+		return true
+	}
+	
+	currentTime := me.now().Unix()
+	tw := &TimeWindow{
+		StartTime: order.TimeWindow.StartTime,
+		EndTime:   order.TimeWindow.EndTime,
+	}
+	if IsWithinTimeWindow(currentTime, tw) {
+		return true
+	}
+	
+	// Orders whose window has ended can never trigger
+	if !tw.EndTime.IsZero() && time.Unix(currentTime, 0).After(tw.EndTime) {
+		me.cancelOrder(order, repository.CancelReasonExpired)
+	}
+	return false
+}
+
+// evaluateOrder evaluates an order's trigger condition against market data and executes it when met
+func (me *MonitoringEngine) evaluateOrder(order *repository.ConditionalOrder, marketData *MarketData) {
 	// Evaluate trigger condition
 	triggerCond := me.convertToServiceTriggerCondition(order.TriggerCondition)
 	currentValue := me.extractValueFromMarketData(marketData, order.TriggerCondition)
-	triggered, err := me.triggerEngine.EvaluateCondition(triggerCond, currentValue)
+	if order.TriggerCondition.Type == repository.TriggerTypeRSI && len(order.TriggerCondition.SubConditions) == 0 {
+		rsi, err := me.rsiValue(order.Symbol, order.TriggerCondition, marketData.Price)
+		if err != nil {
+			me.logger.Warn("Failed to compute RSI", map[string]interface{}{
+				"order_id": order.OrderID,
+				"symbol":   order.Symbol,
+				"error":    err.Error(),
+			})
+			return
+		}
+		currentValue = rsi
+	}
+	if order.TriggerCondition.Type == repository.TriggerTypeVolume && len(order.TriggerCondition.SubConditions) == 0 {
+		volume, err := me.volumeValue(order.Symbol, order.TriggerCondition)
+		if err != nil {
+			me.logger.Warn("Failed to get traded volume", map[string]interface{}{
+				"order_id": order.OrderID,
+				"symbol":   order.Symbol,
+				"error":    err.Error(),
+			})
+			return
+		}
+		currentValue = volume
+	}
+	if order.TriggerCondition.Type == repository.TriggerTypeVolumeSurge && len(order.TriggerCondition.SubConditions) == 0 {
+		multiplier, err := me.volumeSurgeValue(order.Symbol, order.TriggerCondition)
+		if err != nil {
+			me.logger.Warn("Failed to compute volume surge", map[string]interface{}{
+				"order_id": order.OrderID,
+				"symbol":   order.Symbol,
+				"error":    err.Error(),
+			})
+			return
+		}
+		currentValue = multiplier
+	}
+	if order.TriggerCondition.Type == repository.TriggerTypeAskBidImbalance && len(order.TriggerCondition.SubConditions) == 0 {
+		imbalance, err := me.imbalanceValue(order.Symbol)
+		if err != nil {
+			me.logger.Warn("Failed to compute ask/bid imbalance", map[string]interface{}{
+				"order_id": order.OrderID,
+				"symbol":   order.Symbol,
+				"error":    err.Error(),
+			})
+			return
+		}
+		currentValue = imbalance
+	}
+	if order.TriggerCondition.Type == repository.TriggerTypeMACrossover && len(order.TriggerCondition.SubConditions) == 0 {
+		spread, err := me.maSpread(order.Symbol, order.TriggerCondition, marketData.Price)
+		if err != nil {
+			me.logger.Warn("Failed to compute moving averages", map[string]interface{}{
+				"order_id": order.OrderID,
+				"symbol":   order.Symbol,
+				"error":    err.Error(),
+			})
+			return
+		}
+		// Only a change of sides is a crossover; the operator then picks its direction
+		if !me.maCrossed(order.OrderID, spread) {
+			return
+		}
+		currentValue = spread
+	}
+	if order.TriggerCondition.Type == repository.TriggerTypeTime && len(order.TriggerCondition.SubConditions) == 0 {
+		currentValue = float64(timeutil.Millis(me.now()))
+	}
+	
+	var triggered bool
+	var err error
+	if len(order.TriggerCondition.SubConditions) > 0 {
+		// Each sub-condition compares its own value rather than one shared one
+		triggered, err = me.evaluateComposite(order.TriggerCondition, marketData)
+	} else {
+		triggered, err = me.triggerEngine.EvaluateCondition(triggerCond, currentValue)
+	}
 	if err != nil {
 		me.logger.Warn("Failed to evaluate trigger condition", map[string]interface{}{
 			"order_id": order.OrderID,
@@ -261,24 +500,33 @@ func (me *MonitoringEngine) processOrder(order *repository.ConditionalOrder) {
 // getMarketData retrieves market data for a symbol with caching
 func (me *MonitoringEngine) getMarketData(symbol string) (*MarketData, error) {
 	// Check cache first
-	me.mu.RLock()
-	cached, exists := me.marketDataCache[symbol]
-	me.mu.RUnlock()
-	
-	if exists && time.Since(time.Unix(cached.Timestamp, 0)) < 1*time.Second {
+	if cached := me.cachedMarketData(symbol); cached != nil {
 		return cached, nil
 	}
 	
-	// Fetch from market data service
-	price, err := me.marketDataService.GetCurrentPrice(symbol)
-	if err != nil {
-		return nil, err
+	// Prefer the pushed price and poll the market data service only without a fresh one
+	price, fromFeed := me.feedPrice(symbol)
+	if !fromFeed {
+		var err error
+		price, err = me.marketDataService.GetCurrentPrice(symbol)
+		me.recordAPIResult(err)
+		if err != nil {
+			return nil, err
+		}
 	}
 	
 	marketData := &MarketData{
 		Symbol:    symbol,
 		Price:     price,
-		Timestamp: time.Now().Unix(),
+		Timestamp: me.now().Unix(),
+	}
+	
+	// Flag bad ticks so protective triggers wait for confirmation
+	me.mu.RLock()
+	checker := me.priceChecker
+	me.mu.RUnlock()
+	if checker != nil {
+		marketData.Unconfirmed = !checker.Observe(symbol, price)
 	}
 	
 	// Update cache
@@ -289,6 +537,18 @@ func (me *MonitoringEngine) getMarketData(symbol string) (*MarketData, error) {
 	return marketData, nil
 }
 
+// cachedMarketData returns the cached market data of a symbol while it is fresh
+func (me *MonitoringEngine) cachedMarketData(symbol string) *MarketData {
+	me.mu.RLock()
+	cached, exists := me.marketDataCache[symbol]
+	me.mu.RUnlock()
+	
+	if exists && me.now().Sub(time.Unix(cached.Timestamp, 0)) < 1*time.Second {
+		return cached
+	}
+	return nil
+}
+
 // executeTrigger executes a triggered conditional order
 func (me *MonitoringEngine) executeTrigger(order *repository.ConditionalOrder, marketData *MarketData) {
 	// Log trigger event with complete information
@@ -296,7 +556,7 @@ func (me *MonitoringEngine) executeTrigger(order *repository.ConditionalOrder, m
 	me.logger.Info("Trigger condition met, executing order", triggerInfo)
 	
 	// Update status to triggered
-	triggeredAt := time.Now().Unix()
+	triggeredAt := timeutil.NowMillis()
 	if err := me.repo.UpdateStatus(order.OrderID, repository.ConditionalOrderStatusTriggered, triggeredAt, 0); err != nil {
 		me.logger.Error("Failed to update order status to triggered", map[string]interface{}{
 			"order_id": order.OrderID,
@@ -304,15 +564,36 @@ func (me *MonitoringEngine) executeTrigger(order *repository.ConditionalOrder, m
 		})
 		return
 	}
+	me.metrics.ConditionalOrdersTriggered.Inc(order.Symbol)
 	
 	// Execute order via trading service
-	executedOrder, err := me.executeOrder(order)
+	executedOrder, err := me.executeOrder(order, marketData.Price)
+	me.recordAPIResult(err)
+	if err != nil && (me.isPausedForMaintenance() || errors.IsTemporary(err)) {
+		// Keep the order pending so it is re-evaluated once maintenance ends or the guard that
+		// held it back (symbol pause, notional cap, daily order limit) clears
+		me.logger.Warn("Conditional order held back, keeping it pending", map[string]interface{}{
+			"order_id": order.OrderID,
+			"symbol":   order.Symbol,
+			"error":    err.Error(),
+		})
+		if revertErr := me.repo.UpdateStatus(order.OrderID, repository.ConditionalOrderStatusPending, 0, 0); revertErr != nil {
+			me.logger.Error("Failed to revert order status to pending", map[string]interface{}{
+				"order_id": order.OrderID,
+				"error":    revertErr.Error(),
+			})
+		}
+		return
+	}
 	if err != nil {
 		me.logger.Error("Failed to execute conditional order", map[string]interface{}{
 			"order_id": order.OrderID,
 			"symbol":   order.Symbol,
 			"error":    err.Error(),
 		})
+		if isRiskBlock(err) {
+			me.cancelOrder(order, repository.CancelReasonRiskBlock)
+		}
 		return
 	}
 	
@@ -345,8 +626,54 @@ func (me *MonitoringEngine) executeTrigger(order *repository.ConditionalOrder, m
 	})
 }
 
+// cancelOrder cancels a conditional order the engine can no longer execute
+func (me *MonitoringEngine) cancelOrder(order *repository.ConditionalOrder, reason repository.CancelReason) {
+	if err := me.repo.UpdateStatusWithReason(order.OrderID, repository.ConditionalOrderStatusCancelled, reason, "monitor", timeutil.Millis(me.now())); err != nil {
+		me.logger.Error("Failed to cancel conditional order", map[string]interface{}{
+			"order_id": order.OrderID,
+			"reason":   string(reason),
+			"error":    err.Error(),
+		})
+		return
+	}
+	
+	me.mu.Lock()
+	delete(me.activeOrders, order.OrderID)
+	me.mu.Unlock()
+	
+	if err := me.triggerEngine.UnregisterCondition(order.OrderID); err != nil {
+		me.logger.Warn("Failed to unregister condition from trigger engine", map[string]interface{}{
+			"order_id": order.OrderID,
+			"error":    err.Error(),
+		})
+	}
+	
+	me.logger.Info("Conditional order cancelled", map[string]interface{}{
+		"order_id":     order.OrderID,
+		"symbol":       order.Symbol,
+		"reason":       string(reason),
+		"cancelled_by": "monitor",
+	})
+}
+
+// isRiskBlock checks if an order was rejected by risk limits it cannot pass later; temporary
+// guards are not risk blocks
+func isRiskBlock(err error) bool {
+	tradingErr, ok := err.(*errors.TradingError)
+	if !ok {
+		return false
+	}
+	
+	switch tradingErr.Type {
+	case errors.ErrRiskLimitExceeded, errors.ErrMaxPositionExceeded, errors.ErrLiquidationRisk:
+		return true
+	default:
+		return false
+	}
+}
+
 // executeOrder executes the actual order through the trading service
-func (me *MonitoringEngine) executeOrder(order *repository.ConditionalOrder) (*api.Order, error) {
+func (me *MonitoringEngine) executeOrder(order *repository.ConditionalOrder, triggerPrice float64) (*api.Order, error) {
 	// Execute based on order type and side
 	var executedOrder *api.Order
 	var err error
@@ -358,8 +685,28 @@ func (me *MonitoringEngine) executeOrder(order *repository.ConditionalOrder) (*a
 			return nil, err
 		}
 		
+	case order.Type == api.OrderTypeMarket && order.Side == api.OrderSideSell:
+		executedOrder, err = me.tradingService.PlaceMarketSellOrder(order.Symbol, order.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		
 	case order.Type == api.OrderTypeLimit && order.Side == api.OrderSideSell:
-		executedOrder, err = me.tradingService.PlaceLimitSellOrder(order.Symbol, order.Price, order.Quantity)
+		price, err := conditionalLimitPrice(order, triggerPrice)
+		if err != nil {
+			return nil, err
+		}
+		executedOrder, err = me.tradingService.PlaceLimitSellOrder(order.Symbol, price, order.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		
+	case order.Type == api.OrderTypeLimit && order.Side == api.OrderSideBuy:
+		price, err := conditionalLimitPrice(order, triggerPrice)
+		if err != nil {
+			return nil, err
+		}
+		executedOrder, err = me.tradingService.PlaceLimitBuyOrder(order.Symbol, price, order.Quantity)
 		if err != nil {
 			return nil, err
 		}
@@ -403,9 +750,7 @@ func (me *MonitoringEngine) addTriggerConditionToLog(logInfo map[string]interfac
 			me.addSimpleConditionToLog(subCondInfo, subCond, marketData)
 			
 			// Check if this sub-condition is satisfied
-			serviceCond := me.convertToServiceTriggerCondition(subCond)
-			currentValue := me.extractValueFromMarketData(marketData, subCond)
-			satisfied, _ := me.triggerEngine.EvaluateCondition(serviceCond, currentValue)
+			satisfied, _ := me.evaluateComposite(subCond, marketData)
 			subCondInfo["satisfied"] = satisfied
 			
 			if satisfied {
@@ -438,9 +783,43 @@ func (me *MonitoringEngine) addSimpleConditionToLog(logInfo map[string]interface
 		}
 		
 	case repository.TriggerTypeVolume:
-		logInfo["current_volume"] = marketData.Volume24h
-		if condition.TimeWindow > 0 {
-			logInfo["time_window"] = condition.TimeWindow.String()
+		logInfo["time_window"] = condition.TimeWindow.String()
+		if volume, err := me.volumeValue(marketData.Symbol, condition); err == nil {
+			logInfo["current_volume"] = volume
+		}
+		
+	case repository.TriggerTypeVolumeSurge:
+		logInfo["baseline_window"] = condition.TimeWindow.String()
+		if multiplier, err := me.volumeSurgeValue(marketData.Symbol, condition); err == nil {
+			logInfo["volume_multiplier"] = multiplier
+		}
+		
+	case repository.TriggerTypeTime:
+		logInfo["trigger_time"] = timeutil.FromMillis(int64(condition.Value)).Format(time.RFC3339)
+		logInfo["current_price"] = marketData.Price
+		
+	case repository.TriggerTypeRSI:
+		logInfo["rsi_period"] = condition.Period
+		logInfo["rsi_interval"] = rsiInterval(condition)
+		logInfo["current_price"] = marketData.Price
+		if rsi, err := me.rsiValue(marketData.Symbol, condition, marketData.Price); err == nil {
+			logInfo["current_rsi"] = rsi
+		}
+
+	case repository.TriggerTypeAskBidImbalance:
+		logInfo["imbalance_depth"] = ImbalanceDepth
+		logInfo["current_price"] = marketData.Price
+		if imbalance, err := me.imbalanceValue(marketData.Symbol); err == nil {
+			logInfo["current_imbalance"] = imbalance
+		}
+
+	case repository.TriggerTypeMACrossover:
+		logInfo["fast_period"] = condition.FastPeriod
+		logInfo["slow_period"] = condition.SlowPeriod
+		logInfo["ma_interval"] = maCrossoverInterval(condition)
+		logInfo["current_price"] = marketData.Price
+		if spread, err := me.maSpread(marketData.Symbol, condition, marketData.Price); err == nil {
+			logInfo["ma_spread"] = spread
 		}
 	}
 }
@@ -454,6 +833,16 @@ func (me *MonitoringEngine) triggerTypeToString(t repository.TriggerType) string
 		return "price_change_percent"
 	case repository.TriggerTypeVolume:
 		return "volume"
+	case repository.TriggerTypeRSI:
+		return "rsi"
+	case repository.TriggerTypeAskBidImbalance:
+		return "ask_bid_imbalance"
+	case repository.TriggerTypeMACrossover:
+		return "ma_crossover"
+	case repository.TriggerTypeVolumeSurge:
+		return "volume_surge"
+	case repository.TriggerTypeTime:
+		return "time"
 	default:
 		return "unknown"
 	}
@@ -497,6 +886,10 @@ func (me *MonitoringEngine) convertToServiceTriggerCondition(repoCond *repositor
 		Value:         repoCond.Value,
 		BasePrice:     repoCond.BasePrice,
 		TimeWindow:    repoCond.TimeWindow,
+		Period:        repoCond.Period,
+		Interval:      repoCond.Interval,
+		FastPeriod:    repoCond.FastPeriod,
+		SlowPeriod:    repoCond.SlowPeriod,
 		CompositeType: LogicOperator(repoCond.CompositeType),
 	}
 	
@@ -549,8 +942,11 @@ func (me *MonitoringEngine) processTrailingStopsForSymbol(symbol string) {
 		return
 	}
 	
-	// Get current market price
-	marketData, err := me.getMarketData(symbol)
+	// Get current market price; a slow symbol is skipped until its fetch completes
+	marketData, skipped, err := me.fetchMarketData(symbol)
+	if skipped {
+		return
+	}
 	if err != nil {
 		me.logger.Warn("Failed to get market data for trailing stops", map[string]interface{}{
 			"symbol": symbol,
@@ -559,6 +955,11 @@ func (me *MonitoringEngine) processTrailingStopsForSymbol(symbol string) {
 		return
 	}
 	
+	// Neither move the stop nor trigger it on an unconfirmed price
+	if marketData.Unconfirmed {
+		return
+	}
+	
 	// Update each trailing stop order
 	for _, order := range trailingOrders {
 		// Use the stop loss service to update the trailing stop price
@@ -583,3 +984,68 @@ func (me *MonitoringEngine) processTrailingStopsForSymbol(symbol string) {
 		}
 	}
 }
+
+// processStopOrders executes active stop loss and take profit orders whose price has been reached.
+// Each ladder level is a separate order, so levels execute independently as price rises; the legs
+// of a stop loss / take profit pair cancel each other when one triggers.
+func (me *MonitoringEngine) processStopOrders() {
+	sls, ok := me.stopLossService.(*stopLossService)
+	if !ok {
+		return
+	}
+	
+	activeOrders, err := me.stopOrderRepo.FindStopOrdersByStatus(repository.StopOrderStatusActive)
+	if err != nil {
+		me.logger.Warn("Failed to get active stop orders", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	me.metrics.ActiveStopOrders.Set(float64(len(activeOrders)))
+	
+	// Lowest targets first, so a gap through several ladder levels executes them in order
+	sort.SliceStable(activeOrders, func(i, j int) bool {
+		return activeOrders[i].StopPrice < activeOrders[j].StopPrice
+	})
+	
+	for _, order := range activeOrders {
+		marketData, skipped, err := me.fetchMarketData(order.Symbol)
+		if skipped {
+			continue
+		}
+		if err != nil {
+			me.logger.Warn("Failed to get market data for stop order", map[string]interface{}{
+				"symbol": order.Symbol,
+				"error":  err.Error(),
+			})
+			continue
+		}
+		if marketData.Unconfirmed {
+			continue
+		}
+		
+		execute, kind := sls.ExecuteTakeProfitIfReached, "take profit"
+		if order.Type == repository.StopOrderTypeStopLoss {
+			execute, kind = sls.ExecuteStopLossIfReached, "stop loss"
+		}
+		
+		triggered, err := execute(order.OrderID, marketData.Price)
+		if err != nil {
+			me.logger.Warn("Failed to execute "+kind+" order", map[string]interface{}{
+				"order_id": order.OrderID,
+				"symbol":   order.Symbol,
+				"error":    err.Error(),
+			})
+			continue
+		}
+		
+		if triggered {
+			me.logger.Info("Stop order triggered", map[string]interface{}{
+				"type":          kind,
+				"order_id":      order.OrderID,
+				"symbol":        order.Symbol,
+				"trigger_price": marketData.Price,
+			})
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"binance-trader/internal/repository"
+	"sort"
+	"time"
+)
+
+// SymbolEvaluationStats holds the evaluation latency of one symbol's conditional orders.
+// Latency covers the market data fetch and the evaluation of every order of the symbol.
+type SymbolEvaluationStats struct {
+	Evaluations int           // Cycles in which the symbol was evaluated
+	Timeouts    int           // Cycles in which its market data did not arrive in time
+	Last        time.Duration // Latency of the most recent evaluation
+	Max         time.Duration
+	Total       time.Duration
+}
+
+// Average returns the mean evaluation latency
+func (s SymbolEvaluationStats) Average() time.Duration {
+	if s.Evaluations == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Evaluations)
+}
+
+// GetSymbolEvaluationStats returns the evaluation latency statistics per symbol
+func (me *MonitoringEngine) GetSymbolEvaluationStats() map[string]SymbolEvaluationStats {
+	me.mu.RLock()
+	defer me.mu.RUnlock()
+
+	stats := make(map[string]SymbolEvaluationStats, len(me.symbolStats))
+	for symbol, entry := range me.symbolStats {
+		stats[symbol] = *entry
+	}
+	return stats
+}
+
+// marketDataResult carries the outcome of a market data fetch
+type marketDataResult struct {
+	data *MarketData
+	err  error
+}
+
+// evaluateSymbols evaluates conditional orders grouped by symbol. Symbols are visited in a
+// rotating order so none is always last; once the cycle budget is spent, the remaining
+// symbols are deferred and evaluated first in the next cycle.
+func (me *MonitoringEngine) evaluateSymbols(orders []*repository.ConditionalOrder) {
+	bySymbol := make(map[string][]*repository.ConditionalOrder)
+	for _, order := range orders {
+		bySymbol[order.Symbol] = append(bySymbol[order.Symbol], order)
+	}
+	if len(bySymbol) == 0 {
+		return
+	}
+
+	symbols := me.rotateSymbols(bySymbol)
+	cycleStart := me.now()
+
+	for i, symbol := range symbols {
+		symbolStart := me.now()
+		me.evaluateSymbol(symbol, bySymbol[symbol])
+
+		elapsed := me.now().Sub(cycleStart)
+		if elapsed <= me.cycleBudget {
+			continue
+		}
+
+		deferred := symbols[i+1:]
+		me.logger.Warn("Monitoring cycle overran its budget", map[string]interface{}{
+			"symbol":           symbol,
+			"symbol_latency":   me.now().Sub(symbolStart).String(),
+			"cycle_elapsed":    elapsed.String(),
+			"cycle_budget":     me.cycleBudget.String(),
+			"deferred_symbols": deferred,
+		})
+		if len(deferred) > 0 {
+			me.mu.Lock()
+			me.rotationStart = deferred[0]
+			me.mu.Unlock()
+			return
+		}
+	}
+
+	// Every symbol was evaluated; the next cycle starts one symbol later
+	me.mu.Lock()
+	me.rotationStart = symbols[1%len(symbols)]
+	me.mu.Unlock()
+}
+
+// rotateSymbols returns the symbols in alphabetical order, starting with the rotation start
+// or the first symbol after it when that symbol no longer has orders
+func (me *MonitoringEngine) rotateSymbols(bySymbol map[string][]*repository.ConditionalOrder) []string {
+	symbols := make([]string, 0, len(bySymbol))
+	for symbol := range bySymbol {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	me.mu.RLock()
+	start := me.rotationStart
+	me.mu.RUnlock()
+
+	offset := sort.SearchStrings(symbols, start) % len(symbols)
+	return append(symbols[offset:], symbols[:offset]...)
+}
+
+// evaluateSymbol fetches a symbol's market data once and evaluates its orders against it.
+// When the data does not arrive within the symbol timeout, the orders are skipped this cycle.
+func (me *MonitoringEngine) evaluateSymbol(symbol string, orders []*repository.ConditionalOrder) {
+	eligible := make([]*repository.ConditionalOrder, 0, len(orders))
+	for _, order := range orders {
+		if me.inTimeWindow(order) {
+			eligible = append(eligible, order)
+		}
+	}
+	if len(eligible) == 0 {
+		return
+	}
+
+	start := me.now()
+	marketData, skipped, err := me.fetchMarketData(symbol)
+	if skipped {
+		me.recordEvaluation(symbol, 0, true)
+		return
+	}
+	if err != nil {
+		me.logger.Warn("Failed to get market data", map[string]interface{}{
+			"symbol": symbol,
+			"orders": len(eligible),
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	for _, order := range eligible {
+		me.evaluateOrder(order, marketData)
+	}
+	me.recordEvaluation(symbol, me.now().Sub(start), false)
+}
+
+// fetchMarketData returns a symbol's market data, waiting at most the symbol timeout.
+// skipped reports that the data did not arrive in time or that an earlier fetch is still
+// running; an abandoned fetch keeps running and refreshes the cache when it completes.
+func (me *MonitoringEngine) fetchMarketData(symbol string) (marketData *MarketData, skipped bool, err error) {
+	if cached := me.cachedMarketData(symbol); cached != nil {
+		return cached, false, nil
+	}
+
+	me.mu.Lock()
+	if me.inFlight[symbol] {
+		me.mu.Unlock()
+		return nil, true, nil
+	}
+	me.inFlight[symbol] = true
+	me.mu.Unlock()
+
+	result := make(chan marketDataResult, 1)
+	go func() {
+		data, err := me.getMarketData(symbol)
+		me.mu.Lock()
+		delete(me.inFlight, symbol)
+		me.mu.Unlock()
+		result <- marketDataResult{data: data, err: err}
+	}()
+
+	timer := time.NewTimer(me.symbolTimeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-result:
+		return r.data, false, r.err
+	case <-timer.C:
+		me.logger.Warn("Market data fetch timed out, skipping symbol this cycle", map[string]interface{}{
+			"symbol":  symbol,
+			"timeout": me.symbolTimeout.String(),
+		})
+		return nil, true, nil
+	}
+}
+
+// recordEvaluation updates a symbol's latency statistics
+func (me *MonitoringEngine) recordEvaluation(symbol string, latency time.Duration, timedOut bool) {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+
+	stats, exists := me.symbolStats[symbol]
+	if !exists {
+		stats = &SymbolEvaluationStats{}
+		me.symbolStats[symbol] = stats
+	}
+
+	if timedOut {
+		stats.Timeouts++
+		return
+	}
+	stats.Evaluations++
+	stats.Last = latency
+	stats.Total += latency
+	if latency > stats.Max {
+		stats.Max = latency
+	}
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// scheduledMarketDataService records the order of price fetches and delays or advances a
+// clock for selected symbols
+type scheduledMarketDataService struct {
+	mockMarketDataService
+	mu      sync.Mutex
+	delays  map[string]time.Duration
+	onFetch func(symbol string)
+	fetches []string
+}
+
+func (m *scheduledMarketDataService) GetCurrentPrice(symbol string) (float64, error) {
+	m.mu.Lock()
+	m.fetches = append(m.fetches, symbol)
+	delay := m.delays[symbol]
+	onFetch := m.onFetch
+	m.mu.Unlock()
+
+	if onFetch != nil {
+		onFetch(symbol)
+	}
+	time.Sleep(delay)
+	return 100, nil
+}
+
+func (m *scheduledMarketDataService) setDelay(symbol string, delay time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.delays[symbol] = delay
+}
+
+// takeFetches returns and clears the recorded fetches
+func (m *scheduledMarketDataService) takeFetches() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fetches := m.fetches
+	m.fetches = nil
+	return fetches
+}
+
+// saveBuyOrders saves one pending market buy per symbol with the given price condition
+func saveBuyOrders(t *testing.T, repo repository.ConditionalOrderRepository, operator repository.ComparisonOperator, value float64, symbols ...string) {
+	t.Helper()
+	for _, symbol := range symbols {
+		order := &repository.ConditionalOrder{
+			OrderID:  "order-" + symbol,
+			Symbol:   symbol,
+			Side:     api.OrderSideBuy,
+			Type:     api.OrderTypeMarket,
+			Quantity: 1,
+			TriggerCondition: &repository.TriggerCondition{
+				Type:     repository.TriggerTypePrice,
+				Operator: operator,
+				Value:    value,
+			},
+			Status:    repository.ConditionalOrderStatusPending,
+			CreatedAt: time.Now().Unix(),
+		}
+		if err := repo.Save(order); err != nil {
+			t.Fatalf("failed to save order: %v", err)
+		}
+	}
+}
+
+func TestMonitoringEngine_SlowSymbolDoesNotStarveOthers(t *testing.T) {
+	repo := repository.NewMemoryConditionalOrderRepository()
+	market := &scheduledMarketDataService{delays: map[string]time.Duration{"AAAUSDT": 500 * time.Millisecond}}
+	engine := NewMonitoringEngine(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+		&mockTradingService{}, market, &mockStopLossService{}, &mockLogger{},
+		&MonitoringEngineConfig{UpdateInterval: time.Second, SymbolTimeout: 50 * time.Millisecond})
+
+	// Every order triggers as soon as its symbol is evaluated; the slow symbol sorts first
+	saveBuyOrders(t, repo, repository.OperatorGreaterThan, 0, "AAAUSDT", "BBBUSDT", "CCCUSDT")
+
+	start := time.Now()
+	engine.checkAndTriggerOrders()
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Errorf("expected the cycle to abandon the slow fetch, took %s", elapsed)
+	}
+
+	status := func(symbol string) repository.ConditionalOrderStatus {
+		order, err := repo.FindByID("order-" + symbol)
+		if err != nil {
+			t.Fatalf("failed to find order: %v", err)
+		}
+		return order.Status
+	}
+	if status("BBBUSDT") != repository.ConditionalOrderStatusExecuted || status("CCCUSDT") != repository.ConditionalOrderStatusExecuted {
+		t.Errorf("expected the other symbols to trigger on time, got %s and %s", status("BBBUSDT"), status("CCCUSDT"))
+	}
+	if status("AAAUSDT") != repository.ConditionalOrderStatusPending {
+		t.Errorf("expected the slow symbol's order to be skipped, not failed, got %s", status("AAAUSDT"))
+	}
+
+	// While the abandoned fetch is still running, the symbol is skipped without a second fetch
+	engine.checkAndTriggerOrders()
+	fetches := 0
+	for _, symbol := range market.takeFetches() {
+		if symbol == "AAAUSDT" {
+			fetches++
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("expected a single fetch of the slow symbol, got %d", fetches)
+	}
+
+	stats := engine.GetSymbolEvaluationStats()
+	if stats["AAAUSDT"].Timeouts != 2 || stats["AAAUSDT"].Evaluations != 0 {
+		t.Errorf("expected 2 timeouts for the slow symbol, got %+v", stats["AAAUSDT"])
+	}
+	if stats["BBBUSDT"].Evaluations != 1 || stats["BBBUSDT"].Timeouts != 0 {
+		t.Errorf("expected 1 evaluation of BBBUSDT, got %+v", stats["BBBUSDT"])
+	}
+
+	// Once the symbol responds again its order triggers
+	market.setDelay("AAAUSDT", 0)
+	time.Sleep(600 * time.Millisecond)
+	engine.checkAndTriggerOrders()
+	if status("AAAUSDT") != repository.ConditionalOrderStatusExecuted {
+		t.Errorf("expected the recovered symbol's order to trigger, got %s", status("AAAUSDT"))
+	}
+	if stats := engine.GetSymbolEvaluationStats(); stats["AAAUSDT"].Evaluations != 1 {
+		t.Errorf("expected 1 evaluation after recovery, got %+v", stats["AAAUSDT"])
+	}
+}
+
+func TestMonitoringEngine_SymbolRotation(t *testing.T) {
+	repo := repository.NewMemoryConditionalOrderRepository()
+	market := &scheduledMarketDataService{delays: map[string]time.Duration{}}
+	engine := NewMonitoringEngine(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+		&mockTradingService{}, market, &mockStopLossService{}, &mockLogger{}, nil)
+
+	// Advance past the price cache between cycles
+	current := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	engine.now = func() time.Time { return current }
+
+	// The orders never trigger, so every cycle fetches every symbol
+	saveBuyOrders(t, repo, repository.OperatorLessThan, 0, "CCCUSDT", "AAAUSDT", "BBBUSDT")
+
+	expected := [][]string{
+		{"AAAUSDT", "BBBUSDT", "CCCUSDT"},
+		{"BBBUSDT", "CCCUSDT", "AAAUSDT"},
+		{"CCCUSDT", "AAAUSDT", "BBBUSDT"},
+		{"AAAUSDT", "BBBUSDT", "CCCUSDT"},
+	}
+	for cycle, want := range expected {
+		engine.checkAndTriggerOrders()
+		if got := market.takeFetches(); !reflect.DeepEqual(got, want) {
+			t.Errorf("cycle %d: expected order %v, got %v", cycle+1, want, got)
+		}
+		current = current.Add(2 * time.Second)
+	}
+}
+
+func TestMonitoringEngine_CycleBudgetDefersRemainingSymbols(t *testing.T) {
+	repo := repository.NewMemoryConditionalOrderRepository()
+	market := &scheduledMarketDataService{delays: map[string]time.Duration{}}
+	log := &mockLoggerCapture{}
+	engine := NewMonitoringEngine(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+		&mockTradingService{}, market, &mockStopLossService{}, log,
+		&MonitoringEngineConfig{UpdateInterval: time.Second, SymbolTimeout: 10 * time.Second})
+
+	// Evaluating AAAUSDT takes two seconds of the one second budget
+	var clockMu sync.Mutex
+	current := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	engine.now = func() time.Time {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		return current
+	}
+	market.onFetch = func(symbol string) {
+		if symbol == "AAAUSDT" {
+			clockMu.Lock()
+			current = current.Add(2 * time.Second)
+			clockMu.Unlock()
+		}
+	}
+
+	saveBuyOrders(t, repo, repository.OperatorLessThan, 0, "AAAUSDT", "BBBUSDT", "CCCUSDT")
+
+	engine.checkAndTriggerOrders()
+	if got := market.takeFetches(); !reflect.DeepEqual(got, []string{"AAAUSDT"}) {
+		t.Errorf("expected the budget to stop the cycle after AAAUSDT, got %v", got)
+	}
+
+	var overrun map[string]interface{}
+	for _, entry := range log.entries {
+		if entry["message"] == "Monitoring cycle overran its budget" {
+			overrun = entry
+		}
+	}
+	if overrun == nil {
+		t.Fatal("expected the overrun to be logged")
+	}
+	if overrun["symbol"] != "AAAUSDT" || !reflect.DeepEqual(overrun["deferred_symbols"], []string{"BBBUSDT", "CCCUSDT"}) {
+		t.Errorf("unexpected overrun log: %v", overrun)
+	}
+
+	// The deferred symbols go first in the next cycle, once the cached prices have expired
+	clockMu.Lock()
+	current = current.Add(2 * time.Second)
+	clockMu.Unlock()
+	engine.checkAndTriggerOrders()
+	if got := market.takeFetches(); !reflect.DeepEqual(got, []string{"BBBUSDT", "CCCUSDT", "AAAUSDT"}) {
+		t.Errorf("expected the deferred symbols first, got %v", got)
+	}
+}
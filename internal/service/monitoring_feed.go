@@ -0,0 +1,119 @@
+package service
+
+import (
+	"binance-trader/internal/repository"
+	"context"
+	"time"
+)
+
+const (
+	// feedStaleAfter is how old a pushed price may be before the engine polls instead; a
+	// quiet book pushes nothing while it is unchanged
+	feedStaleAfter = 3 * time.Second
+
+	// feedRetryInterval is how long the engine polls a symbol before asking for a feed again
+	// after a subscription failed, e.g. because no price stream is configured
+	feedRetryInterval = 30 * time.Second
+)
+
+// symbolFeed is the pushed price of a symbol with active orders
+type symbolFeed struct {
+	cancel     context.CancelFunc // nil while the subscription has failed
+	price      float64
+	receivedAt time.Time
+	retryAt    time.Time
+}
+
+// syncPriceFeeds keeps a price feed open for every symbol with active orders and closes the
+// feeds of symbols that have none left
+func (me *MonitoringEngine) syncPriceFeeds(orders []*repository.ConditionalOrder) {
+	wanted := make(map[string]bool)
+	for _, order := range orders {
+		wanted[order.Symbol] = true
+	}
+
+	now := me.now()
+	var subscribe []string
+
+	me.mu.Lock()
+	for symbol, feed := range me.feeds {
+		if !wanted[symbol] {
+			if feed.cancel != nil {
+				feed.cancel()
+			}
+			delete(me.feeds, symbol)
+		}
+	}
+	for symbol := range wanted {
+		feed, exists := me.feeds[symbol]
+		if !exists || (feed.cancel == nil && !now.Before(feed.retryAt)) {
+			subscribe = append(subscribe, symbol)
+		}
+	}
+	me.mu.Unlock()
+
+	for _, symbol := range subscribe {
+		me.subscribeFeed(symbol, now)
+	}
+}
+
+// subscribeFeed opens the price feed of a symbol; on failure the symbol is polled until the
+// retry interval has passed
+func (me *MonitoringEngine) subscribeFeed(symbol string, now time.Time) {
+	ctx, cancel := context.WithCancel(context.Background())
+	prices, err := me.marketDataService.SubscribePriceFeed(ctx, symbol)
+	if err != nil {
+		cancel()
+		me.mu.Lock()
+		me.feeds[symbol] = &symbolFeed{retryAt: now.Add(feedRetryInterval)}
+		me.mu.Unlock()
+
+		me.logger.Debug("No price feed, polling prices", map[string]interface{}{
+			"symbol": symbol,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	feed := &symbolFeed{cancel: cancel}
+	me.mu.Lock()
+	me.feeds[symbol] = feed
+	me.mu.Unlock()
+
+	go me.consumeFeed(feed, prices)
+}
+
+// consumeFeed records every pushed price until the feed is closed
+func (me *MonitoringEngine) consumeFeed(feed *symbolFeed, prices <-chan float64) {
+	for price := range prices {
+		me.mu.Lock()
+		feed.price = price
+		feed.receivedAt = me.now()
+		me.mu.Unlock()
+	}
+}
+
+// feedPrice returns the pushed price of a symbol while it is fresh
+func (me *MonitoringEngine) feedPrice(symbol string) (float64, bool) {
+	me.mu.RLock()
+	defer me.mu.RUnlock()
+
+	feed, exists := me.feeds[symbol]
+	if !exists || feed.receivedAt.IsZero() || me.now().Sub(feed.receivedAt) > feedStaleAfter {
+		return 0, false
+	}
+	return feed.price, true
+}
+
+// stopPriceFeeds closes every price feed
+func (me *MonitoringEngine) stopPriceFeeds() {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+
+	for _, feed := range me.feeds {
+		if feed.cancel != nil {
+			feed.cancel()
+		}
+	}
+	me.feeds = make(map[string]*symbolFeed)
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// feedMarketDataService hands out price feeds the test pushes into and counts REST polls
+type feedMarketDataService struct {
+	mockStopLossMarketDataService
+	polls atomic.Int32
+
+	mu    sync.Mutex
+	fail  bool
+	feeds map[string]chan float64
+	ctxs  map[string]context.Context
+}
+
+func (m *feedMarketDataService) GetCurrentPrice(symbol string) (float64, error) {
+	m.polls.Add(1)
+	return m.currentPrice, nil
+}
+
+func (m *feedMarketDataService) SubscribePriceFeed(ctx context.Context, symbol string) (<-chan float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.fail {
+		return nil, fmt.Errorf("no price stream configured")
+	}
+	prices := make(chan float64, 1)
+	m.feeds[symbol] = prices
+	m.ctxs[symbol] = ctx
+	return prices, nil
+}
+
+// feedCancelled reports whether the engine cancelled the feed of a symbol
+func (m *feedMarketDataService) feedCancelled(symbol string) bool {
+	m.mu.Lock()
+	ctx := m.ctxs[symbol]
+	m.mu.Unlock()
+	return ctx != nil && ctx.Err() != nil
+}
+
+func TestMonitoringEngine_PriceFeed(t *testing.T) {
+	var clockMu sync.Mutex
+	current := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	advance := func(d time.Duration) {
+		clockMu.Lock()
+		current = current.Add(d)
+		clockMu.Unlock()
+	}
+
+	repo := repository.NewMemoryConditionalOrderRepository()
+	stopOrderRepo := repository.NewMemoryStopOrderRepository()
+	triggerEngine := NewTriggerEngine()
+	trading := &recordingSellTradingService{}
+	market := &feedMarketDataService{
+		mockStopLossMarketDataService: mockStopLossMarketDataService{currentPrice: 50000},
+		feeds:                         make(map[string]chan float64),
+		ctxs:                          make(map[string]context.Context),
+	}
+	stopLoss := NewStopLossService(stopOrderRepo, triggerEngine, trading, market, &mockLogger{})
+
+	engine := NewMonitoringEngine(repo, stopOrderRepo, triggerEngine, trading, market, stopLoss,
+		&mockLogger{}, &MonitoringEngineConfig{UpdateInterval: time.Second})
+	engine.now = func() time.Time {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		return current
+	}
+
+	saveOrder := func(orderID, symbol string, value float64) {
+		order := &repository.ConditionalOrder{
+			OrderID:  orderID,
+			Symbol:   symbol,
+			Side:     api.OrderSideSell,
+			Type:     api.OrderTypeMarket,
+			Quantity: 0.1,
+			TriggerCondition: &repository.TriggerCondition{
+				Type:     repository.TriggerTypePrice,
+				Operator: repository.OperatorGreaterEqual,
+				Value:    value,
+			},
+			Status:    repository.ConditionalOrderStatusPending,
+			CreatedAt: current.Unix(),
+		}
+		if err := repo.Save(order); err != nil {
+			t.Fatalf("failed to save order: %v", err)
+		}
+	}
+	push := func(symbol string, price float64) {
+		market.mu.Lock()
+		prices := market.feeds[symbol]
+		market.mu.Unlock()
+		prices <- price
+
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			if got, ok := engine.feedPrice(symbol); ok && got == price {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("engine did not receive pushed price %v", price)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	saveOrder("take-profit", "BTCUSDT", 51000)
+
+	// The first check opens the feed and polls until a price is pushed
+	engine.checkAndTriggerOrders()
+	if polls := market.polls.Load(); polls != 1 {
+		t.Fatalf("polls before the first push = %d, want 1", polls)
+	}
+
+	// A fresh pushed price is used without polling
+	push("BTCUSDT", 50500)
+	advance(2 * time.Second)
+	engine.checkAndTriggerOrders()
+	if polls := market.polls.Load(); polls != 1 {
+		t.Errorf("polls with a fresh feed = %d, want 1", polls)
+	}
+
+	// A feed that went quiet is stale and the engine polls again
+	advance(5 * time.Second)
+	engine.checkAndTriggerOrders()
+	if polls := market.polls.Load(); polls != 2 {
+		t.Errorf("polls with a stale feed = %d, want 2", polls)
+	}
+
+	// A pushed price triggers the order
+	push("BTCUSDT", 51000)
+	advance(2 * time.Second)
+	engine.checkAndTriggerOrders()
+	executed, _ := repo.FindByID("take-profit")
+	if executed.Status == repository.ConditionalOrderStatusPending || market.polls.Load() != 2 {
+		t.Fatalf("status = %s, polls = %d; want the pushed price to trigger the order", executed.Status, market.polls.Load())
+	}
+
+	// Without active orders left the feed is closed
+	engine.checkAndTriggerOrders()
+	if !market.feedCancelled("BTCUSDT") {
+		t.Error("feed of a symbol without orders was not cancelled")
+	}
+
+	// A failed subscription polls and is retried after the retry interval
+	market.mu.Lock()
+	market.fail = true
+	market.mu.Unlock()
+	saveOrder("eth-take-profit", "ETHUSDT", 60000)
+	engine.checkAndTriggerOrders()
+	market.mu.Lock()
+	market.fail = false
+	market.mu.Unlock()
+
+	advance(2 * time.Second)
+	engine.checkAndTriggerOrders()
+	market.mu.Lock()
+	_, subscribed := market.feeds["ETHUSDT"]
+	market.mu.Unlock()
+	if subscribed {
+		t.Error("failed subscription retried before the retry interval")
+	}
+
+	advance(feedRetryInterval)
+	engine.checkAndTriggerOrders()
+	market.mu.Lock()
+	_, subscribed = market.feeds["ETHUSDT"]
+	market.mu.Unlock()
+	if !subscribed {
+		t.Fatal("failed subscription not retried after the retry interval")
+	}
+
+	// Stopping the engine closes every feed
+	engine.stopPriceFeeds()
+	if !market.feedCancelled("ETHUSDT") {
+		t.Error("stopPriceFeeds() did not cancel the feed")
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"binance-trader/internal/repository"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MonitoringGapReport summarizes what the monitoring engine decided after resuming from a
+// gap between cycles, such as a suspended laptop or a migrated VM
+type MonitoringGapReport struct {
+	Start    time.Time // Last cycle before the gap
+	End      time.Time // First cycle after the gap
+	Duration time.Duration
+
+	Expired     []string // Orders whose time window ended during the gap
+	Waiting     []string // Orders whose time window has not opened yet
+	Reevaluated []string // Orders evaluated again on fresh prices only
+	ResetPrices []string // Symbols whose pre-gap prices were discarded
+}
+
+// Summary returns a one-line description of the decisions taken
+func (r *MonitoringGapReport) Summary() string {
+	return fmt.Sprintf("monitoring paused for %s: %d expired, %d re-evaluated on fresh prices, %d waiting for their window",
+		r.Duration.Round(time.Second), len(r.Expired), len(r.Reevaluated), len(r.Waiting))
+}
+
+// defaultGapThreshold returns the gap threshold used when none is configured
+func defaultGapThreshold(updateInterval time.Duration) time.Duration {
+	if threshold := 10 * updateInterval; threshold > time.Minute {
+		return threshold
+	}
+	return time.Minute
+}
+
+// OnMonitoringGap registers a callback invoked after the engine has handled a monitoring gap
+func (me *MonitoringEngine) OnMonitoringGap(callback func(report *MonitoringGapReport)) {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	me.gapCallbacks = append(me.gapCallbacks, callback)
+}
+
+// recordCycle stamps the start of a cycle and returns the previous stamp, the current one and
+// whether the time between them is a gap. Wall clock readings are compared because the
+// monotonic clock does not advance while the machine is suspended.
+func (me *MonitoringEngine) recordCycle() (time.Time, time.Time, bool) {
+	now := me.now().Round(0)
+
+	me.mu.Lock()
+	last := me.lastCycle
+	me.lastCycle = now
+	me.mu.Unlock()
+
+	return last, now, !last.IsZero() && now.Sub(last) > me.gapThreshold
+}
+
+// handleMonitoringGap discards prices observed before the gap, expires orders whose window
+// ended while nothing was monitored and notifies the registered callbacks
+func (me *MonitoringEngine) handleMonitoringGap(start, end time.Time) {
+	report := &MonitoringGapReport{
+		Start:    start,
+		End:      end,
+		Duration: end.Sub(start),
+	}
+
+	me.logger.Warn("Monitoring gap detected", map[string]interface{}{
+		"gap_start":     start.Format(time.RFC3339),
+		"gap_end":       end.Format(time.RFC3339),
+		"gap_duration":  report.Duration.String(),
+		"gap_threshold": me.gapThreshold.String(),
+	})
+
+	// Comparisons must start again from fresh readings, not from the last pre-gap price.
+	// Symbols stay cached without a price so their trailing stops keep being processed.
+	me.mu.Lock()
+	for symbol := range me.marketDataCache {
+		report.ResetPrices = append(report.ResetPrices, symbol)
+		me.marketDataCache[symbol] = &MarketData{Symbol: symbol}
+	}
+	checker := me.priceChecker
+	callbacks := make([]func(report *MonitoringGapReport), len(me.gapCallbacks))
+	copy(callbacks, me.gapCallbacks)
+	me.mu.Unlock()
+
+	if checker != nil {
+		checker.Reset()
+	}
+
+	orders, err := me.repo.FindActiveOrders()
+	if err != nil {
+		me.logger.Error("Failed to load active orders after monitoring gap", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	for _, order := range orders {
+		switch {
+		case order.TimeWindow != nil && !order.TimeWindow.EndTime.IsZero() && end.After(order.TimeWindow.EndTime):
+			me.cancelOrder(order, repository.CancelReasonExpired)
+			report.Expired = append(report.Expired, order.OrderID)
+		case order.TimeWindow != nil && end.Before(order.TimeWindow.StartTime):
+			report.Waiting = append(report.Waiting, order.OrderID)
+		default:
+			report.Reevaluated = append(report.Reevaluated, order.OrderID)
+		}
+	}
+
+	sort.Strings(report.Expired)
+	sort.Strings(report.Waiting)
+	sort.Strings(report.Reevaluated)
+	sort.Strings(report.ResetPrices)
+
+	me.logger.Info("Monitoring gap handled", map[string]interface{}{
+		"gap_duration":  report.Duration.String(),
+		"expired":       report.Expired,
+		"waiting":       report.Waiting,
+		"reevaluated":   report.Reevaluated,
+		"reset_symbols": report.ResetPrices,
+	})
+
+	for _, callback := range callbacks {
+		callback(report)
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/internal/repository"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMonitoringEngine_MonitoringGap(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	current := t0
+
+	repo := repository.NewMemoryConditionalOrderRepository()
+	stopOrderRepo := repository.NewMemoryStopOrderRepository()
+	triggerEngine := NewTriggerEngine()
+	trading := &recordingSellTradingService{}
+	market := &mockStopLossMarketDataService{currentPrice: 100}
+	stopLoss := NewStopLossService(stopOrderRepo, triggerEngine, trading, market, &mockLogger{})
+
+	engine := NewMonitoringEngine(repo, stopOrderRepo, triggerEngine, trading, market, stopLoss,
+		&mockLogger{}, &MonitoringEngineConfig{UpdateInterval: time.Second})
+	engine.now = func() time.Time { return current }
+	engine.SetPriceSanityChecker(NewPriceSanityChecker(&config.PriceSanityConfig{MaxDeviationPercent: 10}, &mockLogger{}))
+
+	var reports []*MonitoringGapReport
+	engine.OnMonitoringGap(func(report *MonitoringGapReport) {
+		reports = append(reports, report)
+	})
+
+	if _, err := stopLoss.SetTrailingStop("BTCUSDT", 1.0, 5.0); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Every order buys the dip below 60; only the time windows differ
+	dipOrder := func(orderID string, window *repository.TimeWindow) {
+		order := &repository.ConditionalOrder{
+			OrderID:  orderID,
+			Symbol:   "BTCUSDT",
+			Side:     api.OrderSideBuy,
+			Type:     api.OrderTypeMarket,
+			Quantity: 0.1,
+			TriggerCondition: &repository.TriggerCondition{
+				Type:     repository.TriggerTypePrice,
+				Operator: repository.OperatorLessEqual,
+				Value:    60,
+			},
+			TimeWindow: window,
+			Status:     repository.ConditionalOrderStatusPending,
+			CreatedAt:  t0.Unix(),
+		}
+		if err := repo.Save(order); err != nil {
+			t.Fatalf("failed to save order: %v", err)
+		}
+	}
+	dipOrder("window-ends-in-gap", &repository.TimeWindow{StartTime: t0.Add(-time.Hour), EndTime: t0.Add(30 * time.Minute)})
+	dipOrder("window-opens-later", &repository.TimeWindow{StartTime: t0.Add(3 * time.Hour), EndTime: t0.Add(4 * time.Hour)})
+	dipOrder("no-window", nil)
+
+	// Last cycle before the suspend sees a calm market
+	engine.checkAndTriggerOrders()
+	if len(reports) != 0 || len(trading.sells) != 0 {
+		t.Fatalf("expected a quiet first cycle, got reports %v sells %v", reports, trading.sells)
+	}
+
+	// The machine sleeps for two hours while the price halves
+	current = t0.Add(2 * time.Hour)
+	market.currentPrice = 50
+	engine.checkAndTriggerOrders()
+
+	if len(reports) != 1 {
+		t.Fatalf("expected one gap report, got %d", len(reports))
+	}
+	report := reports[0]
+	if report.Duration != 2*time.Hour || !report.Start.Equal(t0) || !report.End.Equal(current) {
+		t.Errorf("unexpected gap bounds: %s from %s to %s", report.Duration, report.Start, report.End)
+	}
+	if !reflect.DeepEqual(report.Expired, []string{"window-ends-in-gap"}) ||
+		!reflect.DeepEqual(report.Waiting, []string{"window-opens-later"}) ||
+		!reflect.DeepEqual(report.Reevaluated, []string{"no-window"}) ||
+		!reflect.DeepEqual(report.ResetPrices, []string{"BTCUSDT"}) {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	// The order whose window passed during the gap expires instead of triggering
+	expired, _ := repo.FindByID("window-ends-in-gap")
+	if expired.Status != repository.ConditionalOrderStatusCancelled || expired.CancelReason != repository.CancelReasonExpired {
+		t.Errorf("expected the order to expire, got %s/%s", expired.Status, expired.CancelReason)
+	}
+	waiting, _ := repo.FindByID("window-opens-later")
+	if waiting.Status != repository.ConditionalOrderStatusPending {
+		t.Errorf("expected the order to keep waiting for its window, got %s", waiting.Status)
+	}
+	executed, _ := repo.FindByID("no-window")
+	if executed.Status != repository.ConditionalOrderStatusExecuted {
+		t.Errorf("expected the order to trigger on the fresh price, got %s", executed.Status)
+	}
+
+	// The post-gap price is a new baseline rather than a spike against the pre-gap one
+	if len(trading.sells) != 1 {
+		t.Errorf("expected the trailing stop to trigger on the first post-gap reading, got sells %v", trading.sells)
+	}
+
+	// Regular cycles afterwards are not gaps
+	current = current.Add(time.Second)
+	engine.checkAndTriggerOrders()
+	if len(reports) != 1 {
+		t.Errorf("expected no further gap reports, got %d", len(reports))
+	}
+}
+
+func TestMonitoringGapReport_Summary(t *testing.T) {
+	report := &MonitoringGapReport{
+		Duration:    90 * time.Minute,
+		Expired:     []string{"a", "b"},
+		Reevaluated: []string{"c"},
+	}
+
+	want := "monitoring paused for 1h30m0s: 2 expired, 1 re-evaluated on fresh prices, 0 waiting for their window"
+	if got := report.Summary(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
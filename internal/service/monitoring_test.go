@@ -2,7 +2,11 @@ package service
 
 import (
 	"binance-trader/internal/api"
+	"binance-trader/internal/config"
 	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	"binance-trader/pkg/metrics"
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -259,6 +263,10 @@ func (m *mockLoggerCapture) SetTradingType(tradingType string) {
 	// No-op for mock
 }
 
+func (m *mockLoggerCapture) Close() error {
+	return nil
+}
+
 func (m *mockLoggerCapture) LogFuturesAPIOperation(operationType string, result string, fields map[string]interface{}) {
 	entry := make(map[string]interface{})
 	entry["level"] = "info"
@@ -331,6 +339,10 @@ func (m *mockTradingService) PlaceMarketBuyOrder(symbol string, quantity float64
 	}, nil
 }
 
+func (m *mockTradingService) PlaceMarketBuyOrderByQuote(symbol string, quoteAmount float64) (*api.Order, error) {
+	return m.PlaceMarketBuyOrder(symbol, 0)
+}
+
 func (m *mockTradingService) PlaceMarketSellOrder(symbol string, quantity float64) (*api.Order, error) {
 	return &api.Order{
 		OrderID:     12346,
@@ -361,10 +373,37 @@ func (m *mockTradingService) PlaceLimitSellOrder(symbol string, price, quantity
 	}, nil
 }
 
+func (m *mockTradingService) PlaceLimitBuyOrder(symbol string, price, quantity float64) (*api.Order, error) {
+	return &api.Order{
+		OrderID:     12347,
+		Symbol:      symbol,
+		Side:        api.OrderSideBuy,
+		Type:        api.OrderTypeLimit,
+		Status:      api.OrderStatusNew,
+		Price:       price,
+		OrigQty:     quantity,
+		ExecutedQty: 0,
+		Time:        time.Now().Unix(),
+		UpdateTime:  time.Now().Unix(),
+	}, nil
+}
+
+func (m *mockTradingService) PlaceOCOOrder(symbol string, side api.OrderSide, quantity, price, stopPrice, stopLimitPrice float64) (*api.OCOResponse, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockTradingService) CancelOCOOrder(symbol string, orderListID int64) error {
+	return nil
+}
+
 func (m *mockTradingService) CancelOrder(orderID int64) error {
 	return nil
 }
 
+func (m *mockTradingService) CancelAllOrders(symbol string) (int, error) {
+	return 0, nil
+}
+
 func (m *mockTradingService) GetOrderStatus(orderID int64) (*OrderStatus, error) {
 	return &OrderStatus{
 		OrderID:     orderID,
@@ -377,6 +416,41 @@ func (m *mockTradingService) GetActiveOrders() ([]*api.Order, error) {
 	return []*api.Order{}, nil
 }
 
+func (m *mockTradingService) GetAllBalances() ([]api.Balance, error) {
+	return nil, nil
+}
+
+func (m *mockTradingService) GetOrderLifecycle(symbol string, orderID int64) (*OrderLifecycle, error) {
+	return nil, nil
+}
+
+func (m *mockTradingService) SetSymbolGuard(guard SymbolFailureGuard) {}
+
+func (m *mockTradingService) SetMetrics(sink *metrics.Sink) {}
+
+func (m *mockTradingService) SubmitOrderIntent(intent *OrderIntent) (*api.Order, error) {
+	return nil, nil
+}
+
+func (m *mockTradingService) SetReplayProtection(protection ReplayProtection) {}
+
+func (m *mockTradingService) SetUserDataStream(stream api.UserDataStream) error {
+	return nil
+}
+
+func (m *mockTradingService) SubscribeOrderUpdates(handler func(*api.Order)) (func(), error) {
+	return func() {}, nil
+}
+
+func (m *mockTradingService) SetSlippageProtection(marketData MarketDataService, maxSlippagePercent float64) {
+}
+
+func (m *mockTradingService) SetSymbolFilter(filter *SymbolFilter) {}
+
+func (m *mockTradingService) ExecuteTWAP(ctx context.Context, symbol string, side api.OrderSide, totalQty float64, duration time.Duration, slices int) (*TWAPExecution, error) {
+	return nil, nil
+}
+
 // Mock market data service for testing
 type mockMarketDataService struct {
 	prices map[string]float64
@@ -397,6 +471,24 @@ func (m *mockMarketDataService) SubscribeToPrice(symbol string, callback func(fl
 	return nil
 }
 
+func (m *mockMarketDataService) GetBestBidAsk(symbol string) (*BestBidAsk, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockMarketDataService) SetBookTickerCache(cache BookTickerCache) {}
+
+func (m *mockMarketDataService) GetOrderBook(symbol string, limit int) (*api.OrderBook, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockMarketDataService) SetPriceStream(stream PriceStream) {}
+
+func (m *mockMarketDataService) SubscribePriceFeed(ctx context.Context, symbol string) (<-chan float64, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockMarketDataService) ConnectedStreams() []string { return nil }
+
 func (m *mockMarketDataService) GetVolume(symbol string, timeWindow time.Duration) (float64, error) {
 	return 1000.0, nil
 }
@@ -548,17 +640,95 @@ func TestMonitoringEngine_TimeWindowFiltering(t *testing.T) {
 	// Process the order
 	engine.processOrder(order)
 
-	// Check that order was NOT executed (time window expired)
+	// Check that order was NOT executed but cancelled as expired
 	updatedOrder, err := repo.FindByID("test-order-2")
 	if err != nil {
 		t.Fatalf("Failed to find order: %v", err)
 	}
 
-	if updatedOrder.Status != repository.ConditionalOrderStatusPending {
+	if updatedOrder.Status != repository.ConditionalOrderStatusCancelled || updatedOrder.CancelReason != repository.CancelReasonExpired {
+		t.Errorf("Expected order to be cancelled as EXPIRED, got %s (%s)", updatedOrder.Status, updatedOrder.CancelReason)
+	}
+	if updatedOrder.CancelledBy != "monitor" || updatedOrder.CancelledAt == 0 {
+		t.Errorf("Expected cancellation by monitor with a timestamp, got %q at %d", updatedOrder.CancelledBy, updatedOrder.CancelledAt)
+	}
+
+	// An order whose window has not opened yet stays pending
+	order.OrderID = "test-order-3"
+	order.TimeWindow = &repository.TimeWindow{
+		StartTime: time.Now().Add(1 * time.Hour),
+		EndTime:   time.Now().Add(2 * time.Hour),
+	}
+	repo.Save(order)
+	engine.processOrder(order)
+
+	if updatedOrder, _ := repo.FindByID("test-order-3"); updatedOrder.Status != repository.ConditionalOrderStatusPending {
 		t.Errorf("Expected order status to remain PENDING, got %s", updatedOrder.Status)
 	}
 }
 
+// riskBlockedTradingService rejects every order with err
+type riskBlockedTradingService struct {
+	mockTradingService
+	err error
+}
+
+func (m *riskBlockedTradingService) PlaceMarketBuyOrder(symbol string, quantity float64) (*api.Order, error) {
+	return nil, m.err
+}
+
+func TestMonitoringEngine_RiskBlockedTriggerCancelled(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		cancelled bool
+	}{
+		{name: "order amount limit", err: errors.NewTradingError(errors.ErrRiskLimitExceeded, "order amount 60000.00 exceeds maximum limit 10000.00", 0, nil), cancelled: true},
+		{name: "daily order limit", err: errors.NewTradingError(errors.ErrDailyLimitReached, "daily order limit reached: 100/100", 0, nil)},
+		{name: "symbol paused", err: errors.NewTradingError(errors.ErrSymbolPaused, "new orders for BTCUSDT are paused", 0, nil)},
+		{name: "notional cap", err: errors.NewTradingError(errors.ErrNotionalCapReached, "notional throughput limit reached", 0, nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryConditionalOrderRepository()
+			mockMarket := &mockMarketDataService{prices: map[string]float64{"BTCUSDT": 50000.0}}
+			mockLogger := &mockLoggerCapture{entries: make([]map[string]interface{}, 0)}
+			engine := NewMonitoringEngine(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(), &riskBlockedTradingService{err: tt.err}, mockMarket, &mockStopLossService{}, mockLogger, nil)
+
+			order := &repository.ConditionalOrder{
+				OrderID:  "test-order-risk",
+				Symbol:   "BTCUSDT",
+				Side:     api.OrderSideBuy,
+				Type:     api.OrderTypeMarket,
+				Quantity: 1.0,
+				TriggerCondition: &repository.TriggerCondition{
+					Type:     repository.TriggerTypePrice,
+					Operator: repository.OperatorGreaterThan,
+					Value:    40000.0,
+				},
+				Status:    repository.ConditionalOrderStatusPending,
+				CreatedAt: time.Now().Unix(),
+			}
+			repo.Save(order)
+
+			engine.processOrder(order)
+
+			// Permanent limits cancel the order; temporary guards leave it pending for a later trigger
+			updatedOrder, _ := repo.FindByID("test-order-risk")
+			if tt.cancelled {
+				if updatedOrder.Status != repository.ConditionalOrderStatusCancelled || updatedOrder.CancelReason != repository.CancelReasonRiskBlock {
+					t.Errorf("Expected order to be cancelled as RISK_BLOCK, got %s (%s)", updatedOrder.Status, updatedOrder.CancelReason)
+				}
+				return
+			}
+			if updatedOrder.Status != repository.ConditionalOrderStatusPending {
+				t.Errorf("Expected order to stay PENDING, got %s (%s)", updatedOrder.Status, updatedOrder.CancelReason)
+			}
+		})
+	}
+}
+
 func TestMonitoringEngine_ConcurrentSafety(t *testing.T) {
 	// Create monitoring engine
 	repo := repository.NewMemoryConditionalOrderRepository()
@@ -862,6 +1032,10 @@ func (m *mockStopLossService) SetTakeProfit(symbol string, position float64, tar
 	return &repository.StopOrder{}, nil
 }
 
+func (m *mockStopLossService) SetTakeProfitLadder(symbol string, position float64, levels []TPLevel) ([]*repository.StopOrder, error) {
+	return []*repository.StopOrder{}, nil
+}
+
 func (m *mockStopLossService) SetStopLossTakeProfit(symbol string, position float64, stopPrice, targetPrice float64) (*repository.StopOrderPair, error) {
 	return &repository.StopOrderPair{}, nil
 }
@@ -870,8 +1044,8 @@ func (m *mockStopLossService) SetTrailingStop(symbol string, position float64, t
 	return &repository.TrailingStopOrder{}, nil
 }
 
-func (m *mockStopLossService) CancelStopOrder(orderID string) error {
-	return nil
+func (m *mockStopLossService) CancelStopOrder(orderID, symbol string) (*CancelledStopOrder, error) {
+	return &CancelledStopOrder{}, nil
 }
 
 func (m *mockStopLossService) GetActiveStopOrders(symbol string) ([]*repository.StopOrder, error) {
@@ -881,3 +1055,83 @@ func (m *mockStopLossService) GetActiveStopOrders(symbol string) ([]*repository.
 func (m *mockStopLossService) UpdateTrailingStop(orderID string, newTrailPercent float64) error {
 	return nil
 }
+
+func (m *mockStopLossService) SetATRTrailingStop(symbol string, position float64, trail ATRTrail) (*repository.TrailingStopOrder, error) {
+	return &repository.TrailingStopOrder{}, nil
+}
+
+func (m *mockStopLossService) SetTrailConfig(cfg *config.StopLossConfig) {}
+
+func (m *mockStopLossService) SetMetrics(sink *metrics.Sink) {}
+
+func TestMonitoringEngine_ConditionalOrderTTL(t *testing.T) {
+	now := time.Now()
+	repo := repository.NewMemoryConditionalOrderRepository()
+	market := &mockMarketDataService{prices: map[string]float64{"BTCUSDT": 49000}}
+	svc := NewConditionalOrderService(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+		&mockTradingService{}, market, &mockStopLossService{}, &mockLogger{})
+	engine := NewMonitoringEngine(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+		&mockTradingService{}, market, &mockStopLossService{}, &mockLogger{}, nil)
+	engine.now = func() time.Time { return now }
+
+	// Both buy above 50000 within the next four hours
+	create := func() *repository.ConditionalOrder {
+		order, err := svc.CreateConditionalOrder(&repository.ConditionalOrderRequest{
+			Symbol:   "BTCUSDT",
+			Side:     api.OrderSideBuy,
+			Type:     api.OrderTypeMarket,
+			Quantity: 0.001,
+			TriggerCondition: &repository.TriggerCondition{
+				Type:     repository.TriggerTypePrice,
+				Operator: repository.OperatorGreaterEqual,
+				Value:    50000,
+			},
+			TimeWindow:     &repository.TimeWindow{EndTime: now.Add(4 * time.Hour)},
+			AllowDuplicate: true,
+		})
+		if err != nil {
+			t.Fatalf("CreateConditionalOrder() error = %v", err)
+		}
+		return order
+	}
+	early, late := create(), create()
+	status := func(order *repository.ConditionalOrder) *repository.ConditionalOrder {
+		updated, _ := repo.FindByID(order.OrderID)
+		return updated
+	}
+
+	// Below the trigger within the TTL: both wait
+	engine.processOrder(early)
+	engine.processOrder(late)
+	if status(early).Status != repository.ConditionalOrderStatusPending || status(late).Status != repository.ConditionalOrderStatusPending {
+		t.Fatalf("orders = %s, %s, want both pending", status(early).Status, status(late).Status)
+	}
+
+	// The trigger fires an hour before the order expires
+	market.prices["BTCUSDT"] = 50500
+	now = now.Add(3 * time.Hour)
+	engine.processOrder(early)
+	if got := status(early).Status; got != repository.ConditionalOrderStatusExecuted {
+		t.Errorf("order triggered within its TTL = %s, want executed", got)
+	}
+
+	// Past the TTL the trigger is not evaluated and the order expires instead
+	now = now.Add(2 * time.Hour)
+	engine.processOrder(late)
+	expired := status(late)
+	if expired.Status != repository.ConditionalOrderStatusCancelled || expired.CancelReason != repository.CancelReasonExpired {
+		t.Errorf("order past its TTL = %s (%s), want cancelled as expired", expired.Status, expired.CancelReason)
+	}
+
+	history, err := svc.GetConditionalOrderHistory(0, time.Now().UnixMilli()+1)
+	if err != nil {
+		t.Fatalf("GetConditionalOrderHistory() error = %v", err)
+	}
+	found := false
+	for _, order := range history {
+		found = found || (order.OrderID == late.OrderID && order.CancelReason == repository.CancelReasonExpired)
+	}
+	if len(history) != 2 || !found {
+		t.Errorf("history = %d orders, want the executed and the expired one", len(history))
+	}
+}
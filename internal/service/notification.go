@@ -0,0 +1,509 @@
+package service
+
+import (
+	"binance-trader/internal/config"
+	"binance-trader/internal/replay"
+	"binance-trader/pkg/logger"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultNotificationCheckInterval is used when the notifier is started without an interval
+const DefaultNotificationCheckInterval = time.Minute
+
+// NotificationClass categorizes a notification
+type NotificationClass string
+
+const (
+	NotificationLiquidationWarning  NotificationClass = "LIQUIDATION_WARNING"
+	NotificationKillSwitch          NotificationClass = "KILL_SWITCH"
+	NotificationUnprotectedPosition NotificationClass = "UNPROTECTED_POSITION"
+	NotificationFill                NotificationClass = "FILL"
+	NotificationTrigger             NotificationClass = "TRIGGER"
+	NotificationMaintenance         NotificationClass = "MAINTENANCE"
+	NotificationDigest              NotificationClass = "DIGEST"
+	NotificationInfo                NotificationClass = "INFO"
+)
+
+// IsCritical reports whether notifications of the class bypass quiet hours
+func (c NotificationClass) IsCritical() bool {
+	switch c {
+	case NotificationLiquidationWarning, NotificationKillSwitch, NotificationUnprotectedPosition:
+		return true
+	default:
+		return false
+	}
+}
+
+// Notification is a message delivered to the notification channels
+type Notification struct {
+	Class   NotificationClass `json:"class"`
+	Title   string            `json:"title"`
+	Message string            `json:"message"`
+	Time    time.Time         `json:"time"`
+}
+
+// NotificationChannel delivers notifications to one destination
+type NotificationChannel interface {
+	Name() string
+	Send(notification *Notification) error
+}
+
+// DigestSource returns the trading activity of a period for the daily digest
+type DigestSource func(from, to time.Time) (*replay.Activity, error)
+
+// Notifier delivers notifications to its channels, holding back non-critical ones during
+// a channel's quiet hours and sending the daily digest
+type Notifier interface {
+	// Notify delivers a notification, or queues it on channels in quiet hours
+	Notify(notification *Notification)
+
+	// AddChannel adds a channel with optional quiet hours
+	AddChannel(channel NotificationChannel, quietHours config.QuietHoursConfig) error
+
+	// SetDigestSource sets where the daily digest reads the day's fills and triggers from
+	SetDigestSource(source DigestSource)
+
+	// SetPnLSource sets the optional source of the unrealized PnL reported by the daily digest
+	SetPnLSource(source func() (float64, error))
+
+	// Deliver sends the queued digest of channels whose quiet hours ended and the daily digest when due
+	Deliver()
+
+	// Scheduled delivery
+	StartMonitoring(checkInterval time.Duration) error
+	StopMonitoring() error
+}
+
+// quietHours is a daily window in a timezone; an end before the start spans midnight
+type quietHours struct {
+	start    time.Duration
+	end      time.Duration
+	location *time.Location
+}
+
+// contains reports whether t falls inside the window
+func (q *quietHours) contains(t time.Time) bool {
+	local := t.In(q.location)
+	offset := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute + time.Duration(local.Second())*time.Second
+
+	if q.start < q.end {
+		return offset >= q.start && offset < q.end
+	}
+	return offset >= q.start || offset < q.end
+}
+
+// notifierChannel is a channel with its quiet hours and queued notifications
+type notifierChannel struct {
+	channel NotificationChannel
+	quiet   *quietHours
+	queue   []*Notification
+}
+
+// notifier implements Notifier
+type notifier struct {
+	mu       sync.Mutex
+	channels []*notifierChannel
+
+	dailyDigest    bool
+	digestAt       time.Duration
+	digestLocation *time.Location
+	digestSource   DigestSource
+	pnlSource      func() (float64, error)
+	lastDigestDay  string
+
+	logger logger.Logger
+	now    func() time.Time
+
+	// Monitoring
+	stopChan     chan struct{}
+	monitoringMu sync.Mutex
+	isMonitoring bool
+}
+
+// NewNotifier creates a notifier with the channels of the configuration
+func NewNotifier(cfg *config.NotificationsConfig, log logger.Logger) (Notifier, error) {
+	if cfg == nil {
+		cfg = &config.NotificationsConfig{}
+	}
+
+	n := &notifier{
+		logger: log,
+		now:    time.Now,
+	}
+
+	for _, channelCfg := range cfg.Channels {
+		var channel NotificationChannel
+		switch channelCfg.Type {
+		case "log":
+			channel = NewLogChannel(channelCfg.Name, log)
+		case "webhook":
+			channel = NewWebhookChannel(channelCfg.Name, channelCfg.URL)
+		default:
+			return nil, fmt.Errorf("unknown notification channel type %q", channelCfg.Type)
+		}
+
+		if err := n.AddChannel(channel, channelCfg.QuietHours); err != nil {
+			return nil, fmt.Errorf("channel %s: %w", channelCfg.Name, err)
+		}
+	}
+
+	if cfg.DailyDigest.Enabled {
+		at, location, err := cfg.DailyDigest.Schedule()
+		if err != nil {
+			return nil, fmt.Errorf("daily digest: %w", err)
+		}
+		n.dailyDigest = true
+		n.digestAt = at
+		n.digestLocation = location
+	}
+
+	return n, nil
+}
+
+// AddChannel adds a channel with optional quiet hours
+func (n *notifier) AddChannel(channel NotificationChannel, quietHoursCfg config.QuietHoursConfig) error {
+	entry := &notifierChannel{channel: channel}
+	if quietHoursCfg.Enabled() {
+		start, end, location, err := quietHoursCfg.Window()
+		if err != nil {
+			return fmt.Errorf("quiet hours: %w", err)
+		}
+		entry.quiet = &quietHours{start: start, end: end, location: location}
+	}
+
+	n.mu.Lock()
+	n.channels = append(n.channels, entry)
+	n.mu.Unlock()
+	return nil
+}
+
+// SetDigestSource sets where the daily digest reads the day's fills and triggers from
+func (n *notifier) SetDigestSource(source DigestSource) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.digestSource = source
+}
+
+// SetPnLSource sets the optional source of the unrealized PnL reported by the daily digest
+func (n *notifier) SetPnLSource(source func() (float64, error)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.pnlSource = source
+}
+
+// Notify delivers a notification to every channel. Non-critical notifications are queued on
+// channels in quiet hours; critical ones are always sent immediately.
+func (n *notifier) Notify(notification *Notification) {
+	now := n.now()
+	if notification.Time.IsZero() {
+		notification.Time = now
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, entry := range n.channels {
+		if !notification.Class.IsCritical() && entry.quiet != nil && entry.quiet.contains(now) {
+			entry.queue = append(entry.queue, notification)
+			continue
+		}
+
+		// Keep the order: a digest queued before this notification goes out first
+		n.flushQueue(entry, now)
+		n.send(entry, notification)
+	}
+}
+
+// Deliver sends the queued digest of channels whose quiet hours ended and the daily digest when due
+func (n *notifier) Deliver() {
+	now := n.now()
+
+	n.mu.Lock()
+	for _, entry := range n.channels {
+		n.flushQueue(entry, now)
+	}
+	dueDay, due := n.dailyDigestDue(now)
+	if due {
+		n.lastDigestDay = dueDay
+	}
+	n.mu.Unlock()
+
+	if !due {
+		return
+	}
+
+	digest, err := n.buildDailyDigest(now)
+	if err != nil {
+		n.logger.Error("Failed to build daily digest", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	n.Notify(digest)
+}
+
+// flushQueue sends the queued notifications of a channel as one digest once its quiet hours ended
+func (n *notifier) flushQueue(entry *notifierChannel, now time.Time) {
+	if len(entry.queue) == 0 || (entry.quiet != nil && entry.quiet.contains(now)) {
+		return
+	}
+
+	queued := entry.queue
+	entry.queue = nil
+	n.send(entry, buildQuietHoursDigest(queued, now))
+}
+
+// send delivers a notification to a channel; failures are logged and not retried
+func (n *notifier) send(entry *notifierChannel, notification *Notification) {
+	if err := entry.channel.Send(notification); err != nil {
+		n.logger.Error("Failed to send notification", map[string]interface{}{
+			"channel": entry.channel.Name(),
+			"class":   string(notification.Class),
+			"title":   notification.Title,
+			"error":   err.Error(),
+		})
+	}
+}
+
+// buildQuietHoursDigest combines notifications queued during quiet hours into one message
+func buildQuietHoursDigest(queued []*Notification, now time.Time) *Notification {
+	var message strings.Builder
+	for _, notification := range queued {
+		fmt.Fprintf(&message, "%s [%s] %s", notification.Time.UTC().Format("15:04"), notification.Class, notification.Title)
+		if notification.Message != "" {
+			fmt.Fprintf(&message, ": %s", notification.Message)
+		}
+		message.WriteString("\n")
+	}
+
+	return &Notification{
+		Class:   NotificationDigest,
+		Title:   fmt.Sprintf("Quiet hours digest: %d notification(s)", len(queued)),
+		Message: strings.TrimSuffix(message.String(), "\n"),
+		Time:    now,
+	}
+}
+
+// digestDay returns the day whose digest is due last at or before t
+func (n *notifier) digestDay(t time.Time) string {
+	local := t.In(n.digestLocation)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, n.digestLocation)
+	if local.Before(midnight.Add(n.digestAt)) {
+		midnight = midnight.AddDate(0, 0, -1)
+	}
+	return midnight.Format("2006-01-02")
+}
+
+// dailyDigestDue reports whether the digest time passed since the last daily digest. The
+// first check only records the current day, so the first digest is sent at the next digest time.
+func (n *notifier) dailyDigestDue(now time.Time) (string, bool) {
+	if !n.dailyDigest {
+		return "", false
+	}
+	day := n.digestDay(now)
+	if n.lastDigestDay == "" {
+		n.lastDigestDay = day
+		return day, false
+	}
+	return day, day != n.lastDigestDay
+}
+
+// buildDailyDigest summarizes the fills, triggers and PnL of the 24 hours before now
+func (n *notifier) buildDailyDigest(now time.Time) (*Notification, error) {
+	n.mu.Lock()
+	source, pnlSource := n.digestSource, n.pnlSource
+	n.mu.Unlock()
+
+	if source == nil {
+		return nil, fmt.Errorf("no digest source configured")
+	}
+
+	activity, err := source(now.Add(-24*time.Hour), now)
+	if err != nil {
+		return nil, err
+	}
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "Fills: %d\n", activity.Fills)
+	for _, symbol := range activity.Symbols {
+		fmt.Fprintf(&message, "  %s: %d fill(s), bought %.8f, sold %.8f, realized PnL %.2f\n",
+			symbol.Symbol, symbol.Fills, symbol.BoughtQty, symbol.SoldQty, symbol.RealizedPnL)
+	}
+	fmt.Fprintf(&message, "Triggers: %d\n", activity.Triggers)
+	fmt.Fprintf(&message, "Errors: %d\n", activity.Errors)
+	fmt.Fprintf(&message, "Realized PnL: %.2f", activity.RealizedPnL)
+
+	if pnlSource != nil {
+		if unrealized, err := pnlSource(); err == nil {
+			fmt.Fprintf(&message, "\nUnrealized PnL: %.2f", unrealized)
+		} else {
+			n.logger.Warn("Failed to get unrealized PnL for daily digest", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	return &Notification{
+		Class:   NotificationDigest,
+		Title:   fmt.Sprintf("Daily digest %s", n.digestDay(now)),
+		Message: message.String(),
+		Time:    now,
+	}, nil
+}
+
+// StartMonitoring starts delivering queued digests and the daily digest on a schedule
+func (n *notifier) StartMonitoring(checkInterval time.Duration) error {
+	n.monitoringMu.Lock()
+	defer n.monitoringMu.Unlock()
+
+	if n.isMonitoring {
+		return fmt.Errorf("monitoring is already running")
+	}
+
+	if checkInterval <= 0 {
+		checkInterval = DefaultNotificationCheckInterval
+	}
+
+	n.stopChan = make(chan struct{})
+	n.isMonitoring = true
+
+	go n.monitoringLoop(checkInterval)
+
+	n.logger.Info("Started notification delivery", map[string]interface{}{
+		"check_interval": checkInterval.String(),
+		"daily_digest":   n.dailyDigest,
+	})
+
+	return nil
+}
+
+// StopMonitoring stops the scheduled delivery
+func (n *notifier) StopMonitoring() error {
+	n.monitoringMu.Lock()
+	defer n.monitoringMu.Unlock()
+
+	if !n.isMonitoring {
+		return fmt.Errorf("monitoring is not running")
+	}
+
+	close(n.stopChan)
+	n.isMonitoring = false
+
+	n.logger.Info("Stopped notification delivery", nil)
+
+	return nil
+}
+
+// monitoringLoop delivers due digests on every tick
+func (n *notifier) monitoringLoop(checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-n.stopChan:
+			return
+		case <-ticker.C:
+			n.Deliver()
+		}
+	}
+}
+
+// NewJournalDigestSource returns a digest source reading the JSON log files written by the logger
+func NewJournalDigestSource(paths ...string) DigestSource {
+	return func(from, to time.Time) (*replay.Activity, error) {
+		combined := &replay.Journal{}
+		for _, path := range paths {
+			file, err := os.Open(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open journal %s: %w", path, err)
+			}
+			journal, err := replay.ParseJournal(file)
+			file.Close()
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse journal %s: %w", path, err)
+			}
+			combined.Entries = append(combined.Entries, journal.Entries...)
+		}
+		return replay.SummarizeActivity(combined, from, to), nil
+	}
+}
+
+// logChannel writes notifications to the log
+type logChannel struct {
+	name   string
+	logger logger.Logger
+}
+
+// NewLogChannel creates a channel writing notifications to the log
+func NewLogChannel(name string, log logger.Logger) NotificationChannel {
+	return &logChannel{name: name, logger: log}
+}
+
+// Name returns the channel name
+func (c *logChannel) Name() string {
+	return c.name
+}
+
+// Send logs the notification, as a warning when it is critical
+func (c *logChannel) Send(notification *Notification) error {
+	fields := map[string]interface{}{
+		"channel":            c.name,
+		"notification_class": string(notification.Class),
+		"title":              notification.Title,
+		"notification":       notification.Message,
+	}
+	if notification.Class.IsCritical() {
+		c.logger.Warn("Notification", fields)
+	} else {
+		c.logger.Info("Notification", fields)
+	}
+	return nil
+}
+
+// webhookChannel posts notifications as JSON to an HTTPS endpoint
+type webhookChannel struct {
+	name       string
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookChannel creates a channel posting notifications as JSON to url
+func NewWebhookChannel(name, url string) NotificationChannel {
+	return &webhookChannel{
+		name:       name,
+		url:        url,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the channel name
+func (c *webhookChannel) Name() string {
+	return c.name
+}
+
+// Send posts the notification to the webhook
+func (c *webhookChannel) Send(notification *Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	resp, err := c.httpClient.Post(c.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"binance-trader/internal/config"
+	"binance-trader/internal/replay"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordingChannel records the notifications it receives
+type recordingChannel struct {
+	name string
+	sent []*Notification
+}
+
+func (c *recordingChannel) Name() string {
+	return c.name
+}
+
+func (c *recordingChannel) Send(notification *Notification) error {
+	c.sent = append(c.sent, notification)
+	return nil
+}
+
+// newTestNotifier returns a notifier with a controllable clock
+func newTestNotifier(t *testing.T, cfg *config.NotificationsConfig, now *time.Time) *notifier {
+	t.Helper()
+	n, err := NewNotifier(cfg, &mockLogger{})
+	if err != nil {
+		t.Fatalf("NewNotifier() unexpected error: %v", err)
+	}
+	impl := n.(*notifier)
+	impl.now = func() time.Time { return *now }
+	return impl
+}
+
+func TestQuietHoursContains(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+	overnight := &quietHours{start: 22 * time.Hour, end: 7 * time.Hour, location: berlin}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"before the window", time.Date(2024, 6, 1, 19, 59, 0, 0, time.UTC), false},
+		{"window start", time.Date(2024, 6, 1, 20, 0, 0, 0, time.UTC), true},
+		{"after midnight", time.Date(2024, 6, 2, 3, 0, 0, 0, time.UTC), true},
+		{"window end", time.Date(2024, 6, 2, 5, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := overnight.contains(tt.at); got != tt.want {
+				t.Errorf("contains(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifier_QueuesAcrossQuietHours(t *testing.T) {
+	now := time.Date(2024, 6, 1, 21, 0, 0, 0, time.UTC)
+	n := newTestNotifier(t, nil, &now)
+
+	quiet := &recordingChannel{name: "phone"}
+	loud := &recordingChannel{name: "log"}
+	if err := n.AddChannel(quiet, config.QuietHoursConfig{Start: "22:00", End: "07:00"}); err != nil {
+		t.Fatalf("AddChannel() unexpected error: %v", err)
+	}
+	if err := n.AddChannel(loud, config.QuietHoursConfig{}); err != nil {
+		t.Fatalf("AddChannel() unexpected error: %v", err)
+	}
+
+	n.Notify(&Notification{Class: NotificationFill, Title: "Filled BTCUSDT"})
+	if len(quiet.sent) != 1 {
+		t.Fatalf("notification before quiet hours should be sent, got %d", len(quiet.sent))
+	}
+
+	now = time.Date(2024, 6, 1, 23, 15, 0, 0, time.UTC)
+	n.Notify(&Notification{Class: NotificationFill, Title: "Filled ETHUSDT", Message: "0.5 @ 3000"})
+	now = time.Date(2024, 6, 2, 2, 30, 0, 0, time.UTC)
+	n.Notify(&Notification{Class: NotificationTrigger, Title: "Stop triggered BTCUSDT"})
+
+	n.Deliver()
+	if len(quiet.sent) != 1 {
+		t.Fatalf("notifications during quiet hours should be queued, got %d sent", len(quiet.sent))
+	}
+	if len(loud.sent) != 3 {
+		t.Errorf("channel without quiet hours should receive every notification, got %d", len(loud.sent))
+	}
+
+	now = time.Date(2024, 6, 2, 7, 0, 0, 0, time.UTC)
+	n.Deliver()
+	if len(quiet.sent) != 2 {
+		t.Fatalf("expected one digest when quiet hours end, got %d sent", len(quiet.sent))
+	}
+
+	digest := quiet.sent[1]
+	if digest.Class != NotificationDigest || digest.Title != "Quiet hours digest: 2 notification(s)" {
+		t.Errorf("unexpected digest %+v", digest)
+	}
+	want := "23:15 [FILL] Filled ETHUSDT: 0.5 @ 3000\n02:30 [TRIGGER] Stop triggered BTCUSDT"
+	if digest.Message != want {
+		t.Errorf("digest message = %q, want %q", digest.Message, want)
+	}
+
+	n.Deliver()
+	if len(quiet.sent) != 2 {
+		t.Errorf("queue should be empty after the digest, got %d sent", len(quiet.sent))
+	}
+}
+
+func TestNotifier_QueueFlushedBeforeNextNotification(t *testing.T) {
+	now := time.Date(2024, 6, 1, 23, 0, 0, 0, time.UTC)
+	n := newTestNotifier(t, nil, &now)
+
+	channel := &recordingChannel{name: "phone"}
+	n.AddChannel(channel, config.QuietHoursConfig{Start: "22:00", End: "07:00"})
+
+	n.Notify(&Notification{Class: NotificationFill, Title: "Filled BTCUSDT"})
+
+	// No tick ran since quiet hours ended; the digest must still precede the new notification
+	now = time.Date(2024, 6, 2, 8, 0, 0, 0, time.UTC)
+	n.Notify(&Notification{Class: NotificationFill, Title: "Filled ETHUSDT"})
+
+	if len(channel.sent) != 2 || channel.sent[0].Class != NotificationDigest || channel.sent[1].Title != "Filled ETHUSDT" {
+		t.Errorf("expected digest then new notification, got %+v", channel.sent)
+	}
+}
+
+func TestNotifier_CriticalBypassesQuietHours(t *testing.T) {
+	now := time.Date(2024, 6, 2, 3, 0, 0, 0, time.UTC)
+	n := newTestNotifier(t, nil, &now)
+
+	channel := &recordingChannel{name: "phone"}
+	n.AddChannel(channel, config.QuietHoursConfig{Start: "22:00", End: "07:00"})
+
+	for _, class := range []NotificationClass{NotificationLiquidationWarning, NotificationKillSwitch, NotificationUnprotectedPosition} {
+		n.Notify(&Notification{Class: class, Title: string(class)})
+	}
+	n.Notify(&Notification{Class: NotificationMaintenance, Title: "Maintenance"})
+
+	if len(channel.sent) != 3 {
+		t.Fatalf("expected the 3 critical notifications immediately, got %d", len(channel.sent))
+	}
+	for i, class := range []NotificationClass{NotificationLiquidationWarning, NotificationKillSwitch, NotificationUnprotectedPosition} {
+		if channel.sent[i].Class != class {
+			t.Errorf("sent[%d] = %s, want %s", i, channel.sent[i].Class, class)
+		}
+	}
+}
+
+func TestNotifier_DailyDigest(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	n := newTestNotifier(t, &config.NotificationsConfig{
+		DailyDigest: config.DailyDigestConfig{Enabled: true, Time: "21:00"},
+	}, &now)
+
+	channel := &recordingChannel{name: "log"}
+	n.AddChannel(channel, config.QuietHoursConfig{})
+
+	var from, to time.Time
+	n.SetDigestSource(func(periodFrom, periodTo time.Time) (*replay.Activity, error) {
+		from, to = periodFrom, periodTo
+		return &replay.Activity{
+			Fills:       3,
+			Triggers:    2,
+			RealizedPnL: 12.5,
+			Symbols: []*replay.SymbolActivity{
+				{Symbol: "BTCUSDT", Fills: 3, BoughtQty: 0.002, SoldQty: 0.001, RealizedPnL: 12.5},
+			},
+		}, nil
+	})
+	n.SetPnLSource(func() (float64, error) { return -4.25, nil })
+
+	n.Deliver()
+	if len(channel.sent) != 0 {
+		t.Fatalf("daily digest should wait for the digest time, got %d sent", len(channel.sent))
+	}
+
+	now = time.Date(2024, 6, 1, 21, 0, 30, 0, time.UTC)
+	n.Deliver()
+	n.Deliver()
+	if len(channel.sent) != 1 {
+		t.Fatalf("expected one daily digest, got %d", len(channel.sent))
+	}
+
+	digest := channel.sent[0]
+	if digest.Title != "Daily digest 2024-06-01" {
+		t.Errorf("unexpected title %q", digest.Title)
+	}
+	for _, line := range []string{
+		"Fills: 3",
+		"BTCUSDT: 3 fill(s), bought 0.00200000, sold 0.00100000, realized PnL 12.50",
+		"Triggers: 2",
+		"Realized PnL: 12.50",
+		"Unrealized PnL: -4.25",
+	} {
+		if !strings.Contains(digest.Message, line) {
+			t.Errorf("digest should contain %q, got:\n%s", line, digest.Message)
+		}
+	}
+	if !to.Equal(now) || to.Sub(from) != 24*time.Hour {
+		t.Errorf("digest should cover the last 24 hours, got %s - %s", from, to)
+	}
+
+	now = time.Date(2024, 6, 2, 21, 0, 0, 0, time.UTC)
+	n.Deliver()
+	if len(channel.sent) != 2 {
+		t.Errorf("expected the next digest a day later, got %d", len(channel.sent))
+	}
+}
+
+func TestNotifier_DailyDigestSourceError(t *testing.T) {
+	now := time.Date(2024, 6, 1, 20, 0, 0, 0, time.UTC)
+	n := newTestNotifier(t, &config.NotificationsConfig{
+		DailyDigest: config.DailyDigestConfig{Enabled: true, Time: "21:00"},
+	}, &now)
+
+	channel := &recordingChannel{name: "log"}
+	n.AddChannel(channel, config.QuietHoursConfig{})
+	n.SetDigestSource(func(from, to time.Time) (*replay.Activity, error) {
+		return nil, fmt.Errorf("journal unavailable")
+	})
+
+	now = now.Add(2 * time.Hour)
+	n.Deliver()
+	if len(channel.sent) != 0 {
+		t.Errorf("no digest should be sent when the journal cannot be read, got %+v", channel.sent)
+	}
+}
+
+func TestNewNotifier_InvalidChannel(t *testing.T) {
+	_, err := NewNotifier(&config.NotificationsConfig{
+		Channels: []config.NotificationChannelConfig{{Name: "sms", Type: "sms"}},
+	}, &mockLogger{})
+	if err == nil {
+		t.Error("expected error for unknown channel type")
+	}
+
+	_, err = NewNotifier(&config.NotificationsConfig{
+		Channels: []config.NotificationChannelConfig{{Name: "log", Type: "log", QuietHours: config.QuietHoursConfig{Start: "22:00", End: "25:00"}}},
+	}, &mockLogger{})
+	if err == nil {
+		t.Error("expected error for invalid quiet hours")
+	}
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/pkg/errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// LifecycleEventType identifies a step in an order's lifecycle
+type LifecycleEventType string
+
+const (
+	LifecycleEventCreated LifecycleEventType = "CREATED"
+	LifecycleEventFill    LifecycleEventType = "FILL"
+	LifecycleEventFinal   LifecycleEventType = "FINAL"
+)
+
+// lifecycleQtyTolerance absorbs float rounding when comparing quantities
+const lifecycleQtyTolerance = 1e-9
+
+// LifecycleEvent is one entry of an order timeline
+type LifecycleEvent struct {
+	Type            LifecycleEventType
+	Time            int64 // Milliseconds since epoch
+	Status          api.OrderStatus
+	Price           float64
+	Quantity        float64
+	CumulativeQty   float64
+	Commission      float64
+	CommissionAsset string
+	TradeID         int64
+}
+
+// OrderLifecycle correlates our record, the exchange order and its fills into one timeline
+type OrderLifecycle struct {
+	Symbol        string
+	OrderID       int64
+	Record        *api.Order // Local repository record, nil when the order is not tracked locally
+	Exchange      *api.Order
+	Fills         []*api.Trade
+	Events        []*LifecycleEvent
+	FilledQty     float64
+	QuoteQty      float64
+	AvgPrice      float64
+	Fees          map[string]float64 // Commission per asset
+	Discrepancies []string
+}
+
+// GetOrderLifecycle assembles the repository record, live exchange status and fills of an order.
+// It is read-only: nothing is written back to the repository.
+func (s *spotTradingService) GetOrderLifecycle(symbol string, orderID int64) (*OrderLifecycle, error) {
+	if symbol == "" || orderID <= 0 {
+		return nil, errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			"symbol and a positive order ID are required",
+			0,
+			nil,
+		)
+	}
+
+	lifecycle := &OrderLifecycle{
+		Symbol:  symbol,
+		OrderID: orderID,
+		Fees:    make(map[string]float64),
+	}
+
+	if record, err := s.orderRepo.FindByID(orderID); err == nil {
+		lifecycle.Record = record
+	} else {
+		lifecycle.Discrepancies = append(lifecycle.Discrepancies, "order is not tracked in the local repository")
+	}
+
+	exchangeOrder, err := s.client.GetOrder(symbol, orderID)
+	if err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation": "get_order_lifecycle",
+			"order_id":  orderID,
+			"symbol":    symbol,
+		})
+		return nil, err
+	}
+	lifecycle.Exchange = exchangeOrder
+
+	fills, err := s.client.GetMyTrades(symbol, orderID)
+	if err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation": "get_order_fills",
+			"order_id":  orderID,
+			"symbol":    symbol,
+		})
+		return nil, err
+	}
+	sort.SliceStable(fills, func(i, j int) bool {
+		if fills[i].Time != fills[j].Time {
+			return fills[i].Time < fills[j].Time
+		}
+		return fills[i].ID < fills[j].ID
+	})
+	lifecycle.Fills = fills
+
+	lifecycle.buildTimeline()
+	lifecycle.checkConsistency()
+
+	return lifecycle, nil
+}
+
+// buildTimeline creates the created → fills → final status events and fill totals
+func (l *OrderLifecycle) buildTimeline() {
+	created := l.Exchange
+	if l.Record != nil && l.Record.Time > 0 {
+		created = l.Record
+	}
+	l.Events = append(l.Events, &LifecycleEvent{
+		Type:     LifecycleEventCreated,
+		Time:     created.Time,
+		Status:   api.OrderStatusNew,
+		Price:    l.Exchange.Price,
+		Quantity: l.Exchange.OrigQty,
+	})
+
+	for _, fill := range l.Fills {
+		l.FilledQty += fill.Qty
+		l.QuoteQty += fill.QuoteQty
+		l.Fees[fill.CommissionAsset] += fill.Commission
+
+		status := api.OrderStatusPartiallyFilled
+		if l.Exchange.OrigQty > 0 && l.FilledQty >= l.Exchange.OrigQty-lifecycleQtyTolerance {
+			status = api.OrderStatusFilled
+		}
+		l.Events = append(l.Events, &LifecycleEvent{
+			Type:            LifecycleEventFill,
+			Time:            fill.Time,
+			Status:          status,
+			Price:           fill.Price,
+			Quantity:        fill.Qty,
+			CumulativeQty:   l.FilledQty,
+			Commission:      fill.Commission,
+			CommissionAsset: fill.CommissionAsset,
+			TradeID:         fill.ID,
+		})
+	}
+
+	if l.FilledQty > 0 {
+		l.AvgPrice = l.QuoteQty / l.FilledQty
+	}
+
+	if isFinalOrderStatus(l.Exchange.Status) {
+		l.Events = append(l.Events, &LifecycleEvent{
+			Type:          LifecycleEventFinal,
+			Time:          l.Exchange.UpdateTime,
+			Status:        l.Exchange.Status,
+			CumulativeQty: l.Exchange.ExecutedQty,
+		})
+	}
+}
+
+// checkConsistency records mismatches between our record, the exchange order and its fills
+func (l *OrderLifecycle) checkConsistency() {
+	if l.Record != nil {
+		if l.Record.Status != l.Exchange.Status {
+			l.Discrepancies = append(l.Discrepancies,
+				fmt.Sprintf("local status %s differs from exchange status %s", l.Record.Status, l.Exchange.Status))
+		}
+		if math.Abs(l.Record.ExecutedQty-l.Exchange.ExecutedQty) > lifecycleQtyTolerance {
+			l.Discrepancies = append(l.Discrepancies,
+				fmt.Sprintf("local executed quantity %.8f differs from exchange %.8f", l.Record.ExecutedQty, l.Exchange.ExecutedQty))
+		}
+	}
+
+	if math.Abs(l.FilledQty-l.Exchange.ExecutedQty) > lifecycleQtyTolerance {
+		l.Discrepancies = append(l.Discrepancies,
+			fmt.Sprintf("fills total %.8f but exchange reports %.8f executed", l.FilledQty, l.Exchange.ExecutedQty))
+	}
+}
+
+// isFinalOrderStatus returns whether no further fills can happen for an order in this status
+func isFinalOrderStatus(status api.OrderStatus) bool {
+	switch status {
+	case api.OrderStatusFilled, api.OrderStatusCanceled, api.OrderStatusRejected, api.OrderStatusExpired:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGetOrderLifecycle_AssemblesTimeline(t *testing.T) {
+	client := &mockBinanceClient{
+		getOrderFunc: func(symbol string, orderID int64) (*api.Order, error) {
+			return &api.Order{
+				OrderID: orderID, Symbol: symbol, Side: api.OrderSideBuy, Type: api.OrderTypeLimit,
+				Status: api.OrderStatusFilled, Price: 50000, OrigQty: 0.001, ExecutedQty: 0.001,
+				CummulativeQuoteQty: 50.006, Time: 1700000000000, UpdateTime: 1700000003000,
+			}, nil
+		},
+		getMyTradesFunc: func(symbol string, orderID int64) ([]*api.Trade, error) {
+			// Returned out of order to check the timeline is sorted
+			return []*api.Trade{
+				{ID: 502, OrderID: orderID, Price: 50010, Qty: 0.0004, QuoteQty: 20.004, Commission: 0.0000004, CommissionAsset: "BTC", Time: 1700000002000},
+				{ID: 501, OrderID: orderID, Price: 50000, Qty: 0.0006, QuoteQty: 30, Commission: 0.0000006, CommissionAsset: "BTC", Time: 1700000001000},
+			}, nil
+		},
+	}
+
+	orderRepo := repository.NewMemoryOrderRepository()
+	orderRepo.Save(&api.Order{
+		OrderID: 12345, Symbol: "BTCUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeLimit,
+		Status: api.OrderStatusNew, Price: 50000, OrigQty: 0.001, Time: 1699999999500,
+	})
+
+	svc := NewSpotTradingService(client, NewRiskManager(nil, client), orderRepo, &mockLogger{})
+	lifecycle, err := svc.GetOrderLifecycle("BTCUSDT", 12345)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []struct {
+		eventType LifecycleEventType
+		time      int64
+		status    api.OrderStatus
+		cumQty    float64
+	}{
+		{LifecycleEventCreated, 1699999999500, api.OrderStatusNew, 0},
+		{LifecycleEventFill, 1700000001000, api.OrderStatusPartiallyFilled, 0.0006},
+		{LifecycleEventFill, 1700000002000, api.OrderStatusFilled, 0.001},
+		{LifecycleEventFinal, 1700000003000, api.OrderStatusFilled, 0.001},
+	}
+	if len(lifecycle.Events) != len(expected) {
+		t.Fatalf("expected %d events, got %d", len(expected), len(lifecycle.Events))
+	}
+	for i, want := range expected {
+		event := lifecycle.Events[i]
+		if event.Type != want.eventType || event.Time != want.time || event.Status != want.status ||
+			math.Abs(event.CumulativeQty-want.cumQty) > 1e-12 {
+			t.Errorf("event %d: expected %+v, got %+v", i, want, *event)
+		}
+	}
+
+	if lifecycle.Events[1].TradeID != 501 || lifecycle.Events[1].CommissionAsset != "BTC" {
+		t.Errorf("expected first fill to be trade 501 with BTC fee, got %+v", *lifecycle.Events[1])
+	}
+	if math.Abs(lifecycle.Fees["BTC"]-0.000001) > 1e-12 {
+		t.Errorf("expected total BTC fee 0.000001, got %.10f", lifecycle.Fees["BTC"])
+	}
+	if math.Abs(lifecycle.AvgPrice-50004) > 1e-6 {
+		t.Errorf("expected average price 50004, got %.8f", lifecycle.AvgPrice)
+	}
+
+	// The local record still says NEW: reported, but not written back
+	if len(lifecycle.Discrepancies) != 2 || !strings.Contains(lifecycle.Discrepancies[0], "local status NEW differs from exchange status FILLED") {
+		t.Errorf("expected status and quantity discrepancies, got %v", lifecycle.Discrepancies)
+	}
+	if record, _ := orderRepo.FindByID(12345); record.Status != api.OrderStatusNew {
+		t.Errorf("expected trace to be read-only, local status changed to %s", record.Status)
+	}
+}
+
+func TestGetOrderLifecycle_OpenUntrackedOrder(t *testing.T) {
+	client := &mockBinanceClient{
+		getOrderFunc: func(symbol string, orderID int64) (*api.Order, error) {
+			return &api.Order{
+				OrderID: orderID, Symbol: symbol, Status: api.OrderStatusPartiallyFilled,
+				Price: 3000, OrigQty: 1, ExecutedQty: 0.25, Time: 1700000000000, UpdateTime: 1700000001000,
+			}, nil
+		},
+		getMyTradesFunc: func(symbol string, orderID int64) ([]*api.Trade, error) {
+			return []*api.Trade{
+				{ID: 7, OrderID: orderID, Price: 3000, Qty: 0.25, QuoteQty: 750, Commission: 0.75, CommissionAsset: "USDT", Time: 1700000001000},
+			}, nil
+		},
+	}
+
+	svc := NewSpotTradingService(client, NewRiskManager(nil, client), repository.NewMemoryOrderRepository(), &mockLogger{})
+	lifecycle, err := svc.GetOrderLifecycle("ETHUSDT", 99)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if lifecycle.Record != nil {
+		t.Error("expected no local record")
+	}
+	if len(lifecycle.Events) != 2 || lifecycle.Events[0].Time != 1700000000000 ||
+		lifecycle.Events[1].Status != api.OrderStatusPartiallyFilled {
+		t.Errorf("expected created and one partial fill without a final event, got %d events", len(lifecycle.Events))
+	}
+	if len(lifecycle.Discrepancies) != 1 || !strings.Contains(lifecycle.Discrepancies[0], "not tracked") {
+		t.Errorf("expected untracked discrepancy only, got %v", lifecycle.Discrepancies)
+	}
+	if lifecycle.Fees["USDT"] != 0.75 {
+		t.Errorf("expected 0.75 USDT fee, got %v", lifecycle.Fees)
+	}
+
+	if _, err := svc.GetOrderLifecycle("", 99); err == nil {
+		t.Error("expected error for empty symbol")
+	}
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/pkg/errors"
+	"math"
+	"sort"
+)
+
+// PlaceOCOOrder places a limit leg and a stop leg as one order list. A sell pair takes profit
+// above the market and stops out below it; a buy pair is the mirror image. The stop leg is a
+// market order when stopLimitPrice is 0. Placing both legs in one request means a crash can
+// never leave one leg without the other, and the exchange cancels the remaining leg as soon
+// as the other fills.
+func (s *spotTradingService) PlaceOCOOrder(symbol string, side api.OrderSide, quantity, price, stopPrice, stopLimitPrice float64) (*api.OCOResponse, error) {
+	fields := map[string]interface{}{
+		"symbol":           symbol,
+		"side":             string(side),
+		"quantity":         quantity,
+		"price":            price,
+		"stop_price":       stopPrice,
+		"stop_limit_price": stopLimitPrice,
+	}
+
+	if err := validateOCOOrder(symbol, side, price, stopPrice, stopLimitPrice); err != nil {
+		s.logger.Error("OCO order failed validation", withError(fields, err))
+		return nil, err
+	}
+
+	if err := s.checkSymbolPaused(symbol); err != nil {
+		s.logger.Warn("OCO order rejected: symbol paused", withError(fields, err))
+		return nil, err
+	}
+
+	// The risk check covers the more expensive leg, since either may fill
+	riskReq := &api.OrderRequest{
+		Symbol:   symbol,
+		Side:     side,
+		Type:     api.OrderTypeLimit,
+		Quantity: quantity,
+		Price:    math.Max(price, math.Max(stopPrice, stopLimitPrice)),
+	}
+	if err := s.riskMgr.ValidateOrder(riskReq); err != nil {
+		s.logger.Error("OCO order failed risk validation", withError(fields, err))
+		return nil, err
+	}
+	defer s.releaseNotional(riskReq)
+	if err := s.riskMgr.CheckDailyLimit(); err != nil {
+		s.logger.Error("OCO order failed daily limit check", withError(fields, err))
+		return nil, err
+	}
+	if side == api.OrderSideBuy {
+		if quoteAsset := extractQuoteAsset(symbol); quoteAsset != "" {
+			if err := s.riskMgr.CheckMinimumBalance(quoteAsset); err != nil {
+				s.logger.Error("OCO order failed minimum balance check", withError(fields, err))
+				return nil, err
+			}
+		}
+	}
+
+	s.logger.Info("Placing OCO order", fields)
+
+	response, err := s.client.CreateOCOOrder(&api.OCORequest{
+		Symbol:         symbol,
+		Side:           side,
+		Quantity:       quantity,
+		Price:          price,
+		StopPrice:      stopPrice,
+		StopLimitPrice: stopLimitPrice,
+	})
+	s.recordOrderResult(symbol, err)
+	if err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation": "place_oco_order",
+			"symbol":    symbol,
+			"side":      string(side),
+			"quantity":  quantity,
+		})
+		return nil, err
+	}
+
+	for _, leg := range response.OrderReports {
+		if leg.OrderListID <= 0 {
+			leg.OrderListID = response.OrderListID
+		}
+		if leg.Side == "" {
+			leg.Side = side
+		}
+		if leg.Time == 0 {
+			leg.Time = response.TransactionTime
+			leg.UpdateTime = response.TransactionTime
+		}
+		s.syncOrderListLeg(leg)
+	}
+
+	s.recordPlacedOrder(riskReq, riskReq.Price*quantity)
+
+	s.logger.LogOrderEvent(
+		"order_list_created",
+		response.LimitOrderID,
+		symbol,
+		string(side),
+		string(api.OrderTypeLimitMaker),
+		quantity,
+		map[string]interface{}{
+			"order_list_id":    response.OrderListID,
+			"stop_order_id":    response.StopOrderID,
+			"price":            price,
+			"stop_price":       stopPrice,
+			"stop_limit_price": stopLimitPrice,
+			"list_status":      string(response.ListStatusType),
+		},
+	)
+
+	return response, nil
+}
+
+// validateOCOOrder checks the prices of an OCO pair are positive and on the right sides of
+// each other: the limit leg above the stop for a sell, below it for a buy. A stop limit
+// price of 0 asks for a stop-market leg.
+func validateOCOOrder(symbol string, side api.OrderSide, price, stopPrice, stopLimitPrice float64) error {
+	invalid := func(message string) error {
+		return errors.NewTradingError(errors.ErrInvalidParameter, message, 0, nil)
+	}
+
+	if symbol == "" {
+		return invalid("symbol cannot be empty")
+	}
+	if price <= 0 || stopPrice <= 0 {
+		return invalid("price and stop price must be greater than 0")
+	}
+	if stopLimitPrice < 0 {
+		return invalid("stop limit price cannot be negative")
+	}
+
+	switch side {
+	case api.OrderSideSell:
+		if price <= stopPrice {
+			return invalid("sell OCO price must be above the stop price")
+		}
+	case api.OrderSideBuy:
+		if price >= stopPrice {
+			return invalid("buy OCO price must be below the stop price")
+		}
+	default:
+		return invalid("side must be BUY or SELL")
+	}
+	return nil
+}
+
+// withError returns a copy of log fields with the error added
+func withError(fields map[string]interface{}, err error) map[string]interface{} {
+	result := make(map[string]interface{}, len(fields)+1)
+	for key, value := range fields {
+		result[key] = value
+	}
+	result["error"] = err.Error()
+	return result
+}
+
+// CancelOCOOrder cancels an order list by its ID, e.g. an OCO pair placed outside this system
+func (s *spotTradingService) CancelOCOOrder(symbol string, orderListID int64) error {
+	if symbol == "" || orderListID <= 0 {
+		return errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			"symbol and an order list ID greater than 0 are required",
+			0,
+			nil,
+		)
+	}
+
+	s.logger.Info("Canceling order list", map[string]interface{}{
+		"order_list_id": orderListID,
+		"symbol":        symbol,
+	})
+
+	list, err := s.client.CancelOrderList(symbol, orderListID)
+	if err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation":     "cancel_oco_order",
+			"order_list_id": orderListID,
+			"symbol":        symbol,
+		})
+		return err
+	}
+	s.syncCanceledOrderList(list)
+
+	s.logger.Info("Order list canceled", map[string]interface{}{
+		"order_list_id": list.OrderListID,
+		"symbol":        symbol,
+		"list_status":   string(list.ListStatusType),
+		"legs":          len(list.OrderReports),
+	})
+	return nil
+}
+
+// cancelOrderList cancels the order list a leg belongs to. The exchange cancels every leg
+// together, so the local record of each leg is updated from the reported final states.
+func (s *spotTradingService) cancelOrderList(order *api.Order) error {
+	s.logger.Info("Canceling order list", map[string]interface{}{
+		"order_id":      order.OrderID,
+		"order_list_id": order.OrderListID,
+		"symbol":        order.Symbol,
+	})
+
+	list, err := s.client.CancelOrderList(order.Symbol, order.OrderListID)
+	if err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation":     "cancel_order_list",
+			"order_id":      order.OrderID,
+			"order_list_id": order.OrderListID,
+			"symbol":        order.Symbol,
+		})
+		return err
+	}
+
+	s.syncCanceledOrderList(list)
+
+	s.logger.LogOrderEvent(
+		"order_list_canceled",
+		order.OrderID,
+		order.Symbol,
+		string(order.Side),
+		string(order.Type),
+		order.OrigQty,
+		map[string]interface{}{
+			"order_list_id": list.OrderListID,
+			"list_status":   string(list.ListStatusType),
+			"legs":          len(list.OrderReports),
+		},
+	)
+
+	return nil
+}
+
+// syncCanceledOrderList stores the final state of every leg reported by a list cancellation
+func (s *spotTradingService) syncCanceledOrderList(list *api.OrderList) {
+	for _, leg := range list.OrderReports {
+		if leg.OrderListID <= 0 {
+			leg.OrderListID = list.OrderListID
+		}
+		if leg.UpdateTime == 0 {
+			leg.UpdateTime = list.TransactionTime
+		}
+		s.syncOrderListLeg(leg)
+	}
+}
+
+// reconcileOrderLists settles the local legs of order lists that no longer have a leg among
+// the open orders. Once the exchange reports the list as done, the final state of each leg
+// is fetched, so a leg cancelled because its sibling filled is not left open locally.
+func (s *spotTradingService) reconcileOrderLists(openOrders []*api.Order) {
+	localOpen, err := s.orderRepo.FindOpenOrders()
+	if err != nil {
+		s.logger.Warn("Failed to load open orders for order list reconciliation", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	stillOpen := make(map[int64]bool)
+	for _, order := range openOrders {
+		if order.InOrderList() {
+			stillOpen[order.OrderListID] = true
+		}
+	}
+
+	var listIDs []int64
+	seen := make(map[int64]bool)
+	for _, order := range localOpen {
+		if order.InOrderList() && !stillOpen[order.OrderListID] && !seen[order.OrderListID] {
+			seen[order.OrderListID] = true
+			listIDs = append(listIDs, order.OrderListID)
+		}
+	}
+	sort.Slice(listIDs, func(i, j int) bool { return listIDs[i] < listIDs[j] })
+
+	for _, listID := range listIDs {
+		list, err := s.client.GetOrderList(listID)
+		if err != nil {
+			s.logger.Warn("Failed to get order list status", map[string]interface{}{
+				"order_list_id": listID,
+				"error":         err.Error(),
+			})
+			continue
+		}
+		if !list.IsDone() {
+			continue
+		}
+
+		for _, ref := range list.Orders {
+			leg, err := s.client.GetOrder(ref.Symbol, ref.OrderID)
+			if err != nil {
+				s.logger.Warn("Failed to get order list leg", map[string]interface{}{
+					"order_list_id": listID,
+					"order_id":      ref.OrderID,
+					"error":         err.Error(),
+				})
+				continue
+			}
+			if leg.OrderListID <= 0 {
+				leg.OrderListID = listID
+			}
+			s.syncOrderListLeg(leg)
+		}
+
+		s.logger.Info("Order list settled", map[string]interface{}{
+			"order_list_id": listID,
+			"symbol":        list.Symbol,
+			"list_status":   string(list.ListStatusType),
+			"order_status":  list.ListOrderStatus,
+		})
+	}
+}
+
+// syncOrderListLeg stores the exchange state of an order list leg, saving legs not yet known
+func (s *spotTradingService) syncOrderListLeg(leg *api.Order) {
+	if _, err := s.orderRepo.FindByID(leg.OrderID); err != nil {
+		if err := s.orderRepo.Save(leg); err != nil {
+			s.logger.Warn("Failed to save order list leg", map[string]interface{}{
+				"order_id":      leg.OrderID,
+				"order_list_id": leg.OrderListID,
+				"error":         err.Error(),
+			})
+		}
+		return
+	}
+
+	if err := s.orderRepo.SyncOrderStatus(leg.OrderID, leg.Status, leg.ExecutedQty, leg.UpdateTime); err != nil {
+		s.logger.Warn("Failed to update order list leg", map[string]interface{}{
+			"order_id":      leg.OrderID,
+			"order_list_id": leg.OrderListID,
+			"error":         err.Error(),
+		})
+	}
+}
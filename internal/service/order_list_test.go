@@ -0,0 +1,251 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"testing"
+)
+
+// ocoLegs returns the two open legs of OCO list 7
+func ocoLegs() []*api.Order {
+	return []*api.Order{
+		{OrderID: 101, Symbol: "BTCUSDT", Side: api.OrderSideSell, Type: "STOP_LOSS_LIMIT", Status: api.OrderStatusNew, Price: 48000, OrigQty: 0.1, OrderListID: 7},
+		{OrderID: 102, Symbol: "BTCUSDT", Side: api.OrderSideSell, Type: "LIMIT_MAKER", Status: api.OrderStatusNew, Price: 55000, OrigQty: 0.1, OrderListID: 7},
+	}
+}
+
+func TestCancelOrder_OrderListLegCancelsList(t *testing.T) {
+	var cancelledList int64
+	mockClient := &mockBinanceClient{
+		cancelOrderFunc: func(symbol string, orderID int64) (*api.CancelResponse, error) {
+			t.Errorf("expected the list to be cancelled, not order %d", orderID)
+			return nil, nil
+		},
+		cancelOrderListFunc: func(symbol string, orderListID int64) (*api.OrderList, error) {
+			cancelledList = orderListID
+			return &api.OrderList{
+				OrderListID:     orderListID,
+				ContingencyType: "OCO",
+				ListStatusType:  api.OrderListStatusAllDone,
+				ListOrderStatus: "ALL_DONE",
+				TransactionTime: 1700000000000,
+				Symbol:          symbol,
+				OrderReports: []*api.Order{
+					{OrderID: 101, Symbol: symbol, Status: api.OrderStatusCanceled},
+					{OrderID: 102, Symbol: symbol, Status: api.OrderStatusCanceled},
+				},
+			}, nil
+		},
+	}
+
+	orderRepo := repository.NewMemoryOrderRepository()
+	for _, leg := range ocoLegs() {
+		orderRepo.Save(leg)
+	}
+	service := NewTradingService(mockClient, NewRiskManager(nil, mockClient), orderRepo, &mockLogger{})
+
+	if err := service.CancelOrder(102); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cancelledList != 7 {
+		t.Errorf("Expected order list 7 to be cancelled, got %d", cancelledList)
+	}
+
+	legs, _ := orderRepo.FindByOrderListID(7)
+	if len(legs) != 2 {
+		t.Fatalf("Expected 2 legs, got %d", len(legs))
+	}
+	for _, leg := range legs {
+		if leg.Status != api.OrderStatusCanceled {
+			t.Errorf("Expected leg %d to be CANCELED, got %s", leg.OrderID, leg.Status)
+		}
+		if leg.UpdateTime != 1700000000000 {
+			t.Errorf("Expected leg %d to take the list transaction time, got %d", leg.OrderID, leg.UpdateTime)
+		}
+	}
+}
+
+func TestGetActiveOrders_SettlesDoneOrderList(t *testing.T) {
+	// The take-profit leg filled and the exchange cancelled the stop leg with it
+	finalStates := map[int64]*api.Order{
+		101: {OrderID: 101, Symbol: "BTCUSDT", Status: api.OrderStatusCanceled, OrderListID: 7, UpdateTime: 1700000000000},
+		102: {OrderID: 102, Symbol: "BTCUSDT", Status: api.OrderStatusFilled, ExecutedQty: 0.1, OrderListID: 7, UpdateTime: 1700000000000},
+	}
+	var listQueries int
+	mockClient := &mockBinanceClient{
+		getOpenOrdersFunc: func(symbol string) ([]*api.Order, error) {
+			return []*api.Order{{OrderID: 200, Symbol: "ETHUSDT", Status: api.OrderStatusNew, OrderListID: -1}}, nil
+		},
+		getOrderListFunc: func(orderListID int64) (*api.OrderList, error) {
+			listQueries++
+			return &api.OrderList{
+				OrderListID:     orderListID,
+				ListStatusType:  api.OrderListStatusAllDone,
+				ListOrderStatus: "ALL_DONE",
+				Symbol:          "BTCUSDT",
+				Orders: []*api.OrderListOrder{
+					{Symbol: "BTCUSDT", OrderID: 101},
+					{Symbol: "BTCUSDT", OrderID: 102},
+				},
+			}, nil
+		},
+		getOrderFunc: func(symbol string, orderID int64) (*api.Order, error) {
+			order := *finalStates[orderID]
+			return &order, nil
+		},
+	}
+
+	orderRepo := repository.NewMemoryOrderRepository()
+	for _, leg := range ocoLegs() {
+		orderRepo.Save(leg)
+	}
+	service := NewTradingService(mockClient, NewRiskManager(nil, mockClient), orderRepo, &mockLogger{})
+
+	if _, err := service.GetActiveOrders(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if listQueries != 1 {
+		t.Errorf("Expected 1 order list query, got %d", listQueries)
+	}
+
+	stop, _ := orderRepo.FindByID(101)
+	takeProfit, _ := orderRepo.FindByID(102)
+	if stop.Status != api.OrderStatusCanceled || takeProfit.Status != api.OrderStatusFilled {
+		t.Errorf("Expected CANCELED and FILLED legs, got %s and %s", stop.Status, takeProfit.Status)
+	}
+
+	// Settled lists are not queried again
+	if _, err := service.GetActiveOrders(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if listQueries != 1 {
+		t.Errorf("Expected no further order list queries, got %d", listQueries)
+	}
+}
+
+func TestGetActiveOrders_KeepsExecutingOrderList(t *testing.T) {
+	mockClient := &mockBinanceClient{
+		getOpenOrdersFunc: func(symbol string) ([]*api.Order, error) {
+			// Only one leg is listed, but the list is still working on the exchange
+			return []*api.Order{ocoLegs()[0]}, nil
+		},
+		getOrderListFunc: func(orderListID int64) (*api.OrderList, error) {
+			t.Errorf("Expected no list query while a leg is open")
+			return nil, nil
+		},
+	}
+
+	orderRepo := repository.NewMemoryOrderRepository()
+	for _, leg := range ocoLegs() {
+		orderRepo.Save(leg)
+	}
+	service := NewTradingService(mockClient, NewRiskManager(nil, mockClient), orderRepo, &mockLogger{})
+
+	if _, err := service.GetActiveOrders(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if leg, _ := orderRepo.FindByID(102); leg.Status != api.OrderStatusNew {
+		t.Errorf("Expected the other leg to stay NEW, got %s", leg.Status)
+	}
+}
+
+func TestPlaceOCOOrder(t *testing.T) {
+	var placed *api.OCORequest
+	mockClient := &mockBinanceClient{
+		createOCOOrderFunc: func(order *api.OCORequest) (*api.OCOResponse, error) {
+			placed = order
+			return &api.OCOResponse{
+				OrderList: api.OrderList{
+					OrderListID:     7,
+					ContingencyType: "OCO",
+					ListStatusType:  api.OrderListStatusExecStarted,
+					ListOrderStatus: "EXECUTING",
+					TransactionTime: 1700000000000,
+					Symbol:          order.Symbol,
+					OrderReports:    ocoLegs(),
+				},
+				LimitOrderID: 102,
+				StopOrderID:  101,
+			}, nil
+		},
+		cancelOrderListFunc: func(symbol string, orderListID int64) (*api.OrderList, error) {
+			return &api.OrderList{
+				OrderListID:     orderListID,
+				ListStatusType:  api.OrderListStatusAllDone,
+				TransactionTime: 1700000005000,
+				Symbol:          symbol,
+				OrderReports: []*api.Order{
+					{OrderID: 101, Symbol: symbol, Status: api.OrderStatusCanceled},
+					{OrderID: 102, Symbol: symbol, Status: api.OrderStatusCanceled},
+				},
+			}, nil
+		},
+	}
+
+	orderRepo := repository.NewMemoryOrderRepository()
+	service := NewTradingService(mockClient, NewRiskManager(nil, mockClient), orderRepo, &mockLogger{})
+
+	response, err := service.PlaceOCOOrder("BTCUSDT", api.OrderSideSell, 0.1, 55000, 48000, 47900)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if placed.Price != 55000 || placed.StopPrice != 48000 || placed.StopLimitPrice != 47900 || placed.Quantity != 0.1 {
+		t.Errorf("Unexpected OCO request %+v", placed)
+	}
+	if response.OrderListID != 7 || response.LimitOrderID != 102 || response.StopOrderID != 101 {
+		t.Errorf("Unexpected OCO response %+v", response)
+	}
+
+	// Both legs are recorded as open orders of the list
+	legs, _ := orderRepo.FindByOrderListID(7)
+	if len(legs) != 2 {
+		t.Fatalf("Expected 2 legs saved, got %d", len(legs))
+	}
+
+	// Cancelling by list ID settles both legs
+	if err := service.CancelOCOOrder("BTCUSDT", 7); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	legs, _ = orderRepo.FindByOrderListID(7)
+	for _, leg := range legs {
+		if leg.Status != api.OrderStatusCanceled || leg.UpdateTime != 1700000005000 {
+			t.Errorf("Expected leg %d to be cancelled, got %s at %d", leg.OrderID, leg.Status, leg.UpdateTime)
+		}
+	}
+}
+
+func TestPlaceOCOOrder_Rejected(t *testing.T) {
+	tests := []struct {
+		name                             string
+		side                             api.OrderSide
+		quantity, price, stop, stopLimit float64
+	}{
+		{"sell price below stop", api.OrderSideSell, 0.1, 47000, 48000, 47900},
+		{"buy price above stop", api.OrderSideBuy, 0.1, 52000, 51000, 51100},
+		{"negative stop limit price", api.OrderSideSell, 0.1, 55000, 48000, -1},
+		{"zero price", api.OrderSideBuy, 0.1, 0, 51000, 51100},
+		{"zero quantity", api.OrderSideSell, 0, 55000, 48000, 47900},
+		{"unknown side", "HOLD", 0.1, 55000, 48000, 47900},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &mockBinanceClient{
+				createOCOOrderFunc: func(order *api.OCORequest) (*api.OCOResponse, error) {
+					t.Error("Expected no order list to be placed")
+					return nil, nil
+				},
+			}
+			service := NewTradingService(mockClient, NewRiskManager(nil, mockClient), repository.NewMemoryOrderRepository(), &mockLogger{})
+
+			if _, err := service.PlaceOCOOrder("BTCUSDT", tt.side, tt.quantity, tt.price, tt.stop, tt.stopLimit); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+
+	service := NewTradingService(&mockBinanceClient{}, NewRiskManager(nil, &mockBinanceClient{}), repository.NewMemoryOrderRepository(), &mockLogger{})
+	if err := service.CancelOCOOrder("BTCUSDT", -1); err == nil {
+		t.Error("Expected an error cancelling a standalone order list ID")
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"fmt"
+)
+
+// SetUserDataStream sets the user data stream pushing the account's order updates; from then on
+// the order repository follows fills, partial fills and cancellations without polling
+func (s *spotTradingService) SetUserDataStream(stream api.UserDataStream) error {
+	if _, err := stream.SubscribeOrderUpdates(s.recordOrderUpdate); err != nil {
+		return fmt.Errorf("failed to subscribe to order updates: %w", err)
+	}
+	s.userStream = stream
+	return nil
+}
+
+// SubscribeOrderUpdates calls handler with every order update pushed by the user data stream,
+// after the order repository has recorded it
+func (s *spotTradingService) SubscribeOrderUpdates(handler func(*api.Order)) (func(), error) {
+	if s.userStream == nil {
+		return nil, fmt.Errorf("order updates need the user data stream, which is not enabled")
+	}
+	return s.userStream.SubscribeOrderUpdates(handler)
+}
+
+// recordOrderUpdate saves a pushed order update. The fill state of a known order is updated
+// unless the repository already holds a later one; orders placed elsewhere, e.g. on the
+// website, are saved as reported.
+func (s *spotTradingService) recordOrderUpdate(update *api.Order) {
+	if s.orderRepo == nil {
+		return
+	}
+
+	order := update
+	if saved, err := s.orderRepo.FindByID(update.OrderID); err == nil {
+		if saved.UpdateTime > update.UpdateTime {
+			return
+		}
+		saved.Status = update.Status
+		saved.ExecutedQty = update.ExecutedQty
+		saved.CummulativeQuoteQty = update.CummulativeQuoteQty
+		saved.UpdateTime = update.UpdateTime
+		order = saved
+	}
+	if err := s.orderRepo.Save(order); err != nil {
+		s.logger.Warn("Failed to save pushed order update", map[string]interface{}{
+			"order_id": update.OrderID,
+			"error":    err.Error(),
+		})
+		return
+	}
+
+	s.logger.LogOrderEvent(
+		"order_update",
+		order.OrderID,
+		order.Symbol,
+		string(order.Side),
+		string(order.Type),
+		order.OrigQty,
+		map[string]interface{}{
+			"status":       string(order.Status),
+			"executed_qty": order.ExecutedQty,
+			"quote_qty":    order.CummulativeQuoteQty,
+		},
+	)
+}
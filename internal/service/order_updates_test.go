@@ -0,0 +1,111 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"testing"
+)
+
+// fakeUserDataStream hands pushed orders to its subscribers in subscription order
+type fakeUserDataStream struct {
+	handlers []func(*api.Order)
+}
+
+func (s *fakeUserDataStream) SubscribeOrderUpdates(handler func(*api.Order)) (func(), error) {
+	s.handlers = append(s.handlers, handler)
+	return func() {}, nil
+}
+
+func (s *fakeUserDataStream) Connected() bool { return true }
+func (s *fakeUserDataStream) Close() error    { return nil }
+
+// push parses an executionReport frame and passes its order to the subscribers
+func (s *fakeUserDataStream) push(t *testing.T, frame string) {
+	t.Helper()
+	order, err := api.ParseExecutionReport([]byte(frame))
+	if err != nil {
+		t.Fatalf("ParseExecutionReport() error = %v", err)
+	}
+	for _, handler := range s.handlers {
+		handler(order)
+	}
+}
+
+// orderUpdateFrame returns an executionReport frame of a 1 BTC limit buy at 50000
+func orderUpdateFrame(orderID, status, executedQty, quoteQty, transactionTime string) string {
+	return `{"e":"executionReport","E":1700000000100,"s":"BTCUSDT","c":"web_abc","S":"BUY","o":"LIMIT","f":"GTC",` +
+		`"q":"1.00000000","p":"50000.00","P":"0.00","g":-1,"C":"","x":"TRADE","X":"` + status + `","i":` + orderID + `,` +
+		`"z":"` + executedQty + `","T":` + transactionTime + `,"t":7,"I":1,"O":1700000000000,"Z":"` + quoteQty + `"}`
+}
+
+func TestSpotTradingService_OrderUpdates(t *testing.T) {
+	client := &mockBinanceClient{}
+	orderRepo := repository.NewMemoryOrderRepository()
+	orderRepo.Save(&api.Order{
+		OrderID: 12345, Symbol: "BTCUSDT", ClientOrderID: "bt_12345", Side: api.OrderSideBuy, Type: api.OrderTypeLimit,
+		Status: api.OrderStatusNew, Price: 50000, OrigQty: 1, Time: 1700000000000, UpdateTime: 1700000000000,
+	})
+	svc := NewSpotTradingService(client, NewRiskManager(nil, client), orderRepo, &mockLogger{})
+
+	if _, err := svc.SubscribeOrderUpdates(func(*api.Order) {}); err == nil {
+		t.Error("SubscribeOrderUpdates() without a user data stream expected an error")
+	}
+
+	stream := &fakeUserDataStream{}
+	if err := svc.SetUserDataStream(stream); err != nil {
+		t.Fatalf("SetUserDataStream() error = %v", err)
+	}
+	var updates []api.OrderStatus
+	if _, err := svc.SubscribeOrderUpdates(func(order *api.Order) {
+		// The repository has recorded the update, or a later one, by the time subscribers hear of it
+		saved, _ := orderRepo.FindByID(order.OrderID)
+		if saved == nil || saved.UpdateTime < order.UpdateTime {
+			t.Errorf("repository holds %+v when the %s update arrives", saved, order.Status)
+		}
+		updates = append(updates, order.Status)
+	}); err != nil {
+		t.Fatalf("SubscribeOrderUpdates() error = %v", err)
+	}
+
+	expectSaved := func(status api.OrderStatus, executedQty, quoteQty float64) {
+		t.Helper()
+		saved, err := orderRepo.FindByID(12345)
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if saved.Status != status || saved.ExecutedQty != executedQty || saved.CummulativeQuoteQty != quoteQty {
+			t.Errorf("saved order = %s, %v executed for %v; want %s, %v for %v",
+				saved.Status, saved.ExecutedQty, saved.CummulativeQuoteQty, status, executedQty, quoteQty)
+		}
+		// Only the fill state changes; what the order was placed with is kept
+		if saved.ClientOrderID != "bt_12345" || saved.Time != 1700000000000 {
+			t.Errorf("saved order lost its client ID or time: %+v", saved)
+		}
+	}
+
+	stream.push(t, orderUpdateFrame("12345", "PARTIALLY_FILLED", "0.40000000", "20000.00", "1700000001000"))
+	expectSaved(api.OrderStatusPartiallyFilled, 0.4, 20000)
+
+	stream.push(t, orderUpdateFrame("12345", "FILLED", "1.00000000", "50000.00", "1700000002000"))
+	expectSaved(api.OrderStatusFilled, 1, 50000)
+
+	// An update arriving after a later one does not roll the order back
+	stream.push(t, orderUpdateFrame("12345", "PARTIALLY_FILLED", "0.40000000", "20000.00", "1700000001000"))
+	expectSaved(api.OrderStatusFilled, 1, 50000)
+
+	// An order placed elsewhere is saved as reported
+	stream.push(t, orderUpdateFrame("67890", "CANCELED", "0.00000000", "0.00", "1700000003000"))
+	if saved, err := orderRepo.FindByID(67890); err != nil || saved.Status != api.OrderStatusCanceled || saved.ClientOrderID != "web_abc" {
+		t.Errorf("FindByID(67890) = %+v, %v; want the canceled order", saved, err)
+	}
+
+	want := []api.OrderStatus{api.OrderStatusPartiallyFilled, api.OrderStatusFilled, api.OrderStatusPartiallyFilled, api.OrderStatusCanceled}
+	if len(updates) != len(want) {
+		t.Fatalf("subscriber got %v, want %v", updates, want)
+	}
+	for i := range want {
+		if updates[i] != want[i] {
+			t.Errorf("update %d = %s, want %s", i, updates[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/pkg/errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultPaperBalanceAsset is the asset dry_run.starting_balance is given in
+	DefaultPaperBalanceAsset = "USDT"
+	// DefaultPaperBalance is the starting balance --paper uses when dry_run.starting_balance is not set
+	DefaultPaperBalance = 10000.0
+)
+
+// PaperAccount is the virtual account of a dry run started from dry_run.starting_balance. Its
+// balances start as that much USDT and change only with simulated fills.
+type PaperAccount interface {
+	// GetBalance returns the virtual balance of an asset
+	GetBalance(asset string) (*api.Balance, error)
+
+	// GetExchangeBalance returns the balance of the real exchange account, which paper orders never touch
+	GetExchangeBalance(asset string) (*api.Balance, error)
+
+	// GetPaperSummary values the virtual balances at current prices against the starting balance
+	GetPaperSummary() (*PaperSummary, error)
+}
+
+// PaperSummary is the simulated profit and loss of a paper account, in USDT
+type PaperSummary struct {
+	StartingBalance float64
+	Equity          float64 // Free and locked balances valued at current prices
+	PnL             float64
+	PnLPercent      float64
+	Balances        []*api.Balance // Sorted by asset
+}
+
+// PaperAccount returns the simulator itself when its balances are virtual
+func (s *dryRunSimulator) PaperAccount() PaperAccount {
+	if s.balances == nil {
+		return nil
+	}
+	return s
+}
+
+// GetBalance returns the virtual balance of an asset, or the exchange balance without a starting balance
+func (s *dryRunSimulator) GetBalance(asset string) (*api.Balance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.balances == nil {
+		return s.SpotClient.GetBalance(asset)
+	}
+	if balance, exists := s.balances[asset]; exists {
+		balanceCopy := *balance
+		return &balanceCopy, nil
+	}
+	return &api.Balance{Asset: asset}, nil
+}
+
+// GetAccountInfo returns the exchange account with its balances replaced by the non-zero virtual
+// ones, sorted by asset, or the exchange account as is without a starting balance
+func (s *dryRunSimulator) GetAccountInfo() (*api.AccountInfo, error) {
+	accountInfo, err := s.SpotClient.GetAccountInfo()
+	if err != nil || s.balances == nil {
+		return accountInfo, err
+	}
+
+	s.mu.Lock()
+	balances := make([]api.Balance, 0, len(s.balances))
+	for _, balance := range s.balances {
+		if balance.Free > dryRunQtyTolerance || balance.Locked > dryRunQtyTolerance {
+			balances = append(balances, *balance)
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Slice(balances, func(i, j int) bool {
+		return balances[i].Asset < balances[j].Asset
+	})
+	accountCopy := *accountInfo
+	accountCopy.Balances = balances
+	return &accountCopy, nil
+}
+
+// GetAllBalances returns the non-zero virtual balances, or the exchange ones without a starting balance
+func (s *dryRunSimulator) GetAllBalances() ([]api.Balance, error) {
+	accountInfo, err := s.GetAccountInfo()
+	if err != nil {
+		return nil, err
+	}
+	return accountInfo.Balances, nil
+}
+
+// GetExchangeBalance returns the balance of the exchange account
+func (s *dryRunSimulator) GetExchangeBalance(asset string) (*api.Balance, error) {
+	return s.SpotClient.GetBalance(asset)
+}
+
+// GetPaperSummary values every virtual balance at the current price of its USDT pair
+func (s *dryRunSimulator) GetPaperSummary() (*PaperSummary, error) {
+	s.mu.Lock()
+	if s.balances == nil {
+		s.mu.Unlock()
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "dry run has no starting balance, balances are read from the exchange", 0, nil)
+	}
+	balances := make([]*api.Balance, 0, len(s.balances))
+	for _, balance := range s.balances {
+		balanceCopy := *balance
+		balances = append(balances, &balanceCopy)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(balances, func(i, j int) bool {
+		return balances[i].Asset < balances[j].Asset
+	})
+
+	summary := &PaperSummary{StartingBalance: s.startingBalance, Balances: balances}
+	for _, balance := range balances {
+		total := balance.Free + balance.Locked
+		if total <= dryRunQtyTolerance {
+			continue
+		}
+		if balance.Asset == DefaultPaperBalanceAsset {
+			summary.Equity += total
+			continue
+		}
+		price, err := s.SpotClient.GetPrice(balance.Asset + DefaultPaperBalanceAsset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to value virtual %s: %w", balance.Asset, err)
+		}
+		summary.Equity += total * price.Price
+	}
+	summary.PnL = summary.Equity - summary.StartingBalance
+	summary.PnLPercent = summary.PnL / summary.StartingBalance * 100
+
+	return summary, nil
+}
+
+// reserveFunds checks that the virtual account can pay for an order and locks what a limit order
+// holds until it fills or is cancelled: the quote amount at its limit price for a buy, the base
+// quantity for a sell. Market orders are paid from free balances as they fill. The caller holds mu.
+func (s *dryRunSimulator) reserveFunds(req *api.OrderRequest, book *api.OrderBook) error {
+	if s.balances == nil {
+		return nil
+	}
+	baseAsset, quoteAsset, err := splitSymbol(req.Symbol)
+	if err != nil {
+		return err
+	}
+
+	asset, required := baseAsset, req.Quantity
+	if req.Side == api.OrderSideBuy {
+		asset = quoteAsset
+		if req.Type == api.OrderTypeLimit {
+			required = req.Quantity * req.Price
+		} else {
+			required = marketBuyCost(book.Asks, req.Quantity)
+		}
+	}
+
+	balance := s.paperBalance(asset)
+	if balance.Free < required-dryRunQtyTolerance {
+		return errors.NewTradingError(
+			errors.ErrInsufficientBalance,
+			fmt.Sprintf("insufficient virtual %s balance: %.8f available, %.8f required", asset, balance.Free, required),
+			0,
+			nil,
+		)
+	}
+	if req.Type == api.OrderTypeLimit {
+		balance.Free -= required
+		balance.Locked += required
+	}
+	return nil
+}
+
+// settleFill pays for a fill and credits what it bought or sold; the caller holds mu
+func (s *dryRunSimulator) settleFill(order *api.Order, price, qty float64) {
+	if s.balances == nil {
+		return
+	}
+	baseAsset, quoteAsset, err := splitSymbol(order.Symbol)
+	if err != nil {
+		return
+	}
+	base, quote := s.paperBalance(baseAsset), s.paperBalance(quoteAsset)
+
+	if order.Side == api.OrderSideBuy {
+		if order.Type == api.OrderTypeLimit {
+			// The limit price was locked; fills at a better price return the difference
+			quote.Locked -= qty * order.Price
+			quote.Free += qty * (order.Price - price)
+		} else {
+			quote.Free -= qty * price
+		}
+		base.Free += qty
+		return
+	}
+
+	if order.Type == api.OrderTypeLimit {
+		base.Locked -= qty
+	} else {
+		base.Free -= qty
+	}
+	quote.Free += qty * price
+}
+
+// releaseFunds returns what an open limit order still holds to the free balance; the caller holds mu
+func (s *dryRunSimulator) releaseFunds(order *api.Order) {
+	if s.balances == nil || order.Type != api.OrderTypeLimit {
+		return
+	}
+	baseAsset, quoteAsset, err := splitSymbol(order.Symbol)
+	if err != nil {
+		return
+	}
+
+	balance, amount := s.paperBalance(baseAsset), order.OrigQty-order.ExecutedQty
+	if order.Side == api.OrderSideBuy {
+		balance, amount = s.paperBalance(quoteAsset), amount*order.Price
+	}
+	balance.Locked -= amount
+	balance.Free += amount
+}
+
+// paperBalance returns the virtual balance of an asset, adding an empty one; the caller holds mu
+func (s *dryRunSimulator) paperBalance(asset string) *api.Balance {
+	balance, exists := s.balances[asset]
+	if !exists {
+		balance = &api.Balance{Asset: asset}
+		s.balances[asset] = balance
+	}
+	return balance
+}
+
+// marketBuyCost is the quote amount a market buy pays for what the ask levels can fill
+func marketBuyCost(asks []api.OrderBookLevel, quantity float64) float64 {
+	cost := 0.0
+	for _, level := range asks {
+		if quantity <= dryRunQtyTolerance {
+			break
+		}
+		qty := math.Min(quantity, math.Max(level.Qty, 0))
+		cost += qty * level.Price
+		quantity -= qty
+	}
+	return cost
+}
+
+// splitSymbol splits a trading pair into its base and quote asset, e.g. BTCUSDT -> BTC, USDT
+func splitSymbol(symbol string) (string, string, error) {
+	quoteAsset := extractQuoteAsset(symbol)
+	if quoteAsset == "" || quoteAsset == symbol {
+		return "", "", errors.NewTradingError(errors.ErrInvalidParameter, fmt.Sprintf("cannot determine the assets of %s", symbol), 0, nil)
+	}
+	return strings.TrimSuffix(symbol, quoteAsset), quoteAsset, nil
+}
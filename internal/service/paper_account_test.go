@@ -0,0 +1,182 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func assertPaperAccountBalance(t *testing.T, account PaperAccount, asset string, free, locked float64) {
+	t.Helper()
+	balance, err := account.GetBalance(asset)
+	if err != nil {
+		t.Fatalf("GetBalance(%s) unexpected error: %v", asset, err)
+	}
+	if math.Abs(balance.Free-free) > 1e-9 || math.Abs(balance.Locked-locked) > 1e-9 {
+		t.Fatalf("%s balance = %v free, %v locked, want %v free, %v locked", asset, balance.Free, balance.Locked, free, locked)
+	}
+}
+
+func TestDryRunSimulator_PaperBalances(t *testing.T) {
+	simulator, _ := newTestDryRunSimulator(&config.DryRunConfig{StartingBalance: 10000}, &mockBinanceClient{})
+	account := simulator.PaperAccount()
+	if account == nil {
+		t.Fatal("expected a paper account with a starting balance")
+	}
+	assertPaperAccountBalance(t, account, "USDT", 10000, 0)
+
+	// The buy locks 101 at its limit and fills at 100 and 101, returning the 0.5 saved
+	if _, err := simulator.CreateOrder(&api.OrderRequest{Symbol: "SOLUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeLimit, Quantity: 1, Price: 101}); err != nil {
+		t.Fatalf("CreateOrder() unexpected error: %v", err)
+	}
+	assertPaperAccountBalance(t, account, "USDT", 9899.5, 0)
+	assertPaperAccountBalance(t, account, "SOL", 1, 0)
+
+	// A resting buy holds its funds until cancelled
+	resting, err := simulator.CreateOrder(&api.OrderRequest{Symbol: "SOLUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeLimit, Quantity: 2, Price: 99})
+	if err != nil {
+		t.Fatalf("CreateOrder() unexpected error: %v", err)
+	}
+	assertPaperAccountBalance(t, account, "USDT", 9701.5, 198)
+	if _, err := simulator.CancelOrder("SOLUSDT", resting.OrderID); err != nil {
+		t.Fatalf("CancelOrder() unexpected error: %v", err)
+	}
+	assertPaperAccountBalance(t, account, "USDT", 9899.5, 0)
+
+	// Selling more than is held never reaches the book
+	_, err = simulator.CreateOrder(&api.OrderRequest{Symbol: "SOLUSDT", Side: api.OrderSideSell, Type: api.OrderTypeMarket, Quantity: 5})
+	if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrInsufficientBalance {
+		t.Fatalf("selling 5 of 1 SOL: expected ErrInsufficientBalance, got %v", err)
+	}
+
+	// The market sell fills at the 99.9 bid
+	if _, err := simulator.CreateOrder(&api.OrderRequest{Symbol: "SOLUSDT", Side: api.OrderSideSell, Type: api.OrderTypeMarket, Quantity: 1}); err != nil {
+		t.Fatalf("CreateOrder() unexpected error: %v", err)
+	}
+	assertPaperAccountBalance(t, account, "SOL", 0, 0)
+
+	// Account info lists the non-zero virtual balances: the sold SOL is gone
+	info, err := simulator.GetAccountInfo()
+	if err != nil {
+		t.Fatalf("GetAccountInfo() unexpected error: %v", err)
+	}
+	if len(info.Balances) != 1 || info.Balances[0].Asset != "USDT" || math.Abs(info.Balances[0].Free-9999.4) > 1e-9 {
+		t.Errorf("account balances = %+v, want only the virtual USDT", info.Balances)
+	}
+
+	summary, err := account.GetPaperSummary()
+	if err != nil {
+		t.Fatalf("GetPaperSummary() unexpected error: %v", err)
+	}
+	if math.Abs(summary.Equity-9999.4) > 1e-9 || math.Abs(summary.PnL+0.6) > 1e-9 || math.Abs(summary.PnLPercent+0.006) > 1e-9 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
+
+func TestDryRunSimulator_PaperMarketBuyNeedsFunds(t *testing.T) {
+	simulator, _ := newTestDryRunSimulator(&config.DryRunConfig{StartingBalance: 100}, &mockBinanceClient{})
+
+	// One SOL costs 100.5 across the first two ask levels
+	_, err := simulator.CreateOrder(&api.OrderRequest{Symbol: "SOLUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeMarket, Quantity: 1})
+	if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrInsufficientBalance {
+		t.Fatalf("expected ErrInsufficientBalance, got %v", err)
+	}
+	if orders, _ := simulator.GetHistoricalOrders("SOLUSDT", 0, math.MaxInt64); len(orders) != 0 {
+		t.Errorf("expected the rejected order not to be recorded, got %d", len(orders))
+	}
+	assertPaperAccountBalance(t, simulator.PaperAccount(), "USDT", 100, 0)
+}
+
+func TestDryRunSimulator_NoStartingBalanceReadsExchange(t *testing.T) {
+	simulator, _ := newTestDryRunSimulator(&config.DryRunConfig{}, &mockBinanceClient{
+		getBalanceFunc: func(asset string) (*api.Balance, error) {
+			return &api.Balance{Asset: asset, Free: 42}, nil
+		},
+	})
+
+	if simulator.PaperAccount() != nil {
+		t.Error("expected no paper account without a starting balance")
+	}
+	if balance, err := simulator.GetBalance("USDT"); err != nil || balance.Free != 42 {
+		t.Errorf("GetBalance() = %+v, %v, want the exchange balance", balance, err)
+	}
+}
+
+// TestPaperTrading_StopLossEndToEnd buys, protects and stops out a position through the real
+// trading, stop loss and monitoring services with the simulator in place of the exchange
+func TestPaperTrading_StopLossEndToEnd(t *testing.T) {
+	market := &mockStopLossMarketDataService{currentPrice: 100}
+	client := &mockBinanceClient{
+		// The book sits 0.1 either side of the market price
+		getOrderBookFunc: func(symbol string, limit int) (*api.OrderBook, error) {
+			return &api.OrderBook{
+				Symbol: symbol,
+				Bids:   []api.OrderBookLevel{{Price: market.currentPrice - 0.1, Qty: 100}},
+				Asks:   []api.OrderBookLevel{{Price: market.currentPrice + 0.1, Qty: 100}},
+			}, nil
+		},
+		getPriceFunc: func(symbol string) (*api.Price, error) {
+			return &api.Price{Symbol: symbol, Price: market.currentPrice}, nil
+		},
+		getBalanceFunc: func(asset string) (*api.Balance, error) {
+			return &api.Balance{Asset: asset, Free: 1}, nil
+		},
+		createOrderFunc: func(req *api.OrderRequest) (*api.OrderResponse, error) {
+			t.Errorf("paper order reached the exchange: %+v", req)
+			return nil, errors.NewTradingError(errors.ErrInvalidParameter, "unexpected order", 0, nil)
+		},
+	}
+	simulator, current := newTestDryRunSimulator(&config.DryRunConfig{StartingBalance: 10000}, client)
+	account := simulator.PaperAccount()
+
+	riskMgr := NewRiskManager(&RiskLimits{MaxOrderAmount: 100000, MaxDailyOrders: 100}, simulator)
+	trading := NewTradingService(simulator, riskMgr, repository.NewMemoryOrderRepository(), &mockLogger{})
+	stopOrderRepo := repository.NewMemoryStopOrderRepository()
+	triggerEngine := NewTriggerEngine()
+	stopLoss := NewStopLossService(stopOrderRepo, triggerEngine, trading, market, &mockLogger{})
+	engine := NewMonitoringEngine(repository.NewMemoryConditionalOrderRepository(), stopOrderRepo, triggerEngine, trading, market, stopLoss,
+		&mockLogger{}, &MonitoringEngineConfig{UpdateInterval: time.Second})
+	engine.now = simulator.now
+
+	// Buy 10 SOL at the 100.1 ask and protect them at 95
+	if _, err := trading.PlaceMarketBuyOrder("SOLUSDT", 10); err != nil {
+		t.Fatalf("PlaceMarketBuyOrder() unexpected error: %v", err)
+	}
+	assertPaperAccountBalance(t, account, "USDT", 8999, 0)
+	assertPaperAccountBalance(t, account, "SOL", 10, 0)
+	stop, err := stopLoss.SetStopLoss("SOLUSDT", 10, 95)
+	if err != nil {
+		t.Fatalf("SetStopLoss() unexpected error: %v", err)
+	}
+
+	engine.checkAndTriggerOrders()
+	if order, _ := stopOrderRepo.FindStopOrderByID(stop.OrderID); order.Status != repository.StopOrderStatusActive {
+		t.Fatalf("stop order triggered above its price: %s", order.Status)
+	}
+
+	// The price falls through the stop; the position is sold at the 93.9 bid of a fresh book
+	market.currentPrice = 94
+	*current = current.Add(time.Minute)
+	engine.checkAndTriggerOrders()
+
+	if order, _ := stopOrderRepo.FindStopOrderByID(stop.OrderID); order.Status != repository.StopOrderStatusTriggered {
+		t.Fatalf("stop order status = %s, want triggered", order.Status)
+	}
+	assertPaperAccountBalance(t, account, "SOL", 0, 0)
+	assertPaperAccountBalance(t, account, "USDT", 9938, 0)
+
+	summary, err := account.GetPaperSummary()
+	if err != nil {
+		t.Fatalf("GetPaperSummary() unexpected error: %v", err)
+	}
+	if math.Abs(summary.PnL+62) > 1e-9 {
+		t.Errorf("paper P&L = %v, want -62", summary.PnL)
+	}
+	if exchange, _ := account.GetExchangeBalance("USDT"); exchange.Free != 1 {
+		t.Errorf("exchange balance = %v, want it untouched", exchange.Free)
+	}
+}
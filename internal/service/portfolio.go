@@ -0,0 +1,124 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/pkg/logger"
+	"fmt"
+	"sort"
+)
+
+const (
+	// PortfolioQuoteAsset is the asset portfolio holdings are valued in
+	PortfolioQuoteAsset = "USDT"
+
+	// portfolioBridgeAsset prices assets without a USDT pair through their pair with it
+	portfolioBridgeAsset = "BTC"
+)
+
+// PortfolioService values the whole spot account at current prices
+type PortfolioService interface {
+	// GetPortfolio values every non-zero balance in USDT; assets without a USDT or BTC pair are
+	// listed unpriced and left out of the total
+	GetPortfolio() (*Portfolio, error)
+}
+
+// Portfolio is the spot account valued in PortfolioQuoteAsset
+type Portfolio struct {
+	Holdings   []*PortfolioHolding // Largest value first, unpriced holdings last
+	TotalValue float64             // Sum of the priced holdings
+	Unpriced   int                 // Holdings left out of the total
+}
+
+// PortfolioHolding is one asset of a portfolio
+type PortfolioHolding struct {
+	Asset  string
+	Free   float64
+	Locked float64
+	Priced bool
+	Price  float64 // In PortfolioQuoteAsset, 0 when unpriced
+	Value  float64 // (Free + Locked) * Price
+	Route  string  // Symbols the price comes from, e.g. "ETHUSDT" or "XYZBTC, BTCUSDT"
+}
+
+// portfolioService implements PortfolioService
+type portfolioService struct {
+	trading SpotTradingService
+	client  api.SpotClient
+	logger  logger.Logger
+}
+
+// NewPortfolioService creates a portfolio service reading balances from the trading service, so
+// paper trading values its virtual balances, and every price from one ticker request
+func NewPortfolioService(trading SpotTradingService, client api.SpotClient, log logger.Logger) PortfolioService {
+	return &portfolioService{
+		trading: trading,
+		client:  client,
+		logger:  log,
+	}
+}
+
+// GetPortfolio values the non-zero balances at the current prices of all symbols
+func (s *portfolioService) GetPortfolio() (*Portfolio, error) {
+	balances, err := s.trading.GetAllBalances()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balances: %w", err)
+	}
+	if len(balances) == 0 {
+		return &Portfolio{}, nil
+	}
+
+	tickers, err := s.client.GetAllPrices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prices: %w", err)
+	}
+	prices := make(map[string]float64, len(tickers))
+	for _, ticker := range tickers {
+		prices[ticker.Symbol] = ticker.Price
+	}
+
+	portfolio := &Portfolio{Holdings: make([]*PortfolioHolding, 0, len(balances))}
+	for _, balance := range balances {
+		holding := &PortfolioHolding{Asset: balance.Asset, Free: balance.Free, Locked: balance.Locked}
+		holding.Price, holding.Route, holding.Priced = portfolioPrice(balance.Asset, prices)
+		if holding.Priced {
+			holding.Value = (holding.Free + holding.Locked) * holding.Price
+			portfolio.TotalValue += holding.Value
+		} else {
+			portfolio.Unpriced++
+			s.logger.Debug("No price for portfolio asset", map[string]interface{}{
+				"asset": balance.Asset,
+			})
+		}
+		portfolio.Holdings = append(portfolio.Holdings, holding)
+	}
+
+	sort.SliceStable(portfolio.Holdings, func(i, j int) bool {
+		a, b := portfolio.Holdings[i], portfolio.Holdings[j]
+		if a.Priced != b.Priced {
+			return a.Priced
+		}
+		if a.Value != b.Value {
+			return a.Value > b.Value
+		}
+		return a.Asset < b.Asset
+	})
+	return portfolio, nil
+}
+
+// portfolioPrice returns the USDT price of an asset from its USDT pair, or from its BTC pair
+// and BTCUSDT; ok is false when neither is listed
+func portfolioPrice(asset string, prices map[string]float64) (price float64, route string, ok bool) {
+	if asset == PortfolioQuoteAsset {
+		return 1, "", true
+	}
+	if price, exists := prices[asset+PortfolioQuoteAsset]; exists && price > 0 {
+		return price, asset + PortfolioQuoteAsset, true
+	}
+
+	bridge := portfolioBridgeAsset + PortfolioQuoteAsset
+	bridgePrice, bridged := prices[bridge]
+	if assetPrice, exists := prices[asset+portfolioBridgeAsset]; exists && assetPrice > 0 && bridged && bridgePrice > 0 {
+		return assetPrice * bridgePrice, asset + portfolioBridgeAsset + ", " + bridge, true
+	}
+	return 0, "", false
+}
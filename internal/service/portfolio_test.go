@@ -0,0 +1,93 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// newPortfolioTestService values the given balances at the given prices; the returned counter
+// counts price requests
+func newPortfolioTestService(balances []api.Balance, prices map[string]float64) (PortfolioService, *int) {
+	priceRequests := 0
+	client := &mockBinanceClient{
+		getAccountInfoFunc: func() (*api.AccountInfo, error) {
+			return &api.AccountInfo{Balances: balances}, nil
+		},
+		getAllPricesFunc: func() ([]*api.Price, error) {
+			priceRequests++
+			tickers := make([]*api.Price, 0, len(prices))
+			for symbol, price := range prices {
+				tickers = append(tickers, &api.Price{Symbol: symbol, Price: price})
+			}
+			return tickers, nil
+		},
+		getPriceFunc: func(symbol string) (*api.Price, error) {
+			priceRequests++
+			return nil, fmt.Errorf("per-symbol price requested for %s", symbol)
+		},
+	}
+	trading := NewSpotTradingService(client, NewRiskManager(nil, client), repository.NewMemoryOrderRepository(), &mockLogger{})
+	return NewPortfolioService(trading, client, &mockLogger{}), &priceRequests
+}
+
+func TestPortfolioService_ValuesHoldings(t *testing.T) {
+	svc, priceRequests := newPortfolioTestService(
+		[]api.Balance{
+			{Asset: "USDT", Free: 500},
+			{Asset: "ETH", Free: 1, Locked: 1},
+			{Asset: "XYZ", Free: 1000},
+			{Asset: "BTC", Free: 0.2},
+			{Asset: "NOPE", Free: 3},
+		},
+		map[string]float64{"BTCUSDT": 50000, "ETHUSDT": 2500, "ETHBTC": 0.05, "XYZBTC": 0.0000002},
+	)
+
+	portfolio, err := svc.GetPortfolio()
+	if err != nil {
+		t.Fatalf("GetPortfolio() error = %v", err)
+	}
+	if *priceRequests != 1 {
+		t.Errorf("price requests = %d, want one for every symbol", *priceRequests)
+	}
+
+	want := []struct {
+		asset  string
+		priced bool
+		price  float64
+		value  float64
+		route  string
+	}{
+		{"BTC", true, 50000, 10000, "BTCUSDT"},
+		{"ETH", true, 2500, 5000, "ETHUSDT"},
+		{"USDT", true, 1, 500, ""},
+		{"XYZ", true, 0.01, 10, "XYZBTC, BTCUSDT"},
+		{"NOPE", false, 0, 0, ""},
+	}
+	if len(portfolio.Holdings) != len(want) {
+		t.Fatalf("holdings = %d, want %d", len(portfolio.Holdings), len(want))
+	}
+	for i, w := range want {
+		h := portfolio.Holdings[i]
+		if h.Asset != w.asset || h.Priced != w.priced || math.Abs(h.Price-w.price) > 1e-9 || math.Abs(h.Value-w.value) > 1e-9 || h.Route != w.route {
+			t.Errorf("holding %d = %+v, want %+v", i, h, w)
+		}
+	}
+	if math.Abs(portfolio.TotalValue-15510) > 1e-9 || portfolio.Unpriced != 1 {
+		t.Errorf("total = %v with %d unpriced, want 15510 with 1", portfolio.TotalValue, portfolio.Unpriced)
+	}
+}
+
+func TestPortfolioService_EmptyAccount(t *testing.T) {
+	svc, priceRequests := newPortfolioTestService(nil, nil)
+
+	portfolio, err := svc.GetPortfolio()
+	if err != nil || len(portfolio.Holdings) != 0 || portfolio.TotalValue != 0 {
+		t.Errorf("GetPortfolio() = %+v, %v; want an empty portfolio", portfolio, err)
+	}
+	if *priceRequests != 0 {
+		t.Errorf("price requests = %d, want none without holdings", *priceRequests)
+	}
+}
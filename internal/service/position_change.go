@@ -0,0 +1,562 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/logger"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPositionChangeCheckInterval is used when no position change check interval is configured
+const DefaultPositionChangeCheckInterval = 30 * time.Second
+
+// positionChangeEpsilon ignores relative size changes too small to be a real fill
+const positionChangeEpsilon = 1e-9
+
+// AutoScaleMode selects how local stop orders follow a change in position size
+type AutoScaleMode string
+
+const (
+	// AutoScaleOff only tracks position sizes (default)
+	AutoScaleOff AutoScaleMode = "off"
+	// AutoScaleScale resizes every stop and take profit order by the size change
+	AutoScaleScale AutoScaleMode = "scale"
+	// AutoScaleSupplement adds an order for the added size and trims the newest orders on decreases
+	AutoScaleSupplement AutoScaleMode = "supplement"
+)
+
+// ParseAutoScaleMode parses protection.auto_scale, defaulting to off when empty
+func ParseAutoScaleMode(value string) (AutoScaleMode, error) {
+	switch AutoScaleMode(strings.ToLower(strings.TrimSpace(value))) {
+	case "", AutoScaleOff:
+		return AutoScaleOff, nil
+	case AutoScaleScale:
+		return AutoScaleScale, nil
+	case AutoScaleSupplement:
+		return AutoScaleSupplement, nil
+	default:
+		return "", fmt.Errorf("invalid auto scale mode: %s", value)
+	}
+}
+
+// ResizedStopOrder is a stop order whose quantity was changed to follow its position
+type ResizedStopOrder struct {
+	OrderID     string
+	Type        repository.StopOrderType
+	PreviousQty float64
+	Quantity    float64
+}
+
+// ProtectionAdjustment describes how the stop orders of one position or holding were changed
+// after its size changed
+type ProtectionAdjustment struct {
+	Market       string
+	Symbol       string
+	PositionSide api.PositionSide // Empty for spot holdings
+	Mode         AutoScaleMode
+	PreviousQty  float64
+	Quantity     float64
+	Resized      []*ResizedStopOrder
+	Created      []*repository.StopOrder // Supplemental orders covering added size
+	Cancelled    []string                // Orders removed because the position shrank
+	Errors       []string                // Changes that could not be made
+	AdjustedAt   int64                   // Unix ms
+}
+
+// Label identifies the position in notifications, e.g. "BTCUSDT LONG" or "ETHUSDT"
+func (a *ProtectionAdjustment) Label() string {
+	if a.PositionSide == "" || a.PositionSide == api.PositionSideBoth {
+		return a.Symbol
+	}
+	return fmt.Sprintf("%s %s", a.Symbol, a.PositionSide)
+}
+
+// Summary describes the size change and every order change on one line
+func (a *ProtectionAdjustment) Summary() string {
+	parts := []string{fmt.Sprintf("%s size %g -> %g", a.Label(), a.PreviousQty, a.Quantity)}
+	for _, resized := range a.Resized {
+		parts = append(parts, fmt.Sprintf("resized %s %g -> %g", resized.OrderID, resized.PreviousQty, resized.Quantity))
+	}
+	for _, order := range a.Created {
+		parts = append(parts, fmt.Sprintf("added %s %g @ %g", order.OrderID, order.Position, order.StopPrice))
+	}
+	for _, orderID := range a.Cancelled {
+		parts = append(parts, fmt.Sprintf("cancelled %s", orderID))
+	}
+	for _, message := range a.Errors {
+		parts = append(parts, "failed: "+message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Changed reports whether any order was resized, created or cancelled
+func (a *ProtectionAdjustment) Changed() bool {
+	return len(a.Resized) > 0 || len(a.Created) > 0 || len(a.Cancelled) > 0
+}
+
+// PositionChangeWatcher keeps local stop and take profit orders in proportion to their position
+// when it is added to or reduced outside of those orders
+type PositionChangeWatcher interface {
+	// CheckPositions compares position sizes with the previous check and adjusts the stop orders
+	// of every position whose size changed; the first check only records the sizes
+	CheckPositions() ([]*ProtectionAdjustment, error)
+
+	// OnAdjustment registers a callback invoked for every position whose stop orders were changed
+	OnAdjustment(callback func(adjustment *ProtectionAdjustment))
+
+	// Scheduled check
+	StartMonitoring(checkInterval time.Duration) error
+	StopMonitoring() error
+}
+
+// positionChangeWatcher implements PositionChangeWatcher for one market
+type positionChangeWatcher struct {
+	market        string
+	mode          AutoScaleMode
+	collect       func() ([]*ExposureCoverage, error)
+	localOrders   func(exposure *ExposureCoverage) ([]*repository.StopOrder, error)
+	place         func(exposure *ExposureCoverage, orderType repository.StopOrderType, quantity, price float64) (*repository.StopOrder, error)
+	cancel        func(orderID, symbol string) error
+	stopOrderRepo repository.StopOrderRepository
+	logger        logger.Logger
+	now           func() time.Time
+
+	mu         sync.Mutex
+	quantities map[string]float64 // Size of every open exposure at the previous check, by label
+	callbacks  []func(adjustment *ProtectionAdjustment)
+
+	// Monitoring
+	stopChan     chan struct{}
+	monitoringMu sync.Mutex
+	isMonitoring bool
+}
+
+// NewSpotPositionChangeWatcher creates a watcher for the spot holdings listed in risk.coverage.spot_symbols
+func NewSpotPositionChangeWatcher(
+	client api.SpotClient,
+	stopLossSvc StopLossService,
+	stopOrderRepo repository.StopOrderRepository,
+	coverageCfg *config.ProtectionCoverageConfig,
+	cfg *config.ProtectionConfig,
+	log logger.Logger,
+) (PositionChangeWatcher, error) {
+	watcher, err := newPositionChangeWatcher(CoverageMarketSpot, stopOrderRepo, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	var symbols []string
+	if coverageCfg != nil {
+		symbols = coverageCfg.SpotSymbols
+	}
+
+	watcher.collect = func() ([]*ExposureCoverage, error) {
+		exposures := make([]*ExposureCoverage, 0, len(symbols))
+		for _, symbol := range symbols {
+			symbol = strings.ToUpper(symbol)
+			quote := extractQuoteAsset(symbol)
+			if quote == "" {
+				return nil, fmt.Errorf("cannot determine base asset of %s", symbol)
+			}
+
+			balance, err := client.GetBalance(strings.TrimSuffix(symbol, quote))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get balance for %s: %w", symbol, err)
+			}
+			exposures = append(exposures, &ExposureCoverage{
+				Market:   CoverageMarketSpot,
+				Symbol:   symbol,
+				Long:     true,
+				Quantity: balance.Free + balance.Locked,
+			})
+		}
+		return exposures, nil
+	}
+	watcher.localOrders = func(exposure *ExposureCoverage) ([]*repository.StopOrder, error) {
+		return stopLossSvc.GetActiveStopOrders(exposure.Symbol)
+	}
+	watcher.place = func(exposure *ExposureCoverage, orderType repository.StopOrderType, quantity, price float64) (*repository.StopOrder, error) {
+		if orderType == repository.StopOrderTypeTakeProfit {
+			return stopLossSvc.SetTakeProfit(exposure.Symbol, quantity, price)
+		}
+		return stopLossSvc.SetStopLoss(exposure.Symbol, quantity, price)
+	}
+	watcher.cancel = func(orderID, symbol string) error {
+		_, err := stopLossSvc.CancelStopOrder(orderID, symbol)
+		return err
+	}
+
+	return watcher, nil
+}
+
+// NewFuturesPositionChangeWatcher creates a watcher for open futures positions
+func NewFuturesPositionChangeWatcher(
+	positionMgr FuturesPositionManager,
+	stopLossSvc FuturesStopLossService,
+	marketService FuturesMarketDataService,
+	stopOrderRepo repository.StopOrderRepository,
+	cfg *config.ProtectionConfig,
+	log logger.Logger,
+) (PositionChangeWatcher, error) {
+	watcher, err := newPositionChangeWatcher(CoverageMarketFutures, stopOrderRepo, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher.collect = func() ([]*ExposureCoverage, error) {
+		positions, err := positionMgr.GetAllPositions()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get positions: %w", err)
+		}
+
+		exposures := make([]*ExposureCoverage, 0, len(positions))
+		for _, position := range positions {
+			if position.PositionAmt == 0 {
+				continue
+			}
+			exposures = append(exposures, &ExposureCoverage{
+				Market:       CoverageMarketFutures,
+				Symbol:       position.Symbol,
+				PositionSide: position.PositionSide,
+				Long: position.PositionSide == api.PositionSideLong ||
+					(position.PositionSide != api.PositionSideShort && position.PositionAmt > 0),
+				Quantity: math.Abs(position.PositionAmt),
+			})
+		}
+		return exposures, nil
+	}
+	// Local futures stops carry no position side; the side is inferred from the mark price
+	// as in the coverage check
+	watcher.localOrders = func(exposure *ExposureCoverage) ([]*repository.StopOrder, error) {
+		orders, err := stopLossSvc.GetActiveStopOrders(exposure.Symbol)
+		if err != nil || len(orders) == 0 {
+			return nil, err
+		}
+		markPrice, err := marketService.GetMarkPrice(exposure.Symbol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get mark price for %s: %w", exposure.Symbol, err)
+		}
+
+		var matching []*repository.StopOrder
+		for _, order := range orders {
+			if protectsLong(order, markPrice) == exposure.Long {
+				matching = append(matching, order)
+			}
+		}
+		return matching, nil
+	}
+	watcher.place = func(exposure *ExposureCoverage, orderType repository.StopOrderType, quantity, price float64) (*repository.StopOrder, error) {
+		positionSide := api.PositionSideShort
+		if exposure.Long {
+			positionSide = api.PositionSideLong
+		}
+		if orderType == repository.StopOrderTypeTakeProfit {
+			return stopLossSvc.SetTakeProfit(exposure.Symbol, positionSide, quantity, price)
+		}
+		return stopLossSvc.SetStopLoss(exposure.Symbol, positionSide, quantity, price)
+	}
+	watcher.cancel = func(orderID, symbol string) error {
+		_, err := stopLossSvc.CancelStopOrder(orderID, symbol)
+		return err
+	}
+
+	return watcher, nil
+}
+
+// newPositionChangeWatcher creates the shared watcher state
+func newPositionChangeWatcher(market string, stopOrderRepo repository.StopOrderRepository, cfg *config.ProtectionConfig, log logger.Logger) (*positionChangeWatcher, error) {
+	mode := AutoScaleOff
+	if cfg != nil {
+		parsed, err := ParseAutoScaleMode(cfg.AutoScale)
+		if err != nil {
+			return nil, err
+		}
+		mode = parsed
+	}
+
+	return &positionChangeWatcher{
+		market:        market,
+		mode:          mode,
+		stopOrderRepo: stopOrderRepo,
+		logger:        log,
+		now:           time.Now,
+		quantities:    make(map[string]float64),
+	}, nil
+}
+
+// CheckPositions compares position sizes with the previous check and adjusts stop orders
+func (w *positionChangeWatcher) CheckPositions() ([]*ProtectionAdjustment, error) {
+	exposures, err := w.collect()
+	if err != nil {
+		w.logger.Error("Failed to check position changes", map[string]interface{}{
+			"market": w.market,
+			"error":  err.Error(),
+		})
+		return nil, err
+	}
+
+	w.mu.Lock()
+	previous := w.quantities
+	current := make(map[string]float64, len(exposures))
+	for _, exposure := range exposures {
+		if exposure.Quantity > 0 {
+			current[exposure.Label()] = exposure.Quantity
+		}
+	}
+	w.quantities = current
+	callbacks := make([]func(adjustment *ProtectionAdjustment), len(w.callbacks))
+	copy(callbacks, w.callbacks)
+	w.mu.Unlock()
+
+	var adjustments []*ProtectionAdjustment
+	for _, exposure := range exposures {
+		// New and closed positions have no earlier size to keep the stops in proportion to
+		previousQty := previous[exposure.Label()]
+		if previousQty <= 0 || exposure.Quantity <= 0 {
+			continue
+		}
+		if math.Abs(exposure.Quantity-previousQty) <= previousQty*positionChangeEpsilon {
+			continue
+		}
+
+		adjustment := w.adjust(exposure, previousQty)
+		if adjustment == nil {
+			continue
+		}
+		adjustments = append(adjustments, adjustment)
+
+		w.logger.Info("Adjusted stop orders after position size change", map[string]interface{}{
+			"market":  w.market,
+			"mode":    string(w.mode),
+			"summary": adjustment.Summary(),
+		})
+		for _, callback := range callbacks {
+			callback(adjustment)
+		}
+	}
+
+	return adjustments, nil
+}
+
+// adjust brings the stop and take profit orders of an exposure in line with its new size;
+// it returns nil when nothing was attempted
+func (w *positionChangeWatcher) adjust(exposure *ExposureCoverage, previousQty float64) *ProtectionAdjustment {
+	if w.mode == AutoScaleOff {
+		return nil
+	}
+
+	orders, err := w.localOrders(exposure)
+	if err != nil {
+		w.logger.Warn("Failed to get stop orders for position change", map[string]interface{}{
+			"market": w.market,
+			"symbol": exposure.Symbol,
+			"error":  err.Error(),
+		})
+		return nil
+	}
+	if len(orders) == 0 {
+		return nil
+	}
+
+	adjustment := &ProtectionAdjustment{
+		Market:       w.market,
+		Symbol:       exposure.Symbol,
+		PositionSide: exposure.PositionSide,
+		Mode:         w.mode,
+		PreviousQty:  previousQty,
+		Quantity:     exposure.Quantity,
+		AdjustedAt:   w.now().UnixMilli(),
+	}
+
+	// Stops and take profits each cover the position independently
+	for _, orderType := range []repository.StopOrderType{repository.StopOrderTypeStopLoss, repository.StopOrderTypeTakeProfit} {
+		var group []*repository.StopOrder
+		covered := 0.0
+		for _, order := range orders {
+			if order.Type == orderType {
+				group = append(group, order)
+				covered += order.Position
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+
+		// Increases keep the covered share of the position. Decreases only shrink what exceeds
+		// the new size, since a decrease may come from one of these orders having triggered.
+		target := math.Min(covered*exposure.Quantity/previousQty, exposure.Quantity)
+		if exposure.Quantity < previousQty {
+			target = math.Min(covered, exposure.Quantity)
+		}
+		if math.Abs(target-covered) <= covered*positionChangeEpsilon {
+			continue
+		}
+		if w.mode == AutoScaleScale || target < covered {
+			if w.mode == AutoScaleScale {
+				w.scaleOrders(adjustment, group, target/covered)
+			} else {
+				w.trimOrders(adjustment, group, covered-target)
+			}
+			continue
+		}
+
+		price := nearestStopPrice(group, exposure.Long)
+		order, err := w.place(exposure, orderType, target-covered, price)
+		if err != nil {
+			adjustment.Errors = append(adjustment.Errors, fmt.Sprintf("add %g @ %g: %v", target-covered, price, err))
+			continue
+		}
+		adjustment.Created = append(adjustment.Created, order)
+	}
+
+	if !adjustment.Changed() && len(adjustment.Errors) == 0 {
+		return nil
+	}
+	return adjustment
+}
+
+// scaleOrders multiplies the quantity of every order by factor
+func (w *positionChangeWatcher) scaleOrders(adjustment *ProtectionAdjustment, orders []*repository.StopOrder, factor float64) {
+	for _, order := range orders {
+		w.resize(adjustment, order, order.Position*factor)
+	}
+}
+
+// trimOrders removes excess quantity from the newest orders first, cancelling orders that are
+// entirely in excess
+func (w *positionChangeWatcher) trimOrders(adjustment *ProtectionAdjustment, orders []*repository.StopOrder, excess float64) {
+	sort.Slice(orders, func(i, j int) bool {
+		if orders[i].CreatedAt != orders[j].CreatedAt {
+			return orders[i].CreatedAt > orders[j].CreatedAt
+		}
+		return orders[i].OrderID > orders[j].OrderID
+	})
+
+	for _, order := range orders {
+		if excess <= order.Position*positionChangeEpsilon {
+			return
+		}
+		if order.Position <= excess*(1+positionChangeEpsilon) {
+			if err := w.cancel(order.OrderID, order.Symbol); err != nil {
+				adjustment.Errors = append(adjustment.Errors, fmt.Sprintf("cancel %s: %v", order.OrderID, err))
+				continue
+			}
+			adjustment.Cancelled = append(adjustment.Cancelled, order.OrderID)
+			excess -= order.Position
+			continue
+		}
+		w.resize(adjustment, order, order.Position-excess)
+		return
+	}
+}
+
+// resize stores a new quantity for a local stop order
+func (w *positionChangeWatcher) resize(adjustment *ProtectionAdjustment, order *repository.StopOrder, quantity float64) {
+	resized := *order
+	resized.Position = quantity
+	if err := w.stopOrderRepo.UpdateStopOrder(&resized); err != nil {
+		adjustment.Errors = append(adjustment.Errors, fmt.Sprintf("resize %s: %v", order.OrderID, err))
+		return
+	}
+	adjustment.Resized = append(adjustment.Resized, &ResizedStopOrder{
+		OrderID:     order.OrderID,
+		Type:        order.Type,
+		PreviousQty: order.Position,
+		Quantity:    quantity,
+	})
+}
+
+// nearestStopPrice returns the order price closest to a position's current price: the highest
+// stop or lowest target of a long, the lowest stop or highest target of a short
+func nearestStopPrice(orders []*repository.StopOrder, long bool) float64 {
+	highest := (orders[0].Type == repository.StopOrderTypeStopLoss) == long
+	price := orders[0].StopPrice
+	for _, order := range orders[1:] {
+		if (highest && order.StopPrice > price) || (!highest && order.StopPrice < price) {
+			price = order.StopPrice
+		}
+	}
+	return price
+}
+
+// OnAdjustment registers a callback invoked for every position whose stop orders were changed
+func (w *positionChangeWatcher) OnAdjustment(callback func(adjustment *ProtectionAdjustment)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callbacks = append(w.callbacks, callback)
+}
+
+// StartMonitoring starts the scheduled position change check
+func (w *positionChangeWatcher) StartMonitoring(checkInterval time.Duration) error {
+	w.monitoringMu.Lock()
+	defer w.monitoringMu.Unlock()
+
+	if w.isMonitoring {
+		return fmt.Errorf("monitoring is already running")
+	}
+
+	if checkInterval <= 0 {
+		checkInterval = DefaultPositionChangeCheckInterval
+	}
+
+	w.stopChan = make(chan struct{})
+	w.isMonitoring = true
+
+	// Record the starting sizes right away so the first interval already detects changes
+	if _, err := w.CheckPositions(); err != nil {
+		w.logger.Warn("Initial position size check failed", map[string]interface{}{
+			"market": w.market,
+			"error":  err.Error(),
+		})
+	}
+
+	go w.monitoringLoop(checkInterval)
+
+	w.logger.Info("Started position change monitoring", map[string]interface{}{
+		"market":         w.market,
+		"mode":           string(w.mode),
+		"check_interval": checkInterval.String(),
+	})
+
+	return nil
+}
+
+// StopMonitoring stops the scheduled position change check
+func (w *positionChangeWatcher) StopMonitoring() error {
+	w.monitoringMu.Lock()
+	defer w.monitoringMu.Unlock()
+
+	if !w.isMonitoring {
+		return fmt.Errorf("monitoring is not running")
+	}
+
+	close(w.stopChan)
+	w.isMonitoring = false
+
+	w.logger.Info("Stopped position change monitoring", map[string]interface{}{
+		"market": w.market,
+	})
+
+	return nil
+}
+
+// monitoringLoop runs the position change check on every tick
+func (w *positionChangeWatcher) monitoringLoop(checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stopChan:
+			return
+		case <-ticker.C:
+			w.CheckPositions()
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/internal/repository"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// positionChangeFixture drives a watcher over one long position with a memory stop order repository
+type positionChangeFixture struct {
+	watcher  *positionChangeWatcher
+	repo     repository.StopOrderRepository
+	quantity float64
+	created  int
+}
+
+func newPositionChangeFixture(t *testing.T, mode string, quantity float64, orders ...*repository.StopOrder) *positionChangeFixture {
+	t.Helper()
+
+	repo := repository.NewMemoryStopOrderRepository()
+	for _, order := range orders {
+		order.Symbol = "BTCUSDT"
+		order.Status = repository.StopOrderStatusActive
+		if err := repo.SaveStopOrder(order); err != nil {
+			t.Fatalf("failed to save order: %v", err)
+		}
+	}
+
+	watcher, err := newPositionChangeWatcher(CoverageMarketFutures, repo, &config.ProtectionConfig{AutoScale: mode}, &mockLogger{})
+	if err != nil {
+		t.Fatalf("newPositionChangeWatcher() error = %v", err)
+	}
+
+	f := &positionChangeFixture{watcher: watcher, repo: repo, quantity: quantity}
+	watcher.collect = func() ([]*ExposureCoverage, error) {
+		return []*ExposureCoverage{{
+			Market:       CoverageMarketFutures,
+			Symbol:       "BTCUSDT",
+			PositionSide: api.PositionSideLong,
+			Long:         true,
+			Quantity:     f.quantity,
+		}}, nil
+	}
+	watcher.localOrders = func(exposure *ExposureCoverage) ([]*repository.StopOrder, error) {
+		return repo.FindActiveStopOrders(exposure.Symbol)
+	}
+	watcher.place = func(exposure *ExposureCoverage, orderType repository.StopOrderType, quantity, price float64) (*repository.StopOrder, error) {
+		f.created++
+		order := &repository.StopOrder{
+			OrderID:   fmt.Sprintf("SUP_%d", f.created),
+			Symbol:    exposure.Symbol,
+			Position:  quantity,
+			StopPrice: price,
+			Type:      orderType,
+			Status:    repository.StopOrderStatusActive,
+			CreatedAt: int64(1_700_000_000_000 + f.created),
+		}
+		return order, repo.SaveStopOrder(order)
+	}
+	watcher.cancel = func(orderID, symbol string) error {
+		return repo.UpdateStopOrderStatus(orderID, repository.StopOrderStatusCancelled, 0, 0)
+	}
+
+	// The first check records the starting size
+	if adjustments, err := watcher.CheckPositions(); err != nil || len(adjustments) != 0 {
+		t.Fatalf("first CheckPositions() = %v, %v; want no adjustments", adjustments, err)
+	}
+	return f
+}
+
+// resizeTo changes the position size and runs a check
+func (f *positionChangeFixture) resizeTo(t *testing.T, quantity float64) *ProtectionAdjustment {
+	t.Helper()
+	f.quantity = quantity
+	adjustments, err := f.watcher.CheckPositions()
+	if err != nil {
+		t.Fatalf("CheckPositions() error = %v", err)
+	}
+	if len(adjustments) != 1 {
+		t.Fatalf("CheckPositions() returned %d adjustments, want 1", len(adjustments))
+	}
+	return adjustments[0]
+}
+
+// activeQuantities returns the quantity of every active order by ID
+func (f *positionChangeFixture) activeQuantities(t *testing.T) map[string]float64 {
+	t.Helper()
+	orders, err := f.repo.FindActiveStopOrders("BTCUSDT")
+	if err != nil {
+		t.Fatalf("FindActiveStopOrders() error = %v", err)
+	}
+	quantities := make(map[string]float64, len(orders))
+	for _, order := range orders {
+		quantities[order.OrderID] = order.Position
+	}
+	return quantities
+}
+
+func assertQuantities(t *testing.T, got, want map[string]float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("active orders = %v, want %v", got, want)
+	}
+	for orderID, quantity := range want {
+		if math.Abs(got[orderID]-quantity) > 1e-9 {
+			t.Errorf("order %s quantity = %v, want %v (all: %v)", orderID, got[orderID], quantity, got)
+		}
+	}
+}
+
+func TestPositionChangeWatcherScale(t *testing.T) {
+	orders := func() []*repository.StopOrder {
+		return []*repository.StopOrder{
+			{OrderID: "SL_1", Position: 1, StopPrice: 95, Type: repository.StopOrderTypeStopLoss, CreatedAt: 1},
+			{OrderID: "TP_1", Position: 0.5, StopPrice: 110, Type: repository.StopOrderTypeTakeProfit, CreatedAt: 2},
+		}
+	}
+
+	t.Run("increase scales every order", func(t *testing.T) {
+		f := newPositionChangeFixture(t, "scale", 1, orders()...)
+
+		adjustment := f.resizeTo(t, 1.5)
+		if len(adjustment.Resized) != 2 || len(adjustment.Created) != 0 {
+			t.Errorf("adjustment = %s, want two resized orders", adjustment.Summary())
+		}
+		assertQuantities(t, f.activeQuantities(t), map[string]float64{"SL_1": 1.5, "TP_1": 0.75})
+	})
+
+	t.Run("decrease shrinks orders larger than the position", func(t *testing.T) {
+		f := newPositionChangeFixture(t, "scale", 1, orders()...)
+
+		adjustment := f.resizeTo(t, 0.4)
+		if len(adjustment.Resized) != 2 {
+			t.Errorf("adjustment = %s, want two resized orders", adjustment.Summary())
+		}
+		assertQuantities(t, f.activeQuantities(t), map[string]float64{"SL_1": 0.4, "TP_1": 0.4})
+	})
+}
+
+func TestPositionChangeWatcherSupplement(t *testing.T) {
+	t.Run("increase adds an order at the nearest stop", func(t *testing.T) {
+		f := newPositionChangeFixture(t, "supplement", 1,
+			&repository.StopOrder{OrderID: "SL_1", Position: 0.6, StopPrice: 90, Type: repository.StopOrderTypeStopLoss, CreatedAt: 1},
+			&repository.StopOrder{OrderID: "SL_2", Position: 0.4, StopPrice: 95, Type: repository.StopOrderTypeStopLoss, CreatedAt: 2},
+		)
+
+		adjustment := f.resizeTo(t, 2)
+		if len(adjustment.Created) != 1 || len(adjustment.Resized) != 0 {
+			t.Fatalf("adjustment = %s, want one supplemental order", adjustment.Summary())
+		}
+		if created := adjustment.Created[0]; created.StopPrice != 95 || created.Type != repository.StopOrderTypeStopLoss {
+			t.Errorf("supplemental order = %+v, want a stop loss at 95", created)
+		}
+		assertQuantities(t, f.activeQuantities(t), map[string]float64{"SL_1": 0.6, "SL_2": 0.4, "SUP_1": 1})
+	})
+
+	t.Run("decrease trims the newest orders first", func(t *testing.T) {
+		f := newPositionChangeFixture(t, "supplement", 2,
+			&repository.StopOrder{OrderID: "SL_1", Position: 1, StopPrice: 90, Type: repository.StopOrderTypeStopLoss, CreatedAt: 1},
+			&repository.StopOrder{OrderID: "SL_2", Position: 0.5, StopPrice: 95, Type: repository.StopOrderTypeStopLoss, CreatedAt: 2},
+			&repository.StopOrder{OrderID: "SL_3", Position: 0.5, StopPrice: 95, Type: repository.StopOrderTypeStopLoss, CreatedAt: 3},
+		)
+
+		adjustment := f.resizeTo(t, 0.8)
+		if len(adjustment.Cancelled) != 2 || len(adjustment.Resized) != 1 {
+			t.Fatalf("adjustment = %s, want two cancelled and one resized order", adjustment.Summary())
+		}
+		assertQuantities(t, f.activeQuantities(t), map[string]float64{"SL_1": 0.8})
+	})
+
+	t.Run("decrease within the remaining protection changes nothing", func(t *testing.T) {
+		// A partially triggered stop reduces the position and leaves the rest correctly sized
+		f := newPositionChangeFixture(t, "supplement", 1,
+			&repository.StopOrder{OrderID: "SL_2", Position: 0.5, StopPrice: 80, Type: repository.StopOrderTypeStopLoss, CreatedAt: 2},
+		)
+
+		f.quantity = 0.5
+		adjustments, err := f.watcher.CheckPositions()
+		if err != nil || len(adjustments) != 0 {
+			t.Fatalf("CheckPositions() = %v, %v; want no adjustments", adjustments, err)
+		}
+		assertQuantities(t, f.activeQuantities(t), map[string]float64{"SL_2": 0.5})
+	})
+}
+
+func TestPositionChangeWatcherOffAndNotifications(t *testing.T) {
+	order := &repository.StopOrder{OrderID: "SL_1", Position: 1, StopPrice: 95, Type: repository.StopOrderTypeStopLoss}
+
+	off := newPositionChangeFixture(t, "off", 1, order)
+	off.quantity = 2
+	if adjustments, err := off.watcher.CheckPositions(); err != nil || len(adjustments) != 0 {
+		t.Fatalf("off mode CheckPositions() = %v, %v; want no adjustments", adjustments, err)
+	}
+	assertQuantities(t, off.activeQuantities(t), map[string]float64{"SL_1": 1})
+
+	var notified []*ProtectionAdjustment
+	f := newPositionChangeFixture(t, "scale", 1, &repository.StopOrder{OrderID: "SL_1", Position: 1, StopPrice: 95, Type: repository.StopOrderTypeStopLoss})
+	f.watcher.OnAdjustment(func(adjustment *ProtectionAdjustment) {
+		notified = append(notified, adjustment)
+	})
+	f.resizeTo(t, 3)
+	if len(notified) != 1 || notified[0].Summary() != "BTCUSDT LONG size 1 -> 3; resized SL_1 1 -> 3" {
+		t.Errorf("notifications = %v", notified)
+	}
+
+	if _, err := ParseAutoScaleMode("double"); err == nil {
+		t.Error("expected error for an unknown auto scale mode")
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"binance-trader/internal/config"
+	"binance-trader/pkg/logger"
+	"math"
+	"sync"
+)
+
+// PriceSanityChecker filters bad ticks before protective triggers act on a price
+type PriceSanityChecker interface {
+	// Observe records a price reading and returns whether it is confirmed
+	Observe(symbol string, price float64) bool
+	// GetSuppressedCounts returns the number of suppressed readings per symbol
+	GetSuppressedCounts() map[string]int
+	// Reset forgets the prior readings so each symbol's next reading becomes its new baseline
+	Reset()
+}
+
+// symbolPriceState tracks the last confirmed and the pending suspicious reading of a symbol
+type symbolPriceState struct {
+	confirmed float64
+	pending   float64
+}
+
+// priceSanityChecker implements PriceSanityChecker. A reading that moves more than the
+// symbol's threshold from the last confirmed price is held back until a second consecutive
+// reading agrees with it within the same threshold.
+type priceSanityChecker struct {
+	defaultPercent float64
+	symbolPercent  map[string]float64
+	states         map[string]*symbolPriceState
+	suppressed     map[string]int
+	logger         logger.Logger
+	mu             sync.Mutex
+}
+
+// NewPriceSanityChecker creates a new price sanity checker
+func NewPriceSanityChecker(cfg *config.PriceSanityConfig, log logger.Logger) PriceSanityChecker {
+	checker := &priceSanityChecker{
+		symbolPercent: make(map[string]float64),
+		states:        make(map[string]*symbolPriceState),
+		suppressed:    make(map[string]int),
+		logger:        log,
+	}
+
+	if cfg != nil {
+		checker.defaultPercent = cfg.MaxDeviationPercent
+		for symbol, percent := range cfg.SymbolDeviationPercent {
+			checker.symbolPercent[symbol] = percent
+		}
+	}
+
+	return checker
+}
+
+// Observe records a price reading and returns whether it is confirmed
+func (c *priceSanityChecker) Observe(symbol string, price float64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// A zero or negative price is never valid
+	if price <= 0 {
+		c.suppress(symbol, price, 0, "non-positive price")
+		return false
+	}
+
+	threshold := c.thresholdFor(symbol)
+	state, exists := c.states[symbol]
+	if !exists || threshold <= 0 {
+		c.states[symbol] = &symbolPriceState{confirmed: price}
+		return true
+	}
+
+	deviation := deviationPercent(state.confirmed, price)
+	if deviation <= threshold {
+		state.confirmed = price
+		state.pending = 0
+		return true
+	}
+
+	// A second consecutive reading close to the pending one confirms the move
+	if state.pending > 0 && deviationPercent(state.pending, price) <= threshold {
+		c.logger.Info("Large price move confirmed", map[string]interface{}{
+			"symbol":         symbol,
+			"previous_price": state.confirmed,
+			"price":          price,
+			"deviation_pct":  deviation,
+		})
+		state.confirmed = price
+		state.pending = 0
+		return true
+	}
+
+	state.pending = price
+	c.suppress(symbol, price, deviation, "deviation above threshold")
+	return false
+}
+
+// GetSuppressedCounts returns the number of suppressed readings per symbol
+func (c *priceSanityChecker) GetSuppressedCounts() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counts := make(map[string]int, len(c.suppressed))
+	for symbol, count := range c.suppressed {
+		counts[symbol] = count
+	}
+	return counts
+}
+
+// Reset forgets the prior readings so each symbol's next reading becomes its new baseline.
+// Suppressed counts are kept.
+func (c *priceSanityChecker) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.states = make(map[string]*symbolPriceState)
+}
+
+// thresholdFor returns the deviation threshold of a symbol; 0 disables the check
+func (c *priceSanityChecker) thresholdFor(symbol string) float64 {
+	if percent, exists := c.symbolPercent[symbol]; exists {
+		return percent
+	}
+	return c.defaultPercent
+}
+
+// suppress counts and logs a suppressed reading; the caller must hold c.mu
+func (c *priceSanityChecker) suppress(symbol string, price, deviation float64, reason string) {
+	c.suppressed[symbol]++
+
+	fields := map[string]interface{}{
+		"symbol":           symbol,
+		"price":            price,
+		"reason":           reason,
+		"suppressed_count": c.suppressed[symbol],
+	}
+	if state, exists := c.states[symbol]; exists {
+		fields["previous_price"] = state.confirmed
+		fields["deviation_pct"] = deviation
+	}
+	c.logger.Warn("Suppressed anomalous price tick", fields)
+}
+
+// deviationPercent returns the absolute move from base to price in percent
+func deviationPercent(base, price float64) float64 {
+	return math.Abs(price-base) / base * 100
+}
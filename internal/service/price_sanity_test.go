@@ -0,0 +1,102 @@
+package service
+
+import (
+	"binance-trader/internal/config"
+	"binance-trader/internal/repository"
+	"testing"
+)
+
+func TestPriceSanityChecker_Observe(t *testing.T) {
+	checker := NewPriceSanityChecker(&config.PriceSanityConfig{
+		MaxDeviationPercent:    10,
+		SymbolDeviationPercent: map[string]float64{"DOGEUSDT": 30},
+	}, &mockLogger{})
+
+	steps := []struct {
+		symbol   string
+		price    float64
+		accepted bool
+	}{
+		{"BTCUSDT", 100, true},   // first reading sets the baseline
+		{"BTCUSDT", 105, true},   // within 10%
+		{"BTCUSDT", 150, false},  // one-tick spike is held back
+		{"BTCUSDT", 104, true},   // back within range of the baseline
+		{"BTCUSDT", 80, false},   // sharp drop awaits confirmation
+		{"BTCUSDT", 79, true},    // second consecutive reading confirms the move
+		{"BTCUSDT", 0, false},    // non-positive prices are never valid
+		{"DOGEUSDT", 0.10, true}, // per-symbol threshold
+		{"DOGEUSDT", 0.12, true}, // 20% is within the DOGEUSDT override
+	}
+
+	for i, step := range steps {
+		if accepted := checker.Observe(step.symbol, step.price); accepted != step.accepted {
+			t.Errorf("step %d (%s at %.4f): expected accepted=%v, got %v", i, step.symbol, step.price, step.accepted, accepted)
+		}
+	}
+
+	counts := checker.GetSuppressedCounts()
+	if counts["BTCUSDT"] != 3 {
+		t.Errorf("expected 3 suppressed BTCUSDT readings, got %d", counts["BTCUSDT"])
+	}
+	if counts["DOGEUSDT"] != 0 {
+		t.Errorf("expected no suppressed DOGEUSDT readings, got %d", counts["DOGEUSDT"])
+	}
+}
+
+func TestPriceSanityChecker_ZeroThresholdDisablesCheck(t *testing.T) {
+	checker := NewPriceSanityChecker(&config.PriceSanityConfig{}, &mockLogger{})
+
+	for _, price := range []float64{100, 10, 1000} {
+		if !checker.Observe("BTCUSDT", price) {
+			t.Errorf("expected %.2f to be accepted with the check disabled", price)
+		}
+	}
+}
+
+func TestMonitoringEngine_TrailingStopIgnoresUnconfirmedSpike(t *testing.T) {
+	stopOrderRepo := repository.NewMemoryStopOrderRepository()
+	triggerEngine := NewTriggerEngine()
+	trading := &recordingSellTradingService{}
+	market := &mockStopLossMarketDataService{currentPrice: 100}
+	stopLoss := NewStopLossService(stopOrderRepo, triggerEngine, trading, market, &mockLogger{})
+
+	engine := NewMonitoringEngine(repository.NewMemoryConditionalOrderRepository(), stopOrderRepo,
+		triggerEngine, trading, market, stopLoss, &mockLogger{}, nil)
+	checker := NewPriceSanityChecker(&config.PriceSanityConfig{MaxDeviationPercent: 10}, &mockLogger{})
+	engine.SetPriceSanityChecker(checker)
+
+	if _, err := stopLoss.SetTrailingStop("BTCUSDT", 1.0, 5.0); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tick := func(price float64) {
+		market.currentPrice = price
+		engine.marketDataCache = make(map[string]*MarketData)
+		engine.processTrailingStopsForSymbol("BTCUSDT")
+	}
+
+	// Baseline, then a one-tick 50% drop that immediately recovers
+	tick(100)
+	tick(50)
+	if len(trading.sells) != 0 {
+		t.Fatalf("expected a single bad tick not to trigger the stop, got sells %v", trading.sells)
+	}
+	tick(100)
+	if len(trading.sells) != 0 {
+		t.Fatalf("expected no sell after the price recovered, got %v", trading.sells)
+	}
+
+	if counts := checker.GetSuppressedCounts(); counts["BTCUSDT"] != 1 {
+		t.Errorf("expected 1 suppressed reading, got %d", counts["BTCUSDT"])
+	}
+
+	// A confirmed drop triggers the stop on the second consecutive reading
+	tick(50)
+	if len(trading.sells) != 0 {
+		t.Fatalf("expected the first reading of the drop to await confirmation, got sells %v", trading.sells)
+	}
+	tick(50)
+	if len(trading.sells) != 1 {
+		t.Fatalf("expected the confirmed drop to trigger the stop, got sells %v", trading.sells)
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/pkg/errors"
+	"fmt"
+)
+
+// Taker commission rates used when stop_loss.profit_guard.fee_rate is not configured. Entries
+// are usually market orders and a triggered take profit closes with a market order, so both
+// sides pay the taker rate.
+const (
+	DefaultSpotFeeRate    = 0.001
+	DefaultFuturesFeeRate = 0.0004
+)
+
+// FeeModel holds the commission rates paid on entering and on exiting a position, as fractions
+// of the traded value
+type FeeModel struct {
+	EntryRate float64
+	ExitRate  float64
+}
+
+// TakeProfitCheck is the fee-adjusted result of closing a position at a take-profit target
+type TakeProfitCheck struct {
+	Side             api.PositionSide
+	EntryPrice       float64
+	EntryEstimated   bool // The entry price is the current price, not a known fill price
+	TargetPrice      float64
+	Quantity         float64
+	GrossPnL         float64
+	Fees             float64 // Entry and exit commission
+	NetPnL           float64
+	NetPercent       float64 // Net PnL as a percent of the entry value
+	MinProfitPercent float64
+}
+
+// Unprofitable reports whether reaching the target loses money after fees
+func (c *TakeProfitCheck) Unprofitable() bool {
+	return c.NetPnL < 0
+}
+
+// BelowMinimum reports whether the net profit at the target is under the configured minimum
+func (c *TakeProfitCheck) BelowMinimum() bool {
+	return c.NetPercent < c.MinProfitPercent
+}
+
+// TakeProfitGuard checks take-profit targets against a fee model, so targets that only cover
+// the commission are caught before the order is created
+type TakeProfitGuard struct {
+	fees             FeeModel
+	minProfitPercent float64
+}
+
+// NewTakeProfitGuard creates a guard charging feeRate on both entry and exit; a nil or zero
+// configured fee rate uses defaultFeeRate
+func NewTakeProfitGuard(cfg *config.ProfitGuardConfig, defaultFeeRate float64) *TakeProfitGuard {
+	guard := &TakeProfitGuard{fees: FeeModel{EntryRate: defaultFeeRate, ExitRate: defaultFeeRate}}
+	if cfg == nil {
+		return guard
+	}
+
+	if cfg.FeeRate > 0 {
+		guard.fees = FeeModel{EntryRate: cfg.FeeRate, ExitRate: cfg.FeeRate}
+	}
+	guard.minProfitPercent = cfg.MinProfitPercent
+	return guard
+}
+
+// Fees returns the fee model of the guard
+func (g *TakeProfitGuard) Fees() FeeModel {
+	return g.fees
+}
+
+// Check computes the net PnL of closing quantity at targetPrice for a position entered at
+// entryPrice. Spot holdings are checked as LONG positions.
+func (g *TakeProfitGuard) Check(side api.PositionSide, entryPrice, targetPrice, quantity float64) (*TakeProfitCheck, error) {
+	if side != api.PositionSideLong && side != api.PositionSideShort {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "position side must be LONG or SHORT", 0, nil)
+	}
+	if entryPrice <= 0 || targetPrice <= 0 || quantity <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter,
+			fmt.Sprintf("entry price, target price and quantity must be greater than 0, got %g, %g and %g", entryPrice, targetPrice, quantity), 0, nil)
+	}
+
+	gross := (targetPrice - entryPrice) * quantity
+	if side == api.PositionSideShort {
+		gross = -gross
+	}
+
+	entryValue := entryPrice * quantity
+	fees := entryValue*g.fees.EntryRate + targetPrice*quantity*g.fees.ExitRate
+	net := gross - fees
+
+	return &TakeProfitCheck{
+		Side:             side,
+		EntryPrice:       entryPrice,
+		TargetPrice:      targetPrice,
+		Quantity:         quantity,
+		GrossPnL:         gross,
+		Fees:             fees,
+		NetPnL:           net,
+		NetPercent:       net / entryValue * 100,
+		MinProfitPercent: g.minProfitPercent,
+	}, nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"math"
+	"testing"
+)
+
+func TestTakeProfitGuardCheck(t *testing.T) {
+	// Entry at 100 with 0.1% charged on both sides: a round trip costs about 0.2%, so a 0.1%
+	// target loses money and a 1% target keeps about 0.8%
+	guard := NewTakeProfitGuard(&config.ProfitGuardConfig{FeeRate: 0.001, MinProfitPercent: 0.5}, DefaultSpotFeeRate)
+
+	tests := []struct {
+		name         string
+		side         api.PositionSide
+		entry        float64
+		target       float64
+		wantNet      float64
+		unprofitable bool
+		belowMinimum bool
+	}{
+		// gross 0.1, fees 0.1 + 0.1001
+		{"long 0.1% target", api.PositionSideLong, 100, 100.1, -0.1001, true, true},
+		// gross 1, fees 0.1 + 0.101
+		{"long 1% target", api.PositionSideLong, 100, 101, 0.799, false, false},
+		// gross 0.1, fees 0.1 + 0.0999
+		{"short 0.1% target", api.PositionSideShort, 100, 99.9, -0.0999, true, true},
+		// gross 1, fees 0.1 + 0.099
+		{"short 1% target", api.PositionSideShort, 100, 99, 0.801, false, false},
+		// gross 0.3, fees 0.1 + 0.1003
+		{"long 0.3% target", api.PositionSideLong, 100, 100.3, 0.0997, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := guard.Check(tt.side, tt.entry, tt.target, 1)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(check.NetPnL-tt.wantNet) > 1e-9 {
+				t.Errorf("NetPnL = %v, want %v", check.NetPnL, tt.wantNet)
+			}
+			if math.Abs(check.NetPercent-tt.wantNet) > 1e-9 {
+				t.Errorf("NetPercent = %v, want %v", check.NetPercent, tt.wantNet)
+			}
+			if check.Unprofitable() != tt.unprofitable {
+				t.Errorf("Unprofitable() = %v, want %v", check.Unprofitable(), tt.unprofitable)
+			}
+			if check.BelowMinimum() != tt.belowMinimum {
+				t.Errorf("BelowMinimum() = %v, want %v", check.BelowMinimum(), tt.belowMinimum)
+			}
+		})
+	}
+}
+
+func TestTakeProfitGuardDefaults(t *testing.T) {
+	if fees := NewTakeProfitGuard(nil, DefaultFuturesFeeRate).Fees(); fees.EntryRate != DefaultFuturesFeeRate || fees.ExitRate != DefaultFuturesFeeRate {
+		t.Errorf("nil config fees = %+v, want the default rate on both sides", fees)
+	}
+	if fees := NewTakeProfitGuard(&config.ProfitGuardConfig{}, DefaultSpotFeeRate).Fees(); fees.EntryRate != DefaultSpotFeeRate {
+		t.Errorf("unset fee rate = %+v, want the default rate", fees)
+	}
+
+	guard := NewTakeProfitGuard(nil, DefaultSpotFeeRate)
+	if _, err := guard.Check("BOTH", 100, 101, 1); err == nil {
+		t.Error("expected error for a side other than LONG or SHORT")
+	}
+	if _, err := guard.Check(api.PositionSideLong, 0, 101, 1); err == nil {
+		t.Error("expected error for a zero entry price")
+	}
+}
@@ -0,0 +1,482 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/logger"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCoverageCheckInterval is used when no coverage check interval is configured
+const DefaultCoverageCheckInterval = 5 * time.Minute
+
+// Exposure markets reported by the coverage check
+const (
+	CoverageMarketSpot    = "SPOT"
+	CoverageMarketFutures = "FUTURES"
+)
+
+// ExposureCoverage reports how much of one position or holding is protected by stop orders
+type ExposureCoverage struct {
+	Market          string
+	Symbol          string
+	PositionSide    api.PositionSide // Empty for spot holdings
+	Long            bool
+	Quantity        float64
+	Price           float64 // Current (mark) price used for distances and notional
+	ProtectedQty    float64
+	UnprotectedQty  float64
+	StopPrice       float64 // Nearest stop to the current price, 0 when unprotected
+	StopDistancePct float64 // Distance from the current price to StopPrice in percent
+	HasTakeProfit   bool
+	LocalStops      int
+	NativeStops     int
+}
+
+// CoveragePct returns the protected share of the exposure in percent
+func (e *ExposureCoverage) CoveragePct() float64 {
+	if e.Quantity <= 0 {
+		return 100
+	}
+	return e.ProtectedQty / e.Quantity * 100
+}
+
+// Label identifies the exposure in reports, e.g. "BTCUSDT LONG" or "ETHUSDT"
+func (e *ExposureCoverage) Label() string {
+	if e.PositionSide == "" || e.PositionSide == api.PositionSideBoth {
+		return e.Symbol
+	}
+	return fmt.Sprintf("%s %s", e.Symbol, e.PositionSide)
+}
+
+// CoverageReport summarizes stop protection across all open exposure
+type CoverageReport struct {
+	Exposures         []*ExposureCoverage
+	TotalNotional     float64
+	ProtectedNotional float64
+	CoveragePct       float64  // Protected share of total notional; 100 when nothing is open
+	Unprotected       []string // Labels of exposures without any stop protection
+	CheckedAt         int64
+}
+
+// ProtectionCoverageChecker cross-references open exposure with local and native stop orders
+type ProtectionCoverageChecker interface {
+	CheckCoverage() (*CoverageReport, error)
+
+	// OnAlert registers a callback invoked when a scheduled check finds coverage below the minimum
+	OnAlert(callback func(report *CoverageReport))
+
+	// Scheduled check
+	StartMonitoring(checkInterval time.Duration) error
+	StopMonitoring() error
+}
+
+// protectiveOrder is a local or exchange-side order that closes part of an exposure
+type protectiveOrder struct {
+	long       bool // Direction of the exposure the order protects
+	quantity   float64
+	stopPrice  float64
+	takeProfit bool
+	native     bool
+}
+
+// protectionCoverageChecker implements ProtectionCoverageChecker for one market
+type protectionCoverageChecker struct {
+	market         string
+	collect        func() ([]*ExposureCoverage, map[string][]*protectiveOrder, error)
+	minCoveragePct float64
+	logger         logger.Logger
+	now            func() time.Time
+
+	mu             sync.Mutex
+	alertCallbacks []func(report *CoverageReport)
+	belowMinimum   bool
+
+	// Monitoring
+	stopChan     chan struct{}
+	monitoringMu sync.Mutex
+	isMonitoring bool
+}
+
+// NewSpotCoverageChecker creates a coverage checker for the configured spot holdings
+func NewSpotCoverageChecker(
+	client api.SpotClient,
+	stopLossSvc StopLossService,
+	marketService MarketDataService,
+	cfg *config.ProtectionCoverageConfig,
+	log logger.Logger,
+) ProtectionCoverageChecker {
+	if cfg == nil {
+		cfg = &config.ProtectionCoverageConfig{}
+	}
+	symbols := cfg.SpotSymbols
+
+	checker := newProtectionCoverageChecker(CoverageMarketSpot, cfg, log)
+	checker.collect = func() ([]*ExposureCoverage, map[string][]*protectiveOrder, error) {
+		exposures := make([]*ExposureCoverage, 0, len(symbols))
+		orders := make(map[string][]*protectiveOrder)
+
+		for _, symbol := range symbols {
+			symbol = strings.ToUpper(symbol)
+			quote := extractQuoteAsset(symbol)
+			if quote == "" {
+				return nil, nil, fmt.Errorf("cannot determine base asset of %s", symbol)
+			}
+
+			balance, err := client.GetBalance(strings.TrimSuffix(symbol, quote))
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get balance for %s: %w", symbol, err)
+			}
+			quantity := balance.Free + balance.Locked
+			if quantity <= 0 {
+				continue
+			}
+
+			price, err := marketService.GetCurrentPrice(symbol)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get price for %s: %w", symbol, err)
+			}
+
+			localOrders, err := stopLossSvc.GetActiveStopOrders(symbol)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get stop orders for %s: %w", symbol, err)
+			}
+			for _, order := range localOrders {
+				orders[symbol] = append(orders[symbol], localProtectiveOrder(order, true))
+			}
+
+			openOrders, err := client.GetOpenOrders(symbol)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get open orders for %s: %w", symbol, err)
+			}
+			for _, order := range openOrders {
+				if order.Side != api.OrderSideSell {
+					continue
+				}
+				if protective := nativeProtectiveOrder(order.Type, true, order.OrigQty-order.ExecutedQty, order.StopPrice); protective != nil {
+					orders[symbol] = append(orders[symbol], protective)
+				}
+			}
+
+			exposures = append(exposures, &ExposureCoverage{
+				Market:   CoverageMarketSpot,
+				Symbol:   symbol,
+				Long:     true,
+				Quantity: quantity,
+				Price:    price,
+			})
+		}
+
+		return exposures, orders, nil
+	}
+
+	return checker
+}
+
+// NewFuturesCoverageChecker creates a coverage checker for open futures positions
+func NewFuturesCoverageChecker(
+	positionMgr FuturesPositionManager,
+	tradingService FuturesTradingService,
+	stopLossSvc FuturesStopLossService,
+	marketService FuturesMarketDataService,
+	cfg *config.ProtectionCoverageConfig,
+	log logger.Logger,
+) ProtectionCoverageChecker {
+	checker := newProtectionCoverageChecker(CoverageMarketFutures, cfg, log)
+	checker.collect = func() ([]*ExposureCoverage, map[string][]*protectiveOrder, error) {
+		positions, err := positionMgr.GetAllPositions()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get positions: %w", err)
+		}
+
+		var exposures []*ExposureCoverage
+		orders := make(map[string][]*protectiveOrder)
+		prices := make(map[string]float64)
+
+		for _, position := range positions {
+			if position.PositionAmt == 0 {
+				continue
+			}
+			symbol := position.Symbol
+
+			if _, loaded := prices[symbol]; !loaded {
+				markPrice, err := marketService.GetMarkPrice(symbol)
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to get mark price for %s: %w", symbol, err)
+				}
+				prices[symbol] = markPrice
+
+				// Local stops carry no position side; the side is inferred from where the
+				// stop sits relative to the mark price
+				localOrders, err := stopLossSvc.GetActiveStopOrders(symbol)
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to get stop orders for %s: %w", symbol, err)
+				}
+				for _, order := range localOrders {
+					orders[symbol] = append(orders[symbol], localProtectiveOrder(order, protectsLong(order, markPrice)))
+				}
+
+				openOrders, err := tradingService.GetActiveOrders(symbol)
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to get open orders for %s: %w", symbol, err)
+				}
+				for _, order := range openOrders {
+					long := order.Side == api.OrderSideSell
+					if order.PositionSide == api.PositionSideLong || order.PositionSide == api.PositionSideShort {
+						long = order.PositionSide == api.PositionSideLong
+					}
+					quantity := order.OrigQty - order.ExecutedQty
+					if order.ClosePosition {
+						quantity = math.Inf(1)
+					}
+					if protective := nativeProtectiveOrder(order.Type, long, quantity, order.StopPrice); protective != nil {
+						orders[symbol] = append(orders[symbol], protective)
+					}
+				}
+			}
+
+			long := position.PositionSide == api.PositionSideLong ||
+				(position.PositionSide != api.PositionSideShort && position.PositionAmt > 0)
+			exposures = append(exposures, &ExposureCoverage{
+				Market:       CoverageMarketFutures,
+				Symbol:       symbol,
+				PositionSide: position.PositionSide,
+				Long:         long,
+				Quantity:     math.Abs(position.PositionAmt),
+				Price:        prices[symbol],
+			})
+		}
+
+		return exposures, orders, nil
+	}
+
+	return checker
+}
+
+// newProtectionCoverageChecker creates the shared checker state
+func newProtectionCoverageChecker(market string, cfg *config.ProtectionCoverageConfig, log logger.Logger) *protectionCoverageChecker {
+	minCoveragePct := 0.0
+	if cfg != nil {
+		minCoveragePct = cfg.MinCoveragePct
+	}
+
+	return &protectionCoverageChecker{
+		market:         market,
+		minCoveragePct: minCoveragePct,
+		logger:         log,
+		now:            time.Now,
+	}
+}
+
+// protectsLong infers the direction of the position a local futures stop order protects from
+// where it sits relative to the mark price: stops below and targets above protect longs
+func protectsLong(order *repository.StopOrder, markPrice float64) bool {
+	if order.Type == repository.StopOrderTypeTakeProfit {
+		return order.StopPrice > markPrice
+	}
+	return order.StopPrice < markPrice
+}
+
+// localProtectiveOrder converts a locally monitored stop order
+func localProtectiveOrder(order *repository.StopOrder, long bool) *protectiveOrder {
+	return &protectiveOrder{
+		long:       long,
+		quantity:   order.Position,
+		stopPrice:  order.StopPrice,
+		takeProfit: order.Type == repository.StopOrderTypeTakeProfit,
+	}
+}
+
+// nativeProtectiveOrder converts an exchange-side stop or take profit order; other order types return nil
+func nativeProtectiveOrder(orderType api.OrderType, long bool, quantity, stopPrice float64) *protectiveOrder {
+	kind := string(orderType)
+	switch {
+	case strings.Contains(kind, "TAKE_PROFIT"):
+		return &protectiveOrder{long: long, quantity: quantity, stopPrice: stopPrice, takeProfit: true, native: true}
+	case strings.Contains(kind, "STOP"):
+		return &protectiveOrder{long: long, quantity: quantity, stopPrice: stopPrice, native: true}
+	default:
+		return nil
+	}
+}
+
+// CheckCoverage computes the protection of every open exposure
+func (c *protectionCoverageChecker) CheckCoverage() (*CoverageReport, error) {
+	exposures, orders, err := c.collect()
+	if err != nil {
+		c.logger.Error("Failed to check protection coverage", map[string]interface{}{
+			"market": c.market,
+			"error":  err.Error(),
+		})
+		return nil, err
+	}
+
+	for _, exposure := range exposures {
+		applyProtection(exposure, orders[exposure.Symbol])
+	}
+	return buildCoverageReport(exposures, c.now().UnixMilli()), nil
+}
+
+// applyProtection fills the protected quantity, nearest stop and take profit flag of an exposure
+func applyProtection(exposure *ExposureCoverage, orders []*protectiveOrder) {
+	stopQty := 0.0
+	for _, order := range orders {
+		if order.long != exposure.Long {
+			continue
+		}
+		if order.takeProfit {
+			exposure.HasTakeProfit = true
+			continue
+		}
+
+		stopQty += order.quantity
+		if order.native {
+			exposure.NativeStops++
+		} else {
+			exposure.LocalStops++
+		}
+
+		// Nearest stop: the highest below a long, the lowest above a short
+		if order.stopPrice > 0 && (exposure.StopPrice == 0 ||
+			(exposure.Long && order.stopPrice > exposure.StopPrice) ||
+			(!exposure.Long && order.stopPrice < exposure.StopPrice)) {
+			exposure.StopPrice = order.stopPrice
+		}
+	}
+
+	exposure.ProtectedQty = math.Min(stopQty, exposure.Quantity)
+	exposure.UnprotectedQty = exposure.Quantity - exposure.ProtectedQty
+	if exposure.StopPrice > 0 && exposure.Price > 0 {
+		exposure.StopDistancePct = math.Abs(exposure.Price-exposure.StopPrice) / exposure.Price * 100
+	}
+}
+
+// buildCoverageReport aggregates exposures into notional totals
+func buildCoverageReport(exposures []*ExposureCoverage, checkedAt int64) *CoverageReport {
+	report := &CoverageReport{
+		Exposures:   exposures,
+		CoveragePct: 100,
+		CheckedAt:   checkedAt,
+	}
+
+	for _, exposure := range exposures {
+		report.TotalNotional += exposure.Quantity * exposure.Price
+		report.ProtectedNotional += exposure.ProtectedQty * exposure.Price
+		if exposure.ProtectedQty <= 0 {
+			report.Unprotected = append(report.Unprotected, exposure.Label())
+		}
+	}
+
+	if report.TotalNotional > 0 {
+		report.CoveragePct = report.ProtectedNotional / report.TotalNotional * 100
+	}
+	return report
+}
+
+// OnAlert registers a callback invoked when a scheduled check finds coverage below the minimum
+func (c *protectionCoverageChecker) OnAlert(callback func(report *CoverageReport)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.alertCallbacks = append(c.alertCallbacks, callback)
+}
+
+// StartMonitoring starts the scheduled coverage check
+func (c *protectionCoverageChecker) StartMonitoring(checkInterval time.Duration) error {
+	c.monitoringMu.Lock()
+	defer c.monitoringMu.Unlock()
+
+	if c.isMonitoring {
+		return fmt.Errorf("monitoring is already running")
+	}
+
+	if checkInterval <= 0 {
+		checkInterval = DefaultCoverageCheckInterval
+	}
+
+	c.stopChan = make(chan struct{})
+	c.isMonitoring = true
+
+	go c.monitoringLoop(checkInterval)
+
+	c.logger.Info("Started protection coverage monitoring", map[string]interface{}{
+		"market":           c.market,
+		"check_interval":   checkInterval.String(),
+		"min_coverage_pct": c.minCoveragePct,
+	})
+
+	return nil
+}
+
+// StopMonitoring stops the scheduled coverage check
+func (c *protectionCoverageChecker) StopMonitoring() error {
+	c.monitoringMu.Lock()
+	defer c.monitoringMu.Unlock()
+
+	if !c.isMonitoring {
+		return fmt.Errorf("monitoring is not running")
+	}
+
+	close(c.stopChan)
+	c.isMonitoring = false
+
+	c.logger.Info("Stopped protection coverage monitoring", map[string]interface{}{
+		"market": c.market,
+	})
+
+	return nil
+}
+
+// monitoringLoop runs the coverage check on every tick
+func (c *protectionCoverageChecker) monitoringLoop(checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopChan:
+			return
+		case <-ticker.C:
+			c.checkAndNotify()
+		}
+	}
+}
+
+// checkAndNotify runs a coverage check and notifies once when coverage drops below the minimum
+func (c *protectionCoverageChecker) checkAndNotify() {
+	report, err := c.CheckCoverage()
+	if err != nil {
+		return
+	}
+
+	below := report.CoveragePct < c.minCoveragePct
+
+	c.mu.Lock()
+	wasBelow := c.belowMinimum
+	c.belowMinimum = below
+	callbacks := make([]func(report *CoverageReport), len(c.alertCallbacks))
+	copy(callbacks, c.alertCallbacks)
+	c.mu.Unlock()
+
+	if below && !wasBelow {
+		// Single notification until coverage recovers
+		c.logger.Warn("Protection coverage below minimum", map[string]interface{}{
+			"market":           c.market,
+			"coverage_pct":     report.CoveragePct,
+			"min_coverage_pct": c.minCoveragePct,
+			"unprotected":      strings.Join(report.Unprotected, ", "),
+		})
+		for _, callback := range callbacks {
+			callback(report)
+		}
+	} else if !below && wasBelow {
+		c.logger.Info("Protection coverage restored", map[string]interface{}{
+			"market":       c.market,
+			"coverage_pct": report.CoveragePct,
+		})
+	}
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/internal/repository"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// coverageStopLossService returns fixed active spot stop orders per symbol
+type coverageStopLossService struct {
+	mockStopLossService
+	orders map[string][]*repository.StopOrder
+}
+
+func (m *coverageStopLossService) GetActiveStopOrders(symbol string) ([]*repository.StopOrder, error) {
+	return m.orders[symbol], nil
+}
+
+// coverageFuturesStopLossService returns fixed active futures stop orders per symbol
+type coverageFuturesStopLossService struct {
+	FuturesStopLossService
+	orders map[string][]*repository.StopOrder
+}
+
+func (m *coverageFuturesStopLossService) GetActiveStopOrders(symbol string) ([]*repository.StopOrder, error) {
+	return m.orders[symbol], nil
+}
+
+// coverageFuturesTradingService returns fixed open exchange orders per symbol
+type coverageFuturesTradingService struct {
+	mockFuturesTradingServiceShared
+	open map[string][]*api.FuturesOrder
+}
+
+func (m *coverageFuturesTradingService) GetActiveOrders(symbol string) ([]*api.FuturesOrder, error) {
+	return m.open[symbol], nil
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestApplyProtection(t *testing.T) {
+	tests := []struct {
+		name            string
+		long            bool
+		orders          []*protectiveOrder
+		wantProtected   float64
+		wantUnprotected float64
+		wantStopPrice   float64
+		wantTakeProfit  bool
+	}{
+		{
+			name:            "fully protected",
+			long:            true,
+			orders:          []*protectiveOrder{{long: true, quantity: 2, stopPrice: 90}},
+			wantProtected:   2,
+			wantUnprotected: 0,
+			wantStopPrice:   90,
+		},
+		{
+			name:            "partially protected",
+			long:            true,
+			orders:          []*protectiveOrder{{long: true, quantity: 0.5, stopPrice: 95}},
+			wantProtected:   0.5,
+			wantUnprotected: 1.5,
+			wantStopPrice:   95,
+		},
+		{
+			name:            "missing protection",
+			long:            true,
+			wantProtected:   0,
+			wantUnprotected: 2,
+		},
+		{
+			name: "take profit alone does not protect",
+			long: true,
+			orders: []*protectiveOrder{
+				{long: true, quantity: 2, stopPrice: 120, takeProfit: true},
+			},
+			wantProtected:   0,
+			wantUnprotected: 2,
+			wantTakeProfit:  true,
+		},
+		{
+			name: "stops beyond the quantity are capped",
+			long: true,
+			orders: []*protectiveOrder{
+				{long: true, quantity: 2, stopPrice: 90},
+				{long: true, quantity: 1, stopPrice: 80, native: true},
+			},
+			wantProtected:   2,
+			wantUnprotected: 0,
+			wantStopPrice:   90,
+		},
+		{
+			name: "nearest stop above a short",
+			long: false,
+			orders: []*protectiveOrder{
+				{long: false, quantity: 1, stopPrice: 115},
+				{long: false, quantity: 1, stopPrice: 105, native: true},
+				{long: false, quantity: 2, stopPrice: 90, takeProfit: true},
+			},
+			wantProtected:   2,
+			wantUnprotected: 0,
+			wantStopPrice:   105,
+			wantTakeProfit:  true,
+		},
+		{
+			name:            "stops of the opposite side are ignored",
+			long:            true,
+			orders:          []*protectiveOrder{{long: false, quantity: 2, stopPrice: 110}},
+			wantProtected:   0,
+			wantUnprotected: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exposure := &ExposureCoverage{Symbol: "BTCUSDT", Long: tt.long, Quantity: 2, Price: 100}
+			applyProtection(exposure, tt.orders)
+
+			if !approxEqual(exposure.ProtectedQty, tt.wantProtected) {
+				t.Errorf("ProtectedQty = %v, want %v", exposure.ProtectedQty, tt.wantProtected)
+			}
+			if !approxEqual(exposure.UnprotectedQty, tt.wantUnprotected) {
+				t.Errorf("UnprotectedQty = %v, want %v", exposure.UnprotectedQty, tt.wantUnprotected)
+			}
+			if exposure.StopPrice != tt.wantStopPrice {
+				t.Errorf("StopPrice = %v, want %v", exposure.StopPrice, tt.wantStopPrice)
+			}
+			if tt.wantStopPrice > 0 && !approxEqual(exposure.StopDistancePct, math.Abs(100-tt.wantStopPrice)) {
+				t.Errorf("StopDistancePct = %v, want %v", exposure.StopDistancePct, math.Abs(100-tt.wantStopPrice))
+			}
+			if exposure.HasTakeProfit != tt.wantTakeProfit {
+				t.Errorf("HasTakeProfit = %v, want %v", exposure.HasTakeProfit, tt.wantTakeProfit)
+			}
+		})
+	}
+}
+
+func TestSpotCoverageChecker(t *testing.T) {
+	balances := map[string]*api.Balance{
+		"BTC": {Asset: "BTC", Free: 1},
+		"ETH": {Asset: "ETH", Free: 8, Locked: 2},
+		"BNB": {Asset: "BNB", Free: 5},
+		"SOL": {Asset: "SOL"},
+	}
+	client := &mockBinanceClient{
+		getBalanceFunc: func(asset string) (*api.Balance, error) {
+			return balances[asset], nil
+		},
+		getOpenOrdersFunc: func(symbol string) ([]*api.Order, error) {
+			switch symbol {
+			case "BTCUSDT":
+				return []*api.Order{
+					{Symbol: symbol, Side: api.OrderSideSell, Type: "TAKE_PROFIT_LIMIT", StopPrice: 60000, OrigQty: 1},
+				}, nil
+			case "ETHUSDT":
+				return []*api.Order{
+					{Symbol: symbol, Side: api.OrderSideSell, Type: "STOP_LOSS_LIMIT", StopPrice: 2700, OrigQty: 5, ExecutedQty: 1},
+					{Symbol: symbol, Side: api.OrderSideSell, Type: api.OrderTypeLimit, Price: 3500, OrigQty: 5},
+				}, nil
+			}
+			return nil, nil
+		},
+	}
+	stopLossSvc := &coverageStopLossService{orders: map[string][]*repository.StopOrder{
+		"BTCUSDT": {{Symbol: "BTCUSDT", Position: 1, StopPrice: 45000, Type: repository.StopOrderTypeStopLoss}},
+	}}
+	marketService := &mockMarketDataService{prices: map[string]float64{
+		"BTCUSDT": 50000,
+		"ETHUSDT": 3000,
+		"BNBUSDT": 400,
+	}}
+	cfg := &config.ProtectionCoverageConfig{SpotSymbols: []string{"BTCUSDT", "ethusdt", "BNBUSDT", "SOLUSDT"}}
+
+	checker := NewSpotCoverageChecker(client, stopLossSvc, marketService, cfg, &mockLogger{})
+	report, err := checker.CheckCoverage()
+	if err != nil {
+		t.Fatalf("CheckCoverage failed: %v", err)
+	}
+
+	if len(report.Exposures) != 3 {
+		t.Fatalf("expected 3 exposures (empty SOL balance skipped), got %d", len(report.Exposures))
+	}
+
+	btc, eth, bnb := report.Exposures[0], report.Exposures[1], report.Exposures[2]
+	if btc.ProtectedQty != 1 || btc.LocalStops != 1 || !btc.HasTakeProfit || !approxEqual(btc.StopDistancePct, 10) {
+		t.Errorf("unexpected BTC coverage: %+v", btc)
+	}
+	if eth.Quantity != 10 || eth.ProtectedQty != 4 || eth.UnprotectedQty != 6 || eth.NativeStops != 1 || eth.HasTakeProfit {
+		t.Errorf("unexpected ETH coverage: %+v", eth)
+	}
+	if bnb.ProtectedQty != 0 || bnb.StopPrice != 0 {
+		t.Errorf("unexpected BNB coverage: %+v", bnb)
+	}
+
+	if !reflect.DeepEqual(report.Unprotected, []string{"BNBUSDT"}) {
+		t.Errorf("Unprotected = %v, want [BNBUSDT]", report.Unprotected)
+	}
+	// 50000 + 4*3000 protected out of 50000 + 30000 + 2000
+	wantPct := 62000.0 / 82000.0 * 100
+	if !approxEqual(report.CoveragePct, wantPct) {
+		t.Errorf("CoveragePct = %v, want %v", report.CoveragePct, wantPct)
+	}
+}
+
+func TestFuturesCoverageChecker(t *testing.T) {
+	positionMgr := &mockFuturesPositionManagerShared{positions: map[string]*api.Position{
+		"BTCUSDTLONG":  {Symbol: "BTCUSDT", PositionSide: api.PositionSideLong, PositionAmt: 0.2},
+		"BTCUSDTSHORT": {Symbol: "BTCUSDT", PositionSide: api.PositionSideShort, PositionAmt: -0.1},
+		"ETHUSDTBOTH":  {Symbol: "ETHUSDT", PositionSide: api.PositionSideBoth, PositionAmt: -1},
+		"BNBUSDTLONG":  {Symbol: "BNBUSDT", PositionSide: api.PositionSideLong},
+	}}
+	tradingService := &coverageFuturesTradingService{open: map[string][]*api.FuturesOrder{
+		"BTCUSDT": {
+			{Symbol: "BTCUSDT", Side: api.OrderSideBuy, PositionSide: api.PositionSideShort, Type: "STOP_MARKET", StopPrice: 52000, ClosePosition: true},
+			{Symbol: "BTCUSDT", Side: api.OrderSideSell, PositionSide: api.PositionSideLong, Type: "TAKE_PROFIT_MARKET", StopPrice: 55000, OrigQty: 0.2},
+		},
+	}}
+	// Local stops carry no side: below the mark protects the long, a take profit below the mark closes the short
+	stopLossSvc := &coverageFuturesStopLossService{orders: map[string][]*repository.StopOrder{
+		"BTCUSDT": {
+			{Symbol: "BTCUSDT", Position: 0.05, StopPrice: 48000, Type: repository.StopOrderTypeStopLoss},
+			{Symbol: "BTCUSDT", Position: 0.1, StopPrice: 45000, Type: repository.StopOrderTypeTakeProfit},
+		},
+	}}
+	marketService := &mockFuturesMarketDataServiceShared{markPrice: 50000}
+
+	checker := NewFuturesCoverageChecker(positionMgr, tradingService, stopLossSvc, marketService, nil, &mockLogger{})
+	report, err := checker.CheckCoverage()
+	if err != nil {
+		t.Fatalf("CheckCoverage failed: %v", err)
+	}
+
+	if len(report.Exposures) != 3 {
+		t.Fatalf("expected 3 exposures (flat BNB position skipped), got %d", len(report.Exposures))
+	}
+
+	byLabel := make(map[string]*ExposureCoverage)
+	for _, exposure := range report.Exposures {
+		byLabel[exposure.Label()] = exposure
+	}
+
+	long := byLabel["BTCUSDT LONG"]
+	if long == nil || !approxEqual(long.ProtectedQty, 0.05) || !approxEqual(long.UnprotectedQty, 0.15) ||
+		long.StopPrice != 48000 || !approxEqual(long.StopDistancePct, 4) || !long.HasTakeProfit || long.LocalStops != 1 {
+		t.Errorf("unexpected long coverage: %+v", long)
+	}
+
+	short := byLabel["BTCUSDT SHORT"]
+	if short == nil || !approxEqual(short.ProtectedQty, 0.1) || short.UnprotectedQty != 0 ||
+		short.StopPrice != 52000 || !short.HasTakeProfit || short.NativeStops != 1 {
+		t.Errorf("unexpected short coverage: %+v", short)
+	}
+
+	eth := byLabel["ETHUSDT"]
+	if eth == nil || eth.Long || eth.Quantity != 1 || eth.ProtectedQty != 0 {
+		t.Errorf("unexpected ETH coverage: %+v", eth)
+	}
+
+	if !reflect.DeepEqual(report.Unprotected, []string{"ETHUSDT"}) {
+		t.Errorf("Unprotected = %v, want [ETHUSDT]", report.Unprotected)
+	}
+}
+
+func TestCoverageReport_NoExposureIsFullyCovered(t *testing.T) {
+	report := buildCoverageReport(nil, 0)
+	if report.CoveragePct != 100 || len(report.Unprotected) != 0 {
+		t.Errorf("expected full coverage without exposure, got %+v", report)
+	}
+}
+
+func TestCoverageAlert_NotifiesOnceUntilRestored(t *testing.T) {
+	log := &mockLoggerCapture{}
+	checker := newProtectionCoverageChecker(CoverageMarketSpot, &config.ProtectionCoverageConfig{MinCoveragePct: 80}, log)
+
+	protectedQty := 0.5
+	checker.collect = func() ([]*ExposureCoverage, map[string][]*protectiveOrder, error) {
+		exposures := []*ExposureCoverage{{Symbol: "BTCUSDT", Long: true, Quantity: 1, Price: 100}}
+		orders := map[string][]*protectiveOrder{
+			"BTCUSDT": {{long: true, quantity: protectedQty, stopPrice: 90}},
+		}
+		return exposures, orders, nil
+	}
+
+	alerts := 0
+	checker.OnAlert(func(report *CoverageReport) {
+		alerts++
+		if report.CoveragePct != 50 {
+			t.Errorf("alert CoveragePct = %v, want 50", report.CoveragePct)
+		}
+	})
+
+	checker.checkAndNotify()
+	checker.checkAndNotify()
+	if alerts != 1 {
+		t.Fatalf("expected a single alert while coverage stays low, got %d", alerts)
+	}
+
+	protectedQty = 1
+	checker.checkAndNotify()
+
+	protectedQty = 0.5
+	checker.checkAndNotify()
+	if alerts != 2 {
+		t.Errorf("expected a new alert after coverage recovered and dropped again, got %d", alerts)
+	}
+
+	warnings := 0
+	for _, entry := range log.entries {
+		if entry["level"] == "warn" && entry["message"] == "Protection coverage below minimum" {
+			warnings++
+		}
+	}
+	if warnings != 2 {
+		t.Errorf("expected 2 warnings, got %d", warnings)
+	}
+}
+
+func TestCoverageMonitoring_StartStop(t *testing.T) {
+	checker := NewSpotCoverageChecker(&mockBinanceClient{}, &coverageStopLossService{}, &mockMarketDataService{}, nil, &mockLogger{})
+
+	if err := checker.StopMonitoring(); err == nil {
+		t.Error("expected error stopping monitoring that is not running")
+	}
+	if err := checker.StartMonitoring(0); err != nil {
+		t.Fatalf("StartMonitoring failed: %v", err)
+	}
+	if err := checker.StartMonitoring(0); err == nil {
+		t.Error("expected error starting monitoring twice")
+	}
+	if err := checker.StopMonitoring(); err != nil {
+		t.Errorf("StopMonitoring failed: %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/pkg/errors"
+	"binance-trader/pkg/logger"
+	"fmt"
+	"time"
+)
+
+// OrderIntent is an order a strategy or reconcile wants on the exchange, identified by a
+// client order ID that stays the same when the order is resubmitted after a restart
+type OrderIntent struct {
+	ClientOrderID string
+	Symbol        string
+	Side          api.OrderSide
+	Type          api.OrderType
+	Quantity      float64
+	Price         float64 // Limit orders only
+}
+
+// ReplayProtection cross-checks order intents against recent exchange orders and fills after a
+// restart, so an order that executed just before a crash is not submitted a second time
+type ReplayProtection interface {
+	// CheckIntent returns the exchange order already placed for the intent, or nil when it must be submitted
+	CheckIntent(intent *OrderIntent) (*api.Order, error)
+
+	// IsActive reports whether the protection window after startup is still open
+	IsActive() bool
+}
+
+// replayProtection implements ReplayProtection
+type replayProtection struct {
+	client    api.SpotClient
+	window    time.Duration
+	startedAt time.Time
+	logger    logger.Logger
+	now       func() time.Time
+}
+
+// NewReplayProtection creates replay protection whose window opens now, at startup
+func NewReplayProtection(client api.SpotClient, cfg *config.ReplayProtectionConfig, log logger.Logger) ReplayProtection {
+	if cfg == nil {
+		cfg = &config.ReplayProtectionConfig{}
+	}
+
+	return &replayProtection{
+		client:    client,
+		window:    time.Duration(cfg.WindowMs) * time.Millisecond,
+		startedAt: time.Now(),
+		logger:    log,
+		now:       time.Now,
+	}
+}
+
+// IsActive reports whether the protection window after startup is still open
+func (r *replayProtection) IsActive() bool {
+	return r.window > 0 && r.now().Before(r.startedAt.Add(r.window))
+}
+
+// CheckIntent looks the intent up among orders placed since one window before startup. An order
+// that is still working or has any fills counts as already placed; one that was cancelled,
+// rejected or expired without fills may be submitted again.
+func (r *replayProtection) CheckIntent(intent *OrderIntent) (*api.Order, error) {
+	if !r.IsActive() || intent.ClientOrderID == "" {
+		return nil, nil
+	}
+
+	startTime := r.startedAt.Add(-r.window).UnixMilli()
+	orders, err := r.client.GetHistoricalOrders(intent.Symbol, startTime, r.now().UnixMilli())
+	if err != nil {
+		return nil, errors.NewTradingError(
+			errors.ErrNetwork,
+			fmt.Sprintf("replay protection could not verify recent orders for %s", intent.Symbol),
+			0,
+			err,
+		)
+	}
+
+	var unfilled *api.Order
+	for _, order := range orders {
+		if order.ClientOrderID != intent.ClientOrderID {
+			continue
+		}
+
+		if order.Status == api.OrderStatusNew || order.Status == api.OrderStatusPartiallyFilled {
+			return order, nil
+		}
+
+		// The order record can lag behind its fills; the trade list is authoritative
+		if order.ExecutedQty <= 0 {
+			trades, err := r.client.GetMyTrades(intent.Symbol, order.OrderID)
+			if err != nil {
+				return nil, errors.NewTradingError(
+					errors.ErrNetwork,
+					fmt.Sprintf("replay protection could not verify fills of order %d", order.OrderID),
+					0,
+					err,
+				)
+			}
+			for _, trade := range trades {
+				order.ExecutedQty += trade.Qty
+				order.CummulativeQuoteQty += trade.QuoteQty
+			}
+		}
+
+		if order.ExecutedQty > 0 {
+			return order, nil
+		}
+		unfilled = order
+	}
+
+	if unfilled != nil {
+		r.logger.Info("Replay protection found an unfilled earlier attempt, resubmitting", map[string]interface{}{
+			"client_order_id": intent.ClientOrderID,
+			"order_id":        unfilled.OrderID,
+			"status":          string(unfilled.Status),
+		})
+	}
+	return nil, nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/internal/repository"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func newTestReplayProtection(client *mockBinanceClient) (*replayProtection, *time.Time) {
+	protection := NewReplayProtection(client, &config.ReplayProtectionConfig{WindowMs: 600000}, &mockLogger{}).(*replayProtection)
+
+	current := time.Unix(1700000000, 0)
+	protection.startedAt = current
+	protection.now = func() time.Time { return current }
+	return protection, &current
+}
+
+var testOrderIntent = &OrderIntent{
+	ClientOrderID: "grid-BTCUSDT-7",
+	Symbol:        "BTCUSDT",
+	Side:          api.OrderSideBuy,
+	Type:          api.OrderTypeLimit,
+	Quantity:      0.001,
+	Price:         50000,
+}
+
+func TestSubmitOrderIntent_SkipsAlreadyExecutedOrder(t *testing.T) {
+	created := 0
+	client := &mockBinanceClient{
+		getHistoricalOrdersFunc: func(symbol string, startTime, endTime int64) ([]*api.Order, error) {
+			if startTime != 1700000000000-600000 {
+				t.Errorf("expected lookup from one window before startup, got %d", startTime)
+			}
+			// The order record lags behind its fills and still reports nothing executed
+			return []*api.Order{
+				{OrderID: 41, Symbol: symbol, ClientOrderID: "other-intent", Status: api.OrderStatusFilled, ExecutedQty: 0.5},
+				{OrderID: 42, Symbol: symbol, ClientOrderID: "grid-BTCUSDT-7", Side: api.OrderSideBuy, Type: api.OrderTypeLimit, Status: api.OrderStatusFilled, OrigQty: 0.001},
+			}, nil
+		},
+		getMyTradesFunc: func(symbol string, orderID int64) ([]*api.Trade, error) {
+			if orderID != 42 {
+				t.Errorf("expected fills of order 42, got %d", orderID)
+			}
+			return []*api.Trade{
+				{ID: 1, OrderID: orderID, Price: 50000, Qty: 0.0004, QuoteQty: 20},
+				{ID: 2, OrderID: orderID, Price: 50000, Qty: 0.0006, QuoteQty: 30},
+			}, nil
+		},
+		createOrderFunc: func(order *api.OrderRequest) (*api.OrderResponse, error) {
+			created++
+			return &api.OrderResponse{OrderID: 99, Symbol: order.Symbol, Status: api.OrderStatusNew}, nil
+		},
+	}
+	protection, _ := newTestReplayProtection(client)
+
+	orderRepo := repository.NewMemoryOrderRepository()
+	svc := NewSpotTradingService(client, NewRiskManager(nil, client), orderRepo, &mockLogger{})
+	svc.SetReplayProtection(protection)
+
+	order, err := svc.SubmitOrderIntent(testOrderIntent)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if created != 0 {
+		t.Fatalf("already executed order should not be resubmitted, got %d submissions", created)
+	}
+	if order.OrderID != 42 || order.ExecutedQty < 0.000999 || order.CummulativeQuoteQty != 50 {
+		t.Errorf("expected existing order with fills from trades, got %+v", order)
+	}
+	if _, err := orderRepo.FindByID(42); err != nil {
+		t.Errorf("existing order should be recorded locally: %v", err)
+	}
+}
+
+func TestSubmitOrderIntent_ResubmitsUnfilledCancelledOrder(t *testing.T) {
+	var submitted *api.OrderRequest
+	client := &mockBinanceClient{
+		getHistoricalOrdersFunc: func(symbol string, startTime, endTime int64) ([]*api.Order, error) {
+			return []*api.Order{
+				{OrderID: 42, Symbol: symbol, ClientOrderID: "grid-BTCUSDT-7", Status: api.OrderStatusCanceled},
+			}, nil
+		},
+		createOrderFunc: func(order *api.OrderRequest) (*api.OrderResponse, error) {
+			submitted = order
+			return &api.OrderResponse{OrderID: 43, Symbol: order.Symbol, Status: api.OrderStatusNew, Price: order.Price, OrigQty: order.Quantity}, nil
+		},
+	}
+	protection, _ := newTestReplayProtection(client)
+
+	svc := NewSpotTradingService(client, NewRiskManager(nil, client), repository.NewMemoryOrderRepository(), &mockLogger{})
+	svc.SetReplayProtection(protection)
+
+	order, err := svc.SubmitOrderIntent(testOrderIntent)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if submitted == nil || submitted.NewClientOrderID != "grid-BTCUSDT-7" || submitted.TimeInForce != "GTC" {
+		t.Fatalf("expected resubmission under the same client order ID, got %+v", submitted)
+	}
+	if order.OrderID != 43 || order.ClientOrderID != "grid-BTCUSDT-7" {
+		t.Errorf("expected new order, got %+v", order)
+	}
+}
+
+func TestReplayProtection_WindowAndLookupFailure(t *testing.T) {
+	lookups := 0
+	client := &mockBinanceClient{
+		getHistoricalOrdersFunc: func(symbol string, startTime, endTime int64) ([]*api.Order, error) {
+			lookups++
+			return nil, fmt.Errorf("connection reset")
+		},
+	}
+	protection, current := newTestReplayProtection(client)
+
+	// A failed lookup must not let a possible duplicate through
+	if _, err := protection.CheckIntent(testOrderIntent); err == nil {
+		t.Error("expected error when recent orders cannot be verified")
+	}
+
+	*current = current.Add(10 * time.Minute)
+	if protection.IsActive() {
+		t.Error("protection should expire at the end of the window")
+	}
+	if order, err := protection.CheckIntent(testOrderIntent); order != nil || err != nil || lookups != 1 {
+		t.Errorf("expired protection should not look up orders, got %v, %v after %d lookups", order, err, lookups)
+	}
+
+	disabled := NewReplayProtection(client, &config.ReplayProtectionConfig{}, &mockLogger{})
+	if disabled.IsActive() {
+		t.Error("zero window should disable replay protection")
+	}
+}
@@ -2,8 +2,11 @@ package service
 
 import (
 	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
 	"binance-trader/pkg/errors"
+	"binance-trader/pkg/logger"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 )
@@ -14,6 +17,28 @@ type RiskLimits struct {
 	MaxDailyOrders    int     // Maximum orders per day
 	MinBalanceReserve float64 // Minimum balance to keep
 	MaxAPICallsPerMin int     // Maximum API calls per minute
+	MaxNotionalPerMin float64 // Maximum traded notional per rolling minute, 0 disables the cap
+	NotionalCapMode   string  // NotionalCapReject or NotionalCapDelay: the rejection carries when to retry
+}
+
+// Notional throughput cap modes
+const (
+	NotionalCapReject = "reject"
+	NotionalCapDelay  = "delay"
+)
+
+// notionalWindow is the rolling window of the notional throughput cap
+const notionalWindow = time.Minute
+
+// dailyOrderDateLayout formats the UTC date the daily order count is kept for
+const dailyOrderDateLayout = "2006-01-02"
+
+// DailyOrderStats is the daily order count against its limit
+type DailyOrderStats struct {
+	Date    string    // UTC date the count is for
+	Count   int       // Orders placed on that date
+	Limit   int       // MaxDailyOrders
+	ResetAt time.Time // Next UTC midnight, when the count starts again from 0
 }
 
 // RiskManager defines the interface for risk management
@@ -22,10 +47,14 @@ type RiskManager interface {
 	ValidateOrder(order *api.OrderRequest) error
 	CheckDailyLimit() error
 	CheckMinimumBalance(asset string) error
+	CheckAvailableBalance(asset string, quantity float64) error
 
 	// Limit management
 	UpdateLimits(limits *RiskLimits) error
 	GetCurrentLimits() *RiskLimits
+
+	// GetDailyOrderStats returns the orders placed today (UTC) against the daily limit
+	GetDailyOrderStats() *DailyOrderStats
 }
 
 // riskManager implements the RiskManager interface
@@ -33,7 +62,12 @@ type riskManager struct {
 	limits        *RiskLimits
 	client        api.BinanceClient
 	orderHistory  []orderRecord
+	reservations  map[*api.OrderRequest]orderRecord // Notional held for validated orders until they are placed or fail
+	dailyCount    repository.DailyOrderCount        // Orders placed on dailyCount.Date (UTC)
+	dailyStore    repository.DailyOrderCountStore
+	logger        logger.Logger
 	mu            sync.RWMutex
+	now           func() time.Time
 }
 
 // orderRecord tracks order creation time for frequency limiting
@@ -57,11 +91,38 @@ func NewRiskManager(limits *RiskLimits, client api.BinanceClient) RiskManager {
 		limits:       limits,
 		client:       client,
 		orderHistory: make([]orderRecord, 0),
+		reservations: make(map[*api.OrderRequest]orderRecord),
+		dailyStore:   repository.NewMemoryDailyOrderCountStore(),
+		now:          time.Now,
+	}
+}
+
+// NewRiskManagerWithStore creates a RiskManager whose daily order count is kept in store, so a
+// restart continues the count of the same UTC day instead of starting from 0
+func NewRiskManagerWithStore(limits *RiskLimits, client api.BinanceClient, store repository.DailyOrderCountStore, log logger.Logger) (RiskManager, error) {
+	rm := NewRiskManager(limits, client).(*riskManager)
+	rm.dailyStore = store
+	rm.logger = log
+
+	saved, err := store.Load()
+	if err != nil {
+		return nil, err
 	}
+	if saved != nil {
+		rm.dailyCount = *saved
+	}
+	return rm, nil
 }
 
-// ValidateOrder validates an order against risk limits
+// ValidateOrder validates an order against risk limits and reserves its notional against the
+// per-minute cap until the order is placed or released
 func (rm *riskManager) ValidateOrder(order *api.OrderRequest) error {
+	return rm.validateOrder(order, false)
+}
+
+// validateOrder validates an order against risk limits; protective exits close exposure, so the
+// notional cap never holds them back
+func (rm *riskManager) validateOrder(order *api.OrderRequest, protective bool) error {
 	if order == nil {
 		return errors.NewTradingError(
 			errors.ErrInvalidParameter,
@@ -71,12 +132,49 @@ func (rm *riskManager) ValidateOrder(order *api.OrderRequest) error {
 		)
 	}
 	
+	if order.Quantity > 0 && order.QuoteOrderQty > 0 {
+		return errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			"quantity and quote order quantity cannot both be set",
+			0,
+			nil,
+		)
+	}
+	if order.Quantity <= 0 && order.QuoteOrderQty <= 0 {
+		return errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			"quantity must be greater than 0",
+			0,
+			nil,
+		)
+	}
+	if order.QuoteOrderQty > 0 && order.Type != api.OrderTypeMarket {
+		return errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			"quote order quantity is only supported for market orders",
+			0,
+			nil,
+		)
+	}
+	if order.Type == api.OrderTypeLimit && order.Price <= 0 {
+		return errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			"price must be greater than 0",
+			0,
+			nil,
+		)
+	}
+	
 	rm.mu.RLock()
-	defer rm.mu.RUnlock()
+	maxOrderAmount := rm.limits.MaxOrderAmount
+	rm.mu.RUnlock()
 	
 	// Calculate order amount
 	var orderAmount float64
-	if order.Type == api.OrderTypeMarket {
+	if order.QuoteOrderQty > 0 {
+		// Quote-denominated orders state their amount directly
+		orderAmount = order.QuoteOrderQty
+	} else if order.Type == api.OrderTypeMarket {
 		// For market orders, we need to get current price
 		price, err := rm.client.GetPrice(order.Symbol)
 		if err != nil {
@@ -94,39 +192,129 @@ func (rm *riskManager) ValidateOrder(order *api.OrderRequest) error {
 	}
 	
 	// Check order amount limit
-	if orderAmount > rm.limits.MaxOrderAmount {
+	if orderAmount > maxOrderAmount {
 		return errors.NewTradingError(
 			errors.ErrRiskLimitExceeded,
-			fmt.Sprintf("order amount %.2f exceeds maximum limit %.2f", orderAmount, rm.limits.MaxOrderAmount),
+			fmt.Sprintf("order amount %.2f exceeds maximum limit %.2f", orderAmount, maxOrderAmount),
 			0,
 			nil,
 		)
 	}
 	
-	return nil
+	if protective {
+		return nil
+	}
+	return rm.reserveNotional(order, orderAmount)
 }
 
-// CheckDailyLimit checks if the daily order limit has been reached
-func (rm *riskManager) CheckDailyLimit() error {
-	rm.mu.RLock()
-	defer rm.mu.RUnlock()
+// reserveNotional checks the order against the notional traded and reserved in the last minute,
+// and reserves its notional when it fits. Holding the reservation under the same lock as the
+// check keeps concurrent orders from passing against the same capacity. In delay mode the
+// rejection carries how long until enough of the window rolls off.
+func (rm *riskManager) reserveNotional(order *api.OrderRequest, orderAmount float64) error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	
+	maxNotional := rm.limits.MaxNotionalPerMin
+	if maxNotional <= 0 {
+		return nil
+	}
+	
+	// A validated order validated again replaces its reservation
+	now := rm.now()
+	delete(rm.reservations, order)
+	windowNotional, wait := rm.notionalWindowUsage(now, orderAmount, maxNotional)
+	if windowNotional+orderAmount <= maxNotional {
+		rm.reservations[order] = orderRecord{timestamp: now, amount: orderAmount}
+		return nil
+	}
 	
-	// Get current time
-	now := time.Now()
-	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// An order larger than the whole cap can never fit in the window
+	if orderAmount > maxNotional {
+		return errors.NewTradingError(
+			errors.ErrRiskLimitExceeded,
+			fmt.Sprintf("order notional %.2f exceeds max notional per minute %.2f", orderAmount, maxNotional),
+			0,
+			nil,
+		)
+	}
 	
-	// Count orders created today
-	todayOrders := 0
+	err := errors.NewTradingError(
+		errors.ErrNotionalCapReached,
+		fmt.Sprintf("notional throughput limit reached: %.2f traded in the last minute + order %.2f exceeds %.2f per minute, retry in %s",
+			windowNotional, orderAmount, maxNotional, wait.Round(time.Second)),
+		0,
+		nil,
+	)
+	if rm.limits.NotionalCapMode == NotionalCapDelay {
+		err.RetryAfter = wait
+	}
+	return err
+}
+
+// notionalWindowUsage returns the notional traded and reserved in the current window and how
+// long until enough of it rolls off for the order to fit. Reservations older than the window
+// belong to orders that were never placed nor released and are dropped. The caller must hold
+// rm.mu for writing.
+func (rm *riskManager) notionalWindowUsage(now time.Time, orderAmount, maxNotional float64) (float64, time.Duration) {
+	cutoff := now.Add(-notionalWindow)
+	
+	var window []orderRecord
 	for _, record := range rm.orderHistory {
-		if record.timestamp.After(dayStart) || record.timestamp.Equal(dayStart) {
-			todayOrders++
+		if record.timestamp.After(cutoff) {
+			window = append(window, record)
+		}
+	}
+	for order, record := range rm.reservations {
+		if !record.timestamp.After(cutoff) {
+			delete(rm.reservations, order)
+			continue
 		}
+		window = append(window, record)
+	}
+	sort.SliceStable(window, func(i, j int) bool {
+		return window[i].timestamp.Before(window[j].timestamp)
+	})
+	
+	windowNotional := 0.0
+	for _, record := range window {
+		windowNotional += record.amount
 	}
 	
+	// The oldest records roll off first
+	var wait time.Duration
+	remaining := windowNotional
+	for _, record := range window {
+		if remaining+orderAmount <= maxNotional {
+			break
+		}
+		remaining -= record.amount
+		wait = record.timestamp.Add(notionalWindow).Sub(now)
+	}
+	
+	return windowNotional, wait
+}
+
+// releaseNotional gives back the notional reserved for an order that was not placed; orders
+// without a reservation are ignored
+func (rm *riskManager) releaseNotional(order *api.OrderRequest) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	
+	delete(rm.reservations, order)
+}
+
+// CheckDailyLimit checks if the daily order limit has been reached; days start at UTC midnight
+func (rm *riskManager) CheckDailyLimit() error {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	
+	todayOrders := rm.ordersToday(rm.now())
+	
 	// Check if limit exceeded
 	if todayOrders >= rm.limits.MaxDailyOrders {
 		return errors.NewTradingError(
-			errors.ErrRiskLimitExceeded,
+			errors.ErrDailyLimitReached,
 			fmt.Sprintf("daily order limit reached: %d/%d", todayOrders, rm.limits.MaxDailyOrders),
 			0,
 			nil,
@@ -174,6 +362,39 @@ func (rm *riskManager) CheckMinimumBalance(asset string) error {
 	return nil
 }
 
+// CheckAvailableBalance checks that the free balance of an asset covers the quantity an order sells
+func (rm *riskManager) CheckAvailableBalance(asset string, quantity float64) error {
+	if asset == "" {
+		return errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			"asset cannot be empty",
+			0,
+			nil,
+		)
+	}
+	
+	balance, err := rm.client.GetBalance(asset)
+	if err != nil {
+		return errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			fmt.Sprintf("failed to get balance for %s: %v", asset, err),
+			0,
+			err,
+		)
+	}
+	
+	if quantity > balance.Free {
+		return errors.NewTradingError(
+			errors.ErrInsufficientBalance,
+			fmt.Sprintf("quantity %.8f exceeds free balance %.8f for asset %s", quantity, balance.Free, asset),
+			0,
+			nil,
+		)
+	}
+	
+	return nil
+}
+
 // UpdateLimits updates the risk limits
 func (rm *riskManager) UpdateLimits(limits *RiskLimits) error {
 	if limits == nil {
@@ -210,6 +431,14 @@ func (rm *riskManager) UpdateLimits(limits *RiskLimits) error {
 			nil,
 		)
 	}
+	if limits.MaxNotionalPerMin < 0 {
+		return errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			"max notional per minute cannot be negative",
+			0,
+			nil,
+		)
+	}
 	
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
@@ -229,21 +458,77 @@ func (rm *riskManager) GetCurrentLimits() *RiskLimits {
 		MaxDailyOrders:    rm.limits.MaxDailyOrders,
 		MinBalanceReserve: rm.limits.MinBalanceReserve,
 		MaxAPICallsPerMin: rm.limits.MaxAPICallsPerMin,
+		MaxNotionalPerMin: rm.limits.MaxNotionalPerMin,
+		NotionalCapMode:   rm.limits.NotionalCapMode,
+	}
+}
+
+// GetDailyOrderStats returns the orders placed today (UTC) against the daily limit
+func (rm *riskManager) GetDailyOrderStats() *DailyOrderStats {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	
+	now := rm.now().UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return &DailyOrderStats{
+		Date:    now.Format(dailyOrderDateLayout),
+		Count:   rm.ordersToday(now),
+		Limit:   rm.limits.MaxDailyOrders,
+		ResetAt: dayStart.AddDate(0, 0, 1),
 	}
 }
 
+// ordersToday returns the orders counted for the UTC date of now; a count kept for an earlier
+// date no longer applies. The caller must hold rm.mu.
+func (rm *riskManager) ordersToday(now time.Time) int {
+	if rm.dailyCount.Date != now.UTC().Format(dailyOrderDateLayout) {
+		return 0
+	}
+	return rm.dailyCount.Count
+}
+
 // RecordOrder records an order for frequency tracking (internal method)
 func (rm *riskManager) RecordOrder(amount float64) {
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
 	
+	rm.recordOrder(amount)
+}
+
+// recordPlacedOrder records a placed order, replacing the notional reserved for it when it was
+// validated with the amount it actually traded
+func (rm *riskManager) recordPlacedOrder(order *api.OrderRequest, amount float64) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	
+	delete(rm.reservations, order)
+	rm.recordOrder(amount)
+}
+
+// recordOrder records an order in the notional history and the daily count; the caller must
+// hold rm.mu for writing
+func (rm *riskManager) recordOrder(amount float64) {
+	now := rm.now()
 	rm.orderHistory = append(rm.orderHistory, orderRecord{
-		timestamp: time.Now(),
+		timestamp: now,
 		amount:    amount,
 	})
 	
+	// The first order of a new UTC day starts its count again
+	rm.dailyCount = repository.DailyOrderCount{
+		Date:  now.UTC().Format(dailyOrderDateLayout),
+		Count: rm.ordersToday(now) + 1,
+	}
+	if err := rm.dailyStore.Save(&rm.dailyCount); err != nil && rm.logger != nil {
+		rm.logger.Error("Failed to save daily order count", map[string]interface{}{
+			"date":  rm.dailyCount.Date,
+			"count": rm.dailyCount.Count,
+			"error": err.Error(),
+		})
+	}
+	
 	// Clean up old records (older than 24 hours)
-	cutoff := time.Now().Add(-24 * time.Hour)
+	cutoff := now.Add(-24 * time.Hour)
 	newHistory := make([]orderRecord, 0)
 	for _, record := range rm.orderHistory {
 		if record.timestamp.After(cutoff) {
@@ -2,8 +2,13 @@ package service
 
 import (
 	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
 	"binance-trader/pkg/errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
@@ -71,3 +76,282 @@ func TestProperty_MinimumBalanceProtection(t *testing.T) {
 	
 	properties.TestingRun(t)
 }
+
+func newNotionalCapRiskManager(mode string, clock *time.Time) *riskManager {
+	rm := NewRiskManager(&RiskLimits{
+		MaxOrderAmount:    100000.0,
+		MaxDailyOrders:    1000,
+		MinBalanceReserve: 0,
+		MaxAPICallsPerMin: 1000,
+		MaxNotionalPerMin: 10000.0,
+		NotionalCapMode:   mode,
+	}, &mockBinanceClient{}).(*riskManager)
+	rm.now = func() time.Time { return *clock }
+	return rm
+}
+
+func limitOrder(side api.OrderSide, price, quantity float64) *api.OrderRequest {
+	return &api.OrderRequest{Symbol: "BTCUSDT", Side: side, Type: api.OrderTypeLimit, Price: price, Quantity: quantity}
+}
+
+func TestNotionalCap_AccumulatesAndRejects(t *testing.T) {
+	clock := time.Unix(1700000000, 0)
+	rm := newNotionalCapRiskManager(NotionalCapReject, &clock)
+
+	// Buys and sells both count toward throughput
+	buy := limitOrder(api.OrderSideBuy, 1000, 4)
+	if err := rm.ValidateOrder(buy); err != nil {
+		t.Fatalf("expected first order to pass, got %v", err)
+	}
+	rm.recordPlacedOrder(buy, 4000)
+	clock = clock.Add(10 * time.Second)
+	sell := limitOrder(api.OrderSideSell, 1000, 5)
+	if err := rm.ValidateOrder(sell); err != nil {
+		t.Fatalf("expected second order to pass, got %v", err)
+	}
+	rm.recordPlacedOrder(sell, 5000)
+
+	clock = clock.Add(10 * time.Second)
+	err := rm.ValidateOrder(limitOrder(api.OrderSideBuy, 1000, 2))
+	if err == nil {
+		t.Fatal("expected order pushing the window over the cap to be rejected")
+	}
+	tradingErr, ok := err.(*errors.TradingError)
+	if !ok || tradingErr.Type != errors.ErrNotionalCapReached {
+		t.Fatalf("expected notional cap error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "notional throughput limit reached") || !strings.Contains(err.Error(), "retry in 40s") {
+		t.Errorf("expected a clear throughput message with retry hint, got %q", err.Error())
+	}
+	if _, retry := errors.RetryAfter(err); retry {
+		t.Error("expected reject mode not to ask for a retry")
+	}
+
+	// An order that still fits is accepted
+	if err := rm.ValidateOrder(limitOrder(api.OrderSideSell, 1000, 1)); err != nil {
+		t.Errorf("expected order within remaining capacity to pass, got %v", err)
+	}
+
+	// An order larger than the whole cap is rejected outright, and retrying will not help
+	err = rm.ValidateOrder(limitOrder(api.OrderSideBuy, 1000, 11))
+	if err == nil || !strings.Contains(err.Error(), "exceeds max notional per minute") {
+		t.Errorf("expected oversized order to be rejected, got %v", err)
+	}
+	if errors.IsTemporary(err) {
+		t.Errorf("expected an oversized order to be a permanent rejection, got %v", err)
+	}
+}
+
+func TestNotionalCap_WindowRolls(t *testing.T) {
+	clock := time.Unix(1700000000, 0)
+	rm := newNotionalCapRiskManager(NotionalCapReject, &clock)
+
+	rm.RecordOrder(6000)
+	clock = clock.Add(30 * time.Second)
+	rm.RecordOrder(3000)
+
+	if err := rm.ValidateOrder(limitOrder(api.OrderSideBuy, 1000, 2)); err == nil {
+		t.Fatal("expected order to be rejected while the window is full")
+	}
+
+	// The first fill rolls off one minute after it was recorded
+	clock = clock.Add(30 * time.Second)
+	if err := rm.ValidateOrder(limitOrder(api.OrderSideBuy, 1000, 2)); err != nil {
+		t.Fatalf("expected order to pass after the window rolled, got %v", err)
+	}
+
+	// Disabling the cap accepts any throughput
+	limits := rm.GetCurrentLimits()
+	limits.MaxNotionalPerMin = 0
+	if err := rm.UpdateLimits(limits); err != nil {
+		t.Fatalf("failed to update limits: %v", err)
+	}
+	rm.RecordOrder(50000)
+	if err := rm.ValidateOrder(limitOrder(api.OrderSideSell, 1000, 9)); err != nil {
+		t.Errorf("expected disabled cap to accept order, got %v", err)
+	}
+}
+
+func TestNotionalCap_DelayModeReturnsRetryAfter(t *testing.T) {
+	clock := time.Unix(1700000000, 0)
+	rm := newNotionalCapRiskManager(NotionalCapDelay, &clock)
+
+	rm.RecordOrder(4000)
+	clock = clock.Add(20 * time.Second)
+	rm.RecordOrder(5000)
+	clock = clock.Add(10 * time.Second)
+
+	// Needs the first fill to roll off, 30s from now; the caller is told instead of blocked
+	order := limitOrder(api.OrderSideBuy, 1000, 3)
+	err := rm.ValidateOrder(order)
+	if wait, retry := errors.RetryAfter(err); !retry || wait != 30*time.Second {
+		t.Fatalf("expected a retry after 30s, got %v (%v)", wait, err)
+	}
+
+	clock = clock.Add(30 * time.Second)
+	if err := rm.ValidateOrder(order); err != nil {
+		t.Errorf("expected the order to pass once the window rolled, got %v", err)
+	}
+}
+
+func TestNotionalCap_ReservesUntilPlacedOrReleased(t *testing.T) {
+	clock := time.Unix(1700000000, 0)
+	rm := newNotionalCapRiskManager(NotionalCapReject, &clock)
+
+	// Two orders validated before either is placed cannot share the same capacity
+	first := limitOrder(api.OrderSideBuy, 1000, 6)
+	second := limitOrder(api.OrderSideBuy, 1000, 6)
+	if err := rm.ValidateOrder(first); err != nil {
+		t.Fatalf("expected first order to pass, got %v", err)
+	}
+	if err := rm.ValidateOrder(second); err == nil {
+		t.Fatal("expected second order to be rejected against the reserved notional")
+	}
+
+	// A failed order gives its reservation back
+	rm.releaseNotional(first)
+	if err := rm.ValidateOrder(second); err != nil {
+		t.Fatalf("expected second order to pass after the release, got %v", err)
+	}
+
+	// A placed order counts the notional it traded, not the reservation as well
+	rm.recordPlacedOrder(second, 5000)
+	if err := rm.ValidateOrder(limitOrder(api.OrderSideSell, 1000, 5)); err != nil {
+		t.Errorf("expected the remaining 5000 to be available, got %v", err)
+	}
+
+	// Reservations of orders never placed nor released expire with the window
+	clock = clock.Add(time.Minute)
+	if err := rm.ValidateOrder(limitOrder(api.OrderSideSell, 1000, 10)); err != nil {
+		t.Errorf("expected stale reservations to roll off, got %v", err)
+	}
+}
+
+func TestNotionalCap_ExemptsProtectiveExits(t *testing.T) {
+	clock := time.Unix(1700000000, 0)
+	rm := newNotionalCapRiskManager(NotionalCapReject, &clock)
+	rm.RecordOrder(10000)
+
+	if err := rm.ValidateOrder(limitOrder(api.OrderSideSell, 1000, 2)); err == nil {
+		t.Fatal("expected a new order to be rejected while the window is full")
+	}
+	if err := rm.validateOrder(limitOrder(api.OrderSideSell, 1000, 2), true); err != nil {
+		t.Errorf("expected a protective exit to pass the notional cap, got %v", err)
+	}
+}
+
+func TestValidateOrder_QuoteOrderQty(t *testing.T) {
+	priceLookups := 0
+	rm := NewRiskManager(&RiskLimits{MaxOrderAmount: 500, MaxDailyOrders: 100}, &mockBinanceClient{
+		getPriceFunc: func(symbol string) (*api.Price, error) {
+			priceLookups++
+			return &api.Price{Symbol: symbol, Price: 50000}, nil
+		},
+	})
+
+	// The quote amount is the order amount, without a price lookup
+	if err := rm.ValidateOrder(&api.OrderRequest{Symbol: "BTCUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeMarket, QuoteOrderQty: 500}); err != nil {
+		t.Errorf("expected quote order within the limit to pass, got %v", err)
+	}
+	err := rm.ValidateOrder(&api.OrderRequest{Symbol: "BTCUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeMarket, QuoteOrderQty: 501})
+	if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrRiskLimitExceeded {
+		t.Errorf("expected quote order over the limit to be rejected, got %v", err)
+	}
+	if priceLookups != 0 {
+		t.Errorf("expected no price lookups for quote orders, got %d", priceLookups)
+	}
+
+	for _, order := range []*api.OrderRequest{
+		{Symbol: "BTCUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeMarket, Quantity: 0.001, QuoteOrderQty: 50},
+		{Symbol: "BTCUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeLimit, Price: 50000, QuoteOrderQty: 50},
+	} {
+		err := rm.ValidateOrder(order)
+		if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrInvalidParameter {
+			t.Errorf("expected %+v to be rejected as invalid, got %v", order, err)
+		}
+	}
+}
+
+// newDailyLimitRiskManager creates a risk manager allowing two orders a day on a controllable clock
+func newDailyLimitRiskManager(t *testing.T, store repository.DailyOrderCountStore, clock *time.Time) *riskManager {
+	t.Helper()
+	rm, err := NewRiskManagerWithStore(&RiskLimits{MaxOrderAmount: 100000, MaxDailyOrders: 2}, &mockBinanceClient{}, store, &mockLogger{})
+	if err != nil {
+		t.Fatalf("NewRiskManagerWithStore() error = %v", err)
+	}
+	manager := rm.(*riskManager)
+	manager.now = func() time.Time { return *clock }
+	return manager
+}
+
+func TestDailyLimit_ResetsAtUTCMidnight(t *testing.T) {
+	clock := time.Date(2024, 3, 1, 23, 59, 59, 0, time.UTC)
+	rm := newDailyLimitRiskManager(t, repository.NewMemoryDailyOrderCountStore(), &clock)
+
+	rm.RecordOrder(100)
+	rm.RecordOrder(100)
+	if err := rm.CheckDailyLimit(); err == nil {
+		t.Fatal("expected the daily limit to be reached")
+	}
+	stats := rm.GetDailyOrderStats()
+	wantReset := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	if stats.Date != "2024-03-01" || stats.Count != 2 || stats.Limit != 2 || !stats.ResetAt.Equal(wantReset) {
+		t.Errorf("stats before midnight = %+v, want 2 of 2 on 2024-03-01 resetting at %v", stats, wantReset)
+	}
+
+	// A second later it is a new UTC day, whatever the local time zone
+	clock = wantReset.In(time.FixedZone("UTC-5", -5*3600))
+	if err := rm.CheckDailyLimit(); err != nil {
+		t.Fatalf("expected the limit to reset at UTC midnight, got %v", err)
+	}
+	stats = rm.GetDailyOrderStats()
+	if stats.Date != "2024-03-02" || stats.Count != 0 || !stats.ResetAt.Equal(wantReset.AddDate(0, 0, 1)) {
+		t.Errorf("stats after midnight = %+v, want 0 on 2024-03-02", stats)
+	}
+
+	rm.RecordOrder(100)
+	if stats := rm.GetDailyOrderStats(); stats.Count != 1 {
+		t.Errorf("count after the first order of the day = %d, want 1", stats.Count)
+	}
+}
+
+func TestDailyLimit_ContinuesAcrossRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "daily_orders.json")
+	clock := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	first := newDailyLimitRiskManager(t, repository.NewFileDailyOrderCountStore(path), &clock)
+	first.RecordOrder(100)
+	first.RecordOrder(100)
+
+	// A restart on the same UTC day keeps the limit reached
+	restarted := newDailyLimitRiskManager(t, repository.NewFileDailyOrderCountStore(path), &clock)
+	if stats := restarted.GetDailyOrderStats(); stats.Count != 2 {
+		t.Errorf("count after restart = %d, want 2", stats.Count)
+	}
+	if err := restarted.CheckDailyLimit(); err == nil {
+		t.Error("expected the daily limit to stay reached after a restart")
+	}
+
+	// A restart the next day starts from 0 and saves the new day
+	clock = clock.Add(14 * time.Hour)
+	nextDay := newDailyLimitRiskManager(t, repository.NewFileDailyOrderCountStore(path), &clock)
+	if err := nextDay.CheckDailyLimit(); err != nil {
+		t.Fatalf("expected a new day after restart, got %v", err)
+	}
+	nextDay.RecordOrder(100)
+	saved, err := repository.NewFileDailyOrderCountStore(path).Load()
+	if err != nil || saved == nil || saved.Date != "2024-03-02" || saved.Count != 1 {
+		t.Errorf("saved count = %+v, %v; want 1 on 2024-03-02", saved, err)
+	}
+}
+
+func TestNewRiskManagerWithStore_LoadError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daily_orders.json")
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewRiskManagerWithStore(&RiskLimits{MaxDailyOrders: 2}, &mockBinanceClient{},
+		repository.NewFileDailyOrderCountStore(path), &mockLogger{}); err == nil {
+		t.Error("expected an error for an unreadable daily order count")
+	}
+}
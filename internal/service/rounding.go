@@ -0,0 +1,122 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RoundingMode represents how quantities and prices are aligned to step/tick sizes
+type RoundingMode string
+
+const (
+	// RoundingModeTruncate rounds toward zero (default)
+	RoundingModeTruncate RoundingMode = "truncate"
+	// RoundingModeNearest rounds half up to the nearest step
+	RoundingModeNearest RoundingMode = "nearest"
+	// RoundingModeConservative rounds in the direction that never increases risk
+	RoundingModeConservative RoundingMode = "conservative"
+)
+
+// roundingEpsilon absorbs floating point error when dividing by a step size
+const roundingEpsilon = 1e-9
+
+// ParseRoundingMode parses a rounding mode, defaulting to truncate when empty
+func ParseRoundingMode(value string) (RoundingMode, error) {
+	switch RoundingMode(strings.ToLower(strings.TrimSpace(value))) {
+	case "", RoundingModeTruncate:
+		return RoundingModeTruncate, nil
+	case RoundingModeNearest:
+		return RoundingModeNearest, nil
+	case RoundingModeConservative:
+		return RoundingModeConservative, nil
+	default:
+		return "", fmt.Errorf("invalid rounding mode: %s", value)
+	}
+}
+
+// Rounder aligns order quantities and prices to exchange step and tick sizes
+type Rounder struct {
+	mode RoundingMode
+}
+
+// NewRounder creates a new rounder using the given mode
+func NewRounder(mode RoundingMode) *Rounder {
+	if mode == "" {
+		mode = RoundingModeTruncate
+	}
+	return &Rounder{mode: mode}
+}
+
+// Mode returns the rounding mode in use
+func (r *Rounder) Mode() RoundingMode {
+	return r.mode
+}
+
+// RoundQuantity aligns a quantity to the step size.
+// Conservative mode always rounds down so an order never exceeds the requested size.
+func (r *Rounder) RoundQuantity(quantity, stepSize float64, side api.OrderSide) float64 {
+	switch r.mode {
+	case RoundingModeNearest:
+		return roundToStep(quantity, stepSize, roundHalfUp)
+	default:
+		return roundToStep(quantity, stepSize, roundDown)
+	}
+}
+
+// RoundPrice aligns a price to the tick size.
+// Conservative mode rounds buy prices down and sell prices up so the fill is never worse than requested.
+func (r *Rounder) RoundPrice(price, tickSize float64, side api.OrderSide) float64 {
+	switch r.mode {
+	case RoundingModeNearest:
+		return roundToStep(price, tickSize, roundHalfUp)
+	case RoundingModeConservative:
+		if side == api.OrderSideSell {
+			return roundToStep(price, tickSize, roundUp)
+		}
+		return roundToStep(price, tickSize, roundDown)
+	default:
+		return roundToStep(price, tickSize, roundDown)
+	}
+}
+
+// roundingDirection selects how a fractional step count is resolved
+type roundingDirection int
+
+const (
+	roundDown roundingDirection = iota
+	roundUp
+	roundHalfUp
+)
+
+// roundToStep rounds a value to a multiple of step in the given direction
+func roundToStep(value, step float64, direction roundingDirection) float64 {
+	if step <= 0 || value <= 0 {
+		return value
+	}
+
+	steps := value / step
+	switch direction {
+	case roundUp:
+		steps = math.Ceil(steps - roundingEpsilon)
+	case roundHalfUp:
+		steps = math.Floor(steps + 0.5 + roundingEpsilon)
+	default:
+		steps = math.Floor(steps + roundingEpsilon)
+	}
+
+	// Trim floating point noise to the precision of the step
+	precision := math.Pow(10, float64(stepDecimals(step)))
+	return math.Round(steps*step*precision) / precision
+}
+
+// stepDecimals returns the number of decimal places in a step size
+func stepDecimals(step float64) int {
+	str := strconv.FormatFloat(step, 'f', -1, 64)
+	if idx := strings.IndexByte(str, '.'); idx >= 0 {
+		return len(str) - idx - 1
+	}
+	return 0
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"testing"
+)
+
+func TestParseRoundingMode(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    RoundingMode
+		expectError bool
+	}{
+		{"", RoundingModeTruncate, false},
+		{"truncate", RoundingModeTruncate, false},
+		{"NEAREST", RoundingModeNearest, false},
+		{"conservative", RoundingModeConservative, false},
+		{"ceil", "", true},
+	}
+
+	for _, tt := range tests {
+		mode, err := ParseRoundingMode(tt.input)
+		if tt.expectError {
+			if err == nil {
+				t.Errorf("ParseRoundingMode(%q) expected error", tt.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseRoundingMode(%q) unexpected error: %v", tt.input, err)
+		}
+		if mode != tt.expected {
+			t.Errorf("ParseRoundingMode(%q) = %s, expected %s", tt.input, mode, tt.expected)
+		}
+	}
+}
+
+func TestRounder_RoundQuantity(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     RoundingMode
+		quantity float64
+		step     float64
+		side     api.OrderSide
+		expected float64
+	}{
+		{"truncate exact multiple", RoundingModeTruncate, 0.3, 0.1, api.OrderSideBuy, 0.3},
+		{"truncate below midpoint", RoundingModeTruncate, 0.00123, 0.001, api.OrderSideBuy, 0.001},
+		{"truncate at midpoint", RoundingModeTruncate, 0.0015, 0.001, api.OrderSideBuy, 0.001},
+		{"truncate above midpoint", RoundingModeTruncate, 0.0019, 0.001, api.OrderSideSell, 0.001},
+		{"nearest exact multiple", RoundingModeNearest, 0.3, 0.1, api.OrderSideBuy, 0.3},
+		{"nearest below midpoint", RoundingModeNearest, 0.00123, 0.001, api.OrderSideBuy, 0.001},
+		{"nearest at midpoint", RoundingModeNearest, 0.0015, 0.001, api.OrderSideBuy, 0.002},
+		{"nearest above midpoint", RoundingModeNearest, 0.0019, 0.001, api.OrderSideSell, 0.002},
+		{"conservative buy rounds down", RoundingModeConservative, 0.0019, 0.001, api.OrderSideBuy, 0.001},
+		{"conservative sell rounds down", RoundingModeConservative, 0.0019, 0.001, api.OrderSideSell, 0.001},
+		{"conservative exact multiple", RoundingModeConservative, 1.5, 0.5, api.OrderSideBuy, 1.5},
+		{"zero step unchanged", RoundingModeTruncate, 0.123, 0, api.OrderSideBuy, 0.123},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewRounder(tt.mode).RoundQuantity(tt.quantity, tt.step, tt.side)
+			if got != tt.expected {
+				t.Errorf("RoundQuantity(%v, %v) = %v, expected %v", tt.quantity, tt.step, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRounder_RoundPrice(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     RoundingMode
+		price    float64
+		tick     float64
+		side     api.OrderSide
+		expected float64
+	}{
+		{"truncate exact multiple", RoundingModeTruncate, 50000.10, 0.01, api.OrderSideBuy, 50000.10},
+		{"truncate fractional", RoundingModeTruncate, 50000.129, 0.01, api.OrderSideSell, 50000.12},
+		{"truncate at midpoint", RoundingModeTruncate, 50000.125, 0.01, api.OrderSideBuy, 50000.12},
+		{"nearest at midpoint", RoundingModeNearest, 100.25, 0.5, api.OrderSideBuy, 100.5},
+		{"nearest below midpoint", RoundingModeNearest, 100.2, 0.5, api.OrderSideSell, 100.0},
+		{"nearest exact multiple", RoundingModeNearest, 100.5, 0.5, api.OrderSideSell, 100.5},
+		{"conservative buy rounds down", RoundingModeConservative, 100.4, 0.5, api.OrderSideBuy, 100.0},
+		{"conservative sell rounds up", RoundingModeConservative, 100.1, 0.5, api.OrderSideSell, 100.5},
+		{"conservative sell at midpoint", RoundingModeConservative, 100.25, 0.5, api.OrderSideSell, 100.5},
+		{"conservative sell exact multiple", RoundingModeConservative, 0.3, 0.1, api.OrderSideSell, 0.3},
+		{"conservative buy exact multiple", RoundingModeConservative, 0.3, 0.1, api.OrderSideBuy, 0.3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewRounder(tt.mode).RoundPrice(tt.price, tt.tick, tt.side)
+			if got != tt.expected {
+				t.Errorf("RoundPrice(%v, %v) = %v, expected %v", tt.price, tt.tick, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewRounder_DefaultsToTruncate(t *testing.T) {
+	if mode := NewRounder("").Mode(); mode != RoundingModeTruncate {
+		t.Errorf("Expected default mode truncate, got %s", mode)
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"fmt"
+)
+
+const (
+	// DefaultRSIInterval is the kline interval of RSI conditions that do not set one
+	DefaultRSIInterval = "1h"
+
+	// maxRSIPeriod keeps the kline request within the exchange's limit of 1000
+	maxRSIPeriod = 300
+)
+
+// rsiKlines caches the closes of the closed klines of one symbol and interval until the
+// candle that was forming when they were fetched closes
+type rsiKlines struct {
+	closes    []float64 // Oldest first
+	limit     int       // Klines requested
+	nextClose int64     // Close time (ms) of the candle forming at fetch time
+}
+
+// RelativeStrengthIndex returns Wilder's Relative Strength Index of closes ordered oldest
+// first. The first average gain and loss cover the first period price changes; every later
+// change smooths them as (average × (period-1) + change) / period. A series without losses
+// has an RSI of 100, a flat one 50.
+func RelativeStrengthIndex(closes []float64, period int) (float64, error) {
+	if period <= 0 {
+		return 0, fmt.Errorf("RSI period must be greater than 0")
+	}
+	if len(closes) < period+1 {
+		return 0, fmt.Errorf("RSI(%d) needs %d closes, got %d", period, period+1, len(closes))
+	}
+
+	gainLoss := func(i int) (float64, float64) {
+		change := closes[i] - closes[i-1]
+		if change > 0 {
+			return change, 0
+		}
+		return 0, -change
+	}
+
+	avgGain, avgLoss := 0.0, 0.0
+	for i := 1; i <= period; i++ {
+		gain, loss := gainLoss(i)
+		avgGain += gain
+		avgLoss += loss
+	}
+	avgGain /= float64(period)
+	avgLoss /= float64(period)
+
+	for i := period + 1; i < len(closes); i++ {
+		gain, loss := gainLoss(i)
+		avgGain = (avgGain*float64(period-1) + gain) / float64(period)
+		avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
+	}
+
+	if avgLoss == 0 {
+		if avgGain == 0 {
+			return 50, nil
+		}
+		return 100, nil
+	}
+	return 100 - 100/(1+avgGain/avgLoss), nil
+}
+
+// validateRSICondition checks the period and kline interval of an RSI condition
+func validateRSICondition(condition *repository.TriggerCondition) error {
+	if condition.Period <= 0 || condition.Period > maxRSIPeriod {
+		return fmt.Errorf("RSI period must be between 1 and %d", maxRSIPeriod)
+	}
+	if condition.Interval != "" {
+		if _, ok := api.KlineIntervalDuration(condition.Interval); !ok {
+			return fmt.Errorf("unsupported RSI kline interval %q", condition.Interval)
+		}
+	}
+	if condition.Value < 0 || condition.Value > 100 {
+		return fmt.Errorf("RSI threshold must be between 0 and 100")
+	}
+	return nil
+}
+
+// rsiValue returns the RSI of a condition over the closed klines of its interval followed by
+// the current price, so the value moves with the candle still forming
+func (me *MonitoringEngine) rsiValue(symbol string, condition *repository.TriggerCondition, price float64) (float64, error) {
+	closes, err := me.closedKlineCloses(symbol, rsiInterval(condition), condition.Period)
+	if err != nil {
+		return 0, err
+	}
+	return RelativeStrengthIndex(append(closes, price), condition.Period)
+}
+
+// closedKlineCloses returns the closes of the newest closed klines of a symbol, at least
+// period of them. They are fetched once per candle: the cache is kept until the candle that
+// was forming at fetch time closes.
+func (me *MonitoringEngine) closedKlineCloses(symbol, interval string, period int) ([]float64, error) {
+	key := symbol + "|" + interval
+	nowMs := me.now().UnixMilli()
+	// Extra candles let Wilder smoothing settle; one more covers the candle still forming
+	limit := 3*period + 1
+
+	me.mu.RLock()
+	cached, ok := me.klineCache[key]
+	me.mu.RUnlock()
+	if ok && nowMs < cached.nextClose && cached.limit >= limit {
+		return append([]float64(nil), cached.closes...), nil
+	}
+
+	klines, err := me.marketDataService.GetHistoricalData(symbol, interval, limit)
+	me.recordAPIResult(err)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &rsiKlines{limit: limit}
+	for _, kline := range klines {
+		if kline.CloseTime < nowMs {
+			entry.closes = append(entry.closes, kline.Close)
+		} else if entry.nextClose == 0 {
+			entry.nextClose = kline.CloseTime
+		}
+	}
+	if entry.nextClose == 0 {
+		// No forming candle was returned; fetch again one interval later
+		duration, _ := api.KlineIntervalDuration(interval)
+		entry.nextClose = nowMs + duration.Milliseconds()
+	}
+	if len(entry.closes) < period {
+		return nil, fmt.Errorf("not enough %s klines for RSI(%d) of %s: got %d closed", interval, period, symbol, len(entry.closes))
+	}
+
+	me.mu.Lock()
+	me.klineCache[key] = entry
+	me.mu.Unlock()
+
+	return append([]float64(nil), entry.closes...), nil
+}
+
+// rsiInterval returns the kline interval of an RSI condition
+func rsiInterval(condition *repository.TriggerCondition) string {
+	if condition.Interval == "" {
+		return DefaultRSIInterval
+	}
+	return condition.Interval
+}
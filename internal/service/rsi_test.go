@@ -0,0 +1,227 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+// rsiMarketDataService serves hourly klines closing at the given prices, the last one still
+// forming at now, and counts the kline requests
+type rsiMarketDataService struct {
+	mockMarketDataService
+	closes  []float64
+	now     *time.Time
+	fetches int
+}
+
+func (m *rsiMarketDataService) GetHistoricalData(symbol string, interval string, limit int) ([]*api.Kline, error) {
+	m.fetches++
+	// The candle forming at now closes at the end of its hour
+	formingClose := m.now.Truncate(time.Hour).Add(time.Hour).UnixMilli() - 1
+	klines := make([]*api.Kline, 0, len(m.closes))
+	for i, close := range m.closes {
+		closeTime := formingClose - int64(len(m.closes)-1-i)*time.Hour.Milliseconds()
+		klines = append(klines, &api.Kline{OpenTime: closeTime + 1 - time.Hour.Milliseconds(), Close: close, CloseTime: closeTime})
+	}
+	if len(klines) > limit {
+		klines = klines[len(klines)-limit:]
+	}
+	return klines, nil
+}
+
+func newRSIOrder(id string, operator repository.ComparisonOperator, value float64, period int) *repository.ConditionalOrder {
+	return &repository.ConditionalOrder{
+		OrderID:  id,
+		Symbol:   "BTCUSDT",
+		Side:     api.OrderSideBuy,
+		Type:     api.OrderTypeMarket,
+		Quantity: 1.0,
+		TriggerCondition: &repository.TriggerCondition{
+			Type:     repository.TriggerTypeRSI,
+			Operator: operator,
+			Value:    value,
+			Period:   period,
+			Interval: "1h",
+		},
+		Status:    repository.ConditionalOrderStatusPending,
+		CreatedAt: time.Now().Unix(),
+	}
+}
+
+func TestRelativeStrengthIndex(t *testing.T) {
+	closes := []float64{44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08,
+		45.89, 46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64}
+
+	// The first value averages the first 14 changes, later ones are Wilder-smoothed
+	for _, tt := range []struct {
+		count int
+		want  float64
+	}{{15, 70.4641}, {20, 57.9150}} {
+		rsi, err := RelativeStrengthIndex(closes[:tt.count], 14)
+		if err != nil || math.Abs(rsi-tt.want) > 1e-3 {
+			t.Errorf("RSI(14) of %d closes = %v, %v; want %v", tt.count, rsi, err, tt.want)
+		}
+	}
+
+	if rsi, _ := RelativeStrengthIndex([]float64{10, 10, 10}, 2); rsi != 50 {
+		t.Errorf("RSI of a flat series = %v, want 50", rsi)
+	}
+	if rsi, _ := RelativeStrengthIndex([]float64{10, 9, 8, 7}, 3); rsi != 0 {
+		t.Errorf("RSI of a falling series = %v, want 0", rsi)
+	}
+	if _, err := RelativeStrengthIndex(closes[:14], 14); err == nil {
+		t.Error("expected an error with fewer than period+1 closes")
+	}
+	if _, err := RelativeStrengthIndex(closes, 0); err == nil {
+		t.Error("expected an error for a zero period")
+	}
+}
+
+// Feature: binance-auto-trading, RSI trigger
+// For any monotonically rising price series, the RSI approaches 100 and an RSI >= 70 condition triggers
+func TestProperty_RSIRisingPricesTrigger(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("rising prices push the RSI to 100 and trigger RSI >= 70", prop.ForAll(
+		func(start, step float64, period int) bool {
+			closes := make([]float64, 3*period+1)
+			for i := range closes {
+				closes[i] = start + step*float64(i)
+			}
+
+			rsi, err := RelativeStrengthIndex(closes, period)
+			if err != nil || rsi < 99.99 {
+				return false
+			}
+
+			now := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+			market := &rsiMarketDataService{
+				mockMarketDataService: mockMarketDataService{prices: map[string]float64{"BTCUSDT": closes[len(closes)-1] + step}},
+				closes:                closes,
+				now:                   &now,
+			}
+			repo := repository.NewMemoryConditionalOrderRepository()
+			engine := NewMonitoringEngine(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+				&mockTradingService{}, market, &mockStopLossService{}, &mockLogger{}, nil)
+			engine.now = func() time.Time { return now }
+
+			order := newRSIOrder("rsi-rising", repository.OperatorGreaterEqual, 70, period)
+			repo.Save(order)
+			engine.processOrder(order)
+
+			updated, err := repo.FindByID(order.OrderID)
+			return err == nil && updated.Status == repository.ConditionalOrderStatusExecuted
+		},
+		gen.Float64Range(1, 100000),
+		gen.Float64Range(0.01, 1000),
+		gen.IntRange(2, 30),
+	))
+
+	properties.TestingRun(t)
+}
+
+func TestMonitoringEngine_RSIKlineCache(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 5, 0, 0, time.UTC)
+	// Falling closes keep the RSI low, so the RSI >= 70 order stays pending
+	market := &rsiMarketDataService{
+		mockMarketDataService: mockMarketDataService{prices: map[string]float64{"BTCUSDT": 90}},
+		closes:                []float64{110, 108, 109, 105, 103, 104, 100},
+		now:                   &now,
+	}
+	repo := repository.NewMemoryConditionalOrderRepository()
+	engine := NewMonitoringEngine(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+		&mockTradingService{}, market, &mockStopLossService{}, &mockLogger{}, nil)
+	engine.now = func() time.Time { return now }
+
+	order := newRSIOrder("rsi-cache", repository.OperatorGreaterEqual, 70, 2)
+	repo.Save(order)
+
+	// Ticks within the same candle reuse the fetched klines
+	for i := 0; i < 5; i++ {
+		engine.processOrder(order)
+		now = now.Add(2 * time.Second)
+	}
+	if market.fetches != 1 {
+		t.Fatalf("kline fetches within one candle = %d, want 1", market.fetches)
+	}
+
+	// The closed candles plus the current price: the forming candle never counts as closed
+	rsi, err := engine.rsiValue("BTCUSDT", order.TriggerCondition, 90)
+	if err != nil {
+		t.Fatalf("rsiValue() error = %v", err)
+	}
+	if want, _ := RelativeStrengthIndex([]float64{110, 108, 109, 105, 103, 104, 90}, 2); math.Abs(rsi-want) > 1e-9 {
+		t.Errorf("rsiValue() = %v, want %v", rsi, want)
+	}
+
+	// Once the forming candle closes, the klines are fetched again
+	now = time.Date(2024, 3, 1, 11, 0, 1, 0, time.UTC)
+	market.closes = append(market.closes, 130)
+	engine.processOrder(order)
+	if market.fetches != 2 {
+		t.Errorf("kline fetches after the candle closed = %d, want 2", market.fetches)
+	}
+	if updated, _ := repo.FindByID(order.OrderID); updated.Status != repository.ConditionalOrderStatusPending {
+		t.Errorf("order status = %s, want PENDING while the RSI stays below 70", updated.Status)
+	}
+}
+
+func TestConditionalOrderService_ValidateRSICondition(t *testing.T) {
+	svc := NewConditionalOrderService(repository.NewMemoryConditionalOrderRepository(), repository.NewMemoryStopOrderRepository(),
+		NewTriggerEngine(), &mockTradingService{}, &mockMarketDataService{}, &mockStopLossService{}, &mockLogger{})
+
+	rsi := func(period int, interval string, value float64) *repository.TriggerCondition {
+		return &repository.TriggerCondition{Type: repository.TriggerTypeRSI, Operator: repository.OperatorLessEqual, Value: value, Period: period, Interval: interval}
+	}
+	tests := []struct {
+		name      string
+		condition *repository.TriggerCondition
+		wantErr   bool
+	}{
+		{"valid", rsi(14, "15m", 30), false},
+		{"default interval", rsi(14, "", 30), false},
+		{"no period", rsi(0, "1h", 30), true},
+		{"period too long", rsi(maxRSIPeriod+1, "1h", 30), true},
+		{"unknown interval", rsi(14, "7m", 30), true},
+		{"threshold above 100", rsi(14, "1h", 101), true},
+		{"inside a composite", &repository.TriggerCondition{
+			CompositeType: repository.LogicAND,
+			SubConditions: []*repository.TriggerCondition{
+				rsi(14, "1h", 30),
+				{Type: repository.TriggerTypePrice, Operator: repository.OperatorLessThan, Value: 50000},
+			},
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateConditionalOrder(&repository.ConditionalOrderRequest{
+				Symbol:           "BTCUSDT",
+				Side:             api.OrderSideBuy,
+				Type:             api.OrderTypeMarket,
+				Quantity:         0.01,
+				TriggerCondition: tt.condition,
+				AllowDuplicate:   true,
+			})
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("CreateConditionalOrder() error = %v", err)
+				}
+				return
+			}
+			if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrInvalidTriggerCondition {
+				t.Errorf("CreateConditionalOrder() error = %v, want ErrInvalidTriggerCondition", err)
+			}
+		})
+	}
+}
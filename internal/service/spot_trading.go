@@ -2,31 +2,96 @@ package service
 
 import (
 	"binance-trader/internal/api"
+	"binance-trader/pkg/metrics"
 	"binance-trader/internal/repository"
 	"binance-trader/pkg/errors"
 	"binance-trader/pkg/logger"
+	"context"
+	"fmt"
+	"math"
 	"strings"
+	"time"
 )
 
 // SpotTradingService defines the interface for spot trading operations
 type SpotTradingService interface {
 	// Order creation
 	PlaceMarketBuyOrder(symbol string, quantity float64) (*api.Order, error)
+	PlaceMarketBuyOrderByQuote(symbol string, quoteAmount float64) (*api.Order, error)
 	PlaceMarketSellOrder(symbol string, quantity float64) (*api.Order, error)
 	PlaceLimitSellOrder(symbol string, price, quantity float64) (*api.Order, error)
+	PlaceLimitBuyOrder(symbol string, price, quantity float64) (*api.Order, error)
+
+	// Order lists (OCO): the exchange places both legs atomically and cancels them together
+	PlaceOCOOrder(symbol string, side api.OrderSide, quantity, price, stopPrice, stopLimitPrice float64) (*api.OCOResponse, error)
+	CancelOCOOrder(symbol string, orderListID int64) error
+
+	// ExecuteTWAP splits a market order into slices equal child orders placed evenly over
+	// duration; cancelling ctx stops it and returns the partial execution with an error
+	ExecuteTWAP(ctx context.Context, symbol string, side api.OrderSide, totalQty float64, duration time.Duration, slices int) (*TWAPExecution, error)
+
+	// SubmitOrderIntent places an order under a client order ID, returning the existing order
+	// instead when replay protection finds it already executed before a restart
+	SubmitOrderIntent(intent *OrderIntent) (*api.Order, error)
 
 	// Order management
 	CancelOrder(orderID int64) error
+	// CancelAllOrders cancels every open order of a symbol and returns how many were cancelled;
+	// when some fail, the count of the others comes with a *api.BulkCancelError
+	CancelAllOrders(symbol string) (int, error)
 	GetOrderStatus(orderID int64) (*OrderStatus, error)
 	GetActiveOrders() ([]*api.Order, error)
+
+	// SubscribeOrderUpdates calls handler with every order update the exchange pushes: fills,
+	// partial fills and cancellations. It needs the user data stream set with SetUserDataStream
+	// and returns a function dropping the subscription.
+	SubscribeOrderUpdates(handler func(*api.Order)) (func(), error)
+
+	// GetAllBalances returns the free and locked balance of every asset the account holds
+	GetAllBalances() ([]api.Balance, error)
+
+	// Diagnostics
+	GetOrderLifecycle(symbol string, orderID int64) (*OrderLifecycle, error)
+
+	// SetSymbolGuard sets the optional guard that pauses new orders for repeatedly failing symbols
+	SetSymbolGuard(guard SymbolFailureGuard)
+
+	// SetReplayProtection sets the optional post-restart check used by SubmitOrderIntent
+	SetReplayProtection(protection ReplayProtection)
+
+	// SetUserDataStream sets the optional stream of order updates that keeps the order
+	// repository up to date without polling
+	SetUserDataStream(stream api.UserDataStream) error
+
+	// SetSlippageProtection rejects market orders whose estimated fill deviates from the current
+	// price by more than maxSlippagePercent; 0 disables the check
+	SetSlippageProtection(marketData MarketDataService, maxSlippagePercent float64)
+
+	// SetSymbolFilter rounds orders to the step and tick size of their symbol and rejects the
+	// ones its exchange filters would refuse before they are sent; nil disables the check
+	SetSymbolFilter(filter *SymbolFilter)
+
+	// SetMetrics sets the sink order metrics are recorded in; a new service records them nowhere
+	SetMetrics(sink *metrics.Sink)
 }
 
 // spotTradingService implements the SpotTradingService interface
 type spotTradingService struct {
-	client    api.SpotClient
-	riskMgr   RiskManager
-	orderRepo repository.OrderRepository
-	logger    logger.Logger
+	client      api.SpotClient
+	riskMgr     RiskManager
+	orderRepo   repository.OrderRepository
+	logger      logger.Logger
+	symbolGuard SymbolFailureGuard
+	replay      ReplayProtection
+
+	slippageMarketData MarketDataService
+	maxSlippagePercent float64
+	symbolFilter       *SymbolFilter
+
+	userStream api.UserDataStream
+	metrics    *metrics.Sink
+
+	after func(time.Duration) <-chan time.Time
 }
 
 // NewSpotTradingService creates a new spot trading service instance
@@ -41,7 +106,183 @@ func NewSpotTradingService(
 		riskMgr:   riskMgr,
 		orderRepo: orderRepo,
 		logger:    log,
+		metrics:   metrics.Noop(),
+		after:     time.After,
+	}
+}
+
+// SetSymbolGuard sets the optional symbol failure guard
+func (s *spotTradingService) SetSymbolGuard(guard SymbolFailureGuard) {
+	s.symbolGuard = guard
+}
+
+// SetReplayProtection sets the optional replay protection
+func (s *spotTradingService) SetReplayProtection(protection ReplayProtection) {
+	s.replay = protection
+}
+
+// SetSlippageProtection sets the optional market order slippage check
+func (s *spotTradingService) SetSlippageProtection(marketData MarketDataService, maxSlippagePercent float64) {
+	s.slippageMarketData = marketData
+	s.maxSlippagePercent = maxSlippagePercent
+}
+
+// SetSymbolFilter sets the optional exchange filter check
+func (s *spotTradingService) SetSymbolFilter(filter *SymbolFilter) {
+	s.symbolFilter = filter
+}
+
+// SetMetrics sets the sink order metrics are recorded in
+func (s *spotTradingService) SetMetrics(sink *metrics.Sink) {
+	s.metrics = sink
+}
+
+// applySymbolFilter rounds an order to the filters of its symbol and rejects it when the
+// exchange would; market orders are valued at the current price
+func (s *spotTradingService) applySymbolFilter(req *api.OrderRequest) error {
+	if s.symbolFilter == nil {
+		return nil
+	}
+	return s.symbolFilter.Apply(req, func() (float64, error) {
+		price, err := s.client.GetPrice(req.Symbol)
+		if err != nil {
+			return 0, err
+		}
+		return price.Price, nil
+	})
+}
+
+// ExecuteTWAP places a TWAP order as a series of market orders
+func (s *spotTradingService) ExecuteTWAP(ctx context.Context, symbol string, side api.OrderSide, totalQty float64, duration time.Duration, slices int) (*TWAPExecution, error) {
+	return executeTWAP(ctx, s, s.after, s.logger, symbol, side, totalQty, duration, slices)
+}
+
+// validateOrder runs the risk checks of an order; protective exits close exposure, so the
+// notional cap never holds them back
+func (s *spotTradingService) validateOrder(req *api.OrderRequest, protective bool) error {
+	if rm, ok := s.riskMgr.(*riskManager); ok {
+		return rm.validateOrder(req, protective)
+	}
+	return s.riskMgr.ValidateOrder(req)
+}
+
+// recordPlacedOrder counts a placed order in the risk manager, in place of the notional
+// reserved for it when it was validated
+func (s *spotTradingService) recordPlacedOrder(req *api.OrderRequest, amount float64) {
+	if rm, ok := s.riskMgr.(*riskManager); ok {
+		rm.recordPlacedOrder(req, amount)
+	}
+}
+
+// releaseNotional gives back the notional reserved for an order that was validated but not placed
+func (s *spotTradingService) releaseNotional(req *api.OrderRequest) {
+	if rm, ok := s.riskMgr.(*riskManager); ok {
+		rm.releaseNotional(req)
+	}
+}
+
+// protectiveSeller is implemented by trading services that close positions without the
+// checks meant for new orders
+type protectiveSeller interface {
+	PlaceProtectiveSellOrder(symbol string, quantity float64) (*api.Order, error)
+}
+
+// placeProtectiveSell sells a position at market for a stop loss, take profit or trailing
+// stop, falling back to a plain market sell for services without a protective path
+func placeProtectiveSell(trading SpotTradingService, symbol string, quantity float64) (*api.Order, error) {
+	if seller, ok := trading.(protectiveSeller); ok {
+		return seller.PlaceProtectiveSellOrder(symbol, quantity)
+	}
+	return trading.PlaceMarketSellOrder(symbol, quantity)
+}
+
+// checkSlippage estimates the fill of a market order at the top of the book, the best ask for
+// buys and the best bid for sells, and rejects it when that deviates from the current price by
+// more than the configured percentage
+func (s *spotTradingService) checkSlippage(symbol string, side api.OrderSide, quantity float64) error {
+	if s.slippageMarketData == nil || s.maxSlippagePercent <= 0 {
+		return nil
+	}
+	
+	currentPrice, err := s.slippageMarketData.GetCurrentPrice(symbol)
+	if err != nil {
+		return err
+	}
+	quote, err := s.slippageMarketData.GetBestBidAsk(symbol)
+	if err != nil {
+		return err
+	}
+	
+	estimatedPrice := quote.AskPrice
+	if side == api.OrderSideSell {
+		estimatedPrice = quote.BidPrice
+	}
+	if currentPrice <= 0 || estimatedPrice <= 0 {
+		return errors.NewTradingError(
+			errors.ErrRiskLimitExceeded,
+			fmt.Sprintf("cannot estimate slippage for %s: no price or empty book", symbol),
+			0,
+			nil,
+		)
+	}
+	
+	slippage := math.Abs(estimatedPrice-currentPrice) / currentPrice * 100
+	rejected := slippage > s.maxSlippagePercent
+	
+	s.logger.LogOrderEvent(
+		"slippage_checked",
+		0,
+		symbol,
+		string(side),
+		string(api.OrderTypeMarket),
+		quantity,
+		map[string]interface{}{
+			"current_price":        currentPrice,
+			"estimated_price":      estimatedPrice,
+			"slippage_percent":     slippage,
+			"max_slippage_percent": s.maxSlippagePercent,
+			"rejected":             rejected,
+		},
+	)
+	
+	if rejected {
+		return errors.NewTradingError(
+			errors.ErrRiskLimitExceeded,
+			fmt.Sprintf("estimated slippage %.4f%% exceeds the maximum of %.4f%%", slippage, s.maxSlippagePercent),
+			0,
+			nil,
+		)
+	}
+	return nil
+}
+
+// checkSymbolPaused returns an error if new orders for the symbol are paused
+func (s *spotTradingService) checkSymbolPaused(symbol string) error {
+	if s.symbolGuard == nil {
+		return nil
+	}
+	return s.symbolGuard.CheckSymbol(symbol)
+}
+
+// recordOrderResult reports an order placement result to the symbol failure guard
+func (s *spotTradingService) recordOrderResult(symbol string, err error) {
+	if s.symbolGuard == nil {
+		return
+	}
+	if err != nil {
+		s.symbolGuard.RecordFailure(symbol, err)
+		return
 	}
+	s.symbolGuard.RecordSuccess(symbol)
+}
+
+// countOrder counts an order sent to the exchange by side and the status it came back with
+func (s *spotTradingService) countOrder(req *api.OrderRequest, resp *api.OrderResponse, err error) {
+	status := "error"
+	if err == nil {
+		status = string(resp.Status)
+	}
+	s.metrics.Orders.Inc(string(req.Side), status)
 }
 
 // PlaceMarketBuyOrder places a market buy order
@@ -72,6 +313,16 @@ func (s *spotTradingService) PlaceMarketBuyOrder(symbol string, quantity float64
 		)
 	}
 	
+	// Reject new orders for symbols paused after repeated failures
+	if err := s.checkSymbolPaused(symbol); err != nil {
+		s.logger.Warn("Market buy order rejected: symbol paused", map[string]interface{}{
+			"symbol":   symbol,
+			"quantity": quantity,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	
 	// Create order request
 	orderReq := &api.OrderRequest{
 		Symbol:   symbol,
@@ -80,6 +331,17 @@ func (s *spotTradingService) PlaceMarketBuyOrder(symbol string, quantity float64
 		Quantity: quantity,
 	}
 	
+	// Round to the symbol's step and tick size and reject what the exchange filters would refuse
+	if err := s.applySymbolFilter(orderReq); err != nil {
+		s.logger.Error("Market buy order failed symbol filter check", map[string]interface{}{
+			"symbol":   symbol,
+			"quantity": quantity,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	quantity = orderReq.Quantity
+	
 	// Validate order with risk manager
 	if err := s.riskMgr.ValidateOrder(orderReq); err != nil {
 		s.logger.Error("Market buy order failed risk validation", map[string]interface{}{
@@ -89,6 +351,8 @@ func (s *spotTradingService) PlaceMarketBuyOrder(symbol string, quantity float64
 		})
 		return nil, err
 	}
+	// Give back the reserved notional unless the order is placed
+	defer s.releaseNotional(orderReq)
 	
 	// Check daily limit
 	if err := s.riskMgr.CheckDailyLimit(); err != nil {
@@ -114,6 +378,16 @@ func (s *spotTradingService) PlaceMarketBuyOrder(symbol string, quantity float64
 		}
 	}
 	
+	// Reject the order when the book would fill it too far from the current price
+	if err := s.checkSlippage(symbol, api.OrderSideBuy, quantity); err != nil {
+		s.logger.Error("Market buy order failed slippage check", map[string]interface{}{
+			"symbol":   symbol,
+			"quantity": quantity,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	
 	// Place order via API
 	s.logger.Info("Placing market buy order", map[string]interface{}{
 		"symbol":   symbol,
@@ -121,6 +395,8 @@ func (s *spotTradingService) PlaceMarketBuyOrder(symbol string, quantity float64
 	})
 	
 	orderResp, err := s.client.CreateOrder(orderReq)
+	s.countOrder(orderReq, orderResp, err)
+	s.recordOrderResult(symbol, err)
 	if err != nil {
 		s.logger.LogError(err, map[string]interface{}{
 			"operation": "place_market_buy_order",
@@ -154,10 +430,172 @@ func (s *spotTradingService) PlaceMarketBuyOrder(symbol string, quantity float64
 	}
 	
 	// Record order in risk manager
-	if rm, ok := s.riskMgr.(*riskManager); ok {
-		rm.RecordOrder(orderResp.CummulativeQuoteQty)
+	s.recordPlacedOrder(orderReq, orderResp.CummulativeQuoteQty)
+	
+	// Log order event
+	s.logger.LogOrderEvent(
+		"order_created",
+		order.OrderID,
+		order.Symbol,
+		string(order.Side),
+		string(order.Type),
+		order.OrigQty,
+		map[string]interface{}{
+			"status":       string(order.Status),
+			"executed_qty": order.ExecutedQty,
+			"price":        order.Price,
+			"quote_qty":    order.CummulativeQuoteQty,
+		},
+	)
+	
+	return order, nil
+}
+
+// PlaceMarketBuyOrderByQuote places a market buy order spending quoteAmount of the quote asset
+func (s *spotTradingService) PlaceMarketBuyOrderByQuote(symbol string, quoteAmount float64) (*api.Order, error) {
+	// Validate input parameters
+	if symbol == "" {
+		s.logger.Error("Market buy order failed: empty symbol", map[string]interface{}{
+			"quote_amount": quoteAmount,
+		})
+		return nil, errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			"symbol cannot be empty",
+			0,
+			nil,
+		)
+	}
+	
+	if quoteAmount <= 0 {
+		s.logger.Error("Market buy order failed: invalid quote amount", map[string]interface{}{
+			"symbol":       symbol,
+			"quote_amount": quoteAmount,
+		})
+		return nil, errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			"quote amount must be greater than 0",
+			0,
+			nil,
+		)
+	}
+	
+	// Reject new orders for symbols paused after repeated failures
+	if err := s.checkSymbolPaused(symbol); err != nil {
+		s.logger.Warn("Market buy order rejected: symbol paused", map[string]interface{}{
+			"symbol":       symbol,
+			"quote_amount": quoteAmount,
+			"error":        err.Error(),
+		})
+		return nil, err
+	}
+	
+	// Create order request
+	orderReq := &api.OrderRequest{
+		Symbol:        symbol,
+		Side:          api.OrderSideBuy,
+		Type:          api.OrderTypeMarket,
+		QuoteOrderQty: quoteAmount,
+	}
+	
+	// Reject what the exchange filters would refuse
+	if err := s.applySymbolFilter(orderReq); err != nil {
+		s.logger.Error("Market buy order failed symbol filter check", map[string]interface{}{
+			"symbol":       symbol,
+			"quote_amount": quoteAmount,
+			"error":        err.Error(),
+		})
+		return nil, err
+	}
+	
+	// Validate order with risk manager
+	if err := s.riskMgr.ValidateOrder(orderReq); err != nil {
+		s.logger.Error("Market buy order failed risk validation", map[string]interface{}{
+			"symbol":       symbol,
+			"quote_amount": quoteAmount,
+			"error":        err.Error(),
+		})
+		return nil, err
+	}
+	// Give back the reserved notional unless the order is placed
+	defer s.releaseNotional(orderReq)
+	
+	// Check daily limit
+	if err := s.riskMgr.CheckDailyLimit(); err != nil {
+		s.logger.Error("Market buy order failed daily limit check", map[string]interface{}{
+			"symbol":       symbol,
+			"quote_amount": quoteAmount,
+			"error":        err.Error(),
+		})
+		return nil, err
 	}
 	
+	quoteAsset := extractQuoteAsset(symbol)
+	if quoteAsset != "" {
+		if err := s.riskMgr.CheckMinimumBalance(quoteAsset); err != nil {
+			s.logger.Error("Market buy order failed minimum balance check", map[string]interface{}{
+				"symbol":       symbol,
+				"quote_amount": quoteAmount,
+				"quote_asset":  quoteAsset,
+				"error":        err.Error(),
+			})
+			return nil, err
+		}
+	}
+	
+	// The base quantity is only known once the order fills
+	if err := s.checkSlippage(symbol, api.OrderSideBuy, 0); err != nil {
+		s.logger.Error("Market buy order failed slippage check", map[string]interface{}{
+			"symbol":       symbol,
+			"quote_amount": quoteAmount,
+			"error":        err.Error(),
+		})
+		return nil, err
+	}
+	
+	// Place order via API
+	s.logger.Info("Placing market buy order", map[string]interface{}{
+		"symbol":       symbol,
+		"quote_amount": quoteAmount,
+	})
+	
+	orderResp, err := s.client.CreateOrder(orderReq)
+	s.countOrder(orderReq, orderResp, err)
+	s.recordOrderResult(symbol, err)
+	if err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation":    "place_market_buy_order_by_quote",
+			"symbol":       symbol,
+			"quote_amount": quoteAmount,
+		})
+		return nil, err
+	}
+	
+	// Convert response to Order
+	order := &api.Order{
+		OrderID:             orderResp.OrderID,
+		Symbol:              orderResp.Symbol,
+		Side:                api.OrderSideBuy,
+		Type:                api.OrderTypeMarket,
+		Status:              orderResp.Status,
+		Price:               orderResp.Price,
+		OrigQty:             orderResp.OrigQty,
+		ExecutedQty:         orderResp.ExecutedQty,
+		CummulativeQuoteQty: orderResp.CummulativeQuoteQty,
+		Time:                orderResp.TransactTime,
+		UpdateTime:          orderResp.TransactTime,
+	}
+	
+	// Save order to repository
+	if err := s.orderRepo.Save(order); err != nil {
+		s.logger.Warn("Failed to save order to repository", map[string]interface{}{
+			"order_id": order.OrderID,
+			"error":    err.Error(),
+		})
+	}
+	
+	// Record order in risk manager
+	s.recordPlacedOrder(orderReq, orderResp.CummulativeQuoteQty)
+	
 	// Log order event
 	s.logger.LogOrderEvent(
 		"order_created",
@@ -170,6 +608,8 @@ func (s *spotTradingService) PlaceMarketBuyOrder(symbol string, quantity float64
 			"status":       string(order.Status),
 			"executed_qty": order.ExecutedQty,
 			"price":        order.Price,
+			"quote_qty":    order.CummulativeQuoteQty,
+			"quote_amount": quoteAmount,
 		},
 	)
 	
@@ -178,6 +618,17 @@ func (s *spotTradingService) PlaceMarketBuyOrder(symbol string, quantity float64
 
 // PlaceMarketSellOrder places a market sell order
 func (s *spotTradingService) PlaceMarketSellOrder(symbol string, quantity float64) (*api.Order, error) {
+	return s.placeMarketSell(symbol, quantity, false)
+}
+
+// PlaceProtectiveSellOrder places the market sell of a stop loss, take profit or trailing
+// stop. It skips the slippage check: a wide spread must not keep a position from closing.
+func (s *spotTradingService) PlaceProtectiveSellOrder(symbol string, quantity float64) (*api.Order, error) {
+	return s.placeMarketSell(symbol, quantity, true)
+}
+
+// placeMarketSell places a market sell order; protective sells close an existing position
+func (s *spotTradingService) placeMarketSell(symbol string, quantity float64, protective bool) (*api.Order, error) {
 	// Validate input parameters
 	if symbol == "" {
 		s.logger.Error("Market sell order failed: empty symbol", map[string]interface{}{
@@ -212,8 +663,19 @@ func (s *spotTradingService) PlaceMarketSellOrder(symbol string, quantity float6
 		Quantity: quantity,
 	}
 	
+	// Round to the symbol's step and tick size and reject what the exchange filters would refuse
+	if err := s.applySymbolFilter(orderReq); err != nil {
+		s.logger.Error("Market sell order failed symbol filter check", map[string]interface{}{
+			"symbol":   symbol,
+			"quantity": quantity,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	quantity = orderReq.Quantity
+	
 	// Validate order with risk manager
-	if err := s.riskMgr.ValidateOrder(orderReq); err != nil {
+	if err := s.validateOrder(orderReq, protective); err != nil {
 		s.logger.Error("Market sell order failed risk validation", map[string]interface{}{
 			"symbol":   symbol,
 			"quantity": quantity,
@@ -221,6 +683,8 @@ func (s *spotTradingService) PlaceMarketSellOrder(symbol string, quantity float6
 		})
 		return nil, err
 	}
+	// Give back the reserved notional unless the order is placed
+	defer s.releaseNotional(orderReq)
 	
 	// Check daily limit
 	if err := s.riskMgr.CheckDailyLimit(); err != nil {
@@ -232,13 +696,42 @@ func (s *spotTradingService) PlaceMarketSellOrder(symbol string, quantity float6
 		return nil, err
 	}
 	
+	// Extract base asset from symbol (e.g., BTC from BTCUSDT)
+	if quoteAsset := extractQuoteAsset(symbol); quoteAsset != "" && quoteAsset != symbol {
+		baseAsset := strings.TrimSuffix(symbol, quoteAsset)
+		if err := s.riskMgr.CheckAvailableBalance(baseAsset, quantity); err != nil {
+			s.logger.Error("Market sell order failed available balance check", map[string]interface{}{
+				"symbol":     symbol,
+				"quantity":   quantity,
+				"base_asset": baseAsset,
+				"error":      err.Error(),
+			})
+			return nil, err
+		}
+	}
+	
+	// Reject the order when the book would fill it too far from the current price
+	if !protective {
+		if err := s.checkSlippage(symbol, api.OrderSideSell, quantity); err != nil {
+			s.logger.Error("Market sell order failed slippage check", map[string]interface{}{
+				"symbol":   symbol,
+				"quantity": quantity,
+				"error":    err.Error(),
+			})
+			return nil, err
+		}
+	}
+	
 	// Place order via API
 	s.logger.Info("Placing market sell order", map[string]interface{}{
-		"symbol":   symbol,
-		"quantity": quantity,
+		"symbol":     symbol,
+		"quantity":   quantity,
+		"protective": protective,
 	})
 	
 	orderResp, err := s.client.CreateOrder(orderReq)
+	s.countOrder(orderReq, orderResp, err)
+	s.recordOrderResult(symbol, err)
 	if err != nil {
 		s.logger.LogError(err, map[string]interface{}{
 			"operation": "place_market_sell_order",
@@ -272,9 +765,7 @@ func (s *spotTradingService) PlaceMarketSellOrder(symbol string, quantity float6
 	}
 	
 	// Record order in risk manager
-	if rm, ok := s.riskMgr.(*riskManager); ok {
-		rm.RecordOrder(orderResp.CummulativeQuoteQty)
-	}
+	s.recordPlacedOrder(orderReq, orderResp.CummulativeQuoteQty)
 	
 	// Log order event
 	s.logger.LogOrderEvent(
@@ -288,6 +779,7 @@ func (s *spotTradingService) PlaceMarketSellOrder(symbol string, quantity float6
 			"status":       string(order.Status),
 			"executed_qty": order.ExecutedQty,
 			"price":        order.Price,
+			"quote_qty":    order.CummulativeQuoteQty,
 		},
 	)
 	
@@ -348,6 +840,18 @@ func (s *spotTradingService) PlaceLimitSellOrder(symbol string, price, quantity
 		TimeInForce: "GTC", // Good Till Cancel
 	}
 	
+	// Round to the symbol's step and tick size and reject what the exchange filters would refuse
+	if err := s.applySymbolFilter(orderReq); err != nil {
+		s.logger.Error("Limit sell order failed symbol filter check", map[string]interface{}{
+			"symbol":   symbol,
+			"price":    price,
+			"quantity": quantity,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	quantity, price = orderReq.Quantity, orderReq.Price
+	
 	// Validate order with risk manager
 	if err := s.riskMgr.ValidateOrder(orderReq); err != nil {
 		s.logger.Error("Limit sell order failed risk validation", map[string]interface{}{
@@ -358,6 +862,8 @@ func (s *spotTradingService) PlaceLimitSellOrder(symbol string, price, quantity
 		})
 		return nil, err
 	}
+	// Give back the reserved notional unless the order is placed
+	defer s.releaseNotional(orderReq)
 	
 	// Check daily limit
 	if err := s.riskMgr.CheckDailyLimit(); err != nil {
@@ -378,6 +884,8 @@ func (s *spotTradingService) PlaceLimitSellOrder(symbol string, price, quantity
 	})
 	
 	orderResp, err := s.client.CreateOrder(orderReq)
+	s.countOrder(orderReq, orderResp, err)
+	s.recordOrderResult(symbol, err)
 	if err != nil {
 		s.logger.LogError(err, map[string]interface{}{
 			"operation": "place_limit_sell_order",
@@ -412,10 +920,161 @@ func (s *spotTradingService) PlaceLimitSellOrder(symbol string, price, quantity
 	}
 	
 	// Record order in risk manager
-	if rm, ok := s.riskMgr.(*riskManager); ok {
-		rm.RecordOrder(price * quantity)
+	s.recordPlacedOrder(orderReq, price*quantity)
+	
+	// Log order event
+	s.logger.LogOrderEvent(
+		"order_created",
+		order.OrderID,
+		order.Symbol,
+		string(order.Side),
+		string(order.Type),
+		order.OrigQty,
+		map[string]interface{}{
+			"status":       string(order.Status),
+			"executed_qty": order.ExecutedQty,
+			"price":        order.Price,
+			"quote_qty":    order.CummulativeQuoteQty,
+		},
+	)
+	
+	return order, nil
+}
+
+// PlaceLimitBuyOrder places a resting limit buy order
+func (s *spotTradingService) PlaceLimitBuyOrder(symbol string, price, quantity float64) (*api.Order, error) {
+	// Validate input parameters; price and quantity are checked by the risk manager
+	if symbol == "" {
+		s.logger.Error("Limit buy order failed: empty symbol", map[string]interface{}{
+			"price":    price,
+			"quantity": quantity,
+		})
+		return nil, errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			"symbol cannot be empty",
+			0,
+			nil,
+		)
+	}
+	
+	// Reject new orders for symbols paused after repeated failures
+	if err := s.checkSymbolPaused(symbol); err != nil {
+		s.logger.Warn("Limit buy order rejected: symbol paused", map[string]interface{}{
+			"symbol":   symbol,
+			"price":    price,
+			"quantity": quantity,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	
+	// Create order request
+	orderReq := &api.OrderRequest{
+		Symbol:      symbol,
+		Side:        api.OrderSideBuy,
+		Type:        api.OrderTypeLimit,
+		Quantity:    quantity,
+		Price:       price,
+		TimeInForce: "GTC", // Good Till Cancel
+	}
+	
+	// Round to the symbol's step and tick size and reject what the exchange filters would refuse
+	if err := s.applySymbolFilter(orderReq); err != nil {
+		s.logger.Error("Limit buy order failed symbol filter check", map[string]interface{}{
+			"symbol":   symbol,
+			"price":    price,
+			"quantity": quantity,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	quantity, price = orderReq.Quantity, orderReq.Price
+	
+	// Validate order with risk manager
+	if err := s.riskMgr.ValidateOrder(orderReq); err != nil {
+		s.logger.Error("Limit buy order failed risk validation", map[string]interface{}{
+			"symbol":   symbol,
+			"price":    price,
+			"quantity": quantity,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	// Give back the reserved notional unless the order is placed
+	defer s.releaseNotional(orderReq)
+	
+	// Check daily limit
+	if err := s.riskMgr.CheckDailyLimit(); err != nil {
+		s.logger.Error("Limit buy order failed daily limit check", map[string]interface{}{
+			"symbol":   symbol,
+			"price":    price,
+			"quantity": quantity,
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	
+	// The order locks quote asset while it rests on the book
+	quoteAsset := extractQuoteAsset(symbol)
+	if quoteAsset != "" {
+		if err := s.riskMgr.CheckMinimumBalance(quoteAsset); err != nil {
+			s.logger.Error("Limit buy order failed minimum balance check", map[string]interface{}{
+				"symbol":      symbol,
+				"price":       price,
+				"quantity":    quantity,
+				"quote_asset": quoteAsset,
+				"error":       err.Error(),
+			})
+			return nil, err
+		}
+	}
+	
+	// Place order via API
+	s.logger.Info("Placing limit buy order", map[string]interface{}{
+		"symbol":   symbol,
+		"price":    price,
+		"quantity": quantity,
+	})
+	
+	orderResp, err := s.client.CreateOrder(orderReq)
+	s.countOrder(orderReq, orderResp, err)
+	s.recordOrderResult(symbol, err)
+	if err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation": "place_limit_buy_order",
+			"symbol":    symbol,
+			"price":     price,
+			"quantity":  quantity,
+		})
+		return nil, err
+	}
+	
+	// Convert response to Order
+	order := &api.Order{
+		OrderID:             orderResp.OrderID,
+		Symbol:              orderResp.Symbol,
+		Side:                api.OrderSideBuy,
+		Type:                api.OrderTypeLimit,
+		Status:              orderResp.Status,
+		Price:               orderResp.Price,
+		OrigQty:             orderResp.OrigQty,
+		ExecutedQty:         orderResp.ExecutedQty,
+		CummulativeQuoteQty: orderResp.CummulativeQuoteQty,
+		Time:                orderResp.TransactTime,
+		UpdateTime:          orderResp.TransactTime,
 	}
 	
+	// Save order to repository
+	if err := s.orderRepo.Save(order); err != nil {
+		s.logger.Warn("Failed to save order to repository", map[string]interface{}{
+			"order_id": order.OrderID,
+			"error":    err.Error(),
+		})
+	}
+	
+	// Record order in risk manager
+	s.recordPlacedOrder(orderReq, price*quantity)
+	
 	// Log order event
 	s.logger.LogOrderEvent(
 		"order_created",
@@ -428,6 +1087,154 @@ func (s *spotTradingService) PlaceLimitSellOrder(symbol string, price, quantity
 			"status":       string(order.Status),
 			"executed_qty": order.ExecutedQty,
 			"price":        order.Price,
+			"quote_qty":    order.CummulativeQuoteQty,
+		},
+	)
+	
+	return order, nil
+}
+
+// SubmitOrderIntent places a market or limit order under the intent's client order ID
+func (s *spotTradingService) SubmitOrderIntent(intent *OrderIntent) (*api.Order, error) {
+	if intent == nil || intent.ClientOrderID == "" {
+		return nil, errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			"order intent requires a client order ID",
+			0,
+			nil,
+		)
+	}
+	
+	if intent.Symbol == "" || intent.Quantity <= 0 || (intent.Type == api.OrderTypeLimit && intent.Price <= 0) {
+		return nil, errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			"order intent requires a symbol, a positive quantity and a limit price for limit orders",
+			0,
+			nil,
+		)
+	}
+	
+	// Skip orders that already executed before a restart but were never recorded
+	if s.replay != nil {
+		existing, err := s.replay.CheckIntent(intent)
+		if err != nil {
+			s.logger.Error("Order intent rejected: replay protection check failed", map[string]interface{}{
+				"client_order_id": intent.ClientOrderID,
+				"symbol":          intent.Symbol,
+				"error":           err.Error(),
+			})
+			return nil, err
+		}
+		if existing != nil {
+			s.logger.Warn("Order intent already executed before restart, skipping resubmission", map[string]interface{}{
+				"client_order_id": intent.ClientOrderID,
+				"order_id":        existing.OrderID,
+				"symbol":          existing.Symbol,
+				"status":          string(existing.Status),
+				"executed_qty":    existing.ExecutedQty,
+			})
+			if _, err := s.orderRepo.FindByID(existing.OrderID); err != nil {
+				if err := s.orderRepo.Save(existing); err != nil {
+					s.logger.Warn("Failed to save order to repository", map[string]interface{}{
+						"order_id": existing.OrderID,
+						"error":    err.Error(),
+					})
+				}
+			}
+			return existing, nil
+		}
+	}
+	
+	if err := s.checkSymbolPaused(intent.Symbol); err != nil {
+		return nil, err
+	}
+	
+	orderReq := &api.OrderRequest{
+		Symbol:           intent.Symbol,
+		Side:             intent.Side,
+		Type:             intent.Type,
+		Quantity:         intent.Quantity,
+		NewClientOrderID: intent.ClientOrderID,
+	}
+	if intent.Type == api.OrderTypeLimit {
+		orderReq.Price = intent.Price
+		orderReq.TimeInForce = "GTC"
+	}
+	
+	if err := s.applySymbolFilter(orderReq); err != nil {
+		return nil, err
+	}
+	if err := s.riskMgr.ValidateOrder(orderReq); err != nil {
+		return nil, err
+	}
+	defer s.releaseNotional(orderReq)
+	if err := s.riskMgr.CheckDailyLimit(); err != nil {
+		return nil, err
+	}
+	if intent.Type == api.OrderTypeMarket {
+		if err := s.checkSlippage(intent.Symbol, intent.Side, intent.Quantity); err != nil {
+			return nil, err
+		}
+	}
+	
+	s.logger.Info("Placing order intent", map[string]interface{}{
+		"client_order_id": intent.ClientOrderID,
+		"symbol":          intent.Symbol,
+		"side":            string(intent.Side),
+		"type":            string(intent.Type),
+		"quantity":        intent.Quantity,
+		"price":           intent.Price,
+	})
+	
+	orderResp, err := s.client.CreateOrder(orderReq)
+	s.countOrder(orderReq, orderResp, err)
+	s.recordOrderResult(intent.Symbol, err)
+	if err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation":       "submit_order_intent",
+			"client_order_id": intent.ClientOrderID,
+			"symbol":          intent.Symbol,
+		})
+		return nil, err
+	}
+	
+	order := &api.Order{
+		OrderID:             orderResp.OrderID,
+		Symbol:              orderResp.Symbol,
+		ClientOrderID:       intent.ClientOrderID,
+		Side:                intent.Side,
+		Type:                intent.Type,
+		Status:              orderResp.Status,
+		Price:               orderResp.Price,
+		OrigQty:             orderResp.OrigQty,
+		ExecutedQty:         orderResp.ExecutedQty,
+		CummulativeQuoteQty: orderResp.CummulativeQuoteQty,
+		Time:                orderResp.TransactTime,
+		UpdateTime:          orderResp.TransactTime,
+	}
+	
+	if err := s.orderRepo.Save(order); err != nil {
+		s.logger.Warn("Failed to save order to repository", map[string]interface{}{
+			"order_id": order.OrderID,
+			"error":    err.Error(),
+		})
+	}
+	
+	s.recordPlacedOrder(orderReq, orderResp.CummulativeQuoteQty)
+	
+	s.logger.LogOrderEvent(
+		"order_created",
+		order.OrderID,
+		order.Symbol,
+		string(order.Side),
+		string(order.Type),
+		order.OrigQty,
+		map[string]interface{}{
+			"status":          string(order.Status),
+			"executed_qty":    order.ExecutedQty,
+			"price":           order.Price,
+			"quote_qty":       order.CummulativeQuoteQty,
+			"client_order_id": order.ClientOrderID,
 		},
 	)
 	
@@ -459,6 +1266,11 @@ func (s *spotTradingService) CancelOrder(orderID int64) error {
 		return err
 	}
 	
+	// The exchange cancels order lists as a whole, so a leg cancels every leg
+	if order.InOrderList() {
+		return s.cancelOrderList(order)
+	}
+	
 	// Cancel order via API
 	s.logger.Info("Canceling order", map[string]interface{}{
 		"order_id": orderID,
@@ -499,6 +1311,55 @@ func (s *spotTradingService) CancelOrder(orderID int64) error {
 	return nil
 }
 
+// CancelAllOrders cancels all open orders of a symbol in one request and marks the cancelled
+// orders the repository knows as canceled
+func (s *spotTradingService) CancelAllOrders(symbol string) (int, error) {
+	if symbol == "" {
+		return 0, errors.NewTradingError(
+			errors.ErrInvalidParameter,
+			"symbol cannot be empty",
+			0,
+			nil,
+		)
+	}
+	
+	s.logger.Info("Canceling all orders", map[string]interface{}{
+		"symbol": symbol,
+	})
+	
+	cancelled, err := s.client.BulkCancelOrders(symbol)
+	now := time.Now().UnixMilli()
+	for _, resp := range cancelled {
+		// Orders placed outside this process are not in the repository
+		order, findErr := s.orderRepo.FindByID(resp.OrderID)
+		if findErr != nil {
+			continue
+		}
+		if syncErr := s.orderRepo.SyncOrderStatus(resp.OrderID, api.OrderStatusCanceled, order.ExecutedQty, now); syncErr != nil {
+			s.logger.Warn("Failed to update order status in repository", map[string]interface{}{
+				"order_id": resp.OrderID,
+				"error":    syncErr.Error(),
+			})
+		}
+	}
+	
+	if err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation": "cancel_all_orders",
+			"symbol":    symbol,
+			"cancelled": len(cancelled),
+		})
+		return len(cancelled), err
+	}
+	
+	s.logger.Info("All orders canceled", map[string]interface{}{
+		"symbol":    symbol,
+		"cancelled": len(cancelled),
+	})
+	
+	return len(cancelled), nil
+}
+
 // GetOrderStatus retrieves the current status of an order
 func (s *spotTradingService) GetOrderStatus(orderID int64) (*OrderStatus, error) {
 	// Validate input
@@ -567,6 +1428,18 @@ func (s *spotTradingService) GetOrderStatus(orderID int64) (*OrderStatus, error)
 	return status, nil
 }
 
+// GetAllBalances returns the non-zero balances of the account
+func (s *spotTradingService) GetAllBalances() ([]api.Balance, error) {
+	balances, err := s.client.GetAllBalances()
+	if err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation": "get_all_balances",
+		})
+		return nil, err
+	}
+	return balances, nil
+}
+
 // GetActiveOrders retrieves all active (open) orders
 func (s *spotTradingService) GetActiveOrders() ([]*api.Order, error) {
 	s.logger.Debug("Retrieving active orders", nil)
@@ -605,6 +1478,9 @@ func (s *spotTradingService) GetActiveOrders() ([]*api.Order, error) {
 		}
 	}
 	
+	// Legs of lists that left the open orders are settled from the list status
+	s.reconcileOrderLists(apiOrders)
+	
 	s.logger.Info("Active orders retrieved", map[string]interface{}{
 		"count": len(apiOrders),
 	})
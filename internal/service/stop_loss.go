@@ -1,9 +1,12 @@
 package service
 
 import (
+	"binance-trader/internal/config"
+	"binance-trader/pkg/metrics"
 	"binance-trader/internal/repository"
 	"binance-trader/pkg/errors"
 	"binance-trader/pkg/logger"
+	"binance-trader/pkg/timeutil"
 	"fmt"
 	"sync"
 	"time"
@@ -14,13 +17,41 @@ type StopLossService interface {
 	// Set stop loss and take profit
 	SetStopLoss(symbol string, position float64, stopPrice float64) (*repository.StopOrder, error)
 	SetTakeProfit(symbol string, position float64, targetPrice float64) (*repository.StopOrder, error)
+	SetTakeProfitLadder(symbol string, position float64, levels []TPLevel) ([]*repository.StopOrder, error)
 	SetStopLossTakeProfit(symbol string, position float64, stopPrice, targetPrice float64) (*repository.StopOrderPair, error)
 	SetTrailingStop(symbol string, position float64, trailPercent float64) (*repository.TrailingStopOrder, error)
+	SetATRTrailingStop(symbol string, position float64, trail ATRTrail) (*repository.TrailingStopOrder, error)
 
 	// Manage stop orders
-	CancelStopOrder(orderID string) error
+	CancelStopOrder(orderID, symbol string) (*CancelledStopOrder, error)
 	GetActiveStopOrders(symbol string) ([]*repository.StopOrder, error)
 	UpdateTrailingStop(orderID string, newTrailPercent float64) error
+
+	// SetTrailConfig sets the trail bounds and kline interval of ATR trailing stops
+	SetTrailConfig(cfg *config.StopLossConfig)
+
+	// SetMetrics sets the sink trigger metrics are recorded in; a new service records them nowhere
+	SetMetrics(sink *metrics.Sink)
+}
+
+// CancelledStopOrder is the order removed by CancelStopOrder; exactly one of the fields is set
+type CancelledStopOrder struct {
+	StopOrder     *repository.StopOrder
+	TrailingOrder *repository.TrailingStopOrder
+}
+
+// checkOrderSymbol rejects a cancellation whose expected symbol differs from the order's symbol,
+// so a mistyped ID that matches another order is not cancelled; an empty symbol skips the check
+func checkOrderSymbol(orderID, orderSymbol, expected string) error {
+	if expected == "" || expected == orderSymbol {
+		return nil
+	}
+	return errors.NewTradingError(
+		errors.ErrInvalidParameter,
+		fmt.Sprintf("order %s belongs to %s, not %s", orderID, orderSymbol, expected),
+		0,
+		nil,
+	)
 }
 
 // stopLossService implements the StopLossService interface
@@ -30,6 +61,13 @@ type stopLossService struct {
 	tradingService TradingService
 	marketService  MarketDataService
 	logger         logger.Logger
+	metrics        *metrics.Sink
+	now            func() time.Time
+
+	mu              sync.RWMutex
+	minTrailPercent float64
+	maxTrailPercent float64
+	atrInterval     string
 }
 
 // UpdateTrailingStopPrice updates the trailing stop price based on current market price
@@ -46,13 +84,17 @@ func (s *stopLossService) UpdateTrailingStopPrice(orderID string, currentPrice f
 		return false, nil
 	}
 
+	// ATR trails follow volatility, recomputed once per closed candle
 	updated := false
+	if trailingOrder.TrailMode == repository.TrailModeATR {
+		updated = s.refreshATRTrail(trailingOrder)
+	}
 
 	// If current price is higher than highest price, update highest price and stop price
 	if currentPrice > trailingOrder.HighestPrice {
 		trailingOrder.HighestPrice = currentPrice
 		trailingOrder.CurrentStopPrice = currentPrice * (1 - trailingOrder.TrailPercent/100)
-		trailingOrder.LastUpdatedAt = time.Now().Unix()
+		trailingOrder.LastUpdatedAt = timeutil.NowMillis()
 		updated = true
 
 		// Save updated order
@@ -96,9 +138,10 @@ func (s *stopLossService) triggerTrailingStop(order *repository.TrailingStopOrde
 	if err := s.stopOrderRepo.UpdateTrailingStopOrder(order); err != nil {
 		return err
 	}
+	s.metrics.StopLossTriggered.Inc(order.Symbol, "trailing_stop")
 
 	// Execute market sell order to close position
-	executedOrder, err := s.tradingService.PlaceMarketSellOrder(order.Symbol, order.Position)
+	executedOrder, err := placeProtectiveSell(s.tradingService, order.Symbol, order.Position)
 	if err != nil {
 		s.logger.LogError(err, map[string]interface{}{
 			"operation":    "execute_trailing_stop",
@@ -138,9 +181,20 @@ func NewStopLossService(
 		tradingService: tradingService,
 		marketService:  marketService,
 		logger:         log,
+		metrics:        metrics.Noop(),
+		now:            time.Now,
+
+		minTrailPercent: DefaultMinTrailPercent,
+		maxTrailPercent: DefaultMaxTrailPercent,
+		atrInterval:     DefaultATRInterval,
 	}
 }
 
+// SetMetrics sets the sink trigger metrics are recorded in
+func (s *stopLossService) SetMetrics(sink *metrics.Sink) {
+	s.metrics = sink
+}
+
 // SetStopLoss sets a stop loss order for a position
 func (s *stopLossService) SetStopLoss(symbol string, position float64, stopPrice float64) (*repository.StopOrder, error) {
 	// Validate input parameters
@@ -164,7 +218,7 @@ func (s *stopLossService) SetStopLoss(symbol string, position float64, stopPrice
 		StopPrice: stopPrice,
 		Type:      repository.StopOrderTypeStopLoss,
 		Status:    repository.StopOrderStatusActive,
-		CreatedAt: time.Now().Unix(),
+		CreatedAt: timeutil.NowMillis(),
 	}
 
 	// Save to repository
@@ -225,7 +279,7 @@ func (s *stopLossService) SetTakeProfit(symbol string, position float64, targetP
 		StopPrice: targetPrice,
 		Type:      repository.StopOrderTypeTakeProfit,
 		Status:    repository.StopOrderStatusActive,
-		CreatedAt: time.Now().Unix(),
+		CreatedAt: timeutil.NowMillis(),
 	}
 
 	// Save to repository
@@ -263,6 +317,232 @@ func (s *stopLossService) SetTakeProfit(symbol string, position float64, targetP
 	return takeProfitOrder, nil
 }
 
+// SetTakeProfitLadder registers one take profit order per level, each closing its
+// portion of the position independently when the level price is reached
+func (s *stopLossService) SetTakeProfitLadder(symbol string, position float64, levels []TPLevel) ([]*repository.StopOrder, error) {
+	// Validate input parameters
+	if symbol == "" {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "symbol cannot be empty", 0, nil)
+	}
+
+	if position <= 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "position must be greater than 0", 0, nil)
+	}
+
+	if err := validateTakeProfitLadder(levels, true); err != nil {
+		return nil, err
+	}
+
+	// Create and save one take profit order per level
+	orders := make([]*repository.StopOrder, 0, len(levels))
+	for _, level := range levels {
+		takeProfitOrder := &repository.StopOrder{
+			OrderID:   generateOrderID("TP"),
+			Symbol:    symbol,
+			Position:  position * level.Portion / 100,
+			StopPrice: level.Price,
+			Type:      repository.StopOrderTypeTakeProfit,
+			Status:    repository.StopOrderStatusActive,
+			CreatedAt: timeutil.NowMillis(),
+		}
+
+		if err := s.stopOrderRepo.SaveStopOrder(takeProfitOrder); err != nil {
+			s.logger.LogError(err, map[string]interface{}{
+				"operation":    "set_take_profit_ladder",
+				"symbol":       symbol,
+				"target_price": level.Price,
+			})
+			// Rollback levels saved so far
+			for _, saved := range orders {
+				s.stopOrderRepo.DeleteStopOrder(saved.OrderID)
+			}
+			return nil, err
+		}
+
+		orders = append(orders, takeProfitOrder)
+	}
+
+	// Register trigger conditions
+	for _, order := range orders {
+		condition := &TriggerCondition{
+			Type:     TriggerTypePrice,
+			Operator: OperatorGreaterEqual,
+			Value:    order.StopPrice,
+		}
+
+		if err := s.triggerEngine.RegisterCondition(order.OrderID, condition); err != nil {
+			s.logger.Warn("Failed to register trigger condition", map[string]interface{}{
+				"order_id": order.OrderID,
+				"error":    err.Error(),
+			})
+		}
+	}
+
+	s.logger.Info("Take profit ladder created", map[string]interface{}{
+		"symbol":   symbol,
+		"position": position,
+		"levels":   len(orders),
+	})
+
+	return orders, nil
+}
+
+// ExecuteTakeProfitIfReached sells the order's position once the price reaches its target
+// This should be called periodically by the monitoring engine
+func (s *stopLossService) ExecuteTakeProfitIfReached(orderID string, currentPrice float64) (bool, error) {
+	order, err := s.stopOrderRepo.FindStopOrderByID(orderID)
+	if err != nil {
+		return false, err
+	}
+
+	if order.Type != repository.StopOrderTypeTakeProfit || order.Status != repository.StopOrderStatusActive {
+		return false, nil
+	}
+
+	if currentPrice < order.StopPrice {
+		return false, nil
+	}
+
+	return s.executeStopOrder(order, currentPrice)
+}
+
+// ExecuteStopLossIfReached sells the order's position once the price falls to its stop
+// This should be called periodically by the monitoring engine
+func (s *stopLossService) ExecuteStopLossIfReached(orderID string, currentPrice float64) (bool, error) {
+	order, err := s.stopOrderRepo.FindStopOrderByID(orderID)
+	if err != nil {
+		return false, err
+	}
+
+	if order.Type != repository.StopOrderTypeStopLoss || order.Status != repository.StopOrderStatusActive {
+		return false, nil
+	}
+
+	if currentPrice > order.StopPrice {
+		return false, nil
+	}
+
+	return s.executeStopOrder(order, currentPrice)
+}
+
+// executeStopOrder triggers a stop order and sells its position. The order is marked triggered,
+// and the other leg of its pair cancelled, before selling, so neither is ever sold twice.
+func (s *stopLossService) executeStopOrder(order *repository.StopOrder, currentPrice float64) (bool, error) {
+	orderID := order.OrderID
+	cancelled, err := s.stopOrderRepo.TriggerStopOrder(orderID, timeutil.NowMillis())
+	if err != nil {
+		// Another leg of the pair, or another caller, got there first
+		if tradingErr, ok := err.(*errors.TradingError); ok && tradingErr.Type == errors.ErrOrderAlreadyTriggered {
+			return false, nil
+		}
+		return false, err
+	}
+	s.triggerEngine.UnregisterCondition(orderID)
+
+	for _, sibling := range cancelled {
+		s.triggerEngine.UnregisterCondition(sibling.OrderID)
+		s.logger.Info("Paired stop order cancelled", map[string]interface{}{
+			"order_id":           sibling.OrderID,
+			"symbol":             sibling.Symbol,
+			"pair_id":            sibling.PairID,
+			"triggered_order_id": orderID,
+		})
+	}
+
+	operation, message, priceField := "execute_take_profit", "Take profit order triggered and executed", "target_price"
+	metricType := "take_profit"
+	if order.Type == repository.StopOrderTypeStopLoss {
+		operation, message, priceField = "execute_stop_loss", "Stop loss order triggered and executed", "stop_price"
+		metricType = "stop_loss"
+	}
+	s.metrics.StopLossTriggered.Inc(order.Symbol, metricType)
+
+	executedOrder, err := placeProtectiveSell(s.tradingService, order.Symbol, order.Position)
+	if err != nil {
+		s.logger.LogError(err, map[string]interface{}{
+			"operation":     operation,
+			"order_id":      orderID,
+			"symbol":        order.Symbol,
+			"position":      order.Position,
+			"trigger_price": currentPrice,
+		})
+		s.reactivateStopOrder(order, cancelled)
+		return false, err
+	}
+
+	s.stopOrderRepo.UpdateStopOrderStatus(orderID, repository.StopOrderStatusTriggered, 0, executedOrder.OrderID)
+
+	s.logger.Info(message, map[string]interface{}{
+		"order_id":          orderID,
+		"symbol":            order.Symbol,
+		"position":          order.Position,
+		priceField:          order.StopPrice,
+		"trigger_price":     currentPrice,
+		"executed_order_id": executedOrder.OrderID,
+	})
+
+	return true, nil
+}
+
+// reactivateStopOrder undoes the trigger of a stop order whose sell failed. The order and the
+// pair legs its trigger cancelled return to ACTIVE, so the next check retries the sell.
+func (s *stopLossService) reactivateStopOrder(order *repository.StopOrder, cancelled []*repository.StopOrder) {
+	for _, leg := range append([]*repository.StopOrder{order}, cancelled...) {
+		restored := *leg
+		restored.Status = repository.StopOrderStatusActive
+		restored.TriggeredAt = 0
+		if err := s.stopOrderRepo.UpdateStopOrder(&restored); err != nil {
+			s.logger.Warn("Failed to reactivate stop order", map[string]interface{}{
+				"order_id": leg.OrderID,
+				"error":    err.Error(),
+			})
+			continue
+		}
+
+		operator := OperatorGreaterEqual
+		if leg.Type == repository.StopOrderTypeStopLoss {
+			operator = OperatorLessEqual
+		}
+		condition := &TriggerCondition{
+			Type:     TriggerTypePrice,
+			Operator: operator,
+			Value:    leg.StopPrice,
+		}
+		if err := s.triggerEngine.RegisterCondition(leg.OrderID, condition); err != nil {
+			s.logger.Warn("Failed to register trigger condition", map[string]interface{}{
+				"order_id": leg.OrderID,
+				"error":    err.Error(),
+			})
+		}
+	}
+
+	if order.PairID == "" {
+		return
+	}
+
+	// Keep the stored pair in step with its legs
+	pair, err := s.stopOrderRepo.FindStopOrderPairByID(order.PairID)
+	if err != nil {
+		return
+	}
+	pair.Status = "ACTIVE"
+	for _, leg := range []*repository.StopOrder{pair.StopLossOrder, pair.TakeProfitOrder} {
+		if leg == nil {
+			continue
+		}
+		if stored, err := s.stopOrderRepo.FindStopOrderByID(leg.OrderID); err == nil {
+			leg.Status = stored.Status
+			leg.TriggeredAt = stored.TriggeredAt
+		}
+	}
+	if err := s.stopOrderRepo.UpdateStopOrderPair(pair); err != nil {
+		s.logger.Warn("Failed to reactivate stop order pair", map[string]interface{}{
+			"pair_id": pair.PairID,
+			"error":   err.Error(),
+		})
+	}
+}
+
 // SetStopLossTakeProfit sets both stop loss and take profit orders as a pair
 func (s *stopLossService) SetStopLossTakeProfit(symbol string, position float64, stopPrice, targetPrice float64) (*repository.StopOrderPair, error) {
 	// Validate input parameters
@@ -282,6 +562,9 @@ func (s *stopLossService) SetStopLossTakeProfit(symbol string, position float64,
 		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "target price must be greater than 0", 0, nil)
 	}
 
+	// Both legs carry the pair ID, so triggering one cancels the other
+	pairID := generateOrderID("PAIR")
+
 	// Create stop loss order
 	stopLossOrder := &repository.StopOrder{
 		OrderID:   generateOrderID("SL"),
@@ -290,7 +573,8 @@ func (s *stopLossService) SetStopLossTakeProfit(symbol string, position float64,
 		StopPrice: stopPrice,
 		Type:      repository.StopOrderTypeStopLoss,
 		Status:    repository.StopOrderStatusActive,
-		CreatedAt: time.Now().Unix(),
+		CreatedAt: timeutil.NowMillis(),
+		PairID:    pairID,
 	}
 
 	// Create take profit order
@@ -301,12 +585,13 @@ func (s *stopLossService) SetStopLossTakeProfit(symbol string, position float64,
 		StopPrice: targetPrice,
 		Type:      repository.StopOrderTypeTakeProfit,
 		Status:    repository.StopOrderStatusActive,
-		CreatedAt: time.Now().Unix(),
+		CreatedAt: timeutil.NowMillis(),
+		PairID:    pairID,
 	}
 
 	// Create order pair
 	orderPair := &repository.StopOrderPair{
-		PairID:          generateOrderID("PAIR"),
+		PairID:          pairID,
 		Symbol:          symbol,
 		Position:        position,
 		StopLossOrder:   stopLossOrder,
@@ -411,8 +696,8 @@ func (s *stopLossService) SetTrailingStop(symbol string, position float64, trail
 		HighestPrice:     currentPrice,
 		CurrentStopPrice: initialStopPrice,
 		Status:           repository.StopOrderStatusActive,
-		CreatedAt:        time.Now().Unix(),
-		LastUpdatedAt:    time.Now().Unix(),
+		CreatedAt:        timeutil.NowMillis(),
+		LastUpdatedAt:    timeutil.NowMillis(),
 	}
 
 	// Save to repository
@@ -438,24 +723,30 @@ func (s *stopLossService) SetTrailingStop(symbol string, position float64, trail
 	return trailingStopOrder, nil
 }
 
-// CancelStopOrder cancels a stop order
-func (s *stopLossService) CancelStopOrder(orderID string) error {
+// CancelStopOrder cancels a stop order; a non-empty symbol must match the order's symbol.
+// The cancelled order is returned so callers can show what was cancelled.
+func (s *stopLossService) CancelStopOrder(orderID, symbol string) (*CancelledStopOrder, error) {
 	// Validate input
 	if orderID == "" {
-		return errors.NewTradingError(errors.ErrInvalidParameter, "order ID cannot be empty", 0, nil)
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "order ID cannot be empty", 0, nil)
 	}
 
 	// Try to find as regular stop order
 	stopOrder, err := s.stopOrderRepo.FindStopOrderByID(orderID)
 	if err == nil {
+		if err := checkOrderSymbol(orderID, stopOrder.Symbol, symbol); err != nil {
+			return nil, err
+		}
+
 		// Update status to cancelled
 		if err := s.stopOrderRepo.UpdateStopOrderStatus(orderID, repository.StopOrderStatusCancelled, 0, 0); err != nil {
 			s.logger.LogError(err, map[string]interface{}{
 				"operation": "cancel_stop_order",
 				"order_id":  orderID,
 			})
-			return err
+			return nil, err
 		}
+		stopOrder.Status = repository.StopOrderStatusCancelled
 
 		// Unregister trigger condition
 		s.triggerEngine.UnregisterCondition(orderID)
@@ -465,12 +756,16 @@ func (s *stopLossService) CancelStopOrder(orderID string) error {
 			"symbol":   stopOrder.Symbol,
 		})
 
-		return nil
+		return &CancelledStopOrder{StopOrder: stopOrder}, nil
 	}
 
 	// Try to find as trailing stop order
 	trailingOrder, err := s.stopOrderRepo.FindTrailingStopOrderByID(orderID)
 	if err == nil {
+		if err := checkOrderSymbol(orderID, trailingOrder.Symbol, symbol); err != nil {
+			return nil, err
+		}
+
 		// Update status to cancelled
 		trailingOrder.Status = repository.StopOrderStatusCancelled
 		if err := s.stopOrderRepo.UpdateTrailingStopOrder(trailingOrder); err != nil {
@@ -478,7 +773,7 @@ func (s *stopLossService) CancelStopOrder(orderID string) error {
 				"operation": "cancel_stop_order",
 				"order_id":  orderID,
 			})
-			return err
+			return nil, err
 		}
 
 		s.logger.Info("Trailing stop order cancelled", map[string]interface{}{
@@ -486,11 +781,11 @@ func (s *stopLossService) CancelStopOrder(orderID string) error {
 			"symbol":   trailingOrder.Symbol,
 		})
 
-		return nil
+		return &CancelledStopOrder{TrailingOrder: trailingOrder}, nil
 	}
 
 	// Order not found
-	return errors.NewTradingError(errors.ErrStopOrderNotFound, "stop order not found", 0, nil)
+	return nil, errors.NewTradingError(errors.ErrStopOrderNotFound, "stop order not found", 0, nil)
 }
 
 // GetActiveStopOrders retrieves all active stop orders for a symbol
@@ -539,10 +834,11 @@ func (s *stopLossService) UpdateTrailingStop(orderID string, newTrailPercent flo
 		return err
 	}
 
-	// Update trail percent and recalculate stop price
+	// Update trail percent and recalculate stop price; an explicit percent ends ATR mode
+	trailingOrder.TrailMode = repository.TrailModePercent
 	trailingOrder.TrailPercent = newTrailPercent
 	trailingOrder.CurrentStopPrice = trailingOrder.HighestPrice * (1 - newTrailPercent/100)
-	trailingOrder.LastUpdatedAt = time.Now().Unix()
+	trailingOrder.LastUpdatedAt = timeutil.NowMillis()
 
 	// Save updated order
 	if err := s.stopOrderRepo.UpdateTrailingStopOrder(trailingOrder); err != nil {
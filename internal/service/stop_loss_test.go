@@ -3,6 +3,11 @@ package service
 import (
 	"binance-trader/internal/api"
 	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	"binance-trader/pkg/metrics"
+	"context"
+	"fmt"
+	"strings"
 	"math"
 	"testing"
 	"time"
@@ -280,6 +285,10 @@ func (m *mockStopLossTradingService) PlaceMarketBuyOrder(symbol string, quantity
 	}, nil
 }
 
+func (m *mockStopLossTradingService) PlaceMarketBuyOrderByQuote(symbol string, quoteAmount float64) (*api.Order, error) {
+	return m.PlaceMarketBuyOrder(symbol, 0)
+}
+
 func (m *mockStopLossTradingService) PlaceLimitSellOrder(symbol string, price, quantity float64) (*api.Order, error) {
 	return &api.Order{
 		OrderID: 12346,
@@ -288,10 +297,30 @@ func (m *mockStopLossTradingService) PlaceLimitSellOrder(symbol string, price, q
 	}, nil
 }
 
+func (m *mockStopLossTradingService) PlaceLimitBuyOrder(symbol string, price, quantity float64) (*api.Order, error) {
+	return &api.Order{
+		OrderID: 12347,
+		Symbol:  symbol,
+		Status:  api.OrderStatusNew,
+	}, nil
+}
+
+func (m *mockStopLossTradingService) PlaceOCOOrder(symbol string, side api.OrderSide, quantity, price, stopPrice, stopLimitPrice float64) (*api.OCOResponse, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockStopLossTradingService) CancelOCOOrder(symbol string, orderListID int64) error {
+	return nil
+}
+
 func (m *mockStopLossTradingService) CancelOrder(orderID int64) error {
 	return nil
 }
 
+func (m *mockStopLossTradingService) CancelAllOrders(symbol string) (int, error) {
+	return 0, nil
+}
+
 func (m *mockStopLossTradingService) GetOrderStatus(orderID int64) (*OrderStatus, error) {
 	return &OrderStatus{
 		OrderID: orderID,
@@ -303,6 +332,41 @@ func (m *mockStopLossTradingService) GetActiveOrders() ([]*api.Order, error) {
 	return []*api.Order{}, nil
 }
 
+func (m *mockStopLossTradingService) GetAllBalances() ([]api.Balance, error) {
+	return nil, nil
+}
+
+func (m *mockStopLossTradingService) GetOrderLifecycle(symbol string, orderID int64) (*OrderLifecycle, error) {
+	return nil, nil
+}
+
+func (m *mockStopLossTradingService) SetSymbolGuard(guard SymbolFailureGuard) {}
+
+func (m *mockStopLossTradingService) SetMetrics(sink *metrics.Sink) {}
+
+func (m *mockStopLossTradingService) SubmitOrderIntent(intent *OrderIntent) (*api.Order, error) {
+	return nil, nil
+}
+
+func (m *mockStopLossTradingService) SetReplayProtection(protection ReplayProtection) {}
+
+func (m *mockStopLossTradingService) SetUserDataStream(stream api.UserDataStream) error {
+	return nil
+}
+
+func (m *mockStopLossTradingService) SubscribeOrderUpdates(handler func(*api.Order)) (func(), error) {
+	return func() {}, nil
+}
+
+func (m *mockStopLossTradingService) SetSlippageProtection(marketData MarketDataService, maxSlippagePercent float64) {
+}
+
+func (m *mockStopLossTradingService) SetSymbolFilter(filter *SymbolFilter) {}
+
+func (m *mockStopLossTradingService) ExecuteTWAP(ctx context.Context, symbol string, side api.OrderSide, totalQty float64, duration time.Duration, slices int) (*TWAPExecution, error) {
+	return nil, nil
+}
+
 type mockStopLossMarketDataService struct {
 	currentPrice float64
 }
@@ -319,6 +383,24 @@ func (m *mockStopLossMarketDataService) SubscribeToPrice(symbol string, callback
 	return nil
 }
 
+func (m *mockStopLossMarketDataService) GetBestBidAsk(symbol string) (*BestBidAsk, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockStopLossMarketDataService) SetBookTickerCache(cache BookTickerCache) {}
+
+func (m *mockStopLossMarketDataService) GetOrderBook(symbol string, limit int) (*api.OrderBook, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockStopLossMarketDataService) SetPriceStream(stream PriceStream) {}
+
+func (m *mockStopLossMarketDataService) SubscribePriceFeed(ctx context.Context, symbol string) (<-chan float64, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockStopLossMarketDataService) ConnectedStreams() []string { return nil }
+
 func (m *mockStopLossMarketDataService) GetVolume(symbol string, timeWindow time.Duration) (float64, error) {
 	return 1000.0, nil
 }
@@ -599,11 +681,20 @@ func TestCancelStopOrder(t *testing.T) {
 	t.Run("cancel stop loss order", func(t *testing.T) {
 		stopOrder, _ := service.SetStopLoss("BTCUSDT", 1.0, 45000.0)
 
-		err := service.CancelStopOrder(stopOrder.OrderID)
+		cancelled, err := service.CancelStopOrder(stopOrder.OrderID, "BTCUSDT")
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
 
+		// The cancelled order is returned for confirmation
+		if cancelled.StopOrder == nil || cancelled.TrailingOrder != nil {
+			t.Fatalf("expected the stop order in the result, got %+v", cancelled)
+		}
+		if cancelled.StopOrder.OrderID != stopOrder.OrderID || cancelled.StopOrder.StopPrice != 45000.0 ||
+			cancelled.StopOrder.Status != repository.StopOrderStatusCancelled {
+			t.Errorf("unexpected cancelled order: %+v", cancelled.StopOrder)
+		}
+
 		// Verify order is cancelled
 		order, _ := stopOrderRepo.FindStopOrderByID(stopOrder.OrderID)
 		if order.Status != repository.StopOrderStatusCancelled {
@@ -615,10 +706,13 @@ func TestCancelStopOrder(t *testing.T) {
 	t.Run("cancel trailing stop order", func(t *testing.T) {
 		trailingOrder, _ := service.SetTrailingStop("BTCUSDT", 1.0, 2.0)
 
-		err := service.CancelStopOrder(trailingOrder.OrderID)
+		cancelled, err := service.CancelStopOrder(trailingOrder.OrderID, "")
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
+		if cancelled.TrailingOrder == nil || cancelled.TrailingOrder.TrailPercent != 2.0 || cancelled.StopOrder != nil {
+			t.Errorf("expected the trailing stop order in the result, got %+v", cancelled)
+		}
 
 		// Verify order is cancelled
 		order, _ := stopOrderRepo.FindTrailingStopOrderByID(trailingOrder.OrderID)
@@ -629,18 +723,41 @@ func TestCancelStopOrder(t *testing.T) {
 
 	// Test invalid order ID
 	t.Run("empty order ID", func(t *testing.T) {
-		err := service.CancelStopOrder("")
+		_, err := service.CancelStopOrder("", "")
 		if err == nil {
 			t.Error("expected error for empty order ID")
 		}
 	})
 
 	t.Run("non-existent order ID", func(t *testing.T) {
-		err := service.CancelStopOrder("non-existent")
+		_, err := service.CancelStopOrder("non-existent", "")
 		if err == nil {
 			t.Error("expected error for non-existent order ID")
 		}
 	})
+
+	// An ID of another symbol's order is refused and the order stays active
+	t.Run("symbol mismatch", func(t *testing.T) {
+		stopOrder, _ := service.SetStopLoss("ETHUSDT", 2.0, 2800.0)
+		trailingOrder, _ := service.SetTrailingStop("ETHUSDT", 2.0, 3.0)
+
+		for _, orderID := range []string{stopOrder.OrderID, trailingOrder.OrderID} {
+			cancelled, err := service.CancelStopOrder(orderID, "BTCUSDT")
+			tradingErr, ok := err.(*errors.TradingError)
+			if !ok || tradingErr.Type != errors.ErrInvalidParameter || cancelled != nil {
+				t.Fatalf("%s: expected an invalid parameter error, got %v", orderID, err)
+			}
+			if !strings.Contains(err.Error(), "belongs to ETHUSDT, not BTCUSDT") {
+				t.Errorf("%s: unexpected message %q", orderID, err.Error())
+			}
+		}
+
+		order, _ := stopOrderRepo.FindStopOrderByID(stopOrder.OrderID)
+		trailing, _ := stopOrderRepo.FindTrailingStopOrderByID(trailingOrder.OrderID)
+		if order.Status != repository.StopOrderStatusActive || trailing.Status != repository.StopOrderStatusActive {
+			t.Errorf("expected both orders to stay active, got %s and %s", order.Status, trailing.Status)
+		}
+	})
 }
 
 func TestGetActiveStopOrders(t *testing.T) {
@@ -661,7 +778,7 @@ func TestGetActiveStopOrders(t *testing.T) {
 		stopOrder3, _ := service.SetStopLoss("BTCUSDT", 0.5, 46000.0)
 
 		// Cancel one order
-		service.CancelStopOrder(stopOrder3.OrderID)
+		service.CancelStopOrder(stopOrder3.OrderID, "")
 
 		orders, err := service.GetActiveStopOrders("BTCUSDT")
 		if err != nil {
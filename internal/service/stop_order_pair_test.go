@@ -0,0 +1,105 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// countingSellTradingService counts market sells and is safe for concurrent use
+type countingSellTradingService struct {
+	mockStopLossTradingService
+	sells atomic.Int32
+}
+
+func (m *countingSellTradingService) PlaceMarketSellOrder(symbol string, quantity float64) (*api.Order, error) {
+	n := m.sells.Add(1)
+	return &api.Order{OrderID: int64(n), Symbol: symbol, Status: api.OrderStatusFilled}, nil
+}
+
+func TestMonitoringEngine_StopLossTakeProfitPair(t *testing.T) {
+	tests := []struct {
+		name          string
+		prices        []float64
+		wantTriggered repository.StopOrderType
+	}{
+		{name: "stop then target", prices: []float64{50000, 47900, 55100, 56000}, wantTriggered: repository.StopOrderTypeStopLoss},
+		{name: "target then stop", prices: []float64{50000, 55000, 47000, 46000}, wantTriggered: repository.StopOrderTypeTakeProfit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stopOrderRepo := repository.NewMemoryStopOrderRepository()
+			triggerEngine := NewTriggerEngine()
+			trading := &recordingSellTradingService{}
+			market := &mockStopLossMarketDataService{currentPrice: tt.prices[0]}
+			stopLoss := NewStopLossService(stopOrderRepo, triggerEngine, trading, market, &mockLogger{})
+
+			engine := NewMonitoringEngine(repository.NewMemoryConditionalOrderRepository(), stopOrderRepo,
+				triggerEngine, trading, market, stopLoss, &mockLogger{}, nil)
+
+			pair, err := stopLoss.SetStopLossTakeProfit("BTCUSDT", 0.5, 48000, 55000)
+			if err != nil {
+				t.Fatalf("SetStopLossTakeProfit() error = %v", err)
+			}
+			if pair.StopLossOrder.PairID != pair.PairID || pair.TakeProfitOrder.PairID != pair.PairID {
+				t.Fatalf("legs not linked to pair %s: %q / %q", pair.PairID, pair.StopLossOrder.PairID, pair.TakeProfitOrder.PairID)
+			}
+
+			// One monitoring tick per price
+			for _, price := range tt.prices {
+				market.currentPrice = price
+				engine.marketDataCache = make(map[string]*MarketData)
+				engine.checkAndTriggerOrders()
+			}
+
+			if len(trading.sells) != 1 || trading.sells[0] != 0.5 {
+				t.Fatalf("sells = %v, want a single sell of 0.5", trading.sells)
+			}
+
+			triggered, cancelled := pair.StopLossOrder.OrderID, pair.TakeProfitOrder.OrderID
+			if tt.wantTriggered == repository.StopOrderTypeTakeProfit {
+				triggered, cancelled = cancelled, triggered
+			}
+			if order, _ := stopOrderRepo.FindStopOrderByID(triggered); order.Status != repository.StopOrderStatusTriggered || order.ExecutedOrderID == 0 {
+				t.Errorf("triggered leg = %s / %d, want TRIGGERED with an executed order", order.Status, order.ExecutedOrderID)
+			}
+			if order, _ := stopOrderRepo.FindStopOrderByID(cancelled); order.Status != repository.StopOrderStatusCancelled {
+				t.Errorf("other leg = %s, want CANCELLED", order.Status)
+			}
+		})
+	}
+}
+
+func TestStopLossTakeProfitPair_ConcurrentTriggers(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		stopOrderRepo := repository.NewMemoryStopOrderRepository()
+		trading := &countingSellTradingService{}
+		svc := NewStopLossService(stopOrderRepo, NewTriggerEngine(), trading,
+			&mockStopLossMarketDataService{currentPrice: 50000}, &mockLogger{}).(*stopLossService)
+
+		pair, err := svc.SetStopLossTakeProfit("BTCUSDT", 1, 48000, 55000)
+		if err != nil {
+			t.Fatalf("SetStopLossTakeProfit() error = %v", err)
+		}
+
+		// Both legs see a price reaching them at the same time
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			svc.ExecuteStopLossIfReached(pair.StopLossOrder.OrderID, 47000)
+		}()
+		go func() {
+			defer wg.Done()
+			svc.ExecuteTakeProfitIfReached(pair.TakeProfitOrder.OrderID, 56000)
+		}()
+		wg.Wait()
+
+		if sells := trading.sells.Load(); sells != 1 {
+			t.Fatalf("run %d: %d sells, want 1", i, sells)
+		}
+	}
+}
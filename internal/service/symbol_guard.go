@@ -0,0 +1,211 @@
+package service
+
+import (
+	"binance-trader/internal/config"
+	"binance-trader/pkg/errors"
+	"binance-trader/pkg/logger"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultFailureWindow is used when trading.failure_pause.window_ms is not configured
+	DefaultFailureWindow = 10 * time.Minute
+	// DefaultPauseCooldown is used when trading.failure_pause.cooldown_ms is not configured
+	DefaultPauseCooldown = 30 * time.Minute
+)
+
+// SymbolPause describes a symbol whose new orders are paused
+type SymbolPause struct {
+	Symbol    string
+	Failures  int
+	LastError string
+	PausedAt  int64
+	ResumeAt  int64
+}
+
+// SymbolFailureGuard pauses new orders for symbols that repeatedly fail
+type SymbolFailureGuard interface {
+	// CheckSymbol returns an error if new orders for the symbol are paused
+	CheckSymbol(symbol string) error
+
+	// RecordFailure records a failed order; reaching the threshold within the window pauses the symbol
+	RecordFailure(symbol string, err error)
+	RecordSuccess(symbol string)
+
+	// ClearPause lifts a pause manually; returns false if the symbol was not paused
+	ClearPause(symbol string) bool
+	GetPausedSymbols() []*SymbolPause
+}
+
+// symbolFailureGuard implements SymbolFailureGuard interface
+type symbolFailureGuard struct {
+	maxFailures int
+	window      time.Duration
+	cooldown    time.Duration
+	logger      logger.Logger
+	now         func() time.Time
+
+	mu       sync.Mutex
+	failures map[string][]time.Time
+	paused   map[string]*SymbolPause
+}
+
+// NewSymbolFailureGuard creates a new symbol failure guard; a zero max_failures disables pausing
+func NewSymbolFailureGuard(cfg *config.FailurePauseConfig, log logger.Logger) SymbolFailureGuard {
+	guard := &symbolFailureGuard{
+		window:   DefaultFailureWindow,
+		cooldown: DefaultPauseCooldown,
+		logger:   log,
+		now:      time.Now,
+		failures: make(map[string][]time.Time),
+		paused:   make(map[string]*SymbolPause),
+	}
+
+	if cfg != nil {
+		guard.maxFailures = cfg.MaxFailures
+		if cfg.WindowMs > 0 {
+			guard.window = time.Duration(cfg.WindowMs) * time.Millisecond
+		}
+		if cfg.CooldownMs > 0 {
+			guard.cooldown = time.Duration(cfg.CooldownMs) * time.Millisecond
+		}
+	}
+
+	return guard
+}
+
+// CheckSymbol returns an error if the symbol is paused and the cooldown has not elapsed
+func (g *symbolFailureGuard) CheckSymbol(symbol string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	pause, exists := g.paused[symbol]
+	if !exists {
+		return nil
+	}
+
+	// Lift the pause automatically once the cooldown has elapsed
+	if g.now().Unix() >= pause.ResumeAt {
+		delete(g.paused, symbol)
+		g.logger.Info("Symbol pause expired, resuming new orders", map[string]interface{}{
+			"symbol": symbol,
+		})
+		return nil
+	}
+
+	return errors.NewTradingError(
+		errors.ErrSymbolPaused,
+		fmt.Sprintf("new orders for %s are paused after %d failed orders until %s",
+			symbol, pause.Failures, time.Unix(pause.ResumeAt, 0).Format("2006-01-02 15:04:05")),
+		0,
+		nil,
+	)
+}
+
+// RecordFailure records a symbol-specific order failure and pauses the symbol when the threshold is reached
+func (g *symbolFailureGuard) RecordFailure(symbol string, err error) {
+	if g.maxFailures <= 0 || symbol == "" || err == nil || !isSymbolFailure(err) {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, exists := g.paused[symbol]; exists {
+		return
+	}
+
+	now := g.now()
+	cutoff := now.Add(-g.window)
+
+	// Keep only failures within the window
+	recent := make([]time.Time, 0, len(g.failures[symbol])+1)
+	for _, failedAt := range g.failures[symbol] {
+		if failedAt.After(cutoff) {
+			recent = append(recent, failedAt)
+		}
+	}
+	recent = append(recent, now)
+
+	if len(recent) < g.maxFailures {
+		g.failures[symbol] = recent
+		return
+	}
+
+	delete(g.failures, symbol)
+	pause := &SymbolPause{
+		Symbol:    symbol,
+		Failures:  len(recent),
+		LastError: err.Error(),
+		PausedAt:  now.Unix(),
+		ResumeAt:  now.Add(g.cooldown).Unix(),
+	}
+	g.paused[symbol] = pause
+
+	g.logger.Warn("Symbol paused after repeated order failures, new orders blocked until cooldown ends", map[string]interface{}{
+		"symbol":     symbol,
+		"failures":   pause.Failures,
+		"window":     g.window.String(),
+		"cooldown":   g.cooldown.String(),
+		"last_error": pause.LastError,
+	})
+}
+
+// RecordSuccess resets the failure count for a symbol
+func (g *symbolFailureGuard) RecordSuccess(symbol string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.failures, symbol)
+}
+
+// ClearPause lifts a pause manually
+func (g *symbolFailureGuard) ClearPause(symbol string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.failures, symbol)
+	if _, exists := g.paused[symbol]; !exists {
+		return false
+	}
+	delete(g.paused, symbol)
+
+	g.logger.Info("Symbol pause cleared manually", map[string]interface{}{
+		"symbol": symbol,
+	})
+	return true
+}
+
+// GetPausedSymbols returns the currently paused symbols sorted by symbol
+func (g *symbolFailureGuard) GetPausedSymbols() []*SymbolPause {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	nowUnix := g.now().Unix()
+	pauses := make([]*SymbolPause, 0, len(g.paused))
+	for symbol, pause := range g.paused {
+		if nowUnix >= pause.ResumeAt {
+			delete(g.paused, symbol)
+			continue
+		}
+		pauseCopy := *pause
+		pauses = append(pauses, &pauseCopy)
+	}
+	sort.Slice(pauses, func(i, j int) bool {
+		return pauses[i].Symbol < pauses[j].Symbol
+	})
+
+	return pauses
+}
+
+// isSymbolFailure reports whether an order error is likely caused by the symbol itself
+// rather than transient network or rate limit conditions
+func isSymbolFailure(err error) bool {
+	tradingErr, ok := err.(*errors.TradingError)
+	if !ok {
+		return true
+	}
+	return tradingErr.Type != errors.ErrNetwork && tradingErr.Type != errors.ErrRateLimit && tradingErr.Type != errors.ErrSafeMode
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	"testing"
+	"time"
+)
+
+// newTestSymbolGuard creates a guard with an injectable clock
+func newTestSymbolGuard(maxFailures int, window, cooldown time.Duration) (*symbolFailureGuard, *time.Time) {
+	guard := NewSymbolFailureGuard(&config.FailurePauseConfig{
+		MaxFailures: maxFailures,
+		WindowMs:    int(window / time.Millisecond),
+		CooldownMs:  int(cooldown / time.Millisecond),
+	}, &mockLogger{}).(*symbolFailureGuard)
+
+	current := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	guard.now = func() time.Time { return current }
+	return guard, &current
+}
+
+func TestSymbolFailureGuard_PausesAfterThreshold(t *testing.T) {
+	guard, _ := newTestSymbolGuard(3, time.Minute, 10*time.Minute)
+	orderErr := errors.NewTradingError(errors.ErrInvalidParameter, "filter failure: LOT_SIZE", 400, nil)
+
+	guard.RecordFailure("BTCUSDT", orderErr)
+	guard.RecordFailure("BTCUSDT", orderErr)
+	if err := guard.CheckSymbol("BTCUSDT"); err != nil {
+		t.Fatalf("Expected symbol to be allowed below threshold, got %v", err)
+	}
+
+	guard.RecordFailure("BTCUSDT", orderErr)
+	err := guard.CheckSymbol("BTCUSDT")
+	if err == nil {
+		t.Fatal("Expected symbol to be paused after reaching threshold")
+	}
+	if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrSymbolPaused {
+		t.Errorf("Expected ErrSymbolPaused, got %v", err)
+	}
+
+	// Other symbols are unaffected
+	if err := guard.CheckSymbol("ETHUSDT"); err != nil {
+		t.Errorf("Expected other symbols to be allowed, got %v", err)
+	}
+
+	pauses := guard.GetPausedSymbols()
+	if len(pauses) != 1 || pauses[0].Symbol != "BTCUSDT" || pauses[0].Failures != 3 {
+		t.Errorf("Unexpected paused symbols: %+v", pauses)
+	}
+}
+
+func TestSymbolFailureGuard_AutoResumeAfterCooldown(t *testing.T) {
+	guard, current := newTestSymbolGuard(2, time.Minute, 10*time.Minute)
+	orderErr := errors.NewTradingError(errors.ErrInvalidParameter, "invalid symbol", 400, nil)
+
+	guard.RecordFailure("BTCUSDT", orderErr)
+	guard.RecordFailure("BTCUSDT", orderErr)
+	if err := guard.CheckSymbol("BTCUSDT"); err == nil {
+		t.Fatal("Expected symbol to be paused")
+	}
+
+	*current = current.Add(9 * time.Minute)
+	if err := guard.CheckSymbol("BTCUSDT"); err == nil {
+		t.Fatal("Expected symbol to stay paused before cooldown ends")
+	}
+
+	*current = current.Add(time.Minute)
+	if err := guard.CheckSymbol("BTCUSDT"); err != nil {
+		t.Errorf("Expected symbol to resume after cooldown, got %v", err)
+	}
+	if pauses := guard.GetPausedSymbols(); len(pauses) != 0 {
+		t.Errorf("Expected no paused symbols, got %d", len(pauses))
+	}
+}
+
+func TestSymbolFailureGuard_WindowAndFailureClassification(t *testing.T) {
+	guard, current := newTestSymbolGuard(2, time.Minute, 10*time.Minute)
+	orderErr := errors.NewTradingError(errors.ErrInvalidParameter, "invalid quantity", 400, nil)
+
+	// Failures outside the window do not accumulate
+	guard.RecordFailure("BTCUSDT", orderErr)
+	*current = current.Add(2 * time.Minute)
+	guard.RecordFailure("BTCUSDT", orderErr)
+	if err := guard.CheckSymbol("BTCUSDT"); err != nil {
+		t.Errorf("Expected failures outside the window to be ignored, got %v", err)
+	}
+
+	// A success resets the count
+	guard.RecordSuccess("BTCUSDT")
+	guard.RecordFailure("BTCUSDT", orderErr)
+	if err := guard.CheckSymbol("BTCUSDT"); err != nil {
+		t.Errorf("Expected success to reset failures, got %v", err)
+	}
+
+	// Network and rate limit errors are not symbol-specific
+	networkErr := errors.NewTradingError(errors.ErrNetwork, "HTTP server error: 503", 503, nil)
+	rateLimitErr := errors.NewTradingError(errors.ErrRateLimit, "rate limit exceeded", 429, nil)
+	guard.RecordFailure("ETHUSDT", networkErr)
+	guard.RecordFailure("ETHUSDT", rateLimitErr)
+	guard.RecordFailure("ETHUSDT", networkErr)
+	if err := guard.CheckSymbol("ETHUSDT"); err != nil {
+		t.Errorf("Expected transient errors to be ignored, got %v", err)
+	}
+	// Writes rejected by safe mode never reached the exchange
+	safeModeErr := errors.NewTradingError(errors.ErrSafeMode, "safe mode active: order placement is disabled", 0, nil)
+	for i := 0; i < 3; i++ {
+		guard.RecordFailure("SOLUSDT", safeModeErr)
+	}
+	if err := guard.CheckSymbol("SOLUSDT"); err != nil {
+		t.Errorf("Expected safe mode rejections to be ignored, got %v", err)
+	}
+}
+
+func TestSymbolFailureGuard_ManualClearAndDisabled(t *testing.T) {
+	guard, _ := newTestSymbolGuard(1, time.Minute, time.Hour)
+	orderErr := errors.NewTradingError(errors.ErrInvalidParameter, "invalid symbol", 400, nil)
+
+	guard.RecordFailure("BTCUSDT", orderErr)
+	if err := guard.CheckSymbol("BTCUSDT"); err == nil {
+		t.Fatal("Expected symbol to be paused")
+	}
+	if !guard.ClearPause("BTCUSDT") {
+		t.Fatal("Expected ClearPause to return true for a paused symbol")
+	}
+	if err := guard.CheckSymbol("BTCUSDT"); err != nil {
+		t.Errorf("Expected symbol to be allowed after manual clear, got %v", err)
+	}
+	if guard.ClearPause("BTCUSDT") {
+		t.Error("Expected ClearPause to return false for a symbol that is not paused")
+	}
+
+	disabled, _ := newTestSymbolGuard(0, time.Minute, time.Hour)
+	for i := 0; i < 10; i++ {
+		disabled.RecordFailure("BTCUSDT", orderErr)
+	}
+	if err := disabled.CheckSymbol("BTCUSDT"); err != nil {
+		t.Errorf("Expected disabled guard to never pause, got %v", err)
+	}
+}
+
+func TestSpotTradingService_SymbolGuardBlocksNewBuysOnly(t *testing.T) {
+	orderErr := errors.NewTradingError(errors.ErrInvalidParameter, "filter failure: PRICE_FILTER", 400, nil)
+	failing := true
+	createCalls := 0
+	mockClient := &mockBinanceClient{
+		getBalanceFunc: func(asset string) (*api.Balance, error) {
+			return &api.Balance{Asset: asset, Free: 10000.0, Locked: 0}, nil
+		},
+		createOrderFunc: func(order *api.OrderRequest) (*api.OrderResponse, error) {
+			createCalls++
+			if failing {
+				return nil, orderErr
+			}
+			return &api.OrderResponse{OrderID: int64(createCalls), Symbol: order.Symbol, Status: api.OrderStatusFilled}, nil
+		},
+	}
+
+	riskMgr := NewRiskManager(&RiskLimits{
+		MaxOrderAmount:    100000.0,
+		MaxDailyOrders:    100,
+		MinBalanceReserve: 100.0,
+	}, mockClient)
+
+	svc := NewSpotTradingService(mockClient, riskMgr, repository.NewMemoryOrderRepository(), &mockLogger{})
+	guard, current := newTestSymbolGuard(2, time.Minute, 5*time.Minute)
+	svc.SetSymbolGuard(guard)
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.PlaceMarketBuyOrder("BTCUSDT", 0.01); err == nil {
+			t.Fatal("Expected order failure")
+		}
+	}
+
+	// Paused symbol: the buy is rejected without reaching the exchange
+	failing = false
+	callsBefore := createCalls
+	if _, err := svc.PlaceMarketBuyOrder("BTCUSDT", 0.01); err == nil {
+		t.Fatal("Expected buy to be rejected while paused")
+	}
+	if createCalls != callsBefore {
+		t.Errorf("Expected no API call while paused, got %d", createCalls-callsBefore)
+	}
+
+	// Selling existing holdings (e.g. stop loss execution) is still allowed
+	if _, err := svc.PlaceMarketSellOrder("BTCUSDT", 0.01); err != nil {
+		t.Errorf("Expected sell to be allowed while paused, got %v", err)
+	}
+
+	*current = current.Add(5 * time.Minute)
+	if _, err := svc.PlaceMarketBuyOrder("BTCUSDT", 0.01); err != nil {
+		t.Errorf("Expected buy to be allowed after cooldown, got %v", err)
+	}
+}
@@ -0,0 +1,487 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/logger"
+	"binance-trader/pkg/timeutil"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSymbolStatusInterval is used when symbol_status.refresh_interval_ms is not configured
+const DefaultSymbolStatusInterval = time.Minute
+
+// SymbolSuspender pauses and resumes the local automation of a symbol while it is not trading
+type SymbolSuspender interface {
+	// SuspendSymbol pauses the active orders of the symbol and returns how many were paused
+	SuspendSymbol(symbol string) (int, error)
+
+	// ResumeSymbol reactivates the suspended orders of the symbol and returns how many were resumed
+	ResumeSymbol(symbol string) (int, error)
+}
+
+// SymbolSuspension describes a symbol whose automation is suspended
+type SymbolSuspension struct {
+	Symbol      string
+	Status      string // Exchange status, e.g. BREAK
+	SuspendedAt int64  // Unix ms
+	Orders      int    // Local orders suspended
+	Cancelled   int    // Open exchange orders cancelled
+}
+
+// SymbolStatusChange is a symbol whose automation was suspended or resumed by a refresh
+type SymbolStatusChange struct {
+	Market    string
+	Symbol    string
+	Status    string
+	Resumed   bool
+	Orders    int // Local orders suspended or resumed
+	Cancelled int // Open exchange orders cancelled
+	Errors    []string
+}
+
+// Summary describes the change in one line
+func (c *SymbolStatusChange) Summary() string {
+	var b strings.Builder
+	if c.Resumed {
+		fmt.Fprintf(&b, "%s %s is trading again; resumed %d orders", c.Market, c.Symbol, c.Orders)
+	} else {
+		fmt.Fprintf(&b, "%s %s is %s; suspended %d orders", c.Market, c.Symbol, c.Status, c.Orders)
+		if c.Cancelled > 0 {
+			fmt.Fprintf(&b, ", cancelled %d open orders", c.Cancelled)
+		}
+	}
+	if len(c.Errors) > 0 {
+		fmt.Fprintf(&b, " (%d errors: %s)", len(c.Errors), strings.Join(c.Errors, "; "))
+	}
+	return b.String()
+}
+
+// SymbolStatusMonitor refreshes the trading status of every symbol from exchange info and
+// suspends the automation of symbols that are halted or delisted until they trade again
+type SymbolStatusMonitor interface {
+	// Refresh reloads exchange info and returns the symbols suspended or resumed
+	Refresh() ([]*SymbolStatusChange, error)
+
+	// GetSuspendedSymbols returns the suspended symbols sorted by symbol
+	GetSuspendedSymbols() []*SymbolSuspension
+
+	// OnChange registers a callback invoked for every suspension and resumption
+	OnChange(callback func(change *SymbolStatusChange))
+
+	StartMonitoring(refreshInterval time.Duration) error
+	StopMonitoring() error
+}
+
+// symbolOpenOrder is an open exchange order that can be cancelled
+type symbolOpenOrder struct {
+	orderID     int64
+	orderListID int64 // Cancelling one leg of an order list cancels the others
+}
+
+// symbolStatusMonitor implements SymbolStatusMonitor for one market
+type symbolStatusMonitor struct {
+	market     string
+	suspenders []SymbolSuspender
+	cancelOpen bool
+	logger     logger.Logger
+	now        func() time.Time
+
+	// Exchange access, replaceable in tests
+	fetch      func() ([]*api.ExchangeSymbol, error)
+	openOrders func() (map[string][]*symbolOpenOrder, error)
+	cancel     func(symbol string, orderID int64) error
+
+	refreshMu sync.Mutex // Serializes refreshes
+	mu        sync.Mutex
+	statuses  map[string]string // Status of every symbol at the last refresh
+	suspended map[string]*SymbolSuspension
+	callbacks []func(change *SymbolStatusChange)
+
+	monitoringMu sync.Mutex
+	isMonitoring bool
+	stopChan     chan struct{}
+}
+
+// NewSpotSymbolStatusMonitor creates a monitor for spot symbols
+func NewSpotSymbolStatusMonitor(client api.SpotClient, cfg *config.SymbolStatusConfig, log logger.Logger, suspenders ...SymbolSuspender) SymbolStatusMonitor {
+	m := newSymbolStatusMonitor(CoverageMarketSpot, cfg, log, suspenders)
+	m.fetch = client.GetExchangeSymbols
+	m.openOrders = func() (map[string][]*symbolOpenOrder, error) {
+		orders, err := client.GetOpenOrders("")
+		if err != nil {
+			return nil, err
+		}
+		bySymbol := make(map[string][]*symbolOpenOrder)
+		for _, order := range orders {
+			bySymbol[order.Symbol] = append(bySymbol[order.Symbol], &symbolOpenOrder{orderID: order.OrderID, orderListID: order.OrderListID})
+		}
+		return bySymbol, nil
+	}
+	m.cancel = func(symbol string, orderID int64) error {
+		_, err := client.CancelOrder(symbol, orderID)
+		return err
+	}
+	return m
+}
+
+// NewFuturesSymbolStatusMonitor creates a monitor for futures contracts
+func NewFuturesSymbolStatusMonitor(client api.FuturesClient, cfg *config.SymbolStatusConfig, log logger.Logger, suspenders ...SymbolSuspender) SymbolStatusMonitor {
+	m := newSymbolStatusMonitor(CoverageMarketFutures, cfg, log, suspenders)
+	m.fetch = client.GetExchangeSymbols
+	m.openOrders = func() (map[string][]*symbolOpenOrder, error) {
+		orders, err := client.GetOpenOrders("")
+		if err != nil {
+			return nil, err
+		}
+		bySymbol := make(map[string][]*symbolOpenOrder)
+		for _, order := range orders {
+			bySymbol[order.Symbol] = append(bySymbol[order.Symbol], &symbolOpenOrder{orderID: order.OrderID})
+		}
+		return bySymbol, nil
+	}
+	m.cancel = func(symbol string, orderID int64) error {
+		_, err := client.CancelOrder(symbol, orderID)
+		return err
+	}
+	return m
+}
+
+// newSymbolStatusMonitor creates a monitor without exchange access
+func newSymbolStatusMonitor(market string, cfg *config.SymbolStatusConfig, log logger.Logger, suspenders []SymbolSuspender) *symbolStatusMonitor {
+	m := &symbolStatusMonitor{
+		market:     market,
+		suspenders: suspenders,
+		logger:     log,
+		now:        time.Now,
+		statuses:   make(map[string]string),
+		suspended:  make(map[string]*SymbolSuspension),
+	}
+	if cfg != nil {
+		m.cancelOpen = cfg.CancelOpenOrders
+	}
+	return m
+}
+
+// Refresh reloads exchange info. Symbols that are not trading have their active orders
+// suspended, and their open exchange orders cancelled when configured; a symbol is reported
+// as suspended once something of it was paused or cancelled. Suspended symbols that trade
+// again are resumed. A symbol missing from exchange info stays suspended.
+func (m *symbolStatusMonitor) Refresh() ([]*SymbolStatusChange, error) {
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+
+	symbols, err := m.fetch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh exchange info: %w", err)
+	}
+
+	statuses := make(map[string]string, len(symbols))
+	var halted []string
+	for _, symbol := range symbols {
+		statuses[symbol.Symbol] = symbol.Status
+		if !symbol.IsTrading() {
+			halted = append(halted, symbol.Symbol)
+		}
+	}
+	sort.Strings(halted)
+
+	var open map[string][]*symbolOpenOrder
+	if m.cancelOpen && len(halted) > 0 {
+		if open, err = m.openOrders(); err != nil {
+			m.logger.Warn("Failed to list open orders of halted symbols", map[string]interface{}{
+				"market": m.market,
+				"error":  err.Error(),
+			})
+		}
+	}
+
+	var changes []*SymbolStatusChange
+	for _, symbol := range halted {
+		if change := m.suspend(symbol, statuses[symbol], open[symbol]); change != nil {
+			changes = append(changes, change)
+		}
+	}
+
+	m.mu.Lock()
+	var trading []string
+	for symbol := range m.suspended {
+		if status, listed := statuses[symbol]; listed && status == api.SymbolStatusTrading {
+			trading = append(trading, symbol)
+		}
+	}
+	m.statuses = statuses
+	m.mu.Unlock()
+	sort.Strings(trading)
+
+	for _, symbol := range trading {
+		changes = append(changes, m.resume(symbol))
+	}
+
+	m.notify(changes)
+	return changes, nil
+}
+
+// suspend pauses the automation of a halted symbol and cancels its open orders. It returns
+// a change when the symbol becomes suspended, and nil when it already was or has nothing to pause.
+func (m *symbolStatusMonitor) suspend(symbol, status string, open []*symbolOpenOrder) *SymbolStatusChange {
+	change := &SymbolStatusChange{Market: m.market, Symbol: symbol, Status: status}
+
+	for _, suspender := range m.suspenders {
+		suspended, err := suspender.SuspendSymbol(symbol)
+		change.Orders += suspended
+		if err != nil {
+			change.Errors = append(change.Errors, err.Error())
+		}
+	}
+
+	cancelledLists := make(map[int64]bool)
+	for _, order := range open {
+		if order.orderListID > 0 && cancelledLists[order.orderListID] {
+			continue
+		}
+		if err := m.cancel(symbol, order.orderID); err != nil {
+			change.Errors = append(change.Errors, fmt.Sprintf("cancel %d: %v", order.orderID, err))
+			continue
+		}
+		change.Cancelled++
+		if order.orderListID > 0 {
+			cancelledLists[order.orderListID] = true
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if suspension, exists := m.suspended[symbol]; exists {
+		// Orders created while the symbol is halted are suspended on the next refresh
+		suspension.Status = status
+		suspension.Orders += change.Orders
+		suspension.Cancelled += change.Cancelled
+		if change.Orders > 0 || change.Cancelled > 0 || len(change.Errors) > 0 {
+			m.logger.Info("Suspended new orders of a halted symbol", map[string]interface{}{
+				"market":    m.market,
+				"symbol":    symbol,
+				"status":    status,
+				"orders":    change.Orders,
+				"cancelled": change.Cancelled,
+				"errors":    change.Errors,
+			})
+		}
+		return nil
+	}
+	if change.Orders == 0 && change.Cancelled == 0 && len(change.Errors) == 0 {
+		return nil
+	}
+
+	m.suspended[symbol] = &SymbolSuspension{
+		Symbol:      symbol,
+		Status:      status,
+		SuspendedAt: timeutil.Millis(m.now()),
+		Orders:      change.Orders,
+		Cancelled:   change.Cancelled,
+	}
+	m.logger.Warn("Symbol stopped trading, suspended its automation", map[string]interface{}{
+		"market":    m.market,
+		"symbol":    symbol,
+		"status":    status,
+		"orders":    change.Orders,
+		"cancelled": change.Cancelled,
+		"errors":    change.Errors,
+	})
+	return change
+}
+
+// resume reactivates the suspended orders of a symbol that trades again
+func (m *symbolStatusMonitor) resume(symbol string) *SymbolStatusChange {
+	change := &SymbolStatusChange{Market: m.market, Symbol: symbol, Status: api.SymbolStatusTrading, Resumed: true}
+
+	for _, suspender := range m.suspenders {
+		resumed, err := suspender.ResumeSymbol(symbol)
+		change.Orders += resumed
+		if err != nil {
+			change.Errors = append(change.Errors, err.Error())
+		}
+	}
+
+	m.mu.Lock()
+	delete(m.suspended, symbol)
+	m.mu.Unlock()
+
+	m.logger.Info("Symbol is trading again, resumed its automation", map[string]interface{}{
+		"market": m.market,
+		"symbol": symbol,
+		"orders": change.Orders,
+		"errors": change.Errors,
+	})
+	return change
+}
+
+// notify passes changes to the registered callbacks
+func (m *symbolStatusMonitor) notify(changes []*SymbolStatusChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	callbacks := make([]func(change *SymbolStatusChange), len(m.callbacks))
+	copy(callbacks, m.callbacks)
+	m.mu.Unlock()
+
+	for _, change := range changes {
+		for _, callback := range callbacks {
+			callback(change)
+		}
+	}
+}
+
+// GetSuspendedSymbols returns the suspended symbols sorted by symbol
+func (m *symbolStatusMonitor) GetSuspendedSymbols() []*SymbolSuspension {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	suspensions := make([]*SymbolSuspension, 0, len(m.suspended))
+	for _, suspension := range m.suspended {
+		suspensionCopy := *suspension
+		suspensions = append(suspensions, &suspensionCopy)
+	}
+	sort.Slice(suspensions, func(i, j int) bool {
+		return suspensions[i].Symbol < suspensions[j].Symbol
+	})
+	return suspensions
+}
+
+// OnChange registers a callback invoked for every suspension and resumption
+func (m *symbolStatusMonitor) OnChange(callback func(change *SymbolStatusChange)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.callbacks = append(m.callbacks, callback)
+}
+
+// StartMonitoring refreshes exchange info now and then on every interval
+func (m *symbolStatusMonitor) StartMonitoring(refreshInterval time.Duration) error {
+	m.monitoringMu.Lock()
+	defer m.monitoringMu.Unlock()
+
+	if m.isMonitoring {
+		return fmt.Errorf("monitoring is already running")
+	}
+
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultSymbolStatusInterval
+	}
+
+	m.stopChan = make(chan struct{})
+	m.isMonitoring = true
+
+	go m.monitoringLoop(refreshInterval)
+
+	m.logger.Info("Started symbol status monitoring", map[string]interface{}{
+		"market":             m.market,
+		"refresh_interval":   refreshInterval.String(),
+		"cancel_open_orders": m.cancelOpen,
+	})
+
+	return nil
+}
+
+// StopMonitoring stops the scheduled refresh
+func (m *symbolStatusMonitor) StopMonitoring() error {
+	m.monitoringMu.Lock()
+	defer m.monitoringMu.Unlock()
+
+	if !m.isMonitoring {
+		return fmt.Errorf("monitoring is not running")
+	}
+
+	close(m.stopChan)
+	m.isMonitoring = false
+
+	m.logger.Info("Stopped symbol status monitoring", map[string]interface{}{
+		"market": m.market,
+	})
+
+	return nil
+}
+
+// monitoringLoop refreshes right away and then on every tick
+func (m *symbolStatusMonitor) monitoringLoop(refreshInterval time.Duration) {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.Refresh(); err != nil {
+			m.logger.Warn("Symbol status refresh failed", map[string]interface{}{
+				"market": m.market,
+				"error":  err.Error(),
+			})
+		}
+
+		select {
+		case <-m.stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// stopOrderSuspender suspends the local stop orders and trailing stops of a symbol
+type stopOrderSuspender struct {
+	repo repository.StopOrderRepository
+}
+
+// NewStopOrderSuspender creates a suspender for the stop orders and trailing stops of a repository
+func NewStopOrderSuspender(repo repository.StopOrderRepository) SymbolSuspender {
+	return &stopOrderSuspender{repo: repo}
+}
+
+// SuspendSymbol moves the active stop orders and trailing stops of a symbol to SUSPENDED_SYMBOL
+func (s *stopOrderSuspender) SuspendSymbol(symbol string) (int, error) {
+	return s.setStatus(symbol, repository.StopOrderStatusActive, repository.StopOrderStatusSuspendedSymbol)
+}
+
+// ResumeSymbol returns the suspended stop orders and trailing stops of a symbol to ACTIVE
+func (s *stopOrderSuspender) ResumeSymbol(symbol string) (int, error) {
+	return s.setStatus(symbol, repository.StopOrderStatusSuspendedSymbol, repository.StopOrderStatusActive)
+}
+
+// setStatus moves the orders of symbol from one status to another
+func (s *stopOrderSuspender) setStatus(symbol string, from, to repository.StopOrderStatus) (int, error) {
+	orders, err := s.repo.FindStopOrdersBySymbol(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, order := range orders {
+		if order.Status != from {
+			continue
+		}
+		if err := s.repo.UpdateStopOrderStatus(order.OrderID, to, 0, 0); err != nil {
+			return changed, err
+		}
+		changed++
+	}
+
+	trailingOrders, err := s.repo.FindTrailingStopOrdersBySymbol(symbol)
+	if err != nil {
+		return changed, err
+	}
+	for _, order := range trailingOrders {
+		if order.Status != from {
+			continue
+		}
+		order.Status = to
+		if err := s.repo.UpdateTrailingStopOrder(order); err != nil {
+			return changed, err
+		}
+		changed++
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/internal/repository"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// symbolStatusFixture serves exchange info whose symbol statuses can be flipped between refreshes
+type symbolStatusFixture struct {
+	mu       sync.Mutex
+	statuses map[string]string
+}
+
+func (f *symbolStatusFixture) set(symbol, status string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.statuses[symbol] = status
+}
+
+func (f *symbolStatusFixture) symbols() ([]*api.ExchangeSymbol, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	symbols := make([]*api.ExchangeSymbol, 0, len(f.statuses))
+	for symbol, status := range f.statuses {
+		symbols = append(symbols, &api.ExchangeSymbol{Symbol: symbol, Status: status})
+	}
+	return symbols, nil
+}
+
+func TestSymbolStatusMonitorSuspendsAndResumes(t *testing.T) {
+	fixture := &symbolStatusFixture{statuses: map[string]string{
+		"BTCUSDT":  api.SymbolStatusTrading,
+		"LUNAUSDT": api.SymbolStatusTrading,
+		"OLDUSDT":  "BREAK", // Long delisted, nothing of it is automated
+	}}
+
+	var cancelled []int64
+	client := &mockBinanceClient{
+		getExchangeSymbolsFunc: fixture.symbols,
+		getOpenOrdersFunc: func(symbol string) ([]*api.Order, error) {
+			return []*api.Order{
+				{Symbol: "LUNAUSDT", OrderID: 11, OrderListID: 7},
+				{Symbol: "LUNAUSDT", OrderID: 12, OrderListID: 7},
+				{Symbol: "LUNAUSDT", OrderID: 13, OrderListID: -1},
+				{Symbol: "BTCUSDT", OrderID: 21, OrderListID: -1},
+			}, nil
+		},
+		cancelOrderFunc: func(symbol string, orderID int64) (*api.CancelResponse, error) {
+			cancelled = append(cancelled, orderID)
+			return &api.CancelResponse{Symbol: symbol, OrderID: orderID}, nil
+		},
+	}
+
+	conditionalRepo := repository.NewMemoryConditionalOrderRepository()
+	stopRepo := repository.NewMemoryStopOrderRepository()
+	conditionalService := NewConditionalOrderService(conditionalRepo, stopRepo, NewTriggerEngine(), nil, nil, nil, &mockLogger{})
+
+	for _, order := range []*repository.ConditionalOrder{
+		{OrderID: "luna-buy", Symbol: "LUNAUSDT", Status: repository.ConditionalOrderStatusPending},
+		{OrderID: "btc-buy", Symbol: "BTCUSDT", Status: repository.ConditionalOrderStatusPending},
+	} {
+		if err := conditionalRepo.Save(order); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	if err := stopRepo.SaveStopOrder(&repository.StopOrder{OrderID: "luna-stop", Symbol: "LUNAUSDT", Status: repository.StopOrderStatusActive}); err != nil {
+		t.Fatalf("SaveStopOrder() error = %v", err)
+	}
+	if err := stopRepo.SaveTrailingStopOrder(&repository.TrailingStopOrder{OrderID: "luna-trail", Symbol: "LUNAUSDT", Status: repository.StopOrderStatusActive}); err != nil {
+		t.Fatalf("SaveTrailingStopOrder() error = %v", err)
+	}
+
+	monitor := NewSpotSymbolStatusMonitor(client, &config.SymbolStatusConfig{CancelOpenOrders: true}, &mockLogger{},
+		conditionalService, NewStopOrderSuspender(stopRepo))
+	var notified []*SymbolStatusChange
+	monitor.OnChange(func(change *SymbolStatusChange) {
+		notified = append(notified, change)
+	})
+
+	expectStatuses := func(conditional repository.ConditionalOrderStatus, stop repository.StopOrderStatus) {
+		t.Helper()
+		if order, _ := conditionalRepo.FindByID("luna-buy"); order.Status != conditional {
+			t.Errorf("conditional order status = %s, want %s", order.Status, conditional)
+		}
+		if order, _ := conditionalRepo.FindByID("btc-buy"); order.Status != repository.ConditionalOrderStatusPending {
+			t.Errorf("order of a trading symbol changed to %s", order.Status)
+		}
+		if order, _ := stopRepo.FindStopOrderByID("luna-stop"); order.Status != stop {
+			t.Errorf("stop order status = %s, want %s", order.Status, stop)
+		}
+		if order, _ := stopRepo.FindTrailingStopOrderByID("luna-trail"); order.Status != stop {
+			t.Errorf("trailing stop status = %s, want %s", order.Status, stop)
+		}
+	}
+
+	// Everything automated trades; the old BREAK symbol is not reported
+	changes, err := monitor.Refresh()
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if len(changes) != 0 || len(monitor.GetSuspendedSymbols()) != 0 {
+		t.Fatalf("changes = %v, suspended = %v, want none", changes, monitor.GetSuspendedSymbols())
+	}
+	expectStatuses(repository.ConditionalOrderStatusPending, repository.StopOrderStatusActive)
+
+	// The symbol is halted: its orders are suspended and open orders cancelled, one per order list
+	fixture.set("LUNAUSDT", "BREAK")
+	changes, err = monitor.Refresh()
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if len(changes) != 1 || changes[0].Symbol != "LUNAUSDT" || changes[0].Resumed || changes[0].Orders != 3 || changes[0].Cancelled != 2 {
+		t.Fatalf("changes = %+v, want LUNAUSDT suspended with 3 orders and 2 cancellations", changes)
+	}
+	if want := "SPOT LUNAUSDT is BREAK; suspended 3 orders, cancelled 2 open orders"; changes[0].Summary() != want {
+		t.Errorf("Summary() = %q, want %q", changes[0].Summary(), want)
+	}
+	if len(cancelled) != 2 || cancelled[0] != 11 || cancelled[1] != 13 {
+		t.Errorf("cancelled orders = %v, want [11 13]", cancelled)
+	}
+	expectStatuses(repository.ConditionalOrderStatusSuspendedSymbol, repository.StopOrderStatusSuspendedSymbol)
+
+	suspended := monitor.GetSuspendedSymbols()
+	if len(suspended) != 1 || suspended[0].Symbol != "LUNAUSDT" || suspended[0].Status != "BREAK" {
+		t.Fatalf("GetSuspendedSymbols() = %+v", suspended)
+	}
+
+	// Suspended orders are not active, and a repeated refresh reports nothing new
+	if active, _ := conditionalService.GetActiveConditionalOrders(); len(active) != 1 || active[0].OrderID != "btc-buy" {
+		t.Errorf("active conditional orders = %v, want only btc-buy", active)
+	}
+	if changes, _ = monitor.Refresh(); len(changes) != 0 {
+		t.Errorf("repeated refresh changes = %+v, want none", changes)
+	}
+
+	// Trading again: the orders resume
+	fixture.set("LUNAUSDT", api.SymbolStatusTrading)
+	changes, err = monitor.Refresh()
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if len(changes) != 1 || !changes[0].Resumed || changes[0].Orders != 3 {
+		t.Fatalf("changes = %+v, want LUNAUSDT resumed with 3 orders", changes)
+	}
+	if !strings.Contains(changes[0].Summary(), "trading again") {
+		t.Errorf("Summary() = %q", changes[0].Summary())
+	}
+	expectStatuses(repository.ConditionalOrderStatusPending, repository.StopOrderStatusActive)
+	if len(monitor.GetSuspendedSymbols()) != 0 {
+		t.Errorf("GetSuspendedSymbols() = %+v, want none", monitor.GetSuspendedSymbols())
+	}
+
+	if len(notified) != 2 || notified[0].Resumed || !notified[1].Resumed {
+		t.Errorf("notified changes = %+v, want a suspension then a resumption", notified)
+	}
+}
+
+func TestSymbolStatusMonitorKeepsOpenOrdersByDefault(t *testing.T) {
+	fixture := &symbolStatusFixture{statuses: map[string]string{"LUNAUSDT": "HALT"}}
+	client := &mockBinanceClient{
+		getExchangeSymbolsFunc: fixture.symbols,
+		getOpenOrdersFunc: func(symbol string) ([]*api.Order, error) {
+			t.Error("open orders should not be listed unless cancel_open_orders is set")
+			return nil, nil
+		},
+	}
+
+	stopRepo := repository.NewMemoryStopOrderRepository()
+	if err := stopRepo.SaveStopOrder(&repository.StopOrder{OrderID: "luna-stop", Symbol: "LUNAUSDT", Status: repository.StopOrderStatusActive}); err != nil {
+		t.Fatalf("SaveStopOrder() error = %v", err)
+	}
+
+	monitor := NewSpotSymbolStatusMonitor(client, &config.SymbolStatusConfig{}, &mockLogger{}, NewStopOrderSuspender(stopRepo))
+	changes, err := monitor.Refresh()
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if len(changes) != 1 || changes[0].Orders != 1 || changes[0].Cancelled != 0 {
+		t.Fatalf("changes = %+v, want the stop order suspended without cancellations", changes)
+	}
+
+	// A symbol that disappears from exchange info stays suspended
+	fixture.mu.Lock()
+	delete(fixture.statuses, "LUNAUSDT")
+	fixture.mu.Unlock()
+	if changes, _ = monitor.Refresh(); len(changes) != 0 || len(monitor.GetSuspendedSymbols()) != 1 {
+		t.Errorf("changes = %+v, suspended = %+v, want LUNAUSDT still suspended", changes, monitor.GetSuspendedSymbols())
+	}
+}
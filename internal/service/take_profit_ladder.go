@@ -0,0 +1,58 @@
+package service
+
+import (
+	"binance-trader/pkg/errors"
+	"fmt"
+)
+
+// portionTolerance absorbs float rounding when level portions add up to exactly 100%
+const portionTolerance = 1e-9
+
+// TPLevel represents one step of a take profit ladder
+type TPLevel struct {
+	Price   float64 // Target price for this level
+	Portion float64 // Share of the position to close, in percent (25 = 25%)
+}
+
+// validateTakeProfitLadder checks level prices and portions. Prices must be strictly
+// ascending when ascending is true (long positions) and strictly descending otherwise
+// (short positions), so levels are reached one after another as profit grows.
+func validateTakeProfitLadder(levels []TPLevel, ascending bool) error {
+	if len(levels) == 0 {
+		return errors.NewTradingError(errors.ErrInvalidParameter, "take profit ladder must have at least one level", 0, nil)
+	}
+
+	totalPortion := 0.0
+	for i, level := range levels {
+		if level.Price <= 0 {
+			return errors.NewTradingError(errors.ErrInvalidParameter,
+				fmt.Sprintf("level %d: target price must be greater than 0", i+1), 0, nil)
+		}
+
+		if level.Portion <= 0 {
+			return errors.NewTradingError(errors.ErrInvalidParameter,
+				fmt.Sprintf("level %d: portion must be greater than 0", i+1), 0, nil)
+		}
+
+		if i > 0 {
+			previous := levels[i-1].Price
+			if ascending && level.Price <= previous {
+				return errors.NewTradingError(errors.ErrInvalidParameter,
+					fmt.Sprintf("level %d: target prices must be in ascending order", i+1), 0, nil)
+			}
+			if !ascending && level.Price >= previous {
+				return errors.NewTradingError(errors.ErrInvalidParameter,
+					fmt.Sprintf("level %d: target prices must be in descending order", i+1), 0, nil)
+			}
+		}
+
+		totalPortion += level.Portion
+	}
+
+	if totalPortion > 100+portionTolerance {
+		return errors.NewTradingError(errors.ErrInvalidParameter,
+			fmt.Sprintf("level portions sum to %.2f%%, must not exceed 100%%", totalPortion), 0, nil)
+	}
+
+	return nil
+}
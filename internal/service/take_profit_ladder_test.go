@@ -0,0 +1,260 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// recordingSellTradingService records the quantity of each market sell, failing them while err is set
+type recordingSellTradingService struct {
+	mockStopLossTradingService
+	sells []float64
+	err   error
+}
+
+func (m *recordingSellTradingService) PlaceMarketSellOrder(symbol string, quantity float64) (*api.Order, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.sells = append(m.sells, quantity)
+	return &api.Order{OrderID: int64(len(m.sells)), Symbol: symbol, Status: api.OrderStatusFilled}, nil
+}
+
+// recordingCloseFuturesTradingService records the quantity of each position close, failing them while err is set
+type recordingCloseFuturesTradingService struct {
+	mockFuturesTradingService
+	closes []float64
+	err    error
+}
+
+func (m *recordingCloseFuturesTradingService) ClosePosition(symbol string, positionSide api.PositionSide, quantity float64) (*api.FuturesOrder, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.closes = append(m.closes, quantity)
+	return &api.FuturesOrder{OrderID: int64(len(m.closes)), Symbol: symbol, Status: api.OrderStatusFilled}, nil
+}
+
+func TestValidateTakeProfitLadder(t *testing.T) {
+	tests := []struct {
+		name      string
+		levels    []TPLevel
+		ascending bool
+		wantErr   bool
+	}{
+		{"valid ascending ladder", []TPLevel{{102, 25}, {104, 25}, {106, 50}}, true, false},
+		{"partial ladder below 100%", []TPLevel{{102, 30}, {104, 30}}, true, false},
+		{"valid descending ladder", []TPLevel{{98, 50}, {96, 50}}, false, false},
+		{"portions sum above 100%", []TPLevel{{102, 50}, {104, 40}, {106, 20}}, true, true},
+		{"prices out of order", []TPLevel{{104, 25}, {102, 25}}, true, true},
+		{"duplicate prices", []TPLevel{{102, 25}, {102, 25}}, true, true},
+		{"ascending prices for short", []TPLevel{{96, 50}, {98, 50}}, false, true},
+		{"zero portion", []TPLevel{{102, 0}}, true, true},
+		{"zero price", []TPLevel{{0, 25}}, true, true},
+		{"no levels", nil, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTakeProfitLadder(tt.levels, tt.ascending)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateTakeProfitLadder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetTakeProfitLadder(t *testing.T) {
+	stopOrderRepo := repository.NewMemoryStopOrderRepository()
+	svc := NewStopLossService(stopOrderRepo, NewTriggerEngine(), &mockStopLossTradingService{},
+		&mockStopLossMarketDataService{currentPrice: 100}, &mockLogger{})
+
+	orders, err := svc.SetTakeProfitLadder("BTCUSDT", 2.0, []TPLevel{{102, 25}, {104, 25}, {106, 50}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []struct{ price, quantity float64 }{{102, 0.5}, {104, 0.5}, {106, 1.0}}
+	if len(orders) != len(expected) {
+		t.Fatalf("expected %d orders, got %d", len(expected), len(orders))
+	}
+	for i, order := range orders {
+		if order.Type != repository.StopOrderTypeTakeProfit || order.Status != repository.StopOrderStatusActive {
+			t.Errorf("level %d: unexpected type %v / status %s", i+1, order.Type, order.Status)
+		}
+		if order.StopPrice != expected[i].price || math.Abs(order.Position-expected[i].quantity) > 1e-9 {
+			t.Errorf("level %d: expected %.4f @ %.2f, got %.4f @ %.2f",
+				i+1, expected[i].quantity, expected[i].price, order.Position, order.StopPrice)
+		}
+	}
+
+	// Invalid portions register nothing
+	if _, err := svc.SetTakeProfitLadder("ETHUSDT", 1.0, []TPLevel{{102, 60}, {104, 60}}); err == nil {
+		t.Error("expected error when portions exceed 100%")
+	}
+	if active, _ := stopOrderRepo.FindActiveStopOrders("ETHUSDT"); len(active) != 0 {
+		t.Errorf("expected no orders for rejected ladder, got %d", len(active))
+	}
+}
+
+func TestTakeProfitLadder_PartialExecutionSequence(t *testing.T) {
+	stopOrderRepo := repository.NewMemoryStopOrderRepository()
+	triggerEngine := NewTriggerEngine()
+	trading := &recordingSellTradingService{}
+	market := &mockStopLossMarketDataService{currentPrice: 100}
+	stopLoss := NewStopLossService(stopOrderRepo, triggerEngine, trading, market, &mockLogger{})
+
+	engine := NewMonitoringEngine(repository.NewMemoryConditionalOrderRepository(), stopOrderRepo,
+		triggerEngine, trading, market, stopLoss, &mockLogger{}, nil)
+
+	orders, err := stopLoss.SetTakeProfitLadder("BTCUSDT", 4.0, []TPLevel{{102, 25}, {104, 25}, {106, 50}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	steps := []struct {
+		price         float64
+		expectedSells []float64
+	}{
+		{101, nil},                // below the first level
+		{102.5, []float64{1}},     // first level only
+		{103, []float64{1}},       // no new level, first is not sold again
+		{107, []float64{1, 1, 2}}, // gap up executes the remaining levels
+		{110, []float64{1, 1, 2}}, // ladder exhausted
+	}
+
+	for _, step := range steps {
+		market.currentPrice = step.price
+		engine.marketDataCache = make(map[string]*MarketData)
+		engine.processStopOrders()
+
+		if len(trading.sells) != len(step.expectedSells) {
+			t.Fatalf("at price %.2f: expected %d sells, got %v", step.price, len(step.expectedSells), trading.sells)
+		}
+		for i, quantity := range step.expectedSells {
+			if math.Abs(trading.sells[i]-quantity) > 1e-9 {
+				t.Errorf("at price %.2f: sell %d expected %.4f, got %.4f", step.price, i+1, quantity, trading.sells[i])
+			}
+		}
+	}
+
+	for _, order := range orders {
+		stored, _ := stopOrderRepo.FindStopOrderByID(order.OrderID)
+		if stored.Status != repository.StopOrderStatusTriggered || stored.ExecutedOrderID == 0 {
+			t.Errorf("expected level %s to be triggered with an executed order, got %s / %d",
+				order.OrderID, stored.Status, stored.ExecutedOrderID)
+		}
+	}
+}
+
+func TestFuturesTakeProfitLadder_ShortPartialExecution(t *testing.T) {
+	stopOrderRepo := repository.NewMemoryStopOrderRepository()
+	trading := &recordingCloseFuturesTradingService{}
+	market := &mockFuturesMarketDataService{markPrice: 100}
+	svc := NewFuturesStopLossService(stopOrderRepo, NewTriggerEngine(), trading, market, &mockLogger{})
+
+	// Short ladders must step down in price
+	if _, err := svc.SetTakeProfitLadder("BTCUSDT", api.PositionSideShort, 1.0, []TPLevel{{96, 50}, {98, 50}}); err == nil {
+		t.Error("expected error for ascending short ladder")
+	}
+
+	orders, err := svc.SetTakeProfitLadder("BTCUSDT", api.PositionSideShort, 1.0, []TPLevel{{98, 50}, {96, 50}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, order := range orders {
+		if order.PositionSide != string(api.PositionSideShort) {
+			t.Errorf("expected level %s to store the SHORT side, got %q", order.OrderID, order.PositionSide)
+		}
+	}
+
+	// The monitor closes levels as the mark price falls
+	execute := func(price float64) {
+		market.markPrice = price
+		if err := svc.CheckTakeProfits(); err != nil {
+			t.Fatalf("unexpected error at price %.2f: %v", price, err)
+		}
+	}
+
+	execute(99)
+	if len(trading.closes) != 0 {
+		t.Fatalf("expected no closes above the first level, got %v", trading.closes)
+	}
+
+	execute(97.5)
+	if len(trading.closes) != 1 || trading.closes[0] != 0.5 {
+		t.Fatalf("expected first level to close 0.5, got %v", trading.closes)
+	}
+
+	execute(95)
+	if len(trading.closes) != 2 || trading.closes[1] != 0.5 {
+		t.Fatalf("expected second level to close 0.5, got %v", trading.closes)
+	}
+}
+
+func TestFuturesTakeProfit_FailedCloseIsRetried(t *testing.T) {
+	stopOrderRepo := repository.NewMemoryStopOrderRepository()
+	trading := &recordingCloseFuturesTradingService{err: fmt.Errorf("exchange unavailable")}
+	market := &mockFuturesMarketDataService{markPrice: 110}
+	svc := NewFuturesStopLossService(stopOrderRepo, NewTriggerEngine(), trading, market, &mockLogger{})
+
+	order, err := svc.SetTakeProfit("BTCUSDT", api.PositionSideLong, 0.5, 105)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := svc.CheckTakeProfits(); err != nil {
+		t.Fatalf("CheckTakeProfits() error = %v", err)
+	}
+	stored, _ := stopOrderRepo.FindStopOrderByID(order.OrderID)
+	if stored.Status != repository.StopOrderStatusActive || stored.TriggeredAt != 0 {
+		t.Fatalf("expected failed close to leave the order active, got %s at %d", stored.Status, stored.TriggeredAt)
+	}
+
+	// The next check retries the close
+	trading.err = nil
+	if err := svc.CheckTakeProfits(); err != nil {
+		t.Fatalf("CheckTakeProfits() error = %v", err)
+	}
+	stored, _ = stopOrderRepo.FindStopOrderByID(order.OrderID)
+	if len(trading.closes) != 1 || stored.Status != repository.StopOrderStatusTriggered || stored.ExecutedOrderID == 0 {
+		t.Errorf("expected retried close to trigger the order, got closes %v and status %s", trading.closes, stored.Status)
+	}
+}
+
+func TestTakeProfit_FailedSellReactivatesPair(t *testing.T) {
+	stopOrderRepo := repository.NewMemoryStopOrderRepository()
+	trading := &recordingSellTradingService{err: fmt.Errorf("exchange unavailable")}
+	svc := NewStopLossService(stopOrderRepo, NewTriggerEngine(), trading,
+		&mockStopLossMarketDataService{currentPrice: 100}, &mockLogger{}).(*stopLossService)
+
+	pair, err := svc.SetStopLossTakeProfit("BTCUSDT", 1.0, 95, 105)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, err := svc.ExecuteTakeProfitIfReached(pair.TakeProfitOrder.OrderID, 106); err == nil {
+		t.Fatal("expected the failed sell to be reported")
+	}
+
+	// Both legs and the pair are back in place for the next check
+	for _, leg := range []*repository.StopOrder{pair.TakeProfitOrder, pair.StopLossOrder} {
+		stored, _ := stopOrderRepo.FindStopOrderByID(leg.OrderID)
+		if stored.Status != repository.StopOrderStatusActive {
+			t.Errorf("expected %s to be active again, got %s", leg.OrderID, stored.Status)
+		}
+	}
+	storedPair, _ := stopOrderRepo.FindStopOrderPairByID(pair.PairID)
+	if storedPair.Status != "ACTIVE" || storedPair.StopLossOrder.Status != repository.StopOrderStatusActive {
+		t.Errorf("expected pair to be active again, got %s / %s", storedPair.Status, storedPair.StopLossOrder.Status)
+	}
+
+	trading.err = nil
+	triggered, err := svc.ExecuteTakeProfitIfReached(pair.TakeProfitOrder.OrderID, 106)
+	if err != nil || !triggered || len(trading.sells) != 1 {
+		t.Errorf("expected retried sell to trigger, got %v / %v with sells %v", triggered, err, trading.sells)
+	}
+}
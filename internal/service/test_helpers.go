@@ -3,6 +3,7 @@ package service
 import (
 	"binance-trader/internal/api"
 	"binance-trader/internal/repository"
+	"fmt"
 	"time"
 )
 
@@ -31,6 +32,10 @@ func (m *mockFuturesClientShared) GetPrice(symbol string) (*api.Price, error) {
 	}, nil
 }
 
+func (m *mockFuturesClientShared) GetBookTicker(symbol string) (*api.BookTicker, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
 func (m *mockFuturesClientShared) GetFundingRate(symbol string) (*api.FundingRate, error) {
 	if m.fundingRate == nil {
 		return &api.FundingRate{
@@ -65,14 +70,17 @@ func (m *mockFuturesClientShared) CreateOrder(order *api.FuturesOrderRequest) (*
 	}, nil
 }
 
-func (m *mockFuturesClientShared) GetAccountInfo() (*api.FuturesAccountInfo, error) { return nil, nil }
-func (m *mockFuturesClientShared) GetBalance() (*api.FuturesBalance, error)         { return nil, nil }
+func (m *mockFuturesClientShared) GetFuturesAccountInfo() (*api.FuturesAccountInfo, error) { return nil, nil }
+func (m *mockFuturesClientShared) GetBalance() (*api.FuturesBalance, error) { return nil, nil }
 func (m *mockFuturesClientShared) GetKlines(symbol string, interval string, limit int) ([]*api.Kline, error) {
 	return nil, nil
 }
 func (m *mockFuturesClientShared) GetFundingRateHistory(symbol string, startTime, endTime int64) ([]*api.FundingRate, error) {
 	return nil, nil
 }
+func (m *mockFuturesClientShared) GetExchangeSymbols() ([]*api.ExchangeSymbol, error) {
+	return nil, nil
+}
 func (m *mockFuturesClientShared) SetLeverage(symbol string, leverage int) (*api.LeverageResponse, error) {
 	return &api.LeverageResponse{
 		Leverage: leverage,
@@ -87,6 +95,9 @@ func (m *mockFuturesClientShared) GetPositionMode() (*api.PositionMode, error) {
 func (m *mockFuturesClientShared) CancelOrder(symbol string, orderID int64) (*api.CancelResponse, error) {
 	return nil, nil
 }
+func (m *mockFuturesClientShared) BulkCancelFuturesOrders(symbol string) ([]api.CancelResponse, error) {
+	return nil, nil
+}
 func (m *mockFuturesClientShared) GetOrder(symbol string, orderID int64) (*api.FuturesOrder, error) {
 	return nil, nil
 }
@@ -118,6 +129,10 @@ func (m *mockFuturesMarketDataServiceShared) GetHistoricalData(symbol string, in
 	return nil, nil
 }
 
+func (m *mockFuturesMarketDataServiceShared) GetVolume(symbol string, timeWindow time.Duration) (float64, error) {
+	return 0, nil
+}
+
 func (m *mockFuturesMarketDataServiceShared) GetFundingRate(symbol string) (*api.FundingRate, error) {
 	if m.fundingRate == nil {
 		return &api.FundingRate{
@@ -137,6 +152,12 @@ func (m *mockFuturesMarketDataServiceShared) SubscribeToMarkPrice(symbol string,
 	return nil
 }
 
+func (m *mockFuturesMarketDataServiceShared) GetBestBidAsk(symbol string) (*BestBidAsk, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockFuturesMarketDataServiceShared) SetBookTickerCache(cache BookTickerCache) {}
+
 type mockFuturesPositionManagerShared struct {
 	positions map[string]*api.Position
 }
@@ -155,6 +176,16 @@ func (m *mockFuturesPositionManagerShared) GetPosition(symbol string, positionSi
 	}, nil
 }
 
+func (m *mockFuturesPositionManagerShared) GetPositionsBySymbol(symbol string) ([]*api.Position, error) {
+	var result []*api.Position
+	for _, pos := range m.positions {
+		if pos.Symbol == symbol {
+			result = append(result, pos)
+		}
+	}
+	return result, nil
+}
+
 func (m *mockFuturesPositionManagerShared) GetAllPositions() ([]*api.Position, error) {
 	var result []*api.Position
 	for _, pos := range m.positions {
@@ -233,6 +264,10 @@ func (m *mockFuturesTradingServiceShared) CancelOrder(symbol string, orderID int
 	return nil
 }
 
+func (m *mockFuturesTradingServiceShared) CancelAllOrders(symbol string) (int, error) {
+	return 0, nil
+}
+
 func (m *mockFuturesTradingServiceShared) GetOrderStatus(orderID int64) (*api.FuturesOrder, error) {
 	return nil, nil
 }
@@ -252,6 +287,20 @@ func (m *mockFuturesTradingServiceShared) GetLeverage(symbol string) (int, error
 	return 1, nil
 }
 
+func (m *mockFuturesTradingServiceShared) SetMarginType(symbol string, marginType api.MarginType) error {
+	return nil
+}
+
+func (m *mockFuturesTradingServiceShared) SetSymbolGuard(guard SymbolFailureGuard) {}
+
+func (m *mockFuturesTradingServiceShared) SetRiskManager(riskMgr FuturesRiskManager) {}
+
+func (m *mockFuturesTradingServiceShared) SetPositionMode(dualSide bool) error { return nil }
+
+func (m *mockFuturesTradingServiceShared) GetAccountBalance() (*api.FuturesAccountInfo, error) {
+	return &api.FuturesAccountInfo{}, nil
+}
+
 // mockLogger is a simple mock logger for testing
 type mockLogger struct{}
 
@@ -268,6 +317,7 @@ func (m *mockLogger) LogFuturesOrderEvent(eventType string, orderID int64, symbo
 func (m *mockLogger) LogLiquidationEvent(symbol string, positionSide string, liquidationPrice float64, lossAmount float64, reason string, fields map[string]interface{}) {}
 func (m *mockLogger) LogFundingRateSettlement(symbol string, fundingFee float64, fundingRate float64, positionSize float64, fields map[string]interface{}) {}
 func (m *mockLogger) SetTradingType(tradingType string)                                                                                              {}
+func (m *mockLogger) Close() error                                                                                                                   { return nil }
 
 // mockBinanceClient is a mock for spot trading client
 type mockBinanceClient struct {
@@ -276,8 +326,24 @@ type mockBinanceClient struct {
 	getBalanceFunc    func(asset string) (*api.Balance, error)
 	createOrderFunc   func(order *api.OrderRequest) (*api.OrderResponse, error)
 	cancelOrderFunc   func(symbol string, orderID int64) (*api.CancelResponse, error)
+	bulkCancelOrdersFunc    func(symbol string) ([]api.CancelResponse, error)
 	getOrderFunc      func(symbol string, orderID int64) (*api.Order, error)
 	getOpenOrdersFunc func(symbol string) ([]*api.Order, error)
+	getSystemStatusFunc func() (*api.SystemStatus, error)
+	getBookTickerFunc   func(symbol string) (*api.BookTicker, error)
+	getOrderBookFunc    func(symbol string, limit int) (*api.OrderBook, error)
+	getRateLimitsFunc   func() ([]api.RateLimitRule, error)
+	getMyTradesFunc     func(symbol string, orderID int64) ([]*api.Trade, error)
+	getHistoricalOrdersFunc func(symbol string, startTime, endTime int64) ([]*api.Order, error)
+	getDustAssetsFunc       func() (*api.DustEligibility, error)
+	convertDustFunc         func(assets []string) (*api.DustConversionResult, error)
+	createOCOOrderFunc      func(order *api.OCORequest) (*api.OCOResponse, error)
+	cancelOrderListFunc     func(symbol string, orderListID int64) (*api.OrderList, error)
+	getOrderListFunc        func(orderListID int64) (*api.OrderList, error)
+	getExchangeSymbolsFunc  func() ([]*api.ExchangeSymbol, error)
+	getExchangeInfoFunc     func() (*api.ExchangeInfo, error)
+	getAccountInfoFunc      func() (*api.AccountInfo, error)
+	getAllPricesFunc        func() ([]*api.Price, error)
 }
 
 func (m *mockBinanceClient) GetPrice(symbol string) (*api.Price, error) {
@@ -287,6 +353,20 @@ func (m *mockBinanceClient) GetPrice(symbol string) (*api.Price, error) {
 	return &api.Price{Symbol: symbol, Price: 50000.0}, nil
 }
 
+func (m *mockBinanceClient) GetBookTicker(symbol string) (*api.BookTicker, error) {
+	if m.getBookTickerFunc != nil {
+		return m.getBookTickerFunc(symbol)
+	}
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockBinanceClient) GetOrderBook(symbol string, limit int) (*api.OrderBook, error) {
+	if m.getOrderBookFunc != nil {
+		return m.getOrderBookFunc(symbol, limit)
+	}
+	return nil, fmt.Errorf("not implemented")
+}
+
 func (m *mockBinanceClient) GetKlines(symbol string, interval string, limit int) ([]*api.Kline, error) {
 	if m.getKlinesFunc != nil {
 		return m.getKlinesFunc(symbol, interval, limit)
@@ -315,6 +395,13 @@ func (m *mockBinanceClient) CancelOrder(symbol string, orderID int64) (*api.Canc
 	return &api.CancelResponse{OrderID: orderID, Symbol: symbol}, nil
 }
 
+func (m *mockBinanceClient) BulkCancelOrders(symbol string) ([]api.CancelResponse, error) {
+	if m.bulkCancelOrdersFunc != nil {
+		return m.bulkCancelOrdersFunc(symbol)
+	}
+	return nil, nil
+}
+
 func (m *mockBinanceClient) GetOrder(symbol string, orderID int64) (*api.Order, error) {
 	if m.getOrderFunc != nil {
 		return m.getOrderFunc(symbol, orderID)
@@ -329,10 +416,113 @@ func (m *mockBinanceClient) GetOpenOrders(symbol string) ([]*api.Order, error) {
 	return nil, nil
 }
 
+func (m *mockBinanceClient) CreateOCOOrder(order *api.OCORequest) (*api.OCOResponse, error) {
+	if m.createOCOOrderFunc != nil {
+		return m.createOCOOrderFunc(order)
+	}
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockBinanceClient) CancelOrderList(symbol string, orderListID int64) (*api.OrderList, error) {
+	if m.cancelOrderListFunc != nil {
+		return m.cancelOrderListFunc(symbol, orderListID)
+	}
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockBinanceClient) GetOrderList(orderListID int64) (*api.OrderList, error) {
+	if m.getOrderListFunc != nil {
+		return m.getOrderListFunc(orderListID)
+	}
+	return nil, fmt.Errorf("not implemented")
+}
+
 func (m *mockBinanceClient) GetAccountInfo() (*api.AccountInfo, error) {
+	if m.getAccountInfoFunc != nil {
+		return m.getAccountInfoFunc()
+	}
 	return &api.AccountInfo{}, nil
 }
 
+func (m *mockBinanceClient) GetAllBalances() ([]api.Balance, error) {
+	accountInfo, err := m.GetAccountInfo()
+	if err != nil {
+		return nil, err
+	}
+	return accountInfo.Balances, nil
+}
+
+func (m *mockBinanceClient) GetAllPrices() ([]*api.Price, error) {
+	if m.getAllPricesFunc != nil {
+		return m.getAllPricesFunc()
+	}
+	return nil, fmt.Errorf("not implemented")
+}
+
 func (m *mockBinanceClient) GetHistoricalOrders(symbol string, startTime, endTime int64) ([]*api.Order, error) {
+	if m.getHistoricalOrdersFunc != nil {
+		return m.getHistoricalOrdersFunc(symbol, startTime, endTime)
+	}
+	return nil, nil
+}
+
+func (m *mockBinanceClient) GetMyTrades(symbol string, orderID int64) ([]*api.Trade, error) {
+	if m.getMyTradesFunc != nil {
+		return m.getMyTradesFunc(symbol, orderID)
+	}
+	return nil, nil
+}
+
+func (m *mockBinanceClient) GetSystemStatus() (*api.SystemStatus, error) {
+	if m.getSystemStatusFunc != nil {
+		return m.getSystemStatusFunc()
+	}
+	return &api.SystemStatus{Status: api.SystemStatusNormal, Msg: "normal"}, nil
+}
+
+func (m *mockBinanceClient) GetRateLimits() ([]api.RateLimitRule, error) {
+	if m.getRateLimitsFunc != nil {
+		return m.getRateLimitsFunc()
+	}
 	return nil, nil
 }
+
+func (m *mockBinanceClient) GetExchangeSymbols() ([]*api.ExchangeSymbol, error) {
+	if m.getExchangeSymbolsFunc != nil {
+		return m.getExchangeSymbolsFunc()
+	}
+	return nil, nil
+}
+
+func (m *mockBinanceClient) GetExchangeInfo() (*api.ExchangeInfo, error) {
+	if m.getExchangeInfoFunc != nil {
+		return m.getExchangeInfoFunc()
+	}
+	return &api.ExchangeInfo{}, nil
+}
+
+func (m *mockBinanceClient) GetDustAssets() (*api.DustEligibility, error) {
+	if m.getDustAssetsFunc != nil {
+		return m.getDustAssetsFunc()
+	}
+	return &api.DustEligibility{}, nil
+}
+
+func (m *mockBinanceClient) ConvertDust(assets []string) (*api.DustConversionResult, error) {
+	if m.convertDustFunc != nil {
+		return m.convertDustFunc(assets)
+	}
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockBinanceClient) CreateListenKey() (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+func (m *mockBinanceClient) KeepAliveListenKey(listenKey string) error {
+	return fmt.Errorf("not implemented")
+}
+
+func (m *mockBinanceClient) CloseListenKey(listenKey string) error {
+	return fmt.Errorf("not implemented")
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/timeutil"
+	"fmt"
+	"math"
+)
+
+// validateTimeCondition checks the timestamp of a time condition, in Unix milliseconds like
+// every other stored timestamp. Timestamps already passed are accepted and trigger on the
+// first monitoring tick.
+func validateTimeCondition(condition *repository.TriggerCondition) error {
+	if condition.Value <= 0 || condition.Value != math.Trunc(condition.Value) {
+		return fmt.Errorf("time trigger must be a Unix time in whole milliseconds")
+	}
+	return timeutil.ValidateMillis("time trigger", int64(condition.Value))
+}
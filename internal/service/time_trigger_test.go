@@ -0,0 +1,215 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	"testing"
+	"time"
+)
+
+// newTimeTriggerTestEngine returns a monitoring engine whose clock reads *current, with one
+// pending order under the given condition
+func newTimeTriggerTestEngine(market *mockMarketDataService, current *time.Time, condition *repository.TriggerCondition) (*MonitoringEngine, *repository.ConditionalOrder, func() repository.ConditionalOrderStatus) {
+	repo := repository.NewMemoryConditionalOrderRepository()
+	engine := NewMonitoringEngine(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+		&mockTradingService{}, market, &mockStopLossService{}, &mockLogger{}, nil)
+	engine.now = func() time.Time { return *current }
+
+	order := &repository.ConditionalOrder{
+		OrderID:          "time",
+		Symbol:           "BTCUSDT",
+		Side:             api.OrderSideBuy,
+		Type:             api.OrderTypeMarket,
+		Quantity:         0.1,
+		TriggerCondition: condition,
+		Status:           repository.ConditionalOrderStatusPending,
+		CreatedAt:        current.UnixMilli(),
+	}
+	repo.Save(order)
+	status := func() repository.ConditionalOrderStatus {
+		updated, _ := repo.FindByID(order.OrderID)
+		return updated.Status
+	}
+	return engine, order, status
+}
+
+func TestMonitoringEngine_TimeTriggerBoundary(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	current := at.Add(-2 * time.Second)
+	engine, order, status := newTimeTriggerTestEngine(&mockMarketDataService{}, &current, &repository.TriggerCondition{
+		Type:     repository.TriggerTypeTime,
+		Operator: repository.OperatorGreaterEqual,
+		Value:    float64(at.UnixMilli()),
+	})
+
+	// The price never moves; only the clock decides
+	for _, offset := range []time.Duration{-2 * time.Second, -time.Second} {
+		current = at.Add(offset)
+		engine.processOrder(order)
+		if status() != repository.ConditionalOrderStatusPending {
+			t.Fatalf("order %s before its time = %s, want pending", -offset, status())
+		}
+	}
+
+	current = at
+	engine.processOrder(order)
+	if status() != repository.ConditionalOrderStatusExecuted {
+		t.Errorf("order at exactly its time = %s, want executed", status())
+	}
+}
+
+func TestMonitoringEngine_TimeTriggerAfterExcludesBoundary(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	current := at
+	engine, order, status := newTimeTriggerTestEngine(&mockMarketDataService{}, &current, &repository.TriggerCondition{
+		Type:     repository.TriggerTypeTime,
+		Operator: repository.OperatorGreaterThan,
+		Value:    float64(at.UnixMilli()),
+	})
+
+	engine.processOrder(order)
+	if status() != repository.ConditionalOrderStatusPending {
+		t.Fatalf("order at exactly its time with > = %s, want pending", status())
+	}
+	current = at.Add(time.Second)
+	engine.processOrder(order)
+	if status() != repository.ConditionalOrderStatusExecuted {
+		t.Errorf("order a second after its time = %s, want executed", status())
+	}
+}
+
+func TestMonitoringEngine_PastTimeTriggersOnFirstTick(t *testing.T) {
+	current := time.Unix(1700000000, 0)
+	engine, _, status := newTimeTriggerTestEngine(&mockMarketDataService{}, &current, &repository.TriggerCondition{
+		Type:     repository.TriggerTypeTime,
+		Operator: repository.OperatorGreaterEqual,
+		Value:    float64(current.Add(-time.Hour).UnixMilli()),
+	})
+
+	engine.checkAndTriggerOrders()
+	if status() != repository.ConditionalOrderStatusExecuted {
+		t.Errorf("order with a past time after the first tick = %s, want executed", status())
+	}
+}
+
+func TestMonitoringEngine_TimeAndPriceComposite(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	timeCondition := &repository.TriggerCondition{
+		Type:     repository.TriggerTypeTime,
+		Operator: repository.OperatorGreaterEqual,
+		Value:    float64(at.UnixMilli()),
+	}
+	priceCondition := &repository.TriggerCondition{
+		Type:     repository.TriggerTypePrice,
+		Operator: repository.OperatorLessEqual,
+		Value:    48000,
+	}
+
+	t.Run("AND waits for both", func(t *testing.T) {
+		market := &mockMarketDataService{prices: map[string]float64{"BTCUSDT": 47000}}
+		current := at.Add(-time.Minute)
+		engine, order, status := newTimeTriggerTestEngine(market, &current, &repository.TriggerCondition{
+			CompositeType: repository.LogicAND,
+			SubConditions: []*repository.TriggerCondition{timeCondition, priceCondition},
+		})
+
+		// The price is met but the time is not
+		engine.processOrder(order)
+		if status() != repository.ConditionalOrderStatusPending {
+			t.Fatalf("order before its time = %s, want pending", status())
+		}
+
+		// A millisecond early is still before its time
+		current = at.Add(-time.Millisecond)
+		engine.processOrder(order)
+		if status() != repository.ConditionalOrderStatusPending {
+			t.Fatalf("order a millisecond before its time = %s, want pending", status())
+		}
+
+		// The time is met but the price is not
+		market.prices["BTCUSDT"] = 50000
+		current = at
+		engine.processOrder(order)
+		if status() != repository.ConditionalOrderStatusPending {
+			t.Fatalf("order above its price = %s, want pending", status())
+		}
+
+		market.prices["BTCUSDT"] = 48000
+		current = at.Add(time.Minute)
+		engine.processOrder(order)
+		if status() != repository.ConditionalOrderStatusExecuted {
+			t.Errorf("order with both met = %s, want executed", status())
+		}
+	})
+
+	t.Run("OR fires on either", func(t *testing.T) {
+		market := &mockMarketDataService{prices: map[string]float64{"BTCUSDT": 50000}}
+		current := at.Add(-time.Minute)
+		engine, order, status := newTimeTriggerTestEngine(market, &current, &repository.TriggerCondition{
+			CompositeType: repository.LogicOR,
+			SubConditions: []*repository.TriggerCondition{timeCondition, priceCondition},
+		})
+
+		engine.processOrder(order)
+		if status() != repository.ConditionalOrderStatusPending {
+			t.Fatalf("order with neither met = %s, want pending", status())
+		}
+
+		// The deadline passes without the price reaching its target
+		current = at
+		engine.processOrder(order)
+		if status() != repository.ConditionalOrderStatusExecuted {
+			t.Errorf("order at its time = %s, want executed", status())
+		}
+	})
+}
+
+func TestConditionalOrderService_ValidateTimeCondition(t *testing.T) {
+	svc := NewConditionalOrderService(repository.NewMemoryConditionalOrderRepository(), repository.NewMemoryStopOrderRepository(),
+		NewTriggerEngine(), &mockTradingService{}, &mockMarketDataService{}, &mockStopLossService{}, &mockLogger{})
+
+	timeAt := func(value float64) *repository.TriggerCondition {
+		return &repository.TriggerCondition{Type: repository.TriggerTypeTime, Operator: repository.OperatorGreaterEqual, Value: value}
+	}
+	tests := []struct {
+		name      string
+		condition *repository.TriggerCondition
+		wantErr   bool
+	}{
+		{"future time", timeAt(float64(time.Now().Add(time.Hour).UnixMilli())), false},
+		{"past time", timeAt(1700000000000), false},
+		{"inside a composite", &repository.TriggerCondition{
+			CompositeType: repository.LogicAND,
+			SubConditions: []*repository.TriggerCondition{
+				timeAt(1700000000000),
+				{Type: repository.TriggerTypePrice, Operator: repository.OperatorGreaterThan, Value: 50000},
+			},
+		}, false},
+		{"zero time", timeAt(0), true},
+		{"negative time", timeAt(-1700000000000), true},
+		{"fractional millisecond", timeAt(1700000000000.5), true},
+		{"seconds", timeAt(1700000000), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateConditionalOrder(&repository.ConditionalOrderRequest{
+				Symbol:           "BTCUSDT",
+				Side:             api.OrderSideBuy,
+				Type:             api.OrderTypeMarket,
+				Quantity:         0.1,
+				TriggerCondition: tt.condition,
+				AllowDuplicate:   true,
+			})
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("CreateConditionalOrder() error = %v", err)
+				}
+				return
+			}
+			if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrInvalidTriggerCondition {
+				t.Errorf("CreateConditionalOrder() error = %v, want ErrInvalidTriggerCondition", err)
+			}
+		})
+	}
+}
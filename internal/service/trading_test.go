@@ -2,6 +2,7 @@ package service
 
 import (
 	"binance-trader/internal/api"
+	"binance-trader/internal/metrics"
 	"binance-trader/internal/repository"
 	"binance-trader/pkg/errors"
 	"fmt"
@@ -138,6 +139,164 @@ func TestPlaceMarketBuyOrder_RiskValidationFailure(t *testing.T) {
 	}
 }
 
+// TestPlaceMarketBuyOrderByQuote_Success tests market buy order placement by quote amount
+func TestPlaceMarketBuyOrderByQuote_Success(t *testing.T) {
+	// Setup
+	mockClient := &mockBinanceClient{
+		createOrderFunc: func(req *api.OrderRequest) (*api.OrderResponse, error) {
+			if req.QuoteOrderQty != 100 || req.Quantity != 0 {
+				t.Errorf("Expected quote order quantity 100 without quantity, got %f / %f", req.QuoteOrderQty, req.Quantity)
+			}
+			
+			return &api.OrderResponse{
+				OrderID:             12348,
+				Symbol:              "BTCUSDT",
+				Status:              api.OrderStatusFilled,
+				OrigQty:             0.002,
+				ExecutedQty:         0.002,
+				CummulativeQuoteQty: 100.0,
+			}, nil
+		},
+		getBalanceFunc: func(asset string) (*api.Balance, error) {
+			return &api.Balance{Asset: asset, Free: 10000.0, Locked: 0}, nil
+		},
+	}
+	
+	riskMgr := NewRiskManager(&RiskLimits{
+		MaxOrderAmount:    100.0,
+		MaxDailyOrders:    100,
+		MinBalanceReserve: 100.0,
+	}, mockClient)
+	
+	orderRepo := repository.NewMemoryOrderRepository()
+	service := NewTradingService(mockClient, riskMgr, orderRepo, &mockLogger{})
+	
+	// Execute
+	order, err := service.PlaceMarketBuyOrderByQuote("BTCUSDT", 100)
+	
+	// Verify
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	
+	if order.Side != api.OrderSideBuy || order.ExecutedQty != 0.002 || order.CummulativeQuoteQty != 100 {
+		t.Errorf("Expected BUY filling 0.002 for 100, got %+v", order)
+	}
+	
+	if _, err := orderRepo.FindByID(12348); err != nil {
+		t.Errorf("Expected order to be saved in repository, got error: %v", err)
+	}
+	
+	// The quote amount is checked against the maximum order amount
+	_, err = service.PlaceMarketBuyOrderByQuote("BTCUSDT", 150)
+	if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrRiskLimitExceeded {
+		t.Errorf("Expected ErrRiskLimitExceeded, got %v", err)
+	}
+}
+
+// TestPlaceMarketSellOrder_Success tests successful market sell order placement
+func TestPlaceMarketSellOrder_Success(t *testing.T) {
+	// Setup
+	var balanceAsset string
+	mockClient := &mockBinanceClient{
+		createOrderFunc: func(req *api.OrderRequest) (*api.OrderResponse, error) {
+			if req.Side != api.OrderSideSell || req.Type != api.OrderTypeMarket || req.Quantity != 0.1 {
+				t.Errorf("Expected MARKET SELL of 0.1, got %s %s of %f", req.Type, req.Side, req.Quantity)
+			}
+			
+			return &api.OrderResponse{
+				OrderID:             12346,
+				Symbol:              "BTCUSDT",
+				Status:              api.OrderStatusFilled,
+				OrigQty:             0.1,
+				ExecutedQty:         0.1,
+				CummulativeQuoteQty: 5000.0,
+				TransactTime:        1234567890,
+			}, nil
+		},
+		getPriceFunc: func(symbol string) (*api.Price, error) {
+			return &api.Price{Symbol: symbol, Price: 50000.0}, nil
+		},
+		getBalanceFunc: func(asset string) (*api.Balance, error) {
+			balanceAsset = asset
+			return &api.Balance{Asset: asset, Free: 0.1, Locked: 0}, nil
+		},
+	}
+	
+	riskMgr := NewRiskManager(&RiskLimits{
+		MaxOrderAmount:    10000.0,
+		MaxDailyOrders:    100,
+		MinBalanceReserve: 100.0,
+	}, mockClient)
+	
+	orderRepo := repository.NewMemoryOrderRepository()
+	service := NewTradingService(mockClient, riskMgr, orderRepo, &mockLogger{})
+	
+	// Execute: the whole free balance can be sold
+	order, err := service.PlaceMarketSellOrder("BTCUSDT", 0.1)
+	
+	// Verify
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	
+	if order.OrderID != 12346 || order.Side != api.OrderSideSell || order.Type != api.OrderTypeMarket {
+		t.Errorf("Expected MARKET SELL order 12346, got %+v", order)
+	}
+	
+	if balanceAsset != "BTC" {
+		t.Errorf("Expected the BTC balance to be checked, got %q", balanceAsset)
+	}
+	
+	// Verify order was saved to repository
+	if savedOrder, err := orderRepo.FindByID(12346); err != nil || savedOrder.Side != api.OrderSideSell {
+		t.Errorf("Expected sell order to be saved in repository, got %+v, %v", savedOrder, err)
+	}
+}
+
+// TestPlaceMarketSellOrder_InsufficientBalance tests market sell order beyond the free base asset balance
+func TestPlaceMarketSellOrder_InsufficientBalance(t *testing.T) {
+	// Setup
+	created := 0
+	mockClient := &mockBinanceClient{
+		createOrderFunc: func(req *api.OrderRequest) (*api.OrderResponse, error) {
+			created++
+			return &api.OrderResponse{OrderID: 1, Symbol: req.Symbol, Status: api.OrderStatusFilled}, nil
+		},
+		getPriceFunc: func(symbol string) (*api.Price, error) {
+			return &api.Price{Symbol: symbol, Price: 50000.0}, nil
+		},
+		getBalanceFunc: func(asset string) (*api.Balance, error) {
+			return &api.Balance{Asset: asset, Free: 0.05, Locked: 0.5}, nil
+		},
+	}
+	
+	riskMgr := NewRiskManager(&RiskLimits{
+		MaxOrderAmount:    10000.0,
+		MaxDailyOrders:    100,
+		MinBalanceReserve: 100.0,
+	}, mockClient)
+	
+	service := NewTradingService(mockClient, riskMgr, repository.NewMemoryOrderRepository(), &mockLogger{})
+	
+	// Execute: locked balance does not count
+	order, err := service.PlaceMarketSellOrder("BTCUSDT", 0.1)
+	
+	// Verify
+	tradingErr, ok := err.(*errors.TradingError)
+	if !ok || tradingErr.Type != errors.ErrInsufficientBalance {
+		t.Fatalf("Expected ErrInsufficientBalance, got %v", err)
+	}
+	
+	if order != nil {
+		t.Error("Expected no order to be returned")
+	}
+	
+	if created != 0 {
+		t.Errorf("Expected no order to reach the exchange, got %d", created)
+	}
+}
+
 // TestPlaceLimitSellOrder_Success tests successful limit sell order placement
 func TestPlaceLimitSellOrder_Success(t *testing.T) {
 	// Setup
@@ -239,6 +398,110 @@ func TestPlaceLimitSellOrder_InvalidPrice(t *testing.T) {
 	}
 }
 
+// TestPlaceLimitBuyOrder_Success tests successful limit buy order placement
+func TestPlaceLimitBuyOrder_Success(t *testing.T) {
+	// Setup
+	mockClient := &mockBinanceClient{
+		createOrderFunc: func(req *api.OrderRequest) (*api.OrderResponse, error) {
+			if req.Side != api.OrderSideBuy || req.Type != api.OrderTypeLimit || req.TimeInForce != "GTC" {
+				t.Errorf("Expected a GTC limit buy, got %s %s %s", req.Side, req.Type, req.TimeInForce)
+			}
+			if req.Price != 45000.0 || req.Quantity != 0.1 {
+				t.Errorf("Expected 0.1 @ 45000, got %f @ %f", req.Quantity, req.Price)
+			}
+			
+			return &api.OrderResponse{
+				OrderID:      12347,
+				Symbol:       "BTCUSDT",
+				Status:       api.OrderStatusNew,
+				Price:        45000.0,
+				OrigQty:      0.1,
+				TransactTime: 1234567890,
+			}, nil
+		},
+	}
+	
+	riskMgr := NewRiskManager(&RiskLimits{
+		MaxOrderAmount:    10000.0,
+		MaxDailyOrders:    100,
+		MinBalanceReserve: 100.0,
+	}, mockClient)
+	
+	orderRepo := repository.NewMemoryOrderRepository()
+	log := &mockLogger{}
+	
+	service := NewTradingService(mockClient, riskMgr, orderRepo, log)
+	
+	// Execute
+	order, err := service.PlaceLimitBuyOrder("BTCUSDT", 45000.0, 0.1)
+	
+	// Verify
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	
+	if order.OrderID != 12347 || order.Side != api.OrderSideBuy || order.Type != api.OrderTypeLimit {
+		t.Errorf("Unexpected order: %+v", order)
+	}
+	
+	savedOrder, err := orderRepo.FindByID(12347)
+	if err != nil {
+		t.Fatalf("Expected order to be saved, got %v", err)
+	}
+	if savedOrder.Price != 45000.0 || savedOrder.Status != api.OrderStatusNew {
+		t.Errorf("Unexpected saved order: %+v", savedOrder)
+	}
+}
+
+// TestPlaceLimitBuyOrder_Rejected tests limit buy orders rejected before reaching the exchange
+func TestPlaceLimitBuyOrder_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		symbol   string
+		price    float64
+		quantity float64
+		balance  float64
+		wantType errors.ErrorType
+	}{
+		{"empty symbol", "", 45000, 0.1, 10000, errors.ErrInvalidParameter},
+		{"zero price", "BTCUSDT", 0, 0.1, 10000, errors.ErrInvalidParameter},
+		{"negative price", "BTCUSDT", -1, 0.1, 10000, errors.ErrInvalidParameter},
+		{"zero quantity", "BTCUSDT", 45000, 0, 10000, errors.ErrInvalidParameter},
+		{"above order limit", "BTCUSDT", 45000, 1, 10000, errors.ErrRiskLimitExceeded},
+		{"below balance reserve", "BTCUSDT", 45000, 0.1, 50, errors.ErrRiskLimitExceeded},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &mockBinanceClient{
+				createOrderFunc: func(req *api.OrderRequest) (*api.OrderResponse, error) {
+					t.Error("Expected no order to be sent")
+					return nil, nil
+				},
+				getBalanceFunc: func(asset string) (*api.Balance, error) {
+					return &api.Balance{Asset: asset, Free: tt.balance}, nil
+				},
+			}
+			riskMgr := NewRiskManager(&RiskLimits{
+				MaxOrderAmount:    10000.0,
+				MaxDailyOrders:    100,
+				MinBalanceReserve: 100.0,
+			}, mockClient)
+			orderRepo := repository.NewMemoryOrderRepository()
+			service := NewTradingService(mockClient, riskMgr, orderRepo, &mockLogger{})
+			
+			order, err := service.PlaceLimitBuyOrder(tt.symbol, tt.price, tt.quantity)
+			if order != nil {
+				t.Error("Expected no order to be returned")
+			}
+			tradingErr, ok := err.(*errors.TradingError)
+			if !ok || tradingErr.Type != tt.wantType {
+				t.Errorf("Expected %v, got %v", tt.wantType, err)
+			}
+		})
+	}
+}
+
 // TestCancelOrder_Success tests successful order cancellation
 func TestCancelOrder_Success(t *testing.T) {
 	// Setup
@@ -324,6 +587,47 @@ func TestCancelOrder_OrderNotFound(t *testing.T) {
 	}
 }
 
+// TestCancelAllOrders_PartialFailure tests that orders cancelled before a failure are counted
+// and marked canceled
+func TestCancelAllOrders_PartialFailure(t *testing.T) {
+	mockClient := &mockBinanceClient{
+		bulkCancelOrdersFunc: func(symbol string) ([]api.CancelResponse, error) {
+			cancelled := []api.CancelResponse{
+				{Symbol: symbol, OrderID: 1, Status: api.OrderStatusCanceled},
+				{Symbol: symbol, OrderID: 2, Status: api.OrderStatusCanceled},
+				// Placed outside this process
+				{Symbol: symbol, OrderID: 9, Status: api.OrderStatusCanceled},
+			}
+			return cancelled, &api.BulkCancelError{Symbol: symbol, Cancelled: len(cancelled),
+				Failures: []api.BulkCancelFailure{{OrderID: 3, Code: -2011, Message: "Unknown order sent."}}}
+		},
+	}
+	orderRepo := repository.NewMemoryOrderRepository()
+	for id := int64(1); id <= 3; id++ {
+		orderRepo.Save(&api.Order{OrderID: id, Symbol: "BTCUSDT", Side: api.OrderSideBuy, Type: api.OrderTypeLimit,
+			Status: api.OrderStatusPartiallyFilled, Price: 50000, OrigQty: 0.1, ExecutedQty: 0.04})
+	}
+	service := NewTradingService(mockClient, NewRiskManager(nil, mockClient), orderRepo, &mockLogger{})
+
+	cancelled, err := service.CancelAllOrders("BTCUSDT")
+	if cancelled != 3 {
+		t.Errorf("Expected 3 cancelled orders, got %d", cancelled)
+	}
+	if _, ok := err.(*api.BulkCancelError); !ok {
+		t.Fatalf("Expected the bulk cancel error, got %v", err)
+	}
+	for id, want := range map[int64]api.OrderStatus{1: api.OrderStatusCanceled, 2: api.OrderStatusCanceled, 3: api.OrderStatusPartiallyFilled} {
+		order, _ := orderRepo.FindByID(id)
+		if order.Status != want || order.ExecutedQty != 0.04 {
+			t.Errorf("Order %d = %s with %v executed, want %s with 0.04", id, order.Status, order.ExecutedQty, want)
+		}
+	}
+
+	if _, err := service.CancelAllOrders(""); err == nil {
+		t.Error("Expected error for an empty symbol")
+	}
+}
+
 // TestGetOrderStatus_Success tests successful order status retrieval
 func TestGetOrderStatus_Success(t *testing.T) {
 	// Setup
@@ -478,3 +782,286 @@ func TestGetActiveOrders_APIError(t *testing.T) {
 		t.Error("Expected no orders to be returned")
 	}
 }
+
+func TestPlaceOrder_CountsOrders(t *testing.T) {
+	createErr := error(nil)
+	mockClient := &mockBinanceClient{
+		createOrderFunc: func(req *api.OrderRequest) (*api.OrderResponse, error) {
+			if createErr != nil {
+				return nil, createErr
+			}
+			return &api.OrderResponse{OrderID: 1, Symbol: req.Symbol, Status: api.OrderStatusNew, Price: req.Price, OrigQty: req.Quantity}, nil
+		},
+		getBalanceFunc: func(asset string) (*api.Balance, error) {
+			return &api.Balance{Asset: asset, Free: 10000.0}, nil
+		},
+	}
+	service := NewTradingService(mockClient, NewRiskManager(&RiskLimits{MaxOrderAmount: 100000, MaxDailyOrders: 100}, mockClient),
+		repository.NewMemoryOrderRepository(), &mockLogger{})
+
+	sink := metrics.NewSink(metrics.NewRegistry())
+	service.SetMetrics(sink)
+	orders := sink.Orders.(*metrics.Counter)
+
+	if _, err := service.PlaceLimitSellOrder("BTCUSDT", 60000, 0.1); err != nil {
+		t.Fatalf("PlaceLimitSellOrder() unexpected error: %v", err)
+	}
+	createErr = errors.NewTradingError(errors.ErrInvalidParameter, "order rejected", 400, nil)
+	if _, err := service.PlaceLimitSellOrder("BTCUSDT", 60000, 0.1); err == nil {
+		t.Fatal("expected the exchange error")
+	}
+	// Orders refused before they are sent are not counted
+	service.PlaceLimitSellOrder("BTCUSDT", 60000, 0)
+
+	if got := orders.Value("SELL", "NEW"); got != 1 {
+		t.Errorf("orders counted as NEW = %v, want 1", got)
+	}
+	if got := orders.Value("SELL", "error"); got != 1 {
+		t.Errorf("orders counted as error = %v, want 1", got)
+	}
+}
+
+func TestGetAllBalances(t *testing.T) {
+	accountErr := error(nil)
+	mockClient := &mockBinanceClient{
+		getAccountInfoFunc: func() (*api.AccountInfo, error) {
+			if accountErr != nil {
+				return nil, accountErr
+			}
+			return &api.AccountInfo{
+				CanTrade: true,
+				Balances: []api.Balance{{Asset: "BTC", Free: 0.5, Locked: 0.1}, {Asset: "USDT", Free: 1000}},
+			}, nil
+		},
+	}
+	service := NewTradingService(mockClient, NewRiskManager(nil, mockClient), repository.NewMemoryOrderRepository(), &mockLogger{})
+
+	balances, err := service.GetAllBalances()
+	if err != nil {
+		t.Fatalf("GetAllBalances() unexpected error: %v", err)
+	}
+	if len(balances) != 2 || balances[0].Asset != "BTC" || balances[0].Locked != 0.1 || balances[1].Free != 1000 {
+		t.Errorf("GetAllBalances() = %+v", balances)
+	}
+
+	accountErr = fmt.Errorf("API connection failed")
+	if _, err := service.GetAllBalances(); err == nil {
+		t.Error("expected the account error")
+	}
+}
+
+// mockQuoteMarketDataService serves a fixed current price and top of book
+type mockQuoteMarketDataService struct {
+	mockStopLossMarketDataService
+	quote    *BestBidAsk
+	quoteErr error
+}
+
+func (m *mockQuoteMarketDataService) GetBestBidAsk(symbol string) (*BestBidAsk, error) {
+	if m.quoteErr != nil {
+		return nil, m.quoteErr
+	}
+	return m.quote, nil
+}
+
+// newSlippageTestService creates a trading service whose client counts the orders it receives
+func newSlippageTestService(market MarketDataService, maxSlippagePercent float64, log *mockLoggerCapture) (SpotTradingService, *int) {
+	created := 0
+	mockClient := &mockBinanceClient{
+		createOrderFunc: func(req *api.OrderRequest) (*api.OrderResponse, error) {
+			created++
+			return &api.OrderResponse{
+				OrderID:             int64(created),
+				Symbol:              req.Symbol,
+				Status:              api.OrderStatusFilled,
+				OrigQty:             req.Quantity,
+				ExecutedQty:         req.Quantity,
+				CummulativeQuoteQty: req.Quantity * 50000,
+			}, nil
+		},
+		getBalanceFunc: func(asset string) (*api.Balance, error) {
+			return &api.Balance{Asset: asset, Free: 100000.0}, nil
+		},
+	}
+	riskMgr := NewRiskManager(&RiskLimits{
+		MaxOrderAmount:    100000.0,
+		MaxDailyOrders:    100,
+		MinBalanceReserve: 100.0,
+	}, mockClient)
+	
+	service := NewTradingService(mockClient, riskMgr, repository.NewMemoryOrderRepository(), log)
+	service.SetSlippageProtection(market, maxSlippagePercent)
+	return service, &created
+}
+
+// TestPlaceMarketOrder_SlippageProtection tests market orders are rejected when the spread is too wide
+func TestPlaceMarketOrder_SlippageProtection(t *testing.T) {
+	// A wide book: buying fills 3% above the current price, selling 2% below it
+	market := &mockQuoteMarketDataService{
+		mockStopLossMarketDataService: mockStopLossMarketDataService{currentPrice: 50000},
+		quote:                         &BestBidAsk{Symbol: "BTCUSDT", BidPrice: 49000, AskPrice: 51500},
+	}
+	log := &mockLoggerCapture{}
+	service, created := newSlippageTestService(market, 1, log)
+	
+	for _, place := range []func(string, float64) (*api.Order, error){service.PlaceMarketBuyOrder, service.PlaceMarketSellOrder} {
+		order, err := place("BTCUSDT", 0.1)
+		tradingErr, ok := err.(*errors.TradingError)
+		if !ok || tradingErr.Type != errors.ErrRiskLimitExceeded {
+			t.Fatalf("Expected slippage rejection, got %v", err)
+		}
+		if order != nil {
+			t.Error("Expected no order to be returned")
+		}
+	}
+	if *created != 0 {
+		t.Errorf("Expected no order to reach the exchange, got %d", *created)
+	}
+	
+	// Both checks are logged with the computed slippage
+	var checks []map[string]interface{}
+	for _, entry := range log.entries {
+		if entry["event_type"] == "slippage_checked" {
+			checks = append(checks, entry)
+		}
+	}
+	if len(checks) != 2 {
+		t.Fatalf("Expected 2 slippage events, got %d", len(checks))
+	}
+	for i, want := range []float64{3, 2} {
+		if slippage := checks[i]["slippage_percent"].(float64); slippage < want-1e-9 || slippage > want+1e-9 {
+			t.Errorf("Expected slippage %v%%, got %v%%", want, slippage)
+		}
+		if checks[i]["rejected"] != true {
+			t.Errorf("Expected slippage event %d to be rejected", i)
+		}
+	}
+	
+	// A tight book passes the check
+	market.quote = &BestBidAsk{Symbol: "BTCUSDT", BidPrice: 49990, AskPrice: 50010}
+	if _, err := service.PlaceMarketBuyOrder("BTCUSDT", 0.1); err != nil {
+		t.Fatalf("Expected order within the slippage limit to be placed, got %v", err)
+	}
+	if *created != 1 {
+		t.Errorf("Expected 1 order to reach the exchange, got %d", *created)
+	}
+}
+
+// TestPlaceMarketOrder_SlippageProtectionDisabled tests a maximum of 0 skips the check
+func TestPlaceMarketOrder_SlippageProtectionDisabled(t *testing.T) {
+	market := &mockQuoteMarketDataService{
+		mockStopLossMarketDataService: mockStopLossMarketDataService{currentPrice: 50000},
+		quote:                         &BestBidAsk{Symbol: "BTCUSDT", BidPrice: 40000, AskPrice: 60000},
+	}
+	log := &mockLoggerCapture{}
+	service, created := newSlippageTestService(market, 0, log)
+	
+	if _, err := service.PlaceMarketBuyOrder("BTCUSDT", 0.1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if *created != 1 {
+		t.Errorf("Expected 1 order to reach the exchange, got %d", *created)
+	}
+	for _, entry := range log.entries {
+		if entry["event_type"] == "slippage_checked" {
+			t.Error("Expected no slippage check when disabled")
+		}
+	}
+}
+
+// TestStopLoss_IgnoresSlippageProtection tests stop loss and trailing stop sells still close
+// the position when the spread is wider than the slippage limit
+func TestStopLoss_IgnoresSlippageProtection(t *testing.T) {
+	// Selling would fill 4% below the current price, four times the limit
+	market := &mockQuoteMarketDataService{
+		mockStopLossMarketDataService: mockStopLossMarketDataService{currentPrice: 50000},
+		quote:                         &BestBidAsk{Symbol: "BTCUSDT", BidPrice: 48000, AskPrice: 50100},
+	}
+	trading, created := newSlippageTestService(market, 1, &mockLoggerCapture{})
+	stopOrderRepo := repository.NewMemoryStopOrderRepository()
+	svc := NewStopLossService(stopOrderRepo, NewTriggerEngine(), trading, market, &mockLogger{})
+	sls := svc.(*stopLossService)
+	
+	// A plain market sell is still rejected
+	if _, err := trading.PlaceMarketSellOrder("BTCUSDT", 0.1); err == nil {
+		t.Fatal("Expected the market sell to fail the slippage check")
+	}
+	
+	stop, err := svc.SetStopLoss("BTCUSDT", 0.1, 49500)
+	if err != nil {
+		t.Fatalf("SetStopLoss() unexpected error: %v", err)
+	}
+	trailing, err := svc.SetTrailingStop("BTCUSDT", 0.1, 1)
+	if err != nil {
+		t.Fatalf("SetTrailingStop() unexpected error: %v", err)
+	}
+	
+	market.currentPrice = 49000
+	if executed, err := sls.ExecuteStopLossIfReached(stop.OrderID, 49000); err != nil || !executed {
+		t.Fatalf("Expected the stop loss to fire, got executed=%v err=%v", executed, err)
+	}
+	if triggered, err := sls.UpdateTrailingStopPrice(trailing.OrderID, 49000); err != nil || !triggered {
+		t.Fatalf("Expected the trailing stop to fire, got triggered=%v err=%v", triggered, err)
+	}
+	if *created != 2 {
+		t.Errorf("Expected both protective sells to reach the exchange, got %d", *created)
+	}
+	if order, _ := stopOrderRepo.FindStopOrderByID(stop.OrderID); order.Status != repository.StopOrderStatusTriggered {
+		t.Errorf("Expected the stop loss to be triggered, got %s", order.Status)
+	}
+}
+
+// TestPlaceOrder_ReleasesNotionalOnFailure tests an order the exchange rejects gives back the
+// notional it reserved under the per-minute cap
+func TestPlaceOrder_ReleasesNotionalOnFailure(t *testing.T) {
+	failing := true
+	mockClient := &mockBinanceClient{
+		createOrderFunc: func(req *api.OrderRequest) (*api.OrderResponse, error) {
+			if failing {
+				return nil, errors.NewTradingError(errors.ErrNetwork, "exchange unavailable", 0, nil)
+			}
+			return &api.OrderResponse{OrderID: 1, Symbol: req.Symbol, Status: api.OrderStatusNew, OrigQty: req.Quantity}, nil
+		},
+		getBalanceFunc: func(asset string) (*api.Balance, error) {
+			return &api.Balance{Asset: asset, Free: 100000.0}, nil
+		},
+	}
+	riskMgr := NewRiskManager(&RiskLimits{
+		MaxOrderAmount:    100000.0,
+		MaxDailyOrders:    100,
+		MaxNotionalPerMin: 10000.0,
+		NotionalCapMode:   NotionalCapReject,
+	}, mockClient)
+	service := NewTradingService(mockClient, riskMgr, repository.NewMemoryOrderRepository(), &mockLogger{})
+	
+	if _, err := service.PlaceLimitBuyOrder("BTCUSDT", 50000, 0.2); err == nil {
+		t.Fatal("Expected the exchange error")
+	}
+	
+	// The failed order left the whole cap available
+	failing = false
+	if _, err := service.PlaceLimitBuyOrder("BTCUSDT", 50000, 0.2); err != nil {
+		t.Fatalf("Expected the retried order to fit the cap, got %v", err)
+	}
+	
+	// The placed order uses it up
+	if _, err := service.PlaceLimitBuyOrder("BTCUSDT", 50000, 0.01); err == nil {
+		t.Error("Expected an order over the cap to be rejected")
+	}
+}
+
+// TestPlaceMarketOrder_SlippageQuoteUnavailable tests orders are rejected when the book cannot be read
+func TestPlaceMarketOrder_SlippageQuoteUnavailable(t *testing.T) {
+	market := &mockQuoteMarketDataService{
+		mockStopLossMarketDataService: mockStopLossMarketDataService{currentPrice: 50000},
+		quoteErr:                      fmt.Errorf("book ticker unavailable"),
+	}
+	service, created := newSlippageTestService(market, 1, &mockLoggerCapture{})
+	
+	if _, err := service.PlaceMarketSellOrder("BTCUSDT", 0.1); err == nil {
+		t.Fatal("Expected error when the book cannot be read")
+	}
+	if *created != 0 {
+		t.Errorf("Expected no order to reach the exchange, got %d", *created)
+	}
+}
@@ -13,6 +13,11 @@ const (
 	TriggerTypePrice TriggerType = iota
 	TriggerTypePriceChangePercent
 	TriggerTypeVolume
+	TriggerTypeRSI
+	TriggerTypeAskBidImbalance
+	TriggerTypeMACrossover
+	TriggerTypeVolumeSurge
+	TriggerTypeTime
 )
 
 // ComparisonOperator represents comparison operators for trigger conditions
@@ -42,6 +47,10 @@ type TriggerCondition struct {
 	Value         float64
 	BasePrice     float64
 	TimeWindow    time.Duration
+	Period        int    // RSI period
+	Interval      string // RSI or MA crossover kline interval
+	FastPeriod    int    // MA crossover fast period
+	SlowPeriod    int    // MA crossover slow period
 	CompositeType LogicOperator
 	SubConditions []*TriggerCondition
 }
@@ -157,6 +166,16 @@ func (te *triggerEngine) EvaluateCondition(condition *TriggerCondition, currentV
 		return te.evaluateCompositeCondition(condition, currentValue)
 	}
 	
+	// Imbalance ratios cannot leave [-1, 1]; a value outside comes from a broken book
+	if condition.Type == TriggerTypeAskBidImbalance && (currentValue < -1 || currentValue > 1) {
+		return false, fmt.Errorf("ask/bid imbalance %v is outside [-1, 1]", currentValue)
+	}
+	
+	// A volume surge multiplier divides two volumes and cannot be negative
+	if condition.Type == TriggerTypeVolumeSurge && !(currentValue >= 0) {
+		return false, fmt.Errorf("volume surge multiplier %v is negative", currentValue)
+	}
+	
 	// Evaluate simple condition
 	return te.evaluateSimpleCondition(condition, currentValue), nil
 }
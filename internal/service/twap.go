@@ -0,0 +1,256 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/pkg/errors"
+	"binance-trader/pkg/logger"
+	"context"
+	"fmt"
+	"time"
+)
+
+// TWAPSlice is one child market order of a TWAP execution
+type TWAPSlice struct {
+	OrderID     int64
+	Quantity    float64 // Quantity the slice asked for
+	ExecutedQty float64
+	QuoteQty    float64
+	Price       float64 // Average fill price of the slice
+	Status      api.OrderStatus
+}
+
+// TWAPExecution is the result of a TWAP order: the child orders submitted so far and their
+// combined fill. A cancelled execution lists only the slices submitted before the cancellation.
+type TWAPExecution struct {
+	Symbol        string
+	Side          api.OrderSide
+	TotalQuantity float64
+	Interval      time.Duration // Time between two child orders
+	SliceCount    int
+	Slices        []*TWAPSlice
+	ExecutedQty   float64
+	QuoteQty      float64
+	AveragePrice  float64 // Quote quantity over executed quantity of all slices
+}
+
+// UnfilledQty returns the part of the total quantity that no slice filled
+func (e *TWAPExecution) UnfilledQty() float64 {
+	unfilled := e.TotalQuantity - e.ExecutedQty
+	if unfilled < roundingEpsilon {
+		return 0
+	}
+	return unfilled
+}
+
+// record adds a submitted child order to the execution
+func (e *TWAPExecution) record(quantity float64, order *api.Order) {
+	slice := &TWAPSlice{
+		OrderID:     order.OrderID,
+		Quantity:    quantity,
+		ExecutedQty: order.ExecutedQty,
+		QuoteQty:    order.CummulativeQuoteQty,
+		Status:      order.Status,
+	}
+	if order.ExecutedQty > 0 {
+		slice.Price = order.CummulativeQuoteQty / order.ExecutedQty
+	}
+	e.Slices = append(e.Slices, slice)
+
+	e.ExecutedQty += slice.ExecutedQty
+	e.QuoteQty += slice.QuoteQty
+	if e.ExecutedQty > 0 {
+		e.AveragePrice = e.QuoteQty / e.ExecutedQty
+	}
+}
+
+// executeTWAP splits totalQty into equal market orders placed through trading, the first at once
+// and the others one interval apart; after returns a channel firing once the interval has passed.
+// The trading service saves every child order to its order repository. A slice held back by a
+// temporary limit that says when to retry, such as the notional cap in delay mode, is retried
+// then. When ctx is done, the remaining slices are dropped, child orders still open are
+// cancelled and the partial execution is returned with an error.
+func executeTWAP(
+	ctx context.Context,
+	trading SpotTradingService,
+	after func(time.Duration) <-chan time.Time,
+	log logger.Logger,
+	symbol string,
+	side api.OrderSide,
+	totalQty float64,
+	duration time.Duration,
+	slices int,
+) (*TWAPExecution, error) {
+	if err := validateTWAP(symbol, side, totalQty, duration, slices); err != nil {
+		return nil, err
+	}
+
+	execution := &TWAPExecution{
+		Symbol:        symbol,
+		Side:          side,
+		TotalQuantity: totalQty,
+		Interval:      duration / time.Duration(slices),
+		SliceCount:    slices,
+	}
+	sliceQty := totalQty / float64(slices)
+
+	log.Info("Starting TWAP execution", map[string]interface{}{
+		"symbol":   symbol,
+		"side":     string(side),
+		"quantity": totalQty,
+		"slices":   slices,
+		"interval": execution.Interval.String(),
+	})
+
+	cancelled := func() (*TWAPExecution, error) {
+		cancelOpenTWAPSlices(trading, log, execution)
+		log.Warn("TWAP execution cancelled", map[string]interface{}{
+			"symbol":       symbol,
+			"slices_done":  len(execution.Slices),
+			"executed_qty": execution.ExecutedQty,
+			"unfilled_qty": execution.UnfilledQty(),
+		})
+		return execution, fmt.Errorf("TWAP execution cancelled after %d of %d slices: %w",
+			len(execution.Slices), slices, ctx.Err())
+	}
+
+	for i := 0; i < slices; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+			case <-after(execution.Interval):
+			}
+		}
+		if ctx.Err() != nil {
+			return cancelled()
+		}
+
+		// The last slice takes the remainder so floating point error never changes the total
+		quantity := sliceQty
+		if i == slices-1 {
+			quantity = totalQty - sliceQty*float64(slices-1)
+		}
+
+		order, err := placeTWAPSlice(trading, side, symbol, quantity)
+		for err != nil {
+			wait, retry := errors.RetryAfter(err)
+			if !retry {
+				break
+			}
+			log.Warn("TWAP slice held back, retrying", map[string]interface{}{
+				"symbol":      symbol,
+				"slice":       i + 1,
+				"retry_after": wait.String(),
+				"error":       err.Error(),
+			})
+			select {
+			case <-ctx.Done():
+				return cancelled()
+			case <-after(wait):
+			}
+			order, err = placeTWAPSlice(trading, side, symbol, quantity)
+		}
+		if err != nil {
+			log.Error("TWAP slice failed, stopping execution", map[string]interface{}{
+				"symbol": symbol,
+				"slice":  i + 1,
+				"error":  err.Error(),
+			})
+			return execution, fmt.Errorf("TWAP slice %d of %d failed: %w", i+1, slices, err)
+		}
+		execution.record(quantity, order)
+	}
+
+	log.Info("TWAP execution completed", map[string]interface{}{
+		"symbol":        symbol,
+		"side":          string(side),
+		"executed_qty":  execution.ExecutedQty,
+		"average_price": execution.AveragePrice,
+	})
+	return execution, nil
+}
+
+// placeTWAPSlice places one child market order of a TWAP execution
+func placeTWAPSlice(trading SpotTradingService, side api.OrderSide, symbol string, quantity float64) (*api.Order, error) {
+	if side == api.OrderSideBuy {
+		return trading.PlaceMarketBuyOrder(symbol, quantity)
+	}
+	return trading.PlaceMarketSellOrder(symbol, quantity)
+}
+
+// TWAPResult is the outcome of a TWAP order run by a TWAPExecutor
+type TWAPResult = TWAPExecution
+
+// TWAPExecutor runs TWAP orders through a trading service, which keeps every child order in its
+// order repository; Shutdown stops the running orders before their next slice
+type TWAPExecutor struct {
+	trading SpotTradingService
+	logger  logger.Logger
+	after   func(time.Duration) <-chan time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewTWAPExecutor creates a TWAP executor placing its child orders through trading
+func NewTWAPExecutor(trading SpotTradingService, log logger.Logger) *TWAPExecutor {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &TWAPExecutor{
+		trading: trading,
+		logger:  log,
+		after:   time.After,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// ExecuteTWAP places slices market orders for an equal share of totalQuantity, the first at
+// once and the others interval apart, and returns once the last one is placed. After Shutdown
+// it returns the slices placed so far with an error.
+func (e *TWAPExecutor) ExecuteTWAP(symbol string, side api.OrderSide, totalQuantity float64, slices int, interval time.Duration) (*TWAPResult, error) {
+	if interval < 0 {
+		return nil, errors.NewTradingError(errors.ErrInvalidParameter, "interval cannot be negative", 0, nil)
+	}
+	return executeTWAP(e.ctx, e.trading, e.after, e.logger, symbol, side, totalQuantity, interval*time.Duration(slices), slices)
+}
+
+// Shutdown cancels the running TWAP orders and any started later
+func (e *TWAPExecutor) Shutdown() {
+	e.cancel()
+}
+
+// validateTWAP checks the parameters of a TWAP order
+func validateTWAP(symbol string, side api.OrderSide, totalQty float64, duration time.Duration, slices int) error {
+	var message string
+	switch {
+	case symbol == "":
+		message = "symbol cannot be empty"
+	case side != api.OrderSideBuy && side != api.OrderSideSell:
+		message = "side must be BUY or SELL"
+	case totalQty <= 0:
+		message = "quantity must be greater than 0"
+	case duration < 0:
+		message = "duration cannot be negative"
+	case slices < 1:
+		message = "slices must be at least 1"
+	default:
+		return nil
+	}
+	return errors.NewTradingError(errors.ErrInvalidParameter, message, 0, nil)
+}
+
+// cancelOpenTWAPSlices cancels the child orders of an execution that have not completely filled
+func cancelOpenTWAPSlices(trading SpotTradingService, log logger.Logger, execution *TWAPExecution) {
+	for _, slice := range execution.Slices {
+		if slice.Status != api.OrderStatusNew && slice.Status != api.OrderStatusPartiallyFilled {
+			continue
+		}
+		if err := trading.CancelOrder(slice.OrderID); err != nil {
+			log.Warn("Failed to cancel TWAP slice", map[string]interface{}{
+				"order_id": slice.OrderID,
+				"error":    err.Error(),
+			})
+			continue
+		}
+		slice.Status = api.OrderStatusCanceled
+	}
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	"context"
+	stderrors "errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// fakeTWAPClock stands in for time.After: every wait fires at once, and onWait runs before it
+// so a test can move the price or cancel the execution between slices
+type fakeTWAPClock struct {
+	waits  []time.Duration
+	onWait func(wait int) bool // Returns false to leave the wait pending
+}
+
+func (c *fakeTWAPClock) after(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	if c.onWait != nil && !c.onWait(len(c.waits)) {
+		return nil
+	}
+	fired := make(chan time.Time, 1)
+	fired <- time.Time{}
+	return fired
+}
+
+// newFillingSpotService returns a spot trading service whose market orders fill at once at the
+// price, adding their quantity to filled
+func newFillingSpotService(price *float64, filled *float64, orderRepo repository.OrderRepository) SpotTradingService {
+	nextOrderID := int64(0)
+	client := &mockBinanceClient{
+		createOrderFunc: func(req *api.OrderRequest) (*api.OrderResponse, error) {
+			nextOrderID++
+			*filled += req.Quantity
+			return &api.OrderResponse{
+				OrderID:             nextOrderID,
+				Symbol:              req.Symbol,
+				Status:              api.OrderStatusFilled,
+				OrigQty:             req.Quantity,
+				ExecutedQty:         req.Quantity,
+				CummulativeQuoteQty: req.Quantity * *price,
+			}, nil
+		},
+		getPriceFunc: func(symbol string) (*api.Price, error) {
+			return &api.Price{Symbol: symbol, Price: *price}, nil
+		},
+		getBalanceFunc: func(asset string) (*api.Balance, error) {
+			return &api.Balance{Asset: asset, Free: 10000}, nil
+		},
+	}
+	riskMgr := NewRiskManager(&RiskLimits{MaxOrderAmount: 10000, MaxDailyOrders: 100}, client)
+	return NewSpotTradingService(client, riskMgr, orderRepo, &mockLogger{})
+}
+
+func TestExecuteTWAP(t *testing.T) {
+	price, bought := 50000.0, 0.0
+	orderRepo := repository.NewMemoryOrderRepository()
+	spot := newFillingSpotService(&price, &bought, orderRepo)
+	clock := &fakeTWAPClock{onWait: func(wait int) bool {
+		price += 100
+		return true
+	}}
+	spot.(*spotTradingService).after = clock.after
+
+	execution, err := spot.ExecuteTWAP(context.Background(), "BTCUSDT", api.OrderSideBuy, 0.1, time.Hour, 4)
+	if err != nil {
+		t.Fatalf("ExecuteTWAP() error = %v", err)
+	}
+
+	// The first slice goes out at once and the others one interval apart
+	if len(clock.waits) != 3 {
+		t.Fatalf("waits = %v, want 3", clock.waits)
+	}
+	for _, wait := range clock.waits {
+		if wait != 15*time.Minute {
+			t.Errorf("wait = %s, want 15m", wait)
+		}
+	}
+
+	if len(execution.Slices) != 4 {
+		t.Fatalf("slices = %d, want 4", len(execution.Slices))
+	}
+	for i, slice := range execution.Slices {
+		wantPrice := 50000 + 100*float64(i)
+		if math.Abs(slice.Quantity-0.025) > 1e-9 || math.Abs(slice.ExecutedQty-0.025) > 1e-9 || slice.Price != wantPrice {
+			t.Errorf("slice %d = %+v, want 0.025 filled at %v", i+1, slice, wantPrice)
+		}
+		if slice.Status != api.OrderStatusFilled || slice.OrderID == 0 {
+			t.Errorf("slice %d = %s / %d, want FILLED with an order ID", i+1, slice.Status, slice.OrderID)
+		}
+	}
+	if math.Abs(execution.ExecutedQty-0.1) > 1e-9 || math.Abs(execution.AveragePrice-50150) > 1e-6 {
+		t.Errorf("execution = %v filled at %v, want 0.1 at 50150", execution.ExecutedQty, execution.AveragePrice)
+	}
+	if execution.UnfilledQty() != 0 {
+		t.Errorf("unfilled = %v, want 0", execution.UnfilledQty())
+	}
+	if math.Abs(bought-0.1) > 1e-9 {
+		t.Errorf("bought = %v, want 0.1", bought)
+	}
+
+	// The trading service records every child order
+	for _, slice := range execution.Slices {
+		if stored, err := orderRepo.FindByID(slice.OrderID); err != nil || stored.Symbol != "BTCUSDT" {
+			t.Errorf("stored order %d = %+v, %v; want the child order", slice.OrderID, stored, err)
+		}
+	}
+}
+
+func TestExecuteTWAP_Cancelled(t *testing.T) {
+	price, sold := 2000.0, 0.0
+	spot := newFillingSpotService(&price, &sold, repository.NewMemoryOrderRepository())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cancel while waiting for the third slice
+	clock := &fakeTWAPClock{onWait: func(wait int) bool {
+		if wait == 2 {
+			cancel()
+			return false
+		}
+		return true
+	}}
+	spot.(*spotTradingService).after = clock.after
+
+	execution, err := spot.ExecuteTWAP(ctx, "ETHUSDT", api.OrderSideSell, 4, 40*time.Minute, 4)
+	if !stderrors.Is(err, context.Canceled) {
+		t.Fatalf("ExecuteTWAP() error = %v, want context.Canceled", err)
+	}
+	if execution == nil || len(execution.Slices) != 2 {
+		t.Fatalf("execution = %+v, want the two slices placed before the cancellation", execution)
+	}
+	if execution.ExecutedQty != 2 || execution.UnfilledQty() != 2 {
+		t.Errorf("executed / unfilled = %v / %v, want 2 / 2", execution.ExecutedQty, execution.UnfilledQty())
+	}
+	if sold != 2 {
+		t.Errorf("sold = %v, want 2", sold)
+	}
+}
+
+func TestExecuteTWAP_CancelsOpenSlices(t *testing.T) {
+	var cancelled []int64
+	nextOrderID := int64(100)
+	client := &mockBinanceClient{
+		createOrderFunc: func(req *api.OrderRequest) (*api.OrderResponse, error) {
+			nextOrderID++
+			return &api.OrderResponse{OrderID: nextOrderID, Symbol: req.Symbol, Status: api.OrderStatusNew, OrigQty: req.Quantity}, nil
+		},
+		cancelOrderFunc: func(symbol string, orderID int64) (*api.CancelResponse, error) {
+			cancelled = append(cancelled, orderID)
+			return &api.CancelResponse{OrderID: orderID, Symbol: symbol, Status: api.OrderStatusCanceled}, nil
+		},
+		getBalanceFunc: func(asset string) (*api.Balance, error) {
+			return &api.Balance{Asset: asset, Free: 10000}, nil
+		},
+	}
+	riskMgr := NewRiskManager(&RiskLimits{MaxOrderAmount: 10000, MaxDailyOrders: 100}, client)
+	spot := NewSpotTradingService(client, riskMgr, repository.NewMemoryOrderRepository(), &mockLogger{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clock := &fakeTWAPClock{onWait: func(wait int) bool {
+		cancel()
+		return false
+	}}
+	spot.(*spotTradingService).after = clock.after
+
+	execution, err := spot.ExecuteTWAP(ctx, "BTCUSDT", api.OrderSideBuy, 0.03, 3*time.Minute, 3)
+	if !stderrors.Is(err, context.Canceled) {
+		t.Fatalf("ExecuteTWAP() error = %v, want context.Canceled", err)
+	}
+	// The first slice rested on the book, so the cancellation cancels it
+	if len(cancelled) != 1 || cancelled[0] != 101 {
+		t.Fatalf("cancelled orders = %v, want [101]", cancelled)
+	}
+	if len(execution.Slices) != 1 || execution.Slices[0].Status != api.OrderStatusCanceled {
+		t.Errorf("slices = %+v, want the first slice CANCELED", execution.Slices)
+	}
+}
+
+func TestExecuteTWAP_Validation(t *testing.T) {
+	price, filled := 100.0, 0.0
+	spot := newFillingSpotService(&price, &filled, repository.NewMemoryOrderRepository())
+	tests := []struct {
+		name     string
+		symbol   string
+		side     api.OrderSide
+		quantity float64
+		duration time.Duration
+		slices   int
+	}{
+		{"empty symbol", "", api.OrderSideBuy, 1, time.Minute, 2},
+		{"invalid side", "BNBUSDT", "HOLD", 1, time.Minute, 2},
+		{"zero quantity", "BNBUSDT", api.OrderSideBuy, 0, time.Minute, 2},
+		{"negative duration", "BNBUSDT", api.OrderSideBuy, 1, -time.Minute, 2},
+		{"no slices", "BNBUSDT", api.OrderSideBuy, 1, time.Minute, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execution, err := spot.ExecuteTWAP(context.Background(), tt.symbol, tt.side, tt.quantity, tt.duration, tt.slices)
+			if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrInvalidParameter {
+				t.Errorf("error = %v, want ErrInvalidParameter", err)
+			}
+			if execution != nil {
+				t.Errorf("execution = %+v, want nil", execution)
+			}
+		})
+	}
+}
+
+// twapTradingService records the child orders of a TWAP executor, giving each a new ID; each
+// entry of holdBack rejects one order with a temporary limit asking to retry after it
+type twapTradingService struct {
+	mockStopLossTradingService
+	quantities []float64
+	holdBack   []time.Duration
+}
+
+func (m *twapTradingService) PlaceMarketSellOrder(symbol string, quantity float64) (*api.Order, error) {
+	if len(m.holdBack) > 0 {
+		err := errors.NewTradingError(errors.ErrRiskLimitExceeded, "notional throughput limit reached", 0, nil)
+		err.RetryAfter, m.holdBack = m.holdBack[0], m.holdBack[1:]
+		return nil, err
+	}
+	m.quantities = append(m.quantities, quantity)
+	return &api.Order{
+		OrderID:             int64(len(m.quantities)),
+		Symbol:              symbol,
+		Side:                api.OrderSideSell,
+		Status:              api.OrderStatusFilled,
+		OrigQty:             quantity,
+		ExecutedQty:         quantity,
+		CummulativeQuoteQty: quantity * 300,
+	}, nil
+}
+
+func TestTWAPExecutor(t *testing.T) {
+	trading := &twapTradingService{}
+	executor := NewTWAPExecutor(trading, &mockLogger{})
+	clock := &fakeTWAPClock{}
+	executor.after = clock.after
+
+	result, err := executor.ExecuteTWAP("BNBUSDT", api.OrderSideSell, 5, 10, 30*time.Second)
+	if err != nil {
+		t.Fatalf("ExecuteTWAP() error = %v", err)
+	}
+
+	// Exactly one child order per slice, the others one interval after the first
+	if len(trading.quantities) != 10 {
+		t.Fatalf("child orders = %d, want 10", len(trading.quantities))
+	}
+	for i, quantity := range trading.quantities {
+		if math.Abs(quantity-0.5) > 1e-9 {
+			t.Errorf("child order %d quantity = %v, want 0.5", i+1, quantity)
+		}
+	}
+	if len(clock.waits) != 9 || clock.waits[0] != 30*time.Second {
+		t.Errorf("waits = %v, want 9 of 30s", clock.waits)
+	}
+	if len(result.Slices) != 10 || math.Abs(result.ExecutedQty-5) > 1e-9 || math.Abs(result.AveragePrice-300) > 1e-9 {
+		t.Errorf("result = %+v, want 10 slices filling 5 at 300", result)
+	}
+}
+
+func TestTWAPExecutor_Shutdown(t *testing.T) {
+	trading := &twapTradingService{}
+	executor := NewTWAPExecutor(trading, &mockLogger{})
+
+	// Shut down while waiting for the fourth slice
+	clock := &fakeTWAPClock{onWait: func(wait int) bool {
+		if wait == 3 {
+			executor.Shutdown()
+			return false
+		}
+		return true
+	}}
+	executor.after = clock.after
+
+	result, err := executor.ExecuteTWAP("BNBUSDT", api.OrderSideSell, 5, 10, time.Minute)
+	if !stderrors.Is(err, context.Canceled) {
+		t.Fatalf("ExecuteTWAP() error = %v, want context.Canceled", err)
+	}
+	if len(trading.quantities) != 3 || len(result.Slices) != 3 {
+		t.Errorf("child orders = %d, slices = %d; want 3", len(trading.quantities), len(result.Slices))
+	}
+
+	// Orders started after the shutdown place nothing
+	if _, err := executor.ExecuteTWAP("BNBUSDT", api.OrderSideSell, 1, 2, time.Minute); !stderrors.Is(err, context.Canceled) {
+		t.Errorf("ExecuteTWAP() after Shutdown error = %v, want context.Canceled", err)
+	}
+	if len(trading.quantities) != 3 {
+		t.Errorf("child orders after Shutdown = %d, want 3", len(trading.quantities))
+	}
+}
+
+func TestTWAPExecutor_RetriesHeldBackSlices(t *testing.T) {
+	trading := &twapTradingService{holdBack: []time.Duration{20 * time.Second, 5 * time.Second}}
+	executor := NewTWAPExecutor(trading, &mockLogger{})
+	clock := &fakeTWAPClock{}
+	executor.after = clock.after
+
+	result, err := executor.ExecuteTWAP("BNBUSDT", api.OrderSideSell, 2, 2, 30*time.Second)
+	if err != nil {
+		t.Fatalf("ExecuteTWAP() error = %v", err)
+	}
+
+	// The first slice waits out both rejections before the regular interval to the second
+	want := []time.Duration{20 * time.Second, 5 * time.Second, 30 * time.Second}
+	if len(clock.waits) != len(want) {
+		t.Fatalf("waits = %v, want %v", clock.waits, want)
+	}
+	for i := range want {
+		if clock.waits[i] != want[i] {
+			t.Errorf("waits = %v, want %v", clock.waits, want)
+			break
+		}
+	}
+	if len(result.Slices) != 2 || math.Abs(result.ExecutedQty-2) > 1e-9 {
+		t.Errorf("result = %+v, want 2 slices filling 2", result)
+	}
+}
@@ -6,11 +6,12 @@ import (
 
 // MarketData represents market data for a symbol
 type MarketData struct {
-	Symbol     string
-	Price      float64
-	Volume     float64
-	Volume24h  float64
-	Timestamp  int64
+	Symbol      string
+	Price       float64
+	Volume      float64
+	Volume24h   float64
+	Timestamp   int64
+	Unconfirmed bool // price deviated sharply and awaits a confirming reading
 }
 
 // TimeWindow represents a time range
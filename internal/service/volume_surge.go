@@ -0,0 +1,66 @@
+package service
+
+import (
+	"binance-trader/internal/repository"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// DefaultVolumeSurgeWindow is the baseline window of volume surge conditions created without one
+	DefaultVolumeSurgeWindow = time.Hour
+
+	// volumeSurgeSample is the recent volume a surge condition compares with its baseline
+	volumeSurgeSample = time.Minute
+
+	// minVolumeSurgeWindow makes the baseline span more than the sample it is compared with
+	minVolumeSurgeWindow = 2 * volumeSurgeSample
+)
+
+// VolumeSurgeMultiplier returns how many times the volume of the last minute exceeds the average
+// minute of a baseline window that traded baselineVolume. It fails without baseline volume,
+// since any trading would then be an infinite surge.
+func VolumeSurgeMultiplier(currentVolume, baselineVolume float64, window time.Duration) (float64, error) {
+	if window < volumeSurgeSample {
+		return 0, fmt.Errorf("volume surge baseline window must be at least %s", volumeSurgeSample)
+	}
+	if currentVolume < 0 || baselineVolume < 0 || math.IsNaN(currentVolume) || math.IsNaN(baselineVolume) {
+		return 0, fmt.Errorf("volumes cannot be negative")
+	}
+	average := baselineVolume / (float64(window) / float64(volumeSurgeSample))
+	if average <= 0 {
+		return 0, fmt.Errorf("no volume traded in the %s baseline window", FormatShortDuration(window))
+	}
+	return currentVolume / average, nil
+}
+
+// validateVolumeSurgeCondition checks the multiplier and baseline window of a volume surge condition
+func validateVolumeSurgeCondition(condition *repository.TriggerCondition) error {
+	if condition.Value <= 0 {
+		return fmt.Errorf("volume surge multiplier must be greater than 0")
+	}
+	if condition.TimeWindow < minVolumeSurgeWindow {
+		return fmt.Errorf("volume surge baseline window must be at least %s", minVolumeSurgeWindow)
+	}
+	if condition.TimeWindow > maxVolumeWindow {
+		return fmt.Errorf("volume surge baseline window cannot exceed %s", maxVolumeWindow)
+	}
+	return nil
+}
+
+// volumeSurgeValue returns the volume multiplier of a symbol: its volume over the last minute
+// against the average minute of the condition's baseline window
+func (me *MonitoringEngine) volumeSurgeValue(symbol string, condition *repository.TriggerCondition) (float64, error) {
+	current, err := me.marketDataService.GetVolume(symbol, volumeSurgeSample)
+	me.recordAPIResult(err)
+	if err != nil {
+		return 0, err
+	}
+	baseline, err := me.marketDataService.GetVolume(symbol, condition.TimeWindow)
+	me.recordAPIResult(err)
+	if err != nil {
+		return 0, err
+	}
+	return VolumeSurgeMultiplier(current, baseline, condition.TimeWindow)
+}
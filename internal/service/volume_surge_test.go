@@ -0,0 +1,231 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+// surgeMarketDataService reports a traded volume per window
+type surgeMarketDataService struct {
+	mockMarketDataService
+	volumes map[time.Duration]float64
+}
+
+func (m *surgeMarketDataService) GetVolume(symbol string, timeWindow time.Duration) (float64, error) {
+	return m.volumes[timeWindow], nil
+}
+
+func TestVolumeSurgeMultiplier(t *testing.T) {
+	// 600 over an hour is an average minute of 10
+	if multiplier, err := VolumeSurgeMultiplier(25, 600, time.Hour); err != nil || multiplier != 2.5 {
+		t.Errorf("VolumeSurgeMultiplier(25, 600, 1h) = %v, %v; want 2.5", multiplier, err)
+	}
+	if multiplier, err := VolumeSurgeMultiplier(0, 600, time.Hour); err != nil || multiplier != 0 {
+		t.Errorf("VolumeSurgeMultiplier(0, 600, 1h) = %v, %v; want 0", multiplier, err)
+	}
+	for _, tt := range []struct {
+		name              string
+		current, baseline float64
+		window            time.Duration
+	}{
+		{"no baseline volume", 25, 0, time.Hour},
+		{"negative volume", -1, 600, time.Hour},
+		{"NaN volume", math.NaN(), 600, time.Hour},
+		{"window shorter than a minute", 25, 600, 30 * time.Second},
+	} {
+		if _, err := VolumeSurgeMultiplier(tt.current, tt.baseline, tt.window); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+// Feature: binance-auto-trading, volume surge trigger
+// For any traded volumes and baseline window, the surge multiplier is positive whenever the last
+// minute traded, never negative, and accepted by the trigger engine
+func TestProperty_VolumeSurgeMultiplierPositive(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("surge multiplier is positive for positive volumes", prop.ForAll(
+		func(current, baseline float64, minutes int) bool {
+			multiplier, err := VolumeSurgeMultiplier(current, baseline, time.Duration(minutes)*time.Minute)
+			if err != nil || multiplier <= 0 || math.IsInf(multiplier, 0) {
+				return false
+			}
+
+			engine := NewTriggerEngine()
+			condition := &TriggerCondition{Type: TriggerTypeVolumeSurge, Operator: OperatorGreaterEqual, Value: 2.5}
+			met, err := engine.EvaluateCondition(condition, multiplier)
+			return err == nil && met == (multiplier >= 2.5)
+		},
+		gen.Float64Range(0.0001, 1000000),
+		gen.Float64Range(0.0001, 1000000),
+		gen.IntRange(2, int(maxVolumeWindow/time.Minute)),
+	))
+
+	properties.Property("surge multiplier is never negative", prop.ForAll(
+		func(current, baseline float64, minutes int) bool {
+			multiplier, err := VolumeSurgeMultiplier(current, baseline, time.Duration(minutes)*time.Minute)
+			if current < 0 || baseline <= 0 {
+				return err != nil
+			}
+			return err == nil && multiplier >= 0
+		},
+		gen.Float64Range(-1000, 1000000),
+		gen.Float64Range(-1000, 1000000),
+		gen.IntRange(1, int(maxVolumeWindow/time.Minute)),
+	))
+
+	properties.TestingRun(t)
+}
+
+func TestTriggerEngine_VolumeSurgeNegative(t *testing.T) {
+	engine := NewTriggerEngine()
+	condition := &TriggerCondition{Type: TriggerTypeVolumeSurge, Operator: OperatorLessThan, Value: 0.5}
+
+	if met, err := engine.EvaluateCondition(condition, 0); err != nil || !met {
+		t.Errorf("EvaluateCondition(0) = %v, %v; want met", met, err)
+	}
+	for _, value := range []float64{-1, math.NaN()} {
+		if _, err := engine.EvaluateCondition(condition, value); err == nil {
+			t.Errorf("expected an error for a surge multiplier of %v", value)
+		}
+	}
+}
+
+func TestMonitoringEngine_VolumeSurgeTrigger(t *testing.T) {
+	// 600 traded over the hour: an average minute of 10
+	market := &surgeMarketDataService{volumes: map[time.Duration]float64{time.Minute: 12, time.Hour: 600}}
+	repo := repository.NewMemoryConditionalOrderRepository()
+	engine := NewMonitoringEngine(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+		&mockTradingService{}, market, &mockStopLossService{}, &mockLogger{}, nil)
+
+	order := &repository.ConditionalOrder{
+		OrderID:  "surge",
+		Symbol:   "BTCUSDT",
+		Side:     api.OrderSideBuy,
+		Type:     api.OrderTypeMarket,
+		Quantity: 0.01,
+		TriggerCondition: &repository.TriggerCondition{
+			Type:       repository.TriggerTypeVolumeSurge,
+			Operator:   repository.OperatorGreaterEqual,
+			Value:      2.5,
+			TimeWindow: time.Hour,
+		},
+		Status:    repository.ConditionalOrderStatusPending,
+		CreatedAt: time.Now().Unix(),
+	}
+	repo.Save(order)
+	status := func() repository.ConditionalOrderStatus {
+		updated, _ := repo.FindByID(order.OrderID)
+		return updated.Status
+	}
+
+	// 1.2x the average minute is ordinary trading
+	engine.processOrder(order)
+	if status() != repository.ConditionalOrderStatusPending {
+		t.Fatalf("order at 1.2x = %s, want pending", status())
+	}
+
+	// Without baseline volume there is nothing to compare with
+	market.volumes = map[time.Duration]float64{time.Minute: 40}
+	engine.processOrder(order)
+	if status() != repository.ConditionalOrderStatusPending {
+		t.Fatalf("order without baseline volume = %s, want pending", status())
+	}
+
+	// 25 in the last minute is 2.5x the average
+	market.volumes = map[time.Duration]float64{time.Minute: 25, time.Hour: 600}
+	engine.processOrder(order)
+	if status() != repository.ConditionalOrderStatusExecuted {
+		t.Errorf("order at 2.5x = %s, want executed", status())
+	}
+}
+
+func TestConditionalOrderService_ValidateVolumeSurgeCondition(t *testing.T) {
+	svc := NewConditionalOrderService(repository.NewMemoryConditionalOrderRepository(), repository.NewMemoryStopOrderRepository(),
+		NewTriggerEngine(), &mockTradingService{}, &mockMarketDataService{}, &mockStopLossService{}, &mockLogger{})
+
+	surge := func(multiplier float64, window time.Duration) *repository.TriggerCondition {
+		return &repository.TriggerCondition{Type: repository.TriggerTypeVolumeSurge, Operator: repository.OperatorGreaterEqual,
+			Value: multiplier, TimeWindow: window}
+	}
+	tests := []struct {
+		name      string
+		condition *repository.TriggerCondition
+		wantErr   bool
+	}{
+		{"valid", surge(2.5, time.Hour), false},
+		{"longest window", surge(2.5, maxVolumeWindow), false},
+		{"zero multiplier", surge(0, time.Hour), true},
+		{"negative multiplier", surge(-2, time.Hour), true},
+		{"window of one minute", surge(2.5, time.Minute), true},
+		{"window too long", surge(2.5, maxVolumeWindow+time.Hour), true},
+		{"inside a composite", &repository.TriggerCondition{
+			CompositeType: repository.LogicAND,
+			SubConditions: []*repository.TriggerCondition{
+				surge(2.5, time.Hour),
+				{Type: repository.TriggerTypePrice, Operator: repository.OperatorGreaterThan, Value: 50000},
+			},
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateConditionalOrder(&repository.ConditionalOrderRequest{
+				Symbol:           "BTCUSDT",
+				Side:             api.OrderSideBuy,
+				Type:             api.OrderTypeMarket,
+				Quantity:         0.01,
+				TriggerCondition: tt.condition,
+				AllowDuplicate:   true,
+			})
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("CreateConditionalOrder() error = %v", err)
+				}
+				return
+			}
+			if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrInvalidTriggerCondition {
+				t.Errorf("CreateConditionalOrder() error = %v, want ErrInvalidTriggerCondition", err)
+			}
+		})
+	}
+}
+
+func TestFuturesMarketDataService_GetVolume(t *testing.T) {
+	now := time.Now().UnixMilli()
+	var requested string
+	var requestedLimit int
+	service := NewFuturesMarketDataService(&mockFuturesClient{
+		klinesFunc: func(symbol string, interval string, limit int) ([]*api.Kline, error) {
+			requested, requestedLimit = interval, limit
+			// The first kline opened before the window and is left out
+			return []*api.Kline{
+				{OpenTime: now - 20*time.Minute.Milliseconds(), Volume: 100},
+				{OpenTime: now - 10*time.Minute.Milliseconds(), Volume: 5},
+				{OpenTime: now - time.Minute.Milliseconds(), Volume: 7},
+			}, nil
+		},
+	}, &mockLogger{})
+
+	volume, err := service.GetVolume("BTCUSDT", 15*time.Minute)
+	if err != nil || volume != 12 {
+		t.Errorf("GetVolume() = %v, %v; want 12", volume, err)
+	}
+	if requested != "1m" || requestedLimit != 15 {
+		t.Errorf("klines requested = %d of %s, want 15 of 1m", requestedLimit, requested)
+	}
+	if _, err := service.GetVolume("BTCUSDT", 0); err == nil {
+		t.Error("expected an error for a zero window")
+	}
+}
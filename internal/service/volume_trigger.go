@@ -0,0 +1,39 @@
+package service
+
+import (
+	"binance-trader/internal/repository"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultVolumeWindow is the window of volume conditions created without one, matching the
+	// 24h volume the condorder command has always compared against
+	DefaultVolumeWindow = 24 * time.Hour
+
+	// maxVolumeWindow keeps the volume kline request within the exchange's limit of 1000
+	maxVolumeWindow = 7 * 24 * time.Hour
+)
+
+// validateVolumeCondition checks the window and threshold of a volume condition
+func validateVolumeCondition(condition *repository.TriggerCondition) error {
+	if condition.TimeWindow <= 0 {
+		return fmt.Errorf("volume window must be greater than 0")
+	}
+	if condition.TimeWindow > maxVolumeWindow {
+		return fmt.Errorf("volume window cannot exceed %s", maxVolumeWindow)
+	}
+	if condition.Value < 0 {
+		return fmt.Errorf("volume threshold cannot be negative")
+	}
+	return nil
+}
+
+// volumeValue returns the base asset volume traded in a symbol over the window of a condition.
+// The market data service caches it briefly, so orders on the same symbol and window share a
+// kline request per tick.
+func (me *MonitoringEngine) volumeValue(symbol string, condition *repository.TriggerCondition) (float64, error) {
+	volume, err := me.marketDataService.GetVolume(symbol, condition.TimeWindow)
+	me.recordAPIResult(err)
+	return volume, err
+}
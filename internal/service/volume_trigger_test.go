@@ -0,0 +1,200 @@
+package service
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/repository"
+	"binance-trader/pkg/errors"
+	stderrors "errors"
+	"testing"
+	"time"
+)
+
+// volumeMarketDataService reports a settable traded volume and records the windows asked for
+type volumeMarketDataService struct {
+	mockMarketDataService
+	volume  float64
+	err     error
+	windows []time.Duration
+}
+
+func (m *volumeMarketDataService) GetVolume(symbol string, timeWindow time.Duration) (float64, error) {
+	m.windows = append(m.windows, timeWindow)
+	return m.volume, m.err
+}
+
+func newVolumeOrder(id string, operator repository.ComparisonOperator, threshold float64, window time.Duration) *repository.ConditionalOrder {
+	return &repository.ConditionalOrder{
+		OrderID:  id,
+		Symbol:   "BTCUSDT",
+		Side:     api.OrderSideBuy,
+		Type:     api.OrderTypeMarket,
+		Quantity: 0.01,
+		TriggerCondition: &repository.TriggerCondition{
+			Type:       repository.TriggerTypeVolume,
+			Operator:   operator,
+			Value:      threshold,
+			TimeWindow: window,
+		},
+		Status:    repository.ConditionalOrderStatusPending,
+		CreatedAt: time.Now().Unix(),
+	}
+}
+
+func TestMonitoringEngine_VolumeTrigger(t *testing.T) {
+	market := &volumeMarketDataService{volume: 800}
+	repo := repository.NewMemoryConditionalOrderRepository()
+	engine := NewMonitoringEngine(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+		&mockTradingService{}, market, &mockStopLossService{}, &mockLogger{}, nil)
+
+	spike := newVolumeOrder("spike", repository.OperatorGreaterThan, 5000, 15*time.Minute)
+	quiet := newVolumeOrder("quiet", repository.OperatorLessThan, 100, time.Hour)
+	repo.Save(spike)
+	repo.Save(quiet)
+
+	status := func(order *repository.ConditionalOrder) repository.ConditionalOrderStatus {
+		updated, _ := repo.FindByID(order.OrderID)
+		return updated.Status
+	}
+
+	// Normal trading: neither the spike nor the lull threshold is crossed
+	engine.processOrder(spike)
+	engine.processOrder(quiet)
+	if status(spike) != repository.ConditionalOrderStatusPending || status(quiet) != repository.ConditionalOrderStatusPending {
+		t.Fatalf("at volume 800: spike %s, quiet %s, want both pending", status(spike), status(quiet))
+	}
+	if len(market.windows) != 2 || market.windows[0] != 15*time.Minute || market.windows[1] != time.Hour {
+		t.Errorf("volume windows asked for = %v, want each order's own window", market.windows)
+	}
+
+	// A spike above the threshold fires the spike order only
+	market.volume = 12000
+	engine.processOrder(spike)
+	engine.processOrder(quiet)
+	if status(spike) != repository.ConditionalOrderStatusExecuted {
+		t.Errorf("spike order at volume 12000 = %s, want executed", status(spike))
+	}
+	if status(quiet) != repository.ConditionalOrderStatusPending {
+		t.Errorf("quiet order at volume 12000 = %s, want pending", status(quiet))
+	}
+
+	// A quiet period fires the order waiting for one
+	market.volume = 40
+	engine.processOrder(quiet)
+	if status(quiet) != repository.ConditionalOrderStatusExecuted {
+		t.Errorf("quiet order at volume 40 = %s, want executed", status(quiet))
+	}
+}
+
+func TestMonitoringEngine_VolumeAndPriceComposite(t *testing.T) {
+	market := &volumeMarketDataService{mockMarketDataService: mockMarketDataService{prices: map[string]float64{"BTCUSDT": 50000}}}
+	repo := repository.NewMemoryConditionalOrderRepository()
+	engine := NewMonitoringEngine(repo, repository.NewMemoryStopOrderRepository(), NewTriggerEngine(),
+		&mockTradingService{}, market, &mockStopLossService{}, &mockLogger{}, nil)
+	// Each tick is a second apart so the price is fetched afresh
+	current := time.Unix(1700000000, 0)
+	engine.now = func() time.Time {
+		current = current.Add(time.Second)
+		return current
+	}
+
+	order := newVolumeOrder("breakout", repository.OperatorGreaterThan, 5000, 15*time.Minute)
+	order.TriggerCondition = &repository.TriggerCondition{
+		CompositeType: repository.LogicAND,
+		SubConditions: []*repository.TriggerCondition{
+			{Type: repository.TriggerTypePrice, Operator: repository.OperatorGreaterThan, Value: 51000},
+			order.TriggerCondition,
+		},
+	}
+	repo.Save(order)
+	status := func() repository.ConditionalOrderStatus {
+		updated, _ := repo.FindByID(order.OrderID)
+		return updated.Status
+	}
+
+	// The volume leaf compares the traded volume, not the price of 50000
+	engine.processOrder(order)
+	if status() != repository.ConditionalOrderStatusPending {
+		t.Fatalf("order with neither met = %s, want pending", status())
+	}
+	if len(market.windows) != 1 || market.windows[0] != 15*time.Minute {
+		t.Errorf("volume windows asked for = %v, want the volume leaf's window", market.windows)
+	}
+
+	// Only the volume is met
+	market.volume = 12000
+	engine.processOrder(order)
+	if status() != repository.ConditionalOrderStatusPending {
+		t.Fatalf("order with only the volume met = %s, want pending", status())
+	}
+
+	// Only the price is met
+	market.volume = 800
+	market.prices["BTCUSDT"] = 52000
+	engine.processOrder(order)
+	if status() != repository.ConditionalOrderStatusPending {
+		t.Fatalf("order with only the price met = %s, want pending", status())
+	}
+
+	// Without the volume the order waits for the next tick
+	market.volume, market.err = 12000, stderrors.New("klines unavailable")
+	engine.processOrder(order)
+	if status() != repository.ConditionalOrderStatusPending {
+		t.Fatalf("order without the volume = %s, want pending", status())
+	}
+
+	market.err = nil
+	engine.processOrder(order)
+	if status() != repository.ConditionalOrderStatusExecuted {
+		t.Errorf("order with both met = %s, want executed", status())
+	}
+}
+
+func TestConditionalOrderService_ValidateVolumeCondition(t *testing.T) {
+	svc := NewConditionalOrderService(repository.NewMemoryConditionalOrderRepository(), repository.NewMemoryStopOrderRepository(),
+		NewTriggerEngine(), &mockTradingService{}, &mockMarketDataService{}, &mockStopLossService{}, &mockLogger{})
+
+	volume := func(threshold float64, window time.Duration) *repository.TriggerCondition {
+		return &repository.TriggerCondition{Type: repository.TriggerTypeVolume, Operator: repository.OperatorGreaterThan,
+			Value: threshold, TimeWindow: window}
+	}
+	tests := []struct {
+		name      string
+		condition *repository.TriggerCondition
+		wantErr   bool
+	}{
+		{"valid", volume(20000, time.Hour), false},
+		{"longest window", volume(20000, maxVolumeWindow), false},
+		{"zero window", volume(20000, 0), true},
+		{"negative window", volume(20000, -time.Minute), true},
+		{"window too long", volume(20000, maxVolumeWindow+time.Hour), true},
+		{"negative threshold", volume(-1, time.Hour), true},
+		{"inside a composite", &repository.TriggerCondition{
+			CompositeType: repository.LogicAND,
+			SubConditions: []*repository.TriggerCondition{
+				volume(20000, 0),
+				{Type: repository.TriggerTypePrice, Operator: repository.OperatorGreaterThan, Value: 50000},
+			},
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateConditionalOrder(&repository.ConditionalOrderRequest{
+				Symbol:           "BTCUSDT",
+				Side:             api.OrderSideBuy,
+				Type:             api.OrderTypeMarket,
+				Quantity:         0.01,
+				TriggerCondition: tt.condition,
+				AllowDuplicate:   true,
+			})
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("CreateConditionalOrder() error = %v", err)
+				}
+				return
+			}
+			if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrInvalidTriggerCondition {
+				t.Errorf("CreateConditionalOrder() error = %v, want ErrInvalidTriggerCondition", err)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // ErrorType represents the type of error
 type ErrorType int
@@ -27,6 +30,14 @@ const (
 	ErrMaxPositionExceeded
 	ErrReduceOnlyViolation
 	ErrPositionNotFound
+	// Writes to the exchange are disabled by safe mode
+	ErrSafeMode
+	// An active conditional order already does the same
+	ErrDuplicateConditionalOrder
+	// Temporary guards: the same order may pass once they clear
+	ErrSymbolPaused       // New orders for the symbol are paused after repeated failures
+	ErrNotionalCapReached // The per-minute notional cap has no room left
+	ErrDailyLimitReached  // The daily order count is used up
 )
 
 // TradingError represents a trading system error
@@ -35,6 +46,9 @@ type TradingError struct {
 	Message string
 	Code    int
 	Cause   error
+
+	// RetryAfter is how long until a temporary limit lets the request through, 0 = not known
+	RetryAfter time.Duration
 }
 
 // Error implements the error interface
@@ -59,3 +73,27 @@ func NewTradingError(errType ErrorType, message string, code int, cause error) *
 		Cause:   cause,
 	}
 }
+
+// IsTemporary reports whether err comes from a guard that clears by itself, so the order it
+// held back may be retried later instead of being dropped
+func IsTemporary(err error) bool {
+	tradingErr, ok := err.(*TradingError)
+	if !ok {
+		return false
+	}
+	switch tradingErr.Type {
+	case ErrSymbolPaused, ErrNotionalCapReached, ErrDailyLimitReached:
+		return true
+	default:
+		return false
+	}
+}
+
+// RetryAfter returns how long the limit that rejected err asks the caller to wait before retrying
+func RetryAfter(err error) (time.Duration, bool) {
+	tradingErr, ok := err.(*TradingError)
+	if !ok || tradingErr.RetryAfter <= 0 {
+		return 0, false
+	}
+	return tradingErr.RetryAfter, true
+}
@@ -3,6 +3,7 @@ package errors
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
@@ -44,6 +45,21 @@ func TestTradingErrorError(t *testing.T) {
 	}
 }
 
+func TestRetryAfter(t *testing.T) {
+	limited := NewTradingError(ErrRiskLimitExceeded, "limit reached", 0, nil)
+	if _, ok := RetryAfter(limited); ok {
+		t.Error("Expected no retry for an error without RetryAfter")
+	}
+
+	limited.RetryAfter = 30 * time.Second
+	if wait, ok := RetryAfter(limited); !ok || wait != 30*time.Second {
+		t.Errorf("Expected a retry after 30s, got %v (%v)", wait, ok)
+	}
+	if _, ok := RetryAfter(errors.New("plain error")); ok {
+		t.Error("Expected no retry for a plain error")
+	}
+}
+
 // Property-based test to verify gopter is working
 // Feature: binance-auto-trading, Property 0: Error message consistency
 func TestTradingErrorProperty(t *testing.T) {
@@ -0,0 +1,145 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultQueueSize is the number of entries buffered for the file writer
+	DefaultQueueSize = 4096
+
+	// dropReportInterval is how often dropped debug entries are reported
+	dropReportInterval = time.Minute
+)
+
+// queuedEntry is a formatted entry, or a flush request when done is set
+type queuedEntry struct {
+	data []byte
+	done chan struct{}
+}
+
+// asyncWriter hands formatted entries to a dedicated goroutine so that a slow disk or a log
+// rotation doesn't stall the caller. When the queue is full, debug entries are dropped and
+// counted; entries of other levels wait for room, so nothing trading-relevant is lost.
+type asyncWriter struct {
+	write  func(data []byte) // Writes one entry to the destination, called from one goroutine at a time
+	report func(dropped uint64)
+
+	mu       sync.RWMutex // Held for reading while enqueueing, for writing while closing
+	closed   bool
+	queue    chan queuedEntry
+	stopped  chan struct{}
+	dropped  uint64 // Total debug entries dropped
+	reported uint64 // Drops already reported
+}
+
+// newAsyncWriter starts the writer goroutine; report receives the number of entries dropped
+// since the previous report
+func newAsyncWriter(queueSize int, write func(data []byte), report func(dropped uint64)) *asyncWriter {
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	w := &asyncWriter{
+		write:   write,
+		report:  report,
+		queue:   make(chan queuedEntry, queueSize),
+		stopped: make(chan struct{}),
+	}
+	go w.run(dropReportInterval)
+	return w
+}
+
+// run writes queued entries until the queue is closed
+func (w *asyncWriter) run(reportInterval time.Duration) {
+	defer close(w.stopped)
+
+	ticker := time.NewTicker(reportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case entry, ok := <-w.queue:
+			if !ok {
+				w.reportDrops()
+				return
+			}
+			if entry.done != nil {
+				close(entry.done)
+				continue
+			}
+			w.write(entry.data)
+		case <-ticker.C:
+			w.reportDrops()
+		}
+	}
+}
+
+// reportDrops reports the entries dropped since the last report
+func (w *asyncWriter) reportDrops() {
+	dropped := atomic.LoadUint64(&w.dropped)
+	if dropped == w.reported {
+		return
+	}
+	if w.report != nil {
+		w.report(dropped - w.reported)
+	}
+	w.reported = dropped
+}
+
+// Write queues an entry. Debug entries are dropped when the queue is full. After Close,
+// entries are written synchronously.
+func (w *asyncWriter) Write(level logrus.Level, data []byte) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		w.write(data)
+		return
+	}
+
+	entry := queuedEntry{data: data}
+	if level >= logrus.DebugLevel {
+		select {
+		case w.queue <- entry:
+		default:
+			atomic.AddUint64(&w.dropped, 1)
+		}
+		return
+	}
+	w.queue <- entry
+}
+
+// Flush blocks until every entry queued before the call has been written
+func (w *asyncWriter) Flush() {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return
+	}
+	done := make(chan struct{})
+	w.queue <- queuedEntry{done: done}
+	<-done
+}
+
+// Dropped returns the number of debug entries dropped so far
+func (w *asyncWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+// Close writes the queued entries and stops the goroutine
+func (w *asyncWriter) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return
+	}
+	w.closed = true
+	close(w.queue)
+	<-w.stopped
+}
@@ -0,0 +1,183 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// slowSink records entries and blocks the writer goroutine until released
+type slowSink struct {
+	mu      sync.Mutex
+	entries []string
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func newSlowSink() *slowSink {
+	return &slowSink{started: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (s *slowSink) write(data []byte) {
+	s.once.Do(func() { close(s.started) })
+	<-s.release
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, string(data))
+}
+
+func (s *slowSink) written() map[string]bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	written := make(map[string]bool, len(s.entries))
+	for _, entry := range s.entries {
+		written[entry] = true
+	}
+	return written
+}
+
+func TestAsyncWriterDropsOnlyDebugUnderPressure(t *testing.T) {
+	sink := newSlowSink()
+	var reported uint64
+	w := newAsyncWriter(4, sink.write, func(dropped uint64) { reported += dropped })
+
+	// The goroutine takes the first entry and stalls on it, then the queue fills up
+	w.Write(logrus.InfoLevel, []byte("info-0"))
+	<-sink.started
+	for i := 1; i <= 4; i++ {
+		w.Write(logrus.InfoLevel, []byte(fmt.Sprintf("info-%d", i)))
+	}
+	for i := 0; i < 10; i++ {
+		w.Write(logrus.DebugLevel, []byte(fmt.Sprintf("debug-%d", i)))
+	}
+	if dropped := w.Dropped(); dropped != 10 {
+		t.Errorf("Dropped() = %d, want 10", dropped)
+	}
+
+	// Entries above debug wait for room instead of being dropped
+	var wg sync.WaitGroup
+	for _, level := range []logrus.Level{logrus.WarnLevel, logrus.ErrorLevel} {
+		wg.Add(1)
+		go func(level logrus.Level) {
+			defer wg.Done()
+			w.Write(level, []byte(level.String()+"-entry"))
+		}(level)
+	}
+
+	close(sink.release)
+	wg.Wait()
+	w.Close()
+
+	written := sink.written()
+	for _, want := range []string{"info-0", "info-1", "info-2", "info-3", "info-4", "warning-entry", "error-entry"} {
+		if !written[want] {
+			t.Errorf("entry %q was lost", want)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		if written[fmt.Sprintf("debug-%d", i)] {
+			t.Errorf("debug-%d should have been dropped", i)
+		}
+	}
+	if reported != 10 {
+		t.Errorf("reported %d drops, want 10", reported)
+	}
+
+	// Entries after Close are written synchronously
+	w.Write(logrus.DebugLevel, []byte("late"))
+	if !sink.written()["late"] {
+		t.Error("entry written after Close was lost")
+	}
+}
+
+// readLogLines returns the JSON entries of a log file
+func readLogLines(t *testing.T, path string) []map[string]interface{} {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open log file: %v", err)
+	}
+	defer file.Close()
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("failed to parse log line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestLoggerFlushesOnClose(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "test.log")
+	log, err := NewLogger(Config{Level: "debug", FilePath: logFile, MaxSizeMB: 10, MaxBackups: 1, QueueSize: 8})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	for i := 0; i < 500; i++ {
+		log.Info("Order event", map[string]interface{}{"n": i})
+	}
+	l := log.(*logrusLogger)
+	l.async.Flush()
+	l.reportDrops(3)
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := log.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+	// Entries after Close no longer reach the file and must not panic
+	log.Info("after close", nil)
+
+	lines := readLogLines(t, logFile)
+	if len(lines) != 501 {
+		t.Fatalf("log file has %d entries, want 501", len(lines))
+	}
+	for i, line := range lines[:500] {
+		if line["n"] != float64(i) {
+			t.Fatalf("entry %d = %v, want entries in order", i, line)
+		}
+	}
+	if report := lines[500]; report["level"] != "warning" || report["dropped"] != float64(3) {
+		t.Errorf("drop report = %v", report)
+	}
+}
+
+func TestLoggerFlushesOnFatal(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "test.log")
+	log, err := NewLogger(Config{Level: "info", FilePath: logFile, MaxSizeMB: 10, MaxBackups: 1})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	l := log.(*logrusLogger)
+	defer l.Close()
+
+	exitCode := -1
+	l.logger.ExitFunc = func(code int) { exitCode = code }
+
+	for i := 0; i < 100; i++ {
+		l.Error("Order failed", map[string]interface{}{"n": i})
+	}
+	l.Fatal("Application error", nil)
+
+	if exitCode != 1 {
+		t.Errorf("exit code = %d, want 1", exitCode)
+	}
+	// Everything logged before Fatal is on disk without calling Close
+	lines := readLogLines(t, logFile)
+	if len(lines) != 101 || lines[100]["message"] != "Application error" {
+		t.Errorf("log file has %d entries, want 100 errors and the fatal entry last", len(lines))
+	}
+}
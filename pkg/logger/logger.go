@@ -34,6 +34,9 @@ type Logger interface {
 	
 	// Set trading type for log entries
 	SetTradingType(tradingType string)
+	
+	// Close writes buffered entries and closes the log file; later entries go to the console only
+	Close() error
 }
 
 // Config holds logger configuration
@@ -44,8 +47,13 @@ type Config struct {
 	MaxBackups    int    // max number of backup files
 	EnableConsole bool   // also log to console
 	TradingType   string // trading type marker (spot, futures)
+	QueueSize     int    // entries buffered for the file writer, 0 uses DefaultQueueSize
+	Journal       bool   // write single-line text to stderr for journald instead of the file and console
 }
 
+// journalOutput receives the entries of journal loggers
+var journalOutput io.Writer = os.Stderr
+
 // logrusLogger implements Logger interface using logrus
 type logrusLogger struct {
 	logger      *logrus.Logger
@@ -53,7 +61,9 @@ type logrusLogger struct {
 	mu          sync.Mutex
 	currentSize int64
 	fileHandle  *os.File
+	console     io.Writer // also receives file entries when console output is enabled
 	tradingType string
+	async       *asyncWriter // nil without a log file
 }
 
 // sensitivePatterns are regex patterns for sensitive information
@@ -91,25 +101,46 @@ func NewLogger(config Config) (Logger, error) {
 		tradingType: config.TradingType,
 	}
 	
+	// journald stamps every line itself and keeps the log, so no timestamp, colors or file
+	if config.Journal {
+		log.SetFormatter(&logrus.TextFormatter{
+			DisableColors:    true,
+			DisableTimestamp: true,
+			FieldMap: logrus.FieldMap{
+				logrus.FieldKeyMsg: "message",
+			},
+		})
+		log.SetOutput(journalOutput)
+		return logger, nil
+	}
+	
 	// Setup output
 	if config.FilePath != "" {
 		if err := logger.setupFileOutput(); err != nil {
 			return nil, err
 		}
-	}
-	
-	if config.EnableConsole {
-		if config.FilePath != "" {
-			// Write to both file and console
-			logger.logger.SetOutput(io.MultiWriter(logger.fileHandle, os.Stdout))
-		} else {
-			logger.logger.SetOutput(os.Stdout)
+		if config.EnableConsole {
+			logger.console = os.Stdout
 		}
+		// File writes and rotation run on a background goroutine so callers never wait on the disk
+		logger.async = newAsyncWriter(config.QueueSize, logger.writeFile, logger.reportDrops)
+	} else if config.EnableConsole {
+		logger.logger.SetOutput(os.Stdout)
 	}
 	
 	return logger, nil
 }
 
+// SetLevel changes the level of a running logger, e.g. after the config is reloaded
+func (l *logrusLogger) SetLevel(level string) error {
+	parsed, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.logger.SetLevel(parsed)
+	return nil
+}
+
 // setupFileOutput initializes file output with rotation support
 func (l *logrusLogger) setupFileOutput() error {
 	l.mu.Lock()
@@ -186,6 +217,60 @@ func (l *logrusLogger) checkRotation() error {
 	return nil
 }
 
+// writeFile writes one formatted entry to the log file, rotating it first when it is full
+func (l *logrusLogger) writeFile(data []byte) {
+	if err := l.checkRotation(); err != nil {
+		fmt.Fprintf(os.Stderr, "log rotation failed: %v\n", err)
+	}
+	
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	
+	if l.fileHandle != nil {
+		if _, err := l.fileHandle.Write(data); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write log entry: %v\n", err)
+		}
+		l.currentSize += int64(len(data))
+	}
+	if l.console != nil {
+		l.console.Write(data)
+	}
+}
+
+// reportDrops logs how many debug entries were dropped while the file writer was behind
+func (l *logrusLogger) reportDrops(dropped uint64) {
+	entry := l.logger.WithFields(logrus.Fields{"dropped": dropped})
+	if data, err := l.format(entry, logrus.WarnLevel, "Dropped debug log entries while the log writer was behind"); err == nil {
+		l.writeFile(data)
+	}
+}
+
+// format renders an entry with the configured formatter
+func (l *logrusLogger) format(entry *logrus.Entry, level logrus.Level, msg string) ([]byte, error) {
+	entry.Time = time.Now()
+	entry.Level = level
+	entry.Message = msg
+	return l.logger.Formatter.Format(entry)
+}
+
+// Close writes the queued entries and closes the log file
+func (l *logrusLogger) Close() error {
+	if l.async == nil {
+		return nil
+	}
+	l.async.Close()
+	
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	
+	if l.fileHandle == nil {
+		return nil
+	}
+	err := l.fileHandle.Close()
+	l.fileHandle = nil
+	return err
+}
+
 // maskSensitiveInfo masks sensitive information in strings
 func maskSensitiveInfo(s string) string {
 	result := s
@@ -246,8 +331,9 @@ func maskSensitiveFields(fields map[string]interface{}) map[string]interface{} {
 
 // log is the internal logging method
 func (l *logrusLogger) log(level logrus.Level, msg string, fields map[string]interface{}) {
-	// Check rotation before logging
-	l.checkRotation()
+	if !l.logger.IsLevelEnabled(level) {
+		return
+	}
 	
 	// Mask sensitive information
 	maskedMsg := maskSensitiveInfo(msg)
@@ -264,10 +350,22 @@ func (l *logrusLogger) log(level logrus.Level, msg string, fields map[string]int
 	// Create entry with fields
 	entry := l.logger.WithFields(logrus.Fields(maskedFields))
 	
-	// Update size estimate
-	l.mu.Lock()
-	l.currentSize += int64(len(maskedMsg) + 100) // Rough estimate
-	l.mu.Unlock()
+	if l.async != nil {
+		data, err := l.format(entry, level, maskedMsg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to format log entry: %v\n", err)
+			return
+		}
+		if level == logrus.FatalLevel {
+			// The process exits next, so everything queued has to reach the file first
+			l.async.Write(level, data)
+			l.async.Flush()
+			l.logger.Exit(1)
+			return
+		}
+		l.async.Write(level, data)
+		return
+	}
 	
 	// Log at appropriate level
 	switch level {
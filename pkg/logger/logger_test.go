@@ -1,9 +1,11 @@
 package logger
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/leanovate/gopter"
@@ -40,10 +42,8 @@ func TestAPIOperationLogCompleteness(t *testing.T) {
 			// Log API operation
 			logger.LogAPIOperation(operationType, result, nil)
 
-			// Close file handle before reading
-			if l, ok := logger.(*logrusLogger); ok && l.fileHandle != nil {
-				l.fileHandle.Close()
-			}
+			// Flush and close the log file before reading
+			logger.Close()
 
 			// Read log file
 			content, err := os.ReadFile(logFile)
@@ -111,10 +111,8 @@ func TestOrderEventLogCompleteness(t *testing.T) {
 			// Log order event
 			logger.LogOrderEvent("created", orderID, symbol, side, orderType, quantity, nil)
 
-			// Close file handle before reading
-			if l, ok := logger.(*logrusLogger); ok && l.fileHandle != nil {
-				l.fileHandle.Close()
-			}
+			// Flush and close the log file before reading
+			logger.Close()
 
 			// Read log file
 			content, err := os.ReadFile(logFile)
@@ -186,10 +184,8 @@ func TestErrorLogCompleteness(t *testing.T) {
 			// Log error
 			loggerImpl.LogError(testErr, context)
 
-			// Close file handle before reading
-			if l, ok := loggerImpl.(*logrusLogger); ok && l.fileHandle != nil {
-				l.fileHandle.Close()
-			}
+			// Flush and close the log file before reading
+			loggerImpl.Close()
 
 			// Read log file
 			content, err := os.ReadFile(logFile)
@@ -283,13 +279,12 @@ func TestLogRotation(t *testing.T) {
 			// Log large message
 			l.Info(string(largeMessage), nil)
 
-			// Force rotation check
+			// Force rotation check once the entry is written
+			l.async.Flush()
 			l.checkRotation()
 
-			// Close file handle
-			if l.fileHandle != nil {
-				l.fileHandle.Close()
-			}
+			// Flush and close the log file
+			l.Close()
 
 			// Check if backup file was created
 			backupFile := logFile + ".1"
@@ -357,10 +352,8 @@ func TestSensitiveInfoMasking(t *testing.T) {
 			}
 			loggerImpl.Info("Test message", fields)
 
-			// Close file handle before reading
-			if l, ok := loggerImpl.(*logrusLogger); ok && l.fileHandle != nil {
-				l.fileHandle.Close()
-			}
+			// Flush and close the log file before reading
+			loggerImpl.Close()
 
 			// Read log file
 			content, err := os.ReadFile(logFile)
@@ -470,10 +463,8 @@ func TestLoggerCreation(t *testing.T) {
 		t.Fatal("Logger is nil")
 	}
 
-	// Close file handle
-	if l, ok := logger.(*logrusLogger); ok && l.fileHandle != nil {
-		l.fileHandle.Close()
-	}
+	// Flush and close the log file
+	logger.Close()
 }
 
 func TestLogFormatting(t *testing.T) {
@@ -497,10 +488,8 @@ func TestLogFormatting(t *testing.T) {
 		"key2": 123,
 	})
 
-	// Close file handle
-	if l, ok := logger.(*logrusLogger); ok && l.fileHandle != nil {
-		l.fileHandle.Close()
-	}
+	// Flush and close the log file
+	logger.Close()
 
 	// Read and verify log format
 	content, err := os.ReadFile(logFile)
@@ -567,10 +556,8 @@ func TestSensitiveInfoMaskingUnit(t *testing.T) {
 				"api_key": tt.input,
 			})
 
-			// Close file handle
-			if l, ok := logger.(*logrusLogger); ok && l.fileHandle != nil {
-				l.fileHandle.Close()
-			}
+			// Flush and close the log file
+			logger.Close()
 
 			// Read and verify
 			content, err := os.ReadFile(logFile)
@@ -627,12 +614,11 @@ func TestLogRotationUnit(t *testing.T) {
 	}
 
 	l.Info(string(largeMsg), nil)
+	l.async.Flush()
 	l.checkRotation()
 
-	// Close file handle
-	if l.fileHandle != nil {
-		l.fileHandle.Close()
-	}
+	// Flush and close the log file
+	l.Close()
 
 	// Check that backup file exists or current file exists
 	_, err1 := os.Stat(logFile + ".1")
@@ -674,10 +660,8 @@ func TestLogLevels(t *testing.T) {
 				logger.Error("Error message", nil)
 			}
 
-			// Close file handle
-			if l, ok := logger.(*logrusLogger); ok && l.fileHandle != nil {
-				l.fileHandle.Close()
-			}
+			// Flush and close the log file
+			logger.Close()
 
 			// Verify log was written
 			content, err := os.ReadFile(logFile)
@@ -721,10 +705,8 @@ func TestFuturesAPIOperationLogCompleteness(t *testing.T) {
 			// Log futures API operation
 			logger.LogFuturesAPIOperation(operationType, result, nil)
 
-			// Close file handle before reading
-			if l, ok := logger.(*logrusLogger); ok && l.fileHandle != nil {
-				l.fileHandle.Close()
-			}
+			// Flush and close the log file before reading
+			logger.Close()
 
 			// Read log file
 			content, err := os.ReadFile(logFile)
@@ -798,10 +780,8 @@ func TestFuturesOrderEventLogCompleteness(t *testing.T) {
 			// Log futures order event
 			logger.LogFuturesOrderEvent("created", orderID, symbol, side, orderType, quantity, positionChange, nil)
 
-			// Close file handle before reading
-			if l, ok := logger.(*logrusLogger); ok && l.fileHandle != nil {
-				l.fileHandle.Close()
-			}
+			// Flush and close the log file before reading
+			logger.Close()
 
 			// Read log file
 			content, err := os.ReadFile(logFile)
@@ -868,10 +848,8 @@ func TestLiquidationEventLogCompleteness(t *testing.T) {
 			// Log liquidation event
 			logger.LogLiquidationEvent(symbol, positionSide, liquidationPrice, lossAmount, reason, nil)
 
-			// Close file handle before reading
-			if l, ok := logger.(*logrusLogger); ok && l.fileHandle != nil {
-				l.fileHandle.Close()
-			}
+			// Flush and close the log file before reading
+			logger.Close()
 
 			// Read log file
 			content, err := os.ReadFile(logFile)
@@ -937,10 +915,8 @@ func TestFundingRateSettlementLogCompleteness(t *testing.T) {
 			// Log funding rate settlement
 			logger.LogFundingRateSettlement(symbol, fundingFee, fundingRate, positionSize, nil)
 
-			// Close file handle before reading
-			if l, ok := logger.(*logrusLogger); ok && l.fileHandle != nil {
-				l.fileHandle.Close()
-			}
+			// Flush and close the log file before reading
+			logger.Close()
 
 			// Read log file
 			content, err := os.ReadFile(logFile)
@@ -1014,10 +990,8 @@ func TestFuturesSensitiveInfoMasking(t *testing.T) {
 			}
 			logger.LogFuturesAPIOperation("authenticate", "success", fields)
 
-			// Close file handle before reading
-			if l, ok := logger.(*logrusLogger); ok && l.fileHandle != nil {
-				l.fileHandle.Close()
-			}
+			// Flush and close the log file before reading
+			logger.Close()
 
 			// Read log file
 			content, err := os.ReadFile(logFile)
@@ -1114,10 +1088,8 @@ func TestLogTradingTypeMarker(t *testing.T) {
 			// Log futures API operation
 			logger.LogFuturesAPIOperation(operationType, "success", nil)
 
-			// Close file handle before reading
-			if l, ok := logger.(*logrusLogger); ok && l.fileHandle != nil {
-				l.fileHandle.Close()
-			}
+			// Flush and close the log file before reading
+			logger.Close()
 
 			// Read log file
 			content, err := os.ReadFile(logFile)
@@ -1157,3 +1129,44 @@ func TestLogTradingTypeMarker(t *testing.T) {
 
 	properties.TestingRun(t)
 }
+
+func TestJournalLogger(t *testing.T) {
+	var buf bytes.Buffer
+	journalOutput = &buf
+	defer func() { journalOutput = os.Stderr }()
+
+	logFile := filepath.Join(t.TempDir(), "test.log")
+	log, err := NewLogger(Config{Level: "info", FilePath: logFile, EnableConsole: true, TradingType: "futures", Journal: true})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer log.Close()
+
+	log.Info("Order placed\nsecond line", map[string]interface{}{"symbol": "BTCUSDT", "api_key": "abcdefgh12345678"})
+	log.Debug("Not logged at info", nil)
+
+	output := buf.String()
+	if strings.Count(output, "\n") != 1 {
+		t.Fatalf("expected one line, got %q", output)
+	}
+	for _, want := range []string{"level=info", `message="Order placed\nsecond line"`, "symbol=BTCUSDT", "trading_type=futures"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("journal line %q does not contain %s", output, want)
+		}
+	}
+	if strings.Contains(output, "abcdefgh12345678") || strings.Contains(output, "\x1b[") || strings.Contains(output, "time=") {
+		t.Errorf("journal line %q has a secret, colors or a timestamp", output)
+	}
+	if _, err := os.Stat(logFile); !os.IsNotExist(err) {
+		t.Errorf("journal logger created the log file: %v", err)
+	}
+
+	// The level follows a reloaded config
+	if err := log.(*logrusLogger).SetLevel("debug"); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	log.Debug("Logged at debug", nil)
+	if !strings.Contains(buf.String(), "Logged at debug") {
+		t.Error("debug entry missing after SetLevel(debug)")
+	}
+}
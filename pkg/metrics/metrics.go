@@ -0,0 +1,70 @@
+// Package metrics defines the metrics the services record. Services are handed a Sink: one
+// backed by a registry when metrics are served, or Noop() when they are not.
+package metrics
+
+// Counter is a monotonically increasing value per label set
+type Counter interface {
+	Inc(labelValues ...string)
+}
+
+// Gauge is a value per label set that can go up and down
+type Gauge interface {
+	Set(value float64, labelValues ...string)
+}
+
+// Histogram counts observations in buckets per label set
+type Histogram interface {
+	Observe(value float64, labelValues ...string)
+}
+
+// Sink holds every metric the application records
+type Sink struct {
+	// APIRequests counts exchange REST requests by endpoint and HTTP status, or "error"
+	// when no response was received
+	APIRequests Counter
+	// APIRequestDuration observes the latency of exchange REST requests by endpoint
+	APIRequestDuration Histogram
+	// APIRetries counts exchange REST requests retried after a retryable error, by endpoint
+	APIRetries Counter
+	// RateLimitRejections counts requests the exchange refused for the rate limit, by status
+	RateLimitRejections Counter
+	// RateLimitUsedWeight is the request weight the exchange last reported as used this minute
+	RateLimitUsedWeight Gauge
+
+	// Orders counts spot orders sent to the exchange by side and resulting status
+	Orders Counter
+	// ConditionalOrdersTriggered counts conditional orders whose trigger was met, by symbol
+	ConditionalOrdersTriggered Counter
+	// StopLossTriggered counts triggered stop orders by symbol and type
+	StopLossTriggered Counter
+	// ActiveConditionalOrders is the number of conditional orders being monitored
+	ActiveConditionalOrders Gauge
+	// ActiveStopOrders is the number of active stop loss and take profit orders
+	ActiveStopOrders Gauge
+	// MonitoringCycleDuration observes how long each monitoring cycle takes
+	MonitoringCycleDuration Histogram
+}
+
+// noop discards every value it is given
+type noop struct{}
+
+func (noop) Inc(labelValues ...string)                    {}
+func (noop) Set(value float64, labelValues ...string)     {}
+func (noop) Observe(value float64, labelValues ...string) {}
+
+// Noop returns a sink that records nothing, the default when metrics are disabled
+func Noop() *Sink {
+	return &Sink{
+		APIRequests:                noop{},
+		APIRequestDuration:         noop{},
+		APIRetries:                 noop{},
+		RateLimitRejections:        noop{},
+		RateLimitUsedWeight:        noop{},
+		Orders:                     noop{},
+		ConditionalOrdersTriggered: noop{},
+		StopLossTriggered:          noop{},
+		ActiveConditionalOrders:    noop{},
+		ActiveStopOrders:           noop{},
+		MonitoringCycleDuration:    noop{},
+	}
+}
@@ -0,0 +1,21 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNoopSetsEveryMetric(t *testing.T) {
+	sink := Noop()
+	fields := reflect.ValueOf(*sink)
+	for i := 0; i < fields.NumField(); i++ {
+		if fields.Field(i).IsNil() {
+			t.Errorf("Noop().%s is nil", fields.Type().Field(i).Name)
+		}
+	}
+
+	// Recording on the no-op sink must not panic
+	sink.Orders.Inc("BUY", "FILLED")
+	sink.ActiveStopOrders.Set(3)
+	sink.MonitoringCycleDuration.Observe(0.5)
+}
@@ -0,0 +1,102 @@
+// Package timeutil converts between time.Time and the Unix millisecond timestamps stored
+// on order records, matching the exchange's own timestamp resolution.
+package timeutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layouts timestamps are rendered with for people
+const (
+	DisplayLayout       = "2006-01-02 15:04:05 MST"
+	DisplayLayoutMillis = "2006-01-02 15:04:05.000 MST"
+)
+
+// secondsThreshold separates the two resolutions: any later timestamp in seconds would be
+// after the year 5000, and any earlier one in milliseconds before March 1973, so values
+// below it are taken to be seconds
+const secondsThreshold = 100_000_000_000
+
+// Millis returns t as Unix milliseconds
+func Millis(t time.Time) int64 {
+	return t.UnixMilli()
+}
+
+// NowMillis returns the current time as Unix milliseconds
+func NowMillis() int64 {
+	return time.Now().UnixMilli()
+}
+
+// FromMillis returns the UTC time of a Unix millisecond timestamp
+func FromMillis(ms int64) time.Time {
+	return time.UnixMilli(ms).UTC()
+}
+
+// SecondsToMillis converts a Unix timestamp in seconds to milliseconds
+func SecondsToMillis(seconds int64) int64 {
+	return seconds * 1000
+}
+
+// IsSeconds reports whether a non-zero timestamp is too small to be in milliseconds
+func IsSeconds(ts int64) bool {
+	return ts != 0 && ts > -secondsThreshold && ts < secondsThreshold
+}
+
+// IsMillis reports whether a timestamp is large enough to be in milliseconds
+func IsMillis(ts int64) bool {
+	return ts >= secondsThreshold
+}
+
+// NormalizeMillis converts a timestamp that is in seconds to milliseconds and returns
+// millisecond timestamps unchanged. Zero stays zero, meaning "not set".
+func NormalizeMillis(ts int64) int64 {
+	if IsSeconds(ts) {
+		return SecondsToMillis(ts)
+	}
+	return ts
+}
+
+// ValidateMillis rejects negative timestamps and ones that are evidently in seconds; zero
+// is accepted as "unbounded"
+func ValidateMillis(name string, ts int64) error {
+	if ts < 0 {
+		return fmt.Errorf("%s cannot be negative", name)
+	}
+	if IsSeconds(ts) {
+		return fmt.Errorf("%s must be in Unix milliseconds, got %d which looks like seconds", name, ts)
+	}
+	return nil
+}
+
+// Format renders a millisecond timestamp in the given location, UTC when nil; zero renders
+// as "-"
+func Format(ms int64, location *time.Location) string {
+	if ms == 0 {
+		return "-"
+	}
+	if location == nil {
+		location = time.UTC
+	}
+	return FromMillis(ms).In(location).Format(DisplayLayout)
+}
+
+// Parse reads a point in time given as an RFC3339 string ("2024-05-01T08:00:00Z") or as
+// Unix milliseconds and returns it as Unix milliseconds
+func Parse(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return Millis(t), nil
+	}
+
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: expected RFC3339 such as 2024-05-01T08:00:00Z or Unix milliseconds", s)
+	}
+	if err := ValidateMillis("time", ms); err != nil {
+		return 0, err
+	}
+	return ms, nil
+}
@@ -0,0 +1,124 @@
+package timeutil
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMillisConversions(t *testing.T) {
+	instant := time.Date(2024, 1, 1, 12, 0, 0, 250_000_000, time.UTC)
+
+	ms := Millis(instant)
+	if ms != 1704110400250 {
+		t.Errorf("Millis() = %d, want 1704110400250", ms)
+	}
+	if back := FromMillis(ms); !back.Equal(instant) || back.Location() != time.UTC {
+		t.Errorf("FromMillis() = %v, want %v in UTC", back, instant)
+	}
+	if got := SecondsToMillis(1704110400); got != 1704110400000 {
+		t.Errorf("SecondsToMillis() = %d, want 1704110400000", got)
+	}
+	if now := NowMillis(); IsSeconds(now) || !IsMillis(now) {
+		t.Errorf("NowMillis() = %d looks like seconds", now)
+	}
+}
+
+func TestNormalizeMillis(t *testing.T) {
+	tests := []struct {
+		name string
+		in   int64
+		want int64
+	}{
+		{"unset", 0, 0},
+		{"seconds", 1704110400, 1704110400000},
+		{"milliseconds", 1704110400000, 1704110400000},
+		{"early seconds", 1, 1000},
+		{"largest seconds", secondsThreshold - 1, (secondsThreshold - 1) * 1000},
+		{"smallest milliseconds", secondsThreshold, secondsThreshold},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeMillis(tt.in); got != tt.want {
+				t.Errorf("NormalizeMillis(%d) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMillis(t *testing.T) {
+	tests := []struct {
+		name     string
+		ts       int64
+		errorMsg string
+	}{
+		{"unbounded", 0, ""},
+		{"milliseconds", 1704110400000, ""},
+		{"seconds", 1704110400, "must be in Unix milliseconds"},
+		{"negative", -1, "cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMillis("start time", tt.ts)
+			if tt.errorMsg == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+
+	if got := Format(1704110400000, nil); got != "2024-01-01 12:00:00 UTC" {
+		t.Errorf("Format(nil) = %q", got)
+	}
+	if got := Format(1704110400000, shanghai); got != "2024-01-01 20:00:00 CST" {
+		t.Errorf("Format(Asia/Shanghai) = %q", got)
+	}
+	if got := Format(0, shanghai); got != "-" {
+		t.Errorf("Format(0) = %q, want -", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		want     int64
+		errorMsg string
+	}{
+		{name: "RFC3339 UTC", in: "2024-01-01T12:00:00Z", want: 1704110400000},
+		{name: "RFC3339 offset", in: "2024-01-01T20:00:00+08:00", want: 1704110400000},
+		{name: "fractional seconds", in: "2024-01-01T12:00:00.5Z", want: 1704110400500},
+		{name: "milliseconds", in: " 1704110400000 ", want: 1704110400000},
+		{name: "seconds", in: "1704110400", errorMsg: "looks like seconds"},
+		{name: "date only", in: "2024-01-01", errorMsg: "expected RFC3339"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if tt.errorMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+					t.Errorf("expected error containing %q, got %v", tt.errorMsg, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Parse(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package trader
+
+import (
+	"flag"
+	"fmt"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+// describeExportedAPI type-checks the package from source and lists every exported identifier
+// with its signature, together with the methods and fields of the types it exposes, so
+// changes to aliased internal types show up as well
+func describeExportedAPI(t *testing.T) string {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	pkg, err := importer.ForCompiler(fset, "source", nil).Import("binance-trader/pkg/trader")
+	if err != nil {
+		t.Fatalf("failed to type-check package: %v", err)
+	}
+
+	qualifier := func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		return other.Name()
+	}
+
+	var lines []string
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+
+		typeName, ok := obj.(*types.TypeName)
+		if !ok {
+			lines = append(lines, types.ObjectString(obj, qualifier))
+			continue
+		}
+
+		// Defined types list their fields and methods below; aliases name their target
+		if typeName.IsAlias() {
+			lines = append(lines, types.ObjectString(typeName, qualifier))
+		} else if _, isStruct := typeName.Type().Underlying().(*types.Struct); isStruct {
+			lines = append(lines, fmt.Sprintf("type %s struct", name))
+		} else {
+			lines = append(lines, fmt.Sprintf("type %s %s", name, types.TypeString(typeName.Type().Underlying(), qualifier)))
+		}
+		if structType, ok := typeName.Type().Underlying().(*types.Struct); ok {
+			for i := 0; i < structType.NumFields(); i++ {
+				if field := structType.Field(i); field.Exported() {
+					lines = append(lines, fmt.Sprintf("field %s.%s %s", name, field.Name(), types.TypeString(field.Type(), qualifier)))
+				}
+			}
+		}
+		methodSetType := typeName.Type()
+		if !types.IsInterface(methodSetType) {
+			methodSetType = types.NewPointer(methodSetType)
+		}
+		methods := types.NewMethodSet(methodSetType)
+		for i := 0; i < methods.Len(); i++ {
+			if method := methods.At(i).Obj(); method.Exported() {
+				signature := strings.TrimPrefix(types.TypeString(method.Type(), qualifier), "func")
+				lines = append(lines, fmt.Sprintf("method %s.%s%s", name, method.Name(), signature))
+			}
+		}
+	}
+
+	sort.Strings(lines)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// TestExportedAPI guards the public surface against accidental changes. After an intended
+// change, regenerate the golden file with: go test ./pkg/trader -run TestExportedAPI -update
+func TestExportedAPI(t *testing.T) {
+	api := describeExportedAPI(t)
+	goldenPath := filepath.Join("testdata", "api.golden")
+
+	if *updateGolden {
+		if err := os.WriteFile(goldenPath, []byte(api), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+	}
+
+	expected, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+
+	if api != string(expected) {
+		want := strings.Split(string(expected), "\n")
+		got := strings.Split(api, "\n")
+		t.Errorf("exported API changed; removed:\n%s\nadded:\n%s\nrun with -update if the change is intended",
+			strings.Join(missingLines(want, got), "\n"), strings.Join(missingLines(got, want), "\n"))
+	}
+}
+
+// missingLines returns the lines of a that are not in b
+func missingLines(a, b []string) []string {
+	present := make(map[string]bool, len(b))
+	for _, line := range b {
+		present[line] = true
+	}
+
+	var missing []string
+	for _, line := range a {
+		if !present[line] {
+			missing = append(missing, line)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,130 @@
+package trader
+
+import (
+	"binance-trader/internal/api"
+	"fmt"
+	"time"
+)
+
+// Exchange REST endpoints
+const (
+	SpotBaseURL           = "https://api.binance.com"
+	SpotTestnetBaseURL    = "https://testnet.binance.vision"
+	FuturesBaseURL        = "https://fapi.binance.com"
+	FuturesTestnetBaseURL = "https://testnet.binancefuture.com"
+)
+
+// SpotStreamURL is the spot market WebSocket endpoint
+const SpotStreamURL = api.DefaultMarketStreamURL
+
+// Defaults applied to zero ClientOptions fields
+const (
+	DefaultMaxAPICallsPerMin = 1000
+	DefaultMaxAttempts       = 3
+	DefaultInitialDelay      = time.Second
+	DefaultBackoffMultiplier = 2.0
+)
+
+// ClientOptions configures an exchange client. Zero values select the defaults.
+type ClientOptions struct {
+	APIKey    string
+	APISecret string
+
+	// BaseURL overrides the REST endpoint; by default production, or the testnet when Testnet is set
+	BaseURL string
+	Testnet bool
+
+	// MaxAPICallsPerMin caps the request weight the client sends per minute
+	MaxAPICallsPerMin int
+
+	// Retry of failed requests with exponential backoff
+	MaxAttempts       int
+	InitialDelay      time.Duration
+	BackoffMultiplier float64
+
+	// RequestTimeout bounds every request; 0 uses the client default
+	RequestTimeout time.Duration
+
+	// SafeMode rejects orders, cancellations and account changes before they are sent
+	SafeMode bool
+}
+
+// NewSpotClient creates a signed, rate-limited spot REST client
+func NewSpotClient(opts ClientOptions) (SpotClient, error) {
+	httpClient, authMgr, err := opts.build()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := api.NewSpotClient(opts.baseURL(SpotBaseURL, SpotTestnetBaseURL), httpClient, authMgr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spot client: %w", err)
+	}
+	return api.NewSafeModeSpotClient(client, api.NewSafeMode(opts.SafeMode)), nil
+}
+
+// NewMarketStream creates a spot market stream for SpotOptions.PriceStream; an empty url uses
+// SpotStreamURL. It connects on the first subscription and reconnects with backoff.
+func NewMarketStream(url string) (MarketStreamClient, error) {
+	stream, err := api.NewMarketStreamClient(api.MarketStreamOptions{URL: url})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create market stream: %w", err)
+	}
+	return stream, nil
+}
+
+// NewFuturesClient creates a signed, rate-limited USDT-M futures REST client
+func NewFuturesClient(opts ClientOptions) (FuturesClient, error) {
+	httpClient, authMgr, err := opts.build()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := api.NewFuturesClient(opts.baseURL(FuturesBaseURL, FuturesTestnetBaseURL), httpClient, authMgr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create futures client: %w", err)
+	}
+	return api.NewSafeModeFuturesClient(client, api.NewSafeMode(opts.SafeMode)), nil
+}
+
+// baseURL returns the configured endpoint or the production/testnet default
+func (o ClientOptions) baseURL(production, testnet string) string {
+	switch {
+	case o.BaseURL != "":
+		return o.BaseURL
+	case o.Testnet:
+		return testnet
+	default:
+		return production
+	}
+}
+
+// build creates the HTTP client and request signer shared by both client types
+func (o ClientOptions) build() (api.HTTPClient, *api.AuthManager, error) {
+	authMgr, err := api.NewAuthManager(o.APIKey, o.APISecret)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid credentials: %w", err)
+	}
+
+	maxCalls := o.MaxAPICallsPerMin
+	if maxCalls <= 0 {
+		maxCalls = DefaultMaxAPICallsPerMin
+	}
+	retry := api.RetryConfig{
+		MaxAttempts:       o.MaxAttempts,
+		InitialDelayMs:    int(o.InitialDelay / time.Millisecond),
+		BackoffMultiplier: o.BackoffMultiplier,
+	}
+	if retry.MaxAttempts <= 0 {
+		retry.MaxAttempts = DefaultMaxAttempts
+	}
+	if retry.InitialDelayMs <= 0 {
+		retry.InitialDelayMs = int(DefaultInitialDelay / time.Millisecond)
+	}
+	if retry.BackoffMultiplier <= 0 {
+		retry.BackoffMultiplier = DefaultBackoffMultiplier
+	}
+
+	httpClient := api.NewHTTPClientWithTimeouts(api.NewRateLimiter(maxCalls, api.DefaultEndpointWeights), retry, api.TimeoutConfig{Default: o.RequestTimeout})
+	return httpClient, authMgr, nil
+}
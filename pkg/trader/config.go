@@ -0,0 +1,64 @@
+package trader
+
+import (
+	"binance-trader/internal/config"
+	"time"
+)
+
+// LoadConfig reads a YAML config file, substituting ${ENV_VAR} references, and validates
+// the sections needed by the given trading types; without trading types every section
+// present in the file is validated
+func LoadConfig(path string, tradingTypes ...TradingType) (*Config, error) {
+	return config.NewConfigManager().Load(path, tradingTypes...)
+}
+
+// SpotClientOptions returns the spot client options of a loaded config
+func SpotClientOptions(cfg *Config) ClientOptions {
+	binanceConfig := cfg.Spot
+	if binanceConfig == nil {
+		binanceConfig = &cfg.Binance
+	}
+
+	opts := clientOptions(cfg, binanceConfig.APIKey, binanceConfig.APISecret, binanceConfig.BaseURL, binanceConfig.Testnet)
+	opts.MaxAPICallsPerMin = cfg.Risk.MaxAPICallsPerMin
+	return opts
+}
+
+// FuturesClientOptions returns the futures client options of a loaded config; they carry
+// no credentials when the config has no futures section
+func FuturesClientOptions(cfg *Config) ClientOptions {
+	if cfg.Futures == nil {
+		return clientOptions(cfg, "", "", "", false)
+	}
+
+	opts := clientOptions(cfg, cfg.Futures.APIKey, cfg.Futures.APISecret, cfg.Futures.BaseURL, cfg.Futures.Testnet)
+	opts.MaxAPICallsPerMin = cfg.Futures.Risk.MaxAPICallsPerMin
+	return opts
+}
+
+// SpotRiskLimits returns the spot risk limits of a loaded config
+func SpotRiskLimits(cfg *Config) *RiskLimits {
+	return &RiskLimits{
+		MaxOrderAmount:    cfg.Risk.MaxOrderAmount,
+		MaxDailyOrders:    cfg.Risk.MaxDailyOrders,
+		MinBalanceReserve: cfg.Risk.MinBalanceReserve,
+		MaxAPICallsPerMin: cfg.Risk.MaxAPICallsPerMin,
+		MaxNotionalPerMin: cfg.Risk.MaxNotionalPerMin,
+		NotionalCapMode:   cfg.Risk.NotionalCapMode,
+	}
+}
+
+// clientOptions fills the settings shared by spot and futures clients
+func clientOptions(cfg *Config, apiKey, apiSecret, baseURL string, testnet bool) ClientOptions {
+	return ClientOptions{
+		APIKey:            apiKey,
+		APISecret:         apiSecret,
+		BaseURL:           baseURL,
+		Testnet:           testnet,
+		MaxAttempts:       cfg.Retry.MaxAttempts,
+		InitialDelay:      time.Duration(cfg.Retry.InitialDelayMs) * time.Millisecond,
+		BackoffMultiplier: cfg.Retry.BackoffMultiplier,
+		RequestTimeout:    time.Duration(cfg.Network.Timeouts.DefaultMs) * time.Millisecond,
+		SafeMode:          cfg.SafeMode,
+	}
+}
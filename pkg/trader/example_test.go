@@ -0,0 +1,82 @@
+package trader_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"binance-trader/pkg/trader"
+)
+
+// Example places a limit order on the spot testnet and creates a conditional order that buys
+// once BTC trades above 70000. It needs testnet credentials, so it is compiled but not run.
+func Example() {
+	client, err := trader.NewSpotClient(trader.ClientOptions{
+		APIKey:    os.Getenv("BINANCE_TESTNET_API_KEY"),
+		APISecret: os.Getenv("BINANCE_TESTNET_API_SECRET"),
+		Testnet:   true,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	spot, err := trader.NewSpot(trader.SpotOptions{Client: client})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	order, err := spot.Trading.PlaceLimitSellOrder("BTCUSDT", 90000, 0.001)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("placed order", order.OrderID, order.Status)
+
+	conditional, err := spot.Conditional.CreateConditionalOrder(&trader.ConditionalOrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     trader.OrderSideBuy,
+		Type:     trader.OrderTypeMarket,
+		Quantity: 0.001,
+		TriggerCondition: &trader.TriggerCondition{
+			Type:     trader.TriggerTypePrice,
+			Operator: trader.OperatorGreaterThan,
+			Value:    70000,
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("created conditional order", conditional.OrderID)
+
+	// Conditions are evaluated while monitoring runs
+	if err := spot.Conditional.StartMonitoring(); err != nil {
+		log.Fatal(err)
+	}
+	defer spot.Conditional.StopMonitoring()
+}
+
+// ExampleLoadConfig builds the spot stack from the same config file the command uses
+func ExampleLoadConfig() {
+	cfg, err := trader.LoadConfig("config.yaml", trader.TradingTypeSpot)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	client, err := trader.NewSpotClient(trader.SpotClientOptions(cfg))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	spot, err := trader.NewSpot(trader.SpotOptions{
+		Client: client,
+		Risk:   trader.SpotRiskLimits(cfg),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	price, err := spot.Market.GetCurrentPrice("BTCUSDT")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("BTCUSDT", price)
+}
@@ -0,0 +1,59 @@
+package trader
+
+import (
+	"binance-trader/internal/repository"
+	"binance-trader/internal/service"
+	"fmt"
+)
+
+// FuturesOptions configures a futures service stack
+type FuturesOptions struct {
+	// Client is the exchange client every service trades through; required
+	Client FuturesClient
+
+	// Logger receives structured logs; nil logs warnings and errors to stderr
+	Logger Logger
+
+	// SpotPrices supplies the spot leg of BASIS triggers, e.g. a SpotClient; nil refuses them
+	SpotPrices SpotPriceClient
+}
+
+// Futures is a USDT-M futures service stack sharing one client
+type Futures struct {
+	Client      FuturesClient
+	Trading     FuturesTradingService
+	Market      FuturesMarketDataService
+	Positions   FuturesPositionManager
+	Conditional FuturesConditionalOrderService
+	StopLoss    FuturesStopLossService
+}
+
+// NewFutures creates a futures service stack. Conditional orders are only evaluated while
+// monitoring runs; call Conditional.StartMonitoring to begin.
+func NewFutures(opts FuturesOptions) (*Futures, error) {
+	if opts.Client == nil {
+		return nil, fmt.Errorf("futures client cannot be nil")
+	}
+
+	log, err := defaultLogger(opts.Logger, TradingTypeFutures)
+	if err != nil {
+		return nil, err
+	}
+
+	trading := service.NewFuturesTradingService(opts.Client, repository.NewMemoryFuturesOrderRepository(), log)
+	market := service.NewFuturesMarketDataService(opts.Client, log)
+	positions := service.NewFuturesPositionManager(opts.Client, repository.NewMemoryFuturesPositionRepository(), log)
+	conditional := service.NewFuturesConditionalOrderService(opts.Client, market, positions, trading, log)
+	if opts.SpotPrices != nil {
+		conditional.SetSpotPriceSource(opts.SpotPrices)
+	}
+
+	return &Futures{
+		Client:      opts.Client,
+		Trading:     trading,
+		Market:      market,
+		Positions:   positions,
+		Conditional: conditional,
+		StopLoss:    service.NewFuturesStopLossService(repository.NewMemoryStopOrderRepository(), service.NewTriggerEngine(), trading, market, log),
+	}, nil
+}
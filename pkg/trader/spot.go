@@ -0,0 +1,99 @@
+package trader
+
+import (
+	"binance-trader/internal/repository"
+	"binance-trader/internal/service"
+	"fmt"
+	"time"
+)
+
+// DefaultPriceCacheTTL is how long spot prices are reused when SpotOptions sets no TTL
+const DefaultPriceCacheTTL = time.Second
+
+// SpotOptions configures a spot service stack
+type SpotOptions struct {
+	// Client is the exchange client every service trades through; required
+	Client SpotClient
+
+	// Logger receives structured logs; nil logs warnings and errors to stderr
+	Logger Logger
+
+	// Risk limits checked before each order; nil uses the built-in defaults
+	Risk *RiskLimits
+
+	// PriceCacheTTL is how long a fetched price is reused
+	PriceCacheTTL time.Duration
+
+	// PriceStream pushes prices to Market.SubscribeToPrice and Market.SubscribePriceFeed, e.g.
+	// NewMarketStream; nil polls REST
+	PriceStream PriceStream
+}
+
+// Spot is a spot service stack. The services share one client, trigger engine and set of
+// in-memory order repositories, so conditional and stop orders execute through Trading.
+type Spot struct {
+	Client      SpotClient
+	Trading     TradingService
+	Market      MarketDataService
+	Conditional ConditionalOrderService
+	StopLoss    StopLossService
+}
+
+// NewSpot creates a spot service stack. Conditional and stop orders are only evaluated
+// while monitoring runs; call Conditional.StartMonitoring to begin.
+func NewSpot(opts SpotOptions) (*Spot, error) {
+	if opts.Client == nil {
+		return nil, fmt.Errorf("spot client cannot be nil")
+	}
+
+	log, err := defaultLogger(opts.Logger, TradingTypeSpot)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheTTL := opts.PriceCacheTTL
+	if cacheTTL <= 0 {
+		cacheTTL = DefaultPriceCacheTTL
+	}
+
+	riskMgr := service.NewRiskManager(opts.Risk, opts.Client)
+	trading := service.NewSpotTradingService(opts.Client, riskMgr, repository.NewMemoryOrderRepository(), log)
+	market := service.NewMarketDataService(opts.Client, cacheTTL)
+	if opts.PriceStream != nil {
+		market.SetPriceStream(opts.PriceStream)
+	}
+
+	stopOrderRepo := repository.NewMemoryStopOrderRepository()
+	triggerEngine := service.NewTriggerEngine()
+	stopLoss := service.NewStopLossService(stopOrderRepo, triggerEngine, trading, market, log)
+	conditional := service.NewConditionalOrderService(
+		repository.NewMemoryConditionalOrderRepository(),
+		stopOrderRepo,
+		triggerEngine,
+		trading,
+		market,
+		stopLoss,
+		log,
+	)
+
+	return &Spot{
+		Client:      opts.Client,
+		Trading:     trading,
+		Market:      market,
+		Conditional: conditional,
+		StopLoss:    stopLoss,
+	}, nil
+}
+
+// defaultLogger returns the given logger or a stderr logger for warnings and errors
+func defaultLogger(log Logger, tradingType TradingType) (Logger, error) {
+	if log != nil {
+		return log, nil
+	}
+
+	log, err := NewLogger(LoggerConfig{Level: "warn", TradingType: string(tradingType)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create logger: %w", err)
+	}
+	return log, nil
+}
@@ -0,0 +1,136 @@
+// Package trader is the public API for embedding the trading system in another Go program.
+//
+// It exposes the config loader, constructors for the spot and futures exchange clients and
+// the spot and futures service stacks built on them. Types are aliases of the implementation
+// types, so values move freely between this package and the services. Everything that is not
+// exported here lives under internal/ and may change without notice.
+package trader
+
+import (
+	"binance-trader/internal/api"
+	"binance-trader/internal/config"
+	"binance-trader/internal/repository"
+	"binance-trader/internal/service"
+	"binance-trader/pkg/logger"
+)
+
+// Configuration and logging
+type (
+	Config       = config.Config
+	TradingType  = config.TradingType
+	Logger       = logger.Logger
+	LoggerConfig = logger.Config
+)
+
+const (
+	TradingTypeSpot    = config.TradingTypeSpot
+	TradingTypeFutures = config.TradingTypeFutures
+	TradingTypeBoth    = config.TradingTypeBoth
+)
+
+// Exchange clients and orders
+type (
+	SpotClient         = api.SpotClient
+	SpotPriceClient    = api.SpotPriceClient
+	MarketStreamClient = api.MarketStreamClient
+	TickerEvent        = api.TickerEvent
+	BookTicker         = api.BookTicker
+	OrderBook          = api.OrderBook
+	OrderBookLevel     = api.OrderBookLevel
+	FuturesClient      = api.FuturesClient
+	Order              = api.Order
+	FuturesOrder       = api.FuturesOrder
+	OCOResponse        = api.OCOResponse
+	OrderSide          = api.OrderSide
+	OrderType          = api.OrderType
+	OrderStatus        = api.OrderStatus
+	PositionSide       = api.PositionSide
+)
+
+const (
+	OrderSideBuy  = api.OrderSideBuy
+	OrderSideSell = api.OrderSideSell
+
+	OrderTypeMarket = api.OrderTypeMarket
+	OrderTypeLimit  = api.OrderTypeLimit
+
+	PositionSideBoth  = api.PositionSideBoth
+	PositionSideLong  = api.PositionSideLong
+	PositionSideShort = api.PositionSideShort
+)
+
+// Spot services
+type (
+	TradingService          = service.TradingService
+	MarketDataService       = service.MarketDataService
+	PriceStream             = service.PriceStream
+	ConditionalOrderService = service.ConditionalOrderService
+	StopLossService         = service.StopLossService
+	RiskLimits              = service.RiskLimits
+)
+
+// Spot conditional orders
+type (
+	ConditionalOrder        = repository.ConditionalOrder
+	ConditionalOrderRequest = repository.ConditionalOrderRequest
+	ConditionalOrderStatus  = repository.ConditionalOrderStatus
+	TriggerCondition        = repository.TriggerCondition
+	TriggerType             = repository.TriggerType
+	ComparisonOperator      = repository.ComparisonOperator
+	TimeWindow              = repository.TimeWindow
+)
+
+const (
+	TriggerTypePrice              = repository.TriggerTypePrice
+	TriggerTypePriceChangePercent = repository.TriggerTypePriceChangePercent
+	TriggerTypeVolume             = repository.TriggerTypeVolume
+	TriggerTypeRSI                = repository.TriggerTypeRSI
+	TriggerTypeAskBidImbalance    = repository.TriggerTypeAskBidImbalance
+	TriggerTypeMACrossover        = repository.TriggerTypeMACrossover
+
+	OperatorGreaterThan  = repository.OperatorGreaterThan
+	OperatorLessThan     = repository.OperatorLessThan
+	OperatorGreaterEqual = repository.OperatorGreaterEqual
+	OperatorLessEqual    = repository.OperatorLessEqual
+
+	ConditionalOrderStatusPending   = repository.ConditionalOrderStatusPending
+	ConditionalOrderStatusTriggered = repository.ConditionalOrderStatusTriggered
+	ConditionalOrderStatusExecuted  = repository.ConditionalOrderStatusExecuted
+	ConditionalOrderStatusCancelled = repository.ConditionalOrderStatusCancelled
+)
+
+// Futures services
+type (
+	FuturesTradingService          = service.FuturesTradingService
+	FuturesMarketDataService       = service.FuturesMarketDataService
+	FuturesPositionManager         = service.FuturesPositionManager
+	FuturesConditionalOrderService = service.FuturesConditionalOrderService
+	FuturesStopLossService         = service.FuturesStopLossService
+)
+
+// Futures conditional orders
+type (
+	FuturesConditionalOrder        = service.FuturesConditionalOrder
+	FuturesConditionalOrderRequest = service.FuturesConditionalOrderRequest
+	FuturesTriggerCondition        = service.FuturesTriggerCondition
+	FuturesTriggerType             = service.FuturesTriggerType
+	FuturesComparisonOperator      = service.ComparisonOperator
+)
+
+const (
+	FuturesTriggerTypeMarkPrice     = service.FuturesTriggerTypeMarkPrice
+	FuturesTriggerTypeLastPrice     = service.FuturesTriggerTypeLastPrice
+	FuturesTriggerTypeUnrealizedPnL = service.FuturesTriggerTypeUnrealizedPnL
+	FuturesTriggerTypeFundingRate   = service.FuturesTriggerTypeFundingRate
+	FuturesTriggerTypeBasis         = service.FuturesTriggerTypeBasis
+
+	FuturesOperatorGreaterThan  = service.OperatorGreaterThan
+	FuturesOperatorLessThan     = service.OperatorLessThan
+	FuturesOperatorGreaterEqual = service.OperatorGreaterEqual
+	FuturesOperatorLessEqual    = service.OperatorLessEqual
+)
+
+// NewLogger creates a structured JSON logger that masks credentials
+func NewLogger(cfg LoggerConfig) (Logger, error) {
+	return logger.NewLogger(cfg)
+}
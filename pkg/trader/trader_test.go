@@ -0,0 +1,122 @@
+package trader
+
+import (
+	"binance-trader/internal/config"
+	"binance-trader/pkg/errors"
+	"testing"
+	"time"
+)
+
+func TestClientOptionsBaseURL(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ClientOptions
+		want string
+	}{
+		{"production by default", ClientOptions{}, SpotBaseURL},
+		{"testnet", ClientOptions{Testnet: true}, SpotTestnetBaseURL},
+		{"explicit URL wins", ClientOptions{BaseURL: "https://example.com", Testnet: true}, "https://example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.baseURL(SpotBaseURL, SpotTestnetBaseURL); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewSpotClient(t *testing.T) {
+	if _, err := NewSpotClient(ClientOptions{}); err == nil {
+		t.Error("expected an error without credentials")
+	}
+	if _, err := NewFuturesClient(ClientOptions{APIKey: "key", APISecret: "secret", BaseURL: "https://example.com"}); err == nil {
+		t.Error("expected an error for a non-futures endpoint")
+	}
+
+	// Safe mode rejects writes before any request is sent
+	client, err := NewSpotClient(ClientOptions{APIKey: "key", APISecret: "secret", Testnet: true, SafeMode: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	_, err = client.CancelOrder("BTCUSDT", 1)
+	if tradingErr, ok := err.(*errors.TradingError); !ok || tradingErr.Type != errors.ErrSafeMode {
+		t.Errorf("expected a safe mode error, got %v", err)
+	}
+}
+
+func TestNewSpot(t *testing.T) {
+	if _, err := NewSpot(SpotOptions{}); err == nil {
+		t.Error("expected an error without a client")
+	}
+
+	client, err := NewSpotClient(ClientOptions{APIKey: "key", APISecret: "secret", Testnet: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	spot, err := NewSpot(SpotOptions{Client: client})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if spot.Client != client || spot.Trading == nil || spot.Market == nil || spot.Conditional == nil || spot.StopLoss == nil {
+		t.Errorf("expected every service to be set, got %+v", spot)
+	}
+}
+
+func TestNewFutures(t *testing.T) {
+	if _, err := NewFutures(FuturesOptions{}); err == nil {
+		t.Error("expected an error without a client")
+	}
+
+	client, err := NewFuturesClient(ClientOptions{APIKey: "key", APISecret: "secret", Testnet: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	futures, err := NewFutures(FuturesOptions{Client: client})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if futures.Trading == nil || futures.Market == nil || futures.Positions == nil || futures.Conditional == nil || futures.StopLoss == nil {
+		t.Errorf("expected every service to be set, got %+v", futures)
+	}
+}
+
+func TestClientOptionsFromConfig(t *testing.T) {
+	cfg := &Config{
+		Spot:     &config.BinanceConfig{APIKey: "spot-key", APISecret: "spot-secret", BaseURL: SpotTestnetBaseURL, Testnet: true},
+		Futures:  &config.FuturesConfig{APIKey: "futures-key", APISecret: "futures-secret", BaseURL: FuturesBaseURL},
+		Risk:     config.RiskConfig{MaxOrderAmount: 500, MaxDailyOrders: 20, MaxAPICallsPerMin: 600},
+		Retry:    config.RetryConfig{MaxAttempts: 5, InitialDelayMs: 250, BackoffMultiplier: 1.5},
+		SafeMode: true,
+	}
+	cfg.Futures.Risk.MaxAPICallsPerMin = 1200
+
+	spotOpts := SpotClientOptions(cfg)
+	wantSpot := ClientOptions{
+		APIKey:            "spot-key",
+		APISecret:         "spot-secret",
+		BaseURL:           SpotTestnetBaseURL,
+		Testnet:           true,
+		MaxAPICallsPerMin: 600,
+		MaxAttempts:       5,
+		InitialDelay:      250 * time.Millisecond,
+		BackoffMultiplier: 1.5,
+		SafeMode:          true,
+	}
+	if spotOpts != wantSpot {
+		t.Errorf("expected spot options %+v, got %+v", wantSpot, spotOpts)
+	}
+
+	futuresOpts := FuturesClientOptions(cfg)
+	if futuresOpts.APIKey != "futures-key" || futuresOpts.BaseURL != FuturesBaseURL || futuresOpts.MaxAPICallsPerMin != 1200 {
+		t.Errorf("unexpected futures options %+v", futuresOpts)
+	}
+	if opts := FuturesClientOptions(&Config{}); opts.APIKey != "" {
+		t.Errorf("expected no credentials without a futures section, got %+v", opts)
+	}
+
+	if limits := SpotRiskLimits(cfg); limits.MaxOrderAmount != 500 || limits.MaxDailyOrders != 20 {
+		t.Errorf("unexpected risk limits %+v", limits)
+	}
+}